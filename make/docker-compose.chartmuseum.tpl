@@ -1,10 +1,19 @@
 version: '2'
 services:
   core:
+    environment:
+      - CHARTMUSEUM_VERSION=__chartmuseum_version__
     networks:
       harbor-chartmuseum:
         aliases:
           - harbor-core
+  jobservice:
+    # the chart storage is mounted to remove the orphaned chart files, it's only
+    # available when the charts are stored in the local file system
+    volumes:
+      - /data/chart_storage:/chart_storage:z
+    environment:
+      - CHART_STORAGE_DIR=/chart_storage
   redis:
     networks:
       harbor-chartmuseum:
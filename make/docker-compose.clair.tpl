@@ -1,6 +1,8 @@
 version: '2'
 services:
   core:
+    environment:
+      - CLAIR_VERSION=__clair_version__
     networks:
       harbor-clair:
         aliases:
@@ -1,6 +1,8 @@
 version: '2'
 services:
   core:
+    environment:
+      - NOTARY_VERSION=__notary_version__
     networks:
       - harbor-notary
   proxy:
@@ -125,6 +125,8 @@ services:
     container_name: harbor-core
     env_file:
       - ./common/config/core/env
+    environment:
+      - REGISTRY_VERSION=__reg_version__
     restart: always
     cap_drop:
       - ALL
@@ -189,6 +191,7 @@ services:
     volumes:
       - /data/job_logs:/var/log/jobs:z
       - ./common/config/jobservice/config.yml:/etc/jobservice/config.yml:z
+      - /data/secretkey:/etc/jobservice/key:z
     networks:
       - harbor
     dns_search: .
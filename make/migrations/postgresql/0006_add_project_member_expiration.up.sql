@@ -0,0 +1,6 @@
+/*
+ expiration_time is null means the membership never expires
+*/
+ALTER TABLE project_member ADD COLUMN expiration_time timestamp;
+
+CREATE INDEX project_member_expiration_time ON project_member (expiration_time);
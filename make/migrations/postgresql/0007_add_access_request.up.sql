@@ -0,0 +1,23 @@
+CREATE TABLE access_request (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ user_id int NOT NULL,
+ role int NOT NULL,
+ justification varchar(1024),
+ /*
+  The status of the request: pending, approved or denied
+ */
+ status varchar(16) DEFAULT 'pending' NOT NULL,
+ reviewer varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id)
+);
+
+/*
+ A user can only have one pending request for a project
+*/
+CREATE UNIQUE INDEX unique_pending_access_request ON access_request (project_id, user_id) WHERE status = 'pending';
+
+CREATE TRIGGER access_request_update_time_at_modtime BEFORE UPDATE ON access_request FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
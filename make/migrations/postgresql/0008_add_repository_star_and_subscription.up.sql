@@ -0,0 +1,26 @@
+CREATE TABLE repository_star (
+ id SERIAL PRIMARY KEY NOT NULL,
+ user_id int NOT NULL,
+ repository_name varchar(255) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id),
+ FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE,
+ CONSTRAINT unique_repository_star UNIQUE (user_id, repository_name)
+);
+
+CREATE TABLE repository_subscription (
+ id SERIAL PRIMARY KEY NOT NULL,
+ user_id int NOT NULL,
+ repository_name varchar(255) NOT NULL,
+ /*
+  The comma separated events the user subscribes, e.g. "new_tag,critical_cve"
+ */
+ events varchar(255) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id),
+ FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE,
+ CONSTRAINT unique_repository_subscription UNIQUE (user_id, repository_name)
+);
+
+CREATE TRIGGER repository_subscription_update_time_at_modtime BEFORE UPDATE ON repository_subscription FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,17 @@
+CREATE TABLE upload_session (
+ id SERIAL PRIMARY KEY NOT NULL,
+ uuid varchar(64) NOT NULL,
+ repository varchar(255) NOT NULL,
+ /*
+  The "_state" parameter of the location returned by the last response of the upload,
+  the registry rejects the requests of the upload without it
+ */
+ state text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_upload_session_uuid UNIQUE (uuid)
+);
+
+CREATE INDEX upload_session_update_time ON upload_session (update_time);
+
+CREATE TRIGGER upload_session_update_time_at_modtime BEFORE UPDATE ON upload_session FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
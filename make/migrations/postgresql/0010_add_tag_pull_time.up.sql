@@ -0,0 +1,9 @@
+CREATE TABLE tag_pull_time (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ pull_time timestamp NOT NULL,
+ CONSTRAINT unique_tag_pull_time UNIQUE (repository, tag)
+);
+
+CREATE INDEX tag_pull_time_pull_time ON tag_pull_time (pull_time);
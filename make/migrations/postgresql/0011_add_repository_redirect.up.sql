@@ -0,0 +1,18 @@
+CREATE TABLE repository_redirect (
+ id SERIAL PRIMARY KEY NOT NULL,
+ old_name varchar(255) NOT NULL,
+ new_name varchar(255) NOT NULL,
+ expiration_time timestamp NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_repository_redirect_old_name UNIQUE (old_name)
+);
+
+CREATE INDEX repository_redirect_expiration_time ON repository_redirect (expiration_time);
+
+/*
+  The stars and subscriptions follow the repository when it is renamed
+*/
+ALTER TABLE repository_star DROP CONSTRAINT repository_star_repository_name_fkey,
+  ADD CONSTRAINT repository_star_repository_name_fkey FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE repository_subscription DROP CONSTRAINT repository_subscription_repository_name_fkey,
+  ADD CONSTRAINT repository_subscription_repository_name_fkey FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE ON UPDATE CASCADE;
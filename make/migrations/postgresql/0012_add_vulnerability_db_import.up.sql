@@ -0,0 +1,16 @@
+CREATE TABLE vulnerability_db_import (
+ id SERIAL PRIMARY KEY NOT NULL,
+ version varchar(64) NOT NULL,
+ /*
+  The status of the import, it can be "running", "succeeded" or "failed"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ vulnerability_count int DEFAULT 0 NOT NULL,
+ bundle_creation_time timestamp,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER vulnerability_db_import_update_time_at_modtime BEFORE UPDATE ON vulnerability_db_import FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,4 @@
+/*
+  The detailed progress checked in by the admin job, e.g. "2/3 rebuilding the star counts of repositories"
+*/
+ALTER TABLE admin_job ADD COLUMN progress varchar(255) DEFAULT '' NOT NULL;
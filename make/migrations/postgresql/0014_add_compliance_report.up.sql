@@ -0,0 +1,23 @@
+CREATE TABLE compliance_report (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ /*
+  The time range of the audit logs included in the report
+ */
+ start_time timestamp NOT NULL,
+ end_time timestamp NOT NULL,
+ /*
+  The status of the report, it can be "running", "succeeded" or "failed"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ size bigint DEFAULT 0 NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
+
+CREATE INDEX compliance_report_project_id ON compliance_report (project_id);
+
+CREATE TRIGGER compliance_report_update_time_at_modtime BEFORE UPDATE ON compliance_report FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
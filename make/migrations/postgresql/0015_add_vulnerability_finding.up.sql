@@ -0,0 +1,17 @@
+/*
+  The vulnerabilities found by the latest scan of the images, they are replaced
+  when the image is rescanned and used to search the images affected by a vulnerability
+*/
+CREATE TABLE vulnerability_finding (
+ id SERIAL PRIMARY KEY NOT NULL,
+ digest varchar(128) NOT NULL,
+ cve_id varchar(128) NOT NULL,
+ package varchar(255) NOT NULL,
+ version varchar(255),
+ fixed_version varchar(255),
+ severity int NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX vulnerability_finding_cve_id ON vulnerability_finding (cve_id);
+CREATE INDEX vulnerability_finding_digest ON vulnerability_finding (digest);
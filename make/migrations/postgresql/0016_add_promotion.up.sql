@@ -0,0 +1,43 @@
+CREATE TABLE promotion_pipeline (
+ id SERIAL PRIMARY KEY NOT NULL,
+ name varchar(255) NOT NULL,
+ description text,
+ /*
+  The stages of the pipeline in JSON, each stage is a project with the gates checked
+  before an image is promoted into it
+ */
+ stages text NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ UNIQUE (name)
+);
+
+CREATE TRIGGER promotion_pipeline_update_time_at_modtime BEFORE UPDATE ON promotion_pipeline FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
+
+CREATE TABLE promotion (
+ id SERIAL PRIMARY KEY NOT NULL,
+ pipeline_id int NOT NULL,
+ /*
+  The image promoted from the source repository into the project of the target stage
+ */
+ repository varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ digest varchar(128) NOT NULL,
+ stage int NOT NULL,
+ target_repository varchar(255) NOT NULL,
+ /*
+  The status of the promotion, it can be "pending", "succeeded", "failed" or "rejected"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ requester varchar(255),
+ approver varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (pipeline_id) REFERENCES promotion_pipeline(id)
+);
+
+CREATE INDEX promotion_pipeline_id ON promotion (pipeline_id);
+
+CREATE TRIGGER promotion_update_time_at_modtime BEFORE UPDATE ON promotion FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,32 @@
+CREATE TABLE approval (
+ id SERIAL PRIMARY KEY NOT NULL,
+ /*
+  The type of the approval, it can be "access_request", "promotion" or "project_creation",
+  the resource is the access request or promotion waiting for the approval
+ */
+ type varchar(32) NOT NULL,
+ resource_id int DEFAULT 0 NOT NULL,
+ /*
+  The approval is reviewed by the admins of the project, it's reviewed by the system admins if project_id is 0
+ */
+ project_id int DEFAULT 0 NOT NULL,
+ requester varchar(255),
+ summary text,
+ /*
+  The data needed by the approved action, e.g. the request to create the project
+ */
+ payload text,
+ /*
+  The status of the approval, it can be "pending", "approved" or "rejected"
+ */
+ status varchar(16) NOT NULL,
+ reviewer varchar(255),
+ comment text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX approval_type_resource_id ON approval (type, resource_id);
+CREATE INDEX approval_status_project_id ON approval (status, project_id);
+
+CREATE TRIGGER approval_update_time_at_modtime BEFORE UPDATE ON approval FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
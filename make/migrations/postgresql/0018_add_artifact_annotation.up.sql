@@ -0,0 +1,22 @@
+/*
+  The CI metadata of the tags read from the annotations of the images, it's used to trace
+  the images back to the source revisions and the pipeline runs building them
+*/
+CREATE TABLE artifact_annotation (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ digest varchar(128) NOT NULL,
+ revision varchar(255),
+ source varchar(1024),
+ build_url varchar(1024),
+ build_id varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_artifact_annotation UNIQUE (repository, tag)
+);
+
+CREATE INDEX artifact_annotation_revision ON artifact_annotation (revision);
+CREATE INDEX artifact_annotation_build_id ON artifact_annotation (build_id);
+
+CREATE TRIGGER artifact_annotation_update_time_at_modtime BEFORE UPDATE ON artifact_annotation FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
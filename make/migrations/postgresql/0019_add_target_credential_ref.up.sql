@@ -0,0 +1,5 @@
+/*
+  The reference of the credential stored in the external secret store, e.g. "vault:secret/data/harbor/registry-a"
+  or "k8s:harbor/registry-a", the credential is read when the replication is executed
+*/
+ALTER TABLE replication_target ADD COLUMN credential_ref varchar(255);
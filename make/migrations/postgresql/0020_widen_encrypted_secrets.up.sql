@@ -0,0 +1,6 @@
+/*
+  The secrets encrypted with the master key ring are longer than the ones encrypted with the secret key,
+  e.g. "<enc-v2>k1:<wrapped data key>:<ciphertext>", widen the columns storing the encrypted secrets
+*/
+ALTER TABLE replication_target ALTER COLUMN password TYPE varchar(1024);
+ALTER TABLE properties ALTER COLUMN v TYPE varchar(1024);
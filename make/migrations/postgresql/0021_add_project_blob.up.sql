@@ -0,0 +1,12 @@
+/*
+  The blobs (including the manifests) pushed into the projects, the storage usage
+  of a project is the total size of its blobs and is checked against the quota
+*/
+CREATE TABLE project_blob (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ digest varchar(128) NOT NULL,
+ size bigint NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ UNIQUE (project_id, digest)
+);
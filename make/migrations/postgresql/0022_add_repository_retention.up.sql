@@ -0,0 +1,17 @@
+/*
+  The repositories overriding the retention policy of their projects, "mode" is
+  "override" with the policy of the repository or "disabled" to opt out the retention,
+  the repositories without record follow the policy of the project
+*/
+CREATE TABLE repository_retention (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository_name varchar(255) NOT NULL,
+ mode varchar(16) NOT NULL,
+ policy text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE ON UPDATE CASCADE,
+ CONSTRAINT unique_repository_retention UNIQUE (repository_name)
+);
+
+CREATE TRIGGER repository_retention_update_time_at_modtime BEFORE UPDATE ON repository_retention FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
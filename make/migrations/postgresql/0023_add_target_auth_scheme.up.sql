@@ -0,0 +1,7 @@
+/*
+  The auth scheme, customized token realm and path mappings of the targets, which
+  support replicating to the generic Docker Registry v2 endpoints
+*/
+ALTER TABLE replication_target ADD COLUMN auth_scheme varchar(16);
+ALTER TABLE replication_target ADD COLUMN token_realm varchar(255);
+ALTER TABLE replication_target ADD COLUMN path_mappings text;
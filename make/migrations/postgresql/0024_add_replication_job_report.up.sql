@@ -0,0 +1,6 @@
+/*
+  The report checked in by the replication jobs, "size" is the total bytes of the
+  replicated images and "error" is the message of the failure
+*/
+ALTER TABLE replication_job ADD COLUMN size bigint NOT NULL DEFAULT 0;
+ALTER TABLE replication_job ADD COLUMN error text;
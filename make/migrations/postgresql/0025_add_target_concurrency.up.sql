@@ -0,0 +1,6 @@
+/*
+  The max concurrent transfers to the target (0 means unlimited) and the times of retrying
+  the requests with backoff when the target responds with 429 or 5xx (0 means no retry)
+*/
+ALTER TABLE replication_target ADD COLUMN max_concurrent_transfers int NOT NULL DEFAULT 0;
+ALTER TABLE replication_target ADD COLUMN backoff_retries int NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+/*
+  The immutable history of the tag mutations, the records are kept after the tag
+  and repository are deleted
+*/
+CREATE TABLE tag_history (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ digest varchar(128) NOT NULL,
+ operation varchar(32) NOT NULL,
+ operator varchar(255),
+ op_time timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX tag_history_repo_tag_time ON tag_history (repository, tag, op_time);
@@ -0,0 +1,28 @@
+CREATE TABLE project_merge (
+ id SERIAL PRIMARY KEY NOT NULL,
+ /*
+  The source project is retired after merging, so its name is recorded as well
+ */
+ source_project_id int NOT NULL,
+ source_project_name varchar(255) NOT NULL,
+ target_project_id int NOT NULL,
+ /*
+  The rule to deduplicate the members, it can be "higher_role" or "keep_target"
+ */
+ member_rule varchar(16) NOT NULL,
+ /*
+  The status of the merge, it can be "running", "succeeded" or "failed"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ moved_repositories int DEFAULT 0 NOT NULL,
+ moved_members int DEFAULT 0 NOT NULL,
+ moved_robots int DEFAULT 0 NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (source_project_id) REFERENCES project(project_id),
+ FOREIGN KEY (target_project_id) REFERENCES project(project_id)
+);
+
+CREATE TRIGGER project_merge_update_time_at_modtime BEFORE UPDATE ON project_merge FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
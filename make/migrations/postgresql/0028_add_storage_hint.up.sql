@@ -0,0 +1,29 @@
+/*
+ The storage hint in the metadata of the project can be overridden by the repository,
+ the mode can be "override" or "disabled"
+*/
+CREATE TABLE repository_storage_hint (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository_name varchar(255) NOT NULL,
+ mode varchar(16) NOT NULL,
+ hint text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE ON UPDATE CASCADE,
+ CONSTRAINT unique_repository_storage_hint UNIQUE (repository_name)
+);
+
+CREATE TRIGGER repository_storage_hint_update_time_at_modtime BEFORE UPDATE ON repository_storage_hint FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
+
+/*
+ The blobs transitioned to other storage classes by the storage transition job, which
+ are skipped in the later runs and summarized as the savings
+*/
+CREATE TABLE blob_transition (
+ id SERIAL PRIMARY KEY NOT NULL,
+ digest varchar(255) NOT NULL,
+ storage_class varchar(32) NOT NULL,
+ size bigint NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_blob_transition UNIQUE (digest)
+);
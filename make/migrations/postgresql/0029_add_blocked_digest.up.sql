@@ -0,0 +1,12 @@
+/*
+ The digests of the manifests and blobs refused on pull and push across all the projects,
+ e.g. the known-compromised images
+*/
+CREATE TABLE blocked_digest (
+ id SERIAL PRIMARY KEY NOT NULL,
+ digest varchar(255) NOT NULL,
+ reason text,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_blocked_digest UNIQUE (digest)
+);
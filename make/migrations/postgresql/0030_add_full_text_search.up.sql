@@ -0,0 +1,12 @@
+/*
+ All the labels of the image config are kept with the CI metadata of the tags, so that the
+ ticket IDs or component names embedded in them can be searched
+*/
+ALTER TABLE artifact_annotation ADD COLUMN labels text;
+
+/*
+ The full-text indexes over the repository descriptions and the annotations of the tags,
+ the expressions must be the same as the ones used by the search in dao
+*/
+CREATE INDEX repository_full_text ON repository USING gin (to_tsvector('simple', name || ' ' || coalesce(description, '')));
+CREATE INDEX artifact_annotation_full_text ON artifact_annotation USING gin (to_tsvector('simple', repository || ' ' || tag || ' ' || coalesce(revision, '') || ' ' || coalesce(build_id, '') || ' ' || coalesce(labels, '')));
@@ -0,0 +1,16 @@
+/*
+ The traffic of the registry API per repository and per day recorded by the proxy of core,
+ it's used by the statistics API for the chargeback and showback
+*/
+CREATE TABLE registry_traffic (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ repository varchar(255) NOT NULL,
+ day date NOT NULL,
+ request_count bigint NOT NULL DEFAULT 0,
+ error_count bigint NOT NULL DEFAULT 0,
+ bytes_served bigint NOT NULL DEFAULT 0,
+ CONSTRAINT unique_registry_traffic UNIQUE (repository, day)
+);
+
+CREATE INDEX registry_traffic_project_day ON registry_traffic (project_id, day);
@@ -0,0 +1,40 @@
+/*
+ The chargeback reports of the billing periods, each report has one item per project
+*/
+CREATE TABLE chargeback_report (
+ id SERIAL PRIMARY KEY NOT NULL,
+ /*
+  The billing period of the report, the start time is included and the end time is excluded
+ */
+ start_time timestamp NOT NULL,
+ end_time timestamp NOT NULL,
+ /*
+  The status of the report, it can be "running", "succeeded" or "failed"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_chargeback_report_period UNIQUE (start_time, end_time)
+);
+
+CREATE TRIGGER chargeback_report_update_time_at_modtime BEFORE UPDATE ON chargeback_report FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
+
+/*
+ The usage of a project in the billing period of the report, the storage is the size of
+ the blobs of the project when the report is generated
+*/
+CREATE TABLE chargeback_report_item (
+ id SERIAL PRIMARY KEY NOT NULL,
+ report_id int NOT NULL,
+ project_id int NOT NULL,
+ project_name varchar(255) NOT NULL,
+ storage_bytes bigint NOT NULL DEFAULT 0,
+ pull_bytes bigint NOT NULL DEFAULT 0,
+ scan_job_minutes double precision NOT NULL DEFAULT 0,
+ replication_job_minutes double precision NOT NULL DEFAULT 0,
+ FOREIGN KEY (report_id) REFERENCES chargeback_report(id) ON DELETE CASCADE
+);
+
+CREATE INDEX chargeback_report_item_report_id ON chargeback_report_item (report_id);
@@ -0,0 +1,5 @@
+/*
+ The access granted to the robot in JSON, it's the union of the access in the tokens issued to
+ the robot. The tokens aren't stored, so the access of the robots created before is unknown
+*/
+ALTER TABLE robot ADD COLUMN access text;
@@ -0,0 +1,13 @@
+/*
+ The state of the metadata sync of the projects with the peer instance, the digest is the one
+ of the configuration of the project agreed by the two instances in the last sync. The message
+ records the conflict or error of the last sync
+*/
+CREATE TABLE metadata_sync_state (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_name varchar(255) NOT NULL,
+ digest varchar(128),
+ message text,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_metadata_sync_state_project UNIQUE (project_name)
+);
@@ -0,0 +1,20 @@
+/*
+ The custom CA bundle in PEM trusted by the clients of the replication target in addition to
+ the system ones, it's encrypted as the password
+*/
+ALTER TABLE replication_target ADD COLUMN ca_bundle text;
+
+/*
+ The custom CA bundles of the scanner adapters, the scanners are registered in code rather
+ than the database so the bundles are keyed by the ID of the scanner
+*/
+CREATE TABLE scanner_ca_bundle (
+ id SERIAL PRIMARY KEY NOT NULL,
+ scanner_id varchar(64) NOT NULL,
+ ca_bundle text NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_scanner_ca_bundle_scanner UNIQUE (scanner_id)
+);
+
+CREATE TRIGGER scanner_ca_bundle_update_time_at_modtime BEFORE UPDATE ON scanner_ca_bundle FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,23 @@
+/*
+ The repositories created by the API before the first push, they are kept when the
+ repositories are synced from the registry
+*/
+ALTER TABLE repository ADD COLUMN pre_created boolean NOT NULL DEFAULT false;
+
+/*
+ The access granted to the users on the repository in addition to the one granted by the
+ project, the access is "pull" or "push" which includes "pull"
+*/
+CREATE TABLE repository_acl (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository_name varchar(255) NOT NULL,
+ user_id int NOT NULL,
+ access varchar(16) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id),
+ FOREIGN KEY (repository_name) REFERENCES repository(name) ON DELETE CASCADE ON UPDATE CASCADE,
+ CONSTRAINT unique_repository_acl UNIQUE (repository_name, user_id)
+);
+
+CREATE TRIGGER repository_acl_update_time_at_modtime BEFORE UPDATE ON repository_acl FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
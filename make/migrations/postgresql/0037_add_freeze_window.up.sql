@@ -0,0 +1,22 @@
+CREATE TABLE freeze_window (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ /*
+  The first occurrence of the window, the pushes and tag deletions are rejected in it
+ */
+ start_time timestamp NOT NULL,
+ end_time timestamp NOT NULL,
+ /*
+  How the window repeats, it can be "none", "daily" or "weekly"
+ */
+ repeat varchar(16) NOT NULL,
+ message text,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
+
+CREATE INDEX freeze_window_project_id ON freeze_window (project_id);
+
+CREATE TRIGGER freeze_window_update_time_at_modtime BEFORE UPDATE ON freeze_window FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,15 @@
+/*
+  The findings of the push-time linter on the tags, the findings are in JSON
+*/
+CREATE TABLE artifact_lint (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ digest varchar(128) NOT NULL,
+ findings text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_artifact_lint UNIQUE (repository, tag)
+);
+
+CREATE TRIGGER artifact_lint_update_time_at_modtime BEFORE UPDATE ON artifact_lint FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
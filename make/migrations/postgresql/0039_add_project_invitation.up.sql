@@ -0,0 +1,21 @@
+/*
+  The invitation links of the projects, the authenticated users accepting the link before
+  the expiration time are added as the members of the project with the role
+*/
+CREATE TABLE project_invitation (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ token varchar(64) NOT NULL,
+ role int NOT NULL,
+ expiration_time timestamp NOT NULL,
+ accepted_count int DEFAULT 0 NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ CONSTRAINT unique_project_invitation_token UNIQUE (token)
+);
+
+CREATE INDEX project_invitation_project_id ON project_invitation (project_id);
+
+CREATE TRIGGER project_invitation_update_time_at_modtime BEFORE UPDATE ON project_invitation FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
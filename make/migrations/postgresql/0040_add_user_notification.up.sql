@@ -0,0 +1,17 @@
+/*
+  The in-app notifications of the users, e.g. the scan is done or the replication fails
+*/
+CREATE TABLE user_notification (
+ id SERIAL PRIMARY KEY NOT NULL,
+ user_id int NOT NULL,
+ type varchar(64) NOT NULL,
+ message text NOT NULL,
+ read boolean DEFAULT false NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_notification_user_id ON user_notification (user_id, read);
+
+CREATE TRIGGER user_notification_update_time_at_modtime BEFORE UPDATE ON user_notification FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
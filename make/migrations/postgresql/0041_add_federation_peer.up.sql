@@ -0,0 +1,18 @@
+/*
+  The peer Harbor instances of the federation, the global search fans out to them with
+  the credentials when it's federated, the password is encrypted
+*/
+CREATE TABLE federation_peer (
+ id SERIAL PRIMARY KEY NOT NULL,
+ name varchar(255) NOT NULL,
+ url varchar(1024) NOT NULL,
+ username varchar(255),
+ password varchar(1024),
+ insecure boolean DEFAULT false NOT NULL,
+ timeout int DEFAULT 10 NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_federation_peer_name UNIQUE (name)
+);
+
+CREATE TRIGGER federation_peer_update_time_at_modtime BEFORE UPDATE ON federation_peer FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,19 @@
+/*
+  The deprecations of the repositories and tags, the empty tag means the whole repository
+  is deprecated. The pulls of the deprecated images are counted as hits
+*/
+CREATE TABLE repository_deprecation (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ tag varchar(255) DEFAULT '' NOT NULL,
+ message varchar(1024) NOT NULL,
+ replacement varchar(255),
+ creator varchar(255),
+ hit_count bigint DEFAULT 0 NOT NULL,
+ last_hit_time timestamp,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_repository_deprecation UNIQUE (repository, tag)
+);
+
+CREATE TRIGGER repository_deprecation_update_time_at_modtime BEFORE UPDATE ON repository_deprecation FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,17 @@
+/*
+  The alias tags managed by the API, e.g. "stable" pointing to the digest of "v1.4.2",
+  the pushes to the alias tags are refused
+*/
+CREATE TABLE tag_alias (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ alias varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ digest varchar(128) NOT NULL,
+ operator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT unique_tag_alias UNIQUE (repository, alias)
+);
+
+CREATE TRIGGER tag_alias_update_time_at_modtime BEFORE UPDATE ON tag_alias FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
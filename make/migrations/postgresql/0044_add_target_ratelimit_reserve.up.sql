@@ -0,0 +1,5 @@
+/*
+  The remaining budget of the rate limit of the target reserved for the other clients, the
+  transfers to the target are deferred when the budget falls to it (0 means never)
+*/
+ALTER TABLE replication_target ADD COLUMN ratelimit_reserve int NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+/*
+  The name of the job which the log belongs to, the logs of the different jobs are
+  retained for the different days
+*/
+ALTER TABLE job_log ADD COLUMN job_name varchar(64) NOT NULL DEFAULT '';
+CREATE INDEX job_log_name ON job_log (job_name);
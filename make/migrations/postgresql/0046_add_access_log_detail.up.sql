@@ -0,0 +1,5 @@
+/*
+  The detail of the operation, e.g. the field-level changes of the configurations and
+  the policies in JSON
+*/
+ALTER TABLE access_log ADD COLUMN detail text;
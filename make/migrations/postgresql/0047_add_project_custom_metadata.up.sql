@@ -0,0 +1,18 @@
+/*
+  The arbitrary key/value metadata of projects set by the users, e.g. the team owner
+  and the cost center, they aren't interpreted by Harbor
+*/
+CREATE TABLE project_custom_metadata (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ name varchar(64) NOT NULL,
+ value varchar(255) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id) ON DELETE CASCADE,
+ CONSTRAINT unique_project_custom_metadata UNIQUE (project_id, name)
+);
+
+CREATE INDEX project_custom_metadata_name_value ON project_custom_metadata (name, value);
+
+CREATE TRIGGER project_custom_metadata_update_time_at_modtime BEFORE UPDATE ON project_custom_metadata FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,13 @@
+/*
+  The members subscribing the scheduled report emails of the projects, the report
+  summarizes the activities, the new vulnerabilities and the quota status
+*/
+CREATE TABLE project_report_subscription (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ user_id int NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id) ON DELETE CASCADE,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id) ON DELETE CASCADE,
+ CONSTRAINT unique_project_report_subscription UNIQUE (project_id, user_id)
+);
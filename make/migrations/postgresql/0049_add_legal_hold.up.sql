@@ -0,0 +1,27 @@
+/*
+  The legal holds keep the repositories or the tags from being deleted, by the users,
+  the retention or the moving of the repositories, until they are released. The released
+  holds are kept as the history
+*/
+CREATE TABLE legal_hold (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ /* empty if the whole repository is held */
+ tag varchar(128) NOT NULL DEFAULT '',
+ reason varchar(1024) NOT NULL,
+ creator varchar(255) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ releaser varchar(255) NOT NULL DEFAULT '',
+ release_reason varchar(1024) NOT NULL DEFAULT '',
+ release_time timestamp
+);
+
+/* only one active hold of the repository or the tag */
+CREATE UNIQUE INDEX unique_active_legal_hold ON legal_hold (repository, tag) WHERE release_time IS NULL;
+
+/* the users who can place and release the legal holds, granted by the system admins */
+CREATE TABLE compliance_officer (
+ user_id int PRIMARY KEY NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,22 @@
+/*
+  The share links let the holders of their tokens pull one image of a private project
+  without being onboarded, until they expire or are revoked. The revoked links are kept
+  with the usage as the history
+*/
+CREATE TABLE share_link (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ repository varchar(255) NOT NULL,
+ /* the shared tag or digest */
+ reference varchar(255) NOT NULL,
+ description varchar(1024) NOT NULL DEFAULT '',
+ creator varchar(255) NOT NULL,
+ expires_at timestamp NOT NULL,
+ revoked boolean NOT NULL DEFAULT false,
+ pull_count int NOT NULL DEFAULT 0,
+ last_pull_time timestamp,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
+
+CREATE INDEX share_link_project ON share_link (project_id);
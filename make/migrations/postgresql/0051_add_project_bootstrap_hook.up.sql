@@ -0,0 +1,14 @@
+/*
+  The bootstrap hooks are the templates of the project configuration documents applied
+  to every new project right after its creation, e.g. to create the default robots and labels
+*/
+CREATE TABLE project_bootstrap_hook (
+ id SERIAL PRIMARY KEY NOT NULL,
+ name varchar(255) NOT NULL,
+ description varchar(1024) NOT NULL DEFAULT '',
+ template text NOT NULL,
+ enabled boolean NOT NULL DEFAULT true,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ UNIQUE (name)
+);
@@ -0,0 +1,22 @@
+/*
+  The batches of scans expand the filters of projects, repositories, tags and labels into the
+  scan jobs of the matched images on the server side, the jobs are tracked by the batch
+*/
+CREATE TABLE scan_batch (
+ id SERIAL PRIMARY KEY NOT NULL,
+ /* 0 means all the projects */
+ project_id int NOT NULL DEFAULT 0,
+ repository varchar(255) NOT NULL DEFAULT '',
+ tag varchar(255) NOT NULL DEFAULT '',
+ label_id int NOT NULL DEFAULT 0,
+ creator varchar(255) NOT NULL,
+ status varchar(32) NOT NULL,
+ total int NOT NULL DEFAULT 0,
+ failed int NOT NULL DEFAULT 0,
+ error varchar(1024) NOT NULL DEFAULT '',
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP
+);
+
+ALTER TABLE img_scan_job ADD COLUMN batch_id int;
+CREATE INDEX idx_batch_id ON img_scan_job (batch_id);
@@ -0,0 +1,17 @@
+/*
+ The daily snapshots of the vulnerabilities of the images in the projects, the counts are the ones
+ of the vulnerable components by severity summed over the distinct images of the project
+*/
+CREATE TABLE vulnerability_trend (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ day date NOT NULL,
+ images int NOT NULL DEFAULT 0,
+ scanned int NOT NULL DEFAULT 0,
+ negligible int NOT NULL DEFAULT 0,
+ unknown int NOT NULL DEFAULT 0,
+ low int NOT NULL DEFAULT 0,
+ medium int NOT NULL DEFAULT 0,
+ high int NOT NULL DEFAULT 0,
+ CONSTRAINT unique_vulnerability_trend UNIQUE (project_id, day)
+);
@@ -0,0 +1,18 @@
+/*
+  The robots of the projects are replicated to the Harbor targets of the policies with
+  replicate_robots on, the tokens re-issued by the targets under their own keys are kept
+  encrypted here so the CI credentials can be switched over when failing over
+*/
+ALTER TABLE replication_policy ADD COLUMN replicate_robots boolean NOT NULL DEFAULT false;
+
+CREATE TABLE robot_replica (
+ id SERIAL PRIMARY KEY NOT NULL,
+ robot_id int NOT NULL,
+ target_id int NOT NULL,
+ token text NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (robot_id) REFERENCES robot(id) ON DELETE CASCADE,
+ FOREIGN KEY (target_id) REFERENCES replication_target(id) ON DELETE CASCADE,
+ CONSTRAINT unique_robot_replica UNIQUE (robot_id, target_id)
+);
@@ -0,0 +1,10 @@
+/*
+  The IDs of the events of registry processed recently, the notifications of registry may be
+  delivered more than once, so the events are deduplicated by the IDs within the window
+*/
+CREATE TABLE registry_event (
+ event_id varchar(64) PRIMARY KEY NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX registry_event_creation_time ON registry_event (creation_time);
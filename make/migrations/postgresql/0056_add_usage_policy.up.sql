@@ -0,0 +1,21 @@
+/*
+  The usage policy of the project which the users must acknowledge before pulling, e.g. for the
+  export control or licensing, the acknowledgments are removed when the policy is changed
+*/
+CREATE TABLE usage_policy (
+ project_id int PRIMARY KEY NOT NULL,
+ policy text NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id) ON DELETE CASCADE
+);
+
+CREATE TABLE usage_policy_ack (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ user_id int NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES usage_policy(project_id) ON DELETE CASCADE,
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id) ON DELETE CASCADE,
+ CONSTRAINT unique_usage_policy_ack UNIQUE (project_id, user_id)
+);
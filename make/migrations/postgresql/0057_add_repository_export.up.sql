@@ -0,0 +1,23 @@
+CREATE TABLE repository_export (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ /*
+  The format of the dump, it can be "csv" or "json"
+ */
+ format varchar(8) NOT NULL,
+ /*
+  The status of the export, it can be "running", "succeeded" or "failed"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ repository_count int DEFAULT 0 NOT NULL,
+ size bigint DEFAULT 0 NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
+
+CREATE INDEX repository_export_project_id ON repository_export (project_id);
+
+CREATE TRIGGER repository_export_update_time_at_modtime BEFORE UPDATE ON repository_export FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
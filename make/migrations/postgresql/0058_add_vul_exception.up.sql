@@ -0,0 +1,20 @@
+/*
+  The exceptions of the policy "prevent vulnerable images from running" of the projects, the
+  images of the repository aren't prevented by the severity until the exception expires
+*/
+CREATE TABLE vul_exception (
+ id SERIAL PRIMARY KEY NOT NULL,
+ project_id int NOT NULL,
+ repository varchar(255) NOT NULL,
+ justification varchar(1024) NOT NULL,
+ expires_at timestamp NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ CONSTRAINT unique_vul_exception_repository UNIQUE (repository)
+);
+
+CREATE INDEX vul_exception_project_id ON vul_exception (project_id);
+
+CREATE TRIGGER vul_exception_update_time_at_modtime BEFORE UPDATE ON vul_exception FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,20 @@
+/*
+  The server-side copies of the images by retagging and promoting, the copies of an image
+  chained by the digest trace it back to the original build
+*/
+CREATE TABLE artifact_provenance (
+ id SERIAL PRIMARY KEY NOT NULL,
+ repository varchar(255) NOT NULL,
+ tag varchar(255) NOT NULL,
+ digest varchar(255) NOT NULL,
+ source_repository varchar(255) NOT NULL,
+ source_tag varchar(255) NOT NULL,
+ /*
+  The operation copying the image, it can be "retag" or "promote"
+ */
+ operation varchar(32) NOT NULL,
+ operator varchar(255),
+ op_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX artifact_provenance_repository_tag ON artifact_provenance (repository, tag, digest);
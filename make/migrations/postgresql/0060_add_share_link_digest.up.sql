@@ -0,0 +1,5 @@
+/*
+  The digest the reference of the share link resolved to when the link was created, the link
+  only grants the pull of the manifest with the digest and the blobs it references
+*/
+ALTER TABLE share_link ADD COLUMN digest varchar(255);
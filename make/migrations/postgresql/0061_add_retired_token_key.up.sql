@@ -0,0 +1,11 @@
+/*
+  The public keys of the token signing keys replaced by the rotations, the tokens signed
+  by them are verified by all the instances of core until they expire
+*/
+CREATE TABLE retired_token_key (
+ id SERIAL PRIMARY KEY NOT NULL,
+ public_key text NOT NULL,
+ expires_at timestamp NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ UNIQUE (public_key)
+);
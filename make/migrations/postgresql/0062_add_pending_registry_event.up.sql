@@ -0,0 +1,23 @@
+/*
+  The events of registry received but not processed yet, they're persisted before the
+  notifications are acknowledged and removed once processed, so they survive the restarts
+*/
+CREATE TABLE pending_registry_event (
+ id SERIAL PRIMARY KEY NOT NULL,
+ event_id varchar(64),
+ repository varchar(255) NOT NULL,
+ /*
+  The event in JSON
+ */
+ event text NOT NULL,
+ /*
+  The instance of core holding the event, it refreshes the heartbeat of the events
+  periodically, the ones whose heartbeat expires are taken over by the other instances
+ */
+ owner varchar(64) NOT NULL,
+ heartbeat timestamp NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX pending_registry_event_owner ON pending_registry_event (owner);
+CREATE INDEX pending_registry_event_heartbeat ON pending_registry_event (heartbeat);
@@ -0,0 +1,14 @@
+/*
+  The locks of the resources held by the tasks running in background, e.g. the projects
+  being merged, they're shared by all the instances of core and released once the tasks end
+*/
+CREATE TABLE task_lock (
+ resource varchar(255) PRIMARY KEY NOT NULL,
+ /*
+  The task holding the lock, e.g. "PROJECT_MERGE:1"
+ */
+ owner varchar(255) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX task_lock_owner ON task_lock (owner);
@@ -0,0 +1,14 @@
+/*
+  The tokens confirming the destructive operations, they're shared by all the instances
+  of core so the confirmed request can be handled by any of them
+*/
+CREATE TABLE confirmation_token (
+ token varchar(255) PRIMARY KEY NOT NULL,
+ username varchar(255) NOT NULL,
+ method varchar(16) NOT NULL,
+ path varchar(1024) NOT NULL,
+ expires_at timestamp NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX confirmation_token_expires_at ON confirmation_token (expires_at);
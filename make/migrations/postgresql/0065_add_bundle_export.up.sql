@@ -0,0 +1,18 @@
+CREATE TABLE bundle_export (
+ id SERIAL PRIMARY KEY NOT NULL,
+ /*
+  The names of the exported projects separated by comma
+ */
+ projects text NOT NULL,
+ /*
+  The status of the export, it can be "running", "succeeded" or "failed"
+ */
+ status varchar(16) NOT NULL,
+ message text,
+ size bigint DEFAULT 0 NOT NULL,
+ creator varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER bundle_export_update_time_at_modtime BEFORE UPDATE ON bundle_export FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -16,7 +16,7 @@ package encrypt
 
 import (
 	comcfg "github.com/goharbor/harbor/src/common/config"
-	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/keyring"
 )
 
 // Encryptor encrypts or decrypts a strings
@@ -47,7 +47,7 @@ func (a *AESEncryptor) Encrypt(plaintext string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return utils.ReversibleEncrypt(plaintext, key)
+	return keyring.Encrypt(plaintext, key)
 }
 
 // Decrypt ...
@@ -56,5 +56,5 @@ func (a *AESEncryptor) Decrypt(ciphertext string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return utils.ReversibleDecrypt(ciphertext, key)
+	return keyring.Decrypt(ciphertext, key)
 }
@@ -31,26 +31,39 @@ const (
 
 var (
 	numKeys = map[string]bool{
-		common.EmailPort:            true,
-		common.LDAPScope:            true,
-		common.LDAPGroupSearchScope: true,
-		common.LDAPTimeout:          true,
-		common.TokenExpiration:      true,
-		common.MaxJobWorkers:        true,
-		common.CfgExpiration:        true,
-		common.ClairDBPort:          true,
-		common.PostGreSQLPort:       true,
+		common.EmailPort:               true,
+		common.LDAPScope:               true,
+		common.LDAPGroupSearchScope:    true,
+		common.LDAPTimeout:             true,
+		common.TokenExpiration:         true,
+		common.MaxJobWorkers:           true,
+		common.CfgExpiration:           true,
+		common.ClairDBPort:             true,
+		common.PostGreSQLPort:          true,
+		common.ExternalAuthzCacheTTL:   true,
+		common.UploadPurgingAge:        true,
+		common.ManifestCacheTTL:        true,
+		common.RenameRedirectPeriod:    true,
+		common.TokenKeyGracePeriod:     true,
+		common.BreakerThreshold:        true,
+		common.BreakerProbeInterval:    true,
+		common.MaxJSONBodySize:         true,
+		common.MaxChartUploadSize:      true,
+		common.MaxLogQuerySize:         true,
+		common.TokenExchangeExpiration: true,
 	}
 	boolKeys = map[string]bool{
-		common.WithClair:        true,
-		common.WithNotary:       true,
-		common.SelfRegistration: true,
-		common.EmailSSL:         true,
-		common.EmailInsecure:    true,
-		common.LDAPVerifyCert:   true,
-		common.UAAVerifyCert:    true,
-		common.ReadOnly:         true,
-		common.WithChartMuseum:  true,
+		common.WithClair:                 true,
+		common.WithNotary:                true,
+		common.SelfRegistration:          true,
+		common.EmailSSL:                  true,
+		common.EmailInsecure:             true,
+		common.LDAPVerifyCert:            true,
+		common.UAAVerifyCert:             true,
+		common.ReadOnly:                  true,
+		common.WithChartMuseum:           true,
+		common.ExternalAuthzFailOpen:     true,
+		common.DestructiveOpConfirmation: true,
 	}
 	mapKeys = map[string]bool{
 		common.ScanAllPolicy: true,
@@ -143,7 +156,7 @@ func TranslateConfig(config map[string]interface{}) ([]models.ConfigEntry, error
 			}
 			entry.Value = string(data)
 		default:
-			return nil, fmt.Errorf("unknown type %v", v)
+			return nil, fmt.Errorf("unknown type %v of %s", v, k)
 		}
 		configEntries = append(configEntries, *entry)
 	}
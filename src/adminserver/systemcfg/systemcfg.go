@@ -46,14 +46,7 @@ var (
 	CfgStore store.Driver
 
 	// attrs need to be encrypted or decrypted
-	attrs = []string{
-		common.EmailPassword,
-		common.LDAPSearchPwd,
-		common.PostGreSQLPassword,
-		common.AdminInitialPassword,
-		common.ClairDBPassword,
-		common.UAAClientSecret,
-	}
+	attrs = common.HarborEncryptedKeys
 
 	// all configurations need read from environment variables
 	allEnvs = map[string]interface{}{
@@ -69,16 +62,32 @@ var (
 			env:   "POSTGRESQL_PORT",
 			parse: parseStringToInt,
 		},
-		common.PostGreSQLUsername: "POSTGRESQL_USERNAME",
-		common.PostGreSQLPassword: "POSTGRESQL_PASSWORD",
-		common.PostGreSQLDatabase: "POSTGRESQL_DATABASE",
-		common.PostGreSQLSSLMode:  "POSTGRESQL_SSLMODE",
-		common.LDAPURL:            "LDAP_URL",
-		common.LDAPSearchDN:       "LDAP_SEARCH_DN",
-		common.LDAPSearchPwd:      "LDAP_SEARCH_PWD",
-		common.LDAPBaseDN:         "LDAP_BASE_DN",
-		common.LDAPFilter:         "LDAP_FILTER",
-		common.LDAPUID:            "LDAP_UID",
+		common.PostGreSQLUsername:      "POSTGRESQL_USERNAME",
+		common.PostGreSQLPassword:      "POSTGRESQL_PASSWORD",
+		common.PostGreSQLDatabase:      "POSTGRESQL_DATABASE",
+		common.PostGreSQLSSLMode:       "POSTGRESQL_SSLMODE",
+		common.PostGreSQLSSLRootCert:   "POSTGRESQL_SSLROOTCERT",
+		common.PostGreSQLAuthMode:      "POSTGRESQL_AUTH_MODE",
+		common.PostGreSQLAWSRegion:     "POSTGRESQL_AWS_REGION",
+		common.PostGreSQLAzureClientID: "POSTGRESQL_AZURE_CLIENT_ID",
+		common.AccessLogStore:          "ACCESS_LOG_STORE",
+		common.AccessLogESURL:          "ACCESS_LOG_ES_URL",
+		common.AccessLogESIndex:        "ACCESS_LOG_ES_INDEX",
+		common.AccessLogESUsername:     "ACCESS_LOG_ES_USERNAME",
+		common.AccessLogESPassword:     "ACCESS_LOG_ES_PASSWORD",
+		common.MetadataSyncPeerURL:     "METADATA_SYNC_PEER_URL",
+		common.MetadataSyncSecret:      "METADATA_SYNC_SECRET",
+		common.AdmissionWebhookSecret:  "ADMISSION_WEBHOOK_SECRET",
+		common.MetadataSyncPriority: &parser{
+			env:   "METADATA_SYNC_PRIORITY",
+			parse: parseStringToInt,
+		},
+		common.LDAPURL:                 "LDAP_URL",
+		common.LDAPSearchDN:            "LDAP_SEARCH_DN",
+		common.LDAPSearchPwd:           "LDAP_SEARCH_PWD",
+		common.LDAPBaseDN:              "LDAP_BASE_DN",
+		common.LDAPFilter:              "LDAP_FILTER",
+		common.LDAPUID:                 "LDAP_UID",
 		common.LDAPScope: &parser{
 			env:   "LDAP_SCOPE",
 			parse: parseStringToInt,
@@ -155,12 +164,40 @@ var (
 			env:   "UAA_VERIFY_CERT",
 			parse: parseStringToBool,
 		},
-		common.CoreURL:                     "CORE_URL",
-		common.JobServiceURL:               "JOBSERVICE_URL",
-		common.TokenServiceURL:             "TOKEN_SERVICE_URL",
-		common.ClairURL:                    "CLAIR_URL",
-		common.NotaryURL:                   "NOTARY_URL",
-		common.RegistryStorageProviderName: "REGISTRY_STORAGE_PROVIDER_NAME",
+		common.CoreURL:                           "CORE_URL",
+		common.JobServiceURL:                     "JOBSERVICE_URL",
+		common.TokenServiceURL:                   "TOKEN_SERVICE_URL",
+		common.ClairURL:                          "CLAIR_URL",
+		common.NotaryURL:                         "NOTARY_URL",
+		common.RegistryStorageProviderName:       "REGISTRY_STORAGE_PROVIDER_NAME",
+		common.RegistryStorageOSSRegion:          "REGISTRY_STORAGE_OSS_REGION",
+		common.RegistryStorageOSSEndpoint:        "REGISTRY_STORAGE_OSS_ENDPOINT",
+		common.RegistryStorageOSSBucket:          "REGISTRY_STORAGE_OSS_BUCKET",
+		common.RegistryStorageOSSAccessKeyID:     "REGISTRY_STORAGE_OSS_ACCESS_KEY_ID",
+		common.RegistryStorageOSSAccessKeySecret: "REGISTRY_STORAGE_OSS_ACCESS_KEY_SECRET",
+		common.RegistryStorageOSSInternal: &parser{
+			env:   "REGISTRY_STORAGE_OSS_INTERNAL",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageOSSSecure: &parser{
+			env:   "REGISTRY_STORAGE_OSS_SECURE",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageOSSRootDirectory: "REGISTRY_STORAGE_OSS_ROOT_DIRECTORY",
+		common.RegistryStorageCOSRegion:        "REGISTRY_STORAGE_COS_REGION",
+		common.RegistryStorageCOSEndpoint:      "REGISTRY_STORAGE_COS_ENDPOINT",
+		common.RegistryStorageCOSBucket:        "REGISTRY_STORAGE_COS_BUCKET",
+		common.RegistryStorageCOSSecretID:      "REGISTRY_STORAGE_COS_SECRET_ID",
+		common.RegistryStorageCOSSecretKey:     "REGISTRY_STORAGE_COS_SECRET_KEY",
+		common.RegistryStorageCOSInternal: &parser{
+			env:   "REGISTRY_STORAGE_COS_INTERNAL",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageCOSSecure: &parser{
+			env:   "REGISTRY_STORAGE_COS_SECURE",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageCOSRootDirectory: "REGISTRY_STORAGE_COS_ROOT_DIRECTORY",
 		common.ReadOnly: &parser{
 			env:   "READ_ONLY",
 			parse: parseStringToBool,
@@ -172,6 +209,8 @@ var (
 			env:   "WITH_CHARTMUSEUM",
 			parse: parseStringToBool,
 		},
+		// the initial states of the feature flags, they are toggled at runtime via the API of core
+		common.FeatureFlags: "FEATURE_FLAGS",
 	}
 
 	// configurations need read from environment variables
@@ -183,10 +222,26 @@ var (
 			env:   "POSTGRESQL_PORT",
 			parse: parseStringToInt,
 		},
-		common.PostGreSQLUsername: "POSTGRESQL_USERNAME",
-		common.PostGreSQLPassword: "POSTGRESQL_PASSWORD",
-		common.PostGreSQLDatabase: "POSTGRESQL_DATABASE",
-		common.PostGreSQLSSLMode:  "POSTGRESQL_SSLMODE",
+		common.PostGreSQLUsername:      "POSTGRESQL_USERNAME",
+		common.PostGreSQLPassword:      "POSTGRESQL_PASSWORD",
+		common.PostGreSQLDatabase:      "POSTGRESQL_DATABASE",
+		common.PostGreSQLSSLMode:       "POSTGRESQL_SSLMODE",
+		common.PostGreSQLSSLRootCert:   "POSTGRESQL_SSLROOTCERT",
+		common.PostGreSQLAuthMode:      "POSTGRESQL_AUTH_MODE",
+		common.PostGreSQLAWSRegion:     "POSTGRESQL_AWS_REGION",
+		common.PostGreSQLAzureClientID: "POSTGRESQL_AZURE_CLIENT_ID",
+		common.AccessLogStore:          "ACCESS_LOG_STORE",
+		common.AccessLogESURL:          "ACCESS_LOG_ES_URL",
+		common.AccessLogESIndex:        "ACCESS_LOG_ES_INDEX",
+		common.AccessLogESUsername:     "ACCESS_LOG_ES_USERNAME",
+		common.AccessLogESPassword:     "ACCESS_LOG_ES_PASSWORD",
+		common.MetadataSyncPeerURL:     "METADATA_SYNC_PEER_URL",
+		common.MetadataSyncSecret:      "METADATA_SYNC_SECRET",
+		common.AdmissionWebhookSecret:  "ADMISSION_WEBHOOK_SECRET",
+		common.MetadataSyncPriority: &parser{
+			env:   "METADATA_SYNC_PRIORITY",
+			parse: parseStringToInt,
+		},
 		common.MaxJobWorkers: &parser{
 			env:   "MAX_JOB_WORKERS",
 			parse: parseStringToInt,
@@ -219,15 +274,43 @@ var (
 			env:   "UAA_VERIFY_CERT",
 			parse: parseStringToBool,
 		},
-		common.RegistryStorageProviderName: "REGISTRY_STORAGE_PROVIDER_NAME",
-		common.CoreURL:                     "CORE_URL",
-		common.JobServiceURL:               "JOBSERVICE_URL",
-		common.RegistryURL:                 "REGISTRY_URL",
-		common.TokenServiceURL:             "TOKEN_SERVICE_URL",
-		common.ClairURL:                    "CLAIR_URL",
-		common.NotaryURL:                   "NOTARY_URL",
-		common.DatabaseType:                "DATABASE_TYPE",
-		common.ChartRepoURL:                "CHART_REPOSITORY_URL",
+		common.RegistryStorageProviderName:       "REGISTRY_STORAGE_PROVIDER_NAME",
+		common.RegistryStorageOSSRegion:          "REGISTRY_STORAGE_OSS_REGION",
+		common.RegistryStorageOSSEndpoint:        "REGISTRY_STORAGE_OSS_ENDPOINT",
+		common.RegistryStorageOSSBucket:          "REGISTRY_STORAGE_OSS_BUCKET",
+		common.RegistryStorageOSSAccessKeyID:     "REGISTRY_STORAGE_OSS_ACCESS_KEY_ID",
+		common.RegistryStorageOSSAccessKeySecret: "REGISTRY_STORAGE_OSS_ACCESS_KEY_SECRET",
+		common.RegistryStorageOSSInternal: &parser{
+			env:   "REGISTRY_STORAGE_OSS_INTERNAL",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageOSSSecure: &parser{
+			env:   "REGISTRY_STORAGE_OSS_SECURE",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageOSSRootDirectory: "REGISTRY_STORAGE_OSS_ROOT_DIRECTORY",
+		common.RegistryStorageCOSRegion:        "REGISTRY_STORAGE_COS_REGION",
+		common.RegistryStorageCOSEndpoint:      "REGISTRY_STORAGE_COS_ENDPOINT",
+		common.RegistryStorageCOSBucket:        "REGISTRY_STORAGE_COS_BUCKET",
+		common.RegistryStorageCOSSecretID:      "REGISTRY_STORAGE_COS_SECRET_ID",
+		common.RegistryStorageCOSSecretKey:     "REGISTRY_STORAGE_COS_SECRET_KEY",
+		common.RegistryStorageCOSInternal: &parser{
+			env:   "REGISTRY_STORAGE_COS_INTERNAL",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageCOSSecure: &parser{
+			env:   "REGISTRY_STORAGE_COS_SECURE",
+			parse: parseStringToBool,
+		},
+		common.RegistryStorageCOSRootDirectory: "REGISTRY_STORAGE_COS_ROOT_DIRECTORY",
+		common.CoreURL:                         "CORE_URL",
+		common.JobServiceURL:                   "JOBSERVICE_URL",
+		common.RegistryURL:                     "REGISTRY_URL",
+		common.TokenServiceURL:                 "TOKEN_SERVICE_URL",
+		common.ClairURL:                        "CLAIR_URL",
+		common.NotaryURL:                       "NOTARY_URL",
+		common.DatabaseType:                    "DATABASE_TYPE",
+		common.ChartRepoURL:                    "CHART_REPOSITORY_URL",
 		common.WithChartMuseum: &parser{
 			env:   "WITH_CHARTMUSEUM",
 			parse: parseStringToBool,
@@ -433,6 +516,10 @@ func GetDatabaseFromCfg(cfg map[string]interface{}) *models.Database {
 	postgresql.Password = utils.SafeCastString(cfg[common.PostGreSQLPassword])
 	postgresql.Database = utils.SafeCastString(cfg[common.PostGreSQLDatabase])
 	postgresql.SSLMode = utils.SafeCastString(cfg[common.PostGreSQLSSLMode])
+	postgresql.SSLRootCert = utils.SafeCastString(cfg[common.PostGreSQLSSLRootCert])
+	postgresql.AuthMode = utils.SafeCastString(cfg[common.PostGreSQLAuthMode])
+	postgresql.AWSRegion = utils.SafeCastString(cfg[common.PostGreSQLAWSRegion])
+	postgresql.AzureClientID = utils.SafeCastString(cfg[common.PostGreSQLAzureClientID])
 	database.PostGreSQL = postgresql
 	return database
 }
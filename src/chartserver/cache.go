@@ -41,6 +41,9 @@ type ChartCacheConfig struct {
 	// Only support 'in-memory' and 'redis' now
 	DriverType string
 
+	// The name of the beego cache adapter, 'redis' is used if it's empty
+	Adapter string
+
 	// Align with config
 	Config string
 }
@@ -165,10 +168,14 @@ func initCacheDriver(cacheConfig *ChartCacheConfig) beego_cache.Cache {
 		return beego_cache.NewMemoryCache()
 	case cacheDriverRedis:
 		// New with retry
+		adapter := cacheConfig.Adapter
+		if len(adapter) == 0 {
+			adapter = cacheDriverRedis
+		}
 		count := 0
 		for {
 			count++
-			redisCache, err := beego_cache.NewCache(cacheDriverRedis, cacheConfig.Config)
+			redisCache, err := beego_cache.NewCache(adapter, cacheConfig.Config)
 			if err != nil {
 				// Just logged
 				hlog.Errorf("Failed to initialize redis cache: %s", err)
@@ -65,6 +65,16 @@ func (cc *ChartClient) DeleteContent(addr string) error {
 	return err
 }
 
+// PostContent posts the content to the addr
+func (cc *ChartClient) PostContent(addr string, body io.Reader) error {
+	response, err := cc.sendRequest(addr, http.MethodPost, body, []int{http.StatusCreated})
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
 // sendRequest sends requests to the addr with the specified spec
 func (cc *ChartClient) sendRequest(addr string, method string, body io.Reader, expectedCodes []int) (*http.Response, error) {
 	if len(strings.TrimSpace(addr)) == 0 {
@@ -1,6 +1,7 @@
 package chartserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -91,3 +92,16 @@ func (c *Controller) GetChartVersion(namespace, name, version string) (*helm_rep
 
 	return chartVersion, nil
 }
+
+// UploadChartVersion uploads the chart package to the namespace
+func (c *Controller) UploadChartVersion(namespace string, content []byte) error {
+	if len(namespace) == 0 {
+		return errors.New("empty namespace when uploading chart version")
+	}
+
+	if len(content) == 0 {
+		return errors.New("empty chart package for uploading")
+	}
+
+	return c.apiClient.PostContent(c.APIPrefix(namespace), bytes.NewReader(content))
+}
@@ -86,3 +86,20 @@ func TestGetChartVersionSummary(t *testing.T) {
 		t.Fatalf("expect chart version '0.2.0' but got '%s'", chartV.GetVersion())
 	}
 }
+
+// Test post /api/:repo/charts
+func TestUploadChartVersion(t *testing.T) {
+	s, c, err := createMockObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := c.UploadChartVersion("repo1", []byte("chart")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.UploadChartVersion("repo1", nil); err == nil {
+		t.Fatal("expect non nil error for empty chart package but got nil")
+	}
+}
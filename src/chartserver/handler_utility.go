@@ -214,6 +214,19 @@ func (c *Controller) SearchChart(q string, namespaces []string) ([]*search.Resul
 	return results, nil
 }
 
+// GetChartVersionContent returns the content of the package of the specified chart version
+func (c *Controller) GetChartVersionContent(namespace, chartName, version string) ([]byte, error) {
+	chartV, err := c.GetChartVersion(namespace, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(chartV.URLs) == 0 {
+		return nil, fmt.Errorf("no package found for chart %s:%s", chartName, version)
+	}
+
+	return c.getChartVersionContent(namespace, chartV.URLs[0])
+}
+
 // Get the content bytes of the chart version
 func (c *Controller) getChartVersionContent(namespace string, subPath string) ([]byte, error) {
 	url := path.Join(namespace, subPath)
@@ -1,7 +1,10 @@
 package chartserver
 
 import (
+	"bytes"
 	"testing"
+
+	htesting "github.com/goharbor/harbor/src/testing"
 )
 
 // Test the function GetCountOfCharts
@@ -113,3 +116,21 @@ func TestChartSearching(t *testing.T) {
 		t.Fatalf("expect 2 results but got %d", len(results))
 	}
 }
+
+// Test the GetChartVersionContent in utility handler
+func TestGetChartVersionContent(t *testing.T) {
+	s, c, err := createMockObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	content, err := c.GetChartVersionContent("repo1", "harbor", "0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(content, htesting.HelmChartContent) {
+		t.Fatalf("expect the content of harbor-0.2.0.tgz but got %d bytes", len(content))
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"net/url"
 	"os"
 	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils/redis"
 )
 
 const (
@@ -117,6 +119,19 @@ func getCacheConfig() (*ChartCacheConfig, error) {
 	}
 
 	redisConfigV := os.Getenv(redisENVKey)
+	// The cache adapter of harbor is used when the redis is in the sentinel or cluster mode
+	if opts, err := redis.ParseURL(redisConfigV); err == nil && opts.Mode != redis.ModeStandalone {
+		redisCfg, err := redis.CacheConfig(redisConfigV, cacheCollectionName)
+		if err != nil {
+			return nil, err
+		}
+		return &ChartCacheConfig{
+			DriverType: driver,
+			Adapter:    redis.CacheAdapter,
+			Config:     redisCfg,
+		}, nil
+	}
+
 	redisCfg, err := parseRedisConfig(redisConfigV)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse redis configurations from '%s' with error: %s", redisCfg, err)
@@ -124,6 +139,7 @@ func getCacheConfig() (*ChartCacheConfig, error) {
 
 	return &ChartCacheConfig{
 		DriverType: driver,
+		Adapter:    cacheDriverRedis,
 		Config:     redisCfg,
 	}, nil
 }
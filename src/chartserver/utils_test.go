@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/goharbor/harbor/src/common/utils/redis"
 )
 
 // Test the utility function parseRedisConfig
@@ -96,6 +98,16 @@ func TestGetCacheConfig(t *testing.T) {
 		}
 	}
 
+	// case 6: redis cache conf in the sentinel mode
+	os.Setenv(redisENVKey, "redis+sentinel://sentinel:26379/mymaster")
+	sentinelConf, err := getCacheConfig()
+	if err != nil {
+		t.Fatalf("expect nil error but got non-nil one when parsing valid redis sentinel conf")
+	}
+	if sentinelConf.DriverType != cacheDriverRedis || sentinelConf.Adapter != redis.CacheAdapter {
+		t.Fatalf("expect the cache adapter %s but got %s", redis.CacheAdapter, sentinelConf.Adapter)
+	}
+
 	// clear
 	os.Unsetenv(cacheDriverENVKey)
 	os.Unsetenv(redisENVKey)
@@ -22,6 +22,7 @@ import (
 
 	"github.com/astaxie/beego/validation"
 	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/i18n"
 	"github.com/goharbor/harbor/src/common/utils/log"
 
 	"github.com/astaxie/beego"
@@ -30,6 +31,19 @@ import (
 const (
 	defaultPageSize int64 = 500
 	maxPageSize     int64 = 500
+	// MaxCappedCount is the max total counted in the mode CountModeCapped, the header
+	// "X-Total-Count" is "10000+" if there are more records
+	MaxCappedCount int64 = 10000
+)
+
+// the modes of counting the total of the list specified by the parameter "with_count"
+const (
+	// CountModeExact counts all the records, it's the default mode
+	CountModeExact = "true"
+	// CountModeNone skips counting, as counting is expensive on the huge tables
+	CountModeNone = "false"
+	// CountModeCapped counts at most MaxCappedCount records
+	CountModeCapped = "capped"
 )
 
 // BaseAPI wraps common methods for controllers to host API
@@ -57,12 +71,15 @@ func (b *BaseAPI) HandleNotFound(text string) {
 // HandleUnauthorized ...
 func (b *BaseAPI) HandleUnauthorized() {
 	log.Info("unauthorized")
-	b.RenderError(http.StatusUnauthorized, "")
+	b.RenderError(http.StatusUnauthorized, b.T(i18n.MsgUnauthorized))
 }
 
 // HandleForbidden ...
 func (b *BaseAPI) HandleForbidden(text string) {
 	log.Infof("forbidden: %s", text)
+	if len(text) == 0 {
+		text = b.T(i18n.MsgForbidden)
+	}
 	b.RenderError(http.StatusForbidden, text)
 }
 
@@ -92,7 +109,7 @@ func (b *BaseAPI) HandleConflict(text ...string) {
 // HandleInternalServerError ...
 func (b *BaseAPI) HandleInternalServerError(text string) {
 	log.Error(text)
-	b.RenderError(http.StatusInternalServerError, "")
+	b.RenderError(http.StatusInternalServerError, b.T(i18n.MsgInternalError))
 }
 
 // ParseAndHandleError : if the err is an instance of utils/error.Error,
@@ -107,7 +124,7 @@ func (b *BaseAPI) ParseAndHandleError(text string, err error) {
 		b.RenderError(e.Code, e.Message)
 		return
 	}
-	b.RenderError(http.StatusInternalServerError, "")
+	b.RenderError(http.StatusInternalServerError, b.T(i18n.MsgInternalError))
 }
 
 // Render returns nil as it won't render template
@@ -115,8 +132,19 @@ func (b *BaseAPI) Render() error {
 	return nil
 }
 
+// Locale returns the locale negotiated from the "Accept-Language" header of the request
+func (b *BaseAPI) Locale() string {
+	return i18n.Negotiate(b.Ctx.Request.Header.Get("Accept-Language"))
+}
+
+// T returns the message translated into the locale of the request
+func (b *BaseAPI) T(id string, args ...interface{}) string {
+	return i18n.T(b.Locale(), id, args...)
+}
+
 // RenderError provides shortcut to render http error
 func (b *BaseAPI) RenderError(code int, text string) {
+	b.Ctx.ResponseWriter.Header().Set("Content-Language", b.Locale())
 	http.Error(b.Ctx.ResponseWriter, text, code)
 }
 
@@ -126,7 +154,7 @@ func (b *BaseAPI) DecodeJSONReq(v interface{}) {
 	if err != nil {
 		log.Errorf("Error while decoding the json request, error: %v, %v",
 			err, string(b.Ctx.Input.CopyBody(1 << 32)[:]))
-		b.CustomAbort(http.StatusBadRequest, "Invalid json request")
+		b.CustomAbort(http.StatusBadRequest, b.T(i18n.MsgInvalidJSON))
 	}
 }
 
@@ -166,37 +194,63 @@ func (b *BaseAPI) Redirect(statusCode int, resouceID string) {
 func (b *BaseAPI) GetIDFromURL() int64 {
 	idStr := b.Ctx.Input.Param(":id")
 	if len(idStr) == 0 {
-		b.CustomAbort(http.StatusBadRequest, "invalid ID in URL")
+		b.CustomAbort(http.StatusBadRequest, b.T(i18n.MsgInvalidID))
 	}
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil || id <= 0 {
-		b.CustomAbort(http.StatusBadRequest, "invalid ID in URL")
+		b.CustomAbort(http.StatusBadRequest, b.T(i18n.MsgInvalidID))
 	}
 
 	return id
 }
 
-// SetPaginationHeader set"Link" and "X-Total-Count" header for pagination request
+// SetPaginationHeader set"Link" and "X-Total-Count" header for pagination request. The APIs
+// calling it always count the total, so the request is rejected if the parameter "with_count"
+// is specified other than CountModeExact, the ones supporting it call SetPaginationHeaderOfMode
 func (b *BaseAPI) SetPaginationHeader(total, page, pageSize int64) {
+	if mode := b.GetString("with_count"); len(mode) > 0 && mode != CountModeExact {
+		b.CustomAbort(http.StatusBadRequest, fmt.Sprintf("with_count %s isn't supported by the API, only %s is",
+			mode, CountModeExact))
+	}
+	b.setPaginationHeader(total, page, pageSize)
+}
+
+func (b *BaseAPI) setPaginationHeader(total, page, pageSize int64) {
 	b.Ctx.ResponseWriter.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	b.setLinkHeader(page, page > 1 && (page-1)*pageSize <= total, pageSize*page < total)
+}
 
+// SetPaginationHeaderOfMode sets the headers for pagination according to the count mode, the count
+// is the number of the records in the current page. The total is ignored in the mode CountModeNone
+// and "X-Total-Count" isn't set, the total counted at most MaxCappedCount+1 records is expected in the
+// mode CountModeCapped. The next link is set if the page is full when the total is unknown
+func (b *BaseAPI) SetPaginationHeaderOfMode(mode string, total, page, pageSize, count int64) {
+	switch {
+	case mode == CountModeNone:
+		b.setLinkHeader(page, page > 1, count >= pageSize)
+	case mode == CountModeCapped && total > MaxCappedCount:
+		b.Ctx.ResponseWriter.Header().Set("X-Total-Count", strconv.FormatInt(MaxCappedCount, 10)+"+")
+		b.setLinkHeader(page, page > 1, pageSize*page < total || count >= pageSize)
+	default:
+		b.setPaginationHeader(total, page, pageSize)
+	}
+}
+
+func (b *BaseAPI) setLinkHeader(page int64, prev, next bool) {
 	link := ""
 
-	// SetPaginationHeader set previous link
-	if page > 1 && (page-1)*pageSize <= total {
+	// set previous link
+	if prev {
 		u := *(b.Ctx.Request.URL)
 		q := u.Query()
 		q.Set("page", strconv.FormatInt(page-1, 10))
 		u.RawQuery = q.Encode()
-		if len(link) != 0 {
-			link += ", "
-		}
 		link += fmt.Sprintf("<%s>; rel=\"prev\"", u.String())
 	}
 
-	// SetPaginationHeader set next link
-	if pageSize*page < total {
+	// set next link
+	if next {
 		u := *(b.Ctx.Request.URL)
 		q := u.Query()
 		q.Set("page", strconv.FormatInt(page+1, 10))
@@ -212,6 +266,20 @@ func (b *BaseAPI) SetPaginationHeader(total, page, pageSize int64) {
 	}
 }
 
+// GetCountMode returns the mode of counting the total of the list specified by the parameter "with_count"
+func (b *BaseAPI) GetCountMode() string {
+	mode := b.GetString("with_count")
+	switch mode {
+	case "":
+		return CountModeExact
+	case CountModeExact, CountModeNone, CountModeCapped:
+		return mode
+	}
+	b.CustomAbort(http.StatusBadRequest, fmt.Sprintf("invalid with_count %s, it should be %s, %s or %s",
+		mode, CountModeExact, CountModeNone, CountModeCapped))
+	return ""
+}
+
 // GetPaginationParams ...
 func (b *BaseAPI) GetPaginationParams() (page, pageSize int64) {
 	page, err := b.GetInt64("page", 1)
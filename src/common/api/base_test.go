@@ -12,3 +12,75 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/astaxie/beego/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBaseAPI(url string) (*BaseAPI, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	ctx := context.NewContext()
+	ctx.Reset(w, req)
+	b := &BaseAPI{}
+	b.Init(ctx, "", "", nil)
+	return b, w
+}
+
+func TestSetPaginationHeaderOfMode(t *testing.T) {
+	// exact
+	b, w := newBaseAPI("/api/logs?page=2&page_size=10")
+	b.SetPaginationHeaderOfMode(CountModeExact, 25, 2, 10, 10)
+	assert.Equal(t, "25", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, `</api/logs?page=1&page_size=10>; rel="prev", </api/logs?page=3&page_size=10>; rel="next"`,
+		w.Header().Get("Link"))
+
+	// without count, the page isn't full
+	b, w = newBaseAPI("/api/logs?page=2&page_size=10&with_count=false")
+	b.SetPaginationHeaderOfMode(CountModeNone, 0, 2, 10, 5)
+	assert.Equal(t, "", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, `</api/logs?page=1&page_size=10&with_count=false>; rel="prev"`, w.Header().Get("Link"))
+
+	// without count, the page is full
+	b, w = newBaseAPI("/api/logs?page_size=10&with_count=false")
+	b.SetPaginationHeaderOfMode(CountModeNone, 0, 1, 10, 10)
+	assert.Equal(t, `</api/logs?page=2&page_size=10&with_count=false>; rel="next"`, w.Header().Get("Link"))
+
+	// capped, under the limit
+	b, w = newBaseAPI("/api/logs?with_count=capped")
+	b.SetPaginationHeaderOfMode(CountModeCapped, 100, 1, 500, 100)
+	assert.Equal(t, "100", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, "", w.Header().Get("Link"))
+
+	// capped, over the limit
+	b, w = newBaseAPI("/api/logs?page=30&with_count=capped")
+	b.SetPaginationHeaderOfMode(CountModeCapped, MaxCappedCount+1, 30, 500, 500)
+	assert.Equal(t, "10000+", w.Header().Get("X-Total-Count"))
+	assert.Contains(t, w.Header().Get("Link"), `rel="next"`)
+}
+
+func TestGetCountMode(t *testing.T) {
+	b, _ := newBaseAPI("/api/logs")
+	assert.Equal(t, CountModeExact, b.GetCountMode())
+	b, _ = newBaseAPI("/api/logs?with_count=false")
+	assert.Equal(t, CountModeNone, b.GetCountMode())
+	b, _ = newBaseAPI("/api/logs?with_count=capped")
+	assert.Equal(t, CountModeCapped, b.GetCountMode())
+}
+
+func TestSetPaginationHeader(t *testing.T) {
+	b, w := newBaseAPI("/api/labels?page=2&page_size=10&with_count=true")
+	b.SetPaginationHeader(25, 2, 10)
+	assert.Equal(t, "25", w.Header().Get("X-Total-Count"))
+
+	// the APIs without the support of the count mode
+	b, _ = newBaseAPI("/api/labels?with_count=false")
+	assert.Panics(t, func() { b.SetPaginationHeader(25, 1, 10) })
+	b, _ = newBaseAPI("/api/labels?with_count=capped")
+	assert.Panics(t, func() { b.SetPaginationHeader(25, 1, 10) })
+}
@@ -18,7 +18,7 @@ import (
 	"os"
 	"sync"
 
-	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/keyring"
 	"github.com/goharbor/harbor/src/common/utils/log"
 )
 
@@ -70,7 +70,7 @@ func (a *AESEncryptor) Encrypt(plaintext string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return utils.ReversibleEncrypt(plaintext, key)
+	return keyring.Encrypt(plaintext, key)
 }
 
 // Decrypt ...
@@ -79,5 +79,5 @@ func (a *AESEncryptor) Decrypt(ciphertext string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return utils.ReversibleDecrypt(ciphertext, key)
+	return keyring.Decrypt(ciphertext, key)
 }
@@ -165,12 +165,16 @@ func (c *CfgManager) GetDatabaseCfg() *models.Database {
 	return &models.Database{
 		Type: c.Get(common.DatabaseType).GetString(),
 		PostGreSQL: &models.PostGreSQL{
-			Host:     c.Get(common.PostGreSQLHOST).GetString(),
-			Port:     c.Get(common.PostGreSQLPort).GetInt(),
-			Username: c.Get(common.PostGreSQLUsername).GetString(),
-			Password: c.Get(common.PostGreSQLPassword).GetString(),
-			Database: c.Get(common.PostGreSQLDatabase).GetString(),
-			SSLMode:  c.Get(common.PostGreSQLSSLMode).GetString(),
+			Host:          c.Get(common.PostGreSQLHOST).GetString(),
+			Port:          c.Get(common.PostGreSQLPort).GetInt(),
+			Username:      c.Get(common.PostGreSQLUsername).GetString(),
+			Password:      c.Get(common.PostGreSQLPassword).GetString(),
+			Database:      c.Get(common.PostGreSQLDatabase).GetString(),
+			SSLMode:       c.Get(common.PostGreSQLSSLMode).GetString(),
+			SSLRootCert:   c.Get(common.PostGreSQLSSLRootCert).GetString(),
+			AuthMode:      c.Get(common.PostGreSQLAuthMode).GetString(),
+			AWSRegion:     c.Get(common.PostGreSQLAWSRegion).GetString(),
+			AzureClientID: c.Get(common.PostGreSQLAzureClientID).GetString(),
 		},
 	}
 }
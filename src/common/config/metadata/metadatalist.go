@@ -58,8 +58,22 @@ var (
 	// 2. Get/Set config settings by CfgManager
 	// 3. CfgManager.Load()/CfgManager.Save() to load/save from configure storage.
 	ConfigList = []Item{
+		{Name: "access_log_es_index", Scope: SystemScope, Group: BasicGroup, EnvKey: "ACCESS_LOG_ES_INDEX", DefaultValue: "harbor-access-log", ItemType: &StringType{}, Editable: false},
+		{Name: "access_log_es_password", Scope: SystemScope, Group: BasicGroup, EnvKey: "ACCESS_LOG_ES_PASSWORD", DefaultValue: "", ItemType: &PasswordType{}, Editable: false},
+		{Name: "access_log_es_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "ACCESS_LOG_ES_URL", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "access_log_es_username", Scope: SystemScope, Group: BasicGroup, EnvKey: "ACCESS_LOG_ES_USERNAME", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "access_log_store", Scope: SystemScope, Group: BasicGroup, EnvKey: "ACCESS_LOG_STORE", DefaultValue: "database", ItemType: &StringType{}, Editable: false},
 		{Name: "admin_initial_password", Scope: SystemScope, Group: BasicGroup, EnvKey: "HARBOR_ADMIN_PASSWORD", DefaultValue: "", ItemType: &PasswordType{}, Editable: true},
 		{Name: "admiral_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "ADMIRAL_URL", DefaultValue: "NA", ItemType: &StringType{}, Editable: false},
+		{Name: "admission_webhook_secret", Scope: SystemScope, Group: BasicGroup, EnvKey: "ADMISSION_WEBHOOK_SECRET", DefaultValue: "", ItemType: &PasswordType{}, Editable: false},
+		{Name: "approval_webhook_url", Scope: UserScope, Group: BasicGroup, EnvKey: "APPROVAL_WEBHOOK_URL", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "blocklist_webhook_url", Scope: UserScope, Group: BasicGroup, EnvKey: "BLOCKLIST_WEBHOOK_URL", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "project_webhook_url", Scope: UserScope, Group: BasicGroup, EnvKey: "PROJECT_WEBHOOK_URL", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "approval_webhook_schema", Scope: UserScope, Group: BasicGroup, EnvKey: "APPROVAL_WEBHOOK_SCHEMA", DefaultValue: "v1", ItemType: &StringType{}, Editable: false},
+		{Name: "blocklist_webhook_schema", Scope: UserScope, Group: BasicGroup, EnvKey: "BLOCKLIST_WEBHOOK_SCHEMA", DefaultValue: "v1", ItemType: &StringType{}, Editable: false},
+		{Name: "project_webhook_schema", Scope: UserScope, Group: BasicGroup, EnvKey: "PROJECT_WEBHOOK_SCHEMA", DefaultValue: "v1", ItemType: &StringType{}, Editable: false},
+		{Name: "project_report_cron", Scope: UserScope, Group: BasicGroup, EnvKey: "PROJECT_REPORT_CRON", DefaultValue: "0 0 8 * * 1", ItemType: &StringType{}, Editable: false},
+		{Name: "short_name_project", Scope: UserScope, Group: BasicGroup, EnvKey: "SHORT_NAME_PROJECT", DefaultValue: "library", ItemType: &StringType{}, Editable: false},
 		{Name: "auth_mode", Scope: UserScope, Group: BasicGroup, EnvKey: "AUTH_MODE", DefaultValue: "db_auth", ItemType: &StringType{}, Editable: false},
 		{Name: "cfg_expiration", Scope: SystemScope, Group: BasicGroup, EnvKey: "CFG_EXPIRATION", DefaultValue: "5", ItemType: &IntType{}, Editable: false},
 		{Name: "chart_repository_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "CHART_REPOSITORY_URL", DefaultValue: "http://chartmuseum:9999", ItemType: &StringType{}, Editable: false},
@@ -73,7 +87,10 @@ var (
 		{Name: "clair_url", Scope: SystemScope, Group: ClairGroup, EnvKey: "CLAIR_URL", DefaultValue: "http://clair:6060", ItemType: &StringType{}, Editable: false},
 
 		{Name: "core_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "CORE_URL", DefaultValue: "http://core:8080", ItemType: &StringType{}, Editable: false},
+		{Name: "cvss_source", Scope: UserScope, Group: BasicGroup, EnvKey: "CVSS_SOURCE", DefaultValue: "vendor", ItemType: &StringType{}, Editable: false},
 		{Name: "database_type", Scope: SystemScope, Group: BasicGroup, EnvKey: "DATABASE_TYPE", DefaultValue: "postgresql", ItemType: &StringType{}, Editable: false},
+		{Name: "destructive_op_confirmation", Scope: UserScope, Group: BasicGroup, EnvKey: "DESTRUCTIVE_OP_CONFIRMATION", DefaultValue: "false", ItemType: &BoolType{}, Editable: false},
+		{Name: "egress_allowlist", Scope: UserScope, Group: BasicGroup, EnvKey: "EGRESS_ALLOWLIST", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 
 		{Name: "email_from", Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_FROM", DefaultValue: "admin <sample_admin@mydomain.com>", ItemType: &StringType{}, Editable: false},
 		{Name: "email_host", Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_HOST", DefaultValue: "smtp.mydomain.com", ItemType: &StringType{}, Editable: false},
@@ -85,6 +102,10 @@ var (
 		{Name: "email_username", Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_USR", DefaultValue: "sample_admin@mydomain.com", ItemType: &StringType{}, Editable: false},
 
 		{Name: "ext_endpoint", Scope: SystemScope, Group: BasicGroup, EnvKey: "EXT_ENDPOINT", DefaultValue: "https://host01.com", ItemType: &StringType{}, Editable: false},
+		{Name: "external_authz_cache_ttl", Scope: UserScope, Group: BasicGroup, EnvKey: "EXTERNAL_AUTHZ_CACHE_TTL", DefaultValue: "60", ItemType: &IntType{}, Editable: false},
+		{Name: "external_authz_endpoint", Scope: UserScope, Group: BasicGroup, EnvKey: "EXTERNAL_AUTHZ_ENDPOINT", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "external_authz_fail_open", Scope: UserScope, Group: BasicGroup, EnvKey: "EXTERNAL_AUTHZ_FAIL_OPEN", DefaultValue: "false", ItemType: &BoolType{}, Editable: false},
+		{Name: "feature_flags", Scope: SystemScope, Group: BasicGroup, EnvKey: "FEATURE_FLAGS", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 		{Name: "jobservice_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "JOBSERVICE_URL", DefaultValue: "http://jobservice:8080", ItemType: &StringType{}, Editable: false},
 
 		{Name: "ldap_base_dn", Scope: UserScope, Group: LdapBasicGroup, EnvKey: "LDAP_BASE_DN", DefaultValue: "", ItemType: &StringType{}, Editable: false},
@@ -101,24 +122,61 @@ var (
 		{Name: "ldap_uid", Scope: UserScope, Group: LdapBasicGroup, EnvKey: "LDAP_UID", DefaultValue: "cn", ItemType: &StringType{}, Editable: true},
 		{Name: "ldap_url", Scope: UserScope, Group: LdapBasicGroup, EnvKey: "LDAP_URL", DefaultValue: "", ItemType: &StringType{}, Editable: true},
 		{Name: "ldap_verify_cert", Scope: UserScope, Group: LdapBasicGroup, EnvKey: "LDAP_VERIFY_CERT", DefaultValue: "true", ItemType: &BoolType{}, Editable: false},
+		{Name: "maintenance", Scope: SystemScope, Group: BasicGroup, EnvKey: "MAINTENANCE", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "manifest_cache_ttl", Scope: UserScope, Group: BasicGroup, EnvKey: "MANIFEST_CACHE_TTL", DefaultValue: "300", ItemType: &IntType{}, Editable: false},
 
+		{Name: "max_chart_upload_size", Scope: UserScope, Group: BasicGroup, EnvKey: "MAX_CHART_UPLOAD_SIZE", DefaultValue: "102400", ItemType: &IntType{}, Editable: false},
 		{Name: "max_job_workers", Scope: SystemScope, Group: BasicGroup, EnvKey: "MAX_JOB_WORKERS", DefaultValue: "10", ItemType: &IntType{}, Editable: false},
+		{Name: "max_json_body_size", Scope: UserScope, Group: BasicGroup, EnvKey: "MAX_JSON_BODY_SIZE", DefaultValue: "10240", ItemType: &IntType{}, Editable: false},
+		{Name: "max_log_query_size", Scope: UserScope, Group: BasicGroup, EnvKey: "MAX_LOG_QUERY_SIZE", DefaultValue: "8", ItemType: &IntType{}, Editable: false},
+		{Name: "metadata_sync_peer_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "METADATA_SYNC_PEER_URL", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "metadata_sync_priority", Scope: SystemScope, Group: BasicGroup, EnvKey: "METADATA_SYNC_PRIORITY", DefaultValue: "0", ItemType: &IntType{}, Editable: false},
+		{Name: "metadata_sync_secret", Scope: SystemScope, Group: BasicGroup, EnvKey: "METADATA_SYNC_SECRET", DefaultValue: "", ItemType: &PasswordType{}, Editable: false},
 		{Name: "notary_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "NOTARY_URL", DefaultValue: "http://notary-server:4443", ItemType: &StringType{}, Editable: false},
 
+		{Name: "postgresql_auth_mode", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_AUTH_MODE", DefaultValue: "password", ItemType: &StringType{}, Editable: false},
+		{Name: "postgresql_aws_region", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_AWS_REGION", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "postgresql_azure_client_id", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_AZURE_CLIENT_ID", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 		{Name: "postgresql_database", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_DATABASE", DefaultValue: "registry", ItemType: &StringType{}, Editable: false},
 		{Name: "postgresql_host", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_HOST", DefaultValue: "postgresql", ItemType: &StringType{}, Editable: false},
 		{Name: "postgresql_password", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_PASSWORD", DefaultValue: "root123", ItemType: &PasswordType{}, Editable: false},
 		{Name: "postgresql_port", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_PORT", DefaultValue: "5432", ItemType: &IntType{}, Editable: false},
 		{Name: "postgresql_sslmode", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_SSLMODE", DefaultValue: "disable", ItemType: &StringType{}, Editable: false},
+		{Name: "postgresql_sslrootcert", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_SSLROOTCERT", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 		{Name: "postgresql_username", Scope: SystemScope, Group: DatabaseGroup, EnvKey: "POSTGRESQL_USERNAME", DefaultValue: "postgres", ItemType: &StringType{}, Editable: false},
 
 		{Name: "project_creation_restriction", Scope: UserScope, Group: BasicGroup, EnvKey: "PROJECT_CREATION_RESTRICTION", DefaultValue: common.ProCrtRestrEveryone, ItemType: &StringType{}, Editable: false},
 		{Name: "read_only", Scope: UserScope, Group: BasicGroup, EnvKey: "READ_ONLY", DefaultValue: "false", ItemType: &BoolType{}, Editable: false},
 
 		{Name: "registry_storage_provider_name", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_PROVIDER_NAME", DefaultValue: "filesystem", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_oss_region", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_REGION", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_oss_endpoint", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_ENDPOINT", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_oss_bucket", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_BUCKET", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_oss_access_key_id", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_ACCESS_KEY_ID", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_oss_access_key_secret", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_ACCESS_KEY_SECRET", DefaultValue: "", ItemType: &PasswordType{}, Editable: false},
+		{Name: "registry_storage_oss_internal", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_INTERNAL", DefaultValue: "false", ItemType: &BoolType{}, Editable: false},
+		{Name: "registry_storage_oss_secure", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_SECURE", DefaultValue: "true", ItemType: &BoolType{}, Editable: false},
+		{Name: "registry_storage_oss_root_directory", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_OSS_ROOT_DIRECTORY", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_cos_region", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_REGION", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_cos_endpoint", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_ENDPOINT", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_cos_bucket", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_BUCKET", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_cos_secret_id", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_SECRET_ID", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_storage_cos_secret_key", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_SECRET_KEY", DefaultValue: "", ItemType: &PasswordType{}, Editable: false},
+		{Name: "registry_storage_cos_internal", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_INTERNAL", DefaultValue: "false", ItemType: &BoolType{}, Editable: false},
+		{Name: "registry_storage_cos_secure", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_SECURE", DefaultValue: "true", ItemType: &BoolType{}, Editable: false},
+		{Name: "registry_storage_cos_root_directory", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_STORAGE_COS_ROOT_DIRECTORY", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "registry_breaker_probe_interval", Scope: UserScope, Group: BasicGroup, EnvKey: "REGISTRY_BREAKER_PROBE_INTERVAL", DefaultValue: "30", ItemType: &IntType{}, Editable: false},
+		{Name: "registry_breaker_threshold", Scope: UserScope, Group: BasicGroup, EnvKey: "REGISTRY_BREAKER_THRESHOLD", DefaultValue: "5", ItemType: &IntType{}, Editable: false},
 		{Name: "registry_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_URL", DefaultValue: "http://registry:5000", ItemType: &StringType{}, Editable: false},
 		{Name: "registry_controller_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "REGISTRY_CONTROLLER_URL", DefaultValue: "http://registryctl:8080", ItemType: &StringType{}, Editable: false},
+		{Name: "rename_redirect_period", Scope: UserScope, Group: BasicGroup, EnvKey: "RENAME_REDIRECT_PERIOD", DefaultValue: "168", ItemType: &IntType{}, Editable: false},
+		{Name: "token_key_grace_period", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_KEY_GRACE_PERIOD", DefaultValue: "720", ItemType: &IntType{}, Editable: false},
 		{Name: "self_registration", Scope: UserScope, Group: BasicGroup, EnvKey: "SELF_REGISTRATION", DefaultValue: "true", ItemType: &BoolType{}, Editable: false},
+		{Name: "token_exchange_audience", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_EXCHANGE_AUDIENCE", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "token_exchange_bindings", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_EXCHANGE_BINDINGS", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "token_exchange_expiration", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_EXCHANGE_EXPIRATION", DefaultValue: "5", ItemType: &IntType{}, Editable: false},
+		{Name: "token_exchange_issuer", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_EXCHANGE_ISSUER", DefaultValue: "", ItemType: &StringType{}, Editable: false},
+		{Name: "token_exchange_public_keys", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_EXCHANGE_PUBLIC_KEYS", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 		{Name: "token_expiration", Scope: UserScope, Group: BasicGroup, EnvKey: "TOKEN_EXPIRATION", DefaultValue: "30", ItemType: &IntType{}, Editable: false},
 		{Name: "token_service_url", Scope: SystemScope, Group: BasicGroup, EnvKey: "TOKEN_SERVICE_URL", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 
@@ -126,6 +184,7 @@ var (
 		{Name: "uaa_client_secret", Scope: UserScope, Group: UAAGroup, EnvKey: "UAA_CLIENTSECRET", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 		{Name: "uaa_endpoint", Scope: UserScope, Group: UAAGroup, EnvKey: "UAA_ENDPOINT", DefaultValue: "", ItemType: &StringType{}, Editable: false},
 		{Name: "uaa_verify_cert", Scope: UserScope, Group: UAAGroup, EnvKey: "UAA_VERIFY_CERT", DefaultValue: "false", ItemType: &BoolType{}, Editable: false},
+		{Name: "upload_purging_age", Scope: UserScope, Group: BasicGroup, EnvKey: "UPLOAD_PURGING_AGE", DefaultValue: "168", ItemType: &IntType{}, Editable: false},
 
 		{Name: "with_chartmuseum", Scope: SystemScope, Group: BasicGroup, EnvKey: "WITH_CHARTMUSEUM", DefaultValue: "false", ItemType: &BoolType{}, Editable: true},
 		{Name: "with_clair", Scope: SystemScope, Group: BasicGroup, EnvKey: "WITH_CLAIR", DefaultValue: "true", ItemType: &BoolType{}, Editable: true},
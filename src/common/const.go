@@ -22,10 +22,21 @@ const (
 	HTTPAuth            = "http_auth"
 	ProCrtRestrEveryone = "everyone"
 	ProCrtRestrAdmOnly  = "adminonly"
+	ProCrtRestrApproval = "approval"
 	LDAPScopeBase       = 0
 	LDAPScopeOnelevel   = 1
 	LDAPScopeSubtree    = 2
 
+	// the sources of CVSS used to decide the severity of vulnerabilities, the vendor
+	// severity is used if the vulnerability has no score of the NVD CVSS version
+	CVSSSourceVendor = "vendor"
+	CVSSSourceNVDV2  = "nvd_v2"
+	CVSSSourceNVDV3  = "nvd_v3"
+
+	// the stores of the access logs
+	AccessLogStoreDatabase      = "database"
+	AccessLogStoreElasticsearch = "elasticsearch"
+
 	RoleProjectAdmin = 1
 	RoleDeveloper    = 2
 	RoleGuest        = 3
@@ -50,6 +61,19 @@ const (
 	PostGreSQLPassword                = "postgresql_password"
 	PostGreSQLDatabase                = "postgresql_database"
 	PostGreSQLSSLMode                 = "postgresql_sslmode"
+	PostGreSQLSSLRootCert             = "postgresql_sslrootcert"
+	PostGreSQLAuthMode                = "postgresql_auth_mode"
+	PostGreSQLAWSRegion               = "postgresql_aws_region"
+	PostGreSQLAzureClientID           = "postgresql_azure_client_id"
+	AccessLogStore                    = "access_log_store"
+	AccessLogESURL                    = "access_log_es_url"
+	AccessLogESIndex                  = "access_log_es_index"
+	AccessLogESUsername               = "access_log_es_username"
+	AccessLogESPassword               = "access_log_es_password"
+	MetadataSyncPeerURL               = "metadata_sync_peer_url"
+	MetadataSyncSecret                = "metadata_sync_secret"
+	MetadataSyncPriority              = "metadata_sync_priority"
+	AdmissionWebhookSecret            = "admission_webhook_secret"
 	SelfRegistration                  = "self_registration"
 	CoreURL                           = "core_url"
 	JobServiceURL                     = "jobservice_url"
@@ -101,6 +125,22 @@ const (
 	CfgDriverJSON                     = "json"
 	NewHarborAdminName                = "admin@harbor.local"
 	RegistryStorageProviderName       = "registry_storage_provider_name"
+	RegistryStorageOSSRegion          = "registry_storage_oss_region"
+	RegistryStorageOSSEndpoint        = "registry_storage_oss_endpoint"
+	RegistryStorageOSSBucket          = "registry_storage_oss_bucket"
+	RegistryStorageOSSAccessKeyID     = "registry_storage_oss_access_key_id"
+	RegistryStorageOSSAccessKeySecret = "registry_storage_oss_access_key_secret"
+	RegistryStorageOSSInternal        = "registry_storage_oss_internal"
+	RegistryStorageOSSSecure          = "registry_storage_oss_secure"
+	RegistryStorageOSSRootDirectory   = "registry_storage_oss_root_directory"
+	RegistryStorageCOSRegion          = "registry_storage_cos_region"
+	RegistryStorageCOSEndpoint        = "registry_storage_cos_endpoint"
+	RegistryStorageCOSBucket          = "registry_storage_cos_bucket"
+	RegistryStorageCOSSecretID        = "registry_storage_cos_secret_id"
+	RegistryStorageCOSSecretKey       = "registry_storage_cos_secret_key"
+	RegistryStorageCOSInternal        = "registry_storage_cos_internal"
+	RegistryStorageCOSSecure          = "registry_storage_cos_secure"
+	RegistryStorageCOSRootDirectory   = "registry_storage_cos_root_directory"
 	UserMember                        = "u"
 	GroupMember                       = "g"
 	ReadOnly                          = "read_only"
@@ -118,10 +158,44 @@ const (
 	ChartRepoURL                      = "chart_repository_url"
 	DefaultChartRepoURL               = "http://chartmuseum:9999"
 	DefaultPortalURL                  = "http://portal"
+	DefaultProjectReportCron          = "0 0 8 * * 1"
+	DefaultShortNameProject           = "library"
 	DefaultRegistryCtlURL             = "http://registryctl:8080"
 	DefaultClairHealthCheckServerURL  = "http://clair:6061"
+	ExternalAuthzEndpoint             = "external_authz_endpoint"
+	ExternalAuthzFailOpen             = "external_authz_fail_open"
+	ExternalAuthzCacheTTL             = "external_authz_cache_ttl"
+	DestructiveOpConfirmation         = "destructive_op_confirmation"
+	UploadPurgingAge                  = "upload_purging_age"
+	ManifestCacheTTL                  = "manifest_cache_ttl"
+	RenameRedirectPeriod              = "rename_redirect_period"
+	TokenKeyGracePeriod               = "token_key_grace_period"
+	BreakerThreshold                  = "registry_breaker_threshold"
+	BreakerProbeInterval              = "registry_breaker_probe_interval"
+	CVSSSource                        = "cvss_source"
+	ApprovalWebhookURL                = "approval_webhook_url"
+	BlocklistWebhookURL               = "blocklist_webhook_url"
+	ProjectWebhookURL                 = "project_webhook_url"
+	ApprovalWebhookSchema             = "approval_webhook_schema"
+	BlocklistWebhookSchema            = "blocklist_webhook_schema"
+	ProjectWebhookSchema              = "project_webhook_schema"
+	EgressAllowlist                   = "egress_allowlist"
+	ProjectReportCron                 = "project_report_cron"
+	ShortNameProject                  = "short_name_project"
+	FeatureFlags                      = "feature_flags"
+	Maintenance                       = "maintenance"
+	MaxJSONBodySize                   = "max_json_body_size"
+	MaxChartUploadSize                = "max_chart_upload_size"
+	MaxLogQuerySize                   = "max_log_query_size"
+	TokenExchangeIssuer               = "token_exchange_issuer"
+	TokenExchangePublicKeys           = "token_exchange_public_keys"
+	TokenExchangeAudience             = "token_exchange_audience"
+	TokenExchangeBindings             = "token_exchange_bindings"
+	TokenExchangeExpiration           = "token_exchange_expiration"
 	// Use this prefix to distinguish harbor user, the prefix contains a special character($), so it cannot be registered as a harbor user.
 	RobotPrefix = "robot$"
+	// SharePrefix is the prefix of the usernames of the share links, followed by the ID of the link
+	SharePrefix = "share$"
 )
 
 // Shared variable, not allowed to modify
@@ -161,6 +235,34 @@ var (
 		UAAEndpoint,
 		UAAVerifyCert,
 		ReadOnly,
+		ExternalAuthzEndpoint,
+		ExternalAuthzFailOpen,
+		ExternalAuthzCacheTTL,
+		DestructiveOpConfirmation,
+		UploadPurgingAge,
+		ManifestCacheTTL,
+		RenameRedirectPeriod,
+		TokenKeyGracePeriod,
+		BreakerThreshold,
+		BreakerProbeInterval,
+		CVSSSource,
+		ApprovalWebhookURL,
+		BlocklistWebhookURL,
+		ProjectWebhookURL,
+		ApprovalWebhookSchema,
+		BlocklistWebhookSchema,
+		ProjectWebhookSchema,
+		EgressAllowlist,
+		ProjectReportCron,
+		ShortNameProject,
+		MaxJSONBodySize,
+		MaxChartUploadSize,
+		MaxLogQuerySize,
+		TokenExchangeIssuer,
+		TokenExchangePublicKeys,
+		TokenExchangeAudience,
+		TokenExchangeBindings,
+		TokenExchangeExpiration,
 	}
 
 	// value is default value
@@ -184,23 +286,51 @@ var (
 		ProjectCreationRestriction: ProCrtRestrEveryone,
 		UAAClientID:                "",
 		UAAEndpoint:                "",
+		ExternalAuthzEndpoint:      "",
+		CVSSSource:                 CVSSSourceVendor,
+		ApprovalWebhookURL:         "",
+		BlocklistWebhookURL:        "",
+		ProjectWebhookURL:          "",
+		ApprovalWebhookSchema:      "v1",
+		BlocklistWebhookSchema:     "v1",
+		ProjectWebhookSchema:       "v1",
+		EgressAllowlist:            "",
+		ProjectReportCron:          DefaultProjectReportCron,
+		ShortNameProject:           DefaultShortNameProject,
+		TokenExchangeIssuer:        "",
+		TokenExchangePublicKeys:    "",
+		TokenExchangeAudience:      "",
+		TokenExchangeBindings:      "",
 	}
 
 	HarborNumKeysMap = map[string]int{
-		EmailPort:            25,
-		LDAPScope:            2,
-		LDAPTimeout:          5,
-		LDAPGroupSearchScope: 2,
-		TokenExpiration:      30,
+		EmailPort:               25,
+		LDAPScope:               2,
+		LDAPTimeout:             5,
+		LDAPGroupSearchScope:    2,
+		TokenExpiration:         30,
+		ExternalAuthzCacheTTL:   60,
+		UploadPurgingAge:        168,
+		ManifestCacheTTL:        300,
+		RenameRedirectPeriod:    168,
+		TokenKeyGracePeriod:     720,
+		BreakerThreshold:        5,
+		BreakerProbeInterval:    30,
+		MaxJSONBodySize:         10240,
+		MaxChartUploadSize:      102400,
+		MaxLogQuerySize:         8,
+		TokenExchangeExpiration: 5,
 	}
 
 	HarborBoolKeysMap = map[string]bool{
-		EmailSSL:         false,
-		EmailInsecure:    false,
-		SelfRegistration: true,
-		LDAPVerifyCert:   true,
-		UAAVerifyCert:    true,
-		ReadOnly:         false,
+		EmailSSL:                  false,
+		EmailInsecure:             false,
+		SelfRegistration:          true,
+		LDAPVerifyCert:            true,
+		UAAVerifyCert:             true,
+		ReadOnly:                  false,
+		ExternalAuthzFailOpen:     false,
+		DestructiveOpConfirmation: false,
 	}
 
 	HarborPasswordKeys = []string{
@@ -208,4 +338,19 @@ var (
 		LDAPSearchPwd,
 		UAAClientSecret,
 	}
+
+	// HarborEncryptedKeys are the configurations stored encrypted
+	HarborEncryptedKeys = []string{
+		EmailPassword,
+		LDAPSearchPwd,
+		PostGreSQLPassword,
+		AccessLogESPassword,
+		MetadataSyncSecret,
+		AdmissionWebhookSecret,
+		RegistryStorageOSSAccessKeySecret,
+		RegistryStorageCOSSecretKey,
+		AdminInitialPassword,
+		ClairDBPassword,
+		UAAClientSecret,
+	}
 )
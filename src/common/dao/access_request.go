@@ -0,0 +1,166 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddAccessRequest ...
+func AddAccessRequest(req *models.AccessRequest) (int64, error) {
+	now := time.Now()
+	req.CreationTime = now
+	req.UpdateTime = now
+	if len(req.Status) == 0 {
+		req.Status = models.AccessRequestPending
+	}
+	id, err := GetOrmer().Insert(req)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetAccessRequest ...
+func GetAccessRequest(id int64) (*models.AccessRequest, error) {
+	req := &models.AccessRequest{
+		ID: id,
+	}
+	if err := GetOrmer().Read(req); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// ListAccessRequests list access requests according to the query conditions
+func ListAccessRequests(query *models.AccessRequestQuery) ([]*models.AccessRequest, error) {
+	qs := getAccessRequestQuerySetter(query).OrderBy("-CreationTime")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	reqs := []*models.AccessRequest{}
+	_, err := qs.All(&reqs)
+	return reqs, err
+}
+
+func getAccessRequestQuerySetter(query *models.AccessRequestQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.AccessRequest{})
+
+	if query == nil {
+		return qs
+	}
+
+	if query.ProjectID != 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if query.UserID != 0 {
+		qs = qs.Filter("UserID", query.UserID)
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
+
+// CountAccessRequests ...
+func CountAccessRequests(query *models.AccessRequestQuery) (int64, error) {
+	return getAccessRequestQuerySetter(query).Count()
+}
+
+// UpdateAccessRequestStatus updates the status and reviewer of a pending access request,
+// it returns false if the request is not pending anymore
+func UpdateAccessRequestStatus(id int64, status, reviewer string) (bool, error) {
+	n, err := GetOrmer().QueryTable(&models.AccessRequest{}).
+		Filter("ID", id).
+		Filter("Status", models.AccessRequestPending).
+		Update(orm.Params{
+			"Status":     status,
+			"Reviewer":   reviewer,
+			"UpdateTime": time.Now(),
+		})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteAccessRequest ...
+func DeleteAccessRequest(id int64) error {
+	_, err := GetOrmer().QueryTable(&models.AccessRequest{}).Filter("ID", id).Delete()
+	return err
+}
+
+// ApproveAccessRequest approves the pending request and adds the requester as the member of the
+// project with the requested role in one transaction. The expired membership of the requester is
+// renewed with the role and never expires, the unexpired one is kept. False is returned if the
+// request isn't pending
+func ApproveAccessRequest(id int64, reviewer string) (bool, error) {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return false, err
+	}
+
+	request := &models.AccessRequest{}
+	err := o.Raw(`select * from access_request where id = ? and status = ? for update`,
+		id, models.AccessRequestPending).QueryRow(request)
+	if err != nil {
+		o.Rollback()
+		if err == orm.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	member := &models.Member{}
+	err = o.Raw(`select id, role, expiration_time from project_member
+		where project_id = ? and entity_id = ? and entity_type = ? for update`,
+		request.ProjectID, request.UserID, common.UserMember).QueryRow(member)
+	switch {
+	case err == orm.ErrNoRows:
+		_, err = o.Raw(`insert into project_member (project_id, entity_id, role, entity_type) values (?, ?, ?, ?)`,
+			request.ProjectID, request.UserID, request.Role, common.UserMember).Exec()
+	case err == nil && member.IsExpired():
+		_, err = o.Raw(`update project_member set role = ?, expiration_time = null where id = ?`,
+			request.Role, member.ID).Exec()
+	}
+	if err != nil {
+		o.Rollback()
+		return false, err
+	}
+
+	if _, err = o.Raw(`update access_request set status = ?, reviewer = ?, update_time = ? where id = ?`,
+		models.AccessRequestApproved, reviewer, time.Now(), id).Exec(); err != nil {
+		o.Rollback()
+		return false, err
+	}
+	return true, o.Commit()
+}
@@ -0,0 +1,134 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessRequest(t *testing.T) {
+	req := &models.AccessRequest{
+		ProjectID:     1,
+		UserID:        1,
+		Role:          common.RoleGuest,
+		Justification: "for testing",
+	}
+
+	// add
+	id, err := AddAccessRequest(req)
+	require.Nil(t, err)
+	defer DeleteAccessRequest(id)
+
+	// only one pending request is allowed
+	_, err = AddAccessRequest(&models.AccessRequest{
+		ProjectID:     1,
+		UserID:        1,
+		Role:          common.RoleGuest,
+		Justification: "for testing",
+	})
+	assert.Equal(t, ErrDupRows, err)
+
+	// get
+	req, err = GetAccessRequest(id)
+	require.Nil(t, err)
+	require.NotNil(t, req)
+	assert.Equal(t, models.AccessRequestPending, req.Status)
+
+	// list and count
+	query := &models.AccessRequestQuery{
+		ProjectID: 1,
+		Status:    models.AccessRequestPending,
+	}
+	total, err := CountAccessRequests(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	reqs, err := ListAccessRequests(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(reqs))
+	assert.Equal(t, id, reqs[0].ID)
+
+	// update status
+	updated, err := UpdateAccessRequestStatus(id, models.AccessRequestApproved, "admin")
+	require.Nil(t, err)
+	assert.True(t, updated)
+	req, err = GetAccessRequest(id)
+	require.Nil(t, err)
+	assert.Equal(t, models.AccessRequestApproved, req.Status)
+	assert.Equal(t, "admin", req.Reviewer)
+
+	// the request which isn't pending can not be updated again
+	updated, err = UpdateAccessRequestStatus(id, models.AccessRequestDenied, "admin")
+	require.Nil(t, err)
+	assert.False(t, updated)
+
+	// not found
+	req, err = GetAccessRequest(10000)
+	require.Nil(t, err)
+	assert.Nil(t, req)
+}
+
+func TestApproveAccessRequest(t *testing.T) {
+	userID, err := Register(models.User{
+		Username: "dao-access-request-user",
+		Email:    "dao-access-request-user@example.com",
+		Password: "Harbor12345",
+		Realname: "dao access request user",
+	})
+	require.Nil(t, err)
+	defer GetOrmer().Raw(`delete from harbor_user where user_id = ?`, userID).Exec()
+	defer GetOrmer().Raw(`delete from project_member where entity_id = ? and entity_type = ?`,
+		userID, common.UserMember).Exec()
+
+	// the membership of the requester has expired
+	_, err = GetOrmer().Raw(`insert into project_member (project_id, entity_id, role, entity_type, expiration_time)
+		values (?, ?, ?, ?, ?)`, 1, userID, common.RoleGuest, common.UserMember, time.Now().Add(-time.Hour)).Exec()
+	require.Nil(t, err)
+
+	id, err := AddAccessRequest(&models.AccessRequest{
+		ProjectID:     1,
+		UserID:        int(userID),
+		Role:          common.RoleDeveloper,
+		Justification: "for testing",
+	})
+	require.Nil(t, err)
+	defer DeleteAccessRequest(id)
+
+	approved, err := ApproveAccessRequest(id, "admin")
+	require.Nil(t, err)
+	assert.True(t, approved)
+	req, err := GetAccessRequest(id)
+	require.Nil(t, err)
+	assert.Equal(t, models.AccessRequestApproved, req.Status)
+	assert.Equal(t, "admin", req.Reviewer)
+
+	// the membership is renewed with the requested role
+	member := &models.Member{}
+	require.Nil(t, GetOrmer().Raw(`select id, role, expiration_time from project_member
+		where project_id = ? and entity_id = ? and entity_type = ?`, 1, userID, common.UserMember).QueryRow(member))
+	assert.Equal(t, common.RoleDeveloper, member.Role)
+	assert.Nil(t, member.ExpirationTime)
+
+	// the request which isn't pending can not be approved again
+	approved, err = ApproveAccessRequest(id, "admin")
+	require.Nil(t, err)
+	assert.False(t, approved)
+}
@@ -38,6 +38,11 @@ func GetTotalOfAccessLogs(query *models.LogQueryParam) (int64, error) {
 	return logQueryConditions(query).Count()
 }
 
+// GetCappedTotalOfAccessLogs returns the total of the access logs but stops counting at max+1
+func GetCappedTotalOfAccessLogs(query *models.LogQueryParam, max int64) (int64, error) {
+	return cappedCountForQuerySetter(logQueryConditions(query), "log_id", max)
+}
+
 // GetAccessLogs gets access logs according to different conditions
 func GetAccessLogs(query *models.LogQueryParam) ([]models.AccessLog, error) {
 	qs := logQueryConditions(query).OrderBy("-op_time")
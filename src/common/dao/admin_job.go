@@ -73,6 +73,21 @@ func UpdateAdminJobStatus(id int64, status string) error {
 	return err
 }
 
+// UpdateAdminJobProgress updates the progress checked in by the job
+func UpdateAdminJobProgress(id int64, progress string) error {
+	o := GetOrmer()
+	j := models.AdminJob{
+		ID:         id,
+		Progress:   progress,
+		UpdateTime: time.Now(),
+	}
+	n, err := o.Update(&j, "Progress", "UpdateTime")
+	if n == 0 {
+		log.Warningf("no records are updated when updating admin job %d", id)
+	}
+	return err
+}
+
 // SetAdminJobUUID ...
 func SetAdminJobUUID(id int64, uuid string) error {
 	o := GetOrmer()
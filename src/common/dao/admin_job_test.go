@@ -50,6 +50,13 @@ func TestAddAdminJob(t *testing.T) {
 	job2, err := GetAdminJob(id)
 	assert.Equal(t, job2.Status, "testStatus")
 
+	// update progress
+	err = UpdateAdminJobProgress(id, "1/3")
+	require.Nil(t, err)
+	job2, err = GetAdminJob(id)
+	require.Nil(t, err)
+	assert.Equal(t, "1/3", job2.Progress)
+
 	// set uuid
 	err = SetAdminJobUUID(id, "f5ef34f4cb3588d663176132")
 	require.Nil(t, err)
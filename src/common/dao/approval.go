@@ -0,0 +1,119 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddApproval ...
+func AddApproval(approval *models.Approval) (int64, error) {
+	now := time.Now()
+	approval.CreationTime = now
+	approval.UpdateTime = now
+	return GetOrmer().Insert(approval)
+}
+
+// GetApproval returns the approval specified by ID, nil is returned if not found
+func GetApproval(id int64) (*models.Approval, error) {
+	approval := &models.Approval{
+		ID: id,
+	}
+	if err := GetOrmer().Read(approval); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return approval, nil
+}
+
+// GetApprovalByResource returns the latest approval of the resource, nil is returned if not found
+func GetApprovalByResource(approvalType string, resourceID int64) (*models.Approval, error) {
+	approvals := []*models.Approval{}
+	if _, err := GetOrmer().QueryTable(&models.Approval{}).
+		Filter("Type", approvalType).
+		Filter("ResourceID", resourceID).
+		OrderBy("-ID").
+		Limit(1).
+		All(&approvals); err != nil {
+		return nil, err
+	}
+	if len(approvals) == 0 {
+		return nil, nil
+	}
+	return approvals[0], nil
+}
+
+// UpdateApprovalStatus updates the status, reviewer and comment of the pending approval,
+// the returned count is 0 if the approval isn't pending any more
+func UpdateApprovalStatus(id int64, status, reviewer, comment string) (int64, error) {
+	return GetOrmer().QueryTable(&models.Approval{}).
+		Filter("ID", id).
+		Filter("Status", models.ApprovalPending).
+		Update(orm.Params{
+			"Status":     status,
+			"Reviewer":   reviewer,
+			"Comment":    comment,
+			"UpdateTime": time.Now(),
+		})
+}
+
+// ListApprovals lists the approvals according to the query conditions, the latest one is the first
+func ListApprovals(query *models.ApprovalQuery) ([]*models.Approval, error) {
+	approvals := []*models.Approval{}
+	if query != nil && query.ProjectIDs != nil && len(query.ProjectIDs) == 0 {
+		return approvals, nil
+	}
+	qs := getApprovalQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	_, err := qs.All(&approvals)
+	return approvals, err
+}
+
+// CountApprovals ...
+func CountApprovals(query *models.ApprovalQuery) (int64, error) {
+	if query != nil && query.ProjectIDs != nil && len(query.ProjectIDs) == 0 {
+		return 0, nil
+	}
+	return getApprovalQuerySetter(query).Count()
+}
+
+func getApprovalQuerySetter(query *models.ApprovalQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.Approval{})
+	if query == nil {
+		return qs
+	}
+	if len(query.Type) > 0 {
+		qs = qs.Filter("Type", query.Type)
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	if len(query.ProjectIDs) > 0 {
+		qs = qs.Filter("ProjectID__in", query.ProjectIDs)
+	}
+	return qs
+}
@@ -0,0 +1,85 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproval(t *testing.T) {
+	id1, err := AddApproval(&models.Approval{
+		Type:       models.ApprovalAccessRequest,
+		ResourceID: 1,
+		ProjectID:  1,
+		Requester:  "user01",
+		Status:     models.ApprovalPending,
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.ApprovalTable)
+	id2, err := AddApproval(&models.Approval{
+		Type:      models.ApprovalProjectCreation,
+		Requester: "user01",
+		Payload:   `{"project_name":"test"}`,
+		Status:    models.ApprovalPending,
+	})
+	require.Nil(t, err)
+
+	approval, err := GetApproval(id2)
+	require.Nil(t, err)
+	require.NotNil(t, approval)
+	assert.Equal(t, `{"project_name":"test"}`, approval.Payload)
+
+	approval, err = GetApprovalByResource(models.ApprovalAccessRequest, 1)
+	require.Nil(t, err)
+	require.NotNil(t, approval)
+	assert.Equal(t, id1, approval.ID)
+	approval, err = GetApprovalByResource(models.ApprovalPromotion, 1)
+	require.Nil(t, err)
+	assert.Nil(t, approval)
+
+	// the approvals of project 1
+	approvals, err := ListApprovals(&models.ApprovalQuery{
+		ProjectIDs: []int64{1},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(approvals))
+	assert.Equal(t, id1, approvals[0].ID)
+	// no project
+	total, err := CountApprovals(&models.ApprovalQuery{
+		ProjectIDs: []int64{},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	n, err := UpdateApprovalStatus(id1, models.ApprovalApproved, "admin", "welcome")
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = UpdateApprovalStatus(id1, models.ApprovalRejected, "admin", "")
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+
+	total, err = CountApprovals(&models.ApprovalQuery{
+		Status: models.ApprovalPending,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	approval, err = GetApproval(id1)
+	require.Nil(t, err)
+	assert.Equal(t, "welcome", approval.Comment)
+}
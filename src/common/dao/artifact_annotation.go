@@ -0,0 +1,88 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetArtifactAnnotation inserts or updates the CI metadata of the tag
+func SetArtifactAnnotation(annotation *models.ArtifactAnnotation) error {
+	now := time.Now()
+	sql := `insert into artifact_annotation (repository, tag, digest, revision, source, build_url, build_id, labels, creation_time, update_time)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		on conflict (repository, tag) do update set digest = excluded.digest, revision = excluded.revision,
+		source = excluded.source, build_url = excluded.build_url, build_id = excluded.build_id, labels = excluded.labels,
+		update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, annotation.Repository, annotation.Tag, annotation.Digest, annotation.Revision,
+		annotation.Source, annotation.BuildURL, annotation.BuildID, annotation.Labels, now, now).Exec()
+	return err
+}
+
+// GetArtifactAnnotation returns the CI metadata of the tag, nil is returned if not found
+func GetArtifactAnnotation(repository, tag string) (*models.ArtifactAnnotation, error) {
+	annotation := &models.ArtifactAnnotation{
+		Repository: repository,
+		Tag:        tag,
+	}
+	if err := GetOrmer().Read(annotation, "Repository", "Tag"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return annotation, nil
+}
+
+// DeleteArtifactAnnotation deletes the CI metadata of the tag
+func DeleteArtifactAnnotation(repository, tag string) error {
+	_, err := GetOrmer().QueryTable(&models.ArtifactAnnotation{}).
+		Filter("Repository", repository).
+		Filter("Tag", tag).
+		Delete()
+	return err
+}
+
+// ListArtifactAnnotations lists the CI metadata of the tags matching the query conditions exactly
+func ListArtifactAnnotations(query *models.ArtifactAnnotationQuery) ([]*models.ArtifactAnnotation, error) {
+	annotations := []*models.ArtifactAnnotation{}
+	qs := GetOrmer().QueryTable(&models.ArtifactAnnotation{})
+	if query != nil {
+		if query.ProjectNames != nil {
+			if len(query.ProjectNames) == 0 {
+				return annotations, nil
+			}
+			cond := orm.NewCondition()
+			for _, name := range query.ProjectNames {
+				cond = cond.Or("Repository__startswith", name+"/")
+			}
+			qs = qs.SetCond(cond)
+		}
+		if len(query.Revision) > 0 {
+			qs = qs.Filter("Revision", query.Revision)
+		}
+		if len(query.BuildURL) > 0 {
+			qs = qs.Filter("BuildURL", query.BuildURL)
+		}
+		if len(query.BuildID) > 0 {
+			qs = qs.Filter("BuildID", query.BuildID)
+		}
+	}
+	_, err := qs.OrderBy("Repository", "Tag").All(&annotations)
+	return annotations, err
+}
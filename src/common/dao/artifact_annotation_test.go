@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactAnnotation(t *testing.T) {
+	repository := "library/artifact-annotation-test"
+	annotation, err := GetArtifactAnnotation(repository, "latest")
+	require.Nil(t, err)
+	assert.Nil(t, annotation)
+
+	require.Nil(t, SetArtifactAnnotation(&models.ArtifactAnnotation{
+		Repository: repository,
+		Tag:        "latest",
+		Digest:     "sha256:1",
+		Revision:   "rev1",
+		BuildID:    "1",
+	}))
+	defer DeleteArtifactAnnotation(repository, "latest")
+	require.Nil(t, SetArtifactAnnotation(&models.ArtifactAnnotation{
+		Repository: repository,
+		Tag:        "v1",
+		Digest:     "sha256:1",
+		Revision:   "rev1",
+		BuildID:    "1",
+	}))
+	defer DeleteArtifactAnnotation(repository, "v1")
+
+	// the tag "latest" is pushed again
+	require.Nil(t, SetArtifactAnnotation(&models.ArtifactAnnotation{
+		Repository: repository,
+		Tag:        "latest",
+		Digest:     "sha256:2",
+		Revision:   "rev2",
+		BuildURL:   "https://ci.example.com/builds/2",
+		BuildID:    "2",
+	}))
+
+	annotation, err = GetArtifactAnnotation(repository, "latest")
+	require.Nil(t, err)
+	require.NotNil(t, annotation)
+	assert.Equal(t, "sha256:2", annotation.Digest)
+	assert.Equal(t, "rev2", annotation.Revision)
+	assert.Equal(t, "https://ci.example.com/builds/2", annotation.BuildURL)
+
+	// list by revision
+	annotations, err := ListArtifactAnnotations(&models.ArtifactAnnotationQuery{
+		Revision: "rev1",
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(annotations))
+	assert.Equal(t, "v1", annotations[0].Tag)
+
+	// list by build URL
+	annotations, err = ListArtifactAnnotations(&models.ArtifactAnnotationQuery{
+		BuildURL: "https://ci.example.com/builds/2",
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(annotations))
+	assert.Equal(t, "latest", annotations[0].Tag)
+
+	// list by build ID in the projects
+	annotations, err = ListArtifactAnnotations(&models.ArtifactAnnotationQuery{
+		BuildID:      "1",
+		ProjectNames: []string{"library"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(annotations))
+	annotations, err = ListArtifactAnnotations(&models.ArtifactAnnotationQuery{
+		BuildID:      "1",
+		ProjectNames: []string{"lib"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(annotations))
+	annotations, err = ListArtifactAnnotations(&models.ArtifactAnnotationQuery{
+		BuildID:      "1",
+		ProjectNames: []string{},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(annotations))
+
+	require.Nil(t, DeleteArtifactAnnotation(repository, "v1"))
+	annotation, err = GetArtifactAnnotation(repository, "v1")
+	require.Nil(t, err)
+	assert.Nil(t, annotation)
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetArtifactLint inserts or updates the lint findings of the tag
+func SetArtifactLint(artifactLint *models.ArtifactLint) error {
+	findings := artifactLint.Findings
+	if findings == nil {
+		findings = []*models.LintFinding{}
+	}
+	data, err := json.Marshal(findings)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	sql := `insert into artifact_lint (repository, tag, digest, findings, creation_time, update_time)
+		values (?, ?, ?, ?, ?, ?)
+		on conflict (repository, tag) do update set digest = excluded.digest, findings = excluded.findings,
+		update_time = excluded.update_time`
+	_, err = GetOrmer().Raw(sql, artifactLint.Repository, artifactLint.Tag, artifactLint.Digest,
+		string(data), now, now).Exec()
+	return err
+}
+
+// GetArtifactLint returns the lint findings of the tag, nil is returned if not found
+func GetArtifactLint(repository, tag string) (*models.ArtifactLint, error) {
+	artifactLint := &models.ArtifactLint{
+		Repository: repository,
+		Tag:        tag,
+	}
+	if err := GetOrmer().Read(artifactLint, "Repository", "Tag"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	artifactLint.Findings = []*models.LintFinding{}
+	if len(artifactLint.FindingsStr) > 0 {
+		if err := json.Unmarshal([]byte(artifactLint.FindingsStr), &artifactLint.Findings); err != nil {
+			return nil, err
+		}
+	}
+	return artifactLint, nil
+}
+
+// DeleteArtifactLint deletes the lint findings of the tag
+func DeleteArtifactLint(repository, tag string) error {
+	_, err := GetOrmer().QueryTable(&models.ArtifactLint{}).
+		Filter("Repository", repository).
+		Filter("Tag", tag).
+		Delete()
+	return err
+}
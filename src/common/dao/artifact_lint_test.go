@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactLint(t *testing.T) {
+	repository := "library/artifact-lint-test"
+	artifactLint, err := GetArtifactLint(repository, "latest")
+	require.Nil(t, err)
+	assert.Nil(t, artifactLint)
+
+	require.Nil(t, SetArtifactLint(&models.ArtifactLint{
+		Repository: repository,
+		Tag:        "latest",
+		Digest:     "sha256:1",
+		Findings: []*models.LintFinding{
+			{
+				Rule:    models.LintRuleRootUser,
+				Message: "the image runs as root",
+			},
+		},
+	}))
+	defer DeleteArtifactLint(repository, "latest")
+
+	artifactLint, err = GetArtifactLint(repository, "latest")
+	require.Nil(t, err)
+	require.NotNil(t, artifactLint)
+	assert.Equal(t, "sha256:1", artifactLint.Digest)
+	require.Equal(t, 1, len(artifactLint.Findings))
+	assert.Equal(t, models.LintRuleRootUser, artifactLint.Findings[0].Rule)
+
+	// the tag "latest" is pushed again without findings
+	require.Nil(t, SetArtifactLint(&models.ArtifactLint{
+		Repository: repository,
+		Tag:        "latest",
+		Digest:     "sha256:2",
+	}))
+	artifactLint, err = GetArtifactLint(repository, "latest")
+	require.Nil(t, err)
+	require.NotNil(t, artifactLint)
+	assert.Equal(t, "sha256:2", artifactLint.Digest)
+	assert.Equal(t, 0, len(artifactLint.Findings))
+
+	require.Nil(t, DeleteArtifactLint(repository, "latest"))
+	artifactLint, err = GetArtifactLint(repository, "latest")
+	require.Nil(t, err)
+	assert.Nil(t, artifactLint)
+}
@@ -0,0 +1,74 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddArtifactProvenance records the copy of the image, the time of the copy is now if it isn't set
+func AddArtifactProvenance(provenance *models.ArtifactProvenance) (int64, error) {
+	if provenance.OpTime.IsZero() {
+		provenance.OpTime = time.Now()
+	}
+	return GetOrmer().Insert(provenance)
+}
+
+// GetArtifactProvenance returns the latest copy of the image into the tag, nil is returned if
+// the image in the tag isn't copied
+func GetArtifactProvenance(repository, tag, digest string) (*models.ArtifactProvenance, error) {
+	provenances := []*models.ArtifactProvenance{}
+	_, err := GetOrmer().QueryTable(&models.ArtifactProvenance{}).
+		Filter("Repository", repository).
+		Filter("Tag", tag).
+		Filter("Digest", digest).
+		OrderBy("-OpTime", "-ID").
+		Limit(1).
+		All(&provenances)
+	if err != nil {
+		return nil, err
+	}
+	if len(provenances) == 0 {
+		return nil, nil
+	}
+	return provenances[0], nil
+}
+
+// GetProvenanceChain traces the image in the tag back through its copies, the copy into the tag
+// is the first and the one from the original build is the last. The chain is empty if the image
+// isn't copied, and it's cut at models.MaxProvenanceDepth or when a tag occurs again
+func GetProvenanceChain(repository, tag, digest string) ([]*models.ArtifactProvenance, error) {
+	chain := []*models.ArtifactProvenance{}
+	visited := map[string]bool{}
+	for len(chain) < models.MaxProvenanceDepth {
+		key := repository + ":" + tag
+		if visited[key] {
+			break
+		}
+		visited[key] = true
+		provenance, err := GetArtifactProvenance(repository, tag, digest)
+		if err != nil {
+			return nil, err
+		}
+		if provenance == nil {
+			break
+		}
+		chain = append(chain, provenance)
+		repository, tag = provenance.SourceRepository, provenance.SourceTag
+	}
+	return chain, nil
+}
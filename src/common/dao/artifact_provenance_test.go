@@ -0,0 +1,71 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenanceChain(t *testing.T) {
+	defer ClearTable(models.ArtifactProvenanceTable)
+	digest := "sha256:7173b809ca12ec5dee4506cd86be934c4596dd234ee82c0662eac04a8c2c71dc"
+	now := time.Now()
+
+	chain, err := GetProvenanceChain("prod/app", "1.0", digest)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(chain))
+
+	for _, p := range []*models.ArtifactProvenance{
+		{Repository: "staging/app", Tag: "1.0", SourceRepository: "dev/app", SourceTag: "build-42",
+			Operation: models.ProvenancePromote, OpTime: now.Add(-time.Hour)},
+		{Repository: "prod/app", Tag: "1.0", SourceRepository: "staging/app", SourceTag: "1.0",
+			Operation: models.ProvenancePromote, OpTime: now},
+		{Repository: "prod/app", Tag: "stable", SourceRepository: "prod/app", SourceTag: "1.0",
+			Operation: models.ProvenanceRetag, OpTime: now},
+		// the copy of another image into the tag
+		{Repository: "dev/app", Tag: "build-42", SourceRepository: "dev/base", SourceTag: "latest",
+			Operation: models.ProvenanceRetag, OpTime: now, Digest: "sha256:other"},
+	} {
+		if len(p.Digest) == 0 {
+			p.Digest = digest
+		}
+		p.Operator = "admin"
+		_, err = AddArtifactProvenance(p)
+		require.Nil(t, err)
+	}
+
+	chain, err = GetProvenanceChain("prod/app", "stable", digest)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(chain))
+	assert.Equal(t, "prod/app", chain[0].SourceRepository)
+	assert.Equal(t, "staging/app", chain[1].SourceRepository)
+	assert.Equal(t, "dev/app", chain[2].SourceRepository)
+	assert.Equal(t, "build-42", chain[2].SourceTag)
+
+	// the loop is cut
+	_, err = AddArtifactProvenance(&models.ArtifactProvenance{
+		Repository: "dev/app", Tag: "build-42", Digest: digest, SourceRepository: "prod/app", SourceTag: "stable",
+		Operation: models.ProvenanceRetag, Operator: "admin",
+	})
+	require.Nil(t, err)
+	chain, err = GetProvenanceChain("prod/app", "stable", digest)
+	require.Nil(t, err)
+	assert.Equal(t, 4, len(chain))
+}
@@ -107,12 +107,7 @@ func getDatabase(database *models.Database) (db Database, err error) {
 
 	switch database.Type {
 	case "", "postgresql":
-		db = NewPGSQL(database.PostGreSQL.Host,
-			strconv.Itoa(database.PostGreSQL.Port),
-			database.PostGreSQL.Username,
-			database.PostGreSQL.Password,
-			database.PostGreSQL.Database,
-			database.PostGreSQL.SSLMode)
+		db, err = newPGSQLWithOptions(database.PostGreSQL)
 	default:
 		err = fmt.Errorf("invalid database: %s", database.Type)
 	}
@@ -167,6 +162,22 @@ func paginateForQuerySetter(qs orm.QuerySeter, page, size int64) orm.QuerySeter
 	return qs
 }
 
+// cappedCountForQuerySetter counts at most max+1 records matching the query rather than
+// all of them, the field should be indexed, e.g. the primary key
+func cappedCountForQuerySetter(qs orm.QuerySeter, field string, max int64) (int64, error) {
+	var values orm.ParamsList
+	return qs.Limit(max+1).ValuesFlat(&values, field)
+}
+
+// cappedCountForRawSQL counts at most max+1 records matching the conditions, which are
+// the "from" and "where" clauses of the query, rather than all of them
+func cappedCountForRawSQL(conditions string, params []interface{}, max int64) (int64, error) {
+	sql := fmt.Sprintf(`select count(*) from (select 1 %s limit ?) t`, conditions)
+	var total int64
+	err := GetOrmer().Raw(sql, append(params, max+1)).QueryRow(&total)
+	return total, err
+}
+
 // Escape ..
 func Escape(str string) string {
 	str = strings.Replace(str, `%`, `\%`, -1)
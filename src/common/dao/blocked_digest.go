@@ -0,0 +1,97 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddBlockedDigest adds the digest into the blocklist, ErrDupRows is returned if it's blocked already
+func AddBlockedDigest(blocked *models.BlockedDigest) (int64, error) {
+	blocked.CreationTime = time.Now()
+	id, err := GetOrmer().Insert(blocked)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetBlockedDigest returns the blocked digest specified by ID, nil is returned if not found
+func GetBlockedDigest(id int64) (*models.BlockedDigest, error) {
+	blocked := &models.BlockedDigest{
+		ID: id,
+	}
+	if err := GetOrmer().Read(blocked); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return blocked, nil
+}
+
+// GetBlockedDigests returns the blocked ones among the digests
+func GetBlockedDigests(digests ...string) ([]*models.BlockedDigest, error) {
+	blocked := []*models.BlockedDigest{}
+	if len(digests) == 0 {
+		return blocked, nil
+	}
+	_, err := GetOrmer().QueryTable(&models.BlockedDigest{}).
+		Filter("Digest__in", digests).
+		All(&blocked)
+	return blocked, err
+}
+
+// ListBlockedDigests lists the blocked digests according to the query conditions, the latest one is the first
+func ListBlockedDigests(query *models.BlockedDigestQuery) ([]*models.BlockedDigest, error) {
+	qs := getBlockedDigestQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	blocked := []*models.BlockedDigest{}
+	_, err := qs.All(&blocked)
+	return blocked, err
+}
+
+// CountBlockedDigests ...
+func CountBlockedDigests(query *models.BlockedDigestQuery) (int64, error) {
+	return getBlockedDigestQuerySetter(query).Count()
+}
+
+func getBlockedDigestQuerySetter(query *models.BlockedDigestQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.BlockedDigest{})
+	if query != nil && len(query.Digest) > 0 {
+		qs = qs.Filter("Digest__contains", query.Digest)
+	}
+	return qs
+}
+
+// DeleteBlockedDigest removes the digest from the blocklist
+func DeleteBlockedDigest(id int64) error {
+	_, err := GetOrmer().Delete(&models.BlockedDigest{ID: id})
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockedDigest(t *testing.T) {
+	digest1 := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	digest2 := "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	id1, err := AddBlockedDigest(&models.BlockedDigest{
+		Digest:  digest1,
+		Reason:  "cryptominer",
+		Creator: "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.BlockedDigestTable)
+	_, err = AddBlockedDigest(&models.BlockedDigest{
+		Digest: digest1,
+	})
+	assert.Equal(t, ErrDupRows, err)
+	_, err = AddBlockedDigest(&models.BlockedDigest{
+		Digest: digest2,
+	})
+	require.Nil(t, err)
+
+	blocked, err := GetBlockedDigest(id1)
+	require.Nil(t, err)
+	require.NotNil(t, blocked)
+	assert.Equal(t, digest1, blocked.Digest)
+	assert.Equal(t, "cryptominer", blocked.Reason)
+
+	list, err := GetBlockedDigests(digest1, "sha256:not-blocked")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(list))
+	assert.Equal(t, id1, list[0].ID)
+
+	total, err := CountBlockedDigests(&models.BlockedDigestQuery{Digest: "2222"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	list, err = ListBlockedDigests(&models.BlockedDigestQuery{
+		Pagination: models.Pagination{Page: 1, Size: 1},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(list))
+
+	require.Nil(t, DeleteBlockedDigest(id1))
+	blocked, err = GetBlockedDigest(id1)
+	require.Nil(t, err)
+	assert.Nil(t, blocked)
+}
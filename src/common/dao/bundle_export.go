@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddBundleExport ...
+func AddBundleExport(export *models.BundleExport) (int64, error) {
+	now := time.Now()
+	export.CreationTime = now
+	export.UpdateTime = now
+	return GetOrmer().Insert(export)
+}
+
+// UpdateBundleExportStatus updates the status, message and the size of the bundle
+func UpdateBundleExportStatus(id int64, status, message string, size int64) error {
+	_, err := GetOrmer().QueryTable(&models.BundleExport{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"Status":     status,
+			"Message":    message,
+			"Size":       size,
+			"UpdateTime": time.Now(),
+		})
+	return err
+}
+
+// GetBundleExport returns the export specified by ID, nil is returned if not found
+func GetBundleExport(id int64) (*models.BundleExport, error) {
+	export := &models.BundleExport{
+		ID: id,
+	}
+	if err := GetOrmer().Read(export); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// ListBundleExports lists the exports according to the query conditions, the latest one is the first
+func ListBundleExports(query *models.BundleExportQuery) ([]*models.BundleExport, error) {
+	qs := getBundleExportQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	exports := []*models.BundleExport{}
+	_, err := qs.All(&exports)
+	return exports, err
+}
+
+// CountBundleExports ...
+func CountBundleExports(query *models.BundleExportQuery) (int64, error) {
+	return getBundleExportQuerySetter(query).Count()
+}
+
+// DeleteBundleExport ...
+func DeleteBundleExport(id int64) error {
+	_, err := GetOrmer().Delete(&models.BundleExport{
+		ID: id,
+	})
+	return err
+}
+
+func getBundleExportQuerySetter(query *models.BundleExportQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.BundleExport{})
+	if query != nil && len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleExport(t *testing.T) {
+	id, err := AddBundleExport(&models.BundleExport{
+		Projects: "library",
+		Status:   models.BundleExportRunning,
+		Creator:  "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.BundleExportTable)
+
+	export, err := GetBundleExport(id)
+	require.Nil(t, err)
+	require.NotNil(t, export)
+	assert.Equal(t, "library", export.Projects)
+	assert.Equal(t, models.BundleExportRunning, export.Status)
+
+	require.Nil(t, UpdateBundleExportStatus(id, models.BundleExportSucceeded, "", 1024))
+	export, err = GetBundleExport(id)
+	require.Nil(t, err)
+	require.NotNil(t, export)
+	assert.Equal(t, models.BundleExportSucceeded, export.Status)
+	assert.Equal(t, int64(1024), export.Size)
+
+	id2, err := AddBundleExport(&models.BundleExport{
+		Projects: "library,test",
+		Status:   models.BundleExportRunning,
+		Creator:  "admin",
+	})
+	require.Nil(t, err)
+
+	total, err := CountBundleExports(&models.BundleExportQuery{
+		Status: models.BundleExportRunning,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	exports, err := ListBundleExports(nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(exports))
+	assert.Equal(t, id2, exports[0].ID)
+
+	require.Nil(t, DeleteBundleExport(id))
+	export, err = GetBundleExport(id)
+	require.Nil(t, err)
+	assert.Nil(t, export)
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// UpdateRepTargetCABundle updates the encrypted CA bundle of the target, the empty bundle
+// removes it
+func UpdateRepTargetCABundle(id int64, bundle string) error {
+	_, err := GetOrmer().Raw(`update replication_target set ca_bundle = ?, update_time = ? where id = ?`,
+		bundle, time.Now(), id).Exec()
+	return err
+}
+
+// GetScannerCABundle returns the CA bundle of the scanner, nil is returned if the scanner has none
+func GetScannerCABundle(scannerID string) (*models.ScannerCABundle, error) {
+	bundles := []*models.ScannerCABundle{}
+	_, err := GetOrmer().QueryTable(&models.ScannerCABundle{}).
+		Filter("ScannerID", scannerID).
+		All(&bundles)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundles) == 0 {
+		return nil, nil
+	}
+	return bundles[0], nil
+}
+
+// ListScannerCABundles lists the CA bundles of all scanners
+func ListScannerCABundles() ([]*models.ScannerCABundle, error) {
+	bundles := []*models.ScannerCABundle{}
+	_, err := GetOrmer().QueryTable(&models.ScannerCABundle{}).
+		OrderBy("ScannerID").
+		All(&bundles)
+	return bundles, err
+}
+
+// SetScannerCABundle sets the encrypted CA bundle of the scanner, the bundle is created if
+// the scanner has none
+func SetScannerCABundle(scannerID, bundle string) error {
+	sql := `insert into scanner_ca_bundle (scanner_id, ca_bundle) values (?, ?) 
+		on conflict (scanner_id) do update set ca_bundle = excluded.ca_bundle`
+	_, err := GetOrmer().Raw(sql, scannerID, bundle).Exec()
+	return err
+}
+
+// DeleteScannerCABundle ...
+func DeleteScannerCABundle(scannerID string) error {
+	_, err := GetOrmer().QueryTable(&models.ScannerCABundle{}).
+		Filter("ScannerID", scannerID).
+		Delete()
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepTargetCABundle(t *testing.T) {
+	id, err := AddRepTarget(models.RepTarget{
+		URL:  "https://registry.example.com",
+		Name: "target_for_ca_bundle",
+	})
+	require.Nil(t, err)
+	defer DeleteRepTarget(id)
+
+	require.Nil(t, UpdateRepTargetCABundle(id, "bundle"))
+	target, err := GetRepTarget(id)
+	require.Nil(t, err)
+	assert.Equal(t, "bundle", target.CABundle)
+
+	// the bundle isn't changed by the update of the target
+	target.Username = "user"
+	require.Nil(t, UpdateRepTarget(*target))
+	target, err = GetRepTarget(id)
+	require.Nil(t, err)
+	assert.Equal(t, "bundle", target.CABundle)
+
+	require.Nil(t, UpdateRepTargetCABundle(id, ""))
+	target, err = GetRepTarget(id)
+	require.Nil(t, err)
+	assert.Equal(t, "", target.CABundle)
+}
+
+func TestScannerCABundle(t *testing.T) {
+	defer ClearTable(models.ScannerCABundleTable)
+
+	bundle, err := GetScannerCABundle("clair")
+	require.Nil(t, err)
+	assert.Nil(t, bundle)
+
+	require.Nil(t, SetScannerCABundle("clair", "bundle1"))
+	require.Nil(t, SetScannerCABundle("clair", "bundle2"))
+	bundle, err = GetScannerCABundle("clair")
+	require.Nil(t, err)
+	require.NotNil(t, bundle)
+	assert.Equal(t, "bundle2", bundle.CABundle)
+
+	bundles, err := ListScannerCABundles()
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(bundles))
+
+	require.Nil(t, DeleteScannerCABundle("clair"))
+	bundle, err = GetScannerCABundle("clair")
+	require.Nil(t, err)
+	assert.Nil(t, bundle)
+}
@@ -0,0 +1,182 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddChargebackReport adds the report, ErrDupRows is returned if the report of the billing
+// period exists already
+func AddChargebackReport(report *models.ChargebackReport) (int64, error) {
+	now := time.Now()
+	report.CreationTime = now
+	report.UpdateTime = now
+	id, err := GetOrmer().Insert(report)
+	if err != nil && isDupRecErr(err) {
+		return 0, ErrDupRows
+	}
+	return id, err
+}
+
+// UpdateChargebackReportStatus updates the status and message of the report
+func UpdateChargebackReportStatus(id int64, status, message string) error {
+	_, err := GetOrmer().QueryTable(&models.ChargebackReport{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"Status":     status,
+			"Message":    message,
+			"UpdateTime": time.Now(),
+		})
+	return err
+}
+
+// GetChargebackReport returns the report specified by ID, nil is returned if not found
+func GetChargebackReport(id int64) (*models.ChargebackReport, error) {
+	report := &models.ChargebackReport{
+		ID: id,
+	}
+	if err := GetOrmer().Read(report); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetChargebackReportByPeriod returns the report of the billing period, nil is returned if not found
+func GetChargebackReportByPeriod(start, end time.Time) (*models.ChargebackReport, error) {
+	report := &models.ChargebackReport{}
+	err := GetOrmer().QueryTable(&models.ChargebackReport{}).
+		Filter("StartTime", start).
+		Filter("EndTime", end).
+		One(report)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListChargebackReports lists the reports according to the query conditions, the one of
+// the latest period is the first
+func ListChargebackReports(query *models.ChargebackReportQuery) ([]*models.ChargebackReport, error) {
+	qs := getChargebackReportQuerySetter(query).OrderBy("-StartTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	reports := []*models.ChargebackReport{}
+	_, err := qs.All(&reports)
+	return reports, err
+}
+
+// CountChargebackReports ...
+func CountChargebackReports(query *models.ChargebackReportQuery) (int64, error) {
+	return getChargebackReportQuerySetter(query).Count()
+}
+
+// DeleteChargebackReport deletes the report, the items are deleted in cascade
+func DeleteChargebackReport(id int64) error {
+	_, err := GetOrmer().Delete(&models.ChargebackReport{
+		ID: id,
+	})
+	return err
+}
+
+func getChargebackReportQuerySetter(query *models.ChargebackReportQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.ChargebackReport{})
+	if query != nil && len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
+
+// ComputeChargebackReportItems computes the usage of the projects in the billing period.
+// The projects deleted before the end of the period are included only if they have
+// any usage in the period
+func ComputeChargebackReportItems(start, end time.Time) ([]*models.ChargebackReportItem, error) {
+	sql := `select p.project_id, p.name as project_name, 
+		coalesce(s.bytes, 0) as storage_bytes, 
+		coalesce(t.bytes, 0) as pull_bytes, 
+		coalesce(sj.minutes, 0) as scan_job_minutes, 
+		coalesce(rj.minutes, 0) as replication_job_minutes 
+		from project p 
+		left join (select project_id, sum(size) as bytes from project_blob 
+			group by project_id) s on s.project_id = p.project_id 
+		left join (select project_id, sum(bytes_served) as bytes from registry_traffic 
+			where day >= ? and day < ? group by project_id) t on t.project_id = p.project_id 
+		left join (select split_part(repository, '/', 1) as project_name, 
+			sum(extract(epoch from update_time - creation_time)) / 60 as minutes from img_scan_job 
+			where status in (?, ?, ?, ?) and update_time >= ? and update_time < ? 
+			group by split_part(repository, '/', 1)) sj on sj.project_name = p.name 
+		left join (select rp.project_id, 
+			sum(extract(epoch from j.update_time - j.creation_time)) / 60 as minutes 
+			from replication_job j join replication_policy rp on j.policy_id = rp.id 
+			where j.status in (?, ?, ?, ?) and j.update_time >= ? and j.update_time < ? 
+			group by rp.project_id) rj on rj.project_id = p.project_id 
+		where p.deleted = false or t.bytes is not null or sj.minutes is not null or rj.minutes is not null 
+		order by p.name`
+	completed := []interface{}{models.JobFinished, models.JobError, models.JobStopped, models.JobCanceled}
+	params := []interface{}{start.Format("2006-01-02"), end.Format("2006-01-02")}
+	params = append(params, completed...)
+	params = append(params, start, end)
+	params = append(params, completed...)
+	params = append(params, start, end)
+
+	items := []*models.ChargebackReportItem{}
+	_, err := GetOrmer().Raw(sql, params...).QueryRows(&items)
+	return items, err
+}
+
+// AddChargebackReportItems adds the items into the report
+func AddChargebackReportItems(reportID int64, items []*models.ChargebackReportItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	for _, item := range items {
+		item.ReportID = reportID
+	}
+	_, err := GetOrmer().InsertMulti(len(items), items)
+	return err
+}
+
+// DeleteChargebackReportItems deletes the items of the report
+func DeleteChargebackReportItems(reportID int64) error {
+	_, err := GetOrmer().QueryTable(&models.ChargebackReportItem{}).
+		Filter("ReportID", reportID).
+		Delete()
+	return err
+}
+
+// ListChargebackReportItems lists the items of the report in the order of the project names
+func ListChargebackReportItems(reportID int64) ([]*models.ChargebackReportItem, error) {
+	items := []*models.ChargebackReportItem{}
+	_, err := GetOrmer().QueryTable(&models.ChargebackReportItem{}).
+		Filter("ReportID", reportID).
+		OrderBy("ProjectName").
+		All(&items)
+	return items, err
+}
@@ -0,0 +1,143 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargebackReport(t *testing.T) {
+	start := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	id, err := AddChargebackReport(&models.ChargebackReport{
+		StartTime: start,
+		EndTime:   end,
+		Status:    models.ChargebackReportRunning,
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.ChargebackReportTable)
+
+	report, err := GetChargebackReportByPeriod(start, end)
+	require.Nil(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, id, report.ID)
+
+	_, err = AddChargebackReport(&models.ChargebackReport{
+		StartTime: start,
+		EndTime:   end,
+		Status:    models.ChargebackReportRunning,
+	})
+	assert.Equal(t, ErrDupRows, err)
+
+	require.Nil(t, AddChargebackReportItems(id, []*models.ChargebackReportItem{
+		{ProjectID: 2, ProjectName: "b", StorageBytes: 10},
+		{ProjectID: 1, ProjectName: "a", PullBytes: 20, ScanJobMinutes: 1.5},
+	}))
+	items, err := ListChargebackReportItems(id)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(items))
+	assert.Equal(t, "a", items[0].ProjectName)
+	assert.Equal(t, 1.5, items[0].ScanJobMinutes)
+
+	require.Nil(t, DeleteChargebackReportItems(id))
+	items, err = ListChargebackReportItems(id)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(items))
+	require.Nil(t, AddChargebackReportItems(id, []*models.ChargebackReportItem{
+		{ProjectID: 1, ProjectName: "a"},
+	}))
+
+	require.Nil(t, UpdateChargebackReportStatus(id, models.ChargebackReportSucceeded, ""))
+	report, err = GetChargebackReport(id)
+	require.Nil(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, models.ChargebackReportSucceeded, report.Status)
+
+	id2, err := AddChargebackReport(&models.ChargebackReport{
+		StartTime: end,
+		EndTime:   end.AddDate(0, 1, 0),
+		Status:    models.ChargebackReportRunning,
+	})
+	require.Nil(t, err)
+	require.Nil(t, UpdateChargebackReportStatus(id2, models.ChargebackReportFailed, "failed"))
+
+	total, err := CountChargebackReports(&models.ChargebackReportQuery{
+		Status: models.ChargebackReportFailed,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	reports, err := ListChargebackReports(nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(reports))
+	assert.Equal(t, id2, reports[0].ID)
+
+	require.Nil(t, DeleteChargebackReport(id))
+	report, err = GetChargebackReport(id)
+	require.Nil(t, err)
+	assert.Nil(t, report)
+	items, err = ListChargebackReportItems(id)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(items))
+}
+
+func TestComputeChargebackReportItems(t *testing.T) {
+	projectID, err := AddProject(models.Project{
+		Name:    "dao-chargeback",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer delProjPermanent(projectID)
+	defer DeleteProjectBlobs(projectID)
+	defer GetOrmer().Raw(`delete from registry_traffic where project_id = ?`, projectID).Exec()
+	defer GetOrmer().Raw(`delete from img_scan_job where repository like 'dao-chargeback/%'`).Exec()
+
+	start := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	require.Nil(t, AddProjectBlobs(projectID, []*models.ProjectBlob{
+		{Digest: "sha256:1", Size: 100},
+		{Digest: "sha256:2", Size: 200},
+	}))
+	require.Nil(t, AddRegistryTraffic([]*models.RegistryTraffic{
+		{ProjectName: "dao-chargeback", Repository: "dao-chargeback/app", Day: start, BytesServed: 1000},
+		// out of the period
+		{ProjectName: "dao-chargeback", Repository: "dao-chargeback/app", Day: end, BytesServed: 1000},
+	}))
+	_, err = GetOrmer().Raw(`insert into img_scan_job (status, repository, tag, creation_time, update_time) 
+		values (?, 'dao-chargeback/app', 'latest', ?, ?), (?, 'dao-chargeback/app', 'latest', ?, ?)`,
+		models.JobFinished, start, start.Add(90*time.Second),
+		models.JobRunning, start, start.Add(time.Hour)).Exec()
+	require.Nil(t, err)
+
+	items, err := ComputeChargebackReportItems(start, end)
+	require.Nil(t, err)
+	var item *models.ChargebackReportItem
+	for _, i := range items {
+		if i.ProjectID == projectID {
+			item = i
+		}
+	}
+	require.NotNil(t, item)
+	assert.Equal(t, "dao-chargeback", item.ProjectName)
+	assert.Equal(t, int64(300), item.StorageBytes)
+	assert.Equal(t, int64(1000), item.PullBytes)
+	assert.Equal(t, 1.5, item.ScanJobMinutes)
+	assert.Equal(t, float64(0), item.ReplicationJobMinutes)
+}
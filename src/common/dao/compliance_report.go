@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddComplianceReport ...
+func AddComplianceReport(report *models.ComplianceReport) (int64, error) {
+	now := time.Now()
+	report.CreationTime = now
+	report.UpdateTime = now
+	return GetOrmer().Insert(report)
+}
+
+// UpdateComplianceReportStatus updates the status, message and the size of the bundle
+func UpdateComplianceReportStatus(id int64, status, message string, size int64) error {
+	_, err := GetOrmer().QueryTable(&models.ComplianceReport{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"Status":     status,
+			"Message":    message,
+			"Size":       size,
+			"UpdateTime": time.Now(),
+		})
+	return err
+}
+
+// GetComplianceReport returns the report specified by ID, nil is returned if not found
+func GetComplianceReport(id int64) (*models.ComplianceReport, error) {
+	report := &models.ComplianceReport{
+		ID: id,
+	}
+	if err := GetOrmer().Read(report); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListComplianceReports lists the reports according to the query conditions, the latest one is the first
+func ListComplianceReports(query *models.ComplianceReportQuery) ([]*models.ComplianceReport, error) {
+	qs := getComplianceReportQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	reports := []*models.ComplianceReport{}
+	_, err := qs.All(&reports)
+	return reports, err
+}
+
+// CountComplianceReports ...
+func CountComplianceReports(query *models.ComplianceReportQuery) (int64, error) {
+	return getComplianceReportQuerySetter(query).Count()
+}
+
+// DeleteComplianceReport ...
+func DeleteComplianceReport(id int64) error {
+	_, err := GetOrmer().Delete(&models.ComplianceReport{
+		ID: id,
+	})
+	return err
+}
+
+func getComplianceReportQuerySetter(query *models.ComplianceReportQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.ComplianceReport{})
+	if query == nil {
+		return qs
+	}
+	if query.ProjectID > 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
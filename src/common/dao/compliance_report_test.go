@@ -0,0 +1,85 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplianceReport(t *testing.T) {
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+	id, err := AddComplianceReport(&models.ComplianceReport{
+		ProjectID: 1,
+		StartTime: start,
+		EndTime:   end,
+		Status:    models.ComplianceReportRunning,
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.ComplianceReportTable)
+
+	report, err := GetComplianceReport(id)
+	require.Nil(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, int64(1), report.ProjectID)
+	assert.Equal(t, models.ComplianceReportRunning, report.Status)
+
+	require.Nil(t, UpdateComplianceReportStatus(id, models.ComplianceReportSucceeded, "", 1024))
+	report, err = GetComplianceReport(id)
+	require.Nil(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, models.ComplianceReportSucceeded, report.Status)
+	assert.Equal(t, int64(1024), report.Size)
+
+	id2, err := AddComplianceReport(&models.ComplianceReport{
+		ProjectID: 1,
+		StartTime: start,
+		EndTime:   end,
+		Status:    models.ComplianceReportRunning,
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+
+	total, err := CountComplianceReports(&models.ComplianceReportQuery{
+		ProjectID: 1,
+		Status:    models.ComplianceReportRunning,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	require.Nil(t, UpdateComplianceReportStatus(id2, models.ComplianceReportFailed, "failed", 0))
+	report, err = GetComplianceReport(id2)
+	require.Nil(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, models.ComplianceReportFailed, report.Status)
+
+	reports, err := ListComplianceReports(&models.ComplianceReportQuery{
+		ProjectID: 1,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(reports))
+	assert.Equal(t, id2, reports[0].ID)
+
+	require.Nil(t, DeleteComplianceReport(id2))
+	report, err = GetComplianceReport(id2)
+	require.Nil(t, err)
+	assert.Nil(t, report)
+}
@@ -15,6 +15,7 @@
 package dao
 
 import (
+	"github.com/astaxie/beego/orm"
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
@@ -50,28 +51,23 @@ func GetConfigEntries() ([]*models.ConfigEntry, error) {
 	return p, nil
 }
 
-// SaveConfigEntries Save configuration to database.
+// SaveConfigEntries saves the configuration entries into database in one transaction,
+// none of them is saved if any of them fails
 func SaveConfigEntries(entries []models.ConfigEntry) error {
-	o := GetOrmer()
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
 	for _, entry := range entries {
 		if entry.Key == common.LdapGroupAdminDn {
 			entry.Value = utils.TrimLower(entry.Value)
 		}
-		tempEntry := models.ConfigEntry{}
-		tempEntry.Key = entry.Key
-		tempEntry.Value = entry.Value
-		created, _, err := o.ReadOrCreate(&tempEntry, "k")
-		if err != nil && !isDupRecErr(err) {
-			log.Errorf("Error create configuration entry: %v", err)
+		if _, err := o.Raw(`insert into properties (k, v) values (?, ?) 
+			on conflict (k) do update set v = excluded.v`, entry.Key, entry.Value).Exec(); err != nil {
+			o.Rollback()
+			log.Errorf("Error save configuration entry %s: %v", entry.Key, err)
 			return err
 		}
-		if !created {
-			entry.ID = tempEntry.ID
-			_, err := o.Update(&entry, "v")
-			if err != nil {
-				return err
-			}
-		}
 	}
-	return nil
+	return o.Commit()
 }
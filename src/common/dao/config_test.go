@@ -15,9 +15,12 @@
 package dao
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuthModeCanBeModified(t *testing.T) {
@@ -69,3 +72,32 @@ func TestAuthModeCanBeModified(t *testing.T) {
 		}
 	}
 }
+
+func TestSaveConfigEntriesInTransaction(t *testing.T) {
+	defer GetOrmer().Raw(`delete from properties where k in (?, ?)`, "dao_config_test_1", "dao_config_test_2").Exec()
+
+	require.Nil(t, SaveConfigEntries([]models.ConfigEntry{
+		{Key: "dao_config_test_1", Value: "value1"},
+	}))
+	// updated
+	require.Nil(t, SaveConfigEntries([]models.ConfigEntry{
+		{Key: "dao_config_test_1", Value: "value2"},
+	}))
+
+	// nothing is saved if any of the entries fails, the value exceeds the column
+	err := SaveConfigEntries([]models.ConfigEntry{
+		{Key: "dao_config_test_1", Value: "value3"},
+		{Key: "dao_config_test_2", Value: strings.Repeat("v", 2048)},
+	})
+	require.NotNil(t, err)
+
+	entries, err := GetConfigEntries()
+	require.Nil(t, err)
+	values := map[string]string{}
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+	assert.Equal(t, "value2", values["dao_config_test_1"])
+	_, exist := values["dao_config_test_2"]
+	assert.False(t, exist)
+}
@@ -0,0 +1,51 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddConfirmationToken records the confirmation token
+func AddConfirmationToken(token *models.ConfirmationToken) error {
+	_, err := GetOrmer().Insert(token)
+	return err
+}
+
+// ConsumeConfirmationToken deletes the token and returns whether it's valid for the
+// operation, so the token can only be used once even if it's consumed by several
+// instances at the same time
+func ConsumeConfirmationToken(token, username, method, path string) (bool, error) {
+	result, err := GetOrmer().Raw(`delete from confirmation_token 
+		where token = ? and username = ? and method = ? and path = ? and expires_at > ?`,
+		token, username, method, path, time.Now()).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteExpiredConfirmationTokens deletes the expired tokens and returns the count of them
+func DeleteExpiredConfirmationTokens() (int64, error) {
+	return GetOrmer().QueryTable(&models.ConfirmationToken{}).
+		Filter("ExpiresAt__lte", time.Now()).
+		Delete()
+}
@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmationToken(t *testing.T) {
+	defer GetOrmer().Raw(`delete from confirmation_token`).Exec()
+
+	require.Nil(t, AddConfirmationToken(&models.ConfirmationToken{
+		Token:     "token1",
+		Username:  "admin",
+		Method:    http.MethodDelete,
+		Path:      "/api/projects/1",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}))
+
+	// mismatched operation
+	valid, err := ConsumeConfirmationToken("token1", "user", http.MethodDelete, "/api/projects/1")
+	require.Nil(t, err)
+	assert.False(t, valid)
+	valid, err = ConsumeConfirmationToken("token1", "admin", http.MethodDelete, "/api/projects/2")
+	require.Nil(t, err)
+	assert.False(t, valid)
+	valid, err = ConsumeConfirmationToken("invalid", "admin", http.MethodDelete, "/api/projects/1")
+	require.Nil(t, err)
+	assert.False(t, valid)
+
+	// the token can only be used once
+	valid, err = ConsumeConfirmationToken("token1", "admin", http.MethodDelete, "/api/projects/1")
+	require.Nil(t, err)
+	assert.True(t, valid)
+	valid, err = ConsumeConfirmationToken("token1", "admin", http.MethodDelete, "/api/projects/1")
+	require.Nil(t, err)
+	assert.False(t, valid)
+
+	// expired token
+	require.Nil(t, AddConfirmationToken(&models.ConfirmationToken{
+		Token:     "token2",
+		Username:  "admin",
+		Method:    http.MethodDelete,
+		Path:      "/api/projects/1",
+		ExpiresAt: time.Now().Add(-time.Second),
+	}))
+	valid, err = ConsumeConfirmationToken("token2", "admin", http.MethodDelete, "/api/projects/1")
+	require.Nil(t, err)
+	assert.False(t, valid)
+
+	n, err := DeleteExpiredConfirmationTokens()
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+}
@@ -0,0 +1,141 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credential
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	awsService = "rds-db"
+	// the max lifetime of the authentication token of RDS
+	awsTokenExpiration = 15 * time.Minute
+	// the SHA256 of the empty payload
+	awsEmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// awsCredentialsFromEnv reads the credentials from the standard environment variables of AWS,
+// which are injected into the containers when running with the IAM role of the task or pod
+var awsCredentialsFromEnv = func() (*awsCredentials, error) {
+	creds := &awsCredentials{
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if len(creds.accessKeyID) == 0 || len(creds.secretAccessKey) == 0 {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required in the aws_iam mode")
+	}
+	return creds, nil
+}
+
+// awsIAMToken generates the authentication token of RDS, which is a URL presigned with
+// the signature version 4 of AWS
+func awsIAMToken(host string, port int, user, region string, now time.Time) (string, time.Time, error) {
+	creds, err := awsCredentialsFromEnv()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return presignRDSToken(creds, net.JoinHostPort(host, strconv.Itoa(port)), user, region, now), now.Add(awsTokenExpiration), nil
+}
+
+func presignRDSToken(creds *awsCredentials, endpoint, user, region string, now time.Time) string {
+	now = now.UTC()
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := strings.Join([]string{date, region, awsService, "aws4_request"}, "/")
+
+	query := map[string]string{
+		"Action":              "connect",
+		"DBUser":              user,
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    creds.accessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(awsTokenExpiration / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if len(creds.sessionToken) > 0 {
+		query["X-Amz-Security-Token"] = creds.sessionToken
+	}
+	canonicalQuery := canonicalQueryString(query)
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		"/",
+		canonicalQuery,
+		"host:" + endpoint + "\n",
+		"host",
+		awsEmptyPayloadHash,
+	}, "\n")
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+
+	key := awsSigningKey(creds.secretAccessKey, date, region, awsService)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	return fmt.Sprintf("%s/?%s&X-Amz-Signature=%s", endpoint, canonicalQuery, signature)
+}
+
+// canonicalQueryString sorts the parameters by name and encodes them as required by AWS
+func canonicalQueryString(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, awsEscape(k)+"="+awsEscape(query[k]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes all the characters except the unreserved ones of RFC 3986
+func awsEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+func awsSigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
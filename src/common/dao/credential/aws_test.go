@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credential
+
+import (
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSSigningKey(t *testing.T) {
+	// the example in the document of AWS signature version 4
+	key := awsSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestAWSEscape(t *testing.T) {
+	assert.Equal(t, "AKID%2F20190101%2Fus-east-1%2Frds-db%2Faws4_request", awsEscape("AKID/20190101/us-east-1/rds-db/aws4_request"))
+	assert.Equal(t, "a%20b~c", awsEscape("a b~c"))
+}
+
+func TestPresignRDSToken(t *testing.T) {
+	creds := &awsCredentials{
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		sessionToken:    "session+token",
+	}
+	now := time.Date(2019, 3, 1, 8, 30, 0, 0, time.UTC)
+	token := presignRDSToken(creds, "db.example.com:5432", "harbor", "us-east-1", now)
+
+	require.True(t, strings.HasPrefix(token, "db.example.com:5432/?"))
+	u, err := url.Parse("https://" + token)
+	require.Nil(t, err)
+	query := u.Query()
+	assert.Equal(t, "connect", query.Get("Action"))
+	assert.Equal(t, "harbor", query.Get("DBUser"))
+	assert.Equal(t, "AKIDEXAMPLE/20190301/us-east-1/rds-db/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20190301T083000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.Equal(t, "session+token", query.Get("X-Amz-Security-Token"))
+	assert.Len(t, query.Get("X-Amz-Signature"), 64)
+
+	// the signature is stable
+	assert.Equal(t, token, presignRDSToken(creds, "db.example.com:5432", "harbor", "us-east-1", now))
+	assert.NotEqual(t, token, presignRDSToken(creds, "db.example.com:5432", "harbor", "us-east-1", now.Add(time.Second)))
+}
@@ -0,0 +1,84 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	azureAPIVersion = "2018-02-01"
+	// the resource of Azure Database for PostgreSQL
+	azureResource = "https://ossrdbms-aad.database.windows.net"
+)
+
+// azureTokenEndpoint is the endpoint of instance metadata service of Azure
+var azureTokenEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+var azureClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// azureMSIToken gets the access token of the managed identity from the instance metadata
+// service, the system assigned identity is used if the clientID is empty
+func azureMSIToken(clientID string) (string, time.Time, error) {
+	query := url.Values{}
+	query.Set("api-version", azureAPIVersion)
+	query.Set("resource", azureResource)
+	if len(clientID) > 0 {
+		query.Set("client_id", clientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, azureTokenEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := azureClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("unexpected status code %d from the metadata service: %s", resp.StatusCode, string(data))
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+		// the seconds since epoch
+		ExpiresOn string `json:"expires_on"`
+	}{}
+	if err = json.Unmarshal(data, &token); err != nil {
+		return "", time.Time{}, err
+	}
+	if len(token.AccessToken) == 0 {
+		return "", time.Time{}, errors.New("no access token in the response of metadata service")
+	}
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid expiration of the access token %q: %v", token.ExpiresOn, err)
+	}
+	return token.AccessToken, time.Unix(expiresOn, 0), nil
+}
@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credential
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureMSIToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("client_id") == "unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"access_token":"token","expires_on":"1551430000","resource":"https://ossrdbms-aad.database.windows.net"}`))
+	}))
+	defer server.Close()
+
+	endpoint := azureTokenEndpoint
+	azureTokenEndpoint = server.URL
+	defer func() {
+		azureTokenEndpoint = endpoint
+	}()
+
+	token, expiresAt, err := azureMSIToken("")
+	require.Nil(t, err)
+	assert.Equal(t, "token", token)
+	assert.Equal(t, int64(1551430000), expiresAt.Unix())
+
+	_, _, err = azureMSIToken("unknown")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,113 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credential provides the credentials to connect to the PostgreSQL. Besides the
+// static password, the short-lived tokens of AWS RDS IAM authentication and Azure managed
+// identity are supported, they are refreshed automatically before expiring.
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the modes of authentication
+const (
+	ModePassword = "password"
+	ModeAWSIAM   = "aws_iam"
+	ModeAzureMSI = "azure_msi"
+)
+
+// the token is refreshed when it's going to expire in the duration
+const refreshAhead = 2 * time.Minute
+
+// Provider provides the password to connect to the database
+type Provider interface {
+	// Password returns the password, it may be a token which is refreshed periodically
+	Password() (string, error)
+	// Static returns true if the password never changes
+	Static() bool
+}
+
+// NewProvider returns the provider according to the authentication mode of the database
+func NewProvider(db *models.PostGreSQL) (Provider, error) {
+	switch db.AuthMode {
+	case "", ModePassword:
+		return Static(db.Password), nil
+	case ModeAWSIAM:
+		if len(db.AWSRegion) == 0 {
+			return nil, errors.New("the region of AWS is required in the aws_iam mode")
+		}
+		return &tokenProvider{
+			fetch: func() (string, time.Time, error) {
+				return awsIAMToken(db.Host, db.Port, db.Username, db.AWSRegion, time.Now())
+			},
+		}, nil
+	case ModeAzureMSI:
+		return &tokenProvider{
+			fetch: func() (string, time.Time, error) {
+				return azureMSIToken(db.AzureClientID)
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication mode of database: %s", db.AuthMode)
+	}
+}
+
+// Static returns the provider of the static password
+func Static(password string) Provider {
+	return &staticProvider{password: password}
+}
+
+type staticProvider struct {
+	password string
+}
+
+func (s *staticProvider) Password() (string, error) {
+	return s.password, nil
+}
+
+func (s *staticProvider) Static() bool {
+	return true
+}
+
+// tokenProvider caches the token until it's going to expire
+type tokenProvider struct {
+	lock      sync.Mutex
+	fetch     func() (token string, expiresAt time.Time, err error)
+	token     string
+	expiresAt time.Time
+}
+
+func (t *tokenProvider) Password() (string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.token) > 0 && time.Now().Add(refreshAhead).Before(t.expiresAt) {
+		return t.token, nil
+	}
+	token, expiresAt, err := t.fetch()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the token of database: %v", err)
+	}
+	t.token, t.expiresAt = token, expiresAt
+	return token, nil
+}
+
+func (t *tokenProvider) Static() bool {
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credential
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProvider(t *testing.T) {
+	// password mode
+	p, err := NewProvider(&models.PostGreSQL{Password: "root123"})
+	require.Nil(t, err)
+	assert.True(t, p.Static())
+	password, err := p.Password()
+	require.Nil(t, err)
+	assert.Equal(t, "root123", password)
+
+	// aws_iam mode without region
+	_, err = NewProvider(&models.PostGreSQL{AuthMode: ModeAWSIAM})
+	assert.NotNil(t, err)
+
+	// aws_iam mode
+	p, err = NewProvider(&models.PostGreSQL{AuthMode: ModeAWSIAM, AWSRegion: "us-east-1"})
+	require.Nil(t, err)
+	assert.False(t, p.Static())
+
+	// azure_msi mode
+	p, err = NewProvider(&models.PostGreSQL{AuthMode: ModeAzureMSI})
+	require.Nil(t, err)
+	assert.False(t, p.Static())
+
+	// unknown mode
+	_, err = NewProvider(&models.PostGreSQL{AuthMode: "unknown"})
+	assert.NotNil(t, err)
+}
+
+func TestTokenProvider(t *testing.T) {
+	count := 0
+	expiresIn := time.Hour
+	p := &tokenProvider{
+		fetch: func() (string, time.Time, error) {
+			count++
+			if count > 3 {
+				return "", time.Time{}, errors.New("failed")
+			}
+			return "token", time.Now().Add(expiresIn), nil
+		},
+	}
+
+	// fetched at the first time
+	token, err := p.Password()
+	require.Nil(t, err)
+	assert.Equal(t, "token", token)
+	assert.Equal(t, 1, count)
+
+	// cached
+	_, err = p.Password()
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	// refreshed as it's going to expire
+	p.expiresAt = time.Now().Add(refreshAhead / 2)
+	_, err = p.Password()
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	// failed to refresh
+	count = 3
+	p.expiresAt = time.Now()
+	_, err = p.Password()
+	assert.NotNil(t, err)
+}
@@ -762,6 +762,7 @@ func TestUpdateRepTarget(t *testing.T) {
 	target.URL = "http://new_url"
 	target.Username = "new_username"
 	target.Password = "new_password"
+	target.CredentialRef = "vault:secret/data/harbor"
 
 	if err = UpdateRepTarget(*target); err != nil {
 		t.Fatalf("failed to update target: %v", err)
@@ -787,6 +788,10 @@ func TestUpdateRepTarget(t *testing.T) {
 	if target.Password != "new_password" {
 		t.Errorf("unexpected password: %s, expected: %s", target.Password, "new_password")
 	}
+
+	if target.CredentialRef != "vault:secret/data/harbor" {
+		t.Errorf("unexpected credential reference: %s, expected: %s", target.CredentialRef, "vault:secret/data/harbor")
+	}
 }
 
 func TestFilterRepTargets(t *testing.T) {
@@ -938,6 +943,33 @@ func TestUpdateRepJobStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateRepJobReport(t *testing.T) {
+	err := UpdateRepJobReport(jobID, &models.RepJobReport{
+		Size:  1024,
+		Error: "failed to push manifest",
+	})
+	require.Nil(t, err)
+	j, err := GetRepJob(jobID)
+	require.Nil(t, err)
+	require.NotNil(t, j)
+	assert.Equal(t, int64(1024), j.Size)
+	assert.Equal(t, "failed to push manifest", j.Error)
+}
+
+func TestGetRepJobsAfter(t *testing.T) {
+	query := &models.RepJobQuery{
+		PolicyID: policyID,
+	}
+	jobs, err := GetRepJobsAfter(query, 0, 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(jobs))
+	assert.Equal(t, jobID, jobs[0].ID)
+
+	jobs, err = GetRepJobsAfter(query, jobID, 1)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(jobs))
+}
+
 func TestGetRepPolicyByProject(t *testing.T) {
 	p1, err := GetRepPolicyByProject(99)
 	if err != nil {
@@ -1269,6 +1301,26 @@ func TestUpdateScanJobStatus(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestCountScanJobsByStatus(t *testing.T) {
+	assert := assert.New(t)
+	since := time.Now().Add(-time.Minute)
+	_, err := AddScanJob(sj1)
+	assert.Nil(err)
+	id, err := AddScanJob(sj2)
+	assert.Nil(err)
+	err = UpdateScanJobStatus(id, models.JobError)
+	assert.Nil(err)
+	counts, err := CountScanJobsByStatus(since)
+	assert.Nil(err)
+	assert.Equal(int64(1), counts[models.JobPending])
+	assert.Equal(int64(1), counts[models.JobError])
+	counts, err = CountScanJobsByStatus(time.Now().Add(time.Hour))
+	assert.Nil(err)
+	assert.Equal(0, len(counts))
+	err = ClearTable(models.ScanJobTable)
+	assert.Nil(err)
+}
+
 func TestImgScanOverview(t *testing.T) {
 	assert := assert.New(t)
 	err := ClearTable(models.ScanOverviewTable)
@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddFederationPeer adds the peer instance, ErrDupRows is returned if the name is used already
+func AddFederationPeer(peer *models.FederationPeer) (int64, error) {
+	now := time.Now()
+	peer.CreationTime = now
+	peer.UpdateTime = now
+	id, err := GetOrmer().Insert(peer)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetFederationPeer returns the peer instance specified by ID, nil is returned if not found
+func GetFederationPeer(id int64) (*models.FederationPeer, error) {
+	peer := &models.FederationPeer{
+		ID: id,
+	}
+	if err := GetOrmer().Read(peer); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return peer, nil
+}
+
+// ListFederationPeers lists all the peer instances ordered by name
+func ListFederationPeers() ([]*models.FederationPeer, error) {
+	peers := []*models.FederationPeer{}
+	_, err := GetOrmer().QueryTable(&models.FederationPeer{}).OrderBy("Name").All(&peers)
+	return peers, err
+}
+
+// UpdateFederationPeer updates the peer instance, ErrDupRows is returned if the name is used already
+func UpdateFederationPeer(peer *models.FederationPeer) error {
+	peer.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(peer, "Name", "URL", "Username", "Password", "Insecure", "Timeout", "UpdateTime")
+	if err != nil && strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+		return ErrDupRows
+	}
+	return err
+}
+
+// DeleteFederationPeer removes the peer instance
+func DeleteFederationPeer(id int64) error {
+	_, err := GetOrmer().Delete(&models.FederationPeer{ID: id})
+	return err
+}
@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFederationPeer(t *testing.T) {
+	id1, err := AddFederationPeer(&models.FederationPeer{
+		Name:     "us-east",
+		URL:      "https://us-east.example.com",
+		Username: "searcher",
+		Password: "encrypted",
+		Timeout:  5,
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.FederationPeerTable)
+	_, err = AddFederationPeer(&models.FederationPeer{
+		Name: "us-east",
+		URL:  "https://another.example.com",
+	})
+	assert.Equal(t, ErrDupRows, err)
+	id2, err := AddFederationPeer(&models.FederationPeer{
+		Name: "eu-west",
+		URL:  "https://eu-west.example.com",
+	})
+	require.Nil(t, err)
+
+	peer, err := GetFederationPeer(id1)
+	require.Nil(t, err)
+	require.NotNil(t, peer)
+	assert.Equal(t, "https://us-east.example.com", peer.URL)
+	assert.Equal(t, "searcher", peer.Username)
+	assert.Equal(t, 5, peer.Timeout)
+
+	peers, err := ListFederationPeers()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(peers))
+	assert.Equal(t, "eu-west", peers[0].Name)
+
+	peer.Name = "eu-west"
+	assert.Equal(t, ErrDupRows, UpdateFederationPeer(peer))
+	peer.Name = "us-east-1"
+	peer.Insecure = true
+	require.Nil(t, UpdateFederationPeer(peer))
+	peer, err = GetFederationPeer(id1)
+	require.Nil(t, err)
+	assert.Equal(t, "us-east-1", peer.Name)
+	assert.True(t, peer.Insecure)
+
+	require.Nil(t, DeleteFederationPeer(id2))
+	peer, err = GetFederationPeer(id2)
+	require.Nil(t, err)
+	assert.Nil(t, peer)
+}
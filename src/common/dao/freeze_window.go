@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddFreezeWindow ...
+func AddFreezeWindow(window *models.FreezeWindow) (int64, error) {
+	now := time.Now()
+	window.CreationTime = now
+	window.UpdateTime = now
+	return GetOrmer().Insert(window)
+}
+
+// GetFreezeWindow returns the freeze window specified by ID, nil is returned if not found
+func GetFreezeWindow(id int64) (*models.FreezeWindow, error) {
+	window := &models.FreezeWindow{
+		ID: id,
+	}
+	if err := GetOrmer().Read(window); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return window, nil
+}
+
+// ListFreezeWindows returns the freeze windows of the project ordered by the start time
+func ListFreezeWindows(projectID int64) ([]*models.FreezeWindow, error) {
+	windows := []*models.FreezeWindow{}
+	_, err := GetOrmer().QueryTable(&models.FreezeWindow{}).
+		Filter("ProjectID", projectID).
+		OrderBy("StartTime", "ID").
+		All(&windows)
+	return windows, err
+}
+
+// ListFreezeWindowsByProjectName returns the freeze windows of the project specified by name
+func ListFreezeWindowsByProjectName(projectName string) ([]*models.FreezeWindow, error) {
+	windows := []*models.FreezeWindow{}
+	_, err := GetOrmer().Raw(`select f.* from freeze_window f 
+		join project p on f.project_id = p.project_id 
+		where p.name = ? and p.deleted = false`, projectName).QueryRows(&windows)
+	return windows, err
+}
+
+// UpdateFreezeWindow updates the time range, repeat and message of the freeze window
+func UpdateFreezeWindow(window *models.FreezeWindow) error {
+	window.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(window, "StartTime", "EndTime", "Repeat", "Message", "UpdateTime")
+	return err
+}
+
+// DeleteFreezeWindow ...
+func DeleteFreezeWindow(id int64) error {
+	_, err := GetOrmer().Delete(&models.FreezeWindow{
+		ID: id,
+	})
+	return err
+}
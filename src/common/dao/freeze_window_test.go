@@ -0,0 +1,64 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeWindow(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	id, err := AddFreezeWindow(&models.FreezeWindow{
+		ProjectID: 1,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Repeat:    models.FreezeWindowRepeatNone,
+		Message:   "release 1.0",
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+	defer DeleteFreezeWindow(id)
+
+	window, err := GetFreezeWindow(id)
+	require.Nil(t, err)
+	require.NotNil(t, window)
+	assert.Equal(t, "release 1.0", window.Message)
+
+	window.Repeat = models.FreezeWindowRepeatWeekly
+	window.Message = "weekly release"
+	require.Nil(t, UpdateFreezeWindow(window))
+
+	windows, err := ListFreezeWindows(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(windows))
+	assert.Equal(t, models.FreezeWindowRepeatWeekly, windows[0].Repeat)
+	assert.Equal(t, "weekly release", windows[0].Message)
+	assert.True(t, start.Equal(windows[0].StartTime))
+
+	windows, err = ListFreezeWindowsByProjectName("library")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(windows))
+	assert.Equal(t, id, windows[0].ID)
+
+	require.Nil(t, DeleteFreezeWindow(id))
+	window, err = GetFreezeWindow(id)
+	require.Nil(t, err)
+	assert.Nil(t, window)
+}
@@ -1,9 +1,11 @@
 package dao
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/astaxie/beego/orm"
 	"github.com/goharbor/harbor/src/common/models"
-	"time"
 )
 
 // CreateOrUpdateJobLog ...
@@ -28,13 +30,38 @@ func GetJobLog(uuid string) (*models.JobLog, error) {
 	return &jl, nil
 }
 
-// DeleteJobLogsBefore ...
-func DeleteJobLogsBefore(t time.Time) (int64, error) {
+// DeleteJobLogsBefore deletes the logs created before the time, the logs of the
+// excluded jobs are kept
+func DeleteJobLogsBefore(t time.Time, excludedJobNames ...string) (int64, error) {
 	o := GetOrmer()
 	sql := `delete from job_log where creation_time < ?`
-	res, err := o.Raw(sql, t).Exec()
+	params := []interface{}{t}
+	if len(excludedJobNames) > 0 {
+		sql += fmt.Sprintf(` and job_name not in ( %s )`, paramPlaceholder(len(excludedJobNames)))
+		params = append(params, excludedJobNames)
+	}
+	res, err := o.Raw(sql, params...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// DeleteJobLogsOfJobBefore deletes the logs of the job created before the time
+func DeleteJobLogsOfJobBefore(jobName string, t time.Time) (int64, error) {
+	res, err := GetOrmer().Raw(`delete from job_log where job_name = ? and creation_time < ?`,
+		jobName, t).Exec()
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected()
 }
+
+// GetJobLogUsage returns the count and the total bytes of the logs per job
+func GetJobLogUsage() ([]*models.JobLogUsage, error) {
+	usages := []*models.JobLogUsage{}
+	_, err := GetOrmer().Raw(`select job_name, count(*) as count,
+		coalesce(sum(octet_length(content)), 0) as size
+		from job_log group by job_name order by job_name`).QueryRows(&usages)
+	return usages, err
+}
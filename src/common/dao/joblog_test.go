@@ -16,6 +16,7 @@ func TestMethodsOfJobLog(t *testing.T) {
 	content := "content for unit text"
 	jobLog := &models.JobLog{
 		UUID:         uuid,
+		JobName:      "IMAGE_SCAN",
 		CreationTime: now,
 		Content:      content,
 	}
@@ -37,8 +38,22 @@ func TestMethodsOfJobLog(t *testing.T) {
 	assert.Equal(t, updateContent, log.Content)
 	assert.Equal(t, jobLog.LogID, log.LogID)
 
+	// usage
+	usages, err := GetJobLogUsage()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(usages))
+	assert.Equal(t, "IMAGE_SCAN", usages[0].JobName)
+	assert.Equal(t, int64(1), usages[0].Count)
+	assert.Equal(t, int64(len(updateContent)), usages[0].Size)
+
 	// delete
-	count, err := DeleteJobLogsBefore(time.Now().Add(time.Duration(time.Minute)))
+	count, err := DeleteJobLogsBefore(time.Now().Add(time.Duration(time.Minute)), "IMAGE_SCAN")
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = DeleteJobLogsOfJobBefore("IMAGE_REPLICATE", time.Now().Add(time.Duration(time.Minute)))
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = DeleteJobLogsOfJobBefore("IMAGE_SCAN", time.Now().Add(time.Duration(time.Minute)))
 	require.Nil(t, err)
 	assert.Equal(t, int64(1), count)
 }
@@ -0,0 +1,119 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddLegalHold places the legal hold, ErrDupRows is returned if the repository or the tag is held already
+func AddLegalHold(hold *models.LegalHold) (int64, error) {
+	hold.CreationTime = time.Now()
+	id, err := GetOrmer().Insert(hold)
+	if err != nil {
+		if isDupRecErr(err) {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetActiveLegalHold returns the hold of the tag, or the one of the repository if the tag is
+// empty, which isn't released. Nil is returned if not found
+func GetActiveLegalHold(repository, tag string) (*models.LegalHold, error) {
+	holds := []*models.LegalHold{}
+	_, err := GetOrmer().QueryTable(&models.LegalHold{}).
+		Filter("Repository", repository).
+		Filter("Tag", tag).
+		Filter("ReleaseTime__isnull", true).
+		All(&holds)
+	if err != nil {
+		return nil, err
+	}
+	if len(holds) == 0 {
+		return nil, nil
+	}
+	return holds[0], nil
+}
+
+// ReleaseLegalHold releases the hold, the record is kept as the history
+func ReleaseLegalHold(id int64, releaser, reason string) error {
+	_, err := GetOrmer().Raw(`update legal_hold set releaser = ?, release_reason = ?, release_time = ?
+		where id = ? and release_time is null`, releaser, reason, time.Now(), id).Exec()
+	return err
+}
+
+// ListLegalHolds lists the legal holds according to the query conditions, the latest one is the first
+func ListLegalHolds(query *models.LegalHoldQuery) ([]*models.LegalHold, error) {
+	qs := getLegalHoldQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil && query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	holds := []*models.LegalHold{}
+	_, err := qs.All(&holds)
+	return holds, err
+}
+
+// CountLegalHolds ...
+func CountLegalHolds(query *models.LegalHoldQuery) (int64, error) {
+	return getLegalHoldQuerySetter(query).Count()
+}
+
+func getLegalHoldQuerySetter(query *models.LegalHoldQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.LegalHold{})
+	if query == nil {
+		return qs
+	}
+	if len(query.Project) > 0 {
+		qs = qs.Filter("Repository__startswith", strings.TrimSuffix(query.Project, "/")+"/")
+	}
+	if len(query.Repository) > 0 {
+		qs = qs.Filter("Repository", query.Repository)
+	}
+	if query.Active {
+		qs = qs.Filter("ReleaseTime__isnull", true)
+	}
+	return qs
+}
+
+// SetComplianceOfficer grants the user the permission of managing the legal holds, or revokes it
+func SetComplianceOfficer(userID int, officer bool) error {
+	if !officer {
+		_, err := GetOrmer().Delete(&models.ComplianceOfficer{UserID: userID})
+		return err
+	}
+	_, err := GetOrmer().Raw(`insert into compliance_officer (user_id, creation_time) values (?, ?)
+		on conflict (user_id) do nothing`, userID, time.Now()).Exec()
+	return err
+}
+
+// IsComplianceOfficer returns whether the user can manage the legal holds
+func IsComplianceOfficer(userID int) (bool, error) {
+	count, err := GetOrmer().QueryTable(&models.ComplianceOfficer{}).
+		Filter("UserID", userID).
+		Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
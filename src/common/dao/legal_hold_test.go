@@ -0,0 +1,101 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegalHold(t *testing.T) {
+	repository := "library/legal-hold-test"
+	defer ClearTable(models.LegalHoldTable)
+
+	id, err := AddLegalHold(&models.LegalHold{
+		Repository: repository,
+		Reason:     "litigation",
+		Creator:    "officer",
+	})
+	require.Nil(t, err)
+	_, err = AddLegalHold(&models.LegalHold{
+		Repository: repository,
+		Tag:        "1.0",
+		Reason:     "audit",
+		Creator:    "officer",
+	})
+	require.Nil(t, err)
+	// only one active hold of the repository
+	_, err = AddLegalHold(&models.LegalHold{
+		Repository: repository,
+		Reason:     "again",
+		Creator:    "officer",
+	})
+	assert.Equal(t, ErrDupRows, err)
+
+	hold, err := GetActiveLegalHold(repository, "")
+	require.Nil(t, err)
+	require.NotNil(t, hold)
+	assert.Equal(t, id, hold.ID)
+	assert.Nil(t, hold.ReleaseTime)
+
+	total, err := CountLegalHolds(&models.LegalHoldQuery{Project: "library", Active: true})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+
+	// the released hold is kept as the history and the repository can be held again
+	require.Nil(t, ReleaseLegalHold(id, "officer", "settled"))
+	hold, err = GetActiveLegalHold(repository, "")
+	require.Nil(t, err)
+	assert.Nil(t, hold)
+	_, err = AddLegalHold(&models.LegalHold{
+		Repository: repository,
+		Reason:     "litigation again",
+		Creator:    "officer",
+	})
+	require.Nil(t, err)
+
+	holds, err := ListLegalHolds(&models.LegalHoldQuery{Repository: repository})
+	require.Nil(t, err)
+	require.Equal(t, 3, len(holds))
+	for _, h := range holds {
+		if h.ID == id {
+			assert.Equal(t, "settled", h.ReleaseReason)
+			assert.NotNil(t, h.ReleaseTime)
+		}
+	}
+	total, err = CountLegalHolds(&models.LegalHoldQuery{Repository: repository, Active: true})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestComplianceOfficer(t *testing.T) {
+	officer, err := IsComplianceOfficer(1)
+	require.Nil(t, err)
+	assert.False(t, officer)
+
+	require.Nil(t, SetComplianceOfficer(1, true))
+	require.Nil(t, SetComplianceOfficer(1, true))
+	officer, err = IsComplianceOfficer(1)
+	require.Nil(t, err)
+	assert.True(t, officer)
+
+	require.Nil(t, SetComplianceOfficer(1, false))
+	officer, err = IsComplianceOfficer(1)
+	require.Nil(t, err)
+	assert.False(t, officer)
+}
@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// ListMetadataSyncStates lists the states of the metadata sync of all projects
+func ListMetadataSyncStates() ([]*models.MetadataSyncState, error) {
+	states := []*models.MetadataSyncState{}
+	_, err := GetOrmer().QueryTable(&models.MetadataSyncState{}).
+		OrderBy("ProjectName").
+		All(&states)
+	return states, err
+}
+
+// SetMetadataSyncState sets the digest and message of the project, the state is created
+// if it doesn't exist
+func SetMetadataSyncState(projectName, digest, message string) error {
+	sql := `insert into metadata_sync_state (project_name, digest, message, update_time) 
+		values (?, ?, ?, ?) 
+		on conflict (project_name) do update set 
+		digest = excluded.digest, message = excluded.message, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, projectName, digest, message, time.Now()).Exec()
+	return err
+}
+
+// DeleteMetadataSyncState ...
+func DeleteMetadataSyncState(projectName string) error {
+	_, err := GetOrmer().QueryTable(&models.MetadataSyncState{}).
+		Filter("ProjectName", projectName).
+		Delete()
+	return err
+}
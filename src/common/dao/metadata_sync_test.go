@@ -0,0 +1,44 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataSyncState(t *testing.T) {
+	defer ClearTable(models.MetadataSyncStateTable)
+
+	require.Nil(t, SetMetadataSyncState("project-b", "sha256:1", ""))
+	require.Nil(t, SetMetadataSyncState("project-a", "sha256:2", ""))
+	require.Nil(t, SetMetadataSyncState("project-b", "sha256:3", "conflict"))
+
+	states, err := ListMetadataSyncStates()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(states))
+	assert.Equal(t, "project-a", states[0].ProjectName)
+	assert.Equal(t, "project-b", states[1].ProjectName)
+	assert.Equal(t, "sha256:3", states[1].Digest)
+	assert.Equal(t, "conflict", states[1].Message)
+
+	require.Nil(t, DeleteMetadataSyncState("project-b"))
+	states, err = ListMetadataSyncStates()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(states))
+}
@@ -15,17 +15,25 @@
 package dao
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/astaxie/beego/orm"
 	"github.com/golang-migrate/migrate"
 	_ "github.com/golang-migrate/migrate/database/postgres" // import pgsql driver for migrator
 	_ "github.com/golang-migrate/migrate/source/file"       // import local file driver for migrator
 
+	"github.com/goharbor/harbor/src/common/dao/credential"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
-	_ "github.com/lib/pq" // register pgsql driver
+	"github.com/lib/pq" // register pgsql driver
 )
 
 const defaultMigrationPath = "migrations/postgresql/"
@@ -37,6 +45,9 @@ type pgsql struct {
 	pwd      string
 	database string
 	sslmode  string
+	// the CA certificate to verify the server
+	sslRootCert string
+	credentials credential.Provider
 }
 
 // Name returns the name of PostgreSQL
@@ -46,8 +57,8 @@ func (p *pgsql) Name() string {
 
 // String ...
 func (p *pgsql) String() string {
-	return fmt.Sprintf("type-%s host-%s port-%s databse-%s sslmode-%q",
-		p.Name(), p.host, p.port, p.database, p.sslmode)
+	return fmt.Sprintf("type-%s host-%s port-%s databse-%s sslmode-%q static-credentials-%t",
+		p.Name(), p.host, p.port, p.database, p.sslmode, p.credentials.Static())
 }
 
 // NewPGSQL returns an instance of postgres
@@ -56,38 +67,116 @@ func NewPGSQL(host string, port string, usr string, pwd string, database string,
 		sslmode = "disable"
 	}
 	return &pgsql{
-		host:     host,
-		port:     port,
-		usr:      usr,
-		pwd:      pwd,
-		database: database,
-		sslmode:  sslmode,
+		host:        host,
+		port:        port,
+		usr:         usr,
+		pwd:         pwd,
+		database:    database,
+		sslmode:     sslmode,
+		credentials: credential.Static(pwd),
 	}
 }
 
+// newPGSQLWithOptions returns an instance of postgres supporting TLS and the token based authentication
+func newPGSQLWithOptions(db *models.PostGreSQL) (*pgsql, error) {
+	credentials, err := credential.NewProvider(db)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPGSQL(db.Host, strconv.Itoa(db.Port), db.Username, db.Password, db.Database, db.SSLMode).(*pgsql)
+	p.sslRootCert = db.SSLRootCert
+	p.credentials = credentials
+	return p, nil
+}
+
 // Register registers pgSQL to orm with the info wrapped by the instance.
 func (p *pgsql) Register(alias ...string) error {
 	if err := utils.TestTCPConn(fmt.Sprintf("%s:%s", p.host, p.port), 60, 2); err != nil {
 		return err
 	}
 
-	if err := orm.RegisterDriver("postgres", orm.DRPostgres); err != nil {
-		return err
-	}
-
 	an := "default"
 	if len(alias) != 0 {
 		an = alias[0]
 	}
-	info := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		p.host, p.port, p.usr, p.pwd, p.database, p.sslmode)
+	info := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s",
+		p.host, p.port, p.usr, p.database, p.sslmode)
+	if len(p.sslRootCert) > 0 {
+		info = fmt.Sprintf("%s sslrootcert=%s", info, p.sslRootCert)
+	}
+
+	driverName := "postgres"
+	if p.credentials.Static() {
+		info = fmt.Sprintf("%s password=%s", info, p.pwd)
+	} else {
+		// the token is got when the connection is opened, as it expires in a short time
+		driverName = "postgres-token-" + an
+		registerTokenDriver(driverName, p.credentials)
+	}
+
+	if err := orm.RegisterDriver(driverName, orm.DRPostgres); err != nil {
+		return err
+	}
+	return orm.RegisterDataBase(an, driverName, info)
+}
+
+var (
+	tokenDrivers     = map[string]*tokenDriver{}
+	tokenDriversLock sync.Mutex
+)
+
+// registerTokenDriver registers the driver into database/sql, the credentials are
+// replaced if the driver has been registered
+func registerTokenDriver(name string, credentials credential.Provider) {
+	tokenDriversLock.Lock()
+	defer tokenDriversLock.Unlock()
+	if d, ok := tokenDrivers[name]; ok {
+		d.setCredentials(credentials)
+		return
+	}
+	d := &tokenDriver{credentials: credentials}
+	sql.Register(name, d)
+	tokenDrivers[name] = d
+}
+
+// tokenDriver opens the connections to PostgreSQL with the latest token as the password
+type tokenDriver struct {
+	lock        sync.RWMutex
+	credentials credential.Provider
+}
+
+func (t *tokenDriver) setCredentials(credentials credential.Provider) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.credentials = credentials
+}
+
+// Open implements the interface driver.Driver
+func (t *tokenDriver) Open(name string) (driver.Conn, error) {
+	t.lock.RLock()
+	credentials := t.credentials
+	t.lock.RUnlock()
+	password, err := credentials.Password()
+	if err != nil {
+		return nil, err
+	}
+	return pq.Open(fmt.Sprintf("%s password=%s", name, quoteDSNValue(password)))
+}
 
-	return orm.RegisterDataBase(an, "postgres", info)
+// quoteDSNValue quotes the value in the connection string, as the token may contain
+// the special characters
+func quoteDSNValue(v string) string {
+	v = strings.Replace(v, `\`, `\\`, -1)
+	v = strings.Replace(v, `'`, `\'`, -1)
+	return "'" + v + "'"
 }
 
 // UpgradeSchema calls migrate tool to upgrade schema to the latest based on the SQL scripts.
 func (p *pgsql) UpgradeSchema() error {
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", p.usr, p.pwd, p.host, p.port, p.database, p.sslmode)
+	dbURL, err := p.url()
+	if err != nil {
+		return err
+	}
 	// For UT
 	path := os.Getenv("POSTGRES_MIGRATION_SCRIPTS_PATH")
 	if len(path) == 0 {
@@ -114,3 +203,24 @@ func (p *pgsql) UpgradeSchema() error {
 	}
 	return nil
 }
+
+// url returns the URL of database used by the migrator
+func (p *pgsql) url() (string, error) {
+	password, err := p.credentials.Password()
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("sslmode", p.sslmode)
+	if len(p.sslRootCert) > 0 {
+		query.Set("sslrootcert", p.sslRootCert)
+	}
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(p.usr, password),
+		Host:     fmt.Sprintf("%s:%s", p.host, p.port),
+		Path:     "/" + p.database,
+		RawQuery: query.Encode(),
+	}
+	return u.String(), nil
+}
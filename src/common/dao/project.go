@@ -20,6 +20,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/log"
 
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -166,7 +167,8 @@ func GetGroupProjects(groupDNCondition string, query *models.ProjectQueryParam)
 		     from project p 
 		     left join project_member pm on p.project_id = pm.project_id
 		     left join user_group ug on ug.id = pm.entity_id and pm.entity_type = 'g' and ug.group_type = 1
-			 where ug.ldap_group_dn in ( %s ) order by name`,
+			 where ug.ldap_group_dn in ( %s ) 
+			 and (pm.expiration_time is null or pm.expiration_time > now()) order by name`,
 			sql, groupDNCondition)
 	}
 	sqlStr, queryParams := CreatePagination(query, sql, params)
@@ -190,7 +192,8 @@ func GetTotalGroupProjects(groupDNCondition string, query *models.ProjectQueryPa
 			   from project p 
 			   left join project_member pm on p.project_id = pm.project_id
 			   left join user_group ug on ug.id = pm.entity_id and pm.entity_type = 'g' and ug.group_type = 1
-			   where ug.ldap_group_dn in ( %s )) t`,
+			   where ug.ldap_group_dn in ( %s ) 
+			   and (pm.expiration_time is null or pm.expiration_time > now())) t`,
 			sqlCondition, groupDNCondition)
 	}
 	log.Debugf("query sql:%v", sql)
@@ -220,6 +223,7 @@ func projectQueryConditions(query *models.ProjectQueryParam) (string, []interfac
 	if query.Member != nil && len(query.Member.Name) != 0 {
 		sql += ` join project_member pm
 					on p.project_id = pm.project_id and pm.entity_type = 'u'
+					and (pm.expiration_time is null or pm.expiration_time > now())
 					join harbor_user u2
 					on pm.entity_id=u2.user_id`
 	}
@@ -231,8 +235,13 @@ func projectQueryConditions(query *models.ProjectQueryParam) (string, []interfac
 	}
 
 	if len(query.Name) != 0 {
-		sql += ` and p.name like ?`
-		params = append(params, "%"+Escape(query.Name)+"%")
+		if query.ExactMatchName {
+			sql += ` and p.name=?`
+			params = append(params, query.Name)
+		} else {
+			sql += ` and p.name like ?`
+			params = append(params, "%"+Escape(query.Name)+"%")
+		}
 	}
 
 	if query.Member != nil && len(query.Member.Name) != 0 {
@@ -260,6 +269,22 @@ func projectQueryConditions(query *models.ProjectQueryParam) (string, []interfac
 			paramPlaceholder(len(query.ProjectIDs)))
 		params = append(params, query.ProjectIDs)
 	}
+
+	names := []string{}
+	for name := range query.CustomMetadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sql += ` and exists (select 1 from project_custom_metadata cm
+					where cm.project_id = p.project_id and cm.name = ?`
+		params = append(params, name)
+		if value := query.CustomMetadata[name]; len(value) > 0 {
+			sql += ` and cm.value = ?`
+			params = append(params, value)
+		}
+		sql += `)`
+	}
 	return sql, params
 }
 
@@ -304,7 +329,8 @@ func GetRolesByLDAPGroup(projectID int64, groupDNCondition string) ([]int, error
 	sql := fmt.Sprintf(
 		`select min(pm.role) from project_member pm 
 		left join user_group ug on pm.entity_type = 'g' and pm.entity_id = ug.id 
-		where ug.ldap_group_dn in ( %s ) and pm.project_id = ? `,
+		where ug.ldap_group_dn in ( %s ) and pm.project_id = ? 
+		and (pm.expiration_time is null or pm.expiration_time > now()) `,
 		groupDNCondition)
 	log.Debugf("sql:%v", sql)
 	if _, err := o.Raw(sql, projectID).QueryRows(&roles); err != nil {
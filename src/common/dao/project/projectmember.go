@@ -16,6 +16,7 @@ package project
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
@@ -31,11 +32,11 @@ func GetProjectMember(queryMember models.Member) ([]*models.Member, error) {
 
 	o := dao.GetOrmer()
 	sql := ` select a.* from (select pm.id as id, pm.project_id as project_id, ug.id as entity_id, ug.group_name as entity_name, ug.creation_time, ug.update_time, r.name as rolename, 
-		r.role_id as role, pm.entity_type as entity_type from user_group ug join project_member pm 
+		r.role_id as role, pm.entity_type as entity_type, pm.expiration_time as expiration_time from user_group ug join project_member pm 
 		on pm.project_id = ? and ug.id = pm.entity_id join role r on pm.role = r.role_id where  pm.entity_type = 'g'
 		union
 		select pm.id as id, pm.project_id as project_id, u.user_id as entity_id, u.username as entity_name, u.creation_time, u.update_time, r.name as rolename, 
-		r.role_id as role, pm.entity_type as entity_type from harbor_user u join project_member pm 
+		r.role_id as role, pm.entity_type as entity_type, pm.expiration_time as expiration_time from harbor_user u join project_member pm 
 		on pm.project_id = ? and u.user_id = pm.entity_id 
 		join role r on pm.role = r.role_id where u.deleted = false and pm.entity_type = 'u') as a where a.project_id = ? `
 
@@ -70,6 +71,40 @@ func GetProjectMember(queryMember models.Member) ([]*models.Member, error) {
 	return members, err
 }
 
+// GetUserMemberships returns the unexpired memberships of the user in the projects which
+// aren't deleted, the memberships through the groups aren't included
+func GetUserMemberships(userID int) ([]*models.Member, error) {
+	sql := `select pm.id, pm.project_id, pm.entity_id, u.username as entity_name, r.name as rolename, 
+		r.role_id as role, pm.entity_type, pm.expiration_time 
+		from project_member pm 
+		join harbor_user u on pm.entity_id = u.user_id 
+		join project p on pm.project_id = p.project_id 
+		join role r on pm.role = r.role_id 
+		where pm.entity_type = 'u' and pm.entity_id = ? and p.deleted = false 
+		and (pm.expiration_time is null or pm.expiration_time > ?) 
+		order by pm.project_id`
+	members := []*models.Member{}
+	_, err := dao.GetOrmer().Raw(sql, userID, time.Now()).QueryRows(&members)
+	return members, err
+}
+
+// GetGroupMembershipsByType returns the unexpired memberships of the user groups of the type
+// in the projects which aren't deleted
+func GetGroupMembershipsByType(groupType int) ([]*models.Member, error) {
+	sql := `select pm.id, pm.project_id, pm.entity_id, ug.group_name as entity_name, r.name as rolename, 
+		r.role_id as role, pm.entity_type, pm.expiration_time 
+		from project_member pm 
+		join user_group ug on pm.entity_id = ug.id 
+		join project p on pm.project_id = p.project_id 
+		join role r on pm.role = r.role_id 
+		where pm.entity_type = 'g' and ug.group_type = ? and p.deleted = false 
+		and (pm.expiration_time is null or pm.expiration_time > ?) 
+		order by pm.project_id, ug.group_name`
+	members := []*models.Member{}
+	_, err := dao.GetOrmer().Raw(sql, groupType, time.Now()).QueryRows(&members)
+	return members, err
+}
+
 // AddProjectMember inserts a record to table project_member
 func AddProjectMember(member models.Member) (int, error) {
 
@@ -91,8 +126,8 @@ func AddProjectMember(member models.Member) (int, error) {
 	}
 
 	var pmid int
-	sql := "insert into project_member (project_id, entity_id , role, entity_type) values (?, ?, ?, ?) RETURNING id"
-	err = o.Raw(sql, member.ProjectID, member.EntityID, member.Role, member.EntityType).QueryRow(&pmid)
+	sql := "insert into project_member (project_id, entity_id , role, entity_type, expiration_time) values (?, ?, ?, ?, ?) RETURNING id"
+	err = o.Raw(sql, member.ProjectID, member.EntityID, member.Role, member.EntityType, member.ExpirationTime).QueryRow(&pmid)
 	if err != nil {
 		return 0, err
 	}
@@ -107,6 +142,26 @@ func UpdateProjectMemberRole(pmID int, role int) error {
 	return err
 }
 
+// UpdateProjectMemberExpiration updates the expiration time of the project member, nil means never expire
+func UpdateProjectMemberExpiration(pmID int, expiration *time.Time) error {
+	o := dao.GetOrmer()
+	sql := "update project_member set expiration_time = ? where id = ? "
+	_, err := o.Raw(sql, expiration, pmID).Exec()
+	return err
+}
+
+// DeleteExpiredProjectMembers deletes the members whose expiration time is earlier than now,
+// it returns the count of the deleted members
+func DeleteExpiredProjectMembers() (int64, error) {
+	o := dao.GetOrmer()
+	sql := "delete from project_member where expiration_time is not null and expiration_time <= ? "
+	result, err := o.Raw(sql, time.Now()).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 // DeleteProjectMemberByID - Delete Project Member by ID
 func DeleteProjectMemberByID(pmid int) error {
 	o := dao.GetOrmer()
@@ -123,7 +178,7 @@ func SearchMemberByName(projectID int64, entityName string) ([]*models.Member, e
 	sql := `select pm.id, pm.project_id, 
 	               u.username as entity_name, 
 	               r.name as rolename,
-			       pm.role, pm.entity_id, pm.entity_type 
+			       pm.role, pm.entity_id, pm.entity_type, pm.expiration_time 
 			  from project_member pm
          left join harbor_user u on pm.entity_id = u.user_id and pm.entity_type = 'u'
 		 left join role r on pm.role = r.role_id
@@ -132,7 +187,7 @@ func SearchMemberByName(projectID int64, entityName string) ([]*models.Member, e
 		   select pm.id, pm.project_id, 
 			       ug.group_name as entity_name, 
 				   r.name as rolename,
-				   pm.role, pm.entity_id, pm.entity_type 
+				   pm.role, pm.entity_id, pm.entity_type, pm.expiration_time 
 		      from project_member pm
 	     left join user_group ug on pm.entity_id = ug.id and pm.entity_type = 'g'
 	     left join role r on pm.role = r.role_id
@@ -155,7 +210,8 @@ func GetRolesByGroup(projectID int64, groupDNCondition string) []int {
 	o := dao.GetOrmer()
 	sql := `select role from project_member pm 
 	left join user_group ug on pm.project_id = ?
-	where ug.group_type = 1 and ug.ldap_group_dn in (` + groupDNCondition + `)`
+	where ug.group_type = 1 and ug.ldap_group_dn in (` + groupDNCondition + `)
+	and (pm.expiration_time is null or pm.expiration_time > now())`
 	if _, err := o.Raw(sql, projectID).QueryRows(&roles); err != nil {
 		return roles
 	}
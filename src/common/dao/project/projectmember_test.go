@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
@@ -332,3 +333,119 @@ func TestGetRolesByGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectMemberExpiration(t *testing.T) {
+	currentProject, err := dao.GetProjectByName("member_test_01")
+	if err != nil || currentProject == nil {
+		t.Fatalf("Error occurred when GetProjectByName: %v", err)
+	}
+	user := models.User{
+		Username: "pm_expiration",
+		Email:    "pm_expiration@example.com",
+		Realname: "pm_expiration",
+		Password: "1234567d",
+	}
+	userID, err := dao.GetOrmer().Insert(&user)
+	if err != nil {
+		t.Fatalf("Error occurred when add user: %v", err)
+	}
+	defer dao.GetOrmer().Raw("delete from harbor_user where user_id = ?", userID).Exec()
+
+	expiration := time.Now().Add(time.Hour)
+	pmid, err := AddProjectMember(models.Member{
+		ProjectID:      currentProject.ProjectID,
+		EntityID:       int(userID),
+		EntityType:     common.UserMember,
+		Role:           models.DEVELOPER,
+		ExpirationTime: &expiration,
+	})
+	if err != nil {
+		t.Fatalf("Error occurred in AddProjectMember: %v", err)
+	}
+
+	roles, err := dao.GetUserProjectRoles(int(userID), currentProject.ProjectID, common.UserMember)
+	if err != nil {
+		t.Fatalf("Error occurred in GetUserProjectRoles: %v", err)
+	}
+	if len(roles) != 1 {
+		t.Errorf("expected 1 role for the unexpired member, got %d", len(roles))
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if err = UpdateProjectMemberExpiration(pmid, &past); err != nil {
+		t.Fatalf("Error occurred in UpdateProjectMemberExpiration: %v", err)
+	}
+	roles, err = dao.GetUserProjectRoles(int(userID), currentProject.ProjectID, common.UserMember)
+	if err != nil {
+		t.Fatalf("Error occurred in GetUserProjectRoles: %v", err)
+	}
+	if len(roles) != 0 {
+		t.Errorf("expected no role for the expired member, got %d", len(roles))
+	}
+
+	count, err := DeleteExpiredProjectMembers()
+	if err != nil {
+		t.Fatalf("Error occurred in DeleteExpiredProjectMembers: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 expired member deleted, got %d", count)
+	}
+	members, err := GetProjectMember(models.Member{ProjectID: currentProject.ProjectID, ID: pmid})
+	if err != nil {
+		t.Fatalf("Error occurred in GetProjectMember: %v", err)
+	}
+	if len(members) != 0 {
+		t.Errorf("the expired member should be deleted")
+	}
+}
+
+func TestGetUserMemberships(t *testing.T) {
+	currentProject, err := dao.GetProjectByName("member_test_01")
+	if err != nil || currentProject == nil {
+		t.Fatalf("Error occurred when GetProjectByName: %v", err)
+	}
+	user, err := dao.GetUser(models.User{Username: "member_test_01"})
+	if err != nil || user == nil {
+		t.Fatalf("Error occurred when GetUser: %v", err)
+	}
+
+	members, err := GetUserMemberships(user.UserID)
+	if err != nil {
+		t.Fatalf("Error occurred in GetUserMemberships: %v", err)
+	}
+	if len(members) != 1 {
+		t.Fatalf("expected 1 membership, got %d", len(members))
+	}
+	if members[0].ProjectID != currentProject.ProjectID || members[0].Role != models.PROJECTADMIN {
+		t.Errorf("unexpected membership: %+v", members[0])
+	}
+}
+
+func TestGetGroupMembershipsByType(t *testing.T) {
+	currentProject, err := dao.GetProjectByName("member_test_01")
+	if err != nil || currentProject == nil {
+		t.Fatalf("Error occurred when GetProjectByName: %v", err)
+	}
+
+	members, err := GetGroupMembershipsByType(common.LdapGroupType)
+	if err != nil {
+		t.Fatalf("Error occurred in GetGroupMembershipsByType: %v", err)
+	}
+	found := false
+	for _, m := range members {
+		if m.ProjectID == currentProject.ProjectID && m.Entityname == "test_group_01" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("the membership of test_group_01 not found in %+v", members)
+	}
+
+	members, err = GetGroupMembershipsByType(100)
+	if err != nil {
+		t.Fatalf("Error occurred in GetGroupMembershipsByType: %v", err)
+	}
+	if len(members) != 0 {
+		t.Errorf("expected no membership, got %d", len(members))
+	}
+}
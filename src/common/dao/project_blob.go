@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddProjectBlobs adds the blobs into the project, the ones already in the project are ignored
+func AddProjectBlobs(projectID int64, blobs []*models.ProjectBlob) error {
+	if len(blobs) == 0 {
+		return nil
+	}
+	sql := `insert into project_blob (project_id, digest, size) values (?, ?, ?)
+		on conflict (project_id, digest) do nothing`
+	o := GetOrmer()
+	for _, blob := range blobs {
+		if _, err := o.Raw(sql, projectID, blob.Digest, blob.Size).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListProjectBlobs returns the blobs of the project whose digests are in the list
+func ListProjectBlobs(projectID int64, digests ...string) ([]*models.ProjectBlob, error) {
+	blobs := []*models.ProjectBlob{}
+	if len(digests) == 0 {
+		return blobs, nil
+	}
+	_, err := GetOrmer().QueryTable(&models.ProjectBlob{}).
+		Filter("ProjectID", projectID).
+		Filter("Digest__in", digests).
+		All(&blobs)
+	return blobs, err
+}
+
+// GetProjectUsage returns the total size of the blobs in the project
+func GetProjectUsage(projectID int64) (int64, error) {
+	var usage int64
+	err := GetOrmer().Raw(`select coalesce(sum(size), 0) from project_blob where project_id = ?`,
+		projectID).QueryRow(&usage)
+	return usage, err
+}
+
+// DeleteProjectBlobs deletes all the blobs of the project
+func DeleteProjectBlobs(projectID int64) error {
+	_, err := GetOrmer().QueryTable(&models.ProjectBlob{}).Filter("ProjectID", projectID).Delete()
+	return err
+}
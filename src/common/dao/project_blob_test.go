@@ -0,0 +1,59 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectBlob(t *testing.T) {
+	var projectID int64 = 10001
+	defer DeleteProjectBlobs(projectID)
+
+	usage, err := GetProjectUsage(projectID)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), usage)
+
+	require.Nil(t, AddProjectBlobs(projectID, []*models.ProjectBlob{
+		{Digest: "sha256:project-blob-1", Size: 100},
+		{Digest: "sha256:project-blob-2", Size: 200},
+	}))
+	// the existing blob is counted once
+	require.Nil(t, AddProjectBlobs(projectID, []*models.ProjectBlob{
+		{Digest: "sha256:project-blob-2", Size: 200},
+		{Digest: "sha256:project-blob-3", Size: 300},
+	}))
+	usage, err = GetProjectUsage(projectID)
+	require.Nil(t, err)
+	assert.Equal(t, int64(600), usage)
+
+	blobs, err := ListProjectBlobs(projectID, "sha256:project-blob-1", "sha256:project-blob-4")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(blobs))
+	assert.Equal(t, int64(100), blobs[0].Size)
+
+	blobs, err = ListProjectBlobs(projectID)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(blobs))
+
+	require.Nil(t, DeleteProjectBlobs(projectID))
+	usage, err = GetProjectUsage(projectID)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), usage)
+}
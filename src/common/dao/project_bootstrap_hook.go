@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddProjectBootstrapHook adds the bootstrap hook, ErrDupRows is returned if the name is used
+func AddProjectBootstrapHook(hook *models.ProjectBootstrapHook) (int64, error) {
+	now := time.Now()
+	hook.CreationTime = now
+	hook.UpdateTime = now
+	id, err := GetOrmer().Insert(hook)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetProjectBootstrapHook returns the bootstrap hook specified by ID, nil is returned if not found
+func GetProjectBootstrapHook(id int64) (*models.ProjectBootstrapHook, error) {
+	hook := &models.ProjectBootstrapHook{
+		ID: id,
+	}
+	if err := GetOrmer().Read(hook); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hook, nil
+}
+
+// ListProjectBootstrapHooks returns the bootstrap hooks ordered by ID, which is the order
+// they are applied in, only the enabled ones are returned if enabledOnly is true
+func ListProjectBootstrapHooks(enabledOnly bool) ([]*models.ProjectBootstrapHook, error) {
+	qs := GetOrmer().QueryTable(&models.ProjectBootstrapHook{})
+	if enabledOnly {
+		qs = qs.Filter("Enabled", true)
+	}
+	hooks := []*models.ProjectBootstrapHook{}
+	_, err := qs.OrderBy("ID").All(&hooks)
+	return hooks, err
+}
+
+// UpdateProjectBootstrapHook updates the bootstrap hook, ErrDupRows is returned if the name is used
+func UpdateProjectBootstrapHook(hook *models.ProjectBootstrapHook) error {
+	hook.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(hook, "Name", "Description", "Template", "Enabled", "UpdateTime")
+	if err != nil && strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+		return ErrDupRows
+	}
+	return err
+}
+
+// DeleteProjectBootstrapHook ...
+func DeleteProjectBootstrapHook(id int64) error {
+	_, err := GetOrmer().Delete(&models.ProjectBootstrapHook{
+		ID: id,
+	})
+	return err
+}
@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectBootstrapHook(t *testing.T) {
+	id, err := AddProjectBootstrapHook(&models.ProjectBootstrapHook{
+		Name:     "default-robot",
+		Template: "apiVersion: v1\nkind: ProjectConfiguration\nproject: {{.Project}}\n",
+		Enabled:  true,
+	})
+	require.Nil(t, err)
+	defer DeleteProjectBootstrapHook(id)
+
+	_, err = AddProjectBootstrapHook(&models.ProjectBootstrapHook{
+		Name:     "default-robot",
+		Template: "apiVersion: v1\nkind: ProjectConfiguration\nproject: {{.Project}}\n",
+	})
+	assert.Equal(t, ErrDupRows, err)
+
+	hook, err := GetProjectBootstrapHook(id)
+	require.Nil(t, err)
+	require.NotNil(t, hook)
+	assert.Equal(t, "default-robot", hook.Name)
+	assert.True(t, hook.Enabled)
+
+	hooks, err := ListProjectBootstrapHooks(true)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(hooks))
+
+	hook.Enabled = false
+	hook.Description = "creates the robot of CI"
+	require.Nil(t, UpdateProjectBootstrapHook(hook))
+	hooks, err = ListProjectBootstrapHooks(true)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(hooks))
+	hooks, err = ListProjectBootstrapHooks(false)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(hooks))
+	assert.Equal(t, "creates the robot of CI", hooks[0].Description)
+
+	require.Nil(t, DeleteProjectBootstrapHook(id))
+	hook, err = GetProjectBootstrapHook(id)
+	require.Nil(t, err)
+	assert.Nil(t, hook)
+}
@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetProjectCustomMetadata creates the custom metadata of the project or updates its value
+func SetProjectCustomMetadata(projectID int64, name, value string) error {
+	now := time.Now()
+	sql := `insert into project_custom_metadata (project_id, name, value, creation_time, update_time)
+		values (?, ?, ?, ?, ?)
+		on conflict (project_id, name) do update set value = excluded.value, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, projectID, name, value, now, now).Exec()
+	return err
+}
+
+// GetProjectCustomMetadata returns the custom metadata of the project, nil is returned if it doesn't exist
+func GetProjectCustomMetadata(projectID int64, name string) (*models.ProjectCustomMetadata, error) {
+	metadata := &models.ProjectCustomMetadata{
+		ProjectID: projectID,
+		Name:      name,
+	}
+	if err := GetOrmer().Read(metadata, "ProjectID", "Name"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// ListProjectCustomMetadata returns the custom metadata of the projects ordered by the names
+func ListProjectCustomMetadata(projectIDs ...int64) ([]*models.ProjectCustomMetadata, error) {
+	metadata := []*models.ProjectCustomMetadata{}
+	if len(projectIDs) == 0 {
+		return metadata, nil
+	}
+	_, err := GetOrmer().QueryTable(&models.ProjectCustomMetadata{}).
+		Filter("ProjectID__in", projectIDs).
+		OrderBy("ProjectID", "Name").
+		All(&metadata)
+	return metadata, err
+}
+
+// DeleteProjectCustomMetadata deletes the custom metadata of the project
+func DeleteProjectCustomMetadata(projectID int64, name string) error {
+	_, err := GetOrmer().QueryTable(&models.ProjectCustomMetadata{}).
+		Filter("ProjectID", projectID).
+		Filter("Name", name).
+		Delete()
+	return err
+}
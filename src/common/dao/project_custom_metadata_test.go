@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectCustomMetadata(t *testing.T) {
+	metadata, err := GetProjectCustomMetadata(1, "team-owner")
+	require.Nil(t, err)
+	assert.Nil(t, metadata)
+
+	require.Nil(t, SetProjectCustomMetadata(1, "team-owner", "infra"))
+	defer DeleteProjectCustomMetadata(1, "team-owner")
+	require.Nil(t, SetProjectCustomMetadata(1, "cost-center", "cc-1"))
+	defer DeleteProjectCustomMetadata(1, "cost-center")
+
+	// update the value
+	require.Nil(t, SetProjectCustomMetadata(1, "team-owner", "platform"))
+	metadata, err = GetProjectCustomMetadata(1, "team-owner")
+	require.Nil(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "platform", metadata.Value)
+
+	list, err := ListProjectCustomMetadata(1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(list))
+	assert.Equal(t, "cost-center", list[0].Name)
+	assert.Equal(t, "team-owner", list[1].Name)
+
+	// filter the projects by the custom metadata
+	projects, err := GetProjects(&models.ProjectQueryParam{
+		CustomMetadata: map[string]string{"team-owner": "platform"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(projects))
+	assert.Equal(t, int64(1), projects[0].ProjectID)
+	total, err := GetTotalOfProjects(&models.ProjectQueryParam{
+		CustomMetadata: map[string]string{"team-owner": "", "cost-center": "cc-1"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	projects, err = GetProjects(&models.ProjectQueryParam{
+		CustomMetadata: map[string]string{"team-owner": "infra"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(projects))
+
+	require.Nil(t, DeleteProjectCustomMetadata(1, "team-owner"))
+	metadata, err = GetProjectCustomMetadata(1, "team-owner")
+	require.Nil(t, err)
+	assert.Nil(t, metadata)
+}
@@ -0,0 +1,85 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddProjectInvitation ...
+func AddProjectInvitation(invitation *models.ProjectInvitation) (int64, error) {
+	now := time.Now()
+	invitation.CreationTime = now
+	invitation.UpdateTime = now
+	return GetOrmer().Insert(invitation)
+}
+
+// GetProjectInvitation returns the invitation specified by ID, nil is returned if not found
+func GetProjectInvitation(id int64) (*models.ProjectInvitation, error) {
+	invitation := &models.ProjectInvitation{
+		ID: id,
+	}
+	if err := GetOrmer().Read(invitation); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// GetProjectInvitationByToken returns the invitation specified by token, nil is returned if not found
+func GetProjectInvitationByToken(token string) (*models.ProjectInvitation, error) {
+	invitation := &models.ProjectInvitation{
+		Token: token,
+	}
+	if err := GetOrmer().Read(invitation, "Token"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListOutstandingProjectInvitations returns the invitations of the project which haven't expired,
+// the newest first
+func ListOutstandingProjectInvitations(projectID int64) ([]*models.ProjectInvitation, error) {
+	invitations := []*models.ProjectInvitation{}
+	_, err := GetOrmer().QueryTable(&models.ProjectInvitation{}).
+		Filter("ProjectID", projectID).
+		Filter("ExpirationTime__gt", time.Now()).
+		OrderBy("-CreationTime", "-ID").
+		All(&invitations)
+	return invitations, err
+}
+
+// IncreaseProjectInvitationAcceptedCount increases the count of the acceptances of the invitation
+func IncreaseProjectInvitationAcceptedCount(id int64) error {
+	_, err := GetOrmer().Raw(`update project_invitation set accepted_count = accepted_count + 1, update_time = ?
+		where id = ?`, time.Now(), id).Exec()
+	return err
+}
+
+// DeleteProjectInvitation ...
+func DeleteProjectInvitation(id int64) error {
+	_, err := GetOrmer().Delete(&models.ProjectInvitation{
+		ID: id,
+	})
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectInvitation(t *testing.T) {
+	id, err := AddProjectInvitation(&models.ProjectInvitation{
+		ProjectID:      1,
+		Token:          "project-invitation-test",
+		Role:           common.RoleDeveloper,
+		ExpirationTime: time.Now().Add(time.Hour),
+		Creator:        "admin",
+	})
+	require.Nil(t, err)
+	defer DeleteProjectInvitation(id)
+	expiredID, err := AddProjectInvitation(&models.ProjectInvitation{
+		ProjectID:      1,
+		Token:          "project-invitation-test-expired",
+		Role:           common.RoleGuest,
+		ExpirationTime: time.Now().Add(-time.Hour),
+		Creator:        "admin",
+	})
+	require.Nil(t, err)
+	defer DeleteProjectInvitation(expiredID)
+
+	invitation, err := GetProjectInvitationByToken("project-invitation-test")
+	require.Nil(t, err)
+	require.NotNil(t, invitation)
+	assert.Equal(t, id, invitation.ID)
+	assert.Equal(t, common.RoleDeveloper, invitation.Role)
+
+	invitation, err = GetProjectInvitationByToken("non-existing")
+	require.Nil(t, err)
+	assert.Nil(t, invitation)
+
+	// the expired invitations aren't outstanding
+	invitations, err := ListOutstandingProjectInvitations(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(invitations))
+	assert.Equal(t, id, invitations[0].ID)
+
+	require.Nil(t, IncreaseProjectInvitationAcceptedCount(id))
+	invitation, err = GetProjectInvitation(id)
+	require.Nil(t, err)
+	require.NotNil(t, invitation)
+	assert.Equal(t, 1, invitation.AcceptedCount)
+
+	require.Nil(t, DeleteProjectInvitation(id))
+	invitation, err = GetProjectInvitation(id)
+	require.Nil(t, err)
+	assert.Nil(t, invitation)
+}
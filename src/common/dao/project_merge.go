@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddProjectMerge ...
+func AddProjectMerge(merge *models.ProjectMerge) (int64, error) {
+	now := time.Now()
+	merge.CreationTime = now
+	merge.UpdateTime = now
+	return GetOrmer().Insert(merge)
+}
+
+// UpdateProjectMerge updates the status, message and the counts of the moved resources
+func UpdateProjectMerge(merge *models.ProjectMerge) error {
+	merge.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(merge, "Status", "Message", "MovedRepositories",
+		"MovedMembers", "MovedRobots", "UpdateTime")
+	return err
+}
+
+// DeleteProjectMerge ...
+func DeleteProjectMerge(id int64) error {
+	_, err := GetOrmer().Delete(&models.ProjectMerge{
+		ID: id,
+	})
+	return err
+}
+
+// GetProjectMerge returns the merge specified by ID, nil is returned if not found
+func GetProjectMerge(id int64) (*models.ProjectMerge, error) {
+	merge := &models.ProjectMerge{
+		ID: id,
+	}
+	if err := GetOrmer().Read(merge); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return merge, nil
+}
+
+// ListProjectMerges lists the merges according to the query conditions, the latest one is the first
+func ListProjectMerges(query *models.ProjectMergeQuery) ([]*models.ProjectMerge, error) {
+	qs := getProjectMergeQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	merges := []*models.ProjectMerge{}
+	_, err := qs.All(&merges)
+	return merges, err
+}
+
+// CountProjectMerges ...
+func CountProjectMerges(query *models.ProjectMergeQuery) (int64, error) {
+	return getProjectMergeQuerySetter(query).Count()
+}
+
+func getProjectMergeQuerySetter(query *models.ProjectMergeQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.ProjectMerge{})
+	if query == nil {
+		return qs
+	}
+	if query.ProjectID > 0 {
+		cond := orm.NewCondition()
+		qs = qs.SetCond(cond.Or("SourceProjectID", query.ProjectID).Or("TargetProjectID", query.ProjectID))
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
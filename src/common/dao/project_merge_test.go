@@ -0,0 +1,91 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectMerge(t *testing.T) {
+	projectID, err := AddProject(models.Project{
+		OwnerID: 1,
+		Name:    "project_for_merge_test",
+	})
+	require.Nil(t, err)
+	defer GetOrmer().QueryTable(&models.Project{}).
+		Filter("project_id", projectID).Delete()
+
+	merge := &models.ProjectMerge{
+		SourceProjectID:   projectID,
+		SourceProjectName: "project_for_merge_test",
+		TargetProjectID:   1,
+		MemberRule:        models.MemberRuleHigherRole,
+		Status:            models.ProjectMergeRunning,
+		Creator:           "admin",
+	}
+	id, err := AddProjectMerge(merge)
+	require.Nil(t, err)
+	defer ClearTable(models.ProjectMergeTable)
+
+	merge.MovedRepositories = 2
+	merge.MovedMembers = 1
+	merge.Status = models.ProjectMergeSucceeded
+	require.Nil(t, UpdateProjectMerge(merge))
+	m, err := GetProjectMerge(id)
+	require.Nil(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, models.ProjectMergeSucceeded, m.Status)
+	assert.Equal(t, 2, m.MovedRepositories)
+	assert.Equal(t, 1, m.MovedMembers)
+	assert.Equal(t, 0, m.MovedRobots)
+
+	id2, err := AddProjectMerge(&models.ProjectMerge{
+		SourceProjectID:   1,
+		SourceProjectName: "library",
+		TargetProjectID:   projectID,
+		MemberRule:        models.MemberRuleKeepTarget,
+		Status:            models.ProjectMergeRunning,
+		Creator:           "admin",
+	})
+	require.Nil(t, err)
+
+	// both the source and target projects are matched
+	total, err := CountProjectMerges(&models.ProjectMergeQuery{
+		ProjectID: projectID,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+
+	merges, err := ListProjectMerges(&models.ProjectMergeQuery{
+		ProjectID: projectID,
+		Status:    models.ProjectMergeRunning,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(merges))
+	assert.Equal(t, id2, merges[0].ID)
+
+	require.Nil(t, DeleteProjectMerge(id2))
+	m, err = GetProjectMerge(id2)
+	require.Nil(t, err)
+	assert.Nil(t, m)
+
+	m, err = GetProjectMerge(10000)
+	require.Nil(t, err)
+	assert.Nil(t, m)
+}
@@ -0,0 +1,74 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddProjectReportSubscription subscribes the user to the report emails of the project, nothing
+// is changed if the user has subscribed
+func AddProjectReportSubscription(projectID int64, userID int) error {
+	_, err := GetOrmer().Raw(`insert into project_report_subscription (project_id, user_id, creation_time)
+		values (?, ?, ?) on conflict (project_id, user_id) do nothing`, projectID, userID, time.Now()).Exec()
+	return err
+}
+
+// ListProjectReportSubscriptions returns the subscriptions of the project with the usernames
+func ListProjectReportSubscriptions(projectID int64) ([]*models.ProjectReportSubscription, error) {
+	subscriptions := []*models.ProjectReportSubscription{}
+	_, err := GetOrmer().Raw(`select s.id, s.project_id, s.user_id, u.username, s.creation_time
+		from project_report_subscription s
+		join harbor_user u on u.user_id = s.user_id
+		where s.project_id = ? and u.deleted = false
+		order by u.username`, projectID).QueryRows(&subscriptions)
+	return subscriptions, err
+}
+
+// DeleteProjectReportSubscription unsubscribes the user from the report emails of the project
+func DeleteProjectReportSubscription(projectID int64, userID int) error {
+	_, err := GetOrmer().QueryTable(&models.ProjectReportSubscription{}).
+		Filter("ProjectID", projectID).
+		Filter("UserID", userID).
+		Delete()
+	return err
+}
+
+// ListReportedProjectIDs returns the IDs of the projects which have subscriptions
+func ListReportedProjectIDs() ([]int64, error) {
+	ids := []int64{}
+	_, err := GetOrmer().Raw(`select distinct s.project_id from project_report_subscription s
+		join project p on p.project_id = s.project_id
+		where p.deleted = false
+		order by s.project_id`).QueryRows(&ids)
+	return ids, err
+}
+
+// ListProjectReportSubscribers returns the subscribers of the project which are still the
+// members of the project, the reports aren't sent to the users removed from the project
+func ListProjectReportSubscribers(projectID int64) ([]*models.User, error) {
+	users := []*models.User{}
+	_, err := GetOrmer().Raw(`select u.user_id, u.username, u.email, u.realname
+		from project_report_subscription s
+		join harbor_user u on u.user_id = s.user_id
+		where s.project_id = ? and u.deleted = false
+		and exists (select 1 from project_member pm
+			where pm.project_id = s.project_id and pm.entity_type = 'u' and pm.entity_id = s.user_id
+			and (pm.expiration_time is null or pm.expiration_time > now()))
+		order by u.username`, projectID).QueryRows(&users)
+	return users, err
+}
@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectReportSubscription(t *testing.T) {
+	// the admin is the member of project library
+	require.Nil(t, AddProjectReportSubscription(1, 1))
+	defer DeleteProjectReportSubscription(1, 1)
+	// subscribe again
+	require.Nil(t, AddProjectReportSubscription(1, 1))
+
+	subscriptions, err := ListProjectReportSubscriptions(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(subscriptions))
+	assert.Equal(t, 1, subscriptions[0].UserID)
+	assert.Equal(t, "admin", subscriptions[0].Username)
+
+	ids, err := ListReportedProjectIDs()
+	require.Nil(t, err)
+	assert.Contains(t, ids, int64(1))
+
+	users, err := ListProjectReportSubscribers(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(users))
+	assert.Equal(t, "admin", users[0].Username)
+
+	require.Nil(t, DeleteProjectReportSubscription(1, 1))
+	subscriptions, err = ListProjectReportSubscriptions(1)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(subscriptions))
+}
@@ -0,0 +1,168 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddPromotionPipeline ...
+func AddPromotionPipeline(pipeline *models.PromotionPipeline) (int64, error) {
+	if err := pipeline.Marshal(); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	pipeline.CreationTime = now
+	pipeline.UpdateTime = now
+	return GetOrmer().Insert(pipeline)
+}
+
+// GetPromotionPipeline returns the pipeline specified by ID, nil is returned if not found
+func GetPromotionPipeline(id int64) (*models.PromotionPipeline, error) {
+	pipeline := &models.PromotionPipeline{
+		ID: id,
+	}
+	if err := GetOrmer().Read(pipeline); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := pipeline.Unmarshal(); err != nil {
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+// ListPromotionPipelines lists all the pipelines ordered by name
+func ListPromotionPipelines() ([]*models.PromotionPipeline, error) {
+	pipelines := []*models.PromotionPipeline{}
+	if _, err := GetOrmer().QueryTable(&models.PromotionPipeline{}).
+		OrderBy("Name").All(&pipelines); err != nil {
+		return nil, err
+	}
+	for _, pipeline := range pipelines {
+		if err := pipeline.Unmarshal(); err != nil {
+			return nil, err
+		}
+	}
+	return pipelines, nil
+}
+
+// UpdatePromotionPipeline updates the name, description and stages of the pipeline
+func UpdatePromotionPipeline(pipeline *models.PromotionPipeline) error {
+	if err := pipeline.Marshal(); err != nil {
+		return err
+	}
+	pipeline.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(pipeline, "Name", "Description", "StagesStr", "UpdateTime")
+	return err
+}
+
+// DeletePromotionPipeline deletes the pipeline and the promotions of it
+func DeletePromotionPipeline(id int64) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	if _, err := o.QueryTable(&models.Promotion{}).Filter("PipelineID", id).Delete(); err != nil {
+		o.Rollback()
+		return err
+	}
+	if _, err := o.Delete(&models.PromotionPipeline{
+		ID: id,
+	}); err != nil {
+		o.Rollback()
+		return err
+	}
+	return o.Commit()
+}
+
+// AddPromotion ...
+func AddPromotion(promotion *models.Promotion) (int64, error) {
+	now := time.Now()
+	promotion.CreationTime = now
+	promotion.UpdateTime = now
+	return GetOrmer().Insert(promotion)
+}
+
+// GetPromotion returns the promotion specified by ID, nil is returned if not found
+func GetPromotion(id int64) (*models.Promotion, error) {
+	promotion := &models.Promotion{
+		ID: id,
+	}
+	if err := GetOrmer().Read(promotion); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// UpdatePromotionStatus updates the status, message and approver of the pending promotion,
+// the returned count is 0 if the promotion isn't pending any more
+func UpdatePromotionStatus(id int64, status, message, approver string) (int64, error) {
+	return GetOrmer().QueryTable(&models.Promotion{}).
+		Filter("ID", id).
+		Filter("Status", models.PromotionPending).
+		Update(orm.Params{
+			"Status":     status,
+			"Message":    message,
+			"Approver":   approver,
+			"UpdateTime": time.Now(),
+		})
+}
+
+// ListPromotions lists the promotions according to the query conditions, the latest one is the first
+func ListPromotions(query *models.PromotionQuery) ([]*models.Promotion, error) {
+	qs := getPromotionQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	promotions := []*models.Promotion{}
+	_, err := qs.All(&promotions)
+	return promotions, err
+}
+
+// CountPromotions ...
+func CountPromotions(query *models.PromotionQuery) (int64, error) {
+	return getPromotionQuerySetter(query).Count()
+}
+
+func getPromotionQuerySetter(query *models.PromotionQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.Promotion{})
+	if query == nil {
+		return qs
+	}
+	if query.PipelineID > 0 {
+		qs = qs.Filter("PipelineID", query.PipelineID)
+	}
+	if len(query.Repository) > 0 {
+		qs = qs.Filter("Repository", query.Repository)
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionPipeline(t *testing.T) {
+	pipeline := &models.PromotionPipeline{
+		Name: "test_pipeline",
+		Stages: []*models.PromotionStage{
+			{ProjectID: 1},
+			{ProjectID: 2, Gates: &models.PromotionGates{Severity: models.SeverityHigh, Approval: true}},
+		},
+		Creator: "admin",
+	}
+	id, err := AddPromotionPipeline(pipeline)
+	require.Nil(t, err)
+	defer DeletePromotionPipeline(id)
+
+	p, err := GetPromotionPipeline(id)
+	require.Nil(t, err)
+	require.NotNil(t, p)
+	require.Equal(t, 2, len(p.Stages))
+	assert.Equal(t, models.SeverityHigh, p.Stages[1].Gates.Severity)
+	assert.Equal(t, 1, p.StageOf(2))
+
+	p.Description = "dev to prod"
+	p.Stages = p.Stages[:1]
+	require.Nil(t, UpdatePromotionPipeline(p))
+	pipelines, err := ListPromotionPipelines()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(pipelines))
+	assert.Equal(t, "dev to prod", pipelines[0].Description)
+	assert.Equal(t, 1, len(pipelines[0].Stages))
+
+	promotionID, err := AddPromotion(&models.Promotion{
+		PipelineID:       id,
+		Repository:       "library/hello",
+		Tag:              "v1",
+		Digest:           "sha256:digest",
+		Stage:            1,
+		TargetRepository: "prod/hello",
+		Status:           models.PromotionPending,
+		Requester:        "admin",
+	})
+	require.Nil(t, err)
+
+	total, err := CountPromotions(&models.PromotionQuery{
+		PipelineID: id,
+		Status:     models.PromotionPending,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	n, err := UpdatePromotionStatus(promotionID, models.PromotionSucceeded, "", "admin")
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	// the promotion isn't pending any more
+	n, err = UpdatePromotionStatus(promotionID, models.PromotionRejected, "", "admin")
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+
+	promotions, err := ListPromotions(&models.PromotionQuery{
+		Repository: "library/hello",
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(promotions))
+	assert.Equal(t, models.PromotionSucceeded, promotions[0].Status)
+	assert.Equal(t, "admin", promotions[0].Approver)
+
+	require.Nil(t, DeletePromotionPipeline(id))
+	p, err = GetPromotionPipeline(id)
+	require.Nil(t, err)
+	assert.Nil(t, p)
+	promotion, err := GetPromotion(promotionID)
+	require.Nil(t, err)
+	assert.Nil(t, promotion)
+}
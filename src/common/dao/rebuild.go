@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+// The functions in this file rebuild the denormalized data from the source-of-truth
+// tables, they are used by the maintenance job after partial migrations or manual
+// changes of the database. All of them return the count of the fixed rows.
+
+// RebuildRepositoryProjects corrects the project of the repositories according to
+// the project name in their names
+func RebuildRepositoryProjects() (int64, error) {
+	result, err := GetOrmer().Raw(`update repository r set project_id = p.project_id 
+		from project p 
+		where p.name = split_part(r.name, '/', 1) and p.deleted = false and r.project_id <> p.project_id`).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RebuildStarCounts recounts the stars of the repositories
+func RebuildStarCounts() (int64, error) {
+	result, err := GetOrmer().Raw(`update repository r set star_count = s.count 
+		from (select r.repository_id, count(rs.id) as count 
+			from repository r left join repository_star rs on rs.repository_name = r.name 
+			group by r.repository_id) s 
+		where r.repository_id = s.repository_id and r.star_count <> s.count`).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteOrphanTagPullTimes deletes the pull time of tags whose repository doesn't exist
+func DeleteOrphanTagPullTimes() (int64, error) {
+	result, err := GetOrmer().Raw(`delete from tag_pull_time t 
+		where not exists (select 1 from repository r where r.name = t.repository)`).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteTagPullTimesNotIn deletes the pull time of the tags of repository which aren't in the list
+func DeleteTagPullTimesNotIn(repository string, tags []string) (int64, error) {
+	qs := GetOrmer().QueryTable("tag_pull_time").Filter("repository", repository)
+	if len(tags) > 0 {
+		qs = qs.Exclude("tag__in", tags)
+	}
+	return qs.Delete()
+}
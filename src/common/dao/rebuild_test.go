@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildRepositoryProjects(t *testing.T) {
+	name := "library/rebuild-project-test"
+	require.Nil(t, AddRepository(models.RepoRecord{
+		Name: name,
+		// a wrong project
+		ProjectID: 0,
+	}))
+	defer DeleteRepository(name)
+
+	n, err := RebuildRepositoryProjects()
+	require.Nil(t, err)
+	assert.True(t, n >= 1)
+
+	repo, err := GetRepositoryByName(name)
+	require.Nil(t, err)
+	require.NotNil(t, repo)
+	assert.Equal(t, int64(1), repo.ProjectID)
+}
+
+func TestRebuildStarCounts(t *testing.T) {
+	name := "library/rebuild-star-test"
+	require.Nil(t, AddRepository(models.RepoRecord{
+		Name:      name,
+		ProjectID: 1,
+	}))
+	defer DeleteRepository(name)
+
+	_, err := GetOrmer().Raw(`update repository set star_count = 10 where name = ?`, name).Exec()
+	require.Nil(t, err)
+
+	n, err := RebuildStarCounts()
+	require.Nil(t, err)
+	assert.True(t, n >= 1)
+
+	repo, err := GetRepositoryByName(name)
+	require.Nil(t, err)
+	require.NotNil(t, repo)
+	assert.Equal(t, int64(0), repo.StarCount)
+}
+
+func TestDeleteTagPullTimes(t *testing.T) {
+	name := "library/rebuild-pull-time-test"
+	require.Nil(t, AddRepository(models.RepoRecord{
+		Name:      name,
+		ProjectID: 1,
+	}))
+	defer DeleteRepository(name)
+
+	orphan := "library/rebuild-pull-time-orphan"
+	now := time.Now()
+	require.Nil(t, SetTagPullTimes([]*models.TagPullTime{
+		{Repository: name, Tag: "latest", PullTime: now},
+		{Repository: name, Tag: "deleted", PullTime: now},
+		{Repository: orphan, Tag: "latest", PullTime: now},
+	}))
+	defer DeleteTagPullTime(name, "latest")
+
+	n, err := DeleteOrphanTagPullTimes()
+	require.Nil(t, err)
+	assert.True(t, n >= 1)
+	pt, err := GetTagPullTime(orphan, "latest")
+	require.Nil(t, err)
+	assert.Nil(t, pt)
+
+	n, err = DeleteTagPullTimesNotIn(name, []string{"latest"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	pt, err = GetTagPullTime(name, "deleted")
+	require.Nil(t, err)
+	assert.Nil(t, pt)
+	pt, err = GetTagPullTime(name, "latest")
+	require.Nil(t, err)
+	assert.NotNil(t, pt)
+}
@@ -0,0 +1,95 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRegistryEvent records the event of registry as processed, false is returned if the
+// event has been recorded, i.e. it's a duplicate one
+func AddRegistryEvent(eventID string) (bool, error) {
+	result, err := GetOrmer().Raw(`insert into registry_event (event_id, creation_time) values (?, ?) 
+		on conflict (event_id) do nothing`, eventID, time.Now()).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteRegistryEventsBefore deletes the events recorded before the time, they are out of
+// the window of deduplication
+func DeleteRegistryEventsBefore(t time.Time) (int64, error) {
+	result, err := GetOrmer().Raw(`delete from registry_event where creation_time < ?`, t).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteRegistryEvent removes the record of the event, so that it's processed again
+func DeleteRegistryEvent(eventID string) error {
+	_, err := GetOrmer().Raw(`delete from registry_event where event_id = ?`, eventID).Exec()
+	return err
+}
+
+// AddPendingRegistryEvent persists the event received but not processed yet, the event is held
+// by the owner of it
+func AddPendingRegistryEvent(event *models.PendingRegistryEvent) (int64, error) {
+	event.CreationTime = time.Now()
+	event.Heartbeat = event.CreationTime
+	return GetOrmer().Insert(event)
+}
+
+// RefreshPendingRegistryEvents refreshes the heartbeat of the pending events held by the owner
+func RefreshPendingRegistryEvents(owner string) (int64, error) {
+	result, err := GetOrmer().Raw(`update pending_registry_event set heartbeat = ? where owner = ?`,
+		time.Now(), owner).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// TakeOverPendingRegistryEvents transfers the pending events whose heartbeat is before the time,
+// i.e. the ones left by the stopped instances, to the owner and returns them, the earliest is the
+// first. Each event is transferred to only one of the instances taking them over concurrently
+func TakeOverPendingRegistryEvents(owner string, before time.Time) ([]*models.PendingRegistryEvent, error) {
+	events := []*models.PendingRegistryEvent{}
+	_, err := GetOrmer().Raw(`update pending_registry_event set owner = ?, heartbeat = ?
+		where heartbeat < ?
+		returning id, event_id, repository, event, owner, heartbeat, creation_time`,
+		owner, time.Now(), before).QueryRows(&events)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// DeletePendingRegistryEvent removes the pending event once it's processed
+func DeletePendingRegistryEvent(id int64) error {
+	_, err := GetOrmer().Delete(&models.PendingRegistryEvent{ID: id})
+	return err
+}
@@ -0,0 +1,95 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryEvent(t *testing.T) {
+	id := "0a1b2c3d-registry-event-test"
+	added, err := AddRegistryEvent(id)
+	require.Nil(t, err)
+	assert.True(t, added)
+
+	// duplicate
+	added, err = AddRegistryEvent(id)
+	require.Nil(t, err)
+	assert.False(t, added)
+
+	n, err := DeleteRegistryEventsBefore(time.Now().Add(time.Minute))
+	require.Nil(t, err)
+	assert.True(t, n >= 1)
+
+	added, err = AddRegistryEvent(id)
+	require.Nil(t, err)
+	assert.True(t, added)
+	// released to be processed again
+	require.Nil(t, DeleteRegistryEvent(id))
+	added, err = AddRegistryEvent(id)
+	require.Nil(t, err)
+	assert.True(t, added)
+	_, err = DeleteRegistryEventsBefore(time.Now().Add(time.Minute))
+	require.Nil(t, err)
+}
+
+func TestPendingRegistryEvent(t *testing.T) {
+	id, err := AddPendingRegistryEvent(&models.PendingRegistryEvent{
+		EventID:    "0a1b2c3d-pending-event-test",
+		Repository: "library/hello-world",
+		Event:      `{"id":"0a1b2c3d-pending-event-test"}`,
+		Owner:      "instance-a",
+	})
+	require.Nil(t, err)
+
+	// the heartbeat isn't expired
+	events, err := TakeOverPendingRegistryEvents("instance-b", time.Now().Add(-time.Minute))
+	require.Nil(t, err)
+	for _, event := range events {
+		assert.NotEqual(t, id, event.ID)
+	}
+
+	n, err := RefreshPendingRegistryEvents("instance-a")
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	events, err = TakeOverPendingRegistryEvents("instance-b", time.Now().Add(time.Minute))
+	require.Nil(t, err)
+	found := false
+	for _, event := range events {
+		if event.ID == id {
+			found = true
+			assert.Equal(t, "library/hello-world", event.Repository)
+			assert.Equal(t, "instance-b", event.Owner)
+		}
+	}
+	assert.True(t, found)
+	// taken over already
+	n, err = RefreshPendingRegistryEvents("instance-a")
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+
+	require.Nil(t, DeletePendingRegistryEvent(id))
+	events, err = TakeOverPendingRegistryEvents("instance-b", time.Now().Add(time.Minute))
+	require.Nil(t, err)
+	for _, event := range events {
+		assert.NotEqual(t, id, event.ID)
+	}
+}
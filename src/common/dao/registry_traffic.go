@@ -0,0 +1,88 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRegistryTraffic adds the traffic to the recorded one of the repositories on the
+// days in one statement, the project is looked up by the name and the traffic of the
+// projects which don't exist is dropped
+func AddRegistryTraffic(traffics []*models.RegistryTraffic) error {
+	if len(traffics) == 0 {
+		return nil
+	}
+	values := []string{}
+	params := []interface{}{}
+	for _, t := range traffics {
+		values = append(values, "(?, ?, ?, ?, ?, ?)")
+		params = append(params, t.ProjectName, t.Repository, t.Day.Format("2006-01-02"),
+			t.RequestCount, t.ErrorCount, t.BytesServed)
+	}
+	sql := `insert into registry_traffic (project_id, repository, day, request_count, error_count, bytes_served) 
+		select p.project_id, v.repository, cast(v.day as date), cast(v.request_count as bigint), 
+		cast(v.error_count as bigint), cast(v.bytes_served as bigint) 
+		from (values ` + strings.Join(values, ", ") + `) 
+		as v (project_name, repository, day, request_count, error_count, bytes_served) 
+		join project p on p.name = v.project_name and p.deleted = false 
+		on conflict (repository, day) do update set 
+		request_count = registry_traffic.request_count + excluded.request_count, 
+		error_count = registry_traffic.error_count + excluded.error_count, 
+		bytes_served = registry_traffic.bytes_served + excluded.bytes_served`
+	_, err := GetOrmer().Raw(sql, params...).Exec()
+	return err
+}
+
+// ListRegistryTraffic returns the traffic matching the query aggregated by project or
+// by repository in the descent order of the served bytes
+func ListRegistryTraffic(query *models.RegistryTrafficQuery) ([]*models.RegistryTraffic, error) {
+	if query == nil {
+		query = &models.RegistryTrafficQuery{}
+	}
+	columns, groups := `t.project_id, p.name as project_name`, `t.project_id, p.name`
+	if query.ByRepository {
+		columns += `, t.repository`
+		groups += `, t.repository`
+	}
+	sql := `select ` + columns + `, sum(t.request_count) as request_count, 
+		sum(t.error_count) as error_count, sum(t.bytes_served) as bytes_served 
+		from registry_traffic t join project p on t.project_id = p.project_id where 1 = 1`
+	params := []interface{}{}
+	if len(query.ProjectIDs) > 0 {
+		sql += fmt.Sprintf(` and t.project_id in ( %s )`, paramPlaceholder(len(query.ProjectIDs)))
+		params = append(params, query.ProjectIDs)
+	}
+	if len(query.Repository) > 0 {
+		sql += ` and t.repository = ?`
+		params = append(params, query.Repository)
+	}
+	if query.BeginTime != nil {
+		sql += ` and t.day >= ?`
+		params = append(params, query.BeginTime.Format("2006-01-02"))
+	}
+	if query.EndTime != nil {
+		sql += ` and t.day <= ?`
+		params = append(params, query.EndTime.Format("2006-01-02"))
+	}
+	sql += ` group by ` + groups + ` order by bytes_served desc, ` + groups
+
+	traffics := []*models.RegistryTraffic{}
+	_, err := GetOrmer().Raw(sql, params).QueryRows(&traffics)
+	return traffics, err
+}
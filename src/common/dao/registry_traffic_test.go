@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryTraffic(t *testing.T) {
+	projectID, err := AddProject(models.Project{
+		Name:    "dao-traffic",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer delProjPermanent(projectID)
+	defer GetOrmer().Raw(`delete from registry_traffic where project_id = ?`, projectID).Exec()
+
+	day1 := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	require.Nil(t, AddRegistryTraffic([]*models.RegistryTraffic{
+		{
+			ProjectName:  "dao-traffic",
+			Repository:   "dao-traffic/app",
+			Day:          day1,
+			RequestCount: 10,
+			ErrorCount:   1,
+			BytesServed:  1000,
+		},
+		{
+			ProjectName:  "dao-traffic",
+			Repository:   "dao-traffic/db",
+			Day:          day2,
+			RequestCount: 5,
+			BytesServed:  100,
+		},
+		// the project doesn't exist
+		{
+			ProjectName:  "dao-traffic-not-exist",
+			Repository:   "dao-traffic-not-exist/app",
+			Day:          day1,
+			RequestCount: 5,
+		},
+	}))
+	// added to the recorded traffic
+	require.Nil(t, AddRegistryTraffic([]*models.RegistryTraffic{
+		{
+			ProjectName:  "dao-traffic",
+			Repository:   "dao-traffic/app",
+			Day:          day1,
+			RequestCount: 10,
+			BytesServed:  1000,
+		},
+	}))
+
+	traffics, err := ListRegistryTraffic(&models.RegistryTrafficQuery{
+		ProjectIDs: []int64{projectID},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(traffics))
+	assert.Equal(t, "dao-traffic", traffics[0].ProjectName)
+	assert.Equal(t, int64(25), traffics[0].RequestCount)
+	assert.Equal(t, int64(1), traffics[0].ErrorCount)
+	assert.Equal(t, int64(2100), traffics[0].BytesServed)
+
+	traffics, err = ListRegistryTraffic(&models.RegistryTrafficQuery{
+		ProjectIDs:   []int64{projectID},
+		ByRepository: true,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(traffics))
+	assert.Equal(t, "dao-traffic/app", traffics[0].Repository)
+	assert.Equal(t, int64(20), traffics[0].RequestCount)
+	assert.Equal(t, "dao-traffic/db", traffics[1].Repository)
+
+	traffics, err = ListRegistryTraffic(&models.RegistryTrafficQuery{
+		ProjectIDs:   []int64{projectID},
+		BeginTime:    &day2,
+		ByRepository: true,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(traffics))
+	assert.Equal(t, "dao-traffic/db", traffics[0].Repository)
+}
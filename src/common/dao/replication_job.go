@@ -28,10 +28,18 @@ import (
 func AddRepTarget(target models.RepTarget) (int64, error) {
 	o := GetOrmer()
 
-	sql := "insert into replication_target (name, url, username, password, insecure, target_type) values (?, ?, ?, ?, ?, ?) RETURNING id"
+	if err := target.Marshal(); err != nil {
+		return 0, err
+	}
+
+	sql := `insert into replication_target (name, url, username, password, credential_ref, insecure, target_type, 
+		auth_scheme, token_realm, path_mappings, max_concurrent_transfers, backoff_retries, ratelimit_reserve) 
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
 
 	var targetID int64
-	err := o.Raw(sql, target.Name, target.URL, target.Username, target.Password, target.Insecure, target.Type).QueryRow(&targetID)
+	err := o.Raw(sql, target.Name, target.URL, target.Username, target.Password, target.CredentialRef, target.Insecure, target.Type,
+		target.AuthScheme, target.TokenRealm, target.PathMappingStr, target.MaxConcurrentTransfers, target.BackoffRetries,
+		target.RateLimitReserve).QueryRow(&targetID)
 	if err != nil {
 		return 0, err
 	}
@@ -46,7 +54,10 @@ func GetRepTarget(id int64) (*models.RepTarget, error) {
 	if err == orm.ErrNoRows {
 		return nil, nil
 	}
-	return &t, err
+	if err != nil {
+		return nil, err
+	}
+	return &t, t.Unmarshal()
 }
 
 // GetRepTargetByName ...
@@ -57,7 +68,10 @@ func GetRepTargetByName(name string) (*models.RepTarget, error) {
 	if err == orm.ErrNoRows {
 		return nil, nil
 	}
-	return &t, err
+	if err != nil {
+		return nil, err
+	}
+	return &t, t.Unmarshal()
 }
 
 // GetRepTargetByEndpoint ...
@@ -70,7 +84,10 @@ func GetRepTargetByEndpoint(endpoint string) (*models.RepTarget, error) {
 	if err == orm.ErrNoRows {
 		return nil, nil
 	}
-	return &t, err
+	if err != nil {
+		return nil, err
+	}
+	return &t, t.Unmarshal()
 }
 
 // DeleteRepTarget ...
@@ -84,11 +101,19 @@ func DeleteRepTarget(id int64) error {
 func UpdateRepTarget(target models.RepTarget) error {
 	o := GetOrmer()
 
+	if err := target.Marshal(); err != nil {
+		return err
+	}
+
 	sql := `update replication_target 
-	set url = ?, name = ?, username = ?, password = ?, insecure = ?, update_time = ?
+	set url = ?, name = ?, username = ?, password = ?, credential_ref = ?, insecure = ?, target_type = ?, 
+	auth_scheme = ?, token_realm = ?, path_mappings = ?, max_concurrent_transfers = ?, backoff_retries = ?,
+	ratelimit_reserve = ?, update_time = ?
 	where id = ?`
 
-	_, err := o.Raw(sql, target.URL, target.Name, target.Username, target.Password, target.Insecure, time.Now(), target.ID).Exec()
+	_, err := o.Raw(sql, target.URL, target.Name, target.Username, target.Password, target.CredentialRef, target.Insecure, target.Type,
+		target.AuthScheme, target.TokenRealm, target.PathMappingStr, target.MaxConcurrentTransfers, target.BackoffRetries,
+		target.RateLimitReserve, time.Now(), target.ID).Exec()
 
 	return err
 }
@@ -112,20 +137,26 @@ func FilterRepTargets(name string) ([]*models.RepTarget, error) {
 		return nil, err
 	}
 
+	for _, target := range targets {
+		if err := target.Unmarshal(); err != nil {
+			return nil, err
+		}
+	}
+
 	return targets, nil
 }
 
 // AddRepPolicy ...
 func AddRepPolicy(policy models.RepPolicy) (int64, error) {
 	o := GetOrmer()
-	sql := `insert into replication_policy (name, project_id, target_id, enabled, description, cron_str, creation_time, update_time, filters, replicate_deletion) 
-				values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
+	sql := `insert into replication_policy (name, project_id, target_id, enabled, description, cron_str, creation_time, update_time, filters, replicate_deletion, replicate_robots) 
+				values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
 	params := []interface{}{}
 	now := time.Now()
 
 	params = append(params, policy.Name, policy.ProjectID, policy.TargetID, true,
 		policy.Description, policy.Trigger, now, now, policy.Filters,
-		policy.ReplicateDeletion)
+		policy.ReplicateDeletion, policy.ReplicateRobots)
 
 	var policyID int64
 	err := o.Raw(sql, params...).QueryRow(&policyID)
@@ -176,7 +207,7 @@ func FilterRepPolicies(name string, projectID, page, pageSize int64) ([]*models.
 
 	sql := `select rp.id, rp.project_id, rp.target_id, 
 				rt.name as target_name, rp.name, rp.description,
-				rp.cron_str, rp.filters, rp.replicate_deletion, 
+				rp.cron_str, rp.filters, rp.replicate_deletion, rp.replicate_robots, 
 				rp.creation_time, rp.update_time, 
 				count(rj.status) as error_job_count 
 			from replication_policy rp 
@@ -276,10 +307,10 @@ func UpdateRepPolicy(policy *models.RepPolicy) error {
 	o := GetOrmer()
 
 	sql := `update replication_policy 
-		set project_id = ?, target_id = ?, name = ?, description = ?, cron_str = ?, filters = ?, replicate_deletion = ?, update_time = ? 
+		set project_id = ?, target_id = ?, name = ?, description = ?, cron_str = ?, filters = ?, replicate_deletion = ?, replicate_robots = ?, update_time = ? 
 		where id = ?`
 
-	_, err := o.Raw(sql, policy.ProjectID, policy.TargetID, policy.Name, policy.Description, policy.Trigger, policy.Filters, policy.ReplicateDeletion, time.Now(), policy.ID).Exec()
+	_, err := o.Raw(sql, policy.ProjectID, policy.TargetID, policy.Name, policy.Description, policy.Trigger, policy.Filters, policy.ReplicateDeletion, policy.ReplicateRobots, time.Now(), policy.ID).Exec()
 
 	return err
 }
@@ -346,6 +377,22 @@ func GetRepJobs(query ...*models.RepJobQuery) ([]*models.RepJob, error) {
 	return jobs, nil
 }
 
+// GetRepJobsAfter returns at most size jobs matching the query whose ID is greater than
+// the specified one ordered by ID, the pagination of the query is ignored. It's used to
+// iterate all the jobs as the order isn't changed by the updates of the jobs
+func GetRepJobsAfter(query *models.RepJobQuery, id int64, size int64) ([]*models.RepJob, error) {
+	jobs := []*models.RepJob{}
+	qs := repJobQueryConditions(query).
+		Filter("ID__gt", id).
+		OrderBy("ID").
+		Limit(size)
+	if _, err := qs.All(&jobs); err != nil {
+		return nil, err
+	}
+	genTagListForJob(jobs...)
+	return jobs, nil
+}
+
 func repJobQueryConditions(query ...*models.RepJobQuery) orm.QuerySeter {
 	qs := GetOrmer().QueryTable(new(models.RepJob))
 	if len(query) == 0 || query[0] == nil {
@@ -399,6 +446,20 @@ func UpdateRepJobStatus(id int64, status string) error {
 	return err
 }
 
+// UpdateRepJobReport updates the size and error message of the job with the report checked in by it
+func UpdateRepJobReport(id int64, report *models.RepJobReport) error {
+	j := models.RepJob{
+		ID:    id,
+		Size:  report.Size,
+		Error: report.Error,
+	}
+	n, err := GetOrmer().Update(&j, "Size", "Error")
+	if n == 0 {
+		log.Warningf("no records are updated when updating replication job %d", id)
+	}
+	return err
+}
+
 // SetRepJobUUID ...
 func SetRepJobUUID(id int64, uuid string) error {
 	o := GetOrmer()
@@ -0,0 +1,122 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddPreCreatedRepository adds the repository before its first push together with the access
+// control list in one transaction
+func AddPreCreatedRepository(repo *models.RepoRecord, acls []*models.RepoACL) (int64, error) {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	repo.PreCreated = true
+	repo.CreationTime = now
+	repo.UpdateTime = now
+	id, err := o.Insert(repo)
+	if err != nil {
+		o.Rollback()
+		if isDupRecErr(err) {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	for _, acl := range acls {
+		acl.RepositoryName = repo.Name
+		acl.CreationTime = now
+		acl.UpdateTime = now
+		if _, err = o.Insert(acl); err != nil {
+			o.Rollback()
+			return 0, err
+		}
+	}
+	return id, o.Commit()
+}
+
+// GetRepoAccess returns the access granted to the user on the repository by the access
+// control list, it's empty if nothing is granted
+func GetRepoAccess(repository, username string) (string, error) {
+	acl := &models.RepoACL{}
+	err := GetOrmer().Raw(`select a.* from repository_acl a 
+		join harbor_user u on a.user_id = u.user_id 
+		where a.repository_name = ? and u.username = ? and u.deleted = false`,
+		repository, username).QueryRow(acl)
+	if err == orm.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return acl.Access, nil
+}
+
+// ListRepoACLs returns the access control list of the repository with the usernames
+func ListRepoACLs(repository string) ([]*models.RepoACL, error) {
+	rows := []*struct {
+		ID           int64     `orm:"column(id)"`
+		UserID       int       `orm:"column(user_id)"`
+		Username     string    `orm:"column(username)"`
+		Access       string    `orm:"column(access)"`
+		CreationTime time.Time `orm:"column(creation_time)"`
+		UpdateTime   time.Time `orm:"column(update_time)"`
+	}{}
+	if _, err := GetOrmer().Raw(`select a.id, a.user_id, u.username, a.access, a.creation_time, a.update_time 
+		from repository_acl a 
+		join harbor_user u on a.user_id = u.user_id 
+		where a.repository_name = ? and u.deleted = false 
+		order by u.username`, repository).QueryRows(&rows); err != nil {
+		return nil, err
+	}
+	acls := []*models.RepoACL{}
+	for _, row := range rows {
+		acls = append(acls, &models.RepoACL{
+			ID:             row.ID,
+			RepositoryName: repository,
+			UserID:         row.UserID,
+			Username:       row.Username,
+			Access:         row.Access,
+			CreationTime:   row.CreationTime,
+			UpdateTime:     row.UpdateTime,
+		})
+	}
+	return acls, nil
+}
+
+// SetRepoACL grants the access to the user on the repository, the access granted before
+// is replaced
+func SetRepoACL(acl *models.RepoACL) error {
+	now := time.Now()
+	sql := `insert into repository_acl (repository_name, user_id, access, creation_time, update_time)
+		values (?, ?, ?, ?, ?)
+		on conflict (repository_name, user_id) do update set access = excluded.access, 
+		update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, acl.RepositoryName, acl.UserID, acl.Access, now, now).Exec()
+	return err
+}
+
+// DeleteRepoACL revokes the access granted to the user on the repository
+func DeleteRepoACL(repository string, userID int) error {
+	_, err := GetOrmer().Raw(`delete from repository_acl where repository_name = ? and user_id = ?`,
+		repository, userID).Exec()
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddPreCreatedRepository(t *testing.T) {
+	repoName := "library/repo-acl-test"
+	id, err := AddPreCreatedRepository(&models.RepoRecord{
+		Name:        repoName,
+		ProjectID:   1,
+		Description: "created before the first push",
+	}, []*models.RepoACL{
+		{
+			UserID: 1,
+			Access: models.RepoAccessPush,
+		},
+	})
+	require.Nil(t, err)
+	defer deleteRepository(repoName)
+	assert.True(t, id > 0)
+
+	repo, err := GetRepositoryByName(repoName)
+	require.Nil(t, err)
+	require.NotNil(t, repo)
+	assert.True(t, repo.PreCreated)
+	assert.Equal(t, "created before the first push", repo.Description)
+
+	_, err = AddPreCreatedRepository(&models.RepoRecord{
+		Name:      repoName,
+		ProjectID: 1,
+	}, nil)
+	assert.Equal(t, ErrDupRows, err)
+
+	access, err := GetRepoAccess(repoName, "admin")
+	require.Nil(t, err)
+	assert.Equal(t, models.RepoAccessPush, access)
+	access, err = GetRepoAccess(repoName, "non-exist")
+	require.Nil(t, err)
+	assert.Equal(t, "", access)
+
+	// the access control list is deleted together with the repository
+	require.Nil(t, DeleteRepository(repoName))
+	access, err = GetRepoAccess(repoName, "admin")
+	require.Nil(t, err)
+	assert.Equal(t, "", access)
+}
+
+func TestRepoACLs(t *testing.T) {
+	repoName := "library/repo-collaborator-test"
+	_, err := AddPreCreatedRepository(&models.RepoRecord{
+		Name:      repoName,
+		ProjectID: 1,
+	}, nil)
+	require.Nil(t, err)
+	defer deleteRepository(repoName)
+
+	acls, err := ListRepoACLs(repoName)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(acls))
+
+	require.Nil(t, SetRepoACL(&models.RepoACL{
+		RepositoryName: repoName,
+		UserID:         1,
+		Access:         models.RepoAccessPull,
+	}))
+	require.Nil(t, SetRepoACL(&models.RepoACL{
+		RepositoryName: repoName,
+		UserID:         1,
+		Access:         models.RepoAccessPush,
+	}))
+	acls, err = ListRepoACLs(repoName)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(acls))
+	assert.Equal(t, "admin", acls[0].Username)
+	assert.Equal(t, models.RepoAccessPush, acls[0].Access)
+
+	require.Nil(t, DeleteRepoACL(repoName, 1))
+	access, err := GetRepoAccess(repoName, "admin")
+	require.Nil(t, err)
+	assert.Equal(t, "", access)
+}
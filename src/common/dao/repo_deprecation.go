@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetRepoDeprecation deprecates the repository or the tag, the message and replacement of the
+// existing deprecation are updated while the hits are kept
+func SetRepoDeprecation(deprecation *models.RepoDeprecation) error {
+	now := time.Now()
+	sql := `insert into repository_deprecation (repository, tag, message, replacement, creator, creation_time, update_time)
+		values (?, ?, ?, ?, ?, ?, ?)
+		on conflict (repository, tag) do update set message = excluded.message, replacement = excluded.replacement,
+		creator = excluded.creator, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, deprecation.Repository, deprecation.Tag, deprecation.Message,
+		deprecation.Replacement, deprecation.Creator, now, now).Exec()
+	return err
+}
+
+// GetRepoDeprecation returns the deprecation of the tag, or the one of the repository if the tag
+// is empty. Nil is returned if not found
+func GetRepoDeprecation(repository, tag string) (*models.RepoDeprecation, error) {
+	deprecation := &models.RepoDeprecation{
+		Repository: repository,
+		Tag:        tag,
+	}
+	if err := GetOrmer().Read(deprecation, "Repository", "Tag"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return deprecation, nil
+}
+
+// GetEffectiveRepoDeprecation returns the deprecation applied to the pulls of the reference, the
+// deprecation of the tag takes precedence over the one of the repository. Nil is returned if
+// neither is deprecated
+func GetEffectiveRepoDeprecation(repository, reference string) (*models.RepoDeprecation, error) {
+	deprecations := []*models.RepoDeprecation{}
+	// the empty tag of the repository sorts first
+	_, err := GetOrmer().Raw(`select * from repository_deprecation where repository = ? and tag in (?, '')
+		order by tag desc limit 1`, repository, reference).QueryRows(&deprecations)
+	if err != nil {
+		return nil, err
+	}
+	if len(deprecations) == 0 {
+		return nil, nil
+	}
+	return deprecations[0], nil
+}
+
+// ListRepoDeprecations returns the deprecations of the repository and its tags
+func ListRepoDeprecations(repository string) ([]*models.RepoDeprecation, error) {
+	deprecations := []*models.RepoDeprecation{}
+	_, err := GetOrmer().QueryTable(&models.RepoDeprecation{}).
+		Filter("Repository", repository).
+		OrderBy("Tag").
+		All(&deprecations)
+	return deprecations, err
+}
+
+// AddRepoDeprecationHit counts a pull of the deprecated image
+func AddRepoDeprecationHit(id int64, t time.Time) error {
+	_, err := GetOrmer().Raw(`update repository_deprecation set hit_count = hit_count + 1, last_hit_time = ? where id = ?`,
+		t, id).Exec()
+	return err
+}
+
+// DeleteRepoDeprecation removes the deprecation of the tag, or the one of the repository if the tag is empty
+func DeleteRepoDeprecation(repository, tag string) error {
+	_, err := GetOrmer().QueryTable(&models.RepoDeprecation{}).
+		Filter("Repository", repository).
+		Filter("Tag", tag).
+		Delete()
+	return err
+}
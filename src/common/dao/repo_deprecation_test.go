@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoDeprecation(t *testing.T) {
+	repository := "library/repo-deprecation-test"
+	deprecation, err := GetEffectiveRepoDeprecation(repository, "1.0")
+	require.Nil(t, err)
+	assert.Nil(t, deprecation)
+
+	require.Nil(t, SetRepoDeprecation(&models.RepoDeprecation{
+		Repository: repository,
+		Message:    "unmaintained",
+		Creator:    "admin",
+	}))
+	defer DeleteRepoDeprecation(repository, "")
+	require.Nil(t, SetRepoDeprecation(&models.RepoDeprecation{
+		Repository:  repository,
+		Tag:         "1.0",
+		Message:     "vulnerable",
+		Replacement: repository + ":2.0",
+		Creator:     "admin",
+	}))
+	defer DeleteRepoDeprecation(repository, "1.0")
+
+	// the deprecation of the tag takes precedence
+	deprecation, err = GetEffectiveRepoDeprecation(repository, "1.0")
+	require.Nil(t, err)
+	require.NotNil(t, deprecation)
+	assert.Equal(t, "vulnerable", deprecation.Message)
+	deprecation, err = GetEffectiveRepoDeprecation(repository, "2.0")
+	require.Nil(t, err)
+	require.NotNil(t, deprecation)
+	assert.Equal(t, "unmaintained", deprecation.Message)
+
+	require.Nil(t, AddRepoDeprecationHit(deprecation.ID, time.Now()))
+	require.Nil(t, AddRepoDeprecationHit(deprecation.ID, time.Now()))
+	// the hits are kept when the message is updated
+	require.Nil(t, SetRepoDeprecation(&models.RepoDeprecation{
+		Repository: repository,
+		Message:    "use the new one",
+		Creator:    "admin",
+	}))
+	deprecation, err = GetRepoDeprecation(repository, "")
+	require.Nil(t, err)
+	require.NotNil(t, deprecation)
+	assert.Equal(t, "use the new one", deprecation.Message)
+	assert.Equal(t, int64(2), deprecation.HitCount)
+	assert.NotNil(t, deprecation.LastHitTime)
+
+	deprecations, err := ListRepoDeprecations(repository)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(deprecations))
+	assert.Equal(t, "", deprecations[0].Tag)
+	assert.Equal(t, "1.0", deprecations[1].Tag)
+
+	require.Nil(t, DeleteRepoDeprecation(repository, "1.0"))
+	deprecation, err = GetRepoDeprecation(repository, "1.0")
+	require.Nil(t, err)
+	assert.Nil(t, deprecation)
+}
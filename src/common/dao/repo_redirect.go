@@ -0,0 +1,155 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+type statement struct {
+	sql    string
+	params []interface{}
+}
+
+// RenameRepository renames the repository and moves it to the project specified by projectID, the
+// access logs, scan jobs, labels and pull time of the images are updated in one transaction.
+// The stars and subscriptions are updated by the foreign keys.
+func RenameRepository(oldName, newName string, projectID int64) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+
+	result, err := o.Raw(`update repository set name = ?, project_id = ?, update_time = ? where name = ?`,
+		newName, projectID, time.Now(), oldName).Exec()
+	if err != nil {
+		o.Rollback()
+		if isDupRecErr(err) {
+			return ErrDupRows
+		}
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		o.Rollback()
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("repository %s not found", oldName)
+	}
+
+	prefix := oldName + ":"
+	statements := []statement{
+		{`update access_log set repo_name = ?, project_id = ? where repo_name = ?`,
+			[]interface{}{newName, projectID, oldName}},
+		{`update img_scan_job set repository = ? where repository = ?`,
+			[]interface{}{newName, oldName}},
+		{`update harbor_resource_label set resource_name = ? || substr(resource_name, ?) 
+			where resource_type = ? and left(resource_name, ?) = ?`,
+			[]interface{}{newName, len(oldName) + 1, common.ResourceTypeImage, len(prefix), prefix}},
+		{`update tag_pull_time set repository = ? where repository = ?`,
+			[]interface{}{newName, oldName}},
+		{`update artifact_lint set repository = ? where repository = ?`,
+			[]interface{}{newName, oldName}},
+		{`update repository_deprecation set repository = ? where repository = ?`,
+			[]interface{}{newName, oldName}},
+		{`update tag_alias set repository = ? where repository = ?`,
+			[]interface{}{newName, oldName}},
+		{`update artifact_provenance set repository = ? where repository = ?`,
+			[]interface{}{newName, oldName}},
+		{`update artifact_provenance set source_repository = ? where source_repository = ?`,
+			[]interface{}{newName, oldName}},
+	}
+	for _, stmt := range statements {
+		if _, err = o.Raw(stmt.sql, stmt.params...).Exec(); err != nil {
+			o.Rollback()
+			return err
+		}
+	}
+	return o.Commit()
+}
+
+// AddRepoRedirect redirects the old name of the renamed repository to the new one until the expiration
+// time, the existing redirects to the old name are pointed to the new name as well
+func AddRepoRedirect(oldName, newName string, expiration time.Time) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	statements := []statement{
+		// the new name is a real repository now
+		{`delete from repository_redirect where old_name = ? or old_name = ?`,
+			[]interface{}{oldName, newName}},
+		{`update repository_redirect set new_name = ? where new_name = ?`,
+			[]interface{}{newName, oldName}},
+		{`insert into repository_redirect (old_name, new_name, expiration_time, creation_time) values (?, ?, ?, ?)`,
+			[]interface{}{oldName, newName, expiration, time.Now()}},
+	}
+	for _, stmt := range statements {
+		if _, err := o.Raw(stmt.sql, stmt.params...).Exec(); err != nil {
+			o.Rollback()
+			return err
+		}
+	}
+	return o.Commit()
+}
+
+// GetRepoRedirect returns the unexpired redirect of the old repository name, nil is returned if not found
+func GetRepoRedirect(oldName string) (*models.RepoRedirect, error) {
+	redirect := &models.RepoRedirect{}
+	err := GetOrmer().QueryTable(&models.RepoRedirect{}).
+		Filter("OldName", oldName).
+		Filter("ExpirationTime__gt", time.Now()).
+		One(redirect)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return redirect, nil
+}
+
+// ListRepoRedirectSources returns the old names of the unexpired redirects and their expiration time
+func ListRepoRedirectSources() (map[string]time.Time, error) {
+	redirects := []*models.RepoRedirect{}
+	_, err := GetOrmer().QueryTable(&models.RepoRedirect{}).
+		Filter("ExpirationTime__gt", time.Now()).
+		All(&redirects, "OldName", "ExpirationTime")
+	if err != nil {
+		return nil, err
+	}
+	sources := map[string]time.Time{}
+	for _, redirect := range redirects {
+		sources[redirect.OldName] = redirect.ExpirationTime
+	}
+	return sources, nil
+}
+
+// DeleteRepoRedirects deletes the redirects of the old repository name and returns the count of them
+func DeleteRepoRedirects(oldName string) (int64, error) {
+	return GetOrmer().QueryTable(&models.RepoRedirect{}).Filter("OldName", oldName).Delete()
+}
+
+// DeleteExpiredRepoRedirects deletes the expired redirects and returns the count of them
+func DeleteExpiredRepoRedirects() (int64, error) {
+	return GetOrmer().QueryTable(&models.RepoRedirect{}).
+		Filter("ExpirationTime__lte", time.Now()).
+		Delete()
+}
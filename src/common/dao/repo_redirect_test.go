@@ -0,0 +1,112 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameRepository(t *testing.T) {
+	oldName := "library/rename-repository-test"
+	newName := "library/renamed-repository-test"
+	require.Nil(t, AddRepository(models.RepoRecord{
+		Name:      oldName,
+		ProjectID: 1,
+	}))
+	defer DeleteRepository(oldName)
+	defer DeleteRepository(newName)
+
+	id, err := AddResourceLabel(&models.ResourceLabel{
+		LabelID:      1,
+		ResourceName: oldName + ":latest",
+		ResourceType: common.ResourceTypeImage,
+	})
+	require.Nil(t, err)
+	defer DeleteResourceLabel(id)
+
+	require.Nil(t, SetTagPullTimes([]*models.TagPullTime{
+		{Repository: oldName, Tag: "latest", PullTime: time.Now()},
+	}))
+	defer DeleteTagPullTime(newName, "latest")
+
+	// nonexistent repository
+	assert.NotNil(t, RenameRepository("library/nonexistent-repository", newName, 1))
+
+	require.Nil(t, RenameRepository(oldName, newName, 1))
+	assert.False(t, RepositoryExists(oldName))
+	assert.True(t, RepositoryExists(newName))
+
+	rl, err := GetResourceLabel(common.ResourceTypeImage, newName+":latest", 1)
+	require.Nil(t, err)
+	assert.NotNil(t, rl)
+
+	pt, err := GetTagPullTime(newName, "latest")
+	require.Nil(t, err)
+	assert.NotNil(t, pt)
+
+	// the new name is used
+	require.Nil(t, AddRepository(models.RepoRecord{
+		Name:      oldName,
+		ProjectID: 1,
+	}))
+	assert.Equal(t, ErrDupRows, RenameRepository(oldName, newName, 1))
+}
+
+func TestRepoRedirect(t *testing.T) {
+	oldName := "library/repo-redirect-test"
+	newName := "library/repo-redirected-test"
+	defer DeleteRepoRedirects(oldName)
+
+	redirect, err := GetRepoRedirect(oldName)
+	require.Nil(t, err)
+	assert.Nil(t, redirect)
+
+	require.Nil(t, AddRepoRedirect(oldName, newName, time.Now().Add(time.Hour)))
+	redirect, err = GetRepoRedirect(oldName)
+	require.Nil(t, err)
+	require.NotNil(t, redirect)
+	assert.Equal(t, newName, redirect.NewName)
+
+	// rename again, the redirect of the original name follows
+	latestName := "library/repo-redirected-latest-test"
+	defer DeleteRepoRedirects(newName)
+	require.Nil(t, AddRepoRedirect(newName, latestName, time.Now().Add(time.Hour)))
+	redirect, err = GetRepoRedirect(oldName)
+	require.Nil(t, err)
+	require.NotNil(t, redirect)
+	assert.Equal(t, latestName, redirect.NewName)
+	sources, err := ListRepoRedirectSources()
+	require.Nil(t, err)
+	assert.Contains(t, sources, oldName)
+	assert.Contains(t, sources, newName)
+
+	// expired
+	require.Nil(t, AddRepoRedirect(oldName, newName, time.Now().Add(-time.Hour)))
+	redirect, err = GetRepoRedirect(oldName)
+	require.Nil(t, err)
+	assert.Nil(t, redirect)
+	sources, err = ListRepoRedirectSources()
+	require.Nil(t, err)
+	assert.NotContains(t, sources, oldName)
+	n, err := DeleteExpiredRepoRedirects()
+	require.Nil(t, err)
+	assert.True(t, n >= 1)
+}
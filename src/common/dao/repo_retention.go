@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetRepoRetention creates or updates the retention override of the repository
+func SetRepoRetention(retention *models.RepoRetention) error {
+	if err := retention.Marshal(); err != nil {
+		return err
+	}
+	now := time.Now()
+	sql := `insert into repository_retention (repository_name, mode, policy, creation_time, update_time) 
+		values (?, ?, ?, ?, ?) 
+		on conflict (repository_name) do update set mode = excluded.mode, policy = excluded.policy, 
+		update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, retention.RepositoryName, retention.Mode, retention.PolicyStr, now, now).Exec()
+	return err
+}
+
+// GetRepoRetention returns the retention override of the repository, nil is returned if the
+// repository follows the retention policy of the project
+func GetRepoRetention(repository string) (*models.RepoRetention, error) {
+	retention := &models.RepoRetention{
+		RepositoryName: repository,
+	}
+	if err := GetOrmer().Read(retention, "RepositoryName"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := retention.Unmarshal(); err != nil {
+		return nil, err
+	}
+	return retention, nil
+}
+
+// ListRepoRetentions returns the retention overrides of the repositories under the project
+func ListRepoRetentions(project string) ([]*models.RepoRetention, error) {
+	retentions := []*models.RepoRetention{}
+	_, err := GetOrmer().Raw(`select * from repository_retention where repository_name like ? order by repository_name`,
+		Escape(project)+"/%").QueryRows(&retentions)
+	if err != nil {
+		return nil, err
+	}
+	for _, retention := range retentions {
+		if err = retention.Unmarshal(); err != nil {
+			return nil, err
+		}
+	}
+	return retentions, nil
+}
+
+// DeleteRepoRetention deletes the retention override, the repository follows the policy of the project then
+func DeleteRepoRetention(repository string) error {
+	_, err := GetOrmer().QueryTable(&models.RepoRetention{}).
+		Filter("RepositoryName", repository).
+		Delete()
+	return err
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoRetention(t *testing.T) {
+	repoName := "library/repo-retention-test"
+	require.Nil(t, addRepository(&models.RepoRecord{
+		Name:      repoName,
+		ProjectID: 1,
+	}))
+	defer deleteRepository(repoName)
+
+	retention, err := GetRepoRetention(repoName)
+	require.Nil(t, err)
+	assert.Nil(t, retention)
+
+	// create
+	require.Nil(t, SetRepoRetention(&models.RepoRetention{
+		RepositoryName: repoName,
+		Mode:           models.RetentionModeOverride,
+		Policy: &models.RetentionPolicy{
+			KeepLatest: 10,
+			KeepTags:   []string{"v*"},
+		},
+	}))
+	retention, err = GetRepoRetention(repoName)
+	require.Nil(t, err)
+	require.NotNil(t, retention)
+	assert.Equal(t, models.RetentionModeOverride, retention.Mode)
+	require.NotNil(t, retention.Policy)
+	assert.Equal(t, 10, retention.Policy.KeepLatest)
+	assert.Equal(t, []string{"v*"}, retention.Policy.KeepTags)
+
+	retentions, err := ListRepoRetentions("library")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(retentions))
+	assert.Equal(t, repoName, retentions[0].RepositoryName)
+	require.NotNil(t, retentions[0].Policy)
+	assert.Equal(t, 10, retentions[0].Policy.KeepLatest)
+	retentions, err = ListRepoRetentions("lib")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(retentions))
+
+	// update
+	require.Nil(t, SetRepoRetention(&models.RepoRetention{
+		RepositoryName: repoName,
+		Mode:           models.RetentionModeDisabled,
+	}))
+	retention, err = GetRepoRetention(repoName)
+	require.Nil(t, err)
+	require.NotNil(t, retention)
+	assert.Equal(t, models.RetentionModeDisabled, retention.Mode)
+	assert.Nil(t, retention.Policy)
+
+	// delete
+	require.Nil(t, DeleteRepoRetention(repoName))
+	retention, err = GetRepoRetention(repoName)
+	require.Nil(t, err)
+	assert.Nil(t, retention)
+}
@@ -0,0 +1,150 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRepoStar stars the repository for the user and increases the star count of the repository
+func AddRepoStar(userID int, repository string) error {
+	o := GetOrmer()
+	if _, err := o.Insert(&models.RepoStar{
+		UserID:         userID,
+		RepositoryName: repository,
+		CreationTime:   time.Now(),
+	}); err != nil {
+		if isDupRecErr(err) {
+			return ErrDupRows
+		}
+		return err
+	}
+	_, err := o.QueryTable("repository").Filter("name", repository).Update(
+		orm.Params{
+			"star_count": orm.ColValue(orm.ColAdd, 1),
+		})
+	return err
+}
+
+// DeleteRepoStar unstars the repository for the user and decreases the star count of the repository
+func DeleteRepoStar(userID int, repository string) error {
+	o := GetOrmer()
+	n, err := o.QueryTable(&models.RepoStar{}).
+		Filter("UserID", userID).
+		Filter("RepositoryName", repository).
+		Delete()
+	if err != nil || n == 0 {
+		return err
+	}
+	_, err = o.QueryTable("repository").Filter("name", repository).Filter("star_count__gt", 0).Update(
+		orm.Params{
+			"star_count": orm.ColValue(orm.ColMinus, 1),
+		})
+	return err
+}
+
+// RepoStarred returns whether the user starred the repository
+func RepoStarred(userID int, repository string) bool {
+	return GetOrmer().QueryTable(&models.RepoStar{}).
+		Filter("UserID", userID).
+		Filter("RepositoryName", repository).
+		Exist()
+}
+
+// GetStarredRepositories returns the repositories starred by the user in the projects visible
+// to the query, i.e. the ones the user can still read
+func GetStarredRepositories(userID int, query *models.SearchQuery, page, size int64) ([]*models.RepoRecord, error) {
+	from, params := starredRepositoriesSQL(userID, query)
+	sql := `select r.repository_id, r.name, r.project_id, r.description, r.pull_count, 
+		r.star_count, r.pre_created, r.creation_time, r.update_time ` + from + `order by s.creation_time desc `
+	if size > 0 {
+		sql += `limit ? `
+		params = append(params, size)
+		if page > 0 {
+			sql += `offset ? `
+			params = append(params, size*(page-1))
+		}
+	}
+	repositories := []*models.RepoRecord{}
+	_, err := GetOrmer().Raw(sql, params).QueryRows(&repositories)
+	return repositories, err
+}
+
+// CountStarredRepositories returns the count of the repositories starred by the user in the
+// projects visible to the query
+func CountStarredRepositories(userID int, query *models.SearchQuery) (int64, error) {
+	from, params := starredRepositoriesSQL(userID, query)
+	var count int64
+	err := GetOrmer().Raw(`select count(*) `+from, params).QueryRow(&count)
+	return count, err
+}
+
+func starredRepositoriesSQL(userID int, query *models.SearchQuery) (string, []interface{}) {
+	condition, params := visibleProjectsCondition(query)
+	sql := `from repository r join repository_star s on r.name = s.repository_name 
+		join project p on r.project_id = p.project_id 
+		where s.user_id = ? and ` + condition + ` `
+	return sql, append([]interface{}{userID}, params...)
+}
+
+// SetRepoSubscription creates or updates the subscription of the user to the repository
+func SetRepoSubscription(sub *models.RepoSubscription) error {
+	sub.Marshal()
+	now := time.Now()
+	sql := `insert into repository_subscription (user_id, repository_name, events, creation_time, update_time) 
+		values (?, ?, ?, ?, ?) 
+		on conflict (user_id, repository_name) do update set events = excluded.events, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, sub.UserID, sub.RepositoryName, sub.EventsStr, now, now).Exec()
+	return err
+}
+
+// GetRepoSubscription returns the subscription of the user to the repository, nil is returned if not found
+func GetRepoSubscription(userID int, repository string) (*models.RepoSubscription, error) {
+	sub := &models.RepoSubscription{
+		UserID:         userID,
+		RepositoryName: repository,
+	}
+	if err := GetOrmer().Read(sub, "UserID", "RepositoryName"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sub.Unmarshal()
+	return sub, nil
+}
+
+// DeleteRepoSubscription ...
+func DeleteRepoSubscription(userID int, repository string) error {
+	_, err := GetOrmer().QueryTable(&models.RepoSubscription{}).
+		Filter("UserID", userID).
+		Filter("RepositoryName", repository).
+		Delete()
+	return err
+}
+
+// GetRepoSubscribers returns the users who subscribe the event of the repository
+func GetRepoSubscribers(repository, event string) ([]*models.User, error) {
+	sql := `select u.user_id, u.username, u.email, u.realname, u.sysadmin_flag 
+		from harbor_user u join repository_subscription s on u.user_id = s.user_id 
+		where s.repository_name = ? and u.deleted = false 
+		and (',' || s.events || ',') like ?`
+	users := []*models.User{}
+	_, err := GetOrmer().Raw(sql, repository, "%,"+event+",%").QueryRows(&users)
+	return users, err
+}
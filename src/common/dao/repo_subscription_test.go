@@ -0,0 +1,107 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoStar(t *testing.T) {
+	repoName := "library/repo-star-test"
+	require.Nil(t, addRepository(&models.RepoRecord{
+		Name:      repoName,
+		ProjectID: 1,
+	}))
+	defer deleteRepository(repoName)
+
+	require.Nil(t, AddRepoStar(1, repoName))
+	assert.Equal(t, ErrDupRows, AddRepoStar(1, repoName))
+	assert.True(t, RepoStarred(1, repoName))
+
+	repo, err := GetRepositoryByName(repoName)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), repo.StarCount)
+
+	query := &models.SearchQuery{Username: "admin"}
+	total, err := CountStarredRepositories(1, query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	repos, err := GetStarredRepositories(1, query, 1, 10)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(repos))
+	assert.Equal(t, repoName, repos[0].Name)
+	repos, err = GetStarredRepositories(1, query, 2, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(repos))
+
+	// the project isn't readable anymore
+	require.Nil(t, UpdateProjectMetadata(&models.ProjectMetadata{ProjectID: 1, Name: "public", Value: "false"}))
+	query = &models.SearchQuery{Username: "non-member"}
+	total, err = CountStarredRepositories(1, query)
+	UpdateProjectMetadata(&models.ProjectMetadata{ProjectID: 1, Name: "public", Value: "true"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	require.Nil(t, DeleteRepoStar(1, repoName))
+	assert.False(t, RepoStarred(1, repoName))
+	repo, err = GetRepositoryByName(repoName)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), repo.StarCount)
+}
+
+func TestRepoSubscription(t *testing.T) {
+	repoName := "library/repo-subscription-test"
+	require.Nil(t, addRepository(&models.RepoRecord{
+		Name:      repoName,
+		ProjectID: 1,
+	}))
+	defer deleteRepository(repoName)
+
+	sub, err := GetRepoSubscription(1, repoName)
+	require.Nil(t, err)
+	assert.Nil(t, sub)
+
+	// create
+	require.Nil(t, SetRepoSubscription(&models.RepoSubscription{
+		UserID:         1,
+		RepositoryName: repoName,
+		Events:         []string{models.SubscriptionEventNewTag},
+	}))
+	sub, err = GetRepoSubscription(1, repoName)
+	require.Nil(t, err)
+	require.NotNil(t, sub)
+	assert.Equal(t, []string{models.SubscriptionEventNewTag}, sub.Events)
+
+	// update
+	require.Nil(t, SetRepoSubscription(&models.RepoSubscription{
+		UserID:         1,
+		RepositoryName: repoName,
+		Events:         []string{models.SubscriptionEventNewTag, models.SubscriptionEventCriticalCVE},
+	}))
+	users, err := GetRepoSubscribers(repoName, models.SubscriptionEventCriticalCVE)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(users))
+	assert.Equal(t, 1, users[0].UserID)
+
+	// delete
+	require.Nil(t, DeleteRepoSubscription(1, repoName))
+	users, err = GetRepoSubscribers(repoName, models.SubscriptionEventNewTag)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(users))
+}
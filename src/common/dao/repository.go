@@ -114,6 +114,20 @@ func GetTopRepos(projectIDs []int64, n int) ([]*models.RepoRecord, error) {
 	return repositories, err
 }
 
+// GetProjectRepositoriesAfter returns at most size repositories of the project whose IDs are
+// greater than the specified one ordered by ID, it's used to walk through the repositories
+// of the huge projects in batches without the cost of the offset
+func GetProjectRepositoriesAfter(projectID, id int64, size int) ([]*models.RepoRecord, error) {
+	repositories := []*models.RepoRecord{}
+	_, err := GetOrmer().QueryTable(&models.RepoRecord{}).
+		Filter("project_id", projectID).
+		Filter("repository_id__gt", id).
+		OrderBy("repository_id").
+		Limit(size).
+		All(&repositories)
+	return repositories, err
+}
+
 // GetTotalOfRepositories ...
 func GetTotalOfRepositories(query ...*models.RepositoryQuery) (int64, error) {
 	sql, params := repositoryQueryConditions(query...)
@@ -125,6 +139,12 @@ func GetTotalOfRepositories(query ...*models.RepositoryQuery) (int64, error) {
 	return total, nil
 }
 
+// GetCappedTotalOfRepositories returns the total of the repositories but stops counting at max+1
+func GetCappedTotalOfRepositories(max int64, query ...*models.RepositoryQuery) (int64, error) {
+	conditions, params := repositoryQueryConditions(query...)
+	return cappedCountForRawSQL(conditions, params, max)
+}
+
 // GetRepositories ...
 func GetRepositories(query ...*models.RepositoryQuery) ([]*models.RepoRecord, error) {
 	repositories := []*models.RepoRecord{}
@@ -137,7 +157,7 @@ func GetRepositories(query ...*models.RepositoryQuery) ([]*models.RepoRecord, er
 
 	condition, params := repositoryQueryConditions(query...)
 	sql := fmt.Sprintf(`select r.repository_id, r.name, r.project_id, r.description, r.pull_count, 
-	r.star_count, r.creation_time, r.update_time %s order by r.%s `, condition, order)
+	r.star_count, r.pre_created, r.creation_time, r.update_time %s order by r.%s `, condition, order)
 	if len(query) > 0 && query[0] != nil {
 		page, size := query[0].Page, query[0].Size
 		if size > 0 {
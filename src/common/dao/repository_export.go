@@ -0,0 +1,102 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRepositoryExport ...
+func AddRepositoryExport(export *models.RepositoryExport) (int64, error) {
+	now := time.Now()
+	export.CreationTime = now
+	export.UpdateTime = now
+	return GetOrmer().Insert(export)
+}
+
+// UpdateRepositoryExportStatus updates the status, message, the count of the exported
+// repositories and the size of the dump
+func UpdateRepositoryExportStatus(id int64, status, message string, count, size int64) error {
+	_, err := GetOrmer().QueryTable(&models.RepositoryExport{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"Status":          status,
+			"Message":         message,
+			"RepositoryCount": count,
+			"Size":            size,
+			"UpdateTime":      time.Now(),
+		})
+	return err
+}
+
+// GetRepositoryExport returns the export specified by ID, nil is returned if not found
+func GetRepositoryExport(id int64) (*models.RepositoryExport, error) {
+	export := &models.RepositoryExport{
+		ID: id,
+	}
+	if err := GetOrmer().Read(export); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// ListRepositoryExports lists the exports according to the query conditions, the latest one is the first
+func ListRepositoryExports(query *models.RepositoryExportQuery) ([]*models.RepositoryExport, error) {
+	qs := getRepositoryExportQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	exports := []*models.RepositoryExport{}
+	_, err := qs.All(&exports)
+	return exports, err
+}
+
+// CountRepositoryExports ...
+func CountRepositoryExports(query *models.RepositoryExportQuery) (int64, error) {
+	return getRepositoryExportQuerySetter(query).Count()
+}
+
+// DeleteRepositoryExport ...
+func DeleteRepositoryExport(id int64) error {
+	_, err := GetOrmer().Delete(&models.RepositoryExport{
+		ID: id,
+	})
+	return err
+}
+
+func getRepositoryExportQuerySetter(query *models.RepositoryExportQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.RepositoryExport{})
+	if query == nil {
+		return qs
+	}
+	if query.ProjectID > 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
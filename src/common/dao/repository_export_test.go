@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryExport(t *testing.T) {
+	id, err := AddRepositoryExport(&models.RepositoryExport{
+		ProjectID: 1,
+		Format:    models.RepositoryExportCSV,
+		Status:    models.RepositoryExportRunning,
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.RepositoryExportTable)
+
+	export, err := GetRepositoryExport(id)
+	require.Nil(t, err)
+	require.NotNil(t, export)
+	assert.Equal(t, models.RepositoryExportCSV, export.Format)
+	assert.Equal(t, models.RepositoryExportRunning, export.Status)
+
+	require.Nil(t, UpdateRepositoryExportStatus(id, models.RepositoryExportSucceeded, "", 10, 1024))
+	export, err = GetRepositoryExport(id)
+	require.Nil(t, err)
+	require.NotNil(t, export)
+	assert.Equal(t, models.RepositoryExportSucceeded, export.Status)
+	assert.Equal(t, int64(10), export.RepositoryCount)
+	assert.Equal(t, int64(1024), export.Size)
+
+	id2, err := AddRepositoryExport(&models.RepositoryExport{
+		ProjectID: 1,
+		Format:    models.RepositoryExportJSON,
+		Status:    models.RepositoryExportRunning,
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+
+	total, err := CountRepositoryExports(&models.RepositoryExportQuery{
+		ProjectID: 1,
+		Status:    models.RepositoryExportRunning,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	require.Nil(t, UpdateRepositoryExportStatus(id2, models.RepositoryExportFailed, "failed", 0, 0))
+	export, err = GetRepositoryExport(id2)
+	require.Nil(t, err)
+	require.NotNil(t, export)
+	assert.Equal(t, models.RepositoryExportFailed, export.Status)
+
+	exports, err := ListRepositoryExports(&models.RepositoryExportQuery{
+		ProjectID: 1,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(exports))
+	assert.Equal(t, id2, exports[0].ID)
+
+	require.Nil(t, DeleteRepositoryExport(id))
+	export, err = GetRepositoryExport(id)
+	require.Nil(t, err)
+	assert.Nil(t, export)
+}
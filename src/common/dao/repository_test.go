@@ -46,6 +46,27 @@ func TestGetTotalOfRepositories(t *testing.T) {
 	assert.Equal(t, total+1, n)
 }
 
+func TestGetCappedTotalOfRepositories(t *testing.T) {
+	err := addRepository(repository)
+	require.Nil(t, err)
+	defer deleteRepository(name)
+
+	query := &models.RepositoryQuery{
+		Name: name,
+	}
+	n, err := GetCappedTotalOfRepositories(10, query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	total, err := GetTotalOfRepositories()
+	require.Nil(t, err)
+	n, err = GetCappedTotalOfRepositories(0)
+	require.Nil(t, err)
+	if total > 0 {
+		assert.Equal(t, int64(1), n)
+	}
+}
+
 func TestGetRepositories(t *testing.T) {
 	// no query
 	repositories, err := GetRepositories()
@@ -122,6 +143,36 @@ func TestGetRepositories(t *testing.T) {
 	assert.Equal(t, name, repositories[0].Name)
 }
 
+func TestGetProjectRepositoriesAfter(t *testing.T) {
+	err := addRepository(repository)
+	require.Nil(t, err)
+	defer deleteRepository(name)
+
+	repositories, err := GetProjectRepositoriesAfter(1, 0, 1000)
+	require.Nil(t, err)
+	var id int64
+	for i, repository := range repositories {
+		if i > 0 {
+			assert.True(t, repository.RepositoryID > repositories[i-1].RepositoryID)
+		}
+		if repository.Name == name {
+			id = repository.RepositoryID
+		}
+	}
+	require.NotEqual(t, int64(0), id)
+
+	repositories, err = GetProjectRepositoriesAfter(1, id-1, 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(repositories))
+	assert.Equal(t, name, repositories[0].Name)
+
+	repositories, err = GetProjectRepositoriesAfter(1, id, 1000)
+	require.Nil(t, err)
+	for _, repository := range repositories {
+		assert.NotEqual(t, name, repository.Name)
+	}
+}
+
 func TestGetTopRepos(t *testing.T) {
 	var err error
 	require := require.New(t)
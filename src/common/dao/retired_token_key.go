@@ -0,0 +1,44 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRetiredTokenKey records the retired key, the expiration is extended if the key is
+// recorded already. The expired keys are removed at the same time
+func AddRetiredTokenKey(key *models.RetiredTokenKey) error {
+	now := time.Now()
+	if _, err := GetOrmer().Raw(`delete from retired_token_key where expires_at <= ?`, now).Exec(); err != nil {
+		return err
+	}
+	_, err := GetOrmer().Raw(`insert into retired_token_key (public_key, expires_at, creation_time) values (?, ?, ?)
+		on conflict (public_key) do update set expires_at = greatest(retired_token_key.expires_at, excluded.expires_at)`,
+		key.PublicKey, key.ExpiresAt, now).Exec()
+	return err
+}
+
+// ListRetiredTokenKeys lists the retired keys which haven't expired
+func ListRetiredTokenKeys() ([]*models.RetiredTokenKey, error) {
+	keys := []*models.RetiredTokenKey{}
+	_, err := GetOrmer().QueryTable(&models.RetiredTokenKey{}).
+		Filter("ExpiresAt__gt", time.Now()).
+		OrderBy("-ExpiresAt").
+		All(&keys)
+	return keys, err
+}
@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetiredTokenKey(t *testing.T) {
+	defer GetOrmer().Raw(`delete from retired_token_key`).Exec()
+
+	now := time.Now()
+	require.Nil(t, AddRetiredTokenKey(&models.RetiredTokenKey{
+		PublicKey: "key1",
+		ExpiresAt: now.Add(time.Hour),
+	}))
+	// the expiration is extended
+	require.Nil(t, AddRetiredTokenKey(&models.RetiredTokenKey{
+		PublicKey: "key1",
+		ExpiresAt: now.Add(2 * time.Hour),
+	}))
+	require.Nil(t, AddRetiredTokenKey(&models.RetiredTokenKey{
+		PublicKey: "key2",
+		ExpiresAt: now.Add(-time.Hour),
+	}))
+
+	keys, err := ListRetiredTokenKeys()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(keys))
+	assert.Equal(t, "key1", keys[0].PublicKey)
+	assert.True(t, keys[0].ExpiresAt.After(now.Add(time.Hour)))
+}
@@ -104,3 +104,25 @@ func DeleteRobot(id int64) error {
 	_, err := GetOrmer().QueryTable(&models.Robot{}).Filter("ID", id).Delete()
 	return err
 }
+
+// MoveRobot moves the robot account to another project
+func MoveRobot(id, projectID int64) error {
+	_, err := GetOrmer().QueryTable(&models.Robot{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"ProjectID":  projectID,
+			"UpdateTime": time.Now(),
+		})
+	return err
+}
+
+// UpdateRobotAccess updates the access granted to the robot
+func UpdateRobotAccess(id int64, access string) error {
+	_, err := GetOrmer().QueryTable(&models.Robot{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"Access":     access,
+			"UpdateTime": time.Now(),
+		})
+	return err
+}
@@ -0,0 +1,43 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetRobotReplica inserts or replaces the token of the robot re-issued by the target
+func SetRobotReplica(replica *models.RobotReplica) error {
+	now := time.Now()
+	sql := `insert into robot_replica (robot_id, target_id, token, creation_time, update_time) 
+		values (?, ?, ?, ?, ?) 
+		on conflict (robot_id, target_id) do update set token = excluded.token, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, replica.RobotID, replica.TargetID, replica.Token, now, now).Exec()
+	return err
+}
+
+// ListRobotReplicas returns the tokens of the robot re-issued by the targets, the ones of
+// all the robots are returned if the robot ID is 0
+func ListRobotReplicas(robotID int64) ([]*models.RobotReplica, error) {
+	qs := GetOrmer().QueryTable(&models.RobotReplica{})
+	if robotID > 0 {
+		qs = qs.Filter("RobotID", robotID)
+	}
+	replicas := []*models.RobotReplica{}
+	_, err := qs.OrderBy("RobotID", "TargetID").All(&replicas)
+	return replicas, err
+}
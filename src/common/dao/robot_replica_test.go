@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRobotReplica(t *testing.T) {
+	robotID, err := AddRobot(&models.Robot{
+		Name:      "robot$replica",
+		ProjectID: 1,
+	})
+	require.Nil(t, err)
+	defer DeleteRobot(robotID)
+
+	targetID, err := AddRepTarget(models.RepTarget{
+		Name: "robot_replica_target",
+		URL:  "https://dr.harbor.example.com",
+	})
+	require.Nil(t, err)
+	defer DeleteRepTarget(targetID)
+
+	require.Nil(t, SetRobotReplica(&models.RobotReplica{
+		RobotID:  robotID,
+		TargetID: targetID,
+		Token:    "token1",
+	}))
+	require.Nil(t, SetRobotReplica(&models.RobotReplica{
+		RobotID:  robotID,
+		TargetID: targetID,
+		Token:    "token2",
+	}))
+
+	replicas, err := ListRobotReplicas(robotID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(replicas))
+	assert.Equal(t, targetID, replicas[0].TargetID)
+	assert.Equal(t, "token2", replicas[0].Token)
+
+	// the replicas are removed with the robot
+	require.Nil(t, DeleteRobot(robotID))
+	replicas, err = ListRobotReplicas(robotID)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(replicas))
+}
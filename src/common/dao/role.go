@@ -33,6 +33,7 @@ func GetUserProjectRoles(userID int, projectID int64, entityType string) ([]mode
 				select role
 				from project_member
 				where project_id = ? and entity_id = ? and entity_type = 'u'
+				and (expiration_time is null or expiration_time > now())
 			)`
 
 	var roleList []models.Role
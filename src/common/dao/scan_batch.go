@@ -0,0 +1,71 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddScanBatch ...
+func AddScanBatch(batch *models.ScanBatch) (int64, error) {
+	now := time.Now()
+	batch.CreationTime = now
+	batch.UpdateTime = now
+	if len(batch.Status) == 0 {
+		batch.Status = models.ScanBatchExpanding
+	}
+	return GetOrmer().Insert(batch)
+}
+
+// GetScanBatch returns the batch specified by ID, nil is returned if not found
+func GetScanBatch(id int64) (*models.ScanBatch, error) {
+	batch := &models.ScanBatch{
+		ID: id,
+	}
+	if err := GetOrmer().Read(batch); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return batch, nil
+}
+
+// UpdateScanBatchStatus updates the status, the counts of the scans and the error of the batch
+func UpdateScanBatchStatus(batch *models.ScanBatch) error {
+	batch.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(batch, "Status", "Total", "Failed", "Error", "UpdateTime")
+	return err
+}
+
+// CountScanJobsByBatch returns the count of the scan jobs of the batch grouped by the status
+func CountScanJobsByBatch(batchID int64) (map[string]int64, error) {
+	rows := []*struct {
+		Status string
+		Count  int64
+	}{}
+	if _, err := GetOrmer().Raw(`select status, count(*) as count from img_scan_job
+		where batch_id = ? group by status`, batchID).QueryRows(&rows); err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanBatch(t *testing.T) {
+	id, err := AddScanBatch(&models.ScanBatch{
+		ProjectID:  1,
+		Repository: "library/*",
+		Creator:    "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.ScanBatchTable)
+
+	batch, err := GetScanBatch(id)
+	require.Nil(t, err)
+	require.NotNil(t, batch)
+	assert.Equal(t, models.ScanBatchExpanding, batch.Status)
+	assert.Equal(t, "library/*", batch.Repository)
+
+	batch.Status = models.ScanBatchSubmitted
+	batch.Total = 2
+	batch.Failed = 1
+	require.Nil(t, UpdateScanBatchStatus(batch))
+	batch, err = GetScanBatch(id)
+	require.Nil(t, err)
+	require.NotNil(t, batch)
+	assert.Equal(t, models.ScanBatchSubmitted, batch.Status)
+	assert.Equal(t, 2, batch.Total)
+	assert.Equal(t, 1, batch.Failed)
+
+	jobID, err := AddScanJob(models.ScanJob{
+		Repository: "library/hello-world",
+		Tag:        "latest",
+		BatchID:    id,
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.ScanJobTable)
+	require.Nil(t, UpdateScanJobStatus(jobID, models.JobRunning))
+
+	counts, err := CountScanJobsByBatch(id)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]int64{models.JobRunning: 1}, counts)
+
+	batch, err = GetScanBatch(id + 1)
+	require.Nil(t, err)
+	assert.Nil(t, batch)
+}
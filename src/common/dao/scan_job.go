@@ -99,6 +99,23 @@ func SetScanJobUUID(id int64, uuid string) error {
 
 }
 
+// CountScanJobsByStatus returns the count of scan jobs updated since the time grouped by the status
+func CountScanJobsByStatus(since time.Time) (map[string]int64, error) {
+	rows := []*struct {
+		Status string
+		Count  int64
+	}{}
+	if _, err := GetOrmer().Raw(`select status, count(*) as count from img_scan_job
+		where update_time >= ? group by status`, since).QueryRows(&rows); err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 func scanJobQs(limit ...int) orm.QuerySeter {
 	o := GetOrmer()
 	l := -1
@@ -0,0 +1,307 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// projectValue holds a value aggregated by project
+type projectValue struct {
+	ProjectID int64 `orm:"column(project_id)"`
+	Value     int64 `orm:"column(value)"`
+}
+
+// SearchProjects returns the visible projects whose names contain the keyword and
+// the total count of them, the metadata, repository count and the role of the
+// current user are populated
+func SearchProjects(query *models.SearchQuery) ([]*models.Project, int64, error) {
+	condition, params := visibleProjectsCondition(query)
+	sql := ` from project p where ` + condition
+	if len(query.Keyword) > 0 {
+		sql += ` and p.name like ?`
+		params = append(params, "%"+Escape(query.Keyword)+"%")
+	}
+
+	var total int64
+	if err := GetOrmer().Raw(`select count(*)`+sql, params).QueryRow(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql, params = searchLimit(query, `select p.project_id, p.name, p.owner_id, 
+		p.creation_time, p.update_time`+sql+` order by p.name`, params)
+	projects := []*models.Project{}
+	if _, err := GetOrmer().Raw(sql, params).QueryRows(&projects); err != nil {
+		return nil, 0, err
+	}
+	if err := populateSearchProjects(query, projects); err != nil {
+		return nil, 0, err
+	}
+	return projects, total, nil
+}
+
+// populateSearchProjects populates the metadata, repository count and the role
+// of the projects with one query for each of them rather than for each project
+func populateSearchProjects(query *models.SearchQuery, projects []*models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := []int64{}
+	projectMap := map[int64]*models.Project{}
+	for _, project := range projects {
+		ids = append(ids, project.ProjectID)
+		projectMap[project.ProjectID] = project
+	}
+	placeholder := paramPlaceholder(len(ids))
+
+	metas := []*models.ProjectMetadata{}
+	if _, err := GetOrmer().Raw(fmt.Sprintf(`select * from project_metadata 
+		where deleted = false and project_id in ( %s )`, placeholder), ids).QueryRows(&metas); err != nil {
+		return err
+	}
+	for _, meta := range metas {
+		projectMap[meta.ProjectID].SetMetadata(meta.Name, meta.Value)
+	}
+
+	counts := []*projectValue{}
+	if _, err := GetOrmer().Raw(fmt.Sprintf(`select project_id, count(*) as value from repository 
+		where project_id in ( %s ) group by project_id`, placeholder), ids).QueryRows(&counts); err != nil {
+		return err
+	}
+	for _, count := range counts {
+		projectMap[count.ProjectID].RepoCount = count.Value
+	}
+
+	if len(query.Username) == 0 && len(query.GroupDNCondition) == 0 {
+		return nil
+	}
+	// the role id is in descent order of the privilege except that of master,
+	// use min to select the max privilege role as GetRolesByLDAPGroup does
+	sql := fmt.Sprintf(`select pm.project_id, min(pm.role) as value from project_member pm 
+		left join harbor_user u on pm.entity_type = 'u' and pm.entity_id = u.user_id 
+		left join user_group ug on pm.entity_type = 'g' and pm.entity_id = ug.id and ug.group_type = 1 
+		where pm.project_id in ( %s ) 
+		and (pm.expiration_time is null or pm.expiration_time > now()) 
+		and (u.username = ?`, placeholder)
+	params := []interface{}{ids, query.Username}
+	if len(query.GroupDNCondition) > 0 {
+		sql += fmt.Sprintf(` or ug.ldap_group_dn in ( %s )`, query.GroupDNCondition)
+	}
+	sql += `) group by pm.project_id`
+	roles := []*projectValue{}
+	if _, err := GetOrmer().Raw(sql, params).QueryRows(&roles); err != nil {
+		return err
+	}
+	for _, role := range roles {
+		projectMap[role.ProjectID].Role = int(role.Value)
+	}
+	return nil
+}
+
+// SearchRepositories returns the repositories of the visible projects whose names
+// contain the keyword and the total count of them
+func SearchRepositories(query *models.SearchQuery) ([]*models.SearchRepository, int64, error) {
+	condition, params := visibleProjectsCondition(query)
+	sql := ` from repository r join project p on r.project_id = p.project_id where ` + condition
+	if len(query.Keyword) > 0 {
+		sql += ` and r.name like ?`
+		params = append(params, "%"+Escape(query.Keyword)+"%")
+	}
+
+	var total int64
+	if err := GetOrmer().Raw(`select count(*)`+sql, params).QueryRow(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql, params = searchLimit(query, `select r.name as repository_name, p.project_id, 
+		p.name as project_name, r.pull_count, exists (select 1 from project_metadata pmd 
+		where pmd.project_id = p.project_id and pmd.name = 'public' and pmd.value = 'true' 
+		and pmd.deleted = false) as project_public`+sql+` order by r.name`, params)
+	repositories := []*models.SearchRepository{}
+	if _, err := GetOrmer().Raw(sql, params).QueryRows(&repositories); err != nil {
+		return nil, 0, err
+	}
+	return repositories, total, nil
+}
+
+// SearchLabels returns the global labels and the labels of the visible projects
+// whose names contain the keyword and the total count of them
+func SearchLabels(query *models.SearchQuery) ([]*models.Label, int64, error) {
+	condition, params := visibleProjectsCondition(query)
+	sql := ` from harbor_label l left join project p on l.project_id = p.project_id 
+		where l.deleted = false and l.level = ? and (l.scope = ? or (l.scope = ? and ` + condition + `))`
+	params = append([]interface{}{common.LabelLevelUser, common.LabelScopeGlobal,
+		common.LabelScopeProject}, params...)
+	if len(query.Keyword) > 0 {
+		sql += ` and l.name like ?`
+		params = append(params, "%"+Escape(query.Keyword)+"%")
+	}
+
+	var total int64
+	if err := GetOrmer().Raw(`select count(*)`+sql, params).QueryRow(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql, params = searchLimit(query, `select l.*`+sql+` order by l.name, l.id`, params)
+	labels := []*models.Label{}
+	if _, err := GetOrmer().Raw(sql, params).QueryRows(&labels); err != nil {
+		return nil, 0, err
+	}
+	return labels, total, nil
+}
+
+// SearchUsers returns the users whose usernames or real names contain the keyword
+// and the total count of them. All the users are visible to the system admin, while
+// only the ones sharing a project with the user of the query are visible to others
+func SearchUsers(query *models.SearchQuery) ([]*models.SearchUser, int64, error) {
+	params := []interface{}{}
+	sql := ` from harbor_user where deleted = false and user_id > 1`
+	if !query.SysAdmin {
+		members, memberParams := memberProjectsSQL(query)
+		if len(members) == 0 {
+			return []*models.SearchUser{}, 0, nil
+		}
+		sql += ` and user_id in ( 
+			select pm.entity_id from project_member pm 
+			where pm.entity_type = 'u' and (pm.expiration_time is null or pm.expiration_time > now()) 
+			and pm.project_id in ( ` + strings.Join(members, ` union `) + ` ) )`
+		params = append(params, memberParams...)
+	}
+	if len(query.Keyword) > 0 {
+		sql += ` and (username like ? or realname like ?)`
+		keyword := "%" + Escape(query.Keyword) + "%"
+		params = append(params, keyword, keyword)
+	}
+
+	var total int64
+	if err := GetOrmer().Raw(`select count(*)`+sql, params).QueryRow(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql, params = searchLimit(query, `select user_id, username, realname`+sql+` order by username`, params)
+	users := []*models.SearchUser{}
+	if _, err := GetOrmer().Raw(sql, params).QueryRows(&users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// the documents of the full-text search, they must be the same as the expressions
+// of the indexes created in the migration to make use of them
+const (
+	repositoryDocument = `to_tsvector('simple', r.name || ' ' || coalesce(r.description, ''))`
+	annotationDocument = `to_tsvector('simple', a.repository || ' ' || a.tag || ' ' || 
+		coalesce(a.revision, '') || ' ' || coalesce(a.build_id, '') || ' ' || coalesce(a.labels, ''))`
+)
+
+// SearchArtifacts returns the repositories whose descriptions and the tags whose
+// annotations match the keyword in the full-text search and the total count of them,
+// the results are ordered by the rank
+func SearchArtifacts(query *models.SearchQuery) ([]*models.SearchArtifact, int64, error) {
+	if len(query.Keyword) == 0 {
+		return []*models.SearchArtifact{}, 0, nil
+	}
+	condition, conditionParams := visibleProjectsCondition(query)
+	sql := ` from (select r.name as repository_name, '' as tag, '' as digest, 
+		coalesce(r.description, '') as description, p.project_id, p.name as project_name, 
+		ts_rank(` + repositoryDocument + `, q) as rank 
+		from repository r join project p on r.project_id = p.project_id, plainto_tsquery('simple', ?) q 
+		where ` + condition + ` and ` + repositoryDocument + ` @@ q 
+		union all 
+		select a.repository as repository_name, a.tag, a.digest, '' as description, p.project_id, 
+		p.name as project_name, ts_rank(` + annotationDocument + `, q) as rank 
+		from artifact_annotation a join repository r on a.repository = r.name 
+		join project p on r.project_id = p.project_id, plainto_tsquery('simple', ?) q 
+		where ` + condition + ` and ` + annotationDocument + ` @@ q) t`
+	params := []interface{}{query.Keyword}
+	params = append(params, conditionParams...)
+	params = append(params, query.Keyword)
+	params = append(params, conditionParams...)
+
+	var total int64
+	if err := GetOrmer().Raw(`select count(*)`+sql, params).QueryRow(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql, params = searchLimit(query, `select *`+sql+` order by rank desc, repository_name, tag`, params)
+	artifacts := []*models.SearchArtifact{}
+	if _, err := GetOrmer().Raw(sql, params).QueryRows(&artifacts); err != nil {
+		return nil, 0, err
+	}
+	return artifacts, total, nil
+}
+
+// GetSearchProjectNames returns the names of all the visible projects
+func GetSearchProjectNames(query *models.SearchQuery) ([]string, error) {
+	condition, params := visibleProjectsCondition(query)
+	names := []string{}
+	_, err := GetOrmer().Raw(`select p.name from project p where `+condition+
+		` order by p.name`, params).QueryRows(&names)
+	return names, err
+}
+
+// visibleProjectsCondition returns the condition that selects the visible projects
+// in one statement, the project table must be aliased as "p"
+func visibleProjectsCondition(query *models.SearchQuery) (string, []interface{}) {
+	if query.SysAdmin {
+		return `p.deleted = false`, []interface{}{}
+	}
+	sql := `p.deleted = false and p.project_id in ( 
+		select pmd.project_id from project_metadata pmd 
+		where pmd.name = 'public' and pmd.value = 'true' and pmd.deleted = false`
+	members, params := memberProjectsSQL(query)
+	for _, member := range members {
+		sql += ` union ` + member
+	}
+	sql += ` )`
+	return sql, params
+}
+
+// memberProjectsSQL returns the statements that select the IDs of the projects which
+// the user of the query is the member of, they are joined by "union" by the callers
+func memberProjectsSQL(query *models.SearchQuery) ([]string, []interface{}) {
+	sqls := []string{}
+	params := []interface{}{}
+	if len(query.Username) > 0 {
+		sqls = append(sqls, `select pm.project_id from project_member pm 
+			join harbor_user u on pm.entity_type = 'u' and pm.entity_id = u.user_id 
+			where u.username = ? and (pm.expiration_time is null or pm.expiration_time > now())`)
+		params = append(params, query.Username)
+	}
+	if len(query.GroupDNCondition) > 0 {
+		sqls = append(sqls, fmt.Sprintf(`select pm.project_id from project_member pm 
+			join user_group ug on pm.entity_type = 'g' and pm.entity_id = ug.id and ug.group_type = 1 
+			where ug.ldap_group_dn in ( %s ) 
+			and (pm.expiration_time is null or pm.expiration_time > now())`, query.GroupDNCondition))
+	}
+	if len(query.ProjectIDs) > 0 {
+		sqls = append(sqls, fmt.Sprintf(`select project_id from project where project_id in ( %s )`,
+			paramPlaceholder(len(query.ProjectIDs))))
+		params = append(params, query.ProjectIDs)
+	}
+	return sqls, params
+}
+
+func searchLimit(query *models.SearchQuery, sql string, params []interface{}) (string, []interface{}) {
+	if query.Limit > 0 {
+		sql += ` limit ?`
+		params = append(params, query.Limit)
+	}
+	return sql, params
+}
@@ -0,0 +1,225 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	userID, err := Register(models.User{
+		Username: "dao-search-user",
+		Email:    "dao-search-user@example.com",
+		Password: "Harbor12345",
+		Realname: "dao search user",
+	})
+	require.Nil(t, err)
+	defer GetOrmer().Raw(`delete from harbor_user where user_id = ?`, userID).Exec()
+
+	publicID, err := AddProject(models.Project{
+		Name:    "dao-search-public",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer delProjPermanent(publicID)
+	require.Nil(t, AddProjectMetadata(&models.ProjectMetadata{
+		ProjectID: publicID,
+		Name:      models.ProMetaPublic,
+		Value:     "true",
+	}))
+	defer GetOrmer().Raw(`delete from project_metadata where project_id = ?`, publicID).Exec()
+
+	privateID, err := AddProject(models.Project{
+		Name:    "dao-search-private",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer delProjPermanent(privateID)
+	_, err = GetOrmer().Raw(`insert into project_member (project_id, entity_id, entity_type, role) 
+		values (?, ?, 'u', ?)`, privateID, userID, common.RoleDeveloper).Exec()
+	require.Nil(t, err)
+
+	require.Nil(t, AddRepository(models.RepoRecord{
+		ProjectID: publicID,
+		Name:      "dao-search-public/app",
+	}))
+	defer DeleteRepository("dao-search-public/app")
+	require.Nil(t, AddRepository(models.RepoRecord{
+		ProjectID: privateID,
+		Name:      "dao-search-private/app",
+	}))
+	defer DeleteRepository("dao-search-private/app")
+
+	labelID, err := AddLabel(&models.Label{
+		Name:      "dao-search-label",
+		Level:     common.LabelLevelUser,
+		Scope:     common.LabelScopeProject,
+		ProjectID: privateID,
+	})
+	require.Nil(t, err)
+	defer GetOrmer().Raw(`delete from harbor_label where id = ?`, labelID).Exec()
+
+	// anonymous
+	query := &models.SearchQuery{Keyword: "dao-search"}
+	projects, total, err := SearchProjects(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(projects))
+	assert.Equal(t, publicID, projects[0].ProjectID)
+	assert.True(t, projects[0].IsPublic())
+	assert.Equal(t, int64(1), projects[0].RepoCount)
+	assert.Equal(t, 0, projects[0].Role)
+
+	repositories, total, err := SearchRepositories(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(repositories))
+	assert.Equal(t, "dao-search-public/app", repositories[0].RepositoryName)
+	assert.Equal(t, "dao-search-public", repositories[0].ProjectName)
+	assert.True(t, repositories[0].ProjectPublic)
+
+	_, total, err = SearchLabels(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	// the member of the private project
+	query.Username = "dao-search-user"
+	query.Limit = 1
+	projects, total, err = SearchProjects(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Equal(t, 1, len(projects))
+	assert.Equal(t, privateID, projects[0].ProjectID)
+	assert.False(t, projects[0].IsPublic())
+	assert.Equal(t, common.RoleDeveloper, projects[0].Role)
+
+	labels, total, err := SearchLabels(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(labels))
+	assert.Equal(t, labelID, labels[0].ID)
+
+	names, err := GetSearchProjectNames(query)
+	require.Nil(t, err)
+	assert.Contains(t, names, "dao-search-private")
+
+	// the system admin
+	repositories, total, err = SearchRepositories(&models.SearchQuery{
+		Keyword:  "dao-search",
+		SysAdmin: true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, 2, len(repositories))
+
+	users, total, err := SearchUsers(&models.SearchQuery{
+		Keyword:  "dao search",
+		SysAdmin: true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(users))
+	assert.Equal(t, "dao-search-user", users[0].Username)
+
+	// the users are invisible to the anonymous
+	_, total, err = SearchUsers(&models.SearchQuery{Keyword: "dao search"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	// the users sharing the private project with the robot account
+	users, total, err = SearchUsers(&models.SearchQuery{
+		Keyword:    "dao search",
+		ProjectIDs: []int64{privateID},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(users))
+	assert.Equal(t, "dao-search-user", users[0].Username)
+
+	// the users of the other projects are invisible
+	_, total, err = SearchUsers(&models.SearchQuery{
+		Keyword:    "dao search",
+		ProjectIDs: []int64{publicID},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestSearchArtifacts(t *testing.T) {
+	projectID, err := AddProject(models.Project{
+		Name:    "dao-search-artifact",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer delProjPermanent(projectID)
+
+	require.Nil(t, AddRepository(models.RepoRecord{
+		ProjectID:   projectID,
+		Name:        "dao-search-artifact/billing",
+		Description: "The billing component, see JIRA-1234",
+	}))
+	defer DeleteRepository("dao-search-artifact/billing")
+	require.Nil(t, AddRepository(models.RepoRecord{
+		ProjectID: projectID,
+		Name:      "dao-search-artifact/app",
+	}))
+	defer DeleteRepository("dao-search-artifact/app")
+	require.Nil(t, SetArtifactAnnotation(&models.ArtifactAnnotation{
+		Repository: "dao-search-artifact/app",
+		Tag:        "v1",
+		Digest:     "sha256:1",
+		Labels:     "component=billing\nticket=JIRA-5678",
+	}))
+	defer DeleteArtifactAnnotation("dao-search-artifact/app", "v1")
+
+	// the project is private
+	artifacts, total, err := SearchArtifacts(&models.SearchQuery{Keyword: "billing"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Equal(t, 0, len(artifacts))
+
+	query := &models.SearchQuery{
+		Keyword:  "billing",
+		SysAdmin: true,
+	}
+	artifacts, total, err = SearchArtifacts(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, 2, len(artifacts))
+
+	query.Keyword = "JIRA-5678"
+	artifacts, total, err = SearchArtifacts(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(artifacts))
+	assert.Equal(t, "dao-search-artifact/app", artifacts[0].RepositoryName)
+	assert.Equal(t, "v1", artifacts[0].Tag)
+	assert.Equal(t, "sha256:1", artifacts[0].Digest)
+	assert.Equal(t, projectID, artifacts[0].ProjectID)
+
+	query.Keyword = "JIRA-1234"
+	query.Limit = 1
+	artifacts, total, err = SearchArtifacts(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, 1, len(artifacts))
+	assert.Equal(t, "dao-search-artifact/billing", artifacts[0].RepositoryName)
+	assert.Equal(t, "", artifacts[0].Tag)
+}
@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddShareLink adds the share link
+func AddShareLink(link *models.ShareLink) (int64, error) {
+	link.CreationTime = time.Now()
+	return GetOrmer().Insert(link)
+}
+
+// GetShareLink returns the share link by ID, nil is returned if not found
+func GetShareLink(id int64) (*models.ShareLink, error) {
+	link := &models.ShareLink{ID: id}
+	if err := GetOrmer().Read(link); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+// ListShareLinks lists the share links according to the query conditions, the latest one is the first
+func ListShareLinks(query *models.ShareLinkQuery) ([]*models.ShareLink, error) {
+	qs := getShareLinkQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil && query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	links := []*models.ShareLink{}
+	_, err := qs.All(&links)
+	return links, err
+}
+
+// CountShareLinks ...
+func CountShareLinks(query *models.ShareLinkQuery) (int64, error) {
+	return getShareLinkQuerySetter(query).Count()
+}
+
+func getShareLinkQuerySetter(query *models.ShareLinkQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.ShareLink{})
+	if query == nil {
+		return qs
+	}
+	if query.ProjectID > 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if len(query.Repository) > 0 {
+		qs = qs.Filter("Repository", query.Repository)
+	}
+	if query.Active {
+		qs = qs.Filter("Revoked", false).Filter("ExpiresAt__gt", time.Now())
+	}
+	return qs
+}
+
+// RevokeShareLink revokes the share link, the record is kept with the usage as the history
+func RevokeShareLink(id int64) error {
+	_, err := GetOrmer().Raw(`update share_link set revoked = true where id = ?`, id).Exec()
+	return err
+}
+
+// AddShareLinkPull counts a pull of the image through the share link
+func AddShareLinkPull(id int64, t time.Time) error {
+	_, err := GetOrmer().Raw(`update share_link set pull_count = pull_count + 1, last_pull_time = ? where id = ?`,
+		t, id).Exec()
+	return err
+}
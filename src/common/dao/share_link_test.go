@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLink(t *testing.T) {
+	defer ClearTable(models.ShareLinkTable)
+
+	id, err := AddShareLink(&models.ShareLink{
+		ProjectID:  1,
+		Repository: "library/share-link-test",
+		Reference:  "1.0",
+		Creator:    "admin",
+		ExpiresAt:  time.Now().Add(time.Hour),
+	})
+	require.Nil(t, err)
+	_, err = AddShareLink(&models.ShareLink{
+		ProjectID:  1,
+		Repository: "library/share-link-test",
+		Reference:  "2.0",
+		Creator:    "admin",
+		ExpiresAt:  time.Now().Add(-time.Hour),
+	})
+	require.Nil(t, err)
+
+	link, err := GetShareLink(id)
+	require.Nil(t, err)
+	require.NotNil(t, link)
+	assert.Equal(t, "1.0", link.Reference)
+	assert.Nil(t, link.LastPullTime)
+
+	link, err = GetShareLink(id + 100)
+	require.Nil(t, err)
+	assert.Nil(t, link)
+
+	require.Nil(t, AddShareLinkPull(id, time.Now()))
+	require.Nil(t, AddShareLinkPull(id, time.Now()))
+	link, err = GetShareLink(id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), link.PullCount)
+	assert.NotNil(t, link.LastPullTime)
+
+	total, err := CountShareLinks(&models.ShareLinkQuery{ProjectID: 1})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	// the expired one isn't active
+	links, err := ListShareLinks(&models.ShareLinkQuery{ProjectID: 1, Active: true})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(links))
+	assert.Equal(t, id, links[0].ID)
+
+	// the revoked link is kept
+	require.Nil(t, RevokeShareLink(id))
+	link, err = GetShareLink(id)
+	require.Nil(t, err)
+	assert.True(t, link.Revoked)
+	total, err = CountShareLinks(&models.ShareLinkQuery{ProjectID: 1, Active: true})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetRepoStorageHint creates or updates the storage hint override of the repository
+func SetRepoStorageHint(hint *models.RepoStorageHint) error {
+	if err := hint.Marshal(); err != nil {
+		return err
+	}
+	now := time.Now()
+	sql := `insert into repository_storage_hint (repository_name, mode, hint, creation_time, update_time) 
+		values (?, ?, ?, ?, ?) 
+		on conflict (repository_name) do update set mode = excluded.mode, hint = excluded.hint, 
+		update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, hint.RepositoryName, hint.Mode, hint.HintStr, now, now).Exec()
+	return err
+}
+
+// GetRepoStorageHint returns the storage hint override of the repository, nil is returned if the
+// repository follows the storage hint of the project
+func GetRepoStorageHint(repository string) (*models.RepoStorageHint, error) {
+	hint := &models.RepoStorageHint{
+		RepositoryName: repository,
+	}
+	if err := GetOrmer().Read(hint, "RepositoryName"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := hint.Unmarshal(); err != nil {
+		return nil, err
+	}
+	return hint, nil
+}
+
+// DeleteRepoStorageHint deletes the storage hint override, the repository follows the hint of the project then
+func DeleteRepoStorageHint(repository string) error {
+	_, err := GetOrmer().QueryTable(&models.RepoStorageHint{}).
+		Filter("RepositoryName", repository).
+		Delete()
+	return err
+}
+
+// AddBlobTransition records the blob transitioned to the storage class, the record of
+// the blob is updated if it has been transitioned before
+func AddBlobTransition(transition *models.BlobTransition) error {
+	sql := `insert into blob_transition (digest, storage_class, size, creation_time) values (?, ?, ?, ?) 
+		on conflict (digest) do update set storage_class = excluded.storage_class, size = excluded.size, 
+		creation_time = excluded.creation_time`
+	_, err := GetOrmer().Raw(sql, transition.Digest, transition.StorageClass, transition.Size, time.Now()).Exec()
+	return err
+}
+
+// ListBlobTransitions returns the transitioned blobs whose digests are in the list
+func ListBlobTransitions(digests ...string) ([]*models.BlobTransition, error) {
+	transitions := []*models.BlobTransition{}
+	if len(digests) == 0 {
+		return transitions, nil
+	}
+	_, err := GetOrmer().QueryTable(&models.BlobTransition{}).
+		Filter("Digest__in", digests).
+		All(&transitions)
+	return transitions, err
+}
+
+// SummarizeBlobTransitions returns the count and total size of the transitioned blobs per storage class
+func SummarizeBlobTransitions() ([]*models.StorageClassUsage, error) {
+	usages := []*models.StorageClassUsage{}
+	_, err := GetOrmer().Raw(`select storage_class, count(*) as count, coalesce(sum(size), 0) as size 
+		from blob_transition group by storage_class order by storage_class`).QueryRows(&usages)
+	return usages, err
+}
@@ -0,0 +1,108 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoStorageHint(t *testing.T) {
+	repoName := "library/repo-storage-hint-test"
+	require.Nil(t, addRepository(&models.RepoRecord{
+		Name:      repoName,
+		ProjectID: 1,
+	}))
+	defer deleteRepository(repoName)
+
+	hint, err := GetRepoStorageHint(repoName)
+	require.Nil(t, err)
+	assert.Nil(t, hint)
+
+	// create
+	require.Nil(t, SetRepoStorageHint(&models.RepoStorageHint{
+		RepositoryName: repoName,
+		Mode:           models.StorageHintModeOverride,
+		Hint: &models.StorageHint{
+			StorageClass: "STANDARD_IA",
+			AfterDays:    30,
+		},
+	}))
+	hint, err = GetRepoStorageHint(repoName)
+	require.Nil(t, err)
+	require.NotNil(t, hint)
+	assert.Equal(t, models.StorageHintModeOverride, hint.Mode)
+	require.NotNil(t, hint.Hint)
+	assert.Equal(t, "STANDARD_IA", hint.Hint.StorageClass)
+	assert.Equal(t, 30, hint.Hint.AfterDays)
+
+	// update
+	require.Nil(t, SetRepoStorageHint(&models.RepoStorageHint{
+		RepositoryName: repoName,
+		Mode:           models.StorageHintModeDisabled,
+	}))
+	hint, err = GetRepoStorageHint(repoName)
+	require.Nil(t, err)
+	require.NotNil(t, hint)
+	assert.Equal(t, models.StorageHintModeDisabled, hint.Mode)
+	assert.Nil(t, hint.Hint)
+
+	// delete
+	require.Nil(t, DeleteRepoStorageHint(repoName))
+	hint, err = GetRepoStorageHint(repoName)
+	require.Nil(t, err)
+	assert.Nil(t, hint)
+}
+
+func TestBlobTransition(t *testing.T) {
+	digest1 := "sha256:blob-transition-test-1"
+	digest2 := "sha256:blob-transition-test-2"
+	defer GetOrmer().Raw(`delete from blob_transition where digest in (?, ?)`, digest1, digest2).Exec()
+
+	require.Nil(t, AddBlobTransition(&models.BlobTransition{
+		Digest:       digest1,
+		StorageClass: "STANDARD_IA",
+		Size:         100,
+	}))
+	require.Nil(t, AddBlobTransition(&models.BlobTransition{
+		Digest:       digest2,
+		StorageClass: "STANDARD_IA",
+		Size:         200,
+	}))
+	// transitioned again
+	require.Nil(t, AddBlobTransition(&models.BlobTransition{
+		Digest:       digest2,
+		StorageClass: "GLACIER_IR",
+		Size:         200,
+	}))
+
+	transitions, err := ListBlobTransitions(digest1, digest2, "sha256:not-transitioned")
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(transitions))
+
+	usages, err := SummarizeBlobTransitions()
+	require.Nil(t, err)
+	classes := map[string]*models.StorageClassUsage{}
+	for _, usage := range usages {
+		classes[usage.StorageClass] = usage
+	}
+	require.NotNil(t, classes["STANDARD_IA"])
+	assert.True(t, classes["STANDARD_IA"].Size >= 100)
+	require.NotNil(t, classes["GLACIER_IR"])
+	assert.True(t, classes["GLACIER_IR"].Count >= 1)
+}
@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetTagAlias creates the alias or points it to another tag
+func SetTagAlias(alias *models.TagAlias) error {
+	now := time.Now()
+	sql := `insert into tag_alias (repository, alias, tag, digest, operator, creation_time, update_time)
+		values (?, ?, ?, ?, ?, ?, ?)
+		on conflict (repository, alias) do update set tag = excluded.tag, digest = excluded.digest,
+		operator = excluded.operator, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, alias.Repository, alias.Alias, alias.Tag, alias.Digest,
+		alias.Operator, now, now).Exec()
+	return err
+}
+
+// GetTagAlias returns the alias of the repository, nil is returned if the tag isn't an alias
+func GetTagAlias(repository, alias string) (*models.TagAlias, error) {
+	tagAlias := &models.TagAlias{
+		Repository: repository,
+		Alias:      alias,
+	}
+	if err := GetOrmer().Read(tagAlias, "Repository", "Alias"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return tagAlias, nil
+}
+
+// ListTagAliases returns the aliases of the repository ordered by the names
+func ListTagAliases(repository string) ([]*models.TagAlias, error) {
+	aliases := []*models.TagAlias{}
+	_, err := GetOrmer().QueryTable(&models.TagAlias{}).
+		Filter("Repository", repository).
+		OrderBy("Alias").
+		All(&aliases)
+	return aliases, err
+}
+
+// DeleteTagAlias deletes the alias, the tag isn't managed by the API anymore
+func DeleteTagAlias(repository, alias string) error {
+	_, err := GetOrmer().QueryTable(&models.TagAlias{}).
+		Filter("Repository", repository).
+		Filter("Alias", alias).
+		Delete()
+	return err
+}
+
+// DeleteTagAliasesByDigest deletes the aliases pointing to the digest, it's called when the
+// manifest is deleted as the alias tags are deleted together
+func DeleteTagAliasesByDigest(repository, digest string) error {
+	_, err := GetOrmer().QueryTable(&models.TagAlias{}).
+		Filter("Repository", repository).
+		Filter("Digest", digest).
+		Delete()
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagAlias(t *testing.T) {
+	repository := "library/tag-alias-test"
+	alias, err := GetTagAlias(repository, "stable")
+	require.Nil(t, err)
+	assert.Nil(t, alias)
+
+	require.Nil(t, SetTagAlias(&models.TagAlias{
+		Repository: repository,
+		Alias:      "stable",
+		Tag:        "v1.0",
+		Digest:     "sha256:1",
+		Operator:   "admin",
+	}))
+	defer DeleteTagAlias(repository, "stable")
+	require.Nil(t, SetTagAlias(&models.TagAlias{
+		Repository: repository,
+		Alias:      "edge",
+		Tag:        "v1.0",
+		Digest:     "sha256:1",
+		Operator:   "admin",
+	}))
+	defer DeleteTagAlias(repository, "edge")
+
+	// point the alias to another tag
+	require.Nil(t, SetTagAlias(&models.TagAlias{
+		Repository: repository,
+		Alias:      "stable",
+		Tag:        "v1.1",
+		Digest:     "sha256:2",
+		Operator:   "user",
+	}))
+	alias, err = GetTagAlias(repository, "stable")
+	require.Nil(t, err)
+	require.NotNil(t, alias)
+	assert.Equal(t, "v1.1", alias.Tag)
+	assert.Equal(t, "sha256:2", alias.Digest)
+	assert.Equal(t, "user", alias.Operator)
+
+	aliases, err := ListTagAliases(repository)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(aliases))
+	assert.Equal(t, "edge", aliases[0].Alias)
+	assert.Equal(t, "stable", aliases[1].Alias)
+
+	require.Nil(t, DeleteTagAliasesByDigest(repository, "sha256:1"))
+	alias, err = GetTagAlias(repository, "edge")
+	require.Nil(t, err)
+	assert.Nil(t, alias)
+
+	require.Nil(t, DeleteTagAlias(repository, "stable"))
+	aliases, err = ListTagAliases(repository)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(aliases))
+}
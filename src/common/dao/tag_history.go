@@ -0,0 +1,175 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddTagHistory ...
+func AddTagHistory(history *models.TagHistory) (int64, error) {
+	if history.OpTime.IsZero() {
+		history.OpTime = time.Now()
+	}
+	return GetOrmer().Insert(history)
+}
+
+// GetLatestTagHistory returns the latest history of the tag, nil is returned if the tag has no history
+func GetLatestTagHistory(repository, tag string) (*models.TagHistory, error) {
+	histories, err := ListTagHistories(&models.TagHistoryQuery{
+		Repository: repository,
+		Tag:        tag,
+		Pagination: models.Pagination{
+			Page: 1,
+			Size: 1,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		return nil, nil
+	}
+	return histories[0], nil
+}
+
+// DigestTagged returns whether any tag of the repository references the digest according to
+// the latest histories of the tags
+func DigestTagged(repository, digest string) (bool, error) {
+	var count int64
+	err := GetOrmer().Raw(`select count(*) from 
+		(select distinct on (tag) tag, digest, operation from tag_history 
+		where repository = ? order by tag, op_time desc, id desc) t 
+		where t.digest = ? and t.operation <> ?`,
+		repository, digest, models.TagHistoryDelete).QueryRow(&count)
+	return count > 0, err
+}
+
+// ListTagDigests returns the tags of the repository which aren't deleted according to their
+// latest histories and the digests they reference
+func ListTagDigests(repository string) (map[string]string, error) {
+	tags := []*struct {
+		Tag    string `orm:"column(tag)"`
+		Digest string `orm:"column(digest)"`
+	}{}
+	_, err := GetOrmer().Raw(`select tag, digest from 
+		(select distinct on (tag) tag, digest, operation from tag_history 
+		where repository = ? order by tag, op_time desc, id desc) t 
+		where t.operation <> ?`,
+		repository, models.TagHistoryDelete).QueryRows(&tags)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	for _, tag := range tags {
+		digests[tag.Tag] = tag.Digest
+	}
+	return digests, nil
+}
+
+// ListTagHistories lists the histories according to the query conditions, the latest one is the first
+func ListTagHistories(query *models.TagHistoryQuery) ([]*models.TagHistory, error) {
+	histories := []*models.TagHistory{}
+	qs := getTagHistoryQuerySetter(query).OrderBy("-OpTime", "-ID")
+	if query != nil && query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	_, err := qs.All(&histories)
+	return histories, err
+}
+
+// CountTagHistories ...
+func CountTagHistories(query *models.TagHistoryQuery) (int64, error) {
+	return getTagHistoryQuerySetter(query).Count()
+}
+
+func getTagHistoryQuerySetter(query *models.TagHistoryQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.TagHistory{})
+	if query == nil {
+		return qs
+	}
+	if len(query.Repository) > 0 {
+		qs = qs.Filter("Repository", query.Repository)
+	}
+	if len(query.Tag) > 0 {
+		qs = qs.Filter("Tag", query.Tag)
+	}
+	if !query.BeginTime.IsZero() {
+		qs = qs.Filter("OpTime__gte", query.BeginTime)
+	}
+	if !query.EndTime.IsZero() {
+		qs = qs.Filter("OpTime__lte", query.EndTime)
+	}
+	return qs
+}
+
+// ListMovedTags lists the tags of the project which are updated to new digests in the
+// period, the latest updated one is the first. The digest is the latest one
+func ListMovedTags(query *models.MovedTagQuery) ([]*models.MovedTag, error) {
+	sql, params := movedTagsSQL(query)
+	sql = `select repository, tag, count(*) as updates, 
+		(array_agg(digest order by op_time desc, id desc))[1] as digest, 
+		string_agg(distinct operator, ',') as operators, max(op_time) as update_time ` + sql + ` 
+		order by update_time desc, repository, tag`
+	if query.Size > 0 {
+		sql += ` limit ?`
+		params = append(params, query.Size)
+		if query.Page > 0 {
+			sql += ` offset ?`
+			params = append(params, (query.Page-1)*query.Size)
+		}
+	}
+
+	tags := []*models.MovedTag{}
+	if _, err := GetOrmer().Raw(sql, params...).QueryRows(&tags); err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		tag.Operators = []string{}
+		if len(tag.OperatorStr) > 0 {
+			tag.Operators = strings.Split(tag.OperatorStr, ",")
+		}
+	}
+	return tags, nil
+}
+
+// CountMovedTags ...
+func CountMovedTags(query *models.MovedTagQuery) (int64, error) {
+	sql, params := movedTagsSQL(query)
+	var count int64
+	err := GetOrmer().Raw(`select count(*) from (select 1 `+sql+`) as t`, params...).QueryRow(&count)
+	return count, err
+}
+
+func movedTagsSQL(query *models.MovedTagQuery) (string, []interface{}) {
+	sql := `from tag_history where operation = ? and repository like ?`
+	params := []interface{}{models.TagHistoryUpdate, Escape(query.Project) + "/%"}
+	if !query.BeginTime.IsZero() {
+		sql += ` and op_time >= ?`
+		params = append(params, query.BeginTime)
+	}
+	if !query.EndTime.IsZero() {
+		sql += ` and op_time <= ?`
+		params = append(params, query.EndTime)
+	}
+	return sql + ` group by repository, tag`, params
+}
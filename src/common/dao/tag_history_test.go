@@ -0,0 +1,134 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagHistory(t *testing.T) {
+	repository := "library/tag-history-test"
+	defer GetOrmer().QueryTable(&models.TagHistory{}).Filter("Repository", repository).Delete()
+
+	history, err := GetLatestTagHistory(repository, "latest")
+	require.Nil(t, err)
+	assert.Nil(t, history)
+
+	now := time.Now()
+	for i, h := range []*models.TagHistory{
+		{Digest: "sha256:1", Operation: models.TagHistoryCreate, OpTime: now.Add(-2 * time.Hour)},
+		{Digest: "sha256:2", Operation: models.TagHistoryUpdate, OpTime: now.Add(-time.Hour)},
+		{Digest: "sha256:2", Operation: models.TagHistoryDelete, OpTime: now},
+	} {
+		h.Repository = repository
+		h.Tag = "latest"
+		h.Operator = "admin"
+		_, err = AddTagHistory(h)
+		require.Nil(t, err, "history %d", i)
+	}
+	_, err = AddTagHistory(&models.TagHistory{
+		Repository: repository,
+		Tag:        "v1",
+		Digest:     "sha256:1",
+		Operation:  models.TagHistoryCreate,
+	})
+	require.Nil(t, err)
+
+	history, err = GetLatestTagHistory(repository, "latest")
+	require.Nil(t, err)
+	require.NotNil(t, history)
+	assert.Equal(t, models.TagHistoryDelete, history.Operation)
+
+	query := &models.TagHistoryQuery{
+		Repository: repository,
+		Tag:        "latest",
+	}
+	total, err := CountTagHistories(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+
+	// the digest which the tag pointed to at the specified time
+	query.EndTime = now.Add(-30 * time.Minute)
+	query.Page, query.Size = 1, 1
+	histories, err := ListTagHistories(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(histories))
+	assert.Equal(t, "sha256:2", histories[0].Digest)
+
+	query.EndTime = time.Time{}
+	query.BeginTime = now.Add(-90 * time.Minute)
+	query.Size = 0
+	histories, err = ListTagHistories(query)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(histories))
+
+	// "latest" is deleted while "v1" still references sha256:1
+	tagged, err := DigestTagged(repository, "sha256:1")
+	require.Nil(t, err)
+	assert.True(t, tagged)
+	tagged, err = DigestTagged(repository, "sha256:2")
+	require.Nil(t, err)
+	assert.False(t, tagged)
+
+	digests, err := ListTagDigests(repository)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"v1": "sha256:1"}, digests)
+}
+
+func TestMovedTags(t *testing.T) {
+	repository := "moved_tags/test"
+	defer GetOrmer().QueryTable(&models.TagHistory{}).Filter("Repository__startswith", "moved").Delete()
+
+	now := time.Now()
+	for _, h := range []*models.TagHistory{
+		{Repository: repository, Tag: "v1", Digest: "sha256:1", Operation: models.TagHistoryCreate, Operator: "a"},
+		{Repository: repository, Tag: "v1", Digest: "sha256:2", Operation: models.TagHistoryUpdate, Operator: "b", OpTime: now.Add(-time.Hour)},
+		{Repository: repository, Tag: "v1", Digest: "sha256:3", Operation: models.TagHistoryUpdate, Operator: "c"},
+		{Repository: repository, Tag: "v2", Digest: "sha256:1", Operation: models.TagHistoryUpdate, Operator: "a", OpTime: now.Add(-time.Minute)},
+		// the "_" in the project name isn't a wildcard
+		{Repository: "movedxtags/test", Tag: "v1", Digest: "sha256:1", Operation: models.TagHistoryUpdate, Operator: "a"},
+	} {
+		_, err := AddTagHistory(h)
+		require.Nil(t, err)
+	}
+
+	query := &models.MovedTagQuery{
+		Project:   "moved_tags",
+		BeginTime: now.Add(-2 * time.Hour),
+	}
+	total, err := CountMovedTags(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+
+	query.Page, query.Size = 1, 1
+	tags, err := ListMovedTags(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tags))
+	assert.Equal(t, "v1", tags[0].Tag)
+	assert.Equal(t, int64(2), tags[0].Updates)
+	assert.Equal(t, "sha256:3", tags[0].Digest)
+	assert.ElementsMatch(t, []string{"b", "c"}, tags[0].Operators)
+
+	query.BeginTime = now.Add(-30 * time.Minute)
+	query.Size = 0
+	tags, err = ListMovedTags(query)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(tags))
+}
@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetTagPullTimes inserts or updates the pull time of the tags in one statement,
+// the pull time is only updated when it is later than the stored one
+func SetTagPullTimes(pullTimes []*models.TagPullTime) error {
+	if len(pullTimes) == 0 {
+		return nil
+	}
+	values := []string{}
+	params := []interface{}{}
+	for _, pt := range pullTimes {
+		values = append(values, "(?, ?, ?)")
+		params = append(params, pt.Repository, pt.Tag, pt.PullTime)
+	}
+	sql := `insert into tag_pull_time (repository, tag, pull_time) 
+		values ` + strings.Join(values, ", ") + ` 
+		on conflict (repository, tag) do update set pull_time = greatest(tag_pull_time.pull_time, excluded.pull_time)`
+	_, err := GetOrmer().Raw(sql, params...).Exec()
+	return err
+}
+
+// GetTagPullTime returns the last pull time of the tag, nil is returned if the tag has never been pulled
+func GetTagPullTime(repository, tag string) (*models.TagPullTime, error) {
+	pt := &models.TagPullTime{
+		Repository: repository,
+		Tag:        tag,
+	}
+	if err := GetOrmer().Read(pt, "Repository", "Tag"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pt, nil
+}
+
+// DeleteTagPullTime ...
+func DeleteTagPullTime(repository, tag string) error {
+	_, err := GetOrmer().QueryTable(&models.TagPullTime{}).
+		Filter("Repository", repository).
+		Filter("Tag", tag).
+		Delete()
+	return err
+}
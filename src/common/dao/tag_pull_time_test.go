@@ -0,0 +1,60 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagPullTime(t *testing.T) {
+	repository := "library/tag-pull-time-test"
+	pt, err := GetTagPullTime(repository, "latest")
+	require.Nil(t, err)
+	assert.Nil(t, pt)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	require.Nil(t, SetTagPullTimes([]*models.TagPullTime{
+		{Repository: repository, Tag: "latest", PullTime: now},
+		{Repository: repository, Tag: "v1", PullTime: now},
+	}))
+	defer DeleteTagPullTime(repository, "latest")
+	defer DeleteTagPullTime(repository, "v1")
+
+	// the earlier pull time is ignored
+	require.Nil(t, SetTagPullTimes([]*models.TagPullTime{
+		{Repository: repository, Tag: "latest", PullTime: now.Add(-time.Hour)},
+		{Repository: repository, Tag: "v1", PullTime: now.Add(time.Hour)},
+	}))
+
+	pt, err = GetTagPullTime(repository, "latest")
+	require.Nil(t, err)
+	require.NotNil(t, pt)
+	assert.True(t, now.Equal(pt.PullTime))
+
+	pt, err = GetTagPullTime(repository, "v1")
+	require.Nil(t, err)
+	require.NotNil(t, pt)
+	assert.True(t, now.Add(time.Hour).Equal(pt.PullTime))
+
+	require.Nil(t, DeleteTagPullTime(repository, "v1"))
+	pt, err = GetTagPullTime(repository, "v1")
+	require.Nil(t, err)
+	assert.Nil(t, pt)
+}
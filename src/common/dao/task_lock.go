@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"errors"
+
+	"github.com/astaxie/beego/orm"
+)
+
+// ErrResourceLocked is returned when the resource is locked by another task
+var ErrResourceLocked = errors.New("the resource is locked by another task")
+
+// LockResources locks the resources for the task in a transaction, ErrResourceLocked is
+// returned and none of them is locked if any is locked by another task. The locks held
+// by the task already are kept
+func LockResources(owner string, resources ...string) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if _, err := o.Raw(`insert into task_lock (resource, owner) values (?, ?)
+			on conflict (resource) do nothing`, resource, owner).Exec(); err != nil {
+			o.Rollback()
+			return err
+		}
+		var holder string
+		if err := o.Raw(`select owner from task_lock where resource = ?`, resource).QueryRow(&holder); err != nil {
+			o.Rollback()
+			return err
+		}
+		if holder != owner {
+			o.Rollback()
+			return ErrResourceLocked
+		}
+	}
+	return o.Commit()
+}
+
+// UnlockResources releases the locks held by the task
+func UnlockResources(owner string) error {
+	_, err := GetOrmer().Raw(`delete from task_lock where owner = ?`, owner).Exec()
+	return err
+}
@@ -0,0 +1,38 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLock(t *testing.T) {
+	require.Nil(t, LockResources("TEST:1", "project:1", "project:2"))
+	defer UnlockResources("TEST:1")
+	// locked again by the same task
+	require.Nil(t, LockResources("TEST:1", "project:1"))
+
+	// none is locked if any is held by another task
+	assert.Equal(t, ErrResourceLocked, LockResources("TEST:2", "project:3", "project:2"))
+	require.Nil(t, LockResources("TEST:3", "project:3"))
+	require.Nil(t, UnlockResources("TEST:3"))
+
+	require.Nil(t, UnlockResources("TEST:1"))
+	require.Nil(t, LockResources("TEST:2", "project:1"))
+	require.Nil(t, UnlockResources("TEST:2"))
+}
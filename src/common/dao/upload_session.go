@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddUploadSession ...
+func AddUploadSession(session *models.UploadSession) (int64, error) {
+	now := time.Now()
+	session.CreationTime = now
+	session.UpdateTime = now
+	id, err := GetOrmer().Insert(session)
+	if err != nil {
+		if isDupRecErr(err) {
+			return 0, ErrDupRows
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// TouchUploadSession refreshes the update time and the state of the upload session
+func TouchUploadSession(uuid, state string) error {
+	_, err := GetOrmer().QueryTable(&models.UploadSession{}).
+		Filter("UUID", uuid).
+		Update(orm.Params{
+			"State":      state,
+			"UpdateTime": time.Now(),
+		})
+	return err
+}
+
+// DeleteUploadSession ...
+func DeleteUploadSession(uuid string) error {
+	_, err := GetOrmer().QueryTable(&models.UploadSession{}).Filter("UUID", uuid).Delete()
+	return err
+}
+
+// ListUploadSessions lists the upload sessions according to the query conditions
+func ListUploadSessions(query *models.UploadSessionQuery) ([]*models.UploadSession, error) {
+	qs := getUploadSessionQuerySetter(query).OrderBy("UpdateTime")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	sessions := []*models.UploadSession{}
+	_, err := qs.All(&sessions)
+	return sessions, err
+}
+
+// CountUploadSessions ...
+func CountUploadSessions(query *models.UploadSessionQuery) (int64, error) {
+	return getUploadSessionQuerySetter(query).Count()
+}
+
+func getUploadSessionQuerySetter(query *models.UploadSessionQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.UploadSession{})
+	if query == nil {
+		return qs
+	}
+	if len(query.Repository) > 0 {
+		qs = qs.Filter("Repository", query.Repository)
+	}
+	if query.UpdatedBefore != nil {
+		qs = qs.Filter("UpdateTime__lt", *query.UpdatedBefore)
+	}
+	return qs
+}
@@ -0,0 +1,71 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadSession(t *testing.T) {
+	uuid := "0f2d6c3e-upload-session-test"
+	_, err := AddUploadSession(&models.UploadSession{
+		UUID:       uuid,
+		Repository: "library/upload-session-test",
+	})
+	require.Nil(t, err)
+	defer DeleteUploadSession(uuid)
+
+	_, err = AddUploadSession(&models.UploadSession{
+		UUID:       uuid,
+		Repository: "library/upload-session-test",
+	})
+	assert.Equal(t, ErrDupRows, err)
+
+	query := &models.UploadSessionQuery{
+		Repository: "library/upload-session-test",
+	}
+	total, err := CountUploadSessions(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// not stale
+	before := time.Now().Add(-time.Hour)
+	query.UpdatedBefore = &before
+	sessions, err := ListUploadSessions(query)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(sessions))
+
+	// stale
+	after := time.Now().Add(time.Hour)
+	query.UpdatedBefore = &after
+	sessions, err = ListUploadSessions(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(sessions))
+	assert.Equal(t, uuid, sessions[0].UUID)
+
+	require.Nil(t, TouchUploadSession(uuid, "state"))
+
+	require.Nil(t, DeleteUploadSession(uuid))
+	total, err = CountUploadSessions(&models.UploadSessionQuery{
+		Repository: "library/upload-session-test",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+}
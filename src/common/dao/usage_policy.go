@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// GetUsagePolicy returns the usage policy of the project, nil is returned if the project has none
+func GetUsagePolicy(projectID int64) (*models.UsagePolicy, error) {
+	policy := &models.UsagePolicy{
+		ProjectID: projectID,
+	}
+	if err := GetOrmer().Read(policy); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// SetUsagePolicy creates or updates the usage policy of the project, the acknowledgments
+// of the previous policy are removed so the users must acknowledge the new one again
+func SetUsagePolicy(projectID int64, policy string) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if _, err := o.Raw(`insert into usage_policy (project_id, policy, creation_time, update_time) 
+		values (?, ?, ?, ?) 
+		on conflict (project_id) do update set policy = excluded.policy, update_time = excluded.update_time`,
+		projectID, policy, now, now).Exec(); err != nil {
+		o.Rollback()
+		return err
+	}
+	if _, err := o.QueryTable(&models.UsagePolicyAck{}).Filter("ProjectID", projectID).Delete(); err != nil {
+		o.Rollback()
+		return err
+	}
+	return o.Commit()
+}
+
+// DeleteUsagePolicy removes the usage policy of the project together with its acknowledgments
+func DeleteUsagePolicy(projectID int64) error {
+	_, err := GetOrmer().Delete(&models.UsagePolicy{ProjectID: projectID})
+	return err
+}
+
+// AcknowledgeUsagePolicy records that the user has acknowledged the usage policy of the project,
+// it's a no-op if the user has acknowledged it already
+func AcknowledgeUsagePolicy(projectID int64, userID int) error {
+	_, err := GetOrmer().Raw(`insert into usage_policy_ack (project_id, user_id, creation_time) 
+		values (?, ?, ?) on conflict (project_id, user_id) do nothing`,
+		projectID, userID, time.Now()).Exec()
+	return err
+}
+
+// GetUsagePolicyAck returns the acknowledgment of the usage policy of the project by the user,
+// nil is returned if the user hasn't acknowledged it
+func GetUsagePolicyAck(projectID int64, userID int) (*models.UsagePolicyAck, error) {
+	ack := &models.UsagePolicyAck{}
+	err := GetOrmer().QueryTable(ack).Filter("ProjectID", projectID).Filter("UserID", userID).One(ack)
+	if err == orm.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
+
+// UsagePolicyPending returns whether the project has a usage policy which the user hasn't
+// acknowledged, the username is empty for the anonymous users who can't acknowledge it
+func UsagePolicyPending(projectID int64, username string) (bool, error) {
+	var count int64
+	err := GetOrmer().Raw(`select count(*) from usage_policy p 
+		where p.project_id = ? and not exists (select 1 from usage_policy_ack a 
+		join harbor_user u on a.user_id = u.user_id 
+		where a.project_id = p.project_id and u.username = ? and u.deleted = false)`,
+		projectID, username).QueryRow(&count)
+	return count > 0, err
+}
@@ -0,0 +1,66 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsagePolicy(t *testing.T) {
+	policy, err := GetUsagePolicy(1)
+	require.Nil(t, err)
+	assert.Nil(t, policy)
+	pending, err := UsagePolicyPending(1, "admin")
+	require.Nil(t, err)
+	assert.False(t, pending)
+
+	require.Nil(t, SetUsagePolicy(1, "export controlled"))
+	defer DeleteUsagePolicy(1)
+	policy, err = GetUsagePolicy(1)
+	require.Nil(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, "export controlled", policy.Policy)
+	pending, err = UsagePolicyPending(1, "admin")
+	require.Nil(t, err)
+	assert.True(t, pending)
+
+	require.Nil(t, AcknowledgeUsagePolicy(1, 1))
+	// acknowledged twice
+	require.Nil(t, AcknowledgeUsagePolicy(1, 1))
+	ack, err := GetUsagePolicyAck(1, 1)
+	require.Nil(t, err)
+	require.NotNil(t, ack)
+	pending, err = UsagePolicyPending(1, "admin")
+	require.Nil(t, err)
+	assert.False(t, pending)
+	// the anonymous users can't acknowledge the policy
+	pending, err = UsagePolicyPending(1, "")
+	require.Nil(t, err)
+	assert.True(t, pending)
+
+	// the acknowledgments are removed when the policy is changed
+	require.Nil(t, SetUsagePolicy(1, "licensed"))
+	ack, err = GetUsagePolicyAck(1, 1)
+	require.Nil(t, err)
+	assert.Nil(t, ack)
+
+	require.Nil(t, DeleteUsagePolicy(1))
+	policy, err = GetUsagePolicy(1)
+	require.Nil(t, err)
+	assert.Nil(t, policy)
+}
@@ -265,3 +265,13 @@ func CleanUser(id int64) error {
 	}
 	return nil
 }
+
+// GetSysAdmins returns the users who have the system admin role
+func GetSysAdmins() ([]*models.User, error) {
+	users := []*models.User{}
+	_, err := GetOrmer().QueryTable(&models.User{}).
+		Filter("HasAdminRole", true).
+		Filter("Deleted", false).
+		All(&users)
+	return users, err
+}
@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddUserNotifications adds the notification to each of the users
+func AddUserNotifications(userIDs []int, notificationType, message string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	notifications := []*models.UserNotification{}
+	for _, userID := range userIDs {
+		notifications = append(notifications, &models.UserNotification{
+			UserID:       userID,
+			Type:         notificationType,
+			Message:      message,
+			CreationTime: now,
+			UpdateTime:   now,
+		})
+	}
+	_, err := GetOrmer().InsertMulti(len(notifications), notifications)
+	return err
+}
+
+// ListUserNotifications lists the notifications of the user matching the query, the newest first
+func ListUserNotifications(query *models.UserNotificationQuery) ([]*models.UserNotification, error) {
+	qs := getUserNotificationQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	notifications := []*models.UserNotification{}
+	_, err := qs.All(&notifications)
+	return notifications, err
+}
+
+// CountUserNotifications ...
+func CountUserNotifications(query *models.UserNotificationQuery) (int64, error) {
+	return getUserNotificationQuerySetter(query).Count()
+}
+
+func getUserNotificationQuerySetter(query *models.UserNotificationQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.UserNotification{}).Filter("UserID", query.UserID)
+	if query.Unread {
+		qs = qs.Filter("Read", false)
+	}
+	return qs
+}
+
+// MarkUserNotificationsRead marks the notifications of the user as read, all the notifications of the
+// user are marked if no ID is specified. It returns the count of the notifications marked.
+func MarkUserNotificationsRead(userID int, ids ...int64) (int64, error) {
+	qs := GetOrmer().QueryTable(&models.UserNotification{}).
+		Filter("UserID", userID).
+		Filter("Read", false)
+	if len(ids) > 0 {
+		qs = qs.Filter("ID__in", ids)
+	}
+	return qs.Update(orm.Params{
+		"Read":       true,
+		"UpdateTime": time.Now(),
+	})
+}
+
+// DeleteUserNotification deletes the notification of the user, it returns false if not found
+func DeleteUserNotification(userID int, id int64) (bool, error) {
+	n, err := GetOrmer().QueryTable(&models.UserNotification{}).
+		Filter("UserID", userID).
+		Filter("ID", id).
+		Delete()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
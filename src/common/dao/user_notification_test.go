@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserNotification(t *testing.T) {
+	userID := 1
+	require.Nil(t, AddUserNotifications([]int{userID}, models.UserNotificationScanDone, "scan done"))
+	require.Nil(t, AddUserNotifications([]int{userID}, models.UserNotificationQuotaWarning, "quota warning"))
+	notifications, err := ListUserNotifications(&models.UserNotificationQuery{UserID: userID})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(notifications))
+	for _, notification := range notifications {
+		defer DeleteUserNotification(userID, notification.ID)
+	}
+	// the newest first
+	assert.Equal(t, models.UserNotificationQuotaWarning, notifications[0].Type)
+	assert.False(t, notifications[0].Read)
+
+	// no notification of other users
+	total, err := CountUserNotifications(&models.UserNotificationQuery{UserID: 10000})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	n, err := MarkUserNotificationsRead(userID, notifications[1].ID)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	unread, err := ListUserNotifications(&models.UserNotificationQuery{UserID: userID, Unread: true})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(unread))
+	assert.Equal(t, notifications[0].ID, unread[0].ID)
+
+	// mark all
+	n, err = MarkUserNotificationsRead(userID)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	total, err = CountUserNotifications(&models.UserNotificationQuery{UserID: userID, Unread: true})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	deleted, err := DeleteUserNotification(userID, notifications[0].ID)
+	require.Nil(t, err)
+	assert.True(t, deleted)
+	deleted, err = DeleteUserNotification(userID, notifications[0].ID)
+	require.Nil(t, err)
+	assert.False(t, deleted)
+}
+
+func TestGetSysAdmins(t *testing.T) {
+	admins, err := GetSysAdmins()
+	require.Nil(t, err)
+	found := false
+	for _, admin := range admins {
+		if admin.UserID == 1 {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetVulException adds the exception of the repository, or updates the justification and
+// the expiration of the existing one
+func SetVulException(exception *models.VulException) error {
+	now := time.Now()
+	sql := `insert into vul_exception (project_id, repository, justification, expires_at, creator, creation_time, update_time)
+		values (?, ?, ?, ?, ?, ?, ?)
+		on conflict (repository) do update set justification = excluded.justification, expires_at = excluded.expires_at,
+		creator = excluded.creator, update_time = excluded.update_time`
+	_, err := GetOrmer().Raw(sql, exception.ProjectID, exception.Repository, exception.Justification,
+		exception.ExpiresAt, exception.Creator, now, now).Exec()
+	return err
+}
+
+// GetVulException returns the exception of the repository including the expired one. Nil
+// is returned if not found
+func GetVulException(repository string) (*models.VulException, error) {
+	exception := &models.VulException{
+		Repository: repository,
+	}
+	if err := GetOrmer().Read(exception, "Repository"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return exception, nil
+}
+
+// GetActiveVulException returns the exception of the repository which isn't expired. Nil
+// is returned if not found
+func GetActiveVulException(repository string) (*models.VulException, error) {
+	exception, err := GetVulException(repository)
+	if err != nil || exception == nil {
+		return nil, err
+	}
+	if exception.Expired(time.Now()) {
+		return nil, nil
+	}
+	return exception, nil
+}
+
+// ListVulExceptions lists the exceptions according to the query conditions, the one expiring
+// first is the first
+func ListVulExceptions(query *models.VulExceptionQuery) ([]*models.VulException, error) {
+	qs := getVulExceptionQuerySetter(query).OrderBy("ExpiresAt", "ID")
+	if query != nil && query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	exceptions := []*models.VulException{}
+	_, err := qs.All(&exceptions)
+	return exceptions, err
+}
+
+// CountVulExceptions ...
+func CountVulExceptions(query *models.VulExceptionQuery) (int64, error) {
+	return getVulExceptionQuerySetter(query).Count()
+}
+
+func getVulExceptionQuerySetter(query *models.VulExceptionQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.VulException{})
+	if query == nil {
+		return qs
+	}
+	if query.ProjectID > 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if query.Active {
+		qs = qs.Filter("ExpiresAt__gt", time.Now())
+	}
+	return qs
+}
+
+// DeleteVulException removes the exception of the repository
+func DeleteVulException(repository string) error {
+	_, err := GetOrmer().QueryTable(&models.VulException{}).
+		Filter("Repository", repository).
+		Delete()
+	return err
+}
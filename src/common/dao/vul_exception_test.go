@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulException(t *testing.T) {
+	repository := "library/vul-exception-test"
+	defer ClearTable(models.VulExceptionTable)
+
+	exception, err := GetActiveVulException(repository)
+	require.Nil(t, err)
+	assert.Nil(t, exception)
+
+	require.Nil(t, SetVulException(&models.VulException{
+		ProjectID:     1,
+		Repository:    repository,
+		Justification: "false positive",
+		ExpiresAt:     time.Now().Add(time.Hour),
+		Creator:       "admin",
+	}))
+	exception, err = GetActiveVulException(repository)
+	require.Nil(t, err)
+	require.NotNil(t, exception)
+	assert.Equal(t, "false positive", exception.Justification)
+
+	// the expired exception is kept but doesn't apply
+	require.Nil(t, SetVulException(&models.VulException{
+		ProjectID:     1,
+		Repository:    repository,
+		Justification: "no fix available",
+		ExpiresAt:     time.Now().Add(-time.Hour),
+		Creator:       "admin",
+	}))
+	exception, err = GetActiveVulException(repository)
+	require.Nil(t, err)
+	assert.Nil(t, exception)
+	exception, err = GetVulException(repository)
+	require.Nil(t, err)
+	require.NotNil(t, exception)
+	assert.Equal(t, "no fix available", exception.Justification)
+
+	total, err := CountVulExceptions(&models.VulExceptionQuery{ProjectID: 1})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	total, err = CountVulExceptions(&models.VulExceptionQuery{ProjectID: 1, Active: true})
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+	exceptions, err := ListVulExceptions(&models.VulExceptionQuery{ProjectID: 1})
+	require.Nil(t, err)
+	require.Len(t, exceptions, 1)
+	assert.Equal(t, repository, exceptions[0].Repository)
+
+	require.Nil(t, DeleteVulException(repository))
+	exception, err = GetVulException(repository)
+	require.Nil(t, err)
+	assert.Nil(t, exception)
+}
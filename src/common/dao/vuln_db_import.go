@@ -0,0 +1,101 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddVulnDBImport ...
+func AddVulnDBImport(imp *models.VulnDBImport) (int64, error) {
+	now := time.Now()
+	imp.CreationTime = now
+	imp.UpdateTime = now
+	return GetOrmer().Insert(imp)
+}
+
+// UpdateVulnDBImportStatus updates the status, message and count of the imported vulnerabilities
+func UpdateVulnDBImportStatus(id int64, status, message string, count int64) error {
+	_, err := GetOrmer().QueryTable(&models.VulnDBImport{}).
+		Filter("ID", id).
+		Update(orm.Params{
+			"Status":             status,
+			"Message":            message,
+			"VulnerabilityCount": count,
+			"UpdateTime":         time.Now(),
+		})
+	return err
+}
+
+// FailRunningVulnDBImports marks the running imports as failed, it is called when the
+// core starts as the imports interrupted by the restart never complete
+func FailRunningVulnDBImports(message string) (int64, error) {
+	return GetOrmer().QueryTable(&models.VulnDBImport{}).
+		Filter("Status", models.VulnDBImportRunning).
+		Update(orm.Params{
+			"Status":     models.VulnDBImportFailed,
+			"Message":    message,
+			"UpdateTime": time.Now(),
+		})
+}
+
+// GetVulnDBImport returns the import specified by ID, nil is returned if not found
+func GetVulnDBImport(id int64) (*models.VulnDBImport, error) {
+	imp := &models.VulnDBImport{
+		ID: id,
+	}
+	if err := GetOrmer().Read(imp); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return imp, nil
+}
+
+// ListVulnDBImports lists the imports according to the query conditions, the latest one is the first
+func ListVulnDBImports(query *models.VulnDBImportQuery) ([]*models.VulnDBImport, error) {
+	qs := getVulnDBImportQuerySetter(query).OrderBy("-CreationTime", "-ID")
+	if query != nil {
+		if query.Size > 0 {
+			qs = qs.Limit(query.Size)
+			if query.Page > 0 {
+				qs = qs.Offset((query.Page - 1) * query.Size)
+			}
+		}
+	}
+	imps := []*models.VulnDBImport{}
+	_, err := qs.All(&imps)
+	return imps, err
+}
+
+// CountVulnDBImports ...
+func CountVulnDBImports(query *models.VulnDBImportQuery) (int64, error) {
+	return getVulnDBImportQuerySetter(query).Count()
+}
+
+func getVulnDBImportQuerySetter(query *models.VulnDBImportQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.VulnDBImport{})
+	if query == nil {
+		return qs
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulnDBImport(t *testing.T) {
+	id, err := AddVulnDBImport(&models.VulnDBImport{
+		Version:            "2018.12.01",
+		Status:             models.VulnDBImportRunning,
+		BundleCreationTime: time.Now(),
+		Creator:            "admin",
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.VulnDBImportTable)
+
+	imp, err := GetVulnDBImport(id)
+	require.Nil(t, err)
+	require.NotNil(t, imp)
+	assert.Equal(t, "2018.12.01", imp.Version)
+	assert.Equal(t, models.VulnDBImportRunning, imp.Status)
+
+	require.Nil(t, UpdateVulnDBImportStatus(id, models.VulnDBImportSucceeded, "", 10))
+	imp, err = GetVulnDBImport(id)
+	require.Nil(t, err)
+	require.NotNil(t, imp)
+	assert.Equal(t, models.VulnDBImportSucceeded, imp.Status)
+	assert.Equal(t, int64(10), imp.VulnerabilityCount)
+
+	id2, err := AddVulnDBImport(&models.VulnDBImport{
+		Version:            "2018.12.02",
+		Status:             models.VulnDBImportRunning,
+		BundleCreationTime: time.Now(),
+		Creator:            "admin",
+	})
+	require.Nil(t, err)
+
+	query := &models.VulnDBImportQuery{
+		Status: models.VulnDBImportRunning,
+	}
+	total, err := CountVulnDBImports(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	n, err := FailRunningVulnDBImports("interrupted")
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	imp, err = GetVulnDBImport(id2)
+	require.Nil(t, err)
+	require.NotNil(t, imp)
+	assert.Equal(t, models.VulnDBImportFailed, imp.Status)
+
+	imps, err := ListVulnDBImports(nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(imps))
+	assert.Equal(t, id2, imps[0].ID)
+
+	imp, err = GetVulnDBImport(10000)
+	require.Nil(t, err)
+	assert.Nil(t, imp)
+}
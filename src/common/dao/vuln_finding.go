@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// ReplaceVulnFindings replaces the vulnerabilities found in the image with the ones of the latest scan
+func ReplaceVulnFindings(digest string, findings []*models.VulnFinding) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	if _, err := o.QueryTable(&models.VulnFinding{}).Filter("Digest", digest).Delete(); err != nil {
+		o.Rollback()
+		return err
+	}
+	if len(findings) > 0 {
+		now := time.Now()
+		for _, finding := range findings {
+			finding.Digest = digest
+			finding.CreationTime = now
+		}
+		if _, err := o.InsertMulti(100, findings); err != nil {
+			o.Rollback()
+			return err
+		}
+	}
+	return o.Commit()
+}
+
+// ListVulnFindings lists the vulnerabilities found in the image
+func ListVulnFindings(digest string) ([]*models.VulnFinding, error) {
+	findings := []*models.VulnFinding{}
+	_, err := GetOrmer().QueryTable(&models.VulnFinding{}).
+		Filter("Digest", digest).
+		OrderBy("-Severity", "CVEID").
+		All(&findings)
+	return findings, err
+}
+
+// ListAffectedArtifacts returns the tags whose latest scan contains the vulnerability, the
+// tags are the ones scanned last time for the digests
+func ListAffectedArtifacts(cveID string) ([]*models.AffectedArtifact, error) {
+	artifacts := []*models.AffectedArtifact{}
+	_, err := GetOrmer().Raw(`select j.repository, j.tag, f.digest, f.package, f.version, f.fixed_version, f.severity
+		from vulnerability_finding f
+		join img_scan_job j on j.digest = f.digest
+		where f.cve_id = ?
+		and j.id = (select max(id) from img_scan_job where repository = j.repository and tag = j.tag)
+		order by j.repository, j.tag, f.package`, cveID).QueryRows(&artifacts)
+	if err != nil {
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		artifact.Project, _ = utils.ParseRepository(artifact.Repository)
+	}
+	return artifacts, nil
+}
+
+// CountNewVulnsOfProject returns the count of the distinct vulnerabilities of each severity found
+// in the images of the project by the scans since the time
+func CountNewVulnsOfProject(projectName string, since time.Time) ([]*models.SeverityCount, error) {
+	counts := []*models.SeverityCount{}
+	_, err := GetOrmer().Raw(`select f.severity, count(distinct f.cve_id) as count
+		from vulnerability_finding f
+		where f.creation_time >= ?
+		and exists (select 1 from img_scan_job j where j.digest = f.digest and j.repository like ?)
+		group by f.severity
+		order by f.severity desc`, since, Escape(projectName)+"/%").QueryRows(&counts)
+	return counts, err
+}
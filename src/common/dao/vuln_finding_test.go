@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulnFindings(t *testing.T) {
+	digest := "sha256:0204dc6e09fa57ab99ac40e415eb637d62c8b2571ecbbc9ca0eb5e2ad2b5c56f"
+	_, err := AddScanJob(models.ScanJob{
+		Status:     models.JobFinished,
+		Repository: "library/hello",
+		Tag:        "v1",
+		Digest:     digest,
+	})
+	require.Nil(t, err)
+	defer ClearTable(models.ScanJobTable)
+	// the tag is scanned again with another digest
+	_, err = AddScanJob(models.ScanJob{
+		Status:     models.JobFinished,
+		Repository: "library/hello",
+		Tag:        "v2",
+		Digest:     digest,
+	})
+	require.Nil(t, err)
+	_, err = AddScanJob(models.ScanJob{
+		Status:     models.JobFinished,
+		Repository: "library/hello",
+		Tag:        "v2",
+		Digest:     "sha256:other",
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, ReplaceVulnFindings(digest, []*models.VulnFinding{
+		{CVEID: "CVE-2021-44228", Package: "log4j", Version: "2.14.1", FixedVersion: "2.15.0", Severity: models.SevHigh},
+		{CVEID: "CVE-2019-0001", Package: "openssl", Version: "1.0.1", Severity: models.SevLow},
+	}))
+	defer ClearTable(models.VulnFindingTable)
+
+	findings, err := ListVulnFindings(digest)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(findings))
+	assert.Equal(t, "CVE-2021-44228", findings[0].CVEID)
+
+	artifacts, err := ListAffectedArtifacts("CVE-2021-44228")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(artifacts))
+	assert.Equal(t, "library", artifacts[0].Project)
+	assert.Equal(t, "v1", artifacts[0].Tag)
+	assert.Equal(t, "2.15.0", artifacts[0].FixedVersion)
+
+	counts, err := CountNewVulnsOfProject("library", time.Now().Add(-time.Hour))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(counts))
+	assert.Equal(t, models.SevHigh, counts[0].Severity)
+	assert.Equal(t, int64(1), counts[0].Count)
+	counts, err = CountNewVulnsOfProject("library", time.Now().Add(time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(counts))
+	counts, err = CountNewVulnsOfProject("other", time.Now().Add(-time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(counts))
+
+	// replaced by the latest scan
+	require.Nil(t, ReplaceVulnFindings(digest, []*models.VulnFinding{
+		{CVEID: "CVE-2019-0001", Package: "openssl", Version: "1.0.1", Severity: models.SevLow},
+	}))
+	artifacts, err = ListAffectedArtifacts("CVE-2021-44228")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(artifacts))
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetVulnerabilityTrend inserts or replaces the snapshot of the project on the day
+func SetVulnerabilityTrend(trend *models.VulnerabilityTrend) error {
+	sql := `insert into vulnerability_trend (project_id, day, images, scanned, negligible, unknown, low, medium, high) 
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?) 
+		on conflict (project_id, day) do update set images = excluded.images, scanned = excluded.scanned, 
+		negligible = excluded.negligible, unknown = excluded.unknown, low = excluded.low, 
+		medium = excluded.medium, high = excluded.high`
+	_, err := GetOrmer().Raw(sql, trend.ProjectID, trend.Day.UTC().Format("2006-01-02"), trend.Images,
+		trend.Scanned, trend.Negligible, trend.Unknown, trend.Low, trend.Medium, trend.High).Exec()
+	return err
+}
+
+// ListVulnerabilityTrends returns the snapshots of the project ordered by the day
+func ListVulnerabilityTrends(query *models.VulnerabilityTrendQuery) ([]*models.VulnerabilityTrend, error) {
+	sql := `select project_id, day, images, scanned, negligible, unknown, low, medium, high 
+		from vulnerability_trend where project_id = ?`
+	params := []interface{}{query.ProjectID}
+	if query.BeginTime != nil {
+		sql += ` and day >= ?`
+		params = append(params, query.BeginTime.UTC().Format("2006-01-02"))
+	}
+	if query.EndTime != nil {
+		sql += ` and day <= ?`
+		params = append(params, query.EndTime.UTC().Format("2006-01-02"))
+	}
+	sql += ` order by day`
+
+	trends := []*models.VulnerabilityTrend{}
+	_, err := GetOrmer().Raw(sql, params).QueryRows(&trends)
+	return trends, err
+}
+
+// CountVulnerabilityTrendsOfDay returns the count of the snapshots of all the projects on the day
+func CountVulnerabilityTrendsOfDay(day time.Time) (int64, error) {
+	var count int64
+	err := GetOrmer().Raw(`select count(*) from vulnerability_trend where day = ?`,
+		day.UTC().Format("2006-01-02")).QueryRow(&count)
+	return count, err
+}
@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulnerabilityTrend(t *testing.T) {
+	day := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	defer GetOrmer().Raw(`delete from vulnerability_trend`).Exec()
+
+	require.Nil(t, SetVulnerabilityTrend(&models.VulnerabilityTrend{
+		ProjectID: 1,
+		Day:       day,
+		Images:    2,
+		Scanned:   1,
+		High:      3,
+	}))
+	require.Nil(t, SetVulnerabilityTrend(&models.VulnerabilityTrend{
+		ProjectID: 1,
+		Day:       day.AddDate(0, 0, 1),
+		Images:    2,
+		Scanned:   2,
+		High:      1,
+	}))
+	// the snapshot of the day is replaced
+	require.Nil(t, SetVulnerabilityTrend(&models.VulnerabilityTrend{
+		ProjectID: 1,
+		Day:       day,
+		Images:    2,
+		Scanned:   2,
+		High:      5,
+	}))
+
+	trends, err := ListVulnerabilityTrends(&models.VulnerabilityTrendQuery{
+		ProjectID: 1,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(trends))
+	assert.Equal(t, 5, trends[0].High)
+	assert.Equal(t, 2, trends[0].Scanned)
+	assert.Equal(t, 1, trends[1].High)
+
+	begin := day.AddDate(0, 0, 1)
+	trends, err = ListVulnerabilityTrends(&models.VulnerabilityTrendQuery{
+		ProjectID: 1,
+		BeginTime: &begin,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(trends))
+	assert.Equal(t, 1, trends[0].High)
+
+	count, err := CountVulnerabilityTrendsOfDay(day)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = CountVulnerabilityTrendsOfDay(day.AddDate(0, 0, 2))
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package feature defines the feature flags which enable the risky capabilities per deployment,
+// the flags are toggled at runtime and the overrides are kept in the configuration "feature_flags"
+// as a JSON object, e.g. {"online_gc": true}
+package feature
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// the names of the feature flags
+const (
+	OnlineGC  = "online_gc"
+	OCICharts = "oci_charts"
+	Cosign    = "cosign"
+)
+
+// Flag describes a feature flag
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// the state of the flag when it isn't overridden
+	Default bool `json:"default"`
+}
+
+var flags = map[string]*Flag{
+	OnlineGC: {
+		Name:        OnlineGC,
+		Description: "Run the garbage collection of registry without switching Harbor into read only mode",
+	},
+	OCICharts: {
+		Name:        OCICharts,
+		Description: "Store the helm charts as OCI artifacts in the registry",
+	},
+	Cosign: {
+		Name:        Cosign,
+		Description: "Verify and display the cosign signatures of the images",
+	},
+}
+
+// All returns all the feature flags sorted by name
+func All() []*Flag {
+	all := make([]*Flag, 0, len(flags))
+	for _, flag := range flags {
+		all = append(all, flag)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
+
+// Get returns the feature flag, false is returned if it isn't defined
+func Get(name string) (*Flag, bool) {
+	flag, ok := flags[name]
+	return flag, ok
+}
+
+// Overrides are the states of the feature flags set at runtime
+type Overrides map[string]bool
+
+// Parse parses the overrides stored in the configuration, the empty value means no override
+func Parse(value string) (Overrides, error) {
+	overrides := Overrides{}
+	if len(value) == 0 {
+		return overrides, nil
+	}
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid feature flags %q: %v", value, err)
+	}
+	return overrides, nil
+}
+
+// String encodes the overrides to be stored in the configuration
+func (o Overrides) String() string {
+	data, err := json.Marshal(map[string]bool(o))
+	if err != nil {
+		// never happens for a map of bool
+		return "{}"
+	}
+	return string(data)
+}
+
+// Enabled returns whether the feature is enabled, the overrides take precedence
+// over the default, the undefined features are always disabled
+func (o Overrides) Enabled(name string) bool {
+	flag, ok := flags[name]
+	if !ok {
+		return false
+	}
+	if enabled, ok := o[name]; ok {
+		return enabled
+	}
+	return flag.Default
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	all := All()
+	require.Equal(t, 3, len(all))
+	assert.Equal(t, Cosign, all[0].Name)
+	assert.Equal(t, OCICharts, all[1].Name)
+	assert.Equal(t, OnlineGC, all[2].Name)
+}
+
+func TestGet(t *testing.T) {
+	flag, ok := Get(OnlineGC)
+	require.True(t, ok)
+	assert.Equal(t, OnlineGC, flag.Name)
+
+	_, ok = Get("unknown")
+	assert.False(t, ok)
+}
+
+func TestParse(t *testing.T) {
+	overrides, err := Parse("")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(overrides))
+
+	overrides, err = Parse(`{"online_gc": true, "cosign": false}`)
+	require.Nil(t, err)
+	assert.True(t, overrides[OnlineGC])
+	assert.False(t, overrides[Cosign])
+
+	_, err = Parse("online_gc")
+	assert.NotNil(t, err)
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "{}", Overrides{}.String())
+	overrides, err := Parse(Overrides{OnlineGC: true}.String())
+	require.Nil(t, err)
+	assert.Equal(t, Overrides{OnlineGC: true}, overrides)
+}
+
+func TestEnabled(t *testing.T) {
+	overrides := Overrides{
+		OnlineGC:  true,
+		"unknown": true,
+	}
+	assert.True(t, overrides.Enabled(OnlineGC))
+	assert.False(t, overrides.Enabled(Cosign))
+	assert.False(t, overrides.Enabled("unknown"))
+}
@@ -0,0 +1,188 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n translates the user-facing messages of the API according to the header
+// Accept-Language. The messages are identified by the IDs in the catalogs, en-US and zh-CN
+// are built in, more locales can be registered or loaded from the JSON files named by the
+// locales, e.g. "fr-FR.json", in the directory specified by the environment variable I18N_CATALOG_DIR.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// DefaultLocale is used when none of the locales accepted by the client is supported,
+// the messages missing in the other catalogs fall back to it as well
+const DefaultLocale = "en-US"
+
+// CatalogDirEnv is the environment variable specifying the directory of the extra catalogs
+const CatalogDirEnv = "I18N_CATALOG_DIR"
+
+// Catalog maps the message IDs to the messages, the messages can contain the verbs of fmt
+type Catalog map[string]string
+
+var (
+	lock     sync.RWMutex
+	catalogs = map[string]Catalog{}
+)
+
+func init() {
+	Register(DefaultLocale, enUS)
+	Register("zh-CN", zhCN)
+}
+
+// Register adds the messages of the locale, the existing messages are overwritten
+func Register(locale string, catalog Catalog) {
+	locale = canonical(locale)
+	lock.Lock()
+	defer lock.Unlock()
+	c, ok := catalogs[locale]
+	if !ok {
+		c = Catalog{}
+		catalogs[locale] = c
+	}
+	for id, message := range catalog {
+		c[id] = message
+	}
+}
+
+// Locales returns the supported locales in order
+func Locales() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LoadDir registers the catalogs in the JSON files of the directory, the name of the file is the locale
+func LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		catalog := Catalog{}
+		if err = json.Unmarshal(data, &catalog); err != nil {
+			return fmt.Errorf("invalid catalog %s: %v", file, err)
+		}
+		locale := strings.TrimSuffix(filepath.Base(file), ".json")
+		Register(locale, catalog)
+		log.Infof("the catalog of %s is loaded from %s", canonical(locale), file)
+	}
+	return nil
+}
+
+// Init loads the extra catalogs from the directory specified by the environment variable I18N_CATALOG_DIR
+func Init() error {
+	dir := os.Getenv(CatalogDirEnv)
+	if len(dir) == 0 {
+		return nil
+	}
+	return LoadDir(dir)
+}
+
+// Negotiate returns the supported locale preferred by the client according to the value of header
+// Accept-Language, e.g. "zh-CN,zh;q=0.9,en;q=0.8". The locale matching the language only is accepted
+// if no locale matches exactly, e.g. "zh" matches "zh-CN"
+func Negotiate(acceptLanguage string) string {
+	type tag struct {
+		value string
+		q     float64
+	}
+	tags := []tag{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		value := strings.TrimSpace(fields[0])
+		if len(value) == 0 || value == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if f, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{value: value, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, t := range tags {
+		locale := canonical(t.value)
+		if _, ok := catalogs[locale]; ok {
+			return locale
+		}
+		language := strings.SplitN(locale, "-", 2)[0]
+		for supported := range catalogs {
+			if strings.SplitN(supported, "-", 2)[0] == language {
+				return supported
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// T returns the message of the locale formatted with the arguments, the message of default
+// locale is used if it's missing in the catalog of the locale, and the ID is used if it's missing at all
+func T(locale, id string, args ...interface{}) string {
+	lock.RLock()
+	message, ok := catalogs[canonical(locale)][id]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][id]
+	}
+	lock.RUnlock()
+	if !ok {
+		message = id
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// canonical formats the locale as "ll-CC", e.g. "zh_cn" is formatted as "zh-CN"
+func canonical(locale string) string {
+	parts := strings.SplitN(strings.Replace(strings.TrimSpace(locale), "_", "-", -1), "-", 2)
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+	return strings.Join(parts, "-")
+}
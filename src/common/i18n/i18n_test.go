@@ -0,0 +1,86 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for id := range enUS {
+		_, ok := zhCN[id]
+		assert.True(t, ok, "message %s is missing in zh-CN", id)
+	}
+	assert.Equal(t, len(enUS), len(zhCN))
+}
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		header string
+		locale string
+	}{
+		{"", DefaultLocale},
+		{"*", DefaultLocale},
+		{"en-US", "en-US"},
+		{"zh-CN", "zh-CN"},
+		{"zh-cn", "zh-CN"},
+		{"zh_CN", "zh-CN"},
+		{"zh", "zh-CN"},
+		{"zh-TW", "zh-CN"},
+		{"fr-FR", DefaultLocale},
+		{"fr-FR,zh;q=0.8,en;q=0.5", "zh-CN"},
+		{"en;q=0.5,zh-CN;q=0.9", "zh-CN"},
+		{"zh-CN;q=0,en", "en-US"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.locale, Negotiate(c.header), "header: %s", c.header)
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "project library not found", T("en-US", MsgProjectNotFound, "library"))
+	assert.Equal(t, "项目 library 不存在", T("zh-CN", MsgProjectNotFound, "library"))
+	assert.Equal(t, "invalid ID in URL", T("fr-FR", MsgInvalidID))
+	assert.Equal(t, "unknown_message", T("zh-CN", "unknown_message"))
+}
+
+func TestRegisterAndLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "i18n")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "fr-FR.json"),
+		[]byte(`{"invalid_id": "ID invalide"}`), 0600))
+	require.Nil(t, os.Setenv(CatalogDirEnv, dir))
+	defer os.Unsetenv(CatalogDirEnv)
+
+	require.Nil(t, Init())
+	assert.Contains(t, Locales(), "fr-FR")
+	assert.Equal(t, "fr-FR", Negotiate("fr"))
+	assert.Equal(t, "ID invalide", T("fr-FR", MsgInvalidID))
+	// fall back to the default locale
+	assert.Equal(t, "Invalid json request", T("fr-FR", MsgInvalidJSON))
+
+	Register("de_de", Catalog{MsgInvalidID: "Ungültige ID"})
+	assert.Equal(t, "Ungültige ID", T(Negotiate("de-DE"), MsgInvalidID))
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "es-ES.json"), []byte(`invalid`), 0600))
+	assert.NotNil(t, LoadDir(dir))
+}
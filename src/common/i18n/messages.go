@@ -0,0 +1,64 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// the IDs of the messages
+const (
+	MsgUnauthorized         = "unauthorized"
+	MsgForbidden            = "forbidden"
+	MsgInternalError        = "internal_error"
+	MsgInvalidJSON          = "invalid_json"
+	MsgInvalidID            = "invalid_id"
+	MsgProjectNotFound      = "project_not_found"
+	MsgRepositoryNotFound   = "repository_not_found"
+	MsgReadOnly             = "read_only"
+	MsgMaintenance          = "maintenance"
+	MsgRequestBodyTooLarge  = "request_body_too_large"
+	MsgChartTooLarge        = "chart_too_large"
+	MsgLogQueryTooLong      = "log_query_too_long"
+	MsgConfirmationRequired = "confirmation_required"
+)
+
+var enUS = Catalog{
+	MsgUnauthorized:         "Unauthorized, please log in first.",
+	MsgForbidden:            "Forbidden, the user doesn't have the permission to perform the operation.",
+	MsgInternalError:        "Internal error occurred, please contact the system admin.",
+	MsgInvalidJSON:          "Invalid json request",
+	MsgInvalidID:            "invalid ID in URL",
+	MsgProjectNotFound:      "project %s not found",
+	MsgRepositoryNotFound:   "repository %s not found",
+	MsgReadOnly:             "The system is in read only mode. Any modification is prohibited.",
+	MsgMaintenance:          "Harbor is under maintenance, please retry later.",
+	MsgRequestBodyTooLarge:  "The request body exceeds the max size %d bytes.",
+	MsgChartTooLarge:        "The chart exceeds the max size %d bytes.",
+	MsgLogQueryTooLong:      "The query exceeds the max size %d bytes.",
+	MsgConfirmationRequired: "The operation requires a confirmation, please send the request again with the confirmation token.",
+}
+
+var zhCN = Catalog{
+	MsgUnauthorized:         "未授权，请先登录。",
+	MsgForbidden:            "禁止访问，用户没有执行该操作的权限。",
+	MsgInternalError:        "发生内部错误，请联系系统管理员。",
+	MsgInvalidJSON:          "无效的JSON请求",
+	MsgInvalidID:            "URL中的ID无效",
+	MsgProjectNotFound:      "项目 %s 不存在",
+	MsgRepositoryNotFound:   "镜像仓库 %s 不存在",
+	MsgReadOnly:             "系统处于只读模式，禁止任何修改。",
+	MsgMaintenance:          "Harbor 正在维护中，请稍后重试。",
+	MsgRequestBodyTooLarge:  "请求体超过了最大限制 %d 字节。",
+	MsgChartTooLarge:        "Chart 超过了最大限制 %d 字节。",
+	MsgLogQueryTooLong:      "查询超过了最大限制 %d 字节。",
+	MsgConfirmationRequired: "该操作需要确认，请携带确认令牌重新发送请求。",
+}
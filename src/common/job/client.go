@@ -10,12 +10,15 @@ import (
 	commonhttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/http/modifier/auth"
 	"github.com/goharbor/harbor/src/common/job/models"
+	commonmodels "github.com/goharbor/harbor/src/common/models"
 )
 
 // Client wraps interface to access jobservice.
 type Client interface {
 	SubmitJob(*models.JobData) (string, error)
 	GetJobLog(uuid string) ([]byte, error)
+	// GetJobLogUsage returns the count and the total bytes of the logs per job
+	GetJobLogUsage() ([]*commonmodels.JobLogUsage, error)
 	PostAction(uuid, action string) error
 	// TODO Redirect joblog when we see there's memory issue.
 }
@@ -103,6 +106,15 @@ func (d *DefaultClient) GetJobLog(uuid string) ([]byte, error) {
 	return data, nil
 }
 
+// GetJobLogUsage call jobservice API to get the count and the total bytes of the logs per job
+func (d *DefaultClient) GetJobLogUsage() ([]*commonmodels.JobLogUsage, error) {
+	usages := []*commonmodels.JobLogUsage{}
+	if err := d.client.Get(d.endpoint+"/api/v1/logs/usage", &usages); err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
 // PostAction call jobservice's API to operate action for job specified by uuid
 func (d *DefaultClient) PostAction(uuid, action string) error {
 	url := d.endpoint + "/api/v1/jobs/" + uuid
@@ -47,6 +47,17 @@ func TestGetJobLog(t *testing.T) {
 	assert.Contains(text, "The content in this file is for mocking the get log api.")
 }
 
+func TestGetJobLogUsage(t *testing.T) {
+	assert := assert.New(t)
+	usages, err := testClient.GetJobLogUsage()
+	assert.Nil(err)
+	if assert.Equal(1, len(usages)) {
+		assert.Equal("IMAGE_SCAN", usages[0].JobName)
+		assert.Equal(int64(2), usages[0].Count)
+		assert.Equal(int64(1024), usages[0].Size)
+	}
+}
+
 func TestPostAction(t *testing.T) {
 	assert := assert.New(t)
 	err := testClient.PostAction(ID, "fff")
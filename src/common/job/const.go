@@ -13,6 +13,32 @@ const (
 	ImageReplicate = "IMAGE_REPLICATE"
 	// ImageGC the name of image garbage collection job in job service
 	ImageGC = "IMAGE_GC"
+	// ChartGC the name of the job removing the orphaned chart files from the chart storage
+	ChartGC = "CHART_GC"
+	// RebuildIndex the name of the job rebuilding the denormalized data in database
+	RebuildIndex = "REBUILD_INDEX"
+	// SeverityRecalculation the name of the job recalculating the severity of the scan reports
+	SeverityRecalculation = "SEVERITY_RECALCULATION"
+	// StorageTransition the name of the job transitioning the idle blobs to the storage classes hinted by
+	// the projects and repositories
+	StorageTransition = "STORAGE_TRANSITION"
+	// SecretReencryption the name of the job re-encrypting the secrets with the primary master key
+	SecretReencryption = "SECRET_REENCRYPTION"
+	// AuthModeMigration the name of the admin job converting the users to the new auth mode and switching
+	// the auth mode, it runs in core as it searches the users with the authenticators of core
+	AuthModeMigration = "AUTH_MODE_MIGRATION"
+	// ProjectReport the name of the periodic job sending the report emails to the subscribers of the projects
+	ProjectReport = "PROJECT_REPORT"
+	// Reconciliation the name of the job checking and repairing the drifts between the registry and the database
+	Reconciliation = "RECONCILIATION"
+
+	// CoreTask the name of the job of the tasks running in core, e.g. the project merges. The tasks
+	// depend on the components of core, so the job calls core to run them and is retried if core restarts
+	CoreTask = "CORE_TASK"
+	// TaskNameParam the parameter of the CoreTask job, which is the name of the task
+	TaskNameParam = "task_name"
+	// TaskIDParam the parameter of the CoreTask job, which is the ID of the task record
+	TaskIDParam = "task_id"
 
 	// JobKindGeneric : Kind of generic job
 	JobKindGeneric = "Generic"
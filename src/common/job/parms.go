@@ -6,4 +6,6 @@ type ScanJobParms struct {
 	Repository string `json:"repository"`
 	Tag        string `json:"tag"`
 	Digest     string `json:"digest"`
+	// the custom CA bundle trusted by the client of the scanner
+	CABundle string `json:"ca_bundle,omitempty"`
 }
@@ -45,6 +45,18 @@ func NewJobServiceServer() *httptest.Server {
 				panic(err)
 			}
 		})
+	mux.HandleFunc("/api/v1/logs/usage",
+		func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet {
+				rw.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			rw.Header().Add("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusOK)
+			if _, err := rw.Write([]byte(`[{"job_name":"IMAGE_SCAN","count":2,"size":1024}]`)); err != nil {
+				panic(err)
+			}
+		})
 	mux.HandleFunc(fmt.Sprintf("%s/%s", jobsPrefix, jobUUID),
 		func(rw http.ResponseWriter, req *http.Request) {
 			if req.Method != http.MethodPost {
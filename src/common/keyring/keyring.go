@@ -0,0 +1,301 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keyring encrypts the secrets stored in the database with the envelope
+// encryption: each secret is encrypted by a random data key with AES-GCM, and the
+// data key is wrapped by a master key of the key ring. The ciphertext is in format
+// "<enc-v2><key ID>:<wrapped data key>:<encrypted secret>", so the secrets encrypted
+// by the old master keys can still be decrypted after the primary key is rotated.
+//
+// The key ring is a flat map of the key IDs and the base64 encoded AES keys, with the
+// key "primary" specifying the ID of the key used to encrypt the new secrets, e.g.
+//
+//	{"primary": "2026-10", "2026-10": "<base64 key>", "2019-01": "<base64 key>"}
+//
+// It's read from the JSON file specified by the environment variable MASTER_KEY_PATH
+// or the secret store referenced by MASTER_KEY_REF, e.g. "vault:secret/data/harbor/keys".
+// If neither is set, the secrets are encrypted by the secret key of Harbor as before.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/secretstore"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+const (
+	// EncryptHeaderV2 is the header of the secrets encrypted with the envelope encryption
+	EncryptHeaderV2 = "<enc-v2>"
+
+	keyPathEnv  = "MASTER_KEY_PATH"
+	keyRefEnv   = "MASTER_KEY_REF"
+	primaryKey  = "primary"
+	dataKeySize = 32
+)
+
+// KeyRing holds the master keys
+type KeyRing struct {
+	// Primary is the ID of the key used to encrypt the new secrets
+	Primary string
+	keys    map[string][]byte
+}
+
+// New creates the key ring from the map of the key IDs and the base64 encoded keys
+func New(entries map[string]string) (*KeyRing, error) {
+	ring := &KeyRing{
+		Primary: entries[primaryKey],
+		keys:    map[string][]byte{},
+	}
+	for id, value := range entries {
+		if id == primaryKey {
+			continue
+		}
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key ID %s, it can not contain ':'", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the master key %s: %v", id, err)
+		}
+		if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+			return nil, fmt.Errorf("invalid size of the master key %s: %d, it should be 16, 24 or 32 bytes", id, len(key))
+		}
+		ring.keys[id] = key
+	}
+	if len(ring.Primary) == 0 {
+		return nil, errors.New("the primary master key isn't specified")
+	}
+	if _, exist := ring.keys[ring.Primary]; !exist {
+		return nil, fmt.Errorf("the primary master key %s not found", ring.Primary)
+	}
+	return ring, nil
+}
+
+// Encrypt encrypts the plaintext with a new data key wrapped by the primary master key
+func (k *KeyRing) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.Primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return EncryptHeaderV2 + k.Primary + ":" + base64.StdEncoding.EncodeToString(wrapped) +
+		":" + base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// Decrypt decrypts the ciphertext encrypted by any master key of the key ring
+func (k *KeyRing) Decrypt(ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, EncryptHeaderV2) {
+		return "", errors.New("the ciphertext isn't encrypted with the envelope encryption")
+	}
+	strs := strings.Split(strings.TrimPrefix(ciphertext, EncryptHeaderV2), ":")
+	if len(strs) != 3 {
+		return "", errors.New("invalid format of the ciphertext")
+	}
+	masterKey, exist := k.keys[strs[0]]
+	if !exist {
+		return "", fmt.Errorf("the master key %s not found", strs[0])
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(strs[1])
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(strs[2])
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := open(masterKey, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap the data key: %v", err)
+	}
+	plaintext, err := open(dataKey, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Has returns whether the master key with the ID is in the key ring
+func (k *KeyRing) Has(id string) bool {
+	_, exist := k.keys[id]
+	return exist
+}
+
+// KeyID returns the ID of the master key which encrypts the ciphertext, the
+// empty string is returned if the envelope encryption isn't used
+func KeyID(ciphertext string) string {
+	if !strings.HasPrefix(ciphertext, EncryptHeaderV2) {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(ciphertext, EncryptHeaderV2), ":", 2)[0]
+}
+
+// seal encrypts the data with AES-GCM, the nonce is prepended to the result
+func seal(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("the ciphertext is too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Load reads the key ring from the file or the secret store, nil is returned if neither is configured
+func Load() (*KeyRing, error) {
+	entries := map[string]string{}
+	if path := os.Getenv(keyPathEnv); len(path) > 0 {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse the master key file %s: %v", path, err)
+		}
+	} else if ref := os.Getenv(keyRefEnv); len(ref) > 0 {
+		var err error
+		if entries, err = secretstore.Get(ref); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, nil
+	}
+	return New(entries)
+}
+
+var (
+	lock    sync.Mutex
+	current *KeyRing
+	loaded  bool
+	// the modification time of the key file when the key ring is read, the key ring
+	// is read again once the file is changed, e.g. the master keys are rotated
+	modTime time.Time
+)
+
+// get returns the key ring read before, it's read again if the key file is changed since then
+func get() (*KeyRing, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	mt := keyFileModTime()
+	if loaded && mt.Equal(modTime) {
+		return current, nil
+	}
+	return load(mt)
+}
+
+// Reload reads the key ring again, it's called when the master keys are rotated or a secret
+// is encrypted by a master key unknown to the key ring read before
+func Reload() (*KeyRing, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	return load(keyFileModTime())
+}
+
+func load(mt time.Time) (*KeyRing, error) {
+	ring, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	current, loaded, modTime = ring, true, mt
+	return current, nil
+}
+
+// keyFileModTime returns the modification time of the key file, the zero time is returned
+// if the key ring isn't read from a file
+func keyFileModTime() time.Time {
+	path := os.Getenv(keyPathEnv)
+	if len(path) == 0 {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Encrypt encrypts the secret with the primary master key if the key ring is configured,
+// otherwise the secret is encrypted by the legacy secret key
+func Encrypt(plaintext, legacyKey string) (string, error) {
+	ring, err := get()
+	if err != nil {
+		return "", err
+	}
+	if ring == nil {
+		return utils.ReversibleEncrypt(plaintext, legacyKey)
+	}
+	return ring.Encrypt(plaintext)
+}
+
+// Decrypt decrypts the secret encrypted by either the master keys or the legacy secret key
+func Decrypt(ciphertext, legacyKey string) (string, error) {
+	if !strings.HasPrefix(ciphertext, EncryptHeaderV2) {
+		return utils.ReversibleDecrypt(ciphertext, legacyKey)
+	}
+	ring, err := get()
+	if err != nil {
+		return "", err
+	}
+	// the secret may be encrypted by a new master key of the other components after the
+	// rotation, e.g. the key ring in the secret store is updated
+	if ring == nil || !ring.Has(KeyID(ciphertext)) {
+		if ring, err = Reload(); err != nil {
+			return "", err
+		}
+	}
+	if ring == nil {
+		return "", errors.New("the secret is encrypted by the master key but the key ring isn't configured")
+	}
+	return ring.Decrypt(ciphertext)
+}
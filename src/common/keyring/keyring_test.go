@@ -0,0 +1,165 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyring
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	key1 = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	key2 = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210"))
+)
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		entries  map[string]string
+		hasError bool
+	}{
+		// no primary key
+		{entries: map[string]string{"k1": key1}, hasError: true},
+		// primary key not found
+		{entries: map[string]string{"primary": "k2", "k1": key1}, hasError: true},
+		// invalid base64
+		{entries: map[string]string{"primary": "k1", "k1": "invalid base64"}, hasError: true},
+		// invalid key size
+		{entries: map[string]string{"primary": "k1", "k1": base64.StdEncoding.EncodeToString([]byte("short"))}, hasError: true},
+		// invalid key ID
+		{entries: map[string]string{"primary": "k:1", "k:1": key1}, hasError: true},
+		{entries: map[string]string{"primary": "k1", "k1": key1, "k2": key2}},
+	}
+	for _, c := range cases {
+		_, err := New(c.entries)
+		if c.hasError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	ring, err := New(map[string]string{"primary": "k1", "k1": key1})
+	require.Nil(t, err)
+
+	ciphertext, err := ring.Encrypt("Harbor12345")
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, EncryptHeaderV2))
+	assert.Equal(t, "k1", KeyID(ciphertext))
+	plaintext, err := ring.Decrypt(ciphertext)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+
+	// the data key is generated for each secret
+	ciphertext2, err := ring.Encrypt("Harbor12345")
+	require.Nil(t, err)
+	assert.NotEqual(t, ciphertext, ciphertext2)
+
+	// the primary key is rotated, the secret encrypted by the old key can be decrypted
+	rotated, err := New(map[string]string{"primary": "k2", "k1": key1, "k2": key2})
+	require.Nil(t, err)
+	plaintext, err = rotated.Decrypt(ciphertext)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+	ciphertext, err = rotated.Encrypt("Harbor12345")
+	require.Nil(t, err)
+	assert.Equal(t, "k2", KeyID(ciphertext))
+
+	// the old key is removed
+	_, err = ring.Decrypt(ciphertext)
+	assert.NotNil(t, err)
+
+	// tampered
+	_, err = rotated.Decrypt(ciphertext[:len(ciphertext)-4] + "AAA=")
+	assert.NotNil(t, err)
+
+	// not encrypted with the envelope encryption
+	assert.Equal(t, "", KeyID("<enc-v1>abc"))
+	_, err = ring.Decrypt("<enc-v1>abc")
+	assert.NotNil(t, err)
+}
+
+func TestEncryptWithConfiguredKeyRing(t *testing.T) {
+	legacyKey := "0123456789abcdef"
+	defer func() {
+		os.Unsetenv(keyPathEnv)
+		_, err := Reload()
+		require.Nil(t, err)
+	}()
+
+	// the key ring isn't configured, the legacy key is used
+	os.Unsetenv(keyPathEnv)
+	_, err := Reload()
+	require.Nil(t, err)
+	legacy, err := Encrypt("Harbor12345", legacyKey)
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(legacy, utils.EncryptHeaderV1))
+	plaintext, err := Decrypt(legacy, legacyKey)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+
+	f, err := ioutil.TempFile("", "master_keys")
+	require.Nil(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{"primary": "k1", "k1": "` + key1 + `"}`)
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+	os.Setenv(keyPathEnv, f.Name())
+	_, err = Reload()
+	require.Nil(t, err)
+
+	ciphertext, err := Encrypt("Harbor12345", legacyKey)
+	require.Nil(t, err)
+	assert.Equal(t, "k1", KeyID(ciphertext))
+	plaintext, err = Decrypt(ciphertext, legacyKey)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+
+	// the secrets encrypted by the legacy key can still be decrypted
+	plaintext, err = Decrypt(legacy, legacyKey)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+
+	// the master keys are rotated by the other component, the key ring is read again when
+	// a secret encrypted by the new key is met even if the file looks unchanged
+	info, err := os.Stat(f.Name())
+	require.Nil(t, err)
+	entries := map[string]string{"primary": "k2", "k1": key1, "k2": key2}
+	require.Nil(t, ioutil.WriteFile(f.Name(), []byte(`{"primary": "k2", "k1": "`+key1+`", "k2": "`+key2+`"}`), 0600))
+	require.Nil(t, os.Chtimes(f.Name(), info.ModTime(), info.ModTime()))
+	rotated, err := New(entries)
+	require.Nil(t, err)
+	ciphertext, err = rotated.Encrypt("Harbor12345")
+	require.Nil(t, err)
+	plaintext, err = Decrypt(ciphertext, legacyKey)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+
+	// the new primary key is used once the file is changed
+	require.Nil(t, ioutil.WriteFile(f.Name(), []byte(`{"primary": "k1", "k1": "`+key1+`", "k2": "`+key2+`"}`), 0600))
+	require.Nil(t, os.Chtimes(f.Name(), info.ModTime().Add(time.Minute), info.ModTime().Add(time.Minute)))
+	ciphertext, err = Encrypt("Harbor12345", legacyKey)
+	require.Nil(t, err)
+	assert.Equal(t, "k1", KeyID(ciphertext))
+}
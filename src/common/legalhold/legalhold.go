@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package legalhold checks the legal holds shared by core and jobservice before deleting
+// the manifests of the repositories
+package legalhold
+
+import (
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+)
+
+// Blocking returns the active legal hold which keeps the tags from being deleted, nil
+// if none. The digests are the ones of the tags to delete keyed by the tags. As the registry
+// deletes the tags referencing the same digest together, a tag sharing the digest with a held
+// tag is held too
+func Blocking(client *registry.Repository, repository string, digests map[string]string) (*models.LegalHold, error) {
+	holds, err := dao.ListLegalHolds(&models.LegalHoldQuery{
+		Repository: repository,
+		Active:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	deleted := map[string]bool{}
+	for _, digest := range digests {
+		deleted[digest] = true
+	}
+	for _, hold := range holds {
+		if len(hold.Tag) == 0 {
+			return hold, nil
+		}
+		digest, ok := digests[hold.Tag]
+		if !ok {
+			d, exist, err := client.ManifestExist(hold.Tag)
+			if err != nil {
+				return nil, err
+			}
+			if !exist {
+				continue
+			}
+			digest = d
+		}
+		if deleted[digest] {
+			return hold, nil
+		}
+	}
+	return nil, nil
+}
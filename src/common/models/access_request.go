@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common"
+)
+
+const (
+	// AccessRequestTable is the name of table in DB that holds the access request object
+	AccessRequestTable = "access_request"
+
+	// AccessRequestPending ...
+	AccessRequestPending = "pending"
+	// AccessRequestApproved ...
+	AccessRequestApproved = "approved"
+	// AccessRequestDenied ...
+	AccessRequestDenied = "denied"
+)
+
+// AccessRequest holds the details of a request to access a project
+type AccessRequest struct {
+	ID            int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID     int64     `orm:"column(project_id)" json:"project_id"`
+	UserID        int       `orm:"column(user_id)" json:"user_id"`
+	Username      string    `orm:"-" json:"username"`
+	Role          int       `orm:"column(role)" json:"role_id"`
+	Justification string    `orm:"column(justification)" json:"justification"`
+	Status        string    `orm:"column(status)" json:"status"`
+	Reviewer      string    `orm:"column(reviewer)" json:"reviewer"`
+	CreationTime  time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime    time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (a *AccessRequest) TableName() string {
+	return AccessRequestTable
+}
+
+// AccessRequestQuery ...
+type AccessRequestQuery struct {
+	ProjectID int64
+	UserID    int
+	Status    string
+	Pagination
+}
+
+// AccessRequestReq ...
+type AccessRequestReq struct {
+	Role          int    `json:"role_id"`
+	Justification string `json:"justification"`
+}
+
+// Valid ...
+func (a *AccessRequestReq) Valid(v *validation.Validation) {
+	if len(a.Justification) == 0 {
+		v.SetError("justification", "cannot be empty")
+	}
+	if len(a.Justification) > 1024 {
+		v.SetError("justification", "max length is 1024")
+	}
+	switch a.Role {
+	case 0, common.RoleProjectAdmin, common.RoleMaster, common.RoleDeveloper, common.RoleGuest:
+	default:
+		v.SetError("role_id", "invalid role")
+	}
+}
@@ -28,6 +28,8 @@ type AccessLog struct {
 	GUID      string    `orm:"column(guid)"  json:"guid"`
 	Operation string    `orm:"column(operation)" json:"operation"`
 	OpTime    time.Time `orm:"column(op_time)" json:"op_time"`
+	// the detail of the operation, e.g. the changes of the fields in JSON
+	Detail string `orm:"column(detail)" json:"detail,omitempty"`
 }
 
 // LogQueryParam is used to set query conditions when listing
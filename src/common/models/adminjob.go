@@ -30,6 +30,7 @@ type AdminJob struct {
 	Kind         string    `orm:"column(job_kind)"  json:"job_kind"`
 	Cron         string    `orm:"column(cron_str)"  json:"cron_str"`
 	Status       string    `orm:"column(status)"  json:"job_status"`
+	Progress     string    `orm:"column(progress)" json:"progress"`
 	UUID         string    `orm:"column(job_uuid)" json:"-"`
 	Deleted      bool      `orm:"column(deleted)" json:"deleted"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ApprovalTable is the name of table in DB that holds the approvals
+const ApprovalTable = "approval"
+
+// the types of the approval
+const (
+	ApprovalAccessRequest   = "access_request"
+	ApprovalPromotion       = "promotion"
+	ApprovalProjectCreation = "project_creation"
+)
+
+// the status of the approval
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// Approval is an action waiting for the review of the project admins, or the system
+// admins if the project ID is 0
+type Approval struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Type         string    `orm:"column(type)" json:"type"`
+	ResourceID   int64     `orm:"column(resource_id)" json:"resource_id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	Requester    string    `orm:"column(requester)" json:"requester"`
+	Summary      string    `orm:"column(summary)" json:"summary"`
+	Payload      string    `orm:"column(payload)" json:"-"`
+	Status       string    `orm:"column(status)" json:"status"`
+	Reviewer     string    `orm:"column(reviewer)" json:"reviewer,omitempty"`
+	Comment      string    `orm:"column(comment)" json:"comment,omitempty"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (a *Approval) TableName() string {
+	return ApprovalTable
+}
+
+// ApprovalQuery ...
+type ApprovalQuery struct {
+	Type   string
+	Status string
+	// the approvals of all the projects are returned if it's nil
+	ProjectIDs []int64
+	Pagination
+}
+
+// ApprovalReview is the comment of the reviewer when approving or rejecting the approval
+type ApprovalReview struct {
+	Comment string `json:"comment"`
+}
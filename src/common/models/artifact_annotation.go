@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArtifactAnnotationTable is the name of table in DB that holds the CI metadata of tags
+const ArtifactAnnotationTable = "artifact_annotation"
+
+// the annotations of the image carrying the CI metadata, they are set as the labels of the
+// image config, e.g. "docker build --label org.opencontainers.image.revision=<commit>"
+const (
+	// AnnotationRevision is the source control revision defined by OCI
+	AnnotationRevision = "org.opencontainers.image.revision"
+	// AnnotationSource is the URL to the source code defined by OCI
+	AnnotationSource = "org.opencontainers.image.source"
+	// AnnotationBuildURL is the URL of the CI pipeline run building the image
+	AnnotationBuildURL = "io.goharbor.build.url"
+	// AnnotationBuildID is the ID of the CI pipeline run building the image
+	AnnotationBuildID = "io.goharbor.build.id"
+)
+
+// ArtifactAnnotation holds the CI metadata of the tag, the Labels holds all the labels
+// of the image config formatted by FormatAnnotationLabels for the full-text search
+type ArtifactAnnotation struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"-"`
+	Repository   string    `orm:"column(repository)" json:"repository"`
+	Tag          string    `orm:"column(tag)" json:"tag"`
+	Digest       string    `orm:"column(digest)" json:"digest"`
+	Revision     string    `orm:"column(revision)" json:"revision,omitempty"`
+	Source       string    `orm:"column(source)" json:"source,omitempty"`
+	BuildURL     string    `orm:"column(build_url)" json:"build_url,omitempty"`
+	BuildID      string    `orm:"column(build_id)" json:"build_id,omitempty"`
+	Labels       string    `orm:"column(labels)" json:"-"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"-"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"-"`
+}
+
+// TableName ...
+func (a *ArtifactAnnotation) TableName() string {
+	return ArtifactAnnotationTable
+}
+
+// ParseArtifactAnnotations reads the CI metadata from the labels of the image,
+// nil is returned if none of the annotations is set
+func ParseArtifactAnnotations(labels map[string]string) *ArtifactAnnotation {
+	annotation := &ArtifactAnnotation{
+		Revision: labels[AnnotationRevision],
+		Source:   labels[AnnotationSource],
+		BuildURL: labels[AnnotationBuildURL],
+		BuildID:  labels[AnnotationBuildID],
+	}
+	if len(annotation.Revision) == 0 && len(annotation.Source) == 0 &&
+		len(annotation.BuildURL) == 0 && len(annotation.BuildID) == 0 {
+		return nil
+	}
+	return annotation
+}
+
+// FormatAnnotationLabels formats the labels of the image as one "key=value" per line
+// in the order of the keys
+func FormatAnnotationLabels(labels map[string]string) string {
+	keys := []string{}
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := []string{}
+	for _, key := range keys {
+		lines = append(lines, key+"="+labels[key])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ArtifactAnnotationQuery ...
+type ArtifactAnnotationQuery struct {
+	Revision string
+	BuildURL string
+	BuildID  string
+	// the tags of the repositories in the projects are returned if it isn't nil
+	ProjectNames []string
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArtifactAnnotations(t *testing.T) {
+	// no annotations
+	assert.Nil(t, ParseArtifactAnnotations(nil))
+	assert.Nil(t, ParseArtifactAnnotations(map[string]string{
+		"maintainer": "harbor",
+	}))
+
+	annotation := ParseArtifactAnnotations(map[string]string{
+		"maintainer":       "harbor",
+		AnnotationRevision: "0a1b2c3d",
+		AnnotationSource:   "https://github.com/goharbor/harbor",
+		AnnotationBuildURL: "https://ci.example.com/builds/100",
+		AnnotationBuildID:  "100",
+	})
+	require.NotNil(t, annotation)
+	assert.Equal(t, "0a1b2c3d", annotation.Revision)
+	assert.Equal(t, "https://github.com/goharbor/harbor", annotation.Source)
+	assert.Equal(t, "https://ci.example.com/builds/100", annotation.BuildURL)
+	assert.Equal(t, "100", annotation.BuildID)
+
+	// only part of the annotations are set
+	annotation = ParseArtifactAnnotations(map[string]string{
+		AnnotationBuildID: "100",
+	})
+	require.NotNil(t, annotation)
+	assert.Equal(t, "100", annotation.BuildID)
+	assert.Equal(t, "", annotation.Revision)
+}
+
+func TestFormatAnnotationLabels(t *testing.T) {
+	assert.Equal(t, "", FormatAnnotationLabels(nil))
+	assert.Equal(t, "component=billing\nmaintainer=harbor\nticket=JIRA-1234",
+		FormatAnnotationLabels(map[string]string{
+			"ticket":     "JIRA-1234",
+			"maintainer": "harbor",
+			"component":  "billing",
+		}))
+}
@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// the status of the user when the auth mode is switched
+const (
+	// the user is found in the directory of the new auth mode
+	AuthModeUserMappable = "mappable"
+	// the user isn't found in the directory of the new auth mode, it can't log in afterwards
+	AuthModeUserUnmappable = "unmappable"
+	// the user can't be searched in the directory of the new auth mode
+	AuthModeUserUnknown = "unknown"
+	// the user is kept in the database but has no password, it can't log in until the
+	// password is reset
+	AuthModeUserPasswordReset = "password_reset_required"
+)
+
+// AuthModePreflight reports what would break if the auth mode is switched
+type AuthModePreflight struct {
+	CurrentMode string          `json:"current_mode"`
+	TargetMode  string          `json:"target_mode"`
+	Users       []*AuthModeUser `json:"users"`
+	// the memberships which grant nothing after the switch, they belong to the unmappable
+	// users or the groups which aren't supported by the new auth mode
+	OrphanedMemberships []*OrphanedMembership `json:"orphaned_memberships"`
+	// the count of the users keyed by the status
+	Summary map[string]int `json:"summary"`
+}
+
+// AuthModeUser is the status of the user when the auth mode is switched, the email and
+// real name are the ones in the directory of the new auth mode
+type AuthModeUser struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Realname string `json:"realname,omitempty"`
+}
+
+// OrphanedMembership is the project membership which grants nothing after the switch
+type OrphanedMembership struct {
+	ProjectID  int64  `json:"project_id"`
+	EntityType string `json:"entity_type"`
+	EntityName string `json:"entity_name"`
+	RoleName   string `json:"role_name"`
+	Reason     string `json:"reason"`
+}
+
+// AuthModeMigrationReq is the request to switch the auth mode, the switch is rejected if
+// any user can't be mapped unless it's forced
+type AuthModeMigrationReq struct {
+	AuthMode string `json:"auth_mode"`
+	Force    bool   `json:"force"`
+}
@@ -0,0 +1,27 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// ExternalAuthzSettings wraps the configurations to delegate the authorization
+// decision to an external policy agent
+type ExternalAuthzSettings struct {
+	// Endpoint is the URL the input document is posted to, empty means disabled
+	Endpoint string
+	// FailOpen allows the request when the endpoint is unreachable or returns error, the
+	// one read last time also applies when the settings fail to be read
+	FailOpen bool
+	// CacheTTL is the time in seconds the decisions are cached, 0 disables the cache
+	CacheTTL int
+}
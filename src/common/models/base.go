@@ -38,5 +38,55 @@ func init() {
 		new(UserGroup),
 		new(AdminJob),
 		new(JobLog),
-		new(Robot))
+		new(Robot),
+		new(RobotReplica),
+		new(AccessRequest),
+		new(RepoStar),
+		new(RepoSubscription),
+		new(RepoACL),
+		new(FreezeWindow),
+		new(ArtifactLint),
+		new(ProjectInvitation),
+		new(UserNotification),
+		new(FederationPeer),
+		new(RepoDeprecation),
+		new(LegalHold),
+		new(ComplianceOfficer),
+		new(ShareLink),
+		new(ProjectBootstrapHook),
+		new(ScanBatch),
+		new(TagAlias),
+		new(ProjectCustomMetadata),
+		new(ProjectReportSubscription),
+		new(UploadSession),
+		new(ProjectBlob),
+		new(RepoRetention),
+		new(TagPullTime),
+		new(RepoRedirect),
+		new(VulnDBImport),
+		new(ComplianceReport),
+		new(VulnFinding),
+		new(PromotionPipeline),
+		new(Promotion),
+		new(Approval),
+		new(ArtifactAnnotation),
+		new(TagHistory),
+		new(ProjectMerge),
+		new(RepoStorageHint),
+		new(BlobTransition),
+		new(BlockedDigest),
+		new(ChargebackReport),
+		new(ChargebackReportItem),
+		new(MetadataSyncState),
+		new(ScannerCABundle),
+		new(UsagePolicy),
+		new(UsagePolicyAck),
+		new(RepositoryExport),
+		new(VulException),
+		new(ArtifactProvenance),
+		new(RetiredTokenKey),
+		new(PendingRegistryEvent),
+		new(TaskLock),
+		new(ConfirmationToken),
+		new(BundleExport))
 }
@@ -0,0 +1,54 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// BlockedDigestTable is the name of table in DB that holds the blocked digests
+const BlockedDigestTable = "blocked_digest"
+
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// BlockedDigest is a digest of manifest or blob which is refused on pull and push across all the projects
+type BlockedDigest struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Digest       string    `orm:"column(digest)" json:"digest"`
+	Reason       string    `orm:"column(reason)" json:"reason"`
+	Creator      string    `orm:"column(creator)" json:"creator"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (b *BlockedDigest) TableName() string {
+	return BlockedDigestTable
+}
+
+// Valid ...
+func (b *BlockedDigest) Valid(v *validation.Validation) {
+	if !digestRegexp.MatchString(b.Digest) {
+		v.SetError("digest", "invalid digest "+b.Digest)
+	}
+}
+
+// BlockedDigestQuery is the query for the blocked digests
+type BlockedDigestQuery struct {
+	Digest string
+	Pagination
+}
@@ -0,0 +1,59 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// BundleExportRequest holds the projects to be exported into the offline bundle
+type BundleExportRequest struct {
+	Projects []string `json:"projects"`
+}
+
+// BundleExportTable is the name of table in DB that holds the exports of the offline bundles
+const BundleExportTable = "bundle_export"
+
+// the status of the bundle export
+const (
+	BundleExportRunning   = "running"
+	BundleExportSucceeded = "succeeded"
+	BundleExportFailed    = "failed"
+)
+
+// BundleExport records an export of the offline bundle, the bundle is written into the
+// storage in background and downloaded once it succeeds
+type BundleExport struct {
+	ID int64 `orm:"pk;auto;column(id)" json:"id"`
+	// the names of the projects separated by comma
+	Projects     string    `orm:"column(projects)" json:"projects"`
+	Status       string    `orm:"column(status)" json:"status"`
+	Message      string    `orm:"column(message)" json:"message,omitempty"`
+	Size         int64     `orm:"column(size)" json:"size"`
+	Creator      string    `orm:"column(creator)" json:"creator"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (b *BundleExport) TableName() string {
+	return BundleExportTable
+}
+
+// BundleExportQuery ...
+type BundleExportQuery struct {
+	Status string
+	Pagination
+}
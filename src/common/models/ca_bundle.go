@@ -0,0 +1,52 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ScannerCABundleTable is the name of table in DB that holds the CA bundles of the scanners
+const ScannerCABundleTable = "scanner_ca_bundle"
+
+// ScannerCABundle is the custom CA bundle trusted by the clients of the scanner adapter,
+// the bundle is encrypted in the database
+type ScannerCABundle struct {
+	ID           int64     `orm:"pk;auto;column(id)"`
+	ScannerID    string    `orm:"column(scanner_id)"`
+	CABundle     string    `orm:"column(ca_bundle)"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now"`
+}
+
+// TableName ...
+func (s *ScannerCABundle) TableName() string {
+	return ScannerCABundleTable
+}
+
+// CABundleReq is the request to upload the CA bundle, the certificates are in PEM
+type CABundleReq struct {
+	CABundle string `json:"ca_bundle"`
+}
+
+// Certificate is the summary of a certificate in the CA bundle, the bundle itself
+// isn't returned by the API
+type Certificate struct {
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	NotBefore   time.Time `json:"not_before"`
+	NotAfter    time.Time `json:"not_after"`
+	Fingerprint string    `json:"fingerprint"`
+}
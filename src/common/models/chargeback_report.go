@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ChargebackReportTable is the name of table in DB that holds the chargeback reports
+const ChargebackReportTable = "chargeback_report"
+
+// ChargebackReportItemTable is the name of table in DB that holds the usage of projects in the chargeback reports
+const ChargebackReportItemTable = "chargeback_report_item"
+
+// the status of the chargeback report
+const (
+	ChargebackReportRunning   = "running"
+	ChargebackReportSucceeded = "succeeded"
+	ChargebackReportFailed    = "failed"
+)
+
+// ChargebackReport records the chargeback report of a billing period, the period
+// includes the start time and excludes the end time
+type ChargebackReport struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	StartTime    time.Time `orm:"column(start_time)" json:"start_time"`
+	EndTime      time.Time `orm:"column(end_time)" json:"end_time"`
+	Status       string    `orm:"column(status)" json:"status"`
+	Message      string    `orm:"column(message)" json:"message,omitempty"`
+	Creator      string    `orm:"column(creator)" json:"creator"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (c *ChargebackReport) TableName() string {
+	return ChargebackReportTable
+}
+
+// ChargebackReportItem is the usage of a project in the billing period of the report: the
+// storage is the size of the blobs when the report is generated, the pull bytes are the
+// bytes served by the registry API and the job minutes are the durations of the scan and
+// replication jobs completed in the period
+type ChargebackReportItem struct {
+	ID                    int64   `orm:"pk;auto;column(id)" json:"-"`
+	ReportID              int64   `orm:"column(report_id)" json:"-"`
+	ProjectID             int64   `orm:"column(project_id)" json:"project_id"`
+	ProjectName           string  `orm:"column(project_name)" json:"project_name"`
+	StorageBytes          int64   `orm:"column(storage_bytes)" json:"storage_bytes"`
+	PullBytes             int64   `orm:"column(pull_bytes)" json:"pull_bytes"`
+	ScanJobMinutes        float64 `orm:"column(scan_job_minutes)" json:"scan_job_minutes"`
+	ReplicationJobMinutes float64 `orm:"column(replication_job_minutes)" json:"replication_job_minutes"`
+}
+
+// TableName ...
+func (c *ChargebackReportItem) TableName() string {
+	return ChargebackReportItemTable
+}
+
+// ChargebackReportQuery ...
+type ChargebackReportQuery struct {
+	Status string
+	Pagination
+}
+
+// ChargebackReportRequest is the request to generate the chargeback report of a billing period
+type ChargebackReportRequest struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
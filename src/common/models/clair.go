@@ -67,6 +67,12 @@ type ClairVulnerability struct {
 	FixedIn       []ClairFeature         `json:"FixedIn,omitempty"`
 }
 
+// ClairVulnerabilityEnvelope ...
+type ClairVulnerabilityEnvelope struct {
+	Vulnerability *ClairVulnerability `json:"Vulnerability,omitempty"`
+	Error         *ClairError         `json:"Error,omitempty"`
+}
+
 // ClairError ...
 type ClairError struct {
 	Message string `json:"Message,omitempty"`
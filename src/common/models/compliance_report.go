@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ComplianceReportTable is the name of table in DB that holds the compliance reports of projects
+const ComplianceReportTable = "compliance_report"
+
+// the status of the compliance report
+const (
+	ComplianceReportRunning   = "running"
+	ComplianceReportSucceeded = "succeeded"
+	ComplianceReportFailed    = "failed"
+)
+
+// ComplianceReport records a compliance report bundle of a project, the bundle
+// is generated in background and downloaded by the auditors once it succeeds
+type ComplianceReport struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	StartTime    time.Time `orm:"column(start_time)" json:"start_time"`
+	EndTime      time.Time `orm:"column(end_time)" json:"end_time"`
+	Status       string    `orm:"column(status)" json:"status"`
+	Message      string    `orm:"column(message)" json:"message,omitempty"`
+	Size         int64     `orm:"column(size)" json:"size"`
+	Creator      string    `orm:"column(creator)" json:"creator"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (c *ComplianceReport) TableName() string {
+	return ComplianceReportTable
+}
+
+// ComplianceReportQuery ...
+type ComplianceReportQuery struct {
+	ProjectID int64
+	Status    string
+	Pagination
+}
+
+// ComplianceReportRequest is the request to generate a compliance report, the audit
+// logs between the start and end time are included
+type ComplianceReportRequest struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
@@ -14,6 +14,11 @@
 
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 /*
 // Authentication ...
 type Authentication struct {
@@ -29,6 +34,33 @@ type Database struct {
 	PostGreSQL *PostGreSQL `json:"postgresql,omitempty"`
 }
 
+// AccessLogStore is the store which the access logs are written to and queried from
+type AccessLogStore struct {
+	// "database" or "elasticsearch", "database" is used if it's empty
+	Type string `json:"type"`
+	// the settings of Elasticsearch used in the "elasticsearch" store
+	ESURL      string `json:"es_url,omitempty"`
+	ESIndex    string `json:"es_index,omitempty"`
+	ESUsername string `json:"es_username,omitempty"`
+	ESPassword string `json:"es_password,omitempty"`
+}
+
+// MetadataSync is the settings of the metadata sync with the peer instance
+type MetadataSync struct {
+	// the sync is disabled if the URL of the peer is empty
+	PeerURL  string `json:"peer_url"`
+	Secret   string `json:"secret,omitempty"`
+	Priority int    `json:"priority"`
+}
+
+// RequestSizeLimits are the max sizes in bytes of the requests, 0 means no limit
+type RequestSizeLimits struct {
+	JSONBody    int64
+	ChartUpload int64
+	// the size of the query string of log queries
+	LogQuery int64
+}
+
 // MySQL ...
 type MySQL struct {
 	Host     string `json:"host"`
@@ -51,6 +83,15 @@ type PostGreSQL struct {
 	Password string `json:"password,omitempty"`
 	Database string `json:"database"`
 	SSLMode  string `json:"sslmode"`
+	// the CA certificate to verify the server in the sslmode "verify-ca" and "verify-full"
+	SSLRootCert string `json:"sslrootcert,omitempty"`
+	// "password", "aws_iam" or "azure_msi", "password" is used if it's empty
+	AuthMode string `json:"auth_mode,omitempty"`
+	// the region of AWS RDS used in the "aws_iam" mode
+	AWSRegion string `json:"aws_region,omitempty"`
+	// the client ID of the user assigned identity used in the "azure_msi" mode,
+	// the system assigned identity is used if it's empty
+	AzureClientID string `json:"azure_client_id,omitempty"`
 }
 
 // Email ...
@@ -65,6 +106,57 @@ type Email struct {
 	Insecure bool   `json:"insecure"`
 }
 
+// TokenExchange holds the settings of exchanging the service account tokens of Kubernetes
+// for the registry tokens, the exchange is disabled if the issuer or the audience is empty
+type TokenExchange struct {
+	Issuer     string                   `json:"issuer"`
+	PublicKeys string                   `json:"public_keys"`
+	Audience   string                   `json:"audience"`
+	Bindings   []*ServiceAccountBinding `json:"bindings"`
+	Expiration int                      `json:"expiration"` // in minute
+}
+
+// ServiceAccountBinding binds the service accounts of Kubernetes to a project, whose
+// repositories the exchanged registry tokens can pull from
+type ServiceAccountBinding struct {
+	Namespace string `json:"namespace"`
+	// "*" matches all the service accounts of the namespace
+	ServiceAccount string `json:"service_account"`
+	Project        string `json:"project"`
+}
+
+// Match returns whether the service account is bound by the binding
+func (b *ServiceAccountBinding) Match(namespace, serviceAccount string) bool {
+	return b.Namespace == namespace && (b.ServiceAccount == "*" || b.ServiceAccount == serviceAccount)
+}
+
+// ParseServiceAccountBindings parses the comma-separated bindings in the form of
+// "<namespace>/<service account>:<project>", e.g. "ci/builder:library,ci/*:base"
+func ParseServiceAccountBindings(s string) ([]*ServiceAccountBinding, error) {
+	bindings := []*ServiceAccountBinding{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		i := strings.LastIndex(item, ":")
+		j := strings.Index(item, "/")
+		if i < 0 || j < 0 || j > i {
+			return nil, fmt.Errorf("invalid binding %q, should be <namespace>/<service account>:<project>", item)
+		}
+		binding := &ServiceAccountBinding{
+			Namespace:      item[:j],
+			ServiceAccount: item[j+1 : i],
+			Project:        item[i+1:],
+		}
+		if len(binding.Namespace) == 0 || len(binding.ServiceAccount) == 0 || len(binding.Project) == 0 {
+			return nil, fmt.Errorf("invalid binding %q, should be <namespace>/<service account>:<project>", item)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
 /*
 // Registry ...
 type Registry struct {
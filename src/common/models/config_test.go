@@ -0,0 +1,43 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceAccountBindings(t *testing.T) {
+	bindings, err := ParseServiceAccountBindings("")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(bindings))
+
+	bindings, err = ParseServiceAccountBindings("ci/builder:library, ci/*:base")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(bindings))
+	assert.Equal(t, &ServiceAccountBinding{Namespace: "ci", ServiceAccount: "builder", Project: "library"}, bindings[0])
+	assert.True(t, bindings[0].Match("ci", "builder"))
+	assert.False(t, bindings[0].Match("ci", "deployer"))
+	assert.False(t, bindings[0].Match("default", "builder"))
+	assert.True(t, bindings[1].Match("ci", "deployer"))
+
+	for _, s := range []string{"library", "ci:library", "ci/builder", "/builder:library", "ci/:library", "ci/builder:"} {
+		_, err = ParseServiceAccountBindings(s)
+		assert.NotNil(t, err, s)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ConfirmationTokenTable is the name of table in DB that holds the confirmation tokens
+const ConfirmationTokenTable = "confirmation_token"
+
+// ConfirmationToken is the token confirming a destructive operation of the user, it can
+// only be used once before it expires
+type ConfirmationToken struct {
+	Token        string    `orm:"pk;column(token)" json:"token"`
+	Username     string    `orm:"column(username)" json:"username"`
+	Method       string    `orm:"column(method)" json:"method"`
+	Path         string    `orm:"column(path)" json:"path"`
+	ExpiresAt    time.Time `orm:"column(expires_at)" json:"expires_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (c *ConfirmationToken) TableName() string {
+	return ConfirmationTokenTable
+}
@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ContentTrustPattern limits the enforcement of content trust to the tags matching it, it's
+// in format "<repository>:<tag>" and the repository is relative to the project, e.g.
+// "app/*:release-*". The pattern without ":" matches the tags of all the repositories,
+// the Repository of it is empty.
+// The glob syntax is the one of path.Match, so "*" doesn't match "/".
+type ContentTrustPattern struct {
+	Repository string
+	Tag        string
+}
+
+// ParseContentTrustPatterns parses the comma separated patterns
+func ParseContentTrustPatterns(value string) ([]*ContentTrustPattern, error) {
+	patterns := []*ContentTrustPattern{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		pattern := &ContentTrustPattern{
+			Tag: s,
+		}
+		if i := strings.LastIndex(s, ":"); i >= 0 {
+			pattern.Repository, pattern.Tag = s[:i], s[i+1:]
+			if len(pattern.Repository) == 0 {
+				return nil, fmt.Errorf("invalid content trust pattern %s", s)
+			}
+		}
+		if len(pattern.Tag) == 0 {
+			return nil, fmt.Errorf("invalid content trust pattern %s", s)
+		}
+		for _, p := range []string{pattern.Repository, pattern.Tag} {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("invalid content trust pattern %s: %v", s, err)
+			}
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Match returns whether the pattern matches the repository, which is relative to the
+// project, and the reference. The tag of the image pulled by digest is unknown, so the
+// reference in digest matches any tag.
+func (c *ContentTrustPattern) Match(repository, reference string) bool {
+	if len(c.Repository) > 0 {
+		if matched, _ := path.Match(c.Repository, repository); !matched {
+			return false
+		}
+	}
+	if strings.HasPrefix(reference, "sha256:") {
+		return true
+	}
+	matched, _ := path.Match(c.Tag, reference)
+	return matched
+}
@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentTrustPatterns(t *testing.T) {
+	patterns, err := ParseContentTrustPatterns("release-*, app/*:v*,")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(patterns))
+	assert.Equal(t, "", patterns[0].Repository)
+	assert.Equal(t, "release-*", patterns[0].Tag)
+	assert.Equal(t, "app/*", patterns[1].Repository)
+	assert.Equal(t, "v*", patterns[1].Tag)
+
+	for _, value := range []string{"app:", ":v1", "["} {
+		_, err = ParseContentTrustPatterns(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestContentTrustRequired(t *testing.T) {
+	p := &Project{Name: "library"}
+	// content trust isn't enabled
+	assert.False(t, p.ContentTrustRequired("library/app", "release-1"))
+
+	p.SetMetadata(ProMetaEnableContentTrust, "true")
+	assert.True(t, p.ContentTrustRequired("library/app", "dev"))
+
+	p.SetMetadata(ProMetaTrustPatterns, "release-*,tools/*:stable")
+	cases := []struct {
+		repository string
+		reference  string
+		required   bool
+	}{
+		{"library/app", "release-1.0", true},
+		{"library/app", "dev", false},
+		{"library/tools/helm", "stable", true},
+		{"library/tools/helm", "dev", false},
+		{"library/tools/helm", "release-2", true},
+		{"library/app", "stable", false},
+		// the tag of the image pulled by digest is unknown
+		{"library/app", "sha256:1359608115b94599e5641638bac5aef1ddfaa79bb96057ebf41ebc8d33acf8a7", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.required, p.ContentTrustRequired(c.repository, c.reference), "%s:%s", c.repository, c.reference)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// RepoDeprecationTable is the name of table in DB that holds the deprecations of repositories and tags
+	RepoDeprecationTable = "repository_deprecation"
+
+	// MaxDeprecationMessageLength is the max length of the message of a deprecation
+	MaxDeprecationMessageLength = 1024
+)
+
+// RepoDeprecation marks the repository or one of its tags as deprecated, the pulls still
+// succeed but are warned with the message and counted as hits
+type RepoDeprecation struct {
+	ID         int64  `orm:"pk;auto;column(id)" json:"id"`
+	Repository string `orm:"column(repository)" json:"repository"`
+	// the deprecated tag, empty means the whole repository is deprecated
+	Tag     string `orm:"column(tag)" json:"tag,omitempty"`
+	Message string `orm:"column(message)" json:"message"`
+	// the repository or image to use instead, e.g. "library/app:2.0"
+	Replacement  string     `orm:"column(replacement)" json:"replacement,omitempty"`
+	Creator      string     `orm:"column(creator)" json:"creator"`
+	HitCount     int64      `orm:"column(hit_count)" json:"hit_count"`
+	LastHitTime  *time.Time `orm:"column(last_hit_time);null" json:"last_hit_time,omitempty"`
+	CreationTime time.Time  `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time  `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RepoDeprecation) TableName() string {
+	return RepoDeprecationTable
+}
+
+// Valid ...
+func (r *RepoDeprecation) Valid(v *validation.Validation) {
+	if len(strings.TrimSpace(r.Message)) == 0 {
+		v.SetError("message", "cannot be empty")
+	} else if len(r.Message) > MaxDeprecationMessageLength {
+		v.SetError("message", fmt.Sprintf("max length is %d", MaxDeprecationMessageLength))
+	}
+	if len(r.Replacement) > 255 {
+		v.SetError("replacement", "max length is 255")
+	}
+}
+
+// Warning returns the warning attached to the pulls of the deprecated image, it's in
+// the format of the "Warning" header: 299 - "<text>"
+func (r *RepoDeprecation) Warning() string {
+	target := r.Repository
+	if len(r.Tag) > 0 {
+		target += ":" + r.Tag
+	}
+	text := fmt.Sprintf("%s is deprecated: %s", target, r.Message)
+	if len(r.Replacement) > 0 {
+		text += fmt.Sprintf(", use %s instead", r.Replacement)
+	}
+	// the header value must be a single line and the text a quoted string
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+	return fmt.Sprintf(`299 - "%s"`, text)
+}
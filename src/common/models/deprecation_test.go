@@ -0,0 +1,46 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoDeprecationValid(t *testing.T) {
+	v := &validation.Validation{}
+	(&RepoDeprecation{Message: " "}).Valid(v)
+	assert.True(t, v.HasErrors())
+
+	v = &validation.Validation{}
+	(&RepoDeprecation{Message: "unmaintained"}).Valid(v)
+	assert.False(t, v.HasErrors())
+}
+
+func TestRepoDeprecationWarning(t *testing.T) {
+	deprecation := &RepoDeprecation{
+		Repository: "library/app",
+		Message:    "unmaintained",
+	}
+	assert.Equal(t, `299 - "library/app is deprecated: unmaintained"`, deprecation.Warning())
+
+	deprecation.Tag = "1.0"
+	deprecation.Message = "the \"1.x\" line\nis vulnerable"
+	deprecation.Replacement = "library/app:2.0"
+	assert.Equal(t, `299 - "library/app:1.0 is deprecated: the \"1.x\" line is vulnerable, use library/app:2.0 instead"`,
+		deprecation.Warning())
+}
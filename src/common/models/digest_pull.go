@@ -0,0 +1,39 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ParseDigestPullPatterns parses the comma separated patterns of the repositories which are
+// pulled by digest only, the repositories are relative to the project, e.g. "app/*,base".
+// The glob syntax is the one of path.Match, so "*" doesn't match "/".
+func ParseDigestPullPatterns(value string) ([]string, error) {
+	patterns := []string{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid digest pull pattern %s: %v", s, err)
+		}
+		patterns = append(patterns, s)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,44 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDigestPullPatterns(t *testing.T) {
+	patterns, err := ParseDigestPullPatterns(" app/*, ,base")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"app/*", "base"}, patterns)
+
+	_, err = ParseDigestPullPatterns("app/[")
+	assert.NotNil(t, err)
+}
+
+func TestDigestPullRequired(t *testing.T) {
+	project := &Project{
+		Name: "library",
+	}
+	assert.False(t, project.DigestPullRequired("library/app/web"))
+
+	project.SetMetadata(ProMetaDigestPullRepos, "app/*,base")
+	assert.True(t, project.DigestPullRequired("library/app/web"))
+	assert.True(t, project.DigestPullRequired("library/base"))
+	assert.False(t, project.DigestPullRequired("library/app/web/v2"))
+	assert.False(t, project.DigestPullRequired("library/tools"))
+}
@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// FederationPeerTable is the name of table in DB that holds the peer instances of the federation
+	FederationPeerTable = "federation_peer"
+	// DefaultFederationTimeout is the default timeout in seconds of the requests to a peer
+	DefaultFederationTimeout = 10
+	// MaxFederationTimeout is the max timeout in seconds of the requests to a peer
+	MaxFederationTimeout = 60
+)
+
+// FederationPeer is another Harbor instance which the global search fans out to, the
+// results visible to the user of the credential on the peer are merged
+type FederationPeer struct {
+	ID       int64  `orm:"pk;auto;column(id)" json:"id"`
+	Name     string `orm:"column(name)" json:"name"`
+	URL      string `orm:"column(url)" json:"url"`
+	Username string `orm:"column(username)" json:"username"`
+	// it's encrypted in DB and never returned by the API
+	Password string `orm:"column(password)" json:"password,omitempty"`
+	Insecure bool   `orm:"column(insecure)" json:"insecure"`
+	// the timeout in seconds of the requests to the peer
+	Timeout      int       `orm:"column(timeout)" json:"timeout"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (f *FederationPeer) TableName() string {
+	return FederationPeerTable
+}
+
+// Valid ...
+func (f *FederationPeer) Valid(v *validation.Validation) {
+	if len(f.Name) == 0 {
+		v.SetError("name", "empty name")
+	}
+	u, err := url.Parse(f.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		v.SetError("url", "invalid URL "+f.URL)
+	}
+	if f.Timeout < 0 || f.Timeout > MaxFederationTimeout {
+		v.SetError("timeout", "the timeout must be between 0 and 60 seconds")
+	}
+}
+
+// PeerSearchStatus is the status of the global search fanned out to a peer
+type PeerSearchStatus struct {
+	Name string `json:"name"`
+	// the count of the repositories merged from the peer
+	Repository int64 `json:"repository"`
+	// the error of the search, e.g. the peer can't be reached in time
+	Error string `json:"error,omitempty"`
+}
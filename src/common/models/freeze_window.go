@@ -0,0 +1,127 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// FreezeWindowTable is the name of table in DB that holds the freeze windows of projects
+const FreezeWindowTable = "freeze_window"
+
+// how the freeze window repeats
+const (
+	FreezeWindowRepeatNone   = "none"
+	FreezeWindowRepeatDaily  = "daily"
+	FreezeWindowRepeatWeekly = "weekly"
+)
+
+// FreezeWindow is a period during which the pushes and tag deletions of the project are
+// rejected, e.g. for a release freeze. The repeating windows recur every day or week from
+// the first occurrence
+type FreezeWindow struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	StartTime    time.Time `orm:"column(start_time)" json:"start_time"`
+	EndTime      time.Time `orm:"column(end_time)" json:"end_time"`
+	Repeat       string    `orm:"column(repeat)" json:"repeat"`
+	Message      string    `orm:"column(message)" json:"message"`
+	Creator      string    `orm:"column(creator)" json:"creator"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (f *FreezeWindow) TableName() string {
+	return FreezeWindowTable
+}
+
+// Valid ...
+func (f *FreezeWindow) Valid(v *validation.Validation) {
+	if f.StartTime.IsZero() || f.EndTime.IsZero() {
+		v.SetError("start_time", "the start and end time cannot be empty")
+		return
+	}
+	if !f.EndTime.After(f.StartTime) {
+		v.SetError("end_time", "must be after the start time")
+		return
+	}
+	if len(f.Repeat) == 0 {
+		f.Repeat = FreezeWindowRepeatNone
+	}
+	if f.Repeat != FreezeWindowRepeatNone && f.Repeat != FreezeWindowRepeatDaily &&
+		f.Repeat != FreezeWindowRepeatWeekly {
+		v.SetError("repeat", "invalid repeat "+f.Repeat)
+		return
+	}
+	if period := f.period(); period > 0 && f.EndTime.Sub(f.StartTime) >= period {
+		v.SetError("end_time", fmt.Sprintf("the window must be shorter than the %s repeat", f.Repeat))
+	}
+}
+
+func (f *FreezeWindow) period() time.Duration {
+	switch f.Repeat {
+	case FreezeWindowRepeatDaily:
+		return 24 * time.Hour
+	case FreezeWindowRepeatWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Until returns the end of the occurrence of the window which the time is in, the zero
+// time is returned if the time isn't in any occurrence
+func (f *FreezeWindow) Until(t time.Time) time.Time {
+	if t.Before(f.StartTime) {
+		return time.Time{}
+	}
+	end := f.EndTime
+	if period := f.period(); period > 0 {
+		// the start of the occurrence is the latest one not after the time
+		n := t.Sub(f.StartTime) / period
+		end = end.Add(n * period)
+	}
+	if !t.Before(end) {
+		return time.Time{}
+	}
+	return end
+}
+
+// GetMessage returns the message returned to the rejected requests
+func (f *FreezeWindow) GetMessage(until time.Time) string {
+	msg := fmt.Sprintf("The project is frozen until %s, pushes and tag deletions are rejected", until.UTC().Format(time.RFC3339))
+	if len(f.Message) > 0 {
+		msg += ": " + f.Message
+	}
+	return msg
+}
+
+// ActiveFreezeWindow returns the window which the time is in and the end of its occurrence,
+// the one ending the latest is returned if the time is in several windows. Nil is returned
+// if the time isn't in any window
+func ActiveFreezeWindow(windows []*FreezeWindow, t time.Time) (*FreezeWindow, time.Time) {
+	var active *FreezeWindow
+	var until time.Time
+	for _, window := range windows {
+		end := window.Until(t)
+		if !end.IsZero() && end.After(until) {
+			active, until = window, end
+		}
+	}
+	return active, until
+}
@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeWindowValid(t *testing.T) {
+	start := time.Date(2019, 6, 3, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		window *FreezeWindow
+		valid  bool
+	}{
+		{&FreezeWindow{}, false},
+		{&FreezeWindow{StartTime: start, EndTime: start}, false},
+		{&FreezeWindow{StartTime: start, EndTime: start.Add(time.Hour), Repeat: "monthly"}, false},
+		{&FreezeWindow{StartTime: start, EndTime: start.Add(24 * time.Hour), Repeat: FreezeWindowRepeatDaily}, false},
+		{&FreezeWindow{StartTime: start, EndTime: start.Add(24 * time.Hour), Repeat: FreezeWindowRepeatWeekly}, true},
+		{&FreezeWindow{StartTime: start, EndTime: start.Add(30 * 24 * time.Hour)}, true},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.window.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "%+v", c.window)
+	}
+
+	// the repeat is none by default
+	window := &FreezeWindow{StartTime: start, EndTime: start.Add(time.Hour)}
+	window.Valid(&validation.Validation{})
+	assert.Equal(t, FreezeWindowRepeatNone, window.Repeat)
+}
+
+func TestFreezeWindowUntil(t *testing.T) {
+	start := time.Date(2019, 6, 3, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	window := &FreezeWindow{StartTime: start, EndTime: end, Repeat: FreezeWindowRepeatNone}
+	assert.True(t, window.Until(start.Add(-time.Second)).IsZero())
+	assert.Equal(t, end, window.Until(start))
+	assert.Equal(t, end, window.Until(start.Add(time.Hour)))
+	assert.True(t, window.Until(end).IsZero())
+	assert.True(t, window.Until(start.Add(24*time.Hour)).IsZero())
+
+	window.Repeat = FreezeWindowRepeatDaily
+	assert.Equal(t, end.Add(24*time.Hour), window.Until(start.Add(25*time.Hour)))
+	assert.True(t, window.Until(start.Add(27*time.Hour)).IsZero())
+
+	window.Repeat = FreezeWindowRepeatWeekly
+	assert.True(t, window.Until(start.Add(25*time.Hour)).IsZero())
+	assert.Equal(t, end.Add(14*24*time.Hour), window.Until(start.Add(14*24*time.Hour+time.Hour)))
+}
+
+func TestActiveFreezeWindow(t *testing.T) {
+	start := time.Date(2019, 6, 3, 9, 0, 0, 0, time.UTC)
+	windows := []*FreezeWindow{
+		{ID: 1, StartTime: start, EndTime: start.Add(2 * time.Hour), Repeat: FreezeWindowRepeatDaily},
+		{ID: 2, StartTime: start, EndTime: start.Add(3 * time.Hour), Repeat: FreezeWindowRepeatNone,
+			Message: "release 1.0"},
+	}
+
+	window, until := ActiveFreezeWindow(windows, start.Add(-time.Hour))
+	assert.Nil(t, window)
+	assert.True(t, until.IsZero())
+
+	window, until = ActiveFreezeWindow(windows, start.Add(time.Hour))
+	require.NotNil(t, window)
+	assert.Equal(t, int64(2), window.ID)
+	assert.Equal(t, start.Add(3*time.Hour), until)
+	assert.Equal(t, "The project is frozen until 2019-06-03T12:00:00Z, pushes and tag deletions are rejected: release 1.0",
+		window.GetMessage(until))
+
+	window, until = ActiveFreezeWindow(windows, start.Add(25*time.Hour))
+	require.NotNil(t, window)
+	assert.Equal(t, int64(1), window.ID)
+	assert.Equal(t, start.Add(26*time.Hour), until)
+}
@@ -11,6 +11,7 @@ const JobLogTable = "job_log"
 type JobLog struct {
 	LogID        int       `orm:"pk;auto;column(log_id)" json:"log_id"`
 	UUID         string    `orm:"column(job_uuid)" json:"uuid"`
+	JobName      string    `orm:"column(job_name)" json:"job_name"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	Content      string    `orm:"column(content)" json:"content"`
 }
@@ -19,3 +20,10 @@ type JobLog struct {
 func (a *JobLog) TableName() string {
 	return JobLogTable
 }
+
+// JobLogUsage is the count and the total bytes of the logs of the job
+type JobLogUsage struct {
+	JobName string `orm:"column(job_name)" json:"job_name"`
+	Count   int64  `orm:"column(count)" json:"count"`
+	Size    int64  `orm:"column(size)" json:"size"`
+}
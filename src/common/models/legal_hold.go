@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// LegalHoldTable is the name of table in DB that holds the legal holds of repositories and tags
+	LegalHoldTable = "legal_hold"
+	// ComplianceOfficerTable is the name of table in DB that holds the users managing the legal holds
+	ComplianceOfficerTable = "compliance_officer"
+
+	// MaxLegalHoldReasonLength is the max length of the reasons of placing and releasing a hold
+	MaxLegalHoldReasonLength = 1024
+)
+
+// LegalHold keeps the repository or one of its tags from being deleted, by the users, the
+// retention or the moving of the repository, until it's released. The released holds are
+// kept as the history
+type LegalHold struct {
+	ID         int64  `orm:"pk;auto;column(id)" json:"id"`
+	Repository string `orm:"column(repository)" json:"repository"`
+	// the held tag, empty means the whole repository is held
+	Tag           string     `orm:"column(tag)" json:"tag,omitempty"`
+	Reason        string     `orm:"column(reason)" json:"reason"`
+	Creator       string     `orm:"column(creator)" json:"creator"`
+	CreationTime  time.Time  `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	Releaser      string     `orm:"column(releaser)" json:"releaser,omitempty"`
+	ReleaseReason string     `orm:"column(release_reason)" json:"release_reason,omitempty"`
+	ReleaseTime   *time.Time `orm:"column(release_time);null" json:"release_time,omitempty"`
+}
+
+// TableName ...
+func (l *LegalHold) TableName() string {
+	return LegalHoldTable
+}
+
+// Valid ...
+func (l *LegalHold) Valid(v *validation.Validation) {
+	if len(strings.TrimSpace(l.Reason)) == 0 {
+		v.SetError("reason", "cannot be empty")
+	} else if len(l.Reason) > MaxLegalHoldReasonLength {
+		v.SetError("reason", fmt.Sprintf("max length is %d", MaxLegalHoldReasonLength))
+	}
+}
+
+// Target returns the held repository or image, e.g. "library/app:1.0"
+func (l *LegalHold) Target() string {
+	if len(l.Tag) == 0 {
+		return l.Repository
+	}
+	return l.Repository + ":" + l.Tag
+}
+
+// LegalHoldRelease is the request to release a legal hold
+type LegalHoldRelease struct {
+	Reason string `json:"reason"`
+}
+
+// Valid ...
+func (l *LegalHoldRelease) Valid(v *validation.Validation) {
+	if len(l.Reason) > MaxLegalHoldReasonLength {
+		v.SetError("reason", fmt.Sprintf("max length is %d", MaxLegalHoldReasonLength))
+	}
+}
+
+// LegalHoldQuery ...
+type LegalHoldQuery struct {
+	// the holds of the repositories in the project
+	Project    string
+	Repository string
+	// only the holds which aren't released
+	Active bool
+	Pagination
+}
+
+// ComplianceOfficer is the user who can place and release the legal holds, it's granted by the system admins
+type ComplianceOfficer struct {
+	UserID       int       `orm:"pk;column(user_id)" json:"user_id"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (c *ComplianceOfficer) TableName() string {
+	return ComplianceOfficerTable
+}
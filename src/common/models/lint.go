@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// ArtifactLintTable is the name of table in DB that holds the lint findings of tags
+const ArtifactLintTable = "artifact_lint"
+
+// the rules of the linter
+const (
+	// LintRuleLayerCount flags the images with more layers than the max count
+	LintRuleLayerCount = "layer_count"
+	// LintRuleMissingLabel flags the images without the required labels
+	LintRuleMissingLabel = "missing_label"
+	// LintRuleLatestOnly flags the images pushed as the only tag "latest" of the repository
+	LintRuleLatestOnly = "latest_only"
+	// LintRuleRootUser flags the images running as root
+	LintRuleRootUser = "root_user"
+)
+
+// LintPolicy decides which rules the pushed images are linted with, the rules not set are
+// skipped. The images with findings are rejected if Reject is true, otherwise the findings
+// are recorded only.
+type LintPolicy struct {
+	// the max count of the layers, 0 means unlimited
+	MaxLayers int `json:"max_layers"`
+	// the labels which must be set in the image config, e.g. "org.opencontainers.image.source"
+	RequiredLabels []string `json:"required_labels"`
+	// flag the images pushed as the only tag "latest" of the repository
+	ForbidLatestOnly bool `json:"forbid_latest_only"`
+	// flag the images whose user is root or not set
+	ForbidRootUser bool `json:"forbid_root_user"`
+	Reject         bool `json:"reject"`
+}
+
+// Valid ...
+func (l *LintPolicy) Valid(v *validation.Validation) {
+	if l.MaxLayers < 0 {
+		v.SetError("max_layers", "cannot be negative")
+	}
+	for _, label := range l.RequiredLabels {
+		if len(label) == 0 {
+			v.SetError("required_labels", "cannot contain empty label")
+			return
+		}
+	}
+}
+
+// ParseLintPolicy parses the lint policy in JSON and validates it
+func ParseLintPolicy(value string) (*LintPolicy, error) {
+	policy := &LintPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, err
+	}
+	v := &validation.Validation{}
+	policy.Valid(v)
+	if v.HasErrors() {
+		return nil, fmt.Errorf("%s %s", v.Errors[0].Field, v.Errors[0].Message)
+	}
+	return policy, nil
+}
+
+// LintFinding is a violation of a rule of the lint policy found in the image
+type LintFinding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ArtifactLint holds the lint findings of the tag when it was pushed, the Findings are
+// stored in JSON
+type ArtifactLint struct {
+	ID           int64          `orm:"pk;auto;column(id)" json:"-"`
+	Repository   string         `orm:"column(repository)" json:"repository"`
+	Tag          string         `orm:"column(tag)" json:"tag"`
+	Digest       string         `orm:"column(digest)" json:"digest"`
+	FindingsStr  string         `orm:"column(findings)" json:"-"`
+	Findings     []*LintFinding `orm:"-" json:"findings"`
+	CreationTime time.Time      `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time      `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (a *ArtifactLint) TableName() string {
+	return ArtifactLintTable
+}
@@ -0,0 +1,45 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLintPolicy(t *testing.T) {
+	policy, err := ParseLintPolicy(`{"max_layers":10,"required_labels":["maintainer"],"forbid_root_user":true,"reject":true}`)
+	require.Nil(t, err)
+	assert.Equal(t, 10, policy.MaxLayers)
+	assert.Equal(t, []string{"maintainer"}, policy.RequiredLabels)
+	assert.True(t, policy.ForbidRootUser)
+	assert.False(t, policy.ForbidLatestOnly)
+	assert.True(t, policy.Reject)
+
+	_, err = ParseLintPolicy(`invalid`)
+	assert.NotNil(t, err)
+	_, err = ParseLintPolicy(`{"max_layers":-1}`)
+	assert.NotNil(t, err)
+	_, err = ParseLintPolicy(`{"required_labels":[""]}`)
+	assert.NotNil(t, err)
+
+	p := &Project{}
+	assert.Nil(t, p.LintPolicy())
+	p.SetMetadata(ProMetaLintPolicy, `{"forbid_latest_only":true}`)
+	require.NotNil(t, p.LintPolicy())
+	assert.True(t, p.LintPolicy().ForbidLatestOnly)
+}
@@ -0,0 +1,48 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// DefaultMaintenanceMessage is returned to the rejected requests if the message isn't specified
+const DefaultMaintenanceMessage = "Harbor is under maintenance, please retry later."
+
+// Maintenance is the state of maintenance mode, it's stored in the configuration "maintenance" as a JSON object
+type Maintenance struct {
+	Enabled bool `json:"enabled"`
+	// the message returned to the rejected requests
+	Message string `json:"message"`
+	// the seconds after which the clients should retry, returned in the header Retry-After
+	RetryAfter int `json:"retry_after"`
+	// the seconds during which the pushes in flight are allowed to finish after the maintenance mode is enabled
+	DrainPeriod int `json:"drain_period"`
+	// the time when the maintenance mode is enabled
+	Since time.Time `json:"since"`
+}
+
+// GetMessage returns the message, the default one is returned if it's empty
+func (m *Maintenance) GetMessage() string {
+	if len(m.Message) == 0 {
+		return DefaultMaintenanceMessage
+	}
+	return m.Message
+}
+
+// Draining returns whether the pushes in flight are still allowed to finish
+func (m *Maintenance) Draining(now time.Time) bool {
+	return m.Enabled && now.Before(m.Since.Add(time.Duration(m.DrainPeriod)*time.Second))
+}
@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceGetMessage(t *testing.T) {
+	m := &Maintenance{}
+	assert.Equal(t, DefaultMaintenanceMessage, m.GetMessage())
+	m.Message = "upgrading"
+	assert.Equal(t, "upgrading", m.GetMessage())
+}
+
+func TestMaintenanceDraining(t *testing.T) {
+	now := time.Now()
+	m := &Maintenance{
+		Enabled:     true,
+		DrainPeriod: 60,
+		Since:       now.Add(-30 * time.Second),
+	}
+	assert.True(t, m.Draining(now))
+	assert.False(t, m.Draining(now.Add(time.Minute)))
+
+	m.DrainPeriod = 0
+	assert.False(t, m.Draining(now))
+
+	m.Enabled = false
+	m.DrainPeriod = 60
+	assert.False(t, m.Draining(now))
+}
@@ -14,6 +14,10 @@
 
 package models
 
+import (
+	"time"
+)
+
 // Member holds the details of a member.
 type Member struct {
 	ID         int    `orm:"pk;column(id)" json:"id"`
@@ -23,6 +27,13 @@ type Member struct {
 	Role       int    `json:"role_id"`
 	EntityID   int    `orm:"column(entity_id)" json:"entity_id"`
 	EntityType string `orm:"column(entity_type)" json:"entity_type"`
+	// ExpirationTime is nil if the membership never expires
+	ExpirationTime *time.Time `orm:"column(expiration_time);null" json:"expiration_time,omitempty"`
+}
+
+// IsExpired returns whether the membership has expired
+func (m *Member) IsExpired() bool {
+	return m.ExpirationTime != nil && !m.ExpirationTime.After(time.Now())
 }
 
 // UserMember ...
@@ -39,4 +50,39 @@ type MemberReq struct {
 	Role        int       `json:"role_id,omitempty"`
 	MemberUser  User      `json:"member_user,omitempty"`
 	MemberGroup UserGroup `json:"member_group,omitempty"`
+	// ExpirationTime is optional, the member loses the access after it. The expiration time
+	// of the member being updated is kept if neither it nor NeverExpire is specified
+	ExpirationTime *time.Time `json:"expiration_time,omitempty"`
+	// NeverExpire removes the expiration time of the member being updated
+	NeverExpire bool `json:"never_expire,omitempty"`
+}
+
+// the actions of the entries of the batch membership request
+const (
+	MemberBatchActionAdd    = "add"
+	MemberBatchActionUpdate = "update"
+	MemberBatchActionRemove = "remove"
+)
+
+// MemberBatchReq adds, updates or removes the members of the project in one request
+type MemberBatchReq struct {
+	Members []*MemberBatchEntry `json:"members"`
+}
+
+// MemberBatchEntry is an entry of the batch membership request, the member to update or
+// remove is specified either by the ID of the membership or by the user or group in the
+// same way as the member to add
+type MemberBatchEntry struct {
+	Action string `json:"action"`
+	ID     int    `json:"id,omitempty"`
+	MemberReq
+}
+
+// MemberBatchResult is the result of an entry of the batch membership request, the status
+// code is the one the single member API responds with
+type MemberBatchResult struct {
+	Action     string `json:"action"`
+	ID         int    `json:"id,omitempty"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
 }
@@ -0,0 +1,37 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// MetadataSyncStateTable is the name of table in DB that holds the state of the metadata sync
+const MetadataSyncStateTable = "metadata_sync_state"
+
+// MetadataSyncState is the state of the metadata sync of a project with the peer instance,
+// the digest is empty if the two instances never agree on the configuration of the project
+type MetadataSyncState struct {
+	ID          int64     `orm:"pk;auto;column(id)" json:"-"`
+	ProjectName string    `orm:"column(project_name)" json:"project_name"`
+	Digest      string    `orm:"column(digest)" json:"digest"`
+	Message     string    `orm:"column(message)" json:"message,omitempty"`
+	UpdateTime  time.Time `orm:"column(update_time)" json:"update_time"`
+}
+
+// TableName ...
+func (m *MetadataSyncState) TableName() string {
+	return MetadataSyncStateTable
+}
@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// the formats of the timestamps in the webhook payloads and the emails
+const (
+	NotificationTimeFormatRFC3339  = "rfc3339"  // e.g. "2006-01-02T15:04:05+08:00", the default format
+	NotificationTimeFormatRFC1123  = "rfc1123"  // e.g. "Mon, 02 Jan 2006 15:04:05 CST"
+	NotificationTimeFormatDateTime = "datetime" // e.g. "2006-01-02 15:04:05 CST"
+	NotificationTimeFormatUnix     = "unix"     // the seconds since epoch
+)
+
+var notificationTimeLayouts = map[string]string{
+	NotificationTimeFormatRFC3339:  time.RFC3339,
+	NotificationTimeFormatRFC1123:  time.RFC1123,
+	NotificationTimeFormatDateTime: "2006-01-02 15:04:05 MST",
+}
+
+// DefaultNotificationTime renders the timestamps in RFC 3339 in UTC
+var DefaultNotificationTime = &NotificationTime{
+	Location: time.UTC,
+	Format:   NotificationTimeFormatRFC3339,
+}
+
+// NotificationTime defines how the timestamps are rendered in the notifications of a project
+type NotificationTime struct {
+	Location *time.Location
+	Format   string
+}
+
+// ParseNotificationTime parses the timezone, e.g. "Asia/Shanghai", and the format of the
+// timestamps, the empty ones mean UTC and RFC 3339
+func ParseNotificationTime(timezone, format string) (*NotificationTime, error) {
+	n := &NotificationTime{
+		Location: time.UTC,
+		Format:   NotificationTimeFormatRFC3339,
+	}
+	if len(timezone) > 0 {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %s: %v", timezone, err)
+		}
+		n.Location = location
+	}
+	if len(format) > 0 {
+		if _, ok := notificationTimeLayouts[format]; !ok && format != NotificationTimeFormatUnix {
+			return nil, fmt.Errorf("invalid time format %s, it must be one of %s, %s, %s and %s", format,
+				NotificationTimeFormatRFC3339, NotificationTimeFormatRFC1123, NotificationTimeFormatDateTime,
+				NotificationTimeFormatUnix)
+		}
+		n.Format = format
+	}
+	return n, nil
+}
+
+// Render returns the time in the timezone and the format
+func (n *NotificationTime) Render(t time.Time) string {
+	if n.Format == NotificationTimeFormatUnix {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	layout, ok := notificationTimeLayouts[n.Format]
+	if !ok {
+		layout = time.RFC3339
+	}
+	location := n.Location
+	if location == nil {
+		location = time.UTC
+	}
+	return t.In(location).Format(layout)
+}
+
+// RenderJSON returns the time as a JSON value, it's a number for the format "unix" and
+// a string for the others
+func (n *NotificationTime) RenderJSON(t time.Time) json.RawMessage {
+	if n.Format == NotificationTimeFormatUnix {
+		return json.RawMessage(n.Render(t))
+	}
+	return json.RawMessage(strconv.Quote(n.Render(t)))
+}
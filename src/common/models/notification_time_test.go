@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotificationTime(t *testing.T) {
+	n, err := ParseNotificationTime("", "")
+	require.Nil(t, err)
+	assert.Equal(t, time.UTC, n.Location)
+	assert.Equal(t, NotificationTimeFormatRFC3339, n.Format)
+
+	_, err = ParseNotificationTime("Mars/Olympus_Mons", "")
+	assert.NotNil(t, err)
+	_, err = ParseNotificationTime("", "2006-01-02")
+	assert.NotNil(t, err)
+
+	n, err = ParseNotificationTime("Asia/Shanghai", NotificationTimeFormatUnix)
+	require.Nil(t, err)
+	assert.Equal(t, "Asia/Shanghai", n.Location.String())
+}
+
+func TestRenderNotificationTime(t *testing.T) {
+	tm := time.Date(2019, 10, 1, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2019-10-01T08:00:00Z", DefaultNotificationTime.Render(tm))
+	assert.Equal(t, `"2019-10-01T08:00:00Z"`, string(DefaultNotificationTime.RenderJSON(tm)))
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.Nil(t, err)
+	cases := map[string]string{
+		NotificationTimeFormatRFC3339:  "2019-10-01T16:00:00+08:00",
+		NotificationTimeFormatRFC1123:  "Tue, 01 Oct 2019 16:00:00 CST",
+		NotificationTimeFormatDateTime: "2019-10-01 16:00:00 CST",
+		NotificationTimeFormatUnix:     "1569916800",
+	}
+	for format, expected := range cases {
+		n := &NotificationTime{
+			Location: shanghai,
+			Format:   format,
+		}
+		assert.Equal(t, expected, n.Render(tm), format)
+	}
+	n := &NotificationTime{
+		Location: shanghai,
+		Format:   NotificationTimeFormatUnix,
+	}
+	assert.Equal(t, "1569916800", string(n.RenderJSON(tm)))
+}
+
+func TestProjectNotificationTime(t *testing.T) {
+	p := &Project{}
+	assert.Equal(t, DefaultNotificationTime, p.NotificationTime())
+
+	p.Metadata = map[string]string{
+		ProMetaNotificationTZ:         "Asia/Shanghai",
+		ProMetaNotificationTimeFormat: NotificationTimeFormatRFC1123,
+	}
+	n := p.NotificationTime()
+	assert.Equal(t, "Asia/Shanghai", n.Location.String())
+	assert.Equal(t, NotificationTimeFormatRFC1123, n.Format)
+
+	p.Metadata[ProMetaNotificationTimeFormat] = "invalid"
+	assert.Equal(t, DefaultNotificationTime, p.NotificationTime())
+}
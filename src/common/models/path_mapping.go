@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// PathMapping maps the project of Harbor to the path on the generic registries, e.g. the
+// project "library" can be mapped to "docker-local/team" on Artifactory, the repository
+// "library/nginx" is replicated as "docker-local/team/nginx" then. Empty path means the
+// repositories are placed under the root of the registry, as some Nexus setups require
+type PathMapping struct {
+	Project string `json:"project"`
+	Path    string `json:"path"`
+}
+
+// ValidatePathMappings checks whether the path mappings are valid, every project
+// and path can be mapped only once
+func ValidatePathMappings(mappings []*PathMapping) error {
+	projects := map[string]bool{}
+	paths := map[string]bool{}
+	for _, mapping := range mappings {
+		if mapping == nil || len(mapping.Project) == 0 {
+			return fmt.Errorf("the project of the path mapping can not be empty")
+		}
+		if strings.Contains(mapping.Project, "/") {
+			return fmt.Errorf("invalid project %s in the path mapping", mapping.Project)
+		}
+		path := strings.Trim(mapping.Path, "/")
+		if strings.Contains(path, "//") {
+			return fmt.Errorf("invalid path %s in the path mapping", mapping.Path)
+		}
+		if projects[mapping.Project] {
+			return fmt.Errorf("the project %s is mapped more than once", mapping.Project)
+		}
+		if paths[path] {
+			return fmt.Errorf("the path %s is mapped more than once", mapping.Path)
+		}
+		projects[mapping.Project] = true
+		paths[path] = true
+	}
+	return nil
+}
+
+// MapToRemote returns the name of the repository on the remote registry, the name
+// doesn't change if no mapping matches the project of the repository
+func MapToRemote(mappings []*PathMapping, repository string) string {
+	project, rest := utils.ParseRepository(repository)
+	for _, mapping := range mappings {
+		if mapping.Project != project {
+			continue
+		}
+		if path := strings.Trim(mapping.Path, "/"); len(path) > 0 {
+			return path + "/" + rest
+		}
+		return rest
+	}
+	return repository
+}
+
+// MapToLocal returns the name of the repository in Harbor for the repository on the remote
+// registry, the longest matched path takes precedence. The first component of the name is
+// used as the project if no mapping matches, false is returned if the repository can't be
+// mapped into any project
+func MapToLocal(mappings []*PathMapping, repository string) (string, bool) {
+	var matched *PathMapping
+	rest := ""
+	for _, mapping := range mappings {
+		path := strings.Trim(mapping.Path, "/")
+		var r string
+		switch {
+		case len(path) == 0:
+			if strings.Contains(repository, "/") {
+				continue
+			}
+			r = repository
+		case strings.HasPrefix(repository, path+"/"):
+			r = repository[len(path)+1:]
+		default:
+			continue
+		}
+		if matched == nil || len(path) > len(strings.Trim(matched.Path, "/")) {
+			matched = mapping
+			rest = r
+		}
+	}
+	if matched != nil {
+		return matched.Project + "/" + rest, true
+	}
+	if !strings.Contains(repository, "/") {
+		return "", false
+	}
+	return repository, true
+}
@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePathMappings(t *testing.T) {
+	assert.Nil(t, ValidatePathMappings(nil))
+	assert.Nil(t, ValidatePathMappings([]*PathMapping{
+		{Project: "library", Path: "docker-local/team"},
+		{Project: "base", Path: ""},
+	}))
+
+	cases := [][]*PathMapping{
+		{{Project: "", Path: "docker-local"}},
+		{{Project: "library/nginx", Path: "docker-local"}},
+		{{Project: "library", Path: "docker-local//team"}},
+		{{Project: "library", Path: "a"}, {Project: "library", Path: "b"}},
+		{{Project: "library", Path: "a"}, {Project: "base", Path: "a/"}},
+	}
+	for _, c := range cases {
+		assert.NotNil(t, ValidatePathMappings(c))
+	}
+}
+
+func TestMapToRemote(t *testing.T) {
+	mappings := []*PathMapping{
+		{Project: "library", Path: "docker-local/team/"},
+		{Project: "base", Path: ""},
+	}
+	assert.Equal(t, "docker-local/team/nginx", MapToRemote(mappings, "library/nginx"))
+	assert.Equal(t, "docker-local/team/app/web", MapToRemote(mappings, "library/app/web"))
+	assert.Equal(t, "alpine", MapToRemote(mappings, "base/alpine"))
+	assert.Equal(t, "other/redis", MapToRemote(mappings, "other/redis"))
+	assert.Equal(t, "library/nginx", MapToRemote(nil, "library/nginx"))
+}
+
+func TestMapToLocal(t *testing.T) {
+	mappings := []*PathMapping{
+		{Project: "docker", Path: "docker-local"},
+		{Project: "library", Path: "docker-local/team"},
+		{Project: "base", Path: ""},
+	}
+	cases := []struct {
+		remote string
+		local  string
+		ok     bool
+	}{
+		{"docker-local/team/nginx", "library/nginx", true},
+		{"docker-local/redis", "docker/redis", true},
+		{"alpine", "base/alpine", true},
+		{"other/redis", "other/redis", true},
+	}
+	for _, c := range cases {
+		local, ok := MapToLocal(mappings, c.remote)
+		assert.Equal(t, c.ok, ok, c.remote)
+		assert.Equal(t, c.local, local, c.remote)
+	}
+
+	_, ok := MapToLocal(nil, "alpine")
+	assert.False(t, ok)
+}
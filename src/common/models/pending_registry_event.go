@@ -0,0 +1,40 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// PendingRegistryEventTable is the name of table in DB that holds the events of registry not processed yet
+const PendingRegistryEventTable = "pending_registry_event"
+
+// PendingRegistryEvent is an event of registry which is received but not processed yet
+type PendingRegistryEvent struct {
+	ID         int64  `orm:"pk;auto;column(id)" json:"id"`
+	EventID    string `orm:"column(event_id)" json:"event_id"`
+	Repository string `orm:"column(repository)" json:"repository"`
+	// the event in JSON
+	Event string `orm:"column(event)" json:"event"`
+	// the instance of core holding the event
+	Owner        string    `orm:"column(owner)" json:"owner"`
+	Heartbeat    time.Time `orm:"column(heartbeat)" json:"heartbeat"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (p *PendingRegistryEvent) TableName() string {
+	return PendingRegistryEventTable
+}
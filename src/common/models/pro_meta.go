@@ -20,16 +20,32 @@ import (
 
 // keys of project metadata and severity values
 const (
-	ProMetaPublic             = "public"
-	ProMetaEnableContentTrust = "enable_content_trust"
-	ProMetaPreventVul         = "prevent_vul" // prevent vulnerable images from being pulled
-	ProMetaSeverity           = "severity"
-	ProMetaAutoScan           = "auto_scan"
-	SeverityNone              = "negligible"
-	SeverityLow               = "low"
-	SeverityMedium            = "medium"
-	SeverityHigh              = "high"
-	SeverityCritical          = "critical"
+	ProMetaPublic                 = "public"
+	ProMetaEnableContentTrust     = "enable_content_trust"
+	ProMetaPreventVul             = "prevent_vul" // prevent vulnerable images from being pulled
+	ProMetaSeverity               = "severity"
+	ProMetaAutoScan               = "auto_scan"
+	ProMetaScanner                = "scanner"          // the ID of scanner which scans the images of project
+	ProMetaStorageQuota           = "storage_quota"    // the max storage usage in bytes, -1 means unlimited
+	ProMetaQuotaThresholds        = "quota_thresholds" // the percentages of quota notified when crossed, e.g. "50,80,95"
+	ProMetaQuotaWebhookURL        = "quota_webhook_url"
+	ProMetaQuotaWebhookSchema     = "quota_webhook_schema" // the version of the payload schema of the quota webhook, "v1" or "v2"
+	ProMetaRetentionPolicy        = "retention_policy"         // the retention policy in JSON
+	ProMetaTrustPatterns          = "content_trust_patterns"   // limit the enforcement of content trust to the matched images
+	ProMetaStorageHint            = "storage_hint"             // the storage class hint of the blobs in JSON
+	ProMetaMaxImageSize           = "max_image_size"           // the max size of an image in bytes, -1 means unlimited
+	ProMetaMaxLayerSize           = "max_layer_size"           // the max size of a layer in bytes, -1 means unlimited
+	ProMetaLintPolicy             = "lint_policy"              // the policy of the push-time linter in JSON
+	ProMetaTagPolicy              = "tag_policy"               // the naming policy of the pushed tags in JSON
+	ProMetaNotificationTZ         = "notification_timezone"    // the timezone of the timestamps in the notifications, e.g. "Asia/Shanghai"
+	ProMetaNotificationTimeFormat = "notification_time_format" // the format of the timestamps in the notifications
+	ProMetaDigestPullRepos        = "digest_pull_repositories" // the patterns of the repositories pulled by digest only, e.g. "app/*"
+	ProMetaExportMetrics          = "export_metrics"           // export the quota and usage of the project as the metrics of Prometheus
+	SeverityNone                  = "negligible"
+	SeverityLow                   = "low"
+	SeverityMedium                = "medium"
+	SeverityHigh                  = "high"
+	SeverityCritical              = "critical"
 )
 
 // ProjectMetadata holds the metadata of a project.
@@ -15,6 +15,8 @@
 package models
 
 import (
+	"path"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -36,6 +38,8 @@ type Project struct {
 	RepoCount    int64             `orm:"-" json:"repo_count"`
 	ChartCount   uint64            `orm:"-" json:"chart_count"`
 	Metadata     map[string]string `orm:"-" json:"metadata"`
+	// the custom metadata set by the users, populated only when required
+	CustomMetadata map[string]string `orm:"-" json:"custom_metadata,omitempty"`
 }
 
 // GetMetadata ...
@@ -74,6 +78,50 @@ func (p *Project) ContentTrustEnabled() bool {
 	return isTrue(enabled)
 }
 
+// ContentTrustRequired returns whether the image must be signed to be pulled, the repository
+// is the full name including the project. It's required for all the images of the project if
+// content trust is enabled and no pattern is set, otherwise only for the ones matching the patterns.
+func (p *Project) ContentTrustRequired(repository, reference string) bool {
+	if !p.ContentTrustEnabled() {
+		return false
+	}
+	value, exist := p.GetMetadata(ProMetaTrustPatterns)
+	if !exist || len(strings.TrimSpace(value)) == 0 {
+		return true
+	}
+	patterns, err := ParseContentTrustPatterns(value)
+	if err != nil || len(patterns) == 0 {
+		return true
+	}
+	repository = strings.TrimPrefix(repository, p.Name+"/")
+	for _, pattern := range patterns {
+		if pattern.Match(repository, reference) {
+			return true
+		}
+	}
+	return false
+}
+
+// DigestPullRequired returns whether the repository, which is the full name including the project,
+// can be pulled by digest only, i.e. it matches any of the digest pull patterns of the project
+func (p *Project) DigestPullRequired(repository string) bool {
+	value, exist := p.GetMetadata(ProMetaDigestPullRepos)
+	if !exist || len(strings.TrimSpace(value)) == 0 {
+		return false
+	}
+	patterns, err := ParseDigestPullPatterns(value)
+	if err != nil {
+		return false
+	}
+	repository = strings.TrimPrefix(repository, p.Name+"/")
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, repository); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // VulPrevented ...
 func (p *Project) VulPrevented() bool {
 	prevent, exist := p.GetMetadata(ProMetaPreventVul)
@@ -101,6 +149,148 @@ func (p *Project) AutoScan() bool {
 	return isTrue(auto)
 }
 
+// MetricsExported returns whether the quota and usage of the project are exported as the metrics
+func (p *Project) MetricsExported() bool {
+	exported, exist := p.GetMetadata(ProMetaExportMetrics)
+	if !exist {
+		return false
+	}
+	return isTrue(exported)
+}
+
+// Scanner returns the ID of scanner, it's empty if the project uses the default one
+func (p *Project) Scanner() string {
+	scanner, exist := p.GetMetadata(ProMetaScanner)
+	if !exist {
+		return ""
+	}
+	return scanner
+}
+
+// StorageQuota returns the max storage usage in bytes, -1 means unlimited
+func (p *Project) StorageQuota() int64 {
+	return p.sizeLimit(ProMetaStorageQuota)
+}
+
+// MaxImageSize returns the max size of an image in bytes, -1 means unlimited
+func (p *Project) MaxImageSize() int64 {
+	return p.sizeLimit(ProMetaMaxImageSize)
+}
+
+// MaxLayerSize returns the max size of a layer in bytes, -1 means unlimited
+func (p *Project) MaxLayerSize() int64 {
+	return p.sizeLimit(ProMetaMaxLayerSize)
+}
+
+func (p *Project) sizeLimit(name string) int64 {
+	value, exist := p.GetMetadata(name)
+	if !exist {
+		return -1
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// QuotaThresholds returns the percentages of quota which are notified when the storage
+// usage crosses them, DefaultQuotaThresholds is returned if they aren't set
+func (p *Project) QuotaThresholds() []int {
+	value, exist := p.GetMetadata(ProMetaQuotaThresholds)
+	if !exist {
+		return DefaultQuotaThresholds
+	}
+	thresholds, err := ParseQuotaThresholds(value)
+	if err != nil {
+		return DefaultQuotaThresholds
+	}
+	return thresholds
+}
+
+// QuotaWebhookURL returns the URL which the events of quota are posted to
+func (p *Project) QuotaWebhookURL() string {
+	url, exist := p.GetMetadata(ProMetaQuotaWebhookURL)
+	if !exist {
+		return ""
+	}
+	return url
+}
+
+// QuotaWebhookSchema returns the version of the payload schema of the quota webhook, it's
+// "v1" if not set
+func (p *Project) QuotaWebhookSchema() string {
+	schema, exist := p.GetMetadata(ProMetaQuotaWebhookSchema)
+	if !exist || len(schema) == 0 {
+		return WebhookSchemaV1
+	}
+	return schema
+}
+
+// NotificationTime returns how the timestamps are rendered in the notifications of the project,
+// the default one is returned if the timezone or the format isn't set or invalid
+func (p *Project) NotificationTime() *NotificationTime {
+	timezone, _ := p.GetMetadata(ProMetaNotificationTZ)
+	format, _ := p.GetMetadata(ProMetaNotificationTimeFormat)
+	n, err := ParseNotificationTime(timezone, format)
+	if err != nil {
+		return DefaultNotificationTime
+	}
+	return n
+}
+
+// RetentionPolicy returns the retention policy of the project, nil is returned if it isn't set or invalid
+func (p *Project) RetentionPolicy() *RetentionPolicy {
+	value, exist := p.GetMetadata(ProMetaRetentionPolicy)
+	if !exist || len(value) == 0 {
+		return nil
+	}
+	policy, err := ParseRetentionPolicy(value)
+	if err != nil {
+		return nil
+	}
+	return policy
+}
+
+// LintPolicy returns the lint policy of the project, nil is returned if it isn't set or invalid
+func (p *Project) LintPolicy() *LintPolicy {
+	value, exist := p.GetMetadata(ProMetaLintPolicy)
+	if !exist || len(value) == 0 {
+		return nil
+	}
+	policy, err := ParseLintPolicy(value)
+	if err != nil {
+		return nil
+	}
+	return policy
+}
+
+// TagPolicy returns the tag policy of the project, nil is returned if it isn't set or invalid
+func (p *Project) TagPolicy() *TagPolicy {
+	value, exist := p.GetMetadata(ProMetaTagPolicy)
+	if !exist || len(value) == 0 {
+		return nil
+	}
+	policy, err := ParseTagPolicy(value)
+	if err != nil {
+		return nil
+	}
+	return policy
+}
+
+// StorageHint returns the storage hint of the project, nil is returned if it isn't set or invalid
+func (p *Project) StorageHint() *StorageHint {
+	value, exist := p.GetMetadata(ProMetaStorageHint)
+	if !exist || len(value) == 0 {
+		return nil
+	}
+	hint, err := ParseStorageHint(value)
+	if err != nil {
+		return nil
+	}
+	return hint
+}
+
 func isTrue(value string) bool {
 	return strings.ToLower(value) == "true" ||
 		strings.ToLower(value) == "1"
@@ -118,12 +308,15 @@ func isTrue(value string) bool {
 // List projects which user1 is member of: query := &QueryParam{Member:&Member{Name:"user1"}}
 // List projects which user1 is the project admin : query := &QueryParam{Member:&Member{Name:"user1",Role:1}}
 type ProjectQueryParam struct {
-	Name       string       // the name of project
-	Owner      string       // the username of project owner
-	Public     *bool        // the project is public or not, can be ture, false and nil
-	Member     *MemberQuery // the member of project
-	Pagination *Pagination  // pagination information
-	ProjectIDs []int64      // project ID list
+	Name           string       // the name of project
+	ExactMatchName bool         // the name of project is matched exactly rather than fuzzily
+	Owner          string       // the username of project owner
+	Public         *bool        // the project is public or not, can be ture, false and nil
+	Member         *MemberQuery // the member of project
+	Pagination     *Pagination  // pagination information
+	ProjectIDs     []int64      // project ID list
+	// the custom metadata the projects have, an empty value matches any value of the name
+	CustomMetadata map[string]string
 }
 
 // MemberQuery filter by member's username and role
@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// ProjectBootstrapHookTable is the name of table in DB that holds the bootstrap hooks of projects
+const ProjectBootstrapHookTable = "project_bootstrap_hook"
+
+// ProjectBootstrapHook is a template of the project configuration document applied to
+// every new project, the template is rendered with the name, ID and owner of the project
+type ProjectBootstrapHook struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Name         string    `orm:"column(name)" json:"name"`
+	Description  string    `orm:"column(description)" json:"description"`
+	Template     string    `orm:"column(template)" json:"template"`
+	Enabled      bool      `orm:"column(enabled)" json:"enabled"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (p *ProjectBootstrapHook) TableName() string {
+	return ProjectBootstrapHookTable
+}
+
+// Valid ...
+func (p *ProjectBootstrapHook) Valid(v *validation.Validation) {
+	if len(p.Name) == 0 {
+		v.SetError("name", "cannot be empty")
+	}
+	if len(p.Name) > 255 {
+		v.SetError("name", "max length is 255")
+	}
+	if len(p.Description) > 1024 {
+		v.SetError("description", "max length is 1024")
+	}
+	if len(p.Template) == 0 {
+		v.SetError("template", "cannot be empty")
+	}
+}
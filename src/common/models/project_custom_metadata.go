@@ -0,0 +1,64 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// ProjectCustomMetadataTable is the name of table in DB that holds the custom metadata of projects
+const ProjectCustomMetadataTable = "project_custom_metadata"
+
+var customMetadataNameRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+// ProjectCustomMetadata is an arbitrary key/value metadata of the project set by the users,
+// e.g. "team-owner" and "cost-center". Unlike the metadata of the project, it isn't
+// interpreted by Harbor
+type ProjectCustomMetadata struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"-"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	Name         string    `orm:"column(name)" json:"name"`
+	Value        string    `orm:"column(value)" json:"value"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (p *ProjectCustomMetadata) TableName() string {
+	return ProjectCustomMetadataTable
+}
+
+// Valid ...
+func (p *ProjectCustomMetadata) Valid(v *validation.Validation) {
+	if err := ValidateCustomMetadataName(p.Name); err != nil {
+		v.SetError("name", err.Error())
+	}
+	if len(p.Value) > 255 {
+		v.SetError("value", "max length is 255")
+	}
+}
+
+// ValidateCustomMetadataName checks the name of the custom metadata, it contains the lowercase
+// letters and digits separated by ".", "_" or "-" and is at most 64 characters
+func ValidateCustomMetadataName(name string) error {
+	if len(name) == 0 || len(name) > 64 || !customMetadataNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid name of custom metadata '%s', it must be at most 64 lowercase letters and digits separated by '.', '_' or '-'", name)
+	}
+	return nil
+}
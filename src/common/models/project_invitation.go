@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common"
+)
+
+const (
+	// ProjectInvitationTable is the name of table in DB that holds the invitation links of projects
+	ProjectInvitationTable = "project_invitation"
+	// MaxInvitationLifetime is the max duration between the creation and the expiration of an invitation
+	MaxInvitationLifetime = 30 * 24 * time.Hour
+)
+
+// ProjectInvitation is an invitation link of the project, the authenticated users accepting it
+// before the expiration time become the members of the project with the role
+type ProjectInvitation struct {
+	ID             int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID      int64     `orm:"column(project_id)" json:"project_id"`
+	Token          string    `orm:"column(token)" json:"token"`
+	Role           int       `orm:"column(role)" json:"role_id"`
+	ExpirationTime time.Time `orm:"column(expiration_time)" json:"expiration_time"`
+	AcceptedCount  int       `orm:"column(accepted_count)" json:"accepted_count"`
+	Creator        string    `orm:"column(creator)" json:"creator"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime     time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+	// the URL of the invitation, it's built from the external endpoint and the token
+	Link        string `orm:"-" json:"link,omitempty"`
+	ProjectName string `orm:"-" json:"project_name,omitempty"`
+}
+
+// TableName ...
+func (p *ProjectInvitation) TableName() string {
+	return ProjectInvitationTable
+}
+
+// IsExpired returns whether the invitation has expired
+func (p *ProjectInvitation) IsExpired() bool {
+	return !p.ExpirationTime.After(time.Now())
+}
+
+// ProjectInvitationReq is the request to create an invitation link of the project
+type ProjectInvitationReq struct {
+	Role           int       `json:"role_id"`
+	ExpirationTime time.Time `json:"expiration_time"`
+}
+
+// Valid ...
+func (p *ProjectInvitationReq) Valid(v *validation.Validation) {
+	switch p.Role {
+	case common.RoleProjectAdmin, common.RoleMaster, common.RoleDeveloper, common.RoleGuest:
+	default:
+		v.SetError("role_id", "invalid role")
+	}
+	now := time.Now()
+	if !p.ExpirationTime.After(now) {
+		v.SetError("expiration_time", "must be in the future")
+	} else if p.ExpirationTime.Sub(now) > MaxInvitationLifetime {
+		v.SetError("expiration_time", "must be within 30 days")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectInvitationReq(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		req   *ProjectInvitationReq
+		valid bool
+	}{
+		{&ProjectInvitationReq{Role: common.RoleDeveloper, ExpirationTime: now.Add(24 * time.Hour)}, true},
+		{&ProjectInvitationReq{Role: common.RoleGuest, ExpirationTime: now.Add(29 * 24 * time.Hour)}, true},
+		{&ProjectInvitationReq{Role: 0, ExpirationTime: now.Add(24 * time.Hour)}, false},
+		{&ProjectInvitationReq{Role: common.RoleGuest, ExpirationTime: now.Add(-time.Hour)}, false},
+		{&ProjectInvitationReq{Role: common.RoleGuest}, false},
+		{&ProjectInvitationReq{Role: common.RoleGuest, ExpirationTime: now.Add(31 * 24 * time.Hour)}, false},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.req.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "request: %+v", c.req)
+	}
+}
+
+func TestProjectInvitationIsExpired(t *testing.T) {
+	assert.False(t, (&ProjectInvitation{ExpirationTime: time.Now().Add(time.Hour)}).IsExpired())
+	assert.True(t, (&ProjectInvitation{ExpirationTime: time.Now().Add(-time.Hour)}).IsExpired())
+}
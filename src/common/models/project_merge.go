@@ -0,0 +1,74 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ProjectMergeTable is the name of table in DB that holds the merges of projects
+const ProjectMergeTable = "project_merge"
+
+// the status of the project merge
+const (
+	ProjectMergeRunning   = "running"
+	ProjectMergeSucceeded = "succeeded"
+	ProjectMergeFailed    = "failed"
+)
+
+// the rules to deduplicate the members which exist in both projects
+const (
+	// MemberRuleHigherRole keeps the role with more privileges
+	MemberRuleHigherRole = "higher_role"
+	// MemberRuleKeepTarget keeps the role in the target project
+	MemberRuleKeepTarget = "keep_target"
+)
+
+// ProjectMerge records the merge of the source project into the target one, the merge
+// runs in background and the source project is retired once it succeeds
+type ProjectMerge struct {
+	ID                int64     `orm:"pk;auto;column(id)" json:"id"`
+	SourceProjectID   int64     `orm:"column(source_project_id)" json:"source_project_id"`
+	SourceProjectName string    `orm:"column(source_project_name)" json:"source_project_name"`
+	TargetProjectID   int64     `orm:"column(target_project_id)" json:"target_project_id"`
+	MemberRule        string    `orm:"column(member_rule)" json:"member_rule"`
+	Status            string    `orm:"column(status)" json:"status"`
+	Message           string    `orm:"column(message)" json:"message,omitempty"`
+	MovedRepositories int       `orm:"column(moved_repositories)" json:"moved_repositories"`
+	MovedMembers      int       `orm:"column(moved_members)" json:"moved_members"`
+	MovedRobots       int       `orm:"column(moved_robots)" json:"moved_robots"`
+	Creator           string    `orm:"column(creator)" json:"creator"`
+	CreationTime      time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime        time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (p *ProjectMerge) TableName() string {
+	return ProjectMergeTable
+}
+
+// ProjectMergeQuery ...
+type ProjectMergeQuery struct {
+	ProjectID int64 // matches both the source and target project
+	Status    string
+	Pagination
+}
+
+// ProjectMergeRequest is the request to merge the source project into the target one
+type ProjectMergeRequest struct {
+	SourceProjectID int64  `json:"source_project_id"`
+	TargetProjectID int64  `json:"target_project_id"`
+	MemberRule      string `json:"member_rule"`
+}
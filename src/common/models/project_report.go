@@ -0,0 +1,36 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ProjectReportSubscriptionTable is the name of table in DB that holds the subscriptions of the project reports
+const ProjectReportSubscriptionTable = "project_report_subscription"
+
+// ProjectReportSubscription is the subscription of a member to the scheduled report emails of the project
+type ProjectReportSubscription struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	UserID       int       `orm:"column(user_id)" json:"user_id"`
+	Username     string    `orm:"-" json:"username"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (p *ProjectReportSubscription) TableName() string {
+	return ProjectReportSubscriptionTable
+}
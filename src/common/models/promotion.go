@@ -0,0 +1,185 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// PromotionPipelineTable is the name of table in DB that holds the promotion pipelines
+	PromotionPipelineTable = "promotion_pipeline"
+	// PromotionTable is the name of table in DB that holds the promotions of images
+	PromotionTable = "promotion"
+)
+
+// the status of the promotion
+const (
+	PromotionPending   = "pending"
+	PromotionSucceeded = "succeeded"
+	PromotionFailed    = "failed"
+	PromotionRejected  = "rejected"
+)
+
+// PromotionGates are checked before an image is promoted into the project of the stage
+type PromotionGates struct {
+	// the image can't be promoted if it isn't scanned or the severity of it is equal
+	// to or higher than this one, the scan isn't checked if it's empty
+	Severity string `json:"severity,omitempty"`
+	// the image must be signed in the source repository
+	Signed bool `json:"signed"`
+	// the promotion must be approved by the admin of the project of the stage
+	Approval bool `json:"approval"`
+}
+
+// PromotionStage is a project of the pipeline
+type PromotionStage struct {
+	ProjectID int64           `json:"project_id"`
+	Gates     *PromotionGates `json:"gates,omitempty"`
+}
+
+// PromotionPipeline is an ordered list of projects, the images are promoted from one
+// stage to the next one, e.g. dev -> staging -> prod
+type PromotionPipeline struct {
+	ID           int64             `orm:"pk;auto;column(id)" json:"id"`
+	Name         string            `orm:"column(name)" json:"name"`
+	Description  string            `orm:"column(description)" json:"description"`
+	StagesStr    string            `orm:"column(stages)" json:"-"`
+	Stages       []*PromotionStage `orm:"-" json:"stages"`
+	Creator      string            `orm:"column(creator)" json:"creator"`
+	CreationTime time.Time         `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time         `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (p *PromotionPipeline) TableName() string {
+	return PromotionPipelineTable
+}
+
+// Valid ...
+func (p *PromotionPipeline) Valid(v *validation.Validation) {
+	if len(p.Name) == 0 {
+		v.SetError("name", "cannot be empty")
+		return
+	}
+	if len(p.Stages) < 2 {
+		v.SetError("stages", "at least 2 stages are required")
+		return
+	}
+	projects := map[int64]bool{}
+	for i, stage := range p.Stages {
+		if stage == nil || stage.ProjectID <= 0 {
+			v.SetError("stages", fmt.Sprintf("invalid project of stage %d", i))
+			return
+		}
+		if projects[stage.ProjectID] {
+			v.SetError("stages", fmt.Sprintf("duplicate project %d", stage.ProjectID))
+			return
+		}
+		projects[stage.ProjectID] = true
+		if stage.Gates == nil {
+			continue
+		}
+		switch stage.Gates.Severity {
+		case "", SeverityNone, SeverityLow, SeverityMedium, SeverityHigh:
+		default:
+			v.SetError("stages", fmt.Sprintf("invalid severity %q of stage %d", stage.Gates.Severity, i))
+			return
+		}
+	}
+}
+
+// Marshal converts the stages to the string stored in DB
+func (p *PromotionPipeline) Marshal() error {
+	data, err := json.Marshal(p.Stages)
+	if err != nil {
+		return err
+	}
+	p.StagesStr = string(data)
+	return nil
+}
+
+// Unmarshal converts the string stored in DB to the stages
+func (p *PromotionPipeline) Unmarshal() error {
+	p.Stages = []*PromotionStage{}
+	if len(p.StagesStr) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(p.StagesStr), &p.Stages)
+}
+
+// StageOf returns the index of the stage of the project, -1 is returned if the project isn't in the pipeline
+func (p *PromotionPipeline) StageOf(projectID int64) int {
+	for i, stage := range p.Stages {
+		if stage.ProjectID == projectID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Promotion records the promotion of an image into the project of a stage
+type Promotion struct {
+	ID               int64     `orm:"pk;auto;column(id)" json:"id"`
+	PipelineID       int64     `orm:"column(pipeline_id)" json:"pipeline_id"`
+	Repository       string    `orm:"column(repository)" json:"repository"`
+	Tag              string    `orm:"column(tag)" json:"tag"`
+	Digest           string    `orm:"column(digest)" json:"digest"`
+	Stage            int       `orm:"column(stage)" json:"stage"`
+	TargetRepository string    `orm:"column(target_repository)" json:"target_repository"`
+	Status           string    `orm:"column(status)" json:"status"`
+	Message          string    `orm:"column(message)" json:"message,omitempty"`
+	Requester        string    `orm:"column(requester)" json:"requester"`
+	Approver         string    `orm:"column(approver)" json:"approver,omitempty"`
+	CreationTime     time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime       time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (p *Promotion) TableName() string {
+	return PromotionTable
+}
+
+// PromotionQuery ...
+type PromotionQuery struct {
+	PipelineID int64
+	Repository string
+	Status     string
+	Pagination
+}
+
+// PromotionRequest is the request to promote the image into the next stage of the pipeline
+type PromotionRequest struct {
+	PipelineID int64  `json:"pipeline_id"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+}
+
+// Valid ...
+func (p *PromotionRequest) Valid(v *validation.Validation) {
+	if p.PipelineID <= 0 {
+		v.SetError("pipeline_id", "invalid pipeline")
+	}
+	if len(p.Repository) == 0 {
+		v.SetError("repository", "cannot be empty")
+	}
+	if len(p.Tag) == 0 {
+		v.SetError("tag", "cannot be empty")
+	}
+}
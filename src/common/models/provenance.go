@@ -0,0 +1,52 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// ArtifactProvenanceTable is the name of table in DB that holds the provenance of the copied images
+	ArtifactProvenanceTable = "artifact_provenance"
+
+	// MaxProvenanceDepth is the max count of the copies traced back in the provenance chain
+	MaxProvenanceDepth = 16
+)
+
+// the operations copying the images server-side
+const (
+	ProvenanceRetag   = "retag"
+	ProvenancePromote = "promote"
+)
+
+// ArtifactProvenance records a server-side copy of the image from the source tag into the tag,
+// the copies of an image chained by the digest trace it back to the original build
+type ArtifactProvenance struct {
+	ID               int64     `orm:"pk;auto;column(id)" json:"id"`
+	Repository       string    `orm:"column(repository)" json:"repository"`
+	Tag              string    `orm:"column(tag)" json:"tag"`
+	Digest           string    `orm:"column(digest)" json:"digest"`
+	SourceRepository string    `orm:"column(source_repository)" json:"source_repository"`
+	SourceTag        string    `orm:"column(source_tag)" json:"source_tag"`
+	Operation        string    `orm:"column(operation)" json:"operation"`
+	Operator         string    `orm:"column(operator)" json:"operator"`
+	OpTime           time.Time `orm:"column(op_time)" json:"op_time"`
+}
+
+// TableName ...
+func (a *ArtifactProvenance) TableName() string {
+	return ArtifactProvenanceTable
+}
@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultQuotaThresholds are the percentages of quota notified by default
+var DefaultQuotaThresholds = []int{50, 80, 95}
+
+// ParseQuotaThresholds parses the comma separated percentages, e.g. "50,80,95", the
+// returned ones are sorted in ascending order
+func ParseQuotaThresholds(value string) ([]int, error) {
+	thresholds := []int{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > 100 {
+			return nil, fmt.Errorf("invalid quota threshold %s, it must be an integer between 1 and 100", s)
+		}
+		thresholds = append(thresholds, n)
+	}
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
+// ProjectUsage is the storage usage and the count of the artifacts of a project
+type ProjectUsage struct {
+	ProjectID    int64  `json:"project_id"`
+	ProjectName  string `json:"project_name"`
+	StorageUsage int64  `json:"storage_usage"`
+	// the max storage usage in bytes and the remaining, -1 means unlimited
+	StorageQuota   int64 `json:"storage_quota"`
+	QuotaRemaining int64 `json:"quota_remaining"`
+	RepoCount      int64 `json:"repo_count"`
+	TagCount       int64 `json:"tag_count"`
+	PullCount      int64 `json:"pull_count"`
+}
+
+// UserUsage summarizes the usages of the projects which the user can push to
+type UserUsage struct {
+	StorageUsage int64           `json:"storage_usage"`
+	RepoCount    int64           `json:"repo_count"`
+	TagCount     int64           `json:"tag_count"`
+	Projects     []*ProjectUsage `json:"projects"`
+}
@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuotaThresholds(t *testing.T) {
+	thresholds, err := ParseQuotaThresholds("95, 50,80")
+	require.Nil(t, err)
+	assert.Equal(t, []int{50, 80, 95}, thresholds)
+
+	thresholds, err = ParseQuotaThresholds("")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(thresholds))
+
+	_, err = ParseQuotaThresholds("50,abc")
+	assert.NotNil(t, err)
+	_, err = ParseQuotaThresholds("120")
+	assert.NotNil(t, err)
+}
+
+func TestProjectQuota(t *testing.T) {
+	p := &Project{}
+	assert.Equal(t, int64(-1), p.StorageQuota())
+	assert.Equal(t, DefaultQuotaThresholds, p.QuotaThresholds())
+	assert.Equal(t, "", p.QuotaWebhookURL())
+
+	p.SetMetadata(ProMetaStorageQuota, "1024")
+	p.SetMetadata(ProMetaQuotaThresholds, "90")
+	p.SetMetadata(ProMetaQuotaWebhookURL, "http://example.com/hook")
+	assert.Equal(t, int64(1024), p.StorageQuota())
+	assert.Equal(t, []int{90}, p.QuotaThresholds())
+	assert.Equal(t, "http://example.com/hook", p.QuotaWebhookURL())
+}
+
+func TestProjectSizeLimits(t *testing.T) {
+	p := &Project{}
+	assert.Equal(t, int64(-1), p.MaxImageSize())
+	assert.Equal(t, int64(-1), p.MaxLayerSize())
+
+	p.SetMetadata(ProMetaMaxImageSize, "2048")
+	p.SetMetadata(ProMetaMaxLayerSize, "invalid")
+	assert.Equal(t, int64(2048), p.MaxImageSize())
+	assert.Equal(t, int64(-1), p.MaxLayerSize())
+}
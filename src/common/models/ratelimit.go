@@ -0,0 +1,37 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// RateLimit is the pull budget of an upstream registry read from the rate limit headers of its
+// responses, e.g. "RateLimit-Limit: 100;w=21600" and "RateLimit-Remaining: 76;w=21600" of Docker Hub
+type RateLimit struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+	// the window of the limit in seconds, 0 if unknown
+	Window int `json:"window"`
+	// the source which the budget is counted by, e.g. the IP or the user of Docker Hub
+	Source    string    `json:"source,omitempty"`
+	CheckTime time.Time `json:"check_time"`
+}
+
+// Low returns whether the remaining budget has fallen to the reserve, the reserve 0
+// means never
+func (r *RateLimit) Low(reserve int) bool {
+	return reserve > 0 && r.Remaining <= reserve
+}
@@ -0,0 +1,55 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// RegistryTraffic holds the traffic of the registry API of a repository, it's either
+// the traffic of one day recorded by the proxy or the one aggregated by the query
+type RegistryTraffic struct {
+	ProjectID   int64  `orm:"column(project_id)" json:"project_id"`
+	ProjectName string `orm:"column(project_name)" json:"project_name"`
+	// empty if the traffic is aggregated by project
+	Repository string `orm:"column(repository)" json:"repository,omitempty"`
+	// the day of the recorded traffic, it's zero in the aggregated traffic
+	Day          time.Time `orm:"column(day)" json:"-"`
+	RequestCount int64     `orm:"column(request_count)" json:"request_count"`
+	// the requests responded with the status code 4xx or 5xx except 401, as the
+	// challenges of the authentication are part of the normal flow of the clients
+	ErrorCount  int64 `orm:"column(error_count)" json:"error_count"`
+	BytesServed int64 `orm:"column(bytes_served)" json:"bytes_served"`
+}
+
+// ErrorRate returns the ratio of the errors in the requests
+func (r *RegistryTraffic) ErrorRate() float64 {
+	if r.RequestCount == 0 {
+		return 0
+	}
+	return float64(r.ErrorCount) / float64(r.RequestCount)
+}
+
+// RegistryTrafficQuery holds the conditions of the query of the registry traffic
+type RegistryTrafficQuery struct {
+	// the traffic of all the projects is returned if it's empty
+	ProjectIDs []int64
+	Repository string
+	// the days of the traffic are within [BeginTime, EndTime] if they are set
+	BeginTime *time.Time
+	EndTime   *time.Time
+	// aggregate the traffic by repository rather than by project
+	ByRepository bool
+}
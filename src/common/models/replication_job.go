@@ -15,9 +15,14 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common/secretstore"
 	"github.com/goharbor/harbor/src/common/utils"
 )
 
@@ -34,6 +39,39 @@ const (
 	RepJobTable = "replication_job"
 	// RepPolicyTable is table name for replication policies
 	RepPolicyTable = "replication_policy"
+
+	// RepTargetTypeHarbor is the type of the targets which are Harbor instances
+	RepTargetTypeHarbor = 0
+	// RepTargetTypeGeneric is the type of the targets which are generic Docker Registry v2
+	// endpoints, e.g. Quay, Nexus or Artifactory, the projects aren't created on them
+	RepTargetTypeGeneric = 1
+	// RepTargetTypeQuay is the type of the targets which are Quay, the robot accounts
+	// ("<organization>+<name>") push into their organizations unless the project is mapped,
+	// the repositories are created by the API if the credential is an OAuth token
+	RepTargetTypeQuay = 2
+	// RepTargetTypeArtifactory is the type of the targets which are JFrog Artifactory accessed
+	// by the repository path method, the first component of the path is the repository key
+	RepTargetTypeArtifactory = 3
+	// RepTargetTypeGitHub is the type of the targets which are the container registry of GitHub,
+	// e.g. ghcr.io, the first component of the repository is the user or organization owning it
+	RepTargetTypeGitHub = 4
+	// RepTargetTypeGitLab is the type of the targets which are the container registry of GitLab,
+	// e.g. registry.gitlab.com, the repositories are under the paths of the projects
+	RepTargetTypeGitLab = 5
+
+	// QuayOAuthTokenUsername is the username used by Quay for the OAuth access tokens
+	QuayOAuthTokenUsername = "$oauthtoken"
+
+	maxConcurrentTransfers = 100
+	maxBackoffRetries      = 10
+
+	// RepTargetAuthBearer requests the token with the credential from the realm in the
+	// challenge of the registry or the customized token realm, it's the default scheme
+	RepTargetAuthBearer = "bearer"
+	// RepTargetAuthBasic sends the credential in every request
+	RepTargetAuthBasic = "basic"
+	// RepTargetAuthAnonymous requests the token without credential
+	RepTargetAuthAnonymous = "anonymous"
 )
 
 // RepPolicy is the model for a replication policy, which associate to a project and a target (destination)
@@ -46,6 +84,7 @@ type RepPolicy struct {
 	Trigger           string    `orm:"column(cron_str)"`
 	Filters           string    `orm:"column(filters)"`
 	ReplicateDeletion bool      `orm:"column(replicate_deletion)"`
+	ReplicateRobots   bool      `orm:"column(replicate_robots)"`
 	CreationTime      time.Time `orm:"column(creation_time);auto_now_add"`
 	UpdateTime        time.Time `orm:"column(update_time);auto_now"`
 	Deleted           bool      `orm:"column(deleted)"`
@@ -63,10 +102,23 @@ type RepJob struct {
 	Tags         string    `orm:"column(tags)" json:"-"`
 	TagList      []string  `orm:"-" json:"tags"`
 	UUID         string    `orm:"column(job_uuid)" json:"-"`
+	Size         int64     `orm:"column(size)" json:"size"`
+	Error        string    `orm:"column(error)" json:"error,omitempty"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
 
+// RepJobReport is checked in by the replication job when it completes
+type RepJobReport struct {
+	// the total bytes of the blobs and manifests of the replicated images
+	Size  int64  `json:"size"`
+	Error string `json:"error,omitempty"`
+	// the URL of the target and the last rate limit of it read by the job, the rate
+	// limit is nil if the target doesn't limit the rate
+	Endpoint  string     `json:"endpoint,omitempty"`
+	RateLimit *RateLimit `json:"ratelimit,omitempty"`
+}
+
 // RepTarget is the model for a replication targe, i.e. destination, which wraps the endpoint URL and username/password of a remote registry.
 type RepTarget struct {
 	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
@@ -78,6 +130,29 @@ type RepTarget struct {
 	Insecure     bool      `orm:"column(insecure)" json:"insecure"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+
+	// the reference of the credential in the external secret store, the username and
+	// password are read from the secret store when it's set
+	CredentialRef string `orm:"column(credential_ref)" json:"credential_ref"`
+
+	// the auth scheme, customized token realm and path mappings of the target, the
+	// path mappings are stored as JSON in PathMappingStr
+	AuthScheme     string         `orm:"column(auth_scheme)" json:"auth_scheme"`
+	TokenRealm     string         `orm:"column(token_realm)" json:"token_realm"`
+	PathMappingStr string         `orm:"column(path_mappings)" json:"-"`
+	PathMappings   []*PathMapping `orm:"-" json:"path_mappings"`
+
+	// the max concurrent transfers to the target per jobservice, 0 means unlimited, and the
+	// times of retrying the requests with backoff when the target responds with 429 or 5xx
+	MaxConcurrentTransfers int `orm:"column(max_concurrent_transfers)" json:"max_concurrent_transfers"`
+	BackoffRetries         int `orm:"column(backoff_retries)" json:"backoff_retries"`
+	// the remaining budget of the rate limit reserved for the other clients, e.g. the pulls
+	// of Docker Hub, the transfers are deferred when the budget falls to it, 0 means never
+	RateLimitReserve int `orm:"column(ratelimit_reserve)" json:"ratelimit_reserve"`
+
+	// the custom CA bundle in PEM trusted by the clients of the target, it's encrypted in
+	// the database and managed by the API of the CA bundle only
+	CABundle string `orm:"column(ca_bundle)" json:"-"`
 }
 
 // Valid ...
@@ -106,6 +181,149 @@ func (r *RepTarget) Valid(v *validation.Validation) {
 	if len(r.Password) > 48 {
 		v.SetError("password", "max length is 48")
 	}
+
+	if len(r.CredentialRef) > 0 {
+		if len(r.Password) > 0 {
+			v.SetError("credential_ref", "can not be set with password")
+		}
+		if len(r.CredentialRef) > 255 {
+			v.SetError("credential_ref", "max length is 255")
+		}
+		if _, _, err := secretstore.ParseRef(r.CredentialRef); err != nil {
+			v.SetError("credential_ref", err.Error())
+		}
+	}
+
+	switch r.Type {
+	case RepTargetTypeHarbor, RepTargetTypeGeneric, RepTargetTypeQuay, RepTargetTypeArtifactory,
+		RepTargetTypeGitHub, RepTargetTypeGitLab:
+	default:
+		v.SetError("type", fmt.Sprintf("unsupported type %d", r.Type))
+	}
+
+	switch r.AuthScheme {
+	case "", RepTargetAuthBearer, RepTargetAuthBasic, RepTargetAuthAnonymous:
+	default:
+		v.SetError("auth_scheme", fmt.Sprintf("unsupported auth scheme %s", r.AuthScheme))
+	}
+
+	if len(r.TokenRealm) > 0 {
+		if r.AuthScheme == RepTargetAuthBasic {
+			v.SetError("token_realm", "can not be set with the basic auth scheme")
+		}
+		if len(r.TokenRealm) > 255 {
+			v.SetError("token_realm", "max length is 255")
+		}
+		if _, err := utils.ParseEndpoint(r.TokenRealm); err != nil {
+			v.SetError("token_realm", err.Error())
+		}
+	}
+
+	if r.MaxConcurrentTransfers < 0 || r.MaxConcurrentTransfers > maxConcurrentTransfers {
+		v.SetError("max_concurrent_transfers", fmt.Sprintf("must be between 0 and %d", maxConcurrentTransfers))
+	}
+	if r.BackoffRetries < 0 || r.BackoffRetries > maxBackoffRetries {
+		v.SetError("backoff_retries", fmt.Sprintf("must be between 0 and %d", maxBackoffRetries))
+	}
+	if r.RateLimitReserve < 0 {
+		v.SetError("ratelimit_reserve", "cannot be negative")
+	}
+
+	if len(r.PathMappings) > 0 && !r.IsRegistry() {
+		v.SetError("path_mappings", "not supported by the Harbor targets")
+	}
+	if err := ValidatePathMappings(r.PathMappings); err != nil {
+		v.SetError("path_mappings", err.Error())
+	}
+}
+
+// IsRegistry returns true if the target is a registry rather than a Harbor, the
+// projects aren't created on it
+func (r *RepTarget) IsRegistry() bool {
+	return r.Type != RepTargetTypeHarbor
+}
+
+// RemoteRepository returns the name of the repository on the target for the repository in Harbor
+func (r *RepTarget) RemoteRepository(repository string) string {
+	if !r.IsRegistry() {
+		return repository
+	}
+	name := MapToRemote(r.PathMappings, repository)
+	switch r.Type {
+	case RepTargetTypeQuay:
+	case RepTargetTypeGitHub, RepTargetTypeGitLab:
+		// the names of the owners, groups and projects may contain the uppercase letters
+		// while the repositories of the registries are in lowercase
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+
+	namespace, rest := utils.ParseRepository(name)
+	if i := strings.Index(r.Username, "+"); i > 0 {
+		project, _ := utils.ParseRepository(repository)
+		mapped := false
+		for _, mapping := range r.PathMappings {
+			if mapping.Project == project {
+				mapped = true
+				break
+			}
+		}
+		// the robot account can only push into its organization
+		if !mapped {
+			namespace = r.Username[:i]
+		}
+	}
+	// Quay supports only the repositories in format "<namespace>/<name>"
+	rest = strings.Replace(rest, "/", "_", -1)
+	if len(namespace) == 0 {
+		return rest
+	}
+	return namespace + "/" + rest
+}
+
+// APIURL returns the base URL of the API of GitHub or GitLab hosting the registry of the target,
+// the API is used to list and delete the repositories. It's empty for the other types
+func (r *RepTarget) APIURL() string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	switch r.Type {
+	case RepTargetTypeGitHub:
+		if u.Host == "ghcr.io" {
+			return "https://api.github.com"
+		}
+		// GitHub Enterprise Server serves the registry on the subdomain "containers"
+		return u.Scheme + "://" + strings.TrimPrefix(u.Host, "containers.") + "/api/v3"
+	case RepTargetTypeGitLab:
+		// GitLab serves the registry on the subdomain "registry" by default
+		return u.Scheme + "://" + strings.TrimPrefix(u.Host, "registry.") + "/api/v4"
+	}
+	return ""
+}
+
+// Marshal encodes the path mappings into PathMappingStr
+func (r *RepTarget) Marshal() error {
+	r.PathMappingStr = ""
+	if len(r.PathMappings) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(r.PathMappings)
+	if err != nil {
+		return err
+	}
+	r.PathMappingStr = string(data)
+	return nil
+}
+
+// Unmarshal decodes PathMappingStr into the path mappings
+func (r *RepTarget) Unmarshal() error {
+	r.PathMappings = nil
+	if len(r.PathMappingStr) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(r.PathMappingStr), &r.PathMappings)
 }
 
 // TableName is required by by beego orm to map RepTarget to table replication_target
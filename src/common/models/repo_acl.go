@@ -0,0 +1,85 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// RepoACLTable is the name of table in DB that holds the access control list of repositories
+	RepoACLTable = "repository_acl"
+
+	// RepoAccessPull grants pulling the images of the repository
+	RepoAccessPull = "pull"
+	// RepoAccessPush grants pushing and pulling the images of the repository
+	RepoAccessPush = "push"
+)
+
+// RepoACL grants a user the access to a repository in addition to the one granted by the project
+type RepoACL struct {
+	ID             int64     `orm:"pk;auto;column(id)" json:"id"`
+	RepositoryName string    `orm:"column(repository_name)" json:"repository_name"`
+	UserID         int       `orm:"column(user_id)" json:"user_id"`
+	Username       string    `orm:"-" json:"username"`
+	Access         string    `orm:"column(access)" json:"access"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime     time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RepoACL) TableName() string {
+	return RepoACLTable
+}
+
+// RepoACLReq grants the user the access in the request to create a repository
+type RepoACLReq struct {
+	Username string `json:"username"`
+	Access   string `json:"access"`
+}
+
+// RepoCreateReq is the request to create an empty repository before the first push, the
+// name doesn't contain the project name
+type RepoCreateReq struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	ACLs        []*RepoACLReq `json:"acls"`
+}
+
+// Valid ...
+func (r *RepoCreateReq) Valid(v *validation.Validation) {
+	if len(r.Name) == 0 {
+		v.SetError("name", "cannot be empty")
+		return
+	}
+	users := map[string]struct{}{}
+	for _, acl := range r.ACLs {
+		if acl == nil || len(acl.Username) == 0 {
+			v.SetError("acls", "the username cannot be empty")
+			return
+		}
+		if acl.Access != RepoAccessPull && acl.Access != RepoAccessPush {
+			v.SetError("acls", "invalid access "+acl.Access)
+			return
+		}
+		if _, exist := users[acl.Username]; exist {
+			v.SetError("acls", "duplicate user "+acl.Username)
+			return
+		}
+		users[acl.Username] = struct{}{}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// RepoRedirectTable is the name of table in DB that holds the redirects of the renamed repositories
+const RepoRedirectTable = "repository_redirect"
+
+// RepoRedirect redirects the old name of a renamed repository to the new one until it expires
+type RepoRedirect struct {
+	ID             int64     `orm:"pk;auto;column(id)" json:"id"`
+	OldName        string    `orm:"column(old_name)" json:"old_name"`
+	NewName        string    `orm:"column(new_name)" json:"new_name"`
+	ExpirationTime time.Time `orm:"column(expiration_time)" json:"expiration_time"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (r *RepoRedirect) TableName() string {
+	return RepoRedirectTable
+}
+
+// RepoRenameRequest holds the new name of the repository to be renamed
+type RepoRenameRequest struct {
+	// the full name in format "<project>/<repo>", the repository is moved
+	// to another project if the project part differs
+	Name string `json:"name"`
+}
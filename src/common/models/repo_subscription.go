@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// RepoStarTable is the name of table in DB that holds the repository stars
+	RepoStarTable = "repository_star"
+	// RepoSubscriptionTable is the name of table in DB that holds the repository subscriptions
+	RepoSubscriptionTable = "repository_subscription"
+
+	// SubscriptionEventNewTag is triggered when a new tag is pushed to the repository
+	SubscriptionEventNewTag = "new_tag"
+	// SubscriptionEventCriticalCVE is triggered when a critical vulnerability is found in an image of the repository
+	SubscriptionEventCriticalCVE = "critical_cve"
+	// SubscriptionEventPromotion is triggered when an image is promoted into the repository
+	SubscriptionEventPromotion = "promotion"
+	// SubscriptionEventScanDone is triggered when a scan of an image of the repository is done
+	SubscriptionEventScanDone = UserNotificationScanDone
+)
+
+// RepoStar records that a user starred a repository
+type RepoStar struct {
+	ID             int64     `orm:"pk;auto;column(id)" json:"id"`
+	UserID         int       `orm:"column(user_id)" json:"user_id"`
+	RepositoryName string    `orm:"column(repository_name)" json:"repository_name"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (r *RepoStar) TableName() string {
+	return RepoStarTable
+}
+
+// RepoSubscription holds the events of a repository which a user watches
+type RepoSubscription struct {
+	ID             int64     `orm:"pk;auto;column(id)" json:"id"`
+	UserID         int       `orm:"column(user_id)" json:"user_id"`
+	RepositoryName string    `orm:"column(repository_name)" json:"repository_name"`
+	EventsStr      string    `orm:"column(events)" json:"-"`
+	Events         []string  `orm:"-" json:"events"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime     time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RepoSubscription) TableName() string {
+	return RepoSubscriptionTable
+}
+
+// Valid ...
+func (r *RepoSubscription) Valid(v *validation.Validation) {
+	if len(r.Events) == 0 {
+		v.SetError("events", "cannot be empty")
+		return
+	}
+	for _, event := range r.Events {
+		if event != SubscriptionEventNewTag && event != SubscriptionEventCriticalCVE &&
+			event != SubscriptionEventPromotion && event != SubscriptionEventScanDone {
+			v.SetError("events", "invalid event "+event)
+			return
+		}
+	}
+}
+
+// Marshal converts the events to the string stored in DB
+func (r *RepoSubscription) Marshal() {
+	r.EventsStr = strings.Join(r.Events, ",")
+}
+
+// Unmarshal converts the string stored in DB to the events
+func (r *RepoSubscription) Unmarshal() {
+	r.Events = []string{}
+	if len(r.EventsStr) > 0 {
+		r.Events = strings.Split(r.EventsStr, ",")
+	}
+}
+
+// HasEvent returns whether the subscription contains the event
+func (r *RepoSubscription) HasEvent(event string) bool {
+	for _, e := range r.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
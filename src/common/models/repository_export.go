@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// RepositoryExportTable is the name of table in DB that holds the exports of the repository lists
+const RepositoryExportTable = "repository_export"
+
+// the formats of the repository export
+const (
+	RepositoryExportCSV  = "csv"
+	RepositoryExportJSON = "json"
+)
+
+// the status of the repository export
+const (
+	RepositoryExportRunning   = "running"
+	RepositoryExportSucceeded = "succeeded"
+	RepositoryExportFailed    = "failed"
+)
+
+// RepositoryExport records an export of the full repository list of a project, the dump
+// is generated in background and downloaded once it succeeds
+type RepositoryExport struct {
+	ID              int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID       int64     `orm:"column(project_id)" json:"project_id"`
+	Format          string    `orm:"column(format)" json:"format"`
+	Status          string    `orm:"column(status)" json:"status"`
+	Message         string    `orm:"column(message)" json:"message,omitempty"`
+	RepositoryCount int64     `orm:"column(repository_count)" json:"repository_count"`
+	Size            int64     `orm:"column(size)" json:"size"`
+	Creator         string    `orm:"column(creator)" json:"creator"`
+	CreationTime    time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime      time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RepositoryExport) TableName() string {
+	return RepositoryExportTable
+}
+
+// RepositoryExportQuery ...
+type RepositoryExportQuery struct {
+	ProjectID int64
+	Status    string
+	Pagination
+}
+
+// RepositoryExportRequest is the request to export the repository list, the format is
+// "csv" by default
+type RepositoryExportRequest struct {
+	Format string `json:"format"`
+}
@@ -0,0 +1,137 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// RepoRetentionTable is the name of table in DB that holds the retention overrides of repositories
+	RepoRetentionTable = "repository_retention"
+
+	// RetentionModeInherit means the repository follows the retention policy of the project
+	RetentionModeInherit = "inherit"
+	// RetentionModeOverride means the repository has its own retention policy
+	RetentionModeOverride = "override"
+	// RetentionModeDisabled means the repository opts out the retention
+	RetentionModeDisabled = "disabled"
+)
+
+// RetentionPolicy decides which tags of a repository are retained, a tag is retained if
+// it matches any of the rules and the others are deleted, all the tags are retained if
+// the policy has no rule.
+type RetentionPolicy struct {
+	// retain the N most recently pushed tags
+	KeepLatest int `json:"keep_latest"`
+	// retain the tags pulled within the days
+	KeepPulledWithinDays int `json:"keep_pulled_within_days"`
+	// retain the tags matching the patterns, e.g. "v*"
+	KeepTags []string `json:"keep_tags"`
+}
+
+// Valid ...
+func (r *RetentionPolicy) Valid(v *validation.Validation) {
+	if r.KeepLatest < 0 {
+		v.SetError("keep_latest", "cannot be negative")
+	}
+	if r.KeepPulledWithinDays < 0 {
+		v.SetError("keep_pulled_within_days", "cannot be negative")
+	}
+	for _, pattern := range r.KeepTags {
+		if _, err := path.Match(pattern, ""); err != nil {
+			v.SetError("keep_tags", "invalid pattern "+pattern)
+			return
+		}
+	}
+}
+
+// ParseRetentionPolicy parses the retention policy in JSON and validates it
+func ParseRetentionPolicy(value string) (*RetentionPolicy, error) {
+	policy := &RetentionPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, err
+	}
+	v := &validation.Validation{}
+	policy.Valid(v)
+	if v.HasErrors() {
+		return nil, fmt.Errorf("%s %s", v.Errors[0].Field, v.Errors[0].Message)
+	}
+	return policy, nil
+}
+
+// RepoRetention holds the retention override of a repository
+type RepoRetention struct {
+	ID             int64            `orm:"pk;auto;column(id)" json:"id"`
+	RepositoryName string           `orm:"column(repository_name)" json:"repository_name"`
+	Mode           string           `orm:"column(mode)" json:"mode"`
+	PolicyStr      string           `orm:"column(policy)" json:"-"`
+	Policy         *RetentionPolicy `orm:"-" json:"policy,omitempty"`
+	CreationTime   time.Time        `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime     time.Time        `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RepoRetention) TableName() string {
+	return RepoRetentionTable
+}
+
+// Valid ...
+func (r *RepoRetention) Valid(v *validation.Validation) {
+	switch r.Mode {
+	case RetentionModeOverride:
+		if r.Policy == nil {
+			v.SetError("policy", "cannot be empty when the mode is override")
+			return
+		}
+		r.Policy.Valid(v)
+	case RetentionModeDisabled:
+	default:
+		v.SetError("mode", "invalid mode "+r.Mode)
+	}
+}
+
+// Marshal converts the policy to the string stored in DB
+func (r *RepoRetention) Marshal() error {
+	r.PolicyStr = ""
+	if r.Mode != RetentionModeOverride || r.Policy == nil {
+		return nil
+	}
+	data, err := json.Marshal(r.Policy)
+	if err != nil {
+		return err
+	}
+	r.PolicyStr = string(data)
+	return nil
+}
+
+// Unmarshal converts the string stored in DB to the policy
+func (r *RepoRetention) Unmarshal() error {
+	r.Policy = nil
+	if len(r.PolicyStr) == 0 {
+		return nil
+	}
+	policy := &RetentionPolicy{}
+	if err := json.Unmarshal([]byte(r.PolicyStr), policy); err != nil {
+		return err
+	}
+	r.Policy = policy
+	return nil
+}
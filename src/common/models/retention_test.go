@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionPolicy(t *testing.T) {
+	policy, err := ParseRetentionPolicy(`{"keep_latest":5,"keep_tags":["v*"]}`)
+	require.Nil(t, err)
+	assert.Equal(t, 5, policy.KeepLatest)
+	assert.Equal(t, []string{"v*"}, policy.KeepTags)
+
+	_, err = ParseRetentionPolicy(`invalid`)
+	assert.NotNil(t, err)
+	_, err = ParseRetentionPolicy(`{"keep_latest":-1}`)
+	assert.NotNil(t, err)
+	_, err = ParseRetentionPolicy(`{"keep_tags":["["]}`)
+	assert.NotNil(t, err)
+
+	p := &Project{}
+	assert.Nil(t, p.RetentionPolicy())
+	p.SetMetadata(ProMetaRetentionPolicy, `{"keep_pulled_within_days":30}`)
+	require.NotNil(t, p.RetentionPolicy())
+	assert.Equal(t, 30, p.RetentionPolicy().KeepPulledWithinDays)
+}
+
+func TestRepoRetention(t *testing.T) {
+	cases := []struct {
+		retention *RepoRetention
+		valid     bool
+	}{
+		{&RepoRetention{Mode: RetentionModeDisabled}, true},
+		{&RepoRetention{Mode: RetentionModeOverride, Policy: &RetentionPolicy{KeepLatest: 1}}, true},
+		{&RepoRetention{Mode: RetentionModeOverride}, false},
+		{&RepoRetention{Mode: RetentionModeOverride, Policy: &RetentionPolicy{KeepLatest: -1}}, false},
+		{&RepoRetention{Mode: RetentionModeInherit}, false},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.retention.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "mode: %s", c.retention.Mode)
+	}
+
+	retention := &RepoRetention{
+		Mode:   RetentionModeOverride,
+		Policy: &RetentionPolicy{KeepTags: []string{"release-*"}},
+	}
+	require.Nil(t, retention.Marshal())
+	retention.Policy = nil
+	require.Nil(t, retention.Unmarshal())
+	require.NotNil(t, retention.Policy)
+	assert.Equal(t, []string{"release-*"}, retention.Policy.KeepTags)
+
+	// the policy is dropped when the retention is disabled
+	retention.Mode = RetentionModeDisabled
+	require.Nil(t, retention.Marshal())
+	assert.Equal(t, "", retention.PolicyStr)
+}
@@ -0,0 +1,37 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// RetiredTokenKeyTable is the name of table in DB that holds the retired token signing keys
+const RetiredTokenKeyTable = "retired_token_key"
+
+// RetiredTokenKey is the public key of a token signing key replaced by the rotation, the
+// tokens signed by it are still verified until it expires
+type RetiredTokenKey struct {
+	ID int64 `orm:"pk;auto;column(id)" json:"id"`
+	// the PEM of the public key
+	PublicKey    string    `orm:"column(public_key)" json:"public_key"`
+	ExpiresAt    time.Time `orm:"column(expires_at)" json:"expires_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (r *RetiredTokenKey) TableName() string {
+	return RetiredTokenKeyTable
+}
@@ -15,14 +15,26 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/astaxie/beego/validation"
 	"github.com/goharbor/harbor/src/common/rbac"
+	"regexp"
 	"time"
 )
 
+// the names of the Kubernetes secret and namespace must be the DNS subdomain and label
+var (
+	kubeSecretNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	kubeNamespaceRegexp  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+)
+
 // RobotTable is the name of table in DB that holds the robot object
 const RobotTable = "robot"
 
+// RobotReplicaTable is the name of table in DB that holds the tokens of the robots re-issued by the replication targets
+const RobotReplicaTable = "robot_replica"
+
 // Robot holds the details of a robot.
 type Robot struct {
 	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
@@ -31,10 +43,49 @@ type Robot struct {
 	Description  string    `orm:"column(description)" json:"description"`
 	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
 	Disabled     bool      `orm:"column(disabled)" json:"disabled"`
+	Access       string    `orm:"column(access);null" json:"-"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
 
+// GetAccess returns the access granted to the robot, nil is returned if it's unknown
+func (r *Robot) GetAccess() ([]*rbac.Policy, error) {
+	if len(r.Access) == 0 {
+		return nil, nil
+	}
+	access := []*rbac.Policy{}
+	if err := json.Unmarshal([]byte(r.Access), &access); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// AddAccess adds the access in a new token issued to the robot into the granted one
+func (r *Robot) AddAccess(access []*rbac.Policy) error {
+	granted, err := r.GetAccess()
+	if err != nil {
+		return err
+	}
+	for _, a := range access {
+		exist := false
+		for _, g := range granted {
+			if g.Resource == a.Resource && g.Action == a.Action && g.GetEffect() == a.GetEffect() {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			granted = append(granted, a)
+		}
+	}
+	data, err := json.Marshal(granted)
+	if err != nil {
+		return err
+	}
+	r.Access = string(data)
+	return nil
+}
+
 // RobotQuery ...
 type RobotQuery struct {
 	Name           string
@@ -67,3 +118,75 @@ type RobotRep struct {
 func (r *Robot) TableName() string {
 	return RobotTable
 }
+
+// RobotReplicationReq is the request replicating the robots of the project from the source
+// Harbor, the robots are matched by name and the resources of the access are in the name of
+// the project. The robots which aren't in the request are removed if Prune is true
+type RobotReplicationReq struct {
+	Project string      `json:"project"`
+	Robots  []*RobotReq `json:"robots"`
+	Prune   bool        `json:"prune"`
+}
+
+// Valid ...
+func (r *RobotReplicationReq) Valid(v *validation.Validation) {
+	if len(r.Project) == 0 {
+		v.SetError("project", "can not be empty")
+	}
+	names := map[string]bool{}
+	for _, robot := range r.Robots {
+		if len(robot.Name) == 0 {
+			v.SetError("robots", "the name can not be empty")
+			return
+		}
+		if len(robot.Access) == 0 {
+			v.SetError("robots", fmt.Sprintf("the access of robot %s can not be empty", robot.Name))
+			return
+		}
+		if names[robot.Name] {
+			v.SetError("robots", fmt.Sprintf("duplicate robot %s", robot.Name))
+			return
+		}
+		names[robot.Name] = true
+	}
+}
+
+// RobotReplica is the token of the robot re-issued by the replication target under its own key
+type RobotReplica struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	RobotID      int64     `orm:"column(robot_id)" json:"robot_id"`
+	TargetID     int64     `orm:"column(target_id)" json:"target_id"`
+	Token        string    `orm:"column(token)" json:"token"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RobotReplica) TableName() string {
+	return RobotReplicaTable
+}
+
+// PullSecretReq is the request of generating the Kubernetes image pull secret, the
+// secret is bound to the existing robot specified by the ID or a new robot named RobotName
+type PullSecretReq struct {
+	RobotID    int64  `json:"robot_id"`
+	RobotName  string `json:"robot_name"`
+	SecretName string `json:"secret_name"`
+	Namespace  string `json:"namespace"`
+}
+
+// Valid ...
+func (p *PullSecretReq) Valid(v *validation.Validation) {
+	if (p.RobotID > 0) == (len(p.RobotName) > 0) {
+		v.SetError("robot", "one of robot_id and robot_name is required")
+	}
+	if p.RobotID < 0 {
+		v.SetError("robot_id", "invalid value")
+	}
+	if len(p.SecretName) > 253 || (len(p.SecretName) > 0 && !kubeSecretNameRegexp.MatchString(p.SecretName)) {
+		v.SetError("secret_name", "must be a DNS subdomain")
+	}
+	if len(p.Namespace) > 63 || (len(p.Namespace) > 0 && !kubeNamespaceRegexp.MatchString(p.Namespace)) {
+		v.SetError("namespace", "must be a DNS label")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidOfPullSecretReq(t *testing.T) {
+	cases := []struct {
+		req   *PullSecretReq
+		valid bool
+	}{
+		{&PullSecretReq{}, false},
+		{&PullSecretReq{RobotID: 1, RobotName: "ci"}, false},
+		{&PullSecretReq{RobotID: -1}, false},
+		{&PullSecretReq{RobotName: "ci", SecretName: "Pull_Secret"}, false},
+		{&PullSecretReq{RobotName: "ci", Namespace: "kube.system"}, false},
+		{&PullSecretReq{RobotID: 1}, true},
+		{&PullSecretReq{RobotName: "ci", SecretName: "harbor.pull-secret", Namespace: "kube-system"}, true},
+	}
+	for i, c := range cases {
+		v := &validation.Validation{}
+		c.req.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "case %d", i)
+	}
+}
+
+func TestRobotAccess(t *testing.T) {
+	robot := &Robot{}
+	access, err := robot.GetAccess()
+	require.Nil(t, err)
+	assert.Nil(t, access)
+
+	pull := &rbac.Policy{Resource: "/project/1/repository", Action: rbac.ActionPull}
+	push := &rbac.Policy{Resource: "/project/1/repository", Action: rbac.ActionPush}
+	require.Nil(t, robot.AddAccess([]*rbac.Policy{pull}))
+	require.Nil(t, robot.AddAccess([]*rbac.Policy{pull, push}))
+	access, err = robot.GetAccess()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(access))
+	assert.Equal(t, rbac.ActionPull, access[0].Action)
+	assert.Equal(t, rbac.ActionPush, access[1].Action)
+}
+
+func TestRobotReplicationReqValid(t *testing.T) {
+	pull := []*rbac.Policy{{Resource: "/project/library/repository", Action: rbac.ActionPull}}
+	cases := []struct {
+		req   *RobotReplicationReq
+		valid bool
+	}{
+		{&RobotReplicationReq{}, false},
+		{&RobotReplicationReq{Project: "library", Robots: []*RobotReq{{Access: pull}}}, false},
+		{&RobotReplicationReq{Project: "library", Robots: []*RobotReq{{Name: "ci"}}}, false},
+		{&RobotReplicationReq{Project: "library", Robots: []*RobotReq{{Name: "ci", Access: pull}, {Name: "ci", Access: pull}}}, false},
+		{&RobotReplicationReq{Project: "library"}, true},
+		{&RobotReplicationReq{Project: "library", Robots: []*RobotReq{{Name: "ci", Access: pull}, {Name: "cd", Access: pull}}}, true},
+	}
+	for i, c := range cases {
+		v := &validation.Validation{}
+		c.req.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "case %d", i)
+	}
+}
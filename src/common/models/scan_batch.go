@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"path"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// ScanBatchTable is the name of table in DB that holds the batches of scans
+const ScanBatchTable = "scan_batch"
+
+// the status of the batch of scans
+const (
+	ScanBatchExpanding = "expanding"
+	ScanBatchSubmitted = "submitted"
+	ScanBatchFailed    = "failed"
+)
+
+// ScanBatch is a batch of scans of the images matching the filters, the scan jobs of the
+// batch are submitted on the server side and tracked by its ID. The patterns of the
+// repository and tag are in the glob syntax of path.Match, the repository pattern is
+// matched against the full name, e.g. "library/app-*"
+type ScanBatch struct {
+	ID           int64            `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64            `orm:"column(project_id)" json:"project_id"`
+	Repository   string           `orm:"column(repository)" json:"repository"`
+	Tag          string           `orm:"column(tag)" json:"tag"`
+	LabelID      int64            `orm:"column(label_id)" json:"label_id"`
+	Creator      string           `orm:"column(creator)" json:"creator"`
+	Status       string           `orm:"column(status)" json:"status"`
+	Total        int              `orm:"column(total)" json:"total"`
+	Failed       int              `orm:"column(failed)" json:"failed"`
+	Error        string           `orm:"column(error)" json:"error,omitempty"`
+	Jobs         map[string]int64 `orm:"-" json:"jobs,omitempty"`
+	CreationTime time.Time        `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time        `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (s *ScanBatch) TableName() string {
+	return ScanBatchTable
+}
+
+// ScanBatchReq is the filters of the images scanned by the batch
+type ScanBatchReq struct {
+	ProjectID  int64  `json:"project_id"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	LabelID    int64  `json:"label_id"`
+}
+
+// Valid ...
+func (s *ScanBatchReq) Valid(v *validation.Validation) {
+	if s.ProjectID < 0 {
+		v.SetError("project_id", "cannot be negative")
+	}
+	if s.LabelID < 0 {
+		v.SetError("label_id", "cannot be negative")
+	}
+	if _, err := path.Match(s.Repository, ""); err != nil {
+		v.SetError("repository", "invalid pattern: "+err.Error())
+	}
+	if _, err := path.Match(s.Tag, ""); err != nil {
+		v.SetError("tag", "invalid pattern: "+err.Error())
+	}
+}
+
+// MatchRepository returns whether the repository is matched by the pattern of the batch
+func (s *ScanBatch) MatchRepository(repository string) bool {
+	if len(s.Repository) == 0 {
+		return true
+	}
+	matched, _ := path.Match(s.Repository, repository)
+	return matched
+}
+
+// Match returns whether the image is matched by the patterns of the batch, the empty
+// patterns match everything
+func (s *ScanBatch) Match(repository, tag string) bool {
+	if !s.MatchRepository(repository) {
+		return false
+	}
+	if len(s.Tag) > 0 {
+		if matched, _ := path.Match(s.Tag, tag); !matched {
+			return false
+		}
+	}
+	return true
+}
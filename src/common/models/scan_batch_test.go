@@ -0,0 +1,55 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanBatchReq(t *testing.T) {
+	for _, c := range []struct {
+		req   *ScanBatchReq
+		valid bool
+	}{
+		{&ScanBatchReq{}, true},
+		{&ScanBatchReq{ProjectID: 1, Repository: "library/app-*", Tag: "v1.*", LabelID: 1}, true},
+		{&ScanBatchReq{ProjectID: -1}, false},
+		{&ScanBatchReq{Repository: "library/[app"}, false},
+		{&ScanBatchReq{Tag: "[v1"}, false},
+	} {
+		v := &validation.Validation{}
+		c.req.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "%+v", c.req)
+	}
+}
+
+func TestScanBatchMatch(t *testing.T) {
+	batch := &ScanBatch{}
+	assert.True(t, batch.Match("library/app", "latest"))
+
+	batch = &ScanBatch{
+		Repository: "library/app-*",
+		Tag:        "v1.*",
+	}
+	assert.True(t, batch.Match("library/app-web", "v1.2"))
+	assert.True(t, batch.MatchRepository("library/app-web"))
+	assert.False(t, batch.MatchRepository("library/web"))
+	assert.False(t, batch.Match("library/app/web", "v1.2"))
+	assert.False(t, batch.Match("other/app-web", "v1.2"))
+	assert.False(t, batch.Match("library/app-web", "v2.0"))
+}
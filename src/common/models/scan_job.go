@@ -30,6 +30,7 @@ type ScanJob struct {
 	Tag          string    `orm:"column(tag)" json:"tag"`
 	Digest       string    `orm:"column(digest)" json:"digest"`
 	UUID         string    `orm:"column(job_uuid)" json:"-"`
+	BatchID      int64     `orm:"column(batch_id)" json:"batch_id,omitempty"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
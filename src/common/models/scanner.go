@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ScannerHealth is the health status of the scanner adapter
+type ScannerHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// the time when the vulnerability database of the scanner is updated last time
+	VulnDBUpdateTime *time.Time `json:"vulnerability_db_update_time,omitempty"`
+	// the error rates of the scan jobs in the recent time windows, e.g. "1h", "24h"
+	ErrorRates map[string]*ScanErrorRate `json:"error_rates"`
+}
+
+// ScanErrorRate is the error rate of the scan jobs completed in a time window,
+// the rate is 0 if no job is completed
+type ScanErrorRate struct {
+	Total  int64   `json:"total"`
+	Failed int64   `json:"failed"`
+	Rate   float64 `json:"rate"`
+}
+
+// ScannerCapabilities describes what the scanner adapter supports
+type ScannerCapabilities struct {
+	Name       string `json:"name"`
+	Vendor     string `json:"vendor"`
+	APIVersion string `json:"api_version"`
+	// the media types of the manifests and layers which can be scanned
+	ManifestMimeTypes []string `json:"manifest_mime_types"`
+	LayerMimeTypes    []string `json:"layer_mime_types"`
+	// the namespaces of the vulnerability data, e.g. "debian:9"
+	Namespaces []string `json:"namespaces"`
+}
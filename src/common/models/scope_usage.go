@@ -0,0 +1,83 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/rbac"
+)
+
+// the kinds of the identities whose scope usage is reported
+const (
+	ScopeUsageUser  = "user"
+	ScopeUsageRobot = "robot"
+)
+
+// ScopeUsage compares the permissions held by a user or robot with the repositories it
+// actually pulled and pushed in the time range, it's used to review the least privilege
+type ScopeUsage struct {
+	Identity  string     `json:"identity"`
+	Kind      string     `json:"kind"`
+	BeginTime *time.Time `json:"begin_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	// the system admin holds all the permissions of all projects
+	SysAdmin bool `json:"sysadmin"`
+	// whether the granted permissions are known, they are unknown for the robots
+	// created before the access of robots is recorded
+	GrantKnown bool                 `json:"grant_known"`
+	Projects   []*ProjectScopeUsage `json:"projects"`
+}
+
+// ProjectScopeUsage is the scope usage in a project. The unused actions are granted but
+// not used in the time range, they are the candidates to prune. The used actions which
+// aren't granted are allowed by other means, e.g. the project is public
+type ProjectScopeUsage struct {
+	ProjectID      int64             `json:"project_id"`
+	ProjectName    string            `json:"project_name"`
+	Role           string            `json:"role,omitempty"`
+	GrantedActions []string          `json:"granted_actions"`
+	UsedActions    []string          `json:"used_actions"`
+	UnusedActions  []string          `json:"unused_actions"`
+	Repositories   []*RepoScopeUsage `json:"repositories"`
+}
+
+// RepoScopeUsage is the count of pulls and pushes of a repository in the time range
+type RepoScopeUsage struct {
+	Repository   string     `json:"repository"`
+	PullCount    int64      `json:"pull_count"`
+	PushCount    int64      `json:"push_count"`
+	LastPullTime *time.Time `json:"last_pull_time,omitempty"`
+	LastPushTime *time.Time `json:"last_push_time,omitempty"`
+}
+
+// RobotPermissionReview compares the access granted to the robot with its scope usage in the
+// time range and suggests the minimal access. The suggested access only narrows the pulls
+// and pushes of the repositories, the other access isn't observed and is kept as granted
+type RobotPermissionReview struct {
+	RobotID   int64      `json:"robot_id"`
+	RobotName string     `json:"robot_name"`
+	BeginTime *time.Time `json:"begin_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	// whether the granted access is known, nothing is suggested if it's unknown
+	GrantKnown      bool           `json:"grant_known"`
+	GrantedAccess   []*rbac.Policy `json:"granted_access"`
+	SuggestedAccess []*rbac.Policy `json:"suggested_access"`
+	// whether the suggested access is narrower than the granted one
+	Downgrade bool `json:"downgrade"`
+	// whether the suggested access has been applied to the robot
+	Applied bool               `json:"applied"`
+	Usage   *ProjectScopeUsage `json:"usage,omitempty"`
+}
@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// SearchQuery holds the keyword and the visibility of the global search, all the
+// projects are visible to the system admin and the public ones are visible to everyone
+type SearchQuery struct {
+	Keyword  string
+	SysAdmin bool
+	// the projects which the user is the member of are visible
+	Username string
+	// the projects which the LDAP groups are the member of are visible, it's built
+	// by group.GetGroupDNQueryCondition
+	GroupDNCondition string
+	// the extra visible projects, e.g. the project of the robot account
+	ProjectIDs []int64
+	// the max count of the results of each section, 0 means no limit
+	Limit int64
+}
+
+// SearchCount holds the total count of the matched results of each section
+type SearchCount struct {
+	Project    int64 `json:"project"`
+	Repository int64 `json:"repository"`
+	Chart      int64 `json:"chart"`
+	Label      int64 `json:"label"`
+	User       int64 `json:"user"`
+	Tag        int64 `json:"tag"`
+	Artifact   int64 `json:"artifact"`
+}
+
+// SearchUser is the user returned by the global search, only the fields
+// which can be seen by the others are included
+type SearchUser struct {
+	UserID   int    `orm:"column(user_id)" json:"user_id"`
+	Username string `orm:"column(username)" json:"username"`
+	Realname string `orm:"column(realname)" json:"realname"`
+}
+
+// SearchRepository is the repository returned by the global search
+type SearchRepository struct {
+	RepositoryName string `orm:"column(repository_name)" json:"repository_name"`
+	ProjectID      int64  `orm:"column(project_id)" json:"project_id"`
+	ProjectName    string `orm:"column(project_name)" json:"project_name"`
+	ProjectPublic  bool   `orm:"column(project_public)" json:"project_public"`
+	PullCount      int64  `orm:"column(pull_count)" json:"pull_count"`
+	TagsCount      int    `orm:"-" json:"tags_count"`
+}
+
+// SearchArtifact is the repository or the tag whose metadata matches the keyword
+// in the full-text search, the tag is empty if the repository matches
+type SearchArtifact struct {
+	RepositoryName string  `orm:"column(repository_name)" json:"repository_name"`
+	Tag            string  `orm:"column(tag)" json:"tag,omitempty"`
+	Digest         string  `orm:"column(digest)" json:"digest,omitempty"`
+	Description    string  `orm:"column(description)" json:"description,omitempty"`
+	ProjectID      int64   `orm:"column(project_id)" json:"project_id"`
+	ProjectName    string  `orm:"column(project_name)" json:"project_name"`
+	Rank           float64 `orm:"column(rank)" json:"rank"`
+}
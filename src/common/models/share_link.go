@@ -0,0 +1,120 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common"
+)
+
+const (
+	// ShareLinkTable is the name of table in DB that holds the share links of images
+	ShareLinkTable = "share_link"
+
+	// MaxShareLinkTTL is the max time a share link can be used for since it's created
+	MaxShareLinkTTL = 30 * 24 * time.Hour
+	// MaxShareLinkDescriptionLength is the max length of the description of the share links
+	MaxShareLinkDescriptionLength = 1024
+)
+
+// ShareLink lets the holder of its token pull one image of a private project without
+// being onboarded, until it expires or is revoked. The revoked links are kept with
+// the usage as the history
+type ShareLink struct {
+	ID         int64  `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID  int64  `orm:"column(project_id)" json:"project_id"`
+	Repository string `orm:"column(repository)" json:"repository"`
+	// the shared tag or digest
+	Reference string `orm:"column(reference)" json:"reference"`
+	// the digest the reference resolved to when the link was created
+	Digest       string     `orm:"column(digest)" json:"digest"`
+	Description  string     `orm:"column(description)" json:"description"`
+	Creator      string     `orm:"column(creator)" json:"creator"`
+	ExpiresAt    time.Time  `orm:"column(expires_at)" json:"expires_at"`
+	Revoked      bool       `orm:"column(revoked)" json:"revoked"`
+	PullCount    int64      `orm:"column(pull_count)" json:"pull_count"`
+	LastPullTime *time.Time `orm:"column(last_pull_time);null" json:"last_pull_time,omitempty"`
+	CreationTime time.Time  `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	// the username to log in with the token of the link
+	Username string `orm:"-" json:"username"`
+}
+
+// TableName ...
+func (s *ShareLink) TableName() string {
+	return ShareLinkTable
+}
+
+// Valid ...
+func (s *ShareLink) Valid(v *validation.Validation) {
+	if len(s.Repository) == 0 {
+		v.SetError("repository", "cannot be empty")
+	}
+	if len(s.Reference) == 0 {
+		v.SetError("reference", "cannot be empty")
+	}
+	if len(s.Description) > MaxShareLinkDescriptionLength {
+		v.SetError("description", fmt.Sprintf("max length is %d", MaxShareLinkDescriptionLength))
+	}
+	now := time.Now()
+	if !s.ExpiresAt.After(now) {
+		v.SetError("expires_at", "must be in the future")
+	} else if s.ExpiresAt.After(now.Add(MaxShareLinkTTL)) {
+		v.SetError("expires_at", fmt.Sprintf("must be within %v", MaxShareLinkTTL))
+	}
+}
+
+// Active returns whether the link can be used at the time
+func (s *ShareLink) Active(t time.Time) bool {
+	return !s.Revoked && t.Before(s.ExpiresAt)
+}
+
+// ShareLinkUsername returns the username of the share link, e.g. "share$1"
+func ShareLinkUsername(id int64) string {
+	return common.SharePrefix + strconv.FormatInt(id, 10)
+}
+
+// ParseShareLinkUsername returns the ID of the share link if the username is the one of a share link
+func ParseShareLinkUsername(username string) (int64, bool) {
+	if !strings.HasPrefix(username, common.SharePrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(username, common.SharePrefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// ShareLinkRep is the response of creating a share link, the token is only returned once
+type ShareLinkRep struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ShareLinkQuery ...
+type ShareLinkQuery struct {
+	ProjectID  int64
+	Repository string
+	// only the links which aren't revoked or expired
+	Active bool
+	Pagination
+}
@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareLinkValid(t *testing.T) {
+	cases := []struct {
+		link  *ShareLink
+		valid bool
+	}{
+		{&ShareLink{Reference: "1.0", ExpiresAt: time.Now().Add(time.Hour)}, false},
+		{&ShareLink{Repository: "library/app", ExpiresAt: time.Now().Add(time.Hour)}, false},
+		{&ShareLink{Repository: "library/app", Reference: "1.0", ExpiresAt: time.Now().Add(-time.Hour)}, false},
+		{&ShareLink{Repository: "library/app", Reference: "1.0", ExpiresAt: time.Now().Add(MaxShareLinkTTL + time.Hour)}, false},
+		{&ShareLink{Repository: "library/app", Reference: "1.0", ExpiresAt: time.Now().Add(time.Hour)}, true},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.link.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors())
+	}
+}
+
+func TestShareLinkActive(t *testing.T) {
+	now := time.Now()
+	link := &ShareLink{ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, link.Active(now))
+	assert.False(t, link.Active(now.Add(2*time.Hour)))
+	link.Revoked = true
+	assert.False(t, link.Active(now))
+}
+
+func TestShareLinkUsername(t *testing.T) {
+	assert.Equal(t, "share$12", ShareLinkUsername(12))
+
+	id, ok := ParseShareLinkUsername("share$12")
+	assert.True(t, ok)
+	assert.Equal(t, int64(12), id)
+
+	for _, username := range []string{"admin", "robot$12", "share$", "share$abc", "share$-1"} {
+		_, ok = ParseShareLinkUsername(username)
+		assert.False(t, ok)
+	}
+}
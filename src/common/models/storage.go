@@ -0,0 +1,220 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// StorageProviderOSS is the storage driver of Alibaba Cloud OSS
+	StorageProviderOSS = "oss"
+	// StorageProviderCOS is Tencent Cloud COS, registry accesses it with the driver
+	// "s3" via the S3 compatible API of COS
+	StorageProviderCOS = "cos"
+)
+
+var (
+	ossRegionRegexp = regexp.MustCompile(`^oss-[a-z0-9-]+$`)
+	ossBucketRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+	cosRegionRegexp = regexp.MustCompile(`^[a-z]+-[a-z0-9-]+$`)
+	// the bucket of COS is named as "<name>-<APPID>"
+	cosBucketRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}-[0-9]+$`)
+	hostnameRegexp  = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?$`)
+)
+
+// RegistryStorage is the storage of registry whose driver is configured with the first-class
+// settings of Harbor rather than the free-form "registry_storage_provider_config"
+type RegistryStorage struct {
+	Provider string      `json:"provider"`
+	OSS      *OSSStorage `json:"oss,omitempty"`
+	COS      *COSStorage `json:"cos,omitempty"`
+}
+
+// Valid ...
+func (r *RegistryStorage) Valid(v *validation.Validation) {
+	switch r.Provider {
+	case StorageProviderOSS:
+		if r.OSS == nil {
+			v.SetError("oss", "can not be empty")
+			return
+		}
+		r.OSS.Valid(v)
+	case StorageProviderCOS:
+		if r.COS == nil {
+			v.SetError("cos", "can not be empty")
+			return
+		}
+		r.COS.Valid(v)
+	default:
+		v.SetError("provider", fmt.Sprintf("unsupported provider %s, it should be %s or %s",
+			r.Provider, StorageProviderOSS, StorageProviderCOS))
+	}
+}
+
+// Driver returns the name and the parameters of the storage driver in the configuration of registry
+func (r *RegistryStorage) Driver() (string, map[string]interface{}) {
+	switch r.Provider {
+	case StorageProviderOSS:
+		return "oss", r.OSS.Parameters()
+	case StorageProviderCOS:
+		return "s3", r.COS.Parameters()
+	}
+	return "", nil
+}
+
+// OSSStorage is the settings of the storage driver "oss" of registry
+type OSSStorage struct {
+	// e.g. "oss-cn-hangzhou"
+	Region string `json:"region"`
+	// the custom endpoint, e.g. the accelerated domain, the one of the region is used if it's empty
+	Endpoint        string `json:"endpoint,omitempty"`
+	Bucket          string `json:"bucket"`
+	AccessKeyID     string `json:"access_key_id"`
+	AccessKeySecret string `json:"access_key_secret,omitempty"`
+	// access OSS via the internal network of the region, it's free of the traffic charge
+	// when Harbor runs on the ECS in the same region
+	Internal      bool   `json:"internal"`
+	Secure        bool   `json:"secure"`
+	RootDirectory string `json:"root_directory,omitempty"`
+}
+
+// Valid ...
+func (o *OSSStorage) Valid(v *validation.Validation) {
+	if !ossRegionRegexp.MatchString(o.Region) {
+		v.SetError("region", fmt.Sprintf("invalid region %s of OSS, e.g. oss-cn-hangzhou", o.Region))
+	}
+	if len(o.Endpoint) > 0 {
+		if !hostnameRegexp.MatchString(o.Endpoint) {
+			v.SetError("endpoint", fmt.Sprintf("invalid endpoint %s, it should be a host without the scheme", o.Endpoint))
+		}
+		if o.Internal {
+			v.SetError("internal", "can not be enabled with the custom endpoint")
+		}
+	}
+	if !ossBucketRegexp.MatchString(o.Bucket) {
+		v.SetError("bucket", fmt.Sprintf("invalid bucket name %s", o.Bucket))
+	}
+	validCredential(v, "access_key_id", o.AccessKeyID, "access_key_secret", o.AccessKeySecret)
+	validRootDirectory(v, o.RootDirectory)
+}
+
+// Parameters returns the parameters of the storage driver "oss" of registry
+func (o *OSSStorage) Parameters() map[string]interface{} {
+	params := map[string]interface{}{
+		"accesskeyid":     o.AccessKeyID,
+		"accesskeysecret": o.AccessKeySecret,
+		"region":          o.Region,
+		"bucket":          o.Bucket,
+		"internal":        o.Internal,
+		"secure":          o.Secure,
+	}
+	if len(o.Endpoint) > 0 {
+		params["endpoint"] = o.Endpoint
+	}
+	if len(o.RootDirectory) > 0 {
+		params["rootdirectory"] = o.RootDirectory
+	}
+	return params
+}
+
+// COSStorage is the settings of Tencent Cloud COS, which registry accesses with the
+// storage driver "s3" via the S3 compatible API
+type COSStorage struct {
+	// e.g. "ap-guangzhou"
+	Region string `json:"region"`
+	// the custom endpoint, the one of the region is used if it's empty
+	Endpoint string `json:"endpoint,omitempty"`
+	// e.g. "harbor-1250000000"
+	Bucket    string `json:"bucket"`
+	SecretID  string `json:"secret_id"`
+	SecretKey string `json:"secret_key,omitempty"`
+	// access COS via the internal endpoint of the region, which is only resolvable
+	// in the VPC of Tencent Cloud
+	Internal      bool   `json:"internal"`
+	Secure        bool   `json:"secure"`
+	RootDirectory string `json:"root_directory,omitempty"`
+}
+
+// Valid ...
+func (c *COSStorage) Valid(v *validation.Validation) {
+	if !cosRegionRegexp.MatchString(c.Region) {
+		v.SetError("region", fmt.Sprintf("invalid region %s of COS, e.g. ap-guangzhou", c.Region))
+	}
+	if len(c.Endpoint) > 0 {
+		if !hostnameRegexp.MatchString(c.Endpoint) {
+			v.SetError("endpoint", fmt.Sprintf("invalid endpoint %s, it should be a host without the scheme", c.Endpoint))
+		}
+		if c.Internal {
+			v.SetError("internal", "can not be enabled with the custom endpoint")
+		}
+	}
+	if !cosBucketRegexp.MatchString(c.Bucket) {
+		v.SetError("bucket", fmt.Sprintf("invalid bucket name %s, it should be <name>-<APPID>", c.Bucket))
+	}
+	validCredential(v, "secret_id", c.SecretID, "secret_key", c.SecretKey)
+	validRootDirectory(v, c.RootDirectory)
+}
+
+// EndpointHost returns the host of the S3 compatible endpoint of COS
+func (c *COSStorage) EndpointHost() string {
+	if len(c.Endpoint) > 0 {
+		return c.Endpoint
+	}
+	if c.Internal {
+		return fmt.Sprintf("cos-internal.%s.tencentcos.cn", c.Region)
+	}
+	return fmt.Sprintf("cos.%s.myqcloud.com", c.Region)
+}
+
+// Parameters returns the parameters of the storage driver "s3" of registry pointing to COS
+func (c *COSStorage) Parameters() map[string]interface{} {
+	scheme := "http"
+	if c.Secure {
+		scheme = "https"
+	}
+	params := map[string]interface{}{
+		"accesskey":      c.SecretID,
+		"secretkey":      c.SecretKey,
+		"region":         c.Region,
+		"regionendpoint": scheme + "://" + c.EndpointHost(),
+		"bucket":         c.Bucket,
+		"secure":         c.Secure,
+		"v4auth":         true,
+	}
+	if len(c.RootDirectory) > 0 {
+		params["rootdirectory"] = c.RootDirectory
+	}
+	return params
+}
+
+func validCredential(v *validation.Validation, idKey, id, secretKey, secret string) {
+	if len(id) == 0 {
+		v.SetError(idKey, "can not be empty")
+	}
+	if len(secret) == 0 {
+		v.SetError(secretKey, "can not be empty")
+	}
+}
+
+func validRootDirectory(v *validation.Validation, dir string) {
+	if len(dir) > 0 && !strings.HasPrefix(dir, "/") {
+		v.SetError("root_directory", fmt.Sprintf("invalid root directory %s, it should be an absolute path", dir))
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// RepoStorageHintTable is the name of table in DB that holds the storage hint overrides of repositories
+	RepoStorageHintTable = "repository_storage_hint"
+	// BlobTransitionTable is the name of table in DB that holds the blobs transitioned to other storage classes
+	BlobTransitionTable = "blob_transition"
+
+	// StorageHintModeInherit means the repository follows the storage hint of the project
+	StorageHintModeInherit = "inherit"
+	// StorageHintModeOverride means the repository has its own storage hint
+	StorageHintModeOverride = "override"
+	// StorageHintModeDisabled means the blobs of the repository are kept in the default storage class
+	StorageHintModeDisabled = "disabled"
+)
+
+// StorageClasses are the S3 storage classes which the blobs can be transitioned to, only the
+// ones whose objects can be read without being restored are supported, as the registry serves
+// the blobs directly
+var StorageClasses = []string{
+	"STANDARD_IA",
+	"ONEZONE_IA",
+	"INTELLIGENT_TIERING",
+	"GLACIER_IR",
+}
+
+// StorageHint hints that the blobs not pushed or pulled for the days may be transitioned to
+// a cheaper storage class
+type StorageHint struct {
+	StorageClass string `json:"storage_class"`
+	AfterDays    int    `json:"after_days"`
+}
+
+// Valid ...
+func (s *StorageHint) Valid(v *validation.Validation) {
+	if !isStorageClass(s.StorageClass) {
+		v.SetError("storage_class", "unsupported storage class "+s.StorageClass)
+	}
+	if s.AfterDays <= 0 {
+		v.SetError("after_days", "must be positive")
+	}
+}
+
+func isStorageClass(class string) bool {
+	for _, c := range StorageClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStorageHint parses the storage hint in JSON and validates it
+func ParseStorageHint(value string) (*StorageHint, error) {
+	hint := &StorageHint{}
+	if err := json.Unmarshal([]byte(value), hint); err != nil {
+		return nil, err
+	}
+	v := &validation.Validation{}
+	hint.Valid(v)
+	if v.HasErrors() {
+		return nil, fmt.Errorf("%s %s", v.Errors[0].Field, v.Errors[0].Message)
+	}
+	return hint, nil
+}
+
+// RepoStorageHint holds the storage hint override of a repository
+type RepoStorageHint struct {
+	ID             int64        `orm:"pk;auto;column(id)" json:"id"`
+	RepositoryName string       `orm:"column(repository_name)" json:"repository_name"`
+	Mode           string       `orm:"column(mode)" json:"mode"`
+	HintStr        string       `orm:"column(hint)" json:"-"`
+	Hint           *StorageHint `orm:"-" json:"hint,omitempty"`
+	CreationTime   time.Time    `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime     time.Time    `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RepoStorageHint) TableName() string {
+	return RepoStorageHintTable
+}
+
+// Valid ...
+func (r *RepoStorageHint) Valid(v *validation.Validation) {
+	switch r.Mode {
+	case StorageHintModeOverride:
+		if r.Hint == nil {
+			v.SetError("hint", "cannot be empty when the mode is override")
+			return
+		}
+		r.Hint.Valid(v)
+	case StorageHintModeDisabled:
+	default:
+		v.SetError("mode", "invalid mode "+r.Mode)
+	}
+}
+
+// Marshal converts the hint to the string stored in DB
+func (r *RepoStorageHint) Marshal() error {
+	r.HintStr = ""
+	if r.Mode != StorageHintModeOverride || r.Hint == nil {
+		return nil
+	}
+	data, err := json.Marshal(r.Hint)
+	if err != nil {
+		return err
+	}
+	r.HintStr = string(data)
+	return nil
+}
+
+// Unmarshal converts the string stored in DB to the hint
+func (r *RepoStorageHint) Unmarshal() error {
+	r.Hint = nil
+	if len(r.HintStr) == 0 {
+		return nil
+	}
+	hint := &StorageHint{}
+	if err := json.Unmarshal([]byte(r.HintStr), hint); err != nil {
+		return err
+	}
+	r.Hint = hint
+	return nil
+}
+
+// ResolveStorageHint returns the effective storage hint of the repository, the override takes
+// precedence over the hint of the project. Nil is returned if neither has a hint or the
+// repository opts out.
+func ResolveStorageHint(hint *StorageHint, override *RepoStorageHint) *StorageHint {
+	if override == nil {
+		return hint
+	}
+	switch override.Mode {
+	case StorageHintModeDisabled:
+		return nil
+	case StorageHintModeOverride:
+		return override.Hint
+	default:
+		return hint
+	}
+}
+
+// BlobTransition records a blob transitioned to another storage class
+type BlobTransition struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Digest       string    `orm:"column(digest)" json:"digest"`
+	StorageClass string    `orm:"column(storage_class)" json:"storage_class"`
+	Size         int64     `orm:"column(size)" json:"size"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (b *BlobTransition) TableName() string {
+	return BlobTransitionTable
+}
+
+// StorageClassUsage is the count and total size of the blobs transitioned to the storage class
+type StorageClassUsage struct {
+	StorageClass string `orm:"column(storage_class)" json:"storage_class"`
+	Count        int64  `orm:"column(count)" json:"count"`
+	Size         int64  `orm:"column(size)" json:"size"`
+}
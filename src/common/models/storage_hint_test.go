@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStorageHint(t *testing.T) {
+	hint, err := ParseStorageHint(`{"storage_class":"STANDARD_IA","after_days":30}`)
+	require.Nil(t, err)
+	assert.Equal(t, "STANDARD_IA", hint.StorageClass)
+	assert.Equal(t, 30, hint.AfterDays)
+
+	_, err = ParseStorageHint(`invalid`)
+	assert.NotNil(t, err)
+	// the objects in the storage class need to be restored before being read
+	_, err = ParseStorageHint(`{"storage_class":"GLACIER","after_days":30}`)
+	assert.NotNil(t, err)
+	_, err = ParseStorageHint(`{"storage_class":"ONEZONE_IA","after_days":0}`)
+	assert.NotNil(t, err)
+
+	p := &Project{}
+	assert.Nil(t, p.StorageHint())
+	p.SetMetadata(ProMetaStorageHint, `{"storage_class":"GLACIER_IR","after_days":90}`)
+	require.NotNil(t, p.StorageHint())
+	assert.Equal(t, "GLACIER_IR", p.StorageHint().StorageClass)
+}
+
+func TestRepoStorageHint(t *testing.T) {
+	cases := []struct {
+		hint  *RepoStorageHint
+		valid bool
+	}{
+		{&RepoStorageHint{Mode: StorageHintModeDisabled}, true},
+		{&RepoStorageHint{Mode: StorageHintModeOverride, Hint: &StorageHint{StorageClass: "STANDARD_IA", AfterDays: 7}}, true},
+		{&RepoStorageHint{Mode: StorageHintModeOverride}, false},
+		{&RepoStorageHint{Mode: StorageHintModeOverride, Hint: &StorageHint{StorageClass: "STANDARD", AfterDays: 7}}, false},
+		{&RepoStorageHint{Mode: StorageHintModeInherit}, false},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.hint.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "mode: %s", c.hint.Mode)
+	}
+
+	override := &RepoStorageHint{
+		Mode: StorageHintModeOverride,
+		Hint: &StorageHint{StorageClass: "INTELLIGENT_TIERING", AfterDays: 1},
+	}
+	require.Nil(t, override.Marshal())
+	override.Hint = nil
+	require.Nil(t, override.Unmarshal())
+	require.NotNil(t, override.Hint)
+	assert.Equal(t, "INTELLIGENT_TIERING", override.Hint.StorageClass)
+}
+
+func TestResolveStorageHint(t *testing.T) {
+	project := &StorageHint{StorageClass: "STANDARD_IA", AfterDays: 30}
+	repo := &StorageHint{StorageClass: "GLACIER_IR", AfterDays: 90}
+	assert.Nil(t, ResolveStorageHint(nil, nil))
+	assert.Equal(t, project, ResolveStorageHint(project, nil))
+	assert.Equal(t, repo, ResolveStorageHint(project, &RepoStorageHint{Mode: StorageHintModeOverride, Hint: repo}))
+	assert.Nil(t, ResolveStorageHint(project, &RepoStorageHint{Mode: StorageHintModeDisabled}))
+}
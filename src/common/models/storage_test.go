@@ -0,0 +1,88 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryStorageValid(t *testing.T) {
+	cases := []struct {
+		storage *RegistryStorage
+		valid   bool
+	}{
+		{&RegistryStorage{Provider: "filesystem"}, false},
+		{&RegistryStorage{Provider: StorageProviderOSS}, false},
+		{&RegistryStorage{Provider: StorageProviderOSS, OSS: &OSSStorage{
+			Region: "cn-hangzhou", Bucket: "harbor", AccessKeyID: "id", AccessKeySecret: "secret"}}, false},
+		{&RegistryStorage{Provider: StorageProviderOSS, OSS: &OSSStorage{
+			Region: "oss-cn-hangzhou", Endpoint: "registry.example.com", Internal: true,
+			Bucket: "harbor", AccessKeyID: "id", AccessKeySecret: "secret"}}, false},
+		{&RegistryStorage{Provider: StorageProviderOSS, OSS: &OSSStorage{
+			Region: "oss-cn-hangzhou", Bucket: "harbor", AccessKeyID: "id", AccessKeySecret: "secret"}}, true},
+		{&RegistryStorage{Provider: StorageProviderCOS, COS: &COSStorage{
+			Region: "ap-guangzhou", Bucket: "harbor", SecretID: "id", SecretKey: "key"}}, false},
+		{&RegistryStorage{Provider: StorageProviderCOS, COS: &COSStorage{
+			Region: "ap-guangzhou", Bucket: "harbor-1250000000", SecretID: "id", SecretKey: "key",
+			RootDirectory: "registry"}}, false},
+		{&RegistryStorage{Provider: StorageProviderCOS, COS: &COSStorage{
+			Region: "ap-guangzhou", Bucket: "harbor-1250000000", SecretID: "id", SecretKey: "key"}}, true},
+	}
+	for i, c := range cases {
+		v := &validation.Validation{}
+		c.storage.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "case %d", i)
+	}
+}
+
+func TestRegistryStorageDriver(t *testing.T) {
+	storage := &RegistryStorage{
+		Provider: StorageProviderCOS,
+		COS: &COSStorage{
+			Region:    "ap-guangzhou",
+			Bucket:    "harbor-1250000000",
+			SecretID:  "id",
+			SecretKey: "key",
+			Internal:  true,
+			Secure:    true,
+		},
+	}
+	driver, params := storage.Driver()
+	assert.Equal(t, "s3", driver)
+	assert.Equal(t, "https://cos-internal.ap-guangzhou.tencentcos.cn", params["regionendpoint"])
+	assert.Equal(t, "id", params["accesskey"])
+
+	storage.COS.Endpoint = "cos.example.com"
+	storage.COS.Secure = false
+	_, params = storage.Driver()
+	assert.Equal(t, "http://cos.example.com", params["regionendpoint"])
+
+	storage = &RegistryStorage{
+		Provider: StorageProviderOSS,
+		OSS: &OSSStorage{
+			Region:        "oss-cn-hangzhou",
+			Bucket:        "harbor",
+			RootDirectory: "/registry",
+		},
+	}
+	driver, params = storage.Driver()
+	assert.Equal(t, "oss", driver)
+	assert.Equal(t, "/registry", params["rootdirectory"])
+	_, exist := params["endpoint"]
+	assert.False(t, exist)
+}
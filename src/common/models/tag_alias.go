@@ -0,0 +1,52 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// TagAliasTable is the name of table in DB that holds the alias tags of repositories
+const TagAliasTable = "tag_alias"
+
+// TagAlias is a tag managed by the API which points to the digest of another tag of the
+// repository, e.g. "stable" pointing to "v1.4.2". It's updated only by the API, the
+// pushes to it are refused
+type TagAlias struct {
+	ID         int64  `orm:"pk;auto;column(id)" json:"id"`
+	Repository string `orm:"column(repository)" json:"repository"`
+	Alias      string `orm:"column(alias)" json:"alias"`
+	// the tag which the alias was pointed to, the alias keeps the digest even if
+	// the tag is pushed again later
+	Tag          string    `orm:"column(tag)" json:"tag"`
+	Digest       string    `orm:"column(digest)" json:"digest"`
+	Operator     string    `orm:"column(operator)" json:"operator"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (t *TagAlias) TableName() string {
+	return TagAliasTable
+}
+
+// Valid ...
+func (t *TagAlias) Valid(v *validation.Validation) {
+	if len(t.Tag) == 0 {
+		v.SetError("tag", "cannot be empty")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// TagHistoryTable is the name of table in DB that holds the history of the tags
+const TagHistoryTable = "tag_history"
+
+// the operations of the tag history
+const (
+	TagHistoryCreate = "create"
+	TagHistoryUpdate = "update"
+	TagHistoryDelete = "delete"
+)
+
+// TagHistory records a mutation of the tag, "update" means the tag is pushed again
+// and points to a new digest, the digest of "delete" is the one deleted
+type TagHistory struct {
+	ID         int64     `orm:"pk;auto;column(id)" json:"id"`
+	Repository string    `orm:"column(repository)" json:"repository"`
+	Tag        string    `orm:"column(tag)" json:"tag"`
+	Digest     string    `orm:"column(digest)" json:"digest"`
+	Operation  string    `orm:"column(operation)" json:"operation"`
+	Operator   string    `orm:"column(operator)" json:"operator"`
+	OpTime     time.Time `orm:"column(op_time)" json:"op_time"`
+}
+
+// TableName ...
+func (t *TagHistory) TableName() string {
+	return TagHistoryTable
+}
+
+// TagHistoryQuery ...
+type TagHistoryQuery struct {
+	Repository string
+	Tag        string
+	BeginTime  time.Time
+	EndTime    time.Time
+	Pagination
+}
+
+// MovedTag summarizes the updates of a tag which points to new digests in a period
+type MovedTag struct {
+	Repository string    `orm:"column(repository)" json:"repository"`
+	Tag        string    `orm:"column(tag)" json:"tag"`
+	Updates    int64     `orm:"column(updates)" json:"updates"`
+	Digest     string    `orm:"column(digest)" json:"digest"`
+	Operators  []string  `orm:"-" json:"operators"`
+	UpdateTime time.Time `orm:"column(update_time)" json:"update_time"`
+	// the operators joined by comma
+	OperatorStr string `orm:"column(operators)" json:"-"`
+}
+
+// MovedTagQuery ...
+type MovedTagQuery struct {
+	Project   string
+	BeginTime time.Time
+	EndTime   time.Time
+	Pagination
+}
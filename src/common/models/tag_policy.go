@@ -0,0 +1,106 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// the semantic version 2.0.0 with an optional prefix "v", e.g. "v1.2.3" or "1.2.3-rc.1+build.5"
+var semverRegexp = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(-(0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*)?` +
+	`(\+[0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*)?$`)
+
+// TagPolicy decides which tags can be pushed into the project, a tag is accepted if it
+// matches none of the denied patterns, is a semantic version if RequireSemver is true, and
+// matches one of the allowed patterns if there is any. The patterns are regular expressions
+// matching the whole tag.
+type TagPolicy struct {
+	Allow         []string `json:"allow"`
+	Deny          []string `json:"deny"`
+	RequireSemver bool     `json:"require_semver"`
+}
+
+// Valid ...
+func (t *TagPolicy) Valid(v *validation.Validation) {
+	for field, patterns := range map[string][]string{
+		"allow": t.Allow,
+		"deny":  t.Deny,
+	} {
+		for _, pattern := range patterns {
+			if _, err := compileTagPattern(pattern); err != nil {
+				v.SetError(field, "invalid pattern "+pattern)
+				break
+			}
+		}
+	}
+}
+
+// Check returns an error describing the violation if the tag isn't accepted by the policy
+func (t *TagPolicy) Check(tag string) error {
+	for _, pattern := range t.Deny {
+		if matchTagPattern(pattern, tag) {
+			return fmt.Errorf("the tag %s matches the denied pattern %s", tag, pattern)
+		}
+	}
+	if t.RequireSemver && !semverRegexp.MatchString(tag) {
+		return fmt.Errorf("the tag %s isn't a semantic version", tag)
+	}
+	if len(t.Allow) == 0 {
+		return nil
+	}
+	for _, pattern := range t.Allow {
+		if matchTagPattern(pattern, tag) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the tag %s matches none of the allowed patterns", tag)
+}
+
+func compileTagPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// the invalid patterns are rejected when the policy is set, so they match nothing
+func matchTagPattern(pattern, tag string) bool {
+	re, err := compileTagPattern(pattern)
+	return err == nil && re.MatchString(tag)
+}
+
+// ParseTagPolicy parses the tag policy in JSON and validates it
+func ParseTagPolicy(value string) (*TagPolicy, error) {
+	policy := &TagPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, err
+	}
+	v := &validation.Validation{}
+	policy.Valid(v)
+	if v.HasErrors() {
+		return nil, fmt.Errorf("%s %s", v.Errors[0].Field, v.Errors[0].Message)
+	}
+	return policy, nil
+}
+
+// TagCheckResult is the result of checking a tag against the tag policy of the project
+type TagCheckResult struct {
+	Tag     string `json:"tag"`
+	Allowed bool   `json:"allowed"`
+	// the violation of the policy if the tag isn't allowed
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagPolicy(t *testing.T) {
+	policy, err := ParseTagPolicy(`{"allow":["v.*"],"deny":[".*-dirty"],"require_semver":true}`)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"v.*"}, policy.Allow)
+	assert.Equal(t, []string{".*-dirty"}, policy.Deny)
+	assert.True(t, policy.RequireSemver)
+
+	_, err = ParseTagPolicy(`invalid`)
+	assert.NotNil(t, err)
+	_, err = ParseTagPolicy(`{"allow":["v[0-9"]}`)
+	assert.NotNil(t, err)
+	_, err = ParseTagPolicy(`{"deny":["(latest"]}`)
+	assert.NotNil(t, err)
+
+	p := &Project{}
+	assert.Nil(t, p.TagPolicy())
+	p.SetMetadata(ProMetaTagPolicy, `{"deny":["latest"]}`)
+	require.NotNil(t, p.TagPolicy())
+	assert.Equal(t, []string{"latest"}, p.TagPolicy().Deny)
+}
+
+func TestTagPolicyCheck(t *testing.T) {
+	cases := []struct {
+		policy *TagPolicy
+		tag    string
+		valid  bool
+	}{
+		{&TagPolicy{}, "latest", true},
+		{&TagPolicy{Deny: []string{"latest"}}, "latest", false},
+		// the patterns match the whole tag
+		{&TagPolicy{Deny: []string{"latest"}}, "latest-1", true},
+		{&TagPolicy{Allow: []string{"release-.*", "dev"}}, "release-1", true},
+		{&TagPolicy{Allow: []string{"release-.*", "dev"}}, "dev", true},
+		{&TagPolicy{Allow: []string{"release-.*", "dev"}}, "feature-1", false},
+		{&TagPolicy{Allow: []string{"release-.*"}, Deny: []string{".*-dirty"}}, "release-1-dirty", false},
+		{&TagPolicy{RequireSemver: true}, "1.2.3", true},
+		{&TagPolicy{RequireSemver: true}, "v1.2.3-rc.1+build.5", true},
+		{&TagPolicy{RequireSemver: true}, "v1.2", false},
+		{&TagPolicy{RequireSemver: true}, "01.2.3", false},
+		{&TagPolicy{RequireSemver: true}, "latest", false},
+		{&TagPolicy{RequireSemver: true, Allow: []string{"v.*"}}, "1.2.3", false},
+	}
+	for _, c := range cases {
+		err := c.policy.Check(c.tag)
+		assert.Equal(t, c.valid, err == nil, "policy: %+v, tag: %s", c.policy, c.tag)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// TagPullTimeTable is the name of table in DB that holds the last pull time of tags
+const TagPullTimeTable = "tag_pull_time"
+
+// TagPullTime holds the time when the tag was pulled last time
+type TagPullTime struct {
+	ID         int64     `orm:"pk;auto;column(id)" json:"id"`
+	Repository string    `orm:"column(repository)" json:"repository"`
+	Tag        string    `orm:"column(tag)" json:"tag"`
+	PullTime   time.Time `orm:"column(pull_time)" json:"pull_time"`
+}
+
+// TableName ...
+func (t *TagPullTime) TableName() string {
+	return TagPullTimeTable
+}
@@ -116,6 +116,134 @@ func TestValidOfTarget(t *testing.T) {
 				Name: "endpoint01",
 				URL:  "http://example.com/redirect",
 			}},
+
+		// invalid credential reference
+		{
+			RepTarget{
+				Name:          "endpoint01",
+				URL:           "http://example.com",
+				CredentialRef: "secret/data/harbor",
+			},
+			true,
+			RepTarget{},
+		},
+
+		// credential reference is set with password
+		{
+			RepTarget{
+				Name:          "endpoint01",
+				URL:           "http://example.com",
+				Password:      "Harbor12345",
+				CredentialRef: "vault:secret/data/harbor",
+			},
+			true,
+			RepTarget{},
+		},
+
+		// valid credential reference
+		{
+			RepTarget{
+				Name:          "endpoint01",
+				URL:           "http://example.com",
+				CredentialRef: "vault:secret/data/harbor",
+			},
+			false,
+			RepTarget{
+				Name:          "endpoint01",
+				URL:           "http://example.com",
+				CredentialRef: "vault:secret/data/harbor",
+			}},
+
+		// unsupported auth scheme
+		{
+			RepTarget{
+				Name:       "endpoint01",
+				URL:        "http://example.com",
+				AuthScheme: "digest",
+			},
+			true,
+			RepTarget{},
+		},
+
+		// token realm is set with the basic auth scheme
+		{
+			RepTarget{
+				Name:       "endpoint01",
+				URL:        "http://example.com",
+				AuthScheme: RepTargetAuthBasic,
+				TokenRealm: "https://auth.example.com/token",
+			},
+			true,
+			RepTarget{},
+		},
+
+		// path mappings of the Harbor target
+		{
+			RepTarget{
+				Name:         "endpoint01",
+				URL:          "http://example.com",
+				PathMappings: []*PathMapping{{Project: "library", Path: "docker-local"}},
+			},
+			true,
+			RepTarget{},
+		},
+
+		// unsupported type
+		{
+			RepTarget{
+				Name: "endpoint01",
+				URL:  "http://example.com",
+				Type: 6,
+			},
+			true,
+			RepTarget{},
+		},
+
+		// invalid max concurrent transfers
+		{
+			RepTarget{
+				Name:                   "endpoint01",
+				URL:                    "http://example.com",
+				MaxConcurrentTransfers: -1,
+			},
+			true,
+			RepTarget{},
+		},
+
+		// invalid backoff retries
+		{
+			RepTarget{
+				Name:           "endpoint01",
+				URL:            "http://example.com",
+				BackoffRetries: 11,
+			},
+			true,
+			RepTarget{},
+		},
+
+		// valid generic target
+		{
+			RepTarget{
+				Name:                   "endpoint01",
+				URL:                    "http://example.com",
+				Type:                   RepTargetTypeGeneric,
+				AuthScheme:             RepTargetAuthBearer,
+				TokenRealm:             "https://auth.example.com/token",
+				PathMappings:           []*PathMapping{{Project: "library", Path: "docker-local"}},
+				MaxConcurrentTransfers: 2,
+				BackoffRetries:         5,
+			},
+			false,
+			RepTarget{
+				Name:                   "endpoint01",
+				URL:                    "http://example.com",
+				Type:                   RepTargetTypeGeneric,
+				AuthScheme:             RepTargetAuthBearer,
+				TokenRealm:             "https://auth.example.com/token",
+				PathMappings:           []*PathMapping{{Project: "library", Path: "docker-local"}},
+				MaxConcurrentTransfers: 2,
+				BackoffRetries:         5,
+			}},
 	}
 
 	for _, c := range cases {
@@ -129,3 +257,61 @@ func TestValidOfTarget(t *testing.T) {
 		assert.Equal(t, c.expected, c.target)
 	}
 }
+
+func TestMarshalOfTarget(t *testing.T) {
+	target := &RepTarget{
+		PathMappings: []*PathMapping{{Project: "library", Path: "docker-local"}},
+	}
+	require.Nil(t, target.Marshal())
+	assert.Equal(t, `[{"project":"library","path":"docker-local"}]`, target.PathMappingStr)
+
+	target.PathMappings = nil
+	require.Nil(t, target.Unmarshal())
+	assert.Equal(t, []*PathMapping{{Project: "library", Path: "docker-local"}}, target.PathMappings)
+
+	target.PathMappings = nil
+	require.Nil(t, target.Marshal())
+	assert.Equal(t, "", target.PathMappingStr)
+}
+
+func TestRemoteRepository(t *testing.T) {
+	target := &RepTarget{
+		PathMappings: []*PathMapping{{Project: "library", Path: "docker-local"}},
+	}
+	assert.Equal(t, "library/nginx", target.RemoteRepository("library/nginx"))
+
+	target.Type = RepTargetTypeArtifactory
+	assert.Equal(t, "docker-local/nginx", target.RemoteRepository("library/nginx"))
+	assert.Equal(t, "base/app/web", target.RemoteRepository("base/app/web"))
+
+	target.Type = RepTargetTypeQuay
+	target.PathMappings = []*PathMapping{{Project: "library", Path: "team"}}
+	assert.Equal(t, "team/nginx", target.RemoteRepository("library/nginx"))
+	assert.Equal(t, "base/app_web", target.RemoteRepository("base/app/web"))
+
+	target.Username = "org+robot"
+	assert.Equal(t, "team/nginx", target.RemoteRepository("library/nginx"))
+	assert.Equal(t, "org/app_web", target.RemoteRepository("base/app/web"))
+
+	target.Type = RepTargetTypeGitHub
+	target.PathMappings = []*PathMapping{{Project: "library", Path: "My-Org/team"}}
+	assert.Equal(t, "my-org/team/nginx", target.RemoteRepository("library/nginx"))
+	assert.Equal(t, "base/app/web", target.RemoteRepository("base/app/web"))
+}
+
+func TestAPIURL(t *testing.T) {
+	target := &RepTarget{
+		URL:  "https://ghcr.io",
+		Type: RepTargetTypeGitHub,
+	}
+	assert.Equal(t, "https://api.github.com", target.APIURL())
+	target.URL = "https://containers.github.example.com"
+	assert.Equal(t, "https://github.example.com/api/v3", target.APIURL())
+
+	target.Type = RepTargetTypeGitLab
+	target.URL = "https://registry.gitlab.com"
+	assert.Equal(t, "https://gitlab.com/api/v4", target.APIURL())
+
+	target.Type = RepTargetTypeGeneric
+	assert.Equal(t, "", target.APIURL())
+}
@@ -0,0 +1,34 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// TaskLockTable is the name of table in DB that holds the locks of the resources held by the tasks
+const TaskLockTable = "task_lock"
+
+// TaskLock is the lock of a resource held by a task running in background
+type TaskLock struct {
+	Resource     string    `orm:"pk;column(resource)" json:"resource"`
+	Owner        string    `orm:"column(owner)" json:"owner"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (t *TaskLock) TableName() string {
+	return TaskLockTable
+}
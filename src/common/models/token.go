@@ -19,6 +19,8 @@ type Token struct {
 	Token     string `json:"token"`
 	ExpiresIn int    `json:"expires_in"`
 	IssuedAt  string `json:"issued_at"`
+	// AccessToken is returned instead of Token by some OAuth2 compatible token services
+	AccessToken string `json:"access_token,omitempty"`
 }
 
 // ResourceActions ...
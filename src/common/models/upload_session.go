@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// UploadSessionTable is the name of table in DB that holds the blob upload sessions
+const UploadSessionTable = "upload_session"
+
+// UploadSession holds the details of a blob upload session started by "docker push"
+// which is not completed yet. The state is the "_state" parameter signed by registry,
+// which is required to resume or cancel the upload.
+type UploadSession struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	UUID         string    `orm:"column(uuid)" json:"uuid"`
+	Repository   string    `orm:"column(repository)" json:"repository"`
+	State        string    `orm:"column(state)" json:"-"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (u *UploadSession) TableName() string {
+	return UploadSessionTable
+}
+
+// UploadSessionQuery ...
+type UploadSessionQuery struct {
+	Repository string
+	// only return the sessions which have no activity since the time
+	UpdatedBefore *time.Time
+	Pagination
+}
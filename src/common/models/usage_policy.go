@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// UsagePolicyTable is the name of table in DB that holds the usage policies of projects
+	UsagePolicyTable = "usage_policy"
+	// UsagePolicyAckTable is the name of table in DB that holds the acknowledgments of the usage policies
+	UsagePolicyAckTable = "usage_policy_ack"
+)
+
+// UsagePolicy is the text which the users must acknowledge before pulling the images of the
+// project, e.g. the terms of export control or licensing
+type UsagePolicy struct {
+	ProjectID    int64     `orm:"pk;column(project_id)" json:"project_id"`
+	Policy       string    `orm:"column(policy)" json:"policy"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (u *UsagePolicy) TableName() string {
+	return UsagePolicyTable
+}
+
+// UsagePolicyAck records that the user has acknowledged the current usage policy of the project
+type UsagePolicyAck struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	UserID       int       `orm:"column(user_id)" json:"user_id"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (u *UsagePolicyAck) TableName() string {
+	return UsagePolicyAckTable
+}
+
+// UsagePolicyStatus is the usage policy of the project together with whether the current
+// user has acknowledged it
+type UsagePolicyStatus struct {
+	Policy         string     `json:"policy"`
+	UpdateTime     time.Time  `json:"update_time"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// UsagePolicyReq is the request to set the usage policy of the project
+type UsagePolicyReq struct {
+	Policy string `json:"policy"`
+}
@@ -41,6 +41,8 @@ type User struct {
 	CreationTime time.Time    `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time    `orm:"column(update_time);auto_now" json:"update_time"`
 	GroupList    []*UserGroup `orm:"-" json:"-"`
+	// whether the user can place and release the legal holds
+	ComplianceOfficer bool `orm:"-" json:"compliance_officer"`
 }
 
 // UserQuery ...
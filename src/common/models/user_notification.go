@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// UserNotificationTable is the name of table in DB that holds the in-app notifications of users
+	UserNotificationTable = "user_notification"
+
+	// UserNotificationScanDone is sent to the subscribers of the repository when a scan of it is done
+	UserNotificationScanDone = "scan_done"
+	// UserNotificationReplicationFailed is sent to the system admins when a replication job fails
+	UserNotificationReplicationFailed = "replication_failed"
+	// UserNotificationQuotaWarning is sent to the project admins when the storage usage crosses a threshold
+	UserNotificationQuotaWarning = "quota_warning"
+	// UserNotificationMembershipGranted is sent to the user added as a member of a project
+	UserNotificationMembershipGranted = "membership_granted"
+)
+
+// UserNotification is an in-app notification of the user, the events of the repository subscriptions
+// are notified as well and their types are the events, e.g. "new_tag"
+type UserNotification struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	UserID       int       `orm:"column(user_id)" json:"-"`
+	Type         string    `orm:"column(type)" json:"type"`
+	Message      string    `orm:"column(message)" json:"message"`
+	Read         bool      `orm:"column(read)" json:"read"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (u *UserNotification) TableName() string {
+	return UserNotificationTable
+}
+
+// UserNotificationQuery ...
+type UserNotificationQuery struct {
+	UserID int
+	// only the unread notifications are returned if it's true
+	Unread bool
+	Pagination
+}
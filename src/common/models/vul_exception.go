@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// VulExceptionTable is the name of table in DB that holds the exceptions of the vulnerability gate
+	VulExceptionTable = "vul_exception"
+
+	// MaxVulExceptionJustificationLength is the max length of the justification of an exception
+	MaxVulExceptionJustificationLength = 1024
+)
+
+// VulException exempts the images of the repository from the policy "prevent vulnerable images
+// from running" of the project until it expires, the images with unknown severity are still
+// prevented
+type VulException struct {
+	ID            int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID     int64     `orm:"column(project_id)" json:"project_id"`
+	Repository    string    `orm:"column(repository)" json:"repository"`
+	Justification string    `orm:"column(justification)" json:"justification"`
+	ExpiresAt     time.Time `orm:"column(expires_at)" json:"expires_at"`
+	Creator       string    `orm:"column(creator)" json:"creator"`
+	CreationTime  time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime    time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (e *VulException) TableName() string {
+	return VulExceptionTable
+}
+
+// Valid ...
+func (e *VulException) Valid(v *validation.Validation) {
+	if len(strings.TrimSpace(e.Justification)) == 0 {
+		v.SetError("justification", "cannot be empty")
+	} else if len(e.Justification) > MaxVulExceptionJustificationLength {
+		v.SetError("justification", fmt.Sprintf("max length is %d", MaxVulExceptionJustificationLength))
+	}
+	if !e.ExpiresAt.After(time.Now()) {
+		v.SetError("expires_at", "must be in the future")
+	}
+}
+
+// Expired returns whether the exception doesn't apply anymore at the time
+func (e *VulException) Expired(t time.Time) bool {
+	return !e.ExpiresAt.After(t)
+}
+
+// VulExceptionQuery ...
+type VulExceptionQuery struct {
+	ProjectID int64
+	// only the exceptions which aren't expired
+	Active bool
+	Pagination
+}
@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVulExceptionValid(t *testing.T) {
+	cases := []struct {
+		exception *VulException
+		valid     bool
+	}{
+		{&VulException{Justification: "false positive", ExpiresAt: time.Now().Add(time.Hour)}, true},
+		{&VulException{Justification: " ", ExpiresAt: time.Now().Add(time.Hour)}, false},
+		{&VulException{Justification: "false positive"}, false},
+		{&VulException{Justification: "false positive", ExpiresAt: time.Now().Add(-time.Hour)}, false},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.exception.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors())
+	}
+}
+
+func TestVulExceptionExpired(t *testing.T) {
+	now := time.Now()
+	exception := &VulException{ExpiresAt: now}
+	assert.True(t, exception.Expired(now))
+	assert.False(t, exception.Expired(now.Add(-time.Second)))
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// VulnDBImportTable is the name of table in DB that holds the imports of vulnerability database bundles
+const VulnDBImportTable = "vulnerability_db_import"
+
+// the status of the vulnerability database import
+const (
+	VulnDBImportRunning   = "running"
+	VulnDBImportSucceeded = "succeeded"
+	VulnDBImportFailed    = "failed"
+)
+
+// VulnDBImport records an import of the vulnerability database bundle, which is used
+// to update the vulnerability data of the scanner in the offline deployments
+type VulnDBImport struct {
+	ID                 int64     `orm:"pk;auto;column(id)" json:"id"`
+	Version            string    `orm:"column(version)" json:"version"`
+	Status             string    `orm:"column(status)" json:"status"`
+	Message            string    `orm:"column(message)" json:"message,omitempty"`
+	VulnerabilityCount int64     `orm:"column(vulnerability_count)" json:"vulnerability_count"`
+	BundleCreationTime time.Time `orm:"column(bundle_creation_time)" json:"bundle_creation_time"`
+	Creator            string    `orm:"column(creator)" json:"creator"`
+	CreationTime       time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime         time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (v *VulnDBImport) TableName() string {
+	return VulnDBImportTable
+}
+
+// VulnDBImportQuery ...
+type VulnDBImportQuery struct {
+	Status string
+	Pagination
+}
+
+// VulnDBMetadata is the content of the metadata file in the vulnerability database bundle
+type VulnDBMetadata struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// the digests of the data files in the bundle, e.g. "vulnerabilities.json": "sha256:ab..."
+	Files map[string]string `json:"files"`
+}
+
+// VulnDBVulnerability is a vulnerability in the vulnerability database bundle
+type VulnDBVulnerability struct {
+	Namespace     string                 `json:"namespace"`
+	VersionFormat string                 `json:"version_format"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Link          string                 `json:"link"`
+	Severity      string                 `json:"severity"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	FixedIn       []*VulnDBFeature       `json:"fixed_in"`
+}
+
+// VulnDBFeature is the feature in which the vulnerability is fixed
+type VulnDBFeature struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
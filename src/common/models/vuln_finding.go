@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// VulnFindingTable is the name of table in DB that holds the vulnerabilities found by the latest scan of images
+const VulnFindingTable = "vulnerability_finding"
+
+// VulnFinding is a vulnerability found in a package of the image by the latest scan
+type VulnFinding struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"-"`
+	Digest       string    `orm:"column(digest)" json:"digest"`
+	CVEID        string    `orm:"column(cve_id)" json:"cve_id"`
+	Package      string    `orm:"column(package)" json:"package"`
+	Version      string    `orm:"column(version)" json:"version"`
+	FixedVersion string    `orm:"column(fixed_version)" json:"fixed_version"`
+	Severity     Severity  `orm:"column(severity)" json:"severity"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (v *VulnFinding) TableName() string {
+	return VulnFindingTable
+}
+
+// AffectedArtifact is an image whose latest scan contains the vulnerability
+type AffectedArtifact struct {
+	Project      string   `json:"project"`
+	Repository   string   `json:"repository"`
+	Tag          string   `json:"tag"`
+	Digest       string   `json:"digest"`
+	Package      string   `json:"package"`
+	Version      string   `json:"version"`
+	FixedVersion string   `json:"fixed_version"`
+	Severity     Severity `json:"severity"`
+}
+
+// SeverityCount is the count of the vulnerabilities of the severity
+type SeverityCount struct {
+	Severity Severity `orm:"column(severity)" json:"severity"`
+	Count    int64    `orm:"column(count)" json:"count"`
+}
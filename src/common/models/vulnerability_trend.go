@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// VulnerabilityTrend is the snapshot of the vulnerabilities of a project on one day, the
+// counts by severity are the ones of the vulnerable components summed over the distinct
+// images of the project
+type VulnerabilityTrend struct {
+	ProjectID int64     `orm:"column(project_id)" json:"project_id"`
+	Day       time.Time `orm:"column(day)" json:"day"`
+	// the count of the distinct images and the scanned ones
+	Images     int `orm:"column(images)" json:"images"`
+	Scanned    int `orm:"column(scanned)" json:"scanned"`
+	Negligible int `orm:"column(negligible)" json:"negligible"`
+	Unknown    int `orm:"column(unknown)" json:"unknown"`
+	Low        int `orm:"column(low)" json:"low"`
+	Medium     int `orm:"column(medium)" json:"medium"`
+	High       int `orm:"column(high)" json:"high"`
+}
+
+// Add adds the components overview of a scanned image into the snapshot
+func (v *VulnerabilityTrend) Add(overview *ComponentsOverview) {
+	v.Scanned++
+	if overview == nil {
+		return
+	}
+	for _, entry := range overview.Summary {
+		switch Severity(entry.Sev) {
+		case SevNone:
+			v.Negligible += entry.Count
+		case SevLow:
+			v.Low += entry.Count
+		case SevMedium:
+			v.Medium += entry.Count
+		case SevHigh:
+			v.High += entry.Count
+		default:
+			v.Unknown += entry.Count
+		}
+	}
+}
+
+// VulnerabilityTrendQuery holds the conditions of the query of the snapshots
+type VulnerabilityTrendQuery struct {
+	ProjectID int64
+	// the days of the snapshots are within [BeginTime, EndTime] if they are set
+	BeginTime *time.Time
+	EndTime   *time.Time
+}
@@ -0,0 +1,51 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVulnerabilityTrendAdd(t *testing.T) {
+	trend := &VulnerabilityTrend{
+		Images: 3,
+	}
+	trend.Add(&ComponentsOverview{
+		Total: 10,
+		Summary: []*ComponentsOverviewEntry{
+			{Sev: int(SevNone), Count: 5},
+			{Sev: int(SevMedium), Count: 3},
+			{Sev: int(SevHigh), Count: 2},
+		},
+	})
+	trend.Add(&ComponentsOverview{
+		Total: 4,
+		Summary: []*ComponentsOverviewEntry{
+			{Sev: int(SevUnknown), Count: 1},
+			{Sev: int(SevHigh), Count: 3},
+		},
+	})
+	trend.Add(nil)
+	assert.Equal(t, &VulnerabilityTrend{
+		Images:     3,
+		Scanned:    3,
+		Negligible: 5,
+		Unknown:    1,
+		Medium:     3,
+		High:       5,
+	}, trend)
+}
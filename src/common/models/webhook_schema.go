@@ -0,0 +1,28 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// the versions of the payload schema of the webhooks, the webhooks stay on v1 until their
+// consumers opt in v2, see the conversion in package core/notifier
+const (
+	WebhookSchemaV1 = "v1" // the legacy payload, the fields of the event are at the top level
+	WebhookSchemaV2 = "v2" // the structured payload, the fields are wrapped in an envelope
+)
+
+// ValidWebhookSchema returns whether the version of the payload schema is supported,
+// the empty one means v1
+func ValidWebhookSchema(version string) bool {
+	return len(version) == 0 || version == WebhookSchemaV1 || version == WebhookSchemaV2
+}
@@ -0,0 +1,45 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"sync"
+)
+
+// Authorizer makes the final decision for the requests which have been
+// allowed by the RBAC policies, e.g. delegates the decision to an external policy agent
+type Authorizer interface {
+	// Authorize returns whether the user can do action on resource
+	Authorize(user User, resource Resource, action Action) bool
+}
+
+var (
+	authorizer     Authorizer
+	authorizerLock sync.RWMutex
+)
+
+// SetAuthorizer sets the authorizer which is consulted after the RBAC check,
+// pass nil to remove it
+func SetAuthorizer(a Authorizer) {
+	authorizerLock.Lock()
+	defer authorizerLock.Unlock()
+	authorizer = a
+}
+
+func getAuthorizer() Authorizer {
+	authorizerLock.RLock()
+	defer authorizerLock.RUnlock()
+	return authorizer
+}
@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"testing"
+)
+
+type fakeAuthorizer struct {
+	allowed bool
+	called  int
+}
+
+func (f *fakeAuthorizer) Authorize(user User, resource Resource, action Action) bool {
+	f.called++
+	return f.allowed
+}
+
+func TestHasPermissionWithAuthorizer(t *testing.T) {
+	defer SetAuthorizer(nil)
+
+	user := &userWithRoles{Username: "project admin", RoleName: "projectAdmin"}
+
+	deny := &fakeAuthorizer{allowed: false}
+	SetAuthorizer(deny)
+	if HasPermission(user, "/project", "create") {
+		t.Errorf("HasPermission() = true, want false when the authorizer denies")
+	}
+	if deny.called != 1 {
+		t.Errorf("authorizer called %d times, want 1", deny.called)
+	}
+
+	// the authorizer must not be consulted when RBAC already denies
+	if HasPermission(user, "/project", "delete") {
+		t.Errorf("HasPermission() = true, want false")
+	}
+	if deny.called != 1 {
+		t.Errorf("authorizer called %d times, want 1", deny.called)
+	}
+
+	allow := &fakeAuthorizer{allowed: true}
+	SetAuthorizer(allow)
+	if !HasPermission(user, "/project", "create") {
+		t.Errorf("HasPermission() = false, want true")
+	}
+
+	SetAuthorizer(nil)
+	if !HasPermission(user, "/project", "create") {
+		t.Errorf("HasPermission() = false, want true")
+	}
+}
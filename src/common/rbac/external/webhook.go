@@ -0,0 +1,220 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/cache"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	// interval in seconds to clean up the expired decisions
+	cacheGCInterval = 60
+)
+
+// SettingsGetter returns the latest settings of the external authorization,
+// it's called for every decision so that the settings can be changed at runtime
+type SettingsGetter func() (*models.ExternalAuthzSettings, error)
+
+// Actor is the subject of the request
+type Actor struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Namespace is the namespace the resource belongs to
+type Namespace struct {
+	Kind     string      `json:"kind"`
+	Identity interface{} `json:"identity"`
+}
+
+// Input is the document sent to the external endpoint, it's wrapped
+// under the "input" key so that it can be consumed by OPA directly
+type Input struct {
+	Actor     *Actor     `json:"actor"`
+	Resource  string     `json:"resource"`
+	Namespace *Namespace `json:"namespace,omitempty"`
+	Action    string     `json:"action"`
+}
+
+type request struct {
+	Input *Input `json:"input"`
+}
+
+// the result can either be a bool or an object contains "allow"
+type response struct {
+	Result json.RawMessage `json:"result"`
+}
+
+// WebhookAuthorizer implements rbac.Authorizer by posting the input
+// document to an external endpoint
+type WebhookAuthorizer struct {
+	settings SettingsGetter
+	client   *http.Client
+	cache    cache.Cache
+	// the settings read successfully last time
+	lock sync.RWMutex
+	last *models.ExternalAuthzSettings
+}
+
+// NewWebhookAuthorizer returns an instance of WebhookAuthorizer
+func NewWebhookAuthorizer(settings SettingsGetter) *WebhookAuthorizer {
+	c := cache.NewMemoryCache()
+	if err := c.StartAndGC(fmt.Sprintf(`{"interval":%d}`, cacheGCInterval)); err != nil {
+		log.Errorf("failed to start the GC of external authorization cache: %v", err)
+	}
+	return &WebhookAuthorizer{
+		settings: settings,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+			Timeout: defaultTimeout,
+		},
+		cache: c,
+	}
+}
+
+// Authorize returns whether the external endpoint allows the user to do action on resource.
+// If the settings can't be read, the decision falls back to the settings read last time as
+// if the endpoint is unreachable, i.e. the request is allowed only if the external authorization
+// was disabled or fails open, and it's denied if the settings have never been read
+func (w *WebhookAuthorizer) Authorize(user rbac.User, resource rbac.Resource, action rbac.Action) bool {
+	settings, err := w.settings()
+	if err != nil {
+		w.lock.RLock()
+		last := w.last
+		w.lock.RUnlock()
+		allowed := last != nil && (len(last.Endpoint) == 0 || last.FailOpen)
+		log.Errorf("failed to get the settings of external authorization, allowed: %t, error: %v", allowed, err)
+		return allowed
+	}
+	w.lock.Lock()
+	w.last = settings
+	w.lock.Unlock()
+	if len(settings.Endpoint) == 0 {
+		return true
+	}
+
+	// the decisions of the previous endpoint are never reused once the endpoint is changed
+	key := strings.Join([]string{settings.Endpoint, user.GetUserName(), resource.String(), action.String()}, "|")
+	if settings.CacheTTL > 0 {
+		if v, ok := w.cache.Get(key).(bool); ok {
+			return v
+		}
+	}
+
+	allowed, err := w.query(settings.Endpoint, newInput(user, resource, action))
+	if err != nil {
+		log.Errorf("failed to query the external authorization endpoint %s, fail open: %t, error: %v",
+			settings.Endpoint, settings.FailOpen, err)
+		return settings.FailOpen
+	}
+
+	if settings.CacheTTL > 0 {
+		if err = w.cache.Put(key, allowed, time.Duration(settings.CacheTTL)*time.Second); err != nil {
+			log.Errorf("failed to cache the external authorization decision: %v", err)
+		}
+	}
+	return allowed
+}
+
+func (w *WebhookAuthorizer) query(endpoint string, input *Input) (bool, error) {
+	data, err := json.Marshal(&request{Input: input})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return parseResult(body)
+}
+
+func parseResult(body []byte) (bool, error) {
+	resp := &response{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return false, err
+	}
+	// OPA returns an empty document when the rule is undefined
+	if len(resp.Result) == 0 {
+		return false, nil
+	}
+
+	var allowed bool
+	if err := json.Unmarshal(resp.Result, &allowed); err == nil {
+		return allowed, nil
+	}
+
+	result := &struct {
+		Allow bool `json:"allow"`
+	}{}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return false, fmt.Errorf("invalid result %s: %v", string(resp.Result), err)
+	}
+	return result.Allow, nil
+}
+
+func newInput(user rbac.User, resource rbac.Resource, action rbac.Action) *Input {
+	actor := &Actor{
+		Name: user.GetUserName(),
+	}
+	for _, role := range user.GetRoles() {
+		if name := role.GetRoleName(); len(name) > 0 {
+			actor.Roles = append(actor.Roles, name)
+		}
+	}
+
+	input := &Input{
+		Actor:    actor,
+		Resource: resource.String(),
+		Action:   action.String(),
+	}
+	if ns, err := resource.GetNamespace(); err == nil {
+		input.Namespace = &Namespace{
+			Kind:     ns.Kind(),
+			Identity: ns.Identity(),
+		}
+	}
+	return input
+}
@@ -0,0 +1,178 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	rbac.BaseUser
+}
+
+func (u *user) GetUserName() string {
+	return "tester"
+}
+
+func newServer(t *testing.T, result string, count *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*count++
+		req := &request{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(req))
+		assert.Equal(t, "tester", req.Input.Actor.Name)
+		assert.Equal(t, "pull", req.Input.Action)
+		assert.Equal(t, "/project/1/repository", req.Input.Resource)
+		require.NotNil(t, req.Input.Namespace)
+		assert.Equal(t, "project", req.Input.Namespace.Kind)
+		w.Write([]byte(result))
+	}))
+}
+
+func settingsOf(s *models.ExternalAuthzSettings) SettingsGetter {
+	return func() (*models.ExternalAuthzSettings, error) {
+		return s, nil
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	count := 0
+	server := newServer(t, `{"result": true}`, &count)
+	defer server.Close()
+
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceRepository)
+
+	// disabled
+	a := NewWebhookAuthorizer(settingsOf(&models.ExternalAuthzSettings{}))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	assert.Equal(t, 0, count)
+
+	// without cache
+	a = NewWebhookAuthorizer(settingsOf(&models.ExternalAuthzSettings{Endpoint: server.URL}))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	assert.Equal(t, 2, count)
+
+	// with cache
+	count = 0
+	a = NewWebhookAuthorizer(settingsOf(&models.ExternalAuthzSettings{Endpoint: server.URL, CacheTTL: 60}))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	assert.Equal(t, 1, count)
+}
+
+func TestAuthorizeDeny(t *testing.T) {
+	count := 0
+	server := newServer(t, `{"result": {"allow": false}}`, &count)
+	defer server.Close()
+
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceRepository)
+	a := NewWebhookAuthorizer(settingsOf(&models.ExternalAuthzSettings{Endpoint: server.URL}))
+	assert.False(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+}
+
+func TestAuthorizeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceRepository)
+
+	a := NewWebhookAuthorizer(settingsOf(&models.ExternalAuthzSettings{Endpoint: server.URL}))
+	assert.False(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+
+	a = NewWebhookAuthorizer(settingsOf(&models.ExternalAuthzSettings{Endpoint: server.URL, FailOpen: true}))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+}
+
+func TestAuthorizeSettingsFailure(t *testing.T) {
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceRepository)
+	var settings *models.ExternalAuthzSettings
+	a := NewWebhookAuthorizer(func() (*models.ExternalAuthzSettings, error) {
+		if settings == nil {
+			return nil, errors.New("error")
+		}
+		return settings, nil
+	})
+
+	// never read
+	assert.False(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+
+	// disabled last time
+	settings = &models.ExternalAuthzSettings{}
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	settings = nil
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+
+	// fail open last time
+	settings = &models.ExternalAuthzSettings{Endpoint: "http://127.0.0.1:1", FailOpen: true}
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	settings = nil
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+
+	// fail closed last time
+	settings = &models.ExternalAuthzSettings{Endpoint: "http://127.0.0.1:1"}
+	assert.False(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	settings = nil
+	assert.False(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+}
+
+func TestAuthorizeEndpointChanged(t *testing.T) {
+	allowCount, denyCount := 0, 0
+	allow := newServer(t, `{"result": true}`, &allowCount)
+	defer allow.Close()
+	deny := newServer(t, `{"result": false}`, &denyCount)
+	defer deny.Close()
+
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceRepository)
+	settings := &models.ExternalAuthzSettings{Endpoint: allow.URL, CacheTTL: 60}
+	a := NewWebhookAuthorizer(settingsOf(settings))
+	assert.True(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+
+	// the decision cached for the previous endpoint isn't used
+	settings.Endpoint = deny.URL
+	assert.False(t, a.Authorize(&user{}, resource, rbac.ActionPull))
+	assert.Equal(t, 1, allowCount)
+	assert.Equal(t, 1, denyCount)
+}
+
+func TestParseResult(t *testing.T) {
+	cases := []struct {
+		body    string
+		allowed bool
+		hasErr  bool
+	}{
+		{`{"result": true}`, true, false},
+		{`{"result": false}`, false, false},
+		{`{"result": {"allow": true}}`, true, false},
+		{`{}`, false, false},
+		{`{"result": "yes"}`, false, true},
+		{`invalid`, false, true},
+	}
+	for _, c := range cases {
+		allowed, err := parseResult([]byte(c.body))
+		assert.Equal(t, c.allowed, allowed, c.body)
+		assert.Equal(t, c.hasErr, err != nil, c.body)
+	}
+}
@@ -16,8 +16,10 @@ package rbac
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var (
@@ -48,3 +50,19 @@ func projectNamespaceParser(resource Resource) (Namespace, error) {
 
 	return &projectNamespace{projectIDOrName: projectIDOrName}, nil
 }
+
+// ParseScope converts the scope in the short form "<kind>:<identity>", e.g. "project:1", to the
+// resource of the namespace "/project/1", the scopes in other forms are returned as they are
+func ParseScope(scope string) Resource {
+	if strings.HasPrefix(scope, "/") {
+		return Resource(scope)
+	}
+	parts := strings.SplitN(scope, ":", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return Resource(scope)
+	}
+	if _, ok := namespaceParsers[parts[0]]; !ok {
+		return Resource(scope)
+	}
+	return Resource(fmt.Sprintf("/%s/%s", parts[0], parts[1]))
+}
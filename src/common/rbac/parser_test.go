@@ -34,6 +34,15 @@ func (suite *ProjectParserTestSuite) TestParse() {
 	suite.Error(err)
 }
 
+func (suite *ProjectParserTestSuite) TestParseScope() {
+	suite.Equal(Resource("/project/1"), ParseScope("project:1"))
+	suite.Equal(Resource("/project/library"), ParseScope("project:library"))
+	suite.Equal(Resource("/project/1"), ParseScope("/project/1"))
+	suite.Equal(Resource("project:"), ParseScope("project:"))
+	suite.Equal(Resource("fake:1"), ParseScope("fake:1"))
+	suite.Equal(Resource(""), ParseScope(""))
+}
+
 func TestProjectParserTestSuite(t *testing.T) {
 	suite.Run(t, new(ProjectParserTestSuite))
 }
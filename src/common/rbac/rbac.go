@@ -145,5 +145,13 @@ func (u *BaseUser) GetPolicies() []*Policy {
 
 // HasPermission returns whether the user has action permission on resource
 func HasPermission(user User, resource Resource, action Action) bool {
-	return enforcerForUser(user).Enforce(user.GetUserName(), resource.String(), action.String())
+	if !enforcerForUser(user).Enforce(user.GetUserName(), resource.String(), action.String()) {
+		return false
+	}
+
+	if a := getAuthorizer(); a != nil {
+		return a.Authorize(user, resource, action)
+	}
+
+	return true
 }
@@ -0,0 +1,121 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretstore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// kubernetesProvider reads the secrets of Kubernetes in format "<namespace>/<name>"
+// with the service account of the pod which Harbor runs in
+type kubernetesProvider struct {
+	lock   sync.Mutex
+	host   string
+	token  string
+	client *http.Client
+}
+
+func (k *kubernetesProvider) init() error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if len(k.host) > 0 {
+		return nil
+	}
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(host) == 0 || len(port) == 0 {
+		return errors.New("Harbor isn't running in Kubernetes")
+	}
+	token, err := ioutil.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return err
+	}
+	ca, err := ioutil.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return errors.New("failed to load the CA of Kubernetes")
+	}
+	k.client = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs: pool,
+			},
+		},
+	}
+	k.token = strings.TrimSpace(string(token))
+	k.host = "https://" + net.JoinHostPort(host, port)
+	return nil
+}
+
+func (k *kubernetesProvider) Get(path string) (map[string]string, error) {
+	strs := strings.Split(path, "/")
+	if len(strs) != 2 || len(strs[0]) == 0 || len(strs[1]) == 0 {
+		return nil, fmt.Errorf("invalid path of Kubernetes secret %s, it should be in format <namespace>/<name>", path)
+	}
+	if err := k.init(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s", k.host, strs[0], strs[1]), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+k.token)
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from Kubernetes: %d, %s", resp.StatusCode, string(data))
+	}
+
+	secret := &struct {
+		Data map[string]string `json:"data"`
+	}{}
+	if err = json.Unmarshal(data, secret); err != nil {
+		return nil, err
+	}
+	// the values of the secret are encoded by base64
+	result := map[string]string{}
+	for key, value := range secret.Data {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the value of %s: %v", key, err)
+		}
+		result[key] = string(decoded)
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/namespaces/harbor/secrets/registry" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// "admin" and "Harbor12345" encoded by base64
+		w.Write([]byte(`{"kind":"Secret","data":{"username":"YWRtaW4=","password":"SGFyYm9yMTIzNDU="}}`))
+	}))
+	defer server.Close()
+
+	p := &kubernetesProvider{
+		host:   server.URL,
+		token:  "token",
+		client: http.DefaultClient,
+	}
+	secret, err := p.Get("harbor/registry")
+	require.Nil(t, err)
+	assert.Equal(t, "admin", secret[KeyUsername])
+	assert.Equal(t, "Harbor12345", secret[KeyPassword])
+
+	// invalid path
+	_, err = p.Get("registry")
+	assert.NotNil(t, err)
+
+	// not found
+	_, err = p.Get("harbor/unknown")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,149 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secretstore resolves the credentials referenced from the external secret
+// stores, so that they needn't be stored in the database of Harbor. A reference is
+// in format "<provider>:<path>", e.g. "vault:secret/data/harbor/registry-a" or
+// "k8s:harbor/registry-a", and the secret holds the keys "username" and "password".
+package secretstore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// KeyUsername is the key of the username in the secret
+	KeyUsername = "username"
+	// KeyPassword is the key of the password in the secret
+	KeyPassword = "password"
+
+	// the credentials are cached to avoid reading the secret store for every execution
+	cacheTTL = 5 * time.Minute
+)
+
+// Credential is the credential read from the secret store
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Provider reads the secrets from a kind of secret store
+type Provider interface {
+	// Get returns the key-value pairs of the secret on the path
+	Get(path string) (map[string]string, error)
+}
+
+var (
+	providers = map[string]Provider{
+		"vault": &vaultProvider{},
+		"k8s":   &kubernetesProvider{},
+	}
+	cache = &credentialCache{
+		entries: map[string]*cacheEntry{},
+	}
+)
+
+// ParseRef parses the reference into the name of provider and the path of secret
+func ParseRef(ref string) (string, string, error) {
+	strs := strings.SplitN(ref, ":", 2)
+	if len(strs) != 2 || len(strs[1]) == 0 {
+		return "", "", fmt.Errorf("invalid credential reference %s, it should be in format <provider>:<path>", ref)
+	}
+	if _, exist := providers[strs[0]]; !exist {
+		return "", "", fmt.Errorf("unsupported secret store %s", strs[0])
+	}
+	return strs[0], strs[1], nil
+}
+
+// Get returns the key-value pairs of the secret referenced by ref, it isn't cached
+func Get(ref string) (map[string]string, error) {
+	name, path, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := providers[name].Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the secret %s: %v", ref, err)
+	}
+	return secret, nil
+}
+
+// Resolve returns the credential referenced by ref, the credential is cached for
+// a while after it's read from the secret store
+func Resolve(ref string) (*Credential, error) {
+	if cred := cache.get(ref); cred != nil {
+		return cred, nil
+	}
+	secret, err := Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	password, exist := secret[KeyPassword]
+	if !exist {
+		return nil, fmt.Errorf("the key %s not found in the secret %s", KeyPassword, ref)
+	}
+	cred := &Credential{
+		Username: secret[KeyUsername],
+		Password: password,
+	}
+	cache.put(ref, cred)
+	return cred, nil
+}
+
+// Invalidate removes the cached credential, it should be called when the credential is rotated
+func Invalidate(ref string) {
+	cache.delete(ref)
+}
+
+type cacheEntry struct {
+	credential *Credential
+	expiresAt  time.Time
+}
+
+type credentialCache struct {
+	sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func (c *credentialCache) get(ref string) *Credential {
+	c.Lock()
+	defer c.Unlock()
+	entry, exist := c.entries[ref]
+	if !exist {
+		return nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, ref)
+		return nil
+	}
+	return entry.credential
+}
+
+func (c *credentialCache) put(ref string, cred *Credential) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries[ref] = &cacheEntry{
+		credential: cred,
+		expiresAt:  time.Now().Add(cacheTTL),
+	}
+}
+
+func (c *credentialCache) delete(ref string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, ref)
+}
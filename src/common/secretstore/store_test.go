@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	secrets map[string]map[string]string
+	reads   int
+}
+
+func (f *fakeProvider) Get(path string) (map[string]string, error) {
+	f.reads++
+	return f.secrets[path], nil
+}
+
+func TestParseRef(t *testing.T) {
+	cases := []struct {
+		ref      string
+		provider string
+		path     string
+		hasError bool
+	}{
+		{ref: "", hasError: true},
+		{ref: "vault", hasError: true},
+		{ref: "vault:", hasError: true},
+		{ref: "unknown:secret/harbor", hasError: true},
+		{ref: "vault:secret/data/harbor", provider: "vault", path: "secret/data/harbor"},
+		{ref: "k8s:harbor/registry", provider: "k8s", path: "harbor/registry"},
+	}
+	for _, c := range cases {
+		provider, path, err := ParseRef(c.ref)
+		if c.hasError {
+			assert.NotNil(t, err, c.ref)
+			continue
+		}
+		require.Nil(t, err, c.ref)
+		assert.Equal(t, c.provider, provider)
+		assert.Equal(t, c.path, path)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	fake := &fakeProvider{
+		secrets: map[string]map[string]string{
+			"registry": {
+				KeyUsername: "admin",
+				KeyPassword: "Harbor12345",
+			},
+			"no-password": {
+				KeyUsername: "admin",
+			},
+		},
+	}
+	providers["fake"] = fake
+	defer delete(providers, "fake")
+
+	cred, err := Resolve("fake:registry")
+	require.Nil(t, err)
+	assert.Equal(t, "admin", cred.Username)
+	assert.Equal(t, "Harbor12345", cred.Password)
+	defer Invalidate("fake:registry")
+
+	// read from the cache
+	_, err = Resolve("fake:registry")
+	require.Nil(t, err)
+	assert.Equal(t, 1, fake.reads)
+
+	// read from the store again after the cache is invalidated
+	Invalidate("fake:registry")
+	_, err = Resolve("fake:registry")
+	require.Nil(t, err)
+	assert.Equal(t, 2, fake.reads)
+
+	_, err = Resolve("fake:no-password")
+	assert.NotNil(t, err)
+}
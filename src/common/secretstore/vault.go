@@ -0,0 +1,90 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// vaultProvider reads the secrets from the KV secrets engine of Vault, both the version 1
+// and 2 are supported. The address and token are read from the environment variables
+// VAULT_ADDR and VAULT_TOKEN which are also used by the Vault CLI
+type vaultProvider struct {
+	addr   string
+	token  string
+	client *http.Client
+}
+
+func (v *vaultProvider) Get(path string) (map[string]string, error) {
+	addr, token := v.addr, v.token
+	if len(addr) == 0 {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if len(token) == 0 {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if len(addr) == 0 || len(token) == 0 {
+		return nil, errors.New("the address or token of Vault isn't configured")
+	}
+	client := v.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from Vault: %d, %s", resp.StatusCode, string(data))
+	}
+
+	secret := &struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err = json.Unmarshal(data, secret); err != nil {
+		return nil, err
+	}
+	values := secret.Data
+	// the data of the KV version 2 is nested with the metadata
+	if nested, ok := values["data"].(map[string]interface{}); ok {
+		if _, ok = values["metadata"]; ok {
+			values = nested
+		}
+	}
+	result := map[string]string{}
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result, nil
+}
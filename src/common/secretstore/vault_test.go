@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/registry":
+			w.Write([]byte(`{"data":{"username":"admin","password":"v1"}}`))
+		case "/v1/secret/data/registry":
+			w.Write([]byte(`{"data":{"data":{"username":"admin","password":"v2"},"metadata":{"version":1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := &vaultProvider{
+		addr:  server.URL,
+		token: "token",
+	}
+	// KV version 1
+	secret, err := p.Get("kv/registry")
+	require.Nil(t, err)
+	assert.Equal(t, "admin", secret[KeyUsername])
+	assert.Equal(t, "v1", secret[KeyPassword])
+
+	// KV version 2
+	secret, err = p.Get("secret/data/registry")
+	require.Nil(t, err)
+	assert.Equal(t, "admin", secret[KeyUsername])
+	assert.Equal(t, "v2", secret[KeyPassword])
+
+	// not found
+	_, err = p.Get("secret/data/unknown")
+	assert.NotNil(t, err)
+
+	// invalid token
+	p.token = "invalid"
+	_, err = p.Get("kv/registry")
+	assert.NotNil(t, err)
+}
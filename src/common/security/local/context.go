@@ -53,6 +53,15 @@ func (s *SecurityContext) GetUsername() string {
 	return s.user.Username
 }
 
+// GetGroupList returns the groups that the authenticated user belongs to
+// It returns nil if the user has not been authenticated
+func (s *SecurityContext) GetGroupList() []*models.UserGroup {
+	if !s.IsAuthenticated() {
+		return nil
+	}
+	return s.user.GroupList
+}
+
 // IsSysAdmin returns whether the authenticated user is system admin
 // It returns false if the user has not been authenticated
 func (s *SecurityContext) IsSysAdmin() bool {
@@ -1,6 +1,8 @@
 package robot
 
 import (
+	"strings"
+
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/common/rbac/project"
 )
@@ -40,3 +42,74 @@ func NewRobot(username string, namespace rbac.Namespace, policy []*rbac.Policy)
 		policy:    policy,
 	}
 }
+
+// MovePolicies returns the policies with the resources of the project "from" moved to the
+// project "to", it's used for the robots which are moved to another project by merging
+func MovePolicies(policies []*rbac.Policy, from, to int64) []*rbac.Policy {
+	fromNamespace := rbac.NewProjectNamespace(from, false).Resource().String()
+	toNamespace := rbac.NewProjectNamespace(to, false).Resource().String()
+	moved := []*rbac.Policy{}
+	for _, policy := range policies {
+		p := *policy
+		if res := p.Resource.String(); res == fromNamespace || strings.HasPrefix(res, fromNamespace+"/") {
+			p.Resource = rbac.Resource(toNamespace + strings.TrimPrefix(res, fromNamespace))
+		}
+		moved = append(moved, &p)
+	}
+	return moved
+}
+
+// RestrictPolicies returns the allowed policies of the token which are still granted to the
+// robot, it's used to apply the downgrades of the granted access to the tokens issued before.
+// The resources are compared relative to their projects as the granted access isn't moved
+// with the robot, and the denied policies are always kept
+func RestrictPolicies(policies, granted []*rbac.Policy) []*rbac.Policy {
+	grants := map[string]map[rbac.Action]bool{}
+	for _, g := range granted {
+		if g.GetEffect() != rbac.EffectAllow.String() {
+			continue
+		}
+		key := relativeResource(g.Resource)
+		if grants[key] == nil {
+			grants[key] = map[rbac.Action]bool{}
+		}
+		grants[key][g.Action] = true
+	}
+	restricted := []*rbac.Policy{}
+	for _, policy := range policies {
+		if policy.GetEffect() != rbac.EffectAllow.String() {
+			restricted = append(restricted, policy)
+			continue
+		}
+		actions := grants[relativeResource(policy.Resource)]
+		switch {
+		case actions[policy.Action], actions[rbac.ActionPushPull] &&
+			(policy.Action == rbac.ActionPull || policy.Action == rbac.ActionPush):
+			restricted = append(restricted, policy)
+		case policy.Action == rbac.ActionPushPull:
+			// keep the part of "push+pull" which is still granted
+			for _, action := range []rbac.Action{rbac.ActionPull, rbac.ActionPush} {
+				if actions[action] {
+					p := *policy
+					p.Action = action
+					restricted = append(restricted, &p)
+				}
+			}
+		}
+	}
+	return restricted
+}
+
+// relativeResource returns the resource without the project namespace, e.g. "repository"
+// for "/project/1/repository"
+func relativeResource(resource rbac.Resource) string {
+	res := resource.String()
+	if !strings.HasPrefix(res, "/project/") {
+		return res
+	}
+	parts := strings.SplitN(strings.TrimPrefix(res, "/project/"), "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
@@ -25,3 +25,55 @@ func TestGetPolicies(t *testing.T) {
 	assert.NotNil(t, robot.GetPolicies())
 	assert.Nil(t, robot.GetRoles())
 }
+
+func TestMovePolicies(t *testing.T) {
+	policies := []*rbac.Policy{
+		{
+			Resource: "/project/1/repository",
+			Action:   "pull",
+		},
+		{
+			Resource: "/project/10/repository",
+			Action:   "push",
+		},
+	}
+	moved := MovePolicies(policies, 1, 2)
+	assert.Equal(t, rbac.Resource("/project/2/repository"), moved[0].Resource)
+	assert.Equal(t, rbac.Action("pull"), moved[0].Action)
+	assert.Equal(t, rbac.Resource("/project/10/repository"), moved[1].Resource)
+	// the original policies are untouched
+	assert.Equal(t, rbac.Resource("/project/1/repository"), policies[0].Resource)
+}
+
+func TestRestrictPolicies(t *testing.T) {
+	policies := []*rbac.Policy{
+		{
+			Resource: "/project/1/repository",
+			Action:   "push+pull",
+		},
+		{
+			Resource: "/project/1/helm-chart",
+			Action:   "read",
+		},
+		{
+			Resource: "/project/1/repository",
+			Action:   "delete",
+			Effect:   "deny",
+		},
+	}
+	// the granted access recorded before the robot is moved to project 2
+	restricted := RestrictPolicies(MovePolicies(policies, 1, 2), []*rbac.Policy{
+		{
+			Resource: "/project/1/repository",
+			Action:   "pull",
+		},
+	})
+	assert.Equal(t, 2, len(restricted))
+	assert.Equal(t, rbac.Resource("/project/2/repository"), restricted[0].Resource)
+	assert.Equal(t, rbac.Action("pull"), restricted[0].Action)
+	assert.Equal(t, rbac.Action("delete"), restricted[1].Action)
+
+	// nothing is restricted if the access is still granted
+	restricted = RestrictPolicies(policies, policies)
+	assert.Equal(t, policies, restricted)
+}
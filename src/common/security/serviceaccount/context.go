@@ -0,0 +1,116 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package serviceaccount
+
+import (
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/core/promgr"
+)
+
+// SecurityContext implements security.Context interface based on the service account of
+// Kubernetes whose token is exchanged, it can only pull from the projects bound to it
+type SecurityContext struct {
+	// the subject of the service account token, e.g. "system:serviceaccount:ci:builder"
+	subject  string
+	projects map[string]bool
+	pm       promgr.ProjectManager
+}
+
+// NewSecurityContext ...
+func NewSecurityContext(subject string, projects []string, pm promgr.ProjectManager) *SecurityContext {
+	ctx := &SecurityContext{
+		subject:  subject,
+		projects: map[string]bool{},
+		pm:       pm,
+	}
+	for _, project := range projects {
+		ctx.projects[project] = true
+	}
+	return ctx
+}
+
+// IsAuthenticated returns true if the service account token has been verified
+func (s *SecurityContext) IsAuthenticated() bool {
+	return len(s.subject) > 0
+}
+
+// GetUsername returns the subject of the service account token
+func (s *SecurityContext) GetUsername() string {
+	return s.subject
+}
+
+// IsSysAdmin service account cannot be a system admin
+func (s *SecurityContext) IsSysAdmin() bool {
+	return false
+}
+
+// IsSolutionUser service account cannot be a solution user
+func (s *SecurityContext) IsSolutionUser() bool {
+	return false
+}
+
+// HasReadPerm returns whether the project is bound to the service account
+func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+	if !s.IsAuthenticated() {
+		return false
+	}
+	name, ok := projectIDOrName.(string)
+	if !ok {
+		project, err := s.pm.Get(projectIDOrName)
+		if err != nil || project == nil {
+			return false
+		}
+		name = project.Name
+	}
+	return s.projects[name]
+}
+
+// HasWritePerm service account can only pull
+func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// HasAllPerm service account can only pull
+func (s *SecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// GetMyProjects no implementation
+func (s *SecurityContext) GetMyProjects() ([]*models.Project, error) {
+	return nil, nil
+}
+
+// GetProjectRoles no implementation
+func (s *SecurityContext) GetProjectRoles(projectIDOrName interface{}) []int {
+	return nil
+}
+
+// Can returns whether the action is the pull of the repositories of a bound project
+func (s *SecurityContext) Can(action rbac.Action, resource rbac.Resource) bool {
+	if action != rbac.ActionPull {
+		return false
+	}
+	ns, err := resource.GetNamespace()
+	if err != nil || ns.Kind() != "project" {
+		return false
+	}
+	relative, err := resource.RelativeTo(ns.Resource())
+	if err != nil || relative != rbac.ResourceRepository {
+		return false
+	}
+	return s.HasReadPerm(ns.Identity())
+}
@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package serviceaccount
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/core/promgr"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProjectManager struct {
+	promgr.ProjectManager
+	projects map[int64]*models.Project
+}
+
+func (f *fakeProjectManager) Get(projectIDOrName interface{}) (*models.Project, error) {
+	id, _ := projectIDOrName.(int64)
+	return f.projects[id], nil
+}
+
+func TestSecurityContext(t *testing.T) {
+	ctx := NewSecurityContext("", nil, nil)
+	assert.False(t, ctx.IsAuthenticated())
+	assert.False(t, ctx.HasReadPerm("library"))
+
+	pm := &fakeProjectManager{
+		projects: map[int64]*models.Project{
+			1: {ProjectID: 1, Name: "library"},
+			2: {ProjectID: 2, Name: "private"},
+		},
+	}
+	ctx = NewSecurityContext("system:serviceaccount:ci:builder", []string{"library"}, pm)
+	assert.True(t, ctx.IsAuthenticated())
+	assert.Equal(t, "system:serviceaccount:ci:builder", ctx.GetUsername())
+	assert.False(t, ctx.IsSysAdmin())
+	assert.False(t, ctx.IsSolutionUser())
+	assert.True(t, ctx.HasReadPerm("library"))
+	assert.True(t, ctx.HasReadPerm(int64(1)))
+	assert.False(t, ctx.HasReadPerm("private"))
+	assert.False(t, ctx.HasReadPerm(int64(2)))
+	assert.False(t, ctx.HasWritePerm("library"))
+	assert.False(t, ctx.HasAllPerm("library"))
+
+	resource := rbac.NewProjectNamespace(int64(1), false).Resource(rbac.ResourceRepository)
+	assert.True(t, ctx.Can(rbac.ActionPull, resource))
+	assert.False(t, ctx.Can(rbac.ActionPush, resource))
+	assert.False(t, ctx.Can(rbac.ActionPull, rbac.NewProjectNamespace(int64(1), false).Resource(rbac.ResourceMember)))
+	assert.False(t, ctx.Can(rbac.ActionPull, rbac.NewProjectNamespace(int64(2), false).Resource(rbac.ResourceRepository)))
+}
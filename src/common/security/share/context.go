@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+)
+
+// SecurityContext implements security.Context interface based on the share link, it
+// has no permission on the projects but the pull of the shared repository
+type SecurityContext struct {
+	link *models.ShareLink
+}
+
+// NewSecurityContext ...
+func NewSecurityContext(link *models.ShareLink) *SecurityContext {
+	return &SecurityContext{
+		link: link,
+	}
+}
+
+// IsAuthenticated returns true if the share link has been authenticated
+func (s *SecurityContext) IsAuthenticated() bool {
+	return s.link != nil
+}
+
+// GetUsername returns the username of the share link, e.g. "share$1"
+// It returns null if the share link has not been authenticated
+func (s *SecurityContext) GetUsername() string {
+	if !s.IsAuthenticated() {
+		return ""
+	}
+	return models.ShareLinkUsername(s.link.ID)
+}
+
+// IsSysAdmin share link cannot be a system admin
+func (s *SecurityContext) IsSysAdmin() bool {
+	return false
+}
+
+// IsSolutionUser share link cannot be a solution user
+func (s *SecurityContext) IsSolutionUser() bool {
+	return false
+}
+
+// HasReadPerm the share link has no permission on the project
+func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// HasWritePerm the share link has no permission on the project
+func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// HasAllPerm the share link has no permission on the project
+func (s *SecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// GetMyProjects no implementation
+func (s *SecurityContext) GetMyProjects() ([]*models.Project, error) {
+	return nil, nil
+}
+
+// GetProjectRoles no implementation
+func (s *SecurityContext) GetProjectRoles(projectIDOrName interface{}) []int {
+	return nil
+}
+
+// Can the share link can't do any action on the resources of the project
+func (s *SecurityContext) Can(action rbac.Action, resource rbac.Resource) bool {
+	return false
+}
+
+// CanPull returns whether the repository is the shared one
+func (s *SecurityContext) CanPull(repository string) bool {
+	return s.IsAuthenticated() && s.link.Repository == repository
+}
@@ -0,0 +1,48 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityContext(t *testing.T) {
+	ctx := NewSecurityContext(nil)
+	assert.False(t, ctx.IsAuthenticated())
+	assert.Equal(t, "", ctx.GetUsername())
+	assert.False(t, ctx.CanPull("library/app"))
+
+	ctx = NewSecurityContext(&models.ShareLink{
+		ID:         1,
+		ProjectID:  1,
+		Repository: "library/app",
+		Reference:  "1.0",
+	})
+	assert.True(t, ctx.IsAuthenticated())
+	assert.Equal(t, "share$1", ctx.GetUsername())
+	assert.False(t, ctx.IsSysAdmin())
+	assert.False(t, ctx.IsSolutionUser())
+	assert.False(t, ctx.HasReadPerm(1))
+	assert.False(t, ctx.HasWritePerm(1))
+	assert.False(t, ctx.HasAllPerm(1))
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceRepository)
+	assert.False(t, ctx.Can(rbac.ActionPull, resource))
+	assert.True(t, ctx.CanPull("library/app"))
+	assert.False(t, ctx.CanPull("library/other"))
+}
@@ -0,0 +1,126 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uitoken
+
+import (
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/security"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// SecurityContext implements security.Context interface based on the UI token, it has
+// the read-only permissions of the user who the token is minted for and only on the
+// project which the token is scoped to
+type SecurityContext struct {
+	user     security.Context
+	project  *models.Project
+	audience string
+}
+
+// NewSecurityContext ...
+func NewSecurityContext(user security.Context, project *models.Project, audience string) *SecurityContext {
+	return &SecurityContext{
+		user:     user,
+		project:  project,
+		audience: audience,
+	}
+}
+
+// IsAuthenticated returns true if the user of the token has been authenticated
+func (s *SecurityContext) IsAuthenticated() bool {
+	return s.user != nil && s.project != nil && s.user.IsAuthenticated()
+}
+
+// GetUsername returns the username of the user who the token is minted for
+// It returns null if the token has not been authenticated
+func (s *SecurityContext) GetUsername() string {
+	if !s.IsAuthenticated() {
+		return ""
+	}
+	return s.user.GetUsername()
+}
+
+// GetAudience returns the audience of the token
+func (s *SecurityContext) GetAudience() string {
+	return s.audience
+}
+
+// IsSysAdmin the token doesn't carry the system admin role of the user
+func (s *SecurityContext) IsSysAdmin() bool {
+	return false
+}
+
+// IsSolutionUser the token cannot be a solution user
+func (s *SecurityContext) IsSolutionUser() bool {
+	return false
+}
+
+// HasReadPerm returns whether the user has read permission to the project of the token
+func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+	return s.inScope(projectIDOrName) && s.user.HasReadPerm(projectIDOrName)
+}
+
+// HasWritePerm the token is read-only
+func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// HasAllPerm the token is read-only
+func (s *SecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// GetMyProjects returns the project of the token if the user is the member of it
+func (s *SecurityContext) GetMyProjects() ([]*models.Project, error) {
+	if !s.IsAuthenticated() || len(s.user.GetProjectRoles(s.project.ProjectID)) == 0 {
+		return []*models.Project{}, nil
+	}
+	return []*models.Project{s.project}, nil
+}
+
+// GetProjectRoles the token grants no role as it's read-only
+func (s *SecurityContext) GetProjectRoles(projectIDOrName interface{}) []int {
+	return nil
+}
+
+// Can returns whether the user can do the read-only action on the resource of the project of the token
+func (s *SecurityContext) Can(action rbac.Action, resource rbac.Resource) bool {
+	switch action {
+	case rbac.ActionRead, rbac.ActionList, rbac.ActionPull:
+	default:
+		return false
+	}
+	ns, err := resource.GetNamespace()
+	if err != nil || ns.Kind() != "project" || !s.inScope(ns.Identity()) {
+		return false
+	}
+	return s.user.Can(action, resource)
+}
+
+// inScope returns whether the project is the one which the token is scoped to
+func (s *SecurityContext) inScope(projectIDOrName interface{}) bool {
+	if !s.IsAuthenticated() {
+		return false
+	}
+	id, name, err := utils.ParseProjectIDOrName(projectIDOrName)
+	if err != nil {
+		return false
+	}
+	if id > 0 {
+		return id == s.project.ProjectID
+	}
+	return name == s.project.Name
+}
@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uitoken
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserContext is the project admin of all the projects
+type fakeUserContext struct{}
+
+func (f *fakeUserContext) IsAuthenticated() bool {
+	return true
+}
+func (f *fakeUserContext) GetUsername() string {
+	return "jack"
+}
+func (f *fakeUserContext) IsSysAdmin() bool {
+	return true
+}
+func (f *fakeUserContext) IsSolutionUser() bool {
+	return false
+}
+func (f *fakeUserContext) HasReadPerm(projectIDOrName interface{}) bool {
+	return true
+}
+func (f *fakeUserContext) HasWritePerm(projectIDOrName interface{}) bool {
+	return true
+}
+func (f *fakeUserContext) HasAllPerm(projectIDOrName interface{}) bool {
+	return true
+}
+func (f *fakeUserContext) Can(action rbac.Action, resource rbac.Resource) bool {
+	return true
+}
+func (f *fakeUserContext) GetMyProjects() ([]*models.Project, error) {
+	return nil, nil
+}
+func (f *fakeUserContext) GetProjectRoles(interface{}) []int {
+	return []int{common.RoleProjectAdmin}
+}
+
+func TestSecurityContext(t *testing.T) {
+	ctx := NewSecurityContext(nil, nil, "log_viewer")
+	assert.False(t, ctx.IsAuthenticated())
+	assert.Equal(t, "", ctx.GetUsername())
+	assert.False(t, ctx.HasReadPerm(1))
+
+	project := &models.Project{
+		ProjectID: 1,
+		Name:      "library",
+	}
+	ctx = NewSecurityContext(&fakeUserContext{}, project, "log_viewer")
+	assert.True(t, ctx.IsAuthenticated())
+	assert.Equal(t, "jack", ctx.GetUsername())
+	assert.Equal(t, "log_viewer", ctx.GetAudience())
+	assert.False(t, ctx.IsSysAdmin())
+	assert.False(t, ctx.IsSolutionUser())
+
+	assert.True(t, ctx.HasReadPerm(int64(1)))
+	assert.True(t, ctx.HasReadPerm("library"))
+	assert.False(t, ctx.HasReadPerm(int64(2)))
+	assert.False(t, ctx.HasReadPerm("other"))
+	assert.False(t, ctx.HasWritePerm(int64(1)))
+	assert.False(t, ctx.HasAllPerm(int64(1)))
+	assert.Nil(t, ctx.GetProjectRoles(int64(1)))
+
+	projects, err := ctx.GetMyProjects()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(projects))
+	assert.Equal(t, "library", projects[0].Name)
+
+	resource := rbac.NewProjectNamespace(1, false).Resource(rbac.ResourceLog)
+	assert.True(t, ctx.Can(rbac.ActionList, resource))
+	assert.False(t, ctx.Can(rbac.ActionCreate, resource))
+	resource = rbac.NewProjectNamespace(2, false).Resource(rbac.ResourceLog)
+	assert.False(t, ctx.Can(rbac.ActionList, resource))
+	resource = rbac.NewProjectNamespace("library", false).Resource(rbac.ResourceHelmChart)
+	assert.True(t, ctx.Can(rbac.ActionRead, resource))
+}
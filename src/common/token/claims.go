@@ -27,3 +27,50 @@ func (rc RobotClaims) Valid() error {
 	}
 	return nil
 }
+
+// ShareClaims implements the interface of jwt.Claims, it's signed as the token of the share link
+type ShareClaims struct {
+	jwt.StandardClaims
+	ShareID int64 `json:"sid"`
+}
+
+// Valid valid the claims "shareID" and the expiration.
+func (sc ShareClaims) Valid() error {
+	if sc.ShareID <= 0 {
+		return errors.New("Share id must an valid INT")
+	}
+	return sc.StandardClaims.Valid()
+}
+
+// the audiences of the UI tokens, i.e. the tools embedded in the web UI
+const (
+	// AudienceChartViewer is the viewer of the helm charts
+	AudienceChartViewer = "chart_viewer"
+	// AudienceLogViewer is the viewer of the access logs
+	AudienceLogViewer = "log_viewer"
+)
+
+// UIAudiences are the audiences which the UI tokens can be issued to
+var UIAudiences = []string{AudienceChartViewer, AudienceLogViewer}
+
+// UIClaims implements the interface of jwt.Claims, it's signed as the short-lived token
+// minted from the session of the user, which is scoped to one project and one audience
+type UIClaims struct {
+	jwt.StandardClaims
+	UserID    int   `json:"uid"`
+	ProjectID int64 `json:"pid"`
+}
+
+// Valid valid the claims "userID, projectID, audience" and the expiration.
+func (uc UIClaims) Valid() error {
+	if uc.UserID <= 0 {
+		return errors.New("User id must an valid INT")
+	}
+	if uc.ProjectID <= 0 {
+		return errors.New("Project id must an valid INT")
+	}
+	if len(uc.Audience) == 0 {
+		return errors.New("The audience cannot be empty")
+	}
+	return uc.StandardClaims.Valid()
+}
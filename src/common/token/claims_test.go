@@ -1,9 +1,11 @@
 package token
 
 import (
+	"github.com/dgrijalva/jwt-go"
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestValid(t *testing.T) {
@@ -66,3 +68,43 @@ func TestUnValidPolicy(t *testing.T) {
 	}
 	assert.NotNil(t, rClaims.Valid())
 }
+
+func TestShareClaimsValid(t *testing.T) {
+	sClaims := &ShareClaims{
+		ShareID: 1,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	assert.Nil(t, sClaims.Valid())
+
+	sClaims.ShareID = 0
+	assert.NotNil(t, sClaims.Valid())
+
+	sClaims.ShareID = 1
+	sClaims.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	assert.NotNil(t, sClaims.Valid())
+}
+
+func TestUIClaimsValid(t *testing.T) {
+	uClaims := &UIClaims{
+		UserID:    1,
+		ProjectID: 1,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  AudienceLogViewer,
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	}
+	assert.Nil(t, uClaims.Valid())
+
+	uClaims.Audience = ""
+	assert.NotNil(t, uClaims.Valid())
+
+	uClaims.Audience = AudienceLogViewer
+	uClaims.ProjectID = 0
+	assert.NotNil(t, uClaims.Valid())
+
+	uClaims.ProjectID = 1
+	uClaims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	assert.NotNil(t, uClaims.Valid())
+}
@@ -1,8 +1,6 @@
 package token
 
 import (
-	"crypto/ecdsa"
-	"crypto/rsa"
 	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
@@ -38,6 +36,44 @@ func New(tokenID, projectID int64, access []*rbac.Policy) (*HToken, error) {
 	}, nil
 }
 
+// NewShareToken returns the token of the share link, it expires along with the link
+func NewShareToken(shareID int64, expiresAt time.Time) (*HToken, error) {
+	sClaims := &ShareClaims{
+		ShareID: shareID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+			Issuer:    DefaultOptions.Issuer,
+		},
+	}
+	err := sClaims.Valid()
+	if err != nil {
+		return nil, err
+	}
+	return &HToken{
+		Token: *jwt.NewWithClaims(DefaultOptions.SignMethod, sClaims),
+	}, nil
+}
+
+// NewUIToken returns the token of the user scoped to the project and the audience, it expires after the TTL
+func NewUIToken(userID int, projectID int64, audience string, ttl time.Duration) (*HToken, error) {
+	uClaims := &UIClaims{
+		UserID:    userID,
+		ProjectID: projectID,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  audience,
+			ExpiresAt: time.Now().Add(ttl).Unix(),
+			Issuer:    DefaultOptions.Issuer,
+		},
+	}
+	err := uClaims.Valid()
+	if err != nil {
+		return nil, err
+	}
+	return &HToken{
+		Token: *jwt.NewWithClaims(DefaultOptions.SignMethod, uClaims),
+	}, nil
+}
+
 // Raw get the Raw string of token
 func (htk *HToken) Raw() (string, error) {
 	key, err := DefaultOptions.GetKey()
@@ -54,23 +90,25 @@ func (htk *HToken) Raw() (string, error) {
 
 // ParseWithClaims ...
 func ParseWithClaims(rawToken string, claims jwt.Claims) (*HToken, error) {
-	key, err := DefaultOptions.GetKey()
+	keys, err := DefaultOptions.verificationKeys()
 	if err != nil {
 		return nil, err
 	}
-	token, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
-		if token.Method.Alg() != DefaultOptions.SignMethod.Alg() {
-			return nil, errors.New("invalid signing method")
-		}
-		switch k := key.(type) {
-		case *rsa.PrivateKey:
-			return &k.PublicKey, nil
-		case *ecdsa.PrivateKey:
-			return &k.PublicKey, nil
-		default:
-			return key, nil
+	var token *jwt.Token
+	for i, key := range keys {
+		verifyKey := key
+		token, err = jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+			if token.Method.Alg() != DefaultOptions.SignMethod.Alg() {
+				return nil, errors.New("invalid signing method")
+			}
+			return verifyKey, nil
+		})
+		// the token may be signed by a key replaced by the rotation
+		if e, ok := err.(*jwt.ValidationError); ok && e.Errors&jwt.ValidationErrorSignatureInvalid != 0 && i < len(keys)-1 {
+			continue
 		}
-	})
+		break
+	}
 	if err != nil {
 		log.Errorf(fmt.Sprintf("parse token error, %v", err))
 		return nil, err
@@ -5,8 +5,10 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestMain(m *testing.M) {
@@ -75,3 +77,38 @@ func TestParseWithClaims(t *testing.T) {
 	assert.Equal(t, int64(0), rClaims.ProjectID)
 	assert.Equal(t, "/project/libray/repository", rClaims.Access[0].Resource.String())
 }
+
+func TestNewShareToken(t *testing.T) {
+	_, err := NewShareToken(0, time.Now().Add(time.Hour))
+	assert.NotNil(t, err)
+
+	token, err := NewShareToken(1, time.Now().Add(time.Hour))
+	require.Nil(t, err)
+	rawTk, err := token.Raw()
+	require.Nil(t, err)
+	sClaims := &ShareClaims{}
+	_, err = ParseWithClaims(rawTk, sClaims)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), sClaims.ShareID)
+
+	// the expired token
+	token, err = NewShareToken(1, time.Now().Add(-time.Hour))
+	assert.NotNil(t, err)
+}
+
+func TestNewUIToken(t *testing.T) {
+	_, err := NewUIToken(1, 1, "", time.Minute)
+	assert.NotNil(t, err)
+
+	token, err := NewUIToken(1, 2, AudienceChartViewer, time.Minute)
+	require.Nil(t, err)
+	rawTk, err := token.Raw()
+	require.Nil(t, err)
+	uClaims := &UIClaims{}
+	_, err = ParseWithClaims(rawTk, uClaims)
+	require.Nil(t, err)
+	assert.Equal(t, 1, uClaims.UserID)
+	assert.Equal(t, int64(2), uClaims.ProjectID)
+	assert.True(t, uClaims.VerifyAudience(AudienceChartViewer, true))
+	assert.False(t, uClaims.VerifyAudience(AudienceLogViewer, true))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
 	"io/ioutil"
+	"sync"
 	"time"
 )
 
@@ -29,6 +30,10 @@ type Options struct {
 	PrivateKey []byte
 	TTL        time.Duration
 	Issuer     string
+	lock       sync.RWMutex
+	// the keys replaced by the rotations, the tokens signed by them are still
+	// verified until they expire
+	retired []*retiredKey
 }
 
 // NewOptions ...
@@ -49,20 +54,26 @@ func NewOptions() *Options {
 
 // GetKey ...
 func (o *Options) GetKey() (interface{}, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return o.parseKey(o.PrivateKey, o.PublicKey)
+}
+
+func (o *Options) parseKey(privateKeyPEM, publicKeyPEM []byte) (interface{}, error) {
 	var err error
 	var privateKey *rsa.PrivateKey
 	var publicKey *rsa.PublicKey
 
 	switch o.SignMethod.(type) {
 	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
-		if len(o.PrivateKey) > 0 {
-			privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(o.PrivateKey)
+		if len(privateKeyPEM) > 0 {
+			privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(privateKeyPEM)
 			if err != nil {
 				return nil, err
 			}
 		}
-		if len(o.PublicKey) > 0 {
-			publicKey, err = jwt.ParseRSAPublicKeyFromPEM(o.PublicKey)
+		if len(publicKeyPEM) > 0 {
+			publicKey, err = jwt.ParseRSAPublicKeyFromPEM(publicKeyPEM)
 			if err != nil {
 				return nil, err
 			}
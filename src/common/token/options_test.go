@@ -1,10 +1,16 @@
 package token
 
 import (
-	"github.com/dgrijalva/jwt-go"
-	"github.com/stretchr/testify/assert"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewOptions(t *testing.T) {
@@ -21,3 +27,89 @@ func TestGetKey(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, key)
 }
+
+func TestRotatePrivateKey(t *testing.T) {
+	oldKey, newKey := generateKey(t), generateKey(t)
+	opt := &Options{
+		SignMethod: jwt.GetSigningMethod("RS256"),
+		PrivateKey: oldKey,
+		Issuer:     "harbor-token-issuer",
+		TTL:        60 * time.Minute,
+	}
+
+	_, err := opt.RotatePrivateKey([]byte("invalid"), time.Hour)
+	assert.NotNil(t, err)
+	retired, err := opt.RotatePrivateKey(oldKey, time.Hour)
+	require.Nil(t, err)
+	assert.Nil(t, retired)
+
+	retired, err = opt.RotatePrivateKey(newKey, time.Hour)
+	require.Nil(t, err)
+	require.NotNil(t, retired)
+	assert.Equal(t, newKey, opt.PrivateKey)
+	keys, err := opt.verificationKeys()
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(keys))
+
+	// the old key is dropped after the grace period
+	opt.retired[0].expiresAt = time.Now().Add(-time.Second)
+	keys, err = opt.verificationKeys()
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(keys))
+
+	// the key retired by another instance
+	require.Nil(t, opt.RetireKey(retired, time.Now().Add(time.Hour)))
+	require.Nil(t, opt.RetireKey(retired, time.Now().Add(2*time.Hour)))
+	keys, err = opt.verificationKeys()
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(keys))
+	assert.NotNil(t, opt.RetireKey([]byte("invalid"), time.Now().Add(time.Hour)))
+}
+
+func TestParseWithRetiredKey(t *testing.T) {
+	defaultOpt := DefaultOptions
+	defer func() {
+		DefaultOptions = defaultOpt
+	}()
+	DefaultOptions = &Options{
+		SignMethod: jwt.GetSigningMethod("RS256"),
+		PrivateKey: generateKey(t),
+		Issuer:     "harbor-token-issuer",
+		TTL:        60 * time.Minute,
+	}
+
+	tk, err := NewShareToken(1, time.Now().Add(time.Hour))
+	require.Nil(t, err)
+	raw, err := tk.Raw()
+	require.Nil(t, err)
+
+	retired, err := DefaultOptions.RotatePrivateKey(generateKey(t), time.Hour)
+	require.Nil(t, err)
+	_, err = ParseWithClaims(raw, &ShareClaims{})
+	assert.Nil(t, err)
+
+	// not verified after the grace period
+	DefaultOptions.retired[0].expiresAt = time.Now().Add(-time.Second)
+	_, err = ParseWithClaims(raw, &ShareClaims{})
+	assert.NotNil(t, err)
+
+	// verified by the instance loading the key retired by another one
+	DefaultOptions = &Options{
+		SignMethod: jwt.GetSigningMethod("RS256"),
+		PrivateKey: generateKey(t),
+		Issuer:     "harbor-token-issuer",
+		TTL:        60 * time.Minute,
+	}
+	require.Nil(t, DefaultOptions.RetireKey(retired, time.Now().Add(time.Hour)))
+	_, err = ParseWithClaims(raw, &ShareClaims{})
+	assert.Nil(t, err)
+}
+
+func generateKey(t *testing.T) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
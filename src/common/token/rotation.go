@@ -0,0 +1,216 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+const (
+	// DefaultKeyCheckInterval is the default interval between two checks of the private key file
+	DefaultKeyCheckInterval = time.Minute
+	// MaxTokenLifetime is the max lifetime of the tokens signed by the private key, which is the
+	// one of the share link tokens. The configured grace period can't be shorter than it
+	MaxTokenLifetime = models.MaxShareLinkTTL
+)
+
+type retiredKey struct {
+	// the PEM of the public key
+	publicKey []byte
+	expiresAt time.Time
+}
+
+// RotatePrivateKey replaces the private key signing the tokens, the tokens signed by the
+// old one are still verified in the grace period. It returns the PEM of the public key of
+// the old one, which is nil if the key isn't changed
+func (o *Options) RotatePrivateKey(privateKey []byte, grace time.Duration) ([]byte, error) {
+	if _, err := o.parseKey(privateKey, nil); err != nil {
+		return nil, err
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if bytes.Equal(o.PrivateKey, privateKey) {
+		return nil, nil
+	}
+	var retired []byte
+	if len(o.PrivateKey) > 0 {
+		key, err := o.parseKey(o.PrivateKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		if retired, err = encodePublicKey(key); err != nil {
+			return nil, err
+		}
+		if grace > 0 {
+			o.retire(retired, time.Now().Add(grace))
+		}
+	}
+	o.PrivateKey = privateKey
+	return retired, nil
+}
+
+// RetireKey adds the public key retired by another instance, the tokens signed by it are
+// verified until it expires
+func (o *Options) RetireKey(publicKey []byte, expiresAt time.Time) error {
+	if _, err := o.parseKey(nil, publicKey); err != nil {
+		return err
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.retire(publicKey, expiresAt)
+	return nil
+}
+
+// retire must be called with the lock held, the expired keys are dropped at the same time
+func (o *Options) retire(publicKey []byte, expiresAt time.Time) {
+	now := time.Now()
+	retired := []*retiredKey{}
+	found := false
+	for _, key := range o.retired {
+		if bytes.Equal(key.publicKey, publicKey) {
+			found = true
+			if expiresAt.After(key.expiresAt) {
+				key.expiresAt = expiresAt
+			}
+		}
+		if key.expiresAt.After(now) {
+			retired = append(retired, key)
+		}
+	}
+	if !found && expiresAt.After(now) {
+		retired = append(retired, &retiredKey{
+			publicKey: publicKey,
+			expiresAt: expiresAt,
+		})
+	}
+	o.retired = retired
+}
+
+// verificationKeys returns the keys verifying the tokens, the current one is the first
+func (o *Options) verificationKeys() ([]interface{}, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	key, err := o.parseKey(o.PrivateKey, o.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	keys := []interface{}{publicKey(key)}
+	now := time.Now()
+	for _, retired := range o.retired {
+		if !retired.expiresAt.After(now) {
+			continue
+		}
+		key, err := o.parseKey(nil, retired.publicKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func publicKey(key interface{}) interface{} {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	default:
+		return key
+	}
+}
+
+// encodePublicKey returns the PEM of the public key of the private key
+func encodePublicKey(key interface{}) ([]byte, error) {
+	data, err := x509.MarshalPKIXPublicKey(publicKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: data,
+	}), nil
+}
+
+// gracePeriod returns the configured grace period, which is at least the max lifetime of the tokens
+func gracePeriod() time.Duration {
+	grace, err := config.TokenKeyGracePeriod()
+	if err != nil {
+		log.Errorf("failed to get the grace period of the token keys: %v", err)
+		return MaxTokenLifetime
+	}
+	if grace < MaxTokenLifetime {
+		return MaxTokenLifetime
+	}
+	return grace
+}
+
+// WatchPrivateKey checks the private key file every interval in background and rotates
+// the key of the default options once the file changes, so the certificate and key can be
+// rotated without restarting. The retired keys are recorded in the database and loaded by
+// all the instances, so the tokens signed by them are verified by any instance in the grace period
+func WatchPrivateKey(interval time.Duration) {
+	if DefaultOptions == nil {
+		log.Warningf("the private key %s isn't loaded, it won't be watched", privateKey)
+		return
+	}
+	loadRetiredKeys()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			rotatePrivateKey()
+			loadRetiredKeys()
+		}
+	}()
+	log.Infof("the private key %s is watched, interval: %v", privateKey, interval)
+}
+
+func rotatePrivateKey() {
+	data, err := ioutil.ReadFile(privateKey)
+	if err != nil {
+		log.Errorf("failed to read the private key %s: %v", privateKey, err)
+		return
+	}
+	grace := gracePeriod()
+	// the file may be partially written, it's checked again in the next round
+	retired, err := DefaultOptions.RotatePrivateKey(data, grace)
+	if err != nil {
+		log.Errorf("failed to rotate the private key %s: %v", privateKey, err)
+		return
+	}
+	if retired == nil {
+		return
+	}
+	log.Infof("the private key %s is rotated, the tokens signed by the old key are verified in %v", privateKey, grace)
+	if err = dao.AddRetiredTokenKey(&models.RetiredTokenKey{
+		PublicKey: string(retired),
+		ExpiresAt: time.Now().Add(grace),
+	}); err != nil {
+		log.Errorf("failed to record the retired token key: %v", err)
+	}
+}
+
+// loadRetiredKeys loads the keys retired by all the instances
+func loadRetiredKeys() {
+	keys, err := dao.ListRetiredTokenKeys()
+	if err != nil {
+		log.Errorf("failed to list the retired token keys: %v", err)
+		return
+	}
+	for _, key := range keys {
+		if err = DefaultOptions.RetireKey([]byte(key.PublicKey), key.ExpiresAt); err != nil {
+			log.Errorf("failed to load the retired token key %d: %v", key.ID, err)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	//	"path"
 
@@ -37,13 +38,21 @@ type Client struct {
 
 // NewClient creates a new instance of client, set the logger as the job's logger if it's used in a job handler.
 func NewClient(endpoint string, logger *log.Logger) *Client {
+	return NewClientWithTransport(endpoint, nil, logger)
+}
+
+// NewClientWithTransport creates a new instance of client sending the requests with the transport,
+// e.g. the one trusting the custom CA bundle of the scanner, the default transport is used if it's nil
+func NewClientWithTransport(endpoint string, transport http.RoundTripper, logger *log.Logger) *Client {
 	if logger == nil {
 		logger = log.DefaultLogger()
 	}
 	return &Client{
 		endpoint: strings.TrimSuffix(endpoint, "/"),
 		logger:   logger,
-		client:   &http.Client{},
+		client: &http.Client{
+			Transport: transport,
+		},
 	}
 }
 
@@ -103,6 +112,25 @@ func (c *Client) GetResult(layerName string) (*models.ClairLayerEnvelope, error)
 	return &res, nil
 }
 
+// InsertVulnerability calls Clair's API to insert or update the vulnerability, Clair links it to
+// the features of the scanned layers and sends the notification of the affected layers
+func (c *Client) InsertVulnerability(v models.ClairVulnerability) error {
+	data, err := json.Marshal(models.ClairVulnerabilityEnvelope{
+		Vulnerability: &v,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint+"/v1/namespaces/"+url.PathEscape(v.NamespaceName)+"/vulnerabilities",
+		bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Content-Type"), "application/json")
+	_, err = c.send(req, http.StatusCreated)
+	return err
+}
+
 // GetNotification calls Clair's API to get details of notification
 func (c *Client) GetNotification(id string) (*models.ClairNotification, error) {
 	req, err := http.NewRequest(http.MethodGet, c.endpoint+"/v1/notifications/"+id+"?limit=2", nil)
@@ -23,6 +23,7 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
@@ -67,8 +68,46 @@ func (n *notificationHandler) ServeHTTP(rw http.ResponseWriter, req *http.Reques
 	}
 }
 
+// vulnerabilityHandler stores the vulnerabilities inserted, they're linked to the features
+// of the layer with the same name and namespace regardless of the versions
+type vulnerabilityHandler struct {
+	lock            sync.Mutex
+	vulnerabilities []models.ClairVulnerability
+}
+
+func (v *vulnerabilityHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/vulnerabilities") {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	envelope := &models.ClairVulnerabilityEnvelope{}
+	if err := json.NewDecoder(req.Body).Decode(envelope); err != nil || envelope.Vulnerability == nil {
+		http.Error(rw, "invalid vulnerability", http.StatusBadRequest)
+		return
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.vulnerabilities = append(v.vulnerabilities, *envelope.Vulnerability)
+	rw.WriteHeader(http.StatusCreated)
+}
+
+func (v *vulnerabilityHandler) link(layer *models.ClairLayer) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	for i, feature := range layer.Features {
+		for _, vulnerability := range v.vulnerabilities {
+			for _, fixedIn := range vulnerability.FixedIn {
+				if fixedIn.Name == feature.Name && vulnerability.NamespaceName == feature.NamespaceName {
+					layer.Features[i].Vulnerabilities = append(layer.Features[i].Vulnerabilities, vulnerability)
+				}
+			}
+		}
+	}
+}
+
 type layerHandler struct {
-	name string
+	name            string
+	vulnerabilities *vulnerabilityHandler
 }
 
 func (l *layerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -86,7 +125,7 @@ func (l *layerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	} else if req.Method == http.MethodGet {
 		name := strings.TrimPrefix(req.URL.Path, "/v1/layers/")
 		if name == l.name {
-			serveFile(rw, path.Join(currPath(), "total-12.json"))
+			l.serveLayer(rw)
 		} else {
 			http.Error(rw, fmt.Sprintf("Invalid layer name: %s", name), http.StatusNotFound)
 		}
@@ -95,8 +134,30 @@ func (l *layerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (l *layerHandler) serveLayer(rw http.ResponseWriter) {
+	data, err := ioutil.ReadFile(path.Join(currPath(), "total-12.json"))
+	if err != nil {
+		http.Error(rw, err.Error(), 500)
+		return
+	}
+	envelope := &models.ClairLayerEnvelope{}
+	if err = json.Unmarshal(data, envelope); err != nil {
+		http.Error(rw, err.Error(), 500)
+		return
+	}
+	l.vulnerabilities.link(envelope.Layer)
+	if err = json.NewEncoder(rw).Encode(envelope); err != nil {
+		http.Error(rw, err.Error(), 500)
+	}
+}
+
 // NewMockServer ...
 func NewMockServer() *httptest.Server {
+	vulnerabilities := &vulnerabilityHandler{}
+	layers := &layerHandler{
+		name:            "03adedf41d4e0ea1b2458546a5b4717bf5f24b23489b25589e20c692aaf84d19",
+		vulnerabilities: vulnerabilities,
+	}
 	mux := http.NewServeMux()
 	mux.HandleFunc("/v1/namespaces", func(rw http.ResponseWriter, req *http.Request) {
 		if req.Method == http.MethodGet {
@@ -106,8 +167,9 @@ func NewMockServer() *httptest.Server {
 		}
 	})
 	mux.Handle("/v1/notifications/", &notificationHandler{id: "ec45ec87-bfc8-4129-a1c3-d2b82622175a"})
-	mux.Handle("/v1/layers", &layerHandler{name: "03adedf41d4e0ea1b2458546a5b4717bf5f24b23489b25589e20c692aaf84d19"})
-	mux.Handle("/v1/layers/", &layerHandler{name: "03adedf41d4e0ea1b2458546a5b4717bf5f24b23489b25589e20c692aaf84d19"})
+	mux.Handle("/v1/namespaces/", vulnerabilities)
+	mux.Handle("/v1/layers", layers)
+	mux.Handle("/v1/layers/", layers)
 	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
 		log.Infof("method: %s, path: %s", req.Method, req.URL.Path)
 		rw.WriteHeader(http.StatusNotFound)
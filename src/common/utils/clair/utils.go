@@ -16,6 +16,7 @@ package clair
 
 import (
 	"fmt"
+	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
@@ -41,8 +42,58 @@ func ParseClairSev(clairSev string) models.Severity {
 	}
 }
 
+// ParseVulnSev returns the severity of the vulnerability according to the source of CVSS, the severity
+// of the vendor is returned if the source is "vendor" or the vulnerability isn't scored by NVD in the version
+func ParseVulnSev(v models.ClairVulnerability, cvssSource string) models.Severity {
+	switch cvssSource {
+	case common.CVSSSourceNVDV2:
+		if score, ok := cvssScore(v.Metadata, "CVSSv2"); ok {
+			// NVD CVSS v2 ratings: low 0.0-3.9, medium 4.0-6.9, high 7.0-10.0
+			switch {
+			case score < 4:
+				return models.SevLow
+			case score < 7:
+				return models.SevMedium
+			default:
+				return models.SevHigh
+			}
+		}
+	case common.CVSSSourceNVDV3:
+		if score, ok := cvssScore(v.Metadata, "CVSSv3"); ok {
+			// NVD CVSS v3 ratings: none 0.0, low 0.1-3.9, medium 4.0-6.9, high 7.0-8.9, critical 9.0-10.0,
+			// the critical is treated as high as ParseClairSev does
+			switch {
+			case score == 0:
+				return models.SevNone
+			case score < 4:
+				return models.SevLow
+			case score < 7:
+				return models.SevMedium
+			default:
+				return models.SevHigh
+			}
+		}
+	}
+	return ParseClairSev(v.Severity)
+}
+
+// cvssScore reads the score from the metadata in format {"NVD": {"CVSSv2": {"Score": 7.5, "Vectors": "..."}}}
+func cvssScore(metadata map[string]interface{}, version string) (float64, bool) {
+	nvd, ok := metadata["NVD"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	cvss, ok := nvd[version].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	score, ok := cvss["Score"].(float64)
+	return score, ok
+}
+
 // UpdateScanOverview qeuries the vulnerability based on the layerName and update the record in img_scan_overview table based on digest.
-func UpdateScanOverview(digest, layerName string, clairEndpoint string, l ...*log.Logger) error {
+// The severity is decided according to the source of CVSS
+func UpdateScanOverview(digest, layerName string, clairEndpoint string, cvssSource string, l ...*log.Logger) error {
 	var logger *log.Logger
 	if len(l) > 1 {
 		return fmt.Errorf("More than one logger specified")
@@ -57,11 +108,36 @@ func UpdateScanOverview(digest, layerName string, clairEndpoint string, l ...*lo
 		logger.Errorf("Failed to get result from Clair, error: %v", err)
 		return err
 	}
-	compOverview, sev := transformVuln(res)
-	return dao.UpdateImgScanOverview(digest, layerName, sev, compOverview)
+	return UpdateScanResult(digest, layerName, res, cvssSource)
+}
+
+// UpdateScanResult updates the record in img_scan_overview table and replaces the vulnerabilities
+// found in the image with the result of Clair
+func UpdateScanResult(digest, layerName string, res *models.ClairLayerEnvelope, cvssSource string) error {
+	compOverview, sev := transformVuln(res, cvssSource)
+	if err := dao.UpdateImgScanOverview(digest, layerName, sev, compOverview); err != nil {
+		return err
+	}
+	return dao.ReplaceVulnFindings(digest, vulnFindings(res, cvssSource))
+}
+
+func vulnFindings(clairVuln *models.ClairLayerEnvelope, cvssSource string) []*models.VulnFinding {
+	findings := []*models.VulnFinding{}
+	for _, f := range clairVuln.Layer.Features {
+		for _, v := range f.Vulnerabilities {
+			findings = append(findings, &models.VulnFinding{
+				CVEID:        v.Name,
+				Package:      f.Name,
+				Version:      f.Version,
+				FixedVersion: v.FixedBy,
+				Severity:     ParseVulnSev(v, cvssSource),
+			})
+		}
+	}
+	return findings
 }
 
-func transformVuln(clairVuln *models.ClairLayerEnvelope) (*models.ComponentsOverview, models.Severity) {
+func transformVuln(clairVuln *models.ClairLayerEnvelope, cvssSource string) (*models.ComponentsOverview, models.Severity) {
 	vulnMap := make(map[models.Severity]int)
 	features := clairVuln.Layer.Features
 	totalComponents := len(features)
@@ -69,7 +145,7 @@ func transformVuln(clairVuln *models.ClairLayerEnvelope) (*models.ComponentsOver
 	for _, f := range features {
 		sev := models.SevNone
 		for _, v := range f.Vulnerabilities {
-			temp = ParseClairSev(v.Severity)
+			temp = ParseVulnSev(v, cvssSource)
 			if temp > sev {
 				sev = temp
 			}
@@ -95,6 +171,6 @@ func transformVuln(clairVuln *models.ClairLayerEnvelope) (*models.ComponentsOver
 }
 
 // TransformVuln is for running scanning job in both job service V1 and V2.
-func TransformVuln(clairVuln *models.ClairLayerEnvelope) (*models.ComponentsOverview, models.Severity) {
-	return transformVuln(clairVuln)
+func TransformVuln(clairVuln *models.ClairLayerEnvelope, cvssSource string) (*models.ComponentsOverview, models.Severity) {
+	return transformVuln(clairVuln, cvssSource)
 }
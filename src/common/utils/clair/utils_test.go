@@ -20,6 +20,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -39,12 +40,43 @@ func TestParseServerity(t *testing.T) {
 	}
 }
 
+func TestParseVulnSev(t *testing.T) {
+	assert := assert.New(t)
+	v := models.ClairVulnerability{
+		Severity: "Low",
+		Metadata: map[string]interface{}{
+			"NVD": map[string]interface{}{
+				"CVSSv2": map[string]interface{}{
+					"Score":   7.5,
+					"Vectors": "AV:N/AC:L/Au:N/C:P/I:P",
+				},
+			},
+		},
+	}
+	assert.Equal(models.SevLow, ParseVulnSev(v, common.CVSSSourceVendor))
+	assert.Equal(models.SevHigh, ParseVulnSev(v, common.CVSSSourceNVDV2))
+	// fall back to the vendor severity as no CVSS v3 score
+	assert.Equal(models.SevLow, ParseVulnSev(v, common.CVSSSourceNVDV3))
+
+	v.Metadata["NVD"].(map[string]interface{})["CVSSv3"] = map[string]interface{}{
+		"Score": 5.3,
+	}
+	assert.Equal(models.SevMedium, ParseVulnSev(v, common.CVSSSourceNVDV3))
+	v.Metadata["NVD"].(map[string]interface{})["CVSSv3"] = map[string]interface{}{
+		"Score": 0.0,
+	}
+	assert.Equal(models.SevNone, ParseVulnSev(v, common.CVSSSourceNVDV3))
+
+	v.Metadata = nil
+	assert.Equal(models.SevLow, ParseVulnSev(v, common.CVSSSourceNVDV2))
+}
+
 func TestTransformVuln(t *testing.T) {
 	var clairVuln = &models.ClairLayerEnvelope{}
 	assert := assert.New(t)
 	empty := []byte(`{"Layer":{"Features":[]}}`)
 	loadVuln(empty, clairVuln)
-	output, o := transformVuln(clairVuln)
+	output, o := transformVuln(clairVuln, common.CVSSSourceVendor)
 	assert.Equal(0, output.Total)
 	assert.Equal(models.SevNone, o)
 	_, f, _, ok := runtime.Caller(0)
@@ -57,7 +89,7 @@ func TestTransformVuln(t *testing.T) {
 		panic(err)
 	}
 	loadVuln(real, clairVuln)
-	output, o = transformVuln(clairVuln)
+	output, o = transformVuln(clairVuln, common.CVSSSourceVendor)
 	assert.Equal(12, output.Total)
 	assert.Equal(models.SevHigh, o)
 	hit := false
@@ -70,6 +102,23 @@ func TestTransformVuln(t *testing.T) {
 	assert.True(hit, "Not found entry for high severity in summary list")
 }
 
+func TestVulnFindings(t *testing.T) {
+	clairVuln := &models.ClairLayerEnvelope{}
+	loadVuln([]byte(`{"Layer":{"Features":[
+		{"Name":"openssl","Version":"1.0.1","Vulnerabilities":[
+			{"Name":"CVE-2014-0160","Severity":"High","FixedBy":"1.0.1g"},
+			{"Name":"CVE-2014-0224","Severity":"Medium"}]},
+		{"Name":"bash","Version":"4.3"}]}}`), clairVuln)
+	findings := vulnFindings(clairVuln, common.CVSSSourceVendor)
+	assert.Equal(t, 2, len(findings))
+	assert.Equal(t, "CVE-2014-0160", findings[0].CVEID)
+	assert.Equal(t, "openssl", findings[0].Package)
+	assert.Equal(t, "1.0.1", findings[0].Version)
+	assert.Equal(t, "1.0.1g", findings[0].FixedVersion)
+	assert.Equal(t, models.SevHigh, findings[0].Severity)
+	assert.Equal(t, models.SevMedium, findings[1].Severity)
+}
+
 func loadVuln(input []byte, data *models.ClairLayerEnvelope) {
 	err := json.Unmarshal(input, data)
 	if err != nil {
@@ -0,0 +1,192 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Component is a part of Harbor whose log level can be overridden at runtime,
+// the logs of the component are the ones printed by the source files under its paths
+type Component struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// the paths relative to the root of the source code
+	paths []string
+}
+
+var components = map[string]*Component{
+	"core_api": {
+		Name:        "core_api",
+		Description: "The API of core",
+		paths:       []string{"core/api/"},
+	},
+	"token_service": {
+		Name:        "token_service",
+		Description: "The token service issuing the tokens of registry and notary",
+		paths:       []string{"core/service/token/"},
+	},
+	"replication": {
+		Name:        "replication",
+		Description: "The replication policies, triggers and the dispatching of replication jobs",
+		paths:       []string{"replication/"},
+	},
+	"scan_dispatcher": {
+		Name:        "scan_dispatcher",
+		Description: "The scanners and the dispatching of scan jobs",
+		paths:       []string{"core/scanner/", "core/utils/job.go", "core/utils/scan_batch.go"},
+	},
+}
+
+// ComponentLevel is the log level of the component
+type ComponentLevel struct {
+	*Component
+	Level string `json:"level"`
+	// the time when the overridden level is reverted, it's nil if the level isn't overridden
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type levelOverride struct {
+	level     Level
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+var (
+	overrides     = map[string]*levelOverride{}
+	overridesLock sync.RWMutex
+	// the count of the overrides, the caller isn't located if it's 0
+	overrideCount int32
+)
+
+// Components returns all the components sorted by name
+func Components() []*Component {
+	all := make([]*Component, 0, len(components))
+	for _, c := range components {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
+
+// GetComponent returns the component specified by name
+func GetComponent(name string) (*Component, bool) {
+	c, ok := components[name]
+	return c, ok
+}
+
+// GetComponentLevel returns the current log level of the component, it's the level
+// of the default logger if it isn't overridden
+func GetComponentLevel(c *Component) *ComponentLevel {
+	overridesLock.RLock()
+	defer overridesLock.RUnlock()
+
+	cl := &ComponentLevel{
+		Component: c,
+		Level:     logger.lvl.string(),
+	}
+	if o, ok := overrides[c.Name]; ok {
+		expiresAt := o.expiresAt
+		cl.Level = o.level.string()
+		cl.ExpiresAt = &expiresAt
+	}
+	return cl
+}
+
+// SetComponentLevel overrides the log level of the component, it's reverted
+// automatically after the duration
+func SetComponentLevel(name, level string, duration time.Duration) error {
+	if _, ok := components[name]; !ok {
+		return fmt.Errorf("unknown component: %s", name)
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	if duration <= 0 {
+		return fmt.Errorf("invalid duration: %v", duration)
+	}
+
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+
+	if o, ok := overrides[name]; ok {
+		o.timer.Stop()
+	}
+	o := &levelOverride{
+		level:     lvl,
+		expiresAt: time.Now().Add(duration),
+	}
+	o.timer = time.AfterFunc(duration, func() {
+		overridesLock.Lock()
+		defer overridesLock.Unlock()
+		// the override may be replaced meanwhile
+		if overrides[name] == o {
+			deleteOverride(name)
+		}
+	})
+	overrides[name] = o
+	atomic.StoreInt32(&overrideCount, int32(len(overrides)))
+	return nil
+}
+
+// ResetComponentLevel reverts the overridden log level of the component
+func ResetComponentLevel(name string) {
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+
+	if o, ok := overrides[name]; ok {
+		o.timer.Stop()
+		deleteOverride(name)
+	}
+}
+
+// deleteOverride should be called with the lock held
+func deleteOverride(name string) {
+	delete(overrides, name)
+	atomic.StoreInt32(&overrideCount, int32(len(overrides)))
+}
+
+// componentLevel returns the overridden level of the component which the caller
+// specified by calldepth belongs to
+func componentLevel(calldepth int) (Level, bool) {
+	if atomic.LoadInt32(&overrideCount) == 0 {
+		return 0, false
+	}
+	_, file, _, ok := runtime.Caller(calldepth)
+	if !ok {
+		return 0, false
+	}
+
+	overridesLock.RLock()
+	defer overridesLock.RUnlock()
+
+	for name, o := range overrides {
+		for _, path := range components[name].paths {
+			if strings.Contains(file, "/src/"+path) {
+				return o.level, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComponentLevel(t *testing.T) {
+	// the logs of this package are treated as the ones of a component in testing
+	components["log"] = &Component{
+		Name:  "log",
+		paths: []string{"common/utils/log/"},
+	}
+	defer delete(components, "log")
+
+	buf := enter()
+	defer exit()
+
+	if err := SetComponentLevel("unknown", "debug", time.Minute); err == nil {
+		t.Error("expect error for the unknown component but got nil")
+	}
+	if err := SetComponentLevel("log", "verbose", time.Minute); err == nil {
+		t.Error("expect error for the invalid level but got nil")
+	}
+
+	if err := SetComponentLevel("log", "debug", time.Minute); err != nil {
+		t.Fatalf("failed to set the level of component: %v", err)
+	}
+	cl := GetComponentLevel(components["log"])
+	if cl.Level != "DEBUG" || cl.ExpiresAt == nil {
+		t.Errorf("unexpected level of component: %s %v", cl.Level, cl.ExpiresAt)
+	}
+	Debug(message)
+	if !strings.Contains(buf.String(), message) {
+		t.Errorf("expect the debug log of the component but got: %s", buf.String())
+	}
+
+	// the level is reverted after the duration
+	buf.Reset()
+	if err := SetComponentLevel("log", "debug", 100*time.Millisecond); err != nil {
+		t.Fatalf("failed to set the level of component: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	Debug(message)
+	if buf.String() != "" {
+		t.Errorf("unexpected message after the level is reverted: %s", buf.String())
+	}
+	if cl = GetComponentLevel(components["log"]); cl.ExpiresAt != nil {
+		t.Errorf("expect the level to be reverted but expires at %v", cl.ExpiresAt)
+	}
+
+	// the level can also quiet the component
+	if err := SetComponentLevel("log", "error", time.Minute); err != nil {
+		t.Fatalf("failed to set the level of component: %v", err)
+	}
+	Info(message)
+	if buf.String() != "" {
+		t.Errorf("unexpected info log of the quieted component: %s", buf.String())
+	}
+	ResetComponentLevel("log")
+	Info(message)
+	if !strings.Contains(buf.String(), message) {
+		t.Errorf("expect the info log after reset but got: %s", buf.String())
+	}
+}
@@ -131,7 +131,7 @@ func (l *Logger) output(record *Record) (err error) {
 
 // Debug ...
 func (l *Logger) Debug(v ...interface{}) {
-	if l.lvl <= DebugLevel {
+	if l.enabled(DebugLevel) {
 		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), DebugLevel)
 		l.output(record)
 	}
@@ -139,7 +139,7 @@ func (l *Logger) Debug(v ...interface{}) {
 
 // Debugf ...
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.lvl <= DebugLevel {
+	if l.enabled(DebugLevel) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), DebugLevel)
 		l.output(record)
 	}
@@ -147,7 +147,7 @@ func (l *Logger) Debugf(format string, v ...interface{}) {
 
 // Info ...
 func (l *Logger) Info(v ...interface{}) {
-	if l.lvl <= InfoLevel {
+	if l.enabled(InfoLevel) {
 		record := NewRecord(time.Now(), fmt.Sprint(v...), "", InfoLevel)
 		l.output(record)
 	}
@@ -155,7 +155,7 @@ func (l *Logger) Info(v ...interface{}) {
 
 // Infof ...
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.lvl <= InfoLevel {
+	if l.enabled(InfoLevel) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), "", InfoLevel)
 		l.output(record)
 	}
@@ -163,7 +163,7 @@ func (l *Logger) Infof(format string, v ...interface{}) {
 
 // Warning ...
 func (l *Logger) Warning(v ...interface{}) {
-	if l.lvl <= WarningLevel {
+	if l.enabled(WarningLevel) {
 		record := NewRecord(time.Now(), fmt.Sprint(v...), "", WarningLevel)
 		l.output(record)
 	}
@@ -171,7 +171,7 @@ func (l *Logger) Warning(v ...interface{}) {
 
 // Warningf ...
 func (l *Logger) Warningf(format string, v ...interface{}) {
-	if l.lvl <= WarningLevel {
+	if l.enabled(WarningLevel) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), "", WarningLevel)
 		l.output(record)
 	}
@@ -179,7 +179,7 @@ func (l *Logger) Warningf(format string, v ...interface{}) {
 
 // Error ...
 func (l *Logger) Error(v ...interface{}) {
-	if l.lvl <= ErrorLevel {
+	if l.enabled(ErrorLevel) {
 		record := NewRecord(time.Now(), fmt.Sprint(v...), l.getLine(), ErrorLevel)
 		l.output(record)
 	}
@@ -187,7 +187,7 @@ func (l *Logger) Error(v ...interface{}) {
 
 // Errorf ...
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.lvl <= ErrorLevel {
+	if l.enabled(ErrorLevel) {
 		record := NewRecord(time.Now(), fmt.Sprintf(format, v...), l.getLine(), ErrorLevel)
 		l.output(record)
 	}
@@ -211,6 +211,15 @@ func (l *Logger) Fatalf(format string, v ...interface{}) {
 	os.Exit(1)
 }
 
+// enabled returns whether the level is enabled for the caller, the level overridden for
+// the component of the caller takes precedence over the one of the logger
+func (l *Logger) enabled(lvl Level) bool {
+	if componentLvl, ok := componentLevel(l.callDepth); ok {
+		return componentLvl <= lvl
+	}
+	return l.lvl <= lvl
+}
+
 func (l *Logger) getLine() string {
 	if l.skipLine {
 		return ""
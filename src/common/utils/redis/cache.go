@@ -0,0 +1,157 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	beego_cache "github.com/astaxie/beego/cache"
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+// CacheAdapter is the name of the beego cache adapter supporting all the modes of redis,
+// the config is in format {"url": "<redis URL>", "key": "<collection name>"}
+const CacheAdapter = "harbor_redis"
+
+// CacheConfig returns the config of CacheAdapter with the redis URL and the collection name
+func CacheConfig(url, key string) (string, error) {
+	data, err := json.Marshal(map[string]string{
+		"url": url,
+		"key": key,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// cache is a beego cache with all the keys stored under the hash tag of the collection
+type cache struct {
+	pool   *redigo.Pool
+	prefix string
+}
+
+// newCache returns an empty cache which is initialized in StartAndGC
+func newCache() beego_cache.Cache {
+	return &cache{}
+}
+
+func (c *cache) key(key string) string {
+	return c.prefix + key
+}
+
+func (c *cache) do(cmd string, args ...interface{}) (interface{}, error) {
+	conn := c.pool.Get()
+	defer conn.Close()
+	return conn.Do(cmd, args...)
+}
+
+// Get returns the cached value, it's []byte if the key exists
+func (c *cache) Get(key string) interface{} {
+	v, err := c.do("GET", c.key(key))
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// GetMulti is a batch version of Get
+func (c *cache) GetMulti(keys []string) []interface{} {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, c.key(key))
+	}
+	values, err := redigo.Values(c.do("MGET", args...))
+	if err != nil {
+		return make([]interface{}, len(keys))
+	}
+	return values
+}
+
+// Put sets the value with the timeout
+func (c *cache) Put(key string, val interface{}, timeout time.Duration) error {
+	seconds := int64(timeout / time.Second)
+	if seconds <= 0 {
+		_, err := c.do("SET", c.key(key), val)
+		return err
+	}
+	_, err := c.do("SETEX", c.key(key), seconds, val)
+	return err
+}
+
+// Delete deletes the value
+func (c *cache) Delete(key string) error {
+	_, err := c.do("DEL", c.key(key))
+	return err
+}
+
+// Incr increases the counter
+func (c *cache) Incr(key string) error {
+	_, err := c.do("INCR", c.key(key))
+	return err
+}
+
+// Decr decreases the counter
+func (c *cache) Decr(key string) error {
+	_, err := c.do("DECR", c.key(key))
+	return err
+}
+
+// IsExist checks whether the key exists
+func (c *cache) IsExist(key string) bool {
+	exist, err := redigo.Bool(c.do("EXISTS", c.key(key)))
+	return err == nil && exist
+}
+
+// ClearAll deletes all the keys of the collection
+func (c *cache) ClearAll() error {
+	keys, err := redigo.Strings(c.do("KEYS", c.prefix+"*"))
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err = c.do("DEL", key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartAndGC initializes the cache with the config
+func (c *cache) StartAndGC(config string) error {
+	cfg := map[string]string{}
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return err
+	}
+	if len(cfg["key"]) == 0 {
+		return errors.New("the key of the cache collection is required")
+	}
+	opts, err := ParseURL(cfg["url"])
+	if err != nil {
+		return err
+	}
+	c.pool = NewPool(opts, cfg["key"])
+	c.prefix = "{" + cfg["key"] + "}:"
+
+	conn := c.pool.Get()
+	defer conn.Close()
+	return conn.Err()
+}
+
+func init() {
+	beego_cache.Register(CacheAdapter, newCache)
+}
@@ -0,0 +1,417 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redis connects to the redis in the standalone, sentinel or cluster mode.
+//
+// In the cluster mode, the connections are made to the master which owns the hash slot
+// of a hash tag specified by the caller. All the keys of the caller must contain the
+// hash tag "{<tag>}" to be hosted by the same node, so that the multi-key commands,
+// transactions and scripts keep working.
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+// the modes of redis
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+const (
+	schemeStandalone = "redis"
+	schemeSentinel   = "redis+sentinel"
+	schemeCluster    = "redis+cluster"
+	defaultPoolSize  = 100
+	// the default ports of redis and sentinel
+	defaultPort         = "6379"
+	defaultSentinelPort = "26379"
+	// the count of hash slots of redis cluster
+	slotCount = 16384
+)
+
+// Options holds the information to connect to the redis
+type Options struct {
+	Mode string
+	// the address of the standalone redis, the sentinels or the seed nodes of cluster
+	Addrs []string
+	// the name of the master monitored by the sentinels
+	MasterName string
+	Password   string
+	DB         int
+	PoolSize   int
+}
+
+// ParseURL parses the address of redis in the following formats:
+//
+//	redis://[:password@]host:port[/db]
+//	redis+sentinel://[:password@]host1:port1[,host2:port2]/master_name[/db]
+//	redis+cluster://[:password@]host1:port1[,host2:port2]
+//	host:port[,pool_size[,password[,db]]]
+//
+// the last one is the legacy format used by the session provider of beego
+func ParseURL(raw string) (*Options, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, errors.New("empty redis address")
+	}
+	i := strings.Index(raw, "://")
+	if i < 0 {
+		return parseLegacy(raw)
+	}
+
+	opts := &Options{
+		PoolSize: defaultPoolSize,
+	}
+	scheme, rest := raw[:i], raw[i+3:]
+	switch scheme {
+	case schemeStandalone:
+		opts.Mode = ModeStandalone
+	case schemeSentinel:
+		opts.Mode = ModeSentinel
+	case schemeCluster:
+		opts.Mode = ModeCluster
+	default:
+		return nil, fmt.Errorf("unsupported scheme of redis: %s", scheme)
+	}
+
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		userinfo := rest[:i]
+		rest = rest[i+1:]
+		if j := strings.Index(userinfo, ":"); j >= 0 {
+			password, err := url.PathUnescape(userinfo[j+1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid password of redis: %v", err)
+			}
+			opts.Password = password
+		}
+	}
+
+	hosts, path := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		hosts, path = rest[:i], strings.Trim(rest[i+1:], "/")
+	}
+	port := defaultPort
+	if opts.Mode == ModeSentinel {
+		port = defaultSentinelPort
+	}
+	for _, addr := range strings.Split(hosts, ",") {
+		addr, err := normalizeAddr(addr, port)
+		if err != nil {
+			return nil, err
+		}
+		opts.Addrs = append(opts.Addrs, addr)
+	}
+
+	segments := []string{}
+	if len(path) > 0 {
+		segments = strings.Split(path, "/")
+	}
+	if opts.Mode == ModeSentinel {
+		if len(segments) == 0 || len(segments[0]) == 0 {
+			return nil, errors.New("the master name is required in the sentinel mode")
+		}
+		opts.MasterName, segments = segments[0], segments[1:]
+	}
+	if len(segments) > 1 {
+		return nil, fmt.Errorf("invalid path of redis URL: %s", path)
+	}
+	if len(segments) == 1 {
+		db, err := strconv.Atoi(segments[0])
+		if err != nil || db < 0 {
+			return nil, fmt.Errorf("invalid database index of redis: %s", segments[0])
+		}
+		opts.DB = db
+	}
+	if opts.Mode == ModeStandalone && len(opts.Addrs) > 1 {
+		return nil, errors.New("only one address is allowed in the standalone mode")
+	}
+	if opts.Mode == ModeCluster && opts.DB != 0 {
+		return nil, errors.New("the database index isn't supported in the cluster mode")
+	}
+	return opts, nil
+}
+
+func parseLegacy(raw string) (*Options, error) {
+	segments := strings.Split(raw, ",")
+	addr, err := normalizeAddr(segments[0], defaultPort)
+	if err != nil {
+		return nil, err
+	}
+	opts := &Options{
+		Mode:     ModeStandalone,
+		Addrs:    []string{addr},
+		PoolSize: defaultPoolSize,
+	}
+	if len(segments) > 1 {
+		if size, err := strconv.Atoi(segments[1]); err == nil && size > 0 {
+			opts.PoolSize = size
+		}
+	}
+	if len(segments) > 2 {
+		opts.Password = segments[2]
+	}
+	if len(segments) > 3 && len(segments[3]) > 0 {
+		db, err := strconv.Atoi(segments[3])
+		if err != nil || db < 0 {
+			return nil, fmt.Errorf("invalid database index of redis: %s", segments[3])
+		}
+		opts.DB = db
+	}
+	return opts, nil
+}
+
+// normalizeAddr appends the default port to the address if it's missing
+func normalizeAddr(addr, port string) (string, error) {
+	if len(addr) == 0 {
+		return "", errors.New("empty address of redis")
+	}
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr, nil
+	}
+	addr = net.JoinHostPort(strings.Trim(addr, "[]"), port)
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", fmt.Errorf("invalid address of redis %q: %v", addr, err)
+	}
+	return addr, nil
+}
+
+// Dial connects to the redis, in the sentinel mode the master is resolved by the
+// sentinels and in the cluster mode the master owning the slot of hashTag is connected
+func Dial(opts *Options, hashTag string, options ...redigo.DialOption) (redigo.Conn, error) {
+	addr, err := resolve(opts, hashTag, options...)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, redigo.DialPassword(opts.Password))
+	if opts.Mode != ModeCluster {
+		options = append(options, redigo.DialDatabase(opts.DB))
+	}
+	c, err := redigo.Dial("tcp", addr, options...)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Mode == ModeSentinel {
+		if err = checkRole(c); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// TestConn checks whether the connection is still usable, as the master may change
+// after the failover in the sentinel mode or the slot may be migrated in the cluster mode
+func TestConn(opts *Options, c redigo.Conn, hashTag string) error {
+	switch opts.Mode {
+	case ModeSentinel:
+		return checkRole(c)
+	case ModeCluster:
+		// a MOVED error is returned if the node doesn't own the slot anymore
+		_, err := c.Do("EXISTS", "{"+hashTag+"}")
+		return err
+	default:
+		_, err := c.Do("PING")
+		return err
+	}
+}
+
+// NewPool returns a pool of connections to the redis, see Dial for the hashTag
+func NewPool(opts *Options, hashTag string) *redigo.Pool {
+	return &redigo.Pool{
+		MaxIdle:     opts.PoolSize,
+		MaxActive:   opts.PoolSize,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redigo.Conn, error) {
+			return Dial(opts, hashTag)
+		},
+		TestOnBorrow: func(c redigo.Conn, t time.Time) error {
+			if time.Since(t) < time.Minute {
+				return nil
+			}
+			return TestConn(opts, c, hashTag)
+		},
+	}
+}
+
+func resolve(opts *Options, hashTag string, options ...redigo.DialOption) (string, error) {
+	switch opts.Mode {
+	case ModeSentinel:
+		return resolveMaster(opts, options...)
+	case ModeCluster:
+		return resolveSlotOwner(opts, Slot("{"+hashTag+"}"), options...)
+	default:
+		return opts.Addrs[0], nil
+	}
+}
+
+// resolveMaster asks the sentinels one by one for the address of the master
+func resolveMaster(opts *Options, options ...redigo.DialOption) (string, error) {
+	var lastErr error
+	for _, addr := range opts.Addrs {
+		c, err := redigo.Dial("tcp", addr, options...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		reply, err := redigo.Strings(c.Do("SENTINEL", "get-master-addr-by-name", opts.MasterName))
+		c.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(reply) != 2 {
+			lastErr = fmt.Errorf("master %s is unknown by sentinel %s", opts.MasterName, addr)
+			continue
+		}
+		return net.JoinHostPort(reply[0], reply[1]), nil
+	}
+	return "", fmt.Errorf("failed to resolve the master %s by sentinels: %v", opts.MasterName, lastErr)
+}
+
+// resolveSlotOwner asks the nodes of cluster one by one for the master owning the slot
+func resolveSlotOwner(opts *Options, slot int, options ...redigo.DialOption) (string, error) {
+	var lastErr error
+	options = append(options, redigo.DialPassword(opts.Password))
+	for _, addr := range opts.Addrs {
+		c, err := redigo.Dial("tcp", addr, options...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		reply, err := redigo.Values(c.Do("CLUSTER", "SLOTS"))
+		c.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		owner, err := slotOwner(reply, slot)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return owner, nil
+	}
+	return "", fmt.Errorf("failed to resolve the owner of slot %d: %v", slot, lastErr)
+}
+
+// slotOwner finds the master of the slot from the reply of "CLUSTER SLOTS", each item
+// of which is in format [start, end, [ip, port, ...], replicas...]
+func slotOwner(reply []interface{}, slot int) (string, error) {
+	for _, item := range reply {
+		r, err := redigo.Values(item, nil)
+		if err != nil || len(r) < 3 {
+			return "", fmt.Errorf("invalid reply of cluster slots: %v", item)
+		}
+		start, err := redigo.Int(r[0], nil)
+		if err != nil {
+			return "", err
+		}
+		end, err := redigo.Int(r[1], nil)
+		if err != nil {
+			return "", err
+		}
+		if slot < start || slot > end {
+			continue
+		}
+		node, err := redigo.Values(r[2], nil)
+		if err != nil || len(node) < 2 {
+			return "", fmt.Errorf("invalid node in the reply of cluster slots: %v", r[2])
+		}
+		ip, err := redigo.String(node[0], nil)
+		if err != nil {
+			return "", err
+		}
+		port, err := redigo.Int(node[1], nil)
+		if err != nil {
+			return "", err
+		}
+		return net.JoinHostPort(ip, strconv.Itoa(port)), nil
+	}
+	return "", fmt.Errorf("slot %d isn't served by the cluster", slot)
+}
+
+func checkRole(c redigo.Conn) error {
+	reply, err := redigo.Values(c.Do("ROLE"))
+	if err != nil {
+		return err
+	}
+	if len(reply) == 0 {
+		return errors.New("empty reply of role")
+	}
+	role, err := redigo.String(reply[0], nil)
+	if err != nil {
+		return err
+	}
+	if role != "master" {
+		return fmt.Errorf("the role of the connected redis is %s rather than master", role)
+	}
+	return nil
+}
+
+// ClusterNamespace returns the namespace used as the prefix of the keys in the cluster mode and
+// the hash tag in it, so that all the keys are hosted by the same node. The namespace is wrapped
+// in a hash tag unless it contains one already, an error is returned if the braces in it don't
+// make a valid hash tag
+func ClusterNamespace(namespace string) (string, string, error) {
+	start := strings.Index(namespace, "{")
+	if start < 0 {
+		if strings.Contains(namespace, "}") {
+			return "", "", fmt.Errorf("invalid hash tag in namespace %s", namespace)
+		}
+		return "{" + namespace + "}", namespace, nil
+	}
+	end := strings.Index(namespace[start+1:], "}")
+	if end <= 0 {
+		return "", "", fmt.Errorf("invalid hash tag in namespace %s, it must be a non-empty \"{...}\"", namespace)
+	}
+	return namespace, namespace[start+1 : start+1+end], nil
+}
+
+// Slot returns the hash slot of the key in redis cluster, only the hash tag is
+// hashed if the key contains one
+func Slot(key string) int {
+	if start := strings.Index(key, "{"); start >= 0 {
+		if end := strings.Index(key[start+1:], "}"); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % slotCount)
+}
+
+// crc16 implements the CRC16-CCITT (XMODEM) used by redis cluster
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
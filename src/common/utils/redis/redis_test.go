@@ -0,0 +1,167 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	cases := []struct {
+		url   string
+		opts  *Options
+		isErr bool
+	}{
+		{
+			url: "redis:6379,100,password,2",
+			opts: &Options{
+				Mode:     ModeStandalone,
+				Addrs:    []string{"redis:6379"},
+				Password: "password",
+				DB:       2,
+				PoolSize: 100,
+			},
+		},
+		{
+			url: "redis:6379",
+			opts: &Options{
+				Mode:     ModeStandalone,
+				Addrs:    []string{"redis:6379"},
+				PoolSize: defaultPoolSize,
+			},
+		},
+		{
+			url: "redis://:pass%40word@redis:6379/1",
+			opts: &Options{
+				Mode:     ModeStandalone,
+				Addrs:    []string{"redis:6379"},
+				Password: "pass@word",
+				DB:       1,
+				PoolSize: defaultPoolSize,
+			},
+		},
+		{
+			url: "redis+sentinel://:password@sentinel1:26379,sentinel2:26379/mymaster/2",
+			opts: &Options{
+				Mode:       ModeSentinel,
+				Addrs:      []string{"sentinel1:26379", "sentinel2:26379"},
+				MasterName: "mymaster",
+				Password:   "password",
+				DB:         2,
+				PoolSize:   defaultPoolSize,
+			},
+		},
+		{
+			url: "redis+cluster://node1:6379,node2:6379",
+			opts: &Options{
+				Mode:     ModeCluster,
+				Addrs:    []string{"node1:6379", "node2:6379"},
+				PoolSize: defaultPoolSize,
+			},
+		},
+		// empty
+		{url: "", isErr: true},
+		// unknown scheme
+		{url: "http://redis:6379", isErr: true},
+		{
+			url: "redis+sentinel://sentinel1,sentinel2:26380/mymaster",
+			opts: &Options{
+				Mode:       ModeSentinel,
+				Addrs:      []string{"sentinel1:26379", "sentinel2:26380"},
+				MasterName: "mymaster",
+				PoolSize:   defaultPoolSize,
+			},
+		},
+		{
+			url: "redis://redis",
+			opts: &Options{
+				Mode:     ModeStandalone,
+				Addrs:    []string{"redis:6379"},
+				PoolSize: defaultPoolSize,
+			},
+		},
+		// empty address
+		{url: "redis+cluster://node1:6379,", isErr: true},
+		// multiple addresses in standalone mode
+		{url: "redis://redis1:6379,redis2:6379", isErr: true},
+		// no master name
+		{url: "redis+sentinel://sentinel:26379", isErr: true},
+		// database index in cluster mode
+		{url: "redis+cluster://node1:6379/1", isErr: true},
+		// invalid database index
+		{url: "redis://redis:6379/a", isErr: true},
+	}
+
+	for _, c := range cases {
+		opts, err := ParseURL(c.url)
+		if c.isErr {
+			assert.NotNil(t, err, c.url)
+			continue
+		}
+		require.Nil(t, err, c.url)
+		assert.Equal(t, c.opts, opts, c.url)
+	}
+}
+
+func TestSlot(t *testing.T) {
+	assert.Equal(t, 12739, Slot("123456789"))
+	assert.Equal(t, Slot("user1000"), Slot("{user1000}.following"))
+	assert.Equal(t, Slot("{}.following"), Slot("{}.following"))
+	assert.NotEqual(t, Slot("{a}:1"), Slot("{b}:1"))
+}
+
+func TestClusterNamespace(t *testing.T) {
+	// the default namespace of jobservice
+	namespace, tag, err := ClusterNamespace("harbor_job_service_namespace")
+	require.Nil(t, err)
+	assert.Equal(t, "{harbor_job_service_namespace}", namespace)
+	assert.Equal(t, "harbor_job_service_namespace", tag)
+	assert.Equal(t, Slot(namespace+":jobs"), Slot("{"+tag+"}"))
+
+	namespace, tag, err = ClusterNamespace("{harbor}_job_service")
+	require.Nil(t, err)
+	assert.Equal(t, "{harbor}_job_service", namespace)
+	assert.Equal(t, "harbor", tag)
+
+	for _, ns := range []string{"{}harbor", "{harbor", "harbor}", "}{harbor"} {
+		_, _, err = ClusterNamespace(ns)
+		assert.NotNil(t, err, ns)
+	}
+}
+
+func TestSlotOwner(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{int64(0), int64(8191),
+			[]interface{}{[]byte("10.0.0.1"), int64(6379), []byte("id1")},
+			[]interface{}{[]byte("10.0.0.3"), int64(6379), []byte("id3")},
+		},
+		[]interface{}{int64(8192), int64(16383),
+			[]interface{}{[]byte("10.0.0.2"), int64(6380), []byte("id2")},
+		},
+	}
+	owner, err := slotOwner(reply, 100)
+	require.Nil(t, err)
+	assert.Equal(t, "10.0.0.1:6379", owner)
+
+	owner, err = slotOwner(reply, 12739)
+	require.Nil(t, err)
+	assert.Equal(t, "10.0.0.2:6380", owner)
+
+	_, err = slotOwner(reply[:1], 12739)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,178 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/astaxie/beego/session"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+const (
+	// SessionProvider is the name of the beego session provider supporting all the
+	// modes of redis, the config of the provider is the redis URL
+	SessionProvider = "harbor_redis"
+	sessionHashTag  = "harbor_session"
+)
+
+// sessionStore holds the values of a session
+type sessionStore struct {
+	provider *sessionProvider
+	sid      string
+	lock     sync.RWMutex
+	values   map[interface{}]interface{}
+}
+
+// Set sets the value
+func (s *sessionStore) Set(key, value interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.values[key] = value
+	return nil
+}
+
+// Get gets the value
+func (s *sessionStore) Get(key interface{}) interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.values[key]
+}
+
+// Delete deletes the value
+func (s *sessionStore) Delete(key interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+// Flush deletes all the values
+func (s *sessionStore) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.values = map[interface{}]interface{}{}
+	return nil
+}
+
+// SessionID returns the ID of session
+func (s *sessionStore) SessionID() string {
+	return s.sid
+}
+
+// SessionRelease saves the values into redis
+func (s *sessionStore) SessionRelease(w http.ResponseWriter) {
+	s.lock.RLock()
+	data, err := session.EncodeGob(s.values)
+	s.lock.RUnlock()
+	if err != nil {
+		log.Errorf("failed to encode session %s: %v", s.sid, err)
+		return
+	}
+	if _, err = s.provider.do("SETEX", s.provider.key(s.sid), s.provider.maxLifetime, string(data)); err != nil {
+		log.Errorf("failed to save session %s: %v", s.sid, err)
+	}
+}
+
+// sessionProvider stores the sessions under the same hash tag, so that the
+// sessions can be renamed in the cluster mode
+type sessionProvider struct {
+	pool        *redigo.Pool
+	maxLifetime int64
+}
+
+func (p *sessionProvider) key(sid string) string {
+	return "{" + sessionHashTag + "}:" + sid
+}
+
+func (p *sessionProvider) do(cmd string, args ...interface{}) (interface{}, error) {
+	conn := p.pool.Get()
+	defer conn.Close()
+	return conn.Do(cmd, args...)
+}
+
+// SessionInit initializes the provider with the redis URL
+func (p *sessionProvider) SessionInit(maxLifetime int64, config string) error {
+	opts, err := ParseURL(config)
+	if err != nil {
+		return err
+	}
+	p.maxLifetime = maxLifetime
+	p.pool = NewPool(opts, sessionHashTag)
+
+	conn := p.pool.Get()
+	defer conn.Close()
+	return conn.Err()
+}
+
+// SessionRead reads the session from redis, an empty session is returned if it doesn't exist
+func (p *sessionProvider) SessionRead(sid string) (session.Store, error) {
+	values := map[interface{}]interface{}{}
+	data, err := redigo.Bytes(p.do("GET", p.key(sid)))
+	if err != nil && err != redigo.ErrNil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if values, err = session.DecodeGob(data); err != nil {
+			return nil, err
+		}
+	}
+	return &sessionStore{
+		provider: p,
+		sid:      sid,
+		values:   values,
+	}, nil
+}
+
+// SessionExist checks whether the session exists
+func (p *sessionProvider) SessionExist(sid string) bool {
+	exist, err := redigo.Bool(p.do("EXISTS", p.key(sid)))
+	return err == nil && exist
+}
+
+// SessionRegenerate moves the values of the old session to the new one
+func (p *sessionProvider) SessionRegenerate(oldsid, sid string) (session.Store, error) {
+	if p.SessionExist(oldsid) {
+		if _, err := p.do("RENAME", p.key(oldsid), p.key(sid)); err != nil {
+			return nil, err
+		}
+		if _, err := p.do("EXPIRE", p.key(sid), p.maxLifetime); err != nil {
+			return nil, err
+		}
+	} else if _, err := p.do("SET", p.key(sid), "", "EX", p.maxLifetime); err != nil {
+		return nil, err
+	}
+	return p.SessionRead(sid)
+}
+
+// SessionDestroy deletes the session
+func (p *sessionProvider) SessionDestroy(sid string) error {
+	_, err := p.do("DEL", p.key(sid))
+	return err
+}
+
+// SessionGC does nothing as the sessions expire in redis
+func (p *sessionProvider) SessionGC() {}
+
+// SessionAll isn't supported
+func (p *sessionProvider) SessionAll() int {
+	return 0
+}
+
+func init() {
+	session.Register(SessionProvider, &sessionProvider{})
+}
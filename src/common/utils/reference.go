@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+)
+
+// ImageReference is the reference of an image in Harbor parsed from the forms accepted by
+// the docker client
+type ImageReference struct {
+	Project    string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseImageReference parses the reference of an image, e.g. "project/repo:tag",
+// "host/project/repo@sha256:..." or the short name "repo". The registry host must be the
+// one of Harbor if it's specified, and the short names without project are resolved into
+// the default project. The tag is "latest" if neither the tag nor the digest is specified
+func ParseImageReference(ref, host, defaultProject string) (*ImageReference, error) {
+	name := strings.TrimSpace(ref)
+	if len(name) == 0 {
+		return nil, fmt.Errorf("empty reference")
+	}
+	// the first component is the registry host if it looks like one, as the docker client does
+	if i := strings.Index(name, "/"); i > 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			if !strings.EqualFold(first, host) {
+				return nil, fmt.Errorf("the reference %s doesn't point to the registry %s", ref, host)
+			}
+			name = name[i+1:]
+		}
+	}
+	if !strings.Contains(strings.SplitN(name, "@", 2)[0], "/") {
+		if len(defaultProject) == 0 {
+			return nil, fmt.Errorf("the reference %s has no project", ref)
+		}
+		name = defaultProject + "/" + name
+	}
+
+	parsed, err := reference.Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %v", ref, err)
+	}
+	named, ok := parsed.(reference.Named)
+	if !ok {
+		return nil, fmt.Errorf("invalid reference %s: no repository", ref)
+	}
+	img := &ImageReference{
+		Repository: named.Name(),
+	}
+	img.Project, _ = ParseRepository(img.Repository)
+	if tagged, ok := parsed.(reference.Tagged); ok {
+		img.Tag = tagged.Tag()
+	}
+	if digested, ok := parsed.(reference.Digested); ok {
+		img.Digest = digested.Digest().String()
+	}
+	if len(img.Tag) == 0 && len(img.Digest) == 0 {
+		img.Tag = "latest"
+	}
+	return img, nil
+}
@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageReference(t *testing.T) {
+	digest := "sha256:0b8a2d3c5f8bd2eb7e4c8b3d1e0a8e7b4f6c2d9a1b5e3f7c8d0a2b4c6e8f0a1b"
+	cases := []struct {
+		ref      string
+		expected *ImageReference
+	}{
+		{"myproject/app:1.2", &ImageReference{"myproject", "myproject/app", "1.2", ""}},
+		{"myproject/app", &ImageReference{"myproject", "myproject/app", "latest", ""}},
+		{"app", &ImageReference{"library", "library/app", "latest", ""}},
+		{"app@" + digest, &ImageReference{"library", "library/app", "", digest}},
+		{"harbor.example.com/myproject/team/app:1.2@" + digest,
+			&ImageReference{"myproject", "myproject/team/app", "1.2", digest}},
+		{"HARBOR.example.com/app:1.2", &ImageReference{"library", "library/app", "1.2", ""}},
+	}
+	for _, c := range cases {
+		img, err := ParseImageReference(c.ref, "harbor.example.com", "library")
+		require.Nil(t, err, c.ref)
+		assert.Equal(t, c.expected, img, c.ref)
+	}
+
+	for _, ref := range []string{
+		"",
+		"docker.io/library/app:1.2",
+		"myproject/App:1.2",
+		"myproject/app:",
+		"myproject/app@sha256:invalid",
+	} {
+		_, err := ParseImageReference(ref, "harbor.example.com", "library")
+		assert.NotNil(t, err, ref)
+	}
+
+	// no default project
+	_, err := ParseImageReference("app", "harbor.example.com", "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,46 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// NewAuthorizer returns the authorizer of the auth scheme for the registries, the scheme
+// is one of models.RepTargetAuthBearer(default), models.RepTargetAuthBasic and
+// models.RepTargetAuthAnonymous. The token is requested from the realm if it's set
+// rather than the one in the challenge of the registry
+func NewAuthorizer(client *http.Client, authScheme, username, password, realm string) (modifier.Modifier, error) {
+	var tokenService []string
+	if len(realm) > 0 {
+		tokenService = append(tokenService, realm)
+	}
+	switch authScheme {
+	case "", models.RepTargetAuthBearer:
+		return NewStandardTokenAuthorizer(client, NewBasicAuthCredential(username, password), tokenService...), nil
+	case models.RepTargetAuthBasic:
+		// the registries supporting only basic auth, e.g. Nexus and Artifactory with
+		// the token auth disabled, return no bearer challenge
+		return NewBasicAuthCredential(username, password), nil
+	case models.RepTargetAuthAnonymous:
+		return NewStandardTokenAuthorizer(client, nil, tokenService...), nil
+	default:
+		return nil, fmt.Errorf("unsupported auth scheme %s", authScheme)
+	}
+}
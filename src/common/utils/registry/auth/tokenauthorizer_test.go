@@ -220,3 +220,58 @@ func TestUserAgentModifier(t *testing.T) {
 		t.Errorf("expect request to have header User-Agent=%s, but got User-Agent=%s", agent, actual)
 	}
 }
+
+func TestGetTokenQuirks(t *testing.T) {
+	tokenServer := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: "/oauth2/token",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"access_token":"token"}`),
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: "/empty",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{}`),
+			}),
+		})
+	defer tokenServer.Close()
+
+	tk, err := getToken(http.DefaultClient, nil, tokenServer.URL+"/oauth2/token", "registry", nil)
+	require.Nil(t, err)
+	assert.Equal(t, "token", tk.Token)
+	assert.Equal(t, defaultExpiresIn, tk.ExpiresIn)
+	issuedAt, err := time.Parse(time.RFC3339, tk.IssuedAt)
+	require.Nil(t, err)
+	assert.True(t, time.Since(issuedAt) < time.Minute)
+
+	_, err = getToken(http.DefaultClient, nil, tokenServer.URL+"/empty", "registry", nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	authorizer, err := NewAuthorizer(http.DefaultClient, models.RepTargetAuthBasic, "user", "pass", "")
+	require.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "http://registry/v2/", nil)
+	require.Nil(t, err)
+	require.Nil(t, authorizer.Modify(req))
+	username, password, ok := req.BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pass", password)
+
+	authorizer, err = NewAuthorizer(http.DefaultClient, models.RepTargetAuthBearer, "user", "pass", "http://auth/token")
+	require.Nil(t, err)
+	generator := authorizer.(*tokenAuthorizer).generator.(*standardTokenGenerator)
+	assert.Equal(t, "http://auth/token", generator.realm)
+	assert.NotNil(t, generator.credential)
+
+	authorizer, err = NewAuthorizer(http.DefaultClient, models.RepTargetAuthAnonymous, "", "", "")
+	require.Nil(t, err)
+	assert.Nil(t, authorizer.(*tokenAuthorizer).generator.(*standardTokenGenerator).credential)
+
+	_, err = NewAuthorizer(http.DefaultClient, "digest", "", "", "")
+	assert.NotNil(t, err)
+}
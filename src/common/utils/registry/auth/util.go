@@ -16,9 +16,11 @@ package auth
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/docker/distribution/registry/auth/token"
 	commonhttp "github.com/goharbor/harbor/src/common/http"
@@ -28,6 +30,8 @@ import (
 
 const (
 	service = "harbor-registry"
+	// the default expiration of the token defined in the spec when "expires_in" is missing
+	defaultExpiresIn = 60
 )
 
 // GetToken requests a token against the endpoint using credetial provided
@@ -84,5 +88,19 @@ func getToken(client *http.Client, credential Credential, realm, service string,
 		return nil, err
 	}
 
+	// handle the quirks of the token services which aren't fully compatible with the spec
+	if len(token.Token) == 0 {
+		token.Token = token.AccessToken
+	}
+	if len(token.Token) == 0 {
+		return nil, fmt.Errorf("no token returned by %s", realm)
+	}
+	if token.ExpiresIn <= 0 {
+		token.ExpiresIn = defaultExpiresIn
+	}
+	if _, err = time.Parse(time.RFC3339, token.IssuedAt); err != nil {
+		token.IssuedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
 	return token, nil
 }
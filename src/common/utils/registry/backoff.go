@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+var (
+	// the base and max interval of the backoff, the interval is doubled after every retry
+	backoffBase = time.Second
+	backoffMax  = time.Minute
+)
+
+// BackoffTransport retries the requests with exponential backoff when the registry responds
+// with 429 or 5xx, the "Retry-After" header takes precedence over the calculated interval.
+// The requests whose body can't be replayed, e.g. the blob uploading, aren't retried
+type BackoffTransport struct {
+	transport http.RoundTripper
+	retries   int
+}
+
+// NewBackoffTransport returns a BackoffTransport retrying at most the specified times
+func NewBackoffTransport(transport http.RoundTripper, retries int) *BackoffTransport {
+	return &BackoffTransport{
+		transport: transport,
+		retries:   retries,
+	}
+}
+
+// RoundTrip ...
+func (b *BackoffTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := b.transport.RoundTrip(req)
+		if err != nil || !retryable(resp.StatusCode) || attempt >= b.retries ||
+			(req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		interval := backoffInterval(resp, attempt)
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		log.Warningf("%d | %s %s, retry in %v", resp.StatusCode, req.Method, req.URL.String(), interval)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r := *req
+			r.Body = body
+			req = &r
+		}
+	}
+}
+
+func retryable(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError &&
+		code != http.StatusNotImplemented && code != http.StatusHTTPVersionNotSupported
+}
+
+func backoffInterval(resp *http.Response, attempt int) time.Duration {
+	interval := backoffMax
+	if attempt < 16 {
+		interval = backoffBase << uint(attempt)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		interval = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(resp.Header.Get("Retry-After")); err == nil {
+		interval = time.Until(t)
+	}
+	if interval > backoffMax {
+		interval = backoffMax
+	}
+	if interval < 0 {
+		interval = 0
+	}
+	return interval
+}
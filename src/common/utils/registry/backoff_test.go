@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffTransport(t *testing.T) {
+	base := backoffBase
+	backoffBase = time.Millisecond
+	defer func() {
+		backoffBase = base
+	}()
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: NewBackoffTransport(http.DefaultTransport, 3),
+	}
+	req, err := http.NewRequest(http.MethodPut, server.URL, bytes.NewReader([]byte("manifest")))
+	require.Nil(t, err)
+	resp, err := client.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{"manifest", "manifest", "manifest"}, bodies)
+
+	// stop retrying when the retries are exhausted
+	bodies = nil
+	client.Transport = NewBackoffTransport(http.DefaultTransport, 1)
+	resp, err = client.Get(server.URL)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 2, len(bodies))
+
+	// the body which can't be replayed isn't retried
+	bodies = nil
+	client.Transport = NewBackoffTransport(http.DefaultTransport, 3)
+	req, err = http.NewRequest(http.MethodPut, server.URL, ioutil.NopCloser(bytes.NewReader([]byte("blob"))))
+	require.Nil(t, err)
+	resp, err = client.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 1, len(bodies))
+}
+
+func TestBackoffInterval(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{},
+	}
+	assert.Equal(t, time.Second, backoffInterval(resp, 0))
+	assert.Equal(t, 4*time.Second, backoffInterval(resp, 2))
+	assert.Equal(t, time.Minute, backoffInterval(resp, 10))
+	assert.Equal(t, time.Minute, backoffInterval(resp, 100))
+
+	resp.Header.Set("Retry-After", "5")
+	assert.Equal(t, 5*time.Second, backoffInterval(resp, 0))
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, time.Minute, backoffInterval(resp, 0))
+
+	assert.True(t, retryable(http.StatusTooManyRequests))
+	assert.True(t, retryable(http.StatusServiceUnavailable))
+	assert.False(t, retryable(http.StatusNotImplemented))
+	assert.False(t, retryable(http.StatusNotFound))
+}
@@ -0,0 +1,308 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// the states of the breakers
+const (
+	BreakerStateClosed = "closed"
+	BreakerStateOpen   = "open"
+)
+
+const (
+	// DefaultBreakerThreshold is the default count of the consecutive failures opening the breaker
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerProbeInterval is the default interval between two probes of the open breaker
+	DefaultBreakerProbeInterval = 30 * time.Second
+)
+
+var (
+	// the threshold and interval of the breakers created, replaced in testing
+	breakerThreshold     = DefaultBreakerThreshold
+	breakerProbeInterval = DefaultBreakerProbeInterval
+
+	breakers     = map[string]*Breaker{}
+	breakersLock sync.Mutex
+)
+
+// BreakerStatus is the status of the breaker exposed in the health API
+type BreakerStatus struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Failures  int        `json:"failures"`
+	LastError string     `json:"last_error,omitempty"`
+	OpenTime  *time.Time `json:"open_time,omitempty"`
+}
+
+// BreakerOpenError is returned instead of sending the request when the breaker is open
+type BreakerOpenError struct {
+	Name      string
+	OpenTime  time.Time
+	LastError string
+	// RetryAfter is the interval before the next probe of the backend
+	RetryAfter time.Duration
+}
+
+func (b *BreakerOpenError) Error() string {
+	return fmt.Sprintf("the backend %s is unavailable since %s: %s", b.Name,
+		b.OpenTime.UTC().Format(time.RFC3339), b.LastError)
+}
+
+// IsBreakerOpen returns the BreakerOpenError if the error is, or is wrapped by the HTTP client from, one
+func IsBreakerOpen(err error) (*BreakerOpenError, bool) {
+	if e, ok := err.(*url.Error); ok {
+		err = e.Err
+	}
+	e, ok := err.(*BreakerOpenError)
+	return e, ok
+}
+
+// Breaker stops sending the requests to the backend after the consecutive failures reach the
+// threshold, and probes the backend in background until it recovers
+type Breaker struct {
+	name      string
+	probe     func() error
+	threshold int
+	interval  time.Duration
+	state     string
+	failures  int
+	lastError string
+	openTime  time.Time
+	probeTime time.Time
+	lock      sync.Mutex
+}
+
+// GetBreaker returns the breaker of the backend with the name, it's created with the probe if
+// it doesn't exist. The breakers are shared in the process so all the clients of the same
+// backend see the same state
+func GetBreaker(name string, probe func() error) *Breaker {
+	breakersLock.Lock()
+	defer breakersLock.Unlock()
+	b, exist := breakers[name]
+	if !exist {
+		b = &Breaker{
+			name:      name,
+			probe:     probe,
+			threshold: breakerThreshold,
+			interval:  breakerProbeInterval,
+			state:     BreakerStateClosed,
+		}
+		breakers[name] = b
+	}
+	return b
+}
+
+// Configure sets the count of the consecutive failures opening the breaker and the interval
+// between two probes, the non-positive values are ignored
+func (b *Breaker) Configure(threshold int, interval time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if threshold > 0 {
+		b.threshold = threshold
+	}
+	if interval > 0 {
+		b.interval = interval
+	}
+}
+
+// ListBreakerStatus returns the status of all the breakers sorted by name
+func ListBreakerStatus() []*BreakerStatus {
+	breakersLock.Lock()
+	list := make([]*Breaker, 0, len(breakers))
+	for _, b := range breakers {
+		list = append(list, b)
+	}
+	breakersLock.Unlock()
+
+	statuses := make([]*BreakerStatus, 0, len(list))
+	for _, b := range list {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Status returns the current status of the breaker
+func (b *Breaker) Status() *BreakerStatus {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	status := &BreakerStatus{
+		Name:      b.name,
+		State:     b.state,
+		Failures:  b.failures,
+		LastError: b.lastError,
+	}
+	if b.state == BreakerStateOpen {
+		openTime := b.openTime
+		status.OpenTime = &openTime
+	}
+	return status
+}
+
+// Allow returns nil if the request can be sent to the backend, otherwise the BreakerOpenError
+func (b *Breaker) Allow() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.state != BreakerStateOpen {
+		return nil
+	}
+	retryAfter := time.Until(b.probeTime.Add(b.interval))
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	return &BreakerOpenError{
+		Name:       b.name,
+		OpenTime:   b.openTime,
+		LastError:  b.lastError,
+		RetryAfter: retryAfter,
+	}
+}
+
+// Succeed resets the count of the consecutive failures
+func (b *Breaker) Succeed() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.state == BreakerStateClosed {
+		b.failures = 0
+	}
+}
+
+// Fail counts the failure and opens the breaker when the count reaches the threshold
+func (b *Breaker) Fail(err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.state == BreakerStateOpen {
+		return
+	}
+	b.failures++
+	b.lastError = err.Error()
+	if b.failures < b.threshold {
+		return
+	}
+	b.state = BreakerStateOpen
+	b.openTime = time.Now()
+	b.probeTime = b.openTime
+	log.Warningf("the breaker of %s is open after %d failures: %s", b.name, b.failures, b.lastError)
+	go b.probeUntilRecovered()
+}
+
+// probeUntilRecovered probes the backend every interval and closes the breaker once a
+// probe succeeds
+func (b *Breaker) probeUntilRecovered() {
+	for {
+		b.lock.Lock()
+		interval := b.interval
+		b.lock.Unlock()
+		time.Sleep(interval)
+		err := b.probe()
+		b.lock.Lock()
+		b.probeTime = time.Now()
+		if err != nil {
+			b.lastError = err.Error()
+			b.lock.Unlock()
+			log.Debugf("the probe of %s failed: %v", b.name, err)
+			continue
+		}
+		b.state = BreakerStateClosed
+		b.failures = 0
+		b.lastError = ""
+		b.lock.Unlock()
+		log.Infof("the breaker of %s is closed as the backend recovered", b.name)
+		return
+	}
+}
+
+// backendFailure returns whether the status code of the response means the backend, rather
+// than the request, fails. 500 is counted as the registry returns it when its storage is down,
+// the failures of single objects, e.g. a corrupted blob, reset by the other responses don't
+// reach the threshold
+func backendFailure(code int) bool {
+	return code == http.StatusInternalServerError || code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// ProbeURL returns the probe sending the GET request to the URL with the transport, any
+// response other than the backend failures means the backend is up. The URL should be served
+// by reading the storage of the backend, e.g. the catalog of registry, so that the probe
+// fails when only the storage is down
+func ProbeURL(transport http.RoundTripper, url string) func() error {
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   10 * time.Second,
+	}
+	return func() error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if backendFailure(resp.StatusCode) {
+			return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+		return nil
+	}
+}
+
+// BreakerTransport rejects the requests with BreakerOpenError when the breaker is open, and
+// counts the network errors and the backend failures of the responses into the breaker, any
+// other response resets the count
+type BreakerTransport struct {
+	transport http.RoundTripper
+	breaker   *Breaker
+}
+
+// NewBreakerTransport returns a BreakerTransport of the breaker
+func NewBreakerTransport(transport http.RoundTripper, breaker *Breaker) *BreakerTransport {
+	return &BreakerTransport{
+		transport: transport,
+		breaker:   breaker,
+	}
+}
+
+// RoundTrip ...
+func (b *BreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := b.breaker.Allow(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := b.transport.RoundTrip(req)
+	switch {
+	case err != nil:
+		// the requests canceled by the clients don't mean the backend fails
+		if req.Context().Err() != context.Canceled {
+			b.breaker.Fail(err)
+		}
+	case backendFailure(resp.StatusCode):
+		b.breaker.Fail(fmt.Errorf("%d | %s %s", resp.StatusCode, req.Method, req.URL.Path))
+	default:
+		b.breaker.Succeed()
+	}
+	return resp, err
+}
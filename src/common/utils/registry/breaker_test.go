@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakerTransport(t *testing.T) {
+	threshold, interval := breakerThreshold, breakerProbeInterval
+	breakerThreshold, breakerProbeInterval = 2, 10*time.Millisecond
+	defer func() {
+		breakerThreshold, breakerProbeInterval = threshold, interval
+	}()
+
+	var down int32 = 1
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	breaker := GetBreaker("test-backend", ProbeURL(http.DefaultTransport, server.URL))
+	assert.Equal(t, breaker, GetBreaker("test-backend", nil))
+	client := &http.Client{
+		Transport: NewBreakerTransport(http.DefaultTransport, breaker),
+	}
+
+	// the breaker opens after the consecutive failures reach the threshold
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	status := breaker.Status()
+	assert.Equal(t, BreakerStateOpen, status.State)
+	assert.NotNil(t, status.OpenTime)
+
+	// the requests are rejected without reaching the backend
+	sent := atomic.LoadInt32(&requests)
+	_, err := client.Get(server.URL)
+	require.NotNil(t, err)
+	e, ok := IsBreakerOpen(err)
+	require.True(t, ok)
+	assert.Equal(t, "test-backend", e.Name)
+
+	// the breaker is closed once the probe succeeds
+	atomic.StoreInt32(&down, 0)
+	for i := 0; i < 100 && breaker.Status().State == BreakerStateOpen; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, BreakerStateClosed, breaker.Status().State)
+	assert.True(t, atomic.LoadInt32(&requests) > sent)
+	resp, err := client.Get(server.URL)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	found := false
+	for _, s := range ListBreakerStatus() {
+		if s.Name == "test-backend" {
+			found = true
+			assert.Equal(t, 0, s.Failures)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestBreakerInternalError(t *testing.T) {
+	failed := int32(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failed) == 1 && r.URL.Path == "/v2/_catalog" {
+			// the storage of registry is down
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if r.URL.Path == "/v2/corrupted/blobs/sha256:0" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	breaker := GetBreaker("test-internal-error", ProbeURL(http.DefaultTransport, server.URL+"/v2/_catalog"))
+	breaker.Configure(3, time.Minute)
+	client := &http.Client{
+		Transport: NewBreakerTransport(http.DefaultTransport, breaker),
+	}
+	get := func(path string) {
+		resp, err := client.Get(server.URL + path)
+		require.Nil(t, err)
+		resp.Body.Close()
+	}
+
+	// the 500 of a single object is reset by the other responses
+	for i := 0; i < 3; i++ {
+		get("/v2/corrupted/blobs/sha256:0")
+		get("/v2/")
+	}
+	assert.Equal(t, BreakerStateClosed, breaker.Status().State)
+
+	// the consecutive 500 open the breaker
+	for i := 0; i < 3; i++ {
+		get("/v2/_catalog")
+	}
+	assert.Equal(t, BreakerStateOpen, breaker.Status().State)
+
+	// the probe reading the storage fails though the base API is served
+	assert.NotNil(t, breaker.probe())
+	atomic.StoreInt32(&failed, 0)
+	assert.Nil(t, breaker.probe())
+}
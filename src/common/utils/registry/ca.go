@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ParseCABundle parses the certificates in the PEM encoded CA bundle, an error is returned
+// if the bundle contains anything other than certificates or no certificate at all
+func ParseCABundle(bundle string) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %s in the CA bundle", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in the CA bundle: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found in the CA bundle")
+	}
+	return certs, nil
+}
+
+// GetHTTPTransportWithCA returns the HttpTransport trusting the certificates in the CA bundle
+// in addition to the system ones, the shared transport of GetHTTPTransport is returned if
+// the bundle is empty
+func GetHTTPTransportWithCA(insecure bool, bundle string) (*http.Transport, error) {
+	if len(bundle) == 0 {
+		return GetHTTPTransport(insecure), nil
+	}
+	certs, err := ParseCABundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: insecure,
+			RootCAs:            pool,
+		},
+	}, nil
+}
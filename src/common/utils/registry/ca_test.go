@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	bundle := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}))
+
+	certs, err := ParseCABundle(bundle + bundle)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(certs))
+
+	_, err = ParseCABundle("")
+	assert.NotNil(t, err)
+	_, err = ParseCABundle(string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: []byte("key"),
+	})))
+	assert.NotNil(t, err)
+	_, err = ParseCABundle(string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: []byte("invalid"),
+	})))
+	assert.NotNil(t, err)
+}
+
+func TestGetHTTPTransportWithCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	bundle := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}))
+
+	transport, err := GetHTTPTransportWithCA(false, "")
+	require.Nil(t, err)
+	assert.Equal(t, secureHTTPTransport, transport)
+	_, err = (&http.Client{Transport: transport}).Get(server.URL)
+	assert.NotNil(t, err)
+
+	transport, err = GetHTTPTransportWithCA(false, bundle)
+	require.Nil(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = GetHTTPTransportWithCA(false, "invalid")
+	assert.NotNil(t, err)
+}
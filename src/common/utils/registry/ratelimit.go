@@ -0,0 +1,112 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// RateLimitProbeRepository is the repository of Docker Hub for checking the rate limit, the
+// HEAD requests of its manifest don't consume the budget
+const RateLimitProbeRepository = "ratelimitpreview/test"
+
+// ParseRateLimit reads the rate limit from the headers of the response, nil is returned if
+// the registry doesn't limit the rate
+func ParseRateLimit(header http.Header) *models.RateLimit {
+	limit, window, ok := parseRateLimitHeader(header.Get("RateLimit-Limit"))
+	if !ok {
+		return nil
+	}
+	remaining, _, ok := parseRateLimitHeader(header.Get("RateLimit-Remaining"))
+	if !ok {
+		return nil
+	}
+	source := header.Get("Docker-RateLimit-Source")
+	return &models.RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Window:    window,
+		Source:    source,
+		CheckTime: time.Now(),
+	}
+}
+
+// parseRateLimitHeader parses the value in the format "<quota>;w=<window>", the window is optional
+func parseRateLimitHeader(value string) (int, int, bool) {
+	parts := strings.Split(value, ";")
+	quota, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, false
+	}
+	window := 0
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "w=") {
+			window, _ = strconv.Atoi(part[2:])
+		}
+	}
+	return quota, window, true
+}
+
+// RateLimitTransport passes the rate limits read from the responses to the recorder
+type RateLimitTransport struct {
+	transport http.RoundTripper
+	record    func(*models.RateLimit)
+}
+
+// NewRateLimitTransport returns a RateLimitTransport calling the record function with the
+// rate limit of every response which has one
+func NewRateLimitTransport(transport http.RoundTripper, record func(*models.RateLimit)) *RateLimitTransport {
+	return &RateLimitTransport{
+		transport: transport,
+		record:    record,
+	}
+}
+
+// RoundTrip ...
+func (r *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if rateLimit := ParseRateLimit(resp.Header); rateLimit != nil {
+		r.record(rateLimit)
+	}
+	return resp, nil
+}
+
+// RateLimit checks the rate limit with the HEAD request of the manifest, which doesn't consume
+// the budget of Docker Hub. Nil is returned if the registry doesn't limit the rate
+func (r *Repository) RateLimit(reference string) (*models.RateLimit, error) {
+	req, err := http.NewRequest(http.MethodHead, buildManifestURL(r.Endpoint.String(), r.Name, reference), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(http.CanonicalHeaderKey("Accept"), schema1.MediaTypeManifest)
+	req.Header.Add(http.CanonicalHeaderKey("Accept"), schema2.MediaTypeManifest)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, parseError(err)
+	}
+	defer resp.Body.Close()
+	return ParseRateLimit(resp.Header), nil
+}
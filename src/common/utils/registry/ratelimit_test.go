@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	header := http.Header{}
+	assert.Nil(t, ParseRateLimit(header))
+
+	header.Set("RateLimit-Limit", "100;w=21600")
+	header.Set("RateLimit-Remaining", "invalid")
+	assert.Nil(t, ParseRateLimit(header))
+
+	header.Set("RateLimit-Remaining", "76;w=21600")
+	header.Set("Docker-RateLimit-Source", "192.168.0.1")
+	rateLimit := ParseRateLimit(header)
+	require.NotNil(t, rateLimit)
+	assert.Equal(t, 100, rateLimit.Limit)
+	assert.Equal(t, 76, rateLimit.Remaining)
+	assert.Equal(t, 21600, rateLimit.Window)
+	assert.Equal(t, "192.168.0.1", rateLimit.Source)
+	assert.True(t, rateLimit.Low(80))
+	assert.False(t, rateLimit.Low(10))
+	assert.False(t, rateLimit.Low(0))
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	remaining := "10"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/library/limited/manifests/latest" {
+			w.Header().Set("RateLimit-Limit", "100")
+			w.Header().Set("RateLimit-Remaining", remaining)
+		}
+	}))
+	defer server.Close()
+
+	var recorded *models.RateLimit
+	client := &http.Client{
+		Transport: NewRateLimitTransport(http.DefaultTransport, func(rateLimit *models.RateLimit) {
+			recorded = rateLimit
+		}),
+	}
+	resp, err := client.Get(server.URL + "/v2/library/app/manifests/latest")
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Nil(t, recorded)
+
+	repository, err := NewRepository("library/limited", server.URL, client)
+	require.Nil(t, err)
+	rateLimit, err := repository.RateLimit("latest")
+	require.Nil(t, err)
+	require.NotNil(t, rateLimit)
+	assert.Equal(t, 10, rateLimit.Remaining)
+	require.NotNil(t, recorded)
+	assert.Equal(t, 100, recorded.Limit)
+}
@@ -0,0 +1,118 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build noresumabledigest
+// +build noresumabledigest
+
+// The vendored registry is built without the resumable digests as the
+// dependency isn't vendored, run the tests with "-tags noresumabledigest"
+
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/docker/distribution/configuration"
+	"github.com/docker/distribution/context"
+	"github.com/docker/distribution/registry/handlers"
+	_ "github.com/docker/distribution/registry/storage/driver/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistryServer(t *testing.T) *httptest.Server {
+	config := &configuration.Configuration{
+		Storage: configuration.Storage{
+			"inmemory": configuration.Parameters{},
+			"maintenance": configuration.Parameters{"uploadpurging": map[interface{}]interface{}{
+				"enabled": false,
+			}},
+		},
+	}
+	config.HTTP.Secret = "secret"
+	return httptest.NewServer(handlers.NewApp(context.Background(), config))
+}
+
+// startBlobUpload starts an upload with a chunk and returns the UUID and the state
+// of the location returned by the registry
+func startBlobUpload(t *testing.T, endpoint string) (string, string) {
+	resp, err := http.Post(buildInitiateBlobUploadURL(endpoint, repository), "", nil)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	location, err := resp.Location()
+	require.Nil(t, err)
+	req, err := http.NewRequest(http.MethodPatch, location.String(), bytes.NewReader([]byte("chunk")))
+	require.Nil(t, err)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err = http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	location, err = resp.Location()
+	require.Nil(t, err)
+	return resp.Header.Get("Docker-Upload-UUID"), location.Query().Get("_state")
+}
+
+func TestCancelBlobUploadOfRegistry(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	require.Nil(t, err)
+	uuid, state := startBlobUpload(t, server.URL)
+	require.NotEmpty(t, uuid)
+	require.NotEmpty(t, state)
+
+	// the registry rejects the request without the state
+	err = client.CancelBlobUpload(uuid, "")
+	assert.NotNil(t, err)
+
+	require.Nil(t, client.CancelBlobUpload(uuid, state))
+	// the upload doesn't exist anymore
+	assert.Nil(t, client.CancelBlobUpload(uuid, state))
+
+	resp, err := http.Get(buildBlobUploadURL(server.URL, repository, uuid) + "?_state=" + url.QueryEscape(state))
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStatBlobOfRegistry(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	require.Nil(t, err)
+	data := []byte("blob")
+	dgt := fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+	require.Nil(t, client.PushBlob(dgt, int64(len(data)), bytes.NewReader(data)))
+
+	exist, size, err := client.StatBlob(dgt)
+	require.Nil(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, int64(len(data)), size)
+
+	exist, _, err = client.StatBlob(fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("unknown"))))
+	require.Nil(t, err)
+	assert.False(t, exist)
+}
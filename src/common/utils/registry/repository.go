@@ -329,6 +329,43 @@ func (r *Repository) BlobExist(digest string) (bool, error) {
 	}
 }
 
+// StatBlob returns whether the blob exists and the size of it
+func (r *Repository) StatBlob(digest string) (bool, int64, error) {
+	req, err := http.NewRequest("HEAD", buildBlobURL(r.Endpoint.String(), r.Name, digest), nil)
+	if err != nil {
+		return false, 0, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, 0, parseError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		size, err := strconv.ParseInt(resp.Header.Get(http.CanonicalHeaderKey("Content-Length")), 10, 64)
+		if err != nil {
+			return false, 0, fmt.Errorf("invalid size of blob %s: %v", digest, err)
+		}
+		return true, size, nil
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, 0, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return false, 0, &commonhttp.Error{
+		Code:    resp.StatusCode,
+		Message: string(b),
+	}
+}
+
 // PullBlob : client must close data if it is not nil
 func (r *Repository) PullBlob(digest string) (size int64, data io.ReadCloser, err error) {
 	req, err := http.NewRequest("GET", buildBlobURL(r.Endpoint.String(), r.Name, digest), nil)
@@ -468,6 +505,61 @@ func (r *Repository) DeleteBlob(digest string) error {
 	}
 }
 
+// CancelBlobUpload cancels the blob upload session and removes the uploaded data, the
+// state is the "_state" parameter of the location returned by the last request of the
+// upload. nil is returned if the upload session doesn't exist
+func (r *Repository) CancelBlobUpload(uuid, state string) error {
+	req, err := http.NewRequest("DELETE", buildBlobUploadURL(r.Endpoint.String(), r.Name, uuid)+
+		"?_state="+url.QueryEscape(state), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return parseError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// the registry returns 404 for the invalid state as well, so only the unknown upload is ignored
+	if resp.StatusCode == http.StatusNotFound && hasErrorCode(b, "BLOB_UPLOAD_UNKNOWN") {
+		return nil
+	}
+
+	return &commonhttp.Error{
+		Code:    resp.StatusCode,
+		Message: string(b),
+	}
+}
+
+// hasErrorCode returns whether the error body returned by registry contains the code
+func hasErrorCode(body []byte, code string) bool {
+	errs := &struct {
+		Errors []struct {
+			Code string `json:"code"`
+		} `json:"errors"`
+	}{}
+	if err := json.Unmarshal(body, errs); err != nil {
+		return false
+	}
+	for _, e := range errs.Errors {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
 func buildPingURL(endpoint string) string {
 	return fmt.Sprintf("%s/v2/", endpoint)
 }
@@ -484,6 +576,10 @@ func buildBlobURL(endpoint, repoName, reference string) string {
 	return fmt.Sprintf("%s/v2/%s/blobs/%s", endpoint, repoName, reference)
 }
 
+func buildBlobUploadURL(endpoint, repoName, uuid string) string {
+	return fmt.Sprintf("%s/v2/%s/blobs/uploads/%s", endpoint, repoName, uuid)
+}
+
 func buildMountBlobURL(endpoint, repoName, digest, from string) string {
 	return fmt.Sprintf("%s/v2/%s/blobs/uploads/?mount=%s&from=%s", endpoint, repoName, digest, from)
 }
@@ -202,6 +202,28 @@ func TestDeleteBlob(t *testing.T) {
 	}
 }
 
+func TestCancelBlobUpload(t *testing.T) {
+	uuid := "2c9b8fbe-8ed5-4be6-a6b6-4ec2d1b4b9a8"
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "DELETE",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/%s", repository, uuid),
+			Handler: test.Handler(&test.Response{
+				StatusCode: http.StatusNoContent,
+			}),
+		})
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client for repository: %v", err)
+	}
+
+	if err = client.CancelBlobUpload(uuid, "state"); err != nil {
+		t.Fatalf("failed to cancel blob upload: %v", err)
+	}
+}
+
 func TestManifestExist(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
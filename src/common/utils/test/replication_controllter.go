@@ -14,6 +14,10 @@
 
 package test
 
+import (
+	"github.com/goharbor/harbor/src/replication/models"
+)
+
 type FakeReplicatoinController struct {
 	FakePolicyManager
 }
@@ -24,3 +28,6 @@ func (f *FakeReplicatoinController) Init() error {
 func (f *FakeReplicatoinController) Replicate(policyID int64, metadata ...map[string]interface{}) error {
 	return nil
 }
+func (f *FakeReplicatoinController) ReplicateArtifacts(targetID int64, candidates []models.FilterItem, opUUID string) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslog
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// Store is the store which the access logs are written to and queried from
+type Store interface {
+	// Add persists the access log
+	Add(accessLog models.AccessLog) error
+	// Count returns the total count of the access logs matching the query
+	Count(query *models.LogQueryParam) (int64, error)
+	// List returns the access logs matching the query in the descent order of
+	// the operation time
+	List(query *models.LogQueryParam) ([]models.AccessLog, error)
+}
+
+// the access logs are kept in the database until Init is called
+var store Store = &dbStore{}
+
+// Init initializes the store of the access logs according to the configurations,
+// the database is used unless Elasticsearch is configured
+func Init() error {
+	cfg, err := config.AccessLogStore()
+	if err != nil {
+		return err
+	}
+	switch cfg.Type {
+	case "", common.AccessLogStoreDatabase:
+		store = &dbStore{}
+	case common.AccessLogStoreElasticsearch:
+		es, err := newESStore(cfg)
+		if err != nil {
+			return err
+		}
+		store = es
+	default:
+		return fmt.Errorf("unsupported store of access logs: %s", cfg.Type)
+	}
+	log.Infof("the access logs are stored in %s", cfg.Type)
+	return nil
+}
+
+// Add persists the access log in the configured store
+func Add(accessLog models.AccessLog) error {
+	return store.Add(accessLog)
+}
+
+// Count returns the total count of the access logs matching the query in the configured store
+func Count(query *models.LogQueryParam) (int64, error) {
+	return store.Count(query)
+}
+
+// CappedCount returns the count of the access logs matching the query in the configured store,
+// the store stops counting at max+1 if it supports, otherwise all of them are counted
+func CappedCount(query *models.LogQueryParam, max int64) (int64, error) {
+	if s, ok := store.(cappedCounter); ok {
+		return s.CappedCount(query, max)
+	}
+	return store.Count(query)
+}
+
+// cappedCounter is implemented by the stores in which counting all the access logs is expensive
+type cappedCounter interface {
+	CappedCount(query *models.LogQueryParam, max int64) (int64, error)
+}
+
+// List returns the access logs matching the query in the configured store
+func List(query *models.LogQueryParam) ([]models.AccessLog, error) {
+	return store.List(query)
+}
+
+// dbStore keeps the access logs in the database of Harbor
+type dbStore struct{}
+
+func (d *dbStore) Add(accessLog models.AccessLog) error {
+	return dao.AddAccessLog(accessLog)
+}
+
+func (d *dbStore) Count(query *models.LogQueryParam) (int64, error) {
+	return dao.GetTotalOfAccessLogs(query)
+}
+
+func (d *dbStore) CappedCount(query *models.LogQueryParam, max int64) (int64, error) {
+	return dao.GetCappedTotalOfAccessLogs(query, max)
+}
+
+func (d *dbStore) List(query *models.LogQueryParam) ([]models.AccessLog, error) {
+	return dao.GetAccessLogs(query)
+}
@@ -0,0 +1,146 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslog
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the value recorded for the changes of the sensitive fields, e.g. the passwords
+const maskedValue = "******"
+
+// FieldChange is the change of a field, the old value is nil for the added fields and
+// the new value is nil for the removed ones
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// Diff returns the changes of the fields between the old and the new objects, the objects
+// are compared by their JSON and the nested fields are named as "parent.child". The values
+// of the sensitive fields are masked
+func Diff(old, new interface{}, sensitive ...string) ([]*FieldChange, error) {
+	oldFields, err := flatten(old)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flatten(new)
+	if err != nil {
+		return nil, err
+	}
+	masked := map[string]bool{}
+	for _, field := range sensitive {
+		masked[field] = true
+	}
+
+	names := []string{}
+	for name := range oldFields {
+		names = append(names, name)
+	}
+	for name := range newFields {
+		if _, exist := oldFields[name]; !exist {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []*FieldChange{}
+	for _, name := range names {
+		o, n := oldFields[name], newFields[name]
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		if masked[name] {
+			o, n = mask(o), mask(n)
+		}
+		changes = append(changes, &FieldChange{
+			Field: name,
+			Old:   o,
+			New:   n,
+		})
+	}
+	return changes, nil
+}
+
+// AddChanges records the operation with the changes between the old and the new objects
+// as the detail of the access log, nothing is recorded if nothing changes
+func AddChanges(accessLog models.AccessLog, old, new interface{}, sensitive ...string) error {
+	changes, err := Diff(old, new, sensitive...)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	detail, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	accessLog.Detail = string(detail)
+	if accessLog.OpTime.IsZero() {
+		accessLog.OpTime = time.Now()
+	}
+	return Add(accessLog)
+}
+
+// flatten converts the object into the fields keyed by the paths, the arrays are
+// compared as a whole
+func flatten(object interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if object == nil || reflect.ValueOf(object).Kind() == reflect.Ptr && reflect.ValueOf(object).IsNil() {
+		return fields, nil
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return fields, nil
+	}
+	flattenInto(fields, "", value)
+	return fields, nil
+}
+
+func flattenInto(fields map[string]interface{}, prefix string, value interface{}) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		fields[prefix] = value
+		return
+	}
+	for k, v := range m {
+		name := k
+		if len(prefix) > 0 {
+			name = prefix + "." + k
+		}
+		flattenInto(fields, name, v)
+	}
+}
+
+func mask(value interface{}) interface{} {
+	if value == nil || value == "" {
+		return value
+	}
+	return maskedValue
+}
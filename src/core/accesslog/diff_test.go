@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	logs []models.AccessLog
+}
+
+func (f *fakeStore) Add(accessLog models.AccessLog) error {
+	f.logs = append(f.logs, accessLog)
+	return nil
+}
+
+func (f *fakeStore) Count(query *models.LogQueryParam) (int64, error) {
+	return int64(len(f.logs)), nil
+}
+
+func (f *fakeStore) List(query *models.LogQueryParam) ([]models.AccessLog, error) {
+	return f.logs, nil
+}
+
+func TestDiff(t *testing.T) {
+	old := map[string]interface{}{
+		"name":     "library",
+		"password": "old",
+		"policy": map[string]interface{}{
+			"rules":   []string{"a"},
+			"enabled": true,
+		},
+		"removed": 1,
+	}
+	new := map[string]interface{}{
+		"name":     "library",
+		"password": "new",
+		"policy": map[string]interface{}{
+			"rules":   []string{"a", "b"},
+			"enabled": true,
+		},
+		"added": "value",
+	}
+	changes, err := Diff(old, new, "password")
+	require.Nil(t, err)
+	require.Equal(t, 4, len(changes))
+	assert.Equal(t, &FieldChange{Field: "added", New: "value"}, changes[0])
+	assert.Equal(t, &FieldChange{Field: "password", Old: maskedValue, New: maskedValue}, changes[1])
+	assert.Equal(t, &FieldChange{Field: "policy.rules", Old: []interface{}{"a"},
+		New: []interface{}{"a", "b"}}, changes[2])
+	assert.Equal(t, &FieldChange{Field: "removed", Old: float64(1)}, changes[3])
+
+	// nil objects
+	changes, err = Diff(nil, (*models.RepoRetention)(nil))
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(changes))
+	changes, err = Diff(map[string]string(nil), map[string]string{"auto_scan": "true"})
+	require.Nil(t, err)
+	assert.Equal(t, []*FieldChange{{Field: "auto_scan", New: "true"}}, changes)
+}
+
+func TestAddChanges(t *testing.T) {
+	original := store
+	defer func() { store = original }()
+	fake := &fakeStore{}
+	store = fake
+
+	// nothing changes
+	require.Nil(t, AddChanges(models.AccessLog{Operation: "update_config"},
+		map[string]string{"a": "b"}, map[string]string{"a": "b"}))
+	assert.Equal(t, 0, len(fake.logs))
+
+	require.Nil(t, AddChanges(models.AccessLog{Operation: "update_config"},
+		map[string]string{"a": "b"}, map[string]string{"a": "c"}))
+	require.Equal(t, 1, len(fake.logs))
+	assert.False(t, fake.logs[0].OpTime.IsZero())
+	changes := []*FieldChange{}
+	require.Nil(t, json.Unmarshal([]byte(fake.logs[0].Detail), &changes))
+	assert.Equal(t, []*FieldChange{{Field: "a", Old: "b", New: "c"}}, changes)
+}
@@ -0,0 +1,273 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+const (
+	// the max count of the hits returned by one search of Elasticsearch by default,
+	// it's used when the query isn't paginated
+	esMaxResultWindow = 10000
+	// the mapping of the index, all the strings are keywords as they are matched
+	// by the wildcards rather than the full-text search
+	esIndexMapping = `{"mappings": {"properties": {
+		"username": {"type": "keyword"},
+		"project_id": {"type": "long"},
+		"repo_name": {"type": "keyword"},
+		"repo_tag": {"type": "keyword"},
+		"guid": {"type": "keyword"},
+		"operation": {"type": "keyword"},
+		"op_time": {"type": "date"},
+		"detail": {"type": "text"}}}}`
+)
+
+var esWildcardReplacer = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+// esStore keeps the access logs in an index of Elasticsearch
+type esStore struct {
+	url      string
+	index    string
+	username string
+	password string
+	client   *http.Client
+}
+
+// newESStore returns the store backed by Elasticsearch, the index is created with
+// the mapping of the access logs if it doesn't exist
+func newESStore(cfg *models.AccessLogStore) (*esStore, error) {
+	if len(cfg.ESURL) == 0 {
+		return nil, errors.New("empty URL of Elasticsearch")
+	}
+	if len(cfg.ESIndex) == 0 {
+		return nil, errors.New("empty index of Elasticsearch")
+	}
+	es := &esStore{
+		url:      strings.TrimSuffix(cfg.ESURL, "/"),
+		index:    cfg.ESIndex,
+		username: cfg.ESUsername,
+		password: cfg.ESPassword,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	if err := es.ensureIndex(); err != nil {
+		return nil, err
+	}
+	return es, nil
+}
+
+func (e *esStore) ensureIndex() error {
+	code, _, err := e.do(http.MethodHead, "/"+e.index, nil)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+	default:
+		return fmt.Errorf("unexpected status code %d when checking the index %s", code, e.index)
+	}
+	code, data, err := e.do(http.MethodPut, "/"+e.index, []byte(esIndexMapping))
+	if err != nil {
+		return err
+	}
+	// the index may be created by another instance of core meanwhile
+	if code != http.StatusOK && !strings.Contains(string(data), "resource_already_exists_exception") {
+		return fmt.Errorf("failed to create the index %s: %d %s", e.index, code, string(data))
+	}
+	return nil
+}
+
+func (e *esStore) Add(accessLog models.AccessLog) error {
+	doc := map[string]interface{}{
+		"username":   accessLog.Username,
+		"project_id": accessLog.ProjectID,
+		"repo_name":  accessLog.RepoName,
+		"repo_tag":   accessLog.RepoTag,
+		"guid":       accessLog.GUID,
+		"operation":  accessLog.Operation,
+		"op_time":    accessLog.OpTime,
+	}
+	if len(accessLog.Detail) > 0 {
+		doc["detail"] = accessLog.Detail
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	code, data, err := e.do(http.MethodPost, "/"+e.index+"/_doc", body)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusCreated && code != http.StatusOK {
+		return fmt.Errorf("failed to index the access log: %d %s", code, string(data))
+	}
+	return nil
+}
+
+func (e *esStore) Count(query *models.LogQueryParam) (int64, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"query": esQuery(query),
+	})
+	if err != nil {
+		return 0, err
+	}
+	code, data, err := e.do(http.MethodPost, "/"+e.index+"/_count", body)
+	if err != nil {
+		return 0, err
+	}
+	if code != http.StatusOK {
+		return 0, fmt.Errorf("failed to count the access logs: %d %s", code, string(data))
+	}
+	result := &struct {
+		Count int64 `json:"count"`
+	}{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+func (e *esStore) List(query *models.LogQueryParam) ([]models.AccessLog, error) {
+	from, size := int64(0), int64(esMaxResultWindow)
+	if query != nil && query.Pagination != nil && query.Pagination.Size > 0 {
+		size = query.Pagination.Size
+		if query.Pagination.Page > 0 {
+			from = (query.Pagination.Page - 1) * size
+		}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"query": esQuery(query),
+		"sort": []interface{}{
+			map[string]interface{}{"op_time": "desc"},
+		},
+		"from": from,
+		"size": size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	code, data, err := e.do(http.MethodPost, "/"+e.index+"/_search", body)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("failed to search the access logs: %d %s", code, string(data))
+	}
+	result := &struct {
+		Hits struct {
+			Hits []struct {
+				Source models.AccessLog `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	logs := []models.AccessLog{}
+	for _, hit := range result.Hits.Hits {
+		logs = append(logs, hit.Source)
+	}
+	return logs, nil
+}
+
+func (e *esStore) do(method, path string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequest(method, e.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(e.username) > 0 {
+		req.SetBasicAuth(e.username, e.password)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
+
+// esQuery converts the query to the bool query of Elasticsearch with the same
+// semantic as the one of the database: the names are matched fuzzily and the
+// others are matched exactly
+func esQuery(query *models.LogQueryParam) map[string]interface{} {
+	filters := []interface{}{}
+	if query != nil {
+		if len(query.ProjectIDs) > 0 {
+			filters = append(filters, map[string]interface{}{
+				"terms": map[string]interface{}{"project_id": query.ProjectIDs},
+			})
+		}
+		for _, match := range [][2]string{
+			{"username", query.Username},
+			{"repo_name", query.Repository},
+			{"repo_tag", query.Tag},
+		} {
+			if len(match[1]) == 0 {
+				continue
+			}
+			filters = append(filters, map[string]interface{}{
+				"wildcard": map[string]interface{}{match[0]: "*" + esWildcardReplacer.Replace(match[1]) + "*"},
+			})
+		}
+		operations := []string{}
+		for _, operation := range query.Operations {
+			if len(operation) > 0 {
+				operations = append(operations, operation)
+			}
+		}
+		if len(operations) > 0 {
+			filters = append(filters, map[string]interface{}{
+				"terms": map[string]interface{}{"operation": operations},
+			})
+		}
+		timeRange := map[string]interface{}{}
+		if query.BeginTime != nil {
+			timeRange["gte"] = query.BeginTime.Format(time.RFC3339Nano)
+		}
+		if query.EndTime != nil {
+			timeRange["lte"] = query.EndTime.Format(time.RFC3339Nano)
+		}
+		if len(timeRange) > 0 {
+			filters = append(filters, map[string]interface{}{
+				"range": map[string]interface{}{"op_time": timeRange},
+			})
+		}
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": filters,
+		},
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestESQuery(t *testing.T) {
+	query := esQuery(nil)
+	assert.Equal(t, 0, len(query["bool"].(map[string]interface{})["filter"].([]interface{})))
+
+	begin := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	query = esQuery(&models.LogQueryParam{
+		ProjectIDs: []int64{1, 2},
+		Username:   "adm*n",
+		Operations: []string{"push", ""},
+		BeginTime:  &begin,
+	})
+	data, err := json.Marshal(query)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"bool": {"filter": [
+		{"terms": {"project_id": [1, 2]}},
+		{"wildcard": {"username": "*adm\\*n*"}},
+		{"terms": {"operation": ["push"]}},
+		{"range": {"op_time": {"gte": "2019-01-01T00:00:00Z"}}}]}}`, string(data))
+}
+
+func TestESStore(t *testing.T) {
+	indexed := map[string]interface{}{}
+	created := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "elastic" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/harbor-access-log":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.URL.Path == "/harbor-access-log":
+			created = true
+			w.Write([]byte(`{"acknowledged": true}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/harbor-access-log/_doc":
+			data, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(data, &indexed)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost && r.URL.Path == "/harbor-access-log/_count":
+			w.Write([]byte(`{"count": 1}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/harbor-access-log/_search":
+			body := map[string]interface{}{}
+			data, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(data, &body)
+			if body["from"] != float64(10) || body["size"] != float64(10) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"hits": {"hits": [{"_source": {"username": "admin",
+				"project_id": 1, "repo_name": "library/hello-world", "operation": "push"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	es, err := newESStore(&models.AccessLogStore{
+		ESURL:      server.URL + "/",
+		ESIndex:    "harbor-access-log",
+		ESUsername: "elastic",
+		ESPassword: "secret",
+	})
+	require.Nil(t, err)
+	assert.True(t, created)
+
+	require.Nil(t, es.Add(models.AccessLog{
+		Username:  "admin",
+		ProjectID: 1,
+		RepoName:  "library/hello-world",
+		Operation: "push",
+		OpTime:    time.Now(),
+	}))
+	assert.Equal(t, "admin", indexed["username"])
+	assert.Equal(t, float64(1), indexed["project_id"])
+
+	total, err := es.Count(&models.LogQueryParam{Username: "admin"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	logs, err := es.List(&models.LogQueryParam{
+		Username: "admin",
+		Pagination: &models.Pagination{
+			Page: 2,
+			Size: 10,
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(logs))
+	assert.Equal(t, "library/hello-world", logs[0].RepoName)
+
+	// invalid credentials
+	_, err = newESStore(&models.AccessLogStore{
+		ESURL:   server.URL,
+		ESIndex: "harbor-access-log",
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,324 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/approval"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier"
+)
+
+// AccessRequestAPI handles request to /api/projects/:pid/access_requests
+type AccessRequestAPI struct {
+	BaseController
+	project *models.Project
+	request *models.AccessRequest
+}
+
+// Prepare ...
+func (a *AccessRequestAPI) Prepare() {
+	a.BaseController.Prepare()
+
+	if !a.SecurityCtx.IsAuthenticated() {
+		a.HandleUnauthorized()
+		return
+	}
+
+	pid, err := a.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		var errMsg string
+		if err != nil {
+			errMsg = "failed to get project ID " + err.Error()
+		} else {
+			errMsg = "invalid project ID: " + fmt.Sprintf("%d", pid)
+		}
+		a.HandleBadRequest(errMsg)
+		return
+	}
+	project, err := a.ProjectMgr.Get(pid)
+	if err != nil {
+		a.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		a.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	a.project = project
+
+	// any authenticated user can submit an access request
+	if a.Ctx.Input.IsPost() && len(a.GetStringFromPath(":id")) == 0 {
+		return
+	}
+
+	if !a.SecurityCtx.HasAllPerm(pid) {
+		a.HandleForbidden(a.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(a.GetStringFromPath(":id")) > 0 {
+		id, err := a.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			a.HandleBadRequest(fmt.Sprintf("invalid access request ID: %s", a.GetStringFromPath(":id")))
+			return
+		}
+		request, err := dao.GetAccessRequest(id)
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get access request %d: %v", id, err))
+			return
+		}
+		if request == nil || request.ProjectID != pid {
+			a.HandleNotFound(fmt.Sprintf("access request %d not found", id))
+			return
+		}
+		a.request = request
+	}
+}
+
+// Post submits a request to access the project
+func (a *AccessRequestAPI) Post() {
+	var req models.AccessRequestReq
+	a.DecodeJSONReqAndValidate(&req)
+	if req.Role == 0 {
+		req.Role = common.RoleGuest
+	}
+
+	user, err := dao.GetUser(models.User{
+		Username: a.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", a.SecurityCtx.GetUsername(), err))
+		return
+	}
+	if user == nil {
+		a.HandleNotFound(fmt.Sprintf("user %s not found", a.SecurityCtx.GetUsername()))
+		return
+	}
+
+	members, err := project.GetProjectMember(models.Member{
+		ProjectID:  a.project.ProjectID,
+		EntityID:   user.UserID,
+		EntityType: common.UserMember,
+	})
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get members of project %d: %v", a.project.ProjectID, err))
+		return
+	}
+	if len(members) > 0 && !members[0].IsExpired() {
+		a.HandleConflict(fmt.Sprintf("user %s is already a member of project %s", user.Username, a.project.Name))
+		return
+	}
+
+	request := &models.AccessRequest{
+		ProjectID:     a.project.ProjectID,
+		UserID:        user.UserID,
+		Role:          req.Role,
+		Justification: req.Justification,
+	}
+	id, err := dao.AddAccessRequest(request)
+	if err != nil {
+		if err == dao.ErrDupRows {
+			a.HandleConflict(fmt.Sprintf("a pending access request of user %s already exists", user.Username))
+			return
+		}
+		a.HandleInternalServerError(fmt.Sprintf("failed to create access request: %v", err))
+		return
+	}
+
+	if _, err = approval.Submit(&models.Approval{
+		Type:       models.ApprovalAccessRequest,
+		ResourceID: id,
+		ProjectID:  a.project.ProjectID,
+		Requester:  user.Username,
+		Summary:    fmt.Sprintf("User %s requests to access project %s", user.Username, a.project.Name),
+	}); err != nil {
+		log.Errorf("failed to submit the approval of access request %d: %v", id, err)
+	}
+
+	a.notifyProjectAdmins(user, request)
+	a.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the access requests of the project
+func (a *AccessRequestAPI) List() {
+	query := &models.AccessRequestQuery{
+		ProjectID: a.project.ProjectID,
+		Status:    a.GetString("status"),
+	}
+	total, err := dao.CountAccessRequests(query)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to count access requests of project %d: %v", a.project.ProjectID, err))
+		return
+	}
+	query.Page, query.Size = a.GetPaginationParams()
+
+	requests, err := dao.ListAccessRequests(query)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to list access requests of project %d: %v", a.project.ProjectID, err))
+		return
+	}
+	for _, request := range requests {
+		user, err := dao.GetUser(models.User{UserID: request.UserID})
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get user %d: %v", request.UserID, err))
+			return
+		}
+		if user != nil {
+			request.Username = user.Username
+		}
+	}
+
+	a.SetPaginationHeader(total, query.Page, query.Size)
+	a.Data["json"] = requests
+	a.ServeJSON()
+}
+
+// Approve approves the access request and adds the requester as the member of the project
+func (a *AccessRequestAPI) Approve() {
+	a.review(true)
+}
+
+// Deny denies the access request
+func (a *AccessRequestAPI) Deny() {
+	a.review(false)
+}
+
+// review reviews the pending request through the approval engine
+func (a *AccessRequestAPI) review(approved bool) {
+	if err := approval.ReviewResource(models.ApprovalAccessRequest, a.request.ID, approved,
+		a.SecurityCtx.GetUsername(), ""); err != nil {
+		if err == approval.ErrNotPending {
+			a.HandleConflict(fmt.Sprintf("access request %d is not pending", a.request.ID))
+			return
+		}
+		a.HandleInternalServerError(fmt.Sprintf("failed to review access request %d: %v", a.request.ID, err))
+	}
+}
+
+// accessRequestApprovalHandler reviews the access requests through the approval engine, the
+// requester is added as the member of the project once the request is approved
+type accessRequestApprovalHandler struct{}
+
+func (h *accessRequestApprovalHandler) Approve(ap *models.Approval, reviewer, comment string) error {
+	return reviewAccessRequest(ap.ResourceID, models.AccessRequestApproved, reviewer, comment)
+}
+
+func (h *accessRequestApprovalHandler) Reject(ap *models.Approval, reviewer, comment string) error {
+	return reviewAccessRequest(ap.ResourceID, models.AccessRequestDenied, reviewer, comment)
+}
+
+// reviewAccessRequest updates the status of the pending request and notifies the requester
+func reviewAccessRequest(id int64, status, reviewer, comment string) error {
+	request, err := dao.GetAccessRequest(id)
+	if err != nil {
+		return fmt.Errorf("failed to get access request %d: %v", id, err)
+	}
+	if request == nil {
+		return approval.ErrNotPending
+	}
+	pro, err := config.GlobalProjectMgr.Get(request.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project %d: %v", request.ProjectID, err)
+	}
+	if pro == nil {
+		return fmt.Errorf("project %d not found", request.ProjectID)
+	}
+	user, err := dao.GetUser(models.User{UserID: request.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to get user %d: %v", request.UserID, err)
+	}
+	if user == nil {
+		return fmt.Errorf("user %d not found", request.UserID)
+	}
+
+	// the requester is added as the member in the same transaction as the request is approved
+	var updated bool
+	if status == models.AccessRequestApproved {
+		updated, err = dao.ApproveAccessRequest(request.ID, reviewer)
+	} else {
+		updated, err = dao.UpdateAccessRequestStatus(request.ID, status, reviewer)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update access request %d: %v", request.ID, err)
+	}
+	if !updated {
+		return approval.ErrNotPending
+	}
+
+	notifyAccessRequester(user, pro, request, status, reviewer, comment)
+	return nil
+}
+
+func (a *AccessRequestAPI) notifyProjectAdmins(requester *models.User, request *models.AccessRequest) {
+	members, err := project.GetProjectMember(models.Member{
+		ProjectID:  a.project.ProjectID,
+		EntityType: common.UserMember,
+	})
+	if err != nil {
+		log.Errorf("failed to get members of project %d: %v", a.project.ProjectID, err)
+		return
+	}
+
+	to := []string{}
+	for _, member := range members {
+		if member.Role != common.RoleProjectAdmin || member.IsExpired() {
+			continue
+		}
+		admin, err := dao.GetUser(models.User{UserID: member.EntityID})
+		if err != nil {
+			log.Errorf("failed to get user %d: %v", member.EntityID, err)
+			continue
+		}
+		if admin != nil {
+			to = append(to, admin.Email)
+		}
+	}
+
+	if err := notifier.Publish(notifier.EmailTopic, notifier.EmailNotification{
+		To:      to,
+		Subject: fmt.Sprintf("Harbor: access request for project %s", a.project.Name),
+		Message: fmt.Sprintf("User %s requests to access project %s, justification: %s",
+			requester.Username, a.project.Name, request.Justification),
+	}); err != nil {
+		log.Errorf("failed to publish the notification of access request %d: %v", request.ID, err)
+	}
+}
+
+func notifyAccessRequester(requester *models.User, pro *models.Project, request *models.AccessRequest, status, reviewer, comment string) {
+	message := fmt.Sprintf("Your request to access project %s has been %s by %s", pro.Name, status, reviewer)
+	if len(comment) > 0 {
+		message += ", comment: " + comment
+	}
+	if err := notifier.Publish(notifier.EmailTopic, notifier.EmailNotification{
+		To:      []string{requester.Email},
+		Subject: fmt.Sprintf("Harbor: access request for project %s %s", pro.Name, status),
+		Message: message,
+	}); err != nil {
+		log.Errorf("failed to publish the notification of access request %d: %v", request.ID, err)
+	}
+}
+
+func init() {
+	approval.Register(models.ApprovalAccessRequest, &accessRequestApprovalHandler{})
+}
@@ -0,0 +1,154 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var accessRequestPath = "/api/projects/1/access_requests"
+
+func TestAccessRequestAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    accessRequestPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400, justification is required
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        accessRequestPath,
+				bodyJSON:   &models.AccessRequestReq{},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 409, already a member
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    accessRequestPath,
+				bodyJSON: &models.AccessRequestReq{
+					Justification: "for testing",
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusConflict,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    accessRequestPath,
+				bodyJSON: &models.AccessRequestReq{
+					Justification: "for testing",
+				},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409, pending request exists
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    accessRequestPath,
+				bodyJSON: &models.AccessRequestReq{
+					Justification: "for testing",
+				},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+		// 403, only project admin can list the requests
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        accessRequestPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	requests := []*models.AccessRequest{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    accessRequestPath,
+		queryStruct: struct {
+			Status string `url:"status"`
+		}{
+			Status: models.AccessRequestPending,
+		},
+		credential: projAdmin,
+	}, &requests)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(requests))
+	assert.Equal(t, nonSysAdmin.Name, requests[0].Username)
+	id := requests[0].ID
+	defer dao.DeleteAccessRequest(id)
+
+	cases = []*codeCheckingCase{
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/deny", accessRequestPath, id),
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/deny", accessRequestPath, 10000),
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/deny", accessRequestPath, id),
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 409, the request has been reviewed
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/approve", accessRequestPath, id),
+				credential: projAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,144 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/approval"
+)
+
+// ApprovalAPI handles request to /api/approvals
+type ApprovalAPI struct {
+	BaseController
+	approval *models.Approval
+}
+
+// Prepare validates the user and the approval
+func (a *ApprovalAPI) Prepare() {
+	a.BaseController.Prepare()
+	if !a.SecurityCtx.IsAuthenticated() {
+		a.HandleUnauthorized()
+		return
+	}
+
+	if len(a.GetStringFromPath(":id")) > 0 {
+		id, err := a.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			a.HandleBadRequest(fmt.Sprintf("invalid approval ID: %s", a.GetStringFromPath(":id")))
+			return
+		}
+		ap, err := dao.GetApproval(id)
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get the approval %d: %v", id, err))
+			return
+		}
+		if ap == nil {
+			a.HandleNotFound(fmt.Sprintf("approval %d not found", id))
+			return
+		}
+		a.approval = ap
+	}
+}
+
+// List is the inbox of the current user, it lists the approvals which the user can review,
+// the pending ones are listed if the status isn't specified
+func (a *ApprovalAPI) List() {
+	query := &models.ApprovalQuery{
+		Type:   a.GetString("type"),
+		Status: a.GetString("status", models.ApprovalPending),
+	}
+	// the system admin reviews the approvals of all the projects and the system
+	if !a.SecurityCtx.IsSysAdmin() {
+		projects, err := a.SecurityCtx.GetMyProjects()
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get the projects of %s: %v", a.SecurityCtx.GetUsername(), err))
+			return
+		}
+		query.ProjectIDs = []int64{}
+		for _, project := range projects {
+			if a.SecurityCtx.HasAllPerm(project.ProjectID) {
+				query.ProjectIDs = append(query.ProjectIDs, project.ProjectID)
+			}
+		}
+	}
+
+	total, err := dao.CountApprovals(query)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to count the approvals: %v", err))
+		return
+	}
+	query.Page, query.Size = a.GetPaginationParams()
+	approvals, err := dao.ListApprovals(query)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to list the approvals: %v", err))
+		return
+	}
+	a.SetPaginationHeader(total, query.Page, query.Size)
+	a.Data["json"] = approvals
+	a.ServeJSON()
+}
+
+// Get returns the approval, it's visible to the requester and the reviewers
+func (a *ApprovalAPI) Get() {
+	if a.approval.Requester != a.SecurityCtx.GetUsername() && !a.canReview() {
+		a.HandleForbidden(a.SecurityCtx.GetUsername())
+		return
+	}
+	a.Data["json"] = a.approval
+	a.ServeJSON()
+}
+
+// Approve approves the approval and executes the action of it
+func (a *ApprovalAPI) Approve() {
+	a.review(true)
+}
+
+// Reject rejects the approval
+func (a *ApprovalAPI) Reject() {
+	a.review(false)
+}
+
+func (a *ApprovalAPI) review(approved bool) {
+	if !a.canReview() {
+		a.HandleForbidden(a.SecurityCtx.GetUsername())
+		return
+	}
+	review := &models.ApprovalReview{}
+	if len(a.Ctx.Input.CopyBody(1<<32)) > 0 {
+		a.DecodeJSONReq(review)
+	}
+	if err := approval.Review(a.approval, approved, a.SecurityCtx.GetUsername(), review.Comment); err != nil {
+		if err == approval.ErrNotPending {
+			a.HandleConflict(fmt.Sprintf("approval %d is not pending", a.approval.ID))
+			return
+		}
+		a.HandleInternalServerError(fmt.Sprintf("failed to review the approval %d: %v", a.approval.ID, err))
+		return
+	}
+	a.Data["json"] = a.approval
+	a.ServeJSON()
+}
+
+// canReview returns whether the user is the admin of the project of the approval,
+// the approvals without project are reviewed by the system admin
+func (a *ApprovalAPI) canReview() bool {
+	if a.SecurityCtx.IsSysAdmin() {
+		return true
+	}
+	return a.approval.ProjectID > 0 && a.SecurityCtx.HasAllPerm(a.approval.ProjectID)
+}
@@ -0,0 +1,129 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalAPI(t *testing.T) {
+	id, err := dao.AddApproval(&models.Approval{
+		Type:      models.ApprovalProjectCreation,
+		Requester: nonSysAdmin.Name,
+		Summary:   "create project project_for_test_approval",
+		Payload:   `{"project_name":"project_for_test_approval","metadata":{"public":"false"}}`,
+		Status:    models.ApprovalPending,
+	})
+	require.Nil(t, err)
+	defer dao.ClearTable(models.ApprovalTable)
+
+	path := fmt.Sprintf("/api/approvals/%d", id)
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/approvals",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 200, the requester gets the approval
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        path,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 403, only the system admin reviews the creations of project
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path + "/approve",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/approvals/10000/reject",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the approval isn't in the inbox of the non system admin
+	approvals := []*models.Approval{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/approvals",
+		credential: nonSysAdmin,
+	}, &approvals)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(approvals))
+
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/approvals",
+		credential: sysAdmin,
+	}, &approvals)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(approvals))
+	assert.Equal(t, id, approvals[0].ID)
+
+	approval := &models.Approval{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    path + "/approve",
+		bodyJSON: &models.ApprovalReview{
+			Comment: "approved",
+		},
+		credential: sysAdmin,
+	}, approval)
+	require.Nil(t, err)
+	assert.Equal(t, models.ApprovalApproved, approval.Status)
+	assert.Equal(t, "approved", approval.Comment)
+
+	project, err := dao.GetProjectByName("project_for_test_approval")
+	require.Nil(t, err)
+	require.NotNil(t, project)
+	defer dao.DeleteProject(project.ProjectID)
+	owner, err := dao.GetUser(models.User{UserID: project.OwnerID})
+	require.Nil(t, err)
+	require.NotNil(t, owner)
+	assert.Equal(t, nonSysAdmin.Name, owner.Username)
+
+	// 409, the approval has been reviewed
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPost,
+			url:        path + "/reject",
+			credential: sysAdmin,
+		},
+		code: http.StatusConflict,
+	})
+}
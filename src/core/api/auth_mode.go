@@ -0,0 +1,174 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/authmode"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// AuthModeAPI checks the users before the auth mode is switched and migrates them
+// to the new auth mode
+type AuthModeAPI struct {
+	BaseController
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (a *AuthModeAPI) Prepare() {
+	a.BaseController.Prepare()
+	if !a.SecurityCtx.IsAuthenticated() {
+		a.HandleUnauthorized()
+		return
+	}
+	if !a.SecurityCtx.IsSysAdmin() {
+		a.HandleForbidden(a.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// validate checks the target auth mode against the current one, the error is handled
+// and false is returned if it's invalid
+func (a *AuthModeAPI) validate(target string) bool {
+	current, err := config.AuthMode()
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get the auth mode: %v", err))
+		return false
+	}
+	if err = authmode.Validate(current, target); err != nil {
+		a.HandleBadRequest(err.Error())
+		return false
+	}
+	return true
+}
+
+// Preflight reports the users which can't be mapped and the memberships which grant
+// nothing if the auth mode is switched to the one in the query, nothing is changed
+func (a *AuthModeAPI) Preflight() {
+	target := a.GetString("auth_mode")
+	if !a.validate(target) {
+		return
+	}
+	report, err := authmode.Preflight(target)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to check the users for auth mode %s: %v", target, err))
+		return
+	}
+	a.Data["json"] = report
+	a.ServeJSON()
+}
+
+// Post triggers the migration, it runs in the background and only one can run at the same time
+func (a *AuthModeAPI) Post() {
+	req := &models.AuthModeMigrationReq{}
+	a.DecodeJSONReq(req)
+	if !a.validate(req.AuthMode) {
+		return
+	}
+
+	for _, status := range []string{models.JobPending, models.JobRunning} {
+		jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+			Name:   common_job.AuthModeMigration,
+			Status: status,
+		})
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+			return
+		}
+		if len(jobs) > 0 {
+			a.HandleConflict(fmt.Sprintf("the auth mode migration job %d is %s", jobs[0].ID, status))
+			return
+		}
+	}
+
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: common_job.AuthModeMigration,
+		Kind: common_job.JobKindGeneric,
+	})
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to add admin job: %v", err))
+		return
+	}
+	go migrateAuthMode(id, req.AuthMode, req.Force)
+	a.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List returns the latest 10 migration jobs
+func (a *AuthModeAPI) List() {
+	jobs, err := dao.GetTop10AdminJobsOfName(common_job.AuthModeMigration)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+		return
+	}
+	if jobs == nil {
+		jobs = []*models.AdminJob{}
+	}
+	a.Data["json"] = jobs
+	a.ServeJSON()
+}
+
+// Get returns the migration job, the count of converted users is in the progress when it finishes
+func (a *AuthModeAPI) Get() {
+	id, err := a.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		a.HandleBadRequest("invalid ID")
+		return
+	}
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		ID:   id,
+		Name: common_job.AuthModeMigration,
+	})
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get admin job %d: %v", id, err))
+		return
+	}
+	if len(jobs) == 0 {
+		a.HandleNotFound(fmt.Sprintf("auth mode migration job %d not found", id))
+		return
+	}
+	a.Data["json"] = jobs[0]
+	a.ServeJSON()
+}
+
+func migrateAuthMode(id int64, target string, force bool) {
+	updateStatus := func(status string) {
+		if err := dao.UpdateAdminJobStatus(id, status); err != nil {
+			log.Errorf("failed to update the status of admin job %d to %s: %v", id, status, err)
+		}
+	}
+	updateProgress := func(progress string) {
+		if err := dao.UpdateAdminJobProgress(id, progress); err != nil {
+			log.Errorf("failed to update the progress of admin job %d: %v", id, err)
+		}
+	}
+
+	updateStatus(models.JobRunning)
+	_, converted, err := authmode.Migrate(target, force, updateProgress)
+	if err != nil {
+		log.Errorf("failed to migrate to auth mode %s: %v", target, err)
+		updateProgress(err.Error())
+		updateStatus(models.JobError)
+		return
+	}
+	updateProgress(fmt.Sprintf("the auth mode is switched to %s, %d users are converted", target, converted))
+	updateStatus(models.JobFinished)
+}
@@ -0,0 +1,111 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+var (
+	authModePreflightPath  = "/api/system/auth_mode/preflight"
+	authModeMigrationsPath = "/api/system/auth_mode/migrations"
+)
+
+func TestAuthModeAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    authModePreflightPath + "?auth_mode=ldap_auth",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        authModePreflightPath + "?auth_mode=ldap_auth",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        authModeMigrationsPath,
+				credential: nonSysAdmin,
+				bodyJSON: &models.AuthModeMigrationReq{
+					AuthMode: "ldap_auth",
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid auth mode
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        authModePreflightPath + "?auth_mode=invalid",
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the current auth mode
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        authModePreflightPath + "?auth_mode=db_auth",
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the current auth mode
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        authModeMigrationsPath,
+				credential: sysAdmin,
+				bodyJSON: &models.AuthModeMigrationReq{
+					AuthMode: "db_auth",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        authModeMigrationsPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        authModeMigrationsPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
@@ -15,16 +15,20 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	yaml "github.com/ghodss/yaml"
 	"github.com/goharbor/harbor/src/common/api"
 	"github.com/goharbor/harbor/src/common/security"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/bundle"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/filter"
 	"github.com/goharbor/harbor/src/core/promgr"
-	"github.com/goharbor/harbor/src/core/utils"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
 )
 
 const (
@@ -68,7 +72,7 @@ func (b *BaseController) Prepare() {
 
 // RenderFormatedError renders errors with well formted style `{"error": "This is an error"}`
 func (b *BaseController) RenderFormatedError(code int, err error) {
-	formatedErr := utils.WrapError(err)
+	formatedErr := coreutils.WrapError(err)
 	log.Errorf("%s %s failed with error: %s", b.Ctx.Request.Method, b.Ctx.Request.URL.String(), formatedErr.Error())
 	b.RenderError(code, formatedErr.Error())
 }
@@ -123,6 +127,37 @@ func (b *BaseController) WriteYamlData(object interface{}) {
 	w.Write(yData)
 }
 
+// GetNameQuery returns the "name" in the query string and whether it's matched exactly,
+// the name is matched fuzzily unless "exact_match" is true
+func (b *BaseController) GetNameQuery() (string, bool, error) {
+	exact, err := b.GetBool("exact_match", false)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid exact_match: %s", b.GetString("exact_match"))
+	}
+	return b.GetString("name"), exact, nil
+}
+
+// GetTimeRangeQuery returns the time range specified by the "begin_timestamp" and
+// "end_timestamp" in the query string, nil is returned for the one not specified
+func (b *BaseController) GetTimeRangeQuery() (*time.Time, *time.Time, error) {
+	var begin, end *time.Time
+	if timestamp := b.GetString("begin_timestamp"); len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid begin_timestamp: %s", timestamp)
+		}
+		begin = t
+	}
+	if timestamp := b.GetString("end_timestamp"); len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid end_timestamp: %s", timestamp)
+		}
+		end = t
+	}
+	return begin, end, nil
+}
+
 // Init related objects/configurations for the API controllers
 func Init() error {
 	registerHealthCheckers()
@@ -137,6 +172,7 @@ func Init() error {
 	}
 
 	chartController = chartCtl
+	bundle.SetChartStore(chartCtl)
 
 	return nil
 }
@@ -0,0 +1,118 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// BlocklistAPI handles request to /api/system/blocklist, the digests in the blocklist are
+// refused on pull and push across all the projects
+type BlocklistAPI struct {
+	BaseController
+	blocked *models.BlockedDigest
+}
+
+// Prepare validates the user, only the system admin is allowed to manage the blocklist
+func (b *BlocklistAPI) Prepare() {
+	b.BaseController.Prepare()
+	if !b.SecurityCtx.IsAuthenticated() {
+		b.HandleUnauthorized()
+		return
+	}
+	if !b.SecurityCtx.IsSysAdmin() {
+		b.HandleForbidden(b.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(b.GetStringFromPath(":id")) > 0 {
+		id, err := b.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			b.HandleBadRequest(fmt.Sprintf("invalid ID: %s", b.GetStringFromPath(":id")))
+			return
+		}
+		blocked, err := dao.GetBlockedDigest(id)
+		if err != nil {
+			b.HandleInternalServerError(fmt.Sprintf("failed to get the blocked digest %d: %v", id, err))
+			return
+		}
+		if blocked == nil {
+			b.HandleNotFound(fmt.Sprintf("blocked digest %d not found", id))
+			return
+		}
+		b.blocked = blocked
+	}
+}
+
+// Post adds the digest into the blocklist
+func (b *BlocklistAPI) Post() {
+	blocked := &models.BlockedDigest{}
+	b.DecodeJSONReqAndValidate(blocked)
+	id, err := dao.AddBlockedDigest(&models.BlockedDigest{
+		Digest:  blocked.Digest,
+		Reason:  blocked.Reason,
+		Creator: b.SecurityCtx.GetUsername(),
+	})
+	if err == dao.ErrDupRows {
+		b.HandleConflict(fmt.Sprintf("the digest %s is blocked already", blocked.Digest))
+		return
+	}
+	if err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to block the digest %s: %v", blocked.Digest, err))
+		return
+	}
+	b.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the blocked digests, the "digest" in the query string is matched fuzzily
+func (b *BlocklistAPI) List() {
+	query := &models.BlockedDigestQuery{
+		Digest: b.GetString("digest"),
+	}
+	total, err := dao.CountBlockedDigests(query)
+	if err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to count the blocked digests: %v", err))
+		return
+	}
+	query.Page, query.Size = b.GetPaginationParams()
+	blocked, err := dao.ListBlockedDigests(query)
+	if err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to list the blocked digests: %v", err))
+		return
+	}
+
+	b.SetPaginationHeader(total, query.Page, query.Size)
+	b.Data["json"] = blocked
+	b.ServeJSON()
+}
+
+// Get gets the blocked digest specified by ID
+func (b *BlocklistAPI) Get() {
+	b.Data["json"] = b.blocked
+	b.ServeJSON()
+}
+
+// Delete removes the digest from the blocklist, the pulls and pushes of it are allowed then
+func (b *BlocklistAPI) Delete() {
+	if err := dao.DeleteBlockedDigest(b.blocked.ID); err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to delete the blocked digest %d: %v", b.blocked.ID, err))
+		return
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var blocklistPath = "/api/system/blocklist"
+
+func TestBlocklistAPI(t *testing.T) {
+	digest := "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    blocklistPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        blocklistPath,
+				credential: nonSysAdmin,
+				bodyJSON:   &models.BlockedDigest{Digest: digest},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid digest
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        blocklistPath,
+				credential: sysAdmin,
+				bodyJSON:   &models.BlockedDigest{Digest: "latest"},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        blocklistPath,
+				credential: sysAdmin,
+				bodyJSON: &models.BlockedDigest{
+					Digest: digest,
+					Reason: "known-compromised",
+				},
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        blocklistPath,
+				credential: sysAdmin,
+				bodyJSON:   &models.BlockedDigest{Digest: digest},
+			},
+			code: http.StatusConflict,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        blocklistPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	defer dao.ClearTable(models.BlockedDigestTable)
+	runCodeCheckingCases(t, cases...)
+
+	blocked := []*models.BlockedDigest{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    blocklistPath,
+		queryStruct: struct {
+			Digest string `url:"digest"`
+		}{
+			Digest: "3333",
+		},
+		credential: sysAdmin,
+	}, &blocked)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(blocked))
+	assert.Equal(t, digest, blocked[0].Digest)
+	assert.Equal(t, "known-compromised", blocked[0].Reason)
+	assert.Equal(t, "admin", blocked[0].Creator)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        fmt.Sprintf("%s/%d", blocklistPath, blocked[0].ID),
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	b, err := dao.GetBlockedDigest(blocked[0].ID)
+	require.Nil(t, err)
+	assert.Nil(t, b)
+}
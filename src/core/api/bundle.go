@@ -0,0 +1,71 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/core/bundle"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// BundleAPI handles request to /api/bundles
+type BundleAPI struct {
+	BaseController
+}
+
+// Prepare validates the user
+func (b *BundleAPI) Prepare() {
+	b.BaseController.Prepare()
+	if !b.SecurityCtx.IsAuthenticated() {
+		b.HandleUnauthorized()
+		return
+	}
+	if !b.SecurityCtx.IsSysAdmin() {
+		b.HandleForbidden(b.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Import imports the offline bundle, the projects which don't exist are created
+func (b *BundleAPI) Import() {
+	file, _, err := b.GetFile("bundle")
+	if err != nil {
+		b.HandleBadRequest(fmt.Sprintf("failed to get the bundle: %v", err))
+		return
+	}
+	defer file.Close()
+
+	importer := bundle.NewImporter(b.ProjectMgr, chartStore())
+	summary, err := importer.Import(file, b.SecurityCtx.GetUsername())
+	if err != nil {
+		if _, ok := err.(*bundle.InvalidBundleError); ok {
+			b.HandleBadRequest(fmt.Sprintf("invalid bundle: %v", err))
+			return
+		}
+		b.HandleInternalServerError(fmt.Sprintf("failed to import the bundle: %v", err))
+		return
+	}
+	b.Data["json"] = summary
+	b.ServeJSON()
+}
+
+// chartStore returns the chart controller if chartmuseum is enabled, otherwise nil
+func chartStore() bundle.ChartStore {
+	if !config.WithChartMuseum() || chartController == nil {
+		return nil
+	}
+	return chartController
+}
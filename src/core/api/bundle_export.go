@@ -0,0 +1,150 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/bundle"
+)
+
+// BundleExportAPI handles request to /api/bundles/exports
+type BundleExportAPI struct {
+	BaseController
+	export *models.BundleExport
+}
+
+// Prepare validates the user and the export
+func (b *BundleExportAPI) Prepare() {
+	b.BaseController.Prepare()
+	if !b.SecurityCtx.IsAuthenticated() {
+		b.HandleUnauthorized()
+		return
+	}
+	if !b.SecurityCtx.IsSysAdmin() {
+		b.HandleForbidden(b.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(b.GetStringFromPath(":id")) > 0 {
+		id, err := b.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			b.HandleBadRequest(fmt.Sprintf("invalid export ID: %s", b.GetStringFromPath(":id")))
+			return
+		}
+		export, err := dao.GetBundleExport(id)
+		if err != nil {
+			b.HandleInternalServerError(fmt.Sprintf("failed to get the bundle export %d: %v", id, err))
+			return
+		}
+		if export == nil {
+			b.HandleNotFound(fmt.Sprintf("bundle export %d not found", id))
+			return
+		}
+		b.export = export
+	}
+}
+
+// Post starts to export the images, charts and metadata of the projects into an offline
+// bundle in background
+func (b *BundleExportAPI) Post() {
+	req := &models.BundleExportRequest{}
+	b.DecodeJSONReq(req)
+	if len(req.Projects) == 0 {
+		b.HandleBadRequest("projects are required")
+		return
+	}
+	projects := []string{}
+	for _, name := range req.Projects {
+		project, err := b.ProjectMgr.Get(name)
+		if err != nil {
+			b.ParseAndHandleError(fmt.Sprintf("failed to get project %s", name), err)
+			return
+		}
+		if project == nil {
+			b.HandleNotFound(b.T(i18n.MsgProjectNotFound, name))
+			return
+		}
+		projects = append(projects, project.Name)
+	}
+
+	id, err := bundle.Export(projects, b.SecurityCtx.GetUsername())
+	if err != nil {
+		if err == bundle.ErrExportRunning {
+			b.HandleConflict(err.Error())
+			return
+		}
+		b.HandleInternalServerError(fmt.Sprintf("failed to export the bundle: %v", err))
+		return
+	}
+	b.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the bundle exports
+func (b *BundleExportAPI) List() {
+	query := &models.BundleExportQuery{
+		Status: b.GetString("status"),
+	}
+	total, err := dao.CountBundleExports(query)
+	if err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to count the bundle exports: %v", err))
+		return
+	}
+	query.Page, query.Size = b.GetPaginationParams()
+	exports, err := dao.ListBundleExports(query)
+	if err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to list the bundle exports: %v", err))
+		return
+	}
+
+	b.SetPaginationHeader(total, query.Page, query.Size)
+	b.Data["json"] = exports
+	b.ServeJSON()
+}
+
+// Get gets the bundle export specified by ID
+func (b *BundleExportAPI) Get() {
+	b.Data["json"] = b.export
+	b.ServeJSON()
+}
+
+// Download downloads the bundle of the export
+func (b *BundleExportAPI) Download() {
+	if b.export.Status != models.BundleExportSucceeded {
+		b.HandleStatusPreconditionFailed(fmt.Sprintf("the bundle export %d is %s", b.export.ID, b.export.Status))
+		return
+	}
+	filename := fmt.Sprintf("harbor-bundle-%s-%d.tar.gz",
+		b.export.CreationTime.UTC().Format("20060102150405"), b.export.ID)
+	b.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), "application/gzip")
+	b.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Disposition"), "attachment; filename="+filename)
+	http.ServeFile(b.Ctx.ResponseWriter, b.Ctx.Request, bundle.Path(b.export.ID))
+}
+
+// Delete deletes the bundle export and its bundle
+func (b *BundleExportAPI) Delete() {
+	if b.export.Status == models.BundleExportRunning {
+		b.HandleConflict(fmt.Sprintf("the bundle export %d is running", b.export.ID))
+		return
+	}
+	if err := bundle.Delete(b.export); err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to delete the bundle export %d: %v", b.export.ID, err))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+func TestBundleAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/bundles/exports",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/bundles/exports",
+				bodyJSON:   &models.BundleExportRequest{Projects: []string{"library"}},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no projects
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/bundles/exports",
+				bodyJSON:   &models.BundleExportRequest{},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, project not found
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/bundles/exports",
+				bodyJSON:   &models.BundleExportRequest{Projects: []string{"non-existing-project"}},
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, export not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/bundles/exports/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/bundles/import",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no bundle
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/bundles/import",
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,46 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+)
+
+// certificates returns the summaries of the certificates in the PEM encoded CA bundle,
+// the list is empty if the bundle is empty
+func certificates(bundle string) ([]*models.Certificate, error) {
+	certs := []*models.Certificate{}
+	if len(bundle) == 0 {
+		return certs, nil
+	}
+	parsed, err := registry.ParseCABundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range parsed {
+		certs = append(certs, &models.Certificate{
+			Subject:     cert.Subject.String(),
+			Issuer:      cert.Issuer.String(),
+			NotBefore:   cert.NotBefore.UTC(),
+			NotAfter:    cert.NotAfter.UTC(),
+			Fingerprint: fmt.Sprintf("sha256:%x", sha256.Sum256(cert.Raw)),
+		})
+	}
+	return certs, nil
+}
@@ -0,0 +1,147 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	bundle := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}))
+
+	certs, err := certificates("")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(certs))
+
+	certs, err = certificates(bundle)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(certs))
+	assert.Equal(t, server.Certificate().NotAfter.UTC(), certs[0].NotAfter)
+	assert.Equal(t, 71, len(certs[0].Fingerprint))
+
+	_, err = certificates("invalid")
+	assert.NotNil(t, err)
+}
+
+func TestTargetCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	bundle := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}))
+
+	id, err := dao.AddRepTarget(models.RepTarget{
+		URL:  "https://registry.example.com",
+		Name: "target-ca-bundle",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteRepTarget(id)
+	path := fmt.Sprintf("/api/targets/%d/ca_bundle", id)
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        path,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/targets/10000/ca_bundle",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, invalid bundle
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.CABundleReq{
+					CABundle: "invalid",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.CABundleReq{
+					CABundle: bundle,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	certs := []*models.Certificate{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: sysAdmin,
+	}, &certs)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(certs))
+
+	// the bundle is encrypted in the database
+	target, err := dao.GetRepTarget(id)
+	require.Nil(t, err)
+	assert.NotEqual(t, bundle, target.CABundle)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        path,
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	target, err = dao.GetRepTarget(id)
+	require.Nil(t, err)
+	assert.Equal(t, "", target.CABundle)
+}
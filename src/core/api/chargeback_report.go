@@ -0,0 +1,174 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/chargeback"
+)
+
+// ChargebackReportAPI handles request to /api/chargeback/reports
+type ChargebackReportAPI struct {
+	BaseController
+	report *models.ChargebackReport
+}
+
+// chargebackReportDetail is the report with the usage of the projects
+type chargebackReportDetail struct {
+	*models.ChargebackReport
+	Items []*models.ChargebackReportItem `json:"items"`
+}
+
+// Prepare validates the user, only the system admin is allowed to access the chargeback reports
+func (c *ChargebackReportAPI) Prepare() {
+	c.BaseController.Prepare()
+	if !c.SecurityCtx.IsAuthenticated() {
+		c.HandleUnauthorized()
+		return
+	}
+	if !c.SecurityCtx.IsSysAdmin() {
+		c.HandleForbidden(c.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(c.GetStringFromPath(":id")) > 0 {
+		id, err := c.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			c.HandleBadRequest(fmt.Sprintf("invalid report ID: %s", c.GetStringFromPath(":id")))
+			return
+		}
+		report, err := dao.GetChargebackReport(id)
+		if err != nil {
+			c.HandleInternalServerError(fmt.Sprintf("failed to get the chargeback report %d: %v", id, err))
+			return
+		}
+		if report == nil {
+			c.HandleNotFound(fmt.Sprintf("chargeback report %d not found", id))
+			return
+		}
+		c.report = report
+	}
+}
+
+// Post starts to generate the chargeback report of the billing period in background,
+// the last calendar month is used if the period isn't specified
+func (c *ChargebackReportAPI) Post() {
+	req := &models.ChargebackReportRequest{}
+	c.DecodeJSONReq(req)
+	now := time.Now()
+	if req.StartTime.IsZero() && req.EndTime.IsZero() {
+		req.StartTime, req.EndTime = chargeback.Period(now)
+	}
+	if req.StartTime.IsZero() || req.EndTime.IsZero() {
+		c.HandleBadRequest("both start_time and end_time are required")
+		return
+	}
+	if !req.StartTime.Before(req.EndTime) {
+		c.HandleBadRequest("start_time must be before end_time")
+		return
+	}
+	if req.EndTime.After(now) {
+		c.HandleBadRequest("the billing period hasn't ended")
+		return
+	}
+
+	id, err := chargeback.Generate(req.StartTime, req.EndTime, c.SecurityCtx.GetUsername())
+	if err != nil {
+		if err == chargeback.ErrReportRunning || err == chargeback.ErrReportExists {
+			c.HandleConflict(err.Error())
+			return
+		}
+		c.HandleInternalServerError(fmt.Sprintf("failed to generate the chargeback report: %v", err))
+		return
+	}
+	c.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the chargeback reports without the usage of the projects
+func (c *ChargebackReportAPI) List() {
+	query := &models.ChargebackReportQuery{
+		Status: c.GetString("status"),
+	}
+	total, err := dao.CountChargebackReports(query)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to count the chargeback reports: %v", err))
+		return
+	}
+	query.Page, query.Size = c.GetPaginationParams()
+	reports, err := dao.ListChargebackReports(query)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to list the chargeback reports: %v", err))
+		return
+	}
+
+	c.SetPaginationHeader(total, query.Page, query.Size)
+	c.Data["json"] = reports
+	c.ServeJSON()
+}
+
+// Get gets the chargeback report with the usage of the projects, the usage is returned in
+// CSV if the format is "csv" and in JSON otherwise
+func (c *ChargebackReportAPI) Get() {
+	format := c.GetString("format")
+	if format != "" && format != "json" && format != "csv" {
+		c.HandleBadRequest(fmt.Sprintf("invalid format: %s", format))
+		return
+	}
+	items, err := dao.ListChargebackReportItems(c.report.ID)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to list the items of chargeback report %d: %v", c.report.ID, err))
+		return
+	}
+	if format != "csv" {
+		c.Data["json"] = &chargebackReportDetail{
+			ChargebackReport: c.report,
+			Items:            items,
+		}
+		c.ServeJSON()
+		return
+	}
+
+	if c.report.Status != models.ChargebackReportSucceeded {
+		c.HandleStatusPreconditionFailed(fmt.Sprintf("the chargeback report %d is %s", c.report.ID, c.report.Status))
+		return
+	}
+	w := c.Ctx.ResponseWriter
+	w.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/csv")
+	w.Header().Set(http.CanonicalHeaderKey("Content-Disposition"),
+		fmt.Sprintf("attachment; filename=chargeback-%s-%s.csv",
+			c.report.StartTime.UTC().Format("20060102"), c.report.EndTime.UTC().Format("20060102")))
+	if err = chargeback.WriteCSV(w, items); err != nil {
+		// the status code is already sent, only log the error
+		log.Errorf("failed to write the chargeback report %d: %v", c.report.ID, err)
+	}
+}
+
+// Delete deletes the chargeback report
+func (c *ChargebackReportAPI) Delete() {
+	if c.report.Status == models.ChargebackReportRunning {
+		c.HandleConflict(fmt.Sprintf("the chargeback report %d is running", c.report.ID))
+		return
+	}
+	if err := dao.DeleteChargebackReport(c.report.ID); err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to delete the chargeback report %d: %v", c.report.ID, err))
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+var chargebackReportPath = "/api/chargeback/reports"
+
+func TestChargebackReportAPI(t *testing.T) {
+	now := time.Now().UTC()
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    chargebackReportPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chargebackReportPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no end time
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    chargebackReportPath,
+				bodyJSON: &models.ChargebackReportRequest{
+					StartTime: now.Add(-time.Hour),
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, start time after end time
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    chargebackReportPath,
+				bodyJSON: &models.ChargebackReportRequest{
+					StartTime: now.Add(-time.Hour),
+					EndTime:   now.Add(-2 * time.Hour),
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the period hasn't ended
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    chargebackReportPath,
+				bodyJSON: &models.ChargebackReportRequest{
+					StartTime: now.Add(-time.Hour),
+					EndTime:   now.Add(time.Hour),
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chargebackReportPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404, report not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chargebackReportPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
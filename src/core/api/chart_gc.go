@@ -0,0 +1,185 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	job_models "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
+)
+
+// ChartGCAPI triggers the job which removes the chart packages and provenance files
+// deleted from the index of chart repository but still in the chart storage
+type ChartGCAPI struct {
+	BaseController
+}
+
+type chartGCReq struct {
+	// only report the orphaned files without removing them
+	DryRun bool `json:"dry_run"`
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (c *ChartGCAPI) Prepare() {
+	c.BaseController.Prepare()
+	if !c.SecurityCtx.IsAuthenticated() {
+		c.HandleUnauthorized()
+		return
+	}
+	if !c.SecurityCtx.IsSysAdmin() {
+		c.HandleForbidden(c.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Post triggers the chart GC job, only one job can run at the same time
+func (c *ChartGCAPI) Post() {
+	if !config.WithChartMuseum() {
+		c.SendBadRequestError(errors.New("Harbor isn't deployed with chart repository"))
+		return
+	}
+	req := &chartGCReq{}
+	if c.Ctx.Request.ContentLength > 0 {
+		c.DecodeJSONReq(req)
+	}
+	if !req.DryRun && !c.Confirmed() {
+		return
+	}
+
+	for _, status := range []string{models.JobPending, models.JobRunning} {
+		jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+			Name:   common_job.ChartGC,
+			Status: status,
+		})
+		if err != nil {
+			c.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+			return
+		}
+		if len(jobs) > 0 {
+			c.HandleConflict(fmt.Sprintf("the chart GC job %d is %s", jobs[0].ID, status))
+			return
+		}
+	}
+
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: common_job.ChartGC,
+		Kind: common_job.JobKindGeneric,
+	})
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to add admin job: %v", err))
+		return
+	}
+	uuid, err := utils_core.GetJobServiceClient().SubmitJob(&job_models.JobData{
+		Name: common_job.ChartGC,
+		Parameters: map[string]interface{}{
+			"dry_run": req.DryRun,
+		},
+		Metadata: &job_models.JobMetadata{
+			JobKind:  common_job.JobKindGeneric,
+			IsUnique: true,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d",
+			config.InternalCoreURL(), id),
+	})
+	if err != nil {
+		if err := dao.DeleteAdminJob(id); err != nil {
+			log.Errorf("failed to delete admin job %d: %v", id, err)
+		}
+		c.HandleInternalServerError(fmt.Sprintf("failed to submit the chart GC job: %v", err))
+		return
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to set the UUID of admin job %d: %v", id, err))
+		return
+	}
+	c.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List returns the latest 10 chart GC jobs
+func (c *ChartGCAPI) List() {
+	jobs, err := dao.GetTop10AdminJobsOfName(common_job.ChartGC)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+		return
+	}
+	if jobs == nil {
+		jobs = []*models.AdminJob{}
+	}
+	c.Data["json"] = jobs
+	c.ServeJSON()
+}
+
+// Get returns the chart GC job, the report is in the progress when the job finishes
+func (c *ChartGCAPI) Get() {
+	job := c.getJob()
+	if job == nil {
+		return
+	}
+	c.Data["json"] = job
+	c.ServeJSON()
+}
+
+// GetLog returns the log of the chart GC job which lists the orphaned files
+func (c *ChartGCAPI) GetLog() {
+	job := c.getJob()
+	if job == nil {
+		return
+	}
+	data, err := utils_core.GetJobServiceClient().GetJobLog(job.UUID)
+	if err != nil {
+		if httpErr, ok := err.(*common_http.Error); ok {
+			c.RenderError(httpErr.Code, httpErr.Message)
+			return
+		}
+		c.HandleInternalServerError(fmt.Sprintf("failed to get the log of job %d: %v", job.ID, err))
+		return
+	}
+	c.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Length"), strconv.Itoa(len(data)))
+	c.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/plain")
+	if _, err = c.Ctx.ResponseWriter.Write(data); err != nil {
+		log.Errorf("failed to write the log of job %d: %v", job.ID, err)
+	}
+}
+
+func (c *ChartGCAPI) getJob() *models.AdminJob {
+	id, err := c.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		c.HandleBadRequest("invalid ID")
+		return nil
+	}
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		ID:   id,
+		Name: common_job.ChartGC,
+	})
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to get admin job %d: %v", id, err))
+		return nil
+	}
+	if len(jobs) == 0 {
+		c.HandleNotFound(fmt.Sprintf("chart GC job %d not found", id))
+		return nil
+	}
+	return jobs[0]
+}
@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+var chartGCPath = "/api/system/chart_gc"
+
+func TestChartGCAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    chartGCPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        chartGCPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, Harbor isn't deployed with chart repository
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        chartGCPath,
+				bodyJSON:   &chartGCReq{DryRun: true},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chartGCPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chartGCPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chartGCPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        chartGCPath + "/10000/log",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,169 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/compliance"
+)
+
+// the max time range of the audit logs included in a compliance report
+const maxComplianceReportRange = 366 * 24 * time.Hour
+
+// ComplianceReportAPI handles request to /api/projects/{}/compliance_reports
+type ComplianceReportAPI struct {
+	BaseController
+	project *models.Project
+	report  *models.ComplianceReport
+}
+
+// Prepare validates the user and the project, only the project admin and system admin
+// are allowed to generate and download the compliance reports
+func (c *ComplianceReportAPI) Prepare() {
+	c.BaseController.Prepare()
+	if !c.SecurityCtx.IsAuthenticated() {
+		c.HandleUnauthorized()
+		return
+	}
+
+	pid, err := c.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		c.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", c.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := c.ProjectMgr.Get(pid)
+	if err != nil {
+		c.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		c.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	c.project = project
+
+	if !c.SecurityCtx.HasAllPerm(pid) {
+		c.HandleForbidden(c.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(c.GetStringFromPath(":id")) > 0 {
+		id, err := c.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			c.HandleBadRequest(fmt.Sprintf("invalid report ID: %s", c.GetStringFromPath(":id")))
+			return
+		}
+		report, err := dao.GetComplianceReport(id)
+		if err != nil {
+			c.HandleInternalServerError(fmt.Sprintf("failed to get the compliance report %d: %v", id, err))
+			return
+		}
+		if report == nil || report.ProjectID != pid {
+			c.HandleNotFound(fmt.Sprintf("compliance report %d not found", id))
+			return
+		}
+		c.report = report
+	}
+}
+
+// Post starts to generate the compliance report of the project in background
+func (c *ComplianceReportAPI) Post() {
+	req := &models.ComplianceReportRequest{}
+	c.DecodeJSONReq(req)
+	if req.EndTime.IsZero() {
+		req.EndTime = time.Now().UTC()
+	}
+	if req.StartTime.IsZero() {
+		c.HandleBadRequest("start_time is required")
+		return
+	}
+	if !req.StartTime.Before(req.EndTime) {
+		c.HandleBadRequest("start_time must be before end_time")
+		return
+	}
+	if req.EndTime.Sub(req.StartTime) > maxComplianceReportRange {
+		c.HandleBadRequest(fmt.Sprintf("the time range can't exceed %d days", maxComplianceReportRange/(24*time.Hour)))
+		return
+	}
+
+	id, err := compliance.Generate(c.project, req.StartTime, req.EndTime, c.SecurityCtx.GetUsername())
+	if err != nil {
+		if err == compliance.ErrReportRunning {
+			c.HandleConflict(err.Error())
+			return
+		}
+		c.HandleInternalServerError(fmt.Sprintf("failed to generate the compliance report: %v", err))
+		return
+	}
+	c.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the compliance reports of the project
+func (c *ComplianceReportAPI) List() {
+	query := &models.ComplianceReportQuery{
+		ProjectID: c.project.ProjectID,
+		Status:    c.GetString("status"),
+	}
+	total, err := dao.CountComplianceReports(query)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to count the compliance reports: %v", err))
+		return
+	}
+	query.Page, query.Size = c.GetPaginationParams()
+	reports, err := dao.ListComplianceReports(query)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to list the compliance reports: %v", err))
+		return
+	}
+
+	c.SetPaginationHeader(total, query.Page, query.Size)
+	c.Data["json"] = reports
+	c.ServeJSON()
+}
+
+// Get gets the compliance report specified by ID
+func (c *ComplianceReportAPI) Get() {
+	c.Data["json"] = c.report
+	c.ServeJSON()
+}
+
+// Download downloads the bundle of the compliance report
+func (c *ComplianceReportAPI) Download() {
+	if c.report.Status != models.ComplianceReportSucceeded {
+		c.HandleStatusPreconditionFailed(fmt.Sprintf("the compliance report %d is %s", c.report.ID, c.report.Status))
+		return
+	}
+	filename := fmt.Sprintf("compliance-report-%s-%d.tar.gz", c.project.Name, c.report.ID)
+	c.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), "application/gzip")
+	c.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Disposition"), "attachment; filename="+filename)
+	http.ServeFile(c.Ctx.ResponseWriter, c.Ctx.Request, compliance.Path(c.report.ID))
+}
+
+// Delete deletes the compliance report and its bundle
+func (c *ComplianceReportAPI) Delete() {
+	if c.report.Status == models.ComplianceReportRunning {
+		c.HandleConflict(fmt.Sprintf("the compliance report %d is running", c.report.ID))
+		return
+	}
+	if err := compliance.Delete(c.report.ID); err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to delete the compliance report %d: %v", c.report.ID, err))
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+var complianceReportPath = "/api/projects/1/compliance_reports"
+
+func TestComplianceReportAPI(t *testing.T) {
+	now := time.Now().UTC()
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    complianceReportPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        complianceReportPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404, project not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/compliance_reports",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, no start time
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        complianceReportPath,
+				bodyJSON:   &models.ComplianceReportRequest{},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, start time after end time
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    complianceReportPath,
+				bodyJSON: &models.ComplianceReportRequest{
+					StartTime: now,
+					EndTime:   now.Add(-time.Hour),
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the range is too large
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    complianceReportPath,
+				bodyJSON: &models.ComplianceReportRequest{
+					StartTime: now.Add(-2 * maxComplianceReportRange),
+					EndTime:   now,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        complianceReportPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404, report not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        complianceReportPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, report not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        complianceReportPath + "/10000/download",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
@@ -17,15 +17,26 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
+	"sort"
 
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/database"
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/egress"
+	"github.com/goharbor/harbor/src/core/service/token"
+	"github.com/goharbor/harbor/src/core/utils"
+	"github.com/robfig/cron"
 )
 
+// the min size in KB of the limit of JSON request bodies
+const minJSONBodySize = 64
+
 // ConfigAPI ...
 type ConfigAPI struct {
 	BaseController
@@ -108,6 +119,144 @@ func (c *ConfigAPI) Put() {
 		c.CustomAbort(http.StatusBadRequest, err.Error())
 	}
 
+	c.update(cfg)
+}
+
+// Apply handles PUT to /api/configurations:apply, the configurations in the document are
+// validated and compared with the current ones, the changed ones are applied at once only
+// if all of them are valid, nothing is applied if dry_run is true. The configurations
+// omitted in the document are kept
+func (c *ConfigAPI) Apply() {
+	dryRun, err := c.GetBool("dry_run", false)
+	if err != nil {
+		c.HandleBadRequest(fmt.Sprintf("invalid dry_run: %s", c.GetString("dry_run")))
+		return
+	}
+	m := map[string]interface{}{}
+	c.DecodeJSONReq(&m)
+
+	result := &configApplyResult{
+		DryRun:  dryRun,
+		Errors:  []string{},
+		Changes: []*configChange{},
+	}
+	valid := map[string]bool{}
+	for _, k := range common.HarborValidKeys {
+		valid[k] = true
+	}
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cfg := map[string]interface{}{}
+	for _, k := range keys {
+		if !valid[k] {
+			result.Errors = append(result.Errors, fmt.Sprintf("unknown configuration %s", k))
+			continue
+		}
+		cfg[k] = m[k]
+		// the auth mode is validated with the other configurations below
+		if k == common.AUTHMode {
+			continue
+		}
+		isSysErr, err := validateCfg(map[string]interface{}{k: m[k]})
+		if err != nil {
+			if isSysErr {
+				c.HandleInternalServerError(fmt.Sprintf("failed to validate configurations: %v", err))
+				return
+			}
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+	if len(result.Errors) == 0 {
+		isSysErr, err := validateCfg(cfg)
+		if err != nil {
+			if isSysErr {
+				c.HandleInternalServerError(fmt.Sprintf("failed to validate configurations: %v", err))
+				return
+			}
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+
+	current, err := config.GetSystemCfg()
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to get configurations: %v", err))
+		return
+	}
+	passwords := map[string]bool{}
+	for _, k := range common.HarborPasswordKeys {
+		passwords[k] = true
+	}
+	changed := map[string]interface{}{}
+	for _, k := range keys {
+		v, ok := cfg[k]
+		// the numbers may be loaded as integers or floats, so they're compared as texts
+		if !ok || (current[k] != nil && fmt.Sprint(current[k]) == fmt.Sprint(v)) {
+			continue
+		}
+		changed[k] = v
+		change := &configChange{
+			Key: k,
+		}
+		// the values of the passwords are never returned
+		if !passwords[k] {
+			change.Current = current[k]
+			change.Desired = v
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	if len(result.Errors) > 0 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Data["json"] = result
+		c.ServeJSON()
+		return
+	}
+	if !dryRun && len(changed) > 0 {
+		c.update(changed)
+		result.Applied = true
+	}
+	c.WriteJSONData(result)
+}
+
+// configChange is a configuration which differs from the current one
+type configChange struct {
+	Key     string      `json:"key"`
+	Current interface{} `json:"current,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+type configApplyResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Applied bool            `json:"applied"`
+	Errors  []string        `json:"errors"`
+	Changes []*configChange `json:"changes"`
+}
+
+// update uploads the validated configurations and handles the changes
+func (c *ConfigAPI) update(cfg map[string]interface{}) {
+	// the configurations before the update are recorded in the access log with the changes
+	current, err := config.GetSystemCfg()
+	if err != nil {
+		log.Errorf("failed to get configurations: %v", err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	old := map[string]interface{}{}
+	for k := range cfg {
+		if v, ok := current[k]; ok {
+			old[k] = v
+		}
+	}
+
+	oldCVSSSource, err := config.CVSSSource()
+	if err != nil {
+		log.Errorf("failed to get the source of CVSS: %v", err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
 	if err := config.Upload(cfg); err != nil {
 		log.Errorf("failed to upload configurations: %v", err)
 		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -122,6 +271,22 @@ func (c *ConfigAPI) Put() {
 	if err := watchConfigChanges(cfg); err != nil {
 		log.Errorf("Failed to watch configuration change with error: %s\n", err)
 	}
+
+	if err := accesslog.AddChanges(models.AccessLog{
+		Username:  c.SecurityCtx.GetUsername(),
+		RepoTag:   "N/A",
+		Operation: "update_config",
+	}, old, cfg, common.HarborPasswordKeys...); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
+
+	// the stored scan reports are bucketed with the new source of CVSS to keep the gates consistent
+	if source, ok := cfg[common.CVSSSource]; ok && source != oldCVSSSource && config.WithClair() {
+		if _, err := utils.RecalculateSeverities(); err != nil {
+			log.Errorf("failed to submit the job recalculating the severity of scan reports: %v", err)
+		}
+	}
+
 }
 
 // Reset system configurations
@@ -133,6 +298,10 @@ func (c *ConfigAPI) Reset() {
 }
 
 func validateCfg(c map[string]interface{}) (bool, error) {
+	// the values which can't be stored fail the update after the validation otherwise
+	if _, err := database.TranslateConfig(c); err != nil {
+		return false, err
+	}
 	strMap := map[string]string{}
 	for k := range common.HarborStringKeysMap {
 		if _, ok := c[k]; !ok {
@@ -231,6 +400,10 @@ func validateCfg(c map[string]interface{}) (bool, error) {
 			common.LDAPScopeOnelevel,
 			common.LDAPScopeSubtree)
 	}
+	// too small limit rejects the request to fix the configurations
+	if size, ok := numMap[common.MaxJSONBodySize]; ok && size > 0 && size < minJSONBodySize {
+		return false, fmt.Errorf("invalid %s, should be 0 or not less than %d", common.MaxJSONBodySize, minJSONBodySize)
+	}
 	for k, n := range numMap {
 		if n < 0 {
 			return false, fmt.Errorf("invalid %s: %d", k, n)
@@ -241,13 +414,75 @@ func validateCfg(c map[string]interface{}) (bool, error) {
 		}
 	}
 
+	if source, ok := strMap[common.CVSSSource]; ok &&
+		source != common.CVSSSourceVendor &&
+		source != common.CVSSSourceNVDV2 &&
+		source != common.CVSSSourceNVDV3 {
+		return false, fmt.Errorf("invalid %s, should be %s, %s or %s",
+			common.CVSSSource,
+			common.CVSSSourceVendor,
+			common.CVSSSourceNVDV2,
+			common.CVSSSourceNVDV3)
+	}
+
 	if crt, ok := strMap[common.ProjectCreationRestriction]; ok &&
 		crt != common.ProCrtRestrEveryone &&
-		crt != common.ProCrtRestrAdmOnly {
-		return false, fmt.Errorf("invalid %s, should be %s or %s",
+		crt != common.ProCrtRestrAdmOnly &&
+		crt != common.ProCrtRestrApproval {
+		return false, fmt.Errorf("invalid %s, should be %s, %s or %s",
 			common.ProjectCreationRestriction,
 			common.ProCrtRestrAdmOnly,
-			common.ProCrtRestrEveryone)
+			common.ProCrtRestrEveryone,
+			common.ProCrtRestrApproval)
+	}
+
+	for _, key := range []string{common.ApprovalWebhookURL, common.BlocklistWebhookURL, common.ProjectWebhookURL} {
+		webhook, ok := strMap[key]
+		if !ok || len(webhook) == 0 {
+			continue
+		}
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return false, fmt.Errorf("invalid %s, should be an HTTP or HTTPS URL", key)
+		}
+	}
+
+	for _, key := range []string{common.ApprovalWebhookSchema, common.BlocklistWebhookSchema, common.ProjectWebhookSchema} {
+		if schema, ok := strMap[key]; ok && !models.ValidWebhookSchema(schema) {
+			return false, fmt.Errorf("invalid %s, should be %s or %s", key, models.WebhookSchemaV1, models.WebhookSchemaV2)
+		}
+	}
+
+	if allowlist, ok := strMap[common.EgressAllowlist]; ok {
+		if _, err := egress.Parse(allowlist); err != nil {
+			return false, fmt.Errorf("invalid %s: %v", common.EgressAllowlist, err)
+		}
+	}
+
+	if spec, ok := strMap[common.ProjectReportCron]; ok && len(spec) > 0 {
+		if _, err := cron.Parse(spec); err != nil {
+			return false, fmt.Errorf("invalid %s: %v", common.ProjectReportCron, err)
+		}
+	}
+
+	if project, ok := strMap[common.ShortNameProject]; ok && len(project) > 0 {
+		if err := validateProjectReq(&models.ProjectRequest{Name: project}); err != nil {
+			return false, fmt.Errorf("invalid %s: %v", common.ShortNameProject, err)
+		}
+	}
+
+	if keys, ok := strMap[common.TokenExchangePublicKeys]; ok && len(keys) > 0 {
+		if _, err := token.ParsePublicKeys(keys); err != nil {
+			return false, fmt.Errorf("invalid %s: %v", common.TokenExchangePublicKeys, err)
+		}
+	}
+	if bindings, ok := strMap[common.TokenExchangeBindings]; ok {
+		if _, err := models.ParseServiceAccountBindings(bindings); err != nil {
+			return false, fmt.Errorf("invalid %s: %v", common.TokenExchangeBindings, err)
+		}
+	}
+	if expiration, ok := numMap[common.TokenExchangeExpiration]; ok && expiration == 0 {
+		return false, fmt.Errorf("invalid %s, should be larger than 0", common.TokenExchangeExpiration)
 	}
 	return false, nil
 }
@@ -15,12 +15,15 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetConfig(t *testing.T) {
@@ -103,6 +106,70 @@ func TestPutConfig(t *testing.T) {
 	t.Logf("%v", ccc)
 }
 
+func TestPutConfigCVSSSource(t *testing.T) {
+	assert := assert.New(t)
+	apiTest := newHarborAPI()
+
+	code, err := apiTest.PutConfig(*admin, map[string]interface{}{
+		common.CVSSSource: "unknown",
+	})
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(400, code)
+
+	code, err = apiTest.PutConfig(*admin, map[string]interface{}{
+		common.CVSSSource: common.CVSSSourceNVDV3,
+	})
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(200, code)
+	source, err := config.CVSSSource()
+	assert.Nil(err)
+	assert.Equal(common.CVSSSourceNVDV3, source)
+
+	code, err = apiTest.PutConfig(*admin, map[string]interface{}{
+		common.CVSSSource: common.CVSSSourceVendor,
+	})
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(200, code)
+}
+
+func TestPutConfigMaxJSONBodySize(t *testing.T) {
+	assert := assert.New(t)
+	apiTest := newHarborAPI()
+
+	code, err := apiTest.PutConfig(*admin, map[string]interface{}{
+		common.MaxJSONBodySize: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(400, code)
+
+	code, err = apiTest.PutConfig(*admin, map[string]interface{}{
+		common.MaxJSONBodySize: 0,
+	})
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(200, code)
+	limits, err := config.RequestSizeLimits()
+	assert.Nil(err)
+	assert.Equal(int64(0), limits.JSONBody)
+
+	code, err = apiTest.PutConfig(*admin, map[string]interface{}{
+		common.MaxJSONBodySize: 10240,
+	})
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(200, code)
+}
+
 func TestResetConfig(t *testing.T) {
 	fmt.Println("Testing resetting configurations")
 	assert := assert.New(t)
@@ -177,3 +244,98 @@ func TestPutConfigMaxLength(t *testing.T) {
 	code500, _ := apiTest.PutConfig(*admin, cfg)
 	assert.Equal(500, code500, "the status code of modifying configurations with admin user should be 500")
 }
+
+func TestApplyConfig(t *testing.T) {
+	applyPath := "/api/configurations:apply"
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        applyPath,
+			credential: nonSysAdmin,
+			bodyJSON: map[string]interface{}{
+				common.TokenExpiration: 45,
+			},
+		},
+		code: http.StatusForbidden,
+	})
+
+	expiration, err := config.TokenExpiration()
+	require.Nil(t, err)
+
+	// nothing is applied if any of the configurations is invalid
+	resp, err := handle(&testingRequest{
+		method:     http.MethodPut,
+		url:        applyPath,
+		credential: sysAdmin,
+		bodyJSON: map[string]interface{}{
+			"unknown_key":          "value",
+			common.CVSSSource:      "unknown",
+			common.TokenExpiration: 45,
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	result := &configApplyResult{}
+	require.Nil(t, json.Unmarshal(resp.Body.Bytes(), result))
+	assert.False(t, result.Applied)
+	assert.Equal(t, 2, len(result.Errors))
+	current, err := config.TokenExpiration()
+	require.Nil(t, err)
+	assert.Equal(t, expiration, current)
+
+	// dry run
+	result = &configApplyResult{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodPut,
+		url:        applyPath + "?dry_run=true",
+		credential: sysAdmin,
+		bodyJSON: map[string]interface{}{
+			common.TokenExpiration: 45,
+		},
+	}, result)
+	require.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.False(t, result.Applied)
+	require.Equal(t, 1, len(result.Changes))
+	assert.Equal(t, common.TokenExpiration, result.Changes[0].Key)
+	current, err = config.TokenExpiration()
+	require.Nil(t, err)
+	assert.Equal(t, expiration, current)
+
+	// apply
+	result = &configApplyResult{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodPut,
+		url:        applyPath,
+		credential: sysAdmin,
+		bodyJSON: map[string]interface{}{
+			common.TokenExpiration: 45,
+		},
+	}, result)
+	require.Nil(t, err)
+	assert.True(t, result.Applied)
+	current, err = config.TokenExpiration()
+	require.Nil(t, err)
+	assert.Equal(t, 45, current)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        applyPath,
+			credential: sysAdmin,
+			bodyJSON: map[string]interface{}{
+				common.TokenExpiration: expiration,
+			},
+		},
+		code: http.StatusOK,
+	})
+}
+
+func TestValidateCfgUntranslatable(t *testing.T) {
+	// the arrays can't be stored
+	isSysErr, err := validateCfg(map[string]interface{}{
+		common.CVSSSource: []interface{}{"nvd"},
+	})
+	require.NotNil(t, err)
+	assert.False(t, isSysErr)
+}
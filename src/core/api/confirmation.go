@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+const (
+	// ConfirmationTokenHeader is the header which carries the confirmation token of destructive operations
+	ConfirmationTokenHeader = "X-Harbor-Confirmation-Token"
+	confirmationTokenTTL    = 2 * time.Minute
+)
+
+// issueConfirmation generates a confirmation token for the operation, the token is
+// recorded in the database so it can be consumed by any instance
+func issueConfirmation(username, method, path string) (string, time.Time, error) {
+	token := utils.GenerateRandomString()
+	expiresAt := time.Now().Add(confirmationTokenTTL)
+	if err := dao.AddConfirmationToken(&models.ConfirmationToken{
+		Token:     token,
+		Username:  username,
+		Method:    method,
+		Path:      path,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Confirmed returns whether the destructive operation can be performed. If the two-step
+// confirmation is enabled and the request doesn't carry a valid confirmation token, a new
+// token is issued in the response with the status code 428 and false is returned.
+func (b *BaseController) Confirmed() bool {
+	if !config.DestructiveOpConfirmation() {
+		return true
+	}
+
+	username := b.SecurityCtx.GetUsername()
+	method := b.Ctx.Request.Method
+	path := b.Ctx.Request.URL.Path
+	if token := b.Ctx.Request.Header.Get(ConfirmationTokenHeader); len(token) > 0 {
+		valid, err := dao.ConsumeConfirmationToken(token, username, method, path)
+		if err != nil {
+			b.HandleInternalServerError(fmt.Sprintf("failed to consume the confirmation token: %v", err))
+			return false
+		}
+		if valid {
+			return true
+		}
+	}
+
+	token, expiresAt, err := issueConfirmation(username, method, path)
+	if err != nil {
+		b.HandleInternalServerError(fmt.Sprintf("failed to issue the confirmation token: %v", err))
+		return false
+	}
+	b.Ctx.ResponseWriter.Header().Set(ConfirmationTokenHeader, token)
+	b.Ctx.Output.SetStatus(http.StatusPreconditionRequired)
+	b.Data["json"] = struct {
+		Token     string    `json:"confirmation_token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+	b.ServeJSON()
+	return false
+}
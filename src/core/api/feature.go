@@ -0,0 +1,152 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/feature"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// serializes the updates of the feature flags as they are stored in one configuration
+var featureFlagsLock sync.Mutex
+
+// FeatureAPI toggles the feature flags at runtime
+type FeatureAPI struct {
+	BaseController
+	flag *feature.Flag
+}
+
+type featureFlag struct {
+	*feature.Flag
+	Enabled bool `json:"enabled"`
+	// whether the state is set at runtime rather than the default
+	Overridden bool `json:"overridden"`
+}
+
+type featureFlagReq struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// Prepare validates the user and the feature flag, it needs the system admin permission
+func (f *FeatureAPI) Prepare() {
+	f.BaseController.Prepare()
+	if !f.SecurityCtx.IsAuthenticated() {
+		f.HandleUnauthorized()
+		return
+	}
+	if !f.SecurityCtx.IsSysAdmin() {
+		f.HandleForbidden(f.SecurityCtx.GetUsername())
+		return
+	}
+
+	if name := f.GetStringFromPath(":name"); len(name) > 0 {
+		flag, ok := feature.Get(name)
+		if !ok {
+			f.HandleNotFound(fmt.Sprintf("feature flag %s not found", name))
+			return
+		}
+		f.flag = flag
+	}
+}
+
+// List returns all the feature flags with the states
+func (f *FeatureAPI) List() {
+	overrides, err := config.FeatureFlags()
+	if err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to get the feature flags: %v", err))
+		return
+	}
+	flags := []*featureFlag{}
+	for _, flag := range feature.All() {
+		flags = append(flags, toFeatureFlag(flag, overrides))
+	}
+	f.Data["json"] = flags
+	f.ServeJSON()
+}
+
+// Get returns the feature flag with the state
+func (f *FeatureAPI) Get() {
+	overrides, err := config.FeatureFlags()
+	if err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to get the feature flags: %v", err))
+		return
+	}
+	f.Data["json"] = toFeatureFlag(f.flag, overrides)
+	f.ServeJSON()
+}
+
+// Put enables or disables the feature, it takes effect without restarting Harbor
+func (f *FeatureAPI) Put() {
+	req := &featureFlagReq{}
+	f.DecodeJSONReq(req)
+	if req.Enabled == nil {
+		f.HandleBadRequest("enabled is required")
+		return
+	}
+	enabled := *req.Enabled
+	if err := updateFeatureFlags(func(overrides feature.Overrides) {
+		overrides[f.flag.Name] = enabled
+	}); err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to update the feature flag %s: %v", f.flag.Name, err))
+		return
+	}
+	log.Infof("feature %s is set to enabled=%t by %s", f.flag.Name, enabled, f.SecurityCtx.GetUsername())
+}
+
+// Delete removes the state set at runtime, so the feature is reset to the default state
+func (f *FeatureAPI) Delete() {
+	if err := updateFeatureFlags(func(overrides feature.Overrides) {
+		delete(overrides, f.flag.Name)
+	}); err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to reset the feature flag %s: %v", f.flag.Name, err))
+		return
+	}
+	log.Infof("feature %s is reset to the default by %s", f.flag.Name, f.SecurityCtx.GetUsername())
+}
+
+func updateFeatureFlags(update func(feature.Overrides)) error {
+	featureFlagsLock.Lock()
+	defer featureFlagsLock.Unlock()
+
+	// load the latest configurations as they may be updated by other instances of core
+	if err := config.Load(); err != nil {
+		return err
+	}
+	overrides, err := config.FeatureFlags()
+	if err != nil {
+		return err
+	}
+	update(overrides)
+	if err = config.Upload(map[string]interface{}{
+		common.FeatureFlags: overrides.String(),
+	}); err != nil {
+		return err
+	}
+	return config.Load()
+}
+
+func toFeatureFlag(flag *feature.Flag, overrides feature.Overrides) *featureFlag {
+	_, overridden := overrides[flag.Name]
+	return &featureFlag{
+		Flag:       flag,
+		Enabled:    overrides.Enabled(flag.Name),
+		Overridden: overridden,
+	}
+}
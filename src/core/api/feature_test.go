@@ -0,0 +1,127 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/feature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var featurePath = "/api/system/features"
+
+func TestFeatureAPI(t *testing.T) {
+	enabled := true
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    featurePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        featurePath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        featurePath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        featurePath + "/unknown",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        featurePath + "/" + feature.OnlineGC,
+				bodyJSON:   &featureFlagReq{Enabled: &enabled},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, enabled is required
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        featurePath + "/" + feature.OnlineGC,
+				bodyJSON:   &featureFlagReq{},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        featurePath + "/" + feature.OnlineGC,
+				bodyJSON:   &featureFlagReq{Enabled: &enabled},
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	flag := &featureFlag{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        featurePath + "/" + feature.OnlineGC,
+		credential: sysAdmin,
+	}, flag)
+	require.Nil(t, err)
+	assert.True(t, flag.Enabled)
+	assert.True(t, flag.Overridden)
+
+	// reset to the default
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        featurePath + "/" + feature.OnlineGC,
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	flag = &featureFlag{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        featurePath + "/" + feature.OnlineGC,
+		credential: sysAdmin,
+	}, flag)
+	require.Nil(t, err)
+	assert.False(t, flag.Enabled)
+	assert.False(t, flag.Overridden)
+}
@@ -0,0 +1,153 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// FederationPeerAPI handles request to /api/federation/peers, the peers are the other Harbor
+// instances which the global search fans out to
+type FederationPeerAPI struct {
+	BaseController
+	peer *models.FederationPeer
+}
+
+// Prepare validates the user and the peer, only the system admin can manage the peers
+func (f *FederationPeerAPI) Prepare() {
+	f.BaseController.Prepare()
+	if !f.SecurityCtx.IsAuthenticated() {
+		f.HandleUnauthorized()
+		return
+	}
+	if !f.SecurityCtx.IsSysAdmin() {
+		f.HandleForbidden(f.SecurityCtx.GetUsername())
+		return
+	}
+	if len(f.GetStringFromPath(":id")) > 0 {
+		id, err := f.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			f.HandleBadRequest(fmt.Sprintf("invalid peer ID: %s", f.GetStringFromPath(":id")))
+			return
+		}
+		peer, err := dao.GetFederationPeer(id)
+		if err != nil {
+			f.HandleInternalServerError(fmt.Sprintf("failed to get peer %d: %v", id, err))
+			return
+		}
+		if peer == nil {
+			f.HandleNotFound(fmt.Sprintf("peer %d not found", id))
+			return
+		}
+		f.peer = peer
+	}
+}
+
+// List lists all the peers, the passwords aren't returned
+func (f *FederationPeerAPI) List() {
+	peers, err := dao.ListFederationPeers()
+	if err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to list peers: %v", err))
+		return
+	}
+	for _, peer := range peers {
+		peer.Password = ""
+	}
+	f.Data["json"] = peers
+	f.ServeJSON()
+}
+
+// Get returns the peer, the password isn't returned
+func (f *FederationPeerAPI) Get() {
+	f.peer.Password = ""
+	f.Data["json"] = f.peer
+	f.ServeJSON()
+}
+
+// Post registers a peer, the password is encrypted before being stored
+func (f *FederationPeerAPI) Post() {
+	peer := &models.FederationPeer{}
+	f.DecodeJSONReqAndValidate(peer)
+	if peer.Timeout == 0 {
+		peer.Timeout = models.DefaultFederationTimeout
+	}
+	if !f.encryptPassword(peer) {
+		return
+	}
+	id, err := dao.AddFederationPeer(peer)
+	if err != nil {
+		if err == dao.ErrDupRows {
+			f.HandleConflict(fmt.Sprintf("peer %s already exists", peer.Name))
+			return
+		}
+		f.HandleInternalServerError(fmt.Sprintf("failed to add peer %s: %v", peer.Name, err))
+		return
+	}
+	f.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// Put updates the peer, the password is kept if it's empty in the request
+func (f *FederationPeerAPI) Put() {
+	peer := &models.FederationPeer{}
+	f.DecodeJSONReqAndValidate(peer)
+	peer.ID = f.peer.ID
+	if peer.Timeout == 0 {
+		peer.Timeout = models.DefaultFederationTimeout
+	}
+	if len(peer.Password) == 0 {
+		peer.Password = f.peer.Password
+	} else if !f.encryptPassword(peer) {
+		return
+	}
+	if err := dao.UpdateFederationPeer(peer); err != nil {
+		if err == dao.ErrDupRows {
+			f.HandleConflict(fmt.Sprintf("peer %s already exists", peer.Name))
+			return
+		}
+		f.HandleInternalServerError(fmt.Sprintf("failed to update peer %d: %v", peer.ID, err))
+		return
+	}
+}
+
+// Delete removes the peer
+func (f *FederationPeerAPI) Delete() {
+	if err := dao.DeleteFederationPeer(f.peer.ID); err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to delete peer %d: %v", f.peer.ID, err))
+		return
+	}
+}
+
+func (f *FederationPeerAPI) encryptPassword(peer *models.FederationPeer) bool {
+	if len(peer.Password) == 0 {
+		return true
+	}
+	key, err := config.SecretKey()
+	if err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to get the secret key: %v", err))
+		return false
+	}
+	if peer.Password, err = keyring.Encrypt(peer.Password, key); err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to encrypt the password of peer %s: %v", peer.Name, err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var federationPeerPath = "/api/federation/peers"
+
+func TestFederationPeerAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    federationPeerPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        federationPeerPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid URL
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    federationPeerPath,
+				bodyJSON: &models.FederationPeer{
+					Name: "us-east",
+					URL:  "ftp://us-east.example.com",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid timeout
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    federationPeerPath,
+				bodyJSON: &models.FederationPeer{
+					Name:    "us-east",
+					URL:     "https://us-east.example.com",
+					Timeout: 3600,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    federationPeerPath,
+				bodyJSON: &models.FederationPeer{
+					Name:     "us-east",
+					URL:      "https://us-east.example.com",
+					Username: "searcher",
+					Password: "secret",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    federationPeerPath,
+				bodyJSON: &models.FederationPeer{
+					Name: "us-east",
+					URL:  "https://another.example.com",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+		// 401, the federated search needs authentication
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/search/",
+				queryStruct: struct {
+					Federated bool `url:"federated"`
+				}{true},
+			},
+			code: http.StatusUnauthorized,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+	defer dao.ClearTable(models.FederationPeerTable)
+
+	peers := []*models.FederationPeer{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        federationPeerPath,
+		credential: sysAdmin,
+	}, &peers)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(peers))
+	peer := peers[0]
+	assert.Equal(t, "us-east", peer.Name)
+	assert.Equal(t, "searcher", peer.Username)
+	assert.Empty(t, peer.Password)
+	assert.Equal(t, models.DefaultFederationTimeout, peer.Timeout)
+
+	// the password is kept when it's not specified
+	peerPath := fmt.Sprintf("%s/%d", federationPeerPath, peer.ID)
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPut,
+			url:    peerPath,
+			bodyJSON: &models.FederationPeer{
+				Name:     "us-east-1",
+				URL:      "https://us-east.example.com",
+				Username: "searcher",
+				Timeout:  5,
+			},
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	stored, err := dao.GetFederationPeer(peer.ID)
+	require.Nil(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "us-east-1", stored.Name)
+	assert.Equal(t, 5, stored.Timeout)
+	assert.NotEmpty(t, stored.Password)
+	assert.NotEqual(t, "secret", stored.Password)
+
+	cases = []*codeCheckingCase{
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        peerPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        peerPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,130 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// FreezeWindowAPI handles request to /api/projects/{}/freeze_windows, the members of the project
+// can view the freeze windows while only the project admin can manage them
+type FreezeWindowAPI struct {
+	BaseController
+	project *models.Project
+	window  *models.FreezeWindow
+}
+
+// Prepare validates the user, the project and the freeze window in the path
+func (f *FreezeWindowAPI) Prepare() {
+	f.BaseController.Prepare()
+	if !f.SecurityCtx.IsAuthenticated() {
+		f.HandleUnauthorized()
+		return
+	}
+
+	pid, err := f.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		f.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", f.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := f.ProjectMgr.Get(pid)
+	if err != nil {
+		f.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		f.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	f.project = project
+
+	if !(f.Ctx.Input.IsGet() && f.SecurityCtx.HasReadPerm(pid) ||
+		f.SecurityCtx.HasAllPerm(pid)) {
+		f.HandleForbidden(f.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(f.GetStringFromPath(":id")) > 0 {
+		id, err := f.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			f.HandleBadRequest(fmt.Sprintf("invalid freeze window ID: %s", f.GetStringFromPath(":id")))
+			return
+		}
+		window, err := dao.GetFreezeWindow(id)
+		if err != nil {
+			f.HandleInternalServerError(fmt.Sprintf("failed to get the freeze window %d: %v", id, err))
+			return
+		}
+		if window == nil || window.ProjectID != pid {
+			f.HandleNotFound(fmt.Sprintf("freeze window %d not found", id))
+			return
+		}
+		f.window = window
+	}
+}
+
+// Post declares a freeze window of the project
+func (f *FreezeWindowAPI) Post() {
+	window := &models.FreezeWindow{}
+	f.DecodeJSONReqAndValidate(window)
+	window.ProjectID = f.project.ProjectID
+	window.Creator = f.SecurityCtx.GetUsername()
+
+	id, err := dao.AddFreezeWindow(window)
+	if err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to add the freeze window: %v", err))
+		return
+	}
+	f.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the freeze windows of the project
+func (f *FreezeWindowAPI) List() {
+	windows, err := dao.ListFreezeWindows(f.project.ProjectID)
+	if err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to list the freeze windows: %v", err))
+		return
+	}
+	f.Data["json"] = windows
+	f.ServeJSON()
+}
+
+// Get gets the freeze window specified by ID
+func (f *FreezeWindowAPI) Get() {
+	f.Data["json"] = f.window
+	f.ServeJSON()
+}
+
+// Put updates the time range, repeat and message of the freeze window
+func (f *FreezeWindowAPI) Put() {
+	window := &models.FreezeWindow{}
+	f.DecodeJSONReqAndValidate(window)
+	window.ID = f.window.ID
+	if err := dao.UpdateFreezeWindow(window); err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to update the freeze window %d: %v", window.ID, err))
+	}
+}
+
+// Delete deletes the freeze window
+func (f *FreezeWindowAPI) Delete() {
+	if err := dao.DeleteFreezeWindow(f.window.ID); err != nil {
+		f.HandleInternalServerError(fmt.Sprintf("failed to delete the freeze window %d: %v", f.window.ID, err))
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var freezeWindowPath = "/api/projects/1/freeze_windows"
+
+func TestFreezeWindowAPI(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    freezeWindowPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403, only project admin can declare freeze windows
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    freezeWindowPath,
+				bodyJSON: &models.FreezeWindow{
+					StartTime: start,
+					EndTime:   start.Add(time.Hour),
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, the end time is before the start time
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    freezeWindowPath,
+				bodyJSON: &models.FreezeWindow{
+					StartTime: start,
+					EndTime:   start.Add(-time.Hour),
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the window is longer than the repeat
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    freezeWindowPath,
+				bodyJSON: &models.FreezeWindow{
+					StartTime: start,
+					EndTime:   start.Add(48 * time.Hour),
+					Repeat:    models.FreezeWindowRepeatDaily,
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    freezeWindowPath,
+				bodyJSON: &models.FreezeWindow{
+					StartTime: start,
+					EndTime:   start.Add(time.Hour),
+					Message:   "release 1.0",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusCreated,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the members can view the freeze windows
+	windows := []*models.FreezeWindow{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        freezeWindowPath,
+		credential: projDeveloper,
+	}, &windows)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(windows))
+	assert.Equal(t, models.FreezeWindowRepeatNone, windows[0].Repeat)
+	assert.Equal(t, projAdmin.Name, windows[0].Creator)
+	id := windows[0].ID
+	defer dao.DeleteFreezeWindow(id)
+
+	cases = []*codeCheckingCase{
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d", freezeWindowPath, 10000),
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", freezeWindowPath, id),
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    fmt.Sprintf("%s/%d", freezeWindowPath, id),
+				bodyJSON: &models.FreezeWindow{
+					StartTime: start,
+					EndTime:   start.Add(2 * time.Hour),
+					Repeat:    models.FreezeWindowRepeatWeekly,
+					Message:   "weekly release",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	window := &models.FreezeWindow{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        fmt.Sprintf("%s/%d", freezeWindowPath, id),
+		credential: projDeveloper,
+	}, window)
+	require.Nil(t, err)
+	assert.Equal(t, models.FreezeWindowRepeatWeekly, window.Repeat)
+	assert.Equal(t, "weekly release", window.Message)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        fmt.Sprintf("%s/%d", freezeWindowPath, id),
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+}
+
+func TestFreezeWindowEnforcement(t *testing.T) {
+	now := time.Now()
+	id, err := dao.AddFreezeWindow(&models.FreezeWindow{
+		ProjectID: 1,
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(time.Hour),
+		Repeat:    models.FreezeWindowRepeatNone,
+		Message:   "release 1.0",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteFreezeWindow(id)
+
+	cases := []*codeCheckingCase{
+		// 403, the tag deletion is rejected
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        "/api/repositories/library/hello-world/tags/latest",
+				credential: admin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 403, the retagging is rejected
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/repositories/library/hello-world/tags",
+				bodyJSON: &models.RetagRequest{
+					Tag:      "frozen",
+					SrcImage: "library/hello-world:latest",
+					Override: true,
+				},
+				credential: admin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 403, the renaming is rejected
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    "/api/repositories/library/hello-world/rename",
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "library/hello-world-renamed",
+				},
+				credential: admin,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -99,44 +99,97 @@ func init() {
 
 	beego.Router("/api/health", &HealthAPI{}, "get:CheckHealth")
 	beego.Router("/api/search/", &SearchAPI{})
+	beego.Router("/api/resolve", &ResolveAPI{}, "get:Get")
+	beego.Router("/api/federation/peers", &FederationPeerAPI{}, "get:List;post:Post")
+	beego.Router("/api/federation/peers/:id([0-9]+)", &FederationPeerAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/project_bootstrap_hooks", &ProjectBootstrapHookAPI{}, "get:List;post:Post")
+	beego.Router("/api/project_bootstrap_hooks/:id([0-9]+)", &ProjectBootstrapHookAPI{}, "get:Get;put:Put;delete:Delete")
 	beego.Router("/api/projects/", &ProjectAPI{}, "get:List;post:Post;head:Head")
 	beego.Router("/api/projects/:id", &ProjectAPI{}, "delete:Delete;get:Get;put:Put")
+	beego.Router("/api/projects/by_name/:name", &ProjectAPI{}, "get:Get")
 	beego.Router("/api/users/:id", &UserAPI{}, "get:Get")
 	beego.Router("/api/users", &UserAPI{}, "get:List;post:Post;delete:Delete;put:Put")
 	beego.Router("/api/users/:id([0-9]+)/password", &UserAPI{}, "put:ChangePassword")
 	beego.Router("/api/users/:id/permissions", &UserAPI{}, "get:ListUserPermissions")
+	beego.Router("/api/users/:id([0-9]+)/scope_usage", &UserAPI{}, "get:ScopeUsage")
 	beego.Router("/api/users/:id/sysadmin", &UserAPI{}, "put:ToggleUserAdminRole")
+	beego.Router("/api/users/:id([0-9]+)/compliance_officer", &UserAPI{}, "put:SetComplianceOfficer")
+	beego.Router("/api/users/current/starred", &UserAPI{}, "get:ListStarred")
+	beego.Router("/api/users/current/usage", &UserAPI{}, "get:Usage")
+	beego.Router("/api/users/current/notifications", &UserNotificationAPI{}, "get:List")
+	beego.Router("/api/users/current/notifications/read", &UserNotificationAPI{}, "post:MarkRead")
+	beego.Router("/api/users/current/notifications/:id([0-9]+)/read", &UserNotificationAPI{}, "post:MarkRead")
+	beego.Router("/api/users/current/notifications/:id([0-9]+)", &UserNotificationAPI{}, "delete:Delete")
 	beego.Router("/api/projects/:id([0-9]+)/logs", &ProjectAPI{}, "get:Logs")
+	beego.Router("/api/projects/:id([0-9]+)/moved_tags", &ProjectAPI{}, "get:MovedTags")
+	beego.Router("/api/projects/:id([0-9]+)/vulnerability_trend", &ProjectAPI{}, "get:VulnerabilityTrend")
+	beego.Router("/api/projects/:id([0-9]+)/configuration", &ProjectAPI{}, "get:ExportConfig;put:ApplyConfig")
 	beego.Router("/api/projects/:id([0-9]+)/_deletable", &ProjectAPI{}, "get:Deletable")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/?:name", &MetadataAPI{}, "get:Get")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/", &MetadataAPI{}, "post:Post")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/:name", &MetadataAPI{}, "put:Put;delete:Delete")
+	beego.Router("/api/projects/:id([0-9]+)/custom_metadata", &ProjectCustomMetadataAPI{}, "get:List")
+	beego.Router("/api/projects/:id([0-9]+)/custom_metadata/:name", &ProjectCustomMetadataAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:id([0-9]+)/report_subscriptions", &ProjectReportSubscriptionAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:id([0-9]+)/report_subscriptions/:uid([0-9]+)", &ProjectReportSubscriptionAPI{}, "delete:Delete")
+	beego.Router("/api/projects/:id([0-9]+)/vul_exceptions", &VulExceptionAPI{}, "get:List")
 	beego.Router("/api/projects/:pid([0-9]+)/members/?:pmid([0-9]+)", &ProjectMemberAPI{})
+	beego.Router("/api/projects/:pid([0-9]+)/members/batch", &ProjectMemberAPI{}, "post:Batch")
 	beego.Router("/api/repositories", &RepositoryAPI{})
 	beego.Router("/api/statistics", &StatisticAPI{})
+	beego.Router("/api/statistics/traffic", &StatisticAPI{}, "get:Traffic")
+	beego.Router("/api/chargeback/reports", &ChargebackReportAPI{}, "get:List;post:Post")
+	beego.Router("/api/chargeback/reports/:id([0-9]+)", &ChargebackReportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/metadata_sync", &MetadataSyncAPI{}, "post:Post")
+	beego.Router("/api/metadata_sync/states", &MetadataSyncAPI{}, "get:ListStates")
+	beego.Router("/api/internal/metadata_sync/snapshot", &MetadataSyncSnapshotAPI{}, "get:Get")
+	beego.Router("/api/statistics/metrics", &StatisticAPI{}, "get:Metrics")
 	beego.Router("/api/users/?:id", &UserAPI{})
 	beego.Router("/api/usergroups/?:ugid([0-9]+)", &UserGroupAPI{})
 	beego.Router("/api/logs", &LogAPI{})
 	beego.Router("/api/repositories/*", &RepositoryAPI{}, "put:Put")
+	beego.Router("/api/repositories/*/rename", &RepositoryAPI{}, "put:Rename")
 	beego.Router("/api/repositories/*/labels", &RepositoryLabelAPI{}, "get:GetOfRepository;post:AddToRepository")
 	beego.Router("/api/repositories/*/labels/:id([0-9]+", &RepositoryLabelAPI{}, "delete:RemoveFromRepository")
 	beego.Router("/api/repositories/*/tags/:tag/labels", &RepositoryLabelAPI{}, "get:GetOfImage;post:AddToImage")
 	beego.Router("/api/repositories/*/tags/:tag/labels/:id([0-9]+", &RepositoryLabelAPI{}, "delete:RemoveFromImage")
-	beego.Router("/api/repositories/*/tags/:tag", &RepositoryAPI{}, "delete:Delete;get:GetTag")
+	beego.Router("/api/repositories/*/tags/:tag", &RepositoryAPI{}, "delete:Delete;get:GetTag;head:HeadTag")
 	beego.Router("/api/repositories/*/tags", &RepositoryAPI{}, "get:GetTags;post:Retag")
 	beego.Router("/api/repositories/*/tags/:tag/manifest", &RepositoryAPI{}, "get:GetManifests")
+	beego.Router("/api/repositories/*/tags/:tag/history", &RepositoryAPI{}, "get:GetTagHistory")
+	beego.Router("/api/repositories/*/tags/:tag/badge.svg", &RepositoryAPI{}, "get:GetBadge")
 	beego.Router("/api/repositories/*/signatures", &RepositoryAPI{}, "get:GetSignatures")
+	beego.Router("/api/repositories/*/star", &RepoSubscriptionAPI{}, "put:Star;delete:Unstar")
+	beego.Router("/api/repositories/*/subscription", &RepoSubscriptionAPI{}, "get:GetSubscription;put:SetSubscription;delete:DeleteSubscription")
+	beego.Router("/api/repositories/*/retention", &RepoRetentionAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/collaborators", &RepoCollaboratorAPI{}, "get:List")
+	beego.Router("/api/repositories/*/collaborators/:username", &RepoCollaboratorAPI{}, "put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/storage_hint", &RepoStorageHintAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/deprecation", &RepoDeprecationAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/tags/:tag/deprecation", &RepoDeprecationAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/legal_hold", &LegalHoldAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/tags/:tag/legal_hold", &LegalHoldAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/vul_exception", &VulExceptionAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/legal_holds", &LegalHoldAPI{}, "get:List")
+	beego.Router("/api/repositories/*/aliases", &TagAliasAPI{}, "get:List")
+	beego.Router("/api/repositories/*/aliases/:alias", &TagAliasAPI{}, "put:Put;delete:Delete")
 	beego.Router("/api/repositories/top", &RepositoryAPI{}, "get:GetTopRepos")
+	beego.Router("/api/scan/batch", &ScanBatchAPI{}, "post:Post")
+	beego.Router("/api/scan/batch/:id([0-9]+)", &ScanBatchAPI{}, "get:Get")
 	beego.Router("/api/targets/", &TargetAPI{}, "get:List")
 	beego.Router("/api/targets/", &TargetAPI{}, "post:Post")
 	beego.Router("/api/targets/:id([0-9]+)", &TargetAPI{})
+	beego.Router("/api/targets/by_name/:name", &TargetAPI{}, "get:GetByName")
 	beego.Router("/api/targets/:id([0-9]+)/policies/", &TargetAPI{}, "get:ListPolicies")
+	beego.Router("/api/targets/:id([0-9]+)/ca_bundle", &TargetAPI{}, "get:GetCABundle;put:PutCABundle;delete:DeleteCABundle")
+	beego.Router("/api/targets/:id([0-9]+)/ratelimit", &TargetAPI{}, "get:GetRateLimit")
 	beego.Router("/api/targets/ping", &TargetAPI{}, "post:Ping")
 	beego.Router("/api/policies/replication/:id([0-9]+)", &RepPolicyAPI{})
 	beego.Router("/api/policies/replication", &RepPolicyAPI{}, "get:List")
 	beego.Router("/api/policies/replication", &RepPolicyAPI{}, "post:Post;delete:Delete")
 	beego.Router("/api/systeminfo", &SystemInfoAPI{}, "get:GetGeneralInfo")
 	beego.Router("/api/systeminfo/volumes", &SystemInfoAPI{}, "get:GetVolumeInfo")
+	beego.Router("/api/systeminfo/joblogs", &SystemInfoAPI{}, "get:GetJobLogUsage")
 	beego.Router("/api/systeminfo/getcert", &SystemInfoAPI{}, "get:GetCert")
 	beego.Router("/api/ldap/ping", &LdapAPI{}, "post:Ping")
 	beego.Router("/api/ldap/users/search", &LdapAPI{}, "get:Search")
@@ -144,19 +197,101 @@ func init() {
 	beego.Router("/api/ldap/users/import", &LdapAPI{}, "post:ImportUser")
 	beego.Router("/api/configurations", &ConfigAPI{})
 	beego.Router("/api/configurations/reset", &ConfigAPI{}, "post:Reset")
+	beego.Router("/api/configurations\\:apply", &ConfigAPI{}, "put:Apply")
 	beego.Router("/api/configs", &ConfigAPI{}, "get:GetInternalConfig")
 	beego.Router("/api/email/ping", &EmailAPI{}, "post:Ping")
 	beego.Router("/api/replications", &ReplicationAPI{})
+	beego.Router("/api/replication/executions", &ReplicationAPI{}, "post:Execute")
+	beego.Router("/api/replication/robots", &RobotReplicationAPI{}, "post:Post")
 	beego.Router("/api/labels", &LabelAPI{}, "post:Post;get:List")
 	beego.Router("/api/labels/:id([0-9]+", &LabelAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/labels/by_name/:name", &LabelAPI{}, "get:GetByName")
 	beego.Router("/api/labels/:id([0-9]+)/resources", &LabelAPI{}, "get:ListResources")
 	beego.Router("/api/ping", &SystemInfoAPI{}, "get:Ping")
 	beego.Router("/api/system/gc/:id", &GCAPI{}, "get:GetGC")
 	beego.Router("/api/system/gc/:id([0-9]+)/log", &GCAPI{}, "get:GetLog")
 	beego.Router("/api/system/gc/schedule", &GCAPI{}, "get:Get;put:Put;post:Post")
+	beego.Router("/api/system/uploads", &UploadSessionAPI{}, "get:List;delete:Purge")
+	beego.Router("/api/system/vulnerability_db/imports", &VulnDBAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/vulnerability_db/imports/:id([0-9]+)", &VulnDBAPI{}, "get:Get")
+	beego.Router("/api/bundles/exports", &BundleExportAPI{}, "get:List;post:Post")
+	beego.Router("/api/bundles/exports/:id([0-9]+)", &BundleExportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/bundles/exports/:id([0-9]+)/download", &BundleExportAPI{}, "get:Download")
+	beego.Router("/api/bundles/import", &BundleAPI{}, "post:Import")
+	beego.Router("/api/system/rebuild_index", &RebuildIndexAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/rebuild_index/:id([0-9]+)", &RebuildIndexAPI{}, "get:Get")
+	beego.Router("/api/system/rebuild_index/:id([0-9]+)/log", &RebuildIndexAPI{}, "get:GetLog")
+	beego.Router("/api/system/project_merges", &ProjectMergeAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/project_merges/:id([0-9]+)", &ProjectMergeAPI{}, "get:Get")
+	beego.Router("/api/system/chart_gc", &ChartGCAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/chart_gc/:id([0-9]+)", &ChartGCAPI{}, "get:Get")
+	beego.Router("/api/system/chart_gc/:id([0-9]+)/log", &ChartGCAPI{}, "get:GetLog")
+	beego.Router("/api/system/reconciliation", &ReconciliationAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/reconciliation/:id([0-9]+)", &ReconciliationAPI{}, "get:Get")
+	beego.Router("/api/system/reconciliation/:id([0-9]+)/log", &ReconciliationAPI{}, "get:GetLog")
+	beego.Router("/api/system/storage_transition", &StorageTransitionAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/storage_transition/summary", &StorageTransitionAPI{}, "get:Summary")
+	beego.Router("/api/system/storage_transition/:id([0-9]+)", &StorageTransitionAPI{}, "get:Get")
+	beego.Router("/api/system/storage_transition/:id([0-9]+)/log", &StorageTransitionAPI{}, "get:GetLog")
+	beego.Router("/api/system/blocklist", &BlocklistAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/blocklist/:id([0-9]+)", &BlocklistAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/system/secrets/reencrypt", &SecretReencryptionAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/secrets/reencrypt/:id([0-9]+)", &SecretReencryptionAPI{}, "get:Get")
+	beego.Router("/api/system/auth_mode/preflight", &AuthModeAPI{}, "get:Preflight")
+	beego.Router("/api/system/auth_mode/migrations", &AuthModeAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/auth_mode/migrations/:id([0-9]+)", &AuthModeAPI{}, "get:Get")
+	beego.Router("/api/system/features", &FeatureAPI{}, "get:List")
+	beego.Router("/api/system/features/:name([a-z0-9_]+)", &FeatureAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/system/log_levels", &LogLevelAPI{}, "get:List")
+	beego.Router("/api/system/storage", &StorageAPI{}, "get:Get")
+	beego.Router("/api/system/storage/validate", &StorageAPI{}, "post:Validate")
+	beego.Router("/api/system/log_levels/:name([a-z_]+)", &LogLevelAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/system/maintenance", &MaintenanceAPI{}, "get:Get;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/compliance_reports", &ComplianceReportAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/compliance_reports/:id([0-9]+)", &ComplianceReportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/compliance_reports/:id([0-9]+)/download", &ComplianceReportAPI{}, "get:Download")
+	beego.Router("/api/projects/:pid([0-9]+)/repository_exports", &RepositoryExportAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/repository_exports/:id([0-9]+)", &RepositoryExportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/repository_exports/:id([0-9]+)/download", &RepositoryExportAPI{}, "get:Download")
+	beego.Router("/api/scanners", &ScannerAPI{}, "get:List")
+	beego.Router("/api/scanners/:id/health", &ScannerAPI{}, "get:Health")
+	beego.Router("/api/scanners/:id/capabilities", &ScannerAPI{}, "get:Capabilities")
+	beego.Router("/api/scanners/:id/ca_bundle", &ScannerAPI{}, "get:GetCABundle;put:PutCABundle;delete:DeleteCABundle")
+	beego.Router("/api/vulnerabilities/:cve_id/affected", &VulnerabilityAPI{}, "get:Affected")
+	beego.Router("/api/promotion_pipelines", &PromotionPipelineAPI{}, "get:List;post:Post")
+	beego.Router("/api/promotion_pipelines/:id([0-9]+)", &PromotionPipelineAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/promotions", &PromotionAPI{}, "get:List;post:Post")
+	beego.Router("/api/promotions/:id([0-9]+)", &PromotionAPI{}, "get:Get")
+	beego.Router("/api/promotions/:id([0-9]+)/approve", &PromotionAPI{}, "post:Approve")
+	beego.Router("/api/promotions/:id([0-9]+)/reject", &PromotionAPI{}, "post:Reject")
+	beego.Router("/api/approvals", &ApprovalAPI{}, "get:List")
+	beego.Router("/api/approvals/:id([0-9]+)", &ApprovalAPI{}, "get:Get")
+	beego.Router("/api/approvals/:id([0-9]+)/approve", &ApprovalAPI{}, "post:Approve")
+	beego.Router("/api/approvals/:id([0-9]+)/reject", &ApprovalAPI{}, "post:Reject")
 
 	beego.Router("/api/projects/:pid([0-9]+)/robots/", &RobotAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/pull_secret", &RobotAPI{}, "post:PullSecret")
 	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)", &RobotAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/by_name/:name", &RobotAPI{}, "get:GetByName")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)/scope_usage", &RobotAPI{}, "get:ScopeUsage")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)/permission_review", &RobotAPI{}, "get:PermissionReview;post:ApplyPermissionReview")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)/replicas", &RobotAPI{}, "get:Replicas")
+	beego.Router("/api/projects/:pid([0-9]+)/share_links", &ShareLinkAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/share_links/:id([0-9]+)", &ShareLinkAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/ui_tokens", &UITokenAPI{}, "post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/repositories", &ProjectRepositoryAPI{}, "post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/freeze_windows", &FreezeWindowAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/freeze_windows/:id([0-9]+)", &FreezeWindowAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/tag_policy/check", &TagPolicyAPI{}, "get:Check")
+	beego.Router("/api/projects/:pid([0-9]+)/usage_policy", &UsagePolicyAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/usage_policy/acknowledgment", &UsagePolicyAPI{}, "post:Acknowledge")
+	beego.Router("/api/projects/:pid([0-9]+)/invitations", &ProjectInvitationAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/invitations/:id([0-9]+)", &ProjectInvitationAPI{}, "delete:Delete")
+	beego.Router("/api/invitations/:token", &InvitationAPI{}, "get:Get")
+	beego.Router("/api/invitations/:token/accept", &InvitationAPI{}, "post:Accept")
+	beego.Router("/api/projects/:pid([0-9]+)/access_requests", &AccessRequestAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/access_requests/:id([0-9]+)/approve", &AccessRequestAPI{}, "post:Approve")
+	beego.Router("/api/projects/:pid([0-9]+)/access_requests/:id([0-9]+)/deny", &AccessRequestAPI{}, "post:Deny")
 
 	// Charts are controlled under projects
 	chartRepositoryAPIType := &ChartRepositoryAPI{}
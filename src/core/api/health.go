@@ -27,6 +27,7 @@ import (
 	"github.com/goharbor/harbor/src/common/dao"
 	httputil "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/core/config"
 
 	"github.com/docker/distribution/health"
@@ -41,6 +42,9 @@ var (
 type overallHealthStatus struct {
 	Status     string                   `json:"status"`
 	Components []*componentHealthStatus `json:"components"`
+	// the breakers of the registry and the replication targets, the open ones don't
+	// affect the overall status as the components are checked separately
+	Breakers []*registry.BreakerStatus `json:"breakers"`
 }
 
 type componentHealthStatus struct {
@@ -81,6 +85,7 @@ func (h *HealthAPI) CheckHealth() {
 	status := &overallHealthStatus{}
 	status.Status = isHealthy.String()
 	status.Components = components
+	status.Breakers = registry.ListBreakerStatus()
 	if !isHealthy {
 		log.Debugf("unhealthy system status: %v", status)
 	}
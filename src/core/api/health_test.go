@@ -102,6 +102,8 @@ func TestCheckHealth(t *testing.T) {
 	}, &status)
 	require.Nil(t, err)
 	assert.Equal(t, "healthy", status["status"].(string))
+	_, ok := status["breakers"].([]interface{})
+	assert.True(t, ok)
 
 	// component01: healthy, component02: unhealthy => status: unhealthy
 	healthCheckerRegistry = map[string]health.Checker{}
@@ -162,18 +162,73 @@ func (l *LabelAPI) Get() {
 	l.ServeJSON()
 }
 
+// GetByName gets the label by name in the scope specified by the query strings
+func (l *LabelAPI) GetByName() {
+	query := &models.LabelQuery{
+		Name:  l.GetStringFromPath(":name"),
+		Level: common.LabelLevelUser,
+	}
+	if !l.populateScope(query) {
+		return
+	}
+
+	labels, err := dao.ListLabels(query)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get label %s: %v", query.Name, err))
+		return
+	}
+
+	if len(labels) == 0 {
+		l.HandleNotFound(fmt.Sprintf("label %s not found", query.Name))
+		return
+	}
+
+	l.Data["json"] = labels[0]
+	l.ServeJSON()
+}
+
 // List labels according to the query strings
 func (l *LabelAPI) List() {
+	name, exact, err := l.GetNameQuery()
+	if err != nil {
+		l.HandleBadRequest(err.Error())
+		return
+	}
 	query := &models.LabelQuery{
-		Name:           l.GetString("name"),
-		FuzzyMatchName: true,
+		Name:           name,
+		FuzzyMatchName: !exact,
 		Level:          common.LabelLevelUser,
 	}
+	if !l.populateScope(query) {
+		return
+	}
+
+	total, err := dao.GetTotalOfLabels(query)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get total count of labels: %v", err))
+		return
+	}
+
+	query.Page, query.Size = l.GetPaginationParams()
 
+	labels, err := dao.ListLabels(query)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to list labels: %v", err))
+		return
+	}
+
+	l.SetPaginationHeader(total, query.Page, query.Size)
+	l.Data["json"] = labels
+	l.ServeJSON()
+}
+
+// populateScope populates the scope and project ID of the query with the query strings
+// and checks the permission, false is returned if the request has been handled
+func (l *LabelAPI) populateScope(query *models.LabelQuery) bool {
 	scope := l.GetString("scope")
 	if scope != common.LabelScopeGlobal && scope != common.LabelScopeProject {
 		l.HandleBadRequest(fmt.Sprintf("invalid scope: %s", scope))
-		return
+		return false
 	}
 	query.Scope = scope
 
@@ -181,42 +236,25 @@ func (l *LabelAPI) List() {
 		projectIDStr := l.GetString("project_id")
 		if len(projectIDStr) == 0 {
 			l.HandleBadRequest("project_id is required")
-			return
+			return false
 		}
 		projectID, err := strconv.ParseInt(projectIDStr, 10, 64)
 		if err != nil || projectID <= 0 {
 			l.HandleBadRequest(fmt.Sprintf("invalid project_id: %s", projectIDStr))
-			return
+			return false
 		}
 
 		if !l.SecurityCtx.HasReadPerm(projectID) {
 			if !l.SecurityCtx.IsAuthenticated() {
 				l.HandleUnauthorized()
-				return
+				return false
 			}
 			l.HandleForbidden(l.SecurityCtx.GetUsername())
-			return
+			return false
 		}
 		query.ProjectID = projectID
 	}
-
-	total, err := dao.GetTotalOfLabels(query)
-	if err != nil {
-		l.HandleInternalServerError(fmt.Sprintf("failed to get total count of labels: %v", err))
-		return
-	}
-
-	query.Page, query.Size = l.GetPaginationParams()
-
-	labels, err := dao.ListLabels(query)
-	if err != nil {
-		l.HandleInternalServerError(fmt.Sprintf("failed to list labels: %v", err))
-		return
-	}
-
-	l.SetPaginationHeader(total, query.Page, query.Size)
-	l.Data["json"] = labels
-	l.ServeJSON()
+	return true
 }
 
 // Put updates the label
@@ -269,6 +269,72 @@ func TestLabelAPIList(t *testing.T) {
 	assert.Equal(t, 0, len(labels))
 }
 
+func TestLabelAPIGetByName(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 400 no scope query string
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    labelAPIBasePath + "/by_name/test",
+			},
+			code: http.StatusBadRequest,
+		},
+
+		// 404
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    labelAPIBasePath + "/by_name/tes",
+				queryStruct: struct {
+					Scope     string `url:"scope"`
+					ProjectID int64  `url:"project_id"`
+				}{
+					Scope:     "p",
+					ProjectID: 1,
+				},
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// 200
+	label := &models.Label{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    labelAPIBasePath + "/by_name/test",
+		queryStruct: struct {
+			Scope     string `url:"scope"`
+			ProjectID int64  `url:"project_id"`
+		}{
+			Scope:     "p",
+			ProjectID: 1,
+		},
+	}, label)
+	require.Nil(t, err)
+	assert.Equal(t, labelID, label.ID)
+
+	// exact match
+	labels := []*models.Label{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    labelAPIBasePath,
+		queryStruct: struct {
+			Scope      string `url:"scope"`
+			ProjectID  int64  `url:"project_id"`
+			Name       string `url:"name"`
+			ExactMatch bool   `url:"exact_match"`
+		}{
+			Scope:      "p",
+			ProjectID:  1,
+			Name:       "tes",
+			ExactMatch: true,
+		},
+	}, &labels)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(labels))
+}
+
 func TestLabelAPIPut(t *testing.T) {
 	cases := []*codeCheckingCase{
 		// 401
@@ -0,0 +1,226 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+)
+
+// LegalHoldAPI handles the requests on /api/repositories/*/legal_hold and
+// /api/repositories/*/tags/:tag/legal_hold to hold the repository or the tag, and the
+// listing of the holds on /api/legal_holds. The holds are placed and released by the
+// compliance officers only
+type LegalHoldAPI struct {
+	BaseController
+	project    *models.Project
+	repository string
+	// empty when the whole repository is handled
+	tag string
+}
+
+// Prepare ...
+func (l *LegalHoldAPI) Prepare() {
+	l.BaseController.Prepare()
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return
+	}
+
+	name := l.GetString(":splat")
+	if len(name) == 0 {
+		// the holds of all the repositories are listed to the compliance officers and the system admin
+		if !l.SecurityCtx.IsSysAdmin() && !l.isComplianceOfficer() {
+			l.HandleForbidden(l.SecurityCtx.GetUsername())
+		}
+		return
+	}
+
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		l.HandleNotFound(l.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+	projectName, _ := utils.ParseRepository(name)
+	project, err := l.ProjectMgr.Get(projectName)
+	if err != nil {
+		l.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+		return
+	}
+	if project == nil {
+		l.HandleNotFound(l.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if l.Ctx.Request.Method == http.MethodGet {
+		if !l.SecurityCtx.HasReadPerm(project.ProjectID) {
+			l.HandleForbidden(l.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !l.isComplianceOfficer() {
+		l.HandleForbidden(l.SecurityCtx.GetUsername())
+		return
+	}
+	l.project = project
+	l.repository = name
+	l.tag = l.GetString(":tag")
+}
+
+// isComplianceOfficer returns whether the current user is a compliance officer, the robot
+// accounts and the other non-database users never are
+func (l *LegalHoldAPI) isComplianceOfficer() bool {
+	user, err := dao.GetUser(models.User{Username: l.SecurityCtx.GetUsername()})
+	if err != nil {
+		log.Errorf("failed to get user %s: %v", l.SecurityCtx.GetUsername(), err)
+		return false
+	}
+	if user == nil {
+		return false
+	}
+	officer, err := dao.IsComplianceOfficer(user.UserID)
+	if err != nil {
+		log.Errorf("failed to check whether %s is a compliance officer: %v", user.Username, err)
+		return false
+	}
+	return officer
+}
+
+func (l *LegalHoldAPI) target() string {
+	if len(l.tag) == 0 {
+		return l.repository
+	}
+	return l.repository + ":" + l.tag
+}
+
+// List lists the legal holds including the released ones, the query parameter "active"
+// lists the holds which aren't released only
+func (l *LegalHoldAPI) List() {
+	active, err := l.GetBool("active", false)
+	if err != nil {
+		l.HandleBadRequest(fmt.Sprintf("invalid active: %s", l.GetString("active")))
+		return
+	}
+	query := &models.LegalHoldQuery{
+		Project:    l.GetString("project"),
+		Repository: l.GetString("repository"),
+		Active:     active,
+	}
+	total, err := dao.CountLegalHolds(query)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to count the legal holds: %v", err))
+		return
+	}
+	query.Page, query.Size = l.GetPaginationParams()
+	holds, err := dao.ListLegalHolds(query)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to list the legal holds: %v", err))
+		return
+	}
+	l.SetPaginationHeader(total, query.Page, query.Size)
+	l.Data["json"] = holds
+	l.ServeJSON()
+}
+
+// Get returns the active legal hold of the repository or the tag
+func (l *LegalHoldAPI) Get() {
+	hold, err := dao.GetActiveLegalHold(l.repository, l.tag)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get the legal hold of %s: %v", l.target(), err))
+		return
+	}
+	if hold == nil {
+		l.HandleNotFound(fmt.Sprintf("%s isn't under legal hold", l.target()))
+		return
+	}
+	l.Data["json"] = hold
+	l.ServeJSON()
+}
+
+// Put places the legal hold on the repository or the tag, it overrides the retention, the
+// deletion and the moving of them until it's released
+func (l *LegalHoldAPI) Put() {
+	hold := &models.LegalHold{}
+	l.DecodeJSONReqAndValidate(hold)
+	hold.Repository = l.repository
+	hold.Tag = l.tag
+	hold.Creator = l.SecurityCtx.GetUsername()
+	if _, err := dao.AddLegalHold(hold); err != nil {
+		if err == dao.ErrDupRows {
+			l.HandleConflict(fmt.Sprintf("%s is under legal hold already", l.target()))
+			return
+		}
+		l.HandleInternalServerError(fmt.Sprintf("failed to place the legal hold of %s: %v", l.target(), err))
+		return
+	}
+	l.addAccessLog("place_legal_hold", hold.Reason)
+}
+
+// Delete releases the legal hold, the reason of the release is optional
+func (l *LegalHoldAPI) Delete() {
+	release := &models.LegalHoldRelease{}
+	if len(l.Ctx.Input.CopyBody(1<<32)) > 0 {
+		l.DecodeJSONReqAndValidate(release)
+	}
+	hold, err := dao.GetActiveLegalHold(l.repository, l.tag)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get the legal hold of %s: %v", l.target(), err))
+		return
+	}
+	if hold == nil {
+		l.HandleNotFound(fmt.Sprintf("%s isn't under legal hold", l.target()))
+		return
+	}
+	if err = dao.ReleaseLegalHold(hold.ID, l.SecurityCtx.GetUsername(), release.Reason); err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to release the legal hold of %s: %v", l.target(), err))
+		return
+	}
+	l.addAccessLog("release_legal_hold", release.Reason)
+}
+
+// addAccessLog records the lifecycle of the hold with the reason in the access log
+func (l *LegalHoldAPI) addAccessLog(operation, reason string) {
+	tag := l.tag
+	if len(tag) == 0 {
+		tag = "N/A"
+	}
+	detail, err := json.Marshal(map[string]string{"reason": reason})
+	if err != nil {
+		log.Errorf("failed to marshal the detail of the access log: %v", err)
+	}
+	if err := accesslog.Add(models.AccessLog{
+		Username:  l.SecurityCtx.GetUsername(),
+		ProjectID: l.project.ProjectID,
+		RepoName:  l.repository,
+		RepoTag:   tag,
+		Operation: operation,
+		OpTime:    time.Now(),
+		Detail:    string(detail),
+	}); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
+}
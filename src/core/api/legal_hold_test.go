@@ -0,0 +1,202 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegalHoldAPI(t *testing.T) {
+	repoPath := "/api/repositories/library/hello-world/legal_hold"
+	tagPath := "/api/repositories/library/hello-world/tags/latest/legal_hold"
+	defer dao.ClearTable(models.LegalHoldTable)
+	defer dao.SetComplianceOfficer(int(projGuestID), false)
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    repoPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403, only the system admin can grant the compliance officers
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        fmt.Sprintf("/api/users/%d/compliance_officer", projGuestID),
+				credential: projAdmin,
+				bodyJSON: &models.User{
+					ComplianceOfficer: true,
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        fmt.Sprintf("/api/users/%d/compliance_officer", projGuestID),
+				credential: sysAdmin,
+				bodyJSON: &models.User{
+					ComplianceOfficer: true,
+				},
+			},
+			code: http.StatusOK,
+		},
+		// 403, even the project admins can't hold
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        repoPath,
+				credential: projAdmin,
+				bodyJSON: &models.LegalHold{
+					Reason: "litigation",
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, empty reason
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        tagPath,
+				credential: projGuest,
+				bodyJSON:   &models.LegalHold{},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        tagPath,
+				credential: projGuest,
+				bodyJSON: &models.LegalHold{
+					Reason: "litigation",
+				},
+			},
+			code: http.StatusOK,
+		},
+		// 409, held already
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        tagPath,
+				credential: projGuest,
+				bodyJSON: &models.LegalHold{
+					Reason: "litigation",
+				},
+			},
+			code: http.StatusConflict,
+		},
+		// 412, the held tag can't be deleted
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        "/api/repositories/library/hello-world/tags/latest",
+				credential: projAdmin,
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		// 403, the holds are only listed to the compliance officers and the system admin
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/legal_holds",
+				credential: projAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	hold := &models.LegalHold{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        tagPath,
+		credential: projDeveloper,
+	}, hold))
+	assert.Equal(t, "latest", hold.Tag)
+	assert.Equal(t, "litigation", hold.Reason)
+	assert.Equal(t, projGuest.Name, hold.Creator)
+
+	user := &models.User{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/users/current",
+		credential: projGuest,
+	}, user))
+	assert.True(t, user.ComplianceOfficer)
+
+	cases = []*codeCheckingCase{
+		// 404, the repository isn't held
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        repoPath,
+				credential: projGuest,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        tagPath,
+				credential: projGuest,
+				bodyJSON: &models.LegalHoldRelease{
+					Reason: "settled",
+				},
+			},
+			code: http.StatusOK,
+		},
+		// 404, released already
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        tagPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the released hold is kept as the history
+	holds := []*models.LegalHold{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/legal_holds",
+		credential: sysAdmin,
+		queryStruct: struct {
+			Repository string `url:"repository"`
+		}{
+			Repository: "library/hello-world",
+		},
+	}, &holds))
+	require.Equal(t, 1, len(holds))
+	assert.Equal(t, "settled", holds[0].ReleaseReason)
+	assert.Equal(t, projGuest.Name, holds[0].Releaser)
+	assert.NotNil(t, holds[0].ReleaseTime)
+}
@@ -17,9 +17,10 @@ package api
 import (
 	"fmt"
 
-	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/api"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/accesslog"
 )
 
 // LogAPI handles request api/logs
@@ -43,6 +44,7 @@ func (l *LogAPI) Prepare() {
 // Get returns the recent logs according to parameters
 func (l *LogAPI) Get() {
 	page, size := l.GetPaginationParams()
+	mode := l.GetCountMode()
 	query := &models.LogQueryParam{
 		Username:   l.GetString("username"),
 		Repository: l.GetString("repository"),
@@ -83,7 +85,7 @@ func (l *LogAPI) Get() {
 		}
 
 		if len(projects) == 0 {
-			l.SetPaginationHeader(0, page, size)
+			l.SetPaginationHeaderOfMode(mode, 0, page, size, 0)
 			l.Data["json"] = nil
 			l.ServeJSON()
 			return
@@ -96,22 +98,34 @@ func (l *LogAPI) Get() {
 		query.ProjectIDs = ids
 	}
 
-	total, err := dao.GetTotalOfAccessLogs(query)
+	total, err := countLogs(mode, query)
 	if err != nil {
 		l.HandleInternalServerError(fmt.Sprintf(
 			"failed to get total of access logs: %v", err))
 		return
 	}
 
-	logs, err := dao.GetAccessLogs(query)
+	logs, err := accesslog.List(query)
 	if err != nil {
 		l.HandleInternalServerError(fmt.Sprintf(
 			"failed to get access logs: %v", err))
 		return
 	}
 
-	l.SetPaginationHeader(total, page, size)
+	l.SetPaginationHeaderOfMode(mode, total, page, size, int64(len(logs)))
 
 	l.Data["json"] = logs
 	l.ServeJSON()
 }
+
+// countLogs counts the access logs matching the query according to the count mode
+func countLogs(mode string, query *models.LogQueryParam) (int64, error) {
+	switch mode {
+	case api.CountModeNone:
+		return 0, nil
+	case api.CountModeCapped:
+		return accesslog.CappedCount(query, api.MaxCappedCount)
+	default:
+		return accesslog.Count(query)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	// the duration of the overridden log level if it isn't specified
+	defaultLogLevelDuration = 30 * time.Minute
+	maxLogLevelDuration     = 24 * time.Hour
+)
+
+// LogLevelAPI changes the log levels of the components of core at runtime, the levels
+// are kept in memory of the instance serving the request and reverted automatically
+type LogLevelAPI struct {
+	BaseController
+	component *log.Component
+}
+
+type logLevelReq struct {
+	Level string `json:"level"`
+	// the seconds after which the level is reverted
+	Duration int64 `json:"duration"`
+}
+
+// Prepare validates the user and the component, it needs the system admin permission
+func (l *LogLevelAPI) Prepare() {
+	l.BaseController.Prepare()
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return
+	}
+	if !l.SecurityCtx.IsSysAdmin() {
+		l.HandleForbidden(l.SecurityCtx.GetUsername())
+		return
+	}
+
+	if name := l.GetStringFromPath(":name"); len(name) > 0 {
+		component, ok := log.GetComponent(name)
+		if !ok {
+			l.HandleNotFound(fmt.Sprintf("component %s not found", name))
+			return
+		}
+		l.component = component
+	}
+}
+
+// List returns the log levels of all the components
+func (l *LogLevelAPI) List() {
+	levels := []*log.ComponentLevel{}
+	for _, component := range log.Components() {
+		levels = append(levels, log.GetComponentLevel(component))
+	}
+	l.Data["json"] = levels
+	l.ServeJSON()
+}
+
+// Get returns the log level of the component
+func (l *LogLevelAPI) Get() {
+	l.Data["json"] = log.GetComponentLevel(l.component)
+	l.ServeJSON()
+}
+
+// Put overrides the log level of the component until the duration elapses
+func (l *LogLevelAPI) Put() {
+	req := &logLevelReq{}
+	l.DecodeJSONReq(req)
+	if len(req.Level) == 0 {
+		l.HandleBadRequest("level is required")
+		return
+	}
+	duration := defaultLogLevelDuration
+	if req.Duration != 0 {
+		duration = time.Duration(req.Duration) * time.Second
+	}
+	if duration <= 0 || duration > maxLogLevelDuration {
+		l.HandleBadRequest(fmt.Sprintf("duration must be between 1 and %d seconds", int64(maxLogLevelDuration/time.Second)))
+		return
+	}
+	if err := log.SetComponentLevel(l.component.Name, req.Level, duration); err != nil {
+		l.HandleBadRequest(err.Error())
+		return
+	}
+	log.Infof("log level of %s is set to %s for %v by %s", l.component.Name, req.Level, duration, l.SecurityCtx.GetUsername())
+}
+
+// Delete reverts the log level of the component immediately
+func (l *LogLevelAPI) Delete() {
+	log.ResetComponentLevel(l.component.Name)
+	log.Infof("log level of %s is reverted by %s", l.component.Name, l.SecurityCtx.GetUsername())
+}
@@ -0,0 +1,137 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var logLevelPath = "/api/system/log_levels"
+
+func TestLogLevelAPI(t *testing.T) {
+	defer log.ResetComponentLevel("replication")
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    logLevelPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        logLevelPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        logLevelPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        logLevelPath + "/unknown",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, level is required
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        logLevelPath + "/replication",
+				bodyJSON:   &logLevelReq{},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid level
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        logLevelPath + "/replication",
+				bodyJSON:   &logLevelReq{Level: "verbose"},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the duration is too long
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        logLevelPath + "/replication",
+				bodyJSON:   &logLevelReq{Level: "debug", Duration: 7 * 24 * 3600},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        logLevelPath + "/replication",
+				bodyJSON:   &logLevelReq{Level: "debug", Duration: 600},
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	level := &log.ComponentLevel{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        logLevelPath + "/replication",
+		credential: sysAdmin,
+	}, level)
+	require.Nil(t, err)
+	assert.Equal(t, "DEBUG", level.Level)
+	assert.NotNil(t, level.ExpiresAt)
+
+	// revert
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        logLevelPath + "/replication",
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	level = &log.ComponentLevel{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        logLevelPath + "/replication",
+		credential: sysAdmin,
+	}, level)
+	require.Nil(t, err)
+	assert.Nil(t, level.ExpiresAt)
+}
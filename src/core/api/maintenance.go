@@ -0,0 +1,109 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// MaintenanceAPI switches Harbor into or out of maintenance mode, the modifications are rejected with
+// the message in maintenance mode except the ones from system admins, and the pushes are drained
+type MaintenanceAPI struct {
+	BaseController
+}
+
+type maintenanceReq struct {
+	Enabled     bool   `json:"enabled"`
+	Message     string `json:"message"`
+	RetryAfter  int    `json:"retry_after"`
+	DrainPeriod int    `json:"drain_period"`
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (m *MaintenanceAPI) Prepare() {
+	m.BaseController.Prepare()
+	if !m.SecurityCtx.IsAuthenticated() {
+		m.HandleUnauthorized()
+		return
+	}
+	if !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(m.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Get returns the state of maintenance mode
+func (m *MaintenanceAPI) Get() {
+	maintenance, err := config.Maintenance()
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to get the state of maintenance mode: %v", err))
+		return
+	}
+	m.Data["json"] = maintenance
+	m.ServeJSON()
+}
+
+// Put enables or disables maintenance mode, the drain period starts when it's enabled
+func (m *MaintenanceAPI) Put() {
+	req := &maintenanceReq{}
+	m.DecodeJSONReq(req)
+	if req.RetryAfter < 0 || req.DrainPeriod < 0 {
+		m.HandleBadRequest("retry_after and drain_period should be non-negative")
+		return
+	}
+
+	current, err := config.Maintenance()
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to get the state of maintenance mode: %v", err))
+		return
+	}
+	maintenance := &models.Maintenance{}
+	if req.Enabled {
+		maintenance = &models.Maintenance{
+			Enabled:     true,
+			Message:     req.Message,
+			RetryAfter:  req.RetryAfter,
+			DrainPeriod: req.DrainPeriod,
+			Since:       current.Since,
+		}
+		// keep the start time when only the message is updated
+		if !current.Enabled {
+			maintenance.Since = time.Now().UTC()
+		}
+	}
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to marshal the state of maintenance mode: %v", err))
+		return
+	}
+	if err = config.Upload(map[string]interface{}{
+		common.Maintenance: string(data),
+	}); err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to update the state of maintenance mode: %v", err))
+		return
+	}
+	if err = config.Load(); err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to load the configurations: %v", err))
+		return
+	}
+	log.Infof("maintenance mode is set to enabled=%t by %s", maintenance.Enabled, m.SecurityCtx.GetUsername())
+}
@@ -0,0 +1,118 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var maintenancePath = "/api/system/maintenance"
+
+func TestMaintenanceAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    maintenancePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        maintenancePath,
+				bodyJSON:   &maintenanceReq{Enabled: true},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    maintenancePath,
+				bodyJSON: &maintenanceReq{
+					Enabled:    true,
+					RetryAfter: -1,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    maintenancePath,
+				bodyJSON: &maintenanceReq{
+					Enabled:     true,
+					Message:     "upgrading",
+					RetryAfter:  600,
+					DrainPeriod: 300,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	maintenance := &models.Maintenance{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        maintenancePath,
+		credential: sysAdmin,
+	}, maintenance)
+	require.Nil(t, err)
+	assert.True(t, maintenance.Enabled)
+	assert.Equal(t, "upgrading", maintenance.Message)
+	assert.Equal(t, 600, maintenance.RetryAfter)
+	assert.False(t, maintenance.Since.IsZero())
+
+	info := &GeneralInfo{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/systeminfo",
+	}, info)
+	require.Nil(t, err)
+	assert.True(t, info.InMaintenance)
+	assert.Equal(t, "upgrading", info.MaintenanceMessage)
+
+	// switch off
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        maintenancePath,
+			bodyJSON:   &maintenanceReq{},
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	maintenance = &models.Maintenance{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        maintenancePath,
+		credential: sysAdmin,
+	}, maintenance)
+	require.Nil(t, err)
+	assert.False(t, maintenance.Enabled)
+}
@@ -17,13 +17,16 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
 	"github.com/goharbor/harbor/src/core/promgr/metamgr"
+	"github.com/goharbor/harbor/src/core/scanner"
 )
 
 // MetadataAPI ...
@@ -133,6 +136,10 @@ func (m *MetadataAPI) Post() {
 		m.HandleBadRequest(err.Error())
 		return
 	}
+	if name, ok := sysAdminOnlyMetadata(ms); ok && !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(fmt.Sprintf("only the system admins can set the metadata %s", name))
+		return
+	}
 
 	if len(ms) != 1 {
 		m.HandleBadRequest("invalid request: has no valid key/value pairs or has more than one valid key/value pairs")
@@ -155,6 +162,7 @@ func (m *MetadataAPI) Post() {
 		m.HandleInternalServerError(fmt.Sprintf("failed to create metadata for project %d: %v", m.project.ProjectID, err))
 		return
 	}
+	addMetadataChanges(m.SecurityCtx.GetUsername(), m.project, nil, ms)
 
 	m.Ctx.ResponseWriter.WriteHeader(http.StatusCreated)
 }
@@ -177,21 +185,57 @@ func (m *MetadataAPI) Put() {
 		m.HandleBadRequest(err.Error())
 		return
 	}
+	if name, ok := sysAdminOnlyMetadata(ms); ok && !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(fmt.Sprintf("only the system admins can set the metadata %s", name))
+		return
+	}
 
+	old, err := m.metaMgr.Get(m.project.ProjectID, m.name)
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to get metadata %s of project %d: %v", m.name, m.project.ProjectID, err))
+		return
+	}
 	if err := m.metaMgr.Update(m.project.ProjectID, map[string]string{
 		m.name: ms[m.name],
 	}); err != nil {
 		m.HandleInternalServerError(fmt.Sprintf("failed to update metadata %s of project %d: %v", m.name, m.project.ProjectID, err))
 		return
 	}
+	addMetadataChanges(m.SecurityCtx.GetUsername(), m.project, old, map[string]string{
+		m.name: ms[m.name],
+	})
 }
 
 // Delete ...
 func (m *MetadataAPI) Delete() {
+	if name, ok := sysAdminOnlyMetadata(map[string]string{m.name: ""}); ok && !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(fmt.Sprintf("only the system admins can delete the metadata %s", name))
+		return
+	}
+	old, err := m.metaMgr.Get(m.project.ProjectID, m.name)
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to get metadata %s of project %d: %v", m.name, m.project.ProjectID, err))
+		return
+	}
 	if err := m.metaMgr.Delete(m.project.ProjectID, m.name); err != nil {
 		m.HandleInternalServerError(fmt.Sprintf("failed to delete metadata %s of project %d: %v", m.name, m.project.ProjectID, err))
 		return
 	}
+	addMetadataChanges(m.SecurityCtx.GetUsername(), m.project, old, nil)
+}
+
+// addMetadataChanges records the changes of the metadata of the project, e.g. the retention
+// policy and the webhooks, in the access log
+func addMetadataChanges(username string, project *models.Project, old, new map[string]string) {
+	if err := accesslog.AddChanges(models.AccessLog{
+		Username:  username,
+		ProjectID: project.ProjectID,
+		RepoName:  project.Name + "/",
+		RepoTag:   "N/A",
+		Operation: "update_metadata",
+	}, old, new); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
 }
 
 // validate metas and return a new map which contains the valid key/value pairs only
@@ -204,7 +248,8 @@ func validateProjectMetadata(metas map[string]string) (map[string]string, error)
 		models.ProMetaPublic,
 		models.ProMetaEnableContentTrust,
 		models.ProMetaPreventVul,
-		models.ProMetaAutoScan}
+		models.ProMetaAutoScan,
+		models.ProMetaExportMetrics}
 
 	for _, boolMeta := range boolMetas {
 		value, exist := metas[boolMeta]
@@ -227,5 +272,113 @@ func validateProjectMetadata(metas map[string]string) (map[string]string, error)
 		}
 	}
 
+	if err := scanner.Validate(metas[models.ProMetaScanner]); err != nil {
+		return nil, err
+	}
+
+	value, exist = metas[models.ProMetaStorageQuota]
+	if exist {
+		quota, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || quota < -1 {
+			return nil, fmt.Errorf("invalid storage quota %s, it must be the bytes or -1 for unlimited", value)
+		}
+		metas[models.ProMetaStorageQuota] = strconv.FormatInt(quota, 10)
+	}
+
+	for _, name := range []string{models.ProMetaMaxImageSize, models.ProMetaMaxLayerSize} {
+		value, exist = metas[name]
+		if exist {
+			limit, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || limit < -1 {
+				return nil, fmt.Errorf("invalid %s %s, it must be the bytes or -1 for unlimited", name, value)
+			}
+			metas[name] = strconv.FormatInt(limit, 10)
+		}
+	}
+
+	value, exist = metas[models.ProMetaQuotaThresholds]
+	if exist {
+		thresholds, err := models.ParseQuotaThresholds(value)
+		if err != nil {
+			return nil, err
+		}
+		strs := []string{}
+		for _, threshold := range thresholds {
+			strs = append(strs, strconv.Itoa(threshold))
+		}
+		metas[models.ProMetaQuotaThresholds] = strings.Join(strs, ",")
+	}
+
+	value, exist = metas[models.ProMetaTrustPatterns]
+	if exist {
+		if _, err := models.ParseContentTrustPatterns(value); err != nil {
+			return nil, err
+		}
+	}
+
+	value, exist = metas[models.ProMetaDigestPullRepos]
+	if exist {
+		if _, err := models.ParseDigestPullPatterns(value); err != nil {
+			return nil, err
+		}
+	}
+
+	value, exist = metas[models.ProMetaRetentionPolicy]
+	if exist && len(value) > 0 {
+		if _, err := models.ParseRetentionPolicy(value); err != nil {
+			return nil, fmt.Errorf("invalid retention policy: %v", err)
+		}
+	}
+
+	value, exist = metas[models.ProMetaStorageHint]
+	if exist && len(value) > 0 {
+		if _, err := models.ParseStorageHint(value); err != nil {
+			return nil, fmt.Errorf("invalid storage hint: %v", err)
+		}
+	}
+
+	value, exist = metas[models.ProMetaLintPolicy]
+	if exist && len(value) > 0 {
+		if _, err := models.ParseLintPolicy(value); err != nil {
+			return nil, fmt.Errorf("invalid lint policy: %v", err)
+		}
+	}
+
+	value, exist = metas[models.ProMetaTagPolicy]
+	if exist && len(value) > 0 {
+		if _, err := models.ParseTagPolicy(value); err != nil {
+			return nil, fmt.Errorf("invalid tag policy: %v", err)
+		}
+	}
+
+	if _, err := models.ParseNotificationTime(metas[models.ProMetaNotificationTZ],
+		metas[models.ProMetaNotificationTimeFormat]); err != nil {
+		return nil, err
+	}
+
+	value, exist = metas[models.ProMetaQuotaWebhookURL]
+	if exist && len(value) > 0 {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return nil, fmt.Errorf("invalid quota webhook URL %s", value)
+		}
+	}
+
+	value, exist = metas[models.ProMetaQuotaWebhookSchema]
+	if exist && !models.ValidWebhookSchema(value) {
+		return nil, fmt.Errorf("invalid quota webhook schema %s, should be %s or %s",
+			value, models.WebhookSchemaV1, models.WebhookSchemaV2)
+	}
+
 	return metas, nil
 }
+
+// sysAdminOnlyMetadata returns the first metadata which can only be changed by the system admins
+func sysAdminOnlyMetadata(metas map[string]string) (string, bool) {
+	for _, name := range []string{models.ProMetaStorageQuota, models.ProMetaMaxImageSize, models.ProMetaMaxLayerSize} {
+		if _, exist := metas[name]; exist {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/metasync"
+)
+
+// MetadataSyncAPI handles request to /api/metadata_sync
+type MetadataSyncAPI struct {
+	BaseController
+}
+
+// Prepare validates the user, only the system admin is allowed to access the metadata sync
+func (m *MetadataSyncAPI) Prepare() {
+	m.BaseController.Prepare()
+	if !m.SecurityCtx.IsAuthenticated() {
+		m.HandleUnauthorized()
+		return
+	}
+	if !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(m.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// ListStates lists the states of the metadata sync of the projects
+func (m *MetadataSyncAPI) ListStates() {
+	states, err := dao.ListMetadataSyncStates()
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to list the metadata sync states: %v", err))
+		return
+	}
+	m.WriteJSONData(states)
+}
+
+// Post syncs the metadata with the peer instance immediately
+func (m *MetadataSyncAPI) Post() {
+	err := metasync.Sync()
+	switch err {
+	case nil:
+	case metasync.ErrNotConfigured:
+		m.HandleStatusPreconditionFailed(err.Error())
+	case metasync.ErrSyncRunning:
+		m.HandleConflict(err.Error())
+	default:
+		m.HandleInternalServerError(fmt.Sprintf("failed to sync the metadata: %v", err))
+	}
+}
+
+// MetadataSyncSnapshotAPI returns the snapshot of the instance to the peer, it's
+// authenticated by the secret shared by the two instances rather than the users
+type MetadataSyncSnapshotAPI struct {
+	BaseController
+}
+
+// Get returns the snapshot of the configurations of all projects
+func (m *MetadataSyncSnapshotAPI) Get() {
+	cfg, err := config.MetadataSync()
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to get the configurations of metadata sync: %v", err))
+		return
+	}
+	secret := m.Ctx.Request.Header.Get(metasync.SecretHeader)
+	if len(cfg.Secret) == 0 || subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.Secret)) != 1 {
+		m.HandleUnauthorized()
+		return
+	}
+	snapshot, err := metasync.TakeSnapshot()
+	if err != nil {
+		m.HandleInternalServerError(fmt.Sprintf("failed to take the snapshot: %v", err))
+		return
+	}
+	m.WriteJSONData(snapshot)
+}
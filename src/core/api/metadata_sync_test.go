@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMetadataSyncAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/metadata_sync/states",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/metadata_sync",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 412, the peer isn't configured
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/metadata_sync",
+				credential: sysAdmin,
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/metadata_sync/states",
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 401, the secret isn't configured
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/internal/metadata_sync/snapshot",
+				credential: sysAdmin,
+			},
+			code: http.StatusUnauthorized,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
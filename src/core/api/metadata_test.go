@@ -60,6 +60,62 @@ func TestValidateProjectMetadata(t *testing.T) {
 	ms, err = validateProjectMetadata(metas)
 	require.Nil(t, err)
 	assert.Equal(t, "high", ms[models.ProMetaSeverity])
+
+	// unknown scanner
+	metas = map[string]string{
+		models.ProMetaScanner: "unknown",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.NotNil(t, err)
+
+	// quota
+	metas = map[string]string{
+		models.ProMetaStorageQuota:       "1024",
+		models.ProMetaQuotaThresholds:    "95,50, 80",
+		models.ProMetaQuotaWebhookURL:    "https://example.com/hook",
+		models.ProMetaQuotaWebhookSchema: models.WebhookSchemaV2,
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.Nil(t, err)
+	assert.Equal(t, "1024", ms[models.ProMetaStorageQuota])
+	assert.Equal(t, "50,80,95", ms[models.ProMetaQuotaThresholds])
+
+	// size limits
+	metas = map[string]string{
+		models.ProMetaMaxImageSize: "1073741824",
+		models.ProMetaMaxLayerSize: "-1",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.Nil(t, err)
+	assert.Equal(t, "1073741824", ms[models.ProMetaMaxImageSize])
+	assert.Equal(t, "-1", ms[models.ProMetaMaxLayerSize])
+
+	for name, value := range map[string]string{
+		models.ProMetaStorageQuota:           "-2",
+		models.ProMetaMaxImageSize:           "1GB",
+		models.ProMetaQuotaThresholds:        "0",
+		models.ProMetaQuotaWebhookURL:        "ftp://example.com",
+		models.ProMetaQuotaWebhookSchema:     "v3",
+		models.ProMetaTrustPatterns:          "app/[:release-*",
+		models.ProMetaDigestPullRepos:        "app/[",
+		models.ProMetaRetentionPolicy:        `{"keep_latest":-1}`,
+		models.ProMetaStorageHint:            `{"storage_class":"DEEP_ARCHIVE","after_days":30}`,
+		models.ProMetaLintPolicy:             `{"max_layers":-1}`,
+		models.ProMetaTagPolicy:              `{"deny":["(latest"]}`,
+		models.ProMetaNotificationTZ:         "Mars/Olympus_Mons",
+		models.ProMetaNotificationTimeFormat: "epoch",
+	} {
+		_, err = validateProjectMetadata(map[string]string{name: value})
+		assert.NotNil(t, err, "%s: %s", name, value)
+	}
+
+	name, ok := sysAdminOnlyMetadata(map[string]string{models.ProMetaStorageQuota: "1024"})
+	assert.True(t, ok)
+	assert.Equal(t, models.ProMetaStorageQuota, name)
+	_, ok = sysAdminOnlyMetadata(map[string]string{models.ProMetaMaxLayerSize: "1024"})
+	assert.True(t, ok)
+	_, ok = sysAdminOnlyMetadata(map[string]string{models.ProMetaQuotaWebhookURL: ""})
+	assert.False(t, ok)
 }
 
 func TestMetaAPI(t *testing.T) {
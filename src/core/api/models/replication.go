@@ -15,9 +15,15 @@
 package models
 
 import (
+	"fmt"
+
 	"github.com/astaxie/beego/validation"
+	"github.com/opencontainers/go-digest"
 )
 
+// the max count of the artifacts which can be replicated in one execution
+const maxReplicationArtifacts = 100
+
 // Replication defines the properties of model used in replication API
 type Replication struct {
 	PolicyID int64 `json:"policy_id"`
@@ -34,3 +40,52 @@ func (r *Replication) Valid(v *validation.Validation) {
 		v.SetError("policy_id", "invalid value")
 	}
 }
+
+// ReplicationExecution defines the properties of model used to replicate the specified
+// artifacts to the target without the policy
+type ReplicationExecution struct {
+	TargetID  int64                  `json:"target_id"`
+	Artifacts []*ReplicationArtifact `json:"artifacts"`
+}
+
+// ReplicationArtifact is the artifact to be replicated, it's referenced by either tag or digest
+type ReplicationArtifact struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Digest     string `json:"digest"`
+}
+
+// Reference returns the tag or digest of the artifact
+func (r *ReplicationArtifact) Reference() string {
+	if len(r.Digest) > 0 {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// Valid ...
+func (r *ReplicationExecution) Valid(v *validation.Validation) {
+	if r.TargetID <= 0 {
+		v.SetError("target_id", "invalid value")
+	}
+	if len(r.Artifacts) == 0 || len(r.Artifacts) > maxReplicationArtifacts {
+		v.SetError("artifacts", fmt.Sprintf("the count must be between 1 and %d", maxReplicationArtifacts))
+		return
+	}
+	for _, artifact := range r.Artifacts {
+		if artifact == nil || len(artifact.Repository) == 0 {
+			v.SetError("artifacts", "repository is required")
+			return
+		}
+		if (len(artifact.Tag) == 0) == (len(artifact.Digest) == 0) {
+			v.SetError("artifacts", fmt.Sprintf("one of tag and digest is required for %s", artifact.Repository))
+			return
+		}
+		if len(artifact.Digest) > 0 {
+			if _, err := digest.Parse(artifact.Digest); err != nil {
+				v.SetError("artifacts", fmt.Sprintf("invalid digest %s: %v", artifact.Digest, err))
+				return
+			}
+		}
+	}
+}
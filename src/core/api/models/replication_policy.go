@@ -29,6 +29,7 @@ type ReplicationPolicy struct {
 	Description               string                     `json:"description"`
 	Filters                   []rep_models.Filter        `json:"filters"`
 	ReplicateDeletion         bool                       `json:"replicate_deletion"`
+	ReplicateRobots           bool                       `json:"replicate_robots"`
 	Trigger                   *rep_models.Trigger        `json:"trigger"`
 	Projects                  []*common_models.Project   `json:"projects"`
 	Targets                   []*common_models.RepTarget `json:"targets"`
@@ -15,19 +15,27 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
 
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	errutil "github.com/goharbor/harbor/src/common/utils/error"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/approval"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/projectconfig"
+	"github.com/goharbor/harbor/src/core/promgr"
 
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -46,6 +54,12 @@ const projectNameMaxLen int = 255
 const projectNameMinLen int = 2
 const restrictedNameChars = `[a-z0-9]+(?:[._-][a-z0-9]+)*`
 
+// the default period of listing the moved tags
+const movedTagsPeriod = 7 * 24 * time.Hour
+
+// the default period of listing the vulnerability trend
+const vulnerabilityTrendPeriod = 90 * 24 * time.Hour
+
 // Prepare validates the URL and the user
 func (p *ProjectAPI) Prepare() {
 	p.BaseController.Prepare()
@@ -73,6 +87,22 @@ func (p *ProjectAPI) Prepare() {
 			return
 		}
 
+		p.project = project
+		return
+	}
+
+	if name := p.GetStringFromPath(":name"); len(name) != 0 {
+		project, err := p.ProjectMgr.Get(name)
+		if err != nil {
+			p.ParseAndHandleError(fmt.Sprintf("failed to get project %s", name), err)
+			return
+		}
+
+		if project == nil {
+			p.HandleNotFound(fmt.Sprintf("project %s not found", name))
+			return
+		}
+
 		p.project = project
 	}
 }
@@ -83,19 +113,17 @@ func (p *ProjectAPI) Post() {
 		p.HandleUnauthorized()
 		return
 	}
-	var onlyAdmin bool
+	restriction := common.ProCrtRestrAdmOnly
 	var err error
-	if config.WithAdmiral() {
-		onlyAdmin = true
-	} else {
-		onlyAdmin, err = config.OnlyAdminCreateProject()
+	if !config.WithAdmiral() {
+		restriction, err = config.ProjectCreationRestriction()
 		if err != nil {
 			log.Errorf("failed to determine whether only admin can create projects: %v", err)
 			p.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		}
 	}
 
-	if onlyAdmin && !p.SecurityCtx.IsSysAdmin() {
+	if restriction == common.ProCrtRestrAdmOnly && !p.SecurityCtx.IsSysAdmin() {
 		log.Errorf("Only sys admin can create project")
 		p.RenderError(http.StatusForbidden, "Only system admin can create project")
 		return
@@ -108,6 +136,10 @@ func (p *ProjectAPI) Post() {
 		p.RenderError(http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
 		return
 	}
+	if name, ok := sysAdminOnlyMetadata(pro.Metadata); ok && !p.SecurityCtx.IsSysAdmin() {
+		p.HandleForbidden(fmt.Sprintf("only the system admins can set the metadata %s", name))
+		return
+	}
 
 	exist, err := p.ProjectMgr.Exists(pro.Name)
 	if err != nil {
@@ -133,11 +165,28 @@ func (p *ProjectAPI) Post() {
 		pro.Metadata[models.ProMetaPublic] = strconv.FormatBool(false)
 	}
 
-	projectID, err := p.ProjectMgr.Create(&models.Project{
-		Name:      pro.Name,
-		OwnerName: p.SecurityCtx.GetUsername(),
-		Metadata:  pro.Metadata,
-	})
+	// the project is created once the request is approved by the system admin
+	if restriction == common.ProCrtRestrApproval && !p.SecurityCtx.IsSysAdmin() {
+		payload, err := json.Marshal(pro)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to marshal the project request: %v", err))
+			return
+		}
+		id, err := approval.Submit(&models.Approval{
+			Type:      models.ApprovalProjectCreation,
+			Requester: p.SecurityCtx.GetUsername(),
+			Summary:   fmt.Sprintf("User %s requests to create project %s", p.SecurityCtx.GetUsername(), pro.Name),
+			Payload:   string(payload),
+		})
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to submit the approval of project %s: %v", pro.Name, err))
+			return
+		}
+		p.Ctx.Redirect(http.StatusAccepted, fmt.Sprintf("/api/approvals/%d", id))
+		return
+	}
+
+	projectID, err := createProject(p.ProjectMgr, pro, p.SecurityCtx.GetUsername())
 	if err != nil {
 		if err == errutil.ErrDupProject {
 			log.Debugf("conflict %s", pro.Name)
@@ -148,10 +197,23 @@ func (p *ProjectAPI) Post() {
 		return
 	}
 
+	p.Redirect(http.StatusCreated, strconv.FormatInt(projectID, 10))
+}
+
+func createProject(projectMgr promgr.ProjectManager, pro *models.ProjectRequest, owner string) (int64, error) {
+	projectID, err := projectMgr.Create(&models.Project{
+		Name:      pro.Name,
+		OwnerName: owner,
+		Metadata:  pro.Metadata,
+	})
+	if err != nil {
+		return 0, err
+	}
+
 	go func() {
-		if err = dao.AddAccessLog(
+		if err := accesslog.Add(
 			models.AccessLog{
-				Username:  p.SecurityCtx.GetUsername(),
+				Username:  owner,
 				ProjectID: projectID,
 				RepoName:  pro.Name + "/",
 				RepoTag:   "N/A",
@@ -162,7 +224,51 @@ func (p *ProjectAPI) Post() {
 		}
 	}()
 
-	p.Redirect(http.StatusCreated, strconv.FormatInt(projectID, 10))
+	// the bootstrap hooks are applied before responding, so that the automation
+	// never races the owner of the project
+	project := &models.Project{
+		ProjectID: projectID,
+		Name:      pro.Name,
+		OwnerName: owner,
+	}
+	results, err := projectconfig.NewManager(projectMgr.GetMetadataManager()).RunBootstrapHooks(project)
+	if err != nil {
+		log.Errorf("failed to run the bootstrap hooks of project %s: %v", pro.Name, err)
+	}
+	if err := notifier.Publish(notifier.ProjectTopic, notifier.ProjectEvent{
+		Event:       notifier.ProjectEventCreated,
+		ProjectID:   projectID,
+		ProjectName: pro.Name,
+		Owner:       owner,
+		Bootstrap:   results,
+		OccurAt:     time.Now(),
+	}); err != nil {
+		log.Errorf("failed to publish the creation event of project %s: %v", pro.Name, err)
+	}
+	return projectID, nil
+}
+
+// projectCreationApprovalHandler creates the projects requested by the users other than system
+// admin once the requests are approved, the requester is the owner of the project
+type projectCreationApprovalHandler struct{}
+
+func (h *projectCreationApprovalHandler) Approve(ap *models.Approval, reviewer, comment string) error {
+	pro := &models.ProjectRequest{}
+	if err := json.Unmarshal([]byte(ap.Payload), pro); err != nil {
+		return fmt.Errorf("failed to unmarshal the project request of approval %d: %v", ap.ID, err)
+	}
+	if _, err := createProject(config.GlobalProjectMgr, pro, ap.Requester); err != nil {
+		return fmt.Errorf("failed to create project %s: %v", pro.Name, err)
+	}
+	return nil
+}
+
+func (h *projectCreationApprovalHandler) Reject(ap *models.Approval, reviewer, comment string) error {
+	return nil
+}
+
+func init() {
+	approval.Register(models.ApprovalProjectCreation, &projectCreationApprovalHandler{})
 }
 
 // Head ...
@@ -180,7 +286,7 @@ func (p *ProjectAPI) Head() {
 	}
 
 	if project == nil {
-		p.HandleNotFound(fmt.Sprintf("project %s not found", name))
+		p.HandleNotFound(p.T(i18n.MsgProjectNotFound, name))
 		return
 	}
 }
@@ -227,13 +333,17 @@ func (p *ProjectAPI) Delete() {
 		p.CustomAbort(http.StatusPreconditionFailed, result.Message)
 	}
 
+	if !p.Confirmed() {
+		return
+	}
+
 	if err = p.ProjectMgr.Delete(p.project.ProjectID); err != nil {
 		p.ParseAndHandleError(fmt.Sprintf("failed to delete project %d", p.project.ProjectID), err)
 		return
 	}
 
 	go func() {
-		if err := dao.AddAccessLog(models.AccessLog{
+		if err := accesslog.Add(models.AccessLog{
 			Username:  p.SecurityCtx.GetUsername(),
 			ProjectID: p.project.ProjectID,
 			RepoName:  p.project.Name + "/",
@@ -320,9 +430,15 @@ func (p *ProjectAPI) deletable(projectID int64) (*deletableResp, error) {
 func (p *ProjectAPI) List() {
 	// query strings
 	page, size := p.GetPaginationParams()
+	name, exact, err := p.GetNameQuery()
+	if err != nil {
+		p.HandleBadRequest(err.Error())
+		return
+	}
 	query := &models.ProjectQueryParam{
-		Name:  p.GetString("name"),
-		Owner: p.GetString("owner"),
+		Name:           name,
+		ExactMatchName: exact,
+		Owner:          p.GetString("owner"),
 		Pagination: &models.Pagination{
 			Page: page,
 			Size: size,
@@ -339,6 +455,25 @@ func (p *ProjectAPI) List() {
 		query.Public = &pub
 	}
 
+	// the custom metadata are queried as "name=value" or "name" matching any value
+	for _, value := range p.GetStrings("custom_metadata") {
+		if len(value) == 0 {
+			continue
+		}
+		if query.CustomMetadata == nil {
+			query.CustomMetadata = map[string]string{}
+		}
+		parts := strings.SplitN(value, "=", 2)
+		if err := models.ValidateCustomMetadataName(parts[0]); err != nil {
+			p.HandleBadRequest(err.Error())
+			return
+		}
+		query.CustomMetadata[parts[0]] = ""
+		if len(parts) == 2 {
+			query.CustomMetadata[parts[0]] = parts[1]
+		}
+	}
+
 	// standalone, filter projects according to the privilleges of the user first
 	if !config.WithAdmiral() {
 		var projects []*models.Project
@@ -391,6 +526,26 @@ func (p *ProjectAPI) List() {
 		p.populateProperties(project)
 	}
 
+	withDetail, err := p.GetBool("with_detail", false)
+	if err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid with_detail: %s", p.GetString("with_detail")))
+		return
+	}
+	if withDetail {
+		ids := []int64{}
+		for _, project := range result.Projects {
+			ids = append(ids, project.ProjectID)
+		}
+		metadata, err := getCustomMetadata(ids...)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to list the custom metadata of projects: %v", err))
+			return
+		}
+		for _, project := range result.Projects {
+			project.CustomMetadata = metadata[project.ProjectID]
+		}
+	}
+
 	p.SetPaginationHeader(result.Total, page, size)
 	p.Data["json"] = result.Projects
 	p.ServeJSON()
@@ -445,6 +600,16 @@ func (p *ProjectAPI) Put() {
 
 	var req *models.ProjectRequest
 	p.DecodeJSONReq(&req)
+	metas, err := validateProjectMetadata(req.Metadata)
+	if err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if name, ok := sysAdminOnlyMetadata(metas); ok && !p.SecurityCtx.IsSysAdmin() {
+		p.HandleForbidden(fmt.Sprintf("only the system admins can set the metadata %s", name))
+		return
+	}
+	req.Metadata = metas
 
 	if err := p.ProjectMgr.Update(p.project.ProjectID,
 		&models.Project{
@@ -454,6 +619,13 @@ func (p *ProjectAPI) Put() {
 			p.project.ProjectID), err)
 		return
 	}
+	current := map[string]string{}
+	for name := range req.Metadata {
+		if value, ok := p.project.Metadata[name]; ok {
+			current[name] = value
+		}
+	}
+	addMetadataChanges(p.SecurityCtx.GetUsername(), p.project, current, req.Metadata)
 }
 
 // Logs ...
@@ -469,6 +641,7 @@ func (p *ProjectAPI) Logs() {
 	}
 
 	page, size := p.GetPaginationParams()
+	mode := p.GetCountMode()
 	query := &models.LogQueryParam{
 		ProjectIDs: []int64{p.project.ProjectID},
 		Username:   p.GetString("username"),
@@ -501,25 +674,225 @@ func (p *ProjectAPI) Logs() {
 		query.EndTime = t
 	}
 
-	total, err := dao.GetTotalOfAccessLogs(query)
+	total, err := countLogs(mode, query)
 	if err != nil {
 		p.HandleInternalServerError(fmt.Sprintf(
 			"failed to get total of access log: %v", err))
 		return
 	}
 
-	logs, err := dao.GetAccessLogs(query)
+	logs, err := accesslog.List(query)
 	if err != nil {
 		p.HandleInternalServerError(fmt.Sprintf(
 			"failed to get access log: %v", err))
 		return
 	}
 
-	p.SetPaginationHeader(total, page, size)
+	p.SetPaginationHeaderOfMode(mode, total, page, size, int64(len(logs)))
 	p.Data["json"] = logs
 	p.ServeJSON()
 }
 
+// MovedTags lists the tags of the project which point to new digests in the period, the
+// period is the last 7 days by default
+func (p *ProjectAPI) MovedTags() {
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if !p.SecurityCtx.HasReadPerm(p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	query := &models.MovedTagQuery{
+		Project:   p.project.Name,
+		BeginTime: time.Now().Add(-movedTagsPeriod),
+	}
+
+	timestamp := p.GetString("begin_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			p.HandleBadRequest(fmt.Sprintf("invalid begin_timestamp: %s", timestamp))
+			return
+		}
+		query.BeginTime = *t
+	}
+
+	timestamp = p.GetString("end_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			p.HandleBadRequest(fmt.Sprintf("invalid end_timestamp: %s", timestamp))
+			return
+		}
+		query.EndTime = *t
+	}
+
+	total, err := dao.CountMovedTags(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to count the moved tags of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	query.Page, query.Size = p.GetPaginationParams()
+	tags, err := dao.ListMovedTags(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the moved tags of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+
+	p.SetPaginationHeader(total, query.Page, query.Size)
+	p.Data["json"] = tags
+	p.ServeJSON()
+}
+
+// VulnerabilityTrend returns the daily snapshots of the vulnerabilities of the project in the
+// period, which is the last 90 days by default
+func (p *ProjectAPI) VulnerabilityTrend() {
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if !p.SecurityCtx.HasReadPerm(p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	begin := time.Now().Add(-vulnerabilityTrendPeriod)
+	query := &models.VulnerabilityTrendQuery{
+		ProjectID: p.project.ProjectID,
+		BeginTime: &begin,
+	}
+
+	timestamp := p.GetString("begin_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			p.HandleBadRequest(fmt.Sprintf("invalid begin_timestamp: %s", timestamp))
+			return
+		}
+		query.BeginTime = t
+	}
+
+	timestamp = p.GetString("end_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			p.HandleBadRequest(fmt.Sprintf("invalid end_timestamp: %s", timestamp))
+			return
+		}
+		query.EndTime = t
+	}
+
+	trends, err := dao.ListVulnerabilityTrends(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the vulnerability trend of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	p.Data["json"] = trends
+	p.ServeJSON()
+}
+
+// ExportConfig exports the metadata, members, robots and retention policies of the project as a
+// declarative document, which is in YAML by default and in JSON if the format is "json"
+func (p *ProjectAPI) ExportConfig() {
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if !p.SecurityCtx.HasAllPerm(p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	format := p.GetString("format", "yaml")
+	if format != "yaml" && format != "json" {
+		p.HandleBadRequest(fmt.Sprintf("invalid format %s, should be yaml or json", format))
+		return
+	}
+
+	cfg, err := projectconfig.NewManager(p.ProjectMgr.GetMetadataManager()).Export(p.project)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to export the configuration of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	if format == "json" {
+		p.WriteJSONData(cfg)
+		return
+	}
+	p.WriteYamlData(cfg)
+}
+
+// ApplyConfig applies the declarative document in YAML or JSON to the project, the changes
+// are returned without being applied if dry_run is true
+func (p *ProjectAPI) ApplyConfig() {
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if !p.SecurityCtx.HasAllPerm(p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	dryRun, err := p.GetBool("dry_run", false)
+	if err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid dry_run: %s", p.GetString("dry_run")))
+		return
+	}
+
+	cfg, err := projectconfig.Parse(p.Ctx.Input.CopyBody(1 << 32))
+	if err != nil {
+		p.HandleBadRequest(err.Error())
+		return
+	}
+	if len(cfg.Metadata) > 0 {
+		if cfg.Metadata, err = validateProjectMetadata(cfg.Metadata); err != nil {
+			p.HandleBadRequest(fmt.Sprintf("invalid metadata: %v", err))
+			return
+		}
+	}
+
+	mgr := projectconfig.NewManager(p.ProjectMgr.GetMetadataManager())
+	changes, err := mgr.Plan(p.project, cfg)
+	if err != nil {
+		if _, ok := err.(*projectconfig.InvalidConfigError); ok {
+			p.HandleBadRequest(err.Error())
+			return
+		}
+		p.HandleInternalServerError(fmt.Sprintf("failed to plan the configuration of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	for _, change := range changes {
+		if change.Section != projectconfig.SectionMetadata {
+			continue
+		}
+		if name, ok := sysAdminOnlyMetadata(map[string]string{change.Name: ""}); ok && !p.SecurityCtx.IsSysAdmin() {
+			p.HandleForbidden(fmt.Sprintf("only the system admins can set the metadata %s", name))
+			return
+		}
+	}
+
+	if dryRun {
+		p.WriteJSONData(&projectconfig.Result{
+			DryRun:  true,
+			Changes: changes,
+		})
+		return
+	}
+	result, err := mgr.Execute(p.project, changes)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to apply the configuration of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	p.WriteJSONData(result)
+}
+
 // TODO move this to package models
 func validateProjectReq(req *models.ProjectRequest) error {
 	pn := req.Name
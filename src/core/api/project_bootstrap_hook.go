@@ -0,0 +1,139 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/projectconfig"
+)
+
+// ProjectBootstrapHookAPI handles request to /api/project_bootstrap_hooks, the hooks are
+// applied to every new project right after its creation
+type ProjectBootstrapHookAPI struct {
+	BaseController
+	hook *models.ProjectBootstrapHook
+}
+
+// Prepare validates the user and the hook, only the system admin can manage the hooks
+func (p *ProjectBootstrapHookAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+	if !p.SecurityCtx.IsSysAdmin() {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	if len(p.GetStringFromPath(":id")) > 0 {
+		id, err := p.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			p.HandleBadRequest(fmt.Sprintf("invalid hook ID: %s", p.GetStringFromPath(":id")))
+			return
+		}
+		hook, err := dao.GetProjectBootstrapHook(id)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to get hook %d: %v", id, err))
+			return
+		}
+		if hook == nil {
+			p.HandleNotFound(fmt.Sprintf("hook %d not found", id))
+			return
+		}
+		p.hook = hook
+	}
+}
+
+// List lists all the hooks in the order they are applied
+func (p *ProjectBootstrapHookAPI) List() {
+	hooks, err := dao.ListProjectBootstrapHooks(false)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list hooks: %v", err))
+		return
+	}
+	p.Data["json"] = hooks
+	p.ServeJSON()
+}
+
+// Get returns the hook
+func (p *ProjectBootstrapHookAPI) Get() {
+	p.Data["json"] = p.hook
+	p.ServeJSON()
+}
+
+// Post registers a hook, the template is validated by rendering it for a sample project
+func (p *ProjectBootstrapHookAPI) Post() {
+	hook := &models.ProjectBootstrapHook{}
+	p.DecodeJSONReqAndValidate(hook)
+	if !p.validateTemplate(hook.Template) {
+		return
+	}
+	id, err := dao.AddProjectBootstrapHook(hook)
+	if err != nil {
+		if err == dao.ErrDupRows {
+			p.HandleConflict(fmt.Sprintf("hook %s already exists", hook.Name))
+			return
+		}
+		p.HandleInternalServerError(fmt.Sprintf("failed to add hook %s: %v", hook.Name, err))
+		return
+	}
+	p.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// Put updates the hook, the projects created already aren't affected
+func (p *ProjectBootstrapHookAPI) Put() {
+	hook := &models.ProjectBootstrapHook{}
+	p.DecodeJSONReqAndValidate(hook)
+	if !p.validateTemplate(hook.Template) {
+		return
+	}
+	hook.ID = p.hook.ID
+	if err := dao.UpdateProjectBootstrapHook(hook); err != nil {
+		if err == dao.ErrDupRows {
+			p.HandleConflict(fmt.Sprintf("hook %s already exists", hook.Name))
+			return
+		}
+		p.HandleInternalServerError(fmt.Sprintf("failed to update hook %d: %v", hook.ID, err))
+		return
+	}
+}
+
+// Delete removes the hook
+func (p *ProjectBootstrapHookAPI) Delete() {
+	if err := dao.DeleteProjectBootstrapHook(p.hook.ID); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to delete hook %d: %v", p.hook.ID, err))
+		return
+	}
+}
+
+func (p *ProjectBootstrapHookAPI) validateTemplate(tmpl string) bool {
+	cfg, err := projectconfig.ValidateTemplate(tmpl)
+	if err != nil {
+		p.HandleBadRequest(err.Error())
+		return false
+	}
+	if len(cfg.Metadata) > 0 {
+		if _, err = validateProjectMetadata(cfg.Metadata); err != nil {
+			p.HandleBadRequest(fmt.Sprintf("invalid metadata: %v", err))
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var projectBootstrapHookPath = "/api/project_bootstrap_hooks"
+
+func TestProjectBootstrapHookAPI(t *testing.T) {
+	tmpl := "apiVersion: v1\nkind: ProjectConfiguration\nproject: {{.Project}}\nlabels:\n- name: owned-by-{{.Owner}}\n"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    projectBootstrapHookPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        projectBootstrapHookPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no template
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectBootstrapHookPath,
+				bodyJSON: &models.ProjectBootstrapHook{
+					Name: "labels",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the document isn't for the new project
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectBootstrapHookPath,
+				bodyJSON: &models.ProjectBootstrapHook{
+					Name:     "labels",
+					Template: "apiVersion: v1\nkind: ProjectConfiguration\nproject: library\n",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid metadata
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectBootstrapHookPath,
+				bodyJSON: &models.ProjectBootstrapHook{
+					Name:     "labels",
+					Template: "apiVersion: v1\nkind: ProjectConfiguration\nproject: {{.Project}}\nmetadata:\n  public: maybe\n",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectBootstrapHookPath,
+				bodyJSON: &models.ProjectBootstrapHook{
+					Name:     "labels",
+					Template: tmpl,
+					Enabled:  true,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectBootstrapHookPath,
+				bodyJSON: &models.ProjectBootstrapHook{
+					Name:     "labels",
+					Template: tmpl,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+	defer dao.ClearTable(models.ProjectBootstrapHookTable)
+
+	hooks := []*models.ProjectBootstrapHook{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        projectBootstrapHookPath,
+		credential: sysAdmin,
+	}, &hooks)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(hooks))
+	hook := hooks[0]
+	assert.Equal(t, "labels", hook.Name)
+	assert.True(t, hook.Enabled)
+
+	// the hook is applied to the new project
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPost,
+			url:    "/api/projects",
+			bodyJSON: &models.ProjectRequest{
+				Name: "project_for_test_bootstrap",
+			},
+			credential: sysAdmin,
+		},
+		code: http.StatusCreated,
+	})
+	project, err := dao.GetProjectByName("project_for_test_bootstrap")
+	require.Nil(t, err)
+	require.NotNil(t, project)
+	defer dao.DeleteProject(project.ProjectID)
+	labels, err := dao.ListLabels(&models.LabelQuery{
+		Scope:     common.LabelScopeProject,
+		ProjectID: project.ProjectID,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(labels))
+	defer dao.DeleteLabel(labels[0].ID)
+	assert.Equal(t, "owned-by-admin", labels[0].Name)
+
+	hookPath := fmt.Sprintf("%s/%d", projectBootstrapHookPath, hook.ID)
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPut,
+			url:    hookPath,
+			bodyJSON: &models.ProjectBootstrapHook{
+				Name:     "default-labels",
+				Template: tmpl,
+			},
+			credential: sysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	stored, err := dao.GetProjectBootstrapHook(hook.ID)
+	require.Nil(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "default-labels", stored.Name)
+	assert.False(t, stored.Enabled)
+
+	cases = []*codeCheckingCase{
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        hookPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        hookPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,144 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// ProjectCustomMetadataAPI handles the requests on /api/projects/{}/custom_metadata to manage
+// the arbitrary key/value metadata of the project, e.g. the team owner and the cost center
+type ProjectCustomMetadataAPI struct {
+	BaseController
+	project *models.Project
+	name    string
+}
+
+// Prepare ...
+func (p *ProjectCustomMetadataAPI) Prepare() {
+	p.BaseController.Prepare()
+	id, err := p.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		p.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", p.GetStringFromPath(":id")))
+		return
+	}
+	project, err := p.ProjectMgr.Get(id)
+	if err != nil {
+		p.ParseAndHandleError(fmt.Sprintf("failed to get project %d", id), err)
+		return
+	}
+	if project == nil {
+		p.HandleNotFound(fmt.Sprintf("project %d not found", id))
+		return
+	}
+
+	if p.Ctx.Request.Method == http.MethodGet {
+		if !project.IsPublic() && !p.SecurityCtx.IsAuthenticated() {
+			p.HandleUnauthorized()
+			return
+		}
+		if !p.SecurityCtx.HasReadPerm(project.ProjectID) {
+			p.HandleForbidden(p.SecurityCtx.GetUsername())
+			return
+		}
+	} else {
+		if !p.SecurityCtx.IsAuthenticated() {
+			p.HandleUnauthorized()
+			return
+		}
+		if !p.SecurityCtx.HasAllPerm(project.ProjectID) {
+			p.HandleForbidden(p.SecurityCtx.GetUsername())
+			return
+		}
+	}
+	p.project = project
+	p.name = p.GetStringFromPath(":name")
+}
+
+// List returns the custom metadata of the project as a map
+func (p *ProjectCustomMetadataAPI) List() {
+	metadata, err := getCustomMetadata(p.project.ProjectID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the custom metadata of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	p.Data["json"] = metadata[p.project.ProjectID]
+	p.ServeJSON()
+}
+
+// Get returns the custom metadata of the project
+func (p *ProjectCustomMetadataAPI) Get() {
+	metadata, err := dao.GetProjectCustomMetadata(p.project.ProjectID, p.name)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to get the custom metadata %s of project %d: %v", p.name, p.project.ProjectID, err))
+		return
+	}
+	if metadata == nil {
+		p.HandleNotFound(fmt.Sprintf("custom metadata %s of project %d not found", p.name, p.project.ProjectID))
+		return
+	}
+	p.Data["json"] = metadata
+	p.ServeJSON()
+}
+
+// Put creates the custom metadata of the project or updates its value
+func (p *ProjectCustomMetadataAPI) Put() {
+	metadata := &models.ProjectCustomMetadata{}
+	p.DecodeJSONReq(metadata)
+	metadata.Name = p.name
+	p.Validate(metadata)
+	if err := dao.SetProjectCustomMetadata(p.project.ProjectID, metadata.Name, metadata.Value); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to set the custom metadata %s of project %d: %v", p.name, p.project.ProjectID, err))
+		return
+	}
+}
+
+// Delete removes the custom metadata of the project
+func (p *ProjectCustomMetadataAPI) Delete() {
+	metadata, err := dao.GetProjectCustomMetadata(p.project.ProjectID, p.name)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to get the custom metadata %s of project %d: %v", p.name, p.project.ProjectID, err))
+		return
+	}
+	if metadata == nil {
+		p.HandleNotFound(fmt.Sprintf("custom metadata %s of project %d not found", p.name, p.project.ProjectID))
+		return
+	}
+	if err = dao.DeleteProjectCustomMetadata(p.project.ProjectID, p.name); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to delete the custom metadata %s of project %d: %v", p.name, p.project.ProjectID, err))
+		return
+	}
+}
+
+// getCustomMetadata returns the custom metadata of the projects keyed by the project IDs, every
+// project has a map even if it has no custom metadata
+func getCustomMetadata(projectIDs ...int64) (map[int64]map[string]string, error) {
+	list, err := dao.ListProjectCustomMetadata(projectIDs...)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[int64]map[string]string{}
+	for _, id := range projectIDs {
+		metadata[id] = map[string]string{}
+	}
+	for _, m := range list {
+		metadata[m.ProjectID][m.Name] = m.Value
+	}
+	return metadata, nil
+}
@@ -0,0 +1,151 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectCustomMetadataAPI(t *testing.T) {
+	path := "/api/projects/1/custom_metadata/team-owner"
+	cases := []*codeCheckingCase{
+		// 404, project not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/custom_metadata",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.ProjectCustomMetadata{
+					Value: "infra",
+				},
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403, the developers can't set the custom metadata
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        path,
+				credential: projDeveloper,
+				bodyJSON: &models.ProjectCustomMetadata{
+					Value: "infra",
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid name
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/projects/1/custom_metadata/Team_Owner",
+				credential: projAdmin,
+				bodyJSON: &models.ProjectCustomMetadata{
+					Value: "infra",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, custom metadata not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        path,
+				credential: projGuest,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &models.ProjectCustomMetadata{
+					Value: "infra",
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+	defer runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        path,
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+
+	metadata := &models.ProjectCustomMetadata{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: projGuest,
+	}, metadata))
+	assert.Equal(t, "infra", metadata.Value)
+
+	list := map[string]string{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/projects/1/custom_metadata",
+		credential: projGuest,
+	}, &list))
+	assert.Equal(t, map[string]string{"team-owner": "infra"}, list)
+
+	// list the projects filtered by the custom metadata with the details
+	projects := []*models.Project{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/projects",
+		credential: sysAdmin,
+		queryStruct: struct {
+			CustomMetadata string `url:"custom_metadata"`
+			WithDetail     bool   `url:"with_detail"`
+		}{
+			CustomMetadata: "team-owner=infra",
+			WithDetail:     true,
+		},
+	}, &projects))
+	require.Equal(t, 1, len(projects))
+	assert.Equal(t, int64(1), projects[0].ProjectID)
+	assert.Equal(t, map[string]string{"team-owner": "infra"}, projects[0].CustomMetadata)
+
+	projects = []*models.Project{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/projects",
+		credential: sysAdmin,
+		queryStruct: struct {
+			CustomMetadata string `url:"custom_metadata"`
+		}{
+			CustomMetadata: "team-owner=platform",
+		},
+	}, &projects))
+	assert.Equal(t, 0, len(projects))
+}
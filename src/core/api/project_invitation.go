@@ -0,0 +1,244 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// ProjectInvitationAPI handles request to /api/projects/{}/invitations, only the project admin
+// can generate, list and revoke the invitation links of the project
+type ProjectInvitationAPI struct {
+	BaseController
+	project    *models.Project
+	invitation *models.ProjectInvitation
+}
+
+// Prepare validates the user, the project and the invitation in the path
+func (p *ProjectInvitationAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	pid, err := p.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		p.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", p.GetStringFromPath(":pid")))
+		return
+	}
+	pro, err := p.ProjectMgr.Get(pid)
+	if err != nil {
+		p.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if pro == nil {
+		p.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	p.project = pro
+
+	if !p.SecurityCtx.HasAllPerm(pid) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(p.GetStringFromPath(":id")) > 0 {
+		id, err := p.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			p.HandleBadRequest(fmt.Sprintf("invalid invitation ID: %s", p.GetStringFromPath(":id")))
+			return
+		}
+		invitation, err := dao.GetProjectInvitation(id)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to get the invitation %d: %v", id, err))
+			return
+		}
+		if invitation == nil || invitation.ProjectID != pid {
+			p.HandleNotFound(fmt.Sprintf("invitation %d not found", id))
+			return
+		}
+		p.invitation = invitation
+	}
+}
+
+// Post generates an invitation link of the project
+func (p *ProjectInvitationAPI) Post() {
+	req := &models.ProjectInvitationReq{}
+	p.DecodeJSONReqAndValidate(req)
+	invitation := &models.ProjectInvitation{
+		ProjectID:      p.project.ProjectID,
+		Token:          utils.GenerateRandomString(),
+		Role:           req.Role,
+		ExpirationTime: req.ExpirationTime,
+		Creator:        p.SecurityCtx.GetUsername(),
+	}
+	id, err := dao.AddProjectInvitation(invitation)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to add the invitation: %v", err))
+		return
+	}
+	if err = setInvitationLinks(invitation); err != nil {
+		p.HandleInternalServerError(err.Error())
+		return
+	}
+	p.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+	p.Data["json"] = invitation
+	p.ServeJSON()
+}
+
+// List lists the outstanding invitations of the project
+func (p *ProjectInvitationAPI) List() {
+	invitations, err := dao.ListOutstandingProjectInvitations(p.project.ProjectID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the invitations: %v", err))
+		return
+	}
+	if err = setInvitationLinks(invitations...); err != nil {
+		p.HandleInternalServerError(err.Error())
+		return
+	}
+	p.Data["json"] = invitations
+	p.ServeJSON()
+}
+
+// Delete revokes the invitation, the users who have accepted it remain the members
+func (p *ProjectInvitationAPI) Delete() {
+	if err := dao.DeleteProjectInvitation(p.invitation.ID); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to delete the invitation %d: %v", p.invitation.ID, err))
+	}
+}
+
+// InvitationAPI handles request to /api/invitations/{token}, the authenticated users holding
+// the token of an outstanding invitation can view and accept it
+type InvitationAPI struct {
+	BaseController
+	invitation *models.ProjectInvitation
+	project    *models.Project
+}
+
+// Prepare validates the user and the invitation specified by the token
+func (i *InvitationAPI) Prepare() {
+	i.BaseController.Prepare()
+	if !i.SecurityCtx.IsAuthenticated() {
+		i.HandleUnauthorized()
+		return
+	}
+
+	token := i.GetStringFromPath(":token")
+	invitation, err := dao.GetProjectInvitationByToken(token)
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to get the invitation: %v", err))
+		return
+	}
+	// the expired invitations are treated as the revoked ones
+	if invitation == nil || invitation.IsExpired() {
+		i.HandleNotFound("invitation not found or expired")
+		return
+	}
+	pro, err := i.ProjectMgr.Get(invitation.ProjectID)
+	if err != nil {
+		i.ParseAndHandleError(fmt.Sprintf("failed to get project %d", invitation.ProjectID), err)
+		return
+	}
+	if pro == nil {
+		i.HandleNotFound(fmt.Sprintf("project %d not found", invitation.ProjectID))
+		return
+	}
+	invitation.ProjectName = pro.Name
+	i.invitation = invitation
+	i.project = pro
+}
+
+// Get returns the invitation, e.g. for the user to confirm the project and role before accepting it
+func (i *InvitationAPI) Get() {
+	i.Data["json"] = i.invitation
+	i.ServeJSON()
+}
+
+// Accept adds current user as the member of the project with the role of the invitation, the
+// expired membership of the user is renewed
+func (i *InvitationAPI) Accept() {
+	user, err := dao.GetUser(models.User{
+		Username: i.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", i.SecurityCtx.GetUsername(), err))
+		return
+	}
+	// the robot accounts and the solution users can't be members
+	if user == nil {
+		i.HandleForbidden(fmt.Sprintf("%s can not accept the invitation", i.SecurityCtx.GetUsername()))
+		return
+	}
+
+	members, err := project.GetProjectMember(models.Member{
+		ProjectID:  i.project.ProjectID,
+		EntityID:   user.UserID,
+		EntityType: common.UserMember,
+	})
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to get members of project %d: %v", i.project.ProjectID, err))
+		return
+	}
+	switch {
+	case len(members) > 0 && !members[0].IsExpired():
+		i.HandleConflict(fmt.Sprintf("user %s is already a member of project %s", user.Username, i.project.Name))
+		return
+	case len(members) > 0:
+		if err = project.UpdateProjectMemberRole(members[0].ID, i.invitation.Role); err == nil {
+			err = project.UpdateProjectMemberExpiration(members[0].ID, nil)
+		}
+	default:
+		_, err = AddProjectMember(i.project.ProjectID, models.MemberReq{
+			Role: i.invitation.Role,
+			MemberUser: models.User{
+				UserID: user.UserID,
+			},
+		})
+	}
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to add user %s to project %s: %v", user.Username, i.project.Name, err))
+		return
+	}
+
+	if err = dao.IncreaseProjectInvitationAcceptedCount(i.invitation.ID); err != nil {
+		log.Errorf("failed to increase the accepted count of invitation %d: %v", i.invitation.ID, err)
+	}
+	log.Infof("user %s joined project %s by the invitation %d", user.Username, i.project.Name, i.invitation.ID)
+}
+
+// setInvitationLinks sets the links of the invitations from the external endpoint
+func setInvitationLinks(invitations ...*models.ProjectInvitation) error {
+	endpoint, err := config.ExtEndpoint()
+	if err != nil {
+		return fmt.Errorf("failed to get the external endpoint: %v", err)
+	}
+	for _, invitation := range invitations {
+		invitation.Link = fmt.Sprintf("%s/api/invitations/%s", strings.TrimSuffix(endpoint, "/"), invitation.Token)
+	}
+	return nil
+}
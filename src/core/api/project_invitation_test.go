@@ -0,0 +1,181 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var projectInvitationPath = "/api/projects/1/invitations"
+
+func TestProjectInvitationAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    projectInvitationPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403, only project admin can generate invitations
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectInvitationPath,
+				bodyJSON: &models.ProjectInvitationReq{
+					Role:           common.RoleDeveloper,
+					ExpirationTime: time.Now().Add(time.Hour),
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid role
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectInvitationPath,
+				bodyJSON: &models.ProjectInvitationReq{
+					Role:           10,
+					ExpirationTime: time.Now().Add(time.Hour),
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, expired
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectInvitationPath,
+				bodyJSON: &models.ProjectInvitationReq{
+					Role:           common.RoleDeveloper,
+					ExpirationTime: time.Now().Add(-time.Hour),
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, invalid token
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/invitations/non-existing",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	invitation := &models.ProjectInvitation{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    projectInvitationPath,
+		bodyJSON: &models.ProjectInvitationReq{
+			Role:           common.RoleDeveloper,
+			ExpirationTime: time.Now().Add(time.Hour),
+		},
+		credential: projAdmin,
+	}, invitation)
+	require.Nil(t, err)
+	require.NotEqual(t, 0, invitation.ID)
+	defer dao.DeleteProjectInvitation(invitation.ID)
+	assert.Equal(t, projAdmin.Name, invitation.Creator)
+	assert.NotEmpty(t, invitation.Token)
+	assert.NotEmpty(t, invitation.Link)
+
+	invitations := []*models.ProjectInvitation{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        projectInvitationPath,
+		credential: projAdmin,
+	}, &invitations)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(invitations))
+	assert.Equal(t, invitation.Token, invitations[0].Token)
+
+	// the invited user views and accepts the invitation
+	invitationPath := "/api/invitations/" + invitation.Token
+	viewed := &models.ProjectInvitation{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        invitationPath,
+		credential: nonSysAdmin,
+	}, viewed)
+	require.Nil(t, err)
+	assert.Equal(t, "library", viewed.ProjectName)
+	assert.Equal(t, common.RoleDeveloper, viewed.Role)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPost,
+			url:        invitationPath + "/accept",
+			credential: nonSysAdmin,
+		},
+		code: http.StatusOK,
+	})
+	members, err := project.GetProjectMember(models.Member{
+		ProjectID:  1,
+		EntityID:   int(nonSysAdminID),
+		EntityType: common.UserMember,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(members))
+	defer project.DeleteProjectMemberByID(members[0].ID)
+	assert.Equal(t, common.RoleDeveloper, members[0].Role)
+
+	cases = []*codeCheckingCase{
+		// 409, already a member
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        invitationPath + "/accept",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+		// 200, revoke
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", projectInvitationPath, invitation.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404, revoked
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        invitationPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,217 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/projectmerge"
+)
+
+// ProjectMergeAPI handles request to /api/system/project_merges
+type ProjectMergeAPI struct {
+	BaseController
+	merge *models.ProjectMerge
+}
+
+// Prepare validates the user, only the system admin is allowed to merge projects
+func (p *ProjectMergeAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+	if !p.SecurityCtx.IsSysAdmin() {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(p.GetStringFromPath(":id")) > 0 {
+		id, err := p.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			p.HandleBadRequest(fmt.Sprintf("invalid merge ID: %s", p.GetStringFromPath(":id")))
+			return
+		}
+		merge, err := dao.GetProjectMerge(id)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to get the project merge %d: %v", id, err))
+			return
+		}
+		if merge == nil {
+			p.HandleNotFound(fmt.Sprintf("project merge %d not found", id))
+			return
+		}
+		p.merge = merge
+	}
+}
+
+// Post starts to merge the source project into the target one in background
+func (p *ProjectMergeAPI) Post() {
+	req := &models.ProjectMergeRequest{}
+	p.DecodeJSONReq(req)
+	if req.SourceProjectID <= 0 || req.TargetProjectID <= 0 {
+		p.HandleBadRequest("source_project_id and target_project_id are required")
+		return
+	}
+	if req.SourceProjectID == req.TargetProjectID {
+		p.HandleBadRequest("the source and target projects must be different")
+		return
+	}
+	if len(req.MemberRule) == 0 {
+		req.MemberRule = models.MemberRuleHigherRole
+	}
+	if req.MemberRule != models.MemberRuleHigherRole && req.MemberRule != models.MemberRuleKeepTarget {
+		p.HandleBadRequest(fmt.Sprintf("invalid member_rule: %s", req.MemberRule))
+		return
+	}
+
+	source, ok := p.getProject(req.SourceProjectID)
+	if !ok {
+		return
+	}
+	target, ok := p.getProject(req.TargetProjectID)
+	if !ok {
+		return
+	}
+	message, err := p.mergeable(source)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to check whether project %s can be merged: %v", source.Name, err))
+		return
+	}
+	if len(message) > 0 {
+		p.HandleStatusPreconditionFailed(message)
+		return
+	}
+
+	if !p.Confirmed() {
+		return
+	}
+
+	id, err := projectmerge.Start(source, target, req.MemberRule, p.SecurityCtx.GetUsername())
+	if err != nil {
+		if _, ok := err.(*projectmerge.ConflictError); ok || err == projectmerge.ErrMergeRunning {
+			p.HandleConflict(err.Error())
+			return
+		}
+		p.HandleInternalServerError(fmt.Sprintf("failed to merge project %s into %s: %v", source.Name, target.Name, err))
+		return
+	}
+	p.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// getProject returns the project specified by ID, false is returned if the request has been handled
+func (p *ProjectMergeAPI) getProject(id int64) (*models.Project, bool) {
+	project, err := p.ProjectMgr.Get(id)
+	if err != nil {
+		p.ParseAndHandleError(fmt.Sprintf("failed to get project %d", id), err)
+		return nil, false
+	}
+	if project == nil {
+		p.HandleNotFound(fmt.Sprintf("project %d not found", id))
+		return nil, false
+	}
+	return project, true
+}
+
+// mergeable returns the reason why the project can't be merged into another one, the replication
+// rules and helm charts aren't moved, and the signatures are bound to the repository names
+func (p *ProjectMergeAPI) mergeable(project *models.Project) (string, error) {
+	policies, err := dao.GetRepPolicyByProject(project.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	if len(policies) > 0 {
+		return "the project contains replication rules, can not be merged", nil
+	}
+
+	holds, err := dao.CountLegalHolds(&models.LegalHoldQuery{
+		Project: project.Name,
+		Active:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if holds > 0 {
+		return "the project contains repositories under legal hold, can not be merged", nil
+	}
+
+	if config.WithChartMuseum() {
+		charts, err := chartController.ListCharts(project.Name)
+		if err != nil {
+			return "", err
+		}
+		if len(charts) > 0 {
+			return "the project contains helm charts, can not be merged", nil
+		}
+	}
+
+	if config.WithNotary() {
+		repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+			ProjectIDs: []int64{project.ProjectID},
+		})
+		if err != nil {
+			return "", err
+		}
+		for _, repository := range repositories {
+			signatures, err := getSignatures(p.SecurityCtx.GetUsername(), repository.Name)
+			if err != nil {
+				return "", err
+			}
+			if len(signatures) > 0 {
+				return fmt.Sprintf("repository %s contains signed images, can not be merged", repository.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// List lists the project merges
+func (p *ProjectMergeAPI) List() {
+	projectID, err := p.GetInt64("project_id", 0)
+	if err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid project_id: %s", p.GetString("project_id")))
+		return
+	}
+	query := &models.ProjectMergeQuery{
+		ProjectID: projectID,
+		Status:    p.GetString("status"),
+	}
+	total, err := dao.CountProjectMerges(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to count the project merges: %v", err))
+		return
+	}
+	query.Page, query.Size = p.GetPaginationParams()
+	merges, err := dao.ListProjectMerges(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the project merges: %v", err))
+		return
+	}
+
+	p.SetPaginationHeader(total, query.Page, query.Size)
+	p.Data["json"] = merges
+	p.ServeJSON()
+}
+
+// Get gets the project merge specified by ID
+func (p *ProjectMergeAPI) Get() {
+	p.Data["json"] = p.merge
+	p.ServeJSON()
+}
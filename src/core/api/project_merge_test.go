@@ -0,0 +1,151 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+var projectMergeAPIBasePath = "/api/system/project_merges"
+
+func TestProjectMergeAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectMergeAPIBasePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        projectMergeAPIBasePath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no projects
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        projectMergeAPIBasePath,
+				bodyJSON:   &models.ProjectMergeRequest{},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, same projects
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectMergeAPIBasePath,
+				bodyJSON: &models.ProjectMergeRequest{
+					SourceProjectID: 1,
+					TargetProjectID: 1,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid member rule
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectMergeAPIBasePath,
+				bodyJSON: &models.ProjectMergeRequest{
+					SourceProjectID: 1,
+					TargetProjectID: 1000,
+					MemberRule:      "invalid",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, source project not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectMergeAPIBasePath,
+				bodyJSON: &models.ProjectMergeRequest{
+					SourceProjectID: 1000,
+					TargetProjectID: 1,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, target project not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectMergeAPIBasePath,
+				bodyJSON: &models.ProjectMergeRequest{
+					SourceProjectID: 1,
+					TargetProjectID: 1000,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, list
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        projectMergeAPIBasePath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200, list
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        projectMergeAPIBasePath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 400, invalid project ID
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    projectMergeAPIBasePath,
+				queryStruct: struct {
+					ProjectID string `url:"project_id"`
+				}{
+					ProjectID: "invalid",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, get
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        projectMergeAPIBasePath + "/1000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/promgr"
+)
+
+// the max count of the projects whose metrics are exported, it bounds the cardinality
+// of the metrics as the projects are labeled
+const maxMetricsProjects = 200
+
+var projectLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeProjectMetrics writes the quota and usage of the projects which opt in as the
+// metrics in the text format of Prometheus
+func writeProjectMetrics(pm promgr.ProjectManager, w io.Writer) error {
+	metas, err := dao.ListProjectMetadata(models.ProMetaExportMetrics, "true")
+	if err != nil {
+		return err
+	}
+	sort.Slice(metas, func(i, j int) bool {
+		return metas[i].ProjectID < metas[j].ProjectID
+	})
+	if len(metas) > maxMetricsProjects {
+		log.Warningf("%d projects export the metrics, only the first %d are exported", len(metas), maxMetricsProjects)
+		metas = metas[:maxMetricsProjects]
+	}
+	usages := []*models.ProjectUsage{}
+	for _, meta := range metas {
+		project, err := pm.Get(meta.ProjectID)
+		if err != nil {
+			return err
+		}
+		if project == nil {
+			continue
+		}
+		usage, err := getProjectUsage(project)
+		if err != nil {
+			return err
+		}
+		usages = append(usages, usage)
+	}
+	return writeProjectUsageMetrics(w, usages)
+}
+
+func writeProjectUsageMetrics(w io.Writer, usages []*models.ProjectUsage) error {
+	metrics := []struct {
+		name  string
+		help  string
+		kind  string
+		value func(u *models.ProjectUsage) int64
+	}{
+		{"harbor_project_quota_bytes", "The storage quota of the project, -1 means unlimited.", "gauge",
+			func(u *models.ProjectUsage) int64 { return u.StorageQuota }},
+		{"harbor_project_usage_bytes", "The storage usage of the project.", "gauge",
+			func(u *models.ProjectUsage) int64 { return u.StorageUsage }},
+		{"harbor_project_repositories", "The count of the repositories of the project.", "gauge",
+			func(u *models.ProjectUsage) int64 { return u.RepoCount }},
+		{"harbor_project_tags", "The count of the tags of the project.", "gauge",
+			func(u *models.ProjectUsage) int64 { return u.TagCount }},
+		{"harbor_project_pulls_total", "The count of the pulls of the repositories of the project.", "counter",
+			func(u *models.ProjectUsage) int64 { return u.PullCount }},
+	}
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind); err != nil {
+			return err
+		}
+		for _, usage := range usages {
+			if _, err := fmt.Fprintf(w, "%s{project=\"%s\",project_id=\"%d\"} %d\n", metric.name,
+				projectLabelReplacer.Replace(usage.ProjectName), usage.ProjectID,
+				metric.value(usage)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
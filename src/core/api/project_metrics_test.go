@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteProjectUsageMetrics(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := writeProjectUsageMetrics(buf, []*models.ProjectUsage{
+		{
+			ProjectID:    1,
+			ProjectName:  "library",
+			StorageUsage: 1024,
+			StorageQuota: 4096,
+			RepoCount:    2,
+			TagCount:     3,
+			PullCount:    10,
+		},
+	})
+	require.Nil(t, err)
+	metrics := buf.String()
+	assert.Contains(t, metrics, "# TYPE harbor_project_quota_bytes gauge\n")
+	assert.Contains(t, metrics, "harbor_project_quota_bytes{project=\"library\",project_id=\"1\"} 4096\n")
+	assert.Contains(t, metrics, "harbor_project_usage_bytes{project=\"library\",project_id=\"1\"} 1024\n")
+	assert.Contains(t, metrics, "harbor_project_tags{project=\"library\",project_id=\"1\"} 3\n")
+	assert.Contains(t, metrics, "# TYPE harbor_project_pulls_total counter\n")
+	assert.Contains(t, metrics, "harbor_project_pulls_total{project=\"library\",project_id=\"1\"} 10\n")
+}
@@ -0,0 +1,188 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// ProjectReportSubscriptionAPI handles the requests on /api/projects/{}/report_subscriptions to
+// manage the subscriptions of the members to the scheduled report emails of the project. The
+// members manage their own subscriptions, and the project admins manage the ones of all members
+type ProjectReportSubscriptionAPI struct {
+	BaseController
+	project *models.Project
+	userID  int
+	isAdmin bool
+}
+
+type reportSubscriptionReq struct {
+	// the current user is subscribed if it is 0
+	UserID int `json:"user_id"`
+}
+
+// Prepare ...
+func (p *ProjectReportSubscriptionAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+	id, err := p.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		p.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", p.GetStringFromPath(":id")))
+		return
+	}
+	pro, err := p.ProjectMgr.Get(id)
+	if err != nil {
+		p.ParseAndHandleError(fmt.Sprintf("failed to get project %d", id), err)
+		return
+	}
+	if pro == nil {
+		p.HandleNotFound(fmt.Sprintf("project %d not found", id))
+		return
+	}
+	if !p.SecurityCtx.HasReadPerm(pro.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	user, err := dao.GetUser(models.User{
+		Username: p.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", p.SecurityCtx.GetUsername(), err))
+		return
+	}
+	// the robot accounts can't receive the emails
+	if user == nil {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	p.project = pro
+	p.userID = user.UserID
+	p.isAdmin = p.SecurityCtx.HasAllPerm(pro.ProjectID)
+}
+
+// List returns the subscriptions of the project, the members except the project admins only
+// get their own subscriptions
+func (p *ProjectReportSubscriptionAPI) List() {
+	subscriptions, err := dao.ListProjectReportSubscriptions(p.project.ProjectID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the report subscriptions of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	if !p.isAdmin {
+		own := []*models.ProjectReportSubscription{}
+		for _, subscription := range subscriptions {
+			if subscription.UserID == p.userID {
+				own = append(own, subscription)
+			}
+		}
+		subscriptions = own
+	}
+	p.Data["json"] = subscriptions
+	p.ServeJSON()
+}
+
+// Post subscribes the member to the reports of the project, only the project admins can
+// subscribe the other members
+func (p *ProjectReportSubscriptionAPI) Post() {
+	req := &reportSubscriptionReq{}
+	if p.Ctx.Request.ContentLength > 0 {
+		p.DecodeJSONReq(req)
+	}
+	if req.UserID == 0 {
+		req.UserID = p.userID
+	}
+	if req.UserID != p.userID && !p.isAdmin {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	member, err := p.isDirectMember(req.UserID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to get the members of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	// the reports are only sent to the users who are the members of the project themselves
+	if !member {
+		p.HandleBadRequest(fmt.Sprintf("user %d is not a member of project %s", req.UserID, p.project.Name))
+		return
+	}
+	if err = dao.AddProjectReportSubscription(p.project.ProjectID, req.UserID); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to subscribe user %d to the reports of project %d: %v", req.UserID, p.project.ProjectID, err))
+		return
+	}
+	p.Ctx.ResponseWriter.WriteHeader(http.StatusCreated)
+}
+
+// Delete unsubscribes the member from the reports of the project, only the project admins can
+// unsubscribe the other members
+func (p *ProjectReportSubscriptionAPI) Delete() {
+	uid, err := p.GetInt64FromPath(":uid")
+	if err != nil || uid <= 0 {
+		p.HandleBadRequest(fmt.Sprintf("invalid user ID: %s", p.GetStringFromPath(":uid")))
+		return
+	}
+	userID := int(uid)
+	if userID != p.userID && !p.isAdmin {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	subscriptions, err := dao.ListProjectReportSubscriptions(p.project.ProjectID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the report subscriptions of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+	found := false
+	for _, subscription := range subscriptions {
+		if subscription.UserID == userID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		p.HandleNotFound(fmt.Sprintf("user %d doesn't subscribe to the reports of project %d", userID, p.project.ProjectID))
+		return
+	}
+	if err = dao.DeleteProjectReportSubscription(p.project.ProjectID, userID); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to unsubscribe user %d from the reports of project %d: %v", userID, p.project.ProjectID, err))
+		return
+	}
+}
+
+// isDirectMember returns whether the user is an unexpired member of the project rather than the
+// member of a group of the project
+func (p *ProjectReportSubscriptionAPI) isDirectMember(userID int) (bool, error) {
+	members, err := project.GetProjectMember(models.Member{
+		ProjectID:  p.project.ProjectID,
+		EntityID:   userID,
+		EntityType: common.UserMember,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if !member.IsExpired() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,148 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectReportSubscriptionAPI(t *testing.T) {
+	path := "/api/projects/1/report_subscriptions"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, project not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/report_subscriptions",
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, not a member
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 403, the developers can't subscribe the other members
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projDeveloper,
+				bodyJSON: &reportSubscriptionReq{
+					UserID: int(projGuestID),
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 201, the developer subscribes
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projDeveloper,
+			},
+			code: http.StatusCreated,
+		},
+		// 201, the project admin subscribes the guest
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &reportSubscriptionReq{
+					UserID: int(projGuestID),
+				},
+			},
+			code: http.StatusCreated,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the project admin gets all subscriptions while the others only get their own
+	subscriptions := []*models.ProjectReportSubscription{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: projAdmin,
+	}, &subscriptions))
+	assert.Equal(t, 2, len(subscriptions))
+	subscriptions = []*models.ProjectReportSubscription{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: projDeveloper,
+	}, &subscriptions))
+	require.Equal(t, 1, len(subscriptions))
+	assert.Equal(t, int(projDeveloperID), subscriptions[0].UserID)
+
+	cases = []*codeCheckingCase{
+		// 403, the developers can't unsubscribe the other members
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", path, projGuestID),
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", path, projDeveloperID),
+				credential: projDeveloper,
+			},
+			code: http.StatusOK,
+		},
+		// 404, not subscribed
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", path, projDeveloperID),
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", path, projGuestID),
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
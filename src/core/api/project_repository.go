@@ -0,0 +1,106 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// ProjectRepositoryAPI handles request to /api/projects/:pid/repositories
+type ProjectRepositoryAPI struct {
+	BaseController
+	project *models.Project
+}
+
+// Prepare validates the user, the project admin permission is needed as the access
+// control list of the repository grants access to other users
+func (p *ProjectRepositoryAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	pid, err := p.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		p.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", p.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := p.ProjectMgr.Get(pid)
+	if err != nil {
+		p.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		p.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	if !p.SecurityCtx.HasAllPerm(pid) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	p.project = project
+}
+
+// Post creates an empty repository with the description and access control list before the
+// first push, the users in the list are granted the access in addition to the one granted
+// by the project
+func (p *ProjectRepositoryAPI) Post() {
+	req := &models.RepoCreateReq{}
+	p.DecodeJSONReqAndValidate(req)
+	if !utils.ValidateRepo(req.Name) {
+		p.HandleBadRequest(fmt.Sprintf("invalid repository name: %s", req.Name))
+		return
+	}
+	name := p.project.Name + "/" + req.Name
+
+	acls := []*models.RepoACL{}
+	for _, acl := range req.ACLs {
+		user, err := dao.GetUser(models.User{
+			Username: acl.Username,
+		})
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", acl.Username, err))
+			return
+		}
+		if user == nil {
+			p.HandleBadRequest(fmt.Sprintf("user %s not found", acl.Username))
+			return
+		}
+		acls = append(acls, &models.RepoACL{
+			UserID: user.UserID,
+			Access: acl.Access,
+		})
+	}
+
+	if _, err := dao.AddPreCreatedRepository(&models.RepoRecord{
+		Name:        name,
+		ProjectID:   p.project.ProjectID,
+		Description: req.Description,
+	}, acls); err != nil {
+		if err == dao.ErrDupRows {
+			p.HandleConflict(fmt.Sprintf("repository %s already exists", name))
+			return
+		}
+		p.HandleInternalServerError(fmt.Sprintf("failed to create repository %s: %v", name, err))
+		return
+	}
+	p.Ctx.Redirect(http.StatusCreated, "/api/repositories/"+name)
+}
@@ -0,0 +1,154 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var projectRepositoryPath = "/api/projects/1/repositories"
+
+func TestProjectRepositoryAPI(t *testing.T) {
+	repoName := "library/pre-created"
+	defer dao.DeleteRepository(repoName)
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/projects/10000/repositories",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only project admin can create repositories
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+				bodyJSON: &models.RepoCreateReq{
+					Name: "pre-created",
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid name
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+				bodyJSON: &models.RepoCreateReq{
+					Name: "Pre-Created",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid access
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+				bodyJSON: &models.RepoCreateReq{
+					Name: "pre-created",
+					ACLs: []*models.RepoACLReq{
+						{
+							Username: nonSysAdmin.Name,
+							Access:   "delete",
+						},
+					},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, user not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+				bodyJSON: &models.RepoCreateReq{
+					Name: "pre-created",
+					ACLs: []*models.RepoACLReq{
+						{
+							Username: "non-exist",
+							Access:   models.RepoAccessPull,
+						},
+					},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+				bodyJSON: &models.RepoCreateReq{
+					Name:        "pre-created",
+					Description: "created before the first push",
+					ACLs: []*models.RepoACLReq{
+						{
+							Username: nonSysAdmin.Name,
+							Access:   models.RepoAccessPush,
+						},
+					},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    projectRepositoryPath,
+				bodyJSON: &models.RepoCreateReq{
+					Name: "pre-created",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	repo, err := dao.GetRepositoryByName(repoName)
+	require.Nil(t, err)
+	require.NotNil(t, repo)
+	assert.True(t, repo.PreCreated)
+	assert.Equal(t, "created before the first push", repo.Description)
+
+	access, err := dao.GetRepoAccess(repoName, nonSysAdmin.Name)
+	require.Nil(t, err)
+	assert.Equal(t, models.RepoAccessPush, access)
+}
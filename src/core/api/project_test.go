@@ -22,6 +22,7 @@ import (
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/projectconfig"
 	"github.com/goharbor/harbor/tests/apitests/apilib"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -214,6 +215,68 @@ func TestProGetByID(t *testing.T) {
 	}
 	fmt.Printf("\n")
 }
+func TestProGetByName(t *testing.T) {
+	cMockServer, oldCtrl, err := mockChartController()
+	require.Nil(t, err)
+	defer func() {
+		cMockServer.Close()
+		chartController = oldCtrl
+	}()
+
+	cases := []*codeCheckingCase{
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/by_name/not_exist_project",
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	project := &models.Project{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/projects/by_name/library",
+	}, project)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), project.ProjectID)
+
+	projects := []*models.Project{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/projects/",
+		queryStruct: struct {
+			Name       string `url:"name"`
+			ExactMatch bool   `url:"exact_match"`
+		}{
+			Name:       "librar",
+			ExactMatch: true,
+		},
+		credential: admin,
+	}, &projects)
+	require.Nil(t, err)
+	assert.Len(t, projects, 0)
+
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/projects/",
+		queryStruct: struct {
+			Name       string `url:"name"`
+			ExactMatch bool   `url:"exact_match"`
+		}{
+			Name:       "library",
+			ExactMatch: true,
+		},
+		credential: admin,
+	}, &projects)
+	require.Nil(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "library", projects[0].Name)
+}
+
 func TestDeleteProject(t *testing.T) {
 
 	fmt.Println("\nTesting Delete Project(ProjectsPost) API")
@@ -423,3 +486,256 @@ func TestDeletable(t *testing.T) {
 	assert.Equal(t, http.StatusOK, code)
 	assert.False(t, del)
 }
+
+func TestMovedTags(t *testing.T) {
+	repository := "library/moved-tags-api-test"
+	now := time.Now()
+	for _, h := range []*models.TagHistory{
+		{Tag: "v1", Digest: "sha256:1", Operation: models.TagHistoryCreate, OpTime: now.Add(-3 * time.Hour)},
+		{Tag: "v1", Digest: "sha256:2", Operation: models.TagHistoryUpdate, OpTime: now.Add(-2 * time.Hour)},
+		{Tag: "v1", Digest: "sha256:3", Operation: models.TagHistoryUpdate, OpTime: now.Add(-time.Hour)},
+		{Tag: "v2", Digest: "sha256:1", Operation: models.TagHistoryCreate, OpTime: now.Add(-time.Hour)},
+	} {
+		h.Repository = repository
+		h.Operator = "admin"
+		_, err := dao.AddTagHistory(h)
+		require.Nil(t, err)
+	}
+	defer dao.GetOrmer().QueryTable(&models.TagHistory{}).Filter("Repository", repository).Delete()
+
+	path := "/api/projects/1/moved_tags"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+				queryStruct: struct {
+					BeginTimestamp string `url:"begin_timestamp"`
+				}{
+					BeginTimestamp: "yesterday",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	tags := []*models.MovedTag{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: admin,
+	}, &tags)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tags))
+	assert.Equal(t, "v1", tags[0].Tag)
+	assert.Equal(t, int64(2), tags[0].Updates)
+	assert.Equal(t, "sha256:3", tags[0].Digest)
+	assert.Equal(t, []string{"admin"}, tags[0].Operators)
+
+	// only the update in the period is counted
+	tags = []*models.MovedTag{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    path,
+		queryStruct: struct {
+			EndTimestamp int64 `url:"end_timestamp"`
+		}{
+			EndTimestamp: now.Add(-90 * time.Minute).Unix(),
+		},
+		credential: admin,
+	}, &tags)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tags))
+	assert.Equal(t, int64(1), tags[0].Updates)
+	assert.Equal(t, "sha256:2", tags[0].Digest)
+}
+
+func TestVulnerabilityTrend(t *testing.T) {
+	now := time.Now()
+	for _, trend := range []*models.VulnerabilityTrend{
+		{ProjectID: 1, Day: now.AddDate(0, 0, -200), Images: 2, Scanned: 2, High: 5},
+		{ProjectID: 1, Day: now.AddDate(0, 0, -1), Images: 2, Scanned: 2, High: 1},
+	} {
+		require.Nil(t, dao.SetVulnerabilityTrend(trend))
+	}
+	defer dao.GetOrmer().Raw(`delete from vulnerability_trend where project_id = 1`).Exec()
+
+	path := "/api/projects/1/vulnerability_trend"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+				queryStruct: struct {
+					EndTimestamp string `url:"end_timestamp"`
+				}{
+					EndTimestamp: "today",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the last 90 days by default
+	trends := []*models.VulnerabilityTrend{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: admin,
+	}, &trends)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(trends))
+	assert.Equal(t, 1, trends[0].High)
+
+	trends = []*models.VulnerabilityTrend{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    path,
+		queryStruct: struct {
+			BeginTimestamp int64 `url:"begin_timestamp"`
+		}{
+			BeginTimestamp: now.AddDate(-1, 0, 0).Unix(),
+		},
+		credential: admin,
+	}, &trends)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(trends))
+	assert.Equal(t, 5, trends[0].High)
+}
+
+func TestProjectConfig(t *testing.T) {
+	path := "/api/projects/1/configuration"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        path,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid format
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+				queryStruct: struct {
+					Format string `url:"format"`
+				}{
+					Format: "xml",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the document is for another project
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &projectconfig.Config{
+					APIVersion: projectconfig.APIVersion,
+					Kind:       projectconfig.Kind,
+					Project:    "another",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200, yaml
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        path,
+				credential: admin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	cfg := &projectconfig.Config{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    path,
+		queryStruct: struct {
+			Format string `url:"format"`
+		}{
+			Format: "json",
+		},
+		credential: admin,
+	}, cfg)
+	require.Nil(t, err)
+	assert.Equal(t, "library", cfg.Project)
+	assert.Equal(t, "true", cfg.Metadata[models.ProMetaPublic])
+
+	// dry run
+	cfg.Metadata[models.ProMetaAutoScan] = "true"
+	cfg.Members = nil
+	cfg.Robots = nil
+	cfg.Retention = nil
+	result := &projectconfig.Result{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodPut,
+		url:    path,
+		queryStruct: struct {
+			DryRun bool `url:"dry_run"`
+		}{
+			DryRun: true,
+		},
+		bodyJSON:   cfg,
+		credential: admin,
+	}, result)
+	require.Nil(t, err)
+	assert.True(t, result.DryRun)
+	require.Equal(t, 1, len(result.Changes))
+	assert.Equal(t, projectconfig.SectionMetadata, result.Changes[0].Section)
+	assert.Equal(t, models.ProMetaAutoScan, result.Changes[0].Name)
+
+	// apply
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodPut,
+		url:        path,
+		bodyJSON:   cfg,
+		credential: admin,
+	}, result)
+	require.Nil(t, err)
+	assert.False(t, result.DryRun)
+	defer dao.DeleteProjectMetadata(1, models.ProMetaAutoScan)
+
+	metas, err := dao.GetProjectMetadata(1, models.ProMetaAutoScan)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, "true", metas[0].Value)
+}
@@ -20,12 +20,15 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/dao/project"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/auth"
+	"github.com/goharbor/harbor/src/core/notifier"
 )
 
 // ProjectMemberAPI handles request to /api/projects/{}/members/{}
@@ -43,6 +46,15 @@ var ErrDuplicateProjectMember = errors.New("The project member specified already
 // ErrInvalidRole ...
 var ErrInvalidRole = errors.New("Failed to update project member, role is not in 1,2,3")
 
+// ErrInvalidExpiration ...
+var ErrInvalidExpiration = errors.New("The expiration time of project member should be in the future")
+
+// ErrConflictExpiration ...
+var ErrConflictExpiration = errors.New("The expiration time of project member can't be specified with never_expire")
+
+// the max count of the entries of a batch membership request
+const maxMemberBatchSize = 500
+
 // Prepare validates the URL and parms
 func (pma *ProjectMemberAPI) Prepare() {
 	pma.BaseController.Prepare()
@@ -144,6 +156,9 @@ func (pma *ProjectMemberAPI) Post() {
 	} else if err == ErrInvalidRole {
 		pma.HandleBadRequest(fmt.Sprintf("Invalid role ID, role ID %v", request.Role))
 		return
+	} else if err == ErrInvalidExpiration {
+		pma.HandleBadRequest(fmt.Sprintf("Invalid expiration time: %v", request.ExpirationTime))
+		return
 	} else if err == auth.ErrInvalidLDAPGroupDN {
 		pma.HandleBadRequest(fmt.Sprintf("Invalid LDAP DN: %v", request.MemberGroup.LdapGroupDN))
 		return
@@ -158,17 +173,25 @@ func (pma *ProjectMemberAPI) Post() {
 func (pma *ProjectMemberAPI) Put() {
 	pid := pma.project.ProjectID
 	pmID := pma.id
-	var req models.Member
+	var req models.MemberReq
 	pma.DecodeJSONReq(&req)
 	if req.Role < 1 || req.Role > 4 {
 		pma.HandleBadRequest(fmt.Sprintf("Invalid role id %v", req.Role))
 		return
 	}
+	if err := checkExpiration(&req); err != nil {
+		pma.HandleBadRequest(fmt.Sprintf("Invalid expiration time: %v", err))
+		return
+	}
 	err := project.UpdateProjectMemberRole(pmID, req.Role)
 	if err != nil {
 		pma.HandleInternalServerError(fmt.Sprintf("Failed to update DB to add project user role, project id: %d, pmid : %d, role id: %d", pid, pmID, req.Role))
 		return
 	}
+	if err = updateExpiration(pmID, &req); err != nil {
+		pma.HandleInternalServerError(fmt.Sprintf("Failed to update the expiration time of project member, project id: %d, pmid : %d, error: %v", pid, pmID, err))
+		return
+	}
 }
 
 // Delete ...
@@ -181,11 +204,163 @@ func (pma *ProjectMemberAPI) Delete() {
 	}
 }
 
+// Batch adds, updates or removes the members in the request one by one, the failure of
+// an entry doesn't stop the others and is reported in the result of the entry
+func (pma *ProjectMemberAPI) Batch() {
+	var req models.MemberBatchReq
+	pma.DecodeJSONReq(&req)
+	if len(req.Members) == 0 {
+		pma.HandleBadRequest("no member specified")
+		return
+	}
+	if len(req.Members) > maxMemberBatchSize {
+		pma.HandleBadRequest(fmt.Sprintf("at most %d members can be specified in a batch", maxMemberBatchSize))
+		return
+	}
+
+	results := []*models.MemberBatchResult{}
+	for _, entry := range req.Members {
+		if entry == nil {
+			entry = &models.MemberBatchEntry{}
+		}
+		id, code, err := pma.applyMemberEntry(entry)
+		result := &models.MemberBatchResult{
+			Action:     entry.Action,
+			ID:         id,
+			StatusCode: code,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	pma.Data["json"] = results
+	pma.ServeJSON()
+}
+
+// applyMemberEntry applies the entry of the batch request, it returns the ID of the
+// membership and the status code the single member API responds with
+func (pma *ProjectMemberAPI) applyMemberEntry(entry *models.MemberBatchEntry) (int, int, error) {
+	projectID := pma.project.ProjectID
+	if entry.Action == models.MemberBatchActionAdd {
+		entry.MemberGroup.LdapGroupDN = strings.TrimSpace(entry.MemberGroup.LdapGroupDN)
+		id, err := AddProjectMember(projectID, entry.MemberReq)
+		switch err {
+		case nil:
+			return id, http.StatusCreated, nil
+		case auth.ErrorGroupNotExist, auth.ErrorUserNotExist:
+			return 0, http.StatusNotFound, err
+		case auth.ErrDuplicateLDAPGroup, ErrDuplicateProjectMember:
+			return 0, http.StatusConflict, err
+		case ErrInvalidRole, ErrInvalidExpiration, auth.ErrInvalidLDAPGroupDN:
+			return 0, http.StatusBadRequest, err
+		default:
+			log.Errorf("failed to add the member %+v to project %d: %v", entry.MemberReq, projectID, err)
+			return 0, http.StatusInternalServerError, err
+		}
+	}
+	if entry.Action != models.MemberBatchActionUpdate && entry.Action != models.MemberBatchActionRemove {
+		return 0, http.StatusBadRequest, fmt.Errorf("invalid action %q", entry.Action)
+	}
+
+	query, ok := memberQuery(projectID, entry)
+	if !ok {
+		return 0, http.StatusBadRequest, errors.New("the ID of the membership, the user or the group is required")
+	}
+	members, err := project.GetProjectMember(query)
+	if err != nil {
+		log.Errorf("failed to get the member %+v of project %d: %v", query, projectID, err)
+		return 0, http.StatusInternalServerError, err
+	}
+	if len(members) == 0 {
+		return 0, http.StatusNotFound, errors.New("the project member does not exist")
+	}
+	id := members[0].ID
+
+	if entry.Action == models.MemberBatchActionRemove {
+		if err = project.DeleteProjectMemberByID(id); err != nil {
+			log.Errorf("failed to delete the project member %d: %v", id, err)
+			return id, http.StatusInternalServerError, err
+		}
+		return id, http.StatusOK, nil
+	}
+	if entry.Role < 1 || entry.Role > 4 {
+		return id, http.StatusBadRequest, ErrInvalidRole
+	}
+	if err = checkExpiration(&entry.MemberReq); err != nil {
+		return id, http.StatusBadRequest, err
+	}
+	if err = project.UpdateProjectMemberRole(id, entry.Role); err == nil {
+		err = updateExpiration(id, &entry.MemberReq)
+	}
+	if err != nil {
+		log.Errorf("failed to update the project member %d: %v", id, err)
+		return id, http.StatusInternalServerError, err
+	}
+	return id, http.StatusOK, nil
+}
+
+// checkExpiration checks the expiration time of the request updating the member
+func checkExpiration(req *models.MemberReq) error {
+	if req.ExpirationTime == nil {
+		return nil
+	}
+	if req.NeverExpire {
+		return ErrConflictExpiration
+	}
+	if !req.ExpirationTime.After(time.Now()) {
+		return ErrInvalidExpiration
+	}
+	return nil
+}
+
+// updateExpiration updates the expiration time of the member only if the request specifies it,
+// so that the one updating the role only keeps the expiration time
+func updateExpiration(pmID int, req *models.MemberReq) error {
+	switch {
+	case req.NeverExpire:
+		return project.UpdateProjectMemberExpiration(pmID, nil)
+	case req.ExpirationTime != nil:
+		return project.UpdateProjectMemberExpiration(pmID, req.ExpirationTime)
+	default:
+		return nil
+	}
+}
+
+// memberQuery returns the query of the existing member specified by the entry, false if
+// the entry specifies no member
+func memberQuery(projectID int64, entry *models.MemberBatchEntry) (models.Member, bool) {
+	query := models.Member{ProjectID: projectID}
+	switch {
+	case entry.ID > 0:
+		query.ID = entry.ID
+	case entry.MemberUser.UserID > 0:
+		query.EntityType = common.UserMember
+		query.EntityID = entry.MemberUser.UserID
+	case len(entry.MemberUser.Username) > 0:
+		query.EntityType = common.UserMember
+		query.Entityname = entry.MemberUser.Username
+	case entry.MemberGroup.ID > 0:
+		query.EntityType = common.GroupMember
+		query.EntityID = entry.MemberGroup.ID
+	case len(entry.MemberGroup.GroupName) > 0:
+		query.EntityType = common.GroupMember
+		query.Entityname = entry.MemberGroup.GroupName
+	default:
+		return query, false
+	}
+	return query, true
+}
+
 // AddProjectMember ...
 func AddProjectMember(projectID int64, request models.MemberReq) (int, error) {
 	var member models.Member
 	member.ProjectID = projectID
 	member.Role = request.Role
+	member.ExpirationTime = request.ExpirationTime
+	if member.IsExpired() {
+		return 0, ErrInvalidExpiration
+	}
 	if request.MemberUser.UserID > 0 {
 		member.EntityID = request.MemberUser.UserID
 		member.EntityType = common.UserMember
@@ -230,5 +405,23 @@ func AddProjectMember(projectID int64, request models.MemberReq) (int, error) {
 		// Return invalid role error
 		return 0, ErrInvalidRole
 	}
-	return project.AddProjectMember(member)
+	id, err := project.AddProjectMember(member)
+	if err != nil {
+		return 0, err
+	}
+	if member.EntityType == common.UserMember {
+		go notifyMembershipGranted(member)
+	}
+	return id, nil
+}
+
+// notifyMembershipGranted notifies the user added as the member of the project
+func notifyMembershipGranted(member models.Member) {
+	pro, err := dao.GetProjectByID(member.ProjectID)
+	if err != nil || pro == nil {
+		log.Errorf("failed to get project %d: %v", member.ProjectID, err)
+		return
+	}
+	notifier.NotifyUsers([]int{member.EntityID}, models.UserNotificationMembershipGranted,
+		fmt.Sprintf("You have been added as a member of project %s.", pro.Name))
 }
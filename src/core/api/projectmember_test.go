@@ -18,10 +18,13 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/dao/project"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProjectMemberAPI_Get(t *testing.T) {
@@ -259,3 +262,173 @@ func TestProjectMemberAPI_PutAndDelete(t *testing.T) {
 	runCodeCheckingCases(t, cases...)
 
 }
+
+func TestProjectMemberAPI_PutKeepExpiration(t *testing.T) {
+	userID, err := dao.Register(models.User{
+		Username: "expiringuser",
+		Password: "Harbor12345",
+		Email:    "expiringuser@example.com",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteUser(int(userID))
+
+	expiration := time.Now().Add(time.Hour)
+	ID, err := project.AddProjectMember(models.Member{
+		ProjectID:      1,
+		Role:           1,
+		EntityID:       int(userID),
+		EntityType:     "u",
+		ExpirationTime: &expiration,
+	})
+	require.Nil(t, err)
+	defer project.DeleteProjectMemberByID(ID)
+	URL := fmt.Sprintf("/api/projects/1/members/%v", ID)
+
+	getMember := func() *models.Member {
+		members, err := project.GetProjectMember(models.Member{ProjectID: 1, ID: ID})
+		require.Nil(t, err)
+		require.Equal(t, 1, len(members))
+		return members[0]
+	}
+
+	// the role only
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        URL,
+			bodyJSON:   &models.MemberReq{Role: 2},
+			credential: admin,
+		},
+		code: http.StatusOK,
+	})
+	member := getMember()
+	assert.Equal(t, 2, member.Role)
+	require.NotNil(t, member.ExpirationTime)
+	assert.Equal(t, expiration.Unix(), member.ExpirationTime.Unix())
+
+	// both the expiration time and never_expire
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        URL,
+			bodyJSON:   &models.MemberReq{Role: 2, ExpirationTime: &expiration, NeverExpire: true},
+			credential: admin,
+		},
+		code: http.StatusBadRequest,
+	})
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        URL,
+			bodyJSON:   &models.MemberReq{Role: 2, NeverExpire: true},
+			credential: admin,
+		},
+		code: http.StatusOK,
+	})
+	assert.Nil(t, getMember().ExpirationTime)
+}
+
+func TestProjectMemberAPI_Batch(t *testing.T) {
+	userID, err := dao.Register(models.User{
+		Username: "batchuser",
+		Password: "Harbor12345",
+		Email:    "batchuser@example.com",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteUser(int(userID))
+
+	url := "/api/projects/1/members/batch"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method:   http.MethodPost,
+				url:      url,
+				bodyJSON: &models.MemberBatchReq{},
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        url,
+				bodyJSON:   &models.MemberBatchReq{},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no member
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        url,
+				bodyJSON:   &models.MemberBatchReq{},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	entry := func(action string, role int, username string) *models.MemberBatchEntry {
+		return &models.MemberBatchEntry{
+			Action: action,
+			MemberReq: models.MemberReq{
+				Role: role,
+				MemberUser: models.User{
+					Username: username,
+				},
+			},
+		}
+	}
+	results := []*models.MemberBatchResult{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    url,
+		bodyJSON: &models.MemberBatchReq{
+			Members: []*models.MemberBatchEntry{
+				entry(models.MemberBatchActionAdd, 3, "batchuser"),
+				entry(models.MemberBatchActionAdd, 3, "batchuser"),
+				entry(models.MemberBatchActionUpdate, 2, "batchuser"),
+				entry(models.MemberBatchActionUpdate, 9, "batchuser"),
+				entry(models.MemberBatchActionRemove, 0, "notexistuser"),
+				entry("invalid", 0, "batchuser"),
+			},
+		},
+		credential: admin,
+	}, &results)
+	require.Nil(t, err)
+	require.Equal(t, 6, len(results))
+	codes := []int{}
+	for _, result := range results {
+		codes = append(codes, result.StatusCode)
+	}
+	assert.Equal(t, []int{http.StatusCreated, http.StatusConflict, http.StatusOK,
+		http.StatusBadRequest, http.StatusNotFound, http.StatusBadRequest}, codes)
+	assert.Equal(t, results[0].ID, results[2].ID)
+
+	members, err := project.GetProjectMember(models.Member{ID: results[0].ID, ProjectID: 1})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(members))
+	assert.Equal(t, 2, members[0].Role)
+
+	results = nil
+	err = handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    url,
+		bodyJSON: &models.MemberBatchReq{
+			Members: []*models.MemberBatchEntry{
+				{
+					Action: models.MemberBatchActionRemove,
+					ID:     members[0].ID,
+				},
+			},
+		},
+		credential: admin,
+	}, &results)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(results))
+	assert.Equal(t, http.StatusOK, results[0].StatusCode)
+}
@@ -0,0 +1,330 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/approval"
+	"github.com/goharbor/harbor/src/core/promotion"
+)
+
+// PromotionPipelineAPI handles request to /api/promotion_pipelines
+type PromotionPipelineAPI struct {
+	BaseController
+	pipeline *models.PromotionPipeline
+}
+
+// Prepare validates the user and the pipeline, all the authenticated users can get the
+// pipelines while only the system admin is allowed to manage them
+func (p *PromotionPipelineAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+	if p.Ctx.Request.Method != http.MethodGet && !p.SecurityCtx.IsSysAdmin() {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(p.GetStringFromPath(":id")) > 0 {
+		id, err := p.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			p.HandleBadRequest(fmt.Sprintf("invalid pipeline ID: %s", p.GetStringFromPath(":id")))
+			return
+		}
+		pipeline, err := dao.GetPromotionPipeline(id)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to get the promotion pipeline %d: %v", id, err))
+			return
+		}
+		if pipeline == nil {
+			p.HandleNotFound(fmt.Sprintf("promotion pipeline %d not found", id))
+			return
+		}
+		p.pipeline = pipeline
+	}
+}
+
+// List lists all the pipelines
+func (p *PromotionPipelineAPI) List() {
+	pipelines, err := dao.ListPromotionPipelines()
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the promotion pipelines: %v", err))
+		return
+	}
+	p.Data["json"] = pipelines
+	p.ServeJSON()
+}
+
+// Get returns the pipeline
+func (p *PromotionPipelineAPI) Get() {
+	p.Data["json"] = p.pipeline
+	p.ServeJSON()
+}
+
+// Post creates the pipeline
+func (p *PromotionPipelineAPI) Post() {
+	pipeline := &models.PromotionPipeline{}
+	p.DecodeJSONReqAndValidate(pipeline)
+	if !p.validate(pipeline) {
+		return
+	}
+	pipeline.Creator = p.SecurityCtx.GetUsername()
+	id, err := dao.AddPromotionPipeline(pipeline)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to create the promotion pipeline: %v", err))
+		return
+	}
+	p.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// Put updates the name, description and stages of the pipeline
+func (p *PromotionPipelineAPI) Put() {
+	pipeline := &models.PromotionPipeline{}
+	p.DecodeJSONReqAndValidate(pipeline)
+	pipeline.ID = p.pipeline.ID
+	if !p.validate(pipeline) {
+		return
+	}
+	if err := dao.UpdatePromotionPipeline(pipeline); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to update the promotion pipeline %d: %v", pipeline.ID, err))
+	}
+}
+
+// Delete deletes the pipeline and the promotions of it
+func (p *PromotionPipelineAPI) Delete() {
+	if err := dao.DeletePromotionPipeline(p.pipeline.ID); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to delete the promotion pipeline %d: %v", p.pipeline.ID, err))
+	}
+}
+
+// validate checks the name is unique and the projects of the stages exist
+func (p *PromotionPipelineAPI) validate(pipeline *models.PromotionPipeline) bool {
+	pipelines, err := dao.ListPromotionPipelines()
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the promotion pipelines: %v", err))
+		return false
+	}
+	for _, pl := range pipelines {
+		if pl.Name == pipeline.Name && pl.ID != pipeline.ID {
+			p.HandleConflict(fmt.Sprintf("promotion pipeline %s already exists", pipeline.Name))
+			return false
+		}
+	}
+	for _, stage := range pipeline.Stages {
+		project, err := p.ProjectMgr.Get(stage.ProjectID)
+		if err != nil {
+			p.ParseAndHandleError(fmt.Sprintf("failed to get project %d", stage.ProjectID), err)
+			return false
+		}
+		if project == nil {
+			p.HandleBadRequest(fmt.Sprintf("project %d not found", stage.ProjectID))
+			return false
+		}
+	}
+	return true
+}
+
+// PromotionAPI handles request to /api/promotions
+type PromotionAPI struct {
+	BaseController
+	promotion *models.Promotion
+}
+
+// Prepare validates the user and the promotion
+func (p *PromotionAPI) Prepare() {
+	p.BaseController.Prepare()
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if len(p.GetStringFromPath(":id")) > 0 {
+		id, err := p.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			p.HandleBadRequest(fmt.Sprintf("invalid promotion ID: %s", p.GetStringFromPath(":id")))
+			return
+		}
+		promotion, err := dao.GetPromotion(id)
+		if err != nil {
+			p.HandleInternalServerError(fmt.Sprintf("failed to get the promotion %d: %v", id, err))
+			return
+		}
+		if promotion == nil {
+			p.HandleNotFound(fmt.Sprintf("promotion %d not found", id))
+			return
+		}
+		p.promotion = promotion
+	}
+}
+
+// Post promotes the image into the next stage of the pipeline, the user must have the write
+// permission of the source project while the image is copied into the target one server-side
+func (p *PromotionAPI) Post() {
+	req := &models.PromotionRequest{}
+	p.DecodeJSONReqAndValidate(req)
+
+	projectName, _ := utils.ParseRepository(req.Repository)
+	if !p.SecurityCtx.HasWritePerm(projectName) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	pipeline, err := dao.GetPromotionPipeline(req.PipelineID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to get the promotion pipeline %d: %v", req.PipelineID, err))
+		return
+	}
+	if pipeline == nil {
+		p.HandleBadRequest(fmt.Sprintf("promotion pipeline %d not found", req.PipelineID))
+		return
+	}
+
+	pro, err := promotion.Promote(p.ProjectMgr, pipeline, req.Repository, req.Tag, p.SecurityCtx.GetUsername())
+	if err != nil {
+		switch err.(type) {
+		case *promotion.GateError:
+			p.HandleStatusPreconditionFailed(err.Error())
+			return
+		}
+		switch err {
+		case promotion.ErrNotInPipeline, promotion.ErrLastStage:
+			p.HandleBadRequest(err.Error())
+		case promotion.ErrImageNotFound:
+			p.HandleNotFound(fmt.Sprintf("image %s:%s not found", req.Repository, req.Tag))
+		default:
+			p.HandleInternalServerError(fmt.Sprintf("failed to promote %s:%s: %v", req.Repository, req.Tag, err))
+		}
+		return
+	}
+	p.Redirect(http.StatusCreated, strconv.FormatInt(pro.ID, 10))
+}
+
+// List lists the promotions, the users other than system admin must specify the
+// repository which they have the read permission of
+func (p *PromotionAPI) List() {
+	query := &models.PromotionQuery{
+		Repository: p.GetString("repository"),
+		Status:     p.GetString("status"),
+	}
+	if !p.SecurityCtx.IsSysAdmin() {
+		if len(query.Repository) == 0 {
+			p.HandleBadRequest("repository is required")
+			return
+		}
+		projectName, _ := utils.ParseRepository(query.Repository)
+		if !p.SecurityCtx.HasReadPerm(projectName) {
+			p.HandleForbidden(p.SecurityCtx.GetUsername())
+			return
+		}
+	}
+	if pipelineID := p.GetString("pipeline_id"); len(pipelineID) > 0 {
+		id, err := strconv.ParseInt(pipelineID, 10, 64)
+		if err != nil || id <= 0 {
+			p.HandleBadRequest(fmt.Sprintf("invalid pipeline_id: %s", pipelineID))
+			return
+		}
+		query.PipelineID = id
+	}
+
+	total, err := dao.CountPromotions(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to count the promotions: %v", err))
+		return
+	}
+	query.Page, query.Size = p.GetPaginationParams()
+	promotions, err := dao.ListPromotions(query)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to list the promotions: %v", err))
+		return
+	}
+	p.SetPaginationHeader(total, query.Page, query.Size)
+	p.Data["json"] = promotions
+	p.ServeJSON()
+}
+
+// Get returns the promotion, the user must have the read permission of the source or target project
+func (p *PromotionAPI) Get() {
+	source, _ := utils.ParseRepository(p.promotion.Repository)
+	target, _ := utils.ParseRepository(p.promotion.TargetRepository)
+	if !p.SecurityCtx.HasReadPerm(source) && !p.SecurityCtx.HasReadPerm(target) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+	p.Data["json"] = p.promotion
+	p.ServeJSON()
+}
+
+// Approve approves the pending promotion and executes it, only the admin of the target project is allowed
+func (p *PromotionAPI) Approve() {
+	if !p.requireTargetAdmin() {
+		return
+	}
+	p.review(true, "")
+}
+
+// Reject rejects the pending promotion, only the admin of the target project is allowed
+func (p *PromotionAPI) Reject() {
+	if !p.requireTargetAdmin() {
+		return
+	}
+	req := struct {
+		Message string `json:"message"`
+	}{}
+	if len(p.Ctx.Input.CopyBody(1<<32)) > 0 {
+		p.DecodeJSONReq(&req)
+	}
+	p.review(false, req.Message)
+}
+
+// review reviews the pending promotion through the approval engine and returns the result
+func (p *PromotionAPI) review(approved bool, comment string) {
+	if err := approval.ReviewResource(models.ApprovalPromotion, p.promotion.ID, approved,
+		p.SecurityCtx.GetUsername(), comment); err != nil {
+		if err == approval.ErrNotPending {
+			p.HandleConflict(fmt.Sprintf("promotion %d is not pending", p.promotion.ID))
+			return
+		}
+		p.HandleInternalServerError(fmt.Sprintf("failed to review the promotion %d: %v", p.promotion.ID, err))
+		return
+	}
+	pro, err := dao.GetPromotion(p.promotion.ID)
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to get the promotion %d: %v", p.promotion.ID, err))
+		return
+	}
+	p.Data["json"] = pro
+	p.ServeJSON()
+}
+
+func (p *PromotionAPI) requireTargetAdmin() bool {
+	target, _ := utils.ParseRepository(p.promotion.TargetRepository)
+	project, err := p.ProjectMgr.Get(target)
+	if err != nil {
+		p.ParseAndHandleError(fmt.Sprintf("failed to get project %s", target), err)
+		return false
+	}
+	if project == nil || !p.SecurityCtx.HasAllPerm(project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return false
+	}
+	return true
+}
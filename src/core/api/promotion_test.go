@@ -0,0 +1,201 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionPipelineAPI(t *testing.T) {
+	projectID, err := dao.AddProject(models.Project{
+		Name:    "project_for_test_promotion",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer dao.DeleteProject(projectID)
+
+	pipeline := &models.PromotionPipeline{
+		Name: "pipeline_for_test",
+		Stages: []*models.PromotionStage{
+			{ProjectID: 1},
+			{ProjectID: projectID, Gates: &models.PromotionGates{Approval: true}},
+		},
+	}
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/promotion_pipelines",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/promotion_pipelines",
+				bodyJSON:   pipeline,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, only one stage
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/promotion_pipelines",
+				bodyJSON: &models.PromotionPipeline{
+					Name:   "pipeline_for_test",
+					Stages: pipeline.Stages[:1],
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, project not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/promotion_pipelines",
+				bodyJSON: &models.PromotionPipeline{
+					Name: "pipeline_for_test",
+					Stages: []*models.PromotionStage{
+						{ProjectID: 1},
+						{ProjectID: 10000},
+					},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/promotion_pipelines/10000",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	resp, err := handle(&testingRequest{
+		method:     http.MethodPost,
+		url:        "/api/promotion_pipelines",
+		bodyJSON:   pipeline,
+		credential: sysAdmin,
+	})
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, resp.Code)
+	location := resp.Header().Get("Location")
+	id, err := strconv.ParseInt(location[strings.LastIndex(location, "/")+1:], 10, 64)
+	require.Nil(t, err)
+	defer dao.DeletePromotionPipeline(id)
+
+	cases = []*codeCheckingCase{
+		// 409, the name already exists
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/promotion_pipelines",
+				bodyJSON:   pipeline,
+				credential: sysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+		// 400, the project isn't a stage of the pipeline
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/promotions",
+				bodyJSON: &models.PromotionRequest{
+					PipelineID: id,
+					Repository: "project_for_test_promotion/hello",
+					Tag:        "latest",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, repository is required for non system admin
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/promotions",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("/api/promotions?pipeline_id=%d", id),
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/promotions/10000/approve",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	pipeline.Description = "library to test"
+	resp, err = handle(&testingRequest{
+		method:     http.MethodPut,
+		url:        location,
+		bodyJSON:   pipeline,
+		credential: sysAdmin,
+	})
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	p := &models.PromotionPipeline{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        location,
+		credential: nonSysAdmin,
+	}, p)
+	require.Nil(t, err)
+	assert.Equal(t, "library to test", p.Description)
+	require.Equal(t, 2, len(p.Stages))
+	assert.True(t, p.Stages[1].Gates.Approval)
+
+	resp, err = handle(&testingRequest{
+		method:     http.MethodDelete,
+		url:        location,
+		credential: sysAdmin,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
@@ -0,0 +1,199 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	job_models "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
+)
+
+// the sides repaired by the reconciliation job
+const (
+	reconciliationTargetDatabase = "database"
+	reconciliationTargetRegistry = "registry"
+)
+
+// ReconciliationAPI triggers the job checking and repairing the drifts between the registry
+// and the database, the report is printed in the log of the job
+type ReconciliationAPI struct {
+	BaseController
+}
+
+type reconciliationReq struct {
+	// the side to repair, the drifts are only reported if it's empty
+	Target string `json:"target"`
+	// the drifts of the target are only reported unless it's set to false explicitly
+	DryRun *bool `json:"dry_run"`
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (r *ReconciliationAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+	if !r.SecurityCtx.IsSysAdmin() {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Post triggers the reconciliation job, only one job can run at the same time. The repair
+// needs the confirmation as it may delete the manifests in the registry
+func (r *ReconciliationAPI) Post() {
+	req := &reconciliationReq{}
+	if r.Ctx.Request.ContentLength > 0 {
+		r.DecodeJSONReq(req)
+	}
+	dryRun := req.DryRun == nil || *req.DryRun
+	if len(req.Target) > 0 {
+		if req.Target != reconciliationTargetDatabase && req.Target != reconciliationTargetRegistry {
+			r.HandleBadRequest(fmt.Sprintf("invalid target %s, must be %s or %s", req.Target,
+				reconciliationTargetDatabase, reconciliationTargetRegistry))
+			return
+		}
+		if !dryRun && !r.Confirmed() {
+			return
+		}
+	}
+
+	for _, status := range []string{models.JobPending, models.JobRunning} {
+		jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+			Name:   common_job.Reconciliation,
+			Kind:   common_job.JobKindGeneric,
+			Status: status,
+		})
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+			return
+		}
+		if len(jobs) > 0 {
+			r.HandleConflict(fmt.Sprintf("the reconciliation job %d is %s", jobs[0].ID, status))
+			return
+		}
+	}
+
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: common_job.Reconciliation,
+		Kind: common_job.JobKindGeneric,
+	})
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to add admin job: %v", err))
+		return
+	}
+	uuid, err := utils_core.GetJobServiceClient().SubmitJob(&job_models.JobData{
+		Name: common_job.Reconciliation,
+		Parameters: map[string]interface{}{
+			"target":  req.Target,
+			"dry_run": dryRun,
+		},
+		Metadata: &job_models.JobMetadata{
+			JobKind:  common_job.JobKindGeneric,
+			IsUnique: true,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d",
+			config.InternalCoreURL(), id),
+	})
+	if err != nil {
+		if err := dao.DeleteAdminJob(id); err != nil {
+			log.Errorf("failed to delete admin job %d: %v", id, err)
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to submit the reconciliation job: %v", err))
+		return
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to set the UUID of admin job %d: %v", id, err))
+		return
+	}
+	r.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List returns the latest 10 reconciliation jobs, including the scheduled checks
+func (r *ReconciliationAPI) List() {
+	jobs, err := dao.GetTop10AdminJobsOfName(common_job.Reconciliation)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+		return
+	}
+	if jobs == nil {
+		jobs = []*models.AdminJob{}
+	}
+	r.Data["json"] = jobs
+	r.ServeJSON()
+}
+
+// Get returns the reconciliation job with the progress
+func (r *ReconciliationAPI) Get() {
+	job := r.getJob()
+	if job == nil {
+		return
+	}
+	r.Data["json"] = job
+	r.ServeJSON()
+}
+
+// GetLog returns the log of the reconciliation job which contains the report
+func (r *ReconciliationAPI) GetLog() {
+	job := r.getJob()
+	if job == nil {
+		return
+	}
+	data, err := utils_core.GetJobServiceClient().GetJobLog(job.UUID)
+	if err != nil {
+		if httpErr, ok := err.(*common_http.Error); ok {
+			r.RenderError(httpErr.Code, httpErr.Message)
+			return
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the log of job %d: %v", job.ID, err))
+		return
+	}
+	r.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Length"), strconv.Itoa(len(data)))
+	r.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/plain")
+	if _, err = r.Ctx.ResponseWriter.Write(data); err != nil {
+		log.Errorf("failed to write the log of job %d: %v", job.ID, err)
+	}
+}
+
+func (r *ReconciliationAPI) getJob() *models.AdminJob {
+	id, err := r.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		r.HandleBadRequest("invalid ID")
+		return nil
+	}
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		ID:   id,
+		Name: common_job.Reconciliation,
+	})
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get admin job %d: %v", id, err))
+		return nil
+	}
+	if len(jobs) == 0 {
+		r.HandleNotFound(fmt.Sprintf("reconciliation job %d not found", id))
+		return nil
+	}
+	return jobs[0]
+}
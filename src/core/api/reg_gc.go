@@ -52,6 +52,9 @@ func (gc *GCAPI) Prepare() {
 func (gc *GCAPI) Post() {
 	gr := models.GCReq{}
 	gc.DecodeJSONReqAndValidate(&gr)
+	if gr.Schedule.Type == models.ScheduleManual && !gc.Confirmed() {
+		return
+	}
 	gc.submitJob(&gr)
 	gc.Redirect(http.StatusCreated, strconv.FormatInt(gr.ID, 10))
 }
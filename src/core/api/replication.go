@@ -24,9 +24,11 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/log"
 	api_models "github.com/goharbor/harbor/src/core/api/models"
 	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/core"
 	"github.com/goharbor/harbor/src/replication/event/notification"
 	"github.com/goharbor/harbor/src/replication/event/topic"
+	rep_models "github.com/goharbor/harbor/src/replication/models"
 
 	"github.com/docker/distribution/uuid"
 )
@@ -94,6 +96,55 @@ func (r *ReplicationAPI) Post() {
 	r.ServeJSON()
 }
 
+// Execute replicates the specified artifacts to the target immediately, the filters of
+// policies are bypassed and the jobs of the execution can be queried by the returned uuid
+func (r *ReplicationAPI) Execute() {
+	execution := &api_models.ReplicationExecution{}
+	r.DecodeJSONReqAndValidate(execution)
+
+	target, err := dao.GetRepTarget(execution.TargetID)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get target %d: %v", execution.TargetID, err))
+		return
+	}
+	if target == nil {
+		r.HandleNotFound(fmt.Sprintf("target %d not found", execution.TargetID))
+		return
+	}
+
+	candidates := []rep_models.FilterItem{}
+	for _, artifact := range execution.Artifacts {
+		exist, err := imageExist(r.SecurityCtx.GetUsername(), artifact.Repository, artifact.Reference())
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s:%s: %v",
+				artifact.Repository, artifact.Reference(), err))
+			return
+		}
+		if !exist {
+			r.HandleNotFound(fmt.Sprintf("%s:%s not found", artifact.Repository, artifact.Reference()))
+			return
+		}
+		candidates = append(candidates, rep_models.FilterItem{
+			Kind:      replication.FilterItemKindTag,
+			Value:     artifact.Repository + ":" + artifact.Reference(),
+			Operation: models.RepOpTransfer,
+		})
+	}
+
+	opUUID := strings.Replace(uuid.Generate().String(), "-", "", -1)
+	if err = core.GlobalController.ReplicateArtifacts(target.ID, candidates, opUUID); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to replicate the artifacts to target %d: %v", target.ID, err))
+		return
+	}
+	log.Infof("replication of %d artifacts to target %d started", len(candidates), target.ID)
+
+	r.Ctx.Output.SetStatus(http.StatusCreated)
+	r.Data["json"] = api_models.ReplicationResponse{
+		UUID: opUUID,
+	}
+	r.ServeJSON()
+}
+
 // startReplication triggers a replication and return the uuid of this replication.
 func startReplication(policyID int64) (string, error) {
 	opUUID := strings.Replace(uuid.Generate().String(), "-", "", -1)
@@ -15,9 +15,11 @@
 package api
 
 import (
+	"encoding/csv"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/goharbor/harbor/src/common/dao"
@@ -30,6 +32,9 @@ import (
 	"github.com/goharbor/harbor/src/replication/core"
 )
 
+// the number of the jobs read from the database at a time when generating the report
+const reportBatchSize = 500
+
 // RepJobAPI handles request to /api/replicationJobs /api/replicationJobs/:id/log
 type RepJobAPI struct {
 	BaseController
@@ -63,25 +68,8 @@ func (ra *RepJobAPI) Prepare() {
 // List filters jobs according to the parameters
 func (ra *RepJobAPI) List() {
 
-	policyID, err := ra.GetInt64("policy_id")
-	if err != nil || policyID <= 0 {
-		ra.HandleBadRequest(fmt.Sprintf("invalid policy_id: %s", ra.GetString("policy_id")))
-		return
-	}
-
-	policy, err := core.GlobalController.GetPolicy(policyID)
-	if err != nil {
-		log.Errorf("failed to get policy %d: %v", policyID, err)
-		ra.CustomAbort(http.StatusInternalServerError, "")
-	}
-
-	if policy.ID == 0 {
-		ra.HandleNotFound(fmt.Sprintf("policy %d not found", policyID))
-		return
-	}
-
-	if !ra.SecurityCtx.HasAllPerm(policy.ProjectIDs[0]) {
-		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+	policyID, ok := ra.policyFromQuery()
+	if !ok {
 		return
 	}
 
@@ -137,6 +125,116 @@ func (ra *RepJobAPI) List() {
 	ra.ServeJSON()
 }
 
+// policyFromQuery returns the ID of the policy specified by the query parameter "policy_id" if
+// the user has all the permissions to its project, false is returned if the error is rendered
+func (ra *RepJobAPI) policyFromQuery() (int64, bool) {
+	policyID, err := ra.GetInt64("policy_id")
+	if err != nil || policyID <= 0 {
+		ra.HandleBadRequest(fmt.Sprintf("invalid policy_id: %s", ra.GetString("policy_id")))
+		return 0, false
+	}
+
+	policy, err := core.GlobalController.GetPolicy(policyID)
+	if err != nil {
+		log.Errorf("failed to get policy %d: %v", policyID, err)
+		ra.CustomAbort(http.StatusInternalServerError, "")
+	}
+
+	if policy.ID == 0 {
+		ra.HandleNotFound(fmt.Sprintf("policy %d not found", policyID))
+		return 0, false
+	}
+
+	if !ra.SecurityCtx.HasAllPerm(policy.ProjectIDs[0]) {
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return 0, false
+	}
+	return policyID, true
+}
+
+// Report downloads the jobs of the replication execution specified by "op_uuid" as CSV, the
+// jobs are read from the database in batches and streamed to the client
+func (ra *RepJobAPI) Report() {
+	policyID, ok := ra.policyFromQuery()
+	if !ok {
+		return
+	}
+	opUUID := ra.GetString("op_uuid")
+	if len(opUUID) == 0 {
+		ra.HandleBadRequest("op_uuid is required")
+		return
+	}
+	query := &models.RepJobQuery{
+		PolicyID:   policyID,
+		OpUUID:     opUUID,
+		Operations: []string{models.RepOpTransfer, models.RepOpDelete},
+	}
+	// check the execution before writing any content
+	jobs, err := dao.GetRepJobsAfter(query, 0, reportBatchSize)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get jobs of execution %s: %v", opUUID, err))
+		return
+	}
+	if len(jobs) == 0 {
+		ra.HandleNotFound(fmt.Sprintf("execution %s of policy %d not found", opUUID, policyID))
+		return
+	}
+
+	w := ra.Ctx.ResponseWriter
+	w.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/csv")
+	w.Header().Set(http.CanonicalHeaderKey("Content-Disposition"),
+		fmt.Sprintf("attachment; filename=replication-%d-%s.csv", policyID, opUUID))
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "repository", "tags", "operation", "status", "size",
+		"duration_seconds", "error", "creation_time", "update_time"})
+	for len(jobs) > 0 {
+		for _, job := range jobs {
+			writer.Write(reportRow(job))
+		}
+		writer.Flush()
+		if err = writer.Error(); err != nil {
+			log.Errorf("failed to write the report of execution %s: %v", opUUID, err)
+			return
+		}
+		w.Flush()
+
+		if jobs, err = dao.GetRepJobsAfter(query, jobs[len(jobs)-1].ID, reportBatchSize); err != nil {
+			// the status code is already sent, only log the error
+			log.Errorf("failed to get jobs of execution %s: %v", opUUID, err)
+			return
+		}
+	}
+}
+
+// reportRow returns the CSV row of the job, the duration is the time between the creation
+// and the last update of the job. The values are escaped to not be treated as formulas
+// by the spreadsheets
+func reportRow(job *models.RepJob) []string {
+	duration := ""
+	if job.Status == models.JobFinished || job.Status == models.JobError ||
+		job.Status == models.JobStopped || job.Status == models.JobCanceled {
+		duration = strconv.FormatInt(int64(job.UpdateTime.Sub(job.CreationTime).Seconds()), 10)
+	}
+	row := []string{
+		strconv.FormatInt(job.ID, 10),
+		job.Repository,
+		strings.Join(job.TagList, " "),
+		job.Operation,
+		job.Status,
+		strconv.FormatInt(job.Size, 10),
+		duration,
+		job.Error,
+		job.CreationTime.UTC().Format(time.RFC3339),
+		job.UpdateTime.UTC().Format(time.RFC3339),
+	}
+	for i, value := range row {
+		if len(value) > 0 && strings.ContainsRune("=+-@", rune(value[0])) {
+			row[i] = "'" + value
+		}
+	}
+	return row
+}
+
 // Delete ...
 func (ra *RepJobAPI) Delete() {
 	if ra.jobID == 0 {
@@ -0,0 +1,44 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRow(t *testing.T) {
+	creation := time.Date(2019, 3, 1, 8, 0, 0, 0, time.UTC)
+	job := &models.RepJob{
+		ID:           1,
+		Repository:   "library/nginx",
+		TagList:      []string{"1.15", "latest"},
+		Operation:    models.RepOpTransfer,
+		Status:       models.JobError,
+		Size:         1024,
+		Error:        "=HYPERLINK(\"http://example.com\")",
+		CreationTime: creation,
+		UpdateTime:   creation.Add(90 * time.Second),
+	}
+	assert.Equal(t, []string{"1", "library/nginx", "1.15 latest", "transfer", "error", "1024", "90",
+		"'=HYPERLINK(\"http://example.com\")", "2019-03-01T08:00:00Z", "2019-03-01T08:01:30Z"}, reportRow(job))
+
+	job.Status = models.JobRunning
+	job.Error = ""
+	assert.Equal(t, "", reportRow(job)[6])
+}
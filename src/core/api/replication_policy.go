@@ -166,6 +166,11 @@ func (pa *RepPolicyAPI) Post() {
 			pa.HandleNotFound(fmt.Sprintf("target %d not found", target.ID))
 			return
 		}
+		// the robots can only be re-issued by the Harbor targets
+		if policy.ReplicateRobots && t.IsRegistry() {
+			pa.HandleBadRequest(fmt.Sprintf("target %d is not a Harbor, the robots can not be replicated to it", target.ID))
+			return
+		}
 	}
 
 	// check the existence of labels
@@ -279,6 +284,11 @@ func (pa *RepPolicyAPI) Put() {
 			pa.HandleNotFound(fmt.Sprintf("target %d not found", target.ID))
 			return
 		}
+		// the robots can only be re-issued by the Harbor targets
+		if policy.ReplicateRobots && t.IsRegistry() {
+			pa.HandleBadRequest(fmt.Sprintf("target %d is not a Harbor, the robots can not be replicated to it", target.ID))
+			return
+		}
 	}
 
 	// check the existence of labels
@@ -359,6 +369,7 @@ func convertFromRepPolicy(projectMgr promgr.ProjectManager, policy rep_models.Re
 		Name:              policy.Name,
 		Description:       policy.Description,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		ReplicateRobots:   policy.ReplicateRobots,
 		Trigger:           policy.Trigger,
 		CreationTime:      policy.CreationTime,
 		UpdateTime:        policy.UpdateTime,
@@ -421,6 +432,7 @@ func convertToRepPolicy(policy *api_models.ReplicationPolicy) rep_models.Replica
 		Description:       policy.Description,
 		Filters:           policy.Filters,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		ReplicateRobots:   policy.ReplicateRobots,
 		Trigger:           policy.Trigger,
 		CreationTime:      policy.CreationTime,
 		UpdateTime:        policy.UpdateTime,
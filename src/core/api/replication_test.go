@@ -90,3 +90,111 @@ func TestReplicationAPIPost(t *testing.T) {
 
 	runCodeCheckingCases(t, cases...)
 }
+
+func TestReplicationAPIExecute(t *testing.T) {
+	targetID, err := dao.AddRepTarget(
+		models.RepTarget{
+			Name:     "test_replication_execution_target",
+			URL:      "127.0.0.1",
+			Username: "username",
+			Password: "password",
+		})
+	require.Nil(t, err)
+	defer dao.DeleteRepTarget(targetID)
+
+	url := "/api/replication/executions"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    url,
+				bodyJSON: &api_models.ReplicationExecution{
+					TargetID: targetID,
+					Artifacts: []*api_models.ReplicationArtifact{
+						{Repository: "library/hello-world", Tag: "latest"},
+					},
+				},
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400, no artifact
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    url,
+				bodyJSON: &api_models.ReplicationExecution{
+					TargetID: targetID,
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, both tag and digest
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    url,
+				bodyJSON: &api_models.ReplicationExecution{
+					TargetID: targetID,
+					Artifacts: []*api_models.ReplicationArtifact{
+						{
+							Repository: "library/hello-world",
+							Tag:        "latest",
+							Digest:     "sha256:2557e3c07ed1e38f26e389462d03ed943586f744621577a99efb77324b0fe535",
+						},
+					},
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid digest
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    url,
+				bodyJSON: &api_models.ReplicationExecution{
+					TargetID: targetID,
+					Artifacts: []*api_models.ReplicationArtifact{
+						{Repository: "library/hello-world", Digest: "latest"},
+					},
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, target not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    url,
+				bodyJSON: &api_models.ReplicationExecution{
+					TargetID: 10000,
+					Artifacts: []*api_models.ReplicationArtifact{
+						{Repository: "library/hello-world", Tag: "latest"},
+					},
+				},
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, artifact not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    url,
+				bodyJSON: &api_models.ReplicationExecution{
+					TargetID: targetID,
+					Artifacts: []*api_models.ReplicationArtifact{
+						{Repository: "library/hello-world", Tag: "non-exist"},
+					},
+				},
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
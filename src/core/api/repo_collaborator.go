@@ -0,0 +1,151 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// RepoCollaboratorAPI handles the requests on /api/repositories/*/collaborators to manage
+// the users granted the pull or push of the repository without being the members of the
+// project. The access is merged into the one granted by the project when the token service
+// resolves the scopes of the registry
+type RepoCollaboratorAPI struct {
+	BaseController
+	repository string
+}
+
+// Prepare validates the user, the project admin permission is needed to change the
+// collaborators
+func (r *RepoCollaboratorAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	name := r.GetString(":splat")
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		r.HandleNotFound(r.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+
+	projectName, _ := utils.ParseRepository(name)
+	project, err := r.ProjectMgr.Get(projectName)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(r.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if r.Ctx.Request.Method == http.MethodGet {
+		if !r.SecurityCtx.HasReadPerm(project.ProjectID) {
+			r.HandleForbidden(r.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !r.SecurityCtx.HasAllPerm(project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	r.repository = name
+}
+
+// List returns the collaborators of the repository
+func (r *RepoCollaboratorAPI) List() {
+	acls, err := dao.ListRepoACLs(r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list the collaborators of repository %s: %v", r.repository, err))
+		return
+	}
+	r.Data["json"] = acls
+	r.ServeJSON()
+}
+
+// Put grants the access in the request to the user, the access granted before is replaced
+func (r *RepoCollaboratorAPI) Put() {
+	req := &models.RepoACLReq{}
+	r.DecodeJSONReq(req)
+	if req.Access != models.RepoAccessPull && req.Access != models.RepoAccessPush {
+		r.HandleBadRequest(fmt.Sprintf("invalid access %s", req.Access))
+		return
+	}
+	user := r.user()
+	if user == nil {
+		return
+	}
+	if err := dao.SetRepoACL(&models.RepoACL{
+		RepositoryName: r.repository,
+		UserID:         user.UserID,
+		Access:         req.Access,
+	}); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to grant %s of repository %s to user %s: %v",
+			req.Access, r.repository, user.Username, err))
+		return
+	}
+}
+
+// Delete revokes the access granted to the user
+func (r *RepoCollaboratorAPI) Delete() {
+	user := r.user()
+	if user == nil {
+		return
+	}
+	access, err := dao.GetRepoAccess(r.repository, user.Username)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the access of user %s to repository %s: %v",
+			user.Username, r.repository, err))
+		return
+	}
+	if len(access) == 0 {
+		r.HandleNotFound(fmt.Sprintf("user %s isn't a collaborator of repository %s", user.Username, r.repository))
+		return
+	}
+	if err = dao.DeleteRepoACL(r.repository, user.UserID); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to revoke the access of user %s to repository %s: %v",
+			user.Username, r.repository, err))
+		return
+	}
+}
+
+// user returns the user specified in the path, nil is returned if the request is handled
+func (r *RepoCollaboratorAPI) user() *models.User {
+	username := r.GetStringFromPath(":username")
+	user, err := dao.GetUser(models.User{
+		Username: username,
+	})
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", username, err))
+		return nil
+	}
+	if user == nil {
+		r.HandleNotFound(fmt.Sprintf("user %s not found", username))
+		return nil
+	}
+	return user
+}
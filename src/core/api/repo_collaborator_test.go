@@ -0,0 +1,145 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoCollaboratorAPI(t *testing.T) {
+	collaboratorsPath := "/api/repositories/library/hello-world/collaborators"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    collaboratorsPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, repository not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/repositories/library/not-exist/collaborators",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only the project admins can grant the access
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        collaboratorsPath + "/" + nonSysAdmin.Name,
+				credential: projDeveloper,
+				bodyJSON: &models.RepoACLReq{
+					Access: models.RepoAccessPull,
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid access
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        collaboratorsPath + "/" + nonSysAdmin.Name,
+				credential: projAdmin,
+				bodyJSON: &models.RepoACLReq{
+					Access: "delete",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, user not found
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        collaboratorsPath + "/not-exist",
+				credential: projAdmin,
+				bodyJSON: &models.RepoACLReq{
+					Access: models.RepoAccessPull,
+				},
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, not a collaborator
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        collaboratorsPath + "/" + nonSysAdmin.Name,
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        collaboratorsPath + "/" + nonSysAdmin.Name,
+				credential: projAdmin,
+				bodyJSON: &models.RepoACLReq{
+					Access: models.RepoAccessPull,
+				},
+			},
+			code: http.StatusOK,
+		},
+		// 200, replace the access
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        collaboratorsPath + "/" + nonSysAdmin.Name,
+				credential: projAdmin,
+				bodyJSON: &models.RepoACLReq{
+					Access: models.RepoAccessPush,
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	acls := []*models.RepoACL{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        collaboratorsPath,
+		credential: projGuest,
+	}, &acls)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(acls))
+	assert.Equal(t, nonSysAdmin.Name, acls[0].Username)
+	assert.Equal(t, models.RepoAccessPush, acls[0].Access)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        collaboratorsPath + "/" + nonSysAdmin.Name,
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+	acls = []*models.RepoACL{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        collaboratorsPath,
+		credential: projAdmin,
+	}, &acls)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(acls))
+}
@@ -0,0 +1,142 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/cache"
+)
+
+// RepoDeprecationAPI handles the requests on /api/repositories/*/deprecation and
+// /api/repositories/*/tags/:tag/deprecation to mark the repository or the tag as deprecated
+type RepoDeprecationAPI struct {
+	BaseController
+	repository string
+	// empty when the whole repository is handled
+	tag string
+}
+
+// Prepare ...
+func (r *RepoDeprecationAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	name := r.GetString(":splat")
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		r.HandleNotFound(r.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+
+	projectName, _ := utils.ParseRepository(name)
+	project, err := r.ProjectMgr.Get(projectName)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(r.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if r.Ctx.Request.Method == http.MethodGet {
+		if !r.SecurityCtx.HasReadPerm(project.ProjectID) {
+			r.HandleForbidden(r.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !r.SecurityCtx.HasAllPerm(project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	r.repository = name
+	r.tag = r.GetString(":tag")
+}
+
+func (r *RepoDeprecationAPI) target() string {
+	if len(r.tag) == 0 {
+		return r.repository
+	}
+	return r.repository + ":" + r.tag
+}
+
+// Get returns the deprecation of the repository or the tag, including the count of the
+// pulls since it's deprecated
+func (r *RepoDeprecationAPI) Get() {
+	deprecation, err := dao.GetRepoDeprecation(r.repository, r.tag)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the deprecation of %s: %v", r.target(), err))
+		return
+	}
+	if deprecation == nil {
+		r.HandleNotFound(fmt.Sprintf("%s isn't deprecated", r.target()))
+		return
+	}
+	r.Data["json"] = deprecation
+	r.ServeJSON()
+}
+
+// Put deprecates the repository or the tag, or updates the message and replacement of the deprecation
+func (r *RepoDeprecationAPI) Put() {
+	deprecation := &models.RepoDeprecation{}
+	r.DecodeJSONReqAndValidate(deprecation)
+	deprecation.Repository = r.repository
+	deprecation.Tag = r.tag
+	deprecation.Creator = r.SecurityCtx.GetUsername()
+	if err := dao.SetRepoDeprecation(deprecation); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to deprecate %s: %v", r.target(), err))
+		return
+	}
+	r.invalidateCache()
+}
+
+// Delete removes the deprecation, the pulls aren't warned anymore
+func (r *RepoDeprecationAPI) Delete() {
+	deprecation, err := dao.GetRepoDeprecation(r.repository, r.tag)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the deprecation of %s: %v", r.target(), err))
+		return
+	}
+	if deprecation == nil {
+		r.HandleNotFound(fmt.Sprintf("%s isn't deprecated", r.target()))
+		return
+	}
+	if err = dao.DeleteRepoDeprecation(r.repository, r.tag); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete the deprecation of %s: %v", r.target(), err))
+		return
+	}
+	r.invalidateCache()
+}
+
+// invalidateCache removes the deprecations of the repository cached by the proxy, the
+// failure is only logged as the cached ones expire soon
+func (r *RepoDeprecationAPI) invalidateCache() {
+	if err := cache.InvalidateRepoDeprecations(r.repository); err != nil {
+		log.Errorf("failed to invalidate the cached deprecations of %s: %v", r.repository, err)
+	}
+}
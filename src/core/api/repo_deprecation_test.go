@@ -0,0 +1,133 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoDeprecationAPI(t *testing.T) {
+	repoPath := "/api/repositories/library/hello-world/deprecation"
+	tagPath := "/api/repositories/library/hello-world/tags/latest/deprecation"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    repoPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, repository not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/repositories/library/not-exist/deprecation",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, not deprecated
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        repoPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only the project admins can deprecate
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        repoPath,
+				credential: projDeveloper,
+				bodyJSON: &models.RepoDeprecation{
+					Message: "unmaintained",
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, empty message
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        tagPath,
+				credential: projAdmin,
+				bodyJSON:   &models.RepoDeprecation{},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        tagPath,
+				credential: projAdmin,
+				bodyJSON: &models.RepoDeprecation{
+					Message:     "vulnerable",
+					Replacement: "library/hello-world:v2",
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	deprecation := &models.RepoDeprecation{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        tagPath,
+		credential: projGuest,
+	}, deprecation)
+	require.Nil(t, err)
+	assert.Equal(t, "latest", deprecation.Tag)
+	assert.Equal(t, "vulnerable", deprecation.Message)
+	assert.Equal(t, "library/hello-world:v2", deprecation.Replacement)
+	assert.Equal(t, projAdmin.Name, deprecation.Creator)
+
+	tag := &tagResp{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/repositories/library/hello-world/tags/latest",
+		credential: projAdmin,
+	}, tag)
+	require.Nil(t, err)
+	require.NotNil(t, tag.Deprecation)
+	assert.Equal(t, "vulnerable", tag.Deprecation.Message)
+
+	runCodeCheckingCases(t,
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        tagPath,
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        tagPath,
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		})
+}
@@ -0,0 +1,174 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/retention"
+)
+
+// RepoRetentionAPI handles the requests on /api/repositories/*/retention to override the
+// retention policy of the project for the repository
+type RepoRetentionAPI struct {
+	BaseController
+	repository string
+	project    *models.Project
+}
+
+type repoRetentionResp struct {
+	RepositoryName string                  `json:"repository_name"`
+	Mode           string                  `json:"mode"`
+	Policy         *models.RetentionPolicy `json:"policy,omitempty"`
+	// the policy applied to the repository by the retention
+	EffectivePolicy *models.RetentionPolicy `json:"effective_policy,omitempty"`
+}
+
+// Prepare ...
+func (r *RepoRetentionAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	name := r.GetString(":splat")
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		r.HandleNotFound(r.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+
+	projectName, _ := utils.ParseRepository(name)
+	project, err := r.ProjectMgr.Get(projectName)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(r.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if r.Ctx.Request.Method == http.MethodGet {
+		if !r.SecurityCtx.HasReadPerm(project.ProjectID) {
+			r.HandleForbidden(r.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !r.SecurityCtx.HasAllPerm(project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	r.repository = name
+	r.project = project
+}
+
+// Get returns the retention override of the repository and the effective policy
+func (r *RepoRetentionAPI) Get() {
+	override, err := dao.GetRepoRetention(r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the retention of repository %s: %v", r.repository, err))
+		return
+	}
+	effective, err := retention.Resolve(r.project, r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to resolve the retention policy of repository %s: %v", r.repository, err))
+		return
+	}
+	resp := &repoRetentionResp{
+		RepositoryName:  r.repository,
+		Mode:            models.RetentionModeInherit,
+		EffectivePolicy: effective,
+	}
+	if override != nil {
+		resp.Mode = override.Mode
+		resp.Policy = override.Policy
+	}
+	r.Data["json"] = resp
+	r.ServeJSON()
+}
+
+// Put overrides the retention policy of the project or opts out the retention, the
+// mode "inherit" removes the override
+func (r *RepoRetentionAPI) Put() {
+	override := &models.RepoRetention{}
+	r.DecodeJSONReq(override)
+	if override.Mode == models.RetentionModeInherit {
+		r.Delete()
+		return
+	}
+	r.Validate(override)
+	override.RepositoryName = r.repository
+	old, err := dao.GetRepoRetention(r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the retention of repository %s: %v", r.repository, err))
+		return
+	}
+	if err := dao.SetRepoRetention(override); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to set the retention of repository %s: %v", r.repository, err))
+		return
+	}
+	r.addChanges(old, override)
+}
+
+// Delete removes the override, the repository follows the retention policy of the project then
+func (r *RepoRetentionAPI) Delete() {
+	old, err := dao.GetRepoRetention(r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the retention of repository %s: %v", r.repository, err))
+		return
+	}
+	if err := dao.DeleteRepoRetention(r.repository); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete the retention of repository %s: %v", r.repository, err))
+		return
+	}
+	r.addChanges(old, nil)
+}
+
+// addChanges records the changes of the retention override in the access log
+func (r *RepoRetentionAPI) addChanges(old, new *models.RepoRetention) {
+	if err := accesslog.AddChanges(models.AccessLog{
+		Username:  r.SecurityCtx.GetUsername(),
+		ProjectID: r.project.ProjectID,
+		RepoName:  r.repository,
+		RepoTag:   "N/A",
+		Operation: "update_retention",
+	}, retentionFields(old), retentionFields(new)); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
+}
+
+// retentionFields returns the fields of the override compared in the access log, the
+// time of the creation and the update is skipped
+func retentionFields(override *models.RepoRetention) map[string]interface{} {
+	if override == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"mode":   override.Mode,
+		"policy": override.Policy,
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoRetentionAPI(t *testing.T) {
+	retentionPath := "/api/repositories/library/hello-world/retention"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    retentionPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/repositories/library/not-exist/retention",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only the project admins can override the policy
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        retentionPath,
+				credential: projDeveloper,
+				bodyJSON: &models.RepoRetention{
+					Mode: models.RetentionModeDisabled,
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no policy
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        retentionPath,
+				credential: projAdmin,
+				bodyJSON: &models.RepoRetention{
+					Mode: models.RetentionModeOverride,
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        retentionPath,
+				credential: projAdmin,
+				bodyJSON: &models.RepoRetention{
+					Mode: models.RetentionModeOverride,
+					Policy: &models.RetentionPolicy{
+						KeepLatest: 5,
+					},
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	resp := &repoRetentionResp{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        retentionPath,
+		credential: projDeveloper,
+	}, resp)
+	require.Nil(t, err)
+	assert.Equal(t, models.RetentionModeOverride, resp.Mode)
+	require.NotNil(t, resp.EffectivePolicy)
+	assert.Equal(t, 5, resp.EffectivePolicy.KeepLatest)
+
+	// opt out
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        retentionPath,
+			credential: projAdmin,
+			bodyJSON: &models.RepoRetention{
+				Mode: models.RetentionModeDisabled,
+			},
+		},
+		code: http.StatusOK,
+	})
+	resp = &repoRetentionResp{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        retentionPath,
+		credential: projAdmin,
+	}, resp)
+	require.Nil(t, err)
+	assert.Equal(t, models.RetentionModeDisabled, resp.Mode)
+	assert.Nil(t, resp.EffectivePolicy)
+
+	// back to the policy of project
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        retentionPath,
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+	resp = &repoRetentionResp{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        retentionPath,
+		credential: projAdmin,
+	}, resp)
+	require.Nil(t, err)
+	assert.Equal(t, models.RetentionModeInherit, resp.Mode)
+}
@@ -0,0 +1,129 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// RepoStorageHintAPI handles the requests on /api/repositories/*/storage_hint to override the
+// storage hint of the project for the repository
+type RepoStorageHintAPI struct {
+	BaseController
+	repository string
+	project    *models.Project
+}
+
+type repoStorageHintResp struct {
+	RepositoryName string              `json:"repository_name"`
+	Mode           string              `json:"mode"`
+	Hint           *models.StorageHint `json:"hint,omitempty"`
+	// the hint applied to the repository by the storage transition job
+	EffectiveHint *models.StorageHint `json:"effective_hint,omitempty"`
+}
+
+// Prepare ...
+func (r *RepoStorageHintAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	name := r.GetString(":splat")
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		r.HandleNotFound(r.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+
+	projectName, _ := utils.ParseRepository(name)
+	project, err := r.ProjectMgr.Get(projectName)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(r.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if r.Ctx.Request.Method == http.MethodGet {
+		if !r.SecurityCtx.HasReadPerm(project.ProjectID) {
+			r.HandleForbidden(r.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !r.SecurityCtx.HasAllPerm(project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	r.repository = name
+	r.project = project
+}
+
+// Get returns the storage hint override of the repository and the effective hint
+func (r *RepoStorageHintAPI) Get() {
+	override, err := dao.GetRepoStorageHint(r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the storage hint of repository %s: %v", r.repository, err))
+		return
+	}
+	resp := &repoStorageHintResp{
+		RepositoryName: r.repository,
+		Mode:           models.StorageHintModeInherit,
+		EffectiveHint:  models.ResolveStorageHint(r.project.StorageHint(), override),
+	}
+	if override != nil {
+		resp.Mode = override.Mode
+		resp.Hint = override.Hint
+	}
+	r.Data["json"] = resp
+	r.ServeJSON()
+}
+
+// Put overrides the storage hint of the project or keeps the blobs of the repository in
+// the default storage class, the mode "inherit" removes the override
+func (r *RepoStorageHintAPI) Put() {
+	override := &models.RepoStorageHint{}
+	r.DecodeJSONReq(override)
+	if override.Mode == models.StorageHintModeInherit {
+		r.Delete()
+		return
+	}
+	r.Validate(override)
+	override.RepositoryName = r.repository
+	if err := dao.SetRepoStorageHint(override); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to set the storage hint of repository %s: %v", r.repository, err))
+		return
+	}
+}
+
+// Delete removes the override, the repository follows the storage hint of the project then
+func (r *RepoStorageHintAPI) Delete() {
+	if err := dao.DeleteRepoStorageHint(r.repository); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete the storage hint of repository %s: %v", r.repository, err))
+		return
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoStorageHintAPI(t *testing.T) {
+	hintPath := "/api/repositories/library/hello-world/storage_hint"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    hintPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/repositories/library/not-exist/storage_hint",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only the project admins can override the hint
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        hintPath,
+				credential: projDeveloper,
+				bodyJSON: &models.RepoStorageHint{
+					Mode: models.StorageHintModeDisabled,
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, unsupported storage class
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        hintPath,
+				credential: projAdmin,
+				bodyJSON: &models.RepoStorageHint{
+					Mode: models.StorageHintModeOverride,
+					Hint: &models.StorageHint{
+						StorageClass: "DEEP_ARCHIVE",
+						AfterDays:    30,
+					},
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        hintPath,
+				credential: projAdmin,
+				bodyJSON: &models.RepoStorageHint{
+					Mode: models.StorageHintModeOverride,
+					Hint: &models.StorageHint{
+						StorageClass: "STANDARD_IA",
+						AfterDays:    30,
+					},
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	resp := &repoStorageHintResp{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        hintPath,
+		credential: projDeveloper,
+	}, resp)
+	require.Nil(t, err)
+	assert.Equal(t, models.StorageHintModeOverride, resp.Mode)
+	require.NotNil(t, resp.EffectiveHint)
+	assert.Equal(t, "STANDARD_IA", resp.EffectiveHint.StorageClass)
+
+	// back to the hint of project
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPut,
+			url:        hintPath,
+			credential: projAdmin,
+			bodyJSON: &models.RepoStorageHint{
+				Mode: models.StorageHintModeInherit,
+			},
+		},
+		code: http.StatusOK,
+	})
+	resp = &repoStorageHintResp{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        hintPath,
+		credential: projAdmin,
+	}, resp)
+	require.Nil(t, err)
+	assert.Equal(t, models.StorageHintModeInherit, resp.Mode)
+}
@@ -0,0 +1,124 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// RepoSubscriptionAPI handles the star and watch requests on /api/repositories/*/star
+// and /api/repositories/*/subscription
+type RepoSubscriptionAPI struct {
+	BaseController
+	repository string
+	userID     int
+}
+
+// Prepare ...
+func (r *RepoSubscriptionAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	name := r.GetString(":splat")
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		r.HandleNotFound(r.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+
+	project, _ := utils.ParseRepository(name)
+	if !r.SecurityCtx.HasReadPerm(project) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	r.repository = name
+
+	user, err := dao.GetUser(models.User{
+		Username: r.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", r.SecurityCtx.GetUsername(), err))
+		return
+	}
+	if user == nil {
+		// the robot accounts and solution users can not star or watch repositories
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	r.userID = user.UserID
+}
+
+// Star stars the repository for current user
+func (r *RepoSubscriptionAPI) Star() {
+	if err := dao.AddRepoStar(r.userID, r.repository); err != nil && err != dao.ErrDupRows {
+		r.HandleInternalServerError(fmt.Sprintf("failed to star repository %s: %v", r.repository, err))
+		return
+	}
+}
+
+// Unstar unstars the repository for current user
+func (r *RepoSubscriptionAPI) Unstar() {
+	if err := dao.DeleteRepoStar(r.userID, r.repository); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to unstar repository %s: %v", r.repository, err))
+		return
+	}
+}
+
+// GetSubscription returns the subscription of current user to the repository
+func (r *RepoSubscriptionAPI) GetSubscription() {
+	sub, err := dao.GetRepoSubscription(r.userID, r.repository)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the subscription of repository %s: %v", r.repository, err))
+		return
+	}
+	if sub == nil {
+		r.HandleNotFound(fmt.Sprintf("subscription of repository %s not found", r.repository))
+		return
+	}
+	r.Data["json"] = sub
+	r.ServeJSON()
+}
+
+// SetSubscription subscribes the events of the repository for current user
+func (r *RepoSubscriptionAPI) SetSubscription() {
+	sub := &models.RepoSubscription{}
+	r.DecodeJSONReqAndValidate(sub)
+	sub.UserID = r.userID
+	sub.RepositoryName = r.repository
+	if err := dao.SetRepoSubscription(sub); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to subscribe repository %s: %v", r.repository, err))
+		return
+	}
+}
+
+// DeleteSubscription unsubscribes the repository for current user
+func (r *RepoSubscriptionAPI) DeleteSubscription() {
+	if err := dao.DeleteRepoSubscription(r.userID, r.repository); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to unsubscribe repository %s: %v", r.repository, err))
+		return
+	}
+}
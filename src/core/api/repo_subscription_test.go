@@ -0,0 +1,153 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoStarAPI(t *testing.T) {
+	starPath := "/api/repositories/library/hello-world/star"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    starPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/repositories/library/not-exist/star",
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        starPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	repos := []*repoResp{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/users/current/starred",
+		credential: projDeveloper,
+	}, &repos)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(repos))
+	assert.Equal(t, "library/hello-world", repos[0].Name)
+
+	// paginated after filtering
+	repos = []*repoResp{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/users/current/starred?page=2&page_size=1",
+		credential: projDeveloper,
+	}, &repos)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(repos))
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        starPath,
+			credential: projDeveloper,
+		},
+		code: http.StatusOK,
+	})
+
+	repos = []*repoResp{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/users/current/starred",
+		credential: projDeveloper,
+	}, &repos)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(repos))
+}
+
+func TestRepoSubscriptionAPI(t *testing.T) {
+	subPath := "/api/repositories/library/hello-world/subscription"
+	cases := []*codeCheckingCase{
+		// 404, not subscribed
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        subPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, invalid event
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    subPath,
+				bodyJSON: &models.RepoSubscription{
+					Events: []string{"invalid"},
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    subPath,
+				bodyJSON: &models.RepoSubscription{
+					Events: []string{models.SubscriptionEventNewTag},
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	sub := &models.RepoSubscription{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        subPath,
+		credential: projDeveloper,
+	}, sub)
+	require.Nil(t, err)
+	assert.Equal(t, []string{models.SubscriptionEventNewTag}, sub.Events)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        subPath,
+			credential: projDeveloper,
+		},
+		code: http.StatusOK,
+	})
+}
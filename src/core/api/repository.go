@@ -27,19 +27,26 @@ import (
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/api"
 	"github.com/goharbor/harbor/src/common/dao"
 	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/legalhold"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
-	"github.com/goharbor/harbor/src/common/utils/clair"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/notary"
 	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/cache"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/scanner"
+	"github.com/goharbor/harbor/src/core/service/token"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
 	"github.com/goharbor/harbor/src/replication/event/notification"
 	"github.com/goharbor/harbor/src/replication/event/topic"
+	"github.com/opencontainers/go-digest"
 )
 
 // RepositoryAPI handles request to /api/repositories /api/repositories/tags /api/repositories/manifests, the parm has to be put
@@ -98,6 +105,16 @@ type tagResp struct {
 	Signature    *notary.Target          `json:"signature"`
 	ScanOverview *models.ImgScanOverview `json:"scan_overview,omitempty"`
 	Labels       []*models.Label         `json:"labels"`
+	PullTime     *time.Time              `json:"pull_time,omitempty"`
+	// the CI metadata read from the annotations of the image
+	Annotations *models.ArtifactAnnotation `json:"annotations,omitempty"`
+	// the findings of the linter when the image was pushed
+	LintFindings []*models.LintFinding `json:"lint_findings,omitempty"`
+	// the deprecation of the tag or the repository warned when the image is pulled
+	Deprecation *models.RepoDeprecation `json:"deprecation,omitempty"`
+	// the server-side copies tracing the image back to the original build, the copy into
+	// the tag is the first. It's only returned by the detail of the tag
+	Provenance []*models.ArtifactProvenance `json:"provenance,omitempty"`
 }
 
 type manifestResp struct {
@@ -147,8 +164,15 @@ func (ra *RepositoryAPI) Get() {
 	}
 	query.Page, query.Size = ra.GetPaginationParams()
 	query.Sort = ra.GetString("sort")
+	mode := ra.GetCountMode()
 
-	total, err := dao.GetTotalOfRepositories(query)
+	var total int64
+	switch mode {
+	case api.CountModeExact:
+		total, err = dao.GetTotalOfRepositories(query)
+	case api.CountModeCapped:
+		total, err = dao.GetCappedTotalOfRepositories(api.MaxCappedCount, query)
+	}
 	if err != nil {
 		ra.HandleInternalServerError(fmt.Sprintf("failed to get total of repositories of project %d: %v",
 			projectID, err))
@@ -161,7 +185,7 @@ func (ra *RepositoryAPI) Get() {
 		return
 	}
 
-	ra.SetPaginationHeader(total, query.Page, query.Size)
+	ra.SetPaginationHeaderOfMode(mode, total, query.Page, query.Size, int64(len(repositories)))
 	ra.Data["json"] = repositories
 	ra.ServeJSON()
 }
@@ -238,7 +262,7 @@ func (ra *RepositoryAPI) Delete() {
 	}
 
 	if project == nil {
-		ra.HandleNotFound(fmt.Sprintf("project %s not found", projectName))
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
 		return
 	}
 
@@ -252,6 +276,10 @@ func (ra *RepositoryAPI) Delete() {
 		return
 	}
 
+	if ra.frozen(projectName) {
+		return
+	}
+
 	rc, err := coreutils.NewRepositoryClientForUI(ra.SecurityCtx.GetUsername(), repoName)
 	if err != nil {
 		log.Errorf("error occurred while initializing repository client for %s: %v", repoName, err)
@@ -305,24 +333,31 @@ func (ra *RepositoryAPI) Delete() {
 		}
 	}
 
+	// the digests are got before deleting any tag as the tags referencing
+	// the same digest are deleted together
+	digests := coreutils.TagDigests(rc, repoName, tags)
+	hold, err := legalhold.Blocking(rc, repoName, digests)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to check the legal holds of repository %s: %v", repoName, err))
+		return
+	}
+	if hold != nil {
+		ra.HandleStatusPreconditionFailed(fmt.Sprintf("%s is under legal hold", hold.Target()))
+		return
+	}
 	for _, t := range tags {
-		image := fmt.Sprintf("%s:%s", repoName, t)
-		if err = dao.DeleteLabelsOfResource(common.ResourceTypeImage, image); err != nil {
-			ra.HandleInternalServerError(fmt.Sprintf("failed to delete labels of image %s: %v", image, err))
-			return
-		}
-		if err = rc.DeleteTag(t); err != nil {
+		deleted, err := coreutils.DeleteTag(rc, repoName, t, digests[t], ra.SecurityCtx.GetUsername())
+		if err != nil {
 			if regErr, ok := err.(*commonhttp.Error); ok {
-				if regErr.Code == http.StatusNotFound {
-					continue
-				}
 				log.Errorf("failed to delete tag %s: %v", t, err)
 				ra.CustomAbort(regErr.Code, regErr.Message)
 			}
-			log.Errorf("error occurred while deleting tag %s:%s: %v", repoName, t, err)
-			ra.CustomAbort(http.StatusInternalServerError, "internal error")
+			ra.HandleInternalServerError(fmt.Sprintf("failed to delete tag %s:%s: %v", repoName, t, err))
+			return
+		}
+		if !deleted {
+			continue
 		}
-		log.Infof("delete tag: %s:%s", repoName, t)
 
 		go func(tag string) {
 			image := repoName + ":" + tag
@@ -337,7 +372,7 @@ func (ra *RepositoryAPI) Delete() {
 		}(t)
 
 		go func(tag string) {
-			if err := dao.AddAccessLog(models.AccessLog{
+			if err := accesslog.Add(models.AccessLog{
 				Username:  ra.SecurityCtx.GetUsername(),
 				ProjectID: project.ProjectID,
 				RepoName:  repoName,
@@ -372,6 +407,11 @@ func (ra *RepositoryAPI) Delete() {
 				repoName, err))
 			return
 		}
+		if err = dao.DeleteVulException(repoName); err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to delete the vulnerability exception of repository %s: %v",
+				repoName, err))
+			return
+		}
 		if err = dao.DeleteRepository(repoName); err != nil {
 			log.Errorf("failed to delete repository %s: %v", repoName, err)
 			ra.CustomAbort(http.StatusInternalServerError, "")
@@ -379,6 +419,71 @@ func (ra *RepositoryAPI) Delete() {
 	}
 }
 
+// HeadTag checks whether the tag or digest exists and can be pulled by the user, the permission is
+// resolved as the token service does. The existence is resolved from the manifest cache and the tag
+// history first, the registry is requested only when neither of them knows the reference
+func (ra *RepositoryAPI) HeadTag() {
+	repository := ra.GetString(":splat")
+	reference := ra.GetString(":tag")
+	allowed, err := token.PullAllowed(ra.SecurityCtx, ra.ProjectMgr, repository)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to check the pull permission of %s: %v", repository, err))
+		return
+	}
+	if !allowed {
+		if !ra.SecurityCtx.IsAuthenticated() {
+			ra.HandleUnauthorized()
+			return
+		}
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return
+	}
+
+	var dgt string
+	if _, err = digest.Parse(reference); err == nil {
+		tagged, err := dao.DigestTagged(repository, reference)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s@%s: %v", repository, reference, err))
+			return
+		}
+		if tagged {
+			dgt = reference
+		}
+	} else if manifest := cache.GetManifest(repository, reference); manifest != nil {
+		dgt = manifest.Digest
+	} else {
+		history, err := dao.GetLatestTagHistory(repository, reference)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s:%s: %v", repository, reference, err))
+			return
+		}
+		if history != nil && history.Operation != models.TagHistoryDelete {
+			dgt = history.Digest
+		}
+	}
+	// the images pushed before the tag history was recorded are only known by the registry
+	if len(dgt) == 0 {
+		client, err := coreutils.NewRepositoryClientForUI(ra.SecurityCtx.GetUsername(), repository)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to initialize the client for %s: %v", repository, err))
+			return
+		}
+		d, exist, err := client.ManifestExist(reference)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s:%s: %v", repository, reference, err))
+			return
+		}
+		if exist {
+			dgt = d
+		}
+	}
+	if len(dgt) == 0 {
+		ra.HandleNotFound(fmt.Sprintf("%s:%s not found", repository, reference))
+		return
+	}
+	ra.Ctx.ResponseWriter.Header().Set("Docker-Content-Digest", dgt)
+}
+
 // GetTag returns the tag of a repository
 func (ra *RepositoryAPI) GetTag() {
 	repository := ra.GetString(":splat")
@@ -421,6 +526,14 @@ func (ra *RepositoryAPI) GetTag() {
 
 	result := assembleTagsInParallel(client, repository, []string{tag},
 		ra.SecurityCtx.GetUsername())
+	if len(result[0].Digest) > 0 {
+		provenance, err := dao.GetProvenanceChain(repository, tag, result[0].Digest)
+		if err != nil {
+			log.Errorf("failed to get the provenance of %s:%s: %v", repository, tag, err)
+		} else if len(provenance) > 0 {
+			result[0].Provenance = provenance
+		}
+	}
 	ra.Data["json"] = result[0]
 	ra.ServeJSON()
 }
@@ -453,7 +566,8 @@ func (ra *RepositoryAPI) Retag() {
 	}
 
 	// Check whether source image exists
-	exist, _, err := ra.checkExistence(fmt.Sprintf("%s/%s", srcImage.Project, srcImage.Repo), srcImage.Tag)
+	srcRepoName := fmt.Sprintf("%s/%s", srcImage.Project, srcImage.Repo)
+	exist, digest, err := ra.checkExistence(srcRepoName, srcImage.Tag)
 	if err != nil {
 		ra.HandleInternalServerError(fmt.Sprintf("check existence of %s error: %v", request.SrcImage, err))
 		return
@@ -464,16 +578,24 @@ func (ra *RepositoryAPI) Retag() {
 	}
 
 	// Check whether target project exists
-	exist, err = ra.ProjectMgr.Exists(project)
+	targetProject, err := ra.ProjectMgr.Get(project)
 	if err != nil {
 		ra.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %s", project), err)
 		return
 	}
-	if !exist {
-		ra.HandleNotFound(fmt.Sprintf("project %s not found", project))
+	if targetProject == nil {
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, project))
 		return
 	}
 
+	// the tags created by retagging follow the tag policy as the pushed ones
+	if policy := targetProject.TagPolicy(); policy != nil {
+		if err = policy.Check(request.Tag); err != nil {
+			ra.HandleBadRequest(fmt.Sprintf("the tag violates the tag policy of project %s: %v", project, err))
+			return
+		}
+	}
+
 	// If override not allowed, check whether target tag already exists
 	if !request.Override {
 		exist, _, err := ra.checkExistence(repoName, request.Tag)
@@ -501,6 +623,10 @@ func (ra *RepositoryAPI) Retag() {
 		return
 	}
 
+	if ra.frozen(project) {
+		return
+	}
+
 	// Retag the image
 	if err = coreutils.Retag(srcImage, &models.Image{
 		Project: project,
@@ -508,6 +634,145 @@ func (ra *RepositoryAPI) Retag() {
 		Tag:     request.Tag,
 	}); err != nil {
 		ra.HandleInternalServerError(fmt.Sprintf("%v", err))
+		return
+	}
+	coreutils.AddProvenance(repoName, request.Tag, srcRepoName, srcImage.Tag, digest,
+		models.ProvenanceRetag, ra.SecurityCtx.GetUsername())
+}
+
+// frozen returns true with the response written if the project is in a freeze window or
+// the freeze windows can't be checked
+func (ra *RepositoryAPI) frozen(projectName string) bool {
+	err := coreutils.CheckFreezeWindow(projectName)
+	if err == nil {
+		return false
+	}
+	if _, ok := err.(*coreutils.FrozenError); ok {
+		ra.HandleForbidden(err.Error())
+		return true
+	}
+	ra.HandleInternalServerError(fmt.Sprintf("failed to check the freeze windows of project %s: %v", projectName, err))
+	return true
+}
+
+// Rename renames the repository and moves it to another project if the project part of the new name
+// differs, see coreutils.MoveRepository for the details.
+func (ra *RepositoryAPI) Rename() {
+	if !ra.SecurityCtx.IsAuthenticated() {
+		ra.HandleUnauthorized()
+		return
+	}
+
+	repoName := ra.GetString(":splat")
+	projectName, _ := utils.ParseRepository(repoName)
+	request := models.RepoRenameRequest{}
+	ra.DecodeJSONReq(&request)
+	newProjectName, newRepo := utils.ParseRepository(request.Name)
+	if len(newProjectName) == 0 || !utils.ValidateRepo(newRepo) {
+		ra.HandleBadRequest(fmt.Sprintf("invalid name '%s', should be in format '<project>/<repo>'", request.Name))
+		return
+	}
+	if request.Name == repoName {
+		ra.HandleBadRequest(fmt.Sprintf("the repository is already named %s", repoName))
+		return
+	}
+
+	exist, err := ra.ProjectMgr.Exists(projectName)
+	if err != nil {
+		ra.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %s", projectName), err)
+		return
+	}
+	if !exist {
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+	if !ra.SecurityCtx.HasAllPerm(projectName) {
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return
+	}
+
+	newProject, err := ra.ProjectMgr.Get(newProjectName)
+	if err != nil {
+		ra.ParseAndHandleError(fmt.Sprintf("failed to get the project %s", newProjectName), err)
+		return
+	}
+	if newProject == nil {
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, newProjectName))
+		return
+	}
+	if !ra.SecurityCtx.HasWritePerm(newProjectName) {
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return
+	}
+
+	// the repository is deleted from the old project and pushed to the new one
+	if ra.frozen(projectName) || ra.frozen(newProjectName) {
+		return
+	}
+
+	repository, err := dao.GetRepositoryByName(repoName)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", repoName, err))
+		return
+	}
+	if repository == nil {
+		ra.HandleNotFound(ra.T(i18n.MsgRepositoryNotFound, repoName))
+		return
+	}
+
+	destClient, err := coreutils.NewRepositoryClientForUI(ra.SecurityCtx.GetUsername(), request.Name)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to initialize the client for %s: %v", request.Name, err))
+		return
+	}
+	exist, err = repositoryExist(request.Name, destClient)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to check the existence of repository %s: %v", request.Name, err))
+		return
+	}
+	if exist || dao.RepositoryExists(request.Name) {
+		ra.HandleConflict(fmt.Sprintf("repository %s already exists", request.Name))
+		return
+	}
+
+	// the repository is deleted from the old name when moving
+	holds, err := dao.CountLegalHolds(&models.LegalHoldQuery{
+		Repository: repoName,
+		Active:     true,
+	})
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to count the legal holds of repository %s: %v", repoName, err))
+		return
+	}
+	if holds > 0 {
+		ra.HandleStatusPreconditionFailed(fmt.Sprintf("repository %s is under legal hold", repoName))
+		return
+	}
+
+	// the signatures are bound to the name of the repository
+	if config.WithNotary() {
+		signatures, err := getSignatures(ra.SecurityCtx.GetUsername(), repoName)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to get signatures for repository %s: %v", repoName, err))
+			return
+		}
+		if len(signatures) > 0 {
+			ra.HandleStatusPreconditionFailed(fmt.Sprintf("repository %s contains signed images", repoName))
+			return
+		}
+	}
+
+	if err = coreutils.MoveRepository(repository, request.Name, newProject.ProjectID, ra.SecurityCtx.GetUsername()); err != nil {
+		if err == dao.ErrDupRows {
+			ra.HandleConflict(fmt.Sprintf("repository %s already exists", request.Name))
+			return
+		}
+		if _, ok := err.(*coreutils.FrozenError); ok {
+			ra.HandleForbidden(err.Error())
+			return
+		}
+		ra.HandleInternalServerError(fmt.Sprintf("failed to rename repository %s to %s: %v", repoName, request.Name, err))
+		return
 	}
 }
 
@@ -529,7 +794,7 @@ func (ra *RepositoryAPI) GetTags() {
 	}
 
 	if !exist {
-		ra.HandleNotFound(fmt.Sprintf("project %s not found", projectName))
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
 		return
 	}
 
@@ -626,6 +891,14 @@ func assembleTag(c chan *tagResp, client *registry.Repository,
 		item.Labels = labels
 	}
 
+	// the last pull time, which isn't available if the tag has never been pulled
+	pullTime, err := dao.GetTagPullTime(repository, tag)
+	if err != nil {
+		log.Errorf("failed to get pull time of image %s: %v", image, err)
+	} else if pullTime != nil {
+		item.PullTime = &pullTime.PullTime
+	}
+
 	// the detail information of tag
 	tagDetail, err := getTagDetail(client, tag)
 	if err != nil {
@@ -634,6 +907,24 @@ func assembleTag(c chan *tagResp, client *registry.Repository,
 	if tagDetail != nil {
 		item.tagDetail = *tagDetail
 	}
+	if tagDetail != nil && tagDetail.Config != nil {
+		item.Annotations = models.ParseArtifactAnnotations(tagDetail.Config.Labels)
+	}
+
+	// the lint findings are outdated if the tag has been pushed again without linting
+	artifactLint, err := dao.GetArtifactLint(repository, tag)
+	if err != nil {
+		log.Errorf("failed to get lint findings of image %s: %v", image, err)
+	} else if artifactLint != nil && artifactLint.Digest == item.Digest {
+		item.LintFindings = artifactLint.Findings
+	}
+
+	deprecation, err := dao.GetEffectiveRepoDeprecation(repository, tag)
+	if err != nil {
+		log.Errorf("failed to get deprecation of image %s: %v", image, err)
+	} else {
+		item.Deprecation = deprecation
+	}
 
 	// scan overview
 	if clairEnabled {
@@ -713,6 +1004,74 @@ func populateAuthor(detail *tagDetail) {
 	}
 }
 
+// GetTagHistory returns the history of the tag, the latest one is the first. The history
+// is kept after the tag is deleted
+func (ra *RepositoryAPI) GetTagHistory() {
+	repoName := ra.GetString(":splat")
+	query := &models.TagHistoryQuery{
+		Repository: repoName,
+		Tag:        ra.GetString(":tag"),
+	}
+
+	timestamp := ra.GetString("begin_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			ra.HandleBadRequest(fmt.Sprintf("invalid begin_timestamp: %s", timestamp))
+			return
+		}
+		query.BeginTime = *t
+	}
+
+	timestamp = ra.GetString("end_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			ra.HandleBadRequest(fmt.Sprintf("invalid end_timestamp: %s", timestamp))
+			return
+		}
+		query.EndTime = *t
+	}
+
+	projectName, _ := utils.ParseRepository(repoName)
+	exist, err := ra.ProjectMgr.Exists(projectName)
+	if err != nil {
+		ra.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %s",
+			projectName), err)
+		return
+	}
+
+	if !exist {
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if !ra.SecurityCtx.HasReadPerm(projectName) {
+		if !ra.SecurityCtx.IsAuthenticated() {
+			ra.HandleUnauthorized()
+			return
+		}
+
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return
+	}
+
+	total, err := dao.CountTagHistories(query)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to count the history of %s:%s: %v", repoName, query.Tag, err))
+		return
+	}
+	query.Page, query.Size = ra.GetPaginationParams()
+	histories, err := dao.ListTagHistories(query)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to list the history of %s:%s: %v", repoName, query.Tag, err))
+		return
+	}
+	ra.SetPaginationHeader(total, query.Page, query.Size)
+	ra.Data["json"] = histories
+	ra.ServeJSON()
+}
+
 // GetManifests returns the manifest of a tag
 func (ra *RepositoryAPI) GetManifests() {
 	repoName := ra.GetString(":splat")
@@ -737,7 +1096,7 @@ func (ra *RepositoryAPI) GetManifests() {
 	}
 
 	if !exist {
-		ra.HandleNotFound(fmt.Sprintf("project %s not found", projectName))
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
 		return
 	}
 
@@ -862,7 +1221,7 @@ func (ra *RepositoryAPI) Put() {
 	}
 
 	if repository == nil {
-		ra.HandleNotFound(fmt.Sprintf("repository %s not found", name))
+		ra.HandleNotFound(ra.T(i18n.MsgRepositoryNotFound, name))
 		return
 	}
 
@@ -902,7 +1261,7 @@ func (ra *RepositoryAPI) GetSignatures() {
 	}
 
 	if !exist {
-		ra.HandleNotFound(fmt.Sprintf("project %s not found", projectName))
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
 		return
 	}
 
@@ -942,7 +1301,7 @@ func (ra *RepositoryAPI) ScanImage() {
 		return
 	}
 	if !exist {
-		ra.HandleNotFound(fmt.Sprintf("project %s not found", projectName))
+		ra.HandleNotFound(ra.T(i18n.MsgProjectNotFound, projectName))
 		return
 	}
 	if !ra.SecurityCtx.IsAuthenticated() {
@@ -995,14 +1354,23 @@ func (ra *RepositoryAPI) VulnerabilityDetails() {
 		return
 	}
 	if overview != nil && len(overview.DetailsKey) > 0 {
-		clairClient := clair.NewClient(config.ClairEndpoint(), nil)
+		clairClient, err := scanner.ClairClient()
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to create the client of Clair: %v", err))
+			return
+		}
 		log.Debugf("The key for getting details: %s", overview.DetailsKey)
 		details, err := clairClient.GetResult(overview.DetailsKey)
 		if err != nil {
 			ra.HandleInternalServerError(fmt.Sprintf("Failed to get scan details from Clair, error: %v", err))
 			return
 		}
-		res = transformVulnerabilities(details)
+		cvssSource, err := config.CVSSSource()
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to get the source of CVSS: %v", err))
+			return
+		}
+		res = transformVulnerabilities(details, cvssSource)
 	}
 	ra.Data["json"] = res
 	ra.ServeJSON()
@@ -0,0 +1,128 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/badge"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the types of the badges of tags
+const (
+	badgeTypeScan      = "scan"
+	badgeTypeSignature = "signature"
+)
+
+// GetBadge returns the badge in SVG reflecting the severity of the last scan or the signed
+// status of the tag, the type is specified by the query parameter "type" and the default is
+// "scan". The badges of the tags in the public projects can be embedded without login
+func (ra *RepositoryAPI) GetBadge() {
+	repository := ra.GetString(":splat")
+	tag := ra.GetString(":tag")
+	badgeType := ra.GetString("type", badgeTypeScan)
+	if badgeType != badgeTypeScan && badgeType != badgeTypeSignature {
+		ra.HandleBadRequest(fmt.Sprintf("invalid badge type %s, it must be %s or %s", badgeType,
+			badgeTypeScan, badgeTypeSignature))
+		return
+	}
+	exist, digest, err := ra.checkExistence(repository, tag)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to check the existence of resource, error: %v", err))
+		return
+	}
+	if !exist {
+		ra.HandleNotFound(fmt.Sprintf("resource: %s:%s not found", repository, tag))
+		return
+	}
+	project, _ := utils.ParseRepository(repository)
+	if !ra.SecurityCtx.HasReadPerm(project) {
+		if !ra.SecurityCtx.IsAuthenticated() {
+			ra.HandleUnauthorized()
+			return
+		}
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return
+	}
+
+	var label, message, color string
+	if badgeType == badgeTypeScan {
+		label = "vulnerability"
+		message, color = scanBadge(digest, tag)
+	} else {
+		label = "signature"
+		message, color = ra.signatureBadge(repository, digest)
+	}
+
+	w := ra.Ctx.ResponseWriter
+	w.Header().Set(http.CanonicalHeaderKey("Content-Type"), "image/svg+xml")
+	// the badges reflect the live status, so they shouldn't be cached by the image proxies
+	w.Header().Set(http.CanonicalHeaderKey("Cache-Control"), "no-cache, no-store, must-revalidate")
+	if _, err = w.Write(badge.Render(label, message, color)); err != nil {
+		log.Errorf("failed to write the badge of %s:%s: %v", repository, tag, err)
+	}
+}
+
+// scanBadge returns the message and the color of the scan badge
+func scanBadge(digest, tag string) (string, string) {
+	if !config.WithClair() {
+		return "disabled", badge.ColorLightGrey
+	}
+	overview := getScanOverview(digest, tag)
+	if overview == nil {
+		return "not scanned", badge.ColorLightGrey
+	}
+	switch overview.Status {
+	case models.JobFinished:
+	case models.JobError, models.JobStopped:
+		return "scan " + overview.Status, badge.ColorRed
+	default:
+		return "scan " + overview.Status, badge.ColorBlue
+	}
+	severity := models.Severity(overview.Sev)
+	switch severity {
+	case models.SevNone:
+		return severity.String(), badge.ColorGreen
+	case models.SevLow:
+		return severity.String(), badge.ColorYellow
+	case models.SevMedium:
+		return severity.String(), badge.ColorOrange
+	case models.SevHigh:
+		return severity.String(), badge.ColorRed
+	default:
+		return severity.String(), badge.ColorLightGrey
+	}
+}
+
+// signatureBadge returns the message and the color of the signature badge
+func (ra *RepositoryAPI) signatureBadge(repository, digest string) (string, string) {
+	if !config.WithNotary() {
+		return "disabled", badge.ColorLightGrey
+	}
+	signatures, err := getSignatures(ra.SecurityCtx.GetUsername(), repository)
+	if err != nil {
+		log.Errorf("failed to get signatures of %s: %v", repository, err)
+		return "unknown", badge.ColorLightGrey
+	}
+	if _, ok := signatures[digest]; ok {
+		return "signed", badge.ColorGreen
+	}
+	return "unsigned", badge.ColorOrange
+}
@@ -0,0 +1,167 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/repoexport"
+)
+
+// RepositoryExportAPI handles request to /api/projects/{}/repository_exports
+type RepositoryExportAPI struct {
+	BaseController
+	project *models.Project
+	export  *models.RepositoryExport
+}
+
+// Prepare validates the user and the project, the users who can list the repositories of
+// the project are allowed to export them
+func (r *RepositoryExportAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	pid, err := r.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		r.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", r.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := r.ProjectMgr.Get(pid)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	r.project = project
+
+	if !r.SecurityCtx.HasReadPerm(pid) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(r.GetStringFromPath(":id")) > 0 {
+		id, err := r.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			r.HandleBadRequest(fmt.Sprintf("invalid export ID: %s", r.GetStringFromPath(":id")))
+			return
+		}
+		export, err := dao.GetRepositoryExport(id)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get the repository export %d: %v", id, err))
+			return
+		}
+		if export == nil || export.ProjectID != pid {
+			r.HandleNotFound(fmt.Sprintf("repository export %d not found", id))
+			return
+		}
+		r.export = export
+	}
+}
+
+// Post starts to export the repositories of the project in background
+func (r *RepositoryExportAPI) Post() {
+	req := &models.RepositoryExportRequest{}
+	r.DecodeJSONReq(req)
+	if len(req.Format) == 0 {
+		req.Format = models.RepositoryExportCSV
+	}
+	if !repoexport.ValidFormat(req.Format) {
+		r.HandleBadRequest(fmt.Sprintf("invalid format %s, only %s and %s are supported",
+			req.Format, models.RepositoryExportCSV, models.RepositoryExportJSON))
+		return
+	}
+
+	id, err := repoexport.Export(r.project, req.Format, r.SecurityCtx.GetUsername())
+	if err != nil {
+		if err == repoexport.ErrExportRunning {
+			r.HandleConflict(err.Error())
+			return
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to export the repositories: %v", err))
+		return
+	}
+	r.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the repository exports of the project
+func (r *RepositoryExportAPI) List() {
+	query := &models.RepositoryExportQuery{
+		ProjectID: r.project.ProjectID,
+		Status:    r.GetString("status"),
+	}
+	total, err := dao.CountRepositoryExports(query)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to count the repository exports: %v", err))
+		return
+	}
+	query.Page, query.Size = r.GetPaginationParams()
+	exports, err := dao.ListRepositoryExports(query)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list the repository exports: %v", err))
+		return
+	}
+
+	r.SetPaginationHeader(total, query.Page, query.Size)
+	r.Data["json"] = exports
+	r.ServeJSON()
+}
+
+// Get gets the repository export specified by ID
+func (r *RepositoryExportAPI) Get() {
+	r.Data["json"] = r.export
+	r.ServeJSON()
+}
+
+// Download downloads the dump of the repository export
+func (r *RepositoryExportAPI) Download() {
+	if r.export.Status != models.RepositoryExportSucceeded {
+		r.HandleStatusPreconditionFailed(fmt.Sprintf("the repository export %d is %s", r.export.ID, r.export.Status))
+		return
+	}
+	contentType := "text/csv"
+	if r.export.Format == models.RepositoryExportJSON {
+		contentType = "application/json"
+	}
+	filename := fmt.Sprintf("repositories-%s-%d.%s", r.project.Name, r.export.ID, r.export.Format)
+	r.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), contentType)
+	r.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Disposition"), "attachment; filename="+filename)
+	http.ServeFile(r.Ctx.ResponseWriter, r.Ctx.Request, repoexport.Path(r.export.ID, r.export.Format))
+}
+
+// Delete deletes the repository export and its dump, only the creator and the project
+// admin are allowed to delete it
+func (r *RepositoryExportAPI) Delete() {
+	if r.export.Creator != r.SecurityCtx.GetUsername() && !r.SecurityCtx.HasAllPerm(r.project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	if r.export.Status == models.RepositoryExportRunning {
+		r.HandleConflict(fmt.Sprintf("the repository export %d is running", r.export.ID))
+		return
+	}
+	if err := repoexport.Delete(r.export); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete the repository export %d: %v", r.export.ID, err))
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/require"
+)
+
+var repositoryExportPath = "/api/projects/1/repository_exports"
+
+func TestRepositoryExportAPI(t *testing.T) {
+	id, err := dao.AddRepositoryExport(&models.RepositoryExport{
+		ProjectID: 1,
+		Format:    models.RepositoryExportCSV,
+		Status:    models.RepositoryExportRunning,
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteRepositoryExport(id)
+	exportPath := fmt.Sprintf("%s/%d", repositoryExportPath, id)
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    repositoryExportPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, project not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/repository_exports",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, invalid format
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    repositoryExportPath,
+				bodyJSON: &models.RepositoryExportRequest{
+					Format: "xml",
+				},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200, the repositories of the public project can be exported by any user
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        repositoryExportPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        exportPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404, export not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        repositoryExportPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 412, the export is running
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        exportPath + "/download",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		// 403, neither the creator nor the project admin
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        exportPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 409, the export is running
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        exportPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
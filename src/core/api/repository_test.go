@@ -16,7 +16,9 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/dao/project"
@@ -470,3 +472,278 @@ func TestRetag(t *testing.T) {
 
 	fmt.Printf("\n")
 }
+
+func TestRenameRepository(t *testing.T) {
+	path := "/api/repositories/library/hello-world/rename"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "library/hello-world-renamed",
+				},
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400, invalid name
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "hello-world",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the same name
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "library/hello-world",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, project not found
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    "/api/repositories/nonexist/hello-world/rename",
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "library/hello-world-renamed",
+				},
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, the developer has no permission to delete the repository
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "library/hello-world-renamed",
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404, target project not found
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    path,
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "nonexist/hello-world",
+				},
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, repository not found
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    "/api/repositories/library/nonexist/rename",
+				bodyJSON: &models.RepoRenameRequest{
+					Name: "library/hello-world-renamed",
+				},
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
+
+func TestGetTagHistory(t *testing.T) {
+	repository := "library/tag-history-api-test"
+	historyPath := "/api/repositories/" + repository + "/tags/latest/history"
+	now := time.Now()
+	for _, h := range []*models.TagHistory{
+		{Digest: "sha256:1", Operation: models.TagHistoryCreate, OpTime: now.Add(-2 * time.Hour)},
+		{Digest: "sha256:2", Operation: models.TagHistoryUpdate, OpTime: now.Add(-time.Hour)},
+	} {
+		h.Repository = repository
+		h.Tag = "latest"
+		h.Operator = "admin"
+		_, err := dao.AddTagHistory(h)
+		require.Nil(t, err)
+	}
+	defer dao.GetOrmer().QueryTable(&models.TagHistory{}).Filter("Repository", repository).Delete()
+
+	cases := []*codeCheckingCase{
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/repositories/not-exist/hello-world/tags/latest/history",
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    historyPath,
+				queryStruct: struct {
+					EndTimestamp string `url:"end_timestamp"`
+				}{
+					EndTimestamp: "yesterday",
+				},
+				credential: admin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	histories := []*models.TagHistory{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        historyPath,
+		credential: admin,
+	}, &histories)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(histories))
+	assert.Equal(t, "sha256:2", histories[0].Digest)
+
+	// the digest which the tag pointed to 90 minutes ago
+	histories = []*models.TagHistory{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    historyPath,
+		queryStruct: struct {
+			EndTimestamp int64 `url:"end_timestamp"`
+			PageSize     int   `url:"page_size"`
+		}{
+			EndTimestamp: now.Add(-90 * time.Minute).Unix(),
+			PageSize:     1,
+		},
+		credential: admin,
+	}, &histories)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(histories))
+	assert.Equal(t, "sha256:1", histories[0].Digest)
+}
+
+func TestHeadTag(t *testing.T) {
+	repository := "library/head-tag-test"
+	dgt := "sha256:" + strings.Repeat("a", 64)
+	_, err := dao.AddTagHistory(&models.TagHistory{
+		Repository: repository,
+		Tag:        "1.0",
+		Digest:     dgt,
+		Operation:  models.TagHistoryCreate,
+		Operator:   "admin",
+	})
+	require.Nil(t, err)
+	defer dao.GetOrmer().QueryTable(&models.TagHistory{}).Filter("Repository", repository).Delete()
+
+	cases := []*codeCheckingCase{
+		// 403, the project doesn't exist
+		{
+			request: &testingRequest{
+				method:     http.MethodHead,
+				url:        "/api/repositories/not-exist/head-tag-test/tags/1.0",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodHead,
+				url:        "/api/repositories/" + repository + "/tags/2.0",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, the digest isn't tagged
+		{
+			request: &testingRequest{
+				method:     http.MethodHead,
+				url:        "/api/repositories/" + repository + "/tags/sha256:" + strings.Repeat("b", 64),
+				credential: nonSysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200, the project is public
+		{
+			request: &testingRequest{
+				method: http.MethodHead,
+				url:    "/api/repositories/" + repository + "/tags/" + dgt,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	resp, err := handle(&testingRequest{
+		method:     http.MethodHead,
+		url:        "/api/repositories/" + repository + "/tags/1.0",
+		credential: nonSysAdmin,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, dgt, resp.Header().Get("Docker-Content-Digest"))
+
+	// the tag without history is checked in the registry
+	resp, err = handle(&testingRequest{
+		method:     http.MethodHead,
+		url:        "/api/repositories/library/hello-world/tags/latest",
+		credential: nonSysAdmin,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, strings.HasPrefix(resp.Header().Get("Docker-Content-Digest"), "sha256:"))
+}
+
+func TestGetBadge(t *testing.T) {
+	badgePath := "/api/repositories/library/hello-world/tags/latest/badge.svg"
+	cases := []*codeCheckingCase{
+		// 404
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/repositories/library/hello-world/tags/not-exist/badge.svg",
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, invalid type
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    badgePath,
+				queryStruct: struct {
+					Type string `url:"type"`
+				}{"license"},
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the project "library" is public, so the badges can be got without login
+	for _, badgeType := range []string{"scan", "signature"} {
+		resp, err := handle(&testingRequest{
+			method: http.MethodGet,
+			url:    badgePath,
+			queryStruct: struct {
+				Type string `url:"type"`
+			}{badgeType},
+		})
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "image/svg+xml", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Body.String(), "<svg")
+	}
+}
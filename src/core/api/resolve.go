@@ -0,0 +1,117 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// ResolveAPI handles the request to /api/resolve
+type ResolveAPI struct {
+	BaseController
+}
+
+type resolvedReference struct {
+	Reference  string `json:"reference"`
+	Project    string `json:"project"`
+	ProjectID  int64  `json:"project_id,omitempty"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	// the reference by digest with the host of Harbor, by tag if the digest isn't resolved
+	Canonical        string `json:"canonical"`
+	ProjectExists    bool   `json:"project_exists"`
+	RepositoryExists bool   `json:"repository_exists"`
+	Exists           bool   `json:"exists"`
+	CanPull          bool   `json:"can_pull"`
+	CanPush          bool   `json:"can_push"`
+}
+
+// Get resolves the reference of the image specified by the query parameter "ref" in any form
+// accepted by the docker client into the canonical one, with the existence and the permissions
+// of the current user. The repository and the digest are only resolved when the user can pull
+func (r *ResolveAPI) Get() {
+	ref := r.GetString("ref")
+	if len(ref) == 0 {
+		r.HandleBadRequest("empty ref")
+		return
+	}
+	host, err := config.ExtURL()
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the external URL: %v", err))
+		return
+	}
+	defaultProject, err := config.ShortNameProject()
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the project of short names: %v", err))
+		return
+	}
+	img, err := utils.ParseImageReference(ref, host, defaultProject)
+	if err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+
+	resolved := &resolvedReference{
+		Reference:  ref,
+		Project:    img.Project,
+		Repository: img.Repository,
+		Tag:        img.Tag,
+		Digest:     img.Digest,
+	}
+	project, err := r.ProjectMgr.Get(img.Project)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %s", img.Project), err)
+		return
+	}
+	if project != nil {
+		resolved.ProjectID = project.ProjectID
+		resolved.ProjectExists = true
+		resolved.CanPull = r.SecurityCtx.HasReadPerm(project.ProjectID)
+		resolved.CanPush = r.SecurityCtx.HasWritePerm(project.ProjectID)
+	}
+	if resolved.CanPull && dao.RepositoryExists(img.Repository) {
+		resolved.RepositoryExists = true
+		client, err := coreutils.NewRepositoryClientForUI(r.SecurityCtx.GetUsername(), img.Repository)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to initialize the client for %s: %v", img.Repository, err))
+			return
+		}
+		reference := img.Digest
+		if len(reference) == 0 {
+			reference = img.Tag
+		}
+		digest, exist, err := client.ManifestExist(reference)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s:%s: %v", img.Repository, reference, err))
+			return
+		}
+		if exist {
+			resolved.Exists = true
+			resolved.Digest = digest
+		}
+	}
+	if len(resolved.Digest) > 0 {
+		resolved.Canonical = fmt.Sprintf("%s/%s@%s", host, img.Repository, resolved.Digest)
+	} else {
+		resolved.Canonical = fmt.Sprintf("%s/%s:%s", host, img.Repository, img.Tag)
+	}
+	r.WriteJSONData(resolved)
+}
@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 400, empty ref
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/resolve",
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid ref
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/resolve",
+				queryStruct: struct {
+					Ref string `url:"ref"`
+				}{
+					Ref: "library/App:1.0",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, another registry
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/resolve",
+				queryStruct: struct {
+					Ref string `url:"ref"`
+				}{
+					Ref: "docker.io/library/app:1.0",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the project doesn't exist
+	resolved := &resolvedReference{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/resolve",
+		queryStruct: struct {
+			Ref string `url:"ref"`
+		}{
+			Ref: "non-exist-project/app:1.0",
+		},
+		credential: projDeveloper,
+	}, resolved))
+	assert.Equal(t, "non-exist-project/app", resolved.Repository)
+	assert.False(t, resolved.ProjectExists)
+	assert.False(t, resolved.CanPull)
+
+	// the short name is resolved into the default project
+	resolved = &resolvedReference{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/resolve",
+		queryStruct: struct {
+			Ref string `url:"ref"`
+		}{
+			Ref: "non-exist-repository",
+		},
+		credential: projDeveloper,
+	}, resolved))
+	assert.Equal(t, "library", resolved.Project)
+	assert.Equal(t, "library/non-exist-repository", resolved.Repository)
+	assert.Equal(t, "latest", resolved.Tag)
+	assert.True(t, resolved.ProjectExists)
+	assert.True(t, resolved.CanPull)
+	assert.True(t, resolved.CanPush)
+	assert.False(t, resolved.RepositoryExists)
+	assert.False(t, resolved.Exists)
+}
@@ -15,13 +15,21 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/common/token"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/scopeusage"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // RobotAPI ...
@@ -104,6 +112,10 @@ func (r *RobotAPI) Post() {
 		Description: robotReq.Description,
 		ProjectID:   r.project.ProjectID,
 	}
+	if err := robot.AddAccess(robotReq.Access); err != nil {
+		r.HandleBadRequest(fmt.Sprintf("invalid access: %v", err))
+		return
+	}
 	id, err := dao.AddRobot(&robot)
 	if err != nil {
 		if err == dao.ErrDupRows {
@@ -116,19 +128,9 @@ func (r *RobotAPI) Post() {
 
 	// generate the token, and return it with response data.
 	// token is not stored in the database.
-	jwtToken, err := token.New(id, r.project.ProjectID, robotReq.Access)
+	rawTk, err := robotToken(id, r.project.ProjectID, robotReq.Access)
 	if err != nil {
-		r.HandleInternalServerError(fmt.Sprintf("failed to valid parameters to generate token for robot account, %v", err))
-		err := dao.DeleteRobot(id)
-		if err != nil {
-			r.HandleInternalServerError(fmt.Sprintf("failed to delete the robot account: %d, %v", id, err))
-		}
-		return
-	}
-
-	rawTk, err := jwtToken.Raw()
-	if err != nil {
-		r.HandleInternalServerError(fmt.Sprintf("failed to sign token for robot account, %v", err))
+		r.HandleInternalServerError(fmt.Sprintf("failed to generate token for robot account, %v", err))
 		err := dao.DeleteRobot(id)
 		if err != nil {
 			r.HandleInternalServerError(fmt.Sprintf("failed to delete the robot account: %d, %v", id, err))
@@ -147,8 +149,20 @@ func (r *RobotAPI) Post() {
 
 // List list all the robots of a project
 func (r *RobotAPI) List() {
+	name, exact, err := r.GetNameQuery()
+	if err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
 	query := models.RobotQuery{
-		ProjectID: r.project.ProjectID,
+		ProjectID:      r.project.ProjectID,
+		FuzzyMatchName: !exact,
+	}
+	if len(name) > 0 {
+		query.Name = name
+		if exact {
+			query.Name = robotFullName(name)
+		}
 	}
 
 	count, err := dao.CountRobot(&query)
@@ -191,6 +205,35 @@ func (r *RobotAPI) Get() {
 	r.ServeJSON()
 }
 
+// GetByName gets the robot of the project by name, the name can be with or without the
+// "robot$" prefix
+func (r *RobotAPI) GetByName() {
+	name := robotFullName(r.GetStringFromPath(":name"))
+	robots, err := dao.ListRobots(&models.RobotQuery{
+		Name:      name,
+		ProjectID: r.project.ProjectID,
+	})
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robot %s: %v", name, err))
+		return
+	}
+	if len(robots) == 0 {
+		r.HandleNotFound(fmt.Sprintf("robot %s not found", name))
+		return
+	}
+
+	r.Data["json"] = robots[0]
+	r.ServeJSON()
+}
+
+// robotFullName returns the name of the robot account with the "robot$" prefix
+func robotFullName(name string) string {
+	if strings.HasPrefix(name, common.RobotPrefix) {
+		return name
+	}
+	return common.RobotPrefix + name
+}
+
 // Put disable or enable a robot account
 func (r *RobotAPI) Put() {
 	var robotReq models.RobotReq
@@ -211,3 +254,312 @@ func (r *RobotAPI) Delete() {
 		return
 	}
 }
+
+// ScopeUsage reports the repositories the robot pulled and pushed in the time range versus
+// the access granted to it, only the project admin is allowed
+func (r *RobotAPI) ScopeUsage() {
+	if !r.SecurityCtx.HasAllPerm(r.project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	id, err := r.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		r.HandleBadRequest(fmt.Sprintf("invalid robot ID: %s", r.GetStringFromPath(":id")))
+		return
+	}
+	begin, end, err := r.GetTimeRangeQuery()
+	if err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+	robot, err := dao.GetRobotByID(id)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robot %d: %v", id, err))
+		return
+	}
+	if robot == nil || robot.ProjectID != r.project.ProjectID {
+		r.HandleNotFound(fmt.Sprintf("robot %d not found", id))
+		return
+	}
+	usage, err := scopeusage.ForRobot(robot, begin, end)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the scope usage of robot %d: %v", id, err))
+		return
+	}
+	r.Data["json"] = usage
+	r.ServeJSON()
+}
+
+// PermissionReview compares the access granted to the robot with the repositories it pulled
+// and pushed in the time range and suggests the minimal access, only the project admin is allowed
+func (r *RobotAPI) PermissionReview() {
+	review := r.reviewRobot()
+	if review == nil {
+		return
+	}
+	r.Data["json"] = review
+	r.ServeJSON()
+}
+
+// ApplyPermissionReview reviews the robot as PermissionReview and downgrades the access of the
+// robot to the suggested one. The tokens issued before are restricted to the suggested access
+// as well, so the robot needn't be re-configured
+func (r *RobotAPI) ApplyPermissionReview() {
+	review := r.reviewRobot()
+	if review == nil {
+		return
+	}
+	if !review.GrantKnown {
+		r.HandleBadRequest(fmt.Sprintf("the access of robot %d is unknown as it's created before the access is recorded", review.RobotID))
+		return
+	}
+	if review.Downgrade {
+		data, err := json.Marshal(review.SuggestedAccess)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to marshal the suggested access of robot %d: %v", review.RobotID, err))
+			return
+		}
+		if err = dao.UpdateRobotAccess(review.RobotID, string(data)); err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to update the access of robot %d: %v", review.RobotID, err))
+			return
+		}
+		review.Applied = true
+		log.Infof("the access of robot %s is downgraded by %s: %s", review.RobotName, r.SecurityCtx.GetUsername(), string(data))
+	}
+	r.Data["json"] = review
+	r.ServeJSON()
+}
+
+// reviewRobot returns the permission review of the robot in the path, nil is returned if
+// the request has been handled with an error
+func (r *RobotAPI) reviewRobot() *models.RobotPermissionReview {
+	if !r.SecurityCtx.HasAllPerm(r.project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return nil
+	}
+	id, err := r.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		r.HandleBadRequest(fmt.Sprintf("invalid robot ID: %s", r.GetStringFromPath(":id")))
+		return nil
+	}
+	begin, end, err := r.GetTimeRangeQuery()
+	if err != nil {
+		r.HandleBadRequest(err.Error())
+		return nil
+	}
+	robot, err := dao.GetRobotByID(id)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robot %d: %v", id, err))
+		return nil
+	}
+	if robot == nil || robot.ProjectID != r.project.ProjectID {
+		r.HandleNotFound(fmt.Sprintf("robot %d not found", id))
+		return nil
+	}
+	review, err := scopeusage.ReviewRobot(robot, begin, end)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to review the permissions of robot %d: %v", id, err))
+		return nil
+	}
+	return review
+}
+
+// Replicas returns the tokens of the robot re-issued by the replication targets, they're the
+// credentials of the robot after failing over to the targets, only the project admin is allowed
+func (r *RobotAPI) Replicas() {
+	if !r.SecurityCtx.HasAllPerm(r.project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+	id, err := r.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		r.HandleBadRequest(fmt.Sprintf("invalid robot ID: %s", r.GetStringFromPath(":id")))
+		return
+	}
+	robot, err := dao.GetRobotByID(id)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robot %d: %v", id, err))
+		return
+	}
+	if robot == nil || robot.ProjectID != r.project.ProjectID {
+		r.HandleNotFound(fmt.Sprintf("robot %d not found", id))
+		return
+	}
+	replicas, err := dao.ListRobotReplicas(id)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list the replicas of robot %d: %v", id, err))
+		return
+	}
+	if len(replicas) > 0 {
+		key, err := config.SecretKey()
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get the secret key: %v", err))
+			return
+		}
+		for _, replica := range replicas {
+			if replica.Token, err = keyring.Decrypt(replica.Token, key); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to decrypt the token of robot %d re-issued by target %d: %v",
+					id, replica.TargetID, err))
+				return
+			}
+		}
+	}
+	r.Data["json"] = replicas
+	r.ServeJSON()
+}
+
+// kubeSecret is the Kubernetes secret holding the docker config to pull the images
+type kubeSecret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   kubeSecretMeta    `json:"metadata"`
+	Type       string            `json:"type"`
+	Data       map[string][]byte `json:"data"`
+}
+
+type kubeSecretMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PullSecret generates the Kubernetes image pull secret of the project bound to an existing
+// robot or a new one, the token of the robot in the secret can only pull the images of the
+// project. The secret is in YAML by default and in JSON if the format is "json"
+func (r *RobotAPI) PullSecret() {
+	var req models.PullSecretReq
+	r.DecodeJSONReqAndValidate(&req)
+
+	format := r.GetString("format", "yaml")
+	if format != "yaml" && format != "json" {
+		r.HandleBadRequest(fmt.Sprintf("invalid format: %s", format))
+		return
+	}
+
+	registry, err := config.ExtURL()
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the external URL: %v", err))
+		return
+	}
+
+	// the resources of the access are in both ID and name of the project like the UI does
+	access := []*rbac.Policy{}
+	for _, identity := range []interface{}{r.project.ProjectID, r.project.Name} {
+		access = append(access, &rbac.Policy{
+			Resource: rbac.NewProjectNamespace(identity, false).Resource(rbac.ResourceRepository),
+			Action:   rbac.ActionPull,
+		})
+	}
+
+	var robot *models.Robot
+	if req.RobotID > 0 {
+		robot, err = dao.GetRobotByID(req.RobotID)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get robot %d: %v", req.RobotID, err))
+			return
+		}
+		if robot == nil || robot.ProjectID != r.project.ProjectID {
+			r.HandleNotFound(fmt.Sprintf("robot %d not found", req.RobotID))
+			return
+		}
+		if robot.Disabled {
+			r.HandleBadRequest(fmt.Sprintf("robot %d is disabled", req.RobotID))
+			return
+		}
+		// the access granted before is unknown if it isn't recorded, keep it unknown
+		// rather than recording the access of this token only
+		if len(robot.Access) > 0 {
+			if err = robot.AddAccess(access); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to add the access of robot %d: %v", req.RobotID, err))
+				return
+			}
+			if err = dao.UpdateRobotAccess(robot.ID, robot.Access); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to update the access of robot %d: %v", req.RobotID, err))
+				return
+			}
+		}
+	} else {
+		robot = &models.Robot{
+			Name:        common.RobotPrefix + req.RobotName,
+			Description: "pull secret for Kubernetes",
+			ProjectID:   r.project.ProjectID,
+		}
+		if err = robot.AddAccess(access); err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to add the access of the robot: %v", err))
+			return
+		}
+		id, err := dao.AddRobot(robot)
+		if err != nil {
+			if err == dao.ErrDupRows {
+				r.HandleConflict()
+				return
+			}
+			r.HandleInternalServerError(fmt.Sprintf("failed to create robot account: %v", err))
+			return
+		}
+		robot.ID = id
+	}
+
+	rawTk, err := robotToken(robot.ID, r.project.ProjectID, access)
+	if err != nil {
+		// the robot created for the secret is useless without the token
+		if req.RobotID == 0 {
+			if err := dao.DeleteRobot(robot.ID); err != nil {
+				log.Errorf("failed to delete the robot account %d: %v", robot.ID, err)
+			}
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to generate token for robot account: %v", err))
+		return
+	}
+
+	secret, err := newPullSecret(req.SecretName, req.Namespace, r.project.Name, registry, robot.Name, rawTk)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to generate the pull secret: %v", err))
+		return
+	}
+	if format == "json" {
+		r.WriteJSONData(secret)
+		return
+	}
+	r.WriteYamlData(secret)
+}
+
+// robotToken generates the signed token of the robot
+func robotToken(id, projectID int64, access []*rbac.Policy) (string, error) {
+	jwtToken, err := token.New(id, projectID, access)
+	if err != nil {
+		return "", err
+	}
+	return jwtToken.Raw()
+}
+
+// newPullSecret returns the secret with the docker config, the name of the secret is
+// "<project>-pull-secret" if it isn't specified
+func newPullSecret(name, namespace, project, registry, username, password string) (*kubeSecret, error) {
+	if len(name) == 0 {
+		name = strings.Replace(project, "_", "-", -1) + "-pull-secret"
+	}
+	dockerConfig, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{
+				"username": username,
+				"password": password,
+				"auth":     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &kubeSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: kubeSecretMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: "kubernetes.io/dockerconfigjson",
+		Data: map[string][]byte{
+			".dockerconfigjson": dockerConfig,
+		},
+	}, nil
+}
@@ -0,0 +1,136 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// RobotReplicationAPI receives the robots replicated from the source Harbor for the disaster
+// recovery, the tokens are re-issued under the key of this instance
+type RobotReplicationAPI struct {
+	BaseController
+}
+
+// Prepare validates the user, it needs the system admin permission as the credentials of
+// the replication targets
+func (r *RobotReplicationAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+	if !r.SecurityCtx.IsSysAdmin() {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Post creates the robots which don't exist in the project and updates the existing ones with
+// the same names, the robots not in the request are removed if prune is true. The tokens are
+// re-issued for the new robots and the ones whose access changed, and only returned here
+func (r *RobotReplicationAPI) Post() {
+	req := &models.RobotReplicationReq{}
+	r.DecodeJSONReqAndValidate(req)
+
+	project, err := r.ProjectMgr.Get(req.Project)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %s", req.Project), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(fmt.Sprintf("project %s not found", req.Project))
+		return
+	}
+
+	robots, err := dao.ListRobots(&models.RobotQuery{ProjectID: project.ProjectID})
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list the robots of project %s: %v", project.Name, err))
+		return
+	}
+	existing := map[string]*models.Robot{}
+	for _, robot := range robots {
+		existing[robot.Name] = robot
+	}
+
+	tokens := []*models.RobotRep{}
+	for _, desired := range req.Robots {
+		name := common.RobotPrefix + desired.Name
+		data, err := json.Marshal(desired.Access)
+		if err != nil {
+			r.HandleBadRequest(fmt.Sprintf("invalid access of robot %s: %v", name, err))
+			return
+		}
+		access := string(data)
+
+		robot, exist := existing[name]
+		delete(existing, name)
+		if exist {
+			robot.Description = desired.Description
+			robot.Disabled = desired.Disabled
+			if err = dao.UpdateRobot(robot); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to update robot %s: %v", name, err))
+				return
+			}
+			// the access is in the claims of the token, keep the token if it isn't changed
+			if robot.Access == access {
+				continue
+			}
+			if err = dao.UpdateRobotAccess(robot.ID, access); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to update the access of robot %s: %v", name, err))
+				return
+			}
+		} else {
+			robot = &models.Robot{
+				Name:        name,
+				Description: desired.Description,
+				ProjectID:   project.ProjectID,
+				Disabled:    desired.Disabled,
+				Access:      access,
+			}
+			if robot.ID, err = dao.AddRobot(robot); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to create robot %s: %v", name, err))
+				return
+			}
+		}
+
+		rawTk, err := robotToken(robot.ID, project.ProjectID, desired.Access)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to generate token for robot %s: %v", name, err))
+			return
+		}
+		tokens = append(tokens, &models.RobotRep{
+			Name:  name,
+			Token: rawTk,
+		})
+	}
+
+	if req.Prune {
+		for name, robot := range existing {
+			if err = dao.DeleteRobot(robot.ID); err != nil {
+				r.HandleInternalServerError(fmt.Sprintf("failed to delete robot %s: %v", name, err))
+				return
+			}
+		}
+	}
+
+	r.Data["json"] = tokens
+	r.ServeJSON()
+}
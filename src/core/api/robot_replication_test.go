@@ -0,0 +1,154 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var robotReplicationPath = "/api/replication/robots"
+
+func TestRobotReplicationAPI(t *testing.T) {
+	projectID, err := dao.AddProject(models.Project{
+		Name:    "project_for_test_robot_replication",
+		OwnerID: 1,
+	})
+	require.Nil(t, err)
+	defer dao.DeleteProject(projectID)
+
+	pull := []*rbac.Policy{{Resource: "/project/project_for_test_robot_replication/repository", Action: rbac.ActionPull}}
+	push := []*rbac.Policy{{Resource: "/project/project_for_test_robot_replication/repository", Action: rbac.ActionPush}}
+	req := &models.RobotReplicationReq{
+		Project: "project_for_test_robot_replication",
+		Robots: []*models.RobotReq{
+			{Name: "replicated", Description: "ci", Access: pull},
+		},
+	}
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method:   http.MethodPost,
+				url:      robotReplicationPath,
+				bodyJSON: req,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        robotReplicationPath,
+				bodyJSON:   req,
+				credential: projAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, the access is required
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    robotReplicationPath,
+				bodyJSON: &models.RobotReplicationReq{
+					Project: "project_for_test_robot_replication",
+					Robots:  []*models.RobotReq{{Name: "replicated"}},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    robotReplicationPath,
+				bodyJSON: &models.RobotReplicationReq{
+					Project: "non_exist_project",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the token is issued for the new robot
+	tokens := []*models.RobotRep{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        robotReplicationPath,
+		bodyJSON:   req,
+		credential: sysAdmin,
+	}, &tokens))
+	require.Equal(t, 1, len(tokens))
+	assert.Equal(t, "robot$replicated", tokens[0].Name)
+	assert.NotEmpty(t, tokens[0].Token)
+	robots, err := dao.ListRobots(&models.RobotQuery{Name: "robot$replicated", ProjectID: projectID})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(robots))
+	defer dao.DeleteRobot(robots[0].ID)
+	assert.Equal(t, "ci", robots[0].Description)
+
+	// the token isn't re-issued if the access isn't changed
+	tokens = []*models.RobotRep{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        robotReplicationPath,
+		bodyJSON:   req,
+		credential: sysAdmin,
+	}, &tokens))
+	assert.Equal(t, 0, len(tokens))
+
+	// the token is re-issued as the access changed
+	req.Robots[0].Access = push
+	req.Robots[0].Disabled = true
+	tokens = []*models.RobotRep{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        robotReplicationPath,
+		bodyJSON:   req,
+		credential: sysAdmin,
+	}, &tokens))
+	assert.Equal(t, 1, len(tokens))
+	robot, err := dao.GetRobotByID(robots[0].ID)
+	require.Nil(t, err)
+	assert.True(t, robot.Disabled)
+	access, err := robot.GetAccess()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(access))
+	assert.Equal(t, rbac.ActionPush, access[0].Action)
+
+	// the robot is pruned
+	require.Nil(t, handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    robotReplicationPath,
+		bodyJSON: &models.RobotReplicationReq{
+			Project: "project_for_test_robot_replication",
+			Prune:   true,
+		},
+		credential: sysAdmin,
+	}, &tokens))
+	robot, err = dao.GetRobotByID(robots[0].ID)
+	require.Nil(t, err)
+	assert.Nil(t, robot)
+}
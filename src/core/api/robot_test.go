@@ -15,9 +15,15 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"net/http"
 	"testing"
 )
@@ -146,6 +152,69 @@ func TestRobotAPIGet(t *testing.T) {
 	runCodeCheckingCases(t, cases...)
 }
 
+func TestRobotAPIGetByName(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/by_name/%s", robotPath, "test"),
+			},
+			code: http.StatusUnauthorized,
+		},
+
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/by_name/%s", robotPath, "not_exist"),
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+
+		// 404, in another project
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("/api/projects/%d/robots/by_name/%s", 1000, "test"),
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusNotFound,
+		},
+
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/by_name/%s", robotPath, "test"),
+				credential: projDeveloper,
+			},
+			code: http.StatusOK,
+		},
+
+		// 200, with the prefix
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/by_name/%s", robotPath, common.RobotPrefix+"test"),
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	robot := &models.Robot{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        fmt.Sprintf("%s/by_name/%s", robotPath, "test"),
+		credential: projAdmin4Robot,
+	}, robot)
+	require.Nil(t, err)
+	assert.Equal(t, common.RobotPrefix+"test", robot.Name)
+}
+
 func TestRobotAPIList(t *testing.T) {
 	cases := []*codeCheckingCase{
 		// 401
@@ -186,8 +255,55 @@ func TestRobotAPIList(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+
+		// 400, invalid exact_match
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    robotPath,
+				queryStruct: struct {
+					ExactMatch string `url:"exact_match"`
+				}{
+					ExactMatch: "invalid",
+				},
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusBadRequest,
+		},
 	}
 	runCodeCheckingCases(t, cases...)
+
+	robots := []*models.Robot{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    robotPath,
+		queryStruct: struct {
+			Name       string `url:"name"`
+			ExactMatch bool   `url:"exact_match"`
+		}{
+			Name:       "tes",
+			ExactMatch: true,
+		},
+		credential: projAdmin4Robot,
+	}, &robots)
+	require.Nil(t, err)
+	assert.Len(t, robots, 0)
+
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    robotPath,
+		queryStruct: struct {
+			Name       string `url:"name"`
+			ExactMatch bool   `url:"exact_match"`
+		}{
+			Name:       "test",
+			ExactMatch: true,
+		},
+		credential: projAdmin4Robot,
+	}, &robots)
+	require.Nil(t, err)
+	require.Len(t, robots, 1)
+	assert.Equal(t, common.RobotPrefix+"test", robots[0].Name)
 }
 
 func TestRobotAPIPut(t *testing.T) {
@@ -322,3 +438,240 @@ func TestRobotAPIDelete(t *testing.T) {
 
 	runCodeCheckingCases(t, cases...)
 }
+
+func TestRobotAPIPullSecret(t *testing.T) {
+	path := robotPath + "/pull_secret"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 -- developer
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    path,
+				bodyJSON: &models.PullSecretReq{
+					RobotName: "pull-secret",
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, no robot
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				bodyJSON:   &models.PullSecretReq{},
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, invalid secret name
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    path,
+				bodyJSON: &models.PullSecretReq{
+					RobotName:  "pull-secret",
+					SecretName: "Pull_Secret",
+				},
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, robot not found
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    path,
+				bodyJSON: &models.PullSecretReq{
+					RobotID: 10000,
+				},
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	secret := &kubeSecret{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    path,
+		queryStruct: struct {
+			Format string `url:"format"`
+		}{
+			Format: "json",
+		},
+		bodyJSON: &models.PullSecretReq{
+			RobotName: "pull-secret",
+			Namespace: "default",
+		},
+		credential: projAdmin4Robot,
+	}, secret)
+	require.Nil(t, err)
+	robots, err := dao.ListRobots(&models.RobotQuery{
+		Name:      common.RobotPrefix + "pull-secret",
+		ProjectID: 1,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(robots))
+	defer dao.DeleteRobot(robots[0].ID)
+
+	assert.Equal(t, "library-pull-secret", secret.Metadata.Name)
+	assert.Equal(t, "default", secret.Metadata.Namespace)
+	assert.Equal(t, "kubernetes.io/dockerconfigjson", secret.Type)
+	assert.Contains(t, string(secret.Data[".dockerconfigjson"]), robots[0].Name)
+}
+
+func TestNewPullSecret(t *testing.T) {
+	secret, err := newPullSecret("", "", "my_project", "harbor.example.com", "robot$ci", "token")
+	require.Nil(t, err)
+	assert.Equal(t, "my-project-pull-secret", secret.Metadata.Name)
+
+	config := struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	require.Nil(t, json.Unmarshal(secret.Data[".dockerconfigjson"], &config))
+	auth := config.Auths["harbor.example.com"]
+	assert.Equal(t, "robot$ci", auth.Username)
+	assert.Equal(t, "token", auth.Password)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("robot$ci:token")), auth.Auth)
+}
+
+func TestRobotAPIScopeUsage(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d/scope_usage", robotPath, 1),
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d/scope_usage", robotPath, 1),
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid timestamp
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d/scope_usage", robotPath, 1),
+				queryStruct: struct {
+					BeginTimestamp string `url:"begin_timestamp"`
+				}{
+					BeginTimestamp: "yesterday",
+				},
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d/scope_usage", robotPath, 10000),
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
+
+func TestRobotAPIPermissionReview(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d/permission_review", robotPath, 1),
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/permission_review", robotPath, 1),
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid timestamp
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d/permission_review", robotPath, 1),
+				queryStruct: struct {
+					EndTimestamp string `url:"end_timestamp"`
+				}{
+					EndTimestamp: "today",
+				},
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/permission_review", robotPath, 10000),
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
+
+func TestRobotAPIReplicas(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d/replicas", robotPath, 1),
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d/replicas", robotPath, 1),
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d/replicas", robotPath, 10000),
+				credential: projAdmin4Robot,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
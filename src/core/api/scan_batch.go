@@ -0,0 +1,148 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// ScanBatchAPI handles request to /api/scan/batch, the batch expands the filters into the
+// scan jobs of the matched images on the server side and tracks them with its ID
+type ScanBatchAPI struct {
+	BaseController
+	batch *models.ScanBatch
+}
+
+// Prepare validates the user and the batch
+func (s *ScanBatchAPI) Prepare() {
+	s.BaseController.Prepare()
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	if len(s.GetStringFromPath(":id")) > 0 {
+		id, err := s.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			s.HandleBadRequest(fmt.Sprintf("invalid batch ID: %s", s.GetStringFromPath(":id")))
+			return
+		}
+		batch, err := dao.GetScanBatch(id)
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get batch %d: %v", id, err))
+			return
+		}
+		if batch == nil {
+			s.HandleNotFound(fmt.Sprintf("batch %d not found", id))
+			return
+		}
+		if !s.hasAllPerm(batch.ProjectID) {
+			s.HandleForbidden(s.SecurityCtx.GetUsername())
+			return
+		}
+		s.batch = batch
+	}
+}
+
+// hasAllPerm returns whether the user can scan the images of the project, the batches
+// across all the projects are only for the system admin
+func (s *ScanBatchAPI) hasAllPerm(projectID int64) bool {
+	if s.SecurityCtx.IsSysAdmin() {
+		return true
+	}
+	return projectID > 0 && s.SecurityCtx.HasAllPerm(projectID)
+}
+
+// Post creates a batch of scans, the scans are triggered in background
+func (s *ScanBatchAPI) Post() {
+	if !config.WithClair() {
+		log.Warningf("Harbor is not deployed with Clair, it's not possible to scan images.")
+		s.RenderError(http.StatusServiceUnavailable, "")
+		return
+	}
+	req := &models.ScanBatchReq{}
+	s.DecodeJSONReqAndValidate(req)
+	if !s.hasAllPerm(req.ProjectID) {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+	if req.ProjectID > 0 {
+		exist, err := s.ProjectMgr.Exists(req.ProjectID)
+		if err != nil {
+			s.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %d", req.ProjectID), err)
+			return
+		}
+		if !exist {
+			s.HandleNotFound(fmt.Sprintf("project %d not found", req.ProjectID))
+			return
+		}
+	}
+	if req.LabelID > 0 {
+		label, err := dao.GetLabel(req.LabelID)
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get label %d: %v", req.LabelID, err))
+			return
+		}
+		if label == nil {
+			s.HandleBadRequest(fmt.Sprintf("label %d not found", req.LabelID))
+			return
+		}
+		if label.Scope == common.LabelScopeProject && label.ProjectID != req.ProjectID {
+			s.HandleBadRequest(fmt.Sprintf("label %d doesn't belong to project %d", req.LabelID, req.ProjectID))
+			return
+		}
+	}
+
+	batch := &models.ScanBatch{
+		ProjectID:  req.ProjectID,
+		Repository: req.Repository,
+		Tag:        req.Tag,
+		LabelID:    req.LabelID,
+		Creator:    s.SecurityCtx.GetUsername(),
+		Status:     models.ScanBatchExpanding,
+	}
+	id, err := dao.AddScanBatch(batch)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to add the scan batch: %v", err))
+		return
+	}
+	batch.ID = id
+	go func() {
+		if err := coreutils.RunScanBatch(batch); err != nil {
+			log.Errorf("failed to run scan batch %d: %v", batch.ID, err)
+		}
+	}()
+	s.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// Get returns the batch with the count of its scan jobs grouped by the status
+func (s *ScanBatchAPI) Get() {
+	jobs, err := dao.CountScanJobsByBatch(s.batch.ID)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to count the scan jobs of batch %d: %v", s.batch.ID, err))
+		return
+	}
+	s.batch.Jobs = jobs
+	s.Data["json"] = s.batch
+	s.ServeJSON()
+}
@@ -0,0 +1,109 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scanBatchPath = "/api/scan/batch"
+
+func TestScanBatchAPI(t *testing.T) {
+	id, err := dao.AddScanBatch(&models.ScanBatch{
+		ProjectID: 1,
+		Tag:       "v1.*",
+		Creator:   "admin",
+	})
+	require.Nil(t, err)
+	defer dao.ClearTable(models.ScanBatchTable)
+	global, err := dao.AddScanBatch(&models.ScanBatch{
+		Creator: "admin",
+	})
+	require.Nil(t, err)
+
+	batchPath := fmt.Sprintf("%s/%d", scanBatchPath, id)
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    scanBatchPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    batchPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d", scanBatchPath, global+1),
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, not the project admin
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        batchPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 403, the batch across all the projects
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        fmt.Sprintf("%s/%d", scanBatchPath, global),
+				credential: projAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        batchPath,
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	batch := &models.ScanBatch{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        batchPath,
+		credential: sysAdmin,
+	}, batch)
+	require.Nil(t, err)
+	assert.Equal(t, "v1.*", batch.Tag)
+	assert.Equal(t, models.ScanBatchExpanding, batch.Status)
+}
@@ -0,0 +1,175 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	clairdao "github.com/goharbor/harbor/src/common/dao/clair"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/scanner"
+)
+
+// the time windows in which the error rates of scan jobs are calculated
+var scanErrorRateWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+}
+
+// ScannerAPI handles request to /api/scanners
+type ScannerAPI struct {
+	BaseController
+	scanner *scanner.Scanner
+}
+
+// Prepare validates the user and the scanner, only the system admin is allowed to
+// check the health and capabilities of scanner
+func (s *ScannerAPI) Prepare() {
+	s.BaseController.Prepare()
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	id := s.GetStringFromPath(":id")
+	if len(id) == 0 {
+		return
+	}
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+	s.scanner = scanner.Get(id)
+	if s.scanner == nil {
+		s.HandleNotFound(fmt.Sprintf("scanner %s not found", id))
+		return
+	}
+}
+
+// List lists the enabled scanners which can be chosen by the projects
+func (s *ScannerAPI) List() {
+	s.Data["json"] = scanner.List()
+	s.ServeJSON()
+}
+
+// Health returns the health status, the update time of the vulnerability database and
+// the recent error rates of the scan jobs, the status is "unhealthy" if the scanner
+// can't be reached
+func (s *ScannerAPI) Health() {
+	status := &models.ScannerHealth{
+		ErrorRates: map[string]*models.ScanErrorRate{},
+	}
+	checker := HTTPStatusCodeHealthChecker(http.MethodGet, config.GetClairHealthCheckServerURL()+"/health",
+		nil, 10*time.Second, http.StatusOK)
+	var isHealthy healthy = true
+	if err := checker.Check(); err != nil {
+		isHealthy = false
+		status.Error = err.Error()
+	}
+	status.Status = isHealthy.String()
+
+	last, err := clairdao.GetLastUpdate()
+	if err != nil {
+		log.Errorf("failed to get the last update of vulnerability database: %v", err)
+	} else if last > 0 {
+		t := time.Unix(last, 0).UTC()
+		status.VulnDBUpdateTime = &t
+	}
+
+	now := time.Now()
+	for name, window := range scanErrorRateWindows {
+		counts, err := dao.CountScanJobsByStatus(now.Add(-window))
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to count the scan jobs: %v", err))
+			return
+		}
+		rate := &models.ScanErrorRate{
+			Total:  counts[models.JobFinished] + counts[models.JobError] + counts[models.JobStopped],
+			Failed: counts[models.JobError],
+		}
+		if rate.Total > 0 {
+			rate.Rate = float64(rate.Failed) / float64(rate.Total)
+		}
+		status.ErrorRates[name] = rate
+	}
+	s.Data["json"] = status
+	s.ServeJSON()
+}
+
+// Capabilities returns the version of API, the supported media types and the
+// namespaces of the vulnerability data of the scanner
+func (s *ScannerAPI) Capabilities() {
+	capabilities := &models.ScannerCapabilities{
+		Name:              s.scanner.Name,
+		Vendor:            s.scanner.Vendor,
+		APIVersion:        "v1",
+		ManifestMimeTypes: []string{schema2.MediaTypeManifest},
+		LayerMimeTypes:    []string{schema2.MediaTypeLayer},
+		Namespaces:        []string{},
+	}
+	list, err := namespaces.get()
+	if err != nil {
+		log.Errorf("failed to get namespace list from Clair: %v", err)
+	}
+	capabilities.Namespaces = append(capabilities.Namespaces, list...)
+	sort.Strings(capabilities.Namespaces)
+	s.Data["json"] = capabilities
+	s.ServeJSON()
+}
+
+// GetCABundle returns the summaries of the certificates in the CA bundle of the scanner
+func (s *ScannerAPI) GetCABundle() {
+	bundle, err := scanner.CABundle(s.scanner.ID)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get the CA bundle of scanner %s: %v", s.scanner.ID, err))
+		return
+	}
+	certs, err := certificates(bundle)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to parse the CA bundle of scanner %s: %v", s.scanner.ID, err))
+		return
+	}
+	s.WriteJSONData(certs)
+}
+
+// PutCABundle replaces the CA bundle of the scanner, which is trusted by the clients of the
+// scanner in addition to the system ones
+func (s *ScannerAPI) PutCABundle() {
+	req := &models.CABundleReq{}
+	s.DecodeJSONReq(req)
+	if _, err := registry.ParseCABundle(req.CABundle); err != nil {
+		s.HandleBadRequest(err.Error())
+		return
+	}
+	if err := scanner.SetCABundle(s.scanner.ID, req.CABundle); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to update the CA bundle of scanner %s: %v", s.scanner.ID, err))
+		return
+	}
+}
+
+// DeleteCABundle removes the CA bundle of the scanner
+func (s *ScannerAPI) DeleteCABundle() {
+	if err := scanner.SetCABundle(s.scanner.ID, ""); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to delete the CA bundle of scanner %s: %v", s.scanner.ID, err))
+		return
+	}
+}
@@ -17,14 +17,16 @@ package api
 import (
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/group"
 	"github.com/goharbor/harbor/src/common/models"
-	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/security"
+	"github.com/goharbor/harbor/src/common/security/local"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/federation"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
 	"k8s.io/helm/cmd/helm/search"
 )
@@ -42,106 +44,150 @@ type searchResult struct {
 	Project    []*models.Project        `json:"project"`
 	Repository []map[string]interface{} `json:"repository"`
 	Chart      []*search.Result
+	Label      []*models.Label `json:"label"`
+	// the users are only searched for the authenticated users
+	User []*models.SearchUser `json:"user"`
+	// the tags whose CI metadata match the annotation filters, only set when the filters are specified
+	Tag []*models.ArtifactAnnotation `json:"tag,omitempty"`
+	// the repositories and tags whose descriptions and annotations match the keyword
+	// in the full-text search, ordered by the rank
+	Artifact []*models.SearchArtifact `json:"artifact"`
+	Count    *models.SearchCount      `json:"count"`
+	// the status of the search on each peer, only set when the search is federated
+	Peer []*models.PeerSearchStatus `json:"peer,omitempty"`
 }
 
 // Get ...
 func (s *SearchAPI) Get() {
-	keyword := s.GetString("q")
-	isAuthenticated := s.SecurityCtx.IsAuthenticated()
-	isSysAdmin := s.SecurityCtx.IsSysAdmin()
-
-	var projects []*models.Project
-	var err error
+	limit, err := s.GetInt64("limit", 0)
+	if err != nil || limit < 0 {
+		s.HandleBadRequest(fmt.Sprintf("invalid limit: %s", s.GetString("limit")))
+		return
+	}
+	federated, err := s.GetBool("federated", false)
+	if err != nil {
+		s.HandleBadRequest(fmt.Sprintf("invalid federated: %s", s.GetString("federated")))
+		return
+	}
+	// the peers are searched with their own credentials, so only the authenticated users can search them
+	if federated && !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	query, err := visibilityQuery(s.SecurityCtx, s.GetString("q"), limit)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get projects: %v", err))
+		return
+	}
 
-	if isSysAdmin {
-		result, err := s.ProjectMgr.List(nil)
-		if err != nil {
-			s.ParseAndHandleError("failed to get projects", err)
-			return
-		}
-		projects = result.Projects
-	} else {
-		projects, err = s.ProjectMgr.GetPublic()
-		if err != nil {
-			s.ParseAndHandleError("failed to get projects", err)
-			return
-		}
-		if isAuthenticated {
-			mys, err := s.SecurityCtx.GetMyProjects()
-			if err != nil {
-				s.HandleInternalServerError(fmt.Sprintf(
-					"failed to get projects: %v", err))
-				return
-			}
-			exist := map[int64]bool{}
-			for _, p := range projects {
-				exist[p.ProjectID] = true
-			}
-
-			for _, p := range mys {
-				if !exist[p.ProjectID] {
-					projects = append(projects, p)
-				}
-			}
+	result := &searchResult{
+		Count: &models.SearchCount{},
+	}
+	projects, total, err := dao.SearchProjects(query)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to search projects: %v", err))
+		return
+	}
+	for _, p := range projects {
+		if p.Role == common.RoleProjectAdmin || query.SysAdmin {
+			p.Togglable = true
 		}
 	}
+	result.Project = projects
+	result.Count.Project = total
 
-	projectResult := []*models.Project{}
-	proNames := []string{}
-	for _, p := range projects {
-		proNames = append(proNames, p.Name)
+	repositories, total, err := dao.SearchRepositories(query)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to search repositories: %v", err))
+		return
+	}
+	result.Repository, err = repositoryEntries(repositories)
+	if err != nil {
+		log.Errorf("failed to get tags of repositories: %v", err)
+		s.CustomAbort(http.StatusInternalServerError, "")
+	}
+	result.Count.Repository = total
 
-		if len(keyword) > 0 && !strings.Contains(p.Name, keyword) {
-			continue
+	if federated {
+		peers, err := federation.Peers()
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get the peers of federation: %v", err))
+			return
 		}
+		var repositories []map[string]interface{}
+		repositories, result.Peer = federation.Search(peers, query.Keyword, limit)
+		result.Repository = append(result.Repository, repositories...)
+		result.Count.Repository += int64(len(repositories))
+	}
 
-		if isAuthenticated {
-			roles := s.SecurityCtx.GetProjectRoles(p.ProjectID)
-			if len(roles) != 0 {
-				p.Role = roles[0]
-			}
+	if result.Label, result.Count.Label, err = dao.SearchLabels(query); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to search labels: %v", err))
+		return
+	}
 
-			if p.Role == common.RoleProjectAdmin || isSysAdmin {
-				p.Togglable = true
-			}
-		}
+	if result.Artifact, result.Count.Artifact, err = dao.SearchArtifacts(query); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to search artifacts: %v", err))
+		return
+	}
 
-		total, err := dao.GetTotalOfRepositories(&models.RepositoryQuery{
-			ProjectIDs: []int64{p.ProjectID},
-		})
-		if err != nil {
-			log.Errorf("failed to get total of repositories of project %d: %v", p.ProjectID, err)
-			s.CustomAbort(http.StatusInternalServerError, "")
+	result.User = []*models.SearchUser{}
+	if s.SecurityCtx.IsAuthenticated() && len(query.Keyword) > 0 {
+		if result.User, result.Count.User, err = dao.SearchUsers(query); err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to search users: %v", err))
+			return
 		}
+	}
 
-		p.RepoCount = total
-
-		projectResult = append(projectResult, p)
+	// the tags built from the specified revision or pipeline run
+	annotationQuery := &models.ArtifactAnnotationQuery{
+		Revision: s.GetString("revision"),
+		BuildURL: s.GetString("build_url"),
+		BuildID:  s.GetString("build_id"),
+	}
+	searchTag := len(annotationQuery.Revision) > 0 || len(annotationQuery.BuildURL) > 0 ||
+		len(annotationQuery.BuildID) > 0
+	searchChart := config.WithChartMuseum()
+	if !searchTag && !searchChart {
+		s.Data["json"] = result
+		s.ServeJSON()
+		return
 	}
 
-	repositoryResult, err := filterRepositories(projects, keyword)
+	proNames, err := dao.GetSearchProjectNames(query)
 	if err != nil {
-		log.Errorf("failed to filter repositories: %v", err)
-		s.CustomAbort(http.StatusInternalServerError, "")
+		s.HandleInternalServerError(fmt.Sprintf("failed to get projects: %v", err))
+		return
 	}
-
-	result := &searchResult{
-		Project:    projectResult,
-		Repository: repositoryResult,
+	if searchTag {
+		annotationQuery.ProjectNames = proNames
+		tags, err := dao.ListArtifactAnnotations(annotationQuery)
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to filter tags by annotations: %v", err))
+			return
+		}
+		result.Count.Tag = int64(len(tags))
+		if limit > 0 && int64(len(tags)) > limit {
+			tags = tags[:limit]
+		}
+		result.Tag = tags
 	}
 
 	// If enable chart repository
-	if config.WithChartMuseum() {
+	if searchChart {
 		if searchHandler == nil {
 			searchHandler = chartController.SearchChart
 		}
 
-		chartResults, err := searchHandler(keyword, proNames)
+		chartResults, err := searchHandler(query.Keyword, proNames)
 		if err != nil {
 			log.Errorf("failed to filter charts: %v", err)
 			s.CustomAbort(http.StatusInternalServerError, err.Error())
 		}
 
+		result.Count.Chart = int64(len(chartResults))
+		if limit > 0 && int64(len(chartResults)) > limit {
+			chartResults = chartResults[:limit]
+		}
 		result.Chart = chartResults
 	}
 
@@ -149,42 +195,44 @@ func (s *SearchAPI) Get() {
 	s.ServeJSON()
 }
 
-func filterRepositories(projects []*models.Project, keyword string) (
-	[]map[string]interface{}, error) {
-	result := []map[string]interface{}{}
-	if len(projects) == 0 {
-		return result, nil
+// visibilityQuery builds the query with the visibility of the user of the security context,
+// the visible projects are trimmed in the SQL rather than checked one by one
+func visibilityQuery(secCtx security.Context, keyword string, limit int64) (*models.SearchQuery, error) {
+	query := &models.SearchQuery{
+		Keyword:  keyword,
+		SysAdmin: secCtx.IsSysAdmin(),
+		Limit:    limit,
 	}
-
-	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
-		Name: keyword,
-	})
+	if query.SysAdmin || !secCtx.IsAuthenticated() {
+		return query, nil
+	}
+	if ctx, ok := secCtx.(*local.SecurityContext); ok {
+		query.Username = ctx.GetUsername()
+		query.GroupDNCondition = group.GetGroupDNQueryCondition(ctx.GetGroupList())
+		return query, nil
+	}
+	// the other kinds of users, e.g. the robot accounts, aren't the project members
+	mys, err := secCtx.GetMyProjects()
 	if err != nil {
 		return nil, err
 	}
-	if len(repositories) == 0 {
-		return result, nil
-	}
-
-	projectMap := map[string]*models.Project{}
-	for _, project := range projects {
-		projectMap[project.Name] = project
+	for _, p := range mys {
+		query.ProjectIDs = append(query.ProjectIDs, p.ProjectID)
 	}
+	return query, nil
+}
 
+func repositoryEntries(repositories []*models.SearchRepository) ([]map[string]interface{}, error) {
+	result := []map[string]interface{}{}
 	for _, repository := range repositories {
-		projectName, _ := utils.ParseRepository(repository.Name)
-		project, exist := projectMap[projectName]
-		if !exist {
-			continue
-		}
 		entry := make(map[string]interface{})
-		entry["repository_name"] = repository.Name
-		entry["project_name"] = project.Name
-		entry["project_id"] = project.ProjectID
-		entry["project_public"] = project.IsPublic()
+		entry["repository_name"] = repository.RepositoryName
+		entry["project_name"] = repository.ProjectName
+		entry["project_id"] = repository.ProjectID
+		entry["project_public"] = repository.ProjectPublic
 		entry["pull_count"] = repository.PullCount
 
-		tags, err := getTags(repository.Name)
+		tags, err := getTags(repository.RepositoryName)
 		if err != nil {
 			return nil, err
 		}
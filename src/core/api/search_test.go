@@ -118,6 +118,11 @@ func TestSearch(t *testing.T) {
 	require.Equal(t, 1, len(result.Repository))
 	assert.Equal(t, "search", result.Project[0].Name)
 	assert.Equal(t, "search/hello-world", result.Repository[0]["repository_name"].(string))
+	require.NotNil(t, result.Count)
+	assert.Equal(t, int64(1), result.Count.Project)
+	assert.Equal(t, int64(1), result.Count.Repository)
+	// the users aren't searched without login
+	assert.Equal(t, 0, len(result.User))
 
 	// search with user who is the member of the project
 	err = handleAndParse(&testingRequest{
@@ -133,6 +138,11 @@ func TestSearch(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, 2, len(result.Project))
 	require.Equal(t, 2, len(result.Repository))
+	assert.Equal(t, int64(2), result.Count.Project)
+	for _, project := range result.Project {
+		// the role is populated for the member
+		assert.Equal(t, models.GUEST, project.Role)
+	}
 	projects := map[string]struct{}{}
 	repositories := map[string]struct{}{}
 	for _, project := range result.Project {
@@ -182,6 +192,73 @@ func TestSearch(t *testing.T) {
 	_, exist = repositories["search-2/hello-world"]
 	assert.True(t, exist)
 
+	// limit the results of each section
+	result = &searchResult{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/search",
+		queryStruct: struct {
+			Keyword string `url:"q"`
+			Limit   int    `url:"limit"`
+		}{
+			Keyword: "search",
+			Limit:   1,
+		},
+		credential: sysAdmin,
+	}, result)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(result.Project))
+	assert.Equal(t, 1, len(result.Repository))
+	assert.Equal(t, int64(2), result.Count.Project)
+	assert.Equal(t, int64(2), result.Count.Repository)
+
+	resp, err := handle(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/search?q=search&limit=-1",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// search the tags by the annotations
+	err = dao.SetArtifactAnnotation(&models.ArtifactAnnotation{
+		Repository: "search-2/hello-world",
+		Tag:        "latest",
+		Digest:     "sha256:0a1b2c3d",
+		Revision:   "search-revision",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteArtifactAnnotation("search-2/hello-world", "latest")
+
+	// the private project isn't accessible without login
+	result = &searchResult{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/search",
+		queryStruct: struct {
+			Revision string `url:"revision"`
+		}{
+			Revision: "search-revision",
+		},
+	}, result)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(result.Tag))
+
+	result = &searchResult{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/search",
+		queryStruct: struct {
+			Revision string `url:"revision"`
+		}{
+			Revision: "search-revision",
+		},
+		credential: nonSysAdmin,
+	}, result)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result.Tag))
+	assert.Equal(t, "search-2/hello-world", result.Tag[0].Repository)
+	assert.Equal(t, "latest", result.Tag[0].Tag)
+
 	currentAdminServerURL, ok := os.LookupEnv("ADMINSERVER_URL")
 	if ok {
 		chartSettings := map[string]interface{}{
@@ -0,0 +1,133 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	job_models "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
+)
+
+// SecretReencryptionAPI re-encrypts the secrets stored in database with the primary master key,
+// it's triggered after the master keys are rotated so that the old keys can be retired
+type SecretReencryptionAPI struct {
+	BaseController
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (s *SecretReencryptionAPI) Prepare() {
+	s.BaseController.Prepare()
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Post triggers the re-encryption job in jobservice, only one can run at the same time
+func (s *SecretReencryptionAPI) Post() {
+	for _, status := range []string{models.JobPending, models.JobRunning} {
+		jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+			Name:   common_job.SecretReencryption,
+			Status: status,
+		})
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+			return
+		}
+		if len(jobs) > 0 {
+			s.HandleConflict(fmt.Sprintf("the re-encryption job %d is %s", jobs[0].ID, status))
+			return
+		}
+	}
+
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: common_job.SecretReencryption,
+		Kind: common_job.JobKindGeneric,
+	})
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to add admin job: %v", err))
+		return
+	}
+	uuid, err := utils_core.GetJobServiceClient().SubmitJob(&job_models.JobData{
+		Name: common_job.SecretReencryption,
+		Metadata: &job_models.JobMetadata{
+			JobKind:  common_job.JobKindGeneric,
+			IsUnique: true,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d",
+			config.InternalCoreURL(), id),
+	})
+	if err != nil {
+		if err := dao.DeleteAdminJob(id); err != nil {
+			log.Errorf("failed to delete admin job %d: %v", id, err)
+		}
+		s.HandleInternalServerError(fmt.Sprintf("failed to submit the re-encryption job: %v", err))
+		return
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to set the UUID of admin job %d: %v", id, err))
+		return
+	}
+	s.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List returns the latest 10 re-encryption jobs
+func (s *SecretReencryptionAPI) List() {
+	jobs, err := dao.GetTop10AdminJobsOfName(common_job.SecretReencryption)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+		return
+	}
+	if jobs == nil {
+		jobs = []*models.AdminJob{}
+	}
+	s.Data["json"] = jobs
+	s.ServeJSON()
+}
+
+// Get returns the re-encryption job, the count of re-encrypted secrets is in the progress when it finishes
+func (s *SecretReencryptionAPI) Get() {
+	id, err := s.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		s.HandleBadRequest("invalid ID")
+		return
+	}
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		ID:   id,
+		Name: common_job.SecretReencryption,
+	})
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get admin job %d: %v", id, err))
+		return
+	}
+	if len(jobs) == 0 {
+		s.HandleNotFound(fmt.Sprintf("re-encryption job %d not found", id))
+		return
+	}
+	s.Data["json"] = jobs[0]
+	s.ServeJSON()
+}
@@ -0,0 +1,239 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/token"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/opencontainers/go-digest"
+)
+
+// ShareLinkAPI manages the share links, which let the vendors pull one image of the
+// project without being onboarded
+type ShareLinkAPI struct {
+	BaseController
+	project *models.Project
+	link    *models.ShareLink
+}
+
+// Prepare ...
+func (s *ShareLinkAPI) Prepare() {
+	s.BaseController.Prepare()
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+
+	pid, err := s.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		s.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", s.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := s.ProjectMgr.Get(pid)
+	if err != nil {
+		s.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		s.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	s.project = project
+
+	if !(s.Ctx.Input.IsGet() && s.SecurityCtx.HasReadPerm(pid) ||
+		s.SecurityCtx.HasAllPerm(pid)) {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(s.GetStringFromPath(":id")) > 0 {
+		id, err := s.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			s.HandleBadRequest(fmt.Sprintf("invalid share link ID: %s", s.GetStringFromPath(":id")))
+			return
+		}
+		link, err := dao.GetShareLink(id)
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get share link %d: %v", id, err))
+			return
+		}
+		if link == nil || link.ProjectID != pid {
+			s.HandleNotFound(fmt.Sprintf("share link %d not found", id))
+			return
+		}
+		link.Username = models.ShareLinkUsername(link.ID)
+		s.link = link
+	}
+}
+
+// Post creates the share link of the image, the token of the link is only returned in the response
+func (s *ShareLinkAPI) Post() {
+	link := &models.ShareLink{}
+	s.DecodeJSONReqAndValidate(link)
+	if !strings.HasPrefix(link.Repository, s.project.Name+"/") {
+		s.HandleBadRequest(fmt.Sprintf("repository %s isn't in project %s", link.Repository, s.project.Name))
+		return
+	}
+	if _, err := digest.Parse(link.Reference); err != nil && !utils.ValidateTag(link.Reference) {
+		s.HandleBadRequest(fmt.Sprintf("invalid reference %s, it should be a tag or a digest", link.Reference))
+		return
+	}
+	if !dao.RepositoryExists(link.Repository) {
+		s.HandleNotFound(fmt.Sprintf("repository %s not found", link.Repository))
+		return
+	}
+	// the link is bound to the digest, so moving the tag doesn't share another image
+	client, err := coreutils.NewRepositoryClientForUI(s.SecurityCtx.GetUsername(), link.Repository)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to create the client of repository %s: %v", link.Repository, err))
+		return
+	}
+	dgt, exist, err := client.ManifestExist(link.Reference)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to resolve %s:%s: %v", link.Repository, link.Reference, err))
+		return
+	}
+	if !exist {
+		s.HandleNotFound(fmt.Sprintf("image %s:%s not found", link.Repository, link.Reference))
+		return
+	}
+	link.Digest = dgt
+
+	link.ID = 0
+	link.ProjectID = s.project.ProjectID
+	link.Creator = s.SecurityCtx.GetUsername()
+	link.Revoked = false
+	link.PullCount = 0
+	link.LastPullTime = nil
+	id, err := dao.AddShareLink(link)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to create share link: %v", err))
+		return
+	}
+
+	// the token is not stored in the database, it expires along with the link
+	rawTk, err := shareToken(id, link.ExpiresAt)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to generate token for share link, %v", err))
+		if err := dao.RevokeShareLink(id); err != nil {
+			log.Errorf("failed to revoke the share link %d: %v", id, err)
+		}
+		return
+	}
+	s.addAccessLog(link, "create_share_link")
+
+	s.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+	s.Data["json"] = &models.ShareLinkRep{
+		ID:        id,
+		Username:  models.ShareLinkUsername(id),
+		Token:     rawTk,
+		ExpiresAt: link.ExpiresAt,
+	}
+	s.ServeJSON()
+}
+
+// List lists the share links of the project with the usage, the query parameter "active"
+// lists the links which aren't revoked or expired only
+func (s *ShareLinkAPI) List() {
+	active, err := s.GetBool("active", false)
+	if err != nil {
+		s.HandleBadRequest(fmt.Sprintf("invalid active: %s", s.GetString("active")))
+		return
+	}
+	query := &models.ShareLinkQuery{
+		ProjectID:  s.project.ProjectID,
+		Repository: s.GetString("repository"),
+		Active:     active,
+	}
+	total, err := dao.CountShareLinks(query)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to count the share links: %v", err))
+		return
+	}
+	query.Page, query.Size = s.GetPaginationParams()
+	links, err := dao.ListShareLinks(query)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to list the share links: %v", err))
+		return
+	}
+	for _, link := range links {
+		link.Username = models.ShareLinkUsername(link.ID)
+	}
+	s.SetPaginationHeader(total, query.Page, query.Size)
+	s.Data["json"] = links
+	s.ServeJSON()
+}
+
+// Get returns the share link with the usage
+func (s *ShareLinkAPI) Get() {
+	s.Data["json"] = s.link
+	s.ServeJSON()
+}
+
+// Delete revokes the share link, the pulls through it are rejected immediately even with the
+// registry tokens issued before
+func (s *ShareLinkAPI) Delete() {
+	if s.link.Revoked {
+		return
+	}
+	if err := dao.RevokeShareLink(s.link.ID); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to revoke share link %d: %v", s.link.ID, err))
+		return
+	}
+	s.addAccessLog(s.link, "revoke_share_link")
+}
+
+// addAccessLog records the lifecycle of the share link in the access log
+func (s *ShareLinkAPI) addAccessLog(link *models.ShareLink, operation string) {
+	detail, err := json.Marshal(map[string]interface{}{
+		"username":   models.ShareLinkUsername(link.ID),
+		"expires_at": link.ExpiresAt,
+	})
+	if err != nil {
+		log.Errorf("failed to marshal the detail of the access log: %v", err)
+	}
+	if err := accesslog.Add(models.AccessLog{
+		Username:  s.SecurityCtx.GetUsername(),
+		ProjectID: s.project.ProjectID,
+		RepoName:  link.Repository,
+		RepoTag:   link.Reference,
+		Operation: operation,
+		OpTime:    time.Now(),
+		Detail:    string(detail),
+	}); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
+}
+
+// shareToken generates the signed token of the share link
+func shareToken(id int64, expiresAt time.Time) (string, error) {
+	jwtToken, err := token.NewShareToken(id, expiresAt)
+	if err != nil {
+		return "", err
+	}
+	return jwtToken.Raw()
+}
@@ -0,0 +1,179 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinkAPI(t *testing.T) {
+	path := "/api/projects/1/share_links"
+	defer dao.ClearTable(models.ShareLinkTable)
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403, only the project admins can share
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projDeveloper,
+				bodyJSON: &models.ShareLink{
+					Repository: "library/hello-world",
+					Reference:  "latest",
+					ExpiresAt:  time.Now().Add(time.Hour),
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, expired
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &models.ShareLink{
+					Repository: "library/hello-world",
+					Reference:  "latest",
+					ExpiresAt:  time.Now().Add(-time.Hour),
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the repository of another project
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &models.ShareLink{
+					Repository: "other/hello-world",
+					Reference:  "latest",
+					ExpiresAt:  time.Now().Add(time.Hour),
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &models.ShareLink{
+					Repository: "library/not-exist",
+					Reference:  "latest",
+					ExpiresAt:  time.Now().Add(time.Hour),
+				},
+			},
+			code: http.StatusNotFound,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        path + "/1000",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	created := &models.ShareLinkRep{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        path,
+		credential: projAdmin,
+		bodyJSON: &models.ShareLink{
+			Repository:  "library/hello-world",
+			Reference:   "latest",
+			Description: "for the vendor",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	}, created))
+	assert.Equal(t, models.ShareLinkUsername(created.ID), created.Username)
+	assert.NotEmpty(t, created.Token)
+
+	links := []*models.ShareLink{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: projGuest,
+	}, &links))
+	require.Equal(t, 1, len(links))
+	assert.Equal(t, created.ID, links[0].ID)
+	assert.Equal(t, projAdmin.Name, links[0].Creator)
+	assert.Equal(t, int64(0), links[0].PullCount)
+	// bound to the digest of the tag
+	assert.NotEmpty(t, links[0].Digest)
+
+	runCodeCheckingCases(t, []*codeCheckingCase{
+		// 403, only the project admins can revoke
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", path, created.ID),
+				credential: projGuest,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", path, created.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}...)
+
+	link := &models.ShareLink{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        fmt.Sprintf("%s/%d", path, created.ID),
+		credential: projAdmin,
+	}, link))
+	assert.True(t, link.Revoked)
+
+	links = []*models.ShareLink{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: projAdmin,
+		queryStruct: struct {
+			Active bool `url:"active"`
+		}{
+			Active: true,
+		},
+	}, &links))
+	assert.Equal(t, 0, len(links))
+}
@@ -20,7 +20,10 @@ import (
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/ratelimit"
+	"github.com/goharbor/harbor/src/core/traffic"
 )
 
 const (
@@ -136,3 +139,107 @@ func (s *StatisticAPI) Get() {
 	s.Data["json"] = statistic
 	s.ServeJSON()
 }
+
+// trafficItem is the registry traffic returned by the statistics API
+type trafficItem struct {
+	*models.RegistryTraffic
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// Traffic returns the traffic of the registry API aggregated by project or by repository,
+// only the system admin can get the traffic of all the projects
+func (s *StatisticAPI) Traffic() {
+	query := &models.RegistryTrafficQuery{
+		Repository: s.GetString("repository"),
+	}
+	switch groupBy := s.GetString("group_by"); groupBy {
+	case "", "project":
+	case "repository":
+		query.ByRepository = true
+	default:
+		s.HandleBadRequest(fmt.Sprintf("invalid group_by: %s", groupBy))
+		return
+	}
+
+	projectID, err := s.GetInt64("project_id", 0)
+	if err != nil || projectID < 0 {
+		s.HandleBadRequest(fmt.Sprintf("invalid project_id: %s", s.GetString("project_id")))
+		return
+	}
+	if projectID > 0 {
+		exist, err := s.ProjectMgr.Exists(projectID)
+		if err != nil {
+			s.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %d", projectID), err)
+			return
+		}
+		if !exist {
+			s.HandleNotFound(fmt.Sprintf("project %d not found", projectID))
+			return
+		}
+		if !s.SecurityCtx.HasReadPerm(projectID) {
+			s.HandleForbidden(s.username)
+			return
+		}
+		query.ProjectIDs = []int64{projectID}
+	} else if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.username)
+		return
+	}
+
+	timestamp := s.GetString("begin_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			s.HandleBadRequest(fmt.Sprintf("invalid begin_timestamp: %s", timestamp))
+			return
+		}
+		query.BeginTime = t
+	}
+	timestamp = s.GetString("end_timestamp")
+	if len(timestamp) > 0 {
+		t, err := utils.ParseTimeStamp(timestamp)
+		if err != nil {
+			s.HandleBadRequest(fmt.Sprintf("invalid end_timestamp: %s", timestamp))
+			return
+		}
+		query.EndTime = t
+	}
+
+	traffics, err := dao.ListRegistryTraffic(query)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to list the registry traffic: %v", err))
+		return
+	}
+	items := []*trafficItem{}
+	for _, t := range traffics {
+		items = append(items, &trafficItem{
+			RegistryTraffic: t,
+			ErrorRate:       t.ErrorRate(),
+		})
+	}
+	s.Data["json"] = items
+	s.ServeJSON()
+}
+
+// Metrics returns the traffic of the registry API recorded by this instance of core
+// since it started and the usage of the projects which opt in in the text format of
+// Prometheus, only the system admin is allowed
+func (s *StatisticAPI) Metrics() {
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.username)
+		return
+	}
+	w := s.Ctx.ResponseWriter
+	w.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/plain; version=0.0.4")
+	if err := traffic.WriteMetrics(w); err != nil {
+		log.Errorf("failed to write the metrics: %v", err)
+		return
+	}
+	if err := ratelimit.WriteMetrics(w); err != nil {
+		log.Errorf("failed to write the metrics: %v", err)
+		return
+	}
+	if err := writeProjectMetrics(s.ProjectMgr, w); err != nil {
+		log.Errorf("failed to write the metrics of projects: %v", err)
+	}
+}
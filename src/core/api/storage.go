@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// StorageAPI exposes the first-class configuration of the storage of registry, the
+// configuration is generated by the installer so it's read-only here
+type StorageAPI struct {
+	BaseController
+}
+
+type storageDriver struct {
+	Driver     string                 `json:"driver"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (s *StorageAPI) Prepare() {
+	s.BaseController.Prepare()
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Get returns the storage of registry, the credentials are omitted
+func (s *StorageAPI) Get() {
+	storage, err := config.RegistryStorage()
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get the storage of registry: %v", err))
+		return
+	}
+	if storage.OSS != nil {
+		storage.OSS.AccessKeySecret = ""
+	}
+	if storage.COS != nil {
+		storage.COS.SecretKey = ""
+	}
+	s.Data["json"] = storage
+	s.ServeJSON()
+}
+
+// Validate validates the storage in the request and returns the storage driver section
+// of the configuration of registry rendered from it
+func (s *StorageAPI) Validate() {
+	storage := &models.RegistryStorage{}
+	s.DecodeJSONReqAndValidate(storage)
+	driver, params := storage.Driver()
+	s.Data["json"] = &storageDriver{
+		Driver:     driver,
+		Parameters: params,
+	}
+	s.ServeJSON()
+}
@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var storagePath = "/api/system/storage"
+
+func TestStorageAPI(t *testing.T) {
+	oss := &models.RegistryStorage{
+		Provider: models.StorageProviderOSS,
+		OSS: &models.OSSStorage{
+			Region:          "oss-cn-hangzhou",
+			Bucket:          "harbor",
+			AccessKeyID:     "id",
+			AccessKeySecret: "secret",
+			Internal:        true,
+			Secure:          true,
+		},
+	}
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    storagePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        storagePath + "/validate",
+				bodyJSON:   oss,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        storagePath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 400, unsupported provider
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        storagePath + "/validate",
+				bodyJSON:   &models.RegistryStorage{Provider: "filesystem"},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the bucket of COS without the APPID
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    storagePath + "/validate",
+				bodyJSON: &models.RegistryStorage{
+					Provider: models.StorageProviderCOS,
+					COS: &models.COSStorage{
+						Region:    "ap-guangzhou",
+						Bucket:    "harbor",
+						SecretID:  "id",
+						SecretKey: "key",
+					},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	driver := &storageDriver{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        storagePath + "/validate",
+		bodyJSON:   oss,
+		credential: sysAdmin,
+	}, driver)
+	require.Nil(t, err)
+	assert.Equal(t, "oss", driver.Driver)
+	assert.Equal(t, "oss-cn-hangzhou", driver.Parameters["region"])
+	assert.Equal(t, true, driver.Parameters["internal"])
+}
@@ -0,0 +1,220 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	job_models "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
+)
+
+const (
+	// the eligible blobs are tagged, and the lifecycle rules of the bucket transition them
+	transitionModeTag = "tag"
+	// the eligible blobs are copied in place with the storage class of the hint
+	transitionModeMove = "move"
+)
+
+// StorageTransitionAPI triggers the job which transitions the idle blobs of the repositories
+// with storage hints to cheaper storage classes on the S3 compatible storage of registry
+type StorageTransitionAPI struct {
+	BaseController
+}
+
+type storageTransitionReq struct {
+	// only report the eligible blobs without transitioning them
+	DryRun bool   `json:"dry_run"`
+	Mode   string `json:"mode"`
+}
+
+type storageTransitionSummary struct {
+	Usages    []*models.StorageClassUsage `json:"usages"`
+	TotalSize int64                       `json:"total_size"`
+}
+
+// Prepare validates the user, it needs the system admin permission
+func (s *StorageTransitionAPI) Prepare() {
+	s.BaseController.Prepare()
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Post triggers the storage transition job, only one job can run at the same time
+func (s *StorageTransitionAPI) Post() {
+	req := &storageTransitionReq{}
+	if s.Ctx.Request.ContentLength > 0 {
+		s.DecodeJSONReq(req)
+	}
+	if len(req.Mode) == 0 {
+		req.Mode = transitionModeTag
+	}
+	if req.Mode != transitionModeTag && req.Mode != transitionModeMove {
+		s.HandleBadRequest(fmt.Sprintf("invalid mode %s, it should be %s or %s",
+			req.Mode, transitionModeTag, transitionModeMove))
+		return
+	}
+	// moving the blobs rewrites the objects, which may be charged for the early deletion
+	if req.Mode == transitionModeMove && !req.DryRun && !s.Confirmed() {
+		return
+	}
+
+	for _, status := range []string{models.JobPending, models.JobRunning} {
+		jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+			Name:   common_job.StorageTransition,
+			Status: status,
+		})
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+			return
+		}
+		if len(jobs) > 0 {
+			s.HandleConflict(fmt.Sprintf("the storage transition job %d is %s", jobs[0].ID, status))
+			return
+		}
+	}
+
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: common_job.StorageTransition,
+		Kind: common_job.JobKindGeneric,
+	})
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to add admin job: %v", err))
+		return
+	}
+	uuid, err := utils_core.GetJobServiceClient().SubmitJob(&job_models.JobData{
+		Name: common_job.StorageTransition,
+		Parameters: map[string]interface{}{
+			"dry_run": req.DryRun,
+			"mode":    req.Mode,
+		},
+		Metadata: &job_models.JobMetadata{
+			JobKind:  common_job.JobKindGeneric,
+			IsUnique: true,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d",
+			config.InternalCoreURL(), id),
+	})
+	if err != nil {
+		if err := dao.DeleteAdminJob(id); err != nil {
+			log.Errorf("failed to delete admin job %d: %v", id, err)
+		}
+		s.HandleInternalServerError(fmt.Sprintf("failed to submit the storage transition job: %v", err))
+		return
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to set the UUID of admin job %d: %v", id, err))
+		return
+	}
+	s.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List returns the latest 10 storage transition jobs
+func (s *StorageTransitionAPI) List() {
+	jobs, err := dao.GetTop10AdminJobsOfName(common_job.StorageTransition)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get admin jobs: %v", err))
+		return
+	}
+	if jobs == nil {
+		jobs = []*models.AdminJob{}
+	}
+	s.Data["json"] = jobs
+	s.ServeJSON()
+}
+
+// Get returns the storage transition job, the report is in the progress when the job finishes
+func (s *StorageTransitionAPI) Get() {
+	job := s.getJob()
+	if job == nil {
+		return
+	}
+	s.Data["json"] = job
+	s.ServeJSON()
+}
+
+// GetLog returns the log of the storage transition job which lists the transitioned blobs
+func (s *StorageTransitionAPI) GetLog() {
+	job := s.getJob()
+	if job == nil {
+		return
+	}
+	data, err := utils_core.GetJobServiceClient().GetJobLog(job.UUID)
+	if err != nil {
+		if httpErr, ok := err.(*common_http.Error); ok {
+			s.RenderError(httpErr.Code, httpErr.Message)
+			return
+		}
+		s.HandleInternalServerError(fmt.Sprintf("failed to get the log of job %d: %v", job.ID, err))
+		return
+	}
+	s.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Length"), strconv.Itoa(len(data)))
+	s.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/plain")
+	if _, err = s.Ctx.ResponseWriter.Write(data); err != nil {
+		log.Errorf("failed to write the log of job %d: %v", job.ID, err)
+	}
+}
+
+// Summary returns the count and size of the blobs transitioned to each storage class
+func (s *StorageTransitionAPI) Summary() {
+	usages, err := dao.SummarizeBlobTransitions()
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to summarize the blob transitions: %v", err))
+		return
+	}
+	summary := &storageTransitionSummary{
+		Usages: usages,
+	}
+	for _, usage := range usages {
+		summary.TotalSize += usage.Size
+	}
+	s.Data["json"] = summary
+	s.ServeJSON()
+}
+
+func (s *StorageTransitionAPI) getJob() *models.AdminJob {
+	id, err := s.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		s.HandleBadRequest("invalid ID")
+		return nil
+	}
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		ID:   id,
+		Name: common_job.StorageTransition,
+	})
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get admin job %d: %v", id, err))
+		return nil
+	}
+	if len(jobs) == 0 {
+		s.HandleNotFound(fmt.Sprintf("storage transition job %d not found", id))
+		return nil
+	}
+	return jobs[0]
+}
@@ -0,0 +1,113 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var storageTransitionPath = "/api/system/storage_transition"
+
+func TestStorageTransitionAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    storageTransitionPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        storageTransitionPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, invalid mode
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        storageTransitionPath,
+				bodyJSON:   &storageTransitionReq{Mode: "delete"},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        storageTransitionPath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        storageTransitionPath,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        storageTransitionPath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        storageTransitionPath + "/10000/log",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        storageTransitionPath + "/summary",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+
+	summary := &storageTransitionSummary{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        storageTransitionPath + "/summary",
+		credential: sysAdmin,
+	}, summary)
+	require.Nil(t, err)
+	assert.NotNil(t, summary.Usages)
+}
@@ -15,8 +15,11 @@
 package api
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
@@ -24,13 +27,15 @@ import (
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
 	clairdao "github.com/goharbor/harbor/src/common/dao/clair"
+	"github.com/goharbor/harbor/src/common/feature"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
-	"github.com/goharbor/harbor/src/common/utils/clair"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/scanner"
 	"github.com/goharbor/harbor/src/core/systeminfo"
 	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
 )
 
 // SystemInfoAPI handle requests for getting system info /api/systeminfo
@@ -41,6 +46,17 @@ type SystemInfoAPI struct {
 const defaultRootCert = "/etc/core/ca/ca.crt"
 const harborVersionFile = "/harbor/UIVERSION"
 
+// the environment variables set by the docker compose files with the image tags of the components
+const (
+	registryVersionEnv    = "REGISTRY_VERSION"
+	chartMuseumVersionEnv = "CHARTMUSEUM_VERSION"
+	notaryVersionEnv      = "NOTARY_VERSION"
+	clairVersionEnv       = "CLAIR_VERSION"
+)
+
+// the port of notary server exposed by the proxy
+const notaryPort = "4443"
+
 // SystemInfo models for system info.
 type SystemInfo struct {
 	HarborStorage Storage `json:"storage"`
@@ -55,8 +71,7 @@ type Storage struct {
 // namespaces stores all name spaces on Clair, it should be initialised only once.
 type clairNamespaces struct {
 	sync.RWMutex
-	l     []string
-	clair *clair.Client
+	l []string
 }
 
 func (n *clairNamespaces) get() ([]string, error) {
@@ -64,10 +79,11 @@ func (n *clairNamespaces) get() ([]string, error) {
 	defer n.Unlock()
 	if len(n.l) == 0 {
 		m := make(map[string]struct{})
-		if n.clair == nil {
-			n.clair = clair.NewClient(config.ClairEndpoint(), nil)
+		client, err := scanner.ClairClient()
+		if err != nil {
+			return n.l, err
 		}
-		list, err := n.clair.ListNamespaces()
+		list, err := client.ListNamespaces()
 		if err != nil {
 			return n.l, err
 		}
@@ -102,6 +118,29 @@ type GeneralInfo struct {
 	RegistryStorageProviderName string                           `json:"registry_storage_provider_name"`
 	ReadOnly                    bool                             `json:"read_only"`
 	WithChartMuseum             bool                             `json:"with_chartmuseum"`
+	InMaintenance               bool                             `json:"in_maintenance"`
+	MaintenanceMessage          string                           `json:"maintenance_message,omitempty"`
+	Features                    map[string]bool                  `json:"features"`
+	Endpoints                   *Endpoints                       `json:"endpoints"`
+	Versions                    *ComponentVersions               `json:"versions,omitempty"`
+}
+
+// ComponentVersions are the versions of the components, the empty ones are omitted as
+// the components aren't deployed or the versions are unknown
+type ComponentVersions struct {
+	Core        string `json:"core,omitempty"`
+	Registry    string `json:"registry,omitempty"`
+	ChartMuseum string `json:"chartmuseum,omitempty"`
+	Notary      string `json:"notary,omitempty"`
+	Scanner     string `json:"scanner,omitempty"`
+}
+
+// Endpoints are the external URLs of the services, the ones of the components not deployed are omitted
+type Endpoints struct {
+	Harbor       string `json:"harbor"`
+	TokenService string `json:"token_service"`
+	ChartRepo    string `json:"chart_repo,omitempty"`
+	Notary       string `json:"notary,omitempty"`
 }
 
 // validate for validating user if an admin.
@@ -138,6 +177,19 @@ func (sia *SystemInfoAPI) GetVolumeInfo() {
 	sia.ServeJSON()
 }
 
+// GetJobLogUsage returns the count and the total bytes of the logs kept by jobservice per job,
+// the logs are swept according to the retention configured for jobservice
+func (sia *SystemInfoAPI) GetJobLogUsage() {
+	sia.validate()
+
+	usages, err := utils_core.GetJobServiceClient().GetJobLogUsage()
+	if err != nil {
+		sia.ParseAndHandleError("failed to get the usage of the job logs", err)
+		return
+	}
+	sia.WriteJSONData(usages)
+}
+
 // GetCert gets default self-signed certificate.
 func (sia *SystemInfoAPI) GetCert() {
 	if _, err := os.Stat(defaultRootCert); err == nil {
@@ -183,6 +235,37 @@ func (sia *SystemInfoAPI) GetGeneralInfo() {
 		ReadOnly:                    config.ReadOnly(),
 		WithChartMuseum:             config.WithChartMuseum(),
 	}
+	info.Features = map[string]bool{
+		"notary":                      info.WithNotary,
+		"scanner":                     info.WithClair,
+		"chartmuseum":                 info.WithChartMuseum,
+		"admiral":                     info.WithAdmiral,
+		"read_only":                   info.ReadOnly,
+		"self_registration":           info.SelfRegistration,
+		"destructive_op_confirmation": config.DestructiveOpConfirmation(),
+	}
+	maintenance, err := config.Maintenance()
+	if err != nil {
+		log.Errorf("Error occurred getting the state of maintenance mode: %v", err)
+	} else if maintenance.Enabled {
+		info.InMaintenance = true
+		info.MaintenanceMessage = maintenance.GetMessage()
+	}
+	// the feature flags toggled at runtime
+	overrides, err := config.FeatureFlags()
+	if err != nil {
+		log.Errorf("Error occurred getting feature flags: %v", err)
+		overrides = feature.Overrides{}
+	}
+	for _, flag := range feature.All() {
+		info.Features[flag.Name] = overrides.Enabled(flag.Name)
+	}
+	info.Endpoints = getEndpoints(utils.SafeCastString(cfg[common.ExtEndpoint]), info.WithChartMuseum, info.WithNotary)
+	// the versions of components are only returned to the authenticated users as they tell
+	// the known vulnerabilities of the deployment
+	if sia.SecurityCtx.IsAuthenticated() {
+		info.Versions = getComponentVersions(harborVersion, info.WithChartMuseum, info.WithNotary, info.WithClair)
+	}
 	if info.WithClair {
 		info.ClairVulnStatus = getClairVulnStatus()
 	}
@@ -200,6 +283,42 @@ func (sia *SystemInfoAPI) getVersion() string {
 	return string(version[:])
 }
 
+func getComponentVersions(harborVersion string, withChartMuseum, withNotary, withClair bool) *ComponentVersions {
+	versions := &ComponentVersions{
+		Core:     strings.TrimSpace(harborVersion),
+		Registry: os.Getenv(registryVersionEnv),
+	}
+	if withChartMuseum {
+		versions.ChartMuseum = os.Getenv(chartMuseumVersionEnv)
+	}
+	if withNotary {
+		versions.Notary = os.Getenv(notaryVersionEnv)
+	}
+	if withClair {
+		versions.Scanner = os.Getenv(clairVersionEnv)
+	}
+	return versions
+}
+
+// getEndpoints builds the external URLs from the external endpoint, notary is served by
+// the proxy on the same host with a dedicated port
+func getEndpoints(extEndpoint string, withChartMuseum, withNotary bool) *Endpoints {
+	extEndpoint = strings.TrimSuffix(extEndpoint, "/")
+	endpoints := &Endpoints{
+		Harbor:       extEndpoint,
+		TokenService: extEndpoint + "/service/token",
+	}
+	if withChartMuseum {
+		endpoints.ChartRepo = extEndpoint + "/chartrepo"
+	}
+	if withNotary {
+		if u, err := url.Parse(extEndpoint); err == nil && len(u.Hostname()) > 0 {
+			endpoints.Notary = fmt.Sprintf("%s://%s", u.Scheme, net.JoinHostPort(u.Hostname(), notaryPort))
+		}
+	}
+	return endpoints
+}
+
 func getClairVulnStatus() *models.ClairVulnerabilityStatus {
 	res := &models.ClairVulnerabilityStatus{}
 	last, err := clairdao.GetLastUpdate()
@@ -16,6 +16,8 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -65,6 +67,33 @@ func TestGetGeneralInfo(t *testing.T) {
 	assert.Equal(true, g.HasCARoot, "has ca root should be true")
 	assert.NotEmpty(g.HarborVersion, "harbor version should not be empty")
 	assert.Equal(false, g.ReadOnly, "readonly should be false")
+	assert.Equal(false, g.Features["notary"], "notary feature should be disabled")
+	assert.NotNil(g.Endpoints)
+	assert.NotEmpty(g.Endpoints.Harbor, "the external URL of Harbor should not be empty")
+	assert.Nil(g.Versions, "the versions of components should be hidden for anonymous user")
+
+	g = &GeneralInfo{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/systeminfo",
+		credential: nonSysAdmin,
+	}, g)
+	assert.Nil(err)
+	assert.NotNil(g.Versions)
+	assert.Equal(strings.TrimSpace(g.HarborVersion), g.Versions.Core)
+	assert.Empty(g.Versions.Notary)
+}
+
+func TestGetEndpoints(t *testing.T) {
+	endpoints := getEndpoints("https://harbor.example.com/", true, true)
+	assert.Equal(t, "https://harbor.example.com", endpoints.Harbor)
+	assert.Equal(t, "https://harbor.example.com/service/token", endpoints.TokenService)
+	assert.Equal(t, "https://harbor.example.com/chartrepo", endpoints.ChartRepo)
+	assert.Equal(t, "https://harbor.example.com:4443", endpoints.Notary)
+
+	endpoints = getEndpoints("http://10.0.0.1:8080", false, true)
+	assert.Empty(t, endpoints.ChartRepo)
+	assert.Equal(t, "http://10.0.0.1:4443", endpoints.Notary)
 }
 
 func TestGetCert(t *testing.T) {
@@ -100,3 +129,26 @@ func TestPing(t *testing.T) {
 	assert.Nil(err, fmt.Sprintf("Unexpected Error: %v", err))
 	assert.Equal(200, code, fmt.Sprintf("Unexpected status code: %d", code))
 }
+
+func TestGetJobLogUsage(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/systeminfo/joblogs",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/systeminfo/joblogs",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,192 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// TagAliasAPI handles the requests on /api/repositories/*/aliases to manage the alias tags of
+// the repository. The alias tags are pushed to the registry by the operators, so the changes are
+// recorded in the tag history as the pushes, while the pushes to them by the clients are refused.
+type TagAliasAPI struct {
+	BaseController
+	repository string
+	project    *models.Project
+}
+
+// Prepare ...
+func (t *TagAliasAPI) Prepare() {
+	t.BaseController.Prepare()
+	if !t.SecurityCtx.IsAuthenticated() {
+		t.HandleUnauthorized()
+		return
+	}
+
+	name := t.GetString(":splat")
+	repository, err := dao.GetRepositoryByName(name)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+		return
+	}
+	if repository == nil {
+		t.HandleNotFound(t.T(i18n.MsgRepositoryNotFound, name))
+		return
+	}
+
+	projectName, _ := utils.ParseRepository(name)
+	project, err := t.ProjectMgr.Get(projectName)
+	if err != nil {
+		t.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+		return
+	}
+	if project == nil {
+		t.HandleNotFound(t.T(i18n.MsgProjectNotFound, projectName))
+		return
+	}
+
+	if t.Ctx.Request.Method == http.MethodGet {
+		if !t.SecurityCtx.HasReadPerm(project.ProjectID) {
+			t.HandleForbidden(t.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !t.SecurityCtx.HasWritePerm(project.ProjectID) {
+		t.HandleForbidden(t.SecurityCtx.GetUsername())
+		return
+	}
+	t.repository = name
+	t.project = project
+}
+
+// List returns the aliases of the repository
+func (t *TagAliasAPI) List() {
+	aliases, err := dao.ListTagAliases(t.repository)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to list the aliases of repository %s: %v", t.repository, err))
+		return
+	}
+	t.Data["json"] = aliases
+	t.ServeJSON()
+}
+
+// Put creates the alias or points it to another tag of the repository, the alias is pushed
+// with the manifest of the tag. A tag pushed by the clients can't be turned into an alias
+func (t *TagAliasAPI) Put() {
+	name := t.GetString(":alias")
+	request := &models.TagAlias{}
+	t.DecodeJSONReqAndValidate(request)
+	if !utils.ValidateTag(name) {
+		t.HandleBadRequest(fmt.Sprintf("invalid alias '%s'", name))
+		return
+	}
+	if request.Tag == name {
+		t.HandleBadRequest(fmt.Sprintf("the alias %s cannot point to itself", name))
+		return
+	}
+	// the aliases follow the tag policy as the pushed tags
+	if policy := t.project.TagPolicy(); policy != nil {
+		if err := policy.Check(name); err != nil {
+			t.HandleBadRequest(fmt.Sprintf("the alias violates the tag policy of project %s: %v", t.project.Name, err))
+			return
+		}
+	}
+
+	client, err := coreutils.NewRepositoryClientForUI(t.SecurityCtx.GetUsername(), t.repository)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to initialize the client for %s: %v", t.repository, err))
+		return
+	}
+	digest, exist, err := client.ManifestExist(request.Tag)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s:%s: %v", t.repository, request.Tag, err))
+		return
+	}
+	if !exist {
+		t.HandleNotFound(fmt.Sprintf("image %s:%s not found", t.repository, request.Tag))
+		return
+	}
+
+	alias, err := dao.GetTagAlias(t.repository, name)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to get the alias %s:%s: %v", t.repository, name, err))
+		return
+	}
+	if alias == nil {
+		_, exist, err = client.ManifestExist(name)
+		if err != nil {
+			t.HandleInternalServerError(fmt.Sprintf("failed to check the existence of %s:%s: %v", t.repository, name, err))
+			return
+		}
+		if exist {
+			t.HandleConflict(fmt.Sprintf("tag '%s' already existed for '%s'", name, t.repository))
+			return
+		}
+	}
+
+	if err = pushAlias(client, name, digest); err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to push the alias %s:%s: %v", t.repository, name, err))
+		return
+	}
+	if err = dao.SetTagAlias(&models.TagAlias{
+		Repository: t.repository,
+		Alias:      name,
+		Tag:        request.Tag,
+		Digest:     digest,
+		Operator:   t.SecurityCtx.GetUsername(),
+	}); err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to set the alias %s:%s: %v", t.repository, name, err))
+		return
+	}
+}
+
+// Delete removes the alias. As the registry can't delete a tag without deleting the manifest
+// referenced by the other tags, the tag is kept and can be pushed by the clients then
+func (t *TagAliasAPI) Delete() {
+	name := t.GetString(":alias")
+	alias, err := dao.GetTagAlias(t.repository, name)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to get the alias %s:%s: %v", t.repository, name, err))
+		return
+	}
+	if alias == nil {
+		t.HandleNotFound(fmt.Sprintf("alias %s:%s not found", t.repository, name))
+		return
+	}
+	if err = dao.DeleteTagAlias(t.repository, name); err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to delete the alias %s:%s: %v", t.repository, name, err))
+		return
+	}
+}
+
+// pushAlias pushes the manifest of the digest as the alias
+func pushAlias(client *registry.Repository, alias, digest string) error {
+	accepted := []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest}
+	_, mediaType, payload, err := client.PullManifest(digest, accepted)
+	if err != nil {
+		return err
+	}
+	_, err = client.PushManifest(alias, mediaType, payload)
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the aliases aren't pushed successfully in the testing as the registry can't delete the
+// alias tags without deleting the images used by the other testings
+func TestTagAliasAPI(t *testing.T) {
+	aliasPath := "/api/repositories/library/hello-world/aliases/stable"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/repositories/library/hello-world/aliases",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, repository not found
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/repositories/library/not-exist/aliases",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, the guests can't set the aliases
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        aliasPath,
+				credential: projGuest,
+				bodyJSON: &models.TagAlias{
+					Tag: "latest",
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, empty tag
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        aliasPath,
+				credential: projDeveloper,
+				bodyJSON:   &models.TagAlias{},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, point to itself
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        aliasPath,
+				credential: projDeveloper,
+				bodyJSON: &models.TagAlias{
+					Tag: "stable",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404, the tag not found
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        aliasPath,
+				credential: projDeveloper,
+				bodyJSON: &models.TagAlias{
+					Tag: "not-exist",
+				},
+			},
+			code: http.StatusNotFound,
+		},
+		// 404, the alias not found
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        aliasPath,
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	aliases := []*models.TagAlias{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/repositories/library/hello-world/aliases",
+		credential: projGuest,
+	}, &aliases)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(aliases))
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// TagPolicyAPI handles request to /api/projects/{}/tag_policy/check, it checks the candidate tag
+// names against the tag policy of the project before pushing them
+type TagPolicyAPI struct {
+	BaseController
+	project *models.Project
+}
+
+// Prepare validates the user and the project, the members of the project have the permission
+func (t *TagPolicyAPI) Prepare() {
+	t.BaseController.Prepare()
+	if !t.SecurityCtx.IsAuthenticated() {
+		t.HandleUnauthorized()
+		return
+	}
+
+	pid, err := t.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		t.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", t.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := t.ProjectMgr.Get(pid)
+	if err != nil {
+		t.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		t.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	if !t.SecurityCtx.HasReadPerm(pid) {
+		t.HandleForbidden(t.SecurityCtx.GetUsername())
+		return
+	}
+	t.project = project
+}
+
+// Check checks the tag in the query string against the tag policy of the project, all the
+// valid tags are allowed if the project has no tag policy
+func (t *TagPolicyAPI) Check() {
+	tag := t.GetString("tag")
+	if !utils.ValidateTag(tag) {
+		t.HandleBadRequest(fmt.Sprintf("invalid tag '%s'", tag))
+		return
+	}
+	result := &models.TagCheckResult{
+		Tag:     tag,
+		Allowed: true,
+	}
+	if policy := t.project.TagPolicy(); policy != nil {
+		if err := policy.Check(tag); err != nil {
+			result.Allowed = false
+			result.Reason = err.Error()
+		}
+	}
+	t.Data["json"] = result
+	t.ServeJSON()
+}
@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tagPolicyCheckPath = "/api/projects/1/tag_policy/check"
+
+func TestTagPolicyAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    tagPolicyCheckPath + "?tag=v1.0.0",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400, invalid tag
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        tagPolicyCheckPath + "?tag=",
+				credential: projGuest,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/tag_policy/check?tag=v1.0.0",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// all the tags are allowed without the tag policy
+	result := &models.TagCheckResult{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        tagPolicyCheckPath + "?tag=latest",
+		credential: projGuest,
+	}, result)
+	require.Nil(t, err)
+	assert.True(t, result.Allowed)
+
+	require.Nil(t, dao.AddProjectMetadata(&models.ProjectMetadata{
+		ProjectID: 1,
+		Name:      models.ProMetaTagPolicy,
+		Value:     `{"deny":["latest"],"require_semver":true}`,
+	}))
+	defer dao.DeleteProjectMetadata(1, models.ProMetaTagPolicy)
+
+	result = &models.TagCheckResult{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        tagPolicyCheckPath + "?tag=latest",
+		credential: projGuest,
+	}, result)
+	require.Nil(t, err)
+	assert.Equal(t, "latest", result.Tag)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "the tag latest matches the denied pattern latest", result.Reason)
+
+	result = &models.TagCheckResult{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        tagPolicyCheckPath + "?tag=v1.0.0",
+		credential: projGuest,
+	}, result)
+	require.Nil(t, err)
+	assert.True(t, result.Allowed)
+}
@@ -20,12 +20,16 @@ import (
 	"strconv"
 
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/secretstore"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/common/utils/registry/auth"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/egress"
+	"github.com/goharbor/harbor/src/core/ratelimit"
 )
 
 // TargetAPI handles request to /api/targets/ping /api/targets/{}
@@ -55,8 +59,8 @@ func (t *TargetAPI) Prepare() {
 	}
 }
 
-func (t *TargetAPI) ping(endpoint, username, password string, insecure bool) {
-	registry, err := newRegistryClient(endpoint, insecure, username, password)
+func (t *TargetAPI) ping(target *models.RepTarget) {
+	registry, err := newRegistryClient(target)
 	if err == nil {
 		err = registry.Ping()
 	}
@@ -72,11 +76,14 @@ func (t *TargetAPI) ping(endpoint, username, password string, insecure bool) {
 // Ping validates whether the target is reachable and whether the credential is valid
 func (t *TargetAPI) Ping() {
 	req := struct {
-		ID       *int64  `json:"id"`
-		Endpoint *string `json:"endpoint"`
-		Username *string `json:"username"`
-		Password *string `json:"password"`
-		Insecure *bool   `json:"insecure"`
+		ID            *int64  `json:"id"`
+		Endpoint      *string `json:"endpoint"`
+		Username      *string `json:"username"`
+		Password      *string `json:"password"`
+		CredentialRef *string `json:"credential_ref"`
+		Insecure      *bool   `json:"insecure"`
+		AuthScheme    *string `json:"auth_scheme"`
+		TokenRealm    *string `json:"token_realm"`
 	}{}
 	t.DecodeJSONReq(&req)
 
@@ -93,12 +100,19 @@ func (t *TargetAPI) Ping() {
 			return
 		}
 		if len(target.Password) != 0 {
-			target.Password, err = utils.ReversibleDecrypt(target.Password, t.secretKey)
+			target.Password, err = keyring.Decrypt(target.Password, t.secretKey)
 			if err != nil {
 				t.HandleInternalServerError(fmt.Sprintf("failed to decrypt password: %v", err))
 				return
 			}
 		}
+		if len(target.CABundle) != 0 {
+			target.CABundle, err = keyring.Decrypt(target.CABundle, t.secretKey)
+			if err != nil {
+				t.HandleInternalServerError(fmt.Sprintf("failed to decrypt CA bundle: %v", err))
+				return
+			}
+		}
 	}
 
 	if req.Endpoint != nil {
@@ -117,11 +131,53 @@ func (t *TargetAPI) Ping() {
 	if req.Password != nil {
 		target.Password = *req.Password
 	}
+	if req.CredentialRef != nil {
+		target.CredentialRef = *req.CredentialRef
+	}
 	if req.Insecure != nil {
 		target.Insecure = *req.Insecure
 	}
+	if req.AuthScheme != nil {
+		target.AuthScheme = *req.AuthScheme
+	}
+	if req.TokenRealm != nil {
+		target.TokenRealm = *req.TokenRealm
+	}
 
-	t.ping(target.URL, target.Username, target.Password, target.Insecure)
+	// the password specified in the request takes precedence over the credential reference
+	if len(target.CredentialRef) > 0 && req.Password == nil {
+		// read the latest credential as it may be rotated
+		secretstore.Invalidate(target.CredentialRef)
+		cred, err := secretstore.Resolve(target.CredentialRef)
+		if err != nil {
+			log.Errorf("failed to resolve the credential %s: %v", target.CredentialRef, err)
+			t.HandleBadRequest(fmt.Sprintf("failed to resolve the credential %s", target.CredentialRef))
+			return
+		}
+		if len(cred.Username) > 0 {
+			target.Username = cred.Username
+		}
+		target.Password = cred.Password
+	}
+
+	if !t.checkEgress(target.URL) {
+		return
+	}
+	t.ping(target)
+}
+
+// checkEgress checks the endpoint against the egress allowlist, false is returned with the
+// error responded if it isn't allowed
+func (t *TargetAPI) checkEgress(endpoint string) bool {
+	if err := egress.Check(endpoint, ""); err != nil {
+		if _, ok := err.(*egress.DeniedError); ok {
+			t.HandleBadRequest(err.Error())
+			return false
+		}
+		t.HandleInternalServerError(fmt.Sprintf("failed to check the egress allowlist: %v", err))
+		return false
+	}
+	return true
 }
 
 // Get ...
@@ -145,13 +201,51 @@ func (t *TargetAPI) Get() {
 	t.ServeJSON()
 }
 
+// GetByName gets the target by name
+func (t *TargetAPI) GetByName() {
+	name := t.GetStringFromPath(":name")
+
+	target, err := dao.GetRepTargetByName(name)
+	if err != nil {
+		log.Errorf("failed to get target %s: %v", name, err)
+		t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	if target == nil {
+		t.HandleNotFound(fmt.Sprintf("target %s not found", name))
+		return
+	}
+
+	target.Password = ""
+
+	t.Data["json"] = target
+	t.ServeJSON()
+}
+
 // List ...
 func (t *TargetAPI) List() {
-	name := t.GetString("name")
-	targets, err := dao.FilterRepTargets(name)
+	name, exact, err := t.GetNameQuery()
 	if err != nil {
-		log.Errorf("failed to filter targets %s: %v", name, err)
-		t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		t.HandleBadRequest(err.Error())
+		return
+	}
+
+	targets := []*models.RepTarget{}
+	if exact {
+		target, err := dao.GetRepTargetByName(name)
+		if err != nil {
+			log.Errorf("failed to get target %s: %v", name, err)
+			t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+		if target != nil {
+			targets = append(targets, target)
+		}
+	} else {
+		targets, err = dao.FilterRepTargets(name)
+		if err != nil {
+			log.Errorf("failed to filter targets %s: %v", name, err)
+			t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
 	}
 
 	for _, target := range targets {
@@ -167,6 +261,9 @@ func (t *TargetAPI) List() {
 func (t *TargetAPI) Post() {
 	target := &models.RepTarget{}
 	t.DecodeJSONReqAndValidate(target)
+	if !t.checkEgress(target.URL) {
+		return
+	}
 
 	ta, err := dao.GetRepTargetByName(target.Name)
 	if err != nil {
@@ -191,7 +288,7 @@ func (t *TargetAPI) Post() {
 	}
 
 	if len(target.Password) != 0 {
-		target.Password, err = utils.ReversibleEncrypt(target.Password, t.secretKey)
+		target.Password, err = keyring.Encrypt(target.Password, t.secretKey)
 		if err != nil {
 			log.Errorf("failed to encrypt password: %v", err)
 			t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -223,7 +320,7 @@ func (t *TargetAPI) Put() {
 	}
 
 	if len(target.Password) != 0 {
-		target.Password, err = utils.ReversibleDecrypt(target.Password, t.secretKey)
+		target.Password, err = keyring.Decrypt(target.Password, t.secretKey)
 		if err != nil {
 			log.Errorf("failed to decrypt password: %v", err)
 			t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -231,16 +328,25 @@ func (t *TargetAPI) Put() {
 	}
 
 	req := struct {
-		Name     *string `json:"name"`
-		Endpoint *string `json:"endpoint"`
-		Username *string `json:"username"`
-		Password *string `json:"password"`
-		Insecure *bool   `json:"insecure"`
+		Name                   *string                `json:"name"`
+		Endpoint               *string                `json:"endpoint"`
+		Username               *string                `json:"username"`
+		Password               *string                `json:"password"`
+		CredentialRef          *string                `json:"credential_ref"`
+		Insecure               *bool                  `json:"insecure"`
+		Type                   *int                   `json:"type"`
+		AuthScheme             *string                `json:"auth_scheme"`
+		TokenRealm             *string                `json:"token_realm"`
+		PathMappings           *[]*models.PathMapping `json:"path_mappings"`
+		MaxConcurrentTransfers *int                   `json:"max_concurrent_transfers"`
+		BackoffRetries         *int                   `json:"backoff_retries"`
+		RateLimitReserve       *int                   `json:"ratelimit_reserve"`
 	}{}
 	t.DecodeJSONReq(&req)
 
 	originalName := target.Name
 	originalURL := target.URL
+	originalCredentialRef := target.CredentialRef
 
 	if req.Name != nil {
 		target.Name = *req.Name
@@ -253,10 +359,42 @@ func (t *TargetAPI) Put() {
 	}
 	if req.Password != nil {
 		target.Password = *req.Password
+		// the credential reference is replaced by the password
+		if len(target.Password) > 0 && req.CredentialRef == nil {
+			target.CredentialRef = ""
+		}
+	}
+	if req.CredentialRef != nil {
+		target.CredentialRef = *req.CredentialRef
+		// the stored password is replaced by the credential reference
+		if len(target.CredentialRef) > 0 && req.Password == nil {
+			target.Password = ""
+		}
 	}
 	if req.Insecure != nil {
 		target.Insecure = *req.Insecure
 	}
+	if req.Type != nil {
+		target.Type = *req.Type
+	}
+	if req.AuthScheme != nil {
+		target.AuthScheme = *req.AuthScheme
+	}
+	if req.TokenRealm != nil {
+		target.TokenRealm = *req.TokenRealm
+	}
+	if req.PathMappings != nil {
+		target.PathMappings = *req.PathMappings
+	}
+	if req.MaxConcurrentTransfers != nil {
+		target.MaxConcurrentTransfers = *req.MaxConcurrentTransfers
+	}
+	if req.BackoffRetries != nil {
+		target.BackoffRetries = *req.BackoffRetries
+	}
+	if req.RateLimitReserve != nil {
+		target.RateLimitReserve = *req.RateLimitReserve
+	}
 
 	t.Validate(target)
 
@@ -274,6 +412,9 @@ func (t *TargetAPI) Put() {
 	}
 
 	if target.URL != originalURL {
+		if !t.checkEgress(target.URL) {
+			return
+		}
 		ta, err := dao.GetRepTargetByEndpoint(target.URL)
 		if err != nil {
 			log.Errorf("failed to get target [ %s ]: %v", target.URL, err)
@@ -287,7 +428,7 @@ func (t *TargetAPI) Put() {
 	}
 
 	if len(target.Password) != 0 {
-		target.Password, err = utils.ReversibleEncrypt(target.Password, t.secretKey)
+		target.Password, err = keyring.Encrypt(target.Password, t.secretKey)
 		if err != nil {
 			log.Errorf("failed to encrypt password: %v", err)
 			t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -298,6 +439,9 @@ func (t *TargetAPI) Put() {
 		log.Errorf("failed to update target %d: %v", id, err)
 		t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
+	if len(originalCredentialRef) > 0 {
+		secretstore.Invalidate(originalCredentialRef)
+	}
 }
 
 // Delete ...
@@ -326,19 +470,46 @@ func (t *TargetAPI) Delete() {
 		t.CustomAbort(http.StatusPreconditionFailed, "the target is used by policies, can not be deleted")
 	}
 
+	if !t.Confirmed() {
+		return
+	}
+
 	if err = dao.DeleteRepTarget(id); err != nil {
 		log.Errorf("failed to delete target %d: %v", id, err)
 		t.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
 }
 
-func newRegistryClient(endpoint string, insecure bool, username, password string) (*registry.Registry, error) {
-	transport := registry.GetHTTPTransport(insecure)
-	credential := auth.NewBasicAuthCredential(username, password)
-	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
+func newRegistryClient(target *models.RepTarget) (*registry.Registry, error) {
+	transport, err := registry.GetHTTPTransportWithCA(target.Insecure, target.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	authorizer, err := auth.NewAuthorizer(&http.Client{
 		Transport: transport,
-	}, credential)
-	return registry.NewRegistry(endpoint, &http.Client{
+	}, target.AuthScheme, target.Username, target.Password, target.TokenRealm)
+	if err != nil {
+		return nil, err
+	}
+	return registry.NewRegistry(target.URL, &http.Client{
+		Transport: registry.NewTransport(transport, authorizer),
+	})
+}
+
+// newRateLimitProbeClient returns the client of the repository for probing the rate limit
+// of the target
+func newRateLimitProbeClient(target *models.RepTarget) (*registry.Repository, error) {
+	transport, err := registry.GetHTTPTransportWithCA(target.Insecure, target.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	authorizer, err := auth.NewAuthorizer(&http.Client{
+		Transport: transport,
+	}, target.AuthScheme, target.Username, target.Password, target.TokenRealm)
+	if err != nil {
+		return nil, err
+	}
+	return registry.NewRepository(registry.RateLimitProbeRepository, target.URL, &http.Client{
 		Transport: registry.NewTransport(transport, authorizer),
 	})
 }
@@ -367,3 +538,129 @@ func (t *TargetAPI) ListPolicies() {
 	t.Data["json"] = policies
 	t.ServeJSON()
 }
+
+// GetCABundle returns the summaries of the certificates in the CA bundle of the target
+func (t *TargetAPI) GetCABundle() {
+	target := t.getTarget()
+	if target == nil {
+		return
+	}
+	bundle := target.CABundle
+	if len(bundle) > 0 {
+		var err error
+		if bundle, err = keyring.Decrypt(bundle, t.secretKey); err != nil {
+			t.HandleInternalServerError(fmt.Sprintf("failed to decrypt CA bundle: %v", err))
+			return
+		}
+	}
+	certs, err := certificates(bundle)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to parse the CA bundle of target %d: %v", target.ID, err))
+		return
+	}
+	t.WriteJSONData(certs)
+}
+
+// PutCABundle replaces the CA bundle of the target, which is trusted by the clients of the
+// target in addition to the system ones
+func (t *TargetAPI) PutCABundle() {
+	target := t.getTarget()
+	if target == nil {
+		return
+	}
+	req := &models.CABundleReq{}
+	t.DecodeJSONReq(req)
+	if _, err := registry.ParseCABundle(req.CABundle); err != nil {
+		t.HandleBadRequest(err.Error())
+		return
+	}
+	bundle, err := keyring.Encrypt(req.CABundle, t.secretKey)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to encrypt CA bundle: %v", err))
+		return
+	}
+	if err = dao.UpdateRepTargetCABundle(target.ID, bundle); err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to update the CA bundle of target %d: %v", target.ID, err))
+		return
+	}
+}
+
+// DeleteCABundle removes the CA bundle of the target
+func (t *TargetAPI) DeleteCABundle() {
+	target := t.getTarget()
+	if target == nil {
+		return
+	}
+	if err := dao.UpdateRepTargetCABundle(target.ID, ""); err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to delete the CA bundle of target %d: %v", target.ID, err))
+		return
+	}
+}
+
+// GetRateLimit probes the rate limit of the target, e.g. the pull budget of Docker Hub, the
+// result is recorded and exposed in the metrics as well
+func (t *TargetAPI) GetRateLimit() {
+	target := t.getTarget()
+	if target == nil {
+		return
+	}
+	var err error
+	if len(target.Password) != 0 {
+		if target.Password, err = keyring.Decrypt(target.Password, t.secretKey); err != nil {
+			t.HandleInternalServerError(fmt.Sprintf("failed to decrypt password: %v", err))
+			return
+		}
+	}
+	if len(target.CABundle) != 0 {
+		if target.CABundle, err = keyring.Decrypt(target.CABundle, t.secretKey); err != nil {
+			t.HandleInternalServerError(fmt.Sprintf("failed to decrypt CA bundle: %v", err))
+			return
+		}
+	}
+	if len(target.CredentialRef) > 0 {
+		cred, err := secretstore.Resolve(target.CredentialRef)
+		if err != nil {
+			t.HandleInternalServerError(fmt.Sprintf("failed to resolve the credential %s: %v", target.CredentialRef, err))
+			return
+		}
+		if len(cred.Username) > 0 {
+			target.Username = cred.Username
+		}
+		target.Password = cred.Password
+	}
+
+	repository, err := newRateLimitProbeClient(target)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to create the client of target %d: %v", target.ID, err))
+		return
+	}
+	rateLimit, err := repository.RateLimit("latest")
+	if err != nil {
+		log.Errorf("failed to probe the rate limit of target %d: %v", target.ID, err)
+		// do not return any detail information of the error, or may cause SSRF security issue #3755
+		t.RenderError(http.StatusBadRequest, "failed to probe the rate limit of target")
+		return
+	}
+	if rateLimit == nil {
+		t.HandleNotFound(fmt.Sprintf("target %d doesn't limit the rate", target.ID))
+		return
+	}
+	ratelimit.Record(target.URL, rateLimit)
+	t.WriteJSONData(rateLimit)
+}
+
+// getTarget returns the target specified in the path, nil is returned and the response
+// is written if the target doesn't exist
+func (t *TargetAPI) getTarget() *models.RepTarget {
+	id := t.GetIDFromURL()
+	target, err := dao.GetRepTarget(id)
+	if err != nil {
+		t.HandleInternalServerError(fmt.Sprintf("failed to get target %d: %v", id, err))
+		return nil
+	}
+	if target == nil {
+		t.HandleNotFound(fmt.Sprintf("target %d not found", id))
+		return nil
+	}
+	return target
+}
@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/tests/apitests/apilib"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -187,6 +188,55 @@ func TestTargetGetByID(t *testing.T) {
 
 }
 
+func TestTargetGetByName(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/targets/by_name/" + addTargetName,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/targets/by_name/not_exist_target",
+				credential: admin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	target := &models.RepTarget{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/targets/by_name/" + addTargetName,
+		credential: admin,
+	}, target)
+	require.Nil(t, err)
+	assert.Equal(t, int64(addTargetID), target.ID)
+	assert.Empty(t, target.Password)
+
+	targets := []*models.RepTarget{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    "/api/targets/",
+		queryStruct: struct {
+			Name       string `url:"name"`
+			ExactMatch bool   `url:"exact_match"`
+		}{
+			Name:       addTargetName[1:],
+			ExactMatch: true,
+		},
+		credential: admin,
+	}, &targets)
+	require.Nil(t, err)
+	assert.Len(t, targets, 0)
+}
+
 func TestTargetsPut(t *testing.T) {
 	var httpStatusCode int
 	var err error
@@ -287,3 +337,52 @@ func TestTargetsDelete(t *testing.T) {
 	}
 
 }
+
+func TestTargetsPostWithCredentialRef(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 400, unsupported secret store
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/targets",
+				bodyJSON: map[string]interface{}{
+					"name":           "target-credential-ref",
+					"endpoint":       "https://registry.example.com",
+					"credential_ref": "unknown:harbor/registry",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, both password and credential reference are set
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/targets",
+				bodyJSON: map[string]interface{}{
+					"name":           "target-credential-ref",
+					"endpoint":       "https://registry.example.com",
+					"password":       "Harbor12345",
+					"credential_ref": "vault:secret/data/harbor/registry",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, the credential can't be resolved when pinging
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/targets/ping",
+				bodyJSON: map[string]interface{}{
+					"endpoint":       "https://registry.example.com",
+					"credential_ref": "vault:secret/data/harbor/registry",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
@@ -0,0 +1,125 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/security/local"
+	"github.com/goharbor/harbor/src/common/token"
+)
+
+// the UI tokens are short-lived as they are passed to the embedded tools in the URLs
+const uiTokenTTL = 5 * time.Minute
+
+// UITokenAPI mints the UI tokens, which let the tools embedded in the web UI, e.g. the chart
+// viewer and the log viewer in the iframes, call the APIs of one project without the session
+type UITokenAPI struct {
+	BaseController
+	project *models.Project
+}
+
+type uiTokenReq struct {
+	Audience string `json:"audience"`
+}
+
+type uiTokenResp struct {
+	Token     string    `json:"token"`
+	Audience  string    `json:"audience"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Prepare validates the user and the project, the tokens can only be minted by the users
+// logged in rather than the robots or the other tokens
+func (u *UITokenAPI) Prepare() {
+	u.BaseController.Prepare()
+	if !u.SecurityCtx.IsAuthenticated() {
+		u.HandleUnauthorized()
+		return
+	}
+	if _, ok := u.SecurityCtx.(*local.SecurityContext); !ok {
+		u.HandleForbidden(u.SecurityCtx.GetUsername())
+		return
+	}
+
+	pid, err := u.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		u.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", u.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := u.ProjectMgr.Get(pid)
+	if err != nil {
+		u.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		u.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	if !u.SecurityCtx.HasReadPerm(pid) {
+		u.HandleForbidden(u.SecurityCtx.GetUsername())
+		return
+	}
+	u.project = project
+}
+
+// Post mints the UI token of the current user scoped to the project and the audience
+func (u *UITokenAPI) Post() {
+	req := &uiTokenReq{}
+	u.DecodeJSONReq(req)
+	valid := false
+	for _, audience := range token.UIAudiences {
+		if req.Audience == audience {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		u.HandleBadRequest(fmt.Sprintf("invalid audience %s, it should be one of %v", req.Audience, token.UIAudiences))
+		return
+	}
+
+	user, err := dao.GetUser(models.User{Username: u.SecurityCtx.GetUsername()})
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", u.SecurityCtx.GetUsername(), err))
+		return
+	}
+	if user == nil {
+		u.HandleUnauthorized()
+		return
+	}
+
+	tk, err := token.NewUIToken(user.UserID, u.project.ProjectID, req.Audience, uiTokenTTL)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to create the UI token: %v", err))
+		return
+	}
+	raw, err := tk.Raw()
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to sign the UI token: %v", err))
+		return
+	}
+	u.Ctx.ResponseWriter.WriteHeader(http.StatusCreated)
+	u.Data["json"] = &uiTokenResp{
+		Token:     raw,
+		Audience:  req.Audience,
+		ExpiresAt: time.Unix(tk.Claims.(*token.UIClaims).ExpiresAt, 0).UTC(),
+	}
+	u.ServeJSON()
+}
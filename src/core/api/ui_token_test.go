@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUITokenAPI(t *testing.T) {
+	path := "/api/projects/1/ui_tokens"
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method:   http.MethodPost,
+				url:      path,
+				bodyJSON: &uiTokenReq{Audience: token.AudienceLogViewer},
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/projects/1000/ui_tokens",
+				bodyJSON:   &uiTokenReq{Audience: token.AudienceLogViewer},
+				credential: projGuest,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, unknown audience
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        path,
+				bodyJSON:   &uiTokenReq{Audience: "unknown"},
+				credential: projGuest,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	resp := &uiTokenResp{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        path,
+		bodyJSON:   &uiTokenReq{Audience: token.AudienceChartViewer},
+		credential: projGuest,
+	}, resp))
+	assert.NotEmpty(t, resp.Token)
+	assert.Equal(t, token.AudienceChartViewer, resp.Audience)
+
+	claims := &token.UIClaims{}
+	_, err := token.ParseWithClaims(resp.Token, claims)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), claims.ProjectID)
+	assert.Equal(t, token.AudienceChartViewer, claims.Audience)
+}
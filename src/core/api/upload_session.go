@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// UploadSessionAPI handles request to /api/system/uploads
+type UploadSessionAPI struct {
+	BaseController
+	age time.Duration
+}
+
+// Prepare validates the user and the age
+func (u *UploadSessionAPI) Prepare() {
+	u.BaseController.Prepare()
+	if !u.SecurityCtx.IsAuthenticated() {
+		u.HandleUnauthorized()
+		return
+	}
+	if !u.SecurityCtx.IsSysAdmin() {
+		u.HandleForbidden(u.SecurityCtx.GetUsername())
+		return
+	}
+
+	// the age in hours, use the configured one if not specified
+	olderThan, err := u.GetInt64("older_than", -1)
+	if err != nil || olderThan < -1 {
+		u.HandleBadRequest(fmt.Sprintf("invalid older_than: %s", u.GetString("older_than")))
+		return
+	}
+	if olderThan >= 0 {
+		u.age = time.Duration(olderThan) * time.Hour
+		return
+	}
+	age, err := config.UploadPurgingAge()
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to get the upload purging age: %v", err))
+		return
+	}
+	u.age = age
+}
+
+// List lists the upload sessions which have no activity during the age
+func (u *UploadSessionAPI) List() {
+	before := time.Now().Add(-u.age)
+	query := &models.UploadSessionQuery{
+		Repository:    u.GetString("repository"),
+		UpdatedBefore: &before,
+	}
+	total, err := dao.CountUploadSessions(query)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to count the upload sessions: %v", err))
+		return
+	}
+	query.Page, query.Size = u.GetPaginationParams()
+	sessions, err := dao.ListUploadSessions(query)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to list the upload sessions: %v", err))
+		return
+	}
+
+	u.SetPaginationHeader(total, query.Page, query.Size)
+	u.Data["json"] = sessions
+	u.ServeJSON()
+}
+
+// Purge purges the upload sessions which have no activity during the age
+func (u *UploadSessionAPI) Purge() {
+	count, err := coreutils.PurgeStaleUploadSessions(u.age)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to purge the upload sessions: %v", err))
+		return
+	}
+	u.Data["json"] = struct {
+		Purged int64 `json:"purged"`
+	}{
+		Purged: count,
+	}
+	u.ServeJSON()
+}
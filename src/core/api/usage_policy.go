@@ -0,0 +1,161 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// UsagePolicyAPI handles request to /api/projects/{}/usage_policy, the members of the project
+// can view and acknowledge the usage policy while only the project admin can manage it
+type UsagePolicyAPI struct {
+	BaseController
+	project *models.Project
+}
+
+// Prepare validates the user and the project in the path
+func (u *UsagePolicyAPI) Prepare() {
+	u.BaseController.Prepare()
+	if !u.SecurityCtx.IsAuthenticated() {
+		u.HandleUnauthorized()
+		return
+	}
+
+	pid, err := u.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		u.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", u.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := u.ProjectMgr.Get(pid)
+	if err != nil {
+		u.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		u.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	u.project = project
+
+	if !((u.Ctx.Input.IsGet() || u.Ctx.Input.IsPost()) && u.SecurityCtx.HasReadPerm(pid) ||
+		u.SecurityCtx.HasAllPerm(pid)) {
+		u.HandleForbidden(u.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Get returns the usage policy of the project and whether the current user has acknowledged it
+func (u *UsagePolicyAPI) Get() {
+	policy := u.policy()
+	if policy == nil {
+		return
+	}
+	status := &models.UsagePolicyStatus{
+		Policy:     policy.Policy,
+		UpdateTime: policy.UpdateTime,
+	}
+	user, err := u.user()
+	if err != nil {
+		u.HandleInternalServerError(err.Error())
+		return
+	}
+	if user != nil {
+		ack, err := dao.GetUsagePolicyAck(u.project.ProjectID, user.UserID)
+		if err != nil {
+			u.HandleInternalServerError(fmt.Sprintf("failed to get the acknowledgment of the usage policy: %v", err))
+			return
+		}
+		if ack != nil {
+			status.Acknowledged = true
+			status.AcknowledgedAt = &ack.CreationTime
+		}
+	}
+	u.Data["json"] = status
+	u.ServeJSON()
+}
+
+// Put sets the usage policy of the project, the users must acknowledge it again before pulling
+func (u *UsagePolicyAPI) Put() {
+	req := &models.UsagePolicyReq{}
+	u.DecodeJSONReq(req)
+	if len(strings.TrimSpace(req.Policy)) == 0 {
+		u.HandleBadRequest("the usage policy cannot be empty")
+		return
+	}
+	if err := dao.SetUsagePolicy(u.project.ProjectID, req.Policy); err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to set the usage policy: %v", err))
+		return
+	}
+}
+
+// Delete removes the usage policy of the project, the pulls aren't gated anymore
+func (u *UsagePolicyAPI) Delete() {
+	if u.policy() == nil {
+		return
+	}
+	if err := dao.DeleteUsagePolicy(u.project.ProjectID); err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to delete the usage policy: %v", err))
+		return
+	}
+}
+
+// Acknowledge records that the current user has acknowledged the usage policy of the project
+func (u *UsagePolicyAPI) Acknowledge() {
+	if u.policy() == nil {
+		return
+	}
+	user, err := u.user()
+	if err != nil {
+		u.HandleInternalServerError(err.Error())
+		return
+	}
+	if user == nil {
+		u.HandleBadRequest("only the users can acknowledge the usage policy")
+		return
+	}
+	if err = dao.AcknowledgeUsagePolicy(u.project.ProjectID, user.UserID); err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to acknowledge the usage policy: %v", err))
+		return
+	}
+}
+
+// policy returns the usage policy of the project, nil is returned if the error is handled
+func (u *UsagePolicyAPI) policy() *models.UsagePolicy {
+	policy, err := dao.GetUsagePolicy(u.project.ProjectID)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to get the usage policy of project %d: %v", u.project.ProjectID, err))
+		return nil
+	}
+	if policy == nil {
+		u.HandleNotFound(fmt.Sprintf("the usage policy of project %d not found", u.project.ProjectID))
+		return nil
+	}
+	return policy
+}
+
+// user returns the current user, nil is returned for the robots
+func (u *UsagePolicyAPI) user() (*models.User, error) {
+	user, err := dao.GetUser(models.User{
+		Username: u.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %v", u.SecurityCtx.GetUsername(), err)
+	}
+	return user, nil
+}
@@ -0,0 +1,125 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var usagePolicyPath = "/api/projects/1/usage_policy"
+
+func TestUsagePolicyAPI(t *testing.T) {
+	defer dao.DeleteUsagePolicy(1)
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    usagePolicyPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, the project has no usage policy
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        usagePolicyPath,
+				credential: projGuest,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only project admin can set the usage policy
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    usagePolicyPath,
+				bodyJSON: &models.UsagePolicyReq{
+					Policy: "export controlled",
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, empty policy
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    usagePolicyPath,
+				bodyJSON: &models.UsagePolicyReq{
+					Policy: " ",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    usagePolicyPath,
+				bodyJSON: &models.UsagePolicyReq{
+					Policy: "export controlled",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	status := &models.UsagePolicyStatus{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        usagePolicyPath,
+		credential: projGuest,
+	}, status)
+	require.Nil(t, err)
+	assert.Equal(t, "export controlled", status.Policy)
+	assert.False(t, status.Acknowledged)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodPost,
+			url:        usagePolicyPath + "/acknowledgment",
+			credential: projGuest,
+		},
+		code: http.StatusOK,
+	})
+
+	status = &models.UsagePolicyStatus{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        usagePolicyPath,
+		credential: projGuest,
+	}, status)
+	require.Nil(t, err)
+	assert.True(t, status.Acknowledged)
+	assert.NotNil(t, status.AcknowledgedAt)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        usagePolicyPath,
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+}
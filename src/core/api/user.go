@@ -29,6 +29,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/scopeusage"
 )
 
 // UserAPI handles request to /api/users/{}
@@ -121,6 +122,10 @@ func (ua *UserAPI) Get() {
 		if ua.userID == ua.currentUserID {
 			u.HasAdminRole = ua.SecurityCtx.IsSysAdmin()
 		}
+		if u.ComplianceOfficer, err = dao.IsComplianceOfficer(u.UserID); err != nil {
+			ua.HandleInternalServerError(fmt.Sprintf("failed to check whether user %d is a compliance officer: %v", u.UserID, err))
+			return
+		}
 		ua.Data["json"] = u
 		ua.ServeJSON()
 		return
@@ -341,6 +346,22 @@ func (ua *UserAPI) ToggleUserAdminRole() {
 	}
 }
 
+// SetComplianceOfficer handles PUT api/users/{}/compliance_officer, it grants the user the
+// permission of managing the legal holds or revokes it
+func (ua *UserAPI) SetComplianceOfficer() {
+	if !ua.IsAdmin {
+		log.Warningf("current user, id: %d does not have admin role, can not update other user's role", ua.currentUserID)
+		ua.RenderError(http.StatusForbidden, "User does not have admin role")
+		return
+	}
+	userQuery := models.User{}
+	ua.DecodeJSONReq(&userQuery)
+	if err := dao.SetComplianceOfficer(ua.userID, userQuery.ComplianceOfficer); err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to set the compliance officer %d: %v", ua.userID, err))
+		return
+	}
+}
+
 // ListUserPermissions handles GET to /api/users/{}/permissions
 func (ua *UserAPI) ListUserPermissions() {
 	if ua.userID != ua.currentUserID {
@@ -351,7 +372,8 @@ func (ua *UserAPI) ListUserPermissions() {
 
 	relative := ua.Ctx.Input.Query("relative") == "true"
 
-	scope := rbac.Resource(ua.Ctx.Input.Query("scope"))
+	// the scope can be in the short form, e.g. "project:1"
+	scope := rbac.ParseScope(ua.Ctx.Input.Query("scope"))
 	policies := []*rbac.Policy{}
 
 	namespace, err := scope.GetNamespace()
@@ -392,6 +414,149 @@ func (ua *UserAPI) ListUserPermissions() {
 	return
 }
 
+// ScopeUsage reports the repositories the user pulled and pushed in the time range versus
+// the permissions the user holds, only the system admin and the user are allowed
+func (ua *UserAPI) ScopeUsage() {
+	if ua.userID != ua.currentUserID && !ua.IsAdmin {
+		ua.HandleForbidden(ua.SecurityCtx.GetUsername())
+		return
+	}
+	begin, end, err := ua.GetTimeRangeQuery()
+	if err != nil {
+		ua.HandleBadRequest(err.Error())
+		return
+	}
+	user, err := dao.GetUser(models.User{UserID: ua.userID})
+	if err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to get user %d: %v", ua.userID, err))
+		return
+	}
+	usage, err := scopeusage.ForUser(user, begin, end)
+	if err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to get the scope usage of user %d: %v", ua.userID, err))
+		return
+	}
+	ua.Data["json"] = usage
+	ua.ServeJSON()
+}
+
+// ListStarred handles GET to /api/users/current/starred, returns the repositories starred by current user
+func (ua *UserAPI) ListStarred() {
+	page, size := ua.GetPaginationParams()
+	// skip the repositories which current user has no permission to access anymore, they're
+	// filtered in the SQL so that the pages and the total are consistent
+	query, err := visibilityQuery(ua.SecurityCtx, "", 0)
+	if err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to get the projects visible to user %d: %v", ua.currentUserID, err))
+		return
+	}
+	total, err := dao.CountStarredRepositories(ua.currentUserID, query)
+	if err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to count the starred repositories of user %d: %v", ua.currentUserID, err))
+		return
+	}
+	repositories, err := dao.GetStarredRepositories(ua.currentUserID, query, page, size)
+	if err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to get the starred repositories of user %d: %v", ua.currentUserID, err))
+		return
+	}
+
+	ua.SetPaginationHeader(total, page, size)
+	ua.Data["json"] = assembleReposInParallel(repositories)
+	ua.ServeJSON()
+}
+
+// Usage handles GET to /api/users/current/usage, summarizes the storage usage, the quota
+// remaining and the count of the artifacts of the projects which current user can push to
+func (ua *UserAPI) Usage() {
+	var projects []*models.Project
+	if ua.IsAdmin {
+		result, err := ua.ProjectMgr.List(nil)
+		if err != nil {
+			ua.ParseAndHandleError("failed to list projects", err)
+			return
+		}
+		projects = result.Projects
+	} else {
+		var err error
+		projects, err = ua.SecurityCtx.GetMyProjects()
+		if err != nil {
+			ua.ParseAndHandleError(fmt.Sprintf("failed to get the projects of user %d", ua.currentUserID), err)
+			return
+		}
+	}
+
+	usage := &models.UserUsage{
+		Projects: []*models.ProjectUsage{},
+	}
+	for _, p := range projects {
+		if !ua.SecurityCtx.HasWritePerm(p.ProjectID) {
+			continue
+		}
+		projectUsage, err := getProjectUsage(p)
+		if err != nil {
+			ua.HandleInternalServerError(fmt.Sprintf("failed to get the usage of project %s: %v", p.Name, err))
+			return
+		}
+		usage.StorageUsage += projectUsage.StorageUsage
+		usage.RepoCount += projectUsage.RepoCount
+		usage.TagCount += projectUsage.TagCount
+		usage.Projects = append(usage.Projects, projectUsage)
+	}
+	ua.Data["json"] = usage
+	ua.ServeJSON()
+}
+
+// getProjectUsage returns the usage of the project, the repositories whose tags can't be
+// listed are counted without tags
+func getProjectUsage(p *models.Project) (*models.ProjectUsage, error) {
+	storage, err := dao.GetProjectUsage(p.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{p.ProjectID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := &models.ProjectUsage{
+		ProjectID:      p.ProjectID,
+		ProjectName:    p.Name,
+		StorageUsage:   storage,
+		StorageQuota:   p.StorageQuota(),
+		QuotaRemaining: -1,
+		RepoCount:      int64(len(repositories)),
+	}
+	if usage.StorageQuota >= 0 {
+		usage.QuotaRemaining = usage.StorageQuota - storage
+		if usage.QuotaRemaining < 0 {
+			usage.QuotaRemaining = 0
+		}
+	}
+
+	for _, repository := range repositories {
+		usage.PullCount += repository.PullCount
+	}
+
+	counts := make(chan int64)
+	for _, repository := range repositories {
+		go func(name string) {
+			tags, err := getTags(name)
+			if err != nil {
+				log.Errorf("failed to list tags of %s: %v", name, err)
+				counts <- 0
+				return
+			}
+			counts <- int64(len(tags))
+		}(repository.Name)
+	}
+	for range repositories {
+		usage.TagCount += <-counts
+	}
+	return usage, nil
+}
+
 // modifiable returns whether the modify is allowed based on current auth mode and context
 func (ua *UserAPI) modifiable() bool {
 	if ua.AuthMode == common.DBAuth {
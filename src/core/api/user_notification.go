@@ -0,0 +1,113 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// UserNotificationAPI handles request to /api/users/current/notifications, the users
+// can only view and manage their own notifications
+type UserNotificationAPI struct {
+	BaseController
+	userID int
+	id     int64
+}
+
+// Prepare validates the user and the notification ID in the path
+func (u *UserNotificationAPI) Prepare() {
+	u.BaseController.Prepare()
+	if !u.SecurityCtx.IsAuthenticated() {
+		u.HandleUnauthorized()
+		return
+	}
+	user, err := dao.GetUser(models.User{
+		Username: u.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v", u.SecurityCtx.GetUsername(), err))
+		return
+	}
+	// the robot accounts and the solution users have no notification
+	if user == nil {
+		u.HandleForbidden(u.SecurityCtx.GetUsername())
+		return
+	}
+	u.userID = user.UserID
+
+	if len(u.GetStringFromPath(":id")) > 0 {
+		id, err := u.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			u.HandleBadRequest(fmt.Sprintf("invalid notification ID: %s", u.GetStringFromPath(":id")))
+			return
+		}
+		u.id = id
+	}
+}
+
+// List lists the notifications of current user, the newest first, only the unread
+// ones are listed if the query parameter "unread" is true
+func (u *UserNotificationAPI) List() {
+	unread, err := u.GetBool("unread", false)
+	if err != nil {
+		u.HandleBadRequest(fmt.Sprintf("invalid unread: %s", u.GetString("unread")))
+		return
+	}
+	query := &models.UserNotificationQuery{
+		UserID: u.userID,
+		Unread: unread,
+	}
+	total, err := dao.CountUserNotifications(query)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to count the notifications of user %d: %v", u.userID, err))
+		return
+	}
+	query.Page, query.Size = u.GetPaginationParams()
+	notifications, err := dao.ListUserNotifications(query)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to list the notifications of user %d: %v", u.userID, err))
+		return
+	}
+	u.SetPaginationHeader(total, query.Page, query.Size)
+	u.Data["json"] = notifications
+	u.ServeJSON()
+}
+
+// MarkRead marks the notification specified by ID as read, or all the notifications
+// of current user if no ID is specified
+func (u *UserNotificationAPI) MarkRead() {
+	ids := []int64{}
+	if u.id > 0 {
+		ids = append(ids, u.id)
+	}
+	if _, err := dao.MarkUserNotificationsRead(u.userID, ids...); err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to mark the notifications of user %d as read: %v", u.userID, err))
+	}
+}
+
+// Delete dismisses the notification
+func (u *UserNotificationAPI) Delete() {
+	deleted, err := dao.DeleteUserNotification(u.userID, u.id)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to delete the notification %d: %v", u.id, err))
+		return
+	}
+	if !deleted {
+		u.HandleNotFound(fmt.Sprintf("notification %d not found", u.id))
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userNotificationPath = "/api/users/current/notifications"
+
+func findUserNotification(notifications []*models.UserNotification, message string) *models.UserNotification {
+	for _, n := range notifications {
+		if n.Message == message {
+			return n
+		}
+	}
+	return nil
+}
+
+func TestUserNotificationAPI(t *testing.T) {
+	messages := []string{"notification api test 1", "notification api test 2"}
+	for _, message := range messages {
+		require.Nil(t, dao.AddUserNotifications([]int{int(projGuestID)},
+			models.UserNotificationQuotaWarning, message))
+	}
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    userNotificationPath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 400, invalid unread
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    userNotificationPath,
+				queryStruct: struct {
+					Unread string `url:"unread"`
+				}{"invalid"},
+				credential: projGuest,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	notifications := []*models.UserNotification{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        userNotificationPath,
+		credential: projGuest,
+	}, &notifications)
+	require.Nil(t, err)
+	first := findUserNotification(notifications, messages[0])
+	second := findUserNotification(notifications, messages[1])
+	require.NotNil(t, first)
+	require.NotNil(t, second)
+	assert.Equal(t, models.UserNotificationQuotaWarning, first.Type)
+	assert.False(t, first.Read)
+
+	// the notifications of others are invisible
+	notifications = []*models.UserNotification{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        userNotificationPath,
+		credential: projDeveloper,
+	}, &notifications)
+	require.Nil(t, err)
+	assert.Nil(t, findUserNotification(notifications, messages[0]))
+
+	cases = []*codeCheckingCase{
+		// 200, mark one as read
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/read", userNotificationPath, first.ID),
+				credential: projGuest,
+			},
+			code: http.StatusOK,
+		},
+		// 404, dismiss the notification of others
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", userNotificationPath, second.ID),
+				credential: projDeveloper,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	notifications = []*models.UserNotification{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    userNotificationPath,
+		queryStruct: struct {
+			Unread bool `url:"unread"`
+		}{true},
+		credential: projGuest,
+	}, &notifications)
+	require.Nil(t, err)
+	assert.Nil(t, findUserNotification(notifications, messages[0]))
+	assert.NotNil(t, findUserNotification(notifications, messages[1]))
+
+	cases = []*codeCheckingCase{
+		// 200, mark all as read
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        userNotificationPath + "/read",
+				credential: projGuest,
+			},
+			code: http.StatusOK,
+		},
+		// 200, dismiss
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", userNotificationPath, first.ID),
+				credential: projGuest,
+			},
+			code: http.StatusOK,
+		},
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", userNotificationPath, second.ID),
+				credential: projGuest,
+			},
+			code: http.StatusOK,
+		},
+		// 404, already dismissed
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", userNotificationPath, first.ID),
+				credential: projGuest,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
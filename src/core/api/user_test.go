@@ -584,6 +584,19 @@ func TestUsersCurrentPermissions(t *testing.T) {
 	assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
 	assert.NotEmpty(permissions, "permissions should not be empty")
 
+	// the scope in the short form
+	httpStatusCode, permissions, err = apiTest.UsersGetPermissions("current", "project:1", *projAdmin)
+	assert.Nil(err)
+	assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
+	assert.NotEmpty(permissions, "permissions should not be empty")
+
+	// the guest can pull but not push
+	httpStatusCode, permissions, err = apiTest.UsersGetPermissions("current", "project:1", *projGuest)
+	assert.Nil(err)
+	assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
+	assert.Contains(permissions, apilib.Permission{Resource: "/project/1/repository", Action: "pull"})
+	assert.NotContains(permissions, apilib.Permission{Resource: "/project/1/repository", Action: "push"})
+
 	httpStatusCode, permissions, err = apiTest.UsersGetPermissions("current", "/unsupport-scope", *projAdmin)
 	assert.Nil(err)
 	assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
@@ -597,3 +610,76 @@ func TestUsersCurrentPermissions(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(int(403), httpStatusCode, "httpStatusCode should be 403")
 }
+
+func TestUsersScopeUsage(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/users/1/scope_usage",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/users/1/scope_usage",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/users/1/scope_usage",
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
+
+func TestUsersUsage(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/users/current/usage",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/users/current/usage",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+
+	// the project admin can push to the project "library"
+	usage := &models.UserUsage{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/users/current/usage",
+		credential: projAdmin,
+	}, usage)
+	require.Nil(t, err)
+	found := false
+	for _, p := range usage.Projects {
+		if p.ProjectName == "library" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
@@ -54,8 +54,13 @@ func SyncRegistry(pm promgr.ProjectManager) error {
 	}
 
 	var reposInDB []string
+	// the repositories created before the first push aren't in the registry
+	preCreated := map[string]bool{}
 	for _, repoRecordInDB := range repoRecordsInDB {
 		reposInDB = append(reposInDB, repoRecordInDB.Name)
+		if repoRecordInDB.PreCreated {
+			preCreated[repoRecordInDB.Name] = true
+		}
 	}
 
 	var reposToAdd []string
@@ -95,6 +100,10 @@ func SyncRegistry(pm promgr.ProjectManager) error {
 	if len(reposToDel) > 0 {
 		log.Debugf("Start deleting repositories from DB... ")
 		for _, repoToDel := range reposToDel {
+			if preCreated[repoToDel] {
+				log.Debugf("Keep the pre-created repository: %s.", repoToDel)
+				continue
+			}
 			if err := dao.DeleteRepository(repoToDel); err != nil {
 				log.Errorf("Error happens when deleting the repository: %v", err)
 			} else {
@@ -281,8 +290,9 @@ func repositoryExist(name string, client *registry.Repository) (bool, error) {
 	return len(tags) != 0, nil
 }
 
-// transformVulnerabilities transforms the returned value of Clair API to a list of VulnerabilityItem
-func transformVulnerabilities(layerWithVuln *models.ClairLayerEnvelope) []*models.VulnerabilityItem {
+// transformVulnerabilities transforms the returned value of Clair API to a list of VulnerabilityItem,
+// the severity is decided according to the source of CVSS
+func transformVulnerabilities(layerWithVuln *models.ClairLayerEnvelope, cvssSource string) []*models.VulnerabilityItem {
 	res := []*models.VulnerabilityItem{}
 	l := layerWithVuln.Layer
 	if l == nil {
@@ -302,7 +312,7 @@ func transformVulnerabilities(layerWithVuln *models.ClairLayerEnvelope) []*model
 				ID:          v.Name,
 				Pkg:         f.Name,
 				Version:     f.Version,
-				Severity:    clair.ParseClairSev(v.Severity),
+				Severity:    clair.ParseVulnSev(v, cvssSource),
 				Fixed:       v.FixedBy,
 				Link:        v.Link,
 				Description: v.Description,
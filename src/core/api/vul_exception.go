@@ -0,0 +1,214 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+)
+
+// VulExceptionAPI handles the requests on /api/repositories/*/vul_exception to exempt the
+// repository from the policy "prevent vulnerable images from running" of its project, and
+// the listing of the exceptions of the project on /api/projects/:id/vul_exceptions. The
+// exceptions are managed by the project admins and recorded in the access log
+type VulExceptionAPI struct {
+	BaseController
+	project *models.Project
+	// empty when the exceptions of the project are listed
+	repository string
+}
+
+// Prepare ...
+func (v *VulExceptionAPI) Prepare() {
+	v.BaseController.Prepare()
+	if !v.SecurityCtx.IsAuthenticated() {
+		v.HandleUnauthorized()
+		return
+	}
+
+	name := v.GetString(":splat")
+	var project *models.Project
+	if len(name) == 0 {
+		pid, err := v.GetInt64FromPath(":id")
+		if err != nil || pid <= 0 {
+			v.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", v.GetStringFromPath(":id")))
+			return
+		}
+		if project, err = v.ProjectMgr.Get(pid); err != nil {
+			v.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+			return
+		}
+		if project == nil {
+			v.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+			return
+		}
+	} else {
+		repository, err := dao.GetRepositoryByName(name)
+		if err != nil {
+			v.HandleInternalServerError(fmt.Sprintf("failed to get repository %s: %v", name, err))
+			return
+		}
+		if repository == nil {
+			v.HandleNotFound(v.T(i18n.MsgRepositoryNotFound, name))
+			return
+		}
+		projectName, _ := utils.ParseRepository(name)
+		if project, err = v.ProjectMgr.Get(projectName); err != nil {
+			v.ParseAndHandleError(fmt.Sprintf("failed to get project %s", projectName), err)
+			return
+		}
+		if project == nil {
+			v.HandleNotFound(v.T(i18n.MsgProjectNotFound, projectName))
+			return
+		}
+	}
+
+	if v.Ctx.Request.Method == http.MethodGet {
+		if !v.SecurityCtx.HasReadPerm(project.ProjectID) {
+			v.HandleForbidden(v.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !v.SecurityCtx.HasAllPerm(project.ProjectID) {
+		v.HandleForbidden(v.SecurityCtx.GetUsername())
+		return
+	}
+	v.project = project
+	v.repository = name
+}
+
+// List lists the exceptions of the project including the expired ones, the query parameter
+// "active" lists the exceptions which aren't expired only
+func (v *VulExceptionAPI) List() {
+	active, err := v.GetBool("active", false)
+	if err != nil {
+		v.HandleBadRequest(fmt.Sprintf("invalid active: %s", v.GetString("active")))
+		return
+	}
+	query := &models.VulExceptionQuery{
+		ProjectID: v.project.ProjectID,
+		Active:    active,
+	}
+	total, err := dao.CountVulExceptions(query)
+	if err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to count the vulnerability exceptions: %v", err))
+		return
+	}
+	query.Page, query.Size = v.GetPaginationParams()
+	exceptions, err := dao.ListVulExceptions(query)
+	if err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to list the vulnerability exceptions: %v", err))
+		return
+	}
+	v.SetPaginationHeader(total, query.Page, query.Size)
+	v.Data["json"] = exceptions
+	v.ServeJSON()
+}
+
+// Get returns the exception of the repository, the expired one is returned as well
+func (v *VulExceptionAPI) Get() {
+	exception, err := v.get()
+	if err != nil {
+		v.HandleInternalServerError(err.Error())
+		return
+	}
+	if exception == nil {
+		v.HandleNotFound(fmt.Sprintf("repository %s has no vulnerability exception", v.repository))
+		return
+	}
+	v.Data["json"] = exception
+	v.ServeJSON()
+}
+
+// Put exempts the repository from the policy until the expiration, or updates the
+// justification and the expiration of the existing exception
+func (v *VulExceptionAPI) Put() {
+	exception := &models.VulException{}
+	v.DecodeJSONReqAndValidate(exception)
+	existing, err := v.get()
+	if err != nil {
+		v.HandleInternalServerError(err.Error())
+		return
+	}
+	exception.ProjectID = v.project.ProjectID
+	exception.Repository = v.repository
+	exception.Creator = v.SecurityCtx.GetUsername()
+	if err = dao.SetVulException(exception); err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to set the vulnerability exception of repository %s: %v", v.repository, err))
+		return
+	}
+	operation := "create_vul_exception"
+	if existing != nil {
+		operation = "update_vul_exception"
+	}
+	v.addAccessLog(operation, exception)
+}
+
+// Delete removes the exception, the policy applies to the repository immediately
+func (v *VulExceptionAPI) Delete() {
+	exception, err := v.get()
+	if err != nil {
+		v.HandleInternalServerError(err.Error())
+		return
+	}
+	if exception == nil {
+		v.HandleNotFound(fmt.Sprintf("repository %s has no vulnerability exception", v.repository))
+		return
+	}
+	if err = dao.DeleteVulException(v.repository); err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to delete the vulnerability exception of repository %s: %v", v.repository, err))
+		return
+	}
+	v.addAccessLog("delete_vul_exception", exception)
+}
+
+func (v *VulExceptionAPI) get() (*models.VulException, error) {
+	exception, err := dao.GetVulException(v.repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the vulnerability exception of repository %s: %v", v.repository, err)
+	}
+	return exception, nil
+}
+
+// addAccessLog records the lifecycle of the exception with the justification and the
+// expiration in the access log
+func (v *VulExceptionAPI) addAccessLog(operation string, exception *models.VulException) {
+	detail, err := json.Marshal(map[string]interface{}{
+		"justification": exception.Justification,
+		"expires_at":    exception.ExpiresAt,
+	})
+	if err != nil {
+		log.Errorf("failed to marshal the detail of the access log: %v", err)
+	}
+	if err := accesslog.Add(models.AccessLog{
+		Username:  v.SecurityCtx.GetUsername(),
+		ProjectID: v.project.ProjectID,
+		RepoName:  v.repository,
+		RepoTag:   "N/A",
+		Operation: operation,
+		OpTime:    time.Now(),
+		Detail:    string(detail),
+	}); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulExceptionAPI(t *testing.T) {
+	path := "/api/repositories/library/hello-world/vul_exception"
+	defer dao.ClearTable(models.VulExceptionTable)
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    path,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 404, no exception
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        path,
+				credential: projGuest,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403, only the project admins can manage the exceptions
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        path,
+				credential: projDeveloper,
+				bodyJSON: &models.VulException{
+					Justification: "false positive",
+					ExpiresAt:     time.Now().Add(time.Hour),
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400, the expiration is required
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &models.VulException{
+					Justification: "false positive",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        path,
+				credential: projAdmin,
+				bodyJSON: &models.VulException{
+					Justification: "false positive",
+					ExpiresAt:     time.Now().Add(time.Hour),
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	exception := &models.VulException{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: projGuest,
+	}, exception))
+	assert.Equal(t, "library/hello-world", exception.Repository)
+	assert.Equal(t, "false positive", exception.Justification)
+	assert.Equal(t, projAdmin.Name, exception.Creator)
+
+	exceptions := []*models.VulException{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/projects/1/vul_exceptions",
+		credential: projGuest,
+	}, &exceptions))
+	require.Equal(t, 1, len(exceptions))
+
+	cases = []*codeCheckingCase{
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        path,
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404, deleted already
+		{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        path,
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// the lifecycle of the exception is recorded in the access log
+	logs, err := dao.GetAccessLogs(&models.LogQueryParam{
+		Repository: "library/hello-world",
+		Operations: []string{"create_vul_exception", "delete_vul_exception"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(logs))
+}
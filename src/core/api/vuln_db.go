@@ -0,0 +1,118 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/vulndb"
+)
+
+// VulnDBAPI handles request to /api/system/vulnerability_db/imports
+type VulnDBAPI struct {
+	BaseController
+}
+
+// Prepare validates the user
+func (v *VulnDBAPI) Prepare() {
+	v.BaseController.Prepare()
+	if !v.SecurityCtx.IsAuthenticated() {
+		v.HandleUnauthorized()
+		return
+	}
+	if !v.SecurityCtx.IsSysAdmin() {
+		v.HandleForbidden(v.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Post uploads a vulnerability database bundle and imports it into the scanner in background
+func (v *VulnDBAPI) Post() {
+	if !config.WithClair() {
+		v.HandleStatusPreconditionFailed("Harbor is not deployed with Clair")
+		return
+	}
+
+	file, _, err := v.GetFile("bundle")
+	if err != nil {
+		v.HandleBadRequest(fmt.Sprintf("failed to get the bundle: %v", err))
+		return
+	}
+	defer file.Close()
+
+	bundle, err := vulndb.ParseBundle(file)
+	if err != nil {
+		v.HandleBadRequest(fmt.Sprintf("invalid bundle: %v", err))
+		return
+	}
+
+	id, err := vulndb.Import(bundle, v.SecurityCtx.GetUsername())
+	if err != nil {
+		if err == vulndb.ErrImportRunning {
+			v.HandleConflict(err.Error())
+			return
+		}
+		v.HandleInternalServerError(fmt.Sprintf("failed to import the bundle: %v", err))
+		return
+	}
+	v.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List lists the imports of vulnerability database bundles
+func (v *VulnDBAPI) List() {
+	query := &models.VulnDBImportQuery{
+		Status: v.GetString("status"),
+	}
+	total, err := dao.CountVulnDBImports(query)
+	if err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to count the vulnerability database imports: %v", err))
+		return
+	}
+	query.Page, query.Size = v.GetPaginationParams()
+	imports, err := dao.ListVulnDBImports(query)
+	if err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to list the vulnerability database imports: %v", err))
+		return
+	}
+
+	v.SetPaginationHeader(total, query.Page, query.Size)
+	v.Data["json"] = imports
+	v.ServeJSON()
+}
+
+// Get gets the import specified by ID
+func (v *VulnDBAPI) Get() {
+	id, err := v.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		v.HandleBadRequest(fmt.Sprintf("invalid import ID: %s", v.GetStringFromPath(":id")))
+		return
+	}
+	imp, err := dao.GetVulnDBImport(id)
+	if err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to get the vulnerability database import %d: %v", id, err))
+		return
+	}
+	if imp == nil {
+		v.HandleNotFound(fmt.Sprintf("vulnerability database import %d not found", id))
+		return
+	}
+	v.Data["json"] = imp
+	v.ServeJSON()
+}
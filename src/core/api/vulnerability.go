@@ -0,0 +1,51 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+)
+
+// VulnerabilityAPI handles request to /api/vulnerabilities
+type VulnerabilityAPI struct {
+	BaseController
+}
+
+// Prepare validates the user, only the system admin is allowed to search across the registry
+func (v *VulnerabilityAPI) Prepare() {
+	v.BaseController.Prepare()
+	if !v.SecurityCtx.IsAuthenticated() {
+		v.HandleUnauthorized()
+		return
+	}
+	if !v.SecurityCtx.IsSysAdmin() {
+		v.HandleForbidden(v.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Affected returns the images whose latest scan contains the vulnerability
+func (v *VulnerabilityAPI) Affected() {
+	cveID := v.GetStringFromPath(":cve_id")
+	artifacts, err := dao.ListAffectedArtifacts(cveID)
+	if err != nil {
+		v.HandleInternalServerError(fmt.Sprintf("failed to list the images affected by %s: %v", cveID, err))
+		return
+	}
+	v.Data["json"] = artifacts
+	v.ServeJSON()
+}
@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulnerabilityAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/vulnerabilities/CVE-2014-0160/affected",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/vulnerabilities/CVE-2014-0160/affected",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	digest := "sha256:2d91a1d4d7bba33e44cd0c3a2c4d2a1b7ea5d5fc0c1a5a6d15f5b1f21e0b8a11"
+	_, err := dao.AddScanJob(models.ScanJob{
+		Status:     models.JobFinished,
+		Repository: "library/vuln",
+		Tag:        "latest",
+		Digest:     digest,
+	})
+	require.Nil(t, err)
+	defer dao.ClearTable(models.ScanJobTable)
+	require.Nil(t, dao.ReplaceVulnFindings(digest, []*models.VulnFinding{
+		{CVEID: "CVE-2014-0160", Package: "openssl", Version: "1.0.1", Severity: models.SevHigh},
+	}))
+	defer dao.ClearTable(models.VulnFindingTable)
+
+	artifacts := []*models.AffectedArtifact{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/vulnerabilities/CVE-2014-0160/affected",
+		credential: sysAdmin,
+	}, &artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(artifacts))
+	assert.Equal(t, "library", artifacts[0].Project)
+	assert.Equal(t, "library/vuln", artifacts[0].Repository)
+	assert.Equal(t, "latest", artifacts[0].Tag)
+	assert.Equal(t, digest, artifacts[0].Digest)
+}
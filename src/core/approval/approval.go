@@ -0,0 +1,143 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval is the engine of the actions waiting for the review of admins, e.g. the
+// promotions, access requests and project creations. The actions are executed by the handlers
+// registered for the types of approvals once they are approved, and the events of approvals
+// are posted to the webhook.
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/notifier"
+)
+
+// ErrNotPending is returned when the approval to review isn't pending
+var ErrNotPending = errors.New("the approval isn't pending")
+
+// Handler executes the action of the approval once it's reviewed
+type Handler interface {
+	// Approve executes the action, it returns ErrNotPending if the action has been reviewed
+	Approve(approval *models.Approval, reviewer, comment string) error
+	// Reject cancels the action, it returns ErrNotPending if the action has been reviewed
+	Reject(approval *models.Approval, reviewer, comment string) error
+}
+
+var (
+	handlers = map[string]Handler{}
+	// serializes the reviews so that an approval is executed only once
+	lock sync.Mutex
+)
+
+// Register registers the handler of the type of approval
+func Register(approvalType string, handler Handler) {
+	handlers[approvalType] = handler
+}
+
+// Submit records the pending approval and publishes the event
+func Submit(approval *models.Approval) (int64, error) {
+	if _, ok := handlers[approval.Type]; !ok {
+		return 0, fmt.Errorf("no handler registered for the approval type %s", approval.Type)
+	}
+	approval.Status = models.ApprovalPending
+	id, err := dao.AddApproval(approval)
+	if err != nil {
+		return 0, err
+	}
+	approval.ID = id
+	publish(notifier.ApprovalEventSubmitted, approval)
+	return id, nil
+}
+
+// Review approves or rejects the approval with the comment, the action is executed by the
+// handler before the status of the approval is updated
+func Review(approval *models.Approval, approved bool, reviewer, comment string) error {
+	handler, ok := handlers[approval.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for the approval type %s", approval.Type)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	// the approval isn't recorded if the action is requested before the engine is introduced
+	if approval.ID > 0 {
+		current, err := dao.GetApproval(approval.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Status != models.ApprovalPending {
+			return ErrNotPending
+		}
+	}
+
+	status, event := models.ApprovalApproved, notifier.ApprovalEventApproved
+	var err error
+	if approved {
+		err = handler.Approve(approval, reviewer, comment)
+	} else {
+		status, event = models.ApprovalRejected, notifier.ApprovalEventRejected
+		err = handler.Reject(approval, reviewer, comment)
+	}
+	if err != nil {
+		return err
+	}
+
+	if approval.ID > 0 {
+		n, err := dao.UpdateApprovalStatus(approval.ID, status, reviewer, comment)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrNotPending
+		}
+	}
+	approval.Status = status
+	approval.Reviewer = reviewer
+	approval.Comment = comment
+	publish(event, approval)
+	return nil
+}
+
+// ReviewResource reviews the latest approval of the resource, it's used by the APIs reviewing
+// the access requests and promotions directly
+func ReviewResource(approvalType string, resourceID int64, approved bool, reviewer, comment string) error {
+	approval, err := dao.GetApprovalByResource(approvalType, resourceID)
+	if err != nil {
+		return err
+	}
+	if approval == nil {
+		approval = &models.Approval{
+			Type:       approvalType,
+			ResourceID: resourceID,
+		}
+	}
+	return Review(approval, approved, reviewer, comment)
+}
+
+func publish(event string, approval *models.Approval) {
+	if err := notifier.Publish(notifier.ApprovalTopic, notifier.ApprovalEvent{
+		Event:    event,
+		Approval: approval,
+		OccurAt:  time.Now().UTC(),
+	}); err != nil {
+		log.Errorf("failed to publish the %s event of approval %d: %v", event, approval.ID, err)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHandler struct {
+	approved []int64
+	rejected []int64
+	err      error
+}
+
+func (f *fakeHandler) Approve(approval *models.Approval, reviewer, comment string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.approved = append(f.approved, approval.ResourceID)
+	return nil
+}
+
+func (f *fakeHandler) Reject(approval *models.Approval, reviewer, comment string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.rejected = append(f.rejected, approval.ResourceID)
+	return nil
+}
+
+func TestReview(t *testing.T) {
+	handler := &fakeHandler{}
+	Register("fake", handler)
+	defer delete(handlers, "fake")
+
+	// unknown type
+	_, err := Submit(&models.Approval{
+		Type: "unknown",
+	})
+	assert.NotNil(t, err)
+	assert.NotNil(t, Review(&models.Approval{
+		Type: "unknown",
+	}, true, "admin", ""))
+
+	// the approval isn't recorded
+	approval := &models.Approval{
+		Type:       "fake",
+		ResourceID: 1,
+	}
+	require.Nil(t, Review(approval, true, "admin", "lgtm"))
+	assert.Equal(t, []int64{1}, handler.approved)
+	assert.Equal(t, models.ApprovalApproved, approval.Status)
+	assert.Equal(t, "admin", approval.Reviewer)
+	assert.Equal(t, "lgtm", approval.Comment)
+
+	approval = &models.Approval{
+		Type:       "fake",
+		ResourceID: 2,
+	}
+	require.Nil(t, Review(approval, false, "admin", ""))
+	assert.Equal(t, []int64{2}, handler.rejected)
+	assert.Equal(t, models.ApprovalRejected, approval.Status)
+
+	// the status isn't changed if the handler fails
+	handler.err = errors.New("failed")
+	approval = &models.Approval{
+		Type:       "fake",
+		ResourceID: 3,
+		Status:     models.ApprovalPending,
+	}
+	assert.NotNil(t, Review(approval, true, "admin", ""))
+	assert.Equal(t, models.ApprovalPending, approval.Status)
+}
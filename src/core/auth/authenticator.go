@@ -191,6 +191,16 @@ func SearchUser(username string) (*models.User, error) {
 	return helper.SearchUser(username)
 }
 
+// SearchUserInMode searches the user with the authenticator of the auth mode rather than the
+// current one, it's used to check the users before switching the auth mode
+func SearchUserInMode(mode, username string) (*models.User, error) {
+	helper, ok := registry[mode]
+	if !ok {
+		return nil, fmt.Errorf("Can not get authenticator, authmode: %s", mode)
+	}
+	return helper.SearchUser(username)
+}
+
 // OnBoardGroup - Create a user group in harbor db, if altGroupName is not empty, take the altGroupName as groupName in harbor DB
 func OnBoardGroup(userGroup *models.UserGroup, altGroupName string) error {
 	helper, err := getHelper()
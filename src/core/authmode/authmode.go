@@ -0,0 +1,258 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authmode checks what would break when the auth mode is switched with the existing
+// users, and converts the users to the new auth mode before switching it.
+package authmode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	ldapUtils "github.com/goharbor/harbor/src/common/utils/ldap"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/auth"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the comments of the users onboarded by the authenticators, the same as the ones set by them
+var comments = map[string]string{
+	common.LDAPAuth: "from LDAP.",
+	common.UAAAuth:  "From UAA",
+}
+
+// searchFunc searches the user in the directory of the auth mode, nil is returned if the
+// user isn't found
+type searchFunc func(username string) (*models.User, error)
+
+var (
+	listUsers           = dao.ListUsers
+	getUserMemberships  = project.GetUserMemberships
+	getGroupMemberships = project.GetGroupMembershipsByType
+	changeUserProfile   = dao.ChangeUserProfile
+	uploadConfig        = config.Upload
+	newSearcher         = defaultSearcher
+)
+
+// Validate checks whether the auth mode can be switched to the target one, only the modes
+// which can be set by the API of configurations are supported
+func Validate(current, target string) error {
+	switch target {
+	case common.DBAuth, common.LDAPAuth, common.UAAAuth:
+	default:
+		return fmt.Errorf("invalid auth mode %s, should be one of %s, %s, %s", target,
+			common.DBAuth, common.LDAPAuth, common.UAAAuth)
+	}
+	if current == target {
+		return fmt.Errorf("the auth mode is %s already", target)
+	}
+	return nil
+}
+
+// Preflight reports the users which can't be mapped and the memberships which grant nothing
+// if the auth mode is switched to the target one, nothing is changed
+func Preflight(target string) (*models.AuthModePreflight, error) {
+	current, err := config.AuthMode()
+	if err != nil {
+		return nil, err
+	}
+	if err = Validate(current, target); err != nil {
+		return nil, err
+	}
+	search, closer, err := newSearcher(target)
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+	return preflight(current, target, search)
+}
+
+func preflight(current, target string, search searchFunc) (*models.AuthModePreflight, error) {
+	result := &models.AuthModePreflight{
+		CurrentMode:         current,
+		TargetMode:          target,
+		Users:               []*models.AuthModeUser{},
+		OrphanedMemberships: []*models.OrphanedMembership{},
+		Summary:             map[string]int{},
+	}
+	users, err := listUsers(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		u := &models.AuthModeUser{
+			UserID:   user.UserID,
+			Username: user.Username,
+		}
+		switch {
+		case target == common.DBAuth:
+			// the users onboarded from the directory have no password in the database
+			u.Status = models.AuthModeUserPasswordReset
+		case search == nil:
+			u.Status = models.AuthModeUserUnknown
+			u.Reason = fmt.Sprintf("the users can't be searched in %s", target)
+		default:
+			found, err := search(user.Username)
+			switch {
+			case err != nil:
+				u.Status = models.AuthModeUserUnknown
+				u.Reason = err.Error()
+			case found == nil:
+				u.Status = models.AuthModeUserUnmappable
+				u.Reason = fmt.Sprintf("the user isn't found in %s", target)
+			default:
+				u.Status = models.AuthModeUserMappable
+				u.Email = found.Email
+				u.Realname = found.Realname
+			}
+		}
+		result.Users = append(result.Users, u)
+		result.Summary[u.Status]++
+
+		if u.Status != models.AuthModeUserUnmappable {
+			continue
+		}
+		memberships, err := getUserMemberships(user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range memberships {
+			result.OrphanedMemberships = append(result.OrphanedMemberships, orphaned(m, u.Reason))
+		}
+	}
+
+	// the LDAP groups are only resolved in the LDAP auth mode
+	if current == common.LDAPAuth {
+		memberships, err := getGroupMemberships(common.LdapGroupType)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range memberships {
+			result.OrphanedMemberships = append(result.OrphanedMemberships,
+				orphaned(m, fmt.Sprintf("the LDAP groups aren't supported by %s", target)))
+		}
+	}
+	return result, nil
+}
+
+func orphaned(m *models.Member, reason string) *models.OrphanedMembership {
+	return &models.OrphanedMembership{
+		ProjectID:  m.ProjectID,
+		EntityType: m.EntityType,
+		EntityName: m.Entityname,
+		RoleName:   m.Rolename,
+		Reason:     reason,
+	}
+}
+
+// Migrate converts the users to the target auth mode and switches the auth mode, the profiles
+// of the mappable users are updated with the ones in the directory. The migration is rejected
+// if any user can't be mapped unless it's forced, the returned report is the one checked before
+// the migration
+func Migrate(target string, force bool, progress func(string)) (*models.AuthModePreflight, int, error) {
+	progress("checking the users")
+	report, err := Preflight(target)
+	if err != nil {
+		return nil, 0, err
+	}
+	return migrate(report, force, progress)
+}
+
+func migrate(report *models.AuthModePreflight, force bool, progress func(string)) (*models.AuthModePreflight, int, error) {
+	blocked := report.Summary[models.AuthModeUserUnmappable] + report.Summary[models.AuthModeUserUnknown]
+	if blocked > 0 && !force {
+		return report, 0, fmt.Errorf("%d users can't be mapped to %s, check them with the preflight or force the migration",
+			blocked, report.TargetMode)
+	}
+
+	progress("converting the users")
+	converted := 0
+	for _, u := range report.Users {
+		if u.Status != models.AuthModeUserMappable {
+			continue
+		}
+		user := models.User{
+			UserID:   u.UserID,
+			Email:    u.Email,
+			Realname: u.Realname,
+			Comment:  comments[report.TargetMode],
+		}
+		cols := []string{"Comment"}
+		if len(u.Email) > 0 {
+			cols = append(cols, "Email")
+		}
+		if len(u.Realname) > 0 {
+			cols = append(cols, "Realname")
+		}
+		// the email may be used by another user, the user is kept as it's still mappable
+		if err := changeUserProfile(user, cols...); err != nil {
+			log.Warningf("failed to convert user %s to %s: %v", u.Username, report.TargetMode, err)
+			continue
+		}
+		converted++
+	}
+
+	progress(fmt.Sprintf("switching the auth mode to %s", report.TargetMode))
+	if err := uploadConfig(map[string]interface{}{
+		common.AUTHMode: report.TargetMode,
+	}); err != nil {
+		return report, converted, err
+	}
+	log.Infof("the auth mode is switched from %s to %s, %d users are converted",
+		report.CurrentMode, report.TargetMode, converted)
+	return report, converted, nil
+}
+
+// defaultSearcher returns the function searching the users in the directory of the auth mode,
+// the LDAP is searched with one session rather than the authenticator which requires the
+// current auth mode to be LDAP. The function is nil if the mode has no directory
+func defaultSearcher(mode string) (searchFunc, func(), error) {
+	switch mode {
+	case common.LDAPAuth:
+		ldapConf, err := config.LDAPConf()
+		if err != nil {
+			return nil, nil, err
+		}
+		session, err := ldapUtils.CreateWithConfig(*ldapConf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = session.Open(); err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to LDAP: %v", err)
+		}
+		return func(username string) (*models.User, error) {
+			users, err := session.SearchUser(username)
+			if err != nil {
+				return nil, err
+			}
+			if len(users) == 0 {
+				return nil, nil
+			}
+			return &models.User{
+				Username: strings.TrimSpace(users[0].Username),
+				Email:    strings.TrimSpace(users[0].Email),
+				Realname: strings.TrimSpace(users[0].Realname),
+			}, nil
+		}, session.Close, nil
+	case common.UAAAuth:
+		return func(username string) (*models.User, error) {
+			return auth.SearchUserInMode(mode, username)
+		}, func() {}, nil
+	}
+	return nil, func() {}, nil
+}
@@ -0,0 +1,122 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authmode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stub(t *testing.T) {
+	listUsers = func(query *models.UserQuery) ([]models.User, error) {
+		return []models.User{
+			{UserID: 3, Username: "alice"},
+			{UserID: 4, Username: "bob"},
+			{UserID: 5, Username: "carol"},
+		}, nil
+	}
+	getUserMemberships = func(userID int) ([]*models.Member, error) {
+		return []*models.Member{
+			{ProjectID: 1, EntityType: common.UserMember, Entityname: "bob", Rolename: "developer"},
+		}, nil
+	}
+	getGroupMemberships = func(groupType int) ([]*models.Member, error) {
+		return []*models.Member{
+			{ProjectID: 2, EntityType: common.GroupMember, Entityname: "admins", Rolename: "projectAdmin"},
+		}, nil
+	}
+}
+
+func search(username string) (*models.User, error) {
+	switch username {
+	case "alice":
+		return &models.User{Username: "alice", Email: "alice@example.com", Realname: "Alice"}, nil
+	case "bob":
+		return nil, nil
+	}
+	return nil, errors.New("timeout")
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(common.DBAuth, common.LDAPAuth))
+	assert.NotNil(t, Validate(common.DBAuth, common.DBAuth))
+	assert.NotNil(t, Validate(common.DBAuth, common.HTTPAuth))
+	assert.NotNil(t, Validate(common.DBAuth, "unknown"))
+}
+
+func TestPreflight(t *testing.T) {
+	stub(t)
+
+	report, err := preflight(common.DBAuth, common.LDAPAuth, search)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(report.Users))
+	assert.Equal(t, models.AuthModeUserMappable, report.Users[0].Status)
+	assert.Equal(t, "alice@example.com", report.Users[0].Email)
+	assert.Equal(t, models.AuthModeUserUnmappable, report.Users[1].Status)
+	assert.Equal(t, models.AuthModeUserUnknown, report.Users[2].Status)
+	assert.Equal(t, "timeout", report.Users[2].Reason)
+	assert.Equal(t, map[string]int{
+		models.AuthModeUserMappable:   1,
+		models.AuthModeUserUnmappable: 1,
+		models.AuthModeUserUnknown:    1,
+	}, report.Summary)
+	// only the memberships of the unmappable user
+	require.Equal(t, 1, len(report.OrphanedMemberships))
+	assert.Equal(t, "bob", report.OrphanedMemberships[0].EntityName)
+
+	// the LDAP groups are orphaned when leaving LDAP
+	report, err = preflight(common.LDAPAuth, common.DBAuth, nil)
+	require.Nil(t, err)
+	assert.Equal(t, 3, report.Summary[models.AuthModeUserPasswordReset])
+	require.Equal(t, 1, len(report.OrphanedMemberships))
+	assert.Equal(t, "admins", report.OrphanedMemberships[0].EntityName)
+}
+
+func TestMigrate(t *testing.T) {
+	stub(t)
+	converted := []models.User{}
+	changeUserProfile = func(user models.User, cols ...string) error {
+		converted = append(converted, user)
+		return nil
+	}
+	uploaded := map[string]interface{}{}
+	uploadConfig = func(cfg map[string]interface{}) error {
+		uploaded = cfg
+		return nil
+	}
+	progress := func(string) {}
+
+	report, err := preflight(common.DBAuth, common.LDAPAuth, search)
+	require.Nil(t, err)
+
+	// rejected as some users can't be mapped
+	_, _, err = migrate(report, false, progress)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(converted))
+	assert.Equal(t, 0, len(uploaded))
+
+	_, n, err := migrate(report, true, progress)
+	require.Nil(t, err)
+	assert.Equal(t, 1, n)
+	require.Equal(t, 1, len(converted))
+	assert.Equal(t, 3, converted[0].UserID)
+	assert.Equal(t, "from LDAP.", converted[0].Comment)
+	assert.Equal(t, common.LDAPAuth, uploaded[common.AUTHMode])
+}
@@ -0,0 +1,83 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package badge renders the flat badges in the style of shields.io, which can be embedded
+// in the READMEs and dashboards to show the live status of the images.
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+)
+
+// the colors of the badges
+const (
+	ColorGreen     = "#4c1"
+	ColorYellow    = "#dfb317"
+	ColorOrange    = "#fe7d37"
+	ColorRed       = "#e05d44"
+	ColorLightGrey = "#9f9f9f"
+	ColorBlue      = "#007ec6"
+
+	labelColor = "#555"
+	// the horizontal padding of the texts
+	padding = 6
+)
+
+// Render returns the SVG of the badge with the label on the left and the message on the right
+func Render(label, message, color string) []byte {
+	labelWidth := textWidth(label) + 2*padding
+	messageWidth := textWidth(message) + 2*padding
+	width := labelWidth + messageWidth
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		width, label, message)
+	fmt.Fprintf(buf, `<title>%s: %s</title>`, label, message)
+	buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(buf, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(buf, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="%s"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		labelWidth, labelColor, labelWidth, messageWidth, html.EscapeString(color), width)
+	buf.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`,
+		labelWidth/2, label, labelWidth/2, label)
+	fmt.Fprintf(buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`,
+		labelWidth+messageWidth/2, message, labelWidth+messageWidth/2, message)
+	buf.WriteString(`</g></svg>`)
+	return buf.Bytes()
+}
+
+// textWidth estimates the width in pixels of the text in 11px Verdana, the narrow
+// and the wide characters are weighted so the texts fit the badges well enough
+func textWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r == 'i' || r == 'l' || r == 'j' || r == '.' || r == ',' || r == ':' || r == '\'' || r == '|' || r == '!':
+			width += 3.5
+		case r == ' ' || r == 'f' || r == 't' || r == 'r' || r == '-' || r == '(' || r == ')' || r == '/':
+			width += 4.5
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W':
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 8
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
@@ -0,0 +1,43 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package badge
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	svg := Render("vulnerability", "high", ColorRed)
+	// it must be a well-formed XML
+	require.Nil(t, xml.Unmarshal(svg, &struct{}{}))
+	assert.Contains(t, string(svg), `<title>vulnerability: high</title>`)
+	assert.Contains(t, string(svg), ColorRed)
+
+	// the texts are escaped
+	svg = Render("<label>", "a & b", ColorGreen)
+	require.Nil(t, xml.Unmarshal(svg, &struct{}{}))
+	assert.Contains(t, string(svg), "&lt;label&gt;")
+	assert.Contains(t, string(svg), "a &amp; b")
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 0, textWidth(""))
+	assert.True(t, textWidth("unsigned") > textWidth("signed"))
+	assert.True(t, textWidth("WWW") > textWidth("iii"))
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle exports the projects into an offline bundle file and imports the
+// bundle into a Harbor which has no access to the source one.
+//
+// The bundle is a gzipped tarball, "metadata.json" is the first file and describes
+// the content, followed by the blobs, manifests and chart packages:
+//
+//	metadata.json
+//	blobs/sha256/<hex>
+//	manifests/sha256/<hex>
+//	charts/<project>/<chart>-<version>.tgz
+package bundle
+
+import (
+	"io"
+
+	"github.com/goharbor/harbor/src/chartserver"
+	"github.com/goharbor/harbor/src/common/models"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+const (
+	// Version is the version of the bundle format
+	Version = "1"
+
+	metadataFile = "metadata.json"
+	blobDir      = "blobs"
+	manifestDir  = "manifests"
+	chartDir     = "charts"
+	// the max size of the manifests and chart packages which are read into memory
+	maxFileSize = 64 << 20
+)
+
+// Repository is the client of the repository in the registry
+type Repository interface {
+	PullManifest(reference string, acceptMediaTypes []string) (digest, mediaType string, payload []byte, err error)
+	PushManifest(reference, mediaType string, payload []byte) (digest string, err error)
+	BlobExist(digest string) (bool, error)
+	PullBlob(digest string) (size int64, data io.ReadCloser, err error)
+	PushBlob(digest string, size int64, data io.Reader) error
+	MountBlob(digest, from string) error
+}
+
+// ChartStore is the storage of the charts, it's nil if Harbor isn't deployed with chartmuseum
+type ChartStore interface {
+	ListCharts(namespace string) ([]*chartserver.ChartInfo, error)
+	GetChart(namespace, chartName string) (chartserver.ChartVersions, error)
+	GetChartVersionContent(namespace, chartName, version string) ([]byte, error)
+	UploadChartVersion(namespace string, content []byte) error
+}
+
+// projectManager is the subset of promgr.ProjectManager used by the bundle
+type projectManager interface {
+	Get(projectIDOrName interface{}) (*models.Project, error)
+	Create(*models.Project) (int64, error)
+}
+
+func newRepository(name string) (Repository, error) {
+	return coreutils.NewRepositoryClientForUI("harbor-core", name)
+}
@@ -0,0 +1,306 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/chartserver"
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	helm_repo "k8s.io/helm/pkg/repo"
+)
+
+type fakeRegistry struct {
+	// repository -> tag -> payload
+	manifests map[string]map[string][]byte
+	// repository -> digest -> content
+	blobs   map[string]map[string][]byte
+	mounted int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		manifests: map[string]map[string][]byte{},
+		blobs:     map[string]map[string][]byte{},
+	}
+}
+
+func (f *fakeRegistry) repository(name string) (Repository, error) {
+	if f.manifests[name] == nil {
+		f.manifests[name] = map[string][]byte{}
+		f.blobs[name] = map[string][]byte{}
+	}
+	return &fakeRepository{name: name, registry: f}, nil
+}
+
+type fakeRepository struct {
+	name     string
+	registry *fakeRegistry
+}
+
+func (f *fakeRepository) PullManifest(reference string, acceptMediaTypes []string) (string, string, []byte, error) {
+	payload, ok := f.registry.manifests[f.name][reference]
+	if !ok {
+		// pulled by digest
+		for _, p := range f.registry.manifests[f.name] {
+			if digest(p) == reference {
+				return reference, schema2.MediaTypeManifest, p, nil
+			}
+		}
+		return "", "", nil, fmt.Errorf("manifest %s not found", reference)
+	}
+	return digest(payload), schema2.MediaTypeManifest, payload, nil
+}
+
+func (f *fakeRepository) PushManifest(reference, mediaType string, payload []byte) (string, error) {
+	f.registry.manifests[f.name][reference] = payload
+	return digest(payload), nil
+}
+
+func (f *fakeRepository) BlobExist(dgt string) (bool, error) {
+	_, ok := f.registry.blobs[f.name][dgt]
+	return ok, nil
+}
+
+func (f *fakeRepository) PullBlob(dgt string) (int64, io.ReadCloser, error) {
+	data, ok := f.registry.blobs[f.name][dgt]
+	if !ok {
+		return 0, nil, fmt.Errorf("blob %s not found", dgt)
+	}
+	return int64(len(data)), ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeRepository) PushBlob(dgt string, size int64, data io.Reader) error {
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	if digest(content) != dgt || int64(len(content)) != size {
+		return fmt.Errorf("invalid blob %s", dgt)
+	}
+	f.registry.blobs[f.name][dgt] = content
+	return nil
+}
+
+func (f *fakeRepository) MountBlob(dgt, from string) error {
+	data, ok := f.registry.blobs[from][dgt]
+	if !ok {
+		return fmt.Errorf("blob %s not found in %s", dgt, from)
+	}
+	f.registry.blobs[f.name][dgt] = data
+	f.registry.mounted++
+	return nil
+}
+
+type fakeChartStore struct {
+	// namespace -> versions, the content of the fake package is "<name>:<version>"
+	charts map[string][]*helm_repo.ChartVersion
+}
+
+func (f *fakeChartStore) ListCharts(namespace string) ([]*chartserver.ChartInfo, error) {
+	infos := []*chartserver.ChartInfo{}
+	seen := map[string]bool{}
+	for _, v := range f.charts[namespace] {
+		if !seen[v.Name] {
+			seen[v.Name] = true
+			infos = append(infos, &chartserver.ChartInfo{Name: v.Name})
+		}
+	}
+	return infos, nil
+}
+
+func (f *fakeChartStore) GetChart(namespace, chartName string) (chartserver.ChartVersions, error) {
+	versions := chartserver.ChartVersions{}
+	for _, v := range f.charts[namespace] {
+		if v.Name == chartName {
+			versions = append(versions, &chartserver.ChartVersion{ChartVersion: *v})
+		}
+	}
+	return versions, nil
+}
+
+func (f *fakeChartStore) GetChartVersionContent(namespace, chartName, version string) ([]byte, error) {
+	for _, v := range f.charts[namespace] {
+		if v.Name == chartName && v.Version == version {
+			return []byte(chartName + ":" + version), nil
+		}
+	}
+	return nil, fmt.Errorf("chart %s:%s not found", chartName, version)
+}
+
+func (f *fakeChartStore) UploadChartVersion(namespace string, content []byte) error {
+	segments := strings.SplitN(string(content), ":", 2)
+	f.charts[namespace] = append(f.charts[namespace], newChartVersion(segments[0], segments[1]))
+	return nil
+}
+
+func newChartVersion(name, version string) *helm_repo.ChartVersion {
+	return &helm_repo.ChartVersion{
+		Metadata: &chart.Metadata{
+			Name:    name,
+			Version: version,
+		},
+	}
+}
+
+type fakeProjectManager struct {
+	projects map[string]*common_models.Project
+}
+
+func (f *fakeProjectManager) Get(projectIDOrName interface{}) (*common_models.Project, error) {
+	return f.projects[projectIDOrName.(string)], nil
+}
+
+func (f *fakeProjectManager) Create(project *common_models.Project) (int64, error) {
+	f.projects[project.Name] = project
+	return int64(len(f.projects)), nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func manifest(config, layer []byte) []byte {
+	return []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s",`+
+		`"config":{"mediaType":"%s","size":%d,"digest":"%s"},`+
+		`"layers":[{"mediaType":"%s","size":%d,"digest":"%s"}]}`,
+		schema2.MediaTypeManifest, schema2.MediaTypeConfig, len(config), digest(config),
+		schema2.MediaTypeLayer, len(layer), digest(layer)))
+}
+
+func TestExportAndImport(t *testing.T) {
+	config, layer, base := []byte("config"), []byte("layer"), []byte("base")
+	src := newFakeRegistry()
+	for _, repo := range []string{"library/hello-world", "library/busybox"} {
+		src.repository(repo)
+		src.blobs[repo][digest(config)] = config
+		src.blobs[repo][digest(base)] = base
+	}
+	src.blobs["library/hello-world"][digest(layer)] = layer
+	src.manifests["library/hello-world"]["latest"] = manifest(config, layer)
+	src.manifests["library/busybox"]["1.0"] = manifest(config, base)
+
+	// the source repositories and tags are listed by the file adaptor in testing
+	exporter := &Exporter{
+		projectMgr: &fakeProjectManager{
+			projects: map[string]*common_models.Project{
+				"library": {
+					Name: "library",
+					Metadata: map[string]string{
+						common_models.ProMetaPublic: "true",
+					},
+				},
+			},
+		},
+		adaptor: registry.NewFileAdaptor(&models.BundleMetadata{
+			Projects: []*models.BundleProject{
+				{
+					Name: "library",
+					Repositories: []*models.BundleRepository{
+						{
+							Name: "library/hello-world",
+							Tags: []*models.BundleTag{{Name: "latest"}},
+						},
+						{
+							Name: "library/busybox",
+							Tags: []*models.BundleTag{{Name: "1.0"}},
+						},
+					},
+				},
+			},
+		}),
+		charts: &fakeChartStore{
+			charts: map[string][]*helm_repo.ChartVersion{
+				"library": {newChartVersion("harbor", "0.1.0")},
+			},
+		},
+		newRepository: src.repository,
+	}
+
+	_, err := exporter.Collect([]string{"non-existing"})
+	require.NotNil(t, err)
+
+	metadata, err := exporter.Collect([]string{"library"})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(metadata.Projects))
+	require.Equal(t, 2, len(metadata.Projects[0].Repositories))
+	assert.Equal(t, 2, len(metadata.Projects[0].Repositories[0].Tags[0].Blobs))
+	assert.Equal(t, 1, len(metadata.Projects[0].Charts))
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, exporter.Export(buf))
+	// the manifests are pulled again by digest when they're written
+	pushed := src.manifests["library/busybox"]["1.0"]
+	src.manifests["library/busybox"]["1.0"] = manifest(config, layer)
+	assert.NotNil(t, exporter.Export(ioutil.Discard))
+	src.manifests["library/busybox"]["1.0"] = pushed
+
+	dst := newFakeRegistry()
+	projectMgr := &fakeProjectManager{
+		projects: map[string]*common_models.Project{},
+	}
+	charts := &fakeChartStore{
+		charts: map[string][]*helm_repo.ChartVersion{},
+	}
+	importer := &Importer{
+		projectMgr:    projectMgr,
+		charts:        charts,
+		newRepository: dst.repository,
+	}
+	data := buf.Bytes()
+	summary, err := importer.Import(bytes.NewReader(data), "admin")
+	require.Nil(t, err)
+	assert.Equal(t, 1, summary.Projects)
+	assert.Equal(t, 2, summary.Tags)
+	assert.Equal(t, 1, summary.Charts)
+
+	project := projectMgr.projects["library"]
+	require.NotNil(t, project)
+	assert.Equal(t, "admin", project.OwnerName)
+	assert.Equal(t, "true", project.Metadata[common_models.ProMetaPublic])
+	assert.Equal(t, manifest(config, layer), dst.manifests["library/hello-world"]["latest"])
+	assert.Equal(t, manifest(config, base), dst.manifests["library/busybox"]["1.0"])
+	assert.Equal(t, layer, dst.blobs["library/hello-world"][digest(layer)])
+	assert.Equal(t, config, dst.blobs["library/busybox"][digest(config)])
+	// the config shared by the repositories is pushed once and mounted into the other one
+	assert.Equal(t, 1, dst.mounted)
+	require.Equal(t, 1, len(charts.charts["library"]))
+	assert.Equal(t, "0.1.0", charts.charts["library"][0].Version)
+
+	// the existing chart is skipped when importing again
+	summary, err = importer.Import(bytes.NewReader(data), "admin")
+	require.Nil(t, err)
+	assert.Equal(t, 0, summary.Charts)
+
+	// invalid bundle
+	_, err = importer.Import(bytes.NewBufferString("invalid"), "admin")
+	require.NotNil(t, err)
+	_, ok := err.(*InvalidBundleError)
+	assert.True(t, ok)
+}
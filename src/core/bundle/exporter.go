@@ -0,0 +1,280 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+)
+
+// Exporter exports the projects into the offline bundle
+type Exporter struct {
+	projectMgr    projectManager
+	adaptor       registry.Adaptor
+	charts        ChartStore
+	newRepository func(name string) (Repository, error)
+
+	metadata *models.BundleMetadata
+	// the digest of manifest -> the repository which the manifest is pulled from, the
+	// manifests are pulled again when they're written so they're never held together
+	manifests map[string]string
+	// the digest of blob -> the repository which the blob is pulled from
+	blobs map[string]string
+}
+
+// NewExporter returns an instance of Exporter which reads the images through the
+// Harbor adaptor of replication, the charts are skipped if the store is nil
+func NewExporter(projectMgr projectManager, charts ChartStore) *Exporter {
+	return &Exporter{
+		projectMgr:    projectMgr,
+		adaptor:       &registry.HarborAdaptor{},
+		charts:        charts,
+		newRepository: newRepository,
+	}
+}
+
+// Collect pulls the manifests of images to find their blobs and lists the charts of the
+// projects, only the references are kept. Most of the errors of the registry and chart
+// store come out here before anything is written
+func (e *Exporter) Collect(projects []string) (*models.BundleMetadata, error) {
+	e.metadata = &models.BundleMetadata{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+	e.manifests = map[string]string{}
+	e.blobs = map[string]string{}
+
+	for _, name := range projects {
+		project, err := e.projectMgr.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project %s: %v", name, err)
+		}
+		if project == nil {
+			return nil, fmt.Errorf("project %s not found", name)
+		}
+		bp := &models.BundleProject{
+			Name:     project.Name,
+			Metadata: project.Metadata,
+		}
+		for _, repo := range e.adaptor.GetRepositories(project.Name) {
+			br, err := e.collectRepository(repo.Name, project.Name)
+			if err != nil {
+				return nil, err
+			}
+			bp.Repositories = append(bp.Repositories, br)
+		}
+		if e.charts != nil {
+			charts, err := e.collectCharts(project.Name)
+			if err != nil {
+				return nil, err
+			}
+			bp.Charts = charts
+		}
+		e.metadata.Projects = append(e.metadata.Projects, bp)
+	}
+	return e.metadata, nil
+}
+
+func (e *Exporter) collectRepository(repository, namespace string) (*models.BundleRepository, error) {
+	client, err := e.newRepository(repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for repository %s: %v", repository, err)
+	}
+	br := &models.BundleRepository{
+		Name: repository,
+	}
+	acceptMediaTypes := []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest}
+	for _, tag := range e.adaptor.GetTags(repository, namespace) {
+		digest, mediaType, payload, err := client.PullManifest(tag.Name, acceptMediaTypes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull manifest of %s:%s: %v", repository, tag.Name, err)
+		}
+		if strings.Contains(mediaType, "application/json") {
+			mediaType = schema1.MediaTypeManifest
+		}
+		manifest, _, err := reg.UnMarshal(mediaType, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest of %s:%s: %v", repository, tag.Name, err)
+		}
+
+		bt := &models.BundleTag{
+			Name:      tag.Name,
+			Digest:    digest,
+			MediaType: mediaType,
+		}
+		seen := map[string]bool{}
+		for _, ref := range manifest.References() {
+			d := ref.Digest.String()
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			bt.Blobs = append(bt.Blobs, d)
+			if _, ok := e.blobs[d]; !ok {
+				e.blobs[d] = repository
+			}
+		}
+		if _, ok := e.manifests[digest]; !ok {
+			e.manifests[digest] = repository
+		}
+		br.Tags = append(br.Tags, bt)
+	}
+	return br, nil
+}
+
+func (e *Exporter) collectCharts(namespace string) ([]*models.BundleChart, error) {
+	infos, err := e.charts.ListCharts(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list charts of project %s: %v", namespace, err)
+	}
+	charts := []*models.BundleChart{}
+	for _, info := range infos {
+		versions, err := e.charts.GetChart(namespace, info.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chart %s of project %s: %v", info.Name, namespace, err)
+		}
+		for _, v := range versions {
+			charts = append(charts, &models.BundleChart{
+				Name:    v.Name,
+				Version: v.Version,
+			})
+		}
+	}
+	return charts, nil
+}
+
+// Export writes the bundle of the collected content into w, the blobs, manifests and chart
+// packages are pulled one by one and written once they're pulled
+func (e *Exporter) Export(w io.Writer) error {
+	if e.metadata == nil {
+		return fmt.Errorf("nothing collected")
+	}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	data, err := json.Marshal(e.metadata)
+	if err != nil {
+		return err
+	}
+	if err = writeFile(tw, metadataFile, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	// write the blobs before the manifests so that the importer is able to push
+	// the manifests once they are read
+	for _, digest := range sortedKeys(e.blobs) {
+		if err = e.writeBlob(tw, digest, e.blobs[digest]); err != nil {
+			return err
+		}
+	}
+	for _, digest := range sortedKeys(e.manifests) {
+		if err = e.writeManifest(tw, digest, e.manifests[digest]); err != nil {
+			return err
+		}
+	}
+	for _, project := range e.metadata.Projects {
+		for _, chart := range project.Charts {
+			content, err := e.charts.GetChartVersionContent(project.Name, chart.Name, chart.Version)
+			if err != nil {
+				return fmt.Errorf("failed to get chart %s:%s of project %s: %v", chart.Name, chart.Version, project.Name, err)
+			}
+			if err = writeFile(tw, chartPath(project.Name, chart), int64(len(content)),
+				bytes.NewReader(content)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func (e *Exporter) writeManifest(tw *tar.Writer, digest, repository string) error {
+	client, err := e.newRepository(repository)
+	if err != nil {
+		return fmt.Errorf("failed to create client for repository %s: %v", repository, err)
+	}
+	dgt, _, payload, err := client.PullManifest(digest, []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest})
+	if err != nil {
+		return fmt.Errorf("failed to pull manifest %s of %s: %v", digest, repository, err)
+	}
+	if dgt != digest {
+		return fmt.Errorf("the digest of manifest %s of %s changes to %s", digest, repository, dgt)
+	}
+	return writeFile(tw, digestPath(manifestDir, digest), int64(len(payload)), bytes.NewReader(payload))
+}
+
+func (e *Exporter) writeBlob(tw *tar.Writer, digest, repository string) error {
+	client, err := e.newRepository(repository)
+	if err != nil {
+		return fmt.Errorf("failed to create client for repository %s: %v", repository, err)
+	}
+	size, data, err := client.PullBlob(digest)
+	if err != nil {
+		return fmt.Errorf("failed to pull blob %s of %s: %v", digest, repository, err)
+	}
+	defer data.Close()
+	log.Debugf("exporting blob %s of %s, size: %d", digest, repository, size)
+	return writeFile(tw, digestPath(blobDir, digest), size, data)
+}
+
+func writeFile(tw *tar.Writer, name string, size int64, data io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	_, err := io.CopyN(tw, data, size)
+	return err
+}
+
+// digestPath converts the digest "sha256:<hex>" to the path "<dir>/sha256/<hex>"
+func digestPath(dir, digest string) string {
+	return path.Join(dir, strings.Replace(digest, ":", "/", 1))
+}
+
+func chartPath(project string, chart *models.BundleChart) string {
+	return path.Join(chartDir, project, fmt.Sprintf("%s-%s.tgz", chart.Name, chart.Version))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
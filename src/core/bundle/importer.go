@@ -0,0 +1,316 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+)
+
+// InvalidBundleError is returned when the bundle is malformed
+type InvalidBundleError struct {
+	msg string
+}
+
+func (e *InvalidBundleError) Error() string {
+	return e.msg
+}
+
+func invalidBundle(format string, args ...interface{}) error {
+	return &InvalidBundleError{
+		msg: fmt.Sprintf(format, args...),
+	}
+}
+
+// Summary is the result of the import
+type Summary struct {
+	Projects int `json:"projects"`
+	Tags     int `json:"tags"`
+	Charts   int `json:"charts"`
+}
+
+// Importer imports the offline bundle
+type Importer struct {
+	projectMgr    projectManager
+	charts        ChartStore
+	newRepository func(name string) (Repository, error)
+}
+
+// NewImporter returns an instance of Importer, the charts in the bundle
+// are skipped if the store is nil
+func NewImporter(projectMgr projectManager, charts ChartStore) *Importer {
+	return &Importer{
+		projectMgr:    projectMgr,
+		charts:        charts,
+		newRepository: newRepository,
+	}
+}
+
+// Import reads the bundle from r and pushes the content into Harbor. The projects which
+// don't exist are created with the owner, the content of the bundle is browsed by the
+// file adaptor of replication
+func (i *Importer) Import(r io.Reader, owner string) (*Summary, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, invalidBundle("the bundle isn't a gzipped tarball: %v", err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	metadata, err := readMetadata(tr)
+	if err != nil {
+		return nil, err
+	}
+	adaptor := registry.NewFileAdaptor(metadata)
+
+	summary := &Summary{}
+	for _, namespace := range adaptor.GetNamespaces() {
+		if err = i.ensureProject(namespace, owner); err != nil {
+			return nil, err
+		}
+		summary.Projects++
+	}
+
+	// the digest of blob -> the repositories referencing it
+	blobRepos := map[string][]string{}
+	for _, project := range metadata.Projects {
+		for _, repo := range project.Repositories {
+			for _, tag := range repo.Tags {
+				for _, blob := range tag.Blobs {
+					blobRepos[blob] = appendIfMissing(blobRepos[blob], repo.Name)
+				}
+			}
+		}
+	}
+
+	manifests := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, invalidBundle("failed to read the bundle: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := path.Clean(header.Name)
+		switch {
+		case strings.HasPrefix(name, blobDir+"/"):
+			digest := pathDigest(blobDir, name)
+			if err = i.pushBlob(digest, header.Size, tr, blobRepos[digest]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(name, manifestDir+"/"):
+			digest := pathDigest(manifestDir, name)
+			payload, err := readFile(tr, header)
+			if err != nil {
+				return nil, err
+			}
+			sum := sha256.Sum256(payload)
+			if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+				return nil, invalidBundle("the digest of manifest %s doesn't match: %s", digest, actual)
+			}
+			manifests[digest] = payload
+		case strings.HasPrefix(name, chartDir+"/"):
+			imported, err := i.uploadChart(metadata, name, tr, header)
+			if err != nil {
+				return nil, err
+			}
+			if imported {
+				summary.Charts++
+			}
+		default:
+			log.Warningf("unknown file %s in the bundle, skip", name)
+		}
+	}
+
+	for _, namespace := range adaptor.GetNamespaces() {
+		for _, repo := range adaptor.GetRepositories(namespace.Name) {
+			client, err := i.newRepository(repo.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create client for repository %s: %v", repo.Name, err)
+			}
+			for _, tag := range adaptor.GetTags(repo.Name, namespace.Name) {
+				digest, _ := tag.Metadata["digest"].(string)
+				mediaType, _ := tag.Metadata["media_type"].(string)
+				payload, ok := manifests[digest]
+				if !ok {
+					return nil, invalidBundle("manifest %s of %s:%s not found in the bundle", digest, repo.Name, tag.Name)
+				}
+				if _, err = client.PushManifest(tag.Name, mediaType, payload); err != nil {
+					return nil, fmt.Errorf("failed to push manifest of %s:%s: %v", repo.Name, tag.Name, err)
+				}
+				summary.Tags++
+			}
+		}
+	}
+	return summary, nil
+}
+
+func readMetadata(tr *tar.Reader) (*models.BundleMetadata, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, invalidBundle("failed to read the bundle: %v", err)
+	}
+	if path.Clean(header.Name) != metadataFile {
+		return nil, invalidBundle("%s must be the first file of the bundle", metadataFile)
+	}
+	data, err := readFile(tr, header)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &models.BundleMetadata{}
+	if err = json.Unmarshal(data, metadata); err != nil {
+		return nil, invalidBundle("failed to parse %s: %v", metadataFile, err)
+	}
+	if metadata.Version != Version {
+		return nil, invalidBundle("unsupported bundle version %q", metadata.Version)
+	}
+	for _, project := range metadata.Projects {
+		for _, repo := range project.Repositories {
+			if !strings.HasPrefix(repo.Name, project.Name+"/") {
+				return nil, invalidBundle("repository %s doesn't belong to project %s", repo.Name, project.Name)
+			}
+		}
+	}
+	return metadata, nil
+}
+
+func (i *Importer) ensureProject(namespace models.Namespace, owner string) error {
+	project, err := i.projectMgr.Get(namespace.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get project %s: %v", namespace.Name, err)
+	}
+	if project != nil {
+		return nil
+	}
+	metadata := map[string]string{}
+	for k, v := range namespace.Metadata {
+		metadata[k] = fmt.Sprint(v)
+	}
+	if _, err = i.projectMgr.Create(&common_models.Project{
+		Name:      namespace.Name,
+		OwnerName: owner,
+		Metadata:  metadata,
+	}); err != nil {
+		return fmt.Errorf("failed to create project %s: %v", namespace.Name, err)
+	}
+	log.Infof("project %s is created by the import of bundle", namespace.Name)
+	return nil
+}
+
+// pushBlob pushes the blob into the first repository and mounts it into the others
+func (i *Importer) pushBlob(digest string, size int64, data io.Reader, repositories []string) error {
+	if len(repositories) == 0 {
+		log.Warningf("blob %s isn't referenced by any image in the bundle, skip", digest)
+		return nil
+	}
+	for index, repository := range repositories {
+		client, err := i.newRepository(repository)
+		if err != nil {
+			return fmt.Errorf("failed to create client for repository %s: %v", repository, err)
+		}
+		exist, err := client.BlobExist(digest)
+		if err != nil {
+			return fmt.Errorf("failed to check the existence of blob %s in %s: %v", digest, repository, err)
+		}
+		if exist {
+			continue
+		}
+		if index == 0 {
+			err = client.PushBlob(digest, size, data)
+		} else {
+			err = client.MountBlob(digest, repositories[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to push blob %s into %s: %v", digest, repository, err)
+		}
+	}
+	return nil
+}
+
+// uploadChart uploads the chart package, false is returned if the chart is skipped
+func (i *Importer) uploadChart(metadata *models.BundleMetadata, name string, tr *tar.Reader, header *tar.Header) (bool, error) {
+	if i.charts == nil {
+		log.Warningf("chartmuseum isn't enabled, skip chart %s", name)
+		return false, nil
+	}
+	for _, project := range metadata.Projects {
+		for _, chart := range project.Charts {
+			if chartPath(project.Name, chart) != name {
+				continue
+			}
+			versions, err := i.charts.GetChart(project.Name, chart.Name)
+			if err == nil {
+				for _, v := range versions {
+					if v.Version == chart.Version {
+						log.Infof("chart %s:%s already exists in project %s, skip", chart.Name, chart.Version, project.Name)
+						return false, nil
+					}
+				}
+			}
+			content, err := readFile(tr, header)
+			if err != nil {
+				return false, err
+			}
+			if err = i.charts.UploadChartVersion(project.Name, content); err != nil {
+				return false, fmt.Errorf("failed to upload chart %s:%s into project %s: %v", chart.Name, chart.Version, project.Name, err)
+			}
+			return true, nil
+		}
+	}
+	log.Warningf("chart %s isn't described in %s, skip", name, metadataFile)
+	return false, nil
+}
+
+func readFile(tr *tar.Reader, header *tar.Header) ([]byte, error) {
+	if header.Size > maxFileSize {
+		return nil, invalidBundle("the size of file %s exceeds the limit %d", header.Name, maxFileSize)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(tr, maxFileSize))
+	if err != nil {
+		return nil, invalidBundle("failed to read file %s from the bundle: %v", header.Name, err)
+	}
+	return data, nil
+}
+
+// pathDigest converts the path "<dir>/sha256/<hex>" to the digest "sha256:<hex>"
+func pathDigest(dir, name string) string {
+	return strings.Replace(strings.TrimPrefix(name, dir+"/"), "/", ":", 1)
+}
+
+func appendIfMissing(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
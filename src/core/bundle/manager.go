@@ -0,0 +1,169 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/coretask"
+)
+
+// TaskName is the name of the task exporting the bundles
+const TaskName = "BUNDLE_EXPORT"
+
+var (
+	// ErrExportRunning is returned when another bundle export is still running
+	ErrExportRunning = errors.New("another bundle export is running")
+
+	// Runner runs the exports submitted to jobservice
+	Runner = &coretask.Runner{
+		Run:  Run,
+		Fail: Fail,
+	}
+
+	// the chart store of the exports, nil if chartmuseum isn't enabled
+	chartStore ChartStore
+)
+
+// SetChartStore sets the chart store from which the charts are exported
+func SetChartStore(store ChartStore) {
+	chartStore = store
+}
+
+// Export records the export of the projects and submits it to jobservice, the ID of the
+// export record is returned. The bundle export reads all the content of the projects, so
+// only one of them is allowed to run at the same time, which is locked in the database
+func Export(projects []string, creator string) (int64, error) {
+	id, err := dao.AddBundleExport(&models.BundleExport{
+		Projects: strings.Join(projects, ","),
+		Status:   models.BundleExportRunning,
+		Creator:  creator,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = dao.LockResources(coretask.Owner(TaskName, id), TaskName); err != nil {
+		if e := dao.DeleteBundleExport(id); e != nil {
+			log.Errorf("failed to delete bundle export %d: %v", id, e)
+		}
+		if err == dao.ErrResourceLocked {
+			return 0, ErrExportRunning
+		}
+		return 0, err
+	}
+	if err = coretask.Submit(TaskName, id); err != nil {
+		err = fmt.Errorf("failed to submit the export: %v", err)
+		if e := finish(id, 0, err); e != nil {
+			log.Errorf("failed to finish bundle export %d: %v", id, e)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Run writes the bundle of the export, it's written again if it's interrupted
+func Run(id int64) error {
+	export, err := dao.GetBundleExport(id)
+	if err != nil {
+		return err
+	}
+	if export == nil || export.Status != models.BundleExportRunning {
+		log.Debugf("the bundle export %d isn't running, skip", id)
+		return nil
+	}
+	exporter := NewExporter(config.GlobalProjectMgr, chartStore)
+	size, err := dump(id, exporter, strings.Split(export.Projects, ","))
+	return finish(id, size, err)
+}
+
+// Fail marks the export as failed if it's still running
+func Fail(id int64, message string) error {
+	export, err := dao.GetBundleExport(id)
+	if err != nil {
+		return err
+	}
+	if export == nil || export.Status != models.BundleExportRunning {
+		return nil
+	}
+	return finish(id, 0, errors.New(message))
+}
+
+// finish records the result of the export and releases the lock, the error recording
+// the result is returned
+func finish(id, size int64, result error) error {
+	status, message := models.BundleExportSucceeded, ""
+	if result != nil {
+		log.Errorf("failed to export the bundle of bundle export %d: %v", id, result)
+		status, message = models.BundleExportFailed, result.Error()
+	}
+	if err := dao.UpdateBundleExportStatus(id, status, message, size); err != nil {
+		return fmt.Errorf("failed to update the status of bundle export %d: %v", id, err)
+	}
+	return dao.UnlockResources(coretask.Owner(TaskName, id))
+}
+
+// dump writes the bundle into a temporary file which is renamed once it's complete, the
+// size of the file is returned
+func dump(id int64, exporter *Exporter, projects []string) (int64, error) {
+	if _, err := exporter.Collect(projects); err != nil {
+		return 0, fmt.Errorf("failed to collect the content of projects: %v", err)
+	}
+	if err := os.MkdirAll(config.BundleExportDir(), 0700); err != nil {
+		return 0, err
+	}
+	tmp := Path(id) + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+
+	err = exporter.Export(file)
+	if e := file.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return 0, err
+	}
+	if err = os.Rename(tmp, Path(id)); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Path returns the path of the bundle of the export
+func Path(id int64) string {
+	return filepath.Join(config.BundleExportDir(), fmt.Sprintf("%d.tar.gz", id))
+}
+
+// Delete deletes the export record and the bundle
+func Delete(export *models.BundleExport) error {
+	if err := os.Remove(Path(export.ID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return dao.DeleteBundleExport(export.ID)
+}
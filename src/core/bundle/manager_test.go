@@ -0,0 +1,60 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	os.Setenv("BUNDLE_EXPORT_DIR", dir)
+	defer os.Unsetenv("BUNDLE_EXPORT_DIR")
+
+	exporter := &Exporter{
+		projectMgr: &fakeProjectManager{
+			projects: map[string]*common_models.Project{
+				"library": {Name: "library"},
+			},
+		},
+		adaptor: registry.NewFileAdaptor(&models.BundleMetadata{
+			Projects: []*models.BundleProject{{Name: "library"}},
+		}),
+		newRepository: newFakeRegistry().repository,
+	}
+	size, err := dump(1, exporter, []string{"library"})
+	require.Nil(t, err)
+	info, err := os.Stat(Path(1))
+	require.Nil(t, err)
+	assert.Equal(t, info.Size(), size)
+
+	// nothing is left when the export fails
+	_, err = dump(2, exporter, []string{"non-existing"})
+	assert.NotNil(t, err)
+	_, err = os.Stat(Path(2) + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(Path(2))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,108 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	beego_cache "github.com/astaxie/beego/cache"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/redis"
+
+	// Enable redis cache adaptor
+	_ "github.com/astaxie/beego/cache/redis"
+)
+
+const (
+	redisENVKey    = "_REDIS_URL"
+	collectionName = "harbor_core_cache"
+)
+
+var cc beego_cache.Cache
+
+// Init initializes the cache shared by the core components, redis is used if it
+// is configured, otherwise the cache falls back to the memory
+func Init() error {
+	redisURL := os.Getenv(redisENVKey)
+	if len(redisURL) > 0 {
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return err
+		}
+		// the beego redis cache is kept for the standalone redis, the cache adapter
+		// of harbor is used in the sentinel and cluster mode
+		adapter, cfg := "redis", ""
+		if opts.Mode == redis.ModeStandalone {
+			cfg, err = parseRedisConfig(redisURL)
+		} else {
+			adapter = redis.CacheAdapter
+			cfg, err = redis.CacheConfig(redisURL, collectionName)
+		}
+		if err != nil {
+			return err
+		}
+		c, err := beego_cache.NewCache(adapter, cfg)
+		if err == nil {
+			cc = c
+			log.Info("redis cache is enabled for core")
+			return nil
+		}
+		log.Errorf("failed to initialize redis cache, fall back to memory cache: %v", err)
+	}
+
+	cc = beego_cache.NewMemoryCache()
+	log.Info("memory cache is enabled for core")
+	return nil
+}
+
+// parseRedisConfig converts the redis URL in format "address:port[,pool_size[,password[,db_index]]]"
+// to the configuration of the beego redis cache
+func parseRedisConfig(redisURL string) (string, error) {
+	segments := strings.Split(redisURL, ",")
+	if len(segments[0]) == 0 {
+		return "", errors.New("empty redis address")
+	}
+	cfg := map[string]string{
+		"key":   collectionName,
+		"conn":  strings.TrimPrefix(segments[0], "redis://"),
+		"dbNum": "0",
+	}
+	if len(segments) > 2 {
+		cfg["password"] = segments[2]
+	}
+	if len(segments) > 3 && len(segments[3]) > 0 {
+		cfg["dbNum"] = segments[3]
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// toString converts the value got from the cache to string, the redis cache
+// returns []byte while the memory cache returns the original value
+func toString(v interface{}) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case []byte:
+		return string(s), true
+	}
+	return "", false
+}
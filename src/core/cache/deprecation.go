@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const deprecationKeyPrefix = "repo_deprecations"
+
+// GetRepoDeprecations returns the cached deprecations of the repository and its tags, false
+// is returned if they aren't cached
+func GetRepoDeprecations(repository string) ([]*models.RepoDeprecation, bool) {
+	if cc == nil {
+		return nil, false
+	}
+	s, ok := toString(cc.Get(deprecationKey(repository)))
+	if !ok {
+		return nil, false
+	}
+	deprecations := []*models.RepoDeprecation{}
+	if err := json.Unmarshal([]byte(s), &deprecations); err != nil {
+		log.Errorf("failed to unmarshal the cached deprecations of %s: %v", repository, err)
+		return nil, false
+	}
+	return deprecations, true
+}
+
+// SetRepoDeprecations caches the deprecations of the repository and its tags, the empty ones
+// are cached as well for the repositories not deprecated
+func SetRepoDeprecations(repository string, deprecations []*models.RepoDeprecation, ttl time.Duration) error {
+	if cc == nil {
+		return nil
+	}
+	data, err := json.Marshal(deprecations)
+	if err != nil {
+		return err
+	}
+	return cc.Put(deprecationKey(repository), string(data), ttl)
+}
+
+// InvalidateRepoDeprecations removes the cached deprecations of the repository, it's called
+// once the repository or any of its tags is deprecated or undeprecated
+func InvalidateRepoDeprecations(repository string) error {
+	key := deprecationKey(repository)
+	// the memory cache fails to delete the key not existing
+	if cc == nil || !cc.IsExist(key) {
+		return nil
+	}
+	return cc.Delete(key)
+}
+
+func deprecationKey(repository string) string {
+	return fmt.Sprintf("%s:%s", deprecationKeyPrefix, repository)
+}
@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	manifestKeyPrefix   = "manifest"
+	generationKeyPrefix = "manifest_generation"
+	// the generation of a repository must live longer than the manifests cached under it
+	generationTTL = 24 * time.Hour
+)
+
+// Manifest holds the information returned by the registry for the HEAD request of a manifest,
+// the registry may return different manifests of the tag for the different accepted media types
+type Manifest struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+	Accept    string `json:"accept"`
+}
+
+// GetManifest returns the cached manifest of the tag, nil is returned if it isn't cached
+func GetManifest(repository, tag string) *Manifest {
+	if cc == nil {
+		return nil
+	}
+	s, ok := toString(cc.Get(manifestKey(repository, tag)))
+	if !ok {
+		return nil
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal([]byte(s), manifest); err != nil {
+		log.Errorf("failed to unmarshal the cached manifest of %s:%s: %v", repository, tag, err)
+		return nil
+	}
+	return manifest
+}
+
+// SetManifest caches the manifest of the tag
+func SetManifest(repository, tag string, manifest *Manifest, ttl time.Duration) error {
+	if cc == nil || ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if ttl > generationTTL {
+		ttl = generationTTL
+	}
+	return cc.Put(manifestKey(repository, tag), string(data), ttl)
+}
+
+// DeleteManifest removes the cached manifest of the tag
+func DeleteManifest(repository, tag string) error {
+	if cc == nil {
+		return nil
+	}
+	return cc.Delete(manifestKey(repository, tag))
+}
+
+// InvalidateManifests invalidates all the cached manifests of the repository. As the
+// tags referencing a digest are unknown when the manifest is deleted by digest,
+// a new generation is started for the repository instead of deleting the keys one by one
+func InvalidateManifests(repository string) error {
+	if cc == nil {
+		return nil
+	}
+	return cc.Put(generationKey(repository),
+		strconv.FormatInt(time.Now().UnixNano(), 10), generationTTL)
+}
+
+func manifestKey(repository, tag string) string {
+	generation, ok := toString(cc.Get(generationKey(repository)))
+	if !ok {
+		generation = "0"
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", manifestKeyPrefix, repository, generation, tag)
+}
+
+func generationKey(repository string) string {
+	return fmt.Sprintf("%s:%s", generationKeyPrefix, repository)
+}
@@ -0,0 +1,118 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRedisConfig(t *testing.T) {
+	_, err := parseRedisConfig("")
+	assert.NotNil(t, err)
+
+	cfg, err := parseRedisConfig("redis:6379,100,pass,2")
+	require.Nil(t, err)
+	m := map[string]string{}
+	require.Nil(t, json.Unmarshal([]byte(cfg), &m))
+	assert.Equal(t, "redis:6379", m["conn"])
+	assert.Equal(t, "pass", m["password"])
+	assert.Equal(t, "2", m["dbNum"])
+	assert.Equal(t, collectionName, m["key"])
+
+	cfg, err = parseRedisConfig("redis:6379")
+	require.Nil(t, err)
+	m = map[string]string{}
+	require.Nil(t, json.Unmarshal([]byte(cfg), &m))
+	assert.Equal(t, "0", m["dbNum"])
+}
+
+func TestManifest(t *testing.T) {
+	require.Nil(t, Init())
+
+	repository := "library/hello-world"
+	manifest := &Manifest{
+		Digest:    "sha256:0a1b",
+		MediaType: "application/vnd.docker.distribution.manifest.v2+json",
+		Size:      524,
+	}
+	assert.Nil(t, GetManifest(repository, "latest"))
+
+	// disabled
+	require.Nil(t, SetManifest(repository, "latest", manifest, 0))
+	assert.Nil(t, GetManifest(repository, "latest"))
+
+	require.Nil(t, SetManifest(repository, "latest", manifest, time.Minute))
+	require.Nil(t, SetManifest(repository, "v1", manifest, time.Minute))
+	assert.Equal(t, manifest, GetManifest(repository, "latest"))
+	assert.Nil(t, GetManifest("library/busybox", "latest"))
+
+	require.Nil(t, DeleteManifest(repository, "latest"))
+	assert.Nil(t, GetManifest(repository, "latest"))
+	assert.Equal(t, manifest, GetManifest(repository, "v1"))
+
+	require.Nil(t, InvalidateManifests(repository))
+	assert.Nil(t, GetManifest(repository, "v1"))
+}
+
+func TestRedirectSources(t *testing.T) {
+	require.Nil(t, Init())
+
+	_, ok := GetRedirectSources()
+	assert.False(t, ok)
+	require.Nil(t, InvalidateRedirectSources())
+
+	expiration := time.Now().Add(time.Hour).Round(0)
+	require.Nil(t, SetRedirectSources(map[string]time.Time{"library/renamed": expiration}, time.Minute))
+	sources, ok := GetRedirectSources()
+	require.True(t, ok)
+	assert.True(t, expiration.Equal(sources["library/renamed"]))
+
+	require.Nil(t, InvalidateRedirectSources())
+	_, ok = GetRedirectSources()
+	assert.False(t, ok)
+}
+
+func TestRepoDeprecations(t *testing.T) {
+	require.Nil(t, Init())
+
+	repository := "library/deprecated"
+	_, ok := GetRepoDeprecations(repository)
+	assert.False(t, ok)
+
+	// the repository not deprecated
+	require.Nil(t, SetRepoDeprecations(repository, []*models.RepoDeprecation{}, time.Minute))
+	deprecations, ok := GetRepoDeprecations(repository)
+	require.True(t, ok)
+	assert.Equal(t, 0, len(deprecations))
+
+	require.Nil(t, SetRepoDeprecations(repository, []*models.RepoDeprecation{
+		{ID: 1, Repository: repository, Tag: "1.0", Message: "vulnerable"},
+	}, time.Minute))
+	deprecations, ok = GetRepoDeprecations(repository)
+	require.True(t, ok)
+	require.Equal(t, 1, len(deprecations))
+	assert.Equal(t, "1.0", deprecations[0].Tag)
+
+	require.Nil(t, InvalidateRepoDeprecations(repository))
+	_, ok = GetRepoDeprecations(repository)
+	assert.False(t, ok)
+	require.Nil(t, InvalidateRepoDeprecations(repository))
+}
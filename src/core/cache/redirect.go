@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const redirectSourcesKey = "repo_redirect_sources"
+
+// GetRedirectSources returns the cached old names of the renamed repositories being redirected
+// and the expiration time of the redirects, false is returned if they aren't cached
+func GetRedirectSources() (map[string]time.Time, bool) {
+	if cc == nil {
+		return nil, false
+	}
+	s, ok := toString(cc.Get(redirectSourcesKey))
+	if !ok {
+		return nil, false
+	}
+	sources := map[string]time.Time{}
+	if err := json.Unmarshal([]byte(s), &sources); err != nil {
+		return nil, false
+	}
+	return sources, true
+}
+
+// SetRedirectSources caches the old names of the renamed repositories being redirected
+func SetRedirectSources(sources map[string]time.Time, ttl time.Duration) error {
+	if cc == nil {
+		return nil
+	}
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	return cc.Put(redirectSourcesKey, string(data), ttl)
+}
+
+// InvalidateRedirectSources removes the cached old names of the renamed repositories, it's
+// called once the redirects are changed
+func InvalidateRedirectSources() error {
+	// the memory cache fails to delete the key not existing
+	if cc == nil || !cc.IsExist(redirectSourcesKey) {
+		return nil
+	}
+	return cc.Delete(redirectSourcesKey)
+}
@@ -0,0 +1,191 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chargeback
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/coretask"
+)
+
+const (
+	// DefaultInterval is the default interval between two checks of the report of the last billing period
+	DefaultInterval = time.Hour
+	// TaskName is the name of the task generating the chargeback reports
+	TaskName = "CHARGEBACK_REPORT"
+)
+
+var (
+	// ErrReportRunning is returned when the report of the billing period is being generated
+	ErrReportRunning = errors.New("the chargeback report of the billing period is being generated")
+	// ErrReportExists is returned when the report of the billing period has been generated
+	ErrReportExists = errors.New("the chargeback report of the billing period already exists")
+
+	// Runner runs the reports submitted to jobservice
+	Runner = &coretask.Runner{
+		Run:  Run,
+		Fail: Fail,
+	}
+)
+
+// Period returns the billing period before the time, it's the last calendar month in UTC
+func Period(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	end := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return end.AddDate(0, -1, 0), end
+}
+
+// Generate records the report of the billing period and submits it to jobservice, the ID of
+// the report record is returned. The failed report of the period is replaced while the running
+// and succeeded ones are kept. The report of a period is unique in the database, so it's
+// generated only once when the instances generate it at the same time
+func Generate(start, end time.Time, creator string) (int64, error) {
+	start, end = start.UTC(), end.UTC()
+	report, err := dao.GetChargebackReportByPeriod(start, end)
+	if err != nil {
+		return 0, err
+	}
+	if report != nil {
+		switch report.Status {
+		case models.ChargebackReportRunning:
+			return 0, ErrReportRunning
+		case models.ChargebackReportSucceeded:
+			return 0, ErrReportExists
+		}
+		if err = dao.DeleteChargebackReport(report.ID); err != nil {
+			return 0, err
+		}
+	}
+	id, err := dao.AddChargebackReport(&models.ChargebackReport{
+		StartTime: start,
+		EndTime:   end,
+		Status:    models.ChargebackReportRunning,
+		Creator:   creator,
+	})
+	if err != nil {
+		if err == dao.ErrDupRows {
+			return 0, ErrReportRunning
+		}
+		return 0, err
+	}
+	if err = coretask.Submit(TaskName, id); err != nil {
+		err = fmt.Errorf("failed to submit the report: %v", err)
+		if e := finish(id, err); e != nil {
+			log.Errorf("failed to finish chargeback report %d: %v", id, e)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Run computes the usage of the projects in the report, the items are computed again if
+// it's interrupted
+func Run(id int64) error {
+	report, err := dao.GetChargebackReport(id)
+	if err != nil {
+		return err
+	}
+	if report == nil || report.Status != models.ChargebackReportRunning {
+		log.Debugf("the chargeback report %d isn't running, skip", id)
+		return nil
+	}
+	return finish(id, generate(id, report.StartTime.UTC(), report.EndTime.UTC()))
+}
+
+// Fail marks the report as failed if it's still running
+func Fail(id int64, message string) error {
+	report, err := dao.GetChargebackReport(id)
+	if err != nil {
+		return err
+	}
+	if report == nil || report.Status != models.ChargebackReportRunning {
+		return nil
+	}
+	return finish(id, errors.New(message))
+}
+
+// finish records the result of the report, the error recording the result is returned
+func finish(id int64, result error) error {
+	status, message := models.ChargebackReportSucceeded, ""
+	if result != nil {
+		log.Errorf("failed to generate the chargeback report %d: %v", id, result)
+		status, message = models.ChargebackReportFailed, result.Error()
+	}
+	if err := dao.UpdateChargebackReportStatus(id, status, message); err != nil {
+		return fmt.Errorf("failed to update the status of chargeback report %d: %v", id, err)
+	}
+	return nil
+}
+
+func generate(id int64, start, end time.Time) error {
+	items, err := dao.ComputeChargebackReportItems(start, end)
+	if err != nil {
+		return err
+	}
+	// the items added by the interrupted run are replaced
+	if err = dao.DeleteChargebackReportItems(id); err != nil {
+		return err
+	}
+	return dao.AddChargebackReportItems(id, items)
+}
+
+// Start generates the report of the last billing period in background once the period
+// ends, it's checked every interval as the core may be down when the period ends
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			start, end := Period(time.Now())
+			report, err := dao.GetChargebackReportByPeriod(start, end)
+			if err != nil {
+				log.Errorf("failed to get the chargeback report of %s: %v", start.Format("2006-01"), err)
+			} else if report == nil {
+				if _, err = Generate(start, end, ""); err != nil && err != ErrReportRunning && err != ErrReportExists {
+					log.Errorf("failed to generate the chargeback report of %s: %v", start.Format("2006-01"), err)
+				}
+			}
+			<-ticker.C
+		}
+	}()
+	log.Infof("the chargeback report scheduler started, interval: %v", interval)
+}
+
+// WriteCSV writes the items of the report in CSV, one row per project
+func WriteCSV(w io.Writer, items []*models.ChargebackReportItem) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"project_id", "project_name", "storage_bytes", "pull_bytes",
+		"scan_job_minutes", "replication_job_minutes"})
+	for _, item := range items {
+		writer.Write([]string{
+			strconv.FormatInt(item.ProjectID, 10),
+			item.ProjectName,
+			strconv.FormatInt(item.StorageBytes, 10),
+			strconv.FormatInt(item.PullBytes, 10),
+			strconv.FormatFloat(item.ScanJobMinutes, 'f', 2, 64),
+			strconv.FormatFloat(item.ReplicationJobMinutes, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,56 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chargeback
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriod(t *testing.T) {
+	start, end := Period(time.Date(2019, 3, 15, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), end)
+
+	// across the year
+	start, end = Period(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), end)
+
+	// converted to UTC
+	start, _ = Period(time.Date(2019, 3, 1, 2, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)))
+	assert.Equal(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), start)
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.Nil(t, WriteCSV(buf, []*models.ChargebackReportItem{
+		{
+			ProjectID:             1,
+			ProjectName:           "library",
+			StorageBytes:          100,
+			PullBytes:             200,
+			ScanJobMinutes:        1.5,
+			ReplicationJobMinutes: 0.25,
+		},
+	}))
+	assert.Equal(t, "project_id,project_name,storage_bytes,pull_bytes,scan_job_minutes,replication_job_minutes\n"+
+		"1,library,100,200,1.50,0.25\n", buf.String())
+}
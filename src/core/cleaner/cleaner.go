@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleaner
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// DefaultInterval is the default interval between two rounds of clean up
+const DefaultInterval = 10 * time.Minute
+
+// Task cleans up the stale records and returns the count of records removed
+type Task func() (int64, error)
+
+var (
+	tasks = map[string]Task{}
+	lock  sync.RWMutex
+)
+
+// Register registers the task with the name, the task registered with the same name is replaced
+func Register(name string, task Task) {
+	lock.Lock()
+	defer lock.Unlock()
+	tasks[name] = task
+}
+
+// Start runs the registered tasks every interval in background
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			RunAll()
+		}
+	}()
+	log.Infof("the cleaner started, interval: %v", interval)
+}
+
+// RunAll runs all the registered tasks once, the failure of one task doesn't stop the others
+func RunAll() {
+	lock.RLock()
+	names := []string{}
+	for name := range tasks {
+		names = append(names, name)
+	}
+	lock.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		lock.RLock()
+		task := tasks[name]
+		lock.RUnlock()
+		count, err := task()
+		if err != nil {
+			log.Errorf("failed to clean up %s: %v", name, err)
+			continue
+		}
+		if count > 0 {
+			log.Infof("%d %s cleaned up", count, name)
+		}
+	}
+}
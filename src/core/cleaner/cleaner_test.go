@@ -0,0 +1,40 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleaner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunAll(t *testing.T) {
+	called := []string{}
+	Register("failed", func() (int64, error) {
+		called = append(called, "failed")
+		return 0, errors.New("error")
+	})
+	Register("succeeded", func() (int64, error) {
+		called = append(called, "succeeded")
+		return 1, nil
+	})
+	defer func() {
+		tasks = map[string]Task{}
+	}()
+
+	RunAll()
+	assert.Equal(t, []string{"failed", "succeeded"}, called)
+}
@@ -0,0 +1,208 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compliance generates the compliance report bundles of projects for the auditors.
+//
+// The bundle is a gzipped tarball containing the following JSON files:
+//
+//	report.json      the project, the time range and the creation time of the report
+//	inventory.json   the tags of all the repositories in the project
+//	scans.json       the vulnerability scan summaries of the tags
+//	signatures.json  the signature status of the tags
+//	audit_logs.json  the audit logs of the project within the time range
+package compliance
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// Version is the version of the bundle format
+const Version = "1"
+
+const (
+	reportFile     = "report.json"
+	inventoryFile  = "inventory.json"
+	scansFile      = "scans.json"
+	signaturesFile = "signatures.json"
+	auditLogsFile  = "audit_logs.json"
+)
+
+// Report is the content of "report.json"
+type Report struct {
+	Version     string    `json:"version"`
+	ProjectID   int64     `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Creator     string    `json:"creator"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Artifact is a tag in the inventory
+type Artifact struct {
+	Repository string     `json:"repository"`
+	Tag        string     `json:"tag"`
+	Digest     string     `json:"digest"`
+	Size       int64      `json:"size"`
+	PullTime   *time.Time `json:"pull_time,omitempty"`
+}
+
+// ScanSummary is the summary of the latest scan of a tag, the severity is
+// "unknown" if the tag has never been scanned successfully
+type ScanSummary struct {
+	Repository string                     `json:"repository"`
+	Tag        string                     `json:"tag"`
+	Digest     string                     `json:"digest"`
+	Status     string                     `json:"status"`
+	Severity   string                     `json:"severity"`
+	Components *models.ComponentsOverview `json:"components,omitempty"`
+	ScanTime   *time.Time                 `json:"scan_time,omitempty"`
+}
+
+// SignatureStatus records whether a tag is signed with the same digest
+type SignatureStatus struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Digest     string `json:"digest"`
+	Signed     bool   `json:"signed"`
+}
+
+// Source provides the data of the report
+type Source interface {
+	// ListArtifacts returns the tags of all the repositories in the project
+	ListArtifacts(project *models.Project) ([]*Artifact, error)
+	// GetScanOverview returns nil if the artifact has never been scanned
+	GetScanOverview(artifact *Artifact) (*models.ImgScanOverview, error)
+	// GetSignedDigests returns the digests of the signed tags of the repository,
+	// it returns nil if the signing isn't enabled
+	GetSignedDigests(repository string) (map[string]string, error)
+	// ListAuditLogs returns the audit logs of the project within the time range
+	ListAuditLogs(projectID int64, start, end time.Time) ([]models.AccessLog, error)
+}
+
+// Write collects the data of the project from the source and writes the bundle into w
+func Write(w io.Writer, source Source, project *models.Project, start, end time.Time, creator string) error {
+	artifacts, err := source.ListArtifacts(project)
+	if err != nil {
+		return fmt.Errorf("failed to list the artifacts: %v", err)
+	}
+
+	scans := []*ScanSummary{}
+	for _, artifact := range artifacts {
+		overview, err := source.GetScanOverview(artifact)
+		if err != nil {
+			return fmt.Errorf("failed to get the scan overview of %s:%s: %v", artifact.Repository, artifact.Tag, err)
+		}
+		scans = append(scans, scanSummary(artifact, overview))
+	}
+
+	signatures := []*SignatureStatus{}
+	// the repository -> signed tag -> digest
+	signed := map[string]map[string]string{}
+	for _, artifact := range artifacts {
+		digests, ok := signed[artifact.Repository]
+		if !ok {
+			if digests, err = source.GetSignedDigests(artifact.Repository); err != nil {
+				return fmt.Errorf("failed to get the signatures of %s: %v", artifact.Repository, err)
+			}
+			signed[artifact.Repository] = digests
+		}
+		signatures = append(signatures, &SignatureStatus{
+			Repository: artifact.Repository,
+			Tag:        artifact.Tag,
+			Digest:     artifact.Digest,
+			Signed:     len(artifact.Digest) > 0 && digests[artifact.Tag] == artifact.Digest,
+		})
+	}
+
+	logs, err := source.ListAuditLogs(project.ProjectID, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to list the audit logs: %v", err)
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{reportFile, &Report{
+			Version:     Version,
+			ProjectID:   project.ProjectID,
+			ProjectName: project.Name,
+			StartTime:   start,
+			EndTime:     end,
+			Creator:     creator,
+			CreatedAt:   time.Now().UTC(),
+		}},
+		{inventoryFile, artifacts},
+		{scansFile, scans},
+		{signaturesFile, signatures},
+		{auditLogsFile, logs},
+	}
+	for _, file := range files {
+		if err = writeJSON(tw, file.name, file.content); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func scanSummary(artifact *Artifact, overview *models.ImgScanOverview) *ScanSummary {
+	summary := &ScanSummary{
+		Repository: artifact.Repository,
+		Tag:        artifact.Tag,
+		Digest:     artifact.Digest,
+		Status:     "not_scanned",
+		Severity:   models.SevUnknown.String(),
+	}
+	if overview == nil {
+		return summary
+	}
+	summary.Status = overview.Status
+	if overview.Status == models.JobFinished {
+		summary.Severity = models.Severity(overview.Sev).String()
+		summary.Components = overview.CompOverview
+		scanTime := overview.UpdateTime
+		summary.ScanTime = &scanTime
+	}
+	return summary
+}
+
+func writeJSON(tw *tar.Writer, name string, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", name, err)
+	}
+	if err = tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
@@ -0,0 +1,154 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	artifacts []*Artifact
+	overviews map[string]*models.ImgScanOverview
+	signed    map[string]map[string]string
+	logs      []models.AccessLog
+	err       error
+}
+
+func (f *fakeSource) ListArtifacts(project *models.Project) ([]*Artifact, error) {
+	return f.artifacts, f.err
+}
+
+func (f *fakeSource) GetScanOverview(artifact *Artifact) (*models.ImgScanOverview, error) {
+	return f.overviews[artifact.Digest], nil
+}
+
+func (f *fakeSource) GetSignedDigests(repository string) (map[string]string, error) {
+	return f.signed[repository], nil
+}
+
+func (f *fakeSource) ListAuditLogs(projectID int64, start, end time.Time) ([]models.AccessLog, error) {
+	return f.logs, nil
+}
+
+func readBundle(t *testing.T, data []byte) map[string][]byte {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.Nil(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		content, err := ioutil.ReadAll(tr)
+		require.Nil(t, err)
+		files[header.Name] = content
+	}
+	return files
+}
+
+func TestWrite(t *testing.T) {
+	source := &fakeSource{
+		artifacts: []*Artifact{
+			{Repository: "library/hello", Tag: "v1", Digest: "sha256:1"},
+			{Repository: "library/hello", Tag: "v2", Digest: "sha256:2"},
+			{Repository: "library/hello", Tag: "v3", Digest: "sha256:3"},
+		},
+		overviews: map[string]*models.ImgScanOverview{
+			"sha256:1": {
+				Status: models.JobFinished,
+				Sev:    int(models.SevHigh),
+				CompOverview: &models.ComponentsOverview{
+					Total: 1,
+				},
+			},
+			"sha256:2": {
+				Status: models.JobRunning,
+				Sev:    int(models.SevLow),
+			},
+		},
+		signed: map[string]map[string]string{
+			"library/hello": {
+				"v1": "sha256:1",
+				"v2": "sha256:0",
+			},
+		},
+		logs: []models.AccessLog{
+			{Username: "admin", Operation: "push"},
+		},
+	}
+	project := &models.Project{
+		ProjectID: 1,
+		Name:      "library",
+	}
+	end := time.Now().UTC()
+	start := end.Add(-time.Hour)
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, Write(buf, source, project, start, end, "admin"))
+	files := readBundle(t, buf.Bytes())
+	require.Equal(t, 5, len(files))
+
+	report := &Report{}
+	require.Nil(t, json.Unmarshal(files[reportFile], report))
+	assert.Equal(t, Version, report.Version)
+	assert.Equal(t, "library", report.ProjectName)
+	assert.Equal(t, "admin", report.Creator)
+
+	scans := []*ScanSummary{}
+	require.Nil(t, json.Unmarshal(files[scansFile], &scans))
+	require.Equal(t, 3, len(scans))
+	assert.Equal(t, "high", scans[0].Severity)
+	assert.NotNil(t, scans[0].Components)
+	assert.Equal(t, "unknown", scans[1].Severity)
+	assert.Equal(t, models.JobRunning, scans[1].Status)
+	assert.Equal(t, "not_scanned", scans[2].Status)
+
+	signatures := []*SignatureStatus{}
+	require.Nil(t, json.Unmarshal(files[signaturesFile], &signatures))
+	require.Equal(t, 3, len(signatures))
+	assert.True(t, signatures[0].Signed)
+	// signed with another digest
+	assert.False(t, signatures[1].Signed)
+	assert.False(t, signatures[2].Signed)
+
+	logs := []models.AccessLog{}
+	require.Nil(t, json.Unmarshal(files[auditLogsFile], &logs))
+	assert.Equal(t, 1, len(logs))
+}
+
+func TestWriteError(t *testing.T) {
+	source := &fakeSource{
+		err: errors.New("registry unavailable"),
+	}
+	buf := &bytes.Buffer{}
+	err := Write(buf, source, &models.Project{Name: "library"}, time.Now(), time.Now(), "admin")
+	require.NotNil(t, err)
+	// nothing is written if the data fails to be collected
+	assert.Equal(t, 0, buf.Len())
+}
@@ -0,0 +1,172 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/coretask"
+)
+
+// TaskName is the name of the task generating the compliance reports
+const TaskName = "COMPLIANCE_REPORT"
+
+// ErrReportRunning is returned when another report of the project is still being generated
+var ErrReportRunning = errors.New("another compliance report of the project is being generated")
+
+var (
+	// NewSource returns the source of the report generated by the user, defined as a var for testing
+	NewSource = func(username string) Source {
+		return &harborSource{
+			username: username,
+		}
+	}
+	// Runner runs the reports submitted to jobservice
+	Runner = &coretask.Runner{
+		Run:  Run,
+		Fail: Fail,
+	}
+)
+
+// Generate records the report of the project and submits it to jobservice, the ID of the
+// report record is returned. Only one report of a project is allowed to be generated at the
+// same time, which is locked in the database
+func Generate(project *models.Project, start, end time.Time, creator string) (int64, error) {
+	id, err := dao.AddComplianceReport(&models.ComplianceReport{
+		ProjectID: project.ProjectID,
+		StartTime: start,
+		EndTime:   end,
+		Status:    models.ComplianceReportRunning,
+		Creator:   creator,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = dao.LockResources(coretask.Owner(TaskName, id), coretask.ProjectResource(TaskName, project.ProjectID)); err != nil {
+		if e := dao.DeleteComplianceReport(id); e != nil {
+			log.Errorf("failed to delete compliance report %d: %v", id, e)
+		}
+		if err == dao.ErrResourceLocked {
+			return 0, ErrReportRunning
+		}
+		return 0, err
+	}
+	if err = coretask.Submit(TaskName, id); err != nil {
+		err = fmt.Errorf("failed to submit the report: %v", err)
+		if e := finish(id, 0, err); e != nil {
+			log.Errorf("failed to finish compliance report %d: %v", id, e)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Run generates the bundle of the report, the bundle is generated again if it's interrupted
+func Run(id int64) error {
+	report, err := dao.GetComplianceReport(id)
+	if err != nil {
+		return err
+	}
+	if report == nil || report.Status != models.ComplianceReportRunning {
+		log.Debugf("the compliance report %d isn't running, skip", id)
+		return nil
+	}
+	project, err := config.GlobalProjectMgr.Get(report.ProjectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return finish(id, 0, fmt.Errorf("project %d not found", report.ProjectID))
+	}
+	size, err := generate(id, project, report.StartTime, report.EndTime, report.Creator)
+	return finish(id, size, err)
+}
+
+// Fail marks the report as failed if it's still running
+func Fail(id int64, message string) error {
+	report, err := dao.GetComplianceReport(id)
+	if err != nil {
+		return err
+	}
+	if report == nil || report.Status != models.ComplianceReportRunning {
+		return nil
+	}
+	return finish(id, 0, errors.New(message))
+}
+
+// finish records the result of the report and releases the lock, the error recording
+// the result is returned
+func finish(id, size int64, result error) error {
+	status, message := models.ComplianceReportSucceeded, ""
+	if result != nil {
+		log.Errorf("failed to generate the compliance report %d: %v", id, result)
+		status, message = models.ComplianceReportFailed, result.Error()
+	}
+	if err := dao.UpdateComplianceReportStatus(id, status, message, size); err != nil {
+		return fmt.Errorf("failed to update the status of compliance report %d: %v", id, err)
+	}
+	return dao.UnlockResources(coretask.Owner(TaskName, id))
+}
+
+// generate writes the bundle into a temporary file which is renamed once it's complete,
+// the size of the bundle is returned
+func generate(id int64, project *models.Project, start, end time.Time, creator string) (int64, error) {
+	if err := os.MkdirAll(config.ComplianceReportDir(), 0700); err != nil {
+		return 0, err
+	}
+	tmp := Path(id) + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+
+	err = Write(file, NewSource(creator), project, start, end, creator)
+	if e := file.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return 0, err
+	}
+	if err = os.Rename(tmp, Path(id)); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Path returns the path of the bundle file of the report
+func Path(id int64) string {
+	return filepath.Join(config.ComplianceReportDir(), fmt.Sprintf("%d.tar.gz", id))
+}
+
+// Delete deletes the report record and the bundle file
+func Delete(id int64) error {
+	if err := os.Remove(Path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return dao.DeleteComplianceReport(id)
+}
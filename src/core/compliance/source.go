@@ -0,0 +1,144 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/notary"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// the page size used to list the audit logs
+const auditLogPageSize = 1000
+
+// harborSource reads the data from the database, registry and notary of Harbor,
+// the username is used to access notary
+type harborSource struct {
+	username string
+}
+
+func (h *harborSource) ListArtifacts(project *models.Project) ([]*Artifact, error) {
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{project.ProjectID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	artifacts := []*Artifact{}
+	for _, repository := range repositories {
+		client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for repository %s: %v", repository.Name, err)
+		}
+		tags, err := client.ListTag()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of repository %s: %v", repository.Name, err)
+		}
+		for _, tag := range tags {
+			artifact := &Artifact{
+				Repository: repository.Name,
+				Tag:        tag,
+			}
+			digest, _, payload, err := client.PullManifest(tag, []string{schema2.MediaTypeManifest})
+			if err != nil {
+				return nil, fmt.Errorf("failed to pull manifest of %s:%s: %v", repository.Name, tag, err)
+			}
+			artifact.Digest = digest
+			artifact.Size = int64(len(payload))
+			manifest := &schema2.DeserializedManifest{}
+			if err = manifest.UnmarshalJSON(payload); err == nil {
+				for _, ref := range manifest.References() {
+					artifact.Size += ref.Size
+				}
+			}
+			pullTime, err := dao.GetTagPullTime(repository.Name, tag)
+			if err != nil {
+				log.Errorf("failed to get pull time of %s:%s: %v", repository.Name, tag, err)
+			} else if pullTime != nil {
+				artifact.PullTime = &pullTime.PullTime
+			}
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts, nil
+}
+
+func (h *harborSource) GetScanOverview(artifact *Artifact) (*models.ImgScanOverview, error) {
+	if !config.WithClair() || len(artifact.Digest) == 0 {
+		return nil, nil
+	}
+	overview, err := dao.GetImgScanOverview(artifact.Digest)
+	if err != nil || overview == nil {
+		return nil, err
+	}
+	job, err := dao.GetScanJob(overview.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, nil
+	}
+	overview.Status = job.Status
+	return overview, nil
+}
+
+func (h *harborSource) GetSignedDigests(repository string) (map[string]string, error) {
+	if !config.WithNotary() {
+		return nil, nil
+	}
+	targets, err := notary.GetInternalTargets(config.InternalNotaryEndpoint(), h.username, repository)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	for _, target := range targets {
+		digest, err := notary.DigestFromTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		digests[target.Tag] = digest
+	}
+	return digests, nil
+}
+
+func (h *harborSource) ListAuditLogs(projectID int64, start, end time.Time) ([]models.AccessLog, error) {
+	logs := []models.AccessLog{}
+	for page := int64(1); ; page++ {
+		items, err := accesslog.List(&models.LogQueryParam{
+			ProjectIDs: []int64{projectID},
+			BeginTime:  &start,
+			EndTime:    &end,
+			Pagination: &models.Pagination{
+				Page: page,
+				Size: auditLogPageSize,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, items...)
+		if len(items) < auditLogPageSize {
+			return logs, nil
+		}
+	}
+}
@@ -25,10 +25,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goharbor/harbor/src/adminserver/client"
 	"github.com/goharbor/harbor/src/common"
 	comcfg "github.com/goharbor/harbor/src/common/config"
+	"github.com/goharbor/harbor/src/common/feature"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/secret"
 	"github.com/goharbor/harbor/src/common/utils"
@@ -43,6 +45,9 @@ const (
 	defaultKeyPath                     = "/etc/core/key"
 	defaultTokenFilePath               = "/etc/core/token/tokens.properties"
 	defaultRegistryTokenPrivateKeyPath = "/etc/core/private_key.pem"
+	defaultComplianceReportDir         = "/data/compliance_reports"
+	defaultRepositoryExportDir         = "/data/repository_exports"
+	defaultBundleExportDir             = "/data/bundle_exports"
 )
 
 var (
@@ -202,6 +207,46 @@ func TokenPrivateKeyPath() string {
 	return path
 }
 
+// ComplianceReportDir returns the directory in which the compliance report bundles are stored
+func ComplianceReportDir() string {
+	dir := os.Getenv("COMPLIANCE_REPORT_DIR")
+	if len(dir) == 0 {
+		dir = defaultComplianceReportDir
+	}
+	return dir
+}
+
+// RepositoryExportDir returns the directory in which the dumps of the repository exports are stored
+func RepositoryExportDir() string {
+	dir := os.Getenv("REPOSITORY_EXPORT_DIR")
+	if len(dir) == 0 {
+		dir = defaultRepositoryExportDir
+	}
+	return dir
+}
+
+// BundleExportDir returns the directory in which the offline bundles of the bundle exports are stored
+func BundleExportDir() string {
+	dir := os.Getenv("BUNDLE_EXPORT_DIR")
+	if len(dir) == 0 {
+		dir = defaultBundleExportDir
+	}
+	return dir
+}
+
+// RegistryProxyMiddlewares returns the names of the middlewares of the registry proxy in order,
+// they're configured as a comma separated list, nil is returned if it isn't configured
+func RegistryProxyMiddlewares() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("REGISTRY_PROXY_MIDDLEWARES"), ",") {
+		name = strings.TrimSpace(name)
+		if len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // LDAPConf returns the setting of ldap server
 func LDAPConf() (*models.LdapConf, error) {
 	cfg, err := mg.Get()
@@ -375,6 +420,87 @@ func OnlyAdminCreateProject() (bool, error) {
 	return utils.SafeCastString(cfg[common.ProjectCreationRestriction]) == common.ProCrtRestrAdmOnly, nil
 }
 
+// ProjectCreationRestriction returns who can create the projects, the project created by the user other
+// than system admin must be approved if it's "approval"
+func ProjectCreationRestriction() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.ProjectCreationRestriction]), nil
+}
+
+// ApprovalWebhookURL returns the URL which the events of approvals are posted to
+func ApprovalWebhookURL() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.ApprovalWebhookURL]), nil
+}
+
+// BlocklistWebhookURL returns the URL which the alerts of the requests for blocked digests are posted to
+func BlocklistWebhookURL() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.BlocklistWebhookURL]), nil
+}
+
+// ProjectWebhookURL returns the URL which the events of the creation of projects are posted to
+func ProjectWebhookURL() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.ProjectWebhookURL]), nil
+}
+
+// WebhookSchema returns the version of the payload schema of the webhook whose schema is
+// configured by the key, e.g. common.ApprovalWebhookSchema, it's "v1" if not set
+func WebhookSchema(key string) (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	schema := utils.SafeCastString(cfg[key])
+	if len(schema) == 0 {
+		schema = models.WebhookSchemaV1
+	}
+	return schema, nil
+}
+
+// EgressAllowlist returns the allowlist of the remote registries and namespaces the replication
+// may reach, the replication isn't restricted if it's empty
+func EgressAllowlist() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.EgressAllowlist]), nil
+}
+
+// ProjectReportCron returns the cron of the job sending the report emails of the projects, the
+// reports aren't sent if it is empty
+func ProjectReportCron() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.ProjectReportCron]), nil
+}
+
+// ShortNameProject returns the project which the short names of images without project
+// are resolved into, the short names aren't accepted if it is empty
+func ShortNameProject() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.ShortNameProject]), nil
+}
+
 // Email returns email server settings
 func Email() (*models.Email, error) {
 	cfg, err := mg.Get()
@@ -395,6 +521,27 @@ func Email() (*models.Email, error) {
 	return email, nil
 }
 
+// TokenExchange returns the settings of exchanging the service account tokens for the registry tokens
+func TokenExchange() (*models.TokenExchange, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := &models.TokenExchange{}
+	exchange.Issuer = utils.SafeCastString(cfg[common.TokenExchangeIssuer])
+	exchange.PublicKeys = utils.SafeCastString(cfg[common.TokenExchangePublicKeys])
+	exchange.Audience = utils.SafeCastString(cfg[common.TokenExchangeAudience])
+	exchange.Expiration = int(utils.SafeCastFloat64(cfg[common.TokenExchangeExpiration]))
+	bindings, err := models.ParseServiceAccountBindings(utils.SafeCastString(cfg[common.TokenExchangeBindings]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", common.TokenExchangeBindings, err)
+	}
+	exchange.Bindings = bindings
+
+	return exchange, nil
+}
+
 // Database returns database settings
 func Database() (*models.Database, error) {
 	cfg, err := mg.Get()
@@ -412,11 +559,90 @@ func Database() (*models.Database, error) {
 	postgresql.Password = utils.SafeCastString(cfg[common.PostGreSQLPassword])
 	postgresql.Database = utils.SafeCastString(cfg[common.PostGreSQLDatabase])
 	postgresql.SSLMode = utils.SafeCastString(cfg[common.PostGreSQLSSLMode])
+	postgresql.SSLRootCert = utils.SafeCastString(cfg[common.PostGreSQLSSLRootCert])
+	postgresql.AuthMode = utils.SafeCastString(cfg[common.PostGreSQLAuthMode])
+	postgresql.AWSRegion = utils.SafeCastString(cfg[common.PostGreSQLAWSRegion])
+	postgresql.AzureClientID = utils.SafeCastString(cfg[common.PostGreSQLAzureClientID])
 	database.PostGreSQL = postgresql
 
 	return database, nil
 }
 
+// AccessLogStore returns the settings of the store of the access logs
+func AccessLogStore() (*models.AccessLogStore, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &models.AccessLogStore{
+		Type:       utils.SafeCastString(cfg[common.AccessLogStore]),
+		ESURL:      utils.SafeCastString(cfg[common.AccessLogESURL]),
+		ESIndex:    utils.SafeCastString(cfg[common.AccessLogESIndex]),
+		ESUsername: utils.SafeCastString(cfg[common.AccessLogESUsername]),
+		ESPassword: utils.SafeCastString(cfg[common.AccessLogESPassword]),
+	}, nil
+}
+
+// RegistryStorage returns the storage of registry configured with the first-class settings,
+// OSS and COS are returned with the settings of the provider and the others only with the name
+func RegistryStorage() (*models.RegistryStorage, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	storage := &models.RegistryStorage{
+		Provider: utils.SafeCastString(cfg[common.RegistryStorageProviderName]),
+	}
+	switch storage.Provider {
+	case models.StorageProviderOSS:
+		storage.OSS = &models.OSSStorage{
+			Region:          utils.SafeCastString(cfg[common.RegistryStorageOSSRegion]),
+			Endpoint:        utils.SafeCastString(cfg[common.RegistryStorageOSSEndpoint]),
+			Bucket:          utils.SafeCastString(cfg[common.RegistryStorageOSSBucket]),
+			AccessKeyID:     utils.SafeCastString(cfg[common.RegistryStorageOSSAccessKeyID]),
+			AccessKeySecret: utils.SafeCastString(cfg[common.RegistryStorageOSSAccessKeySecret]),
+			Internal:        utils.SafeCastBool(cfg[common.RegistryStorageOSSInternal]),
+			Secure:          utils.SafeCastBool(cfg[common.RegistryStorageOSSSecure]),
+			RootDirectory:   utils.SafeCastString(cfg[common.RegistryStorageOSSRootDirectory]),
+		}
+	case models.StorageProviderCOS:
+		storage.COS = &models.COSStorage{
+			Region:        utils.SafeCastString(cfg[common.RegistryStorageCOSRegion]),
+			Endpoint:      utils.SafeCastString(cfg[common.RegistryStorageCOSEndpoint]),
+			Bucket:        utils.SafeCastString(cfg[common.RegistryStorageCOSBucket]),
+			SecretID:      utils.SafeCastString(cfg[common.RegistryStorageCOSSecretID]),
+			SecretKey:     utils.SafeCastString(cfg[common.RegistryStorageCOSSecretKey]),
+			Internal:      utils.SafeCastBool(cfg[common.RegistryStorageCOSInternal]),
+			Secure:        utils.SafeCastBool(cfg[common.RegistryStorageCOSSecure]),
+			RootDirectory: utils.SafeCastString(cfg[common.RegistryStorageCOSRootDirectory]),
+		}
+	}
+	return storage, nil
+}
+
+// MetadataSync returns the settings of the metadata sync with the peer instance
+func MetadataSync() (*models.MetadataSync, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &models.MetadataSync{
+		PeerURL:  strings.TrimSuffix(utils.SafeCastString(cfg[common.MetadataSyncPeerURL]), "/"),
+		Secret:   utils.SafeCastString(cfg[common.MetadataSyncSecret]),
+		Priority: int(utils.SafeCastFloat64(cfg[common.MetadataSyncPriority])),
+	}, nil
+}
+
+// AdmissionWebhookSecret returns the secret authenticating the validating admission webhook
+// of Kubernetes, which is sent as the bearer token
+func AdmissionWebhookSecret() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	return utils.SafeCastString(cfg[common.AdmissionWebhookSecret]), nil
+}
+
 // CoreSecret returns a secret to mark harbor-core when communicate with
 // other component
 func CoreSecret() string {
@@ -535,6 +761,19 @@ func UAASettings() (*models.UAASettings, error) {
 	return us, nil
 }
 
+// ExternalAuthzSettings returns the settings to delegate the authorization decision to an external endpoint.
+func ExternalAuthzSettings() (*models.ExternalAuthzSettings, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &models.ExternalAuthzSettings{
+		Endpoint: strings.TrimSpace(utils.SafeCastString(cfg[common.ExternalAuthzEndpoint])),
+		FailOpen: utils.SafeCastBool(cfg[common.ExternalAuthzFailOpen]),
+		CacheTTL: int(utils.SafeCastFloat64(cfg[common.ExternalAuthzCacheTTL])),
+	}, nil
+}
+
 // ReadOnly returns a bool to indicates if Harbor is in read only mode.
 func ReadOnly() bool {
 	cfg, err := mg.Get()
@@ -545,6 +784,132 @@ func ReadOnly() bool {
 	return utils.SafeCastBool(cfg[common.ReadOnly])
 }
 
+// DestructiveOpConfirmation returns a bool to indicate if the destructive operations require a confirmation token.
+func DestructiveOpConfirmation() bool {
+	cfg, err := mg.Get()
+	if err != nil {
+		log.Errorf("Failed to get configuration, will return false as destructive operation confirmation, error: %v", err)
+		return false
+	}
+	return utils.SafeCastBool(cfg[common.DestructiveOpConfirmation])
+}
+
+// UploadPurgingAge returns the age after which the incomplete blob upload sessions are purged.
+func UploadPurgingAge() (time.Duration, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(utils.SafeCastFloat64(cfg[common.UploadPurgingAge])) * time.Hour, nil
+}
+
+// ManifestCacheTTL returns the time the digests of the manifests referenced by tags are cached,
+// 0 means the cache is disabled.
+func ManifestCacheTTL() (time.Duration, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(utils.SafeCastFloat64(cfg[common.ManifestCacheTTL])) * time.Second, nil
+}
+
+// RenameRedirectPeriod returns the period during which the old name of a renamed repository is redirected to the new name.
+func RenameRedirectPeriod() (time.Duration, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(utils.SafeCastFloat64(cfg[common.RenameRedirectPeriod])) * time.Hour, nil
+}
+
+// TokenKeyGracePeriod returns the period during which the tokens signed by a rotated private key are still verified
+func TokenKeyGracePeriod() (time.Duration, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(utils.SafeCastFloat64(cfg[common.TokenKeyGracePeriod])) * time.Hour, nil
+}
+
+// RegistryBreakerThreshold returns the count of the consecutive failures of the registry opening the breaker of the proxy
+func RegistryBreakerThreshold() (int, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	return int(utils.SafeCastFloat64(cfg[common.BreakerThreshold])), nil
+}
+
+// RegistryBreakerProbeInterval returns the interval between two probes of the registry once the breaker of the proxy is open
+func RegistryBreakerProbeInterval() (time.Duration, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(utils.SafeCastFloat64(cfg[common.BreakerProbeInterval])) * time.Second, nil
+}
+
+// CVSSSource returns the source of CVSS used to decide the severity of vulnerabilities
+func CVSSSource() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	source := utils.SafeCastString(cfg[common.CVSSSource])
+	if len(source) == 0 {
+		source = common.CVSSSourceVendor
+	}
+	return source, nil
+}
+
+// FeatureFlags returns the states of the feature flags toggled at runtime
+func FeatureFlags() (feature.Overrides, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	return feature.Parse(utils.SafeCastString(cfg[common.FeatureFlags]))
+}
+
+// FeatureEnabled returns a bool to indicate if the feature is enabled, the default state
+// of the feature is returned if the configuration can't be got
+func FeatureEnabled(name string) bool {
+	overrides, err := FeatureFlags()
+	if err != nil {
+		log.Errorf("Failed to get the feature flags, the default state of %s is returned, error: %v", name, err)
+		return feature.Overrides{}.Enabled(name)
+	}
+	return overrides.Enabled(name)
+}
+
+// RequestSizeLimits returns the max sizes of the requests, they're configured in KB
+func RequestSizeLimits() (*models.RequestSizeLimits, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &models.RequestSizeLimits{
+		JSONBody:    int64(utils.SafeCastFloat64(cfg[common.MaxJSONBodySize])) << 10,
+		ChartUpload: int64(utils.SafeCastFloat64(cfg[common.MaxChartUploadSize])) << 10,
+		LogQuery:    int64(utils.SafeCastFloat64(cfg[common.MaxLogQuerySize])) << 10,
+	}, nil
+}
+
+// Maintenance returns the state of maintenance mode
+func Maintenance() (*models.Maintenance, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	maintenance := &models.Maintenance{}
+	if value := utils.SafeCastString(cfg[common.Maintenance]); len(value) > 0 {
+		if err = json.Unmarshal([]byte(value), maintenance); err != nil {
+			return nil, fmt.Errorf("invalid maintenance %q: %v", value, err)
+		}
+	}
+	return maintenance, nil
+}
+
 // WithChartMuseum returns a bool to indicate if chartmuseum is deployed with Harbor.
 func WithChartMuseum() bool {
 	cfg, err := mg.Get()
@@ -95,6 +95,34 @@ func TestConfig(t *testing.T) {
 		t.Fatalf("failed to get token expiration: %v", err)
 	}
 
+	if _, err := UploadPurgingAge(); err != nil {
+		t.Fatalf("failed to get upload purging age: %v", err)
+	}
+
+	if _, err := ManifestCacheTTL(); err != nil {
+		t.Fatalf("failed to get manifest cache TTL: %v", err)
+	}
+
+	if _, err := RenameRedirectPeriod(); err != nil {
+		t.Fatalf("failed to get rename redirect period: %v", err)
+	}
+
+	if _, err := TokenKeyGracePeriod(); err != nil {
+		t.Fatalf("failed to get token key grace period: %v", err)
+	}
+
+	if _, err := RegistryBreakerThreshold(); err != nil {
+		t.Fatalf("failed to get registry breaker threshold: %v", err)
+	}
+
+	if _, err := RegistryBreakerProbeInterval(); err != nil {
+		t.Fatalf("failed to get registry breaker probe interval: %v", err)
+	}
+
+	if _, err := TokenExchange(); err != nil {
+		t.Fatalf("failed to get token exchange settings: %v", err)
+	}
+
 	if _, err := ExtEndpoint(); err != nil {
 		t.Fatalf("failed to get domain name: %v", err)
 	}
@@ -161,6 +189,9 @@ func TestConfig(t *testing.T) {
 	if ReadOnly() {
 		t.Errorf("ReadOnly should be false")
 	}
+	if DestructiveOpConfirmation() {
+		t.Errorf("DestructiveOpConfirmation should be false")
+	}
 	if AdmiralEndpoint() != "http://www.vmware.com" {
 		t.Errorf("Unexpected admiral endpoint: %s", AdmiralEndpoint())
 	}
@@ -202,11 +233,28 @@ func TestConfig(t *testing.T) {
 	if us.ClientID != "testid" || us.ClientSecret != "testsecret" || us.Endpoint != "10.192.168.5" || us.VerifyCert {
 		t.Errorf("Unexpected UAA setting: %+v", *us)
 	}
+
+	authz, err := ExternalAuthzSettings()
+	if err != nil {
+		t.Fatalf("failed to get external authorization settings, error: %v", err)
+	}
+	assert.Equal("", authz.Endpoint)
+	assert.False(authz.FailOpen)
 	assert.Equal("http://myjob:8888", InternalJobServiceURL())
 	assert.Equal("http://myui:8888/service/token", InternalTokenServiceEndpoint())
 
 }
 
+func TestRegistryProxyMiddlewares(t *testing.T) {
+	ori := os.Getenv("REGISTRY_PROXY_MIDDLEWARES")
+	defer os.Setenv("REGISTRY_PROXY_MIDDLEWARES", ori)
+
+	os.Setenv("REGISTRY_PROXY_MIDDLEWARES", "")
+	assert.Nil(t, RegistryProxyMiddlewares())
+	os.Setenv("REGISTRY_PROXY_MIDDLEWARES", " traffic, ,url,vulnerable ")
+	assert.Equal(t, []string{"traffic", "url", "vulnerable"}, RegistryProxyMiddlewares())
+}
+
 func currPath() string {
 	_, f, _, ok := runtime.Caller(0)
 	if !ok {
@@ -0,0 +1,84 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coretask runs the tasks of core in background through jobservice, e.g. the project
+// merges. The task records are added by the callers, the jobs submitted to jobservice call core
+// to run the tasks and are retried if core restarts in the middle, so the tasks must be resumable.
+// The resources of the tasks are locked in the database, so they're shared by all the instances
+package coretask
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/job"
+	jobmodels "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// Runner runs the tasks of a kind
+type Runner struct {
+	// Run runs the task and records the result in the task record, an error is returned only if
+	// the task should be retried, e.g. the result can't be recorded. The tasks ended are skipped
+	Run func(id int64) error
+	// Fail records the failure of the task once jobservice gives up retrying it, the locks held
+	// by the task are released
+	Fail func(id int64, message string) error
+}
+
+var (
+	runners = map[string]*Runner{}
+	// submits the job to jobservice, replaced in testing
+	submitJob = func(data *jobmodels.JobData) (string, error) {
+		return coreutils.GetJobServiceClient().SubmitJob(data)
+	}
+)
+
+// Register registers the runner of the tasks of the name
+func Register(name string, runner *Runner) {
+	runners[name] = runner
+}
+
+// Get returns the runner of the tasks of the name, nil is returned if it isn't registered
+func Get(name string) *Runner {
+	return runners[name]
+}
+
+// Owner returns the owner of the locks held by the task
+func Owner(name string, id int64) string {
+	return fmt.Sprintf("%s:%d", name, id)
+}
+
+// ProjectResource returns the resource of the project locked by the tasks of the name,
+// e.g. "PROJECT_MERGE/project/1"
+func ProjectResource(name string, projectID int64) string {
+	return fmt.Sprintf("%s/project/%d", name, projectID)
+}
+
+// Submit submits the job running the task to jobservice
+func Submit(name string, id int64) error {
+	_, err := submitJob(&jobmodels.JobData{
+		Name: job.CoreTask,
+		Parameters: jobmodels.Parameters{
+			job.TaskNameParam: name,
+			job.TaskIDParam:   id,
+		},
+		Metadata: &jobmodels.JobMetadata{
+			JobKind: job.JobKindGeneric,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/task/%s/%d",
+			config.InternalCoreURL(), name, id),
+	})
+	return err
+}
@@ -0,0 +1,71 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretask
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/job"
+	jobmodels "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	server, err := test.NewAdminserver(nil)
+	if err != nil {
+		panic(err)
+	}
+	defer server.Close()
+	if err := os.Setenv("ADMINSERVER_URL", server.URL); err != nil {
+		panic(err)
+	}
+	if err := config.Init(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestRegister(t *testing.T) {
+	assert.Nil(t, Get("TEST"))
+	runner := &Runner{}
+	Register("TEST", runner)
+	defer delete(runners, "TEST")
+	assert.Equal(t, runner, Get("TEST"))
+	assert.Equal(t, "TEST:1", Owner("TEST", 1))
+	assert.Equal(t, "TEST/project/1", ProjectResource("TEST", 1))
+}
+
+func TestSubmit(t *testing.T) {
+	defer func(submit func(*jobmodels.JobData) (string, error)) {
+		submitJob = submit
+	}(submitJob)
+	var submitted *jobmodels.JobData
+	submitJob = func(data *jobmodels.JobData) (string, error) {
+		submitted = data
+		return "uuid", nil
+	}
+
+	require.Nil(t, Submit("TEST", 1))
+	require.NotNil(t, submitted)
+	assert.Equal(t, job.CoreTask, submitted.Name)
+	assert.Equal(t, "TEST", submitted.Parameters[job.TaskNameParam])
+	assert.Equal(t, int64(1), submitted.Parameters[job.TaskIDParam])
+	assert.Equal(t, job.JobKindGeneric, submitted.Metadata.JobKind)
+	assert.Equal(t, config.InternalCoreURL()+"/service/notifications/jobs/task/TEST/1", submitted.StatusHook)
+}
@@ -0,0 +1,131 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package egress restricts the remote registries and namespaces the replication may reach to
+// the allowlist defined by the system admin, so that the projects can't replicate their images
+// to arbitrary registries on the internet.
+package egress
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// Rule is an entry of the allowlist, all the repositories of the matched hosts are allowed
+// if the namespace is empty, otherwise only the ones under the namespace are
+type Rule struct {
+	// the host with the optional port, it may contain the wildcards of path.Match, e.g. "*.example.com"
+	Host      string
+	Namespace string
+}
+
+// DeniedError is returned when the endpoint or repository isn't in the allowlist
+type DeniedError struct {
+	Endpoint   string
+	Repository string
+}
+
+func (d *DeniedError) Error() string {
+	if len(d.Repository) == 0 {
+		return fmt.Sprintf("the endpoint %s is not in the egress allowlist", d.Endpoint)
+	}
+	return fmt.Sprintf("the repository %s on %s is not in the egress allowlist", d.Repository, d.Endpoint)
+}
+
+// the function reading the allowlist, replaced in testing
+var getAllowlist = config.EgressAllowlist
+
+// Parse parses the allowlist, the entries in the form "<host>[/<namespace>]" are separated by
+// commas or new lines, e.g. "registry.example.com:5000/dr, *.mirror.example.com"
+func Parse(allowlist string) ([]*Rule, error) {
+	rules := []*Rule{}
+	for _, entry := range strings.FieldsFunc(allowlist, func(r rune) bool {
+		return r == ',' || r == '\n'
+	}) {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		parts := strings.SplitN(entry, "/", 2)
+		rule := &Rule{
+			Host: strings.ToLower(parts[0]),
+		}
+		if len(parts) == 2 {
+			rule.Namespace = strings.Trim(parts[1], "/")
+			if len(rule.Namespace) == 0 {
+				return nil, fmt.Errorf("empty namespace in entry %s", entry)
+			}
+		}
+		if len(rule.Host) == 0 {
+			return nil, fmt.Errorf("empty host in entry %s", entry)
+		}
+		if _, err := path.Match(rule.Host, ""); err != nil {
+			return nil, fmt.Errorf("invalid host in entry %s: %v", entry, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Allowed returns whether the repository on the registry of the endpoint is allowed by the
+// rules, only the endpoint is checked if the repository is empty. Everything is allowed if
+// there is no rule
+func Allowed(rules []*Rule, endpoint, repository string) (bool, error) {
+	if len(rules) == 0 {
+		return true, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false, err
+	}
+	host := strings.ToLower(u.Host)
+	for _, rule := range rules {
+		if matched, _ := path.Match(rule.Host, host); !matched {
+			continue
+		}
+		if len(repository) == 0 || len(rule.Namespace) == 0 ||
+			repository == rule.Namespace || strings.HasPrefix(repository, rule.Namespace+"/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Check checks the repository on the registry of the endpoint against the allowlist configured,
+// a DeniedError is returned if it isn't allowed
+func Check(endpoint, repository string) error {
+	allowlist, err := getAllowlist()
+	if err != nil {
+		return err
+	}
+	rules, err := Parse(allowlist)
+	if err != nil {
+		return fmt.Errorf("invalid egress allowlist: %v", err)
+	}
+	allowed, err := Allowed(rules, endpoint, repository)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return &DeniedError{
+			Endpoint:   endpoint,
+			Repository: repository,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse("")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(rules))
+
+	rules, err = Parse("Registry.example.com:5000/dr/, *.mirror.example.com\n\nquay.io")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(rules))
+	assert.Equal(t, &Rule{Host: "registry.example.com:5000", Namespace: "dr"}, rules[0])
+	assert.Equal(t, &Rule{Host: "*.mirror.example.com"}, rules[1])
+	assert.Equal(t, &Rule{Host: "quay.io"}, rules[2])
+
+	_, err = Parse("/library")
+	assert.NotNil(t, err)
+	_, err = Parse("quay.io//")
+	assert.NotNil(t, err)
+	_, err = Parse("[quay.io")
+	assert.NotNil(t, err)
+}
+
+func TestAllowed(t *testing.T) {
+	allowed, err := Allowed(nil, "https://any.example.com", "library/hello-world")
+	require.Nil(t, err)
+	assert.True(t, allowed)
+
+	rules, err := Parse("registry.example.com:5000/dr, *.mirror.example.com")
+	require.Nil(t, err)
+	cases := []struct {
+		endpoint   string
+		repository string
+		allowed    bool
+	}{
+		{"https://registry.example.com:5000", "", true},
+		{"https://registry.example.com:5000", "dr/hello-world", true},
+		{"https://registry.example.com:5000", "dr", true},
+		{"https://registry.example.com:5000", "drill/hello-world", false},
+		{"https://registry.example.com", "dr/hello-world", false},
+		{"https://eu.mirror.example.com/", "library/hello-world", true},
+		{"https://mirror.example.com", "", false},
+		{"https://registry-1.docker.io", "", false},
+	}
+	for i, c := range cases {
+		allowed, err := Allowed(rules, c.endpoint, c.repository)
+		require.Nil(t, err)
+		assert.Equal(t, c.allowed, allowed, "case %d", i)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer func(f func() (string, error)) {
+		getAllowlist = f
+	}(getAllowlist)
+
+	allowlist := ""
+	getAllowlist = func() (string, error) {
+		return allowlist, nil
+	}
+	assert.Nil(t, Check("https://registry-1.docker.io", "library/hello-world"))
+
+	allowlist = "registry.example.com/dr"
+	assert.Nil(t, Check("https://registry.example.com", "dr/hello-world"))
+	err := Check("https://registry-1.docker.io", "library/hello-world")
+	require.NotNil(t, err)
+	_, ok := err.(*DeniedError)
+	assert.True(t, ok)
+
+	allowlist = "[registry.example.com"
+	err = Check("https://registry.example.com", "")
+	require.NotNil(t, err)
+	_, ok = err.(*DeniedError)
+	assert.False(t, ok)
+}
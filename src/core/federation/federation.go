@@ -0,0 +1,144 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package federation fans the global search out to the peer Harbor instances registered
+// with the local one, so the organizations running regional registries can search them all.
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// SearchPath is the path of the global search API of the peers, the requests to it aren't
+// federated so the search never loops between the peers
+const SearchPath = "/api/search"
+
+// Peers returns all the peer instances with the decrypted passwords
+func Peers() ([]*models.FederationPeer, error) {
+	peers, err := dao.ListFederationPeers()
+	if err != nil {
+		return nil, err
+	}
+	if len(peers) == 0 {
+		return peers, nil
+	}
+	key, err := config.SecretKey()
+	if err != nil {
+		return nil, err
+	}
+	for _, peer := range peers {
+		if len(peer.Password) == 0 {
+			continue
+		}
+		if peer.Password, err = keyring.Decrypt(peer.Password, key); err != nil {
+			return nil, fmt.Errorf("failed to decrypt the password of peer %s: %v", peer.Name, err)
+		}
+	}
+	return peers, nil
+}
+
+// Search searches the keyword on the peers concurrently and merges the repositories found, each
+// of them is tagged with the name and the URL of the peer. A peer failing or timing out doesn't
+// fail the search, the error is reported in its status instead
+func Search(peers []*models.FederationPeer, keyword string, limit int64) ([]map[string]interface{}, []*models.PeerSearchStatus) {
+	results := make([][]map[string]interface{}, len(peers))
+	statuses := make([]*models.PeerSearchStatus, len(peers))
+	wg := &sync.WaitGroup{}
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(i int, peer *models.FederationPeer) {
+			defer wg.Done()
+			statuses[i] = &models.PeerSearchStatus{
+				Name: peer.Name,
+			}
+			repositories, err := search(peer, keyword, limit)
+			if err != nil {
+				statuses[i].Error = err.Error()
+				return
+			}
+			for _, repository := range repositories {
+				repository["peer"] = peer.Name
+				repository["peer_url"] = peer.URL
+			}
+			results[i] = repositories
+			statuses[i].Repository = int64(len(repositories))
+		}(i, peer)
+	}
+	wg.Wait()
+
+	merged := []map[string]interface{}{}
+	for _, repositories := range results {
+		merged = append(merged, repositories...)
+	}
+	return merged, statuses
+}
+
+func search(peer *models.FederationPeer, keyword string, limit int64) ([]map[string]interface{}, error) {
+	query := url.Values{}
+	query.Set("q", keyword)
+	if limit > 0 {
+		query.Set("limit", strconv.FormatInt(limit, 10))
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(peer.URL, "/")+SearchPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(peer.Username) > 0 {
+		req.SetBasicAuth(peer.Username, peer.Password)
+	}
+	timeout := peer.Timeout
+	if timeout <= 0 {
+		timeout = models.DefaultFederationTimeout
+	}
+	client := &http.Client{
+		Transport: registry.GetHTTPTransport(peer.Insecure),
+		Timeout:   time.Duration(timeout) * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from peer %s: %s", resp.StatusCode, peer.Name, string(data))
+	}
+	result := &struct {
+		Repository []map[string]interface{} `json:"repository"`
+	}{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("invalid search result from peer %s: %v", peer.Name, err)
+	}
+	if result.Repository == nil {
+		result.Repository = []map[string]interface{}{}
+	}
+	return result.Repository, nil
+}
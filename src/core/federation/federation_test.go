@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "searcher" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != SearchPath || r.URL.Query().Get("q") != "nginx" || r.URL.Query().Get("limit") != "5" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"project": [], "repository": [
+			{"repository_name": "library/nginx", "project_name": "library", "tags_count": 3}]}`))
+	}))
+	defer server.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+	}))
+	defer slow.Close()
+
+	peers := []*models.FederationPeer{
+		{
+			Name:     "us-east",
+			URL:      server.URL + "/",
+			Username: "searcher",
+			Password: "secret",
+		},
+		{
+			Name: "unauthorized",
+			URL:  server.URL,
+		},
+		{
+			Name:    "slow",
+			URL:     slow.URL,
+			Timeout: 1,
+		},
+	}
+	repositories, statuses := Search(peers, "nginx", 5)
+	require.Equal(t, 1, len(repositories))
+	assert.Equal(t, "library/nginx", repositories[0]["repository_name"])
+	assert.Equal(t, "us-east", repositories[0]["peer"])
+	assert.Equal(t, server.URL+"/", repositories[0]["peer_url"])
+
+	require.Equal(t, 3, len(statuses))
+	assert.Equal(t, "us-east", statuses[0].Name)
+	assert.Equal(t, int64(1), statuses[0].Repository)
+	assert.Empty(t, statuses[0].Error)
+	assert.Contains(t, statuses[1].Error, "401")
+	assert.NotEmpty(t, statuses[2].Error)
+
+	repositories, statuses = Search(nil, "nginx", 0)
+	assert.Equal(t, 0, len(repositories))
+	assert.Equal(t, 0, len(statuses))
+}
@@ -0,0 +1,117 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/astaxie/beego/context"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+var (
+	chartUploadRe = regexp.MustCompile(`^/api/chartrepo/(?:[^/]+/)?(?:charts|prov)$`)
+	logQueryRe    = regexp.MustCompile(`^/api/(?:projects/[0-9]+/)?logs$`)
+)
+
+// BodyLimitFilter rejects the requests exceeding the configured max sizes with 413, it must be
+// inserted before the static files as beego parses the multipart forms before routing.
+// The query strings of log queries exceeding the limit are rejected with 414
+func BodyLimitFilter(ctx *context.Context) {
+	limitBody(ctx.Request, ctx.ResponseWriter)
+}
+
+func limitBody(req *http.Request, resp http.ResponseWriter) {
+	if !strings.HasPrefix(req.URL.Path, "/api/") {
+		return
+	}
+	limits, err := config.RequestSizeLimits()
+	if err != nil {
+		log.Errorf("failed to get the limits of request size: %v", err)
+		return
+	}
+
+	switch {
+	case req.Method == http.MethodGet && logQueryRe.MatchString(req.URL.Path):
+		if limits.LogQuery > 0 && int64(len(req.URL.RawQuery)) > limits.LogQuery {
+			http.Error(resp, i18n.T(locale(req), i18n.MsgLogQueryTooLong, limits.LogQuery),
+				http.StatusRequestURITooLong)
+		}
+	case req.Method == http.MethodPost && chartUploadRe.MatchString(req.URL.Path):
+		if limits.ChartUpload <= 0 {
+			return
+		}
+		if req.ContentLength > limits.ChartUpload {
+			tooLarge(req, resp, i18n.MsgChartTooLarge, limits.ChartUpload)
+			return
+		}
+		// the chart is parsed from the multipart form, the one without content length is cut off
+		req.Body = http.MaxBytesReader(resp, req.Body, limits.ChartUpload)
+	case isJSONRequest(req):
+		if limits.JSONBody <= 0 {
+			return
+		}
+		if req.ContentLength > limits.JSONBody {
+			tooLarge(req, resp, i18n.MsgRequestBodyTooLarge, limits.JSONBody)
+			return
+		}
+		if req.ContentLength >= 0 {
+			req.Body = http.MaxBytesReader(resp, req.Body, limits.JSONBody)
+			return
+		}
+		// the JSON body is read into memory by the API anyway, read it here for the one
+		// without content length to tell whether it exceeds the limit
+		data, err := ioutil.ReadAll(io.LimitReader(req.Body, limits.JSONBody+1))
+		if err != nil {
+			http.Error(resp, fmt.Sprintf("failed to read the request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if int64(len(data)) > limits.JSONBody {
+			tooLarge(req, resp, i18n.MsgRequestBodyTooLarge, limits.JSONBody)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+	}
+}
+
+// isJSONRequest returns whether the request carries a JSON body, the multipart forms and
+// the binary uploads, e.g. the bundles, have their own limits
+func isJSONRequest(req *http.Request) bool {
+	if req.Method != http.MethodPost && req.Method != http.MethodPut &&
+		req.Method != http.MethodPatch && req.Method != http.MethodDelete {
+		return false
+	}
+	contentType := req.Header.Get("Content-Type")
+	return !strings.HasPrefix(contentType, "multipart/form-data") &&
+		!strings.HasPrefix(contentType, "application/octet-stream")
+}
+
+func tooLarge(req *http.Request, resp http.ResponseWriter, msgID string, limit int64) {
+	http.Error(resp, i18n.T(locale(req), msgID, limit), http.StatusRequestEntityTooLarge)
+}
+
+// locale returns the locale negotiated from the "Accept-Language" header of the request
+func locale(req *http.Request) string {
+	return i18n.Negotiate(req.Header.Get("Accept-Language"))
+}
@@ -0,0 +1,117 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	utilstest "github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimitFilter(t *testing.T) {
+	adminServer, err := utilstest.NewAdminserver(map[string]interface{}{
+		common.ExtEndpoint:        "host01.com",
+		common.AUTHMode:           "db_auth",
+		common.CfgExpiration:      5,
+		common.TokenExpiration:    30,
+		common.MaxJSONBodySize:    1,
+		common.MaxChartUploadSize: 2,
+		common.MaxLogQuerySize:    1,
+	})
+	require.Nil(t, err)
+	defer adminServer.Close()
+	require.Nil(t, os.Setenv("ADMINSERVER_URL", adminServer.URL))
+	require.Nil(t, config.Init())
+
+	small := strings.Repeat("a", 1024)
+	large := strings.Repeat("a", 1025)
+
+	// JSON body
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1/api/projects", strings.NewReader(small))
+	rec := httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1/api/projects", strings.NewReader(large))
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, "The request body exceeds the max size 1024 bytes.\n", rec.Body.String())
+
+	// the message is translated by the "Accept-Language" header
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1/api/projects", strings.NewReader(large))
+	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, "请求体超过了最大限制 1024 字节。\n", rec.Body.String())
+
+	// JSON body without content length
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1/api/projects/1", ioutil.NopCloser(strings.NewReader(small)))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	data, err := ioutil.ReadAll(req.Body)
+	require.Nil(t, err)
+	assert.Equal(t, small, string(data))
+
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1/api/projects/1", ioutil.NopCloser(strings.NewReader(large)))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// chart upload, the JSON limit doesn't apply
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1/api/chartrepo/library/charts", bytes.NewReader(make([]byte, 2048)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=abc")
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1/api/chartrepo/charts", bytes.NewReader(make([]byte, 2049)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=abc")
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// other binary uploads aren't limited
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1/api/bundles/import", bytes.NewReader(make([]byte, 4096)))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// log query
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1/api/logs?"+strings.Repeat("operation=push&", 10), nil)
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1/api/projects/1/logs?"+strings.Repeat("operation=push&", 100), nil)
+	rec = httptest.NewRecorder()
+	limitBody(req, rec)
+	assert.Equal(t, http.StatusRequestURITooLong, rec.Code)
+}
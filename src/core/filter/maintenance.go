@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego/context"
+	"github.com/goharbor/harbor/src/common/i18n"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the API to switch off the maintenance mode
+const maintenanceURL = "/api/system/maintenance"
+
+// MaintenanceFilter rejects the modifications via API with 503 when Harbor is in maintenance mode,
+// the system admins and the solution users are exempted so that the maintenance can be done.
+// The pushes to the registry are drained by the proxy
+func MaintenanceFilter(ctx *context.Context) {
+	maintain(ctx.Request, ctx.ResponseWriter)
+}
+
+func maintain(req *http.Request, resp http.ResponseWriter) {
+	if !strings.HasPrefix(req.URL.Path, "/api/") || req.URL.Path == maintenanceURL {
+		return
+	}
+	if req.Method != http.MethodPost && req.Method != http.MethodPut &&
+		req.Method != http.MethodPatch && req.Method != http.MethodDelete {
+		return
+	}
+
+	maintenance, err := config.Maintenance()
+	if err != nil {
+		log.Errorf("failed to get the state of maintenance mode: %v", err)
+		return
+	}
+	if !maintenance.Enabled {
+		return
+	}
+	if secCtx, err := GetSecurityContext(req); err == nil && (secCtx.IsSysAdmin() || secCtx.IsSolutionUser()) {
+		return
+	}
+
+	if maintenance.RetryAfter > 0 {
+		resp.Header().Set("Retry-After", strconv.Itoa(maintenance.RetryAfter))
+	}
+	message := maintenance.Message
+	if len(message) == 0 {
+		message = i18n.T(locale(req), i18n.MsgMaintenance)
+	}
+	resp.WriteHeader(http.StatusServiceUnavailable)
+	if _, err = resp.Write([]byte(message)); err != nil {
+		log.Errorf("failed to write response body: %v", err)
+	}
+}
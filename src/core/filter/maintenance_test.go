@@ -0,0 +1,88 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/security/local"
+	utilstest "github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceFilter(t *testing.T) {
+	adminServer, err := utilstest.NewAdminserver(map[string]interface{}{
+		common.ExtEndpoint:     "host01.com",
+		common.AUTHMode:        "db_auth",
+		common.CfgExpiration:   5,
+		common.TokenExpiration: 30,
+		common.Maintenance:     `{"enabled":true,"message":"upgrading","retry_after":600}`,
+	})
+	require.Nil(t, err)
+	defer adminServer.Close()
+	require.Nil(t, os.Setenv("ADMINSERVER_URL", adminServer.URL))
+	require.Nil(t, config.Init())
+
+	// the reads are allowed
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1/api/projects", nil)
+	rec := httptest.NewRecorder()
+	maintain(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the maintenance mode can be switched off
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1/api/system/maintenance", nil)
+	rec = httptest.NewRecorder()
+	maintain(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the pushes are drained by the proxy
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1/v2/library/hello-world/manifests/latest", nil)
+	rec = httptest.NewRecorder()
+	maintain(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the modifications are rejected
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1/api/projects", nil)
+	rec = httptest.NewRecorder()
+	maintain(req, rec)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "600", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "upgrading", rec.Body.String())
+
+	// the system admins are exempted
+	req, _ = http.NewRequest(http.MethodDelete, "http://127.0.0.1/api/repositories/library/hello-world", nil)
+	addToReqContext(req, SecurCtxKey, local.NewSecurityContext(&models.User{
+		Username:     "admin",
+		HasAdminRole: true,
+	}, nil))
+	rec = httptest.NewRecorder()
+	maintain(req, rec)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "http://127.0.0.1/api/repositories/library/hello-world", nil)
+	addToReqContext(req, SecurCtxKey, local.NewSecurityContext(&models.User{
+		Username: "user",
+	}, nil))
+	rec = httptest.NewRecorder()
+	maintain(req, rec)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
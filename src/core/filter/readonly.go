@@ -19,15 +19,17 @@ import (
 	"regexp"
 
 	"github.com/astaxie/beego/context"
+	"github.com/goharbor/harbor/src/common/i18n"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
 )
 
 const (
-	repoURL  = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)(?:[a-z0-9]+(?:[._-][a-z0-9]+)*)$`
-	tagsURL  = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags$`
-	tagURL   = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags/([\w][\w.-]{0,127})$`
-	labelURL = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags/([\w][\w.-]{0,127})/labels/[0-9]+$`
+	repoURL   = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)(?:[a-z0-9]+(?:[._-][a-z0-9]+)*)$`
+	tagsURL   = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags$`
+	tagURL    = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags/([\w][\w.-]{0,127})$`
+	labelURL  = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags/([\w][\w.-]{0,127})/labels/[0-9]+$`
+	renameURL = `^/api/repositories/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)(?:[a-z0-9]+(?:[._-][a-z0-9]+)*)/rename$`
 )
 
 // ReadonlyFilter filters the deletion or creation (e.g. retag) of repo/tag requests and returns 503.
@@ -40,9 +42,9 @@ func filter(req *http.Request, resp http.ResponseWriter) {
 		return
 	}
 
-	if matchRepoTagDelete(req) || matchRetag(req) {
+	if matchRepoTagDelete(req) || matchRetag(req) || matchRename(req) {
 		resp.WriteHeader(http.StatusServiceUnavailable)
-		_, err := resp.Write([]byte("The system is in read only mode. Any modification is prohibited."))
+		_, err := resp.Write([]byte(i18n.T(locale(req), i18n.MsgReadOnly)))
 		if err != nil {
 			log.Errorf("failed to write response body: %v", err)
 		}
@@ -85,6 +87,16 @@ func matchRetag(req *http.Request) bool {
 	return re.MatchString(req.URL.Path)
 }
 
+// matchRename checks whether a request is a repository rename request, it should be blocked in read-only mode.
+func matchRename(req *http.Request) bool {
+	if req.Method != http.MethodPut {
+		return false
+	}
+
+	re := regexp.MustCompile(renameURL)
+	return re.MatchString(req.URL.Path)
+}
+
 func inWhiteList(req *http.Request) bool {
 	re := regexp.MustCompile(labelURL)
 	s := re.FindStringSubmatch(req.URL.Path)
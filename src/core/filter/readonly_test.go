@@ -98,3 +98,17 @@ func TestMatchRetag(t *testing.T) {
 	req4, _ := http.NewRequest("POST", "http://127.0.0.1:5000/api/repositories/library/hello-world", nil)
 	assert.False(t, matchRetag(req4))
 }
+
+func TestMatchRename(t *testing.T) {
+	req1, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/api/repositories/library/hello-world/rename", nil)
+	assert.True(t, matchRename(req1))
+
+	req2, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/api/repositories/library/vmware/hello-world/rename", nil)
+	assert.True(t, matchRename(req2))
+
+	req3, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/api/repositories/library/hello-world", nil)
+	assert.False(t, matchRename(req3))
+
+	req4, _ := http.NewRequest("GET", "http://127.0.0.1:5000/api/repositories/library/hello-world/rename", nil)
+	assert.False(t, matchRename(req4))
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/goharbor/harbor/src/common/security/local"
 	robotCtx "github.com/goharbor/harbor/src/common/security/robot"
 	"github.com/goharbor/harbor/src/common/security/secret"
+	shareCtx "github.com/goharbor/harbor/src/common/security/share"
+	uiTokenCtx "github.com/goharbor/harbor/src/common/security/uitoken"
 	"github.com/goharbor/harbor/src/common/token"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/auth"
@@ -39,6 +41,7 @@ import (
 	"github.com/goharbor/harbor/src/core/promgr"
 	"github.com/goharbor/harbor/src/core/promgr/pmsdriver/admiral"
 	"strings"
+	"time"
 )
 
 // ContextValueKey for content value
@@ -83,6 +86,25 @@ var (
 			method: http.MethodDelete,
 		},
 	}
+	// the APIs which the UI tokens of each audience can call
+	uiTokenReqPatterns = map[string][]*pathMethod{
+		token.AudienceChartViewer: {
+			{
+				path:   "^/api/chartrepo/[^/]+/charts(/.*)?$",
+				method: http.MethodGet,
+			},
+			{
+				path:   "^/chartrepo/[^/]+/(index\\.yaml|charts/[^/]+)$",
+				method: http.MethodGet,
+			},
+		},
+		token.AudienceLogViewer: {
+			{
+				path:   "^/api/projects/[0-9]+/logs$",
+				method: http.MethodGet,
+			},
+		},
+	}
 )
 
 // Init ReqCtxMofiers list
@@ -101,6 +123,8 @@ func Init() {
 	reqCtxModifiers = []ReqCtxModifier{
 		&secretReqCtxModifier{config.SecretStore},
 		&robotAuthReqCtxModifier{},
+		&shareAuthReqCtxModifier{},
+		&uiTokenReqCtxModifier{},
 		&basicAuthReqCtxModifier{},
 		&sessionReqCtxModifier{},
 		&unauthorizedReqCtxModifier{}}
@@ -189,11 +213,151 @@ func (r *robotAuthReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
 	}
 	log.Debug("creating robot account security context...")
 	pm := config.GlobalProjectMgr
-	securCtx := robotCtx.NewSecurityContext(robot, pm, htk.Claims.(*token.RobotClaims).Access)
+	access := rClaims.Access
+	if rClaims.ProjectID != robot.ProjectID {
+		// the robot has been moved to another project by merging
+		access = robotCtx.MovePolicies(access, rClaims.ProjectID, robot.ProjectID)
+	}
+	// the access granted to the robot may be downgraded after the token is issued, it's
+	// unknown for the robots created before the access is recorded
+	granted, err := robot.GetAccess()
+	if err != nil {
+		log.Errorf("failed to get the access of robot %s: %v", robot.Name, err)
+		return false
+	}
+	if granted != nil {
+		access = robotCtx.RestrictPolicies(access, granted)
+	}
+	securCtx := robotCtx.NewSecurityContext(robot, pm, access)
 	setSecurCtxAndPM(ctx.Request, securCtx, pm)
 	return true
 }
 
+// shareAuthReqCtxModifier authenticates the share links, which are only used to get
+// the registry token pulling the shared image
+type shareAuthReqCtxModifier struct{}
+
+func (s *shareAuthReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
+	username, shareTk, ok := ctx.Request.BasicAuth()
+	if !ok {
+		return false
+	}
+	id, ok := models.ParseShareLinkUsername(username)
+	if !ok {
+		return false
+	}
+	if ctx.Request.URL.Path != "/service/token" {
+		log.Debugf("the share link %s can only be used to get the registry token", username)
+		return false
+	}
+	sClaims := &token.ShareClaims{}
+	if _, err := token.ParseWithClaims(shareTk, sClaims); err != nil {
+		log.Errorf("failed to decrypt share token, %v", err)
+		return false
+	}
+	if sClaims.ShareID != id {
+		log.Errorf("failed to authenticate : %v", username)
+		return false
+	}
+	link, err := dao.GetShareLink(id)
+	if err != nil {
+		log.Errorf("failed to get share link %s: %v", username, err)
+		return false
+	}
+	if link == nil {
+		log.Error("the share link provided doesn't exist.")
+		return false
+	}
+	if !link.Active(time.Now()) {
+		log.Errorf("the share link %s is revoked or expired", username)
+		return false
+	}
+	log.Debug("creating share link security context...")
+	pm := config.GlobalProjectMgr
+	securCtx := shareCtx.NewSecurityContext(link)
+	setSecurCtxAndPM(ctx.Request, securCtx, pm)
+	return true
+}
+
+// uiTokenReqCtxModifier authenticates the UI tokens carried as the bearer tokens, which are
+// minted from the session for the tools embedded in the web UI. The token can only be used
+// to call the APIs allowed for its audience
+type uiTokenReqCtxModifier struct{}
+
+func (u *uiTokenReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
+	auth := strings.SplitN(ctx.Request.Header.Get("Authorization"), " ", 2)
+	if len(auth) != 2 || !strings.EqualFold(auth[0], "Bearer") {
+		return false
+	}
+	// the bearer tokens of the other APIs, e.g. the ones of registry, are not UI tokens
+	audiences := uiTokenAudiences(ctx.Request)
+	if len(audiences) == 0 {
+		return false
+	}
+	uClaims := &token.UIClaims{}
+	if _, err := token.ParseWithClaims(auth[1], uClaims); err != nil {
+		log.Errorf("failed to decrypt UI token, %v", err)
+		return false
+	}
+	allowed := false
+	for _, audience := range audiences {
+		if uClaims.Audience == audience {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		log.Errorf("the UI token of audience %s can not be used to %s %s", uClaims.Audience,
+			ctx.Request.Method, ctx.Request.URL.Path)
+		return false
+	}
+	user, err := dao.GetUser(models.User{UserID: uClaims.UserID})
+	if err != nil {
+		log.Errorf("failed to get user %d: %v", uClaims.UserID, err)
+		return false
+	}
+	if user == nil {
+		log.Errorf("the user %d of the UI token doesn't exist", uClaims.UserID)
+		return false
+	}
+	pm := config.GlobalProjectMgr
+	project, err := pm.Get(uClaims.ProjectID)
+	if err != nil {
+		log.Errorf("failed to get project %d: %v", uClaims.ProjectID, err)
+		return false
+	}
+	if project == nil {
+		log.Errorf("the project %d of the UI token doesn't exist", uClaims.ProjectID)
+		return false
+	}
+	log.Debug("creating UI token security context...")
+	securCtx := uiTokenCtx.NewSecurityContext(local.NewSecurityContext(user, pm), project, uClaims.Audience)
+	setSecurCtxAndPM(ctx.Request, securCtx, pm)
+	return true
+}
+
+// uiTokenAudiences returns the audiences of the UI tokens which can be used to send the request
+func uiTokenAudiences(req *http.Request) []string {
+	audiences := []string{}
+	for audience, patterns := range uiTokenReqPatterns {
+		for _, pattern := range patterns {
+			if pattern.method != req.Method {
+				continue
+			}
+			match, err := regexp.MatchString(pattern.path, req.URL.Path)
+			if err != nil {
+				log.Errorf("failed to match %s with pattern %s", req.URL.Path, pattern.path)
+				continue
+			}
+			if match {
+				audiences = append(audiences, audience)
+				break
+			}
+		}
+	}
+	return audiences
+}
+
 type basicAuthReqCtxModifier struct{}
 
 func (b *basicAuthReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
@@ -34,6 +34,7 @@ import (
 	"github.com/goharbor/harbor/src/common/security"
 	"github.com/goharbor/harbor/src/common/security/local"
 	"github.com/goharbor/harbor/src/common/security/secret"
+	"github.com/goharbor/harbor/src/common/token"
 	_ "github.com/goharbor/harbor/src/core/auth/db"
 	_ "github.com/goharbor/harbor/src/core/auth/ldap"
 	"github.com/goharbor/harbor/src/core/config"
@@ -139,6 +140,71 @@ func TestRobotReqCtxModifier(t *testing.T) {
 	assert.False(t, modified)
 }
 
+func TestShareReqCtxModifier(t *testing.T) {
+	modifier := &shareAuthReqCtxModifier{}
+	for _, c := range []struct {
+		url      string
+		username string
+	}{
+		// not a share link
+		{"http://127.0.0.1/service/token", "admin"},
+		// only the token service
+		{"http://127.0.0.1/api/projects/", "share$1"},
+		// invalid token
+		{"http://127.0.0.1/service/token", "share$1"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, c.url, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", req)
+		}
+		req.SetBasicAuth(c.username, "Harbor12345")
+		ctx, err := newContext(req)
+		if err != nil {
+			t.Fatalf("failed to crate context: %v", err)
+		}
+		assert.False(t, modifier.Modify(ctx))
+	}
+}
+
+func TestUITokenReqCtxModifier(t *testing.T) {
+	modifier := &uiTokenReqCtxModifier{}
+	for _, c := range []struct {
+		url           string
+		authorization string
+	}{
+		// not a bearer token
+		{"http://127.0.0.1/api/projects/1/logs", "Basic YWRtaW46SGFyYm9yMTIzNDU="},
+		// the API isn't allowed for any audience
+		{"http://127.0.0.1/api/projects/1/members", "Bearer token"},
+		// invalid token
+		{"http://127.0.0.1/api/projects/1/logs", "Bearer token"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, c.url, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", req)
+		}
+		req.Header.Set("Authorization", c.authorization)
+		ctx, err := newContext(req)
+		if err != nil {
+			t.Fatalf("failed to crate context: %v", err)
+		}
+		assert.False(t, modifier.Modify(ctx))
+	}
+}
+
+func TestUITokenAudiences(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1/api/projects/1/logs", nil)
+	assert.Equal(t, []string{token.AudienceLogViewer}, uiTokenAudiences(req))
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1/api/chartrepo/library/charts/app/1.0", nil)
+	assert.Equal(t, []string{token.AudienceChartViewer}, uiTokenAudiences(req))
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1/chartrepo/library/index.yaml", nil)
+	assert.Equal(t, []string{token.AudienceChartViewer}, uiTokenAudiences(req))
+	req, _ = http.NewRequest(http.MethodDelete, "http://127.0.0.1/api/chartrepo/library/charts/app", nil)
+	assert.Equal(t, 0, len(uiTokenAudiences(req)))
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1/api/projects/1/members", nil)
+	assert.Equal(t, 0, len(uiTokenAudiences(req)))
+}
+
 func TestBasicAuthReqCtxModifier(t *testing.T) {
 	req, err := http.NewRequest(http.MethodGet,
 		"http://127.0.0.1/api/projects/", nil)
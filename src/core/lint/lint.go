@@ -0,0 +1,87 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks the pushed images against the best practices configured in the lint policy
+// of the project, e.g. the count of the layers, the labels and the user of the images.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// Image is the pushed image to be linted
+type Image struct {
+	Tag string
+	// the count of the layers in the manifest
+	Layers int
+	// the user and the labels read from the config of the image
+	User   string
+	Labels map[string]string
+	// the existing tags of the repository, excluding the pushed one
+	OtherTags []string
+}
+
+// Lint returns the findings of the image violating the rules of the policy
+func Lint(policy *models.LintPolicy, image *Image) []*models.LintFinding {
+	findings := []*models.LintFinding{}
+	if policy == nil {
+		return findings
+	}
+	if policy.MaxLayers > 0 && image.Layers > policy.MaxLayers {
+		findings = append(findings, &models.LintFinding{
+			Rule:    models.LintRuleLayerCount,
+			Message: fmt.Sprintf("the image has %d layers, more than the max count %d", image.Layers, policy.MaxLayers),
+		})
+	}
+	for _, label := range policy.RequiredLabels {
+		if _, exist := image.Labels[label]; !exist {
+			findings = append(findings, &models.LintFinding{
+				Rule:    models.LintRuleMissingLabel,
+				Message: fmt.Sprintf("the required label %s is missing", label),
+			})
+		}
+	}
+	if policy.ForbidLatestOnly && image.Tag == "latest" && len(image.OtherTags) == 0 {
+		findings = append(findings, &models.LintFinding{
+			Rule:    models.LintRuleLatestOnly,
+			Message: "the image is tagged only as latest",
+		})
+	}
+	if policy.ForbidRootUser && isRoot(image.User) {
+		findings = append(findings, &models.LintFinding{
+			Rule:    models.LintRuleRootUser,
+			Message: "the image runs as root",
+		})
+	}
+	return findings
+}
+
+// isRoot returns whether the user in the config of the image is root, the user
+// can be in the form of "user", "uid", "user:group" or "uid:gid"
+func isRoot(user string) bool {
+	name := strings.SplitN(user, ":", 2)[0]
+	return name == "" || name == "root" || name == "0"
+}
+
+// Summary returns the findings in one line
+func Summary(findings []*models.LintFinding) string {
+	messages := []string{}
+	for _, finding := range findings {
+		messages = append(messages, finding.Message)
+	}
+	return strings.Join(messages, "; ")
+}
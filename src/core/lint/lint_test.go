@@ -0,0 +1,86 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	image := &Image{
+		Tag:    "latest",
+		Layers: 12,
+		User:   "0:0",
+		Labels: map[string]string{
+			"maintainer": "admin@example.com",
+		},
+	}
+	// no policy
+	assert.Equal(t, 0, len(Lint(nil, image)))
+
+	policy := &models.LintPolicy{
+		MaxLayers:        10,
+		RequiredLabels:   []string{"maintainer", "org.opencontainers.image.source"},
+		ForbidLatestOnly: true,
+		ForbidRootUser:   true,
+	}
+	findings := Lint(policy, image)
+	require.Equal(t, 4, len(findings))
+	assert.Equal(t, models.LintRuleLayerCount, findings[0].Rule)
+	assert.Equal(t, models.LintRuleMissingLabel, findings[1].Rule)
+	assert.Equal(t, "the required label org.opencontainers.image.source is missing", findings[1].Message)
+	assert.Equal(t, models.LintRuleLatestOnly, findings[2].Rule)
+	assert.Equal(t, models.LintRuleRootUser, findings[3].Rule)
+
+	image = &Image{
+		Tag:       "latest",
+		Layers:    10,
+		User:      "harbor:harbor",
+		OtherTags: []string{"v1"},
+		Labels: map[string]string{
+			"maintainer":                      "admin@example.com",
+			"org.opencontainers.image.source": "https://github.com/goharbor/harbor",
+		},
+	}
+	assert.Equal(t, 0, len(Lint(policy, image)))
+}
+
+func TestIsRoot(t *testing.T) {
+	cases := map[string]bool{
+		"":            true,
+		"root":        true,
+		"0":           true,
+		"root:harbor": true,
+		"0:1000":      true,
+		"1000":        false,
+		"harbor":      false,
+		"1000:0":      false,
+	}
+	for user, root := range cases {
+		assert.Equal(t, root, isRoot(user), user)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "", Summary(nil))
+	assert.Equal(t, "a; b", Summary([]*models.LintFinding{
+		{Message: "a"},
+		{Message: "b"},
+	}))
+}
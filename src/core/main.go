@@ -24,19 +24,40 @@ import (
 	_ "github.com/astaxie/beego/session/redis"
 
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/i18n"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/rbac/external"
+	htoken "github.com/goharbor/harbor/src/common/token"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/redis"
+	"github.com/goharbor/harbor/src/core/accesslog"
 	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/bundle"
 	_ "github.com/goharbor/harbor/src/core/auth/authproxy"
 	_ "github.com/goharbor/harbor/src/core/auth/db"
 	_ "github.com/goharbor/harbor/src/core/auth/ldap"
 	_ "github.com/goharbor/harbor/src/core/auth/uaa"
+	"github.com/goharbor/harbor/src/core/cache"
+	"github.com/goharbor/harbor/src/core/chargeback"
+	"github.com/goharbor/harbor/src/core/cleaner"
+	"github.com/goharbor/harbor/src/core/compliance"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/coretask"
 	"github.com/goharbor/harbor/src/core/filter"
+	"github.com/goharbor/harbor/src/core/metasync"
 	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/projectmerge"
 	"github.com/goharbor/harbor/src/core/proxy"
+	"github.com/goharbor/harbor/src/core/pulltime"
+	"github.com/goharbor/harbor/src/core/repoexport"
+	"github.com/goharbor/harbor/src/core/retention"
 	"github.com/goharbor/harbor/src/core/service/token"
+	"github.com/goharbor/harbor/src/core/traffic"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/goharbor/harbor/src/core/vulntrend"
 	"github.com/goharbor/harbor/src/replication/core"
 	_ "github.com/goharbor/harbor/src/replication/event"
 )
@@ -77,8 +98,15 @@ func main() {
 	// TODO
 	redisURL := os.Getenv("_REDIS_URL")
 	if len(redisURL) > 0 {
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			log.Fatalf("invalid redis URL: %v", err)
+		}
 		gob.Register(models.User{})
 		beego.BConfig.WebConfig.Session.SessionProvider = "redis"
+		if opts.Mode != redis.ModeStandalone {
+			beego.BConfig.WebConfig.Session.SessionProvider = redis.SessionProvider
+		}
 		beego.BConfig.WebConfig.Session.SessionProviderConfig = redisURL
 	}
 	beego.AddTemplateExt("htm")
@@ -88,6 +116,9 @@ func main() {
 		log.Fatalf("failed to initialize configurations: %v", err)
 	}
 	log.Info("configurations initialization completed")
+	if err := i18n.Init(); err != nil {
+		log.Fatalf("failed to load the message catalogs: %v", err)
+	}
 	token.InitCreators()
 	database, err := config.Database()
 	if err != nil {
@@ -96,6 +127,9 @@ func main() {
 	if err := dao.InitDatabase(database); err != nil {
 		log.Fatalf("failed to initialize database: %v", err)
 	}
+	if err := accesslog.Init(); err != nil {
+		log.Fatalf("failed to initialize the store of access logs: %v", err)
+	}
 
 	password, err := config.InitialAdminPassword()
 	if err != nil {
@@ -114,6 +148,38 @@ func main() {
 	if err = notifier.Subscribe(notifier.ScanAllPolicyTopic, &notifier.ScanPolicyNotificationHandler{}); err != nil {
 		log.Errorf("failed to subscribe scan all policy change topic: %v", err)
 	}
+	// Subscribe the email topic.
+	if err = notifier.Subscribe(notifier.EmailTopic, &notifier.EmailNotificationHandler{}); err != nil {
+		log.Errorf("failed to subscribe email topic: %v", err)
+	}
+	// Subscribe the approval topic.
+	if err = notifier.Subscribe(notifier.ApprovalTopic, &notifier.ApprovalWebhookHandler{}); err != nil {
+		log.Errorf("failed to subscribe approval topic: %v", err)
+	}
+	if err = notifier.Subscribe(notifier.QuotaTopic, &notifier.QuotaWebhookHandler{}); err != nil {
+		log.Errorf("failed to subscribe quota topic: %v", err)
+	}
+	if err = notifier.Subscribe(notifier.BlocklistTopic, &notifier.BlocklistWebhookHandler{}); err != nil {
+		log.Errorf("failed to subscribe blocklist topic: %v", err)
+	}
+	if err = notifier.Subscribe(notifier.ProjectTopic, &notifier.ProjectWebhookHandler{}); err != nil {
+		log.Errorf("failed to subscribe project topic: %v", err)
+	}
+	if err = notifier.Subscribe(notifier.UserNotificationTopic, &notifier.UserNotificationHandler{}); err != nil {
+		log.Errorf("failed to subscribe user notification topic: %v", err)
+	}
+	if err = notifier.Subscribe(notifier.DeprecationTopic, &notifier.DeprecationHitHandler{}); err != nil {
+		log.Errorf("failed to subscribe deprecation topic: %v", err)
+	}
+	if err = notifier.Subscribe(notifier.ProjectReportTopic, &notifier.ProjectReportScheduleHandler{}); err != nil {
+		log.Errorf("failed to subscribe project report topic: %v", err)
+	}
+	if err = notifier.ScheduleProjectReport(); err != nil {
+		log.Errorf("failed to schedule the project report job: %v", err)
+	}
+	if err = coreutils.ScheduleReconciliation(); err != nil {
+		log.Errorf("failed to schedule the reconciliation job: %v", err)
+	}
 
 	if config.WithClair() {
 		clairDB, err := config.ClairDB()
@@ -124,13 +190,42 @@ func main() {
 			log.Fatalf("failed to initialize clair database: %v", err)
 		}
 	}
+	// the imports interrupted by the restart of core can never be finished
+	if _, err := dao.FailRunningVulnDBImports("interrupted by the restart of core"); err != nil {
+		log.Errorf("failed to update the status of running vulnerability database imports: %v", err)
+	}
+
+	coretask.Register(projectmerge.TaskName, projectmerge.Runner)
+	coretask.Register(compliance.TaskName, compliance.Runner)
+	coretask.Register(chargeback.TaskName, chargeback.Runner)
+	coretask.Register(repoexport.TaskName, repoexport.Runner)
+	coretask.Register(bundle.TaskName, bundle.Runner)
+
+	cleaner.Register("expired project members", project.DeleteExpiredProjectMembers)
+	cleaner.Register("stale upload sessions", coreutils.PurgeExpiredUploadSessions)
+	cleaner.Register("expired repository redirects", dao.DeleteExpiredRepoRedirects)
+	cleaner.Register("expired confirmation tokens", dao.DeleteExpiredConfirmationTokens)
+	cleaner.Register("tags out of the retention policies", retention.Run)
+	cleaner.Start(cleaner.DefaultInterval)
+	pulltime.Start(pulltime.DefaultInterval)
+	traffic.Start(traffic.DefaultInterval)
+	chargeback.Start(chargeback.DefaultInterval)
+	metasync.Start(metasync.DefaultInterval)
+	vulntrend.Start(vulntrend.DefaultInterval)
+	htoken.WatchPrivateKey(htoken.DefaultKeyCheckInterval)
 
 	if err := core.Init(); err != nil {
 		log.Errorf("failed to initialize the replication controller: %v", err)
 	}
 
+	// the external authorizer is a no-op until the endpoint is configured
+	rbac.SetAuthorizer(external.NewWebhookAuthorizer(config.ExternalAuthzSettings))
+
 	filter.Init()
+	// the body is limited before it is parsed by beego
+	beego.InsertFilter("/api/*", beego.BeforeStatic, filter.BodyLimitFilter)
 	beego.InsertFilter("/*", beego.BeforeRouter, filter.SecurityFilter)
+	beego.InsertFilter("/*", beego.BeforeRouter, filter.MaintenanceFilter)
 	beego.InsertFilter("/*", beego.BeforeRouter, filter.ReadonlyFilter)
 	beego.InsertFilter("/api/*", beego.BeforeRouter, filter.MediaTypeFilter("application/json", "multipart/form-data", "application/octet-stream"))
 
@@ -151,8 +246,14 @@ func main() {
 		log.Infof("Because SYNC_REGISTRY set false , no need to sync registry \n")
 	}
 
+	if err := cache.Init(); err != nil {
+		log.Fatalf("failed to initialize cache: %v", err)
+	}
+
 	log.Info("Init proxy")
-	proxy.Init()
+	if err := proxy.Init(); err != nil {
+		log.Fatalf("failed to initialize the registry proxy: %v", err)
+	}
 	// go proxy.StartProxy()
 	beego.Run()
 }
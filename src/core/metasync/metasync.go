@@ -0,0 +1,361 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metasync
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/projectconfig"
+)
+
+const (
+	// DefaultInterval is the default interval between two syncs with the peer instance
+	DefaultInterval = 5 * time.Minute
+	// SecretHeader is the header carrying the shared secret when fetching the snapshot of the peer
+	SecretHeader = "Harbor-Metadata-Sync-Secret"
+	// SnapshotPath is the path of the API returning the snapshot of the instance
+	SnapshotPath = "/api/internal/metadata_sync/snapshot"
+)
+
+var (
+	// ErrNotConfigured is returned when the peer instance isn't configured
+	ErrNotConfigured = errors.New("the peer instance of the metadata sync isn't configured")
+	// ErrSyncRunning is returned when another sync is running
+	ErrSyncRunning = errors.New("the metadata sync is running")
+
+	lock    sync.Mutex
+	running bool
+	client  = &http.Client{
+		Timeout: 30 * time.Second,
+	}
+)
+
+// Snapshot is the configurations of all projects of an instance
+type Snapshot struct {
+	// URL is the external URL of the instance, it breaks the tie of the priorities
+	URL      string             `json:"url"`
+	Priority int                `json:"priority"`
+	Projects []*ProjectSnapshot `json:"projects"`
+}
+
+// ProjectSnapshot is the configuration of a project and its digest. The robots aren't
+// synced as their tokens are issued by each instance
+type ProjectSnapshot struct {
+	Config *projectconfig.Config `json:"config"`
+	Digest string                `json:"digest"`
+}
+
+// TakeSnapshot returns the snapshot of the local instance
+func TakeSnapshot() (*Snapshot, error) {
+	cfg, err := config.MetadataSync()
+	if err != nil {
+		return nil, err
+	}
+	url, err := config.ExtEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	result, err := config.GlobalProjectMgr.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	mgr := projectconfig.NewManager(config.GlobalProjectMgr.GetMetadataManager())
+	snapshot := &Snapshot{
+		URL:      url,
+		Priority: cfg.Priority,
+		Projects: []*ProjectSnapshot{},
+	}
+	for _, pro := range result.Projects {
+		c, err := mgr.Export(pro)
+		if err != nil {
+			return nil, err
+		}
+		p, err := newProjectSnapshot(c)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Projects = append(snapshot.Projects, p)
+	}
+	return snapshot, nil
+}
+
+// newProjectSnapshot normalizes the configuration and computes its digest, so the digests
+// of the same configuration on the two instances are equal
+func newProjectSnapshot(cfg *projectconfig.Config) (*ProjectSnapshot, error) {
+	cfg.Robots = nil
+	sort.Slice(cfg.Members, func(i, j int) bool {
+		if cfg.Members[i].Type != cfg.Members[j].Type {
+			return cfg.Members[i].Type < cfg.Members[j].Type
+		}
+		return cfg.Members[i].Name < cfg.Members[j].Name
+	})
+	for _, member := range cfg.Members {
+		if member.ExpirationTime != nil {
+			t := member.ExpirationTime.UTC().Truncate(time.Second)
+			member.ExpirationTime = &t
+		}
+	}
+	if cfg.Retention != nil {
+		sort.Slice(cfg.Retention.Repositories, func(i, j int) bool {
+			return cfg.Retention.Repositories[i].Repository < cfg.Retention.Repositories[j].Repository
+		})
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectSnapshot{
+		Config: cfg,
+		Digest: fmt.Sprintf("sha256:%x", sha256.Sum256(data)),
+	}, nil
+}
+
+// localWins returns whether the local instance wins the conflicts with the peer, the one
+// with the higher priority wins and the tie is broken by the external URLs
+func localWins(local, remote *Snapshot) bool {
+	if local.Priority != remote.Priority {
+		return local.Priority > remote.Priority
+	}
+	return local.URL < remote.URL
+}
+
+type action int
+
+const (
+	// nothing to do, the state is kept
+	actionNone action = iota
+	// the two instances agree on the configuration, the digest is recorded
+	actionRecord
+	// the configuration of the peer is applied to the existing project
+	actionApply
+	// the project of the peer is created locally
+	actionCreate
+	// the project is deleted on both instances, the state is removed
+	actionForget
+)
+
+// decision is what to do with a project in the sync, the message is recorded in the state
+type decision struct {
+	action  action
+	message string
+}
+
+// decide compares the digests of the project on the two instances with the one agreed in the
+// last sync, the empty digest means the project doesn't exist. Only the side changed since
+// the last sync is synced to the other one, the conflicts are resolved by the priorities and
+// the deletions aren't propagated
+func decide(local, remote, base string, localWins bool) decision {
+	switch {
+	case len(local) == 0 && len(remote) == 0:
+		return decision{action: actionForget}
+	case len(local) == 0 && len(base) == 0:
+		return decision{action: actionCreate}
+	case len(local) == 0:
+		return decision{action: actionNone, message: "the project is deleted locally, it isn't recreated"}
+	case len(remote) == 0 && len(base) > 0:
+		return decision{action: actionNone, message: "the project is deleted on the peer, it isn't deleted locally"}
+	case len(remote) == 0:
+		// the project is created locally and will be pulled by the peer
+		return decision{action: actionNone}
+	case local == remote:
+		return decision{action: actionRecord}
+	case local == base:
+		return decision{action: actionApply}
+	case remote == base:
+		// the project is changed locally and will be pulled by the peer
+		return decision{action: actionNone}
+	case localWins:
+		return decision{action: actionNone, message: "the project is changed on both instances, the local configuration wins"}
+	default:
+		return decision{action: actionApply, message: "the project is changed on both instances, the configuration of the peer wins"}
+	}
+}
+
+// Sync pulls the snapshot of the peer instance and syncs the configurations of the projects
+// changed on the peer to the local instance
+func Sync() error {
+	lock.Lock()
+	if running {
+		lock.Unlock()
+		return ErrSyncRunning
+	}
+	running = true
+	lock.Unlock()
+	defer func() {
+		lock.Lock()
+		running = false
+		lock.Unlock()
+	}()
+
+	cfg, err := config.MetadataSync()
+	if err != nil {
+		return err
+	}
+	if len(cfg.PeerURL) == 0 {
+		return ErrNotConfigured
+	}
+	remote, err := fetchSnapshot(cfg.PeerURL, cfg.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the snapshot of the peer: %v", err)
+	}
+	local, err := TakeSnapshot()
+	if err != nil {
+		return fmt.Errorf("failed to take the snapshot of the local instance: %v", err)
+	}
+	states, err := dao.ListMetadataSyncStates()
+	if err != nil {
+		return err
+	}
+
+	locals := map[string]*ProjectSnapshot{}
+	for _, p := range local.Projects {
+		locals[p.Config.Project] = p
+	}
+	remotes := map[string]*ProjectSnapshot{}
+	for _, p := range remote.Projects {
+		remotes[p.Config.Project] = p
+	}
+	bases := map[string]string{}
+	for _, state := range states {
+		bases[state.ProjectName] = state.Digest
+	}
+	set := map[string]struct{}{}
+	for name := range locals {
+		set[name] = struct{}{}
+	}
+	for name := range remotes {
+		set[name] = struct{}{}
+	}
+	for name := range bases {
+		set[name] = struct{}{}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	wins := localWins(local, remote)
+	for _, name := range names {
+		l, r := locals[name], remotes[name]
+		d := decide(digest(l), digest(r), bases[name], wins)
+		if err := execute(name, r, bases[name], d); err != nil {
+			log.Errorf("failed to sync the metadata of project %s: %v", name, err)
+			if err = dao.SetMetadataSyncState(name, bases[name], err.Error()); err != nil {
+				log.Errorf("failed to update the metadata sync state of project %s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func execute(name string, remote *ProjectSnapshot, base string, d decision) error {
+	switch d.action {
+	case actionForget:
+		return dao.DeleteMetadataSyncState(name)
+	case actionRecord:
+		return dao.SetMetadataSyncState(name, remote.Digest, d.message)
+	case actionNone:
+		return dao.SetMetadataSyncState(name, base, d.message)
+	case actionCreate:
+		// the project is owned by the admin as the owner on the peer may not exist locally
+		if _, err := config.GlobalProjectMgr.Create(&models.Project{
+			Name:    name,
+			OwnerID: 1,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := apply(name, remote.Config); err != nil {
+		return err
+	}
+	log.Infof("the metadata of project %s is synced from the peer", name)
+	return dao.SetMetadataSyncState(name, remote.Digest, d.message)
+}
+
+func apply(name string, cfg *projectconfig.Config) error {
+	pro, err := config.GlobalProjectMgr.Get(name)
+	if err != nil {
+		return err
+	}
+	if pro == nil {
+		return fmt.Errorf("project %s not found", name)
+	}
+	mgr := projectconfig.NewManager(config.GlobalProjectMgr.GetMetadataManager())
+	changes, err := mgr.Plan(pro, cfg)
+	if err != nil {
+		return err
+	}
+	_, err = mgr.Execute(pro, changes)
+	return err
+}
+
+func digest(p *ProjectSnapshot) string {
+	if p == nil {
+		return ""
+	}
+	return p.Digest
+}
+
+func fetchSnapshot(peerURL, secret string) (*Snapshot, error) {
+	req, err := http.NewRequest(http.MethodGet, peerURL+SnapshotPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(SecretHeader, secret)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(data))
+	}
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Start syncs with the peer instance every interval in background if the peer is configured
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := Sync(); err != nil && err != ErrNotConfigured && err != ErrSyncRunning {
+				log.Errorf("failed to sync the metadata with the peer: %v", err)
+			}
+		}
+	}()
+	log.Infof("the metadata sync started, interval: %v", interval)
+}
@@ -0,0 +1,116 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metasync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/core/projectconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProjectSnapshot(t *testing.T) {
+	expiration := time.Date(2019, 1, 1, 8, 0, 0, 1000, time.FixedZone("CST", 8*3600))
+	a, err := newProjectSnapshot(&projectconfig.Config{
+		Project:  "library",
+		Metadata: map[string]string{"public": "true"},
+		Members: []*projectconfig.Member{
+			{Name: "user-b", Type: projectconfig.MemberTypeUser, Role: "developer"},
+			{Name: "user-a", Type: projectconfig.MemberTypeUser, Role: "guest", ExpirationTime: &expiration},
+		},
+		Robots: []*projectconfig.Robot{{Name: "ci"}},
+	})
+	require.Nil(t, err)
+	assert.Nil(t, a.Config.Robots)
+	assert.Equal(t, "user-a", a.Config.Members[0].Name)
+
+	utc := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	b, err := newProjectSnapshot(&projectconfig.Config{
+		Project:  "library",
+		Metadata: map[string]string{"public": "true"},
+		Members: []*projectconfig.Member{
+			{Name: "user-a", Type: projectconfig.MemberTypeUser, Role: "guest", ExpirationTime: &utc},
+			{Name: "user-b", Type: projectconfig.MemberTypeUser, Role: "developer"},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, a.Digest, b.Digest)
+
+	c, err := newProjectSnapshot(&projectconfig.Config{
+		Project:  "library",
+		Metadata: map[string]string{"public": "false"},
+	})
+	require.Nil(t, err)
+	assert.NotEqual(t, a.Digest, c.Digest)
+}
+
+func TestLocalWins(t *testing.T) {
+	assert.True(t, localWins(&Snapshot{URL: "https://b", Priority: 1}, &Snapshot{URL: "https://a"}))
+	assert.False(t, localWins(&Snapshot{URL: "https://a"}, &Snapshot{URL: "https://b", Priority: 1}))
+	assert.True(t, localWins(&Snapshot{URL: "https://a"}, &Snapshot{URL: "https://b"}))
+	assert.False(t, localWins(&Snapshot{URL: "https://b"}, &Snapshot{URL: "https://a"}))
+}
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		local, remote, base string
+		localWins           bool
+		action              action
+		hasMessage          bool
+	}{
+		{"", "", "1", false, actionForget, false},
+		{"", "1", "", false, actionCreate, false},
+		{"", "1", "1", false, actionNone, true},
+		{"1", "", "1", false, actionNone, true},
+		{"1", "", "", false, actionNone, false},
+		{"1", "1", "", false, actionRecord, false},
+		{"1", "2", "1", true, actionApply, false},
+		{"2", "1", "1", false, actionNone, false},
+		{"2", "3", "1", true, actionNone, true},
+		{"2", "3", "1", false, actionApply, true},
+		{"2", "3", "", false, actionApply, true},
+	}
+	for _, c := range cases {
+		d := decide(c.local, c.remote, c.base, c.localWins)
+		assert.Equal(t, c.action, d.action, "local: %s, remote: %s, base: %s", c.local, c.remote, c.base)
+		assert.Equal(t, c.hasMessage, len(d.message) > 0, "local: %s, remote: %s, base: %s", c.local, c.remote, c.base)
+	}
+}
+
+func TestFetchSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SnapshotPath || r.Header.Get(SecretHeader) != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"url": "https://peer", "priority": 1, "projects": [
+			{"config": {"project": "library"}, "digest": "sha256:1"}]}`))
+	}))
+	defer server.Close()
+
+	snapshot, err := fetchSnapshot(server.URL, "secret")
+	require.Nil(t, err)
+	assert.Equal(t, "https://peer", snapshot.URL)
+	assert.Equal(t, 1, snapshot.Priority)
+	require.Equal(t, 1, len(snapshot.Projects))
+	assert.Equal(t, "library", snapshot.Projects[0].Config.Project)
+
+	_, err = fetchSnapshot(server.URL, "invalid")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the events of approvals
+const (
+	ApprovalEventSubmitted = "submitted"
+	ApprovalEventApproved  = "approved"
+	ApprovalEventRejected  = "rejected"
+)
+
+// ApprovalEvent is defined for passing the event of approval to post.
+type ApprovalEvent struct {
+	Event    string           `json:"event"`
+	Approval *models.Approval `json:"approval"`
+	OccurAt  time.Time        `json:"occur_at"`
+}
+
+// ApprovalWebhookHandler is defined to post the events of approvals to the
+// webhook URL configured in the system settings.
+type ApprovalWebhookHandler struct {
+	// returns the webhook URL, it's read from the configurations if nil
+	getURL func() (string, error)
+	// returns the version of the payload schema, it's read from the configurations if nil
+	getSchema func() (string, error)
+}
+
+// IsStateful to indicate this handler is stateless.
+func (a *ApprovalWebhookHandler) IsStateful() bool {
+	return false
+}
+
+// Handle posts the event of approval in JSON, nothing is posted if the webhook isn't configured.
+func (a *ApprovalWebhookHandler) Handle(value interface{}) error {
+	event, ok := value.(ApprovalEvent)
+	if !ok {
+		return errors.New("ApprovalWebhookHandler can not handle value with invalid type")
+	}
+
+	getURL := a.getURL
+	if getURL == nil {
+		getURL = config.ApprovalWebhookURL
+	}
+	url, err := getURL()
+	if err != nil {
+		return err
+	}
+	if len(url) == 0 {
+		return nil
+	}
+
+	getSchema := a.getSchema
+	if getSchema == nil {
+		getSchema = func() (string, error) {
+			return config.WebhookSchema(common.ApprovalWebhookSchema)
+		}
+	}
+	schema, err := getSchema()
+	if err != nil {
+		return err
+	}
+	return postWebhook(url, schema, "approval", event)
+}
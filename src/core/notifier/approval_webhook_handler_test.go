@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalWebhookHandler(t *testing.T) {
+	events := []*ApprovalEvent{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := &ApprovalEvent{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	url := ""
+	handler := &ApprovalWebhookHandler{
+		getURL: func() (string, error) {
+			return url, nil
+		},
+		getSchema: func() (string, error) {
+			return models.WebhookSchemaV1, nil
+		},
+	}
+	assert.False(t, handler.IsStateful())
+	err := handler.Handle("")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid type")
+	}
+
+	event := ApprovalEvent{
+		Event: ApprovalEventSubmitted,
+		Approval: &models.Approval{
+			ID:   1,
+			Type: models.ApprovalPromotion,
+		},
+	}
+	// the webhook isn't configured
+	require.Nil(t, handler.Handle(event))
+	assert.Equal(t, 0, len(events))
+
+	url = server.URL
+	require.Nil(t, handler.Handle(event))
+	require.Equal(t, 1, len(events))
+	assert.Equal(t, ApprovalEventSubmitted, events[0].Event)
+	assert.Equal(t, models.ApprovalPromotion, events[0].Approval.Type)
+
+	url = server.URL + "/unknown"
+	server.Config.Handler = http.NotFoundHandler()
+	assert.NotNil(t, handler.Handle(event))
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the events of blocklist
+const (
+	BlocklistEventPullBlocked = "pull_blocked"
+	BlocklistEventPushBlocked = "push_blocked"
+)
+
+// BlocklistEvent is defined for passing the alert to post, it's fired when a pull or push
+// requests a digest in the blocklist.
+type BlocklistEvent struct {
+	Event      string    `json:"event"`
+	Digest     string    `json:"digest"`
+	Reason     string    `json:"reason"`
+	Repository string    `json:"repository"`
+	Reference  string    `json:"reference"`
+	Username   string    `json:"username"`
+	OccurAt    time.Time `json:"occur_at"`
+}
+
+// BlocklistWebhookHandler is defined to post the alerts of blocklist to the
+// webhook URL configured in the system settings.
+type BlocklistWebhookHandler struct {
+	// returns the webhook URL, it's read from the configurations if nil
+	getURL func() (string, error)
+	// returns the version of the payload schema, it's read from the configurations if nil
+	getSchema func() (string, error)
+}
+
+// IsStateful to indicate this handler is stateless.
+func (b *BlocklistWebhookHandler) IsStateful() bool {
+	return false
+}
+
+// Handle posts the alert in JSON, nothing is posted if the webhook isn't configured.
+func (b *BlocklistWebhookHandler) Handle(value interface{}) error {
+	event, ok := value.(BlocklistEvent)
+	if !ok {
+		return errors.New("BlocklistWebhookHandler can not handle value with invalid type")
+	}
+
+	getURL := b.getURL
+	if getURL == nil {
+		getURL = config.BlocklistWebhookURL
+	}
+	url, err := getURL()
+	if err != nil {
+		return err
+	}
+	if len(url) == 0 {
+		return nil
+	}
+
+	getSchema := b.getSchema
+	if getSchema == nil {
+		getSchema = func() (string, error) {
+			return config.WebhookSchema(common.BlocklistWebhookSchema)
+		}
+	}
+	schema, err := getSchema()
+	if err != nil {
+		return err
+	}
+	return postWebhook(url, schema, "blocklist", event)
+}
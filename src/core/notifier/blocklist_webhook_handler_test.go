@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklistWebhookHandler(t *testing.T) {
+	events := []*BlocklistEvent{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := &BlocklistEvent{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	url := ""
+	handler := &BlocklistWebhookHandler{
+		getURL: func() (string, error) {
+			return url, nil
+		},
+		getSchema: func() (string, error) {
+			return models.WebhookSchemaV1, nil
+		},
+	}
+	assert.False(t, handler.IsStateful())
+	err := handler.Handle("")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid type")
+	}
+
+	event := BlocklistEvent{
+		Event:      BlocklistEventPullBlocked,
+		Digest:     "sha256:1",
+		Repository: "library/hello-world",
+		Username:   "admin",
+	}
+	// the webhook isn't configured
+	require.Nil(t, handler.Handle(event))
+	assert.Equal(t, 0, len(events))
+
+	url = server.URL
+	require.Nil(t, handler.Handle(event))
+	require.Equal(t, 1, len(events))
+	assert.Equal(t, BlocklistEventPullBlocked, events[0].Event)
+	assert.Equal(t, "sha256:1", events[0].Digest)
+	assert.Equal(t, "admin", events[0].Username)
+
+	server.Config.Handler = http.NotFoundHandler()
+	assert.NotNil(t, handler.Handle(event))
+}
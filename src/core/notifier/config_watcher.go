@@ -13,7 +13,14 @@ func WatchConfigChanges(cfg map[string]interface{}) error {
 		return errors.New("Empty configurations")
 	}
 
-	// Currently only watch the scan all policy change.
+	if v, ok := cfg[ProjectReportTopic]; ok {
+		if err := Publish(ProjectReportTopic, ProjectReportScheduleNotification{
+			Cron: utils.SafeCastString(v),
+		}); err != nil {
+			return err
+		}
+	}
+
 	if v, ok := cfg[ScanAllPolicyTopic]; ok {
 		policyCfg := &models.ScanAllPolicy{}
 		if err := utils.ConvertMapToStruct(policyCfg, v); err != nil {
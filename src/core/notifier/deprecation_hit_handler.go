@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+)
+
+// DeprecationEvent is defined for passing the pull of a deprecated image, it's fired by the
+// registry proxy after the pull succeeds.
+type DeprecationEvent struct {
+	DeprecationID int64
+	Repository    string
+	Reference     string
+	Username      string
+	OccurAt       time.Time
+}
+
+// DeprecationHitHandler is defined to count the pulls of the deprecated images as the hits
+// of the deprecations, so the owners know whether the images are still in use.
+type DeprecationHitHandler struct {
+	// counts the hit, it's dao.AddRepoDeprecationHit if nil
+	addHit func(id int64, t time.Time) error
+}
+
+// IsStateful to indicate this handler is stateless.
+func (d *DeprecationHitHandler) IsStateful() bool {
+	return false
+}
+
+// Handle counts the hit of the deprecation.
+func (d *DeprecationHitHandler) Handle(value interface{}) error {
+	event, ok := value.(DeprecationEvent)
+	if !ok {
+		return errors.New("DeprecationHitHandler can not handle value with invalid type")
+	}
+	addHit := d.addHit
+	if addHit == nil {
+		addHit = dao.AddRepoDeprecationHit
+	}
+	return addHit(event.DeprecationID, event.OccurAt)
+}
@@ -0,0 +1,48 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationHitHandler(t *testing.T) {
+	hits := map[int64]int{}
+	handler := &DeprecationHitHandler{
+		addHit: func(id int64, t time.Time) error {
+			hits[id]++
+			return nil
+		},
+	}
+	assert.False(t, handler.IsStateful())
+	err := handler.Handle("")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid type")
+	}
+
+	event := DeprecationEvent{
+		DeprecationID: 1,
+		Repository:    "library/app",
+		Reference:     "1.0",
+		OccurAt:       time.Now(),
+	}
+	require.Nil(t, handler.Handle(event))
+	require.Nil(t, handler.Handle(event))
+	assert.Equal(t, 2, hits[1])
+}
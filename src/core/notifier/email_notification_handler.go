@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	email_util "github.com/goharbor/harbor/src/common/utils/email"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+const emailTimeout = 60
+
+// EmailNotification is defined for passing the email to send.
+type EmailNotification struct {
+	To      []string
+	Subject string
+	Message string
+}
+
+// EmailNotificationHandler is defined to send the email notifications
+// with the email server settings of the system.
+type EmailNotificationHandler struct{}
+
+// IsStateful to indicate this handler is stateless.
+func (e *EmailNotificationHandler) IsStateful() bool {
+	return false
+}
+
+// Handle sends the email notification.
+func (e *EmailNotificationHandler) Handle(value interface{}) error {
+	notification, ok := value.(EmailNotification)
+	if !ok {
+		return errors.New("EmailNotificationHandler can not handle value with invalid type")
+	}
+
+	to := []string{}
+	for _, addr := range notification.To {
+		if len(addr) > 0 {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	settings, err := config.Email()
+	if err != nil {
+		return err
+	}
+	if len(settings.Host) == 0 {
+		return errors.New("the email server is not configured")
+	}
+
+	addr := net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port))
+	return email_util.Send(addr,
+		settings.Identity,
+		settings.Username,
+		settings.Password,
+		emailTimeout, settings.SSL,
+		settings.Insecure,
+		settings.From,
+		to,
+		notification.Subject,
+		notification.Message)
+}
@@ -0,0 +1,18 @@
+package notifier
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEmailNotificationHandler(t *testing.T) {
+	assert := assert.New(t)
+	e := &EmailNotificationHandler{}
+	assert.False(e.IsStateful())
+	err := e.Handle("")
+	if assert.NotNil(err) {
+		assert.Contains(err.Error(), "invalid type")
+	}
+	// nothing to send without recipients
+	assert.Nil(e.Handle(EmailNotification{To: []string{""}}))
+}
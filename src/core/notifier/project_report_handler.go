@@ -0,0 +1,83 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"errors"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/utils"
+)
+
+// ProjectReportScheduleNotification is the change of the cron of the project reports
+type ProjectReportScheduleNotification struct {
+	// the reports aren't sent if it is empty
+	Cron string
+}
+
+// ProjectReportScheduleHandler reschedules the periodic job sending the report emails of the
+// projects when the cron is changed
+type ProjectReportScheduleHandler struct{}
+
+// IsStateful to indicate this handler is stateful.
+func (p *ProjectReportScheduleHandler) IsStateful() bool {
+	// the schedules should be changed one by one
+	return true
+}
+
+// Handle cancels the scheduled job and schedules it again with the new cron
+func (p *ProjectReportScheduleHandler) Handle(value interface{}) error {
+	notification, ok := value.(ProjectReportScheduleNotification)
+	if !ok {
+		return errors.New("ProjectReportScheduleHandler can not handle value with invalid type")
+	}
+	if err := cancelPeriodicJobs(job.ProjectReport); err != nil {
+		return err
+	}
+	if len(notification.Cron) == 0 {
+		return nil
+	}
+	_, err := utils.ScheduleProjectReport(notification.Cron)
+	return err
+}
+
+// ScheduleProjectReport schedules the job sending the report emails of the projects with the
+// configured cron if it hasn't been scheduled, it's called when core starts
+func ScheduleProjectReport() error {
+	cron, err := config.ProjectReportCron()
+	if err != nil {
+		return err
+	}
+	if len(cron) == 0 {
+		return nil
+	}
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		Name: job.ProjectReport,
+		Kind: job.JobKindPeriodic,
+	})
+	if err != nil {
+		return err
+	}
+	if len(jobs) > 0 {
+		log.Debugf("the project report job has been scheduled, uuid: %s", jobs[0].UUID)
+		return nil
+	}
+	_, err = utils.ScheduleProjectReport(cron)
+	return err
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/projectconfig"
+)
+
+// the events of projects
+const (
+	ProjectEventCreated = "project_created"
+)
+
+// ProjectEvent is defined for passing the event to post, it's fired when a project is created
+// and the bootstrap hooks have been applied to it. The tokens of the robots created by the
+// hooks are only included here.
+type ProjectEvent struct {
+	Event       string                      `json:"event"`
+	ProjectID   int64                       `json:"project_id"`
+	ProjectName string                      `json:"project_name"`
+	Owner       string                      `json:"owner"`
+	Bootstrap   []*projectconfig.HookResult `json:"bootstrap"`
+	OccurAt     time.Time                   `json:"occur_at"`
+}
+
+// ProjectWebhookHandler is defined to post the events of projects to the
+// webhook URL configured in the system settings.
+type ProjectWebhookHandler struct {
+	// returns the webhook URL, it's read from the configurations if nil
+	getURL func() (string, error)
+	// returns the version of the payload schema, it's read from the configurations if nil
+	getSchema func() (string, error)
+}
+
+// IsStateful to indicate this handler is stateless.
+func (p *ProjectWebhookHandler) IsStateful() bool {
+	return false
+}
+
+// Handle posts the event in JSON, nothing is posted if the webhook isn't configured.
+func (p *ProjectWebhookHandler) Handle(value interface{}) error {
+	event, ok := value.(ProjectEvent)
+	if !ok {
+		return errors.New("ProjectWebhookHandler can not handle value with invalid type")
+	}
+
+	getURL := p.getURL
+	if getURL == nil {
+		getURL = config.ProjectWebhookURL
+	}
+	url, err := getURL()
+	if err != nil {
+		return err
+	}
+	if len(url) == 0 {
+		return nil
+	}
+
+	getSchema := p.getSchema
+	if getSchema == nil {
+		getSchema = func() (string, error) {
+			return config.WebhookSchema(common.ProjectWebhookSchema)
+		}
+	}
+	schema, err := getSchema()
+	if err != nil {
+		return err
+	}
+	return postWebhook(url, schema, "project", event)
+}
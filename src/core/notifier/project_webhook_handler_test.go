@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/projectconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectWebhookHandler(t *testing.T) {
+	events := []*ProjectEvent{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := &ProjectEvent{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	url := ""
+	handler := &ProjectWebhookHandler{
+		getURL: func() (string, error) {
+			return url, nil
+		},
+		getSchema: func() (string, error) {
+			return models.WebhookSchemaV1, nil
+		},
+	}
+	assert.False(t, handler.IsStateful())
+	err := handler.Handle("")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid type")
+	}
+
+	event := ProjectEvent{
+		Event:       ProjectEventCreated,
+		ProjectID:   2,
+		ProjectName: "team",
+		Owner:       "alice",
+		Bootstrap: []*projectconfig.HookResult{
+			{
+				Hook: "default-robot",
+				Result: &projectconfig.Result{
+					Robots: []*projectconfig.CreatedRobot{{Name: "robot$team-ci", Token: "token"}},
+				},
+			},
+			{
+				Hook:  "default-labels",
+				Error: "invalid template",
+			},
+		},
+	}
+	// the webhook isn't configured
+	require.Nil(t, handler.Handle(event))
+	assert.Equal(t, 0, len(events))
+
+	url = server.URL
+	require.Nil(t, handler.Handle(event))
+	require.Equal(t, 1, len(events))
+	assert.Equal(t, ProjectEventCreated, events[0].Event)
+	assert.Equal(t, "team", events[0].ProjectName)
+	require.Equal(t, 2, len(events[0].Bootstrap))
+	require.NotNil(t, events[0].Bootstrap[0].Result)
+	assert.Equal(t, "token", events[0].Bootstrap[0].Robots[0].Token)
+	assert.Nil(t, events[0].Bootstrap[1].Result)
+	assert.Equal(t, "invalid template", events[0].Bootstrap[1].Error)
+
+	server.Config.Handler = http.NotFoundHandler()
+	assert.NotNil(t, handler.Handle(event))
+}
@@ -0,0 +1,93 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the events of quota
+const (
+	QuotaEventThresholdCrossed = "threshold_crossed"
+	QuotaEventPushRejected     = "push_rejected"
+)
+
+// QuotaEvent is defined for passing the event of quota to post, it's fired when the
+// storage usage of a project crosses a threshold or a push is rejected as it exceeds the quota.
+type QuotaEvent struct {
+	Event       string `json:"event"`
+	ProjectID   int64  `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Repository  string `json:"repository"`
+	Quota       int64  `json:"quota"`
+	Usage       int64  `json:"usage"`
+	// the percentage of quota crossed, only for the event "threshold_crossed"
+	Threshold int `json:"threshold,omitempty"`
+	// the size of the rejected push, only for the event "push_rejected"
+	Requested int64     `json:"requested,omitempty"`
+	OccurAt   time.Time `json:"occur_at"`
+	// the quota webhook of the project
+	WebhookURL string `json:"-"`
+	// the version of the payload schema of the quota webhook, v1 if empty
+	WebhookSchema string `json:"-"`
+	// how the timestamps are rendered, in RFC 3339 in UTC if nil
+	Time *models.NotificationTime `json:"-"`
+}
+
+// MarshalJSON renders the time of the event with the timezone and the format of the project
+func (q QuotaEvent) MarshalJSON() ([]byte, error) {
+	type event QuotaEvent
+	t := q.Time
+	if t == nil {
+		t = models.DefaultNotificationTime
+	}
+	return json.Marshal(&struct {
+		event
+		OccurAt json.RawMessage `json:"occur_at"`
+	}{
+		event:   event(q),
+		OccurAt: t.RenderJSON(q.OccurAt),
+	})
+}
+
+// QuotaWebhookHandler is defined to post the events of quota to the webhook
+// URL configured in the metadata of the project.
+type QuotaWebhookHandler struct{}
+
+// IsStateful to indicate this handler is stateless.
+func (q *QuotaWebhookHandler) IsStateful() bool {
+	return false
+}
+
+// Handle posts the event of quota in JSON, nothing is posted if the project has no webhook.
+func (q *QuotaWebhookHandler) Handle(value interface{}) error {
+	event, ok := value.(QuotaEvent)
+	if !ok {
+		return errors.New("QuotaWebhookHandler can not handle value with invalid type")
+	}
+	if len(event.WebhookURL) == 0 {
+		return nil
+	}
+
+	if err := postWebhook(event.WebhookURL, event.WebhookSchema, "quota", event); err != nil {
+		return fmt.Errorf("%v of project %s", err, event.ProjectName)
+	}
+	return nil
+}
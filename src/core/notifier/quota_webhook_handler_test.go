@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaWebhookHandler(t *testing.T) {
+	events := []*QuotaEvent{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := &QuotaEvent{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	handler := &QuotaWebhookHandler{}
+	assert.False(t, handler.IsStateful())
+	err := handler.Handle("")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid type")
+	}
+
+	event := QuotaEvent{
+		Event:       QuotaEventThresholdCrossed,
+		ProjectID:   1,
+		ProjectName: "library",
+		Quota:       100,
+		Usage:       80,
+		Threshold:   80,
+	}
+	// the project has no webhook
+	require.Nil(t, handler.Handle(event))
+	assert.Equal(t, 0, len(events))
+
+	event.WebhookURL = server.URL
+	require.Nil(t, handler.Handle(event))
+	require.Equal(t, 1, len(events))
+	assert.Equal(t, QuotaEventThresholdCrossed, events[0].Event)
+	assert.Equal(t, "library", events[0].ProjectName)
+	assert.Equal(t, 80, events[0].Threshold)
+	assert.Equal(t, "", events[0].WebhookURL)
+
+	server.Config.Handler = http.NotFoundHandler()
+	assert.NotNil(t, handler.Handle(event))
+}
+
+func TestQuotaEventMarshalJSON(t *testing.T) {
+	event := QuotaEvent{
+		Event:   QuotaEventThresholdCrossed,
+		OccurAt: time.Date(2019, 10, 1, 8, 0, 0, 0, time.UTC),
+	}
+	data, err := json.Marshal(event)
+	require.Nil(t, err)
+	payload := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, "2019-10-01T08:00:00Z", payload["occur_at"])
+	assert.Equal(t, QuotaEventThresholdCrossed, payload["event"])
+
+	event.Time, err = models.ParseNotificationTime("Asia/Shanghai", models.NotificationTimeFormatDateTime)
+	require.Nil(t, err)
+	data, err = json.Marshal(event)
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, "2019-10-01 16:00:00 CST", payload["occur_at"])
+
+	event.Time.Format = models.NotificationTimeFormatUnix
+	data, err = json.Marshal(event)
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, float64(1569916800), payload["occur_at"])
+}
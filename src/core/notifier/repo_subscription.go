@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/security/local"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// NotifyRepoSubscribers sends the email and in-app notifications to the users who
+// subscribe the event of the repository, the type of the in-app notifications is the event.
+// The time of the event is appended to the emails in the timezone and the format of the project.
+func NotifyRepoSubscribers(repository, event, subject, message string) {
+	occurAt := time.Now()
+	users, err := dao.GetRepoSubscribers(repository, event)
+	if err != nil {
+		log.Errorf("failed to get the subscribers of repository %s: %v", repository, err)
+		return
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	projectName, _ := utils.ParseRepository(repository)
+	body := message + "\n\nTime: " + notificationTime(repository).Render(occurAt)
+	userIDs := []int{}
+	for _, user := range users {
+		// the subscribers may have lost the access to the repository since subscribing
+		if !local.NewSecurityContext(user, config.GlobalProjectMgr).HasReadPerm(projectName) {
+			log.Debugf("user %s can't read repository %s anymore, skip notifying", user.Username, repository)
+			continue
+		}
+		userIDs = append(userIDs, user.UserID)
+		// one email per subscriber so the subscribers aren't disclosed to each other
+		if err := Publish(EmailTopic, EmailNotification{
+			To:      []string{user.Email},
+			Subject: subject,
+			Message: body,
+		}); err != nil {
+			log.Errorf("failed to publish the %s event of repository %s to user %s: %v", event, repository, user.Username, err)
+		}
+	}
+	if len(userIDs) > 0 {
+		NotifyUsers(userIDs, event, message)
+	}
+}
+
+// notificationTime returns how the timestamps are rendered in the notifications of the
+// project which the repository belongs to
+func notificationTime(repository string) *models.NotificationTime {
+	projectName, _ := utils.ParseRepository(repository)
+	pro, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil {
+		log.Warningf("failed to get project %s, the time is rendered in the default format: %v", projectName, err)
+		return models.DefaultNotificationTime
+	}
+	if pro == nil {
+		return models.DefaultNotificationTime
+	}
+	return pro.NotificationTime()
+}
@@ -68,6 +68,11 @@ func (s *ScanPolicyNotificationHandler) Handle(value interface{}) error {
 }
 
 func cancelScanAllJobs(c ...job.Client) error {
+	return cancelPeriodicJobs(job.ImageScanAllJob, c...)
+}
+
+// cancelPeriodicJobs stops the periodic jobs of the name on jobservice and deletes the admin jobs of them
+func cancelPeriodicJobs(name string, c ...job.Client) error {
 	var client job.Client
 	if c == nil || len(c) == 0 {
 		client = utils.GetJobServiceClient()
@@ -75,30 +80,30 @@ func cancelScanAllJobs(c ...job.Client) error {
 		client = c[0]
 	}
 	q := &models.AdminJobQuery{
-		Name: job.ImageScanAllJob,
+		Name: name,
 		Kind: job.JobKindPeriodic,
 	}
 	jobs, err := dao.GetAdminJobs(q)
 	if err != nil {
-		log.Errorf("Failed to query sheduled scan_all jobs, error: %v", err)
+		log.Errorf("Failed to query sheduled %s jobs, error: %v", name, err)
 		return err
 	}
 	if len(jobs) > 1 {
-		log.Warningf("Got more than one scheduled scan_all jobs: %+v", jobs)
+		log.Warningf("Got more than one scheduled %s jobs: %+v", name, jobs)
 	}
 	for _, j := range jobs {
 		if err := dao.DeleteAdminJob(j.ID); err != nil {
-			log.Warningf("Failed to delete scan_all job from DB, job ID: %d, job UUID: %s, error: %v", j.ID, j.UUID, err)
+			log.Warningf("Failed to delete %s job from DB, job ID: %d, job UUID: %s, error: %v", name, j.ID, j.UUID, err)
 		}
 		if err := client.PostAction(j.UUID, job.JobActionStop); err != nil {
 			if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
-				log.Warningf("scan_all job not found on jobservice, UUID: %s, skip", j.UUID)
+				log.Warningf("%s job not found on jobservice, UUID: %s, skip", name, j.UUID)
 			} else {
-				log.Errorf("Failed to stop scan_all job, UUID: %s, error: %v", j.UUID, e)
-				return e
+				log.Errorf("Failed to stop %s job, UUID: %s, error: %v", name, j.UUID, err)
+				return err
 			}
 		}
-		log.Infof("scan_all job canceled, uuid: %s, id: %d", j.UUID, j.ID)
+		log.Infof("%s job canceled, uuid: %s, id: %d", name, j.UUID, j.ID)
 	}
 	return nil
 }
@@ -8,4 +8,28 @@ import (
 const (
 	// ScanAllPolicyTopic is for notifying the change of scanning all policy.
 	ScanAllPolicyTopic = common.ScanAllPolicy
+
+	// EmailTopic is for sending the email notifications to users.
+	EmailTopic = "email"
+
+	// ApprovalTopic is for posting the events of approvals to the webhook.
+	ApprovalTopic = "approval"
+
+	// QuotaTopic is for posting the events of quota to the webhooks of projects.
+	QuotaTopic = "quota"
+
+	// BlocklistTopic is for posting the alerts of the requests for blocked digests to the webhook.
+	BlocklistTopic = "blocklist"
+
+	// ProjectTopic is for posting the events of the creation of projects to the webhook.
+	ProjectTopic = "project"
+
+	// UserNotificationTopic is for storing the in-app notifications of users.
+	UserNotificationTopic = "user_notification"
+
+	// DeprecationTopic is for counting the pulls of the deprecated repositories and tags.
+	DeprecationTopic = "deprecation"
+
+	// ProjectReportTopic is for rescheduling the job sending the report emails of the projects.
+	ProjectReportTopic = common.ProjectReportCron
 )
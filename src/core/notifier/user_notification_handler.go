@@ -0,0 +1,108 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"errors"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// UserNotificationEvent is defined for passing the in-app notification to the users.
+type UserNotificationEvent struct {
+	UserIDs []int
+	Type    string
+	Message string
+}
+
+// UserNotificationHandler is defined to store the in-app notifications of the users,
+// they are shown in the notification center of the UI.
+type UserNotificationHandler struct {
+	// stores the notifications, it's dao.AddUserNotifications if nil
+	add func(userIDs []int, notificationType, message string) error
+}
+
+// IsStateful to indicate this handler is stateless.
+func (u *UserNotificationHandler) IsStateful() bool {
+	return false
+}
+
+// Handle stores the notification for each of the users.
+func (u *UserNotificationHandler) Handle(value interface{}) error {
+	event, ok := value.(UserNotificationEvent)
+	if !ok {
+		return errors.New("UserNotificationHandler can not handle value with invalid type")
+	}
+	if len(event.UserIDs) == 0 {
+		return nil
+	}
+	add := u.add
+	if add == nil {
+		add = dao.AddUserNotifications
+	}
+	return add(event.UserIDs, event.Type, event.Message)
+}
+
+// NotifyUsers sends the in-app notification to the users.
+func NotifyUsers(userIDs []int, notificationType, message string) {
+	if len(userIDs) == 0 {
+		return
+	}
+	if err := Publish(UserNotificationTopic, UserNotificationEvent{
+		UserIDs: userIDs,
+		Type:    notificationType,
+		Message: message,
+	}); err != nil {
+		log.Errorf("failed to publish the %s notification of users: %v", notificationType, err)
+	}
+}
+
+// NotifyProjectAdmins sends the in-app notification to the admins of the project, the
+// admins through the groups aren't notified.
+func NotifyProjectAdmins(projectID int64, notificationType, message string) {
+	members, err := project.GetProjectMember(models.Member{
+		ProjectID:  projectID,
+		EntityType: common.UserMember,
+	})
+	if err != nil {
+		log.Errorf("failed to get members of project %d: %v", projectID, err)
+		return
+	}
+	userIDs := []int{}
+	for _, member := range members {
+		if member.Role == common.RoleProjectAdmin && !member.IsExpired() {
+			userIDs = append(userIDs, member.EntityID)
+		}
+	}
+	NotifyUsers(userIDs, notificationType, message)
+}
+
+// NotifySysAdmins sends the in-app notification to the system admins.
+func NotifySysAdmins(notificationType, message string) {
+	admins, err := dao.GetSysAdmins()
+	if err != nil {
+		log.Errorf("failed to get the system admins: %v", err)
+		return
+	}
+	userIDs := []int{}
+	for _, admin := range admins {
+		userIDs = append(userIDs, admin.UserID)
+	}
+	NotifyUsers(userIDs, notificationType, message)
+}
@@ -0,0 +1,64 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserNotificationHandler(t *testing.T) {
+	stored := map[int][]string{}
+	handler := &UserNotificationHandler{
+		add: func(userIDs []int, notificationType, message string) error {
+			if notificationType == "" {
+				return errors.New("empty type")
+			}
+			for _, userID := range userIDs {
+				stored[userID] = append(stored[userID], message)
+			}
+			return nil
+		},
+	}
+	assert.False(t, handler.IsStateful())
+	err := handler.Handle("")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid type")
+	}
+
+	// no user to notify
+	require.Nil(t, handler.Handle(UserNotificationEvent{
+		Type:    models.UserNotificationScanDone,
+		Message: "scan done",
+	}))
+	assert.Equal(t, 0, len(stored))
+
+	require.Nil(t, handler.Handle(UserNotificationEvent{
+		UserIDs: []int{1, 2},
+		Type:    models.UserNotificationMembershipGranted,
+		Message: "joined",
+	}))
+	assert.Equal(t, []string{"joined"}, stored[1])
+	assert.Equal(t, []string{"joined"}, stored[2])
+
+	assert.NotNil(t, handler.Handle(UserNotificationEvent{
+		UserIDs: []int{1},
+		Message: "no type",
+	}))
+}
@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// WebhookSchemaHeader is the header of the webhook requests carrying the version of the
+// payload schema, the consumers serving several versions dispatch by it
+const WebhookSchemaHeader = "X-Harbor-Webhook-Schema"
+
+// WebhookPayloadV2 is the payload of the schema v2. It's converted from the v1 payload
+// of the event:
+//
+//   - "event" of v1 is prefixed with the kind of the webhook as "type", e.g. the "push_rejected"
+//     event of the quota webhook is "quota.push_rejected"
+//   - "occur_at" of v1 is kept as it is, rendered as the notification time of the project
+//   - all the other fields of v1 are moved into "data" with the same names and values
+//
+// The new fields of the events are only added into "data", so the consumers of v2 are
+// not broken by them, while the v1 payloads are never changed
+type WebhookPayloadV2 struct {
+	SchemaVersion string                     `json:"schema_version"`
+	Type          string                     `json:"type"`
+	OccurAt       json.RawMessage            `json:"occur_at"`
+	Data          map[string]json.RawMessage `json:"data"`
+}
+
+// webhookPayload returns the payload of the event in the version of the schema, the
+// kind is the one of the webhook, e.g. "quota"
+func webhookPayload(schema, kind string, event interface{}) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if schema != models.WebhookSchemaV2 {
+		return data, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var name string
+	if err = json.Unmarshal(fields["event"], &name); err != nil {
+		return nil, fmt.Errorf("invalid name of the %s event: %v", kind, err)
+	}
+	payload := &WebhookPayloadV2{
+		SchemaVersion: models.WebhookSchemaV2,
+		Type:          kind + "." + name,
+		OccurAt:       fields["occur_at"],
+	}
+	delete(fields, "event")
+	delete(fields, "occur_at")
+	payload.Data = fields
+	return json.Marshal(payload)
+}
+
+// postWebhook posts the event to the webhook in the version of the schema
+func postWebhook(url, schema, kind string, event interface{}) error {
+	if len(schema) == 0 {
+		schema = models.WebhookSchemaV1
+	}
+	data, err := webhookPayload(schema, kind, event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSchemaHeader, schema)
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d from the %s webhook %s", resp.StatusCode, kind, url)
+	}
+	return nil
+}
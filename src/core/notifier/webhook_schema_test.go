@@ -0,0 +1,90 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPayload(t *testing.T) {
+	event := QuotaEvent{
+		Event:       QuotaEventPushRejected,
+		ProjectName: "library",
+		Requested:   10,
+		OccurAt:     time.Date(2019, 10, 1, 8, 0, 0, 0, time.UTC),
+	}
+
+	// v1 is the event as it is
+	data, err := webhookPayload(models.WebhookSchemaV1, "quota", event)
+	require.Nil(t, err)
+	v1, err := json.Marshal(event)
+	require.Nil(t, err)
+	assert.JSONEq(t, string(v1), string(data))
+
+	data, err = webhookPayload(models.WebhookSchemaV2, "quota", event)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"schema_version": "v2",
+		"type": "quota.push_rejected",
+		"occur_at": "2019-10-01T08:00:00Z",
+		"data": {
+			"project_id": 0,
+			"project_name": "library",
+			"repository": "",
+			"quota": 0,
+			"usage": 0,
+			"requested": 10
+		}}`, string(data))
+
+	// the event without name
+	_, err = webhookPayload(models.WebhookSchemaV2, "quota", map[string]string{})
+	assert.NotNil(t, err)
+}
+
+func TestPostWebhook(t *testing.T) {
+	var schema string
+	payload := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema = r.Header.Get(WebhookSchemaHeader)
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &payload)
+	}))
+	defer server.Close()
+
+	event := ProjectEvent{
+		Event:       ProjectEventCreated,
+		ProjectName: "library",
+	}
+	require.Nil(t, postWebhook(server.URL, "", "project", event))
+	assert.Equal(t, models.WebhookSchemaV1, schema)
+	assert.Equal(t, ProjectEventCreated, payload["event"])
+
+	require.Nil(t, postWebhook(server.URL, models.WebhookSchemaV2, "project", event))
+	assert.Equal(t, models.WebhookSchemaV2, schema)
+	assert.Equal(t, "project.project_created", payload["type"])
+	assert.Equal(t, "library", payload["data"].(map[string]interface{})["project_name"])
+
+	server.Config.Handler = http.NotFoundHandler()
+	assert.NotNil(t, postWebhook(server.URL, models.WebhookSchemaV2, "project", event))
+}
@@ -0,0 +1,117 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectconfig
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// TemplateData is the data which the templates of the bootstrap hooks are rendered with
+type TemplateData struct {
+	Project   string
+	ProjectID int64
+	Owner     string
+}
+
+// HookResult is the result of applying one bootstrap hook to the new project
+type HookResult struct {
+	Hook string `json:"hook"`
+	*Result
+	Error string `json:"error,omitempty"`
+}
+
+// Render renders the template of the bootstrap hook and parses the rendered document
+func Render(tmpl string, data *TemplateData) (*Config, error) {
+	t, err := template.New("bootstrap").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, invalidConfig("invalid template: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err = t.Execute(buf, data); err != nil {
+		return nil, invalidConfig("failed to render the template: %v", err)
+	}
+	return Parse(buf.Bytes())
+}
+
+// ValidateTemplate checks whether the template renders a valid document for any project,
+// the document rendered for a sample project is returned
+func ValidateTemplate(tmpl string) (*Config, error) {
+	data := &TemplateData{
+		Project:   "bootstrap-sample",
+		ProjectID: 1,
+		Owner:     "admin",
+	}
+	cfg, err := Render(tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Project != data.Project {
+		return nil, invalidConfig("the project of the document should be {{.Project}}")
+	}
+	return cfg, nil
+}
+
+// Bootstrap applies the document rendered from the template to the new project. Only the
+// items which don't exist are created, so that the owner and the metadata specified when
+// creating the project are never removed or overridden by the hook
+func (m *Manager) Bootstrap(pro *models.Project, tmpl string) (*Result, error) {
+	desired, err := Render(tmpl, &TemplateData{
+		Project:   pro.Name,
+		ProjectID: pro.ProjectID,
+		Owner:     pro.OwnerName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes, err := m.Plan(pro, desired)
+	if err != nil {
+		return nil, err
+	}
+	creations := []*Change{}
+	for _, change := range changes {
+		if change.Action == ActionCreate {
+			creations = append(creations, change)
+		}
+	}
+	return m.Execute(pro, creations)
+}
+
+// RunBootstrapHooks applies the enabled bootstrap hooks to the new project in order, the
+// failure of one hook doesn't stop the others
+func (m *Manager) RunBootstrapHooks(pro *models.Project) ([]*HookResult, error) {
+	hooks, err := dao.ListProjectBootstrapHooks(true)
+	if err != nil {
+		return nil, err
+	}
+	results := []*HookResult{}
+	for _, hook := range hooks {
+		result, err := m.Bootstrap(pro, hook.Template)
+		r := &HookResult{
+			Hook:   hook.Name,
+			Result: result,
+		}
+		if err != nil {
+			log.Errorf("failed to apply the bootstrap hook %s to project %s: %v", hook.Name, pro.Name, err)
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tmpl := `apiVersion: v1
+kind: ProjectConfiguration
+project: {{.Project}}
+robots:
+- name: {{.Project}}-ci
+  access:
+  - resource: /project/{{.ProjectID}}/repository
+    action: push
+labels:
+- name: owned-by-{{.Owner}}
+`
+	cfg, err := Render(tmpl, &TemplateData{
+		Project:   "team",
+		ProjectID: 3,
+		Owner:     "alice",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "team", cfg.Project)
+	require.Equal(t, 1, len(cfg.Robots))
+	assert.Equal(t, "team-ci", cfg.Robots[0].Name)
+	assert.Equal(t, "/project/3/repository", cfg.Robots[0].Access[0].Resource.String())
+	require.Equal(t, 1, len(cfg.Labels))
+	assert.Equal(t, "owned-by-alice", cfg.Labels[0].Name)
+	_, err = ValidateTemplate(tmpl)
+	assert.Nil(t, err)
+
+	for _, tmpl := range []string{
+		"project: {{.Project",
+		"apiVersion: v1\nkind: ProjectConfiguration\nproject: {{.Unknown}}\n",
+		"apiVersion: v1\nkind: ProjectConfiguration\nproject: library\n",
+	} {
+		_, err = ValidateTemplate(tmpl)
+		require.NotNil(t, err, tmpl)
+		_, ok := err.(*InvalidConfigError)
+		assert.True(t, ok, tmpl)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package projectconfig exports the configuration of a project as a declarative YAML
+// document and applies the document to the project, which enables managing the projects
+// in GitOps way. Every section of the document is declarative: the items which aren't
+// listed in a section are removed from the project, while the omitted sections are left
+// untouched:
+//
+//	apiVersion: v1
+//	kind: ProjectConfiguration
+//	project: library
+//	metadata:
+//	  public: "true"
+//	members:
+//	- name: alice
+//	  type: user
+//	  role: developer
+//	robots:
+//	- name: ci
+//	  access:
+//	  - resource: /project/1/repository
+//	    action: push
+//	labels:
+//	- name: production
+//	  color: "#C92100"
+//	retention:
+//	  policy:
+//	    keep_latest: 10
+//	  repositories:
+//	  - repository: library/nginx
+//	    mode: disabled
+package projectconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/ghodss/yaml"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+)
+
+const (
+	// APIVersion is the version of the document format
+	APIVersion = "v1"
+	// Kind is the kind of the document
+	Kind = "ProjectConfiguration"
+
+	// MemberTypeUser is the type of the members which are users
+	MemberTypeUser = "user"
+	// MemberTypeGroup is the type of the members which are user groups
+	MemberTypeGroup = "group"
+)
+
+// the IDs of the roles by the names
+var roles = map[string]int{
+	"projectAdmin": common.RoleProjectAdmin,
+	"master":       common.RoleMaster,
+	"developer":    common.RoleDeveloper,
+	"guest":        common.RoleGuest,
+}
+
+// Config is the declarative configuration of a project
+type Config struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Project    string            `json:"project"`
+	Metadata   map[string]string `json:"metadata"`
+	Members    []*Member         `json:"members"`
+	Robots     []*Robot          `json:"robots"`
+	Labels     []*Label          `json:"labels"`
+	Retention  *Retention        `json:"retention"`
+}
+
+// Member is a user or user group which is a member of the project
+type Member struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Role string `json:"role"`
+	// ExpirationTime is nil if the membership never expires
+	ExpirationTime *time.Time `json:"expiration_time,omitempty"`
+
+	// the ID of the existing membership
+	id int
+}
+
+func (m *Member) key() string {
+	return m.Type + "/" + m.Name
+}
+
+// Robot is a robot account of the project, the name is without the prefix "robot$". The
+// access is only used to issue the token when the robot is created, as the token of the
+// existing robot can't be changed
+type Robot struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Disabled    bool           `json:"disabled"`
+	Access      []*rbac.Policy `json:"access,omitempty"`
+
+	robot *models.Robot
+}
+
+// Label is a label of the project scope
+type Label struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+
+	label *models.Label
+}
+
+// Retention is the retention policy of the project and the overrides of the repositories,
+// the project has no retention policy if the policy is nil
+type Retention struct {
+	Policy       *models.RetentionPolicy `json:"policy"`
+	Repositories []*RepoRetention        `json:"repositories"`
+}
+
+// RepoRetention is the retention override of a repository
+type RepoRetention struct {
+	Repository string                  `json:"repository"`
+	Mode       string                  `json:"mode"`
+	Policy     *models.RetentionPolicy `json:"policy,omitempty"`
+}
+
+// InvalidConfigError is returned when the document is malformed
+type InvalidConfigError struct {
+	msg string
+}
+
+func (e *InvalidConfigError) Error() string {
+	return e.msg
+}
+
+func invalidConfig(format string, args ...interface{}) error {
+	return &InvalidConfigError{
+		msg: fmt.Sprintf(format, args...),
+	}
+}
+
+// Parse parses the document in YAML or JSON and validates it, the unknown fields are rejected
+func Parse(data []byte) (*Config, error) {
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, invalidConfig("invalid document: %v", err)
+	}
+	cfg := &Config{}
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(cfg); err != nil {
+		return nil, invalidConfig("invalid document: %v", err)
+	}
+	if err = cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.APIVersion != APIVersion {
+		return invalidConfig("unsupported apiVersion %q, should be %q", c.APIVersion, APIVersion)
+	}
+	if c.Kind != Kind {
+		return invalidConfig("unsupported kind %q, should be %q", c.Kind, Kind)
+	}
+	if len(c.Project) == 0 {
+		return invalidConfig("project is required")
+	}
+
+	if _, exist := c.Metadata[models.ProMetaRetentionPolicy]; exist {
+		return invalidConfig("the metadata %s should be specified in the retention section", models.ProMetaRetentionPolicy)
+	}
+
+	members := map[string]bool{}
+	for _, m := range c.Members {
+		if len(m.Name) == 0 {
+			return invalidConfig("the name of member is required")
+		}
+		if m.Type != MemberTypeUser && m.Type != MemberTypeGroup {
+			return invalidConfig("invalid type %q of member %s, should be %q or %q", m.Type, m.Name, MemberTypeUser, MemberTypeGroup)
+		}
+		if _, ok := roles[m.Role]; !ok {
+			return invalidConfig("invalid role %q of member %s", m.Role, m.Name)
+		}
+		if members[m.key()] {
+			return invalidConfig("duplicate member %s", m.key())
+		}
+		members[m.key()] = true
+	}
+
+	robots := map[string]bool{}
+	for _, r := range c.Robots {
+		r.Name = strings.TrimPrefix(r.Name, common.RobotPrefix)
+		if len(r.Name) == 0 {
+			return invalidConfig("the name of robot is required")
+		}
+		if robots[r.Name] {
+			return invalidConfig("duplicate robot %s", r.Name)
+		}
+		robots[r.Name] = true
+	}
+
+	labels := map[string]bool{}
+	for _, l := range c.Labels {
+		if len(l.Name) == 0 {
+			return invalidConfig("the name of label is required")
+		}
+		if len(l.Name) > 128 {
+			return invalidConfig("the name of label %s is longer than 128", l.Name)
+		}
+		if labels[l.Name] {
+			return invalidConfig("duplicate label %s", l.Name)
+		}
+		labels[l.Name] = true
+	}
+
+	if c.Retention == nil {
+		return nil
+	}
+	if c.Retention.Policy != nil {
+		if err := validate(c.Retention.Policy); err != nil {
+			return invalidConfig("invalid retention policy: %v", err)
+		}
+	}
+	repositories := map[string]bool{}
+	for _, r := range c.Retention.Repositories {
+		if !strings.HasPrefix(r.Repository, c.Project+"/") {
+			return invalidConfig("repository %q isn't under project %s", r.Repository, c.Project)
+		}
+		if err := validate(&models.RepoRetention{
+			RepositoryName: r.Repository,
+			Mode:           r.Mode,
+			Policy:         r.Policy,
+		}); err != nil {
+			return invalidConfig("invalid retention of repository %s: %v", r.Repository, err)
+		}
+		if repositories[r.Repository] {
+			return invalidConfig("duplicate retention of repository %s", r.Repository)
+		}
+		repositories[r.Repository] = true
+	}
+	return nil
+}
+
+func validate(obj validation.ValidFormer) error {
+	v := &validation.Validation{}
+	obj.Valid(v)
+	if v.HasErrors() {
+		return fmt.Errorf("%s %s", v.Errors[0].Field, v.Errors[0].Message)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`
+apiVersion: v1
+kind: ProjectConfiguration
+project: library
+metadata:
+  public: "true"
+members:
+- name: alice
+  type: user
+  role: developer
+- name: ops
+  type: group
+  role: projectAdmin
+robots:
+- name: robot$ci
+  access:
+  - resource: /project/1/repository
+    action: push
+labels:
+- name: production
+  color: "#C92100"
+retention:
+  policy:
+    keep_latest: 10
+  repositories:
+  - repository: library/nginx
+    mode: disabled
+`))
+	require.Nil(t, err)
+	assert.Equal(t, "library", cfg.Project)
+	assert.Equal(t, map[string]string{"public": "true"}, cfg.Metadata)
+	require.Equal(t, 2, len(cfg.Members))
+	assert.Equal(t, "group/ops", cfg.Members[1].key())
+	require.Equal(t, 1, len(cfg.Robots))
+	assert.Equal(t, "ci", cfg.Robots[0].Name)
+	require.Equal(t, 1, len(cfg.Robots[0].Access))
+	require.Equal(t, 1, len(cfg.Labels))
+	assert.Equal(t, "#C92100", cfg.Labels[0].Color)
+	require.NotNil(t, cfg.Retention.Policy)
+	assert.Equal(t, 10, cfg.Retention.Policy.KeepLatest)
+	require.Equal(t, 1, len(cfg.Retention.Repositories))
+
+	// the omitted sections are nil
+	cfg, err = Parse([]byte(`{"apiVersion": "v1", "kind": "ProjectConfiguration", "project": "library", "members": []}`))
+	require.Nil(t, err)
+	assert.Nil(t, cfg.Metadata)
+	assert.NotNil(t, cfg.Members)
+	assert.Nil(t, cfg.Robots)
+	assert.Nil(t, cfg.Labels)
+	assert.Nil(t, cfg.Retention)
+
+	head := "apiVersion: v1\nkind: ProjectConfiguration\nproject: library\n"
+	for _, doc := range []string{
+		"- invalid",
+		"apiVersion: v2\nkind: ProjectConfiguration\nproject: library\n",
+		"apiVersion: v1\nkind: Project\nproject: library\n",
+		"apiVersion: v1\nkind: ProjectConfiguration\n",
+		head + "unknown: true\n",
+		head + "metadata:\n  retention_policy: '{}'\n",
+		head + "members:\n- name: alice\n  type: robot\n  role: developer\n",
+		head + "members:\n- name: alice\n  type: user\n  role: owner\n",
+		head + "members:\n- name: alice\n  type: user\n  role: guest\n- name: alice\n  type: user\n  role: developer\n",
+		head + "robots:\n- name: robot$\n",
+		head + "robots:\n- name: ci\n- name: robot$ci\n",
+		head + "labels:\n- description: no name\n",
+		head + "labels:\n- name: qa\n- name: qa\n",
+		head + "retention:\n  policy:\n    keep_latest: -1\n",
+		head + "retention:\n  repositories:\n  - repository: other/nginx\n    mode: disabled\n",
+		head + "retention:\n  repositories:\n  - repository: library/nginx\n    mode: override\n",
+	} {
+		_, err = Parse([]byte(doc))
+		require.NotNil(t, err, doc)
+		_, ok := err.(*InvalidConfigError)
+		assert.True(t, ok, doc)
+	}
+}
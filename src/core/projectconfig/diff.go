@@ -0,0 +1,233 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectconfig
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the sections of the changes
+const (
+	SectionMetadata  = "metadata"
+	SectionMember    = "member"
+	SectionRobot     = "robot"
+	SectionLabel     = "label"
+	SectionRetention = "retention"
+)
+
+// the actions of the changes
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is a difference between the current configuration and the desired one, the
+// current value is nil for the creations and the desired value is nil for the deletions
+type Change struct {
+	Section string      `json:"section"`
+	Name    string      `json:"name"`
+	Action  string      `json:"action"`
+	Current interface{} `json:"current,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+func newChange(section, name string, current, desired interface{}, currentExist, desiredExist bool) *Change {
+	change := &Change{
+		Section: section,
+		Name:    name,
+	}
+	switch {
+	case !currentExist:
+		change.Action = ActionCreate
+		change.Desired = desired
+	case !desiredExist:
+		change.Action = ActionDelete
+		change.Current = current
+	default:
+		change.Action = ActionUpdate
+		change.Current = current
+		change.Desired = desired
+	}
+	return change
+}
+
+// Diff returns the changes which make the current configuration the desired one, the
+// sections which are omitted in the desired configuration are skipped
+func Diff(current, desired *Config) []*Change {
+	changes := []*Change{}
+	if desired.Metadata != nil {
+		changes = append(changes, diffMetadata(current.Metadata, desired.Metadata)...)
+	}
+	if desired.Members != nil {
+		changes = append(changes, diffMembers(current.Members, desired.Members)...)
+	}
+	if desired.Robots != nil {
+		changes = append(changes, diffRobots(current.Robots, desired.Robots)...)
+	}
+	if desired.Labels != nil {
+		changes = append(changes, diffLabels(current.Labels, desired.Labels)...)
+	}
+	if desired.Retention != nil {
+		changes = append(changes, diffRetention(current.Project, current.Retention, desired.Retention)...)
+	}
+	return changes
+}
+
+func diffMetadata(current, desired map[string]string) []*Change {
+	changes := []*Change{}
+	for _, name := range unionKeys(current, desired) {
+		c, cok := current[name]
+		d, dok := desired[name]
+		if cok && dok && c == d {
+			continue
+		}
+		changes = append(changes, newChange(SectionMetadata, name, c, d, cok, dok))
+	}
+	return changes
+}
+
+func diffMembers(current, desired []*Member) []*Change {
+	cm := map[string]*Member{}
+	for _, m := range current {
+		cm[m.key()] = m
+	}
+	dm := map[string]*Member{}
+	for _, m := range desired {
+		dm[m.key()] = m
+	}
+
+	changes := []*Change{}
+	for _, key := range unionKeys(cm, dm) {
+		c, cok := cm[key]
+		d, dok := dm[key]
+		if cok && dok && c.Role == d.Role && timeEqual(c.ExpirationTime, d.ExpirationTime) {
+			continue
+		}
+		changes = append(changes, newChange(SectionMember, key, c, d, cok, dok))
+	}
+	return changes
+}
+
+func diffRobots(current, desired []*Robot) []*Change {
+	cm := map[string]*Robot{}
+	for _, r := range current {
+		cm[r.Name] = r
+	}
+	dm := map[string]*Robot{}
+	for _, r := range desired {
+		dm[r.Name] = r
+	}
+
+	changes := []*Change{}
+	for _, name := range unionKeys(cm, dm) {
+		c, cok := cm[name]
+		d, dok := dm[name]
+		if cok && dok && c.Description == d.Description && c.Disabled == d.Disabled {
+			continue
+		}
+		changes = append(changes, newChange(SectionRobot, name, c, d, cok, dok))
+	}
+	return changes
+}
+
+func diffLabels(current, desired []*Label) []*Change {
+	cm := map[string]*Label{}
+	for _, l := range current {
+		cm[l.Name] = l
+	}
+	dm := map[string]*Label{}
+	for _, l := range desired {
+		dm[l.Name] = l
+	}
+
+	changes := []*Change{}
+	for _, name := range unionKeys(cm, dm) {
+		c, cok := cm[name]
+		d, dok := dm[name]
+		if cok && dok && c.Description == d.Description && c.Color == d.Color {
+			continue
+		}
+		changes = append(changes, newChange(SectionLabel, name, c, d, cok, dok))
+	}
+	return changes
+}
+
+// diffRetention returns the changes of the retention policy of the project, whose
+// name is the project, and the changes of the overrides of the repositories
+func diffRetention(project string, current, desired *Retention) []*Change {
+	if current == nil {
+		current = &Retention{}
+	}
+	changes := []*Change{}
+	if !policyEqual(current.Policy, desired.Policy) {
+		changes = append(changes, newChange(SectionRetention, project, current.Policy, desired.Policy,
+			current.Policy != nil, desired.Policy != nil))
+	}
+
+	cm := map[string]*RepoRetention{}
+	for _, r := range current.Repositories {
+		cm[r.Repository] = r
+	}
+	dm := map[string]*RepoRetention{}
+	for _, r := range desired.Repositories {
+		dm[r.Repository] = r
+	}
+	for _, name := range unionKeys(cm, dm) {
+		c, cok := cm[name]
+		d, dok := dm[name]
+		if cok && dok && c.Mode == d.Mode && (c.Mode != models.RetentionModeOverride || policyEqual(c.Policy, d.Policy)) {
+			continue
+		}
+		changes = append(changes, newChange(SectionRetention, name, c, d, cok, dok))
+	}
+	return changes
+}
+
+func policyEqual(a, b *models.RetentionPolicy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.KeepLatest == b.KeepLatest &&
+		a.KeepPulledWithinDays == b.KeepPulledWithinDays &&
+		(len(a.KeepTags) == 0 && len(b.KeepTags) == 0 || reflect.DeepEqual(a.KeepTags, b.KeepTags))
+}
+
+func timeEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// unionKeys returns the sorted keys of both maps
+func unionKeys(a, b interface{}) []string {
+	set := map[string]bool{}
+	for _, m := range []interface{}{a, b} {
+		for _, key := range reflect.ValueOf(m).MapKeys() {
+			set[key.String()] = true
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
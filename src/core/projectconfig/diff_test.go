@@ -0,0 +1,121 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	expiration := time.Now().Add(time.Hour)
+	current := &Config{
+		Project: "library",
+		Metadata: map[string]string{
+			"public":    "true",
+			"auto_scan": "true",
+			"severity":  "low",
+		},
+		Members: []*Member{
+			{Name: "admin", Type: MemberTypeUser, Role: "projectAdmin", id: 1},
+			{Name: "alice", Type: MemberTypeUser, Role: "guest", id: 2},
+			{Name: "ops", Type: MemberTypeGroup, Role: "developer", id: 3},
+		},
+		Robots: []*Robot{
+			{Name: "ci", Description: "ci"},
+			{Name: "deploy"},
+		},
+		Labels: []*Label{
+			{Name: "production", Color: "#C92100"},
+			{Name: "qa"},
+		},
+		Retention: &Retention{
+			Policy: &models.RetentionPolicy{KeepLatest: 10, KeepTags: []string{}},
+			Repositories: []*RepoRetention{
+				{Repository: "library/nginx", Mode: models.RetentionModeDisabled},
+				{Repository: "library/redis", Mode: models.RetentionModeDisabled},
+			},
+		},
+	}
+	desired := &Config{
+		Project: "library",
+		Metadata: map[string]string{
+			"public":   "false",
+			"severity": "low",
+			"scanner":  "1",
+		},
+		Members: []*Member{
+			{Name: "admin", Type: MemberTypeUser, Role: "projectAdmin"},
+			{Name: "alice", Type: MemberTypeUser, Role: "guest", ExpirationTime: &expiration},
+			{Name: "ops", Type: MemberTypeUser, Role: "developer"},
+		},
+		Robots: []*Robot{
+			{Name: "ci", Description: "ci", Disabled: true},
+			{Name: "deploy"},
+		},
+		Labels: []*Label{
+			{Name: "production", Color: "#0065AB"},
+			{Name: "staging"},
+		},
+		Retention: &Retention{
+			Policy: &models.RetentionPolicy{KeepLatest: 10},
+			Repositories: []*RepoRetention{
+				{Repository: "library/nginx", Mode: models.RetentionModeDisabled},
+				{Repository: "library/busybox", Mode: models.RetentionModeOverride, Policy: &models.RetentionPolicy{KeepLatest: 1}},
+			},
+		},
+	}
+
+	type change struct {
+		section, name, action string
+	}
+	expected := []change{
+		{SectionMetadata, "auto_scan", ActionDelete},
+		{SectionMetadata, "public", ActionUpdate},
+		{SectionMetadata, "scanner", ActionCreate},
+		{SectionMember, "group/ops", ActionDelete},
+		{SectionMember, "user/alice", ActionUpdate},
+		{SectionMember, "user/ops", ActionCreate},
+		{SectionRobot, "ci", ActionUpdate},
+		{SectionLabel, "production", ActionUpdate},
+		{SectionLabel, "qa", ActionDelete},
+		{SectionLabel, "staging", ActionCreate},
+		{SectionRetention, "library/busybox", ActionCreate},
+		{SectionRetention, "library/redis", ActionDelete},
+	}
+	changes := Diff(current, desired)
+	require.Equal(t, len(expected), len(changes))
+	for i, c := range changes {
+		assert.Equal(t, expected[i], change{c.Section, c.Name, c.Action})
+	}
+
+	// the current and desired values
+	assert.Equal(t, "true", changes[0].Current)
+	assert.Nil(t, changes[0].Desired)
+	assert.Equal(t, 2, changes[4].Current.(*Member).id)
+	assert.Nil(t, changes[5].Current)
+
+	// the omitted sections are skipped
+	assert.Equal(t, 0, len(Diff(current, &Config{Project: "library"})))
+
+	// the project policy is removed
+	changes = Diff(current, &Config{Project: "library", Retention: &Retention{}})
+	require.Equal(t, 3, len(changes))
+	assert.Equal(t, change{SectionRetention, "library", ActionDelete}, change{changes[0].Section, changes[0].Name, changes[0].Action})
+}
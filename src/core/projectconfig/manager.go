@@ -0,0 +1,369 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/group"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/token"
+	"github.com/goharbor/harbor/src/core/auth"
+	"github.com/goharbor/harbor/src/core/promgr/metamgr"
+)
+
+// CreatedRobot is the robot created by the apply, the token is only returned here
+type CreatedRobot struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+// Result is the result of the apply
+type Result struct {
+	DryRun  bool            `json:"dry_run"`
+	Changes []*Change       `json:"changes"`
+	Robots  []*CreatedRobot `json:"robots,omitempty"`
+}
+
+// Manager exports and applies the configurations of the projects
+type Manager struct {
+	metaMgr metamgr.ProjectMetadataManager
+}
+
+// NewManager returns an instance of Manager
+func NewManager(metaMgr metamgr.ProjectMetadataManager) *Manager {
+	return &Manager{
+		metaMgr: metaMgr,
+	}
+}
+
+// Export returns the current configuration of the project
+func (m *Manager) Export(pro *models.Project) (*Config, error) {
+	cfg := &Config{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Project:    pro.Name,
+		Metadata:   map[string]string{},
+		Members:    []*Member{},
+		Robots:     []*Robot{},
+		Labels:     []*Label{},
+		Retention: &Retention{
+			Repositories: []*RepoRetention{},
+		},
+	}
+
+	metas, err := m.metaMgr.Get(pro.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the metadata of project %d: %v", pro.ProjectID, err)
+	}
+	for name, value := range metas {
+		if name != models.ProMetaRetentionPolicy {
+			cfg.Metadata[name] = value
+			continue
+		}
+		if len(value) > 0 {
+			if cfg.Retention.Policy, err = models.ParseRetentionPolicy(value); err != nil {
+				return nil, fmt.Errorf("failed to parse the retention policy of project %d: %v", pro.ProjectID, err)
+			}
+		}
+	}
+
+	members, err := project.GetProjectMember(models.Member{ProjectID: pro.ProjectID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the members of project %d: %v", pro.ProjectID, err)
+	}
+	for _, member := range members {
+		m := &Member{
+			Name:           member.Entityname,
+			Type:           MemberTypeUser,
+			Role:           member.Rolename,
+			ExpirationTime: member.ExpirationTime,
+			id:             member.ID,
+		}
+		if member.EntityType == common.GroupMember {
+			m.Type = MemberTypeGroup
+		}
+		cfg.Members = append(cfg.Members, m)
+	}
+
+	robots, err := dao.ListRobots(&models.RobotQuery{ProjectID: pro.ProjectID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the robots of project %d: %v", pro.ProjectID, err)
+	}
+	for _, robot := range robots {
+		cfg.Robots = append(cfg.Robots, &Robot{
+			Name:        strings.TrimPrefix(robot.Name, common.RobotPrefix),
+			Description: robot.Description,
+			Disabled:    robot.Disabled,
+			robot:       robot,
+		})
+	}
+
+	labels, err := dao.ListLabels(&models.LabelQuery{
+		Level:     common.LabelLevelUser,
+		Scope:     common.LabelScopeProject,
+		ProjectID: pro.ProjectID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the labels of project %d: %v", pro.ProjectID, err)
+	}
+	for _, label := range labels {
+		cfg.Labels = append(cfg.Labels, &Label{
+			Name:        label.Name,
+			Description: label.Description,
+			Color:       label.Color,
+			label:       label,
+		})
+	}
+
+	retentions, err := dao.ListRepoRetentions(pro.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the retention overrides of project %d: %v", pro.ProjectID, err)
+	}
+	for _, retention := range retentions {
+		cfg.Retention.Repositories = append(cfg.Retention.Repositories, &RepoRetention{
+			Repository: retention.RepositoryName,
+			Mode:       retention.Mode,
+			Policy:     retention.Policy,
+		})
+	}
+	return cfg, nil
+}
+
+// Plan returns the changes which make the configuration of the project the desired one
+func (m *Manager) Plan(pro *models.Project, desired *Config) ([]*Change, error) {
+	if desired.Project != pro.Name {
+		return nil, invalidConfig("the document is for project %s rather than %s", desired.Project, pro.Name)
+	}
+	current, err := m.Export(pro)
+	if err != nil {
+		return nil, err
+	}
+	changes := Diff(current, desired)
+	for _, change := range changes {
+		if change.Section == SectionRobot && change.Action == ActionCreate &&
+			len(change.Desired.(*Robot).Access) == 0 {
+			return nil, invalidConfig("the access of the new robot %s is required", change.Name)
+		}
+	}
+	return changes, nil
+}
+
+// Execute applies the planned changes to the project one by one, the changes are not
+// rolled back if any of them fails
+func (m *Manager) Execute(pro *models.Project, changes []*Change) (*Result, error) {
+	result := &Result{
+		Changes: changes,
+	}
+	for _, change := range changes {
+		var err error
+		switch change.Section {
+		case SectionMetadata:
+			err = m.applyMetadata(pro, change)
+		case SectionMember:
+			err = applyMember(pro, change)
+		case SectionRobot:
+			var robot *CreatedRobot
+			if robot, err = applyRobot(pro, change); robot != nil {
+				result.Robots = append(result.Robots, robot)
+			}
+		case SectionLabel:
+			err = applyLabel(pro, change)
+		case SectionRetention:
+			err = m.applyRetention(pro, change)
+		default:
+			err = fmt.Errorf("unknown section %s", change.Section)
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to %s %s %s: %v", change.Action, change.Section, change.Name, err)
+		}
+	}
+	return result, nil
+}
+
+func (m *Manager) applyMetadata(pro *models.Project, change *Change) error {
+	switch change.Action {
+	case ActionCreate:
+		return m.metaMgr.Add(pro.ProjectID, map[string]string{change.Name: change.Desired.(string)})
+	case ActionUpdate:
+		return m.metaMgr.Update(pro.ProjectID, map[string]string{change.Name: change.Desired.(string)})
+	default:
+		return m.metaMgr.Delete(pro.ProjectID, change.Name)
+	}
+}
+
+func applyMember(pro *models.Project, change *Change) error {
+	switch change.Action {
+	case ActionCreate:
+		desired := change.Desired.(*Member)
+		member := models.Member{
+			ProjectID:      pro.ProjectID,
+			Role:           roles[desired.Role],
+			ExpirationTime: desired.ExpirationTime,
+		}
+		if member.IsExpired() {
+			return fmt.Errorf("the membership has expired")
+		}
+		var err error
+		if desired.Type == MemberTypeGroup {
+			member.EntityType = common.GroupMember
+			member.EntityID, err = groupID(desired.Name)
+		} else {
+			member.EntityType = common.UserMember
+			member.EntityID, err = auth.SearchAndOnBoardUser(desired.Name)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = project.AddProjectMember(member)
+		return err
+	case ActionUpdate:
+		current, desired := change.Current.(*Member), change.Desired.(*Member)
+		if err := project.UpdateProjectMemberRole(current.id, roles[desired.Role]); err != nil {
+			return err
+		}
+		return project.UpdateProjectMemberExpiration(current.id, desired.ExpirationTime)
+	default:
+		return project.DeleteProjectMemberByID(change.Current.(*Member).id)
+	}
+}
+
+// groupID returns the ID of the user group whose name is exactly the specified one
+func groupID(name string) (int, error) {
+	groups, err := group.QueryUserGroup(models.UserGroup{GroupName: name})
+	if err != nil {
+		return 0, err
+	}
+	id := 0
+	for _, g := range groups {
+		if g.GroupName != name {
+			continue
+		}
+		if id != 0 {
+			return 0, fmt.Errorf("more than one user group named %s", name)
+		}
+		id = g.ID
+	}
+	if id == 0 {
+		return 0, fmt.Errorf("user group %s not found", name)
+	}
+	return id, nil
+}
+
+func applyRobot(pro *models.Project, change *Change) (*CreatedRobot, error) {
+	switch change.Action {
+	case ActionCreate:
+		desired := change.Desired.(*Robot)
+		robot := &models.Robot{
+			Name:        common.RobotPrefix + desired.Name,
+			Description: desired.Description,
+			ProjectID:   pro.ProjectID,
+			Disabled:    desired.Disabled,
+		}
+		if err := robot.AddAccess(desired.Access); err != nil {
+			return nil, err
+		}
+		id, err := dao.AddRobot(robot)
+		if err != nil {
+			return nil, err
+		}
+		tk, err := token.New(id, pro.ProjectID, desired.Access)
+		if err == nil {
+			var raw string
+			if raw, err = tk.Raw(); err == nil {
+				return &CreatedRobot{
+					Name:  robot.Name,
+					Token: raw,
+				}, nil
+			}
+		}
+		if e := dao.DeleteRobot(id); e != nil {
+			return nil, fmt.Errorf("%v, failed to delete the robot: %v", err, e)
+		}
+		return nil, err
+	case ActionUpdate:
+		robot, desired := change.Current.(*Robot).robot, change.Desired.(*Robot)
+		robot.Description = desired.Description
+		robot.Disabled = desired.Disabled
+		return nil, dao.UpdateRobot(robot)
+	default:
+		return nil, dao.DeleteRobot(change.Current.(*Robot).robot.ID)
+	}
+}
+
+func applyLabel(pro *models.Project, change *Change) error {
+	switch change.Action {
+	case ActionCreate:
+		desired := change.Desired.(*Label)
+		_, err := dao.AddLabel(&models.Label{
+			Name:        desired.Name,
+			Description: desired.Description,
+			Color:       desired.Color,
+			Level:       common.LabelLevelUser,
+			Scope:       common.LabelScopeProject,
+			ProjectID:   pro.ProjectID,
+		})
+		return err
+	case ActionUpdate:
+		label, desired := change.Current.(*Label).label, change.Desired.(*Label)
+		label.Description = desired.Description
+		label.Color = desired.Color
+		return dao.UpdateLabel(label)
+	default:
+		id := change.Current.(*Label).label.ID
+		if err := dao.DeleteResourceLabelByLabel(id); err != nil {
+			return err
+		}
+		return dao.DeleteLabel(id)
+	}
+}
+
+func (m *Manager) applyRetention(pro *models.Project, change *Change) error {
+	// the retention policy of the project
+	if change.Name == pro.Name {
+		if change.Action == ActionDelete {
+			return m.metaMgr.Delete(pro.ProjectID, models.ProMetaRetentionPolicy)
+		}
+		data, err := json.Marshal(change.Desired)
+		if err != nil {
+			return err
+		}
+		// the metadata may exist with an empty value when the project has no policy
+		existing, err := m.metaMgr.Get(pro.ProjectID, models.ProMetaRetentionPolicy)
+		if err != nil {
+			return err
+		}
+		metas := map[string]string{models.ProMetaRetentionPolicy: string(data)}
+		if _, exist := existing[models.ProMetaRetentionPolicy]; !exist {
+			return m.metaMgr.Add(pro.ProjectID, metas)
+		}
+		return m.metaMgr.Update(pro.ProjectID, metas)
+	}
+
+	if change.Action == ActionDelete {
+		return dao.DeleteRepoRetention(change.Name)
+	}
+	desired := change.Desired.(*RepoRetention)
+	return dao.SetRepoRetention(&models.RepoRetention{
+		RepositoryName: desired.Repository,
+		Mode:           desired.Mode,
+		Policy:         desired.Policy,
+	})
+}
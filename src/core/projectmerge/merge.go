@@ -0,0 +1,315 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package projectmerge merges one project into another in background. The repositories
+// are moved with the blobs mounted, the members and robot accounts are deduplicated and
+// the source project is retired, the pulls of the moved repositories are redirected
+// during the configured period. The merges run as the tasks of core through jobservice.
+package projectmerge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/coretask"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// TaskName is the name of the task merging the projects
+const TaskName = "PROJECT_MERGE"
+
+// ErrMergeRunning is returned when another merge of the source or target project is running
+var ErrMergeRunning = errors.New("another merge of the projects is running")
+
+// ConflictError is returned when the repositories of the source project exist in the target one
+type ConflictError struct {
+	Repositories []string
+}
+
+func (c *ConflictError) Error() string {
+	return fmt.Sprintf("the repositories already exist in the target project: %s", strings.Join(c.Repositories, ", "))
+}
+
+// the privileges of the roles, the higher the more
+var rolePrivileges = map[int]int{
+	common.RoleGuest:        1,
+	common.RoleDeveloper:    2,
+	common.RoleMaster:       3,
+	common.RoleProjectAdmin: 4,
+}
+
+// Runner runs the merges submitted to jobservice
+var Runner = &coretask.Runner{
+	Run:  Run,
+	Fail: Fail,
+}
+
+// Start records the merge of the source project into the target one and submits it to jobservice,
+// the ID of the merge record is returned. A project can only be involved in one merge at the
+// same time, which is locked in the database
+func Start(source, target *models.Project, memberRule, operator string) (int64, error) {
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{source.ProjectID},
+	})
+	if err != nil {
+		return 0, err
+	}
+	conflicts := []string{}
+	for _, repository := range repositories {
+		if dao.RepositoryExists(targetName(repository.Name, target.Name)) {
+			conflicts = append(conflicts, repository.Name)
+		}
+	}
+	if len(conflicts) > 0 {
+		return 0, &ConflictError{
+			Repositories: conflicts,
+		}
+	}
+
+	id, err := dao.AddProjectMerge(&models.ProjectMerge{
+		SourceProjectID:   source.ProjectID,
+		SourceProjectName: source.Name,
+		TargetProjectID:   target.ProjectID,
+		MemberRule:        memberRule,
+		Status:            models.ProjectMergeRunning,
+		Creator:           operator,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = dao.LockResources(coretask.Owner(TaskName, id), coretask.ProjectResource(TaskName, source.ProjectID),
+		coretask.ProjectResource(TaskName, target.ProjectID)); err != nil {
+		if e := dao.DeleteProjectMerge(id); e != nil {
+			log.Errorf("failed to delete project merge %d: %v", id, e)
+		}
+		if err == dao.ErrResourceLocked {
+			return 0, ErrMergeRunning
+		}
+		return 0, err
+	}
+	if err = coretask.Submit(TaskName, id); err != nil {
+		err = fmt.Errorf("failed to submit the merge: %v", err)
+		if e := finish(id, err); e != nil {
+			log.Errorf("failed to finish project merge %d: %v", id, e)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Run runs the merge, it's resumed if it's interrupted as the moved repositories aren't
+// in the source project anymore and the members and robots are deduplicated
+func Run(id int64) error {
+	merge, err := dao.GetProjectMerge(id)
+	if err != nil {
+		return err
+	}
+	if merge == nil || merge.Status != models.ProjectMergeRunning {
+		log.Debugf("the project merge %d isn't running, skip", id)
+		return nil
+	}
+	return finish(id, run(merge))
+}
+
+// Fail marks the merge as failed if it's still running
+func Fail(id int64, message string) error {
+	merge, err := dao.GetProjectMerge(id)
+	if err != nil {
+		return err
+	}
+	if merge == nil || merge.Status != models.ProjectMergeRunning {
+		return nil
+	}
+	return finish(id, errors.New(message))
+}
+
+// finish records the result of the merge and releases the locks, the error recording
+// the result is returned
+func finish(id int64, result error) error {
+	merge, err := dao.GetProjectMerge(id)
+	if err != nil {
+		return err
+	}
+	if merge == nil {
+		return dao.UnlockResources(coretask.Owner(TaskName, id))
+	}
+	merge.Status = models.ProjectMergeSucceeded
+	if result != nil {
+		log.Errorf("failed to merge project %s into project %d: %v", merge.SourceProjectName, merge.TargetProjectID, result)
+		merge.Status, merge.Message = models.ProjectMergeFailed, result.Error()
+	}
+	if err = dao.UpdateProjectMerge(merge); err != nil {
+		return fmt.Errorf("failed to update the status of project merge %d: %v", id, err)
+	}
+	return dao.UnlockResources(coretask.Owner(TaskName, id))
+}
+
+// run moves the repositories, members and robots, the progress is recorded after each
+// repository is moved. The source project is retired only if all of them are moved
+func run(merge *models.ProjectMerge) error {
+	source, err := config.GlobalProjectMgr.Get(merge.SourceProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get the source project: %v", err)
+	}
+	if source == nil {
+		// retired by the interrupted run
+		return nil
+	}
+	target, err := config.GlobalProjectMgr.Get(merge.TargetProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get the target project: %v", err)
+	}
+	if target == nil {
+		return fmt.Errorf("the target project %d not found", merge.TargetProjectID)
+	}
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{source.ProjectID},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, repository := range repositories {
+		name := targetName(repository.Name, target.Name)
+		if err := coreutils.MoveRepository(repository, name, target.ProjectID, merge.Creator); err != nil {
+			return fmt.Errorf("failed to move repository %s to %s: %v", repository.Name, name, err)
+		}
+		merge.MovedRepositories++
+		if err := dao.UpdateProjectMerge(merge); err != nil {
+			log.Errorf("failed to update the progress of project merge %d: %v", merge.ID, err)
+		}
+	}
+
+	members, err := mergeMembers(source.ProjectID, target.ProjectID, merge.MemberRule)
+	if err != nil {
+		return fmt.Errorf("failed to merge the members: %v", err)
+	}
+	merge.MovedMembers = members
+
+	robots, err := mergeRobots(source.ProjectID, target.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to merge the robot accounts: %v", err)
+	}
+	merge.MovedRobots = robots
+
+	if err = config.GlobalProjectMgr.Delete(source.ProjectID); err != nil {
+		return fmt.Errorf("failed to retire project %s: %v", source.Name, err)
+	}
+	return nil
+}
+
+// mergeMembers adds the members of the source project to the target one, the members of
+// both projects are deduplicated by the rule. The count of the members added or whose roles
+// are changed is returned
+func mergeMembers(sourceID, targetID int64, rule string) (int, error) {
+	sources, err := project.GetProjectMember(models.Member{ProjectID: sourceID})
+	if err != nil {
+		return 0, err
+	}
+	targets, err := project.GetProjectMember(models.Member{ProjectID: targetID})
+	if err != nil {
+		return 0, err
+	}
+	existing := map[string]*models.Member{}
+	for _, member := range targets {
+		existing[memberKey(member)] = member
+	}
+
+	count := 0
+	for _, member := range sources {
+		if member.IsExpired() {
+			continue
+		}
+		if m, exist := existing[memberKey(member)]; exist {
+			role, changed := mergedRole(rule, member.Role, m.Role)
+			if !changed {
+				continue
+			}
+			if err = project.UpdateProjectMemberRole(m.ID, role); err != nil {
+				return count, err
+			}
+			count++
+			continue
+		}
+		if _, err = project.AddProjectMember(models.Member{
+			ProjectID:      targetID,
+			EntityID:       member.EntityID,
+			EntityType:     member.EntityType,
+			Role:           member.Role,
+			ExpirationTime: member.ExpirationTime,
+		}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// mergeRobots moves the robot accounts of the source project to the target one, the tokens issued
+// before keep working. The robots whose names are used in the target project are deleted. The count
+// of the moved robots is returned
+func mergeRobots(sourceID, targetID int64) (int, error) {
+	robots, err := dao.ListRobots(&models.RobotQuery{
+		ProjectID: sourceID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, robot := range robots {
+		duplicates, err := dao.ListRobots(&models.RobotQuery{
+			Name:      robot.Name,
+			ProjectID: targetID,
+		})
+		if err != nil {
+			return count, err
+		}
+		if len(duplicates) > 0 {
+			if err = dao.DeleteRobot(robot.ID); err != nil {
+				return count, err
+			}
+			continue
+		}
+		if err = dao.MoveRobot(robot.ID, targetID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// mergedRole returns the role of the member which exists in both projects according to
+// the rule, and whether it differs from the role in the target project
+func mergedRole(rule string, sourceRole, targetRole int) (int, bool) {
+	if rule == models.MemberRuleHigherRole && rolePrivileges[sourceRole] > rolePrivileges[targetRole] {
+		return sourceRole, true
+	}
+	return targetRole, false
+}
+
+func memberKey(member *models.Member) string {
+	return fmt.Sprintf("%s:%d", member.EntityType, member.EntityID)
+}
+
+// targetName returns the name of the repository after it's moved to the target project
+func targetName(repository, targetProject string) string {
+	return targetProject + "/" + repository[strings.Index(repository, "/")+1:]
+}
@@ -0,0 +1,56 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projectmerge
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergedRole(t *testing.T) {
+	cases := []struct {
+		rule       string
+		sourceRole int
+		targetRole int
+		role       int
+		changed    bool
+	}{
+		{models.MemberRuleHigherRole, common.RoleProjectAdmin, common.RoleGuest, common.RoleProjectAdmin, true},
+		{models.MemberRuleHigherRole, common.RoleMaster, common.RoleDeveloper, common.RoleMaster, true},
+		{models.MemberRuleHigherRole, common.RoleDeveloper, common.RoleMaster, common.RoleMaster, false},
+		{models.MemberRuleHigherRole, common.RoleGuest, common.RoleGuest, common.RoleGuest, false},
+		{models.MemberRuleKeepTarget, common.RoleProjectAdmin, common.RoleGuest, common.RoleGuest, false},
+	}
+	for _, c := range cases {
+		role, changed := mergedRole(c.rule, c.sourceRole, c.targetRole)
+		assert.Equal(t, c.role, role)
+		assert.Equal(t, c.changed, changed)
+	}
+}
+
+func TestTargetName(t *testing.T) {
+	assert.Equal(t, "target/repo", targetName("source/repo", "target"))
+	assert.Equal(t, "target/a/b", targetName("source/a/b", "target"))
+}
+
+func TestConflictError(t *testing.T) {
+	err := &ConflictError{
+		Repositories: []string{"source/a", "source/b"},
+	}
+	assert.Equal(t, "the repositories already exist in the target project: source/a, source/b", err.Error())
+}
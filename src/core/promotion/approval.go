@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promotion
+
+import (
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/approval"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// approvalHandler executes the pending promotions reviewed through the approval engine
+type approvalHandler struct{}
+
+func (a *approvalHandler) Approve(ap *models.Approval, reviewer, comment string) error {
+	promotion, err := getPending(ap.ResourceID)
+	if err != nil {
+		return err
+	}
+	return convert(Approve(config.GlobalProjectMgr, promotion, reviewer))
+}
+
+func (a *approvalHandler) Reject(ap *models.Approval, reviewer, comment string) error {
+	promotion, err := getPending(ap.ResourceID)
+	if err != nil {
+		return err
+	}
+	return convert(Reject(promotion, reviewer, comment))
+}
+
+func getPending(id int64) (*models.Promotion, error) {
+	promotion, err := dao.GetPromotion(id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil || promotion.Status != models.PromotionPending {
+		return nil, approval.ErrNotPending
+	}
+	return promotion, nil
+}
+
+func convert(err error) error {
+	if err == ErrNotPending {
+		return approval.ErrNotPending
+	}
+	return err
+}
+
+func init() {
+	approval.Register(models.ApprovalPromotion, &approvalHandler{})
+}
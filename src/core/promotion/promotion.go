@@ -0,0 +1,292 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package promotion moves the images along the pipelines of projects, e.g. dev -> staging -> prod.
+// The gates of the target stage are checked before an image is copied into the project of it,
+// the promotions are recorded and the subscribers of the target repository are notified.
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/approval"
+	"github.com/goharbor/harbor/src/core/notifier"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+var (
+	// ErrNotInPipeline is returned when the project of the image isn't a stage of the pipeline
+	ErrNotInPipeline = errors.New("the project of the image isn't a stage of the pipeline")
+	// ErrLastStage is returned when the image is already in the last stage of the pipeline
+	ErrLastStage = errors.New("the image is already in the last stage of the pipeline")
+	// ErrImageNotFound is returned when the image to promote doesn't exist
+	ErrImageNotFound = errors.New("the image not found")
+	// ErrNotPending is returned when the promotion to approve or reject isn't pending
+	ErrNotPending = errors.New("the promotion isn't pending")
+)
+
+// GateError is returned when the image doesn't pass the gates of the target stage
+type GateError struct {
+	msg string
+}
+
+func (e *GateError) Error() string {
+	return e.msg
+}
+
+func gateFailed(format string, args ...interface{}) error {
+	return &GateError{
+		msg: fmt.Sprintf(format, args...),
+	}
+}
+
+// Source provides the images promoted along the pipeline
+type Source interface {
+	// GetDigest returns the digest of the tag, it's empty if the tag doesn't exist
+	GetDigest(repository, tag string) (string, error)
+	GetScanOverview(digest string) (*models.ImgScanOverview, error)
+	// GetSignedDigests returns the signed tags and their digests of the repository
+	GetSignedDigests(repository string) (map[string]string, error)
+	// Copy copies the image specified by digest into the target repository with the tag
+	Copy(repository, digest, targetRepository, tag string) error
+}
+
+// projectManager is the subset of promgr.ProjectManager used by the promotion
+type projectManager interface {
+	Get(projectIDOrName interface{}) (*models.Project, error)
+}
+
+var (
+	// NewSource returns the source of the images promoted by the user, defined as a var for testing
+	NewSource = func(username string) Source {
+		return &harborSource{
+			username: username,
+		}
+	}
+	// serializes the approvals so that a pending promotion is executed only once
+	lock sync.Mutex
+)
+
+// Promote promotes the image into the next stage of the pipeline. The promotion is recorded as
+// pending if the approval is required by the next stage and executed once it's approved, the
+// failed promotion is recorded as well and returned with a *GateError if the image doesn't pass
+// the gates
+func Promote(projectMgr projectManager, pipeline *models.PromotionPipeline, repository, tag, requester string) (*models.Promotion, error) {
+	projectName, repo := utils.ParseRepository(repository)
+	project, err := projectMgr.Get(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s: %v", projectName, err)
+	}
+	if project == nil {
+		return nil, ErrNotInPipeline
+	}
+	index := pipeline.StageOf(project.ProjectID)
+	if index < 0 {
+		return nil, ErrNotInPipeline
+	}
+	if index == len(pipeline.Stages)-1 {
+		return nil, ErrLastStage
+	}
+	target, err := projectMgr.Get(pipeline.Stages[index+1].ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %d: %v", pipeline.Stages[index+1].ProjectID, err)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("project %d of stage %d not found", pipeline.Stages[index+1].ProjectID, index+1)
+	}
+
+	source := NewSource(requester)
+	digest, err := source.GetDigest(repository, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the digest of %s:%s: %v", repository, tag, err)
+	}
+	if len(digest) == 0 {
+		return nil, ErrImageNotFound
+	}
+
+	promotion := &models.Promotion{
+		PipelineID:       pipeline.ID,
+		Repository:       repository,
+		Tag:              tag,
+		Digest:           digest,
+		Stage:            index + 1,
+		TargetRepository: fmt.Sprintf("%s/%s", target.Name, repo),
+		Requester:        requester,
+	}
+	gates := gatesOf(pipeline, promotion.Stage)
+	checkErr := check(source, gates, promotion)
+	switch {
+	case checkErr != nil:
+		if _, ok := checkErr.(*GateError); !ok {
+			return nil, checkErr
+		}
+		promotion.Status = models.PromotionFailed
+		promotion.Message = checkErr.Error()
+	case gates.Approval:
+		promotion.Status = models.PromotionPending
+	default:
+		if err = execute(source, target, promotion, requester); err != nil {
+			promotion.Status = models.PromotionFailed
+			promotion.Message = err.Error()
+		} else {
+			promotion.Status = models.PromotionSucceeded
+		}
+	}
+	if promotion.ID, err = dao.AddPromotion(promotion); err != nil {
+		return nil, fmt.Errorf("failed to record the promotion of %s:%s: %v", repository, tag, err)
+	}
+	if promotion.Status == models.PromotionPending {
+		if _, err = approval.Submit(&models.Approval{
+			Type:       models.ApprovalPromotion,
+			ResourceID: promotion.ID,
+			ProjectID:  target.ProjectID,
+			Requester:  requester,
+			Summary:    fmt.Sprintf("Promote %s:%s into %s", repository, tag, promotion.TargetRepository),
+		}); err != nil {
+			log.Errorf("failed to submit the approval of promotion %d: %v", promotion.ID, err)
+		}
+	}
+	return promotion, checkErr
+}
+
+// Approve checks the gates of the pending promotion again and executes it
+func Approve(projectMgr projectManager, promotion *models.Promotion, approver string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	current, err := dao.GetPromotion(promotion.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Status != models.PromotionPending {
+		return ErrNotPending
+	}
+	pipeline, err := dao.GetPromotionPipeline(promotion.PipelineID)
+	if err != nil {
+		return err
+	}
+	if pipeline == nil || promotion.Stage >= len(pipeline.Stages) {
+		return complete(promotion, models.PromotionFailed, "the stage of the pipeline doesn't exist any more", approver)
+	}
+	stage := pipeline.Stages[promotion.Stage]
+	target, err := projectMgr.Get(stage.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project %d: %v", stage.ProjectID, err)
+	}
+	if target == nil {
+		return complete(promotion, models.PromotionFailed, "the project of the stage doesn't exist any more", approver)
+	}
+
+	source := NewSource(approver)
+	if err = check(source, gatesOf(pipeline, promotion.Stage), promotion); err != nil {
+		if _, ok := err.(*GateError); !ok {
+			return err
+		}
+		return complete(promotion, models.PromotionFailed, err.Error(), approver)
+	}
+	if err = execute(source, target, promotion, approver); err != nil {
+		return complete(promotion, models.PromotionFailed, err.Error(), approver)
+	}
+	return complete(promotion, models.PromotionSucceeded, "", approver)
+}
+
+// Reject rejects the pending promotion
+func Reject(promotion *models.Promotion, approver, message string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return complete(promotion, models.PromotionRejected, message, approver)
+}
+
+func complete(promotion *models.Promotion, status, message, approver string) error {
+	n, err := dao.UpdatePromotionStatus(promotion.ID, status, message, approver)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotPending
+	}
+	promotion.Status = status
+	promotion.Message = message
+	promotion.Approver = approver
+	return nil
+}
+
+func gatesOf(pipeline *models.PromotionPipeline, stage int) *models.PromotionGates {
+	if gates := pipeline.Stages[stage].Gates; gates != nil {
+		return gates
+	}
+	return &models.PromotionGates{}
+}
+
+// check checks the gates of the target stage, a *GateError is returned if the image doesn't pass them
+func check(source Source, gates *models.PromotionGates, promotion *models.Promotion) error {
+	if len(gates.Severity) > 0 {
+		overview, err := source.GetScanOverview(promotion.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to get the scan overview of %s: %v", promotion.Digest, err)
+		}
+		// the severity is 0 if the image isn't scanned successfully
+		if overview == nil || overview.Sev == 0 {
+			return gateFailed("the image %s:%s isn't scanned", promotion.Repository, promotion.Tag)
+		}
+		if threshold := clair.ParseClairSev(gates.Severity); overview.Sev >= int(threshold) {
+			return gateFailed("the severity of vulnerability of the image: %q is equal or higher than the threshold of the stage: %q",
+				models.Severity(overview.Sev), threshold)
+		}
+	}
+	if gates.Signed {
+		digests, err := source.GetSignedDigests(promotion.Repository)
+		if err != nil {
+			return fmt.Errorf("failed to get the signatures of %s: %v", promotion.Repository, err)
+		}
+		if digests[promotion.Tag] != promotion.Digest {
+			return gateFailed("the image %s:%s isn't signed", promotion.Repository, promotion.Tag)
+		}
+	}
+	return nil
+}
+
+// execute copies the image into the target repository, records the access log and notifies the subscribers
+func execute(source Source, target *models.Project, promotion *models.Promotion, username string) error {
+	if err := source.Copy(promotion.Repository, promotion.Digest, promotion.TargetRepository, promotion.Tag); err != nil {
+		return fmt.Errorf("failed to copy %s:%s into %s: %v", promotion.Repository, promotion.Tag, promotion.TargetRepository, err)
+	}
+	log.Infof("%s:%s is promoted into %s by %s", promotion.Repository, promotion.Tag, promotion.TargetRepository, username)
+	coreutils.AddProvenance(promotion.TargetRepository, promotion.Tag, promotion.Repository, promotion.Tag,
+		promotion.Digest, models.ProvenancePromote, username)
+
+	if err := accesslog.Add(models.AccessLog{
+		Username:  username,
+		ProjectID: target.ProjectID,
+		RepoName:  promotion.TargetRepository,
+		RepoTag:   promotion.Tag,
+		Operation: "promote",
+		OpTime:    time.Now(),
+	}); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
+	go notifier.NotifyRepoSubscribers(promotion.TargetRepository, models.SubscriptionEventPromotion,
+		fmt.Sprintf("Image %s:%s is promoted", promotion.TargetRepository, promotion.Tag),
+		fmt.Sprintf("The image %s:%s is promoted from %s into %s by %s.", promotion.TargetRepository,
+			promotion.Tag, promotion.Repository, promotion.TargetRepository, username))
+	return nil
+}
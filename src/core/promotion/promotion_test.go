@@ -0,0 +1,134 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promotion
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	digests   map[string]string
+	overviews map[string]*models.ImgScanOverview
+	signed    map[string]map[string]string
+	copied    []string
+}
+
+func (f *fakeSource) GetDigest(repository, tag string) (string, error) {
+	return f.digests[repository+":"+tag], nil
+}
+
+func (f *fakeSource) GetScanOverview(digest string) (*models.ImgScanOverview, error) {
+	return f.overviews[digest], nil
+}
+
+func (f *fakeSource) GetSignedDigests(repository string) (map[string]string, error) {
+	return f.signed[repository], nil
+}
+
+func (f *fakeSource) Copy(repository, digest, targetRepository, tag string) error {
+	f.copied = append(f.copied, targetRepository+":"+tag)
+	return nil
+}
+
+type fakeProjectManager struct {
+	projects []*models.Project
+}
+
+func (f *fakeProjectManager) Get(projectIDOrName interface{}) (*models.Project, error) {
+	for _, project := range f.projects {
+		if project.ProjectID == projectIDOrName || project.Name == projectIDOrName {
+			return project, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestCheck(t *testing.T) {
+	source := &fakeSource{
+		overviews: map[string]*models.ImgScanOverview{
+			"sha256:low":  {Sev: int(models.SevLow)},
+			"sha256:high": {Sev: int(models.SevHigh)},
+		},
+		signed: map[string]map[string]string{
+			"dev/hello": {"v1": "sha256:low"},
+		},
+	}
+	cases := []struct {
+		gates  *models.PromotionGates
+		digest string
+		tag    string
+		passed bool
+	}{
+		{&models.PromotionGates{}, "sha256:unscanned", "v1", true},
+		{&models.PromotionGates{Severity: models.SeverityHigh}, "sha256:unscanned", "v1", false},
+		{&models.PromotionGates{Severity: models.SeverityHigh}, "sha256:low", "v1", true},
+		{&models.PromotionGates{Severity: models.SeverityHigh}, "sha256:high", "v1", false},
+		{&models.PromotionGates{Signed: true}, "sha256:low", "v1", true},
+		{&models.PromotionGates{Signed: true}, "sha256:low", "v2", false},
+		{&models.PromotionGates{Signed: true}, "sha256:high", "v1", false},
+	}
+	for i, c := range cases {
+		err := check(source, c.gates, &models.Promotion{
+			Repository: "dev/hello",
+			Tag:        c.tag,
+			Digest:     c.digest,
+		})
+		if c.passed {
+			assert.Nil(t, err, "case %d", i)
+			continue
+		}
+		_, ok := err.(*GateError)
+		assert.True(t, ok, "case %d", i)
+	}
+}
+
+func TestPromoteInvalid(t *testing.T) {
+	source := &fakeSource{
+		digests: map[string]string{
+			"dev/hello:v1": "sha256:1",
+		},
+	}
+	NewSource = func(username string) Source {
+		return source
+	}
+	projectMgr := &fakeProjectManager{
+		projects: []*models.Project{
+			{ProjectID: 1, Name: "dev"},
+			{ProjectID: 2, Name: "prod"},
+			{ProjectID: 3, Name: "other"},
+		},
+	}
+	pipeline := &models.PromotionPipeline{
+		ID: 1,
+		Stages: []*models.PromotionStage{
+			{ProjectID: 1},
+			{ProjectID: 2},
+		},
+	}
+
+	_, err := Promote(projectMgr, pipeline, "other/hello", "v1", "admin")
+	assert.Equal(t, ErrNotInPipeline, err)
+	_, err = Promote(projectMgr, pipeline, "unknown/hello", "v1", "admin")
+	assert.Equal(t, ErrNotInPipeline, err)
+	_, err = Promote(projectMgr, pipeline, "prod/hello", "v1", "admin")
+	assert.Equal(t, ErrLastStage, err)
+	_, err = Promote(projectMgr, pipeline, "dev/hello", "v2", "admin")
+	assert.Equal(t, ErrImageNotFound, err)
+	require.Equal(t, 0, len(source.copied))
+}
@@ -0,0 +1,79 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promotion
+
+import (
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/notary"
+	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// harborSource reads the images from the registry, database and notary of Harbor,
+// the username is used to access notary
+type harborSource struct {
+	username string
+}
+
+func (h *harborSource) GetDigest(repository, tag string) (string, error) {
+	client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		return "", err
+	}
+	digest, exist, err := client.ManifestExist(tag)
+	if err != nil || !exist {
+		return "", err
+	}
+	return digest, nil
+}
+
+func (h *harborSource) GetScanOverview(digest string) (*models.ImgScanOverview, error) {
+	return dao.GetImgScanOverview(digest)
+}
+
+func (h *harborSource) GetSignedDigests(repository string) (map[string]string, error) {
+	if !config.WithNotary() {
+		return nil, nil
+	}
+	targets, err := notary.GetInternalTargets(config.InternalNotaryEndpoint(), h.username, repository)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	for _, target := range targets {
+		digest, err := notary.DigestFromTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		digests[target.Tag] = digest
+	}
+	return digests, nil
+}
+
+func (h *harborSource) Copy(repository, digest, targetRepository, tag string) error {
+	srcProject, srcRepo := utils.ParseRepository(repository)
+	destProject, destRepo := utils.ParseRepository(targetRepository)
+	return coreutils.Retag(&models.Image{
+		Project: srcProject,
+		Repo:    srcRepo,
+		Tag:     digest,
+	}, &models.Image{
+		Project: destProject,
+		Repo:    destRepo,
+		Tag:     tag,
+	})
+}
@@ -0,0 +1,222 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/cache"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/quota"
+	tokenutil "github.com/goharbor/harbor/src/core/service/token"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// the operations of the access logs recording the blocked requests
+const (
+	opPullBlocked = "pull_blocked"
+	opPushBlocked = "push_blocked"
+)
+
+// resolves the digest of the tag from the registry, empty is returned if the tag doesn't
+// exist, replaced in testing
+var resolveTagDigest = func(repository, tag string) (string, error) {
+	client, err := coreutils.NewRepositoryClientForUI(tokenUsername, repository)
+	if err != nil {
+		return "", err
+	}
+	digest, _, err := client.ManifestExist(tag)
+	return digest, err
+}
+
+// blockTarget is the digests requested by a pull or push
+type blockTarget struct {
+	repository string
+	reference  string
+	push       bool
+	digests    []string
+}
+
+// blocklistHandler refuses the pulls and pushes of the digests in the blocklist across all the
+// projects, the pushed manifests are checked with the blobs referenced by them. The refused
+// requests are recorded as access logs and posted to the blocklist webhook. It precedes the
+// handlers answering the requests by themselves, so it resolves the digests on its own.
+type blocklistHandler struct {
+	next http.Handler
+}
+
+func (bh blocklistHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	target, err := getBlockTarget(req)
+	if err != nil {
+		if req.Method == http.MethodPut {
+			// leave the invalid manifests to the registry
+			log.Debugf("failed to get the digests of the request %s: %v", req.URL.Path, err)
+			bh.next.ServeHTTP(rw, req)
+			return
+		}
+		log.Errorf("failed to get the digests of the request %s: %v", req.URL.Path, err)
+		http.Error(rw, marshalError("DENIED", "Failed to check the blocklist."), http.StatusInternalServerError)
+		return
+	}
+	if target == nil || len(target.digests) == 0 {
+		bh.next.ServeHTTP(rw, req)
+		return
+	}
+	blocked, err := dao.GetBlockedDigests(target.digests...)
+	if err != nil {
+		log.Errorf("failed to check the blocklist for %s: %v", target.repository, err)
+		http.Error(rw, marshalError("DENIED", "Failed to check the blocklist."), http.StatusInternalServerError)
+		return
+	}
+	if len(blocked) == 0 {
+		bh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	log.Warningf("the request %s %s is refused as the digest %s is blocked", req.Method, req.URL.Path, blocked[0].Digest)
+	go recordBlocked(target, blocked[0], requestUsername(req))
+	msg := fmt.Sprintf("The digest %s is blocked", blocked[0].Digest)
+	if len(blocked[0].Reason) > 0 {
+		msg += ": " + blocked[0].Reason
+	}
+	http.Error(rw, marshalError("DENIED", msg), http.StatusForbidden)
+}
+
+// getBlockTarget returns the digests requested by pulling the manifests or blobs, or by pushing
+// the manifests, uploading or mounting the blobs. Nil is returned for the other requests.
+func getBlockTarget(req *http.Request) (*blockTarget, error) {
+	if match, repository, reference := MatchManifest(req); match {
+		switch req.Method {
+		case http.MethodGet, http.MethodHead:
+			target := &blockTarget{
+				repository: repository,
+				reference:  reference,
+			}
+			if isDigest(reference) {
+				target.digests = append(target.digests, reference)
+				return target, nil
+			}
+			// the tags known by the manifest cache are resolved without requesting the registry
+			if manifest := cache.GetManifest(repository, reference); manifest != nil {
+				target.digests = append(target.digests, manifest.Digest)
+				return target, nil
+			}
+			digest, err := resolveTagDigest(repository, reference)
+			if err != nil {
+				return nil, err
+			}
+			if len(digest) > 0 {
+				target.digests = append(target.digests, digest)
+			}
+			return target, nil
+		case http.MethodPut:
+			data, err := ioutil.ReadAll(io.LimitReader(req.Body, maxManifestSize+1))
+			if err != nil {
+				return nil, err
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(data))
+			if len(data) > maxManifestSize {
+				return nil, fmt.Errorf("the manifest exceeds the max size %d bytes", maxManifestSize)
+			}
+			blobs, err := quota.Blobs(req.Header.Get("Content-Type"), data)
+			if err != nil {
+				return nil, err
+			}
+			target := &blockTarget{
+				repository: repository,
+				reference:  reference,
+				push:       true,
+			}
+			for _, blob := range blobs {
+				target.digests = append(target.digests, blob.Digest)
+			}
+			return target, nil
+		}
+		return nil, nil
+	}
+
+	if match, repository, digest := MatchPullBlob(req); match {
+		return &blockTarget{
+			repository: repository,
+			reference:  digest,
+			digests:    []string{digest},
+		}, nil
+	}
+
+	if match, repository, _ := MatchBlobUpload(req); match {
+		// the digest is in the query when the upload completes or the blob is mounted
+		query := req.URL.Query()
+		for _, key := range []string{"digest", "mount"} {
+			if digest := query.Get(key); len(digest) > 0 {
+				return &blockTarget{
+					repository: repository,
+					reference:  digest,
+					push:       true,
+					digests:    []string{digest},
+				}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// requestUsername returns the user of the bearer token issued by the token service
+func requestUsername(req *http.Request) string {
+	auth := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+	if len(auth) != 2 || !strings.EqualFold(auth[0], "Bearer") {
+		return ""
+	}
+	tk, err := tokenutil.VerifyToken(auth[1], tokenutil.Registry)
+	if err != nil {
+		return ""
+	}
+	return tk.Claims.Subject
+}
+
+// recordBlocked records the refused request as an access log of the project and posts the alert
+func recordBlocked(target *blockTarget, blocked *models.BlockedDigest, username string) {
+	op, event := opPullBlocked, notifier.BlocklistEventPullBlocked
+	if target.push {
+		op, event = opPushBlocked, notifier.BlocklistEventPushBlocked
+	}
+	now := time.Now()
+
+	projectName, _ := utils.ParseRepository(target.repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil {
+		log.Errorf("failed to get project %s: %v", projectName, err)
+	} else if project != nil {
+		if err = accesslog.Add(models.AccessLog{
+			Username:  username,
+			ProjectID: project.ProjectID,
+			RepoName:  target.repository,
+			RepoTag:   target.reference,
+			Operation: op,
+			OpTime:    now,
+		}); err != nil {
+			log.Errorf("failed to add access log: %v", err)
+		}
+	}
+
+	if err = notifier.Publish(notifier.BlocklistTopic, notifier.BlocklistEvent{
+		Event:      event,
+		Digest:     blocked.Digest,
+		Reason:     blocked.Reason,
+		Repository: target.repository,
+		Reference:  target.reference,
+		Username:   username,
+		OccurAt:    now,
+	}); err != nil {
+		log.Errorf("failed to publish the blocklist event of %s: %v", blocked.Digest, err)
+	}
+}
@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlockTarget(t *testing.T) {
+	layer := "sha256:ca4626b691f57d16ce1576231e4a2e2135554d32e13a85dcff380d51fdd13f6a"
+	config := "sha256:0a1b2c3d4e5f0a1b2c3d4e5f0a1b2c3d4e5f0a1b2c3d4e5f0a1b2c3d4e5f0a1b"
+
+	resolve := resolveTagDigest
+	defer func() {
+		resolveTagDigest = resolve
+	}()
+	resolveTagDigest = func(repository, tag string) (string, error) {
+		if repository == "library/ubuntu" && tag == "14.04" {
+			return layer, nil
+		}
+		return "", nil
+	}
+
+	// pull by tag, the digest is resolved from the registry
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	target, err := getBlockTarget(req)
+	require.Nil(t, err)
+	require.NotNil(t, target)
+	assert.False(t, target.push)
+	assert.Equal(t, "14.04", target.reference)
+	assert.Equal(t, []string{layer}, target.digests)
+
+	// pull the tag which doesn't exist
+	req, _ = http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/unknown", nil)
+	target, err = getBlockTarget(req)
+	require.Nil(t, err)
+	require.NotNil(t, target)
+	assert.Equal(t, 0, len(target.digests))
+
+	// pull the blob
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/"+layer, nil)
+	target, err = getBlockTarget(req)
+	require.Nil(t, err)
+	require.NotNil(t, target)
+	assert.Equal(t, []string{layer}, target.digests)
+
+	// push the manifest, the referenced blobs are checked as well
+	manifest, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeConfig,
+			Digest:    digest.Digest(config),
+		},
+		Layers: []distribution.Descriptor{
+			{
+				MediaType: schema2.MediaTypeLayer,
+				Digest:    digest.Digest(layer),
+			},
+		},
+	})
+	require.Nil(t, err)
+	_, payload, err := manifest.Payload()
+	require.Nil(t, err)
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", schema2.MediaTypeManifest)
+	target, err = getBlockTarget(req)
+	require.Nil(t, err)
+	require.NotNil(t, target)
+	assert.True(t, target.push)
+	assert.Equal(t, []string{digest.FromBytes(payload).String(), config, layer}, target.digests)
+	// the body is kept for the registry
+	data, err := ioutil.ReadAll(req.Body)
+	require.Nil(t, err)
+	assert.Equal(t, payload, data)
+
+	// mount the blob
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/?mount="+layer+"&from=library/hello", nil)
+	target, err = getBlockTarget(req)
+	require.Nil(t, err)
+	require.NotNil(t, target)
+	assert.True(t, target.push)
+	assert.Equal(t, []string{layer}, target.digests)
+
+	// initiate the upload
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", nil)
+	target, err = getBlockTarget(req)
+	require.Nil(t, err)
+	assert.Nil(t, target)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/_catalog", nil)
+	target, err = getBlockTarget(req)
+	require.Nil(t, err)
+	assert.Nil(t, target)
+}
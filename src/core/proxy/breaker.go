@@ -0,0 +1,102 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/core/config"
+	tokenutil "github.com/goharbor/harbor/src/core/service/token"
+)
+
+// the name of the breaker of the registry behind the proxy
+const registryBreakerName = "registry"
+
+// newRegistryTransport returns the transport of the proxy which stops sending the requests
+// once the registry or its storage is down, the breaker is closed when the probe listing the
+// catalog, which reads the storage unlike the base API, succeeds. The threshold and interval
+// of the breaker are configurable
+func newRegistryTransport(registryURL string) http.RoundTripper {
+	authorizer := auth.NewRawTokenAuthorizer("harbor-core", tokenutil.Registry)
+	probe := registry.ProbeURL(registry.NewTransport(http.DefaultTransport, authorizer),
+		strings.TrimSuffix(registryURL, "/")+"/v2/_catalog?n=1")
+	breaker := registry.GetBreaker(registryBreakerName, probe)
+	threshold, err := config.RegistryBreakerThreshold()
+	if err != nil {
+		log.Errorf("failed to get the threshold of the registry breaker, the default is used: %v", err)
+	}
+	interval, err := config.RegistryBreakerProbeInterval()
+	if err != nil {
+		log.Errorf("failed to get the probe interval of the registry breaker, the default is used: %v", err)
+	}
+	breaker.Configure(threshold, interval)
+	return registry.NewBreakerTransport(http.DefaultTransport, breaker)
+}
+
+// unavailableDetail is the detail of the error responded when the registry is unavailable
+type unavailableDetail struct {
+	Backend    string     `json:"backend"`
+	State      string     `json:"state"`
+	Since      *time.Time `json:"since,omitempty"`
+	Cause      string     `json:"cause,omitempty"`
+	RetryAfter int        `json:"retry_after,omitempty"`
+}
+
+// handleRegistryError responds 503 with the structured detail when the request can't be
+// passed to the registry, the requests canceled by the clients are only logged
+func handleRegistryError(rw http.ResponseWriter, req *http.Request, err error) {
+	if req.Context().Err() != nil {
+		log.Debugf("the request %s %s is canceled: %v", req.Method, req.URL.Path, err)
+		rw.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	detail := &unavailableDetail{
+		Backend: registryBreakerName,
+		State:   registry.BreakerStateClosed,
+		Cause:   err.Error(),
+	}
+	if e, ok := registry.IsBreakerOpen(err); ok {
+		detail.State = registry.BreakerStateOpen
+		detail.Since = &e.OpenTime
+		detail.Cause = e.LastError
+		// round up so that the clients don't retry before the next probe
+		detail.RetryAfter = int((e.RetryAfter + time.Second - 1) / time.Second)
+		rw.Header().Set("Retry-After", strconv.Itoa(detail.RetryAfter))
+	} else {
+		log.Errorf("failed to pass the request %s %s to the registry: %v", req.Method, req.URL.Path, err)
+	}
+	body, e := json.Marshal(map[string]interface{}{
+		"errors": []interface{}{
+			map[string]interface{}{
+				"code":    "UNAVAILABLE",
+				"message": "The registry is temporarily unavailable, please retry later.",
+				"detail":  detail,
+			},
+		},
+	})
+	if e != nil {
+		log.Errorf("failed to marshal the error: %v", e)
+	}
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.WriteHeader(http.StatusServiceUnavailable)
+	rw.Write(body)
+}
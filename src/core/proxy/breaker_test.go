@@ -0,0 +1,66 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRegistryError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v2/library/hello-world/manifests/latest", nil)
+
+	// the breaker is open
+	rw := httptest.NewRecorder()
+	handleRegistryError(rw, req, &url.Error{
+		Op:  "Get",
+		URL: "http://registry:5000/v2/",
+		Err: &registry.BreakerOpenError{
+			Name:       registryBreakerName,
+			OpenTime:   time.Now(),
+			LastError:  "503 | GET /v2/",
+			RetryAfter: 1500 * time.Millisecond,
+		},
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, "2", rw.Header().Get("Retry-After"))
+	body := struct {
+		Errors []struct {
+			Code   string             `json:"code"`
+			Detail *unavailableDetail `json:"detail"`
+		} `json:"errors"`
+	}{}
+	require.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body))
+	require.Equal(t, 1, len(body.Errors))
+	assert.Equal(t, "UNAVAILABLE", body.Errors[0].Code)
+	assert.Equal(t, registry.BreakerStateOpen, body.Errors[0].Detail.State)
+	assert.Equal(t, "503 | GET /v2/", body.Errors[0].Detail.Cause)
+	assert.NotNil(t, body.Errors[0].Detail.Since)
+
+	// the registry isn't reachable
+	rw = httptest.NewRecorder()
+	handleRegistryError(rw, req, errors.New("connection refused"))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, "", rw.Header().Get("Retry-After"))
+}
@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/cache"
+	"github.com/goharbor/harbor/src/core/notifier"
+)
+
+var (
+	// the functions listing the deprecations and publishing the hit, replaced in testing
+	listDeprecations = dao.ListRepoDeprecations
+	publishHit       = func(event notifier.DeprecationEvent) error {
+		return notifier.Publish(notifier.DeprecationTopic, event)
+	}
+	// the TTL of the cached deprecations, it bounds the delay of the changes made by the
+	// other instances of core when the cache isn't shared by them
+	deprecationCacheTTL = time.Minute
+)
+
+// getDeprecation returns the deprecation applied to the pulls of the reference in the same way
+// as dao.GetEffectiveRepoDeprecation, the tag's takes precedence over the repository's. The
+// deprecations of the repository are cached, so that the DB is queried only once they expire
+// in the cache or are invalidated by the changes
+func getDeprecation(repository, reference string) (*models.RepoDeprecation, error) {
+	deprecations, ok := cache.GetRepoDeprecations(repository)
+	if !ok {
+		var err error
+		if deprecations, err = listDeprecations(repository); err != nil {
+			return nil, err
+		}
+		if err = cache.SetRepoDeprecations(repository, deprecations, deprecationCacheTTL); err != nil {
+			log.Errorf("failed to cache the deprecations of %s: %v", repository, err)
+		}
+	}
+	var effective *models.RepoDeprecation
+	for _, deprecation := range deprecations {
+		if len(deprecation.Tag) == 0 {
+			effective = deprecation
+		} else if deprecation.Tag == reference {
+			return deprecation, nil
+		}
+	}
+	return effective, nil
+}
+
+// deprecationHandler attaches the warning to the pulls of the deprecated repositories and tags,
+// the pulls aren't refused. The successful ones are published as the hits of the deprecations.
+type deprecationHandler struct {
+	next http.Handler
+}
+
+func (dh deprecationHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, reference := MatchPullManifest(req)
+	if !match {
+		dh.next.ServeHTTP(rw, req)
+		return
+	}
+	deprecation, err := getDeprecation(repository, reference)
+	if err != nil {
+		log.Errorf("failed to get the deprecation of %s:%s: %v", repository, reference, err)
+		dh.next.ServeHTTP(rw, req)
+		return
+	}
+	if deprecation == nil {
+		dh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	rw.Header().Add("Warning", deprecation.Warning())
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	dh.next.ServeHTTP(sr, req)
+	if sr.status != http.StatusOK {
+		return
+	}
+	event := notifier.DeprecationEvent{
+		DeprecationID: deprecation.ID,
+		Repository:    repository,
+		Reference:     reference,
+		Username:      requestUsername(req),
+		OccurAt:       time.Now(),
+	}
+	if err = publishHit(event); err != nil {
+		log.Errorf("failed to publish the deprecation hit of %s:%s: %v", repository, reference, err)
+	}
+}
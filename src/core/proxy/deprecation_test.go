@@ -0,0 +1,122 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/cache"
+	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationHandler(t *testing.T) {
+	defer func(list func(string) ([]*models.RepoDeprecation, error),
+		publish func(notifier.DeprecationEvent) error) {
+		listDeprecations = list
+		publishHit = publish
+	}(listDeprecations, publishHit)
+	listed := 0
+	listDeprecations = func(repository string) ([]*models.RepoDeprecation, error) {
+		listed++
+		if repository != "library/old" {
+			return []*models.RepoDeprecation{}, nil
+		}
+		return []*models.RepoDeprecation{
+			{
+				ID:          1,
+				Repository:  repository,
+				Message:     "unmaintained",
+				Replacement: "library/new",
+			},
+			{
+				ID:         2,
+				Repository: repository,
+				Tag:        "1.0",
+				Message:    "vulnerable",
+			},
+		}, nil
+	}
+	for _, repository := range []string{"library/app", "library/old"} {
+		require.Nil(t, cache.InvalidateRepoDeprecations(repository))
+		defer cache.InvalidateRepoDeprecations(repository)
+	}
+	events := []notifier.DeprecationEvent{}
+	publishHit = func(event notifier.DeprecationEvent) error {
+		events = append(events, event)
+		return nil
+	}
+	status := http.StatusOK
+	handler := deprecationHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}),
+	}
+
+	// not deprecated
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/latest", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Header().Get("Warning"))
+
+	// the pull succeeds with the warning
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/old/manifests/latest", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `299 - "library/old is deprecated: unmaintained, use library/new instead"`, rw.Header().Get("Warning"))
+	require.Equal(t, 1, len(events))
+	assert.Equal(t, int64(1), events[0].DeprecationID)
+	assert.Equal(t, "latest", events[0].Reference)
+
+	// the deprecation of the tag takes precedence
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/old/manifests/1.0", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, `299 - "library/old:1.0 is deprecated: vulnerable"`, rw.Header().Get("Warning"))
+	require.Equal(t, 2, len(events))
+	assert.Equal(t, int64(2), events[1].DeprecationID)
+	events = events[:1]
+	// the deprecations are listed once per repository
+	assert.Equal(t, 2, listed)
+
+	// the failed pull isn't counted
+	status = http.StatusNotFound
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Equal(t, 1, len(events))
+
+	// the push isn't warned
+	status = http.StatusCreated
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/old/manifests/latest", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Empty(t, rw.Header().Get("Warning"))
+
+	// listed again once invalidated
+	require.Nil(t, cache.InvalidateRepoDeprecations("library/old"))
+	deprecation, err := getDeprecation("library/old", "latest")
+	require.Nil(t, err)
+	require.NotNil(t, deprecation)
+	assert.Equal(t, int64(1), deprecation.ID)
+	assert.Equal(t, 3, listed)
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the function reading the project, replaced in testing
+var getProject = func(name string) (*models.Project, error) {
+	return config.GlobalProjectMgr.Get(name)
+}
+
+// digestPullHandler rejects the pulls by tag of the repositories which are pulled by digest only
+// according to the policy of the project. The HEAD requests are rejected as well, as the
+// clients resolve the tags with them before pulling by digest.
+type digestPullHandler struct {
+	next http.Handler
+}
+
+func (dh digestPullHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, reference := MatchManifest(req)
+	// the tags can't contain ":", so the reference with it is a digest
+	if !match || (req.Method != http.MethodGet && req.Method != http.MethodHead) || strings.Contains(reference, ":") {
+		dh.next.ServeHTTP(rw, req)
+		return
+	}
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := getProject(projectName)
+	if err != nil || project == nil {
+		log.Errorf("failed to get project %s, skip checking the digest pull policy of %s:%s: %v", projectName, repository, reference, err)
+		dh.next.ServeHTTP(rw, req)
+		return
+	}
+	if !project.DigestPullRequired(repository) {
+		dh.next.ServeHTTP(rw, req)
+		return
+	}
+	log.Warningf("The pull of %s:%s is rejected as the repository can be pulled by digest only", repository, reference)
+	http.Error(rw, marshalError("DENIED", fmt.Sprintf("The repository %s can be pulled by digest only, pull it with %s@<digest> instead",
+		repository, repository)), http.StatusForbidden)
+}
@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestPullHandler(t *testing.T) {
+	defer func(f func(string) (*models.Project, error)) {
+		getProject = f
+	}(getProject)
+	getProject = func(name string) (*models.Project, error) {
+		project := &models.Project{
+			Name: name,
+		}
+		project.SetMetadata(models.ProMetaDigestPullRepos, "app/*")
+		return project, nil
+	}
+	handler := digestPullHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+	digest := "sha256:" + strings.Repeat("a", 64)
+	cases := []struct {
+		method string
+		url    string
+		code   int
+	}{
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/web/manifests/latest", http.StatusForbidden},
+		{http.MethodHead, "http://127.0.0.1:5000/v2/library/app/web/manifests/latest", http.StatusForbidden},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/web/manifests/" + digest, http.StatusOK},
+		{http.MethodPut, "http://127.0.0.1:5000/v2/library/app/web/manifests/latest", http.StatusOK},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/tools/manifests/latest", http.StatusOK},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/web/blobs/" + digest, http.StatusOK},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		assert.Equal(t, c.code, rw.Code, "%s %s", c.method, c.url)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// the function getting the active freeze window of the project, replaced in testing
+var activeFreezeWindow = coreutils.ActiveFreezeWindow
+
+// freezeHandler rejects the pushes and tag deletions of the projects in their freeze windows,
+// the uploads started before the window are allowed to finish but the manifests are rejected
+type freezeHandler struct {
+	next http.Handler
+}
+
+func (fh freezeHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository := matchFreezable(req)
+	if !match {
+		fh.next.ServeHTTP(rw, req)
+		return
+	}
+	projectName, _ := utils.ParseRepository(repository)
+	window, until, err := activeFreezeWindow(projectName)
+	if err != nil {
+		log.Errorf("failed to get the freeze windows of project %s: %v", projectName, err)
+		http.Error(rw, marshalError("DENIED", "Failed to check the freeze windows."), http.StatusInternalServerError)
+		return
+	}
+	if window == nil {
+		fh.next.ServeHTTP(rw, req)
+		return
+	}
+	log.Warningf("the request %s %s is rejected in the freeze window %d of project %s", req.Method,
+		req.URL.Path, window.ID, projectName)
+	http.Error(rw, marshalError("DENIED", window.GetMessage(until)), http.StatusForbidden)
+}
+
+// matchFreezable returns whether the request pushes or deletes the manifest, or starts
+// a blob upload, and the repository of it
+func matchFreezable(req *http.Request) (bool, string) {
+	if match, repository, _ := MatchManifest(req); match {
+		return req.Method == http.MethodPut || req.Method == http.MethodDelete, repository
+	}
+	if match, repository, uuid := MatchBlobUpload(req); match {
+		return req.Method == http.MethodPost && len(uuid) == 0, repository
+	}
+	return false, ""
+}
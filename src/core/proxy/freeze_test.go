@@ -0,0 +1,91 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchFreezable(t *testing.T) {
+	cases := []struct {
+		method string
+		url    string
+		match  bool
+	}{
+		{http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", true},
+		{http.MethodDelete, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/sha256:1", true},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", false},
+		{http.MethodPost, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", true},
+		{http.MethodPatch, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/1234-5678", false},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/sha256:1", false},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		match, repository := matchFreezable(req)
+		assert.Equal(t, c.match, match, "%s %s", c.method, c.url)
+		if match {
+			assert.Equal(t, "library/ubuntu", repository)
+		}
+	}
+}
+
+func TestFreezeHandler(t *testing.T) {
+	defer func(f func(string) (*models.FreezeWindow, time.Time, error)) {
+		activeFreezeWindow = f
+	}(activeFreezeWindow)
+	now := time.Now()
+	activeFreezeWindow = func(projectName string) (*models.FreezeWindow, time.Time, error) {
+		if projectName != "frozen" {
+			return nil, time.Time{}, nil
+		}
+		return &models.FreezeWindow{
+			ID:        1,
+			StartTime: now.Add(-time.Hour),
+			EndTime:   now.Add(time.Hour),
+			Repeat:    models.FreezeWindowRepeatNone,
+			Message:   "release 1.0",
+		}, now.Add(time.Hour), nil
+	}
+	handler := freezeHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}),
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/frozen/app/manifests/latest", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.True(t, strings.Contains(rw.Body.String(), "release 1.0"))
+
+	// the pulls are allowed
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/frozen/app/manifests/latest", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+
+	// the other projects aren't frozen
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/app/manifests/latest", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/goharbor/harbor/src/common/models"
 	notarytest "github.com/goharbor/harbor/src/common/utils/notary/test"
 	utilstest "github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/core/cache"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +16,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 var endpoint = "10.117.4.142"
@@ -30,10 +32,11 @@ func TestMain(m *testing.M) {
 	defer notaryServer.Close()
 	NotaryEndpoint = notaryServer.URL
 	var defaultConfig = map[string]interface{}{
-		common.ExtEndpoint:     "https://" + endpoint,
-		common.WithNotary:      true,
-		common.CfgExpiration:   5,
-		common.TokenExpiration: 30,
+		common.ExtEndpoint:      "https://" + endpoint,
+		common.WithNotary:       true,
+		common.CfgExpiration:    5,
+		common.TokenExpiration:  30,
+		common.ManifestCacheTTL: 300,
 	}
 	adminServer, err := utilstest.NewAdminserver(defaultConfig)
 	if err != nil {
@@ -46,6 +49,9 @@ func TestMain(m *testing.M) {
 	if err := config.Init(); err != nil {
 		panic(err)
 	}
+	if err := cache.Init(); err != nil {
+		panic(err)
+	}
 	adminserverClient = client.NewClient(adminServer.URL, nil)
 	result := m.Run()
 	if result != 0 {
@@ -94,6 +100,189 @@ func TestMatchPullManifest(t *testing.T) {
 	assert.Equal("sha256:ca4626b691f57d16ce1576231e4a2e2135554d32e13a85dcff380d51fdd13f6a", tag7)
 }
 
+func TestMatchBlobUpload(t *testing.T) {
+	assert := assert.New(t)
+	req1, _ := http.NewRequest("POST", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", nil)
+	res1, repo1, uuid1 := MatchBlobUpload(req1)
+	assert.True(res1)
+	assert.Equal("library/ubuntu", repo1)
+	assert.Equal("", uuid1)
+
+	req2, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/c7e1f2d4-9cd3-4f0c-8a4b-2f1a9e4e5b6c?digest=sha256:abc", nil)
+	res2, repo2, uuid2 := MatchBlobUpload(req2)
+	assert.True(res2)
+	assert.Equal("library/ubuntu", repo2)
+	assert.Equal("c7e1f2d4-9cd3-4f0c-8a4b-2f1a9e4e5b6c", uuid2)
+
+	req3, _ := http.NewRequest("GET", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/sha256:abc", nil)
+	res3, _, _ := MatchBlobUpload(req3)
+	assert.False(res3)
+}
+
+func TestUploadState(t *testing.T) {
+	rw := httptest.NewRecorder()
+	assert.Equal(t, "", uploadState(rw))
+
+	rw.Header().Set("Location", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/c7e1f2d4-9cd3-4f0c-8a4b-2f1a9e4e5b6c?_state=eyJOYW1lIjoibGlicmFyeS91YnVudHUifQ%3D%3D")
+	assert.Equal(t, "eyJOYW1lIjoibGlicmFyeS91YnVudHUifQ==", uploadState(rw))
+
+	// relative location
+	rw.Header().Set("Location", "/v2/library/ubuntu/blobs/uploads/c7e1f2d4-9cd3-4f0c-8a4b-2f1a9e4e5b6c?_state=abc")
+	assert.Equal(t, "abc", uploadState(rw))
+}
+
+func TestManifestCacheHandler(t *testing.T) {
+	repository := "library/ubuntu"
+	manifest := &cache.Manifest{
+		Digest:    "sha256:0a1b",
+		MediaType: "application/vnd.docker.distribution.manifest.v2+json",
+		Size:      524,
+	}
+	called := 0
+	status := http.StatusOK
+	handler := manifestCacheHandler{
+		next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			called++
+			rw.WriteHeader(status)
+		}),
+	}
+
+	// the request without a valid token is always forwarded
+	require.Nil(t, cache.SetManifest(repository, "14.04", manifest, time.Minute))
+	req, _ := http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 1, called)
+
+	// push the tag
+	status = http.StatusCreated
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 2, called)
+	assert.Nil(t, cache.GetManifest(repository, "14.04"))
+
+	// delete the manifest
+	require.Nil(t, cache.SetManifest(repository, "16.04", manifest, time.Minute))
+	status = http.StatusAccepted
+	req, _ = http.NewRequest(http.MethodDelete, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/"+manifest.Digest, nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 3, called)
+	assert.Nil(t, cache.GetManifest(repository, "16.04"))
+
+	// other requests
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/_catalog", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 4, called)
+}
+
+func TestRedirectSource(t *testing.T) {
+	listed := 0
+	sources := map[string]time.Time{
+		"library/renamed": time.Now().Add(time.Hour),
+		"library/expired": time.Now().Add(-time.Hour),
+	}
+	list := listRedirectSources
+	defer func() {
+		listRedirectSources = list
+		require.Nil(t, cache.InvalidateRedirectSources())
+	}()
+	listRedirectSources = func() (map[string]time.Time, error) {
+		listed++
+		return sources, nil
+	}
+	require.Nil(t, cache.InvalidateRedirectSources())
+
+	assert.True(t, redirectSource("library/renamed"))
+	assert.False(t, redirectSource("library/expired"))
+	assert.False(t, redirectSource("library/ubuntu"))
+	// listed only once
+	assert.Equal(t, 1, listed)
+
+	// the pulls of the repositories not renamed are passed without reading the redirects
+	called := 0
+	handler := repoRedirectHandler{
+		next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			called++
+		}),
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 1, called)
+	assert.Equal(t, 1, listed)
+
+	// renamed again
+	sources["library/ubuntu"] = time.Now().Add(time.Hour)
+	require.Nil(t, cache.InvalidateRedirectSources())
+	assert.True(t, redirectSource("library/ubuntu"))
+	assert.Equal(t, 2, listed)
+}
+
+func TestAcceptedMediaTypes(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	assert.Equal(t, "", acceptedMediaTypes(req))
+
+	req.Header.Add("Accept", "application/vnd.docker.distribution.manifest.v2+json, application/vnd.docker.distribution.manifest.list.v2+json")
+	req.Header.Add("Accept", "application/vnd.oci.image.manifest.v1+json")
+	other, _ := http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	other.Header.Add("Accept", "application/vnd.oci.image.manifest.v1+json,application/vnd.docker.distribution.manifest.list.v2+json")
+	other.Header.Add("Accept", "application/vnd.docker.distribution.manifest.v2+json")
+	assert.Equal(t, acceptedMediaTypes(req), acceptedMediaTypes(other))
+
+	other.Header.Del("Accept")
+	other.Header.Add("Accept", "application/vnd.docker.distribution.manifest.v2+json")
+	assert.NotEqual(t, acceptedMediaTypes(req), acceptedMediaTypes(other))
+}
+
+func TestPullAuthorized(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	assert.False(t, pullAuthorized(req, "library/ubuntu"))
+	req.SetBasicAuth("admin", "Harbor12345")
+	assert.False(t, pullAuthorized(req, "library/ubuntu"))
+	req.Header.Set("Authorization", "Bearer invalid")
+	assert.False(t, pullAuthorized(req, "library/ubuntu"))
+}
+
+func TestMatchRepoPull(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	match, repository := MatchRepoPull(req)
+	assert.True(t, match)
+	assert.Equal(t, "library/ubuntu", repository)
+
+	req, _ = http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/library/vmware/ubuntu/blobs/sha256:0a1b", nil)
+	match, repository = MatchRepoPull(req)
+	assert.True(t, match)
+	assert.Equal(t, "library/vmware/ubuntu", repository)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/tags/list", nil)
+	match, _ = MatchRepoPull(req)
+	assert.True(t, match)
+
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	match, _ = MatchRepoPull(req)
+	assert.False(t, match)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/_catalog", nil)
+	match, _ = MatchRepoPull(req)
+	assert.False(t, match)
+}
+
+func TestMatchPullBlob(t *testing.T) {
+	digest := "sha256:ca4626b691f57d16ce1576231e4a2e2135554d32e13a85dcff380d51fdd13f6a"
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/"+digest, nil)
+	match, repository, d := MatchPullBlob(req)
+	assert.True(t, match)
+	assert.Equal(t, "library/ubuntu", repository)
+	assert.Equal(t, digest, d)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", nil)
+	match, _, _ = MatchPullBlob(req)
+	assert.False(t, match)
+
+	req, _ = http.NewRequest(http.MethodDelete, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/"+digest, nil)
+	match, _, _ = MatchPullBlob(req)
+	assert.False(t, match)
+}
+
 func TestMatchListRepos(t *testing.T) {
 	assert := assert.New(t)
 	req1, _ := http.NewRequest("POST", "http://127.0.0.1:5000/v2/_catalog", nil)
@@ -205,3 +394,34 @@ func TestIsDigest(t *testing.T) {
 	assert.False(isDigest("latest"))
 	assert.True(isDigest("sha256:1359608115b94599e5641638bac5aef1ddfaa79bb96057ebf41ebc8d33acf8a7"))
 }
+
+func TestInFlightPush(t *testing.T) {
+	draining := &models.Maintenance{
+		Enabled:     true,
+		DrainPeriod: 300,
+		Since:       time.Now(),
+	}
+	drained := &models.Maintenance{
+		Enabled: true,
+		Since:   time.Now(),
+	}
+
+	// start a new upload
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", nil)
+	assert.False(t, inFlightPush(req, draining))
+
+	// continue the upload started before
+	req, _ = http.NewRequest(http.MethodPatch, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/a2fe4a2a-d5f0-4df8-a5a1-2f7b4a9b3c2e", nil)
+	assert.True(t, inFlightPush(req, drained))
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/a2fe4a2a-d5f0-4df8-a5a1-2f7b4a9b3c2e", nil)
+	assert.True(t, inFlightPush(req, drained))
+
+	// push the manifest
+	req, _ = http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	assert.True(t, inFlightPush(req, draining))
+	assert.False(t, inFlightPush(req, drained))
+
+	// delete the manifest
+	req, _ = http.NewRequest(http.MethodDelete, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/sha256:3e17b60ab9d92d953fb8ebefa25624c0d23fb95f78dde5572285d10158044059", nil)
+	assert.False(t, inFlightPush(req, draining))
+}
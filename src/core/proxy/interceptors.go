@@ -5,20 +5,30 @@ import (
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/clair"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/notary"
+	"github.com/goharbor/harbor/src/core/cache"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/promgr"
+	"github.com/goharbor/harbor/src/core/quota"
+	tokenutil "github.com/goharbor/harbor/src/core/service/token"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
 
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type contextKey string
@@ -26,7 +36,12 @@ type contextKey string
 const (
 	manifestURLPattern = `^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)manifests/([\w][\w.:-]{0,127})`
 	catalogURLPattern  = `/v2/_catalog`
+	blobUploadPattern  = `^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)blobs/uploads/([a-zA-Z0-9-_.=]*)$`
+	repoPullPattern    = `^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)(?:manifests|blobs|tags)/`
+	blobPullPattern    = `^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)blobs/(sha256:[a-f0-9]{64})$`
 	imageInfoCtxKey    = contextKey("ImageInfo")
+	// the max size of the manifests accepted by the registry
+	maxManifestSize = 4 << 20
 	// TODO: temp solution, remove after vmware/harbor#2242 is resolved.
 	tokenUsername = "harbor-core"
 )
@@ -37,12 +52,25 @@ var rec *httptest.ResponseRecorder
 // NotaryEndpoint , exported for testing.
 var NotaryEndpoint = ""
 
+var (
+	// the TTL of the cached sources of the redirects, it bounds the delay of the redirects added
+	// by the other instances of core when the cache isn't shared by them
+	redirectSourcesTTL = time.Minute
+	// the function listing the sources of the redirects, replaced in testing
+	listRedirectSources = dao.ListRepoRedirectSources
+)
+
 // MatchPullManifest checks if the request looks like a request to pull manifest.  If it is returns the image and tag/sha256 digest as 2nd and 3rd return values
 func MatchPullManifest(req *http.Request) (bool, string, string) {
 	// TODO: add user agent check.
 	if req.Method != http.MethodGet {
 		return false, "", ""
 	}
+	return MatchManifest(req)
+}
+
+// MatchManifest checks if the request is sent to a manifest regardless of the method. If it is returns the image and tag/sha256 digest as 2nd and 3rd return values
+func MatchManifest(req *http.Request) (bool, string, string) {
 	re := regexp.MustCompile(manifestURLPattern)
 	s := re.FindStringSubmatch(req.URL.Path)
 	if len(s) == 3 {
@@ -65,10 +93,52 @@ func MatchListRepos(req *http.Request) bool {
 	return false
 }
 
+// MatchBlobUpload checks if the request looks like a request of blob upload. If it is returns the repository
+// and the upload UUID as 2nd and 3rd return values, the UUID is empty when initiating the upload.
+func MatchBlobUpload(req *http.Request) (bool, string, string) {
+	re := regexp.MustCompile(blobUploadPattern)
+	s := re.FindStringSubmatch(req.URL.Path)
+	if len(s) == 3 {
+		s[1] = strings.TrimSuffix(s[1], "/")
+		return true, s[1], s[2]
+	}
+	return false, "", ""
+}
+
+// MatchRepoPull checks if the request looks like a request to pull the manifests, blobs or tags of
+// a repository. If it is returns the repository as the 2nd return value
+func MatchRepoPull(req *http.Request) (bool, string) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false, ""
+	}
+	re := regexp.MustCompile(repoPullPattern)
+	s := re.FindStringSubmatch(req.URL.Path)
+	if len(s) == 2 {
+		return true, strings.TrimSuffix(s[1], "/")
+	}
+	return false, ""
+}
+
+// MatchPullBlob checks if the request looks like a request to pull a blob. If it is returns the
+// repository and the digest as 2nd and 3rd return values
+func MatchPullBlob(req *http.Request) (bool, string, string) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false, "", ""
+	}
+	re := regexp.MustCompile(blobPullPattern)
+	s := re.FindStringSubmatch(req.URL.Path)
+	if len(s) == 3 {
+		return true, strings.TrimSuffix(s[1], "/"), s[2]
+	}
+	return false, "", ""
+}
+
 // policyChecker checks the policy of a project by project name, to determine if it's needed to check the image's status under this project.
 type policyChecker interface {
 	// contentTrustEnabled returns whether a project has enabled content trust.
 	contentTrustEnabled(name string) bool
+	// contentTrustRequired returns whether the image must be signed according to the content trust policy of the project.
+	contentTrustRequired(img imageInfo) bool
 	// vulnerablePolicy  returns whether a project has enabled vulnerable, and the project's severity.
 	vulnerablePolicy(name string) (bool, models.Severity)
 }
@@ -85,6 +155,14 @@ func (pc pmsPolicyChecker) contentTrustEnabled(name string) bool {
 	}
 	return project.ContentTrustEnabled()
 }
+func (pc pmsPolicyChecker) contentTrustRequired(img imageInfo) bool {
+	project, err := pc.pm.Get(img.projectName)
+	if err != nil {
+		log.Errorf("Unexpected error when getting the project, error: %v", err)
+		return true
+	}
+	return project.ContentTrustRequired(img.repository, img.reference)
+}
 func (pc pmsPolicyChecker) vulnerablePolicy(name string) (bool, models.Severity) {
 	project, err := pc.pm.Get(name)
 	if err != nil {
@@ -153,6 +231,46 @@ func (uh urlHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	uh.next.ServeHTTP(rw, req)
 }
 
+// maintenanceHandler drains the pushes when Harbor is in maintenance mode, the new uploads are rejected
+// while the upload sessions started before are allowed to finish, and the manifests are accepted during
+// the drain period so that the pushes in flight can complete. All the other modifications are rejected.
+type maintenanceHandler struct {
+	next http.Handler
+}
+
+func (mh maintenanceHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodDelete && req.Method != http.MethodPost &&
+		req.Method != http.MethodPatch && req.Method != http.MethodPut {
+		mh.next.ServeHTTP(rw, req)
+		return
+	}
+	maintenance, err := config.Maintenance()
+	if err != nil {
+		log.Errorf("failed to get the state of maintenance mode: %v", err)
+		mh.next.ServeHTTP(rw, req)
+		return
+	}
+	if !maintenance.Enabled || inFlightPush(req, maintenance) {
+		mh.next.ServeHTTP(rw, req)
+		return
+	}
+	log.Warningf("The request is rejected in maintenance mode, url is: %s", req.URL.Path)
+	if maintenance.RetryAfter > 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(maintenance.RetryAfter))
+	}
+	http.Error(rw, marshalError("UNAVAILABLE", maintenance.GetMessage()), http.StatusServiceUnavailable)
+}
+
+func inFlightPush(req *http.Request, maintenance *models.Maintenance) bool {
+	if match, _, uuid := MatchBlobUpload(req); match {
+		return len(uuid) > 0 && req.Method != http.MethodPost
+	}
+	if match, _, _ := MatchManifest(req); match {
+		return req.Method == http.MethodPut && maintenance.Draining(time.Now())
+	}
+	return false
+}
+
 type readonlyHandler struct {
 	next http.Handler
 }
@@ -168,6 +286,326 @@ func (rh readonlyHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	rh.next.ServeHTTP(rw, req)
 }
 
+// statusRecorder records the status code of the response without buffering the body
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	sr.status = code
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+// uploadHandler tracks the blob upload sessions, so that the sessions left by the
+// interrupted pushes can be purged.
+type uploadHandler struct {
+	next http.Handler
+}
+
+func (uh uploadHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, uuid := MatchBlobUpload(req)
+	if !match {
+		uh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	uh.next.ServeHTTP(sr, req)
+
+	var err error
+	switch {
+	// initiate the upload, the cross repository mounting returns 201 and doesn't start a session
+	case req.Method == http.MethodPost && sr.status == http.StatusAccepted:
+		uuid = rw.Header().Get("Docker-Upload-UUID")
+		if len(uuid) == 0 {
+			return
+		}
+		_, err = dao.AddUploadSession(&models.UploadSession{
+			UUID:       uuid,
+			Repository: repository,
+			State:      uploadState(rw),
+		})
+	case req.Method == http.MethodPatch && sr.status == http.StatusAccepted:
+		err = dao.TouchUploadSession(uuid, uploadState(rw))
+	// the upload is completed or canceled
+	case req.Method == http.MethodPut && sr.status == http.StatusCreated,
+		req.Method == http.MethodDelete && sr.status == http.StatusNoContent:
+		err = dao.DeleteUploadSession(uuid)
+	}
+	if err != nil {
+		log.Errorf("failed to track the upload session %s of repository %s: %v", uuid, repository, err)
+	}
+}
+
+// uploadState returns the "_state" parameter of the location of the upload response, the
+// registry requires it to resume or cancel the upload and it changes with every chunk
+func uploadState(rw http.ResponseWriter) string {
+	location, err := url.Parse(rw.Header().Get("Location"))
+	if err != nil {
+		return ""
+	}
+	return location.Query().Get("_state")
+}
+
+// quotaHandler rejects the pushes of manifests exceeding the storage quota or the size limits of
+// the project and records the blobs of the successful ones to track the storage usage.
+type quotaHandler struct {
+	next http.Handler
+}
+
+func (qh quotaHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, _ := MatchManifest(req)
+	if !match || req.Method != http.MethodPut {
+		qh.next.ServeHTTP(rw, req)
+		return
+	}
+	push, err := prepareQuota(req, repository)
+	if err == quota.ErrQuotaExceeded {
+		log.Warningf("The push of %s is rejected as it exceeds the storage quota", repository)
+		http.Error(rw, marshalError("DENIED", err.Error()), http.StatusForbidden)
+		return
+	}
+	if _, ok := err.(*quota.SizeLimitError); ok {
+		log.Warningf("The push of %s is rejected: %v", repository, err)
+		http.Error(rw, marshalError("DENIED", err.Error()), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		// the quota isn't enforced rather than blocking the pushes when the usage is unknown
+		log.Errorf("failed to check the storage quota of %s: %v", repository, err)
+		qh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	qh.next.ServeHTTP(sr, req)
+	if sr.status != http.StatusCreated {
+		return
+	}
+	if err = push.Commit(); err != nil {
+		log.Errorf("failed to record the storage usage of %s: %v", repository, err)
+	}
+}
+
+// prepareQuota reads the manifest from the request and checks it against the size limits and the
+// quota of the project
+func prepareQuota(req *http.Request, repository string) (*quota.Push, error) {
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project %s not found", projectName)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(req.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("the manifest exceeds the max size %d bytes", maxManifestSize)
+	}
+	mediaType := req.Header.Get("Content-Type")
+	blobs, err := quota.Blobs(mediaType, data)
+	if err != nil {
+		return nil, err
+	}
+	// the sizes declared in the manifest aren't trusted
+	if err = quota.Measure(mediaType, blobs, registryBlobSizer(repository)); err != nil {
+		return nil, err
+	}
+	if err = quota.CheckSizeLimits(project, blobs); err != nil {
+		return nil, err
+	}
+	return quota.Prepare(project, repository, blobs)
+}
+
+// registryBlobSizer returns the sizes of the blobs of the repository stored in the registry
+func registryBlobSizer(repository string) quota.BlobSizer {
+	client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository)
+	return func(digest string) (int64, error) {
+		if err != nil {
+			return 0, err
+		}
+		_, size, err := client.StatBlob(digest)
+		return size, err
+	}
+}
+
+// manifestCacheHandler serves the HEAD requests of the manifests referenced by tags from
+// the cache, as the orchestrators check the digests of the images frequently. The cache
+// is filled by the responses of the registry and invalidated when the manifests are pushed or deleted.
+type manifestCacheHandler struct {
+	next http.Handler
+}
+
+func (mh manifestCacheHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, reference := MatchManifest(req)
+	if !match {
+		mh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodHead:
+		mh.head(rw, req, repository, reference)
+	case http.MethodPut:
+		sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		mh.next.ServeHTTP(sr, req)
+		if sr.status == http.StatusCreated && !isDigest(reference) {
+			if err := cache.DeleteManifest(repository, reference); err != nil {
+				log.Errorf("failed to delete the cached manifest of %s:%s: %v", repository, reference, err)
+			}
+		}
+	case http.MethodDelete:
+		sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		mh.next.ServeHTTP(sr, req)
+		if sr.status == http.StatusAccepted {
+			if err := cache.InvalidateManifests(repository); err != nil {
+				log.Errorf("failed to invalidate the cached manifests of repository %s: %v", repository, err)
+			}
+		}
+	default:
+		mh.next.ServeHTTP(rw, req)
+	}
+}
+
+func (mh manifestCacheHandler) head(rw http.ResponseWriter, req *http.Request, repository, tag string) {
+	ttl, err := config.ManifestCacheTTL()
+	if err != nil {
+		log.Errorf("failed to get the TTL of manifest cache: %v", err)
+		mh.next.ServeHTTP(rw, req)
+		return
+	}
+	// the request must be authorized as it never reaches the registry once the manifest is cached
+	if ttl <= 0 || isDigest(tag) || !pullAuthorized(req, repository) {
+		mh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// the manifest cached for other media types isn't served, as the registry may
+	// negotiate a different one for the request
+	accept := acceptedMediaTypes(req)
+	if manifest := cache.GetManifest(repository, tag); manifest != nil && manifest.Accept == accept {
+		log.Debugf("serve the HEAD request of %s:%s from cache", repository, tag)
+		rw.Header().Set("Content-Type", manifest.MediaType)
+		rw.Header().Set("Content-Length", strconv.FormatInt(manifest.Size, 10))
+		rw.Header().Set("Docker-Content-Digest", manifest.Digest)
+		rw.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
+		rw.Header().Set("Etag", fmt.Sprintf(`"%s"`, manifest.Digest))
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	mh.next.ServeHTTP(sr, req)
+	if sr.status != http.StatusOK {
+		return
+	}
+	manifest := &cache.Manifest{
+		Digest:    rw.Header().Get("Docker-Content-Digest"),
+		MediaType: rw.Header().Get("Content-Type"),
+		Accept:    accept,
+	}
+	manifest.Size, err = strconv.ParseInt(rw.Header().Get("Content-Length"), 10, 64)
+	if err != nil || len(manifest.Digest) == 0 {
+		return
+	}
+	if err = cache.SetManifest(repository, tag, manifest, ttl); err != nil {
+		log.Errorf("failed to cache the manifest of %s:%s: %v", repository, tag, err)
+	}
+}
+
+// acceptedMediaTypes returns the media types accepted by the request in a canonical form
+func acceptedMediaTypes(req *http.Request) string {
+	types := []string{}
+	for _, header := range req.Header["Accept"] {
+		for _, t := range strings.Split(header, ",") {
+			if t = strings.TrimSpace(t); len(t) > 0 {
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Strings(types)
+	return strings.Join(types, ",")
+}
+
+// pullAuthorized checks whether the bearer token of the request, which is issued by the
+// token service, grants the pull permission of the repository
+func pullAuthorized(req *http.Request, repository string) bool {
+	auth := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+	if len(auth) != 2 || !strings.EqualFold(auth[0], "Bearer") {
+		return false
+	}
+	tk, err := tokenutil.VerifyToken(auth[1], tokenutil.Registry)
+	if err != nil {
+		log.Debugf("invalid token of the request for manifest of %s: %v", repository, err)
+		return false
+	}
+	for _, access := range tk.Claims.Access {
+		if access.Type != "repository" || access.Name != repository {
+			continue
+		}
+		for _, action := range access.Actions {
+			if action == "pull" || action == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redirectSource returns whether the repository is the old name of a renamed repository being
+// redirected. The sources of the redirects are cached, so that the DB is queried only once they
+// expire in the cache or are invalidated by the renaming
+func redirectSource(repository string) bool {
+	sources, ok := cache.GetRedirectSources()
+	if !ok {
+		var err error
+		if sources, err = listRedirectSources(); err != nil {
+			log.Errorf("failed to list the sources of the redirects: %v", err)
+			// look up the redirect of the repository directly
+			return true
+		}
+		if err = cache.SetRedirectSources(sources, redirectSourcesTTL); err != nil {
+			log.Errorf("failed to cache the sources of the redirects: %v", err)
+		}
+	}
+	expiration, exist := sources[repository]
+	return exist && expiration.After(time.Now())
+}
+
+// repoRedirectHandler redirects the pulls of the renamed repositories to the new names
+type repoRedirectHandler struct {
+	next http.Handler
+}
+
+func (rh repoRedirectHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository := MatchRepoPull(req)
+	if !match || !redirectSource(repository) {
+		rh.next.ServeHTTP(rw, req)
+		return
+	}
+	redirect, err := dao.GetRepoRedirect(repository)
+	if err != nil {
+		log.Errorf("failed to get the redirect of repository %s: %v", repository, err)
+		rh.next.ServeHTTP(rw, req)
+		return
+	}
+	// the old name may be used by a new repository
+	if redirect == nil || dao.RepositoryExists(repository) {
+		rh.next.ServeHTTP(rw, req)
+		return
+	}
+	u := *req.URL
+	u.Path = "/v2/" + redirect.NewName + strings.TrimPrefix(u.Path, "/v2/"+repository)
+	log.Debugf("redirect the request %s to %s", req.URL.Path, u.Path)
+	http.Redirect(rw, req, u.String(), http.StatusTemporaryRedirect)
+}
+
 type listReposHandler struct {
 	next http.Handler
 }
@@ -235,7 +673,7 @@ func (cth contentTrustHandler) ServeHTTP(rw http.ResponseWriter, req *http.Reque
 		cth.next.ServeHTTP(rw, req)
 		return
 	}
-	if !getPolicyChecker().contentTrustEnabled(img.projectName) {
+	if !getPolicyChecker().contentTrustRequired(img) {
 		cth.next.ServeHTTP(rw, req)
 		return
 	}
@@ -285,7 +723,7 @@ func (vh vulnerableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 		return
 	}
 	imageSev := overview.Sev
-	if imageSev >= int(projectVulnerableSeverity) {
+	if imageSev >= int(projectVulnerableSeverity) && !vulExempted(img.repository) {
 		log.Debugf("the image severity: %q is higher then project setting: %q, failing the response.", models.Severity(imageSev), projectVulnerableSeverity)
 		http.Error(rw, marshalError("PROJECT_POLICY_VIOLATION", fmt.Sprintf("The severity of vulnerability of the image: %q is equal or higher than the threshold in project setting: %q.", models.Severity(imageSev), projectVulnerableSeverity)), http.StatusPreconditionFailed)
 		return
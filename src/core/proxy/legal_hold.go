@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// the function counting the active legal holds, replaced in testing
+var countLegalHolds = dao.CountLegalHolds
+
+// legalHoldHandler rejects the deletions of the manifests in the repositories under legal hold.
+// The manifests are deleted by digest via the registry API, which isn't mapped to the held tags
+// without reading the manifests, so any active hold of the repository rejects the deletions
+type legalHoldHandler struct {
+	next http.Handler
+}
+
+func (lh legalHoldHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, _ := MatchManifest(req)
+	if !match || req.Method != http.MethodDelete {
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+	holds, err := countLegalHolds(&models.LegalHoldQuery{
+		Repository: repository,
+		Active:     true,
+	})
+	if err != nil {
+		log.Errorf("failed to count the legal holds of repository %s: %v", repository, err)
+		http.Error(rw, marshalError("DENIED", "Failed to check the legal holds."), http.StatusInternalServerError)
+		return
+	}
+	if holds == 0 {
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+	log.Warningf("the deletion %s is rejected as repository %s is under legal hold", req.URL.Path, repository)
+	http.Error(rw, marshalError("DENIED", fmt.Sprintf("The repository %s is under legal hold.", repository)),
+		http.StatusForbidden)
+}
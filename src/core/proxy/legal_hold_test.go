@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalHoldHandler(t *testing.T) {
+	defer func(f func(*models.LegalHoldQuery) (int64, error)) {
+		countLegalHolds = f
+	}(countLegalHolds)
+	countLegalHolds = func(query *models.LegalHoldQuery) (int64, error) {
+		if query.Repository == "library/held" && query.Active {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	handler := legalHoldHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	}
+
+	cases := []struct {
+		method string
+		url    string
+		code   int
+	}{
+		{http.MethodDelete, "http://127.0.0.1:5000/v2/library/held/manifests/sha256:1", http.StatusForbidden},
+		{http.MethodDelete, "http://127.0.0.1:5000/v2/library/app/manifests/sha256:1", http.StatusAccepted},
+		{http.MethodPut, "http://127.0.0.1:5000/v2/library/held/manifests/latest", http.StatusAccepted},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/held/manifests/latest", http.StatusAccepted},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		assert.Equal(t, c.code, rw.Code, "%s %s", c.method, c.url)
+	}
+}
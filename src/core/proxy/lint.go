@@ -0,0 +1,160 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/lint"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	// the functions reading the image from the registry and recording the findings, replaced in testing
+	inspectImage    = readImage
+	setArtifactLint = dao.SetArtifactLint
+)
+
+// lintHandler lints the images pushed by tags against the lint policy of the project. The findings
+// are recorded when the push succeeds, or the push is rejected if the policy requires so.
+// The images of other media types than the schema2 manifest aren't linted.
+type lintHandler struct {
+	next http.Handler
+}
+
+func (lh lintHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, tag := matchTagPush(req)
+	if !match {
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil || project == nil {
+		log.Errorf("failed to get project %s, skip linting %s:%s: %v", projectName, repository, tag, err)
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+	policy := project.LintPolicy()
+	if policy == nil {
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(req.Body, maxManifestSize+1))
+	if err != nil {
+		log.Errorf("failed to read the manifest of %s:%s: %v", repository, tag, err)
+		http.Error(rw, marshalError("UNKNOWN", "Failed to read the manifest."), http.StatusInternalServerError)
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	// the oversized manifests are rejected by the registry
+	if len(data) > maxManifestSize || req.Header.Get("Content-Type") != schema2.MediaTypeManifest {
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+	image, err := inspectImage(repository, tag, data)
+	if err != nil {
+		// the images are pushed without linting rather than blocking the pushes
+		log.Errorf("failed to inspect the image %s:%s, skip linting: %v", repository, tag, err)
+		lh.next.ServeHTTP(rw, req)
+		return
+	}
+	findings := lint.Lint(policy, image)
+	if policy.Reject && len(findings) > 0 {
+		log.Warningf("The push of %s:%s is rejected by the lint policy: %s", repository, tag, lint.Summary(findings))
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("The image violates the lint policy of project %s: %s",
+			projectName, lint.Summary(findings))), http.StatusForbidden)
+		return
+	}
+
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	lh.next.ServeHTTP(sr, req)
+	if sr.status != http.StatusCreated {
+		return
+	}
+	if err = setArtifactLint(&models.ArtifactLint{
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest.FromBytes(data).String(),
+		Findings:   findings,
+	}); err != nil {
+		log.Errorf("failed to record the lint findings of %s:%s: %v", repository, tag, err)
+	}
+}
+
+// matchTagPush returns whether the request pushes the manifest by tag, and the repository and the tag
+func matchTagPush(req *http.Request) (bool, string, string) {
+	match, repository, reference := MatchManifest(req)
+	if !match || req.Method != http.MethodPut || strings.HasPrefix(reference, "sha256:") {
+		return false, "", ""
+	}
+	return true, repository, reference
+}
+
+// readImage reads the config referenced by the manifest and the tags of the repository from the registry
+func readImage(repository, tag string, payload []byte) (*lint.Image, error) {
+	manifest := &schema2.DeserializedManifest{}
+	if err := manifest.UnmarshalJSON(payload); err != nil {
+		return nil, err
+	}
+	client, err := coreutils.NewRepositoryClientForUI(tokenUsername, repository)
+	if err != nil {
+		return nil, err
+	}
+	_, reader, err := client.PullBlob(manifest.Target().Digest.String())
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	config := &struct {
+		Config struct {
+			User   string            `json:"User"`
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}{}
+	if err = json.NewDecoder(reader).Decode(config); err != nil {
+		return nil, err
+	}
+	tags, err := client.ListTag()
+	if err != nil {
+		return nil, err
+	}
+	image := &lint.Image{
+		Tag:    tag,
+		Layers: len(manifest.Layers),
+		User:   config.Config.User,
+		Labels: config.Config.Labels,
+	}
+	for _, t := range tags {
+		if t != tag {
+			image.OtherTags = append(image.OtherTags, t)
+		}
+	}
+	return image, nil
+}
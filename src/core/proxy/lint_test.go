@@ -0,0 +1,45 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTagPush(t *testing.T) {
+	cases := []struct {
+		method string
+		url    string
+		match  bool
+	}{
+		{http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", true},
+		{http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/sha256:1", false},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", false},
+		{http.MethodDelete, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", false},
+		{http.MethodPost, "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", false},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		match, repository, tag := matchTagPush(req)
+		assert.Equal(t, c.match, match, "%s %s", c.method, c.url)
+		if match {
+			assert.Equal(t, "library/ubuntu", repository)
+			assert.Equal(t, "latest", tag)
+		}
+	}
+}
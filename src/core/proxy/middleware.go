@@ -0,0 +1,154 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Middleware wraps the next handler in the chain of the registry proxy, it either passes
+// the request to the next one or responds to it directly, e.g. rejects it
+type Middleware func(next http.Handler) http.Handler
+
+// the names of the built-in middlewares
+const (
+	MiddlewareTraffic       = "traffic"
+	MiddlewareMaintenance   = "maintenance"
+	MiddlewareReadonly      = "readonly"
+	MiddlewareFreeze        = "freeze"
+	MiddlewareLegalHold     = "legal_hold"
+	MiddlewareTagPolicy     = "tag_policy"
+	MiddlewareLint          = "lint"
+	MiddlewareQuota         = "quota"
+	MiddlewareDigestPull    = "digest_pull"
+	MiddlewareShareLink     = "share_link"
+	MiddlewareDeprecation   = "deprecation"
+	MiddlewareManifestCache = "manifest_cache"
+	MiddlewareRepoRedirect  = "repo_redirect"
+	MiddlewareURL           = "url"
+	MiddlewareBlocklist     = "blocklist"
+	MiddlewareListRepos     = "list_repos"
+	MiddlewareUpload        = "upload"
+	MiddlewareContentTrust  = "content_trust"
+	MiddlewareVulnerable    = "vulnerable"
+)
+
+// DefaultMiddlewares is the chain used when it isn't configured, in the order the requests pass through
+var DefaultMiddlewares = []string{
+	MiddlewareTraffic,
+	MiddlewareMaintenance,
+	MiddlewareReadonly,
+	MiddlewareBlocklist,
+	MiddlewareFreeze,
+	MiddlewareLegalHold,
+	MiddlewareTagPolicy,
+	MiddlewareLint,
+	MiddlewareQuota,
+	MiddlewareDigestPull,
+	MiddlewareShareLink,
+	MiddlewareDeprecation,
+	MiddlewareRepoRedirect,
+	MiddlewareURL,
+	MiddlewareManifestCache,
+	MiddlewareListRepos,
+	MiddlewareUpload,
+	MiddlewareContentTrust,
+	MiddlewareVulnerable,
+}
+
+var (
+	middlewaresLock sync.RWMutex
+	middlewares     = map[string]Middleware{
+		MiddlewareTraffic:       func(next http.Handler) http.Handler { return trafficHandler{next: next} },
+		MiddlewareMaintenance:   func(next http.Handler) http.Handler { return maintenanceHandler{next: next} },
+		MiddlewareReadonly:      func(next http.Handler) http.Handler { return readonlyHandler{next: next} },
+		MiddlewareFreeze:        func(next http.Handler) http.Handler { return freezeHandler{next: next} },
+		MiddlewareLegalHold:     func(next http.Handler) http.Handler { return legalHoldHandler{next: next} },
+		MiddlewareTagPolicy:     func(next http.Handler) http.Handler { return tagPolicyHandler{next: next} },
+		MiddlewareLint:          func(next http.Handler) http.Handler { return lintHandler{next: next} },
+		MiddlewareQuota:         func(next http.Handler) http.Handler { return quotaHandler{next: next} },
+		MiddlewareDigestPull:    func(next http.Handler) http.Handler { return digestPullHandler{next: next} },
+		MiddlewareShareLink:     func(next http.Handler) http.Handler { return shareLinkHandler{next: next} },
+		MiddlewareDeprecation:   func(next http.Handler) http.Handler { return deprecationHandler{next: next} },
+		MiddlewareManifestCache: func(next http.Handler) http.Handler { return manifestCacheHandler{next: next} },
+		MiddlewareRepoRedirect:  func(next http.Handler) http.Handler { return repoRedirectHandler{next: next} },
+		MiddlewareURL:           func(next http.Handler) http.Handler { return urlHandler{next: next} },
+		MiddlewareBlocklist:     func(next http.Handler) http.Handler { return blocklistHandler{next: next} },
+		MiddlewareListRepos:     func(next http.Handler) http.Handler { return listReposHandler{next: next} },
+		MiddlewareUpload:        func(next http.Handler) http.Handler { return uploadHandler{next: next} },
+		MiddlewareContentTrust:  func(next http.Handler) http.Handler { return contentTrustHandler{next: next} },
+		MiddlewareVulnerable:    func(next http.Handler) http.Handler { return vulnerableHandler{next: next} },
+	}
+	// the middlewares read the image info put into the context by the "url" one, so
+	// it must precede them in the chain. The cached manifests are served without reaching
+	// the following middlewares, so the blocklist must be checked before
+	dependencies = map[string]string{
+		MiddlewareContentTrust:  MiddlewareURL,
+		MiddlewareVulnerable:    MiddlewareURL,
+		MiddlewareManifestCache: MiddlewareBlocklist,
+	}
+)
+
+// Register registers the custom middleware with the name, so it can be put into the configured
+// chain. It's supposed to be called in the init function of the package compiled into core
+func Register(name string, middleware Middleware) error {
+	if len(name) == 0 {
+		return fmt.Errorf("empty name of middleware")
+	}
+	if middleware == nil {
+		return fmt.Errorf("nil middleware %s", name)
+	}
+	middlewaresLock.Lock()
+	defer middlewaresLock.Unlock()
+	if _, exist := middlewares[name]; exist {
+		return fmt.Errorf("middleware %s is registered already", name)
+	}
+	middlewares[name] = middleware
+	return nil
+}
+
+// buildChain chains the middlewares in the order of the names, the last one passes the
+// requests to the handler. The names must be registered and appear only once
+func buildChain(names []string, handler http.Handler) (http.Handler, error) {
+	middlewaresLock.RLock()
+	defer middlewaresLock.RUnlock()
+
+	positions := map[string]int{}
+	for i, name := range names {
+		if _, exist := middlewares[name]; !exist {
+			return nil, fmt.Errorf("unknown middleware %s", name)
+		}
+		if _, exist := positions[name]; exist {
+			return nil, fmt.Errorf("duplicate middleware %s", name)
+		}
+		positions[name] = i
+	}
+	for i, name := range names {
+		dependency, ok := dependencies[name]
+		if !ok {
+			continue
+		}
+		if position, exist := positions[dependency]; !exist || position > i {
+			return nil, fmt.Errorf("middleware %s must be preceded by %s", name, dependency)
+		}
+	}
+
+	for i := len(names) - 1; i >= 0; i-- {
+		handler = middlewares[names[i]](handler)
+	}
+	return handler, nil
+}
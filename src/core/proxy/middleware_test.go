@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func headerMiddleware(value string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Add("X-Middlewares", value)
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	assert.NotNil(t, Register("", headerMiddleware("a")))
+	assert.NotNil(t, Register("test-nil", nil))
+	assert.NotNil(t, Register(MiddlewareQuota, headerMiddleware("a")))
+	require.Nil(t, Register("test-register", headerMiddleware("a")))
+	assert.NotNil(t, Register("test-register", headerMiddleware("a")))
+}
+
+func TestBuildChain(t *testing.T) {
+	require.Nil(t, Register("test-a", headerMiddleware("a")))
+	require.Nil(t, Register("test-b", headerMiddleware("b")))
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusAccepted)
+	})
+
+	_, err := buildChain([]string{"test-a", "non-existing"}, handler)
+	assert.NotNil(t, err)
+	_, err = buildChain([]string{"test-a", "test-a"}, handler)
+	assert.NotNil(t, err)
+	// the dependency is missing or after the middleware
+	_, err = buildChain([]string{MiddlewareVulnerable}, handler)
+	assert.NotNil(t, err)
+	_, err = buildChain([]string{MiddlewareContentTrust, MiddlewareURL}, handler)
+	assert.NotNil(t, err)
+	_, err = buildChain([]string{MiddlewareURL, MiddlewareManifestCache, MiddlewareBlocklist}, handler)
+	assert.NotNil(t, err)
+	// the blocklist doesn't depend on the url one
+	_, err = buildChain([]string{MiddlewareBlocklist, MiddlewareManifestCache}, handler)
+	assert.Nil(t, err)
+
+	_, err = buildChain(DefaultMiddlewares, handler)
+	require.Nil(t, err)
+
+	chain, err := buildChain([]string{"test-b", "test-a"}, handler)
+	require.Nil(t, err)
+	rw := httptest.NewRecorder()
+	chain.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v2/", nil))
+	assert.Equal(t, http.StatusAccepted, rw.Code)
+	assert.Equal(t, []string{"b", "a"}, rw.Header()["X-Middlewares"])
+
+	// no middleware
+	chain, err = buildChain(nil, handler)
+	require.Nil(t, err)
+	rw = httptest.NewRecorder()
+	chain.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v2/", nil))
+	assert.Equal(t, http.StatusAccepted, rw.Code)
+	assert.Empty(t, rw.Header()["X-Middlewares"])
+}
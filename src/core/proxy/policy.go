@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// CheckImagePolicy checks the image against the content trust and vulnerability policies of
+// the project as the pulling does, the reason is returned if the image violates the policies
+func CheckImagePolicy(repository, reference, digest string) (string, error) {
+	projectName, _ := utils.ParseRepository(repository)
+	img := imageInfo{
+		repository:  repository,
+		reference:   reference,
+		projectName: projectName,
+		digest:      digest,
+	}
+	checker := getPolicyChecker()
+
+	if config.WithNotary() && checker.contentTrustRequired(img) {
+		match, err := matchNotaryDigest(img)
+		if err != nil {
+			return "", err
+		}
+		if !match {
+			return "The image is not signed in Notary.", nil
+		}
+	}
+
+	if !config.WithClair() {
+		return "", nil
+	}
+	enabled, severity := checker.vulnerablePolicy(projectName)
+	if !enabled {
+		return "", nil
+	}
+	overview, err := dao.GetImgScanOverview(digest)
+	if err != nil {
+		return "", err
+	}
+	// severity is 0 means that the image fails to scan or not scanned successfully.
+	if overview == nil || overview.Sev == 0 {
+		return "Cannot get the image severity.", nil
+	}
+	if overview.Sev >= int(severity) {
+		return fmt.Sprintf("The severity of vulnerability of the image: %q is equal or higher than the threshold in project setting: %q.",
+			models.Severity(overview.Sev), severity), nil
+	}
+	return "", nil
+}
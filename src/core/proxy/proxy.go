@@ -1,12 +1,14 @@
 package proxy
 
 import (
+	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
 
 	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 )
 
 // Proxy is the instance of the reverse proxy in this package.
@@ -38,7 +40,18 @@ func Init(urls ...string) error {
 		return err
 	}
 	Proxy = httputil.NewSingleHostReverseProxy(targetURL)
-	handlers = handlerChain{head: readonlyHandler{next: urlHandler{next: listReposHandler{next: contentTrustHandler{next: vulnerableHandler{next: Proxy}}}}}}
+	Proxy.Transport = newRegistryTransport(registryURL)
+	Proxy.ErrorHandler = handleRegistryError
+	names := config.RegistryProxyMiddlewares()
+	if len(names) == 0 {
+		names = DefaultMiddlewares
+	}
+	head, err := buildChain(names, Proxy)
+	if err != nil {
+		return fmt.Errorf("invalid middlewares of the registry proxy: %v", err)
+	}
+	handlers = handlerChain{head: head}
+	log.Infof("the middlewares of the registry proxy: %s", strings.Join(names, ", "))
 	return nil
 }
 
@@ -0,0 +1,217 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+var (
+	listTagsPattern = regexp.MustCompile(`^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)tags/list$`)
+
+	// the functions reading the user of the request and the share link, listing the digests
+	// granted by the link and counting the pulls, replaced in testing
+	shareLinkUsername = requestUsername
+	getShareLink      = dao.GetShareLink
+	shareLinkGrants   = cachedShareLinkGrants
+	addShareLinkPull  = dao.AddShareLinkPull
+
+	// the digests granted by the share links keyed by the IDs, they're kept until the links
+	// expire as the content referenced by a digest never changes
+	grantLock   sync.Mutex
+	grantsCache = map[int64]*shareLinkGrant{}
+)
+
+// shareLinkGrant is the digest a share link is bound to and all the digests it grants
+type shareLinkGrant struct {
+	expiresAt time.Time
+	digest    string
+	digests   map[string]bool
+}
+
+// shareLinkHandler narrows the pulls of the share links down to the shared image. The registry
+// token of a share link grants the pull of the whole repository, so only the manifest the link
+// is bound to, the manifests it references when it's a manifest list and their blobs are passed.
+// The pulls of the shared tag are redirected to the bound digest, so moving the tag doesn't
+// share another image. The successful pulls of the shared reference are counted as the usage
+// of the link.
+type shareLinkHandler struct {
+	next http.Handler
+}
+
+func (sh shareLinkHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, _, reference := MatchManifest(req)
+	manifest := match && (req.Method == http.MethodGet || req.Method == http.MethodHead)
+	if !manifest {
+		match, _, reference = MatchPullBlob(req)
+		if !match {
+			match = req.Method == http.MethodGet && listTagsPattern.MatchString(req.URL.Path)
+			reference = ""
+		}
+	}
+	if !manifest && !match {
+		sh.next.ServeHTTP(rw, req)
+		return
+	}
+	id, ok := models.ParseShareLinkUsername(shareLinkUsername(req))
+	if !ok {
+		sh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	link, err := getShareLink(id)
+	if err != nil {
+		log.Errorf("failed to get the share link %d: %v", id, err)
+		http.Error(rw, marshalError("DENIED", "Failed to check the share link."), http.StatusInternalServerError)
+		return
+	}
+	if link == nil || !link.Active(time.Now()) {
+		http.Error(rw, marshalError("DENIED", "The share link is revoked or expired."), http.StatusForbidden)
+		return
+	}
+	granted := false
+	if len(reference) > 0 {
+		grant, err := shareLinkGrants(link)
+		if err != nil {
+			log.Errorf("failed to get the digests granted by the share link %d: %v", id, err)
+			http.Error(rw, marshalError("DENIED", "Failed to check the share link."), http.StatusInternalServerError)
+			return
+		}
+		if manifest && reference == link.Reference && len(grant.digest) > 0 {
+			req.URL.Path = strings.TrimSuffix(req.URL.Path, reference) + grant.digest
+			granted = true
+		} else {
+			granted = grant.digests[reference]
+		}
+	}
+	if !granted {
+		log.Warningf("the request %s %s of the share link %d is rejected", req.Method, req.URL.Path, id)
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("The share link only grants the pull of %s:%s.",
+			link.Repository, link.Reference)), http.StatusForbidden)
+		return
+	}
+	if !manifest || reference != link.Reference || req.Method != http.MethodGet {
+		sh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	sh.next.ServeHTTP(sr, req)
+	if sr.status != http.StatusOK {
+		return
+	}
+	if err = addShareLinkPull(id, time.Now()); err != nil {
+		log.Errorf("failed to count the pull of the share link %d: %v", id, err)
+	}
+}
+
+// cachedShareLinkGrants returns the digests granted by the share link from the cache, the
+// expired links are dropped from the cache when a new one is added
+func cachedShareLinkGrants(link *models.ShareLink) (*shareLinkGrant, error) {
+	grantLock.Lock()
+	grant, ok := grantsCache[link.ID]
+	grantLock.Unlock()
+	if ok {
+		return grant, nil
+	}
+	grant, err := listShareLinkGrants(link)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	grantLock.Lock()
+	defer grantLock.Unlock()
+	for id, g := range grantsCache {
+		if !now.Before(g.expiresAt) {
+			delete(grantsCache, id)
+		}
+	}
+	grantsCache[link.ID] = grant
+	return grant, nil
+}
+
+// listShareLinkGrants returns the digest the share link is bound to, the manifests it references
+// if it's a manifest list and the blobs referenced by them. The links created before being bound
+// to the digests are bound to the current digests of their references when they're used
+func listShareLinkGrants(link *models.ShareLink) (*shareLinkGrant, error) {
+	client, err := coreutils.NewRepositoryClientForUI(tokenUsername, link.Repository)
+	if err != nil {
+		return nil, err
+	}
+	dgt := link.Digest
+	if len(dgt) == 0 {
+		d, exist, err := client.ManifestExist(link.Reference)
+		if err != nil {
+			return nil, err
+		}
+		// nothing is granted as the shared image is gone
+		if !exist {
+			return &shareLinkGrant{
+				expiresAt: link.ExpiresAt,
+				digests:   map[string]bool{},
+			}, nil
+		}
+		dgt = d
+	}
+	grant := &shareLinkGrant{
+		expiresAt: link.ExpiresAt,
+		digest:    dgt,
+		digests:   map[string]bool{dgt: true},
+	}
+	if err = addReferences(client, dgt, grant.digests, true); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// addReferences adds the digests referenced by the manifest, the manifests referenced by
+// a manifest list are followed if nested is true
+func addReferences(client *registry.Repository, dgt string, digests map[string]bool, nested bool) error {
+	accepted := []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest, manifestlist.MediaTypeManifestList}
+	_, mediaType, payload, err := client.PullManifest(dgt, accepted)
+	if err != nil {
+		return fmt.Errorf("failed to pull the manifest %s: %v", dgt, err)
+	}
+	if strings.Contains(mediaType, "application/json") {
+		mediaType = schema1.MediaTypeManifest
+	}
+	manifest, _, err := registry.UnMarshal(mediaType, payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse the manifest %s: %v", dgt, err)
+	}
+	for _, ref := range manifest.References() {
+		digests[ref.Digest.String()] = true
+		if nested && mediaType == manifestlist.MediaTypeManifestList {
+			if err = addReferences(client, ref.Digest.String(), digests, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareLinkHandler(t *testing.T) {
+	bound := "sha256:" + strings.Repeat("a", 64)
+	layer := "sha256:" + strings.Repeat("b", 64)
+	other := "sha256:" + strings.Repeat("c", 64)
+	defer func(u func(*http.Request) string, g func(int64) (*models.ShareLink, error),
+		gr func(*models.ShareLink) (*shareLinkGrant, error), a func(int64, time.Time) error) {
+		shareLinkUsername, getShareLink, shareLinkGrants, addShareLinkPull = u, g, gr, a
+	}(shareLinkUsername, getShareLink, shareLinkGrants, addShareLinkPull)
+	shareLinkUsername = func(req *http.Request) string {
+		return req.Header.Get("X-Test-User")
+	}
+	getShareLink = func(id int64) (*models.ShareLink, error) {
+		switch id {
+		case 1:
+			return &models.ShareLink{ID: 1, Repository: "library/app", Reference: "1.0",
+				Digest: bound, ExpiresAt: time.Now().Add(time.Hour)}, nil
+		case 2:
+			return &models.ShareLink{ID: 2, Repository: "library/app", Reference: "1.0",
+				ExpiresAt: time.Now().Add(time.Hour), Revoked: true}, nil
+		}
+		return nil, nil
+	}
+	shareLinkGrants = func(link *models.ShareLink) (*shareLinkGrant, error) {
+		return &shareLinkGrant{
+			digest:  bound,
+			digests: map[string]bool{bound: true, layer: true},
+		}, nil
+	}
+	pulls := 0
+	addShareLinkPull = func(id int64, t time.Time) error {
+		pulls++
+		return nil
+	}
+	paths := []string{}
+	handler := shareLinkHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+
+	cases := []struct {
+		method string
+		url    string
+		user   string
+		code   int
+	}{
+		// not a share link
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/2.0", "admin", http.StatusOK},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/tags/list", "", http.StatusOK},
+		// the shared tag, the bound digest and the blobs it references
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/1.0", "share$1", http.StatusOK},
+		{http.MethodHead, "http://127.0.0.1:5000/v2/library/app/manifests/1.0", "share$1", http.StatusOK},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/" + bound, "share$1", http.StatusOK},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/blobs/" + layer, "share$1", http.StatusOK},
+		// the other digests, the other tags and the listing of tags
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/" + other, "share$1", http.StatusForbidden},
+		{http.MethodHead, "http://127.0.0.1:5000/v2/library/app/manifests/" + other, "share$1", http.StatusForbidden},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/blobs/" + other, "share$1", http.StatusForbidden},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/2.0", "share$1", http.StatusForbidden},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/tags/list", "share$1", http.StatusForbidden},
+		// revoked and deleted
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/1.0", "share$2", http.StatusForbidden},
+		{http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/1.0", "share$3", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		req.Header.Set("X-Test-User", c.user)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		assert.Equal(t, c.code, rw.Code, "%s %s %s", c.method, c.url, c.user)
+	}
+	// only the GET of the shared tag is counted
+	assert.Equal(t, 1, pulls)
+	// the shared tag is pulled by the bound digest
+	assert.Equal(t, "/v2/library/app/manifests/"+bound, paths[2])
+	assert.Equal(t, "/v2/library/app/manifests/"+bound, paths[3])
+}
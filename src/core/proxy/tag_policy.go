@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the function reading the alias, replaced in testing
+var getTagAlias = dao.GetTagAlias
+
+// tagPolicyHandler rejects the pushes of the tags violating the tag policy of the project and
+// the ones of the alias tags, which are updated only by the API
+type tagPolicyHandler struct {
+	next http.Handler
+}
+
+func (th tagPolicyHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository, tag := matchTagPush(req)
+	if !match {
+		th.next.ServeHTTP(rw, req)
+		return
+	}
+	alias, err := getTagAlias(repository, tag)
+	if err != nil {
+		log.Errorf("failed to get the alias %s:%s: %v", repository, tag, err)
+		http.Error(rw, marshalError("DENIED", "Failed to check the alias tags."), http.StatusInternalServerError)
+		return
+	}
+	if alias != nil {
+		log.Warningf("The push of %s:%s is rejected as it is an alias of %s", repository, tag, alias.Tag)
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("The tag %s is an alias managed by Harbor, it can be updated by the API only",
+			tag)), http.StatusForbidden)
+		return
+	}
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil || project == nil {
+		log.Errorf("failed to get project %s, skip checking the tag policy of %s:%s: %v", projectName, repository, tag, err)
+		th.next.ServeHTTP(rw, req)
+		return
+	}
+	policy := project.TagPolicy()
+	if policy == nil {
+		th.next.ServeHTTP(rw, req)
+		return
+	}
+	if err = policy.Check(tag); err != nil {
+		log.Warningf("The push of %s:%s is rejected by the tag policy: %v", repository, tag, err)
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("The tag violates the tag policy of project %s: %v",
+			projectName, err)), http.StatusForbidden)
+		return
+	}
+	th.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagPolicyHandlerOfAlias(t *testing.T) {
+	defer func(f func(string, string) (*models.TagAlias, error)) {
+		getTagAlias = f
+	}(getTagAlias)
+	getTagAlias = func(repository, alias string) (*models.TagAlias, error) {
+		if alias != "stable" {
+			return nil, nil
+		}
+		return &models.TagAlias{
+			Repository: repository,
+			Alias:      alias,
+			Tag:        "v1.0",
+		}, nil
+	}
+	handler := tagPolicyHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}),
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/app/manifests/stable", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.True(t, strings.Contains(rw.Body.String(), "alias"))
+
+	// the pulls of the alias aren't affected
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/library/app/manifests/stable", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+}
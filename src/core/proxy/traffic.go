@@ -0,0 +1,87 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/core/traffic"
+)
+
+var (
+	// the requests to a repository share the pattern with the pulls regardless of the method
+	repoRequestRe = regexp.MustCompile(repoPullPattern)
+	// the function recording the traffic, replaced in testing
+	recordTraffic = traffic.Record
+)
+
+// MatchRepoRequest checks if the request is sent to the manifests, blobs or tags of a
+// repository regardless of the method. If it is returns the repository as the 2nd return value
+func MatchRepoRequest(req *http.Request) (bool, string) {
+	s := repoRequestRe.FindStringSubmatch(req.URL.Path)
+	if len(s) == 2 {
+		return true, strings.TrimSuffix(s[1], "/")
+	}
+	return false, ""
+}
+
+// trafficHandler records the count, the errors and the bytes served of the requests to
+// the registry API per repository, including the ones refused by the other handlers except
+// the unauthenticated ones, see traffic.Record
+type trafficHandler struct {
+	next http.Handler
+}
+
+func (th trafficHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match, repository := MatchRepoRequest(req)
+	if !match {
+		th.next.ServeHTTP(rw, req)
+		return
+	}
+	recorder := &trafficRecorder{
+		ResponseWriter: rw,
+		status:         http.StatusOK,
+	}
+	th.next.ServeHTTP(recorder, req)
+	recordTraffic(repository, recorder.status, recorder.bytes, time.Now())
+}
+
+// trafficRecorder records the status code and the size of the response without buffering the body
+type trafficRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (tr *trafficRecorder) WriteHeader(code int) {
+	tr.status = code
+	tr.ResponseWriter.WriteHeader(code)
+}
+
+func (tr *trafficRecorder) Write(data []byte) (int, error) {
+	n, err := tr.ResponseWriter.Write(data)
+	tr.bytes += int64(n)
+	return n, err
+}
+
+// Flush flushes the response of the reverse proxy if the underlying writer supports it
+func (tr *trafficRecorder) Flush() {
+	if flusher, ok := tr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/core/traffic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRepoRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPut, "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", nil)
+	match, repository := MatchRepoRequest(req)
+	assert.True(t, match)
+	assert.Equal(t, "library/ubuntu", repository)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/_catalog", nil)
+	match, _ = MatchRepoRequest(req)
+	assert.False(t, match)
+}
+
+func TestTrafficHandler(t *testing.T) {
+	type recorded struct {
+		repository string
+		status     int
+		bytes      int64
+	}
+	records := []recorded{}
+	recordTraffic = func(repository string, status int, bytes int64, _ time.Time) {
+		records = append(records, recorded{repository, status, bytes})
+	}
+	defer func() {
+		recordTraffic = traffic.Record
+	}()
+
+	handler := trafficHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("0123456789"))
+		}),
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/traffic-test/app/blobs/sha256:1", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "0123456789", rw.Body.String())
+
+	req, _ = http.NewRequest(http.MethodHead, "http://127.0.0.1:5000/v2/traffic-test/app/manifests/latest", nil)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+
+	// not the request to a repository
+	req, _ = http.NewRequest(http.MethodGet, "http://127.0.0.1:5000/v2/_catalog", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []recorded{
+		{"traffic-test/app", http.StatusOK, 10},
+		{"traffic-test/app", http.StatusNotFound, 0},
+	}, records)
+}
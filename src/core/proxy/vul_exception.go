@@ -0,0 +1,39 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// the function reading the unexpired exception of the repository, replaced in testing
+var getActiveVulException = dao.GetActiveVulException
+
+// vulExempted returns whether the repository is exempted from the policy "prevent vulnerable
+// images from running" of its project, the pulls are prevented if the exception can't be read
+func vulExempted(repository string) bool {
+	exception, err := getActiveVulException(repository)
+	if err != nil {
+		log.Errorf("failed to get the vulnerability exception of repository %s: %v", repository, err)
+		return false
+	}
+	if exception == nil {
+		return false
+	}
+	log.Infof("the vulnerability policy is skipped for repository %s by the exception of %s until %v: %s",
+		repository, exception.Creator, exception.ExpiresAt, exception.Justification)
+	return true
+}
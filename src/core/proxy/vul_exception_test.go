@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVulExempted(t *testing.T) {
+	defer func(f func(string) (*models.VulException, error)) {
+		getActiveVulException = f
+	}(getActiveVulException)
+
+	getActiveVulException = func(repository string) (*models.VulException, error) {
+		if repository != "library/noisy" {
+			return nil, nil
+		}
+		return &models.VulException{
+			Repository:    repository,
+			Justification: "false positive",
+			ExpiresAt:     time.Now().Add(time.Hour),
+			Creator:       "admin",
+		}, nil
+	}
+	assert.True(t, vulExempted("library/noisy"))
+	assert.False(t, vulExempted("library/hello-world"))
+
+	getActiveVulException = func(repository string) (*models.VulException, error) {
+		return nil, errors.New("database unavailable")
+	}
+	assert.False(t, vulExempted("library/noisy"))
+}
@@ -0,0 +1,95 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulltime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// DefaultInterval is the default interval between two flushes of the recorded pull time
+const DefaultInterval = 30 * time.Second
+
+var (
+	records = map[string]*models.TagPullTime{}
+	lock    sync.Mutex
+	// the function writing the pull time into database, replaced in testing
+	setTagPullTimes = dao.SetTagPullTimes
+)
+
+// Record records the pull time of the tag in memory. As the images are pulled much more
+// frequently than pushed, the records are written into database in batch by Flush and
+// only the latest pull time of each tag is kept between two flushes
+func Record(repository, tag string, pullTime time.Time) {
+	lock.Lock()
+	defer lock.Unlock()
+	record(&models.TagPullTime{
+		Repository: repository,
+		Tag:        tag,
+		PullTime:   pullTime,
+	})
+}
+
+func record(pt *models.TagPullTime) {
+	key := pt.Repository + ":" + pt.Tag
+	if r, ok := records[key]; ok && !r.PullTime.Before(pt.PullTime) {
+		return
+	}
+	records[key] = pt
+}
+
+// Flush writes the recorded pull time into database, the records are kept
+// for the next flush if the writing fails
+func Flush() error {
+	lock.Lock()
+	pullTimes := make([]*models.TagPullTime, 0, len(records))
+	for _, pt := range records {
+		pullTimes = append(pullTimes, pt)
+	}
+	records = map[string]*models.TagPullTime{}
+	lock.Unlock()
+
+	if len(pullTimes) == 0 {
+		return nil
+	}
+	if err := setTagPullTimes(pullTimes); err != nil {
+		lock.Lock()
+		for _, pt := range pullTimes {
+			record(pt)
+		}
+		lock.Unlock()
+		return err
+	}
+	log.Debugf("the pull time of %d tags flushed", len(pullTimes))
+	return nil
+}
+
+// Start flushes the recorded pull time every interval in background
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := Flush(); err != nil {
+				log.Errorf("failed to flush the pull time of tags: %v", err)
+			}
+		}
+	}()
+	log.Infof("the pull time recorder started, interval: %v", interval)
+}
@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulltime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	var written []*models.TagPullTime
+	var err error
+	setTagPullTimes = func(pullTimes []*models.TagPullTime) error {
+		written = pullTimes
+		return err
+	}
+	defer func() {
+		setTagPullTimes = dao.SetTagPullTimes
+		records = map[string]*models.TagPullTime{}
+	}()
+
+	// nothing recorded
+	require.Nil(t, Flush())
+	assert.Nil(t, written)
+
+	now := time.Now()
+	Record("library/hello-world", "latest", now)
+	Record("library/hello-world", "latest", now.Add(-time.Minute))
+	Record("library/hello-world", "v1", now)
+
+	// the records are kept when failed to write
+	err = errors.New("error")
+	assert.NotNil(t, Flush())
+	assert.Equal(t, 2, len(written))
+	Record("library/hello-world", "v1", now.Add(time.Minute))
+
+	err = nil
+	require.Nil(t, Flush())
+	require.Equal(t, 2, len(written))
+	pullTimes := map[string]time.Time{}
+	for _, pt := range written {
+		pullTimes[pt.Tag] = pt.PullTime
+	}
+	assert.Equal(t, now, pullTimes["latest"])
+	assert.Equal(t, now.Add(time.Minute), pullTimes["v1"])
+
+	// flushed
+	written = nil
+	require.Nil(t, Flush())
+	assert.Nil(t, written)
+}
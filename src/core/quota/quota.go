@@ -0,0 +1,249 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quota enforces the storage quota of projects. The storage usage of a project is
+// the total size of the blobs and manifests pushed into it, the blobs shared by its
+// repositories are counted once. The pushes of manifests exceeding the quota are rejected,
+// the events are posted to the quota webhook of the project when the usage crosses the
+// thresholds and when the pushes start being rejected.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution"
+	// register the unmarshalers of the manifests
+	"github.com/docker/distribution/manifest/manifestlist"
+	_ "github.com/docker/distribution/manifest/schema1"
+	_ "github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/notifier"
+)
+
+// ErrQuotaExceeded is returned when the push exceeds the storage quota of the project
+var ErrQuotaExceeded = errors.New("the storage quota of the project is exceeded")
+
+// SizeLimitError is returned when the pushed image or one of its layers is larger than the
+// size limit of the project
+type SizeLimitError struct {
+	message string
+}
+
+func (s *SizeLimitError) Error() string {
+	return s.message
+}
+
+// the usages when the rejections were notified, keyed by the project IDs, so that the
+// retries of the rejected pushes aren't notified again until the usage changes
+var rejections sync.Map
+
+// BlobSizer returns the size of the blob stored in the registry, 0 is returned if the blob doesn't exist
+type BlobSizer func(digest string) (int64, error)
+
+// Blobs returns the manifest itself and the blobs referenced by it. The sizes of the references are
+// the ones declared in the manifest, which are 0 for schema 1 and aren't verified by the registry for
+// schema 2, so they must be measured by Measure before checking the limits and the quota.
+func Blobs(mediaType string, payload []byte) ([]*models.ProjectBlob, error) {
+	manifest, desc, err := distribution.UnmarshalManifest(mediaType, payload)
+	if err != nil {
+		return nil, err
+	}
+	blobs := []*models.ProjectBlob{
+		{
+			Digest: desc.Digest.String(),
+			Size:   int64(len(payload)),
+		},
+	}
+	for _, ref := range manifest.References() {
+		blobs = append(blobs, &models.ProjectBlob{
+			Digest: ref.Digest.String(),
+			Size:   ref.Size,
+		})
+	}
+	return blobs, nil
+}
+
+// Measure replaces the declared sizes of the blobs returned by Blobs with the sizes of the blobs
+// stored in the registry. The manifests referenced by the manifest lists are counted by their own
+// pushes, so their sizes are 0
+func Measure(mediaType string, blobs []*models.ProjectBlob, sizer BlobSizer) error {
+	sizes := map[string]int64{}
+	for _, blob := range blobs[1:] {
+		if mediaType == manifestlist.MediaTypeManifestList {
+			blob.Size = 0
+			continue
+		}
+		size, exist := sizes[blob.Digest]
+		if !exist {
+			var err error
+			if size, err = sizer(blob.Digest); err != nil {
+				return fmt.Errorf("failed to get the size of blob %s: %v", blob.Digest, err)
+			}
+			sizes[blob.Digest] = size
+		}
+		blob.Size = size
+	}
+	return nil
+}
+
+// CheckSizeLimits returns a SizeLimitError if the image or any of its layers is larger than the size
+// limits of the project, the blobs are the ones measured by Measure. The size of the image is the total
+// size of the manifest and the distinct blobs referenced by it
+func CheckSizeLimits(project *models.Project, blobs []*models.ProjectBlob) error {
+	if limit := project.MaxLayerSize(); limit >= 0 {
+		for _, blob := range blobs[1:] {
+			if blob.Size > limit {
+				return &SizeLimitError{
+					message: fmt.Sprintf("the size of layer %s is %d bytes, exceeding the limit %d bytes of project %s",
+						blob.Digest, blob.Size, limit, project.Name),
+				}
+			}
+		}
+	}
+	if limit := project.MaxImageSize(); limit >= 0 {
+		if size := addedSize(blobs, nil); size > limit {
+			return &SizeLimitError{
+				message: fmt.Sprintf("the size of the image is %d bytes, exceeding the limit %d bytes of project %s",
+					size, limit, project.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// Push is the push of a manifest into a project
+type Push struct {
+	Project    *models.Project
+	Repository string
+	Blobs      []*models.ProjectBlob
+	// the usage before the push and the size added by it
+	usage int64
+	added int64
+}
+
+// Prepare calculates the size added by the push, ErrQuotaExceeded is returned if it
+// exceeds the quota of the project
+func Prepare(project *models.Project, repository string, blobs []*models.ProjectBlob) (*Push, error) {
+	push := &Push{
+		Project:    project,
+		Repository: repository,
+		Blobs:      blobs,
+	}
+	usage, err := dao.GetProjectUsage(project.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	push.usage = usage
+
+	digests := []string{}
+	for _, blob := range blobs {
+		digests = append(digests, blob.Digest)
+	}
+	existing, err := dao.ListProjectBlobs(project.ProjectID, digests...)
+	if err != nil {
+		return nil, err
+	}
+	push.added = addedSize(blobs, existing)
+
+	quota := project.StorageQuota()
+	if quota < 0 || push.usage+push.added <= quota {
+		return push, nil
+	}
+	if last, ok := rejections.Load(project.ProjectID); !ok || last.(int64) != push.usage {
+		rejections.Store(project.ProjectID, push.usage)
+		push.publish(&notifier.QuotaEvent{
+			Event:     notifier.QuotaEventPushRejected,
+			Usage:     push.usage,
+			Requested: push.added,
+		})
+	}
+	return nil, ErrQuotaExceeded
+}
+
+// Commit records the blobs of the successful push and posts the events of the thresholds crossed
+func (p *Push) Commit() error {
+	if err := dao.AddProjectBlobs(p.Project.ProjectID, p.Blobs); err != nil {
+		return err
+	}
+	if p.added == 0 {
+		return nil
+	}
+	rejections.Delete(p.Project.ProjectID)
+
+	quota := p.Project.StorageQuota()
+	usage := p.usage + p.added
+	for _, threshold := range crossedThresholds(p.usage, usage, quota, p.Project.QuotaThresholds()) {
+		p.publish(&notifier.QuotaEvent{
+			Event:     notifier.QuotaEventThresholdCrossed,
+			Usage:     usage,
+			Threshold: threshold,
+		})
+		notifier.NotifyProjectAdmins(p.Project.ProjectID, models.UserNotificationQuotaWarning,
+			fmt.Sprintf("The storage usage of project %s has crossed %d%% of the quota, %d of %d bytes are used.",
+				p.Project.Name, threshold, usage, quota))
+	}
+	return nil
+}
+
+func (p *Push) publish(event *notifier.QuotaEvent) {
+	event.ProjectID = p.Project.ProjectID
+	event.ProjectName = p.Project.Name
+	event.Repository = p.Repository
+	event.Quota = p.Project.StorageQuota()
+	event.WebhookURL = p.Project.QuotaWebhookURL()
+	event.WebhookSchema = p.Project.QuotaWebhookSchema()
+	event.Time = p.Project.NotificationTime()
+	event.OccurAt = time.Now().UTC()
+	if err := notifier.Publish(notifier.QuotaTopic, *event); err != nil {
+		log.Errorf("failed to publish the %s event of quota of project %s: %v", event.Event, p.Project.Name, err)
+	}
+}
+
+// addedSize returns the total size of the blobs which don't exist in the project
+func addedSize(blobs, existing []*models.ProjectBlob) int64 {
+	exist := map[string]bool{}
+	for _, blob := range existing {
+		exist[blob.Digest] = true
+	}
+	var size int64
+	for _, blob := range blobs {
+		if exist[blob.Digest] {
+			continue
+		}
+		// the same blob may be referenced more than once
+		exist[blob.Digest] = true
+		size += blob.Size
+	}
+	return size
+}
+
+// crossedThresholds returns the percentages of quota crossed when the usage grows from "before" to "after"
+func crossedThresholds(before, after, quota int64, thresholds []int) []int {
+	crossed := []int{}
+	if quota <= 0 {
+		return crossed
+	}
+	for _, threshold := range thresholds {
+		limit := quota * int64(threshold) / 100
+		if before < limit && after >= limit {
+			crossed = append(crossed, threshold)
+		}
+	}
+	return crossed
+}
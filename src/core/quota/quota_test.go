@@ -0,0 +1,221 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/libtrust"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobs(t *testing.T) {
+	payload := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config": {
+			"mediaType": "application/vnd.docker.container.image.v1+json",
+			"size": 1510,
+			"digest": "sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e"
+		},
+		"layers": [
+			{
+				"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip",
+				"size": 977,
+				"digest": "sha256:1b930d010525941c1d56ec53b97bd057a67ae1865eebf042686d2a2d18271ced"
+			}
+		]
+	}`)
+	blobs, err := Blobs(schema2.MediaTypeManifest, payload)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(blobs))
+	assert.Equal(t, int64(len(payload)), blobs[0].Size)
+	assert.Equal(t, "sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e", blobs[1].Digest)
+	assert.Equal(t, int64(1510), blobs[1].Size)
+	assert.Equal(t, int64(977), blobs[2].Size)
+
+	_, err = Blobs(schema2.MediaTypeManifest, []byte("invalid"))
+	assert.NotNil(t, err)
+}
+
+// sizer returns the sizes of the blobs in the map as the ones stored in the registry
+func sizer(sizes map[string]int64) BlobSizer {
+	return func(digest string) (int64, error) {
+		if _, exist := sizes[digest]; !exist {
+			return 0, fmt.Errorf("unexpected blob %s", digest)
+		}
+		return sizes[digest], nil
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	// the sizes declared in the manifest are replaced by the ones in the registry
+	blobs := []*models.ProjectBlob{
+		{Digest: "sha256:m", Size: 100},
+		{Digest: "sha256:a", Size: 1},
+		{Digest: "sha256:b", Size: 1},
+		{Digest: "sha256:b", Size: 1},
+	}
+	require.Nil(t, Measure(schema2.MediaTypeManifest, blobs, sizer(map[string]int64{
+		"sha256:a": 10,
+		"sha256:b": 20,
+	})))
+	assert.Equal(t, int64(100), blobs[0].Size)
+	assert.Equal(t, int64(10), blobs[1].Size)
+	assert.Equal(t, int64(20), blobs[2].Size)
+	assert.Equal(t, int64(20), blobs[3].Size)
+
+	// the manifests referenced by the manifest list are counted by their own pushes
+	blobs = []*models.ProjectBlob{
+		{Digest: "sha256:l", Size: 100},
+		{Digest: "sha256:m", Size: 1000},
+	}
+	require.Nil(t, Measure(manifestlist.MediaTypeManifestList, blobs, sizer(nil)))
+	assert.Equal(t, int64(100), blobs[0].Size)
+	assert.Equal(t, int64(0), blobs[1].Size)
+
+	// failed to get the size
+	blobs = []*models.ProjectBlob{
+		{Digest: "sha256:m", Size: 100},
+		{Digest: "sha256:a", Size: 1},
+	}
+	assert.NotNil(t, Measure(schema2.MediaTypeManifest, blobs, sizer(nil)))
+}
+
+func TestAddedSize(t *testing.T) {
+	blobs := []*models.ProjectBlob{
+		{Digest: "sha256:a", Size: 1},
+		{Digest: "sha256:b", Size: 10},
+		{Digest: "sha256:b", Size: 10},
+		{Digest: "sha256:c", Size: 100},
+	}
+	assert.Equal(t, int64(111), addedSize(blobs, nil))
+	assert.Equal(t, int64(11), addedSize(blobs, []*models.ProjectBlob{{Digest: "sha256:c"}}))
+}
+
+func TestCheckSizeLimits(t *testing.T) {
+	blobs := []*models.ProjectBlob{
+		{Digest: "sha256:a", Size: 1},
+		{Digest: "sha256:b", Size: 10},
+		{Digest: "sha256:c", Size: 100},
+		{Digest: "sha256:c", Size: 100},
+	}
+	p := &models.Project{Name: "library"}
+	assert.Nil(t, CheckSizeLimits(p, blobs))
+
+	// the image size counts the duplicate layers once
+	p.SetMetadata(models.ProMetaMaxImageSize, "111")
+	assert.Nil(t, CheckSizeLimits(p, blobs))
+	p.SetMetadata(models.ProMetaMaxImageSize, "110")
+	err := CheckSizeLimits(p, blobs)
+	require.NotNil(t, err)
+	_, ok := err.(*SizeLimitError)
+	assert.True(t, ok)
+	assert.Equal(t, "the size of the image is 111 bytes, exceeding the limit 110 bytes of project library", err.Error())
+
+	p.SetMetadata(models.ProMetaMaxImageSize, "-1")
+	p.SetMetadata(models.ProMetaMaxLayerSize, "50")
+	err = CheckSizeLimits(p, blobs)
+	require.NotNil(t, err)
+	assert.Equal(t, "the size of layer sha256:c is 100 bytes, exceeding the limit 50 bytes of project library", err.Error())
+}
+
+func TestCheckSizeLimitsOfSchema1(t *testing.T) {
+	key, err := libtrust.GenerateECP256PrivateKey()
+	require.Nil(t, err)
+	layer := "sha256:1b930d010525941c1d56ec53b97bd057a67ae1865eebf042686d2a2d18271ced"
+	manifest, err := schema1.Sign(&schema1.Manifest{
+		Versioned: manifest.Versioned{
+			SchemaVersion: 1,
+		},
+		Name:         "library/hello-world",
+		Tag:          "latest",
+		Architecture: "amd64",
+		FSLayers: []schema1.FSLayer{
+			{BlobSum: digest.Digest(layer)},
+		},
+		History: []schema1.History{
+			{V1Compatibility: `{"id":"1"}`},
+		},
+	}, key)
+	require.Nil(t, err)
+	payload, err := manifest.MarshalJSON()
+	require.Nil(t, err)
+
+	// the sizes of the layers are unknown in the manifest of schema 1
+	blobs, err := Blobs(schema1.MediaTypeSignedManifest, payload)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(blobs))
+	assert.Equal(t, int64(0), blobs[1].Size)
+
+	require.Nil(t, Measure(schema1.MediaTypeSignedManifest, blobs, sizer(map[string]int64{
+		layer: 100,
+	})))
+	p := &models.Project{Name: "library"}
+	p.SetMetadata(models.ProMetaMaxLayerSize, "50")
+	err = CheckSizeLimits(p, blobs)
+	require.NotNil(t, err)
+	assert.Equal(t, "the size of layer "+layer+" is 100 bytes, exceeding the limit 50 bytes of project library", err.Error())
+}
+
+func TestCheckSizeLimitsOfDeclaredSizes(t *testing.T) {
+	// the manifest declares a smaller size than the one of the layer stored in the registry
+	payload := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config": {
+			"mediaType": "application/vnd.docker.container.image.v1+json",
+			"size": 1,
+			"digest": "sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e"
+		},
+		"layers": [
+			{
+				"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip",
+				"size": 1,
+				"digest": "sha256:1b930d010525941c1d56ec53b97bd057a67ae1865eebf042686d2a2d18271ced"
+			}
+		]
+	}`)
+	blobs, err := Blobs(schema2.MediaTypeManifest, payload)
+	require.Nil(t, err)
+	require.Nil(t, Measure(schema2.MediaTypeManifest, blobs, sizer(map[string]int64{
+		"sha256:fce289e99eb9bca977dae136fbe2a82b6b7d4c372474c9235adc1741675f587e": 10,
+		"sha256:1b930d010525941c1d56ec53b97bd057a67ae1865eebf042686d2a2d18271ced": 1000,
+	})))
+	p := &models.Project{Name: "library"}
+	p.SetMetadata(models.ProMetaMaxImageSize, "500")
+	err = CheckSizeLimits(p, blobs)
+	require.NotNil(t, err)
+	_, ok := err.(*SizeLimitError)
+	assert.True(t, ok)
+}
+
+func TestCrossedThresholds(t *testing.T) {
+	thresholds := []int{50, 80, 95}
+	assert.Equal(t, []int{}, crossedThresholds(0, 40, 100, thresholds))
+	assert.Equal(t, []int{50}, crossedThresholds(40, 50, 100, thresholds))
+	assert.Equal(t, []int{50, 80, 95}, crossedThresholds(0, 100, 100, thresholds))
+	assert.Equal(t, []int{}, crossedThresholds(50, 60, 100, thresholds))
+	// unlimited
+	assert.Equal(t, []int{}, crossedThresholds(0, 100, -1, thresholds))
+}
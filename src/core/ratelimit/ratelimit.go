@@ -0,0 +1,91 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+var (
+	// the last rate limits of the upstream registries, keyed by the endpoints
+	rateLimits = map[string]*models.RateLimit{}
+	lock       sync.Mutex
+
+	labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// Record records the rate limit of the endpoint, the older one is ignored
+func Record(endpoint string, rateLimit *models.RateLimit) {
+	if rateLimit == nil {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if r, ok := rateLimits[endpoint]; ok && r.CheckTime.After(rateLimit.CheckTime) {
+		return
+	}
+	rateLimits[endpoint] = rateLimit
+}
+
+// Get returns the last rate limit of the endpoint, nil if it isn't recorded
+func Get(endpoint string) *models.RateLimit {
+	lock.Lock()
+	defer lock.Unlock()
+	return rateLimits[endpoint]
+}
+
+// WriteMetrics writes the last rate limits of the endpoints as the gauges in the
+// text format of Prometheus
+func WriteMetrics(w io.Writer) error {
+	lock.Lock()
+	endpoints := make([]string, 0, len(rateLimits))
+	limits := map[string]models.RateLimit{}
+	for endpoint, r := range rateLimits {
+		endpoints = append(endpoints, endpoint)
+		limits[endpoint] = *r
+	}
+	lock.Unlock()
+	sort.Strings(endpoints)
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(r *models.RateLimit) int
+	}{
+		{"harbor_upstream_ratelimit_limit", "The limit of the requests to the upstream registry in the window.",
+			func(r *models.RateLimit) int { return r.Limit }},
+		{"harbor_upstream_ratelimit_remaining", "The remaining requests to the upstream registry in the window.",
+			func(r *models.RateLimit) int { return r.Remaining }},
+	}
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name); err != nil {
+			return err
+		}
+		for _, endpoint := range endpoints {
+			r := limits[endpoint]
+			if _, err := fmt.Fprintf(w, "%s{endpoint=\"%s\"} %d\n", metric.name,
+				labelReplacer.Replace(endpoint), metric.value(&r)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
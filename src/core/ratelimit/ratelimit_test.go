@@ -0,0 +1,55 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	defer func() {
+		rateLimits = map[string]*models.RateLimit{}
+	}()
+
+	now := time.Now()
+	Record("https://registry-1.docker.io", nil)
+	assert.Nil(t, Get("https://registry-1.docker.io"))
+
+	Record("https://registry-1.docker.io", &models.RateLimit{Limit: 100, Remaining: 80, CheckTime: now})
+	// the older one is ignored
+	Record("https://registry-1.docker.io", &models.RateLimit{Limit: 100, Remaining: 90, CheckTime: now.Add(-time.Minute)})
+	Record("https://hub.example.com", &models.RateLimit{Limit: 200, Remaining: 10, CheckTime: now})
+	r := Get("https://registry-1.docker.io")
+	require.NotNil(t, r)
+	assert.Equal(t, 80, r.Remaining)
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, WriteMetrics(buf))
+	assert.Equal(t, `# HELP harbor_upstream_ratelimit_limit The limit of the requests to the upstream registry in the window.
+# TYPE harbor_upstream_ratelimit_limit gauge
+harbor_upstream_ratelimit_limit{endpoint="https://hub.example.com"} 200
+harbor_upstream_ratelimit_limit{endpoint="https://registry-1.docker.io"} 100
+# HELP harbor_upstream_ratelimit_remaining The remaining requests to the upstream registry in the window.
+# TYPE harbor_upstream_ratelimit_remaining gauge
+harbor_upstream_ratelimit_remaining{endpoint="https://hub.example.com"} 10
+harbor_upstream_ratelimit_remaining{endpoint="https://registry-1.docker.io"} 80
+`, buf.String())
+}
@@ -0,0 +1,171 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package repoexport dumps the full repository list of a project into a CSV or JSON file
+// in background, the repositories are read from the database in batches so that the
+// projects with tens of thousands of repositories are exported without pagination.
+package repoexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the count of the repositories read from the database in a batch
+const batchSize = 1000
+
+// listRepositories lists the repositories of the project after the ID, defined as a var for testing
+var listRepositories = dao.GetProjectRepositoriesAfter
+
+// Repository is an entry of the dump
+type Repository struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	PullCount    int64     `json:"pull_count"`
+	StarCount    int64     `json:"star_count"`
+	CreationTime time.Time `json:"creation_time"`
+	UpdateTime   time.Time `json:"update_time"`
+}
+
+// ValidFormat returns whether the format of the dump is supported
+func ValidFormat(format string) bool {
+	return format == models.RepositoryExportCSV || format == models.RepositoryExportJSON
+}
+
+// Write writes the repositories of the project into the writer in the format, the count
+// of the written repositories is returned. The JSON dump is an array of the repositories
+func Write(w io.Writer, format string, projectID int64) (int64, error) {
+	var e encoder
+	switch format {
+	case models.RepositoryExportCSV:
+		e = newCSVEncoder(w)
+	case models.RepositoryExportJSON:
+		e = &jsonEncoder{w: w}
+	default:
+		return 0, fmt.Errorf("unsupported format %s", format)
+	}
+	if err := e.begin(); err != nil {
+		return 0, err
+	}
+
+	var count, id int64
+	for {
+		records, err := listRepositories(projectID, id, batchSize)
+		if err != nil {
+			return count, err
+		}
+		for _, record := range records {
+			if err = e.encode(&Repository{
+				Name:         record.Name,
+				Description:  record.Description,
+				PullCount:    record.PullCount,
+				StarCount:    record.StarCount,
+				CreationTime: record.CreationTime.UTC(),
+				UpdateTime:   record.UpdateTime.UTC(),
+			}); err != nil {
+				return count, err
+			}
+			count++
+			id = record.RepositoryID
+		}
+		if len(records) < batchSize {
+			break
+		}
+	}
+	return count, e.end()
+}
+
+type encoder interface {
+	begin() error
+	encode(repository *Repository) error
+	end() error
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{
+		writer: csv.NewWriter(w),
+	}
+}
+
+func (c *csvEncoder) begin() error {
+	return c.writer.Write([]string{"name", "description", "pull_count", "star_count", "creation_time", "update_time"})
+}
+
+// encode writes the row of the repository, the values are escaped to not be treated
+// as formulas by the spreadsheets
+func (c *csvEncoder) encode(repository *Repository) error {
+	row := []string{
+		repository.Name,
+		repository.Description,
+		strconv.FormatInt(repository.PullCount, 10),
+		strconv.FormatInt(repository.StarCount, 10),
+		repository.CreationTime.Format(time.RFC3339),
+		repository.UpdateTime.Format(time.RFC3339),
+	}
+	for i, value := range row {
+		if len(value) > 0 && strings.ContainsRune("=+-@", rune(value[0])) {
+			row[i] = "'" + value
+		}
+	}
+	return c.writer.Write(row)
+}
+
+func (c *csvEncoder) end() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// jsonEncoder writes the repositories as the elements of an array one by one rather
+// than holding the whole list in memory
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonEncoder) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonEncoder) encode(repository *Repository) error {
+	data, err := json.Marshal(repository)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		if _, err = io.WriteString(j.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	j.count++
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonEncoder) end() error {
+	_, err := io.WriteString(j.w, "]\n")
+	return err
+}
@@ -0,0 +1,122 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repoexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepositories returns the function listing n repositories of the project
+func fakeRepositories(n int64) func(int64, int64, int) ([]*models.RepoRecord, error) {
+	return func(projectID, id int64, size int) ([]*models.RepoRecord, error) {
+		records := []*models.RepoRecord{}
+		for i := id + 1; i <= n && len(records) < size; i++ {
+			records = append(records, &models.RepoRecord{
+				RepositoryID: i,
+				ProjectID:    projectID,
+				Name:         fmt.Sprintf("library/repo%d", i),
+				PullCount:    i,
+				CreationTime: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdateTime:   time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+			})
+		}
+		return records, nil
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	defer func(f func(int64, int64, int) ([]*models.RepoRecord, error)) { listRepositories = f }(listRepositories)
+	listRepositories = fakeRepositories(batchSize + 1)
+
+	buf := &bytes.Buffer{}
+	count, err := Write(buf, models.RepositoryExportCSV, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(batchSize+1), count)
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	require.Nil(t, err)
+	require.Equal(t, batchSize+2, len(rows))
+	assert.Equal(t, "name", rows[0][0])
+	assert.Equal(t, []string{"library/repo1", "", "1", "0", "2019-01-01T00:00:00Z", "2019-01-02T00:00:00Z"}, rows[1])
+	assert.Equal(t, fmt.Sprintf("library/repo%d", batchSize+1), rows[batchSize+1][0])
+}
+
+func TestWriteJSON(t *testing.T) {
+	defer func(f func(int64, int64, int) ([]*models.RepoRecord, error)) { listRepositories = f }(listRepositories)
+
+	// no repository
+	listRepositories = fakeRepositories(0)
+	buf := &bytes.Buffer{}
+	count, err := Write(buf, models.RepositoryExportJSON, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	assert.JSONEq(t, `[]`, buf.String())
+
+	listRepositories = fakeRepositories(2)
+	buf.Reset()
+	count, err = Write(buf, models.RepositoryExportJSON, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	repositories := []*Repository{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &repositories))
+	require.Equal(t, 2, len(repositories))
+	assert.Equal(t, "library/repo2", repositories[1].Name)
+	assert.Equal(t, int64(2), repositories[1].PullCount)
+
+	// unsupported format
+	_, err = Write(buf, "xml", 1)
+	assert.NotNil(t, err)
+}
+
+func TestDump(t *testing.T) {
+	defer func(f func(int64, int64, int) ([]*models.RepoRecord, error)) { listRepositories = f }(listRepositories)
+	dir, err := ioutil.TempDir("", "repoexport")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	os.Setenv("REPOSITORY_EXPORT_DIR", dir)
+	defer os.Unsetenv("REPOSITORY_EXPORT_DIR")
+
+	listRepositories = fakeRepositories(3)
+	count, size, err := dump(1, 1, models.RepositoryExportCSV)
+	require.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	info, err := os.Stat(Path(1, models.RepositoryExportCSV))
+	require.Nil(t, err)
+	assert.Equal(t, info.Size(), size)
+
+	// the partial dump is removed when the export fails
+	listRepositories = func(int64, int64, int) ([]*models.RepoRecord, error) {
+		return nil, errors.New("error")
+	}
+	_, _, err = dump(2, 1, models.RepositoryExportCSV)
+	assert.NotNil(t, err)
+	_, err = os.Stat(Path(2, models.RepositoryExportCSV) + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.True(t, ValidFormat(models.RepositoryExportJSON))
+	assert.False(t, ValidFormat("xml"))
+}
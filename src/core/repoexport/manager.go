@@ -0,0 +1,157 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repoexport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/coretask"
+)
+
+// TaskName is the name of the task exporting the repositories
+const TaskName = "REPOSITORY_EXPORT"
+
+var (
+	// ErrExportRunning is returned when another export of the project is still running
+	ErrExportRunning = errors.New("another repository export of the project is running")
+
+	// Runner runs the exports submitted to jobservice
+	Runner = &coretask.Runner{
+		Run:  Run,
+		Fail: Fail,
+	}
+)
+
+// Export records the export of the project and submits it to jobservice, the ID of the
+// export record is returned. Only one export of a project is allowed to run at the same
+// time, which is locked in the database
+func Export(project *models.Project, format, creator string) (int64, error) {
+	id, err := dao.AddRepositoryExport(&models.RepositoryExport{
+		ProjectID: project.ProjectID,
+		Format:    format,
+		Status:    models.RepositoryExportRunning,
+		Creator:   creator,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = dao.LockResources(coretask.Owner(TaskName, id), coretask.ProjectResource(TaskName, project.ProjectID)); err != nil {
+		if e := dao.DeleteRepositoryExport(id); e != nil {
+			log.Errorf("failed to delete repository export %d: %v", id, e)
+		}
+		if err == dao.ErrResourceLocked {
+			return 0, ErrExportRunning
+		}
+		return 0, err
+	}
+	if err = coretask.Submit(TaskName, id); err != nil {
+		err = fmt.Errorf("failed to submit the export: %v", err)
+		if e := finish(id, 0, 0, err); e != nil {
+			log.Errorf("failed to finish repository export %d: %v", id, e)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Run dumps the repositories of the export, they're dumped again if it's interrupted
+func Run(id int64) error {
+	export, err := dao.GetRepositoryExport(id)
+	if err != nil {
+		return err
+	}
+	if export == nil || export.Status != models.RepositoryExportRunning {
+		log.Debugf("the repository export %d isn't running, skip", id)
+		return nil
+	}
+	count, size, err := dump(id, export.ProjectID, export.Format)
+	return finish(id, count, size, err)
+}
+
+// Fail marks the export as failed if it's still running
+func Fail(id int64, message string) error {
+	export, err := dao.GetRepositoryExport(id)
+	if err != nil {
+		return err
+	}
+	if export == nil || export.Status != models.RepositoryExportRunning {
+		return nil
+	}
+	return finish(id, 0, 0, errors.New(message))
+}
+
+// finish records the result of the export and releases the lock, the error recording
+// the result is returned
+func finish(id, count, size int64, result error) error {
+	status, message := models.RepositoryExportSucceeded, ""
+	if result != nil {
+		log.Errorf("failed to export the repositories of repository export %d: %v", id, result)
+		status, message = models.RepositoryExportFailed, result.Error()
+	}
+	if err := dao.UpdateRepositoryExportStatus(id, status, message, count, size); err != nil {
+		return fmt.Errorf("failed to update the status of repository export %d: %v", id, err)
+	}
+	return dao.UnlockResources(coretask.Owner(TaskName, id))
+}
+
+// dump writes the repositories into a temporary file which is renamed once it's complete,
+// the count of the repositories and the size of the file are returned
+func dump(id, projectID int64, format string) (int64, int64, error) {
+	if err := os.MkdirAll(config.RepositoryExportDir(), 0700); err != nil {
+		return 0, 0, err
+	}
+	tmp := Path(id, format) + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(tmp)
+
+	count, err := Write(file, format, projectID)
+	if e := file.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = os.Rename(tmp, Path(id, format)); err != nil {
+		return 0, 0, err
+	}
+	return count, info.Size(), nil
+}
+
+// Path returns the path of the dump of the export
+func Path(id int64, format string) string {
+	return filepath.Join(config.RepositoryExportDir(), fmt.Sprintf("%d.%s", id, format))
+}
+
+// Delete deletes the export record and the dump
+func Delete(export *models.RepositoryExport) error {
+	if err := os.Remove(Path(export.ID, export.Format)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return dao.DeleteRepositoryExport(export.ID)
+}
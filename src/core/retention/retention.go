@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention decides which tags of the repositories are retained. A project has a
+// retention policy in its metadata, the repositories of it can override the policy or opt
+// out the retention, which is stored as the retention override of the repository. The tags
+// which aren't retained are deleted by the cleaner of core.
+package retention
+
+import (
+	"path"
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// Candidate is a tag evaluated by the retention policy
+type Candidate struct {
+	Tag      string
+	Digest   string
+	PushTime time.Time
+	// it's zero if the tag has never been pulled
+	PullTime time.Time
+	// the tag under legal hold is retained regardless of the policy
+	Held bool
+}
+
+// Resolve returns the effective retention policy of the repository, the override of the
+// repository takes precedence over the policy of the project. Nil is returned if neither
+// has a policy or the repository opts out the retention.
+func Resolve(project *models.Project, repository string) (*models.RetentionPolicy, error) {
+	override, err := dao.GetRepoRetention(repository)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(project.RetentionPolicy(), override), nil
+}
+
+func resolve(policy *models.RetentionPolicy, override *models.RepoRetention) *models.RetentionPolicy {
+	if override == nil {
+		return policy
+	}
+	switch override.Mode {
+	case models.RetentionModeDisabled:
+		return nil
+	case models.RetentionModeOverride:
+		return override.Policy
+	default:
+		return policy
+	}
+}
+
+// Evaluate returns the retained and deleted tags according to the policy, all the tags are
+// retained if the policy is nil or has no rule.
+func Evaluate(policy *models.RetentionPolicy, candidates []*Candidate, now time.Time) (retained, deleted []*Candidate) {
+	retained, deleted = []*Candidate{}, []*Candidate{}
+	if policy == nil || (policy.KeepLatest == 0 && policy.KeepPulledWithinDays == 0 && len(policy.KeepTags) == 0) {
+		return append(retained, candidates...), deleted
+	}
+
+	sorted := make([]*Candidate, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PushTime.After(sorted[j].PushTime)
+	})
+	pulledAfter := now.AddDate(0, 0, -policy.KeepPulledWithinDays)
+	for i, c := range sorted {
+		if c.Held || i < policy.KeepLatest ||
+			(policy.KeepPulledWithinDays > 0 && c.PullTime.After(pulledAfter)) ||
+			matchAny(policy.KeepTags, c.Tag) {
+			retained = append(retained, c)
+			continue
+		}
+		deleted = append(deleted, c)
+	}
+	return retained, deleted
+}
+
+func matchAny(patterns []string, tag string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, tag); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func tags(candidates []*Candidate) []string {
+	result := []string{}
+	for _, c := range candidates {
+		result = append(result, c.Tag)
+	}
+	return result
+}
+
+func TestResolve(t *testing.T) {
+	projectPolicy := &models.RetentionPolicy{KeepLatest: 10}
+	repoPolicy := &models.RetentionPolicy{KeepLatest: 3}
+
+	assert.Equal(t, projectPolicy, resolve(projectPolicy, nil))
+	assert.Nil(t, resolve(nil, nil))
+	assert.Equal(t, repoPolicy, resolve(projectPolicy, &models.RepoRetention{
+		Mode:   models.RetentionModeOverride,
+		Policy: repoPolicy,
+	}))
+	assert.Equal(t, repoPolicy, resolve(nil, &models.RepoRetention{
+		Mode:   models.RetentionModeOverride,
+		Policy: repoPolicy,
+	}))
+	assert.Nil(t, resolve(projectPolicy, &models.RepoRetention{
+		Mode: models.RetentionModeDisabled,
+	}))
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	candidates := []*Candidate{
+		{Tag: "v1.0", PushTime: now.Add(-10 * day)},
+		{Tag: "dev-1", PushTime: now.Add(-9 * day), PullTime: now.Add(-1 * day)},
+		{Tag: "dev-2", PushTime: now.Add(-8 * day), PullTime: now.Add(-20 * day)},
+		{Tag: "dev-3", PushTime: now.Add(-2 * day)},
+		{Tag: "latest", PushTime: now.Add(-1 * day)},
+	}
+
+	// no policy
+	retained, deleted := Evaluate(nil, candidates, now)
+	assert.Equal(t, 5, len(retained))
+	assert.Equal(t, 0, len(deleted))
+	retained, deleted = Evaluate(&models.RetentionPolicy{}, candidates, now)
+	assert.Equal(t, 5, len(retained))
+	assert.Equal(t, 0, len(deleted))
+
+	// keep latest
+	retained, deleted = Evaluate(&models.RetentionPolicy{KeepLatest: 2}, candidates, now)
+	assert.Equal(t, []string{"latest", "dev-3"}, tags(retained))
+	assert.Equal(t, []string{"dev-2", "dev-1", "v1.0"}, tags(deleted))
+
+	// all the rules
+	retained, deleted = Evaluate(&models.RetentionPolicy{
+		KeepLatest:           1,
+		KeepPulledWithinDays: 7,
+		KeepTags:             []string{"v*"},
+	}, candidates, now)
+	assert.Equal(t, []string{"latest", "dev-1", "v1.0"}, tags(retained))
+	assert.Equal(t, []string{"dev-3", "dev-2"}, tags(deleted))
+
+	// the tag under legal hold is retained
+	candidates[2].Held = true
+	retained, deleted = Evaluate(&models.RetentionPolicy{KeepLatest: 2}, candidates, now)
+	assert.Equal(t, []string{"latest", "dev-3", "dev-2"}, tags(retained))
+	assert.Equal(t, []string{"dev-1", "v1.0"}, tags(deleted))
+}
+
+func TestExcludeShared(t *testing.T) {
+	retained := []*Candidate{
+		{Tag: "latest", Digest: "sha256:a"},
+	}
+	deleted := []*Candidate{
+		{Tag: "v1.1", Digest: "sha256:a"},
+		{Tag: "v1.0", Digest: "sha256:b"},
+	}
+	assert.Equal(t, []string{"v1.0"}, tags(excludeShared(retained, deleted)))
+	assert.Equal(t, []string{}, tags(excludeShared(retained, nil)))
+}
@@ -0,0 +1,184 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/legalhold"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/accesslog"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/goharbor/harbor/src/replication/event/notification"
+	"github.com/goharbor/harbor/src/replication/event/topic"
+)
+
+// the operator recorded in the histories and access logs of the tags deleted by the retention
+const operator = "harbor-retention"
+
+// Run deletes the tags which aren't retained by the effective retention policies of the
+// repositories, the count of the deleted tags is returned. The tags under legal hold and the
+// ones whose push time is unknown are retained, and the projects in a freeze window are skipped
+func Run() (int64, error) {
+	repositories, err := dao.GetRepositories()
+	if err != nil {
+		return 0, err
+	}
+	projects := map[int64]*models.Project{}
+	var count int64
+	for _, repository := range repositories {
+		project, ok := projects[repository.ProjectID]
+		if !ok {
+			if project, err = config.GlobalProjectMgr.Get(repository.ProjectID); err != nil {
+				return count, err
+			}
+			projects[repository.ProjectID] = project
+		}
+		if project == nil {
+			continue
+		}
+		policy, err := Resolve(project, repository.Name)
+		if err != nil {
+			return count, err
+		}
+		if policy == nil {
+			continue
+		}
+		if err = coreutils.CheckFreezeWindow(project.Name); err != nil {
+			log.Debugf("skip the retention of repository %s: %v", repository.Name, err)
+			continue
+		}
+		n, err := apply(project, repository.Name, policy)
+		count += n
+		if err != nil {
+			log.Errorf("failed to apply the retention policy to repository %s: %v", repository.Name, err)
+		}
+	}
+	return count, nil
+}
+
+// apply deletes the tags of the repository which aren't retained by the policy
+func apply(project *models.Project, repository string, policy *models.RetentionPolicy) (int64, error) {
+	client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		return 0, err
+	}
+	tags, err := client.ListTag()
+	if err != nil {
+		return 0, err
+	}
+	// the digests are got before deleting any tag as the tags referencing
+	// the same digest are deleted together
+	digests := coreutils.TagDigests(client, repository, tags)
+	candidates, err := listCandidates(client, repository, digests)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	retained, deleted := Evaluate(policy, candidates, time.Now())
+	for _, c := range excludeShared(retained, deleted) {
+		// checked again right before the deletion as the hold may be placed in the meantime
+		hold, err := legalhold.Blocking(client, repository, map[string]string{c.Tag: c.Digest})
+		if err != nil {
+			return count, err
+		}
+		if hold != nil {
+			log.Infof("skip the retention of %s:%s, %s is under legal hold", repository, c.Tag, hold.Target())
+			continue
+		}
+		ok, err := coreutils.DeleteTag(client, repository, c.Tag, c.Digest, operator)
+		if err != nil {
+			return count, fmt.Errorf("failed to delete tag %s: %v", c.Tag, err)
+		}
+		if !ok {
+			continue
+		}
+		count++
+		image := repository + ":" + c.Tag
+		if err = notifier.Publish(topic.ReplicationEventTopicOnDeletion, notification.OnDeletionNotification{
+			Image: image,
+		}); err != nil {
+			log.Errorf("failed to publish on deletion topic for resource %s: %v", image, err)
+		}
+		if err = accesslog.Add(models.AccessLog{
+			Username:  operator,
+			ProjectID: project.ProjectID,
+			RepoName:  repository,
+			RepoTag:   c.Tag,
+			Operation: "delete",
+			OpTime:    time.Now(),
+		}); err != nil {
+			log.Errorf("failed to add access log: %v", err)
+		}
+	}
+	return count, nil
+}
+
+// listCandidates returns the tags evaluated by the policy, the push time is the one of the
+// latest history of the tag, the tags without it are left out
+func listCandidates(client *registry.Repository, repository string, digests map[string]string) ([]*Candidate, error) {
+	candidates := []*Candidate{}
+	for tag, digest := range digests {
+		history, err := dao.GetLatestTagHistory(repository, tag)
+		if err != nil {
+			return nil, err
+		}
+		if history == nil || history.Operation == models.TagHistoryDelete {
+			continue
+		}
+		candidate := &Candidate{
+			Tag:      tag,
+			Digest:   digest,
+			PushTime: history.OpTime,
+		}
+		pullTime, err := dao.GetTagPullTime(repository, tag)
+		if err != nil {
+			return nil, err
+		}
+		if pullTime != nil {
+			candidate.PullTime = pullTime.PullTime
+		}
+		hold, err := legalhold.Blocking(client, repository, map[string]string{tag: digest})
+		if err != nil {
+			return nil, err
+		}
+		candidate.Held = hold != nil
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// excludeShared returns the deleted tags which share no digest with the retained ones, as the
+// registry deletes the tags referencing the same digest together
+func excludeShared(retained, deleted []*Candidate) []*Candidate {
+	digests := map[string]bool{}
+	for _, c := range retained {
+		digests[c.Digest] = true
+	}
+	result := []*Candidate{}
+	for _, c := range deleted {
+		if !digests[c.Digest] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
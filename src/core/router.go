@@ -18,10 +18,12 @@ import (
 	"github.com/goharbor/harbor/src/core/api"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/controllers"
+	"github.com/goharbor/harbor/src/core/service/admission"
 	"github.com/goharbor/harbor/src/core/service/notifications/admin"
 	"github.com/goharbor/harbor/src/core/service/notifications/clair"
 	"github.com/goharbor/harbor/src/core/service/notifications/jobs"
 	"github.com/goharbor/harbor/src/core/service/notifications/registry"
+	"github.com/goharbor/harbor/src/core/service/tasks"
 	"github.com/goharbor/harbor/src/core/service/token"
 
 	"github.com/astaxie/beego"
@@ -40,14 +42,24 @@ func initRouters() {
 
 		// API:
 		beego.Router("/api/projects/:pid([0-9]+)/members/?:pmid([0-9]+)", &api.ProjectMemberAPI{})
+		beego.Router("/api/projects/:pid([0-9]+)/members/batch", &api.ProjectMemberAPI{}, "post:Batch")
 		beego.Router("/api/projects/", &api.ProjectAPI{}, "head:Head")
 		beego.Router("/api/projects/:id([0-9]+)", &api.ProjectAPI{})
+		beego.Router("/api/projects/by_name/:name", &api.ProjectAPI{}, "get:Get")
 
 		beego.Router("/api/users/:id", &api.UserAPI{}, "get:Get;delete:Delete;put:Put")
 		beego.Router("/api/users", &api.UserAPI{}, "get:List;post:Post")
 		beego.Router("/api/users/:id([0-9]+)/password", &api.UserAPI{}, "put:ChangePassword")
 		beego.Router("/api/users/:id/permissions", &api.UserAPI{}, "get:ListUserPermissions")
+		beego.Router("/api/users/:id([0-9]+)/scope_usage", &api.UserAPI{}, "get:ScopeUsage")
 		beego.Router("/api/users/:id/sysadmin", &api.UserAPI{}, "put:ToggleUserAdminRole")
+		beego.Router("/api/users/:id([0-9]+)/compliance_officer", &api.UserAPI{}, "put:SetComplianceOfficer")
+		beego.Router("/api/users/current/starred", &api.UserAPI{}, "get:ListStarred")
+		beego.Router("/api/users/current/usage", &api.UserAPI{}, "get:Usage")
+		beego.Router("/api/users/current/notifications", &api.UserNotificationAPI{}, "get:List")
+		beego.Router("/api/users/current/notifications/read", &api.UserNotificationAPI{}, "post:MarkRead")
+		beego.Router("/api/users/current/notifications/:id([0-9]+)/read", &api.UserNotificationAPI{}, "post:MarkRead")
+		beego.Router("/api/users/current/notifications/:id([0-9]+)", &api.UserNotificationAPI{}, "delete:Delete")
 		beego.Router("/api/usergroups/?:ugid([0-9]+)", &api.UserGroupAPI{})
 		beego.Router("/api/ldap/ping", &api.LdapAPI{}, "post:Ping")
 		beego.Router("/api/ldap/users/search", &api.LdapAPI{}, "get:Search")
@@ -60,31 +72,85 @@ func initRouters() {
 	beego.Router("/api/health", &api.HealthAPI{}, "get:CheckHealth")
 	beego.Router("/api/ping", &api.SystemInfoAPI{}, "get:Ping")
 	beego.Router("/api/search", &api.SearchAPI{})
+	beego.Router("/api/resolve", &api.ResolveAPI{}, "get:Get")
+	beego.Router("/api/federation/peers", &api.FederationPeerAPI{}, "get:List;post:Post")
+	beego.Router("/api/federation/peers/:id([0-9]+)", &api.FederationPeerAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/project_bootstrap_hooks", &api.ProjectBootstrapHookAPI{}, "get:List;post:Post")
+	beego.Router("/api/project_bootstrap_hooks/:id([0-9]+)", &api.ProjectBootstrapHookAPI{}, "get:Get;put:Put;delete:Delete")
 	beego.Router("/api/projects/", &api.ProjectAPI{}, "get:List;post:Post")
 	beego.Router("/api/projects/:id([0-9]+)/logs", &api.ProjectAPI{}, "get:Logs")
+	beego.Router("/api/projects/:id([0-9]+)/moved_tags", &api.ProjectAPI{}, "get:MovedTags")
+	beego.Router("/api/projects/:id([0-9]+)/vulnerability_trend", &api.ProjectAPI{}, "get:VulnerabilityTrend")
+	beego.Router("/api/projects/:id([0-9]+)/configuration", &api.ProjectAPI{}, "get:ExportConfig;put:ApplyConfig")
 	beego.Router("/api/projects/:id([0-9]+)/_deletable", &api.ProjectAPI{}, "get:Deletable")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/?:name", &api.MetadataAPI{}, "get:Get")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/", &api.MetadataAPI{}, "post:Post")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/:name", &api.MetadataAPI{}, "put:Put;delete:Delete")
+	beego.Router("/api/projects/:id([0-9]+)/custom_metadata", &api.ProjectCustomMetadataAPI{}, "get:List")
+	beego.Router("/api/projects/:id([0-9]+)/custom_metadata/:name", &api.ProjectCustomMetadataAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:id([0-9]+)/report_subscriptions", &api.ProjectReportSubscriptionAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:id([0-9]+)/report_subscriptions/:uid([0-9]+)", &api.ProjectReportSubscriptionAPI{}, "delete:Delete")
+	beego.Router("/api/projects/:id([0-9]+)/vul_exceptions", &api.VulExceptionAPI{}, "get:List")
 
 	beego.Router("/api/projects/:pid([0-9]+)/robots", &api.RobotAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/pull_secret", &api.RobotAPI{}, "post:PullSecret")
 	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)", &api.RobotAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/by_name/:name", &api.RobotAPI{}, "get:GetByName")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)/scope_usage", &api.RobotAPI{}, "get:ScopeUsage")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)/permission_review", &api.RobotAPI{}, "get:PermissionReview;post:ApplyPermissionReview")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)/replicas", &api.RobotAPI{}, "get:Replicas")
+	beego.Router("/api/projects/:pid([0-9]+)/share_links", &api.ShareLinkAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/share_links/:id([0-9]+)", &api.ShareLinkAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/ui_tokens", &api.UITokenAPI{}, "post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/repositories", &api.ProjectRepositoryAPI{}, "post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/freeze_windows", &api.FreezeWindowAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/freeze_windows/:id([0-9]+)", &api.FreezeWindowAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/tag_policy/check", &api.TagPolicyAPI{}, "get:Check")
+	beego.Router("/api/projects/:pid([0-9]+)/usage_policy", &api.UsagePolicyAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/usage_policy/acknowledgment", &api.UsagePolicyAPI{}, "post:Acknowledge")
+	beego.Router("/api/projects/:pid([0-9]+)/invitations", &api.ProjectInvitationAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/invitations/:id([0-9]+)", &api.ProjectInvitationAPI{}, "delete:Delete")
+	beego.Router("/api/invitations/:token", &api.InvitationAPI{}, "get:Get")
+	beego.Router("/api/invitations/:token/accept", &api.InvitationAPI{}, "post:Accept")
+	beego.Router("/api/projects/:pid([0-9]+)/access_requests", &api.AccessRequestAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/access_requests/:id([0-9]+)/approve", &api.AccessRequestAPI{}, "post:Approve")
+	beego.Router("/api/projects/:pid([0-9]+)/access_requests/:id([0-9]+)/deny", &api.AccessRequestAPI{}, "post:Deny")
 
 	beego.Router("/api/repositories", &api.RepositoryAPI{}, "get:Get")
 	beego.Router("/api/repositories/scanAll", &api.RepositoryAPI{}, "post:ScanAll")
+	beego.Router("/api/scan/batch", &api.ScanBatchAPI{}, "post:Post")
+	beego.Router("/api/scan/batch/:id([0-9]+)", &api.ScanBatchAPI{}, "get:Get")
 	beego.Router("/api/repositories/*", &api.RepositoryAPI{}, "delete:Delete;put:Put")
+	beego.Router("/api/repositories/*/rename", &api.RepositoryAPI{}, "put:Rename")
 	beego.Router("/api/repositories/*/labels", &api.RepositoryLabelAPI{}, "get:GetOfRepository;post:AddToRepository")
 	beego.Router("/api/repositories/*/labels/:id([0-9]+)", &api.RepositoryLabelAPI{}, "delete:RemoveFromRepository")
-	beego.Router("/api/repositories/*/tags/:tag", &api.RepositoryAPI{}, "delete:Delete;get:GetTag")
+	beego.Router("/api/repositories/*/tags/:tag", &api.RepositoryAPI{}, "delete:Delete;get:GetTag;head:HeadTag")
 	beego.Router("/api/repositories/*/tags/:tag/labels", &api.RepositoryLabelAPI{}, "get:GetOfImage;post:AddToImage")
 	beego.Router("/api/repositories/*/tags/:tag/labels/:id([0-9]+)", &api.RepositoryLabelAPI{}, "delete:RemoveFromImage")
 	beego.Router("/api/repositories/*/tags", &api.RepositoryAPI{}, "get:GetTags;post:Retag")
 	beego.Router("/api/repositories/*/tags/:tag/scan", &api.RepositoryAPI{}, "post:ScanImage")
 	beego.Router("/api/repositories/*/tags/:tag/vulnerability/details", &api.RepositoryAPI{}, "Get:VulnerabilityDetails")
 	beego.Router("/api/repositories/*/tags/:tag/manifest", &api.RepositoryAPI{}, "get:GetManifests")
+	beego.Router("/api/repositories/*/tags/:tag/history", &api.RepositoryAPI{}, "get:GetTagHistory")
+	beego.Router("/api/repositories/*/tags/:tag/badge.svg", &api.RepositoryAPI{}, "get:GetBadge")
 	beego.Router("/api/repositories/*/signatures", &api.RepositoryAPI{}, "get:GetSignatures")
+	beego.Router("/api/repositories/*/star", &api.RepoSubscriptionAPI{}, "put:Star;delete:Unstar")
+	beego.Router("/api/repositories/*/subscription", &api.RepoSubscriptionAPI{}, "get:GetSubscription;put:SetSubscription;delete:DeleteSubscription")
+	beego.Router("/api/repositories/*/retention", &api.RepoRetentionAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/collaborators", &api.RepoCollaboratorAPI{}, "get:List")
+	beego.Router("/api/repositories/*/collaborators/:username", &api.RepoCollaboratorAPI{}, "put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/storage_hint", &api.RepoStorageHintAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/deprecation", &api.RepoDeprecationAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/tags/:tag/deprecation", &api.RepoDeprecationAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/legal_hold", &api.LegalHoldAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/tags/:tag/legal_hold", &api.LegalHoldAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/repositories/*/vul_exception", &api.VulExceptionAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/legal_holds", &api.LegalHoldAPI{}, "get:List")
+	beego.Router("/api/repositories/*/aliases", &api.TagAliasAPI{}, "get:List")
+	beego.Router("/api/repositories/*/aliases/:alias", &api.TagAliasAPI{}, "put:Put;delete:Delete")
 	beego.Router("/api/repositories/top", &api.RepositoryAPI{}, "get:GetTopRepos")
 	beego.Router("/api/jobs/replication/", &api.RepJobAPI{}, "get:List;put:StopJobs")
+	beego.Router("/api/jobs/replication/report", &api.RepJobAPI{}, "get:Report")
 	beego.Router("/api/jobs/replication/:id([0-9]+)", &api.RepJobAPI{})
 	beego.Router("/api/jobs/replication/:id([0-9]+)/log", &api.RepJobAPI{}, "get:GetLog")
 	beego.Router("/api/jobs/scan/:id([0-9]+)/log", &api.ScanJobAPI{}, "get:GetLog")
@@ -93,6 +159,63 @@ func initRouters() {
 	beego.Router("/api/system/gc/:id", &api.GCAPI{}, "get:GetGC")
 	beego.Router("/api/system/gc/:id([0-9]+)/log", &api.GCAPI{}, "get:GetLog")
 	beego.Router("/api/system/gc/schedule", &api.GCAPI{}, "get:Get;put:Put;post:Post")
+	beego.Router("/api/system/uploads", &api.UploadSessionAPI{}, "get:List;delete:Purge")
+	beego.Router("/api/system/vulnerability_db/imports", &api.VulnDBAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/vulnerability_db/imports/:id([0-9]+)", &api.VulnDBAPI{}, "get:Get")
+	beego.Router("/api/bundles/exports", &api.BundleExportAPI{}, "get:List;post:Post")
+	beego.Router("/api/bundles/exports/:id([0-9]+)", &api.BundleExportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/bundles/exports/:id([0-9]+)/download", &api.BundleExportAPI{}, "get:Download")
+	beego.Router("/api/bundles/import", &api.BundleAPI{}, "post:Import")
+	beego.Router("/api/system/rebuild_index", &api.RebuildIndexAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/rebuild_index/:id([0-9]+)", &api.RebuildIndexAPI{}, "get:Get")
+	beego.Router("/api/system/rebuild_index/:id([0-9]+)/log", &api.RebuildIndexAPI{}, "get:GetLog")
+	beego.Router("/api/system/project_merges", &api.ProjectMergeAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/project_merges/:id([0-9]+)", &api.ProjectMergeAPI{}, "get:Get")
+	beego.Router("/api/system/chart_gc", &api.ChartGCAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/chart_gc/:id([0-9]+)", &api.ChartGCAPI{}, "get:Get")
+	beego.Router("/api/system/chart_gc/:id([0-9]+)/log", &api.ChartGCAPI{}, "get:GetLog")
+	beego.Router("/api/system/reconciliation", &api.ReconciliationAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/reconciliation/:id([0-9]+)", &api.ReconciliationAPI{}, "get:Get")
+	beego.Router("/api/system/reconciliation/:id([0-9]+)/log", &api.ReconciliationAPI{}, "get:GetLog")
+	beego.Router("/api/system/storage_transition", &api.StorageTransitionAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/storage_transition/summary", &api.StorageTransitionAPI{}, "get:Summary")
+	beego.Router("/api/system/storage_transition/:id([0-9]+)", &api.StorageTransitionAPI{}, "get:Get")
+	beego.Router("/api/system/storage_transition/:id([0-9]+)/log", &api.StorageTransitionAPI{}, "get:GetLog")
+	beego.Router("/api/system/blocklist", &api.BlocklistAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/blocklist/:id([0-9]+)", &api.BlocklistAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/system/secrets/reencrypt", &api.SecretReencryptionAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/secrets/reencrypt/:id([0-9]+)", &api.SecretReencryptionAPI{}, "get:Get")
+	beego.Router("/api/system/auth_mode/preflight", &api.AuthModeAPI{}, "get:Preflight")
+	beego.Router("/api/system/auth_mode/migrations", &api.AuthModeAPI{}, "get:List;post:Post")
+	beego.Router("/api/system/auth_mode/migrations/:id([0-9]+)", &api.AuthModeAPI{}, "get:Get")
+	beego.Router("/api/system/features", &api.FeatureAPI{}, "get:List")
+	beego.Router("/api/system/features/:name([a-z0-9_]+)", &api.FeatureAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/system/log_levels", &api.LogLevelAPI{}, "get:List")
+	beego.Router("/api/system/storage", &api.StorageAPI{}, "get:Get")
+	beego.Router("/api/system/storage/validate", &api.StorageAPI{}, "post:Validate")
+	beego.Router("/api/system/log_levels/:name([a-z_]+)", &api.LogLevelAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/system/maintenance", &api.MaintenanceAPI{}, "get:Get;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/compliance_reports", &api.ComplianceReportAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/compliance_reports/:id([0-9]+)", &api.ComplianceReportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/compliance_reports/:id([0-9]+)/download", &api.ComplianceReportAPI{}, "get:Download")
+	beego.Router("/api/projects/:pid([0-9]+)/repository_exports", &api.RepositoryExportAPI{}, "get:List;post:Post")
+	beego.Router("/api/projects/:pid([0-9]+)/repository_exports/:id([0-9]+)", &api.RepositoryExportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/repository_exports/:id([0-9]+)/download", &api.RepositoryExportAPI{}, "get:Download")
+	beego.Router("/api/scanners", &api.ScannerAPI{}, "get:List")
+	beego.Router("/api/scanners/:id/health", &api.ScannerAPI{}, "get:Health")
+	beego.Router("/api/scanners/:id/capabilities", &api.ScannerAPI{}, "get:Capabilities")
+	beego.Router("/api/scanners/:id/ca_bundle", &api.ScannerAPI{}, "get:GetCABundle;put:PutCABundle;delete:DeleteCABundle")
+	beego.Router("/api/vulnerabilities/:cve_id/affected", &api.VulnerabilityAPI{}, "get:Affected")
+	beego.Router("/api/promotion_pipelines", &api.PromotionPipelineAPI{}, "get:List;post:Post")
+	beego.Router("/api/promotion_pipelines/:id([0-9]+)", &api.PromotionPipelineAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/promotions", &api.PromotionAPI{}, "get:List;post:Post")
+	beego.Router("/api/promotions/:id([0-9]+)", &api.PromotionAPI{}, "get:Get")
+	beego.Router("/api/promotions/:id([0-9]+)/approve", &api.PromotionAPI{}, "post:Approve")
+	beego.Router("/api/promotions/:id([0-9]+)/reject", &api.PromotionAPI{}, "post:Reject")
+	beego.Router("/api/approvals", &api.ApprovalAPI{}, "get:List")
+	beego.Router("/api/approvals/:id([0-9]+)", &api.ApprovalAPI{}, "get:Get")
+	beego.Router("/api/approvals/:id([0-9]+)/approve", &api.ApprovalAPI{}, "post:Approve")
+	beego.Router("/api/approvals/:id([0-9]+)/reject", &api.ApprovalAPI{}, "post:Reject")
 
 	beego.Router("/api/policies/replication/:id([0-9]+)", &api.RepPolicyAPI{})
 	beego.Router("/api/policies/replication", &api.RepPolicyAPI{}, "get:List")
@@ -100,25 +223,40 @@ func initRouters() {
 	beego.Router("/api/targets/", &api.TargetAPI{}, "get:List")
 	beego.Router("/api/targets/", &api.TargetAPI{}, "post:Post")
 	beego.Router("/api/targets/:id([0-9]+)", &api.TargetAPI{})
+	beego.Router("/api/targets/by_name/:name", &api.TargetAPI{}, "get:GetByName")
 	beego.Router("/api/targets/:id([0-9]+)/policies/", &api.TargetAPI{}, "get:ListPolicies")
+	beego.Router("/api/targets/:id([0-9]+)/ca_bundle", &api.TargetAPI{}, "get:GetCABundle;put:PutCABundle;delete:DeleteCABundle")
+	beego.Router("/api/targets/:id([0-9]+)/ratelimit", &api.TargetAPI{}, "get:GetRateLimit")
 	beego.Router("/api/targets/ping", &api.TargetAPI{}, "post:Ping")
 	beego.Router("/api/logs", &api.LogAPI{})
 	beego.Router("/api/configs", &api.ConfigAPI{}, "get:GetInternalConfig")
 	beego.Router("/api/configurations", &api.ConfigAPI{})
 	beego.Router("/api/configurations/reset", &api.ConfigAPI{}, "post:Reset")
+	beego.Router("/api/configurations\\:apply", &api.ConfigAPI{}, "put:Apply")
 	beego.Router("/api/statistics", &api.StatisticAPI{})
+	beego.Router("/api/statistics/traffic", &api.StatisticAPI{}, "get:Traffic")
+	beego.Router("/api/statistics/metrics", &api.StatisticAPI{}, "get:Metrics")
+	beego.Router("/api/chargeback/reports", &api.ChargebackReportAPI{}, "get:List;post:Post")
+	beego.Router("/api/chargeback/reports/:id([0-9]+)", &api.ChargebackReportAPI{}, "get:Get;delete:Delete")
+	beego.Router("/api/metadata_sync", &api.MetadataSyncAPI{}, "post:Post")
+	beego.Router("/api/metadata_sync/states", &api.MetadataSyncAPI{}, "get:ListStates")
 	beego.Router("/api/replications", &api.ReplicationAPI{})
+	beego.Router("/api/replication/executions", &api.ReplicationAPI{}, "post:Execute")
+	beego.Router("/api/replication/robots", &api.RobotReplicationAPI{}, "post:Post")
 	beego.Router("/api/labels", &api.LabelAPI{}, "post:Post;get:List")
 	beego.Router("/api/labels/:id([0-9]+)", &api.LabelAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/labels/by_name/:name", &api.LabelAPI{}, "get:GetByName")
 	beego.Router("/api/labels/:id([0-9]+)/resources", &api.LabelAPI{}, "get:ListResources")
 
 	beego.Router("/api/systeminfo", &api.SystemInfoAPI{}, "get:GetGeneralInfo")
 	beego.Router("/api/systeminfo/volumes", &api.SystemInfoAPI{}, "get:GetVolumeInfo")
+	beego.Router("/api/systeminfo/joblogs", &api.SystemInfoAPI{}, "get:GetJobLogUsage")
 	beego.Router("/api/systeminfo/getcert", &api.SystemInfoAPI{}, "get:GetCert")
 
 	beego.Router("/api/internal/syncregistry", &api.InternalAPI{}, "post:SyncRegistry")
 	beego.Router("/api/internal/renameadmin", &api.InternalAPI{}, "post:RenameAdmin")
 	beego.Router("/api/internal/configurations", &api.ConfigAPI{}, "get:GetInternalConfig")
+	beego.Router("/api/internal/metadata_sync/snapshot", &api.MetadataSyncSnapshotAPI{}, "get:Get")
 
 	// external service that hosted on harbor process:
 	beego.Router("/service/notifications", &registry.NotificationHandler{})
@@ -126,7 +264,11 @@ func initRouters() {
 	beego.Router("/service/notifications/jobs/scan/:id([0-9]+)", &jobs.Handler{}, "post:HandleScan")
 	beego.Router("/service/notifications/jobs/replication/:id([0-9]+)", &jobs.Handler{}, "post:HandleReplication")
 	beego.Router("/service/notifications/jobs/adminjob/:id([0-9]+)", &admin.Handler{}, "post:HandleAdminJob")
+	beego.Router("/service/notifications/jobs/task/:name/:id([0-9]+)", &jobs.Handler{}, "post:HandleTask")
+	beego.Router("/service/tasks/:name/:id([0-9]+)", &tasks.Handler{}, "post:Run")
 	beego.Router("/service/token", &token.Handler{})
+	beego.Router("/service/token/exchange", &token.Handler{}, "post:Exchange")
+	beego.Router("/service/admission/validate", &admission.Handler{}, "post:Validate")
 
 	beego.Router("/v2/*", &controllers.RegistryProxy{}, "*:Handle")
 
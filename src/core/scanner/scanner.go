@@ -0,0 +1,165 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scanner manages the scanner adapters registered in Harbor, each project
+// scans its images with the scanner specified in the metadata "scanner" or the
+// default one if it isn't specified.
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// DefaultID is the ID of the scanner used by the projects which don't specify one
+const DefaultID = "clair"
+
+// Scanner is a scanner adapter registered in Harbor
+type Scanner struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Vendor string `json:"vendor"`
+	// JobName is the name of the job registered in jobservice which scans the images
+	JobName string `json:"-"`
+	// enabled returns whether the scanner is deployed
+	enabled func() bool
+}
+
+// Enabled returns whether the scanner is deployed
+func (s *Scanner) Enabled() bool {
+	return s.enabled == nil || s.enabled()
+}
+
+// the registered scanners, the key is the ID of scanner
+var scanners = map[string]*Scanner{
+	DefaultID: {
+		ID:      DefaultID,
+		Name:    "Clair",
+		Vendor:  "CoreOS",
+		JobName: job.ImageScanJob,
+		enabled: config.WithClair,
+	},
+}
+
+// Get returns the enabled scanner specified by ID, nil is returned if the scanner
+// isn't registered or deployed
+func Get(id string) *Scanner {
+	s, ok := scanners[id]
+	if !ok || !s.Enabled() {
+		return nil
+	}
+	return s
+}
+
+// List returns the enabled scanners sorted by ID
+func List() []*Scanner {
+	list := []*Scanner{}
+	for _, s := range scanners {
+		if s.Enabled() {
+			list = append(list, s)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Validate checks whether the ID specified in the project metadata refers to an enabled
+// scanner, the empty ID means the default scanner
+func Validate(id string) error {
+	if len(id) == 0 {
+		return nil
+	}
+	if Get(id) == nil {
+		return fmt.Errorf("scanner %s not found", id)
+	}
+	return nil
+}
+
+// ForProject returns the scanner which scans the images of the project, an error
+// is returned if the scanner isn't enabled
+func ForProject(project *models.Project) (*Scanner, error) {
+	id := DefaultID
+	if project != nil && len(project.Scanner()) > 0 {
+		id = project.Scanner()
+	}
+	s := Get(id)
+	if s == nil {
+		return nil, fmt.Errorf("the scanner %s of project is not enabled", id)
+	}
+	return s, nil
+}
+
+// CABundle returns the decrypted custom CA bundle trusted by the clients of the scanner,
+// it's empty if the scanner has none
+func CABundle(id string) (string, error) {
+	bundle, err := dao.GetScannerCABundle(id)
+	if err != nil {
+		return "", err
+	}
+	if bundle == nil {
+		return "", nil
+	}
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return keyring.Decrypt(bundle.CABundle, key)
+}
+
+// SetCABundle encrypts and stores the custom CA bundle of the scanner, the empty bundle
+// removes the existing one
+func SetCABundle(id, bundle string) error {
+	if len(bundle) == 0 {
+		return dao.DeleteScannerCABundle(id)
+	}
+	key, err := config.SecretKey()
+	if err != nil {
+		return err
+	}
+	encrypted, err := keyring.Encrypt(bundle, key)
+	if err != nil {
+		return err
+	}
+	return dao.SetScannerCABundle(id, encrypted)
+}
+
+// Transport returns the transport of the clients of the scanner, which trusts the custom
+// CA bundle of the scanner in addition to the system ones
+func Transport(id string) (*http.Transport, error) {
+	bundle, err := CABundle(id)
+	if err != nil {
+		return nil, err
+	}
+	return registry.GetHTTPTransportWithCA(false, bundle)
+}
+
+// ClairClient returns the client of Clair with the transport of the default scanner
+func ClairClient() (*clair.Client, error) {
+	transport, err := Transport(DefaultID)
+	if err != nil {
+		return nil, err
+	}
+	return clair.NewClientWithTransport(config.ClairEndpoint(), transport, nil), nil
+}
@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setScanners(enabled bool) func() {
+	origin := scanners
+	scanners = map[string]*Scanner{
+		DefaultID: {
+			ID:      DefaultID,
+			JobName: job.ImageScanJob,
+			enabled: func() bool { return enabled },
+		},
+		"other": {
+			ID:      "other",
+			JobName: "OTHER_SCAN",
+		},
+	}
+	return func() {
+		scanners = origin
+	}
+}
+
+func TestGetAndList(t *testing.T) {
+	defer setScanners(false)()
+
+	assert.Nil(t, Get(DefaultID))
+	assert.Nil(t, Get("unknown"))
+	require.NotNil(t, Get("other"))
+
+	list := List()
+	require.Equal(t, 1, len(list))
+	assert.Equal(t, "other", list[0].ID)
+}
+
+func TestValidate(t *testing.T) {
+	defer setScanners(false)()
+
+	assert.Nil(t, Validate(""))
+	assert.Nil(t, Validate("other"))
+	assert.NotNil(t, Validate("unknown"))
+	// registered but not deployed
+	assert.NotNil(t, Validate(DefaultID))
+}
+
+func TestForProject(t *testing.T) {
+	defer setScanners(true)()
+
+	project := &models.Project{}
+	s, err := ForProject(project)
+	require.Nil(t, err)
+	assert.Equal(t, job.ImageScanJob, s.JobName)
+
+	project.SetMetadata(models.ProMetaScanner, "other")
+	s, err = ForProject(project)
+	require.Nil(t, err)
+	assert.Equal(t, "OTHER_SCAN", s.JobName)
+
+	project.SetMetadata(models.ProMetaScanner, "unknown")
+	_, err = ForProject(project)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,116 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scopeusage
+
+import (
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+)
+
+// ReviewRobot reviews the access granted to the robot against its scope usage in the time
+// range. The pushes need the pull as well, so the pull is kept if either is used, and the
+// suggested access is empty if the robot is used neither
+func ReviewRobot(robot *models.Robot, begin, end *time.Time) (*models.RobotPermissionReview, error) {
+	usage, err := ForRobot(robot, begin, end)
+	if err != nil {
+		return nil, err
+	}
+	review := &models.RobotPermissionReview{
+		RobotID:         robot.ID,
+		RobotName:       robot.Name,
+		BeginTime:       begin,
+		EndTime:         end,
+		GrantKnown:      usage.GrantKnown,
+		GrantedAccess:   []*rbac.Policy{},
+		SuggestedAccess: []*rbac.Policy{},
+	}
+	for _, p := range usage.Projects {
+		if p.ProjectID == robot.ProjectID {
+			review.Usage = p
+			break
+		}
+	}
+	if !usage.GrantKnown {
+		return review, nil
+	}
+	granted, err := robot.GetAccess()
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	if review.Usage != nil {
+		for _, action := range review.Usage.UsedActions {
+			used[action] = true
+		}
+	}
+	review.GrantedAccess = granted
+	review.SuggestedAccess, review.Downgrade = suggest(granted, used[actionPull] || used[actionPush], used[actionPush])
+	return review, nil
+}
+
+// suggest returns the minimal access covering the pulls and pushes needed, and whether it's
+// narrower than the granted access
+func suggest(granted []*rbac.Policy, pull, push bool) ([]*rbac.Policy, bool) {
+	suggested := []*rbac.Policy{}
+	narrowed := false
+	exist := map[string]bool{}
+	add := func(policy *rbac.Policy) {
+		key := policy.Resource.String() + "|" + policy.Action.String() + "|" + policy.GetEffect()
+		if exist[key] {
+			narrowed = true
+			return
+		}
+		exist[key] = true
+		suggested = append(suggested, policy)
+	}
+	for _, policy := range granted {
+		if policy.GetEffect() != rbac.EffectAllow.String() ||
+			!strings.HasSuffix(policy.Resource.String(), "/"+rbac.ResourceRepository.String()) {
+			add(policy)
+			continue
+		}
+		var actions []rbac.Action
+		switch policy.Action {
+		case rbac.ActionPull:
+			if pull {
+				actions = []rbac.Action{rbac.ActionPull}
+			}
+		case rbac.ActionPush:
+			if push {
+				actions = []rbac.Action{rbac.ActionPush}
+			}
+		case rbac.ActionPushPull:
+			if push {
+				actions = []rbac.Action{rbac.ActionPushPull}
+			} else if pull {
+				actions = []rbac.Action{rbac.ActionPull}
+			}
+		default:
+			actions = []rbac.Action{policy.Action}
+		}
+		if len(actions) == 0 || actions[0] != policy.Action {
+			narrowed = true
+		}
+		for _, action := range actions {
+			p := *policy
+			p.Action = action
+			add(&p)
+		}
+	}
+	return suggested, narrowed
+}
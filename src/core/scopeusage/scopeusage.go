@@ -0,0 +1,237 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scopeusage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/project"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/accesslog"
+)
+
+const (
+	actionPull = string(rbac.ActionPull)
+	actionPush = string(rbac.ActionPush)
+)
+
+// grant is the permissions held in a project
+type grant struct {
+	role    string
+	actions map[string]bool
+}
+
+// ForUser reports the scope usage of the user in the time range, the permissions are
+// the ones of the memberships of the user, the ones through the groups aren't included
+func ForUser(user *models.User, begin, end *time.Time) (*models.ScopeUsage, error) {
+	members, err := project.GetUserMemberships(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	grants := map[int64]*grant{}
+	for _, member := range members {
+		g := &grant{
+			role:    member.Rolename,
+			actions: map[string]bool{actionPull: true},
+		}
+		if member.Role != models.GUEST {
+			g.actions[actionPush] = true
+		}
+		grants[member.ProjectID] = g
+	}
+	usage := &models.ScopeUsage{
+		Identity:   user.Username,
+		Kind:       models.ScopeUsageUser,
+		BeginTime:  begin,
+		EndTime:    end,
+		SysAdmin:   user.HasAdminRole,
+		GrantKnown: true,
+	}
+	return report(usage, grants)
+}
+
+// ForRobot reports the scope usage of the robot in the time range, the permissions are
+// the access granted to the robot on the repositories of its project
+func ForRobot(robot *models.Robot, begin, end *time.Time) (*models.ScopeUsage, error) {
+	access, err := robot.GetAccess()
+	if err != nil {
+		return nil, err
+	}
+	usage := &models.ScopeUsage{
+		Identity:   robot.Name,
+		Kind:       models.ScopeUsageRobot,
+		BeginTime:  begin,
+		EndTime:    end,
+		GrantKnown: access != nil,
+	}
+	grants := map[int64]*grant{}
+	if access != nil {
+		g := &grant{
+			actions: map[string]bool{},
+		}
+		for _, policy := range access {
+			if policy.GetEffect() != rbac.EffectAllow.String() ||
+				!strings.HasSuffix(policy.Resource.String(), "/"+rbac.ResourceRepository.String()) {
+				continue
+			}
+			switch policy.Action {
+			case rbac.ActionPull:
+				g.actions[actionPull] = true
+			case rbac.ActionPush:
+				g.actions[actionPush] = true
+			case rbac.ActionPushPull:
+				g.actions[actionPull] = true
+				g.actions[actionPush] = true
+			}
+		}
+		grants[robot.ProjectID] = g
+	}
+	return report(usage, grants)
+}
+
+func report(usage *models.ScopeUsage, grants map[int64]*grant) (*models.ScopeUsage, error) {
+	logs, err := accesslog.List(&models.LogQueryParam{
+		Username:   usage.Identity,
+		Operations: []string{actionPull, actionPush},
+		BeginTime:  usage.BeginTime,
+		EndTime:    usage.EndTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int64{}
+	for id := range grants {
+		ids = append(ids, id)
+	}
+	for _, entry := range logs {
+		if _, ok := grants[entry.ProjectID]; !ok {
+			ids = append(ids, entry.ProjectID)
+		}
+	}
+	names := map[int64]string{}
+	if len(ids) > 0 {
+		projects, err := dao.GetProjects(&models.ProjectQueryParam{
+			ProjectIDs: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			names[p.ProjectID] = p.Name
+		}
+	}
+	usage.Projects = build(usage.Identity, grants, names, logs)
+	return usage, nil
+}
+
+// build aggregates the access logs of the identity per project and repository and compares
+// the used actions with the granted ones. The username of the logs is matched fuzzily by
+// the store, so the logs of other identities are skipped
+func build(identity string, grants map[int64]*grant, names map[int64]string, logs []models.AccessLog) []*models.ProjectScopeUsage {
+	projects := map[int64]*models.ProjectScopeUsage{}
+	repositories := map[int64]map[string]*models.RepoScopeUsage{}
+	get := func(id int64) *models.ProjectScopeUsage {
+		p, ok := projects[id]
+		if !ok {
+			p = &models.ProjectScopeUsage{
+				ProjectID:      id,
+				ProjectName:    names[id],
+				GrantedActions: []string{},
+				UsedActions:    []string{},
+				UnusedActions:  []string{},
+				Repositories:   []*models.RepoScopeUsage{},
+			}
+			projects[id] = p
+			repositories[id] = map[string]*models.RepoScopeUsage{}
+		}
+		return p
+	}
+	for id := range grants {
+		get(id)
+	}
+
+	used := map[int64]map[string]bool{}
+	for i := range logs {
+		entry := &logs[i]
+		if entry.Username != identity || (entry.Operation != actionPull && entry.Operation != actionPush) {
+			continue
+		}
+		p := get(entry.ProjectID)
+		// the project has been deleted
+		if len(p.ProjectName) == 0 {
+			p.ProjectName, _ = utils.ParseRepository(entry.RepoName)
+		}
+		repo, ok := repositories[entry.ProjectID][entry.RepoName]
+		if !ok {
+			repo = &models.RepoScopeUsage{
+				Repository: entry.RepoName,
+			}
+			repositories[entry.ProjectID][entry.RepoName] = repo
+			p.Repositories = append(p.Repositories, repo)
+		}
+		opTime := entry.OpTime
+		if entry.Operation == actionPull {
+			repo.PullCount++
+			if repo.LastPullTime == nil || opTime.After(*repo.LastPullTime) {
+				repo.LastPullTime = &opTime
+			}
+		} else {
+			repo.PushCount++
+			if repo.LastPushTime == nil || opTime.After(*repo.LastPushTime) {
+				repo.LastPushTime = &opTime
+			}
+		}
+		if used[entry.ProjectID] == nil {
+			used[entry.ProjectID] = map[string]bool{}
+		}
+		used[entry.ProjectID][entry.Operation] = true
+	}
+
+	result := []*models.ProjectScopeUsage{}
+	for id, p := range projects {
+		g := grants[id]
+		if g != nil {
+			p.Role = g.role
+		}
+		for _, action := range []string{actionPull, actionPush} {
+			granted := g != nil && g.actions[action]
+			if granted {
+				p.GrantedActions = append(p.GrantedActions, action)
+			}
+			if used[id][action] {
+				p.UsedActions = append(p.UsedActions, action)
+			} else if granted {
+				p.UnusedActions = append(p.UnusedActions, action)
+			}
+		}
+		sort.Slice(p.Repositories, func(i, j int) bool {
+			return p.Repositories[i].Repository < p.Repositories[j].Repository
+		})
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ProjectName != result[j].ProjectName {
+			return result[i].ProjectName < result[j].ProjectName
+		}
+		return result[i].ProjectID < result[j].ProjectID
+	})
+	return result
+}
@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scopeusage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	t1 := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	grants := map[int64]*grant{
+		1: {role: "developer", actions: map[string]bool{actionPull: true, actionPush: true}},
+		2: {role: "guest", actions: map[string]bool{actionPull: true}},
+	}
+	names := map[int64]string{1: "library", 2: "team", 3: "public"}
+	logs := []models.AccessLog{
+		{Username: "ci", ProjectID: 1, RepoName: "library/app", Operation: "pull", OpTime: t1},
+		{Username: "ci", ProjectID: 1, RepoName: "library/app", Operation: "pull", OpTime: t2},
+		{Username: "ci", ProjectID: 1, RepoName: "library/db", Operation: "pull", OpTime: t1},
+		// the public project
+		{Username: "ci", ProjectID: 3, RepoName: "public/base", Operation: "pull", OpTime: t1},
+		// the deleted project
+		{Username: "ci", ProjectID: 4, RepoName: "deleted/app", Operation: "push", OpTime: t1},
+		// matched fuzzily by the store
+		{Username: "ci2", ProjectID: 2, RepoName: "team/app", Operation: "pull", OpTime: t1},
+		{Username: "ci", ProjectID: 2, RepoName: "team/app", Operation: "delete", OpTime: t1},
+	}
+
+	projects := build("ci", grants, names, logs)
+	require.Equal(t, 4, len(projects))
+
+	deleted := projects[0]
+	assert.Equal(t, "deleted", deleted.ProjectName)
+	assert.Equal(t, []string{}, deleted.GrantedActions)
+	assert.Equal(t, []string{"push"}, deleted.UsedActions)
+
+	library := projects[1]
+	assert.Equal(t, "library", library.ProjectName)
+	assert.Equal(t, "developer", library.Role)
+	assert.Equal(t, []string{"pull", "push"}, library.GrantedActions)
+	assert.Equal(t, []string{"pull"}, library.UsedActions)
+	assert.Equal(t, []string{"push"}, library.UnusedActions)
+	require.Equal(t, 2, len(library.Repositories))
+	assert.Equal(t, "library/app", library.Repositories[0].Repository)
+	assert.Equal(t, int64(2), library.Repositories[0].PullCount)
+	assert.Equal(t, t2, *library.Repositories[0].LastPullTime)
+	assert.Nil(t, library.Repositories[0].LastPushTime)
+
+	public := projects[2]
+	assert.Equal(t, "public", public.ProjectName)
+	assert.Equal(t, []string{}, public.GrantedActions)
+	assert.Equal(t, []string{"pull"}, public.UsedActions)
+
+	team := projects[3]
+	assert.Equal(t, "team", team.ProjectName)
+	assert.Equal(t, []string{}, team.UsedActions)
+	assert.Equal(t, []string{"pull"}, team.UnusedActions)
+	assert.Equal(t, 0, len(team.Repositories))
+}
+
+func TestSuggest(t *testing.T) {
+	granted := []*rbac.Policy{
+		{Resource: "/project/1/repository", Action: rbac.ActionPushPull},
+		{Resource: "/project/1/helm-chart", Action: rbac.ActionRead},
+	}
+
+	// only pulled
+	suggested, downgrade := suggest(granted, true, false)
+	assert.True(t, downgrade)
+	require.Equal(t, 2, len(suggested))
+	assert.Equal(t, rbac.ActionPull, suggested[0].Action)
+	assert.Equal(t, rbac.ActionRead, suggested[1].Action)
+
+	// pushed
+	suggested, downgrade = suggest(granted, true, true)
+	assert.False(t, downgrade)
+	assert.Equal(t, granted, suggested)
+
+	// unused, the access which isn't observed is kept
+	suggested, downgrade = suggest(granted, false, false)
+	assert.True(t, downgrade)
+	require.Equal(t, 1, len(suggested))
+	assert.Equal(t, rbac.Resource("/project/1/helm-chart"), suggested[0].Resource)
+}
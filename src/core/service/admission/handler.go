@@ -0,0 +1,288 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/proxy"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+const (
+	admissionAPIVersion = "admission.k8s.io/v1beta1"
+	admissionKind       = "AdmissionReview"
+	defaultTag          = "latest"
+	// the reason returned to the callers which aren't authenticated
+	deniedMessage = "denied by Harbor"
+)
+
+// Review is the AdmissionReview of the validating admission webhook of Kubernetes
+type Review struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Request    *Request  `json:"request,omitempty"`
+	Response   *Response `json:"response,omitempty"`
+}
+
+// Request is the admission request of the object to be validated
+type Request struct {
+	UID    string          `json:"uid"`
+	Kind   Kind            `json:"kind"`
+	Object json.RawMessage `json:"object"`
+}
+
+// Kind is the group, version and kind of the object
+type Kind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Response is the admission response which allows or denies the object
+type Response struct {
+	UID     string  `json:"uid"`
+	Allowed bool    `json:"allowed"`
+	Result  *Status `json:"status,omitempty"`
+}
+
+// Status carries the reason why the object is denied
+type Status struct {
+	Message string `json:"message"`
+}
+
+type container struct {
+	Image string `json:"image"`
+}
+
+type podSpec struct {
+	Containers     []container `json:"containers"`
+	InitContainers []container `json:"initContainers"`
+}
+
+type podTemplate struct {
+	Spec podSpec `json:"spec"`
+}
+
+// object covers the pods, the workloads with pod template and the cron jobs
+type object struct {
+	Spec struct {
+		podSpec
+		Template    *podTemplate `json:"template"`
+		JobTemplate *struct {
+			Spec struct {
+				Template podTemplate `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// Handler handles the requests on /service/admission/validate, which is called by the
+// validating admission webhook of Kubernetes to check the images of the pods
+type Handler struct {
+	api.BaseController
+}
+
+// Validate allows the object only when all its images hosted by Harbor exist and comply with
+// the content trust and vulnerability policies of the projects, the images hosted by other
+// registries are allowed unless the query parameter "deny_external" is true. The webhook must
+// send the configured secret as the bearer token, the objects of the other callers are denied
+// with a generic reason so that they can't learn the images and the policies from it
+func (h *Handler) Validate() {
+	review := &Review{}
+	h.DecodeJSONReq(review)
+	if review.Request == nil {
+		h.HandleBadRequest("the request of the admission review is required")
+		return
+	}
+
+	denyExternal, err := h.GetBool("deny_external", false)
+	if err != nil {
+		h.HandleBadRequest(fmt.Sprintf("invalid deny_external: %s", h.GetString("deny_external")))
+		return
+	}
+
+	response := &Response{
+		UID:     review.Request.UID,
+		Allowed: true,
+	}
+	secret, err := config.AdmissionWebhookSecret()
+	if err != nil {
+		log.Errorf("failed to get the secret of the admission webhook: %v", err)
+	}
+	if !authenticated(h.Ctx.Request.Header.Get("Authorization"), secret) {
+		log.Warningf("the admission review %s from %s isn't authenticated, denied", review.Request.UID, h.Ctx.Input.IP())
+		response.Allowed = false
+		response.Result = &Status{
+			Message: deniedMessage,
+		}
+	} else if reasons := h.check(review.Request, denyExternal); len(reasons) > 0 {
+		response.Allowed = false
+		response.Result = &Status{
+			Message: strings.Join(reasons, "; "),
+		}
+	}
+
+	apiVersion := review.APIVersion
+	if len(apiVersion) == 0 {
+		apiVersion = admissionAPIVersion
+	}
+	h.WriteJSONData(&Review{
+		APIVersion: apiVersion,
+		Kind:       admissionKind,
+		Response:   response,
+	})
+}
+
+// authenticated returns whether the authorization header carries the secret as the bearer
+// token, nothing is authenticated if the secret isn't configured
+func authenticated(authorization, secret string) bool {
+	auth := strings.SplitN(authorization, " ", 2)
+	if len(secret) == 0 || len(auth) != 2 || !strings.EqualFold(auth[0], "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(auth[1]), []byte(secret)) == 1
+}
+
+// check returns the reasons why the images of the object are denied
+func (h *Handler) check(req *Request, denyExternal bool) []string {
+	images, err := podImages(req.Object)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to parse the %s: %v", req.Kind.Kind, err)}
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	host, err := config.ExtURL()
+	if err != nil {
+		log.Errorf("failed to get the external URL: %v", err)
+		return []string{"failed to get the host of Harbor"}
+	}
+
+	reasons := []string{}
+	for _, image := range images {
+		repository, ref, hosted, err := parseImage(image, host)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid image %s: %v", image, err))
+			continue
+		}
+		if !hosted {
+			if denyExternal {
+				reasons = append(reasons, fmt.Sprintf("image %s isn't hosted by Harbor", image))
+			}
+			continue
+		}
+		if reason := checkImage(repository, ref); len(reason) > 0 {
+			reasons = append(reasons, fmt.Sprintf("image %s: %s", image, reason))
+		}
+	}
+	return reasons
+}
+
+// checkImage returns the reason why the image is denied, the image is denied
+// as well if it can't be checked
+func checkImage(repository, ref string) string {
+	client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		log.Errorf("failed to create the client of repository %s: %v", repository, err)
+		return "failed to check the image"
+	}
+	digest, exist, err := client.ManifestExist(ref)
+	if err != nil {
+		log.Errorf("failed to check the existence of %s:%s: %v", repository, ref, err)
+		return "failed to check the image"
+	}
+	if !exist {
+		return "not found in Harbor"
+	}
+
+	reason, err := proxy.CheckImagePolicy(repository, ref, digest)
+	if err != nil {
+		log.Errorf("failed to check the policies of %s:%s: %v", repository, ref, err)
+		return "failed to check the image"
+	}
+	return reason
+}
+
+// podImages returns the images of the containers and init containers in the pod spec of the object
+func podImages(data json.RawMessage) ([]string, error) {
+	obj := &object{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	specs := []podSpec{obj.Spec.podSpec}
+	if obj.Spec.Template != nil {
+		specs = append(specs, obj.Spec.Template.Spec)
+	}
+	if obj.Spec.JobTemplate != nil {
+		specs = append(specs, obj.Spec.JobTemplate.Spec.Template.Spec)
+	}
+
+	images := []string{}
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		for _, c := range append(spec.InitContainers, spec.Containers...) {
+			if len(c.Image) == 0 || seen[c.Image] {
+				continue
+			}
+			seen[c.Image] = true
+			images = append(images, c.Image)
+		}
+	}
+	return images, nil
+}
+
+// parseImage parses the image into the repository and the reference, which is the digest
+// if it's specified or the tag, hosted indicates whether the image is hosted by Harbor
+func parseImage(image, harborHost string) (repository, ref string, hosted bool, err error) {
+	named, err := reference.ParseNamed(image)
+	if err != nil {
+		return "", "", false, err
+	}
+	// the image is hosted by Harbor only when the first component
+	// of the name is the host of Harbor
+	parts := strings.SplitN(named.Name(), "/", 2)
+	if len(parts) != 2 || trimDefaultPort(parts[0]) != trimDefaultPort(harborHost) {
+		return "", "", false, nil
+	}
+
+	repository = parts[1]
+	ref = defaultTag
+	if digested, ok := named.(reference.Digested); ok {
+		ref = digested.Digest().String()
+	} else if tagged, ok := named.(reference.Tagged); ok {
+		ref = tagged.Tag()
+	}
+	return repository, ref, true, nil
+}
+
+func trimDefaultPort(host string) string {
+	host = strings.ToLower(host)
+	for _, port := range []string{":443", ":80"} {
+		host = strings.TrimSuffix(host, port)
+	}
+	return host
+}
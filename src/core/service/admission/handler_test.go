@@ -0,0 +1,93 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodImages(t *testing.T) {
+	cases := []struct {
+		object string
+		images []string
+	}{
+		{`{}`, []string{}},
+		{
+			`{"spec":{"initContainers":[{"image":"harbor.test/library/init:1.0"}],"containers":[{"image":"nginx"},{"image":"nginx"}]}}`,
+			[]string{"harbor.test/library/init:1.0", "nginx"},
+		},
+		{
+			`{"spec":{"replicas":2,"template":{"spec":{"containers":[{"image":"harbor.test/library/app:v1"}]}}}}`,
+			[]string{"harbor.test/library/app:v1"},
+		},
+		{
+			`{"spec":{"schedule":"* * * * *","jobTemplate":{"spec":{"template":{"spec":{"containers":[{"image":"busybox:1.30"}]}}}}}}`,
+			[]string{"busybox:1.30"},
+		},
+	}
+	for _, c := range cases {
+		images, err := podImages(json.RawMessage(c.object))
+		require.Nil(t, err)
+		assert.Equal(t, c.images, images)
+	}
+
+	_, err := podImages(json.RawMessage(`{"spec":[]}`))
+	assert.NotNil(t, err)
+}
+
+func TestParseImage(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	cases := []struct {
+		image      string
+		host       string
+		repository string
+		ref        string
+		hosted     bool
+	}{
+		{"nginx", "harbor.test", "", "", false},
+		{"docker.io/library/nginx:latest", "harbor.test", "", "", false},
+		{"harbor.test/library/nginx", "harbor.test", "library/nginx", "latest", true},
+		{"harbor.test/library/nginx:1.15", "harbor.test", "library/nginx", "1.15", true},
+		{"harbor.test:443/library/nginx@" + digest, "harbor.test", "library/nginx", digest, true},
+		{"harbor.test/library/nginx:1.15@" + digest, "harbor.test:443", "library/nginx", digest, true},
+		{"harbor.test:8443/library/nginx", "harbor.test:8443", "library/nginx", "latest", true},
+		{"harbor.test/library/nginx", "harbor.test:8443", "", "", false},
+	}
+	for _, c := range cases {
+		repository, ref, hosted, err := parseImage(c.image, c.host)
+		require.Nil(t, err)
+		assert.Equal(t, c.repository, repository, c.image)
+		assert.Equal(t, c.ref, ref, c.image)
+		assert.Equal(t, c.hosted, hosted, c.image)
+	}
+
+	_, _, _, err := parseImage("harbor.test/Library/nginx", "harbor.test")
+	assert.NotNil(t, err)
+}
+
+func TestAuthenticated(t *testing.T) {
+	assert.True(t, authenticated("Bearer s3cret", "s3cret"))
+	assert.True(t, authenticated("bearer s3cret", "s3cret"))
+	assert.False(t, authenticated("Bearer other", "s3cret"))
+	assert.False(t, authenticated("Basic s3cret", "s3cret"))
+	assert.False(t, authenticated("", "s3cret"))
+	// nothing is authenticated without the secret
+	assert.False(t, authenticated("Bearer ", ""))
+	assert.False(t, authenticated("", ""))
+}
@@ -38,9 +38,10 @@ var statusMap = map[string]string{
 // Handler handles reqeust on /service/notifications/jobs/adminjob/*, which listens to the webhook of jobservice.
 type Handler struct {
 	api.BaseController
-	id     int64
-	UUID   string
-	status string
+	id      int64
+	UUID    string
+	status  string
+	checkIn string
 }
 
 // Prepare ...
@@ -68,6 +69,7 @@ func (h *Handler) Prepare() {
 		return
 	}
 	h.status = status
+	h.checkIn = data.CheckIn
 }
 
 // HandleAdminJob handles the webhook of admin jobs
@@ -83,4 +85,12 @@ func (h *Handler) HandleAdminJob() {
 		h.HandleInternalServerError(err.Error())
 		return
 	}
+	// the job checks in the detailed progress when it's running
+	if len(h.checkIn) > 0 {
+		if err := dao.UpdateAdminJobProgress(h.id, h.checkIn); err != nil {
+			log.Errorf("Failed to update job progress, id: %d, progress: %s", h.id, h.checkIn)
+			h.HandleInternalServerError(err.Error())
+			return
+		}
+	}
 }
@@ -25,16 +25,13 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/api"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/scanner"
 )
 
 const (
 	rescanInterval = 15 * time.Minute
 )
 
-var (
-	clairClient *clair.Client
-)
-
 // Handler handles reqeust on /service/notifications/clair/, which listens to clair's notifications.
 // When there's unexpected error it will silently fail without removing the notification such that it will be triggered again.
 type Handler struct {
@@ -43,8 +40,10 @@ type Handler struct {
 
 // Handle ...
 func (h *Handler) Handle() {
-	if clairClient == nil {
-		clairClient = clair.NewClient(config.ClairEndpoint(), nil)
+	clairClient, err := scanner.ClairClient()
+	if err != nil {
+		log.Errorf("Failed to create the client of Clair: %v", err)
+		return
 	}
 	var ne models.ClairNotificationEnvelope
 	if err := json.Unmarshal(h.Ctx.Input.CopyBody(1<<32), &ne); err != nil {
@@ -87,8 +86,17 @@ func (h *Handler) Handle() {
 				log.Errorf("Failed to list scan overview records, error: %v", err)
 				return
 			}
+			cvssSource, err := config.CVSSSource()
+			if err != nil {
+				log.Errorf("Failed to get the source of CVSS, error: %v", err)
+				return
+			}
 			for _, e := range l {
-				if err := clair.UpdateScanOverview(e.Digest, e.DetailsKey, config.ClairEndpoint()); err != nil {
+				res, err := clairClient.GetResult(e.DetailsKey)
+				if err == nil {
+					err = clair.UpdateScanResult(e.Digest, e.DetailsKey, res, cvssSource)
+				}
+				if err != nil {
 					log.Errorf("Failed to refresh scan overview for image: %s", e.Digest)
 				} else {
 					log.Debugf("Refreshed scan overview for record with digest: %s", e.Digest)
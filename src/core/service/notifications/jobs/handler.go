@@ -16,6 +16,7 @@ package jobs
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/job"
@@ -23,6 +24,9 @@ import (
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/coretask"
+	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/ratelimit"
 )
 
 var statusMap = map[string]string{
@@ -38,8 +42,9 @@ var statusMap = map[string]string{
 // Handler handles reqeust on /service/notifications/jobs/*, which listens to the webhook of jobservice.
 type Handler struct {
 	api.BaseController
-	id     int64
-	status string
+	id      int64
+	status  string
+	checkIn string
 }
 
 // Prepare ...
@@ -66,6 +71,7 @@ func (h *Handler) Prepare() {
 		return
 	}
 	h.status = status
+	h.checkIn = data.CheckIn
 }
 
 // HandleScan handles the webhook of scan job
@@ -76,14 +82,93 @@ func (h *Handler) HandleScan() {
 		h.HandleInternalServerError(err.Error())
 		return
 	}
+	if h.status == models.JobFinished {
+		go notifyScanDone(h.id)
+	}
+}
+
+// notifyScanDone notifies the subscribers of the repository that the scan job is done, and
+// if critical vulnerabilities are found by it
+func notifyScanDone(jobID int64) {
+	scanJob, err := dao.GetScanJob(jobID)
+	if err != nil || scanJob == nil {
+		log.Errorf("Failed to get scan job %d: %v", jobID, err)
+		return
+	}
+	notifier.NotifyRepoSubscribers(scanJob.Repository, models.SubscriptionEventScanDone,
+		fmt.Sprintf("Harbor: scan of %s:%s done", scanJob.Repository, scanJob.Tag),
+		fmt.Sprintf("The scan job %d of the image %s:%s is done.", jobID, scanJob.Repository, scanJob.Tag))
+
+	overview, err := dao.GetImgScanOverview(scanJob.Digest)
+	if err != nil || overview == nil {
+		log.Errorf("Failed to get scan overview of image %s: %v", scanJob.Digest, err)
+		return
+	}
+	if models.Severity(overview.Sev) < models.SevHigh {
+		return
+	}
+	notifier.NotifyRepoSubscribers(scanJob.Repository, models.SubscriptionEventCriticalCVE,
+		fmt.Sprintf("Harbor: critical vulnerabilities found in %s:%s", scanJob.Repository, scanJob.Tag),
+		fmt.Sprintf("Critical vulnerabilities are found in the image %s:%s by the scan job %d.",
+			scanJob.Repository, scanJob.Tag, jobID))
 }
 
 // HandleReplication handles the webhook of replication job
 func (h *Handler) HandleReplication() {
 	log.Debugf("received replication job status update event: job-%d, status-%s", h.id, h.status)
+	// the job checks in the report when it completes, the status isn't updated as
+	// the final status may be received before the check in
+	if len(h.checkIn) > 0 {
+		report := &models.RepJobReport{}
+		if err := json.Unmarshal([]byte(h.checkIn), report); err != nil {
+			log.Errorf("Failed to decode the report of replication job %d: %v", h.id, err)
+			return
+		}
+		ratelimit.Record(report.Endpoint, report.RateLimit)
+		if err := dao.UpdateRepJobReport(h.id, report); err != nil {
+			log.Errorf("Failed to update job report, id: %d: %v", h.id, err)
+			h.HandleInternalServerError(err.Error())
+		}
+		return
+	}
 	if err := dao.UpdateRepJobStatus(h.id, h.status); err != nil {
 		log.Errorf("Failed to update job status, id: %d, status: %s", h.id, h.status)
 		h.HandleInternalServerError(err.Error())
 		return
 	}
+	if h.status == models.JobError {
+		go notifyReplicationFailed(h.id)
+	}
+}
+
+// HandleTask handles the webhook of the job running the task of core, the task is marked as
+// failed once jobservice gives up retrying it
+func (h *Handler) HandleTask() {
+	name := h.GetStringFromPath(":name")
+	log.Debugf("received task job status update event: task-%s-%d, status-%s", name, h.id, h.status)
+	if h.status != models.JobError && h.status != models.JobStopped && h.status != models.JobCanceled {
+		return
+	}
+	runner := coretask.Get(name)
+	if runner == nil {
+		log.Errorf("Unknown task %s, job status update event of task %d dropped", name, h.id)
+		return
+	}
+	if err := runner.Fail(h.id, fmt.Sprintf("the job running the task is %s", h.status)); err != nil {
+		log.Errorf("Failed to fail the task %s %d: %v", name, h.id, err)
+		h.HandleInternalServerError(err.Error())
+	}
+}
+
+// notifyReplicationFailed notifies the system admins, who manage the replication policies,
+// that the replication job fails
+func notifyReplicationFailed(jobID int64) {
+	repJob, err := dao.GetRepJob(jobID)
+	if err != nil || repJob == nil {
+		log.Errorf("Failed to get replication job %d: %v", jobID, err)
+		return
+	}
+	notifier.NotifySysAdmins(models.UserNotificationReplicationFailed,
+		fmt.Sprintf("The replication job %d of the repository %s of policy %d failed.",
+			jobID, repJob.Repository, repJob.PolicyID))
 }
@@ -0,0 +1,316 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/uuid"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+var (
+	// the events are held in the buffer for the window, so that the ones delivered
+	// out of order within it are processed in the order of their timestamps
+	reorderWindow = 2 * time.Second
+	// the IDs of the processed events are persisted for the window to drop the ones
+	// delivered again by registry
+	dedupeWindow = 24 * time.Hour
+	// the instance refreshes the heartbeat of the pending events it holds in the interval,
+	// the ones whose heartbeat isn't refreshed for the timeout are regarded as left by the
+	// instances stopped before processing them, and are taken over
+	heartbeatInterval = time.Minute
+	heartbeatTimeout  = 5 * time.Minute
+	// the times an event is processed before it's dropped
+	maxAttempts = 3
+	// the functions persisting the events, replaced in testing
+	markEvent      = dao.AddRegistryEvent
+	releaseEvent   = dao.DeleteRegistryEvent
+	pruneEvents    = dao.DeleteRegistryEventsBefore
+	savePending    = dao.AddPendingRegistryEvent
+	refreshPending = dao.RefreshPendingRegistryEvents
+	adoptPending   = dao.TakeOverPendingRegistryEvents
+	removePending  = dao.DeletePendingRegistryEvent
+)
+
+type pendingEvent struct {
+	// the ID of the persisted pending event
+	id         int64
+	event      *models.Event
+	receivedAt time.Time
+	attempts   int
+}
+
+// eventBuffer is the reorder buffer of the events of registry, the events pending in it
+// are persisted, sorted by their timestamps and the duplicate ones are dropped. The events
+// of different repositories are processed concurrently, the ones of a repository in order
+type eventBuffer struct {
+	sync.Mutex
+	// the ID of the instance, it owns the persisted pending events held by the buffer
+	owner   string
+	pending []*pendingEvent
+	ids     map[string]struct{}
+	// the IDs of the persisted pending events held by the buffer
+	held map[int64]struct{}
+	// the repositories whose events are being processed
+	running  map[string]bool
+	inflight sync.WaitGroup
+	process  func(*models.Event) error
+}
+
+func newEventBuffer(process func(*models.Event) error) *eventBuffer {
+	return &eventBuffer{
+		owner:   uuid.Generate().String(),
+		ids:     map[string]struct{}{},
+		held:    map[int64]struct{}{},
+		running: map[string]bool{},
+		process: process,
+	}
+}
+
+// Add persists the events and adds them into the buffer, the ones pending in it already are
+// dropped. The notification mustn't be acknowledged if an error is returned, so that registry
+// delivers it again
+func (b *eventBuffer) Add(events ...*models.Event) error {
+	now := time.Now()
+	b.Lock()
+	defer b.Unlock()
+	defer b.sort()
+	for _, event := range events {
+		if len(event.ID) > 0 {
+			if _, exist := b.ids[event.ID]; exist {
+				log.Debugf("the event %s is pending already, skip", event.ID)
+				continue
+			}
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		id, err := savePending(&models.PendingRegistryEvent{
+			EventID:    event.ID,
+			Repository: repositoryOf(event),
+			Event:      string(data),
+			Owner:      b.owner,
+		})
+		if err != nil {
+			return err
+		}
+		b.hold(id, event, now)
+	}
+	return nil
+}
+
+// hold must be called with the lock held
+func (b *eventBuffer) hold(id int64, event *models.Event, receivedAt time.Time) {
+	if len(event.ID) > 0 {
+		b.ids[event.ID] = struct{}{}
+	}
+	b.held[id] = struct{}{}
+	b.pending = append(b.pending, &pendingEvent{
+		id:         id,
+		event:      event,
+		receivedAt: receivedAt,
+	})
+}
+
+func repositoryOf(event *models.Event) string {
+	if event.Target == nil {
+		return ""
+	}
+	return event.Target.Repository
+}
+
+// sort must be called with the lock held
+func (b *eventBuffer) sort() {
+	sort.SliceStable(b.pending, func(i, j int) bool {
+		return b.pending[i].event.TimeStamp.Before(b.pending[j].event.TimeStamp)
+	})
+}
+
+// takeOver transfers the persisted pending events whose heartbeat is before the time to the
+// instance and adds them into the buffer, they're left by the instances stopped before
+// processing them
+func (b *eventBuffer) takeOver(before time.Time) {
+	events, err := adoptPending(b.owner, before)
+	if err != nil {
+		log.Errorf("failed to list the pending events of registry: %v", err)
+		return
+	}
+	now := time.Now()
+	b.Lock()
+	defer b.Unlock()
+	defer b.sort()
+	for _, pending := range events {
+		if _, exist := b.held[pending.ID]; exist {
+			continue
+		}
+		event := &models.Event{}
+		if err = json.Unmarshal([]byte(pending.Event), event); err != nil {
+			log.Errorf("failed to unmarshal the pending event %d, drop it: %v", pending.ID, err)
+			b.remove(pending.ID)
+			continue
+		}
+		if _, exist := b.ids[event.ID]; exist && len(event.ID) > 0 {
+			b.remove(pending.ID)
+			continue
+		}
+		log.Infof("take over the pending event %s of repository %s", event.ID, pending.Repository)
+		b.hold(pending.ID, event, now)
+	}
+}
+
+// flush starts processing the events out of the reorder window at the time. The events of a
+// repository are processed in the order of their timestamps, so the ones after an event still
+// in the window or a repository being processed wait
+func (b *eventBuffer) flush(now time.Time) {
+	b.Lock()
+	defer b.Unlock()
+	batches := map[string][]*pendingEvent{}
+	blocked := map[string]bool{}
+	remaining := []*pendingEvent{}
+	for _, pending := range b.pending {
+		repository := repositoryOf(pending.event)
+		if b.running[repository] || blocked[repository] || pending.receivedAt.Add(reorderWindow).After(now) {
+			blocked[repository] = true
+			remaining = append(remaining, pending)
+			continue
+		}
+		batches[repository] = append(batches[repository], pending)
+	}
+	b.pending = remaining
+	for repository, batch := range batches {
+		b.running[repository] = true
+		b.inflight.Add(1)
+		go b.processBatch(repository, batch)
+	}
+}
+
+// processBatch processes the events of the repository in order, the failed event is retried
+// with the following ones later, and dropped after the max attempts
+func (b *eventBuffer) processBatch(repository string, batch []*pendingEvent) {
+	defer b.inflight.Done()
+	for i, pending := range batch {
+		if err := b.handle(pending.event); err != nil {
+			pending.attempts++
+			if pending.attempts < maxAttempts {
+				log.Warningf("failed to process the event %s of repository %s, retry later: %v",
+					pending.event.ID, repository, err)
+				b.retry(repository, batch[i:])
+				return
+			}
+			log.Errorf("failed to process the event %s of repository %s after %d attempts, drop it: %v",
+				pending.event.ID, repository, pending.attempts, err)
+		}
+		b.done(pending)
+	}
+	b.Lock()
+	delete(b.running, repository)
+	b.Unlock()
+}
+
+// handle claims the event by recording it as processed and then processes it, so that the event
+// is processed by only one of the instances, the ones claimed already are skipped. The claim is
+// released if the processing fails to retry the event later, while the event claimed by an
+// instance stopped while processing it isn't processed again
+func (b *eventBuffer) handle(event *models.Event) error {
+	if len(event.ID) == 0 {
+		return b.process(event)
+	}
+	claimed, err := markEvent(event.ID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Debugf("the event %s has been processed, skip", event.ID)
+		return nil
+	}
+	if err = b.process(event); err != nil {
+		if e := releaseEvent(event.ID); e != nil {
+			log.Errorf("failed to release the event %s: %v", event.ID, e)
+		}
+		return err
+	}
+	return nil
+}
+
+// retry puts the events back into the buffer, the first one waits for the reorder window again
+// and holds the others
+func (b *eventBuffer) retry(repository string, events []*pendingEvent) {
+	b.Lock()
+	defer b.Unlock()
+	events[0].receivedAt = time.Now()
+	b.pending = append(b.pending, events...)
+	b.sort()
+	delete(b.running, repository)
+}
+
+func (b *eventBuffer) done(pending *pendingEvent) {
+	b.Lock()
+	defer b.Unlock()
+	b.remove(pending.id)
+	delete(b.held, pending.id)
+	delete(b.ids, pending.event.ID)
+}
+
+// remove deletes the persisted pending event, the error is logged only as the
+// event is deduplicated if it's taken over again
+func (b *eventBuffer) remove(id int64) {
+	if err := removePending(id); err != nil {
+		log.Errorf("failed to remove the pending event %d: %v", id, err)
+	}
+}
+
+// refresh refreshes the heartbeat of the persisted pending events held by the buffer
+func (b *eventBuffer) refresh() {
+	if _, err := refreshPending(b.owner); err != nil {
+		log.Errorf("failed to refresh the heartbeat of the pending events of registry: %v", err)
+	}
+}
+
+// Start takes over the events left by the stopped instances, flushes the buffer in background,
+// refreshes the heartbeat of the held events and takes over the left events in the heartbeat
+// interval, and prunes the events out of the dedupe window hourly
+func (b *eventBuffer) Start() {
+	b.takeOver(time.Now().Add(-heartbeatTimeout))
+	go func() {
+		ticker := time.NewTicker(reorderWindow / 4)
+		defer ticker.Stop()
+		lastHeartbeat, lastPrune := time.Now(), time.Now()
+		for now := range ticker.C {
+			b.flush(now)
+			if now.Sub(lastHeartbeat) >= heartbeatInterval {
+				lastHeartbeat = now
+				b.refresh()
+				b.takeOver(now.Add(-heartbeatTimeout))
+			}
+			if now.Sub(lastPrune) < time.Hour {
+				continue
+			}
+			lastPrune = now
+			if n, err := pruneEvents(now.Add(-dedupeWindow)); err != nil {
+				log.Errorf("failed to prune the events of registry: %v", err)
+			} else {
+				log.Debugf("%d events of registry pruned", n)
+			}
+		}
+	}()
+	log.Infof("the buffer of the events of registry started, reorder window: %v", reorderWindow)
+}
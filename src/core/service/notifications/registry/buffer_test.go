@@ -0,0 +1,277 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore replaces the functions persisting the events
+type fakeStore struct {
+	sync.Mutex
+	processed map[string]bool
+	pending   map[int64]*models.PendingRegistryEvent
+	nextID    int64
+}
+
+func stubStore() (*fakeStore, func()) {
+	store := &fakeStore{
+		processed: map[string]bool{},
+		pending:   map[int64]*models.PendingRegistryEvent{},
+	}
+	mark, release, save, refresh, adopt, remove := markEvent, releaseEvent, savePending, refreshPending, adoptPending, removePending
+	markEvent = func(id string) (bool, error) {
+		store.Lock()
+		defer store.Unlock()
+		if store.processed[id] {
+			return false, nil
+		}
+		store.processed[id] = true
+		return true, nil
+	}
+	releaseEvent = func(id string) error {
+		store.Lock()
+		defer store.Unlock()
+		delete(store.processed, id)
+		return nil
+	}
+	savePending = func(event *models.PendingRegistryEvent) (int64, error) {
+		store.Lock()
+		defer store.Unlock()
+		store.nextID++
+		event.ID = store.nextID
+		event.Heartbeat = time.Now()
+		store.pending[event.ID] = event
+		return event.ID, nil
+	}
+	refreshPending = func(owner string) (int64, error) {
+		store.Lock()
+		defer store.Unlock()
+		var n int64
+		for _, event := range store.pending {
+			if event.Owner == owner {
+				event.Heartbeat = time.Now()
+				n++
+			}
+		}
+		return n, nil
+	}
+	adoptPending = func(owner string, before time.Time) ([]*models.PendingRegistryEvent, error) {
+		store.Lock()
+		defer store.Unlock()
+		events := []*models.PendingRegistryEvent{}
+		for id := int64(1); id <= store.nextID; id++ {
+			if event, exist := store.pending[id]; exist && event.Heartbeat.Before(before) {
+				event.Owner = owner
+				event.Heartbeat = time.Now()
+				events = append(events, event)
+			}
+		}
+		return events, nil
+	}
+	removePending = func(id int64) error {
+		store.Lock()
+		defer store.Unlock()
+		delete(store.pending, id)
+		return nil
+	}
+	return store, func() {
+		markEvent, releaseEvent, savePending, refreshPending, adoptPending, removePending = mark, release, save, refresh, adopt, remove
+	}
+}
+
+// recorder records the IDs of the processed events
+type recorder struct {
+	sync.Mutex
+	ids []string
+}
+
+func (r *recorder) process(event *models.Event) error {
+	r.Lock()
+	defer r.Unlock()
+	r.ids = append(r.ids, event.ID)
+	return nil
+}
+
+func (r *recorder) get() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string{}, r.ids...)
+}
+
+func newEvent(id, repository string, timestamp time.Time) *models.Event {
+	return &models.Event{
+		ID:        id,
+		TimeStamp: timestamp,
+		Target: &models.Target{
+			Repository: repository,
+		},
+	}
+}
+
+func flush(buffer *eventBuffer, now time.Time) {
+	buffer.flush(now)
+	buffer.inflight.Wait()
+}
+
+func TestEventBuffer(t *testing.T) {
+	store, restore := stubStore()
+	defer restore()
+
+	r := &recorder{}
+	buffer := newEventBuffer(r.process)
+	now := time.Now()
+
+	// out of order and duplicated
+	assert.Nil(t, buffer.Add(newEvent("2", "library/hello", now.Add(time.Second)), newEvent("1", "library/hello", now)))
+	assert.Nil(t, buffer.Add(newEvent("2", "library/hello", now.Add(time.Second)), newEvent("3", "library/hello", now.Add(2*time.Second))))
+	assert.Equal(t, 3, len(store.pending))
+	// still in the window
+	flush(buffer, time.Now())
+	assert.Equal(t, 0, len(r.get()))
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, []string{"1", "2", "3"}, r.get())
+	assert.Equal(t, 0, len(store.pending))
+
+	// delivered again after being processed
+	assert.Nil(t, buffer.Add(newEvent("3", "library/hello", now.Add(2*time.Second)), newEvent("4", "library/hello", now.Add(3*time.Second))))
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, []string{"1", "2", "3", "4"}, r.get())
+	assert.Equal(t, 0, len(store.pending))
+}
+
+func TestEventBufferKeepOrder(t *testing.T) {
+	_, restore := stubStore()
+	defer restore()
+
+	r := &recorder{}
+	buffer := newEventBuffer(r.process)
+	now := time.Now()
+	assert.Nil(t, buffer.Add(newEvent("2", "library/hello", now.Add(time.Second))))
+	// the earlier event delivered late holds the later one of the repository,
+	// but not the ones of the other repositories
+	buffer.pending[0].receivedAt = now.Add(-reorderWindow)
+	assert.Nil(t, buffer.Add(newEvent("3", "library/other", now.Add(-time.Second))))
+	buffer.pending[0].receivedAt = now.Add(-reorderWindow)
+	assert.Nil(t, buffer.Add(newEvent("1", "library/hello", now)))
+	flush(buffer, time.Now())
+	assert.Equal(t, []string{"3"}, r.get())
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, []string{"3", "1", "2"}, r.get())
+}
+
+func TestEventBufferRetry(t *testing.T) {
+	store, restore := stubStore()
+	defer restore()
+
+	r := &recorder{}
+	failures := 1
+	buffer := newEventBuffer(func(event *models.Event) error {
+		if event.ID == "1" && failures > 0 {
+			failures--
+			return errors.New("failed")
+		}
+		return r.process(event)
+	})
+	now := time.Now()
+	assert.Nil(t, buffer.Add(newEvent("1", "library/hello", now), newEvent("2", "library/hello", now.Add(time.Second))))
+
+	// the failed event isn't marked and holds the following one
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, 0, len(r.get()))
+	assert.False(t, store.processed["1"])
+	assert.Equal(t, 2, len(store.pending))
+
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, []string{"1", "2"}, r.get())
+	assert.True(t, store.processed["1"])
+	assert.Equal(t, 0, len(store.pending))
+
+	// dropped after the max attempts
+	buffer = newEventBuffer(func(event *models.Event) error {
+		return errors.New("failed")
+	})
+	assert.Nil(t, buffer.Add(newEvent("3", "library/hello", now)))
+	for i := 0; i < maxAttempts; i++ {
+		flush(buffer, time.Now().Add(reorderWindow))
+	}
+	assert.Equal(t, 0, len(buffer.pending))
+	assert.Equal(t, 0, len(store.pending))
+	assert.False(t, store.processed["3"])
+}
+
+func TestEventBufferTakeOver(t *testing.T) {
+	store, restore := stubStore()
+	defer restore()
+
+	// the events left by a stopped instance
+	left := newEventBuffer(func(event *models.Event) error { return nil })
+	now := time.Now()
+	assert.Nil(t, left.Add(newEvent("1", "library/hello", now), newEvent("2", "library/hello", now.Add(time.Second))))
+	assert.Equal(t, 2, len(store.pending))
+
+	r := &recorder{}
+	buffer := newEventBuffer(r.process)
+	// delivered again by registry
+	assert.Nil(t, buffer.Add(newEvent("2", "library/hello", now.Add(time.Second))))
+	buffer.takeOver(time.Now().Add(time.Millisecond))
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, []string{"1", "2"}, r.get())
+	assert.Equal(t, 0, len(store.pending))
+
+	// the events held already aren't taken over again
+	assert.Nil(t, buffer.Add(newEvent("3", "library/hello", now)))
+	buffer.refresh()
+	buffer.takeOver(time.Now().Add(-time.Millisecond))
+	assert.Equal(t, 1, len(buffer.pending))
+}
+
+func TestEventBufferTakeOverLiveInstance(t *testing.T) {
+	store, restore := stubStore()
+	defer restore()
+
+	// the events held by a live instance
+	r1 := &recorder{}
+	live := newEventBuffer(r1.process)
+	now := time.Now()
+	assert.Nil(t, live.Add(newEvent("1", "library/hello", now)))
+	for _, event := range store.pending {
+		event.Heartbeat = now.Add(-heartbeatTimeout - time.Minute)
+	}
+	live.refresh()
+
+	r2 := &recorder{}
+	buffer := newEventBuffer(r2.process)
+	buffer.takeOver(time.Now().Add(-heartbeatTimeout))
+	assert.Equal(t, 0, len(buffer.pending))
+
+	// the heartbeat expires
+	for _, event := range store.pending {
+		event.Heartbeat = now.Add(-heartbeatTimeout - time.Minute)
+	}
+	buffer.takeOver(time.Now().Add(-heartbeatTimeout))
+	assert.Equal(t, 1, len(buffer.pending))
+	// the event held by both instances is processed only once
+	flush(live, time.Now().Add(reorderWindow))
+	flush(buffer, time.Now().Add(reorderWindow))
+	assert.Equal(t, []string{"1"}, r1.get())
+	assert.Equal(t, 0, len(r2.get()))
+}
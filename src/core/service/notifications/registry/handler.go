@@ -16,8 +16,11 @@ package registry
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/goharbor/harbor/src/common/dao"
@@ -25,9 +28,12 @@ import (
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/accesslog"
 	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/cache"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/pulltime"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
 	rep_notification "github.com/goharbor/harbor/src/replication/event/notification"
 	"github.com/goharbor/harbor/src/replication/event/topic"
@@ -38,10 +44,16 @@ type NotificationHandler struct {
 	api.BaseController
 }
 
+var (
+	buffer      = newEventBuffer(handleEvent)
+	startBuffer sync.Once
+)
+
 const manifestPattern = `^application/vnd.docker.distribution.manifest.v\d\+(json|prettyjws)`
 const vicPrefix = "vic/"
 
-// Post handles POST request, and records audit log or refreshes cache based on event.
+// Post handles POST request, the events are deduplicated and processed in the order of their
+// timestamps by the buffer asynchronously
 func (n *NotificationHandler) Post() {
 	var notification models.Notification
 	err := json.Unmarshal(n.Ctx.Input.CopyBody(1<<32), &notification)
@@ -57,92 +69,140 @@ func (n *NotificationHandler) Post() {
 		return
 	}
 
-	for _, event := range events {
-		repository := event.Target.Repository
-		project, _ := utils.ParseRepository(repository)
-		tag := event.Target.Tag
-		action := event.Action
+	startBuffer.Do(buffer.Start)
+	// the notification isn't acknowledged until the events are persisted, so registry
+	// delivers it again if they're lost
+	if err = buffer.Add(events...); err != nil {
+		log.Errorf("failed to add the events into the buffer: %v", err)
+		n.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+}
+
+// handleEvent records audit log or refreshes cache based on the event, the event is
+// retried if an error is returned
+func handleEvent(event *models.Event) error {
+	repository := event.Target.Repository
+	project, _ := utils.ParseRepository(repository)
+	tag := event.Target.Tag
+	action := event.Action
 
-		user := event.Actor.Name
-		if len(user) == 0 {
-			user = "anonymous"
-		}
+	user := event.Actor.Name
+	if len(user) == 0 {
+		user = "anonymous"
+	}
+
+	pro, err := config.GlobalProjectMgr.Get(project)
+	if err != nil {
+		return fmt.Errorf("failed to get project by name %s: %v", project, err)
+	}
+	if pro == nil {
+		log.Warningf("project %s not found", project)
+		return nil
+	}
 
-		pro, err := config.GlobalProjectMgr.Get(project)
+	if action == "push" {
+		// the tag may be pushed directly to the registry, e.g. by the replication jobs
+		if err := cache.DeleteManifest(repository, tag); err != nil {
+			log.Errorf("failed to delete the cached manifest of %s:%s: %v", repository, tag, err)
+		}
+		if err := recordTagPush(repository, tag, event.Target.Digest, user); err != nil {
+			return fmt.Errorf("failed to record the history of %s:%s: %v", repository, tag, err)
+		}
+		// the name of a renamed repository is used again
+		n, err := dao.DeleteRepoRedirects(repository)
 		if err != nil {
-			log.Errorf("failed to get project by name %s: %v", project, err)
-			return
+			return fmt.Errorf("failed to delete the redirects of repository %s: %v", repository, err)
 		}
-		if pro == nil {
-			log.Warningf("project %s not found", project)
-			continue
+		if n > 0 {
+			if err = cache.InvalidateRedirectSources(); err != nil {
+				log.Errorf("failed to invalidate the cached sources of the redirects: %v", err)
+			}
+		}
+	}
+
+	go func() {
+		if err := accesslog.Add(models.AccessLog{
+			Username:  user,
+			ProjectID: pro.ProjectID,
+			RepoName:  repository,
+			RepoTag:   tag,
+			Operation: action,
+			OpTime:    time.Now(),
+		}); err != nil {
+			log.Errorf("failed to add access log: %v", err)
 		}
+	}()
 
+	if action == "push" {
 		go func() {
-			if err := dao.AddAccessLog(models.AccessLog{
-				Username:  user,
+			exist := dao.RepositoryExists(repository)
+			if exist {
+				return
+			}
+			log.Debugf("Add repository %s into DB.", repository)
+			repoRecord := models.RepoRecord{
+				Name:      repository,
 				ProjectID: pro.ProjectID,
-				RepoName:  repository,
-				RepoTag:   tag,
-				Operation: action,
-				OpTime:    time.Now(),
-			}); err != nil {
-				log.Errorf("failed to add access log: %v", err)
+			}
+			if err := dao.AddRepository(repoRecord); err != nil {
+				log.Errorf("Error happens when adding repository: %v", err)
 			}
 		}()
+		if !coreutils.WaitForManifestReady(repository, tag, 5) {
+			log.Errorf("Manifest for image %s:%s is not ready, skip the follow up actions.", repository, tag)
+			return nil
+		}
 
-		if action == "push" {
-			go func() {
-				exist := dao.RepositoryExists(repository)
-				if exist {
-					return
-				}
-				log.Debugf("Add repository %s into DB.", repository)
-				repoRecord := models.RepoRecord{
-					Name:      repository,
-					ProjectID: pro.ProjectID,
-				}
-				if err := dao.AddRepository(repoRecord); err != nil {
-					log.Errorf("Error happens when adding repository: %v", err)
-				}
-			}()
-			if !coreutils.WaitForManifestReady(repository, tag, 5) {
-				log.Errorf("Manifest for image %s:%s is not ready, skip the follow up actions.", repository, tag)
+		go func() {
+			image := repository + ":" + tag
+			err := notifier.Publish(topic.ReplicationEventTopicOnPush, rep_notification.OnPushNotification{
+				Image: image,
+			})
+			if err != nil {
+				log.Errorf("failed to publish on push topic for resource %s: %v", image, err)
 				return
 			}
+			log.Debugf("the on push topic for resource %s published", image)
+		}()
 
-			go func() {
-				image := repository + ":" + tag
-				err := notifier.Publish(topic.ReplicationEventTopicOnPush, rep_notification.OnPushNotification{
-					Image: image,
-				})
-				if err != nil {
-					log.Errorf("failed to publish on push topic for resource %s: %v", image, err)
-					return
-				}
-				log.Debugf("the on push topic for resource %s published", image)
-			}()
-
-			if autoScanEnabled(pro) {
-				last, err := clairdao.GetLastUpdate()
-				if err != nil {
-					log.Errorf("Failed to get last update from Clair DB, error: %v, the auto scan will be skipped.", err)
-				} else if last == 0 {
-					log.Infof("The Vulnerability data is not ready in Clair DB, the auto scan will be skipped.")
-				} else if err := coreutils.TriggerImageScan(repository, tag); err != nil {
-					log.Warningf("Failed to scan image, repository: %s, tag: %s, error: %v", repository, tag, err)
-				}
+		go func() {
+			if err := coreutils.IndexAnnotations(repository, tag); err != nil {
+				log.Errorf("failed to index the annotations of %s:%s: %v", repository, tag, err)
+			}
+		}()
+
+		go notifier.NotifyRepoSubscribers(repository, models.SubscriptionEventNewTag,
+			fmt.Sprintf("Harbor: new tag %s pushed to %s", tag, repository),
+			fmt.Sprintf("The tag %s of repository %s has been pushed by %s.", tag, repository, user))
+
+		if autoScanEnabled(pro) {
+			last, err := clairdao.GetLastUpdate()
+			if err != nil {
+				log.Errorf("Failed to get last update from Clair DB, error: %v, the auto scan will be skipped.", err)
+			} else if last == 0 {
+				log.Infof("The Vulnerability data is not ready in Clair DB, the auto scan will be skipped.")
+			} else if err := coreutils.TriggerImageScan(repository, tag); err != nil {
+				log.Warningf("Failed to scan image, repository: %s, tag: %s, error: %v", repository, tag, err)
 			}
 		}
-		if action == "pull" {
-			go func() {
-				log.Debugf("Increase the repository %s pull count.", repository)
-				if err := dao.IncreasePullCount(repository); err != nil {
-					log.Errorf("Error happens when increasing pull count: %v", repository)
-				}
-			}()
+	}
+	if action == "pull" {
+		// the tag is empty if the image is pulled by digest
+		if len(tag) > 0 {
+			pullTime := event.TimeStamp
+			if pullTime.IsZero() {
+				pullTime = time.Now()
+			}
+			pulltime.Record(repository, tag, pullTime)
 		}
+		go func() {
+			log.Debugf("Increase the repository %s pull count.", repository)
+			if err := dao.IncreasePullCount(repository); err != nil {
+				log.Errorf("Error happens when increasing pull count: %v", repository)
+			}
+		}()
 	}
+	return nil
 }
 
 func filterEvents(notification *models.Notification) ([]*models.Event, error) {
@@ -163,7 +223,9 @@ func filterEvents(notification *models.Notification) ([]*models.Event, error) {
 		}
 
 		if checkEvent(&event) {
-			events = append(events, &event)
+			// the events are kept in the buffer, so copy the loop variable
+			e := event
+			events = append(events, &e)
 			log.Debugf("add event to collection: %s", event.ID)
 			continue
 		}
@@ -184,6 +246,33 @@ func checkEvent(event *models.Event) bool {
 	return false
 }
 
+// recordTagPush records the tag history if the tag is created or points to a new digest
+func recordTagPush(repository, tag, digest, operator string) error {
+	if len(tag) == 0 {
+		return nil
+	}
+	latest, err := dao.GetLatestTagHistory(repository, tag)
+	if err != nil {
+		return err
+	}
+	operation := models.TagHistoryCreate
+	if latest != nil && latest.Operation != models.TagHistoryDelete {
+		// pushed again without change
+		if latest.Digest == digest {
+			return nil
+		}
+		operation = models.TagHistoryUpdate
+	}
+	_, err = dao.AddTagHistory(&models.TagHistory{
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+		Operation:  operation,
+		Operator:   operator,
+	})
+	return err
+}
+
 func autoScanEnabled(project *models.Project) bool {
 	if !config.WithClair() {
 		log.Debugf("Auto Scan disabled because Harbor is not deployed with Clair")
@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/secret"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/coretask"
+)
+
+// Handler handles request on /service/tasks/:name/:id, which is called by the jobs of jobservice
+// to run the tasks of core
+type Handler struct {
+	api.BaseController
+}
+
+// Prepare ...
+func (h *Handler) Prepare() {
+	h.BaseController.Prepare()
+	if !h.SecurityCtx.IsAuthenticated() {
+		h.HandleUnauthorized()
+		return
+	}
+	if !h.SecurityCtx.IsSolutionUser() || h.SecurityCtx.GetUsername() != secret.JobserviceUser {
+		h.HandleForbidden(h.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Run runs the task, an error is returned to jobservice to retry the job if it isn't completed
+func (h *Handler) Run() {
+	name := h.GetStringFromPath(":name")
+	id, err := h.GetInt64FromPath(":id")
+	if err != nil {
+		h.SendBadRequestError(fmt.Errorf("invalid task ID: %v", err))
+		return
+	}
+	runner := coretask.Get(name)
+	if runner == nil {
+		h.SendNotFoundError(fmt.Errorf("task %s not found", name))
+		return
+	}
+	if err = runner.Run(id); err != nil {
+		log.Errorf("failed to run the task %s %d: %v", name, id, err)
+		h.SendInternalServerError(fmt.Errorf("failed to run the task %s %d: %v", name, id, err))
+	}
+}
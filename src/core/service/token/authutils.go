@@ -20,7 +20,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution/registry/auth/token"
@@ -38,6 +40,14 @@ const (
 
 var privateKey string
 
+// the public key verifying the tokens, it's reloaded only when the key file changes
+var (
+	verificationKeyLock    sync.RWMutex
+	verificationKey        libtrust.PublicKey
+	verificationKeyPath    string
+	verificationKeyModTime time.Time
+)
+
 func init() {
 	privateKey = config.TokenPrivateKeyPath()
 }
@@ -101,11 +111,16 @@ func filterAccess(access []*token.ResourceActions, ctx security.Context,
 
 // MakeToken makes a valid jwt token based on parms.
 func MakeToken(username, service string, access []*token.ResourceActions) (*models.Token, error) {
-	pk, err := libtrust.LoadKeyFile(privateKey)
+	expiration, err := config.TokenExpiration()
 	if err != nil {
 		return nil, err
 	}
-	expiration, err := config.TokenExpiration()
+	return makeToken(username, service, expiration, access)
+}
+
+// makeToken makes the token expiring in the specified minutes
+func makeToken(username, service string, expiration int, access []*token.ResourceActions) (*models.Token, error) {
+	pk, err := libtrust.LoadKeyFile(privateKey)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +137,56 @@ func MakeToken(username, service string, access []*token.ResourceActions) (*mode
 	}, nil
 }
 
+// VerifyToken parses the raw token and verifies that it is issued by the token
+// service for the specified service and hasn't expired.
+func VerifyToken(rawToken, service string) (*token.Token, error) {
+	pk, err := getVerificationKey()
+	if err != nil {
+		return nil, err
+	}
+	t, err := token.NewToken(rawToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.Verify(token.VerifyOptions{
+		TrustedIssuers:    []string{issuer},
+		AcceptedAudiences: []string{service},
+		TrustedKeys: map[string]libtrust.PublicKey{
+			pk.KeyID(): pk,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// getVerificationKey returns the public key of the private key signing the tokens, the key
+// file is loaded once and reloaded only when it's modified or its path changes
+func getVerificationKey() (libtrust.PublicKey, error) {
+	info, err := os.Stat(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	verificationKeyLock.RLock()
+	key := verificationKey
+	loaded := key != nil && verificationKeyPath == privateKey && verificationKeyModTime.Equal(info.ModTime())
+	verificationKeyLock.RUnlock()
+	if loaded {
+		return key, nil
+	}
+
+	pk, err := libtrust.LoadKeyFile(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	verificationKeyLock.Lock()
+	defer verificationKeyLock.Unlock()
+	verificationKey = pk.PublicKey()
+	verificationKeyPath = privateKey
+	verificationKeyModTime = info.ModTime()
+	return verificationKey, nil
+}
+
 func permToActions(p string) []string {
 	res := []string{}
 	if strings.Contains(p, "W") {
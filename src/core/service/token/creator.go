@@ -21,8 +21,12 @@ import (
 	"strings"
 
 	"github.com/docker/distribution/registry/auth/token"
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/secret"
 	"github.com/goharbor/harbor/src/common/security"
+	robotCtx "github.com/goharbor/harbor/src/common/security/robot"
+	shareCtx "github.com/goharbor/harbor/src/common/security/share"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/filter"
@@ -33,6 +37,13 @@ var creatorMap map[string]Creator
 var registryFilterMap map[string]accessFilter
 var notaryFilterMap map[string]accessFilter
 
+// the functions querying the database when filtering the access, replaced in testing
+var (
+	getRepoAccess      = dao.GetRepoAccess
+	usagePolicyPending = dao.UsagePolicyPending
+	getRepoRedirect    = dao.GetRepoRedirect
+)
+
 const (
 	// Notary service
 	Notary = "harbor-notary"
@@ -139,7 +150,8 @@ func (reg registryFilter) filter(ctx security.Context, pm promgr.ProjectManager,
 	if a.Name != "catalog" {
 		return fmt.Errorf("Unable to handle, type: %s, name: %s", a.Type, a.Name)
 	}
-	if !ctx.IsSysAdmin() {
+	// the jobservice lists the catalog when reconciling the registry and the database
+	if !ctx.IsSysAdmin() && !(ctx.IsSolutionUser() && ctx.GetUsername() == secret.JobserviceUser) {
 		// Set the actions to empty is the user is not admin
 		a.Actions = []string{}
 	}
@@ -158,6 +170,16 @@ func (rep repositoryFilter) filter(ctx security.Context, pm promgr.ProjectManage
 	if err != nil {
 		return err
 	}
+	// the share link can only pull the shared repository
+	if share, ok := ctx.(*shareCtx.SecurityContext); ok {
+		if share.CanPull(img.namespace + "/" + img.repo) {
+			a.Actions = permToActions("R")
+		} else {
+			a.Actions = []string{}
+		}
+		return nil
+	}
+
 	project := img.namespace
 	permission := ""
 
@@ -179,10 +201,67 @@ func (rep repositoryFilter) filter(ctx security.Context, pm promgr.ProjectManage
 		permission = "R"
 	}
 
+	if ctx.IsAuthenticated() && !strings.Contains(permission, "W") {
+		repository := img.namespace + "/" + img.repo
+		access, err := getRepoAccess(repository, ctx.GetUsername())
+		if err != nil {
+			return err
+		}
+		permission = mergeRepoAccess(permission, access)
+	}
+
+	if len(permission) > 0 && !usagePolicyExempted(ctx) {
+		pro, err := pm.Get(project)
+		if err != nil {
+			return err
+		}
+		pending, err := usagePolicyPending(pro.ProjectID, ctx.GetUsername())
+		if err != nil {
+			return err
+		}
+		// only the pulls are gated by the usage policy, the "*" action is dropped too
+		// as it covers the pull
+		if pending {
+			log.Debugf("the usage policy of project %s isn't acknowledged by %s, remove the pull permission", project, ctx.GetUsername())
+			if strings.Contains(permission, "W") {
+				permission = "W"
+			} else {
+				permission = ""
+			}
+		}
+	}
+
 	a.Actions = permToActions(permission)
 	return nil
 }
 
+// usagePolicyExempted returns whether the security context is exempted from acknowledging
+// the usage policies of the projects, the robots are created by the project admins who
+// manage the policies
+func usagePolicyExempted(ctx security.Context) bool {
+	if ctx.IsSysAdmin() {
+		return true
+	}
+	_, ok := ctx.(*robotCtx.SecurityContext)
+	return ok
+}
+
+// mergeRepoAccess adds the access granted by the access control list of the repository
+// to the permission granted by the project
+func mergeRepoAccess(permission, access string) string {
+	switch access {
+	case models.RepoAccessPush:
+		if !strings.Contains(permission, "W") {
+			return "RW"
+		}
+	case models.RepoAccessPull:
+		if len(permission) == 0 {
+			return "R"
+		}
+	}
+	return permission
+}
+
 type generalCreator struct {
 	service   string
 	filterMap map[string]accessFilter
@@ -216,6 +295,9 @@ func (g generalCreator) Create(r *http.Request) (*models.Token, error) {
 		}
 	}
 	access := GetResourceActions(scopes)
+	if g.service == Registry {
+		access = appendRedirectedAccess(access)
+	}
 	err = filterAccess(access, ctx, pm, g.filterMap)
 	if err != nil {
 		return nil, err
@@ -223,6 +305,32 @@ func (g generalCreator) Create(r *http.Request) (*models.Token, error) {
 	return MakeToken(ctx.GetUsername(), g.service, access)
 }
 
+// appendRedirectedAccess appends the access to the new repositories which the renamed
+// ones are redirected to, so that the redirected pulls of the old names are authorized.
+// The permission of the new repositories are filtered as the others.
+func appendRedirectedAccess(access []*token.ResourceActions) []*token.ResourceActions {
+	result := access
+	for _, a := range access {
+		if a.Type != "repository" {
+			continue
+		}
+		redirect, err := getRepoRedirect(a.Name)
+		if err != nil {
+			log.Errorf("failed to get the redirect of repository %s: %v", a.Name, err)
+			continue
+		}
+		if redirect == nil {
+			continue
+		}
+		result = append(result, &token.ResourceActions{
+			Type:    a.Type,
+			Name:    redirect.NewName,
+			Actions: a.Actions,
+		})
+	}
+	return result
+}
+
 func parseScopes(u *url.URL) []string {
 	var sector string
 	var result []string
@@ -231,3 +339,22 @@ func parseScopes(u *url.URL) []string {
 	}
 	return result
 }
+
+// PullAllowed returns whether the token service grants the pull of the repository to the
+// security context, it's resolved in the same way as the scopes of the registry
+func PullAllowed(ctx security.Context, pm promgr.ProjectManager, repository string) (bool, error) {
+	a := &token.ResourceActions{
+		Type:    "repository",
+		Name:    repository,
+		Actions: []string{"pull"},
+	}
+	if err := (&repositoryFilter{parser: &basicParser{}}).filter(ctx, pm, a); err != nil {
+		return false, err
+	}
+	for _, action := range a.Actions {
+		if action == "pull" || action == "*" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,251 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/docker/distribution/registry/auth/token"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/security/serviceaccount"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/promgr"
+)
+
+const (
+	// GrantTypeTokenExchange is the grant type of OAuth 2.0 token exchange
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// TokenTypeJWT is the type of the subject token, which is the service account token of Kubernetes
+	TokenTypeJWT = "urn:ietf:params:oauth:token-type:jwt"
+	// TokenTypeAccessToken is the type of the issued registry token
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	serviceAccountPrefix = "system:serviceaccount:"
+)
+
+// ExchangeRequest is the request of exchanging the service account token for the registry token
+type ExchangeRequest struct {
+	GrantType        string
+	SubjectToken     string
+	SubjectTokenType string
+	Scopes           []string
+}
+
+// ExchangedToken is the registry token issued for the service account in the format
+// of OAuth 2.0 token exchange response, it's compatible with the token service as well
+type ExchangedToken struct {
+	models.Token
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+}
+
+// ExchangeError is the error response of OAuth 2.0
+type ExchangeError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *ExchangeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// Exchange verifies the service account token against the issuer, audience and public keys in the settings
+// and issues a registry token. The access is filtered in the same way as the token service with the
+// security context of the service account, which can only pull the repositories of the projects bound
+// to it in the settings. The pulls are checked against the blocklist by the proxy as the others
+func Exchange(req *ExchangeRequest, settings *models.TokenExchange, pm promgr.ProjectManager) (*ExchangedToken, error) {
+	if len(settings.Issuer) == 0 || len(settings.Audience) == 0 {
+		return nil, &ExchangeError{"unauthorized_client", "the token exchange isn't enabled"}
+	}
+	if req.GrantType != GrantTypeTokenExchange {
+		return nil, &ExchangeError{"unsupported_grant_type", fmt.Sprintf("unsupported grant type %s", req.GrantType)}
+	}
+	if req.SubjectTokenType != TokenTypeJWT {
+		return nil, &ExchangeError{"invalid_request", fmt.Sprintf("unsupported subject token type %s", req.SubjectTokenType)}
+	}
+	if len(req.SubjectToken) == 0 {
+		return nil, &ExchangeError{"invalid_request", "the subject token is required"}
+	}
+
+	keys, err := ParsePublicKeys(settings.PublicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public keys of the issuer: %v", err)
+	}
+	subject, err := verifyServiceAccountToken(req.SubjectToken, settings.Issuer, settings.Audience, keys)
+	if err != nil {
+		log.Debugf("failed to verify the service account token: %v", err)
+		return nil, &ExchangeError{"invalid_grant", "invalid service account token"}
+	}
+
+	ctx := serviceaccount.NewSecurityContext(subject, boundProjects(subject, settings.Bindings), pm)
+	access := appendRedirectedAccess(GetResourceActions(req.Scopes))
+	if err = filterAccess(access, ctx, pm, registryFilterMap); err != nil {
+		return nil, err
+	}
+	keepPull(access)
+	tk, err := makeToken(subject, Registry, settings.Expiration, access)
+	if err != nil {
+		return nil, err
+	}
+	tk.AccessToken = tk.Token
+	return &ExchangedToken{
+		Token:           *tk,
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+	}, nil
+}
+
+// ParsePublicKeys parses the PEM encoded public keys and certificates
+func ParsePublicKeys(data string) ([]crypto.PublicKey, error) {
+	keys := []crypto.PublicKey{}
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			err = fmt.Errorf("unsupported PEM block %s", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("unsupported public key %T", key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no public key found")
+	}
+	return keys, nil
+}
+
+// verifyServiceAccountToken verifies the signature, the issuer, the audience and the time
+// of the service account token, the subject, which is "system:serviceaccount:<namespace>:<name>", is returned
+func verifyServiceAccountToken(rawToken, issuer, audience string, keys []crypto.PublicKey) (string, error) {
+	var claims jwt.MapClaims
+	var err error
+	for _, key := range keys {
+		claims = jwt.MapClaims{}
+		if _, err = jwt.ParseWithClaims(rawToken, claims, keyFunc(key)); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !claims.VerifyIssuer(issuer, true) {
+		return "", fmt.Errorf("unexpected issuer %v", claims["iss"])
+	}
+	if !containsAudience(claims["aud"], audience) {
+		return "", fmt.Errorf("unexpected audience %v", claims["aud"])
+	}
+	subject, _ := claims["sub"].(string)
+	if !strings.HasPrefix(subject, serviceAccountPrefix) {
+		return "", fmt.Errorf("%q isn't a service account", subject)
+	}
+	return subject, nil
+}
+
+// keyFunc returns the key only if the signing method matches the type of the key
+func keyFunc(key crypto.PublicKey) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		switch key.(type) {
+		case *rsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodRSA); ok {
+				return key, nil
+			}
+			if _, ok := t.Method.(*jwt.SigningMethodRSAPSS); ok {
+				return key, nil
+			}
+		case *ecdsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodECDSA); ok {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+}
+
+// containsAudience checks the audience, which is a string or an array of strings
+func containsAudience(aud interface{}, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// boundProjects returns the projects bound to the service account of the subject
+func boundProjects(subject string, bindings []*models.ServiceAccountBinding) []string {
+	parts := strings.SplitN(strings.TrimPrefix(subject, serviceAccountPrefix), ":", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	projects := []string{}
+	for _, binding := range bindings {
+		if binding.Match(parts[0], parts[1]) {
+			projects = append(projects, binding.Project)
+		}
+	}
+	return projects
+}
+
+// keepPull only keeps the pull action of the filtered access, the exchanged registry
+// tokens can't push even if the access control lists of the repositories allow it
+func keepPull(access []*token.ResourceActions) {
+	for _, a := range access {
+		actions := []string{}
+		for _, action := range a.Actions {
+			if action == "pull" {
+				actions = append(actions, action)
+				break
+			}
+		}
+		a.Actions = actions
+	}
+}
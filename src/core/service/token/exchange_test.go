@@ -0,0 +1,234 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/promgr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublicKeys(t *testing.T) {
+	_, crt := getKeyAndCertPath()
+	data, err := ioutil.ReadFile(crt)
+	require.Nil(t, err)
+	keys, err := ParsePublicKeys(string(data))
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(keys))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.Nil(t, err)
+	keys, err = ParsePublicKeys(string(data) + string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(keys))
+
+	_, err = ParsePublicKeys("")
+	assert.NotNil(t, err)
+	_, err = ParsePublicKeys(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})))
+	assert.NotNil(t, err)
+}
+
+type fakeProjectManager struct {
+	promgr.ProjectManager
+	projects map[string]*models.Project
+}
+
+func (f *fakeProjectManager) Get(projectIDOrName interface{}) (*models.Project, error) {
+	name, _ := projectIDOrName.(string)
+	return f.projects[name], nil
+}
+
+func (f *fakeProjectManager) Exists(projectIDOrName interface{}) (bool, error) {
+	project, err := f.Get(projectIDOrName)
+	return project != nil, err
+}
+
+func TestExchange(t *testing.T) {
+	pk, crt := getKeyAndCertPath()
+	privateKey = pk
+
+	repoAccess := map[string]string{}
+	pendingProjects := map[int64]bool{}
+	getRepoAccess = func(repository, username string) (string, error) {
+		return repoAccess[repository], nil
+	}
+	usagePolicyPending = func(projectID int64, username string) (bool, error) {
+		return pendingProjects[projectID], nil
+	}
+	getRepoRedirect = func(name string) (*models.RepoRedirect, error) {
+		return nil, nil
+	}
+	defer func() {
+		getRepoAccess = dao.GetRepoAccess
+		usagePolicyPending = dao.UsagePolicyPending
+		getRepoRedirect = dao.GetRepoRedirect
+	}()
+	pm := &fakeProjectManager{
+		projects: map[string]*models.Project{
+			"library": {ProjectID: 1, Name: "library"},
+			"base":    {ProjectID: 2, Name: "base"},
+			"private": {ProjectID: 3, Name: "private"},
+		},
+	}
+
+	saKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&saKey.PublicKey)
+	require.Nil(t, err)
+	settings := &models.TokenExchange{
+		Issuer:     "https://kubernetes.default.svc",
+		PublicKeys: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		Audience:   "harbor",
+		Bindings: []*models.ServiceAccountBinding{
+			{Namespace: "default", ServiceAccount: "builder", Project: "library"},
+			{Namespace: "default", ServiceAccount: "*", Project: "base"},
+		},
+		Expiration: 5,
+	}
+	sign := func(claims jwt.MapClaims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(saKey)
+		require.Nil(t, err)
+		return s
+	}
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": settings.Issuer,
+			"sub": "system:serviceaccount:default:builder",
+			"aud": []interface{}{"api", "harbor"},
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	request := func(subjectToken string) *ExchangeRequest {
+		return &ExchangeRequest{
+			GrantType:        GrantTypeTokenExchange,
+			SubjectToken:     subjectToken,
+			SubjectTokenType: TokenTypeJWT,
+			Scopes: []string{"repository:library/nginx:pull,push", "repository:private/app:pull",
+				"repository:base/alpine:pull"},
+		}
+	}
+	// returns the actions of the scopes granted to the exchanged token
+	exchange := func(claims jwt.MapClaims) [][]string {
+		tk, err := Exchange(request(sign(claims)), settings, pm)
+		require.Nil(t, err)
+		pubKey, err := getPublicKey(crt)
+		require.Nil(t, err)
+		parsed, err := jwt.ParseWithClaims(tk.Token.Token, &harborClaims{}, func(token *jwt.Token) (interface{}, error) {
+			return pubKey, nil
+		})
+		require.Nil(t, err)
+		actions := [][]string{}
+		for _, a := range parsed.Claims.(*harborClaims).Access {
+			actions = append(actions, a.Actions)
+		}
+		return actions
+	}
+
+	tk, err := Exchange(request(sign(claims())), settings, pm)
+	require.Nil(t, err)
+	assert.Equal(t, TokenTypeAccessToken, tk.IssuedTokenType)
+	assert.Equal(t, "Bearer", tk.TokenType)
+	assert.Equal(t, tk.Token.Token, tk.AccessToken)
+	assert.Equal(t, 300, tk.ExpiresIn)
+
+	pubKey, err := getPublicKey(crt)
+	require.Nil(t, err)
+	parsed, err := jwt.ParseWithClaims(tk.Token.Token, &harborClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return pubKey, nil
+	})
+	require.Nil(t, err)
+	c := parsed.Claims.(*harborClaims)
+	assert.Equal(t, "system:serviceaccount:default:builder", c.Subject)
+	require.Equal(t, 3, len(c.Access))
+	assert.Equal(t, []string{"pull"}, c.Access[0].Actions)
+	assert.Equal(t, []string{}, c.Access[1].Actions)
+	assert.Equal(t, []string{"pull"}, c.Access[2].Actions)
+
+	// only bound to the projects for all the service accounts of the namespace
+	other := claims()
+	other["sub"] = "system:serviceaccount:default:deployer"
+	assert.Equal(t, [][]string{{}, {}, {"pull"}}, exchange(other))
+	other["sub"] = "system:serviceaccount:ci:builder"
+	assert.Equal(t, [][]string{{}, {}, {}}, exchange(other))
+
+	// the access control list grants the pull but not the push
+	repoAccess["private/app"] = models.RepoAccessPush
+	assert.Equal(t, [][]string{{"pull"}, {"pull"}, {"pull"}}, exchange(claims()))
+	delete(repoAccess, "private/app")
+
+	// the usage policy isn't acknowledged
+	pendingProjects[1] = true
+	assert.Equal(t, [][]string{{}, {}, {"pull"}}, exchange(claims()))
+	delete(pendingProjects, 1)
+
+	// invalid requests
+	req := request(sign(claims()))
+	req.GrantType = "client_credentials"
+	_, err = Exchange(req, settings, pm)
+	assert.Equal(t, "unsupported_grant_type", err.(*ExchangeError).Code)
+
+	req = request(sign(claims()))
+	req.SubjectTokenType = TokenTypeAccessToken
+	_, err = Exchange(req, settings, pm)
+	assert.Equal(t, "invalid_request", err.(*ExchangeError).Code)
+
+	_, err = Exchange(request(sign(claims())), &models.TokenExchange{}, pm)
+	assert.Equal(t, "unauthorized_client", err.(*ExchangeError).Code)
+	// the audience is required
+	_, err = Exchange(request(sign(claims())), &models.TokenExchange{
+		Issuer:     settings.Issuer,
+		PublicKeys: settings.PublicKeys,
+		Bindings:   settings.Bindings,
+		Expiration: 5,
+	}, pm)
+	assert.Equal(t, "unauthorized_client", err.(*ExchangeError).Code)
+
+	// invalid service account tokens
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	otherToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims()).SignedString(otherKey)
+	require.Nil(t, err)
+
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	issuer := claims()
+	issuer["iss"] = "https://other.issuer"
+	audience := claims()
+	audience["aud"] = "api"
+	user := claims()
+	user["sub"] = "admin"
+
+	for _, subjectToken := range []string{"", "invalid", otherToken, sign(expired), sign(issuer), sign(audience), sign(user)} {
+		_, err = Exchange(request(subjectToken), settings, pm)
+		require.NotNil(t, err)
+		_, ok := err.(*ExchangeError)
+		assert.True(t, ok)
+	}
+}
@@ -17,9 +17,11 @@ package token
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/astaxie/beego"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
 )
 
 // Handler handles request on /service/token, which is the auth provider for registry.
@@ -52,3 +54,33 @@ func (h *Handler) Get() {
 	h.ServeJSON()
 
 }
+
+// Exchange handles POST request on /service/token/exchange, it exchanges the service account token
+// of Kubernetes for a short-lived registry token following OAuth 2.0 token exchange, the scopes of
+// the registry token are specified by the space-delimited "scope" of the form.
+func (h *Handler) Exchange() {
+	req := &ExchangeRequest{
+		GrantType:        h.GetString("grant_type"),
+		SubjectToken:     h.GetString("subject_token"),
+		SubjectTokenType: h.GetString("subject_token_type"),
+		Scopes:           strings.Fields(h.GetString("scope")),
+	}
+	settings, err := config.TokenExchange()
+	if err != nil {
+		log.Errorf("failed to get the settings of token exchange: %v", err)
+		h.CustomAbort(http.StatusInternalServerError, "")
+	}
+	tk, err := Exchange(req, settings, config.GlobalProjectMgr)
+	if err != nil {
+		if e, ok := err.(*ExchangeError); ok {
+			h.Ctx.Output.SetStatus(http.StatusBadRequest)
+			h.Data["json"] = e
+			h.ServeJSON()
+			return
+		}
+		log.Errorf("failed to exchange the service account token: %v", err)
+		h.CustomAbort(http.StatusInternalServerError, "")
+	}
+	h.Data["json"] = tk
+	h.ServeJSON()
+}
@@ -17,6 +17,7 @@ import (
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/docker/distribution/registry/auth/token"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"crypto/rsa"
 	"crypto/x509"
@@ -29,8 +30,11 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/rbac"
+	robotCtx "github.com/goharbor/harbor/src/common/security/robot"
+	shareCtx "github.com/goharbor/harbor/src/common/security/share"
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/goharbor/harbor/src/core/config"
 )
@@ -168,6 +172,44 @@ func TestMakeToken(t *testing.T) {
 	assert.Equal(t, claims.Audience, svc, "Audience mismatch")
 }
 
+func TestVerifyToken(t *testing.T) {
+	pk, _ := getKeyAndCertPath()
+	privateKey = pk
+	ra := []*token.ResourceActions{{
+		Type:    "repository",
+		Name:    "library/hello-world",
+		Actions: []string{"pull"},
+	}}
+	tk, err := MakeToken("tester", Registry, ra)
+	require.Nil(t, err)
+
+	tok, err := VerifyToken(tk.Token, Registry)
+	require.Nil(t, err)
+	assert.Equal(t, "tester", tok.Claims.Subject)
+	assert.Equal(t, *ra[0], *tok.Claims.Access[0])
+
+	// the key is loaded once
+	key, err := getVerificationKey()
+	require.Nil(t, err)
+	_, err = VerifyToken(tk.Token, Registry)
+	require.Nil(t, err)
+	cached, err := getVerificationKey()
+	require.Nil(t, err)
+	assert.True(t, key == cached)
+
+	// audience mismatch
+	_, err = VerifyToken(tk.Token, Notary)
+	assert.NotNil(t, err)
+
+	// malformed token
+	_, err = VerifyToken("invalid", Registry)
+	assert.NotNil(t, err)
+
+	// tampered signature
+	_, err = VerifyToken(tk.Token+"a", Registry)
+	assert.NotNil(t, err)
+}
+
 func TestPermToActions(t *testing.T) {
 	perm1 := "RWM"
 	perm2 := "MRR"
@@ -308,6 +350,93 @@ func TestFilterAccess(t *testing.T) {
 	assert.Equal(t, ra2, *a3[0], "Mismatch after registry filter Map")
 }
 
+func TestShareLinkFilter(t *testing.T) {
+	access := GetResourceActions([]string{
+		"repository:library/app:pull,push",
+		"repository:library/other:pull",
+	})
+	ctx := shareCtx.NewSecurityContext(&models.ShareLink{
+		ID:         1,
+		Repository: "library/app",
+		Reference:  "1.0",
+	})
+	err := filterAccess(access, ctx, nil, registryFilterMap)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"pull"}, access[0].Actions)
+	assert.Equal(t, []string{}, access[1].Actions)
+}
+
+func TestPullAllowed(t *testing.T) {
+	ctx := shareCtx.NewSecurityContext(&models.ShareLink{
+		ID:         1,
+		Repository: "library/app",
+		Reference:  "1.0",
+	})
+	allowed, err := PullAllowed(ctx, nil, "library/app")
+	require.Nil(t, err)
+	assert.True(t, allowed)
+	allowed, err = PullAllowed(ctx, nil, "library/other")
+	require.Nil(t, err)
+	assert.False(t, allowed)
+}
+
+func TestUsagePolicyExempted(t *testing.T) {
+	assert.True(t, usagePolicyExempted(&fakeSecurityContext{isAdmin: true}))
+	assert.False(t, usagePolicyExempted(&fakeSecurityContext{isAdmin: false}))
+	assert.True(t, usagePolicyExempted(robotCtx.NewSecurityContext(&models.Robot{Name: "robot$ci"}, nil, nil)))
+}
+
+// writerSecurityContext has the write permission to all the projects
+type writerSecurityContext struct {
+	fakeSecurityContext
+}
+
+func (w *writerSecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+	return true
+}
+func (w *writerSecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
+	return true
+}
+
+func TestUsagePolicyPending(t *testing.T) {
+	pending := true
+	getRepoAccess = func(repository, username string) (string, error) {
+		return "", nil
+	}
+	usagePolicyPending = func(projectID int64, username string) (bool, error) {
+		return pending, nil
+	}
+	defer func() {
+		getRepoAccess = dao.GetRepoAccess
+		usagePolicyPending = dao.UsagePolicyPending
+	}()
+	pm := &fakeProjectManager{
+		projects: map[string]*models.Project{
+			"library": {ProjectID: 1, Name: "library"},
+		},
+	}
+
+	// the push still works while the acknowledgment is pending
+	access := GetResourceActions([]string{"repository:library/app:pull,push"})
+	require.Nil(t, filterAccess(access, &writerSecurityContext{}, pm, registryFilterMap))
+	assert.Equal(t, []string{"push"}, access[0].Actions)
+
+	pending = false
+	access = GetResourceActions([]string{"repository:library/app:pull,push"})
+	require.Nil(t, filterAccess(access, &writerSecurityContext{}, pm, registryFilterMap))
+	assert.Equal(t, []string{"push", "pull"}, access[0].Actions)
+}
+
+func TestMergeRepoAccess(t *testing.T) {
+	assert.Equal(t, "", mergeRepoAccess("", ""))
+	assert.Equal(t, "R", mergeRepoAccess("", models.RepoAccessPull))
+	assert.Equal(t, "RW", mergeRepoAccess("", models.RepoAccessPush))
+	assert.Equal(t, "R", mergeRepoAccess("R", models.RepoAccessPull))
+	assert.Equal(t, "RW", mergeRepoAccess("R", models.RepoAccessPush))
+	assert.Equal(t, "RWM", mergeRepoAccess("RWM", models.RepoAccessPull))
+	assert.Equal(t, "RWM", mergeRepoAccess("RWM", models.RepoAccessPush))
+}
+
 func TestParseScopes(t *testing.T) {
 	assert := assert.New(t)
 	u1 := "/service/token?account=admin&scope=repository%3Alibrary%2Fregistry%3Apush%2Cpull&scope=repository%3Ahello-world%2Fregistry%3Apull&service=harbor-registry"
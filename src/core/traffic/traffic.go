@@ -0,0 +1,219 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+const (
+	// DefaultInterval is the default interval between two flushes of the recorded traffic
+	DefaultInterval = time.Minute
+	// MaxRepositories is the max count of the repositories whose traffic is recorded since
+	// the start of the process, the ones beyond it aren't recorded until the restart
+	MaxRepositories = 10000
+)
+
+var (
+	// the traffic recorded since the last flush, keyed by repository and day
+	records = map[string]*models.RegistryTraffic{}
+	// the traffic recorded since the start of the process, keyed by repository,
+	// they are exposed as the counters of Prometheus
+	totals = map[string]*models.RegistryTraffic{}
+	lock   sync.Mutex
+	// the function writing the traffic into database, replaced in testing
+	addRegistryTraffic = dao.AddRegistryTraffic
+	// the function checking the existence of the project, replaced in testing
+	projectExists = func(name string) (bool, error) {
+		return config.GlobalProjectMgr.Exists(name)
+	}
+
+	labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// IsError returns whether the status code of the response is counted as an error, the
+// challenges of the authentication are part of the normal flow of the clients and
+// aren't recorded at all
+func IsError(status int) bool {
+	return status >= http.StatusBadRequest && status != http.StatusUnauthorized
+}
+
+// Record records the request to the registry API of the repository in memory, the
+// records are written into database in batch by Flush. The unauthenticated requests
+// aren't recorded, and a repository starts being recorded only after it's served
+// successfully in an existing project, so the requests to random names can't grow
+// the records
+func Record(repository string, status int, bytes int64, t time.Time) {
+	if status == http.StatusUnauthorized {
+		return
+	}
+	project, _ := utils.ParseRepository(repository)
+	if !tracked(repository) {
+		if IsError(status) {
+			return
+		}
+		exist, err := projectExists(project)
+		if err != nil {
+			log.Errorf("failed to check the existence of project %s, skip recording the traffic: %v", project, err)
+			return
+		}
+		if !exist {
+			return
+		}
+	}
+	traffic := &models.RegistryTraffic{
+		ProjectName:  project,
+		Repository:   repository,
+		Day:          t.UTC().Truncate(24 * time.Hour),
+		RequestCount: 1,
+		BytesServed:  bytes,
+	}
+	if IsError(status) {
+		traffic.ErrorCount = 1
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	total, ok := totals[repository]
+	if !ok {
+		if len(totals) >= MaxRepositories {
+			log.Debugf("the traffic of more than %d repositories is recorded, skip recording %s", MaxRepositories, repository)
+			return
+		}
+		total = &models.RegistryTraffic{
+			ProjectName: project,
+			Repository:  repository,
+		}
+		totals[repository] = total
+	}
+	add(total, traffic)
+	record(traffic)
+}
+
+// tracked returns whether the traffic of the repository is recorded already
+func tracked(repository string) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	_, ok := totals[repository]
+	return ok
+}
+
+func record(traffic *models.RegistryTraffic) {
+	key := traffic.Repository + "@" + traffic.Day.Format("2006-01-02")
+	r, ok := records[key]
+	if !ok {
+		records[key] = traffic
+		return
+	}
+	add(r, traffic)
+}
+
+func add(to, from *models.RegistryTraffic) {
+	to.RequestCount += from.RequestCount
+	to.ErrorCount += from.ErrorCount
+	to.BytesServed += from.BytesServed
+}
+
+// Flush writes the recorded traffic into database, the records are kept
+// for the next flush if the writing fails
+func Flush() error {
+	lock.Lock()
+	traffics := make([]*models.RegistryTraffic, 0, len(records))
+	for _, t := range records {
+		traffics = append(traffics, t)
+	}
+	records = map[string]*models.RegistryTraffic{}
+	lock.Unlock()
+
+	if len(traffics) == 0 {
+		return nil
+	}
+	if err := addRegistryTraffic(traffics); err != nil {
+		lock.Lock()
+		for _, t := range traffics {
+			record(t)
+		}
+		lock.Unlock()
+		return err
+	}
+	log.Debugf("the traffic of %d repositories flushed", len(traffics))
+	return nil
+}
+
+// Start flushes the recorded traffic every interval in background
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := Flush(); err != nil {
+				log.Errorf("failed to flush the registry traffic: %v", err)
+			}
+		}
+	}()
+	log.Infof("the registry traffic recorder started, interval: %v", interval)
+}
+
+// WriteMetrics writes the traffic recorded since the start of the process as the
+// counters in the text format of Prometheus
+func WriteMetrics(w io.Writer) error {
+	lock.Lock()
+	traffics := make([]models.RegistryTraffic, 0, len(totals))
+	for _, t := range totals {
+		traffics = append(traffics, *t)
+	}
+	lock.Unlock()
+	sort.Slice(traffics, func(i, j int) bool {
+		return traffics[i].Repository < traffics[j].Repository
+	})
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(t *models.RegistryTraffic) int64
+	}{
+		{"harbor_registry_requests_total", "The count of the requests to the registry API.",
+			func(t *models.RegistryTraffic) int64 { return t.RequestCount }},
+		{"harbor_registry_request_errors_total", "The count of the requests to the registry API responded with errors.",
+			func(t *models.RegistryTraffic) int64 { return t.ErrorCount }},
+		{"harbor_registry_served_bytes_total", "The bytes served by the registry API.",
+			func(t *models.RegistryTraffic) int64 { return t.BytesServed }},
+	}
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", metric.name, metric.help, metric.name); err != nil {
+			return err
+		}
+		for i := range traffics {
+			if _, err := fmt.Fprintf(w, "%s{project=\"%s\",repository=\"%s\"} %d\n", metric.name,
+				labelReplacer.Replace(traffics[i].ProjectName), labelReplacer.Replace(traffics[i].Repository),
+				metric.value(&traffics[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
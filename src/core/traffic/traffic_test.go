@@ -0,0 +1,142 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsError(t *testing.T) {
+	assert.False(t, IsError(http.StatusOK))
+	assert.False(t, IsError(http.StatusTemporaryRedirect))
+	assert.False(t, IsError(http.StatusUnauthorized))
+	assert.True(t, IsError(http.StatusNotFound))
+	assert.True(t, IsError(http.StatusInternalServerError))
+}
+
+func TestFlush(t *testing.T) {
+	var written []*models.RegistryTraffic
+	var err error
+	addRegistryTraffic = func(traffics []*models.RegistryTraffic) error {
+		written = traffics
+		return err
+	}
+	exists := projectExists
+	projectExists = func(name string) (bool, error) {
+		return name == "library", nil
+	}
+	defer func() {
+		addRegistryTraffic = dao.AddRegistryTraffic
+		projectExists = exists
+		records = map[string]*models.RegistryTraffic{}
+		totals = map[string]*models.RegistryTraffic{}
+	}()
+
+	// nothing recorded
+	require.Nil(t, Flush())
+	assert.Nil(t, written)
+
+	today := time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC)
+	Record("library/hello-world", http.StatusOK, 100, today)
+	Record("library/hello-world", http.StatusNotFound, 10, today)
+	Record("library/hello-world", http.StatusOK, 100, today.Add(-24*time.Hour))
+	Record("library/ubuntu", http.StatusOK, 1000, today)
+	// the unauthenticated requests, the failed requests to the repositories not recorded
+	// yet and the requests to the projects not existing aren't recorded
+	Record("library/hello-world", http.StatusUnauthorized, 0, today)
+	Record("library/random", http.StatusNotFound, 10, today)
+	Record("random/app", http.StatusOK, 10, today)
+
+	// the records are kept when failed to write
+	err = errors.New("error")
+	assert.NotNil(t, Flush())
+	assert.Equal(t, 3, len(written))
+	Record("library/ubuntu", http.StatusForbidden, 0, today)
+
+	err = nil
+	require.Nil(t, Flush())
+	require.Equal(t, 3, len(written))
+	traffics := map[string]*models.RegistryTraffic{}
+	for _, t := range written {
+		traffics[t.Repository+"@"+t.Day.Format("2006-01-02")] = t
+	}
+	hello := traffics["library/hello-world@2019-03-01"]
+	require.NotNil(t, hello)
+	assert.Equal(t, "library", hello.ProjectName)
+	assert.Equal(t, int64(2), hello.RequestCount)
+	assert.Equal(t, int64(1), hello.ErrorCount)
+	assert.Equal(t, int64(110), hello.BytesServed)
+	ubuntu := traffics["library/ubuntu@2019-03-01"]
+	require.NotNil(t, ubuntu)
+	assert.Equal(t, int64(2), ubuntu.RequestCount)
+	assert.Equal(t, int64(1), ubuntu.ErrorCount)
+
+	// flushed
+	written = nil
+	require.Nil(t, Flush())
+	assert.Nil(t, written)
+
+	// the totals are kept after the flushes
+	buf := &bytes.Buffer{}
+	require.Nil(t, WriteMetrics(buf))
+	assert.Equal(t, `# HELP harbor_registry_requests_total The count of the requests to the registry API.
+# TYPE harbor_registry_requests_total counter
+harbor_registry_requests_total{project="library",repository="library/hello-world"} 3
+harbor_registry_requests_total{project="library",repository="library/ubuntu"} 2
+# HELP harbor_registry_request_errors_total The count of the requests to the registry API responded with errors.
+# TYPE harbor_registry_request_errors_total counter
+harbor_registry_request_errors_total{project="library",repository="library/hello-world"} 1
+harbor_registry_request_errors_total{project="library",repository="library/ubuntu"} 1
+# HELP harbor_registry_served_bytes_total The bytes served by the registry API.
+# TYPE harbor_registry_served_bytes_total counter
+harbor_registry_served_bytes_total{project="library",repository="library/hello-world"} 210
+harbor_registry_served_bytes_total{project="library",repository="library/ubuntu"} 1000
+`, buf.String())
+}
+
+func TestRecordLimit(t *testing.T) {
+	exists := projectExists
+	projectExists = func(name string) (bool, error) {
+		return true, nil
+	}
+	defer func() {
+		projectExists = exists
+		records = map[string]*models.RegistryTraffic{}
+		totals = map[string]*models.RegistryTraffic{}
+	}()
+
+	now := time.Now()
+	for i := 0; i < MaxRepositories; i++ {
+		Record(fmt.Sprintf("library/app-%d", i), http.StatusOK, 1, now)
+	}
+	Record("library/beyond", http.StatusOK, 1, now)
+	assert.Equal(t, MaxRepositories, len(totals))
+	_, ok := totals["library/beyond"]
+	assert.False(t, ok)
+
+	// the recorded repositories are still counted
+	Record("library/app-0", http.StatusOK, 1, now)
+	assert.Equal(t, int64(2), totals["library/app-0"].RequestCount)
+}
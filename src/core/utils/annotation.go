@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// IndexAnnotations reads the CI metadata and the labels from the config of the image and stores
+// them in DB, the existing record of the tag is deleted if the image isn't labeled. Only the images
+// with schema2 manifest are supported
+func IndexAnnotations(repository, tag string) error {
+	client, err := NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		return err
+	}
+	digest, mediaType, payload, err := client.PullManifest(tag, []string{schema2.MediaTypeManifest})
+	if err != nil {
+		return err
+	}
+	if mediaType != schema2.MediaTypeManifest {
+		log.Debugf("the media type of %s:%s is %s, skip indexing the annotations", repository, tag, mediaType)
+		return dao.DeleteArtifactAnnotation(repository, tag)
+	}
+	manifest := &schema2.DeserializedManifest{}
+	if err = manifest.UnmarshalJSON(payload); err != nil {
+		return err
+	}
+	_, reader, err := client.PullBlob(manifest.Target().Digest.String())
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	config := &struct {
+		Config struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"config"`
+	}{}
+	if err = json.NewDecoder(reader).Decode(config); err != nil {
+		return err
+	}
+
+	if len(config.Config.Labels) == 0 {
+		return dao.DeleteArtifactAnnotation(repository, tag)
+	}
+	// the images labeled without the CI metadata are kept for the full-text search
+	annotation := models.ParseArtifactAnnotations(config.Config.Labels)
+	if annotation == nil {
+		annotation = &models.ArtifactAnnotation{}
+	}
+	annotation.Labels = models.FormatAnnotationLabels(config.Config.Labels)
+	annotation.Repository = repository
+	annotation.Tag = tag
+	annotation.Digest = digest
+	return dao.SetArtifactAnnotation(annotation)
+}
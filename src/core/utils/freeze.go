@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the function listing the freeze windows of the project, replaced in testing
+var listFreezeWindows = dao.ListFreezeWindowsByProjectName
+
+// FrozenError is returned when the repositories of the project can't be changed as it's
+// in a freeze window
+type FrozenError struct {
+	Window *models.FreezeWindow
+	Until  time.Time
+}
+
+func (f *FrozenError) Error() string {
+	return f.Window.GetMessage(f.Until)
+}
+
+// ActiveFreezeWindow returns the freeze window of the project which the current time is in and
+// the end of its occurrence, nil is returned if the project isn't frozen
+func ActiveFreezeWindow(projectName string) (*models.FreezeWindow, time.Time, error) {
+	windows, err := listFreezeWindows(projectName)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	window, until := models.ActiveFreezeWindow(windows, time.Now())
+	return window, until, nil
+}
+
+// CheckFreezeWindow returns a *FrozenError if the project is in a freeze window
+func CheckFreezeWindow(projectName string) error {
+	window, until, err := ActiveFreezeWindow(projectName)
+	if err != nil {
+		return err
+	}
+	if window != nil {
+		return &FrozenError{
+			Window: window,
+			Until:  until,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFreezeWindow(t *testing.T) {
+	defer func(f func(string) ([]*models.FreezeWindow, error)) {
+		listFreezeWindows = f
+	}(listFreezeWindows)
+	now := time.Now()
+	listFreezeWindows = func(projectName string) ([]*models.FreezeWindow, error) {
+		if projectName != "frozen" {
+			return nil, nil
+		}
+		return []*models.FreezeWindow{
+			{
+				ID:        1,
+				StartTime: now.Add(-time.Hour),
+				EndTime:   now.Add(time.Hour),
+				Repeat:    models.FreezeWindowRepeatNone,
+				Message:   "release 1.0",
+			},
+		}, nil
+	}
+
+	assert.Nil(t, CheckFreezeWindow("library"))
+
+	err := CheckFreezeWindow("frozen")
+	require.NotNil(t, err)
+	frozen, ok := err.(*FrozenError)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), frozen.Window.ID)
+	assert.Contains(t, frozen.Error(), "release 1.0")
+}
@@ -20,8 +20,10 @@ import (
 	"github.com/goharbor/harbor/src/common/job"
 	jobmodels "github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/common/models"
+	common_utils "github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/scanner"
 
 	"encoding/json"
 	"fmt"
@@ -78,6 +80,87 @@ func scanAll(cron string, c ...job.Client) (string, error) {
 	return client.SubmitJob(data)
 }
 
+// ScheduleProjectReport schedules the periodic job sending the report emails of the projects to the
+// subscribers based on the cron string, and appends a record in admin job table.
+func ScheduleProjectReport(cron string) (string, error) {
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: job.ProjectReport,
+		Kind: job.JobKindPeriodic,
+	})
+	if err != nil {
+		return "", err
+	}
+	data := &jobmodels.JobData{
+		Name: job.ProjectReport,
+		Metadata: &jobmodels.JobMetadata{
+			JobKind:  job.JobKindPeriodic,
+			IsUnique: true,
+			Cron:     cron,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d", config.InternalCoreURL(), id),
+	}
+	uuid, err := GetJobServiceClient().SubmitJob(data)
+	if err != nil {
+		if err := dao.DeleteAdminJob(id); err != nil {
+			log.Errorf("failed to delete admin job %d: %v", id, err)
+		}
+		return "", err
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		log.Warningf("Failed to set UUID for admin job %d: %v", id, err)
+	}
+	log.Infof("project report job scheduled, cron string: '%s'", cron)
+	return uuid, nil
+}
+
+// ReconciliationCron is the cron of the daily check of the drifts between the registry and the database
+const ReconciliationCron = "0 0 3 * * *"
+
+// ScheduleReconciliation schedules the periodic job checking the drifts between the registry and the
+// database if it hasn't been scheduled, the drifts are only reported and the repair is left to the
+// system administrators
+func ScheduleReconciliation() error {
+	jobs, err := dao.GetAdminJobs(&models.AdminJobQuery{
+		Name: job.Reconciliation,
+		Kind: job.JobKindPeriodic,
+	})
+	if err != nil {
+		return err
+	}
+	if len(jobs) > 0 {
+		log.Debugf("the reconciliation job has been scheduled, uuid: %s", jobs[0].UUID)
+		return nil
+	}
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: job.Reconciliation,
+		Kind: job.JobKindPeriodic,
+	})
+	if err != nil {
+		return err
+	}
+	data := &jobmodels.JobData{
+		Name: job.Reconciliation,
+		Metadata: &jobmodels.JobMetadata{
+			JobKind:  job.JobKindPeriodic,
+			IsUnique: true,
+			Cron:     ReconciliationCron,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d", config.InternalCoreURL(), id),
+	}
+	uuid, err := GetJobServiceClient().SubmitJob(data)
+	if err != nil {
+		if err := dao.DeleteAdminJob(id); err != nil {
+			log.Errorf("failed to delete admin job %d: %v", id, err)
+		}
+		return err
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		log.Warningf("Failed to set UUID for admin job %d: %v", id, err)
+	}
+	log.Infof("reconciliation job scheduled, cron string: '%s'", ReconciliationCron)
+	return nil
+}
+
 // GetJobServiceClient returns the job service client instance.
 func GetJobServiceClient() job.Client {
 	cl.Lock()
@@ -88,8 +171,48 @@ func GetJobServiceClient() job.Client {
 	return jobServiceClient
 }
 
+// RecalculateSeverities submits the job recalculating the severity of the stored scan reports to jobservice,
+// it's called when the source of CVSS is changed
+func RecalculateSeverities() (string, error) {
+	id, err := dao.AddAdminJob(&models.AdminJob{
+		Name: job.SeverityRecalculation,
+		Kind: job.JobKindGeneric,
+	})
+	if err != nil {
+		return "", err
+	}
+	bundle, err := scanner.CABundle(scanner.DefaultID)
+	if err != nil {
+		return "", err
+	}
+	data := &jobmodels.JobData{
+		Name: job.SeverityRecalculation,
+		Parameters: map[string]interface{}{
+			"ca_bundle": bundle,
+		},
+		Metadata: &jobmodels.JobMetadata{
+			JobKind:  job.JobKindGeneric,
+			IsUnique: true,
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d", config.InternalCoreURL(), id),
+	}
+	uuid, err := GetJobServiceClient().SubmitJob(data)
+	if err != nil {
+		return "", err
+	}
+	if err = dao.SetAdminJobUUID(id, uuid); err != nil {
+		log.Warningf("Failed to set UUID for admin job %d: %v", id, err)
+	}
+	return uuid, nil
+}
+
 // TriggerImageScan triggers an image scan job on jobservice.
 func TriggerImageScan(repository string, tag string) error {
+	return triggerScan(repository, tag, 0)
+}
+
+// triggerScan triggers the scan job of the image, the job is tracked by the batch if batchID isn't 0
+func triggerScan(repository, tag string, batchID int64) error {
 	repoClient, err := NewRepositoryClientForUI("harbor-core", repository)
 	if err != nil {
 		return err
@@ -102,15 +225,33 @@ func TriggerImageScan(repository string, tag string) error {
 		log.Errorf("Failed to get Manifest for %s:%s", repository, tag)
 		return err
 	}
-	return triggerImageScan(repository, tag, digest, GetJobServiceClient())
+	projectName, _ := common_utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("unable to perform scan: project %s not found", projectName)
+	}
+	s, err := scanner.ForProject(project)
+	if err != nil {
+		return err
+	}
+	bundle, err := scanner.CABundle(s.ID)
+	if err != nil {
+		return err
+	}
+	return triggerImageScan(s.JobName, repository, tag, digest, bundle, batchID, GetJobServiceClient())
 }
 
-func triggerImageScan(repository, tag, digest string, client job.Client) error {
+// triggerImageScan submits the job of the scanner to scan the image
+func triggerImageScan(jobName, repository, tag, digest, caBundle string, batchID int64, client job.Client) error {
 	id, err := dao.AddScanJob(models.ScanJob{
 		Repository: repository,
 		Digest:     digest,
 		Tag:        tag,
 		Status:     models.JobPending,
+		BatchID:    batchID,
 	})
 	if err != nil {
 		return err
@@ -119,7 +260,7 @@ func triggerImageScan(repository, tag, digest string, client job.Client) error {
 	if err != nil {
 		return err
 	}
-	data, err := buildScanJobData(id, repository, tag, digest)
+	data, err := buildScanJobData(jobName, id, repository, tag, digest, caBundle)
 	if err != nil {
 		return err
 	}
@@ -134,12 +275,13 @@ func triggerImageScan(repository, tag, digest string, client job.Client) error {
 	return nil
 }
 
-func buildScanJobData(jobID int64, repository, tag, digest string) (*jobmodels.JobData, error) {
+func buildScanJobData(jobName string, jobID int64, repository, tag, digest, caBundle string) (*jobmodels.JobData, error) {
 	parms := job.ScanJobParms{
 		JobID:      jobID,
 		Repository: repository,
 		Digest:     digest,
 		Tag:        tag,
+		CABundle:   caBundle,
 	}
 	parmsMap := make(map[string]interface{})
 	b, err := json.Marshal(parms)
@@ -156,7 +298,7 @@ func buildScanJobData(jobID int64, repository, tag, digest string) (*jobmodels.J
 	}
 
 	data := &jobmodels.JobData{
-		Name:       job.ImageScanJob,
+		Name:       jobName,
 		Parameters: jobmodels.Parameters(parmsMap),
 		Metadata:   &meta,
 		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/scan/%d", config.InternalCoreURL(), jobID),
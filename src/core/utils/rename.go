@@ -0,0 +1,155 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/cache"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// MoveRepository renames the repository and moves it to the project specified by projectID. As the
+// registry doesn't support renaming, the tags are copied to the new name with the blobs mounted and
+// deleted from the old one. The renaming is reverted if any tag fails to be copied, dao.ErrDupRows
+// is returned if the new name is used already. The pulls of the old name are redirected during the
+// configured period. A *FrozenError is returned if either project is in a freeze window.
+func MoveRepository(repository *models.RepoRecord, newName string, projectID int64, operator string) error {
+	oldName := repository.Name
+	projectName, repo := utils.ParseRepository(oldName)
+	newProjectName, newRepo := utils.ParseRepository(newName)
+	for _, name := range []string{projectName, newProjectName} {
+		if err := CheckFreezeWindow(name); err != nil {
+			return err
+		}
+	}
+
+	srcClient, err := NewRepositoryClientForUI(operator, oldName)
+	if err != nil {
+		return err
+	}
+	destClient, err := NewRepositoryClientForUI(operator, newName)
+	if err != nil {
+		return err
+	}
+	tags, err := srcClient.ListTag()
+	if err != nil {
+		return err
+	}
+
+	// update the records before copying the tags, otherwise the push events sent by
+	// the registry create the new repository
+	if err = dao.RenameRepository(oldName, newName, projectID); err != nil {
+		return err
+	}
+
+	for i, tag := range tags {
+		if err = Retag(&models.Image{
+			Project: projectName,
+			Repo:    repo,
+			Tag:     tag,
+		}, &models.Image{
+			Project: newProjectName,
+			Repo:    newRepo,
+			Tag:     tag,
+		}); err != nil {
+			// revert the renaming and remove the tags copied
+			for _, t := range tags[:i] {
+				if e := destClient.DeleteTag(t); e != nil {
+					log.Errorf("failed to delete the copied tag %s:%s: %v", newName, t, e)
+				}
+			}
+			if e := dao.RenameRepository(newName, oldName, repository.ProjectID); e != nil {
+				log.Errorf("failed to revert the renaming of repository %s: %v", oldName, e)
+			}
+			return fmt.Errorf("failed to copy %s:%s to %s: %v", oldName, tag, newName, err)
+		}
+	}
+
+	period, err := config.RenameRedirectPeriod()
+	if err != nil {
+		log.Errorf("failed to get the rename redirect period: %v", err)
+	} else if period > 0 {
+		if err = dao.AddRepoRedirect(oldName, newName, time.Now().Add(period)); err != nil {
+			log.Errorf("failed to redirect repository %s to %s: %v", oldName, newName, err)
+		} else if err = cache.InvalidateRedirectSources(); err != nil {
+			log.Errorf("failed to invalidate the cached sources of the redirects: %v", err)
+		}
+	}
+
+	digests := TagDigests(srcClient, oldName, tags)
+	for _, tag := range tags {
+		if err = srcClient.DeleteTag(tag); err != nil {
+			// the tags referencing the same digest are deleted already
+			if regErr, ok := err.(*commonhttp.Error); !ok || regErr.Code != http.StatusNotFound {
+				log.Errorf("failed to delete tag %s:%s after renaming: %v", oldName, tag, err)
+				continue
+			}
+		}
+		AddTagDeletionHistory(oldName, tag, digests[tag], operator)
+	}
+	if err = cache.InvalidateManifests(oldName); err != nil {
+		log.Errorf("failed to invalidate the cached manifests of repository %s: %v", oldName, err)
+	}
+	// the deprecations are moved to the new name
+	for _, name := range []string{oldName, newName} {
+		if err = cache.InvalidateRepoDeprecations(name); err != nil {
+			log.Errorf("failed to invalidate the cached deprecations of %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// TagDigests returns the digests of the tags, the tags whose digest can't be got are skipped
+func TagDigests(client *registry.Repository, repository string, tags []string) map[string]string {
+	digests := map[string]string{}
+	for _, tag := range tags {
+		digest, exist, err := client.ManifestExist(tag)
+		if err != nil {
+			log.Errorf("failed to get the digest of %s:%s: %v", repository, tag, err)
+			continue
+		}
+		if exist {
+			digests[tag] = digest
+		}
+	}
+	return digests
+}
+
+// AddTagDeletionHistory records the deletion of the tag, nothing is recorded if the digest
+// is unknown which means the tag doesn't exist. The error is logged only as the tag is
+// deleted already
+func AddTagDeletionHistory(repository, tag, digest, operator string) {
+	if len(digest) == 0 {
+		return
+	}
+	if _, err := dao.AddTagHistory(&models.TagHistory{
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+		Operation:  models.TagHistoryDelete,
+		Operator:   operator,
+	}); err != nil {
+		log.Errorf("failed to record the deletion of %s:%s: %v", repository, tag, err)
+	}
+}
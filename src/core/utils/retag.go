@@ -17,6 +17,7 @@ package utils
 import (
 	"fmt"
 
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/registry"
@@ -90,6 +91,22 @@ func Retag(srcImage, destImage *models.Image) error {
 	return nil
 }
 
+// AddProvenance records the copy of the image with the digest from the source tag into the tag,
+// the error is logged only as the image is copied already
+func AddProvenance(repository, tag, srcRepository, srcTag, digest, operation, operator string) {
+	if _, err := dao.AddArtifactProvenance(&models.ArtifactProvenance{
+		Repository:       repository,
+		Tag:              tag,
+		Digest:           digest,
+		SourceRepository: srcRepository,
+		SourceTag:        srcTag,
+		Operation:        operation,
+		Operator:         operator,
+	}); err != nil {
+		log.Errorf("failed to record the provenance of %s:%s: %v", repository, tag, err)
+	}
+}
+
 func getRepoName(image *models.Image) string {
 	return fmt.Sprintf("%s/%s", image.Project, image.Repo)
 }
@@ -0,0 +1,142 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+var (
+	// the functions listing the repositories, tags and labeled images and triggering the
+	// scans, replaced in testing
+	listRepositories   = dao.GetRepositories
+	listTags           = listRepositoryTags
+	listResourceLabels = dao.ListResourceLabels
+	triggerBatchScan   = triggerScan
+	updateScanBatch    = dao.UpdateScanBatchStatus
+)
+
+// RunScanBatch expands the filters of the batch into the matched images and triggers the
+// scans of them, the scan jobs are tracked by the batch. The failures of triggering the
+// scans of some images don't stop the others, they are counted as failed in the batch
+func RunScanBatch(batch *models.ScanBatch) error {
+	images, err := expandScanBatch(batch)
+	if err != nil {
+		batch.Status = models.ScanBatchFailed
+		batch.Error = err.Error()
+		if e := updateScanBatch(batch); e != nil {
+			log.Errorf("failed to update scan batch %d: %v", batch.ID, e)
+		}
+		return err
+	}
+
+	batch.Total = len(images)
+	for _, image := range images {
+		repository, tag := splitImage(image)
+		if err := triggerBatchScan(repository, tag, batch.ID); err != nil {
+			log.Errorf("failed to trigger the scan of %s in batch %d: %v", image, batch.ID, err)
+			batch.Failed++
+		}
+	}
+	batch.Status = models.ScanBatchSubmitted
+	log.Infof("%d scans of batch %d triggered, %d failed", batch.Total-batch.Failed, batch.ID, batch.Failed)
+	return updateScanBatch(batch)
+}
+
+// expandScanBatch returns the sorted images in format "<repository>:<tag>" matched by the batch
+func expandScanBatch(batch *models.ScanBatch) ([]string, error) {
+	query := &models.RepositoryQuery{}
+	if batch.ProjectID > 0 {
+		query.ProjectIDs = []int64{batch.ProjectID}
+	}
+	repositories, err := listRepositories(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the repositories: %v", err)
+	}
+
+	images := map[string]bool{}
+	// the repositories whose tags are all candidates
+	candidates := repositories
+	if batch.LabelID > 0 {
+		inScope := map[string]bool{}
+		for _, repository := range repositories {
+			inScope[repository.Name] = true
+		}
+		// the images labeled directly
+		rls, err := listResourceLabels(&models.ResourceLabelQuery{
+			LabelID:      batch.LabelID,
+			ResourceType: common.ResourceTypeImage,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the images with label %d: %v", batch.LabelID, err)
+		}
+		for _, rl := range rls {
+			repository, tag := splitImage(rl.ResourceName)
+			if inScope[repository] && batch.Match(repository, tag) {
+				images[rl.ResourceName] = true
+			}
+		}
+		// the tags of the labeled repositories
+		query.LabelID = batch.LabelID
+		if candidates, err = listRepositories(query); err != nil {
+			return nil, fmt.Errorf("failed to list the repositories with label %d: %v", batch.LabelID, err)
+		}
+	}
+
+	for _, repository := range candidates {
+		if !batch.MatchRepository(repository.Name) {
+			continue
+		}
+		tags, err := listTags(repository.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tags of %s: %v", repository.Name, err)
+		}
+		for _, tag := range tags {
+			if batch.Match(repository.Name, tag) {
+				images[repository.Name+":"+tag] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(images))
+	for image := range images {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func listRepositoryTags(repository string) ([]string, error) {
+	client, err := NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListTag()
+}
+
+func splitImage(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i < 0 {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunScanBatch(t *testing.T) {
+	defer func() {
+		listRepositories = dao.GetRepositories
+		listTags = listRepositoryTags
+		listResourceLabels = dao.ListResourceLabels
+		triggerBatchScan = triggerScan
+		updateScanBatch = dao.UpdateScanBatchStatus
+	}()
+	listRepositories = func(query ...*models.RepositoryQuery) ([]*models.RepoRecord, error) {
+		if query[0].LabelID > 0 {
+			return []*models.RepoRecord{{Name: "library/app"}}, nil
+		}
+		return []*models.RepoRecord{{Name: "library/app"}, {Name: "library/web"}, {Name: "library/db"}}, nil
+	}
+	listTags = func(repository string) ([]string, error) {
+		if repository == "library/db" {
+			return nil, errors.New("unavailable")
+		}
+		return []string{"v1.0", "v2.0"}, nil
+	}
+	listResourceLabels = func(query ...*models.ResourceLabelQuery) ([]*models.ResourceLabel, error) {
+		assert.Equal(t, common.ResourceTypeImage, query[0].ResourceType)
+		return []*models.ResourceLabel{
+			{ResourceName: "library/web:v1.0"},
+			{ResourceName: "other/web:v1.0"},
+		}, nil
+	}
+	triggered := []string{}
+	triggerBatchScan = func(repository, tag string, batchID int64) error {
+		assert.Equal(t, int64(1), batchID)
+		if tag == "v2.0" {
+			return errors.New("failed")
+		}
+		triggered = append(triggered, repository+":"+tag)
+		return nil
+	}
+	var updated *models.ScanBatch
+	updateScanBatch = func(batch *models.ScanBatch) error {
+		updated = batch
+		return nil
+	}
+
+	// the tags of the labeled repository and the labeled images
+	batch := &models.ScanBatch{
+		ID:      1,
+		LabelID: 1,
+	}
+	require.Nil(t, RunScanBatch(batch))
+	assert.Equal(t, []string{"library/app:v1.0", "library/web:v1.0"}, triggered)
+	assert.Equal(t, models.ScanBatchSubmitted, updated.Status)
+	assert.Equal(t, 3, updated.Total)
+	assert.Equal(t, 1, updated.Failed)
+
+	// the patterns
+	triggered = []string{}
+	batch = &models.ScanBatch{
+		ID:         1,
+		Repository: "library/[aw]*",
+		Tag:        "v1.*",
+	}
+	require.Nil(t, RunScanBatch(batch))
+	assert.Equal(t, []string{"library/app:v1.0", "library/web:v1.0"}, triggered)
+	assert.Equal(t, 2, updated.Total)
+	assert.Equal(t, 0, updated.Failed)
+
+	// failed to list the tags
+	batch = &models.ScanBatch{
+		ID: 1,
+	}
+	assert.NotNil(t, RunScanBatch(batch))
+	assert.Equal(t, models.ScanBatchFailed, updated.Status)
+	assert.Contains(t, updated.Error, "library/db")
+}
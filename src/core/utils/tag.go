@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/cache"
+)
+
+// DeleteTag deletes the records of the tag, e.g. the labels and pull time, and then the tag
+// from the registry. The digest must be got before deleting any tag of the repository as the
+// tags referencing the same digest are deleted together, false is returned if the tag has been
+// deleted that way. The error of the registry is returned as is, and the legal holds must be
+// checked by the callers
+func DeleteTag(client *registry.Repository, repository, tag, digest, operator string) (bool, error) {
+	image := fmt.Sprintf("%s:%s", repository, tag)
+	if err := dao.DeleteLabelsOfResource(common.ResourceTypeImage, image); err != nil {
+		return false, fmt.Errorf("failed to delete labels of image %s: %v", image, err)
+	}
+	if err := dao.DeleteTagPullTime(repository, tag); err != nil {
+		return false, fmt.Errorf("failed to delete pull time of image %s: %v", image, err)
+	}
+	if err := dao.DeleteArtifactAnnotation(repository, tag); err != nil {
+		return false, fmt.Errorf("failed to delete annotations of image %s: %v", image, err)
+	}
+	if err := dao.DeleteArtifactLint(repository, tag); err != nil {
+		return false, fmt.Errorf("failed to delete lint findings of image %s: %v", image, err)
+	}
+	if err := dao.DeleteRepoDeprecation(repository, tag); err != nil {
+		return false, fmt.Errorf("failed to delete deprecation of image %s: %v", image, err)
+	}
+	if err := cache.InvalidateRepoDeprecations(repository); err != nil {
+		log.Errorf("failed to invalidate the cached deprecations of %s: %v", repository, err)
+	}
+	// the aliases pointing to the digest are deleted by the registry together
+	if err := dao.DeleteTagAliasesByDigest(repository, digest); err != nil {
+		return false, fmt.Errorf("failed to delete aliases of image %s: %v", image, err)
+	}
+	if err := client.DeleteTag(tag); err != nil {
+		if regErr, ok := err.(*commonhttp.Error); !ok || regErr.Code != http.StatusNotFound {
+			return false, err
+		}
+		// deleted with the tag referencing the same digest
+		AddTagDeletionHistory(repository, tag, digest, operator)
+		return false, nil
+	}
+	log.Infof("delete tag: %s", image)
+	AddTagDeletionHistory(repository, tag, digest, operator)
+	// the tags referencing the same digest are deleted as well
+	if err := cache.InvalidateManifests(repository); err != nil {
+		log.Errorf("failed to invalidate the cached manifests of repository %s: %v", repository, err)
+	}
+	return true, nil
+}
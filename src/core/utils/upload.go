@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// PurgeStaleUploadSessions cancels the blob upload sessions which have no activity
+// during the age in registry to release the space of the partial blobs, and returns
+// the count of the purged sessions.
+func PurgeStaleUploadSessions(age time.Duration) (int64, error) {
+	before := time.Now().Add(-age)
+	sessions, err := dao.ListUploadSessions(&models.UploadSessionQuery{
+		UpdatedBefore: &before,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, session := range sessions {
+		// the sessions tracked without the state can't be canceled, they're left to the
+		// upload purging of registry
+		if len(session.State) > 0 {
+			client, err := NewRepositoryClientForUI("harbor-core", session.Repository)
+			if err != nil {
+				return count, err
+			}
+			if err = client.CancelBlobUpload(session.UUID, session.State); err != nil {
+				log.Errorf("failed to cancel the upload session %s of repository %s: %v", session.UUID, session.Repository, err)
+				continue
+			}
+		}
+		if err = dao.DeleteUploadSession(session.UUID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// PurgeExpiredUploadSessions purges the upload sessions older than the configured age
+func PurgeExpiredUploadSessions() (int64, error) {
+	age, err := config.UploadPurgingAge()
+	if err != nil {
+		return 0, err
+	}
+	if age <= 0 {
+		return 0, nil
+	}
+	return PurgeStaleUploadSessions(age)
+}
@@ -0,0 +1,156 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulndb
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+const (
+	metadataFile        = "metadata.json"
+	vulnerabilitiesFile = "vulnerabilities.json"
+	// the max size of a single file in the bundle
+	maxFileSize = 512 << 20
+)
+
+// the severities supported by Clair
+var severities = map[string]bool{
+	"Unknown":    true,
+	"Negligible": true,
+	"Low":        true,
+	"Medium":     true,
+	"High":       true,
+	"Critical":   true,
+	"Defcon1":    true,
+}
+
+// Bundle is the content of a vulnerability database bundle
+type Bundle struct {
+	Metadata        *models.VulnDBMetadata
+	Vulnerabilities []*models.VulnDBVulnerability
+}
+
+// ParseBundle reads the bundle from the gzipped tarball and verifies the files
+// in it against the digests recorded in the metadata file
+func ParseBundle(r io.Reader) (*Bundle, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("the bundle isn't a gzipped tarball: %v", err)
+	}
+	defer gr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the bundle: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if header.Size > maxFileSize {
+			return nil, fmt.Errorf("the size of file %s exceeds the limit %d", name, maxFileSize)
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(tr, maxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s from the bundle: %v", name, err)
+		}
+		files[name] = data
+	}
+
+	data, ok := files[metadataFile]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in the bundle", metadataFile)
+	}
+	metadata := &models.VulnDBMetadata{}
+	if err = json.Unmarshal(data, metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", metadataFile, err)
+	}
+	if err = validateMetadata(metadata, files); err != nil {
+		return nil, err
+	}
+
+	vulnerabilities := []*models.VulnDBVulnerability{}
+	if err = json.Unmarshal(files[vulnerabilitiesFile], &vulnerabilities); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", vulnerabilitiesFile, err)
+	}
+	for i, v := range vulnerabilities {
+		if err = validateVulnerability(v); err != nil {
+			return nil, fmt.Errorf("invalid vulnerability at index %d: %v", i, err)
+		}
+	}
+
+	return &Bundle{
+		Metadata:        metadata,
+		Vulnerabilities: vulnerabilities,
+	}, nil
+}
+
+func validateMetadata(metadata *models.VulnDBMetadata, files map[string][]byte) error {
+	if len(metadata.Version) == 0 {
+		return fmt.Errorf("version is missing in %s", metadataFile)
+	}
+	if metadata.CreatedAt.IsZero() {
+		return fmt.Errorf("created_at is missing in %s", metadataFile)
+	}
+	if _, ok := metadata.Files[vulnerabilitiesFile]; !ok {
+		return fmt.Errorf("the digest of %s is missing in %s", vulnerabilitiesFile, metadataFile)
+	}
+	for name, digest := range metadata.Files {
+		data, ok := files[name]
+		if !ok {
+			return fmt.Errorf("%s not found in the bundle", name)
+		}
+		sum := sha256.Sum256(data)
+		if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+			return fmt.Errorf("the digest of %s doesn't match, expected: %s, actual: %s", name, digest, actual)
+		}
+	}
+	return nil
+}
+
+func validateVulnerability(v *models.VulnDBVulnerability) error {
+	if v == nil {
+		return fmt.Errorf("empty vulnerability")
+	}
+	if len(v.Namespace) == 0 || len(v.Name) == 0 {
+		return fmt.Errorf("namespace and name are required")
+	}
+	if !severities[v.Severity] {
+		return fmt.Errorf("unsupported severity %q of %s", v.Severity, v.Name)
+	}
+	for _, f := range v.FixedIn {
+		if f == nil || len(f.Name) == 0 || len(f.Version) == 0 {
+			return fmt.Errorf("name and version of the fixed features of %s are required", v.Name)
+		}
+	}
+	return nil
+}
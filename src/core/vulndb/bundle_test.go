@@ -0,0 +1,119 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulndb
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func buildBundle(t *testing.T, files map[string][]byte) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, data := range files {
+		require.Nil(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write(data)
+		require.Nil(t, err)
+	}
+	require.Nil(t, tw.Close())
+	require.Nil(t, gw.Close())
+	return buf
+}
+
+func buildFiles(t *testing.T, vulnerabilities []*models.VulnDBVulnerability, tamper bool) map[string][]byte {
+	vulnData, err := json.Marshal(vulnerabilities)
+	require.Nil(t, err)
+	metadata := &models.VulnDBMetadata{
+		Version:   "2019.05.01",
+		CreatedAt: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC),
+		Files: map[string]string{
+			vulnerabilitiesFile: digest(vulnData),
+		},
+	}
+	metaData, err := json.Marshal(metadata)
+	require.Nil(t, err)
+	if tamper {
+		vulnData = append(vulnData, ' ')
+	}
+	return map[string][]byte{
+		metadataFile:               metaData,
+		"./" + vulnerabilitiesFile: vulnData,
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	vulnerabilities := []*models.VulnDBVulnerability{
+		{
+			Namespace:     "debian:9",
+			VersionFormat: "dpkg",
+			Name:          "CVE-2019-0001",
+			Severity:      "High",
+			FixedIn: []*models.VulnDBFeature{
+				{
+					Name:    "openssl",
+					Version: "1.1.0j-1",
+				},
+			},
+		},
+	}
+
+	// valid bundle
+	bundle, err := ParseBundle(buildBundle(t, buildFiles(t, vulnerabilities, false)))
+	require.Nil(t, err)
+	assert.Equal(t, "2019.05.01", bundle.Metadata.Version)
+	require.Equal(t, 1, len(bundle.Vulnerabilities))
+	assert.Equal(t, "CVE-2019-0001", bundle.Vulnerabilities[0].Name)
+	assert.Equal(t, "openssl", bundle.Vulnerabilities[0].FixedIn[0].Name)
+
+	// digest mismatch
+	_, err = ParseBundle(buildBundle(t, buildFiles(t, vulnerabilities, true)))
+	assert.NotNil(t, err)
+
+	// metadata missing
+	files := buildFiles(t, vulnerabilities, false)
+	delete(files, metadataFile)
+	_, err = ParseBundle(buildBundle(t, files))
+	assert.NotNil(t, err)
+
+	// unsupported severity
+	vulnerabilities[0].Severity = "Severe"
+	_, err = ParseBundle(buildBundle(t, buildFiles(t, vulnerabilities, false)))
+	assert.NotNil(t, err)
+
+	// not a gzipped tarball
+	_, err = ParseBundle(bytes.NewBufferString("invalid"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulndb
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/scanner"
+)
+
+// ErrImportRunning is returned when another import is still running
+var ErrImportRunning = errors.New("another import of vulnerability database is running")
+
+// Importer imports the vulnerabilities of the bundle into the scanner
+type Importer interface {
+	// Import returns the count of vulnerabilities imported
+	Import(bundle *Bundle) (int64, error)
+}
+
+// clairImporter feeds the vulnerabilities to Clair through its API as the updaters of Clair
+// can't fetch the data in the offline deployments. Clair links the vulnerabilities to the
+// features of the layers scanned already, so they appear in the scan results. Only the
+// vulnerabilities are imported, the data of Notary isn't part of the bundle
+type clairImporter struct {
+	client func() (*clair.Client, error)
+}
+
+func (c *clairImporter) Import(bundle *Bundle) (int64, error) {
+	client, err := c.client()
+	if err != nil {
+		return 0, err
+	}
+	count, err := insertVulnerabilities(client, bundle.Vulnerabilities)
+	if err != nil {
+		return count, err
+	}
+	// refresh the update time of the namespaces shown in the system information, the one of
+	// the updater of Clair is kept as it's owned by Clair
+	namespaces := map[string]bool{}
+	for _, v := range bundle.Vulnerabilities {
+		if namespaces[v.Namespace] {
+			continue
+		}
+		namespaces[v.Namespace] = true
+		if err := dao.SetClairVulnTimestamp(v.Namespace, bundle.Metadata.CreatedAt); err != nil {
+			log.Errorf("failed to set the update time of namespace %s: %v", v.Namespace, err)
+		}
+	}
+	return count, nil
+}
+
+// insertVulnerabilities inserts the vulnerabilities one by one, the count of the ones
+// inserted before the failure is returned with the error
+func insertVulnerabilities(client *clair.Client, vulnerabilities []*models.VulnDBVulnerability) (int64, error) {
+	var count int64
+	for _, v := range vulnerabilities {
+		vulnerability := models.ClairVulnerability{
+			Name:          v.Name,
+			NamespaceName: v.Namespace,
+			Description:   v.Description,
+			Link:          v.Link,
+			Severity:      v.Severity,
+			Metadata:      v.Metadata,
+		}
+		for _, f := range v.FixedIn {
+			vulnerability.FixedIn = append(vulnerability.FixedIn, models.ClairFeature{
+				Name:          f.Name,
+				NamespaceName: v.Namespace,
+				VersionFormat: v.VersionFormat,
+				Version:       f.Version,
+			})
+		}
+		if err := client.InsertVulnerability(vulnerability); err != nil {
+			return count, fmt.Errorf("failed to import vulnerability %s of namespace %s: %v", v.Name, v.Namespace, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+var (
+	// DefaultImporter is the importer used by Import
+	DefaultImporter Importer = &clairImporter{
+		client: scanner.ClairClient,
+	}
+	// 1 if an import is running
+	running int32
+)
+
+// Import records the import of the bundle and imports it in background, the ID of the
+// import record is returned. Only one import is allowed to run at the same time
+func Import(bundle *Bundle, creator string) (int64, error) {
+	if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+		return 0, ErrImportRunning
+	}
+	id, err := dao.AddVulnDBImport(&models.VulnDBImport{
+		Version:            bundle.Metadata.Version,
+		Status:             models.VulnDBImportRunning,
+		BundleCreationTime: bundle.Metadata.CreatedAt,
+		Creator:            creator,
+	})
+	if err != nil {
+		atomic.StoreInt32(&running, 0)
+		return 0, err
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&running, 0)
+		status, message := models.VulnDBImportSucceeded, ""
+		count, err := DefaultImporter.Import(bundle)
+		if err != nil {
+			log.Errorf("failed to import vulnerability database %s: %v", bundle.Metadata.Version, err)
+			status, message = models.VulnDBImportFailed, err.Error()
+		}
+		if err = dao.UpdateVulnDBImportStatus(id, status, message, count); err != nil {
+			log.Errorf("failed to update the status of vulnerability database import %d: %v", id, err)
+		}
+	}()
+	return id, nil
+}
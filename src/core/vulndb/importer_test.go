@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulndb
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/common/utils/clair/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertVulnerabilities(t *testing.T) {
+	server := test.NewMockServer()
+	defer server.Close()
+	client := clair.NewClient(server.URL, nil)
+
+	count, err := insertVulnerabilities(client, []*models.VulnDBVulnerability{
+		{
+			Namespace:     "alpine:v3.4",
+			VersionFormat: "dpkg",
+			Name:          "CVE-2099-0001",
+			Severity:      "High",
+			FixedIn: []*models.VulnDBFeature{
+				{
+					Name:    "libssl1.0",
+					Version: "1.0.2z-r0",
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// the imported vulnerability appears in the scan result of the layer
+	result, err := client.GetResult("03adedf41d4e0ea1b2458546a5b4717bf5f24b23489b25589e20c692aaf84d19")
+	require.Nil(t, err)
+	found := false
+	for _, feature := range result.Layer.Features {
+		if feature.Name != "libssl1.0" {
+			continue
+		}
+		for _, v := range feature.Vulnerabilities {
+			if v.Name == "CVE-2099-0001" {
+				found = true
+				assert.Equal(t, "High", v.Severity)
+				assert.Equal(t, "1.0.2z-r0", v.FixedIn[0].Version)
+			}
+		}
+	}
+	assert.True(t, found)
+
+	// the vulnerability without namespace is rejected
+	_, err = insertVulnerabilities(client, []*models.VulnDBVulnerability{
+		{
+			Name:     "CVE-2099-0002",
+			Severity: "High",
+		},
+	})
+	assert.NotNil(t, err)
+}
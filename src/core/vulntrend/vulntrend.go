@@ -0,0 +1,149 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vulntrend records the daily snapshots of the vulnerabilities of the projects, which
+// show whether the projects are getting better or worse over time
+package vulntrend
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// DefaultInterval is the default interval between two checks of the snapshot of the day
+const DefaultInterval = time.Hour
+
+var (
+	// the functions reading the projects, images and scan results and writing the snapshots,
+	// replaced in testing
+	listProjects = func() ([]*models.Project, error) {
+		return dao.GetProjects(nil)
+	}
+	listDigests     = listProjectDigests
+	getScanOverview = dao.GetImgScanOverview
+	setTrend        = dao.SetVulnerabilityTrend
+)
+
+// Snapshot records the snapshots of all the projects on the day, the failure of one project
+// doesn't stop the others and the last error is returned
+func Snapshot(day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+	projects, err := listProjects()
+	if err != nil {
+		return fmt.Errorf("failed to list the projects: %v", err)
+	}
+	var lastErr error
+	for _, project := range projects {
+		trend, err := snapshot(project, day)
+		if err == nil {
+			err = setTrend(trend)
+		}
+		if err != nil {
+			log.Errorf("failed to record the vulnerability trend of project %s: %v", project.Name, err)
+			lastErr = err
+		}
+	}
+	log.Infof("the vulnerability trend of %d projects recorded, day: %s", len(projects), day.Format("2006-01-02"))
+	return lastErr
+}
+
+func snapshot(project *models.Project, day time.Time) (*models.VulnerabilityTrend, error) {
+	digests, err := listDigests(project)
+	if err != nil {
+		return nil, err
+	}
+	trend := &models.VulnerabilityTrend{
+		ProjectID: project.ProjectID,
+		Day:       day,
+		Images:    len(digests),
+	}
+	for _, digest := range digests {
+		overview, err := getScanOverview(digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the scan overview of %s: %v", digest, err)
+		}
+		if overview == nil || overview.Sev == 0 {
+			continue
+		}
+		trend.Add(overview.CompOverview)
+	}
+	return trend, nil
+}
+
+// listProjectDigests returns the distinct digests of the tags of the project
+func listProjectDigests(project *models.Project) ([]string, error) {
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{project.ProjectID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	digests := []string{}
+	for _, repository := range repositories {
+		client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for repository %s: %v", repository.Name, err)
+		}
+		tags, err := client.ListTag()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of repository %s: %v", repository.Name, err)
+		}
+		for _, tag := range tags {
+			digest, exist, err := client.ManifestExist(tag)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the digest of %s:%s: %v", repository.Name, tag, err)
+			}
+			if !exist || seen[digest] {
+				continue
+			}
+			seen[digest] = true
+			digests = append(digests, digest)
+		}
+	}
+	return digests, nil
+}
+
+// Start records the snapshots of the day in background once per day, it's checked every
+// interval as the core may be down at the beginning of the day
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last time.Time
+		for {
+			day := time.Now().UTC().Truncate(24 * time.Hour)
+			if !day.Equal(last) {
+				count, err := dao.CountVulnerabilityTrendsOfDay(day)
+				if err != nil {
+					log.Errorf("failed to check the vulnerability trend of %s: %v", day.Format("2006-01-02"), err)
+				} else {
+					if count == 0 {
+						if err = Snapshot(day); err != nil {
+							log.Errorf("failed to record the vulnerability trend of %s: %v", day.Format("2006-01-02"), err)
+						}
+					}
+					last = day
+				}
+			}
+			<-ticker.C
+		}
+	}()
+	log.Infof("the vulnerability trend recorder started, interval: %v", interval)
+}
@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulntrend
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	defer func() {
+		listDigests = listProjectDigests
+		getScanOverview = dao.GetImgScanOverview
+		setTrend = dao.SetVulnerabilityTrend
+	}()
+	listProjects = func() ([]*models.Project, error) {
+		return []*models.Project{
+			{ProjectID: 1, Name: "library"},
+			{ProjectID: 2, Name: "broken"},
+		}, nil
+	}
+	listDigests = func(project *models.Project) ([]string, error) {
+		if project.ProjectID == 2 {
+			return nil, errors.New("registry unavailable")
+		}
+		return []string{"sha256:1", "sha256:2", "sha256:3"}, nil
+	}
+	getScanOverview = func(digest string) (*models.ImgScanOverview, error) {
+		switch digest {
+		case "sha256:1":
+			return &models.ImgScanOverview{
+				Sev: int(models.SevHigh),
+				CompOverview: &models.ComponentsOverview{
+					Summary: []*models.ComponentsOverviewEntry{
+						{Sev: int(models.SevHigh), Count: 2},
+						{Sev: int(models.SevLow), Count: 1},
+					},
+				},
+			}, nil
+		case "sha256:2":
+			// the scan job is still running
+			return &models.ImgScanOverview{}, nil
+		}
+		return nil, nil
+	}
+	trends := []*models.VulnerabilityTrend{}
+	setTrend = func(trend *models.VulnerabilityTrend) error {
+		trends = append(trends, trend)
+		return nil
+	}
+
+	err := Snapshot(time.Date(2019, 3, 1, 15, 4, 5, 0, time.UTC))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "registry unavailable")
+	require.Equal(t, 1, len(trends))
+	assert.Equal(t, &models.VulnerabilityTrend{
+		ProjectID: 1,
+		Day:       time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC),
+		Images:    3,
+		Scanned:   1,
+		Low:       1,
+		High:      2,
+	}, trends[0])
+}
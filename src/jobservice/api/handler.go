@@ -47,6 +47,9 @@ type Handler interface {
 
 	// HandleJobLogReq is used to handle the request of getting job logs
 	HandleJobLogReq(w http.ResponseWriter, req *http.Request)
+
+	// HandleJobLogUsageReq is used to handle the request of getting the usage of job logs
+	HandleJobLogUsageReq(w http.ResponseWriter, req *http.Request)
 }
 
 // DefaultHandler is the default request handler which implements the Handler interface.
@@ -233,6 +236,21 @@ func (dh *DefaultHandler) HandleJobLogReq(w http.ResponseWriter, req *http.Reque
 	w.Write(logData)
 }
 
+// HandleJobLogUsageReq is implementation of method defined in interface 'Handler'
+func (dh *DefaultHandler) HandleJobLogUsageReq(w http.ResponseWriter, req *http.Request) {
+	if !dh.preCheck(w, req) {
+		return
+	}
+
+	usages, err := dh.controller.GetJobLogUsage()
+	if err != nil {
+		dh.handleError(w, req, http.StatusInternalServerError, errs.GetJobLogUsageError(err))
+		return
+	}
+
+	dh.handleJSONData(w, req, http.StatusOK, usages)
+}
+
 func (dh *DefaultHandler) handleJSONData(w http.ResponseWriter, req *http.Request, code int, object interface{}) {
 	data, err := json.Marshal(object)
 	if err != nil {
@@ -27,6 +27,7 @@ import (
 	"testing"
 	"time"
 
+	commonmodels "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/env"
 	"github.com/goharbor/harbor/src/jobservice/models"
 )
@@ -275,6 +276,30 @@ func TestGetJobLog(t *testing.T) {
 	ctx.WG.Wait()
 }
 
+func TestGetJobLogUsage(t *testing.T) {
+	exportUISecret(fakeSecret)
+
+	server, port, ctx := createServer()
+	server.Start()
+	<-time.After(200 * time.Millisecond)
+
+	resData, err := getReq(fmt.Sprintf("http://localhost:%d/api/v1/logs/usage", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usages := []*commonmodels.JobLogUsage{}
+	if err := json.Unmarshal(resData, &usages); err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 1 || usages[0].JobName != "IMAGE_SCAN" {
+		t.Fatalf("expect the usage of IMAGE_SCAN but got %s", resData)
+	}
+
+	server.Stop()
+	ctx.WG.Wait()
+}
+
 func expectFormatedError(data []byte, err error) error {
 	if err == nil {
 		return errors.New("expect error but got nil")
@@ -452,6 +477,14 @@ func (fc *fakeController) GetJobLogData(jobID string) ([]byte, error) {
 	return nil, errors.New("failed")
 }
 
+func (fc *fakeController) GetJobLogUsage() ([]*commonmodels.JobLogUsage, error) {
+	return []*commonmodels.JobLogUsage{{
+		JobName: "IMAGE_SCAN",
+		Count:   1,
+		Size:    7,
+	}}, nil
+}
+
 func createJobStats(name, kind, cron string) models.JobStats {
 	now := time.Now()
 
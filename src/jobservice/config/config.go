@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	common_redis "github.com/goharbor/harbor/src/common/utils/redis"
 	"github.com/goharbor/harbor/src/jobservice/utils"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -36,6 +37,7 @@ const (
 	jobServiceWorkers            = "JOB_SERVICE_POOL_WORKERS"
 	jobServiceRedisURL           = "JOB_SERVICE_POOL_REDIS_URL"
 	jobServiceRedisNamespace     = "JOB_SERVICE_POOL_REDIS_NAMESPACE"
+	jobServiceRedisQueue         = "JOB_SERVICE_POOL_REDIS_QUEUE"
 	jobServiceCoreServerEndpoint = "CORE_URL"
 	jobServiceAuthSecret         = "JOBSERVICE_SECRET"
 
@@ -47,11 +49,19 @@ const (
 	// JobServicePoolBackendRedis represents redis backend
 	JobServicePoolBackendRedis = "redis"
 
+	// JobServiceRedisQueueList represents the list-based queue of gocraft/work
+	JobServiceRedisQueueList = "list"
+	// JobServiceRedisQueueStream represents the queue based on the redis streams
+	JobServiceRedisQueueStream = "stream"
+
 	// secret of UI
 	uiAuthSecret = "CORE_SECRET"
 
 	// redis protocol schema
 	redisSchema = "redis://"
+	// the schemas of redis in the sentinel and cluster mode
+	redisSentinelSchema = "redis+sentinel://"
+	redisClusterSchema  = "redis+cluster://"
 )
 
 // DefaultConfig is the default configuration reference
@@ -90,6 +100,8 @@ type HTTPSConfig struct {
 type RedisPoolConfig struct {
 	RedisURL  string `yaml:"redis_url"`
 	Namespace string `yaml:"namespace"`
+	// Queue of the generic jobs: "list" or "stream", the list is used if it's empty
+	Queue string `yaml:"queue,omitempty"`
 }
 
 // PoolConfig keeps worker pool configurations.
@@ -105,8 +117,10 @@ type CustomizedSettings map[string]interface{}
 
 // LogSweeperConfig keeps settings of log sweeper
 type LogSweeperConfig struct {
-	Duration int                `yaml:"duration"`
-	Settings CustomizedSettings `yaml:"settings"`
+	Duration int `yaml:"duration"`
+	// Retention keeps the days to keep the logs of the specific jobs, it overrides the duration
+	Retention map[string]int     `yaml:"retention,omitempty"`
+	Settings  CustomizedSettings `yaml:"settings"`
 }
 
 // LoggerConfig keeps logger basic configurations.
@@ -150,7 +164,7 @@ func (c *Configuration) Load(yamlFilePath string, detectEnv bool) error {
 					c.PoolConfig.RedisPoolCfg.RedisURL = redisURL
 				}
 			} else {
-				if !strings.HasPrefix(redisAddress, redisSchema) {
+				if !isRedisURL(redisAddress) {
 					c.PoolConfig.RedisPoolCfg.RedisURL = fmt.Sprintf("%s%s", redisSchema, redisAddress)
 				}
 			}
@@ -249,6 +263,14 @@ func (c *Configuration) loadEnvs() {
 			}
 			c.PoolConfig.RedisPoolCfg.Namespace = rn
 		}
+
+		queue := utils.ReadEnv(jobServiceRedisQueue)
+		if !utils.IsEmptyStr(queue) {
+			if c.PoolConfig.RedisPoolCfg == nil {
+				c.PoolConfig.RedisPoolCfg = &RedisPoolConfig{}
+			}
+			c.PoolConfig.RedisPoolCfg.Queue = queue
+		}
 	}
 
 	// admin server
@@ -302,7 +324,7 @@ func (c *Configuration) validate() error {
 			return errors.New("URL of redis pool is empty")
 		}
 
-		if !strings.HasPrefix(c.PoolConfig.RedisPoolCfg.RedisURL, redisSchema) {
+		if !isRedisURL(c.PoolConfig.RedisPoolCfg.RedisURL) {
 			return errors.New("Invalid redis URL")
 		}
 
@@ -310,9 +332,27 @@ func (c *Configuration) validate() error {
 			return fmt.Errorf("Invalid redis URL: %s", err.Error())
 		}
 
+		redisOpts, err := common_redis.ParseURL(c.PoolConfig.RedisPoolCfg.RedisURL)
+		if err != nil {
+			return fmt.Errorf("Invalid redis URL: %s", err.Error())
+		}
+
 		if utils.IsEmptyStr(c.PoolConfig.RedisPoolCfg.Namespace) {
 			return errors.New("namespace of redis pool is required")
 		}
+
+		// all the keys must share the hash tag of namespace in the cluster mode
+		if redisOpts.Mode == common_redis.ModeCluster {
+			if _, _, err := common_redis.ClusterNamespace(c.PoolConfig.RedisPoolCfg.Namespace); err != nil {
+				return err
+			}
+		}
+
+		switch c.PoolConfig.RedisPoolCfg.Queue {
+		case "", JobServiceRedisQueueList, JobServiceRedisQueueStream:
+		default:
+			return fmt.Errorf("queue %s of redis pool does not support", c.PoolConfig.RedisPoolCfg.Queue)
+		}
 	}
 
 	// Job service loggers
@@ -324,6 +364,16 @@ func (c *Configuration) validate() error {
 	if len(c.JobLoggerConfigs) == 0 {
 		return errors.New("missing logger config of job")
 	}
+	for _, lc := range c.JobLoggerConfigs {
+		if lc.Sweeper == nil {
+			continue
+		}
+		for jobName, days := range lc.Sweeper.Retention {
+			if days <= 0 {
+				return fmt.Errorf("invalid retention of the logs of job %s: %d days", jobName, days)
+			}
+		}
+	}
 
 	if _, err := url.Parse(c.AdminServer); err != nil {
 		return fmt.Errorf("invalid admin server endpoint: %s", err)
@@ -331,3 +381,10 @@ func (c *Configuration) validate() error {
 
 	return nil // valid
 }
+
+// isRedisURL checks whether the address is a URL of redis in any mode
+func isRedisURL(address string) bool {
+	return strings.HasPrefix(address, redisSchema) ||
+		strings.HasPrefix(address, redisSentinelSchema) ||
+		strings.HasPrefix(address, redisClusterSchema)
+}
@@ -55,6 +55,9 @@ func TestConfigLoadingWithEnv(t *testing.T) {
 	if cfg.PoolConfig.RedisPoolCfg.Namespace != "ut_namespace" {
 		t.Errorf("expect redis namespace 'ut_namespace' but got '%s'\n", cfg.PoolConfig.RedisPoolCfg.Namespace)
 	}
+	if cfg.PoolConfig.RedisPoolCfg.Queue != JobServiceRedisQueueStream {
+		t.Errorf("expect redis queue 'stream' but got '%s'\n", cfg.PoolConfig.RedisPoolCfg.Queue)
+	}
 	if GetAuthSecret() != "js_secret" {
 		t.Errorf("expect auth secret 'js_secret' but got '%s'", GetAuthSecret())
 	}
@@ -65,6 +68,43 @@ func TestConfigLoadingWithEnv(t *testing.T) {
 	unsetENV()
 }
 
+func TestConfigLoadingWithSentinelRedis(t *testing.T) {
+	setENV()
+	defer unsetENV()
+	sentinelURL := "redis+sentinel://:password@8.8.8.8:26379,8.8.4.4:26379/mymaster/0"
+	os.Setenv("JOB_SERVICE_POOL_REDIS_URL", sentinelURL)
+
+	cfg := &Configuration{}
+	if err := cfg.Load("../config_test.yml", true); err != nil {
+		t.Fatalf("Load config from yaml file, expect nil error but got error '%s'\n", err)
+	}
+	if cfg.PoolConfig.RedisPoolCfg.RedisURL != sentinelURL {
+		t.Errorf("expect redis URL '%s' but got '%s'\n", sentinelURL, cfg.PoolConfig.RedisPoolCfg.RedisURL)
+	}
+
+	os.Setenv("JOB_SERVICE_POOL_REDIS_URL", "redis+sentinel://8.8.8.8:26379")
+	if err := cfg.Load("../config_test.yml", true); err == nil {
+		t.Error("expect non nil error when the master name is missing but got nil")
+	}
+}
+
+func TestConfigLoadingWithClusterRedis(t *testing.T) {
+	setENV()
+	defer unsetENV()
+	os.Setenv("JOB_SERVICE_POOL_REDIS_URL", "redis+cluster://:password@8.8.8.8:6379,8.8.4.4:6379")
+	os.Setenv("JOB_SERVICE_POOL_REDIS_NAMESPACE", "harbor_job_service_namespace")
+
+	cfg := &Configuration{}
+	if err := cfg.Load("../config_test.yml", true); err != nil {
+		t.Fatalf("Load config from yaml file, expect nil error but got error '%s'\n", err)
+	}
+
+	os.Setenv("JOB_SERVICE_POOL_REDIS_NAMESPACE", "{}harbor_job_service_namespace")
+	if err := cfg.Load("../config_test.yml", true); err == nil {
+		t.Error("expect non nil error when the hash tag of namespace is empty but got nil")
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	if err := DefaultConfig.Load("../config_test.yml", true); err != nil {
 		t.Fatalf("Load config from yaml file, expect nil error but got error '%s'\n", err)
@@ -107,6 +147,9 @@ func TestDefaultConfig(t *testing.T) {
 	if theLogger.Sweeper.Duration != 5 {
 		t.Errorf("expect sweep duration to be 5 but got %d", theLogger.Sweeper.Duration)
 	}
+	if theLogger.Sweeper.Retention["IMAGE_SCAN"] != 2 {
+		t.Errorf("expect the retention of IMAGE_SCAN to be 2 but got %d", theLogger.Sweeper.Retention["IMAGE_SCAN"])
+	}
 	if theLogger.Sweeper.Settings["work_dir"] != "/tmp/job_logs" {
 		t.Errorf("expect work dir of sweeper of FILE logger to be '/tmp/job_logs' but got %s", theLogger.Sweeper.Settings["work_dir"])
 	}
@@ -121,6 +164,7 @@ func setENV() {
 	os.Setenv("JOB_SERVICE_POOL_WORKERS", "8")
 	os.Setenv("JOB_SERVICE_POOL_REDIS_URL", "8.8.8.8:6379,100,password,0")
 	os.Setenv("JOB_SERVICE_POOL_REDIS_NAMESPACE", "ut_namespace")
+	os.Setenv("JOB_SERVICE_POOL_REDIS_QUEUE", "stream")
 	os.Setenv("JOBSERVICE_SECRET", "js_secret")
 	os.Setenv("CORE_SECRET", "core_secret")
 }
@@ -134,6 +178,7 @@ func unsetENV() {
 	os.Unsetenv("JOB_SERVICE_POOL_WORKERS")
 	os.Unsetenv("JOB_SERVICE_POOL_REDIS_URL")
 	os.Unsetenv("JOB_SERVICE_POOL_REDIS_NAMESPACE")
+	os.Unsetenv("JOB_SERVICE_POOL_REDIS_QUEUE")
 	os.Unsetenv("JOBSERVICE_SECRET")
 	os.Unsetenv("CORE_SECRET")
 }
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 
+	commonmodels "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 
 	"github.com/goharbor/harbor/src/jobservice/job"
@@ -148,6 +149,11 @@ func (c *Controller) GetJobLogData(jobID string) ([]byte, error) {
 	return logData, nil
 }
 
+// GetJobLogUsage is used to return the count and the total bytes of the logs per job
+func (c *Controller) GetJobLogUsage() ([]*commonmodels.JobLogUsage, error) {
+	return logger.Usage()
+}
+
 // CheckStatus is implementation of same method in core interface.
 func (c *Controller) CheckStatus() (models.JobPoolStats, error) {
 	return c.backendPool.Stats()
@@ -16,6 +16,7 @@
 package core
 
 import (
+	commonmodels "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/models"
 )
 
@@ -68,4 +69,7 @@ type Interface interface {
 
 	// GetJobLogData is used to return the log text data for the specified job if exists
 	GetJobLogData(jobID string) ([]byte, error)
+
+	// GetJobLogUsage is used to return the count and the total bytes of the logs per job
+	GetJobLogUsage() ([]*commonmodels.JobLogUsage, error)
 }
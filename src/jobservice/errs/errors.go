@@ -53,6 +53,8 @@ const (
 	UnAuthorizedErrorCode
 	// ResourceConflictsErrorCode is code for the error of resource conflicting
 	ResourceConflictsErrorCode
+	// GetJobLogUsageErrorCode is code for the error of getting the usage of job logs
+	GetJobLogUsageErrorCode
 )
 
 // baseError ...
@@ -135,6 +137,11 @@ func GetJobLogError(err error) error {
 	return New(GetJobLogErrorCode, "Failed to get the job log", err.Error())
 }
 
+// GetJobLogUsageError is error for the case of getting the usage of job logs failed
+func GetJobLogUsageError(err error) error {
+	return New(GetJobLogUsageErrorCode, "Failed to get the usage of the job logs", err.Error())
+}
+
 // UnauthorizedError is error for the case of unauthorized accessing
 func UnauthorizedError(err error) error {
 	return New(UnAuthorizedErrorCode, "Unauthorized", err.Error())
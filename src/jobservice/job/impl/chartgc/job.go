@@ -0,0 +1,251 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartgc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/utils"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	helm_repo "k8s.io/helm/pkg/repo"
+)
+
+const (
+	// the environment variable of the root directory of the chart storage mounted
+	// into jobservice, only the local file system storage of chartmuseum is supported
+	storageDirEnv = "CHART_STORAGE_DIR"
+	chartSuffix   = ".tgz"
+	provSuffix    = ".prov"
+	// the files modified recently are skipped as the index may be cached by chartmuseum
+	gracePeriod = time.Hour
+)
+
+// orphan is a file in the chart storage which isn't referenced by the index
+type orphan struct {
+	path string
+	size int64
+}
+
+// ChartGarbageCollector removes the chart packages and provenance files which are
+// still in the storage but not in the index of the chart repository anymore
+type ChartGarbageCollector struct {
+	logger     logger.Interface
+	storageDir string
+	coreURL    string
+	client     *common_http.Client
+	// indexed returns the names of the chart packages in the index of the namespace,
+	// nil is returned if the project of the namespace doesn't exist
+	indexed func(namespace string) (map[string]bool, error)
+}
+
+// MaxFails implements the interface in job/Interface
+func (c *ChartGarbageCollector) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (c *ChartGarbageCollector) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (c *ChartGarbageCollector) Validate(params map[string]interface{}) error {
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (c *ChartGarbageCollector) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := c.init(ctx); err != nil {
+		return err
+	}
+	dryRun := utils.SafeCastBool(params["dry_run"])
+	if dryRun {
+		c.logger.Info("start to collect the orphaned chart files in dry run mode, no file will be removed")
+	} else {
+		c.logger.Info("start to remove the orphaned chart files")
+	}
+
+	infos, err := ioutil.ReadDir(c.storageDir)
+	if err != nil {
+		c.logger.Errorf("failed to read the chart storage %s: %v", c.storageDir, err)
+		return err
+	}
+	namespaces := []string{}
+	for _, info := range infos {
+		if info.IsDir() {
+			namespaces = append(namespaces, info.Name())
+		}
+	}
+
+	var count, size int64
+	for i, namespace := range namespaces {
+		if _, stopped := ctx.OPCommand(); stopped {
+			c.logger.Info("the job is stopped")
+			return errs.JobStoppedError()
+		}
+		progress := fmt.Sprintf("%d/%d checking the charts of %s", i+1, len(namespaces), namespace)
+		if err := ctx.Checkin(progress); err != nil {
+			c.logger.Warningf("failed to check in the progress %q: %v", progress, err)
+		}
+		orphans, err := c.collect(namespace)
+		if err != nil {
+			c.logger.Errorf("failed to collect the orphaned chart files of %s: %v", namespace, err)
+			return err
+		}
+		for _, o := range orphans {
+			if !dryRun {
+				if err := os.Remove(o.path); err != nil && !os.IsNotExist(err) {
+					c.logger.Errorf("failed to remove %s: %v", o.path, err)
+					return err
+				}
+			}
+			c.logger.Infof("orphaned file %s, size: %d", o.path, o.size)
+			count++
+			size += o.size
+		}
+	}
+
+	var report string
+	if dryRun {
+		report = fmt.Sprintf("%d orphaned files (%d bytes) found, dry run", count, size)
+	} else {
+		report = fmt.Sprintf("%d orphaned files (%d bytes) removed", count, size)
+	}
+	if err := ctx.Checkin(report); err != nil {
+		c.logger.Warningf("failed to check in the report %q: %v", report, err)
+	}
+	c.logger.Info(report)
+	return nil
+}
+
+func (c *ChartGarbageCollector) init(ctx env.JobContext) error {
+	c.logger = ctx.GetLogger()
+	c.storageDir = os.Getenv(storageDirEnv)
+	if len(c.storageDir) == 0 {
+		return fmt.Errorf("the chart storage isn't mounted into jobservice, environment variable %s not set", storageDirEnv)
+	}
+	if v, ok := ctx.Get(common.CoreURL); ok && len(v.(string)) > 0 {
+		c.coreURL = v.(string)
+	} else {
+		return fmt.Errorf("Failed to get required property: %s", common.CoreURL)
+	}
+	c.client = common_http.NewClient(&http.Client{
+		Transport: reg.GetHTTPTransport(false),
+	}, auth.NewSecretAuthorizer(os.Getenv("JOBSERVICE_SECRET")))
+	if c.indexed == nil {
+		c.indexed = c.getIndexed
+	}
+	return nil
+}
+
+// collect returns the chart files of the namespace which aren't in the index
+func (c *ChartGarbageCollector) collect(namespace string) ([]*orphan, error) {
+	indexed, err := c.indexed(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if indexed == nil {
+		c.logger.Infof("the project %s doesn't exist, all the chart files of it are orphaned", namespace)
+	}
+
+	dir := filepath.Join(c.storageDir, namespace)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	orphans := []*orphan{}
+	for _, info := range infos {
+		if info.IsDir() || time.Since(info.ModTime()) < gracePeriod {
+			continue
+		}
+		name := info.Name()
+		switch {
+		case strings.HasSuffix(name, chartSuffix):
+		case strings.HasSuffix(name, chartSuffix+provSuffix):
+			// the provenance file is orphaned if the chart package isn't indexed
+			name = strings.TrimSuffix(name, provSuffix)
+		default:
+			continue
+		}
+		if indexed[name] {
+			continue
+		}
+		orphans = append(orphans, &orphan{
+			path: filepath.Join(dir, info.Name()),
+			size: info.Size(),
+		})
+	}
+	return orphans, nil
+}
+
+// getIndexed reads the index of the namespace from core
+func (c *ChartGarbageCollector) getIndexed(namespace string) (map[string]bool, error) {
+	project, err := dao.GetProjectByName(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/chartrepo/%s/index.yaml", c.coreURL, namespace), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &common_http.Error{
+			Code:    resp.StatusCode,
+			Message: string(data),
+		}
+	}
+	index := &helm_repo.IndexFile{}
+	if err = yaml.Unmarshal(data, index); err != nil {
+		return nil, err
+	}
+
+	indexed := map[string]bool{}
+	for _, versions := range index.Entries {
+		for _, version := range versions {
+			for _, url := range version.URLs {
+				indexed[path.Base(url)] = true
+			}
+		}
+	}
+	return indexed, nil
+}
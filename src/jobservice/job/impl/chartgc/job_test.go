@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartgc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFailsOfChartGarbageCollector(t *testing.T) {
+	c := &ChartGarbageCollector{}
+	assert.Equal(t, uint(1), c.MaxFails())
+}
+
+func TestValidateOfChartGarbageCollector(t *testing.T) {
+	c := &ChartGarbageCollector{}
+	require.Nil(t, c.Validate(nil))
+}
+
+func TestShouldRetryOfChartGarbageCollector(t *testing.T) {
+	c := &ChartGarbageCollector{}
+	assert.False(t, c.ShouldRetry())
+}
+
+func createFile(t *testing.T, path string, modTime time.Time) {
+	require.Nil(t, ioutil.WriteFile(path, []byte("chart"), 0600))
+	require.Nil(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestCollect(t *testing.T) {
+	root, err := ioutil.TempDir("", "chart_storage")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	old := time.Now().Add(-2 * gracePeriod)
+	require.Nil(t, os.Mkdir(filepath.Join(root, "library"), 0700))
+	for _, name := range []string{
+		"app-1.0.0.tgz",
+		"app-1.0.0.tgz.prov",
+		"app-0.9.0.tgz",
+		"app-0.9.0.tgz.prov",
+		"db-0.1.0.tgz.prov",
+		"index-cache.yaml",
+	} {
+		createFile(t, filepath.Join(root, "library", name), old)
+	}
+	// uploaded recently
+	createFile(t, filepath.Join(root, "library", "app-1.1.0.tgz"), time.Now())
+	require.Nil(t, os.Mkdir(filepath.Join(root, "deleted"), 0700))
+	createFile(t, filepath.Join(root, "deleted", "app-1.0.0.tgz"), old)
+
+	c := &ChartGarbageCollector{
+		logger:     backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4),
+		storageDir: root,
+		indexed: func(namespace string) (map[string]bool, error) {
+			if namespace == "library" {
+				return map[string]bool{
+					"app-1.0.0.tgz": true,
+				}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	orphans, err := c.collect("library")
+	require.Nil(t, err)
+	paths := []string{}
+	for _, o := range orphans {
+		paths = append(paths, o.path)
+		assert.Equal(t, int64(5), o.size)
+	}
+	sort.Strings(paths)
+	assert.Equal(t, []string{
+		filepath.Join(root, "library", "app-0.9.0.tgz"),
+		filepath.Join(root, "library", "app-0.9.0.tgz.prov"),
+		filepath.Join(root, "library", "db-0.1.0.tgz.prov"),
+	}, paths)
+
+	// all the charts of the deleted project are orphaned
+	orphans, err = c.collect("deleted")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(orphans))
+	assert.Equal(t, filepath.Join(root, "deleted", "app-1.0.0.tgz"), orphans[0].path)
+}
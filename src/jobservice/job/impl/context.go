@@ -32,6 +32,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/jobservice/logger/sweeper"
 	jmodel "github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/goharbor/harbor/src/jobservice/utils"
 )
 
 const (
@@ -134,7 +135,7 @@ func (c *Context) Build(dep env.JobData) (env.JobContext, error) {
 	// Set loggers for job
 	if err := setLoggers(func(lg logger.Interface) {
 		jContext.logger = lg
-	}, dep.ID); err != nil {
+	}, dep.ID, dep.Name); err != nil {
 		return nil, err
 	}
 
@@ -231,13 +232,18 @@ func getDBFromConfig(cfg map[string]interface{}) *models.Database {
 	postgresql.Password = cfg[common.PostGreSQLPassword].(string)
 	postgresql.Database = cfg[common.PostGreSQLDatabase].(string)
 	postgresql.SSLMode = cfg[common.PostGreSQLSSLMode].(string)
+	postgresql.SSLRootCert, _ = cfg[common.PostGreSQLSSLRootCert].(string)
+	postgresql.AuthMode, _ = cfg[common.PostGreSQLAuthMode].(string)
+	postgresql.AWSRegion, _ = cfg[common.PostGreSQLAWSRegion].(string)
+	postgresql.AzureClientID, _ = cfg[common.PostGreSQLAzureClientID].(string)
 	database.PostGreSQL = postgresql
 
 	return database
 }
 
-// create loggers based on the configurations and set it to the job executing context.
-func setLoggers(setter func(lg logger.Interface), jobID string) error {
+// create loggers based on the configurations and set it to the job executing context,
+// the name of the job is kept with the log for the retention of the logs.
+func setLoggers(setter func(lg logger.Interface), jobID, jobName string) error {
 	if setter == nil {
 		return errors.New("missing setter func")
 	}
@@ -261,11 +267,12 @@ func setLoggers(setter func(lg logger.Interface), jobID string) error {
 			}
 			if lc.Name == logger.LoggerNameFile {
 				// Append file name param
-				fSettings["filename"] = fmt.Sprintf("%s.log", jobID)
+				fSettings["filename"] = utils.JobLogFileName(jobID, jobName)
 				lOptions = append(lOptions, logger.BackendOption(lc.Name, lc.Level, fSettings))
 			} else { // DB Logger
 				// Append DB key
 				fSettings["key"] = jobID
+				fSettings["job_name"] = jobName
 				lOptions = append(lOptions, logger.BackendOption(lc.Name, lc.Level, fSettings))
 			}
 		} else {
@@ -0,0 +1,95 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coretask implements the job of the tasks running in core, e.g. the project merges.
+// The tasks depend on the components of core, so the job only calls core to run them and
+// jobservice retries it if core restarts in the middle, core resumes the tasks then
+package coretask
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/goharbor/harbor/src/common"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/job"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+// Runner calls core to run the task, core responds once the task ends and records the
+// result in the task record
+type Runner struct {
+	logger  logger.Interface
+	client  *common_http.Client
+	coreURL string
+}
+
+// MaxFails implements the interface in job/Interface
+func (r *Runner) MaxFails() uint {
+	return 3
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (r *Runner) ShouldRetry() bool {
+	return true
+}
+
+// Validate implements the interface in job/Interface
+func (r *Runner) Validate(params map[string]interface{}) error {
+	if name, ok := params[job.TaskNameParam].(string); !ok || len(name) == 0 {
+		return fmt.Errorf("missing parameter %s", job.TaskNameParam)
+	}
+	if id, ok := params[job.TaskIDParam].(float64); !ok || id <= 0 {
+		return fmt.Errorf("missing parameter %s", job.TaskIDParam)
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (r *Runner) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := r.init(ctx); err != nil {
+		return err
+	}
+	name := params[job.TaskNameParam].(string)
+	id := int64(params[job.TaskIDParam].(float64))
+	r.logger.Infof("start to run the task %s %d in core", name, id)
+	if err := r.client.Post(fmt.Sprintf("%s/service/tasks/%s/%d", r.coreURL, name, id)); err != nil {
+		r.logger.Errorf("failed to run the task %s %d: %v", name, id, err)
+		return err
+	}
+	r.logger.Infof("the task %s %d ends", name, id)
+	return nil
+}
+
+func (r *Runner) init(ctx env.JobContext) error {
+	r.logger = ctx.GetLogger()
+	if v, ok := ctx.Get(common.CoreURL); ok && len(v.(string)) > 0 {
+		r.coreURL = v.(string)
+	} else {
+		return fmt.Errorf("Failed to get required property: %s", common.CoreURL)
+	}
+	secret := os.Getenv("JOBSERVICE_SECRET")
+	if len(secret) == 0 {
+		return errors.New("failed to read environment variable JOBSERVICE_SECRET")
+	}
+	r.client = common_http.NewClient(&http.Client{
+		Transport: reg.GetHTTPTransport(false),
+	}, auth.NewSecretAuthorizer(secret))
+	return nil
+}
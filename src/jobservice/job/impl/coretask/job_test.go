@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretask
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJobContext struct {
+	env.JobContext
+	coreURL string
+}
+
+func (f *fakeJobContext) GetLogger() logger.Interface {
+	return backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4)
+}
+
+func (f *fakeJobContext) Get(prop string) (interface{}, bool) {
+	if prop == common.CoreURL {
+		return f.coreURL, true
+	}
+	return nil, false
+}
+
+func TestValidateOfRunner(t *testing.T) {
+	r := &Runner{}
+	assert.NotNil(t, r.Validate(nil))
+	assert.NotNil(t, r.Validate(map[string]interface{}{job.TaskNameParam: "PROJECT_MERGE"}))
+	assert.Nil(t, r.Validate(map[string]interface{}{job.TaskNameParam: "PROJECT_MERGE", job.TaskIDParam: float64(1)}))
+	assert.True(t, r.ShouldRetry())
+}
+
+func TestRunOfRunner(t *testing.T) {
+	os.Setenv("JOBSERVICE_SECRET", "secret")
+	defer os.Unsetenv("JOBSERVICE_SECRET")
+
+	paths := []string{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Harbor-Secret secret", r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	ctx := &fakeJobContext{coreURL: server.URL}
+	params := map[string]interface{}{job.TaskNameParam: "PROJECT_MERGE", job.TaskIDParam: float64(1)}
+	require.Nil(t, (&Runner{}).Run(ctx, params))
+	assert.Equal(t, []string{"/service/tasks/PROJECT_MERGE/1"}, paths)
+
+	// retried by jobservice if core fails to run the task
+	status = http.StatusServiceUnavailable
+	assert.NotNil(t, (&Runner{}).Run(ctx, params))
+}
@@ -72,7 +72,7 @@ func (c *DefaultContext) Build(dep env.JobData) (env.JobContext, error) {
 	// Set loggers for job
 	if err := setLoggers(func(lg logger.Interface) {
 		jContext.logger = lg
-	}, dep.ID); err != nil {
+	}, dep.ID, dep.Name); err != nil {
 		return nil, err
 	}
 
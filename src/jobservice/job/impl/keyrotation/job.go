@@ -0,0 +1,82 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyrotation
+
+import (
+	"fmt"
+	"os"
+
+	comcfg "github.com/goharbor/harbor/src/common/config/encrypt"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+// the path of the secret key of Harbor mounted into jobservice if KEY_PATH isn't set
+const defaultKeyPath = "/etc/jobservice/key"
+
+// Reencryptor re-encrypts the secrets stored in database with the primary master key after the
+// master keys are rotated. Both the master keys and the secret key of Harbor must be available
+// to jobservice, the count of re-encrypted secrets is checked in when it finishes
+type Reencryptor struct {
+	logger logger.Interface
+}
+
+// MaxFails implements the interface in job/Interface
+func (r *Reencryptor) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (r *Reencryptor) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (r *Reencryptor) Validate(params map[string]interface{}) error {
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (r *Reencryptor) Run(ctx env.JobContext, params map[string]interface{}) error {
+	r.logger = ctx.GetLogger()
+	path := os.Getenv("KEY_PATH")
+	if len(path) == 0 {
+		path = defaultKeyPath
+	}
+	legacyKey, err := comcfg.NewFileKeyProvider(path).Get(nil)
+	if err != nil {
+		r.logger.Errorf("failed to read the secret key from %s: %v", path, err)
+		return err
+	}
+
+	r.logger.Info("start to re-encrypt the secrets with the primary master key")
+	result, err := Run(legacyKey, func(progress string) {
+		r.logger.Infof("%s ...", progress)
+		if err := ctx.Checkin(progress); err != nil {
+			r.logger.Warningf("failed to check in the progress %q: %v", progress, err)
+		}
+	})
+	if err != nil {
+		r.logger.Errorf("failed to re-encrypt the secrets: %v", err)
+		return err
+	}
+	summary := fmt.Sprintf("%d passwords of replication targets, %d passwords of federation peers, %d CA bundles, %d robot tokens and %d configurations are re-encrypted",
+		result.Targets, result.Peers, result.CABundles, result.RobotTokens, result.Configurations)
+	if err = ctx.Checkin(summary); err != nil {
+		r.logger.Warningf("failed to check in the progress %q: %v", summary, err)
+	}
+	r.logger.Info(summary)
+	return nil
+}
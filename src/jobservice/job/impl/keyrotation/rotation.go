@@ -0,0 +1,203 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keyrotation re-encrypts the secrets stored in the database with the primary
+// master key of the key ring, so that the old master keys can be retired after rotation.
+package keyrotation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// Result is the count of the re-encrypted secrets
+type Result struct {
+	Targets        int
+	Peers          int
+	CABundles      int
+	RobotTokens    int
+	Configurations int
+}
+
+// Run reloads the key ring and re-encrypts the passwords of the replication targets and the
+// federation peers, the CA bundles of the targets and scanners, the tokens of the robots re-issued
+// by the targets and the encrypted configurations,
+// the secrets encrypted by the primary key are skipped.
+// The legacy key is the secret key used to decrypt the secrets before the key ring is enabled
+func Run(legacyKey string, progress func(string)) (*Result, error) {
+	ring, err := keyring.Reload()
+	if err != nil {
+		return nil, err
+	}
+	if ring == nil {
+		return nil, fmt.Errorf("the master key ring isn't configured")
+	}
+	result := &Result{}
+
+	progress("re-encrypting the passwords of replication targets")
+	targets, err := dao.FilterRepTargets("")
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		password, changed, err := reencrypt(ring, target.Password, legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt the password of target %s: %v", target.Name, err)
+		}
+		if !changed {
+			continue
+		}
+		target.Password = password
+		if err = dao.UpdateRepTarget(*target); err != nil {
+			return nil, err
+		}
+		result.Targets++
+	}
+
+	progress("re-encrypting the passwords of federation peers")
+	peers, err := dao.ListFederationPeers()
+	if err != nil {
+		return nil, err
+	}
+	for _, peer := range peers {
+		password, changed, err := reencrypt(ring, peer.Password, legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt the password of peer %s: %v", peer.Name, err)
+		}
+		if !changed {
+			continue
+		}
+		peer.Password = password
+		if err = dao.UpdateFederationPeer(peer); err != nil {
+			return nil, err
+		}
+		result.Peers++
+	}
+
+	progress("re-encrypting the CA bundles of replication targets and scanners")
+	for _, target := range targets {
+		bundle, changed, err := reencrypt(ring, target.CABundle, legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt the CA bundle of target %s: %v", target.Name, err)
+		}
+		if !changed {
+			continue
+		}
+		if err = dao.UpdateRepTargetCABundle(target.ID, bundle); err != nil {
+			return nil, err
+		}
+		result.CABundles++
+	}
+	bundles, err := dao.ListScannerCABundles()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bundles {
+		bundle, changed, err := reencrypt(ring, b.CABundle, legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt the CA bundle of scanner %s: %v", b.ScannerID, err)
+		}
+		if !changed {
+			continue
+		}
+		if err = dao.SetScannerCABundle(b.ScannerID, bundle); err != nil {
+			return nil, err
+		}
+		result.CABundles++
+	}
+
+	progress("re-encrypting the tokens of robots re-issued by replication targets")
+	replicas, err := dao.ListRobotReplicas(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, replica := range replicas {
+		token, changed, err := reencrypt(ring, replica.Token, legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt the token of robot %d re-issued by target %d: %v",
+				replica.RobotID, replica.TargetID, err)
+		}
+		if !changed {
+			continue
+		}
+		replica.Token = token
+		if err = dao.SetRobotReplica(replica); err != nil {
+			return nil, err
+		}
+		result.RobotTokens++
+	}
+
+	progress("re-encrypting the configurations")
+	entries, err := dao.GetConfigEntries()
+	if err != nil {
+		return nil, err
+	}
+	encrypted := map[string]bool{}
+	for _, key := range common.HarborEncryptedKeys {
+		encrypted[key] = true
+	}
+	updated := []models.ConfigEntry{}
+	for _, entry := range entries {
+		if !encrypted[entry.Key] {
+			continue
+		}
+		value, changed, err := reencrypt(ring, entry.Value, legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt the configuration %s: %v", entry.Key, err)
+		}
+		if !changed {
+			continue
+		}
+		updated = append(updated, models.ConfigEntry{
+			Key:   entry.Key,
+			Value: value,
+		})
+	}
+	if len(updated) > 0 {
+		if err = dao.SaveConfigEntries(updated); err != nil {
+			return nil, err
+		}
+	}
+	result.Configurations = len(updated)
+	return result, nil
+}
+
+// reencrypt decrypts the secret with the key ring or the legacy key and encrypts it with the
+// primary key, false is returned if the secret is empty or encrypted by the primary key already
+func reencrypt(ring *keyring.KeyRing, ciphertext, legacyKey string) (string, bool, error) {
+	if len(ciphertext) == 0 || keyring.KeyID(ciphertext) == ring.Primary {
+		return ciphertext, false, nil
+	}
+	var plaintext string
+	var err error
+	if strings.HasPrefix(ciphertext, keyring.EncryptHeaderV2) {
+		plaintext, err = ring.Decrypt(ciphertext)
+	} else {
+		plaintext, err = utils.ReversibleDecrypt(ciphertext, legacyKey)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	encrypted, err := ring.Encrypt(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return encrypted, true, nil
+}
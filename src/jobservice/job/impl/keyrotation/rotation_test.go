@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyrotation
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReencrypt(t *testing.T) {
+	legacyKey := "0123456789abcdef"
+	key1 := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	key2 := base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	old, err := keyring.New(map[string]string{"primary": "k1", "k1": key1})
+	require.Nil(t, err)
+	ring, err := keyring.New(map[string]string{"primary": "k2", "k1": key1, "k2": key2})
+	require.Nil(t, err)
+
+	// empty
+	_, changed, err := reencrypt(ring, "", legacyKey)
+	require.Nil(t, err)
+	assert.False(t, changed)
+
+	// encrypted by the legacy key
+	legacy, err := utils.ReversibleEncrypt("Harbor12345", legacyKey)
+	require.Nil(t, err)
+	ciphertext, changed, err := reencrypt(ring, legacy, legacyKey)
+	require.Nil(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "k2", keyring.KeyID(ciphertext))
+	plaintext, err := ring.Decrypt(ciphertext)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+
+	// encrypted by the primary key already
+	_, changed, err = reencrypt(ring, ciphertext, legacyKey)
+	require.Nil(t, err)
+	assert.False(t, changed)
+
+	// encrypted by the old master key
+	ciphertext, err = old.Encrypt("Harbor12345")
+	require.Nil(t, err)
+	ciphertext, changed, err = reencrypt(ring, ciphertext, legacyKey)
+	require.Nil(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "k2", keyring.KeyID(ciphertext))
+	plaintext, err = ring.Decrypt(ciphertext)
+	require.Nil(t, err)
+	assert.Equal(t, "Harbor12345", plaintext)
+}
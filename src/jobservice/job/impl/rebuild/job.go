@@ -0,0 +1,163 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebuild
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/utils"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+// step rebuilds one kind of the denormalized data, it returns the count of the fixed records
+type step struct {
+	name string
+	run  func(r *Rebuilder) (int64, error)
+}
+
+var steps = []*step{
+	{
+		name: "rebuilding the projects of repositories",
+		run: func(r *Rebuilder) (int64, error) {
+			return dao.RebuildRepositoryProjects()
+		},
+	},
+	{
+		name: "rebuilding the star counts of repositories",
+		run: func(r *Rebuilder) (int64, error) {
+			return dao.RebuildStarCounts()
+		},
+	},
+	{
+		name: "rebuilding the pull time of tags",
+		run: func(r *Rebuilder) (int64, error) {
+			return r.rebuildTagPullTimes()
+		},
+	},
+}
+
+// Rebuilder rebuilds the denormalized data in database from the source-of-truth tables
+// and the registry, the progress is checked in after each step
+type Rebuilder struct {
+	logger               logger.Interface
+	registryURL          string
+	secret               string
+	tokenServiceEndpoint string
+}
+
+// MaxFails implements the interface in job/Interface
+func (r *Rebuilder) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (r *Rebuilder) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (r *Rebuilder) Validate(params map[string]interface{}) error {
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (r *Rebuilder) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := r.init(ctx); err != nil {
+		return err
+	}
+	r.logger.Info("start to rebuild the denormalized data")
+	for i, s := range steps {
+		if _, stopped := ctx.OPCommand(); stopped {
+			r.logger.Info("the job is stopped")
+			return errs.JobStoppedError()
+		}
+		progress := fmt.Sprintf("%d/%d %s", i+1, len(steps), s.name)
+		if err := ctx.Checkin(progress); err != nil {
+			r.logger.Warningf("failed to check in the progress %q: %v", progress, err)
+		}
+		r.logger.Infof("%s ...", s.name)
+		n, err := s.run(r)
+		if err != nil {
+			r.logger.Errorf("failed to finish %s: %v", s.name, err)
+			return err
+		}
+		r.logger.Infof("%d records are fixed", n)
+	}
+	r.logger.Info("the denormalized data is rebuilt")
+	return nil
+}
+
+func (r *Rebuilder) init(ctx env.JobContext) error {
+	r.logger = ctx.GetLogger()
+	errTpl := "Failed to get required property: %s"
+	if v, ok := ctx.Get(common.RegistryURL); ok && len(v.(string)) > 0 {
+		r.registryURL = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.RegistryURL)
+	}
+	if v, ok := ctx.Get(common.TokenServiceURL); ok && len(v.(string)) > 0 {
+		r.tokenServiceEndpoint = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.TokenServiceURL)
+	}
+	r.secret = os.Getenv("JOBSERVICE_SECRET")
+	if len(r.secret) == 0 {
+		return fmt.Errorf("failed to read evnironment variable JOBSERVICE_SECRET")
+	}
+	return nil
+}
+
+// listTags returns the tags of the repository in the registry
+func (r *Rebuilder) listTags(repository string) ([]string, error) {
+	client, err := utils.NewRepositoryClientForJobservice(repository, r.registryURL, r.secret, r.tokenServiceEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListTag()
+}
+
+// rebuildTagPullTimes deletes the pull time of the tags which don't exist anymore
+func (r *Rebuilder) rebuildTagPullTimes() (int64, error) {
+	total, err := dao.DeleteOrphanTagPullTimes()
+	if err != nil {
+		return 0, err
+	}
+	repositories, err := dao.GetRepositories()
+	if err != nil {
+		return 0, err
+	}
+	for _, repository := range repositories {
+		tags, err := r.listTags(repository.Name)
+		if err != nil {
+			// the repository may be deleted from the registry, it's handled by syncing the registry
+			r.logger.Warningf("failed to list the tags of %s, skip: %v", repository.Name, err)
+			continue
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		n, err := dao.DeleteTagPullTimesNotIn(repository.Name, tags)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,37 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFailsOfRebuilder(t *testing.T) {
+	r := &Rebuilder{}
+	assert.Equal(t, uint(1), r.MaxFails())
+}
+
+func TestValidateOfRebuilder(t *testing.T) {
+	r := &Rebuilder{}
+	require.Nil(t, r.Validate(nil))
+}
+
+func TestShouldRetryOfRebuilder(t *testing.T) {
+	r := &Rebuilder{}
+	assert.False(t, r.ShouldRetry())
+}
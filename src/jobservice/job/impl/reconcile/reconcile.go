@@ -0,0 +1,255 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+// the sides repaired by the reconciliation
+const (
+	// TargetDatabase makes the records in the database consistent with the registry
+	TargetDatabase = "database"
+	// TargetRegistry makes the repositories in the registry consistent with the database
+	TargetRegistry = "registry"
+)
+
+// the types of the drifts
+const (
+	DriftMissingInDatabase = "missing_in_database"
+	DriftMissingInRegistry = "missing_in_registry"
+)
+
+// Source lists the repositories and tags recorded by one side of the reconciliation
+type Source interface {
+	// Repositories returns the names of the repositories
+	Repositories() ([]string, error)
+	// Tags returns the tags of the repository and the digests they reference
+	Tags(repository string) (map[string]string, error)
+}
+
+// Drift is a repository recorded by only one side, the tags are the ones in the registry
+type Drift struct {
+	Repository string            `json:"repository"`
+	Type       string            `json:"type"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Repaired   bool              `json:"repaired"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Report is the result of a reconciliation
+type Report struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// Target is the side repaired, it's empty when the drifts are only reported
+	Target string `json:"target,omitempty"`
+	// DryRun is true when the drifts of the target are only reported rather than repaired
+	DryRun bool     `json:"dry_run"`
+	Drifts []*Drift `json:"drifts"`
+}
+
+// ValidTarget returns whether the target is a side which can be repaired
+func ValidTarget(target string) bool {
+	_, ok := repairers[target]
+	return ok
+}
+
+// Check compares the repositories of the registry with the ones of the database and returns
+// the drifts between them. The repositories without any tag in the registry are treated as
+// missing as they can't be pulled
+func Check(registry, database Source) ([]*Drift, error) {
+	inRegistry, err := repositorySet(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the repositories in the registry: %v", err)
+	}
+	inDatabase, err := repositorySet(database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the repositories in the database: %v", err)
+	}
+	names := []string{}
+	for name := range inRegistry {
+		names = append(names, name)
+	}
+	for name := range inDatabase {
+		if !inRegistry[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	drifts := []*Drift{}
+	for _, name := range names {
+		tags := map[string]string{}
+		if inRegistry[name] {
+			if tags, err = registry.Tags(name); err != nil {
+				return nil, fmt.Errorf("failed to list the tags of %s in the registry: %v", name, err)
+			}
+		}
+		if len(tags) > 0 && !inDatabase[name] {
+			drifts = append(drifts, &Drift{
+				Repository: name,
+				Type:       DriftMissingInDatabase,
+				Tags:       tags,
+			})
+		} else if len(tags) == 0 && inDatabase[name] {
+			drifts = append(drifts, &Drift{
+				Repository: name,
+				Type:       DriftMissingInRegistry,
+			})
+		}
+	}
+	return drifts, nil
+}
+
+func repositorySet(source Source) (map[string]bool, error) {
+	repositories, err := source.Repositories()
+	if err != nil {
+		return nil, err
+	}
+	set := map[string]bool{}
+	for _, repository := range repositories {
+		set[repository] = true
+	}
+	return set, nil
+}
+
+// Reconciler checks the drifts between the registry and the database, and repairs the target
+// side if it's specified and the job isn't a dry run. The report is printed in the log
+type Reconciler struct {
+	logger               logger.Interface
+	registryURL          string
+	secret               string
+	tokenServiceEndpoint string
+	// the sources compared, replaced in testing
+	sources func() (Source, Source, error)
+}
+
+// MaxFails implements the interface in job/Interface
+func (r *Reconciler) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (r *Reconciler) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (r *Reconciler) Validate(params map[string]interface{}) error {
+	target := utils.SafeCastString(params["target"])
+	if len(target) > 0 && !ValidTarget(target) {
+		return fmt.Errorf("invalid target %s, must be %s or %s", target, TargetDatabase, TargetRegistry)
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (r *Reconciler) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := r.init(ctx); err != nil {
+		return err
+	}
+	report := &Report{
+		StartTime: time.Now(),
+		Target:    utils.SafeCastString(params["target"]),
+		DryRun:    dryRun(params),
+	}
+	registry, database, err := r.sources()
+	if err != nil {
+		return err
+	}
+	r.logger.Info("start to check the drifts between the registry and the database")
+	if report.Drifts, err = Check(registry, database); err != nil {
+		r.logger.Errorf("failed to check the drifts: %v", err)
+		return err
+	}
+	r.logger.Infof("%d drifts found", len(report.Drifts))
+
+	failed := 0
+	if len(report.Target) > 0 && !report.DryRun {
+		fn := repairers[report.Target]
+		for i, drift := range report.Drifts {
+			if _, stopped := ctx.OPCommand(); stopped {
+				r.logger.Info("the job is stopped")
+				return errs.JobStoppedError()
+			}
+			progress := fmt.Sprintf("%d/%d repairing %s in the %s", i+1, len(report.Drifts), drift.Repository, report.Target)
+			if err := ctx.Checkin(progress); err != nil {
+				r.logger.Warningf("failed to check in the progress %q: %v", progress, err)
+			}
+			if err := fn(r, drift); err != nil {
+				drift.Error = err.Error()
+				r.logger.Errorf("failed to repair the drift of %s in the %s: %v", drift.Repository, report.Target, err)
+				failed++
+				continue
+			}
+			drift.Repaired = true
+		}
+	}
+	report.EndTime = time.Now()
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	r.logger.Infof("the report of the reconciliation: %s", string(data))
+	if failed > 0 {
+		return fmt.Errorf("failed to repair %d drifts", failed)
+	}
+	return nil
+}
+
+// dryRun returns whether the drifts of the target are only reported, the repair is a dry run
+// unless it's disabled explicitly
+func dryRun(params map[string]interface{}) bool {
+	v, ok := params["dry_run"]
+	if !ok {
+		return true
+	}
+	return utils.SafeCastBool(v)
+}
+
+func (r *Reconciler) init(ctx env.JobContext) error {
+	r.logger = ctx.GetLogger()
+	if r.sources != nil {
+		return nil
+	}
+	errTpl := "Failed to get required property: %s"
+	if v, ok := ctx.Get(common.RegistryURL); ok && len(v.(string)) > 0 {
+		r.registryURL = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.RegistryURL)
+	}
+	if v, ok := ctx.Get(common.TokenServiceURL); ok && len(v.(string)) > 0 {
+		r.tokenServiceEndpoint = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.TokenServiceURL)
+	}
+	r.secret = os.Getenv("JOBSERVICE_SECRET")
+	if len(r.secret) == 0 {
+		return fmt.Errorf("failed to read evnironment variable JOBSERVICE_SECRET")
+	}
+	r.sources = r.newSources
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource map[string]map[string]string
+
+func (f fakeSource) Repositories() ([]string, error) {
+	repositories := []string{}
+	for repository := range f {
+		repositories = append(repositories, repository)
+	}
+	return repositories, nil
+}
+
+func (f fakeSource) Tags(repository string) (map[string]string, error) {
+	return f[repository], nil
+}
+
+type fakeJobContext struct {
+	env.JobContext
+}
+
+func (f *fakeJobContext) GetLogger() logger.Interface {
+	return backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4)
+}
+
+func (f *fakeJobContext) OPCommand() (string, bool) {
+	return "", false
+}
+
+func (f *fakeJobContext) Checkin(status string) error {
+	return nil
+}
+
+var (
+	fakeRegistry = fakeSource{
+		"library/hello-world": {"latest": "sha256:1", "v1": "sha256:2"},
+		"library/registry":    {"latest": "sha256:4"},
+		"library/empty":       {},
+	}
+	fakeDatabase = fakeSource{
+		"library/hello-world": {"latest": "sha256:1", "v1": "sha256:2"},
+		"library/empty":       {},
+		"library/redis":       {},
+	}
+)
+
+func TestCheck(t *testing.T) {
+	drifts, err := Check(fakeRegistry, fakeDatabase)
+	require.Nil(t, err)
+	assert.Equal(t, []*Drift{
+		{Repository: "library/empty", Type: DriftMissingInRegistry},
+		{Repository: "library/redis", Type: DriftMissingInRegistry},
+		{Repository: "library/registry", Type: DriftMissingInDatabase,
+			Tags: map[string]string{"latest": "sha256:4"}},
+	}, drifts)
+
+	drifts, err = Check(fakeRegistry, fakeRegistry)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(drifts))
+}
+
+func TestValidateOfReconciler(t *testing.T) {
+	r := &Reconciler{}
+	assert.Nil(t, r.Validate(nil))
+	assert.Nil(t, r.Validate(map[string]interface{}{"target": TargetRegistry}))
+	assert.NotNil(t, r.Validate(map[string]interface{}{"target": "invalid"}))
+}
+
+func TestRunOfReconciler(t *testing.T) {
+	repairer := repairers[TargetRegistry]
+	defer func() {
+		repairers[TargetRegistry] = repairer
+	}()
+	repaired := []string{}
+	repairers[TargetRegistry] = func(r *Reconciler, drift *Drift) error {
+		if drift.Repository == "library/redis" {
+			return errors.New("manifest unknown")
+		}
+		repaired = append(repaired, drift.Repository)
+		return nil
+	}
+	r := &Reconciler{
+		sources: func() (Source, Source, error) {
+			return fakeRegistry, fakeDatabase, nil
+		},
+	}
+	ctx := &fakeJobContext{}
+
+	// check only
+	require.Nil(t, r.Run(ctx, map[string]interface{}{}))
+	assert.Empty(t, repaired)
+
+	// dry run by default
+	require.Nil(t, r.Run(ctx, map[string]interface{}{"target": TargetRegistry}))
+	assert.Empty(t, repaired)
+
+	err := r.Run(ctx, map[string]interface{}{"target": TargetRegistry, "dry_run": false})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"library/empty", "library/registry"}, repaired)
+}
@@ -0,0 +1,87 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/legalhold"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// the functions repairing the drifts of the repositories on each side, replaced in testing
+var repairers = map[string]func(r *Reconciler, drift *Drift) error{
+	TargetDatabase: repairDatabase,
+	TargetRegistry: repairRegistry,
+}
+
+// repairDatabase makes the records of the repositories in the database consistent with
+// the registry
+func repairDatabase(r *Reconciler, drift *Drift) error {
+	if drift.Type == DriftMissingInRegistry {
+		return dao.DeleteRepository(drift.Repository)
+	}
+	project, _ := utils.ParseRepository(drift.Repository)
+	pro, err := dao.GetProjectByName(project)
+	if err != nil {
+		return err
+	}
+	if pro == nil {
+		return fmt.Errorf("project %s not found", project)
+	}
+	pullCount, err := dao.CountPull(drift.Repository)
+	if err != nil {
+		return err
+	}
+	return dao.AddRepository(models.RepoRecord{
+		Name:      drift.Repository,
+		ProjectID: pro.ProjectID,
+		PullCount: pullCount,
+	})
+}
+
+// repairRegistry deletes the manifests of the repositories missing in the database from the
+// registry, the repository is kept untouched if any of its manifests is under legal hold. The
+// repositories missing in the registry can't be restored as their manifests are gone
+func repairRegistry(r *Reconciler, drift *Drift) error {
+	if drift.Type == DriftMissingInRegistry {
+		return fmt.Errorf("the repository can't be restored in the registry, repair the database instead")
+	}
+	client, err := r.repositoryClient(drift.Repository)
+	if err != nil {
+		return err
+	}
+	hold, err := legalhold.Blocking(client, drift.Repository, drift.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to check the legal holds: %v", err)
+	}
+	if hold != nil {
+		return fmt.Errorf("%s is under legal hold", hold.Target())
+	}
+	// deleting a manifest removes all the tags referencing it
+	deleted := map[string]bool{}
+	for _, digest := range drift.Tags {
+		if deleted[digest] {
+			continue
+		}
+		if err = client.DeleteManifest(digest); err != nil {
+			return fmt.Errorf("failed to delete the manifest %s: %v", digest, err)
+		}
+		deleted[digest] = true
+	}
+	return nil
+}
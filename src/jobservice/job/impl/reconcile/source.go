@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/utils"
+)
+
+func (r *Reconciler) newSources() (Source, Source, error) {
+	client, err := utils.NewRegistryClientForJobservice(r.registryURL, r.secret, r.tokenServiceEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	reg := &registrySource{
+		client:     client,
+		reconciler: r,
+	}
+	return reg, &databaseSource{registry: reg}, nil
+}
+
+func (r *Reconciler) repositoryClient(repository string) (*registry.Repository, error) {
+	return utils.NewRepositoryClientForJobservice(repository, r.registryURL, r.secret, r.tokenServiceEndpoint)
+}
+
+// registrySource reads the repositories and tags from the storage of the registry
+type registrySource struct {
+	client     *registry.Registry
+	reconciler *Reconciler
+}
+
+func (r *registrySource) Repositories() ([]string, error) {
+	return r.client.Catalog()
+}
+
+func (r *registrySource) Tags(repository string) (map[string]string, error) {
+	client, err := r.reconciler.repositoryClient(repository)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := client.ListTag()
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	for _, tag := range tags {
+		digest, exist, err := client.ManifestExist(tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the digest of %s:%s: %v", repository, tag, err)
+		}
+		// the tag is deleted meanwhile
+		if !exist {
+			continue
+		}
+		digests[tag] = digest
+	}
+	return digests, nil
+}
+
+// databaseSource reads the repositories from the repository table, the tags aren't recorded
+// in the database so they're listed from the registry. The repositories created before the
+// first push are skipped until they're pushed
+type databaseSource struct {
+	registry Source
+}
+
+func (d *databaseSource) Repositories() ([]string, error) {
+	repositories, err := dao.GetRepositories()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, repository := range repositories {
+		if repository.PreCreated {
+			tags, err := d.registry.Tags(repository.Name)
+			if err != nil {
+				return nil, err
+			}
+			if len(tags) == 0 {
+				continue
+			}
+		}
+		names = append(names, repository.Name)
+	}
+	return names, nil
+}
+
+func (d *databaseSource) Tags(repository string) (map[string]string, error) {
+	return d.registry.Tags(repository)
+}
@@ -0,0 +1,26 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"strings"
+)
+
+// deleteArtifactoryPath deletes the repository or tag through the Artifactory API, the path
+// begins with the repository key, as Artifactory stores the docker images as folders and
+// the old versions don't support deleting the manifests through the registry API
+func (r *registry) deleteArtifactoryPath(path string) error {
+	return r.client.Delete(strings.TrimRight(r.url, "/") + "/artifactory/" + path)
+}
@@ -18,7 +18,7 @@ import (
 	"net/http"
 
 	common_http "github.com/goharbor/harbor/src/common/http"
-	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/env"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 )
@@ -51,6 +51,7 @@ func (d *Deleter) Validate(params map[string]interface{}) error {
 func (d *Deleter) Run(ctx env.JobContext, params map[string]interface{}) error {
 	err := d.run(ctx, params)
 	d.retry = retry(err)
+	checkInReport(ctx, &models.RepJobReport{}, err)
 	return err
 }
 
@@ -81,14 +82,8 @@ func (d *Deleter) init(ctx env.JobContext, params map[string]interface{}) error
 		}
 	}
 
-	url := params["dst_registry_url"].(string)
-	insecure := params["dst_registry_insecure"].(bool)
-	cred := auth.NewBasicAuthCredential(
-		params["dst_registry_username"].(string),
-		params["dst_registry_password"].(string))
-
 	var err error
-	d.dstRegistry, err = initRegistry(url, insecure, cred, d.repository.name)
+	d.dstRegistry, err = initDstRegistry(params, d.repository.name)
 	if err != nil {
 		d.logger.Errorf("failed to create client for destination registry: %v", err)
 		return err
@@ -0,0 +1,158 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+)
+
+// the page size of the listings of the GitHub and GitLab APIs
+const apiPageSize = 100
+
+// githubToken adds the personal access token to the requests sent to the GitHub API
+type githubToken string
+
+// Modify implements github.com/goharbor/harbor/src/common/http/modifier.Modifier
+func (g githubToken) Modify(req *http.Request) error {
+	req.Header.Set(http.CanonicalHeaderKey("Authorization"), "Bearer "+string(g))
+	return nil
+}
+
+// gitlabToken adds the personal access token to the requests sent to the GitLab API
+type gitlabToken string
+
+// Modify implements github.com/goharbor/harbor/src/common/http/modifier.Modifier
+func (g gitlabToken) Modify(req *http.Request) error {
+	req.Header.Set("PRIVATE-TOKEN", string(g))
+	return nil
+}
+
+// githubPackageURL returns the URL of the container package of the repository, the first
+// component of the repository is the owner which is either the user or an organization
+func (r *registry) githubPackageURL() string {
+	owner, name := r.Name, ""
+	if i := strings.Index(r.Name, "/"); i >= 0 {
+		owner, name = r.Name[:i], r.Name[i+1:]
+	}
+	scope := "/orgs/" + owner
+	if owner == strings.ToLower(r.username) {
+		scope = "/user"
+	}
+	return r.apiURL + scope + "/packages/container/" + url.PathEscape(name)
+}
+
+// deleteGitHubPackage deletes the container package with all the versions of it
+func (r *registry) deleteGitHubPackage() error {
+	return r.client.Delete(r.githubPackageURL())
+}
+
+// deleteGitHubTag deletes the version of the package tagged with the tag, GHCR doesn't
+// support deleting the manifests through the registry API
+func (r *registry) deleteGitHubTag(tag string) error {
+	for page := 1; ; page++ {
+		versions := []struct {
+			ID       int64 `json:"id"`
+			Metadata struct {
+				Container struct {
+					Tags []string `json:"tags"`
+				} `json:"container"`
+			} `json:"metadata"`
+		}{}
+		if err := r.client.Get(fmt.Sprintf("%s/versions?per_page=%d&page=%d",
+			r.githubPackageURL(), apiPageSize, page), &versions); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			for _, t := range version.Metadata.Container.Tags {
+				if t == tag {
+					return r.client.Delete(fmt.Sprintf("%s/versions/%d", r.githubPackageURL(), version.ID))
+				}
+			}
+		}
+		if len(versions) < apiPageSize {
+			return &common_http.Error{
+				Code: http.StatusNotFound,
+			}
+		}
+	}
+}
+
+// gitlabRepository returns the IDs of the project and the container repository, the
+// repository belongs to the project whose path is the longest prefix of the name
+func (r *registry) gitlabRepository() (int64, int64, error) {
+	components := strings.Split(r.Name, "/")
+	for i := len(components); i > 0; i-- {
+		project := url.PathEscape(strings.Join(components[:i], "/"))
+		for page := 1; ; page++ {
+			repositories := []struct {
+				ID        int64  `json:"id"`
+				Path      string `json:"path"`
+				ProjectID int64  `json:"project_id"`
+			}{}
+			err := r.client.Get(fmt.Sprintf("%s/projects/%s/registry/repositories?per_page=%d&page=%d",
+				r.apiURL, project, apiPageSize, page), &repositories)
+			if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+				break
+			}
+			if err != nil {
+				return 0, 0, err
+			}
+			for _, repository := range repositories {
+				if repository.Path == r.Name {
+					return repository.ProjectID, repository.ID, nil
+				}
+			}
+			if len(repositories) < apiPageSize {
+				break
+			}
+		}
+	}
+	return 0, 0, &common_http.Error{
+		Code: http.StatusNotFound,
+	}
+}
+
+// gitlabRepositoryURL returns the URL of the container repository in the GitLab API
+func (r *registry) gitlabRepositoryURL() (string, error) {
+	projectID, repositoryID, err := r.gitlabRepository()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/projects/%d/registry/repositories/%d", r.apiURL, projectID, repositoryID), nil
+}
+
+// deleteGitLabRepository deletes the container repository through the GitLab API
+func (r *registry) deleteGitLabRepository() error {
+	u, err := r.gitlabRepositoryURL()
+	if err != nil {
+		return err
+	}
+	return r.client.Delete(u)
+}
+
+// deleteGitLabTag deletes the tag through the GitLab API rather than the manifest,
+// which removes the other tags of the same manifest too
+func (r *registry) deleteGitLabTag(tag string) error {
+	u, err := r.gitlabRepositoryURL()
+	if err != nil {
+		return err
+	}
+	return r.client.Delete(u + "/tags/" + url.PathEscape(tag))
+}
@@ -0,0 +1,120 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubPackage(t *testing.T) {
+	var requests []string
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v3/",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+r.Header.Get("Authorization"))
+				w.Write([]byte(`[{"id":1,"metadata":{"container":{"tags":["v1"]}}},
+					{"id":2,"metadata":{"container":{"tags":["latest","v2"]}}}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodDelete,
+			Pattern: "/api/v3/",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+r.Header.Get("Authorization"))
+				w.WriteHeader(http.StatusNoContent)
+			},
+		})
+	defer server.Close()
+
+	params := map[string]interface{}{
+		"dst_registry_url":      server.URL,
+		"dst_registry_insecure": false,
+		"dst_registry_username": "User",
+		"dst_registry_password": "token",
+		"dst_registry_type":     float64(models.RepTargetTypeGitHub),
+		"dst_path_mappings":     `[{"project":"library","path":"My-Org/team"},{"project":"private","path":"user"}]`,
+	}
+	r, err := initDstRegistry(params, "library/nginx")
+	require.Nil(t, err)
+	require.Nil(t, r.DeleteImage("library/nginx", "latest"))
+	require.Nil(t, r.DeleteRepository("library/nginx"))
+	assert.NotNil(t, r.DeleteImage("library/nginx", "unknown"))
+
+	r, err = initDstRegistry(params, "private/nginx")
+	require.Nil(t, err)
+	require.Nil(t, r.DeleteRepository("private/nginx"))
+	assert.Equal(t, []string{
+		"GET /api/v3/orgs/my-org/packages/container/team%2Fnginx/versions Bearer token",
+		"DELETE /api/v3/orgs/my-org/packages/container/team%2Fnginx/versions/2 Bearer token",
+		"DELETE /api/v3/orgs/my-org/packages/container/team%2Fnginx Bearer token",
+		"GET /api/v3/orgs/my-org/packages/container/team%2Fnginx/versions Bearer token",
+		"DELETE /api/v3/user/packages/container/nginx Bearer token",
+	}, requests)
+}
+
+func TestGitLabRepository(t *testing.T) {
+	var requests []string
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v4/",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+r.Header.Get("PRIVATE-TOKEN"))
+				if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/registry/repositories" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`[{"id":1,"path":"group/project","project_id":3},
+					{"id":2,"path":"group/project/nginx","project_id":3}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodDelete,
+			Pattern: "/api/v4/",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+r.Header.Get("PRIVATE-TOKEN"))
+				w.WriteHeader(http.StatusAccepted)
+			},
+		})
+	defer server.Close()
+
+	r, err := initDstRegistry(map[string]interface{}{
+		"dst_registry_url":      server.URL,
+		"dst_registry_insecure": false,
+		"dst_registry_username": "user",
+		"dst_registry_password": "token",
+		"dst_registry_type":     float64(models.RepTargetTypeGitLab),
+		"dst_path_mappings":     `[{"project":"library","path":"group/project"}]`,
+	}, "library/nginx")
+	require.Nil(t, err)
+	require.Nil(t, r.DeleteImage("library/nginx", "latest"))
+	assert.Equal(t, []string{
+		"GET /api/v4/projects/group%2Fproject%2Fnginx/registry/repositories token",
+		"GET /api/v4/projects/group%2Fproject/registry/repositories token",
+		"DELETE /api/v4/projects/3/registry/repositories/2/tags/latest token",
+	}, requests)
+
+	requests = nil
+	require.Nil(t, r.DeleteRepository("library/nginx"))
+	assert.Equal(t, "DELETE /api/v4/projects/3/registry/repositories/2 token", requests[len(requests)-1])
+}
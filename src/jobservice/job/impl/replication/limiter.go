@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/env"
+)
+
+// the interval of checking whether the job is canceled while waiting for the transfer slot
+var acquireInterval = time.Second
+
+// limiter holds the transfer slots of every endpoint in the jobservice, the limit
+// is applied per jobservice instance as the slots aren't shared among instances
+type limiter struct {
+	lock  sync.Mutex
+	slots map[string]chan struct{}
+}
+
+var transferLimiter = &limiter{
+	slots: map[string]chan struct{}{},
+}
+
+// get returns the slots of the endpoint, they're recreated when the max changes
+func (l *limiter) get(endpoint string, max int) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	slots, exist := l.slots[endpoint]
+	if !exist || cap(slots) != max {
+		slots = make(chan struct{}, max)
+		l.slots[endpoint] = slots
+	}
+	return slots
+}
+
+// acquireTransfer waits until a transfer slot of the endpoint is available or the job is
+// canceled, the returned function must be called to release the slot. No slot is needed
+// when max is 0
+func acquireTransfer(ctx env.JobContext, endpoint string, max int) (func(), error) {
+	if max <= 0 {
+		return func() {}, nil
+	}
+	slots := transferLimiter.get(endpoint, max)
+	ticker := time.NewTicker(acquireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case slots <- struct{}{}:
+			return func() { <-slots }, nil
+		case <-ticker.C:
+			if canceled(ctx) {
+				return nil, errCanceled
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJobContext struct {
+	env.JobContext
+	canceled bool
+}
+
+func (f *fakeJobContext) OPCommand() (string, bool) {
+	return "", f.canceled
+}
+
+func TestAcquireTransfer(t *testing.T) {
+	interval := acquireInterval
+	acquireInterval = 10 * time.Millisecond
+	defer func() {
+		acquireInterval = interval
+	}()
+	ctx := &fakeJobContext{}
+
+	// unlimited
+	release, err := acquireTransfer(ctx, "http://unlimited.com", 0)
+	require.Nil(t, err)
+	release()
+
+	release, err = acquireTransfer(ctx, "http://limited.com", 1)
+	require.Nil(t, err)
+
+	// the other endpoints aren't affected
+	other, err := acquireTransfer(ctx, "http://other.com", 1)
+	require.Nil(t, err)
+	other()
+
+	// wait until the slot is released
+	acquired := make(chan struct{})
+	go func() {
+		r, err := acquireTransfer(ctx, "http://limited.com", 1)
+		if err == nil {
+			r()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("the slot should not be acquired before it's released")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("the slot should be acquired after it's released")
+	}
+
+	// canceled while waiting
+	release, err = acquireTransfer(ctx, "http://limited.com", 1)
+	require.Nil(t, err)
+	defer release()
+	_, err = acquireTransfer(&fakeJobContext{canceled: true}, "http://limited.com", 1)
+	assert.Equal(t, errCanceled, err)
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"net/http"
+	"strings"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// quayOAuthToken adds the OAuth access token to the requests sent to the Quay API
+type quayOAuthToken string
+
+// Modify implements github.com/goharbor/harbor/src/common/http/modifier.Modifier
+func (q quayOAuthToken) Modify(req *http.Request) error {
+	req.Header.Set(http.CanonicalHeaderKey("Authorization"), "Bearer "+string(q))
+	return nil
+}
+
+func (r *registry) quayRepositoryURL() string {
+	namespace, name := utils.ParseRepository(r.Name)
+	return strings.TrimRight(r.url, "/") + "/api/v1/repository/" + namespace + "/" + name
+}
+
+// createQuayRepository creates the repository through the Quay API if it doesn't exist,
+// as Quay creates the repositories on push only for the users with the creator permission
+func (r *registry) createQuayRepository() error {
+	err := r.client.Get(r.quayRepositoryURL())
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*common_http.Error); !ok || e.Code != http.StatusNotFound {
+		return err
+	}
+
+	namespace, name := utils.ParseRepository(r.Name)
+	repo := struct {
+		Namespace   string `json:"namespace"`
+		Repository  string `json:"repository"`
+		Visibility  string `json:"visibility"`
+		Description string `json:"description"`
+	}{
+		Namespace:  namespace,
+		Repository: name,
+		Visibility: "private",
+	}
+	return r.client.Post(strings.TrimRight(r.url, "/")+"/api/v1/repository", repo)
+}
+
+// deleteQuayRepository deletes the repository through the Quay API
+func (r *registry) deleteQuayRepository() error {
+	return r.client.Delete(r.quayRepositoryURL())
+}
+
+// deleteQuayTag deletes the tag through the Quay API, as Quay doesn't support
+// deleting the manifests through the registry API
+func (r *registry) deleteQuayTag(tag string) error {
+	return r.client.Delete(r.quayRepositoryURL() + "/tag/" + tag)
+}
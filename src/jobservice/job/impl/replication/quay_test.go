@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuayRepository(t *testing.T) {
+	var requests []string
+	record := func(code int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization"))
+			w.WriteHeader(code)
+		}
+	}
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v1/repository/org/nginx",
+			Handler: record(http.StatusNotFound),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodPost,
+			Pattern: "/api/v1/repository",
+			Handler: record(http.StatusCreated),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodDelete,
+			Pattern: "/api/v1/repository/org/nginx",
+			Handler: record(http.StatusNoContent),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodDelete,
+			Pattern: "/artifactory/docker-local/nginx",
+			Handler: record(http.StatusNoContent),
+		})
+	defer server.Close()
+
+	r, err := initDstRegistry(map[string]interface{}{
+		"dst_registry_url":      server.URL,
+		"dst_registry_insecure": false,
+		"dst_registry_username": models.QuayOAuthTokenUsername,
+		"dst_registry_password": "token",
+		"dst_registry_type":     float64(models.RepTargetTypeQuay),
+		"dst_path_mappings":     `[{"project":"library","path":"org"}]`,
+	}, "library/nginx")
+	require.Nil(t, err)
+	require.Nil(t, r.createQuayRepository())
+	require.Nil(t, r.DeleteImage("library/nginx", "latest"))
+	require.Nil(t, r.DeleteRepository("library/nginx"))
+	assert.Equal(t, []string{
+		"GET /api/v1/repository/org/nginx Bearer token",
+		"POST /api/v1/repository Bearer token",
+		"DELETE /api/v1/repository/org/nginx/tag/latest Bearer token",
+		"DELETE /api/v1/repository/org/nginx Bearer token",
+	}, requests)
+
+	requests = nil
+	r, err = initDstRegistry(map[string]interface{}{
+		"dst_registry_url":      server.URL,
+		"dst_registry_insecure": false,
+		"dst_registry_username": "admin",
+		"dst_registry_password": "password",
+		"dst_registry_type":     float64(models.RepTargetTypeArtifactory),
+		"dst_path_mappings":     `[{"project":"library","path":"docker-local"}]`,
+	}, "library/nginx")
+	require.Nil(t, err)
+	require.Nil(t, r.DeleteImage("library/nginx", "latest"))
+	require.Nil(t, r.DeleteRepository("library/nginx"))
+	require.Equal(t, 2, len(requests))
+	assert.Equal(t, "DELETE /artifactory/docker-local/nginx/latest Basic YWRtaW46cGFzc3dvcmQ=", requests[0])
+	assert.Equal(t, "DELETE /artifactory/docker-local/nginx Basic YWRtaW46cGFzc3dvcmQ=", requests[1])
+}
@@ -16,9 +16,11 @@ package replication
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 
 	common_http "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/models"
@@ -36,6 +38,55 @@ type registry struct {
 	client         *common_http.Client // Harbor client
 	url            string
 	insecure       bool
+	// targetType is one of the types of models.RepTarget, the Harbor API
+	// is only available when it's models.RepTargetTypeHarbor
+	targetType int
+	// quayOAuth is true if the client is authorized with the OAuth token of Quay
+	quayOAuth bool
+	// apiURL is the base URL of the API of GitHub or GitLab
+	apiURL string
+	// username is the user of the target, GitHub scopes the packages of the user
+	// differently from the ones of the organizations
+	username string
+	// maxConcurrentTransfers limits the transfers to the registry, 0 means unlimited
+	maxConcurrentTransfers int
+	// rateLimitReserve is the remaining budget of the rate limit under which the
+	// transfers are deferred, 0 means never
+	rateLimitReserve int
+	// rateLimits records the rate limits read from the responses of the registry
+	rateLimits *rateLimitRecorder
+}
+
+// lastRateLimit returns the last rate limit of the registry, nil if it doesn't limit the rate
+func (r *registry) lastRateLimit() *models.RateLimit {
+	if r.rateLimits == nil {
+		return nil
+	}
+	return r.rateLimits.get()
+}
+
+// rateLimitRecorder keeps the last rate limit read from the responses
+type rateLimitRecorder struct {
+	rateLimit *models.RateLimit
+	lock      sync.Mutex
+}
+
+func (r *rateLimitRecorder) record(rateLimit *models.RateLimit) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rateLimit = rateLimit
+}
+
+func (r *rateLimitRecorder) get() *models.RateLimit {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.rateLimit
+}
+
+// targetBreaker returns the breaker of the target shared by the jobs in the process, it's
+// closed when the base API of the target responds again
+func targetBreaker(endpoint string, transport http.RoundTripper) *reg.Breaker {
+	return reg.GetBreaker(endpoint, reg.ProbeURL(transport, strings.TrimRight(endpoint, "/")+"/v2/"))
 }
 
 func (r *registry) GetProject(name string) (*models.Project, error) {
@@ -85,9 +136,58 @@ func (r *registry) CreateProject(project *models.Project) error {
 }
 
 func (r *registry) DeleteRepository(repository string) error {
+	switch {
+	case r.targetType == models.RepTargetTypeQuay && r.quayOAuth:
+		return r.deleteQuayRepository()
+	case r.targetType == models.RepTargetTypeGitHub:
+		return r.deleteGitHubPackage()
+	case r.targetType == models.RepTargetTypeGitLab:
+		return r.deleteGitLabRepository()
+	case r.targetType == models.RepTargetTypeArtifactory:
+		return r.deleteArtifactoryPath(r.Name)
+	case r.targetType != models.RepTargetTypeHarbor:
+		tags, err := r.ListTag()
+		if err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return &common_http.Error{
+				Code: http.StatusNotFound,
+			}
+		}
+		for _, tag := range tags {
+			if err = r.DeleteImage(repository, tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	return r.client.Delete(strings.TrimRight(r.url, "/") + "/api/repositories/" + repository)
 }
 
 func (r *registry) DeleteImage(repository, tag string) error {
+	switch {
+	case r.targetType == models.RepTargetTypeQuay && r.quayOAuth:
+		return r.deleteQuayTag(tag)
+	case r.targetType == models.RepTargetTypeGitHub:
+		return r.deleteGitHubTag(tag)
+	case r.targetType == models.RepTargetTypeGitLab:
+		return r.deleteGitLabTag(tag)
+	case r.targetType == models.RepTargetTypeArtifactory:
+		// the tags are stored as folders in the repository of Artifactory
+		return r.deleteArtifactoryPath(r.Name + "/" + tag)
+	case r.targetType != models.RepTargetTypeHarbor:
+		// the generic registries support only deleting the manifests by digest
+		digest, exist, err := r.ManifestExist(tag)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return &common_http.Error{
+				Code: http.StatusNotFound,
+			}
+		}
+		return r.DeleteManifest(digest)
+	}
 	return r.client.Delete(strings.TrimRight(r.url, "/") + "/api/repositories/" + repository + "/tags/" + tag)
 }
@@ -15,6 +15,7 @@
 package replication
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -28,6 +29,7 @@ import (
 	common_http "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/http/modifier"
 	httpauth "github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	reg "github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/common/utils/registry/auth"
@@ -38,6 +40,9 @@ import (
 
 var (
 	errCanceled = errors.New("the job is canceled")
+	// errRateLimited defers the job until the budget of the rate limit of the destination
+	// registry recovers, the job is retried
+	errRateLimited = errors.New("the budget of the rate limit of the destination registry is low, the transfer is deferred")
 )
 
 // Transfer images from source registry to the destination one
@@ -48,6 +53,8 @@ type Transfer struct {
 	dstRegistry *registry
 	logger      logger.Interface
 	retry       bool
+	// the total bytes of the replicated images
+	size int64
 }
 
 // ShouldRetry : retry if the error is network error
@@ -69,6 +76,14 @@ func (t *Transfer) Validate(params map[string]interface{}) error {
 func (t *Transfer) Run(ctx env.JobContext, params map[string]interface{}) error {
 	err := t.run(ctx, params)
 	t.retry = retry(err)
+	report := &models.RepJobReport{
+		Size: t.size,
+	}
+	if t.dstRegistry != nil {
+		report.Endpoint = t.dstRegistry.url
+		report.RateLimit = t.dstRegistry.lastRateLimit()
+	}
+	checkInReport(ctx, report, err)
 	return err
 }
 
@@ -77,12 +92,31 @@ func (t *Transfer) run(ctx env.JobContext, params map[string]interface{}) error
 	if err := t.init(ctx, params); err != nil {
 		return err
 	}
-	// try to create project on destination registry
-	if err := t.createProject(); err != nil {
+	// try to create project on destination registry, the other
+	// registries have no project or create the namespaces on demand
+	switch {
+	case t.dstRegistry.targetType == models.RepTargetTypeHarbor:
+		if err := t.createProject(); err != nil {
+			return err
+		}
+	case t.dstRegistry.targetType == models.RepTargetTypeQuay && t.dstRegistry.quayOAuth:
+		if err := t.dstRegistry.createQuayRepository(); err != nil {
+			t.logger.Errorf("an error occurred while creating repository %s on destination registry: %v", t.dstRegistry.Name, err)
+			return err
+		}
+	}
+	// wait until the count of the transfers to the destination registry is under the limit
+	release, err := acquireTransfer(t.ctx, t.dstRegistry.url, t.dstRegistry.maxConcurrentTransfers)
+	if err != nil {
+		t.logger.Warning(err.Error())
 		return err
 	}
+	defer release()
 	// replicate the images
 	for _, tag := range t.repository.tags {
+		if err := t.checkRateLimit(); err != nil {
+			return err
+		}
 		digest, manifest, err := t.pullManifest(tag)
 		if err != nil {
 			return err
@@ -93,11 +127,26 @@ func (t *Transfer) run(ctx env.JobContext, params map[string]interface{}) error
 		if err := t.pushManifest(tag, digest, manifest); err != nil {
 			return err
 		}
+		for _, blob := range manifest.References() {
+			t.size += blob.Size
+		}
 	}
 
 	return nil
 }
 
+// checkRateLimit returns errRateLimited if the remaining budget of the rate limit of the
+// destination registry has fallen to the reserve
+func (t *Transfer) checkRateLimit() error {
+	rateLimit := t.dstRegistry.lastRateLimit()
+	if rateLimit == nil || !rateLimit.Low(t.dstRegistry.rateLimitReserve) {
+		return nil
+	}
+	t.logger.Warningf("%v: %d of %d remaining, the reserve is %d", errRateLimited,
+		rateLimit.Remaining, rateLimit.Limit, t.dstRegistry.rateLimitReserve)
+	return errRateLimited
+}
+
 func (t *Transfer) init(ctx env.JobContext, params map[string]interface{}) error {
 	t.logger = ctx.GetLogger()
 	t.ctx = ctx
@@ -139,12 +188,7 @@ func (t *Transfer) init(ctx env.JobContext, params map[string]interface{}) error
 	}
 
 	// init destination registry client
-	dstURL := params["dst_registry_url"].(string)
-	dstInsecure := params["dst_registry_insecure"].(bool)
-	dstCred := auth.NewBasicAuthCredential(
-		params["dst_registry_username"].(string),
-		params["dst_registry_password"].(string))
-	t.dstRegistry, err = initRegistry(dstURL, dstInsecure, dstCred, t.repository.name)
+	t.dstRegistry, err = initDstRegistry(params, t.repository.name)
 	if err != nil {
 		t.logger.Errorf("failed to create client for destination registry: %v", err)
 		return err
@@ -166,25 +210,106 @@ func (t *Transfer) init(ctx env.JobContext, params map[string]interface{}) error
 		t.repository.tags = tags
 	}
 
-	t.logger.Infof("initialization completed: repository: %s, tags: %v, source registry: URL-%s insecure-%v, destination registry: URL-%s insecure-%v",
-		t.repository.name, t.repository.tags, t.srcRegistry.url, t.srcRegistry.insecure, t.dstRegistry.url, t.dstRegistry.insecure)
+	t.logger.Infof("initialization completed: repository: %s, tags: %v, source registry: URL-%s insecure-%v, destination registry: URL-%s insecure-%v repository-%s",
+		t.repository.name, t.repository.tags, t.srcRegistry.url, t.srcRegistry.insecure, t.dstRegistry.url, t.dstRegistry.insecure, t.dstRegistry.Name)
 
 	return nil
 }
 
 func initRegistry(url string, insecure bool, credential modifier.Modifier,
 	repository string, tokenServiceURL ...string) (*registry, error) {
-	registry := &registry{
-		url:      url,
-		insecure: insecure,
-	}
-
 	// use the same transport for clients connecting to docker registry and Harbor UI
 	transport := reg.GetHTTPTransport(insecure)
 
 	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
 		Transport: transport,
 	}, credential, tokenServiceURL...)
+	return newRegistry(url, insecure, transport, credential, authorizer, repository)
+}
+
+// initDstRegistry creates the client of the destination registry, the type, auth scheme,
+// token realm and path mappings of the target are read from the params
+func initDstRegistry(params map[string]interface{}, repository string) (*registry, error) {
+	target := &models.RepTarget{
+		URL:      params["dst_registry_url"].(string),
+		Insecure: params["dst_registry_insecure"].(bool),
+		Username: params["dst_registry_username"].(string),
+		Password: params["dst_registry_password"].(string),
+	}
+	target.AuthScheme, _ = params["dst_auth_scheme"].(string)
+	target.TokenRealm, _ = params["dst_token_realm"].(string)
+	target.PathMappingStr, _ = params["dst_path_mappings"].(string)
+	target.CABundle, _ = params["dst_ca_bundle"].(string)
+	target.Type = intParam(params, "dst_registry_type")
+	target.MaxConcurrentTransfers = intParam(params, "dst_max_concurrent_transfers")
+	target.BackoffRetries = intParam(params, "dst_backoff_retries")
+	target.RateLimitReserve = intParam(params, "dst_ratelimit_reserve")
+	if err := target.Unmarshal(); err != nil {
+		return nil, fmt.Errorf("invalid path mappings: %v", err)
+	}
+
+	tr, err := reg.GetHTTPTransportWithCA(target.Insecure, target.CABundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA bundle: %v", err)
+	}
+	rateLimits := &rateLimitRecorder{}
+	var transport http.RoundTripper = reg.NewRateLimitTransport(tr, rateLimits.record)
+	authorizer, err := auth.NewAuthorizer(&http.Client{
+		Transport: transport,
+	}, target.AuthScheme, target.Username, target.Password, target.TokenRealm)
+	if err != nil {
+		return nil, err
+	}
+	// the requests to the target are retried with backoff and stopped by the breaker of the
+	// target when it's down, the token requests of the authorizer aren't as the token service
+	// is usually not the weak part
+	transport = reg.NewBreakerTransport(transport, targetBreaker(target.URL, tr))
+	if target.BackoffRetries > 0 {
+		transport = reg.NewBackoffTransport(transport, target.BackoffRetries)
+	}
+	var credential modifier.Modifier = auth.NewBasicAuthCredential(target.Username, target.Password)
+	quayOAuth := target.Type == models.RepTargetTypeQuay && target.Username == models.QuayOAuthTokenUsername
+	switch {
+	case quayOAuth:
+		credential = quayOAuthToken(target.Password)
+	case target.Type == models.RepTargetTypeGitHub:
+		credential = githubToken(target.Password)
+	case target.Type == models.RepTargetTypeGitLab:
+		credential = gitlabToken(target.Password)
+	}
+	registry, err := newRegistry(target.URL, target.Insecure, transport,
+		credential, authorizer, target.RemoteRepository(repository))
+	if err != nil {
+		return nil, err
+	}
+	registry.targetType = target.Type
+	registry.quayOAuth = quayOAuth
+	registry.apiURL = target.APIURL()
+	registry.username = target.Username
+	registry.maxConcurrentTransfers = target.MaxConcurrentTransfers
+	registry.rateLimitReserve = target.RateLimitReserve
+	registry.rateLimits = rateLimits
+	return registry, nil
+}
+
+// intParam returns the int param, the number is decoded as float64 from the JSON params
+func intParam(params map[string]interface{}, key string) int {
+	switch v := params[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+func newRegistry(url string, insecure bool, transport http.RoundTripper, credential,
+	authorizer modifier.Modifier, repository string) (*registry, error) {
+	registry := &registry{
+		url:      url,
+		insecure: insecure,
+	}
+
 	uam := &job_utils.UserAgentModifier{
 		UserAgent: "harbor-registry-client",
 	}
@@ -344,6 +469,20 @@ func (t *Transfer) pushManifest(tag, digest string, manifest distribution.Manife
 	return nil
 }
 
+// checkInReport checks in the report and the error of the job
+func checkInReport(ctx env.JobContext, report *models.RepJobReport, err error) {
+	if err != nil {
+		report.Error = err.Error()
+	}
+	data, e := json.Marshal(report)
+	if e == nil {
+		e = ctx.Checkin(string(data))
+	}
+	if e != nil {
+		ctx.GetLogger().Warningf("failed to check in the report of the job: %v", e)
+	}
+}
+
 func canceled(ctx env.JobContext) bool {
 	_, canceled := ctx.OPCommand()
 	return canceled
@@ -353,8 +492,17 @@ func retry(err error) bool {
 	if err == nil {
 		return false
 	}
-	_, ok := err.(net.Error)
-	return ok
+	if err == errRateLimited {
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	// the registry is busy or unavailable temporarily
+	if e, ok := err.(*common_http.Error); ok {
+		return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+	}
+	return false
 }
 
 func secret() string {
@@ -14,8 +14,12 @@
 package replication
 
 import (
+	"net/http"
 	"testing"
 
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,3 +40,75 @@ func TestShouldRetryOfTransfer(t *testing.T) {
 	r.retry = true
 	assert.True(t, r.ShouldRetry())
 }
+
+func TestRetry(t *testing.T) {
+	assert.False(t, retry(nil))
+	assert.True(t, retry(&common_http.Error{Code: http.StatusTooManyRequests}))
+	assert.True(t, retry(&common_http.Error{Code: http.StatusServiceUnavailable}))
+	assert.False(t, retry(&common_http.Error{Code: http.StatusNotFound}))
+	assert.True(t, retry(errRateLimited))
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	tr := &Transfer{
+		dstRegistry: &registry{
+			rateLimits: &rateLimitRecorder{},
+		},
+		logger: backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4),
+	}
+	// no rate limit
+	assert.Nil(t, tr.checkRateLimit())
+
+	// no reserve
+	tr.dstRegistry.rateLimits.record(&models.RateLimit{Limit: 100, Remaining: 0})
+	assert.Nil(t, tr.checkRateLimit())
+
+	tr.dstRegistry.rateLimitReserve = 10
+	tr.dstRegistry.rateLimits.record(&models.RateLimit{Limit: 100, Remaining: 11})
+	assert.Nil(t, tr.checkRateLimit())
+	tr.dstRegistry.rateLimits.record(&models.RateLimit{Limit: 100, Remaining: 10})
+	assert.Equal(t, errRateLimited, tr.checkRateLimit())
+}
+
+func TestInitDstRegistry(t *testing.T) {
+	params := map[string]interface{}{
+		"dst_registry_url":      "https://registry.example.com",
+		"dst_registry_insecure": false,
+		"dst_registry_username": "admin",
+		"dst_registry_password": "Harbor12345",
+	}
+	r, err := initDstRegistry(params, "library/nginx")
+	require.Nil(t, err)
+	assert.Equal(t, models.RepTargetTypeHarbor, r.targetType)
+	assert.Equal(t, "library/nginx", r.Name)
+
+	params["dst_registry_type"] = float64(models.RepTargetTypeGeneric)
+	params["dst_auth_scheme"] = models.RepTargetAuthBasic
+	params["dst_path_mappings"] = `[{"project":"library","path":"docker-local/team"}]`
+	r, err = initDstRegistry(params, "library/nginx")
+	require.Nil(t, err)
+	assert.Equal(t, models.RepTargetTypeGeneric, r.targetType)
+	assert.Equal(t, "docker-local/team/nginx", r.Name)
+	assert.Equal(t, 0, r.maxConcurrentTransfers)
+
+	params["dst_max_concurrent_transfers"] = float64(2)
+	params["dst_backoff_retries"] = float64(3)
+	params["dst_ratelimit_reserve"] = float64(20)
+	r, err = initDstRegistry(params, "library/nginx")
+	require.Nil(t, err)
+	assert.Equal(t, 2, r.maxConcurrentTransfers)
+	assert.Equal(t, 20, r.rateLimitReserve)
+	assert.Nil(t, r.lastRateLimit())
+
+	params["dst_registry_type"] = float64(models.RepTargetTypeQuay)
+	params["dst_registry_username"] = models.QuayOAuthTokenUsername
+	params["dst_path_mappings"] = ""
+	r, err = initDstRegistry(params, "library/app/web")
+	require.Nil(t, err)
+	assert.True(t, r.quayOAuth)
+	assert.Equal(t, "library/app_web", r.Name)
+
+	params["dst_auth_scheme"] = "digest"
+	_, err = initDstRegistry(params, "library/nginx")
+	assert.NotNil(t, err)
+}
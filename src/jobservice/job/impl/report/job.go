@@ -0,0 +1,282 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/email"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+const (
+	// the days covered by the report if they aren't specified
+	defaultDays = 7
+	// the timeout in seconds of sending an email
+	emailTimeout = 60
+)
+
+// report is the summary of a project rendered by the template
+type report struct {
+	Project         string
+	Since           time.Time
+	Until           time.Time
+	Pushes          int64
+	Pulls           int64
+	Deletions       int64
+	Vulnerabilities []*models.SeverityCount
+	StorageUsage    int64
+	// the max storage usage in bytes, -1 means unlimited
+	StorageQuota int64
+	URL          string
+}
+
+// QuotaPercent returns the percentage of the quota used
+func (r *report) QuotaPercent() int64 {
+	if r.StorageQuota <= 0 {
+		return 0
+	}
+	return r.StorageUsage * 100 / r.StorageQuota
+}
+
+// Reporter sends the summary of the activities, the new vulnerabilities and the quota status
+// of the projects in the last days to the members subscribing the reports of the projects
+type Reporter struct {
+	logger   logger.Interface
+	endpoint string
+	settings *models.Email
+}
+
+// MaxFails implements the interface in job/Interface
+func (r *Reporter) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (r *Reporter) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (r *Reporter) Validate(params map[string]interface{}) error {
+	if v, ok := params["days"]; ok && utils.SafeCastInt(v) <= 0 {
+		return fmt.Errorf("invalid days %v, it must be larger than 0", v)
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (r *Reporter) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := r.init(ctx); err != nil {
+		return err
+	}
+	days := defaultDays
+	if v, ok := params["days"]; ok {
+		days = utils.SafeCastInt(v)
+	}
+	until := time.Now()
+	since := until.AddDate(0, 0, -days)
+
+	ids, err := dao.ListReportedProjectIDs()
+	if err != nil {
+		r.logger.Errorf("failed to list the projects with subscriptions: %v", err)
+		return err
+	}
+	r.logger.Infof("start to send the reports of %d projects", len(ids))
+	failed := 0
+	for i, id := range ids {
+		if _, stopped := ctx.OPCommand(); stopped {
+			r.logger.Info("the job is stopped")
+			return errs.JobStoppedError()
+		}
+		progress := fmt.Sprintf("%d/%d sending the report of project %d", i+1, len(ids), id)
+		if err := ctx.Checkin(progress); err != nil {
+			r.logger.Warningf("failed to check in the progress %q: %v", progress, err)
+		}
+		if err := r.sendReport(id, since, until); err != nil {
+			r.logger.Errorf("failed to send the report of project %d: %v", id, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to send the reports of %d projects", failed)
+	}
+	r.logger.Info("the reports are sent")
+	return nil
+}
+
+func (r *Reporter) init(ctx env.JobContext) error {
+	r.logger = ctx.GetLogger()
+	host, _ := ctx.Get(common.EmailHost)
+	if len(utils.SafeCastString(host)) == 0 {
+		return fmt.Errorf("the email server is not configured")
+	}
+	get := func(key string) interface{} {
+		v, _ := ctx.Get(key)
+		return v
+	}
+	r.settings = &models.Email{
+		Host:     utils.SafeCastString(get(common.EmailHost)),
+		Port:     int(utils.SafeCastFloat64(get(common.EmailPort))),
+		Username: utils.SafeCastString(get(common.EmailUsername)),
+		Password: utils.SafeCastString(get(common.EmailPassword)),
+		SSL:      utils.SafeCastBool(get(common.EmailSSL)),
+		From:     utils.SafeCastString(get(common.EmailFrom)),
+		Identity: utils.SafeCastString(get(common.EmailIdentity)),
+		Insecure: utils.SafeCastBool(get(common.EmailInsecure)),
+	}
+	r.endpoint = strings.TrimSuffix(utils.SafeCastString(get(common.ExtEndpoint)), "/")
+	return nil
+}
+
+// sendReport sends the report of the project to its subscribers, nothing is sent if none of
+// the subscribers has an email address
+func (r *Reporter) sendReport(projectID int64, since, until time.Time) error {
+	project, err := dao.GetProjectByID(projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return nil
+	}
+	users, err := dao.ListProjectReportSubscribers(projectID)
+	if err != nil {
+		return err
+	}
+	to := []string{}
+	for _, user := range users {
+		if len(user.Email) > 0 {
+			to = append(to, user.Email)
+		}
+	}
+	if len(to) == 0 {
+		r.logger.Debugf("no subscriber of project %s has an email address, skip", project.Name)
+		return nil
+	}
+
+	rpt, err := r.collect(project, since, until)
+	if err != nil {
+		return err
+	}
+	message, err := render(rpt)
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(r.settings.Host, strconv.Itoa(r.settings.Port))
+	if err = email.Send(addr, r.settings.Identity, r.settings.Username, r.settings.Password,
+		emailTimeout, r.settings.SSL, r.settings.Insecure, r.settings.From, to,
+		fmt.Sprintf("Harbor report of project %s", project.Name), message); err != nil {
+		return err
+	}
+	r.logger.Infof("the report of project %s is sent to %d subscribers", project.Name, len(to))
+	return nil
+}
+
+// collect summarizes the project from the database, the access logs stored in Elasticsearch
+// aren't counted
+func (r *Reporter) collect(project *models.Project, since, until time.Time) (*report, error) {
+	rpt := &report{
+		Project:      project.Name,
+		Since:        since,
+		Until:        until,
+		StorageQuota: -1,
+	}
+	if len(r.endpoint) > 0 {
+		rpt.URL = fmt.Sprintf("%s/harbor/projects/%d/repositories", r.endpoint, project.ProjectID)
+	}
+	for _, item := range []struct {
+		operation string
+		count     *int64
+	}{
+		{"push", &rpt.Pushes},
+		{"pull", &rpt.Pulls},
+		{"delete", &rpt.Deletions},
+	} {
+		n, err := dao.GetTotalOfAccessLogs(&models.LogQueryParam{
+			ProjectIDs: []int64{project.ProjectID},
+			Operations: []string{item.operation},
+			BeginTime:  &since,
+			EndTime:    &until,
+		})
+		if err != nil {
+			return nil, err
+		}
+		*item.count = n
+	}
+
+	vulns, err := dao.CountNewVulnsOfProject(project.Name, since)
+	if err != nil {
+		return nil, err
+	}
+	rpt.Vulnerabilities = vulns
+
+	if rpt.StorageUsage, err = dao.GetProjectUsage(project.ProjectID); err != nil {
+		return nil, err
+	}
+	metas, err := dao.GetProjectMetadata(project.ProjectID, models.ProMetaStorageQuota)
+	if err != nil {
+		return nil, err
+	}
+	for _, meta := range metas {
+		project.SetMetadata(meta.Name, meta.Value)
+	}
+	rpt.StorageQuota = project.StorageQuota()
+	return rpt, nil
+}
+
+// render renders the report with the template
+func render(rpt *report) (string, error) {
+	tpl, err := template.New("report").Funcs(template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.UTC().Format("2006-01-02")
+		},
+		"size": formatSize,
+	}).Parse(reportTemplate)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err = tpl.Execute(buf, rpt); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatSize formats the size in bytes with the binary units, e.g. "1.5 GiB"
+func formatSize(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(size)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
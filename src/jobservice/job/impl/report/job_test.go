@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOfReporter(t *testing.T) {
+	r := &Reporter{}
+	assert.Nil(t, r.Validate(nil))
+	assert.Nil(t, r.Validate(map[string]interface{}{"days": 30}))
+	assert.NotNil(t, r.Validate(map[string]interface{}{"days": 0}))
+	assert.Equal(t, uint(1), r.MaxFails())
+	assert.False(t, r.ShouldRetry())
+}
+
+func TestRender(t *testing.T) {
+	rpt := &report{
+		Project:   "library",
+		Since:     time.Date(2019, 6, 3, 8, 0, 0, 0, time.UTC),
+		Until:     time.Date(2019, 6, 10, 8, 0, 0, 0, time.UTC),
+		Pushes:    3,
+		Pulls:     120,
+		Deletions: 1,
+		Vulnerabilities: []*models.SeverityCount{
+			{Severity: models.SevHigh, Count: 2},
+			{Severity: models.SevLow, Count: 5},
+		},
+		StorageUsage: 800 * 1024 * 1024,
+		StorageQuota: 1024 * 1024 * 1024,
+		URL:          "https://harbor.example.com/harbor/projects/1/repositories",
+	}
+	message, err := render(rpt)
+	require.Nil(t, err)
+	assert.Contains(t, message, "The report of project library from 2019-06-03 to 2019-06-10")
+	assert.Contains(t, message, "Pulls: 120\n")
+	assert.Contains(t, message, "  high: 2\n  low: 5\n")
+	assert.Contains(t, message, "Used: 800.0 MiB of the quota 1.0 GiB (78%)")
+	assert.Contains(t, message, "View the project: https://harbor.example.com/harbor/projects/1/repositories")
+
+	// no vulnerability and unlimited quota
+	rpt.Vulnerabilities = nil
+	rpt.StorageQuota = -1
+	rpt.URL = ""
+	message, err = render(rpt)
+	require.Nil(t, err)
+	assert.Contains(t, message, "New vulnerabilities:\n  None\n")
+	assert.Contains(t, message, "the quota is unlimited")
+	assert.NotContains(t, message, "View the project")
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "0 B", formatSize(0))
+	assert.Equal(t, "1023 B", formatSize(1023))
+	assert.Equal(t, "1.5 KiB", formatSize(1536))
+	assert.Equal(t, "2.0 TiB", formatSize(2*1024*1024*1024*1024))
+}
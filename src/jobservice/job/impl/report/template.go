@@ -0,0 +1,40 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+// reportTemplate is the template of the report emails, the data is the report
+const reportTemplate = `The report of project {{.Project}} from {{date .Since}} to {{date .Until}} (UTC)
+
+Activities:
+  Pushes: {{.Pushes}}
+  Pulls: {{.Pulls}}
+  Deletions: {{.Deletions}}
+
+New vulnerabilities:
+{{- range .Vulnerabilities}}
+  {{.Severity}}: {{.Count}}
+{{- else}}
+  None
+{{- end}}
+
+Storage:
+  Used: {{size .StorageUsage}}{{if ge .StorageQuota 0}} of the quota {{size .StorageQuota}} ({{.QuotaPercent}}%){{else}}, the quota is unlimited{{end}}
+{{- if .URL}}
+
+View the project: {{.URL}}
+{{- end}}
+
+You received this email because you subscribed to the reports of the project.
+`
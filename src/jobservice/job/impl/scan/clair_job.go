@@ -23,11 +23,11 @@ import (
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/common"
-	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/job"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/clair"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/jobservice/env"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/utils"
 )
@@ -38,6 +38,7 @@ type ClairJob struct {
 	secret        string
 	tokenEndpoint string
 	clairEndpoint string
+	cvssSource    string
 }
 
 // MaxFails implements the interface in job/Interface
@@ -93,7 +94,12 @@ func (cj *ClairJob) Run(ctx env.JobContext, params map[string]interface{}) error
 	if !ok {
 		loggerImpl = log.DefaultLogger()
 	}
-	clairClient := clair.NewClient(cj.clairEndpoint, loggerImpl)
+	transport, err := reg.GetHTTPTransportWithCA(false, jobParms.CABundle)
+	if err != nil {
+		logger.Errorf("Invalid CA bundle of the scanner, error: %v", err)
+		return err
+	}
+	clairClient := clair.NewClientWithTransport(cj.clairEndpoint, transport, loggerImpl)
 
 	for _, l := range layers {
 		logger.Infof("Scanning Layer: %s, path: %s", l.Name, l.Path)
@@ -109,9 +115,7 @@ func (cj *ClairJob) Run(ctx env.JobContext, params map[string]interface{}) error
 		logger.Errorf("Failed to get result from Clair, error: %v", err)
 		return err
 	}
-	compOverview, sev := clair.TransformVuln(res)
-	err = dao.UpdateImgScanOverview(jobParms.Digest, layerName, sev, compOverview)
-	return err
+	return clair.UpdateScanResult(jobParms.Digest, layerName, res, cj.cvssSource)
 }
 
 func (cj *ClairJob) init(ctx env.JobContext) error {
@@ -137,6 +141,7 @@ func (cj *ClairJob) init(ctx env.JobContext) error {
 	} else {
 		return fmt.Errorf(errTpl, common.ClairURL)
 	}
+	cj.cvssSource = getCVSSSource(ctx)
 	return nil
 }
 
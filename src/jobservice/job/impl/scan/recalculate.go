@@ -0,0 +1,132 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+)
+
+// SeverityRecalculation recomputes the severity and the components overview of the stored
+// scan reports with the current source of CVSS, it's triggered when the source is changed
+// so that the reports scanned before and after the change are bucketed in the same way
+type SeverityRecalculation struct {
+	clairEndpoint string
+	cvssSource    string
+}
+
+// MaxFails implements the interface in job/Interface
+func (s *SeverityRecalculation) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (s *SeverityRecalculation) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (s *SeverityRecalculation) Validate(params map[string]interface{}) error {
+	for key := range params {
+		if key != "ca_bundle" {
+			return fmt.Errorf("unexpected parm %s for severity recalculation job", key)
+		}
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (s *SeverityRecalculation) Run(ctx env.JobContext, params map[string]interface{}) error {
+	logger := ctx.GetLogger()
+	if err := s.init(ctx); err != nil {
+		logger.Errorf("Failed to initialize the job, error: %v", err)
+		return err
+	}
+	overviews, err := dao.ListImgScanOverviews()
+	if err != nil {
+		logger.Errorf("Failed to list scan overview records, error: %v", err)
+		return err
+	}
+	loggerImpl, ok := logger.(*log.Logger)
+	if !ok {
+		loggerImpl = log.DefaultLogger()
+	}
+	bundle, _ := params["ca_bundle"].(string)
+	transport, err := reg.GetHTTPTransportWithCA(false, bundle)
+	if err != nil {
+		logger.Errorf("Invalid CA bundle of the scanner, error: %v", err)
+		return err
+	}
+	clairClient := clair.NewClientWithTransport(s.clairEndpoint, transport, loggerImpl)
+
+	logger.Infof("Recalculating the severity of %d scan reports with the CVSS source %s", len(overviews), s.cvssSource)
+	failed := 0
+	for i, overview := range overviews {
+		if _, stopped := ctx.OPCommand(); stopped {
+			logger.Info("The job is stopped")
+			return errs.JobStoppedError()
+		}
+		progress := fmt.Sprintf("%d/%d", i+1, len(overviews))
+		if err := ctx.Checkin(progress); err != nil {
+			logger.Warningf("Failed to check in the progress %q, error: %v", progress, err)
+		}
+		// the image isn't scanned successfully
+		if len(overview.DetailsKey) == 0 {
+			continue
+		}
+		res, err := clairClient.GetResult(overview.DetailsKey)
+		if err != nil {
+			logger.Errorf("Failed to get result of %s from Clair, error: %v", overview.Digest, err)
+			failed++
+			continue
+		}
+		if err = clair.UpdateScanResult(overview.Digest, overview.DetailsKey, res, s.cvssSource); err != nil {
+			logger.Errorf("Failed to update scan overview of %s, error: %v", overview.Digest, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to recalculate the severity of %d scan reports", failed)
+	}
+	logger.Info("The severity of scan reports is recalculated")
+	return nil
+}
+
+func (s *SeverityRecalculation) init(ctx env.JobContext) error {
+	v, err := getAttrFromCtx(ctx, common.ClairURL)
+	if err != nil {
+		return err
+	}
+	s.clairEndpoint = v
+	s.cvssSource = getCVSSSource(ctx)
+	return nil
+}
+
+// getCVSSSource returns the source of CVSS configured, it's "vendor" by default
+func getCVSSSource(ctx env.JobContext) string {
+	if v, ok := ctx.Get(common.CVSSSource); ok {
+		if source, ok := v.(string); ok && len(source) > 0 {
+			return source
+		}
+	}
+	return common.CVSSSourceVendor
+}
@@ -0,0 +1,39 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxFailsOfSeverityRecalculation(t *testing.T) {
+	s := &SeverityRecalculation{}
+	assert.Equal(t, uint(1), s.MaxFails())
+}
+
+func TestValidateOfSeverityRecalculation(t *testing.T) {
+	s := &SeverityRecalculation{}
+	assert.Nil(t, s.Validate(nil))
+	assert.NotNil(t, s.Validate(map[string]interface{}{
+		"key": "value",
+	}))
+}
+
+func TestShouldRetryOfSeverityRecalculation(t *testing.T) {
+	s := &SeverityRecalculation{}
+	assert.False(t, s.ShouldRetry())
+}
@@ -0,0 +1,392 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagetransition
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	job_utils "github.com/goharbor/harbor/src/jobservice/job/impl/utils"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+const (
+	modeTag  = "tag"
+	modeMove = "move"
+	// the count of digests queried from database at a time
+	batchSize = 100
+)
+
+// storage transitions the blobs to the storage classes
+type storage interface {
+	TagBlob(digest, class string) error
+	MoveBlob(digest, class string, size int64) error
+}
+
+// Transitioner transitions the blobs which are only referenced by the idle tags of the
+// repositories with storage hints to the hinted storage classes. The blobs are tagged for
+// the lifecycle rules of the bucket in the "tag" mode, or copied in place with the storage
+// class in the "move" mode. The transitioned blobs are recorded as the savings.
+type Transitioner struct {
+	logger               logger.Interface
+	registryURL          string
+	secret               string
+	tokenServiceEndpoint string
+	storage              storage
+}
+
+// MaxFails implements the interface in job/Interface
+func (t *Transitioner) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (t *Transitioner) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (t *Transitioner) Validate(params map[string]interface{}) error {
+	if mode := utils.SafeCastString(params["mode"]); mode != modeTag && mode != modeMove {
+		return fmt.Errorf("invalid mode %q", mode)
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (t *Transitioner) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := t.init(ctx); err != nil {
+		return err
+	}
+	dryRun := utils.SafeCastBool(params["dry_run"])
+	mode := utils.SafeCastString(params["mode"])
+	if dryRun {
+		t.logger.Info("start to collect the eligible blobs in dry run mode, no blob will be transitioned")
+	} else {
+		t.logger.Infof("start to transition the eligible blobs in %s mode", mode)
+	}
+
+	c := newCollector(time.Now())
+	projects, err := dao.GetProjects(nil)
+	if err != nil {
+		t.logger.Errorf("failed to list projects: %v", err)
+		return err
+	}
+	for i, project := range projects {
+		if _, stopped := ctx.OPCommand(); stopped {
+			t.logger.Info("the job is stopped")
+			return errs.JobStoppedError()
+		}
+		progress := fmt.Sprintf("%d/%d checking the blobs of %s", i+1, len(projects), project.Name)
+		if err := ctx.Checkin(progress); err != nil {
+			t.logger.Warningf("failed to check in the progress %q: %v", progress, err)
+		}
+		if err := t.collectProject(c, project); err != nil {
+			t.logger.Errorf("failed to collect the blobs of %s: %v", project.Name, err)
+			return err
+		}
+	}
+
+	candidates, err := pending(c.eligible())
+	if err != nil {
+		t.logger.Errorf("failed to get the transitioned blobs: %v", err)
+		return err
+	}
+	counts, sizes := map[string]int64{}, map[string]int64{}
+	for _, cand := range candidates {
+		if _, stopped := ctx.OPCommand(); stopped {
+			t.logger.Info("the job is stopped")
+			return errs.JobStoppedError()
+		}
+		if !dryRun {
+			if mode == modeMove {
+				err = t.storage.MoveBlob(cand.digest, cand.class, cand.size)
+			} else {
+				err = t.storage.TagBlob(cand.digest, cand.class)
+			}
+			if err != nil {
+				// the blob is retried in the next run
+				t.logger.Warningf("failed to transition the blob %s to %s: %v", cand.digest, cand.class, err)
+				continue
+			}
+			if err = dao.AddBlobTransition(&models.BlobTransition{
+				Digest:       cand.digest,
+				StorageClass: cand.class,
+				Size:         cand.size,
+			}); err != nil {
+				t.logger.Errorf("failed to record the transition of blob %s: %v", cand.digest, err)
+				return err
+			}
+		}
+		t.logger.Infof("eligible blob %s, size: %d, storage class: %s", cand.digest, cand.size, cand.class)
+		counts[cand.class]++
+		sizes[cand.class] += cand.size
+	}
+
+	report := summarize(counts, sizes)
+	if dryRun {
+		report += ", dry run"
+	}
+	if err := ctx.Checkin(report); err != nil {
+		t.logger.Warningf("failed to check in the report %q: %v", report, err)
+	}
+	t.logger.Info(report)
+	return nil
+}
+
+func (t *Transitioner) init(ctx env.JobContext) error {
+	t.logger = ctx.GetLogger()
+	errTpl := "Failed to get required property: %s"
+	if v, ok := ctx.Get(common.RegistryURL); ok && len(v.(string)) > 0 {
+		t.registryURL = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.RegistryURL)
+	}
+	if v, ok := ctx.Get(common.TokenServiceURL); ok && len(v.(string)) > 0 {
+		t.tokenServiceEndpoint = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.TokenServiceURL)
+	}
+	t.secret = os.Getenv("JOBSERVICE_SECRET")
+	if len(t.secret) == 0 {
+		return fmt.Errorf("failed to read evnironment variable JOBSERVICE_SECRET")
+	}
+	if t.storage == nil {
+		s, err := newS3Client()
+		if err != nil {
+			return err
+		}
+		t.storage = s
+	}
+	return nil
+}
+
+// collectProject adds the blobs referenced by the tags of the project into the collector,
+// all the repositories are checked as the blobs may be shared with the ones without hint
+func (t *Transitioner) collectProject(c *collector, project *models.Project) error {
+	var hint *models.StorageHint
+	metas, err := dao.GetProjectMetadata(project.ProjectID, models.ProMetaStorageHint)
+	if err != nil {
+		return err
+	}
+	if len(metas) > 0 && len(metas[0].Value) > 0 {
+		if hint, err = models.ParseStorageHint(metas[0].Value); err != nil {
+			t.logger.Warningf("invalid storage hint of project %s, ignored: %v", project.Name, err)
+		}
+	}
+
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{project.ProjectID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, repository := range repositories {
+		override, err := dao.GetRepoStorageHint(repository.Name)
+		if err != nil {
+			return err
+		}
+		if err = t.collectRepository(c, repository, models.ResolveStorageHint(hint, override)); err != nil {
+			// the blobs of the repository are missed, they are only charged more if
+			// transitioned, so the collection goes on
+			t.logger.Warningf("failed to collect the blobs of %s, skip: %v", repository.Name, err)
+		}
+	}
+	return nil
+}
+
+func (t *Transitioner) collectRepository(c *collector, repository *models.RepoRecord, hint *models.StorageHint) error {
+	client, err := job_utils.NewRepositoryClientForJobservice(repository.Name, t.registryURL, t.secret, t.tokenServiceEndpoint)
+	if err != nil {
+		return err
+	}
+	tags, err := client.ListTag()
+	if err != nil {
+		return err
+	}
+	acceptMediaTypes := []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest}
+	for _, tag := range tags {
+		_, mediaType, payload, err := client.PullManifest(tag, acceptMediaTypes)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(mediaType, "application/json") {
+			mediaType = schema1.MediaTypeManifest
+		}
+		manifest, _, err := reg.UnMarshal(mediaType, payload)
+		if err != nil {
+			return fmt.Errorf("failed to parse manifest of %s:%s: %v", repository.Name, tag, err)
+		}
+
+		// the tag is used when pushed or pulled, the push time of the blobs is the time
+		// they were added into the project, or the creation time of repository for the
+		// blobs pushed before the blobs of projects were recorded
+		var pullTime time.Time
+		pt, err := dao.GetTagPullTime(repository.Name, tag)
+		if err != nil {
+			return err
+		}
+		if pt != nil {
+			pullTime = pt.PullTime
+		}
+		refs := manifest.References()
+		digests := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			digests = append(digests, ref.Digest.String())
+		}
+		blobs, err := dao.ListProjectBlobs(repository.ProjectID, digests...)
+		if err != nil {
+			return err
+		}
+		pushed := map[string]*models.ProjectBlob{}
+		for _, blob := range blobs {
+			pushed[blob.Digest] = blob
+		}
+		for _, ref := range refs {
+			digest := ref.Digest.String()
+			size, lastUsed := ref.Size, repository.CreationTime
+			if blob, exist := pushed[digest]; exist {
+				size, lastUsed = blob.Size, blob.CreationTime
+			}
+			if pullTime.After(lastUsed) {
+				lastUsed = pullTime
+			}
+			c.add(digest, size, hint, lastUsed)
+		}
+	}
+	return nil
+}
+
+// pending removes the blobs which have been transitioned to the storage classes
+func pending(candidates []*candidate) ([]*candidate, error) {
+	result := []*candidate{}
+	for i := 0; i < len(candidates); i += batchSize {
+		end := i + batchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		batch := candidates[i:end]
+		digests := make([]string, 0, len(batch))
+		for _, cand := range batch {
+			digests = append(digests, cand.digest)
+		}
+		transitions, err := dao.ListBlobTransitions(digests...)
+		if err != nil {
+			return nil, err
+		}
+		classes := map[string]string{}
+		for _, transition := range transitions {
+			classes[transition.Digest] = transition.StorageClass
+		}
+		for _, cand := range batch {
+			if classes[cand.digest] != cand.class {
+				result = append(result, cand)
+			}
+		}
+	}
+	return result, nil
+}
+
+func summarize(counts, sizes map[string]int64) string {
+	classes := make([]string, 0, len(counts))
+	var count, size int64
+	for class := range counts {
+		classes = append(classes, class)
+		count += counts[class]
+		size += sizes[class]
+	}
+	sort.Strings(classes)
+	details := make([]string, 0, len(classes))
+	for _, class := range classes {
+		details = append(details, fmt.Sprintf("%s: %d (%d bytes)", class, counts[class], sizes[class]))
+	}
+	report := fmt.Sprintf("%d blobs (%d bytes) transitioned", count, size)
+	if len(details) > 0 {
+		report += ", " + strings.Join(details, ", ")
+	}
+	return report
+}
+
+// candidate is a blob referenced by the tags
+type candidate struct {
+	digest string
+	size   int64
+	class  string
+	// the blob stays in the current storage class if it's referenced by a tag
+	// which isn't hinted or used recently, or the hints conflict
+	pinned bool
+}
+
+// collector collects the blobs which can be transitioned, a blob is eligible only if all the
+// tags referencing it are hinted with the same storage class and idle for the days of hints
+type collector struct {
+	now        time.Time
+	candidates map[string]*candidate
+}
+
+func newCollector(now time.Time) *collector {
+	return &collector{
+		now:        now,
+		candidates: map[string]*candidate{},
+	}
+}
+
+// add adds the blob referenced by a tag, the hint is nil if the repository of the
+// tag has no hint, lastUsed is the latest time when the blob was pushed or pulled by the tag
+func (c *collector) add(digest string, size int64, hint *models.StorageHint, lastUsed time.Time) {
+	cand, exist := c.candidates[digest]
+	if !exist {
+		cand = &candidate{
+			digest: digest,
+			size:   size,
+		}
+		c.candidates[digest] = cand
+	}
+	if cand.pinned {
+		return
+	}
+	if hint == nil || lastUsed.After(c.now.AddDate(0, 0, -hint.AfterDays)) ||
+		(len(cand.class) > 0 && cand.class != hint.StorageClass) {
+		cand.pinned = true
+		return
+	}
+	cand.class = hint.StorageClass
+}
+
+// eligible returns the blobs which can be transitioned sorted by the digests
+func (c *collector) eligible() []*candidate {
+	result := []*candidate{}
+	for _, cand := range c.candidates {
+		if !cand.pinned {
+			result = append(result, cand)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].digest < result[j].digest
+	})
+	return result
+}
@@ -0,0 +1,86 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagetransition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFailsOfTransitioner(t *testing.T) {
+	tr := &Transitioner{}
+	assert.Equal(t, uint(1), tr.MaxFails())
+}
+
+func TestValidateOfTransitioner(t *testing.T) {
+	tr := &Transitioner{}
+	require.Nil(t, tr.Validate(map[string]interface{}{"mode": modeTag}))
+	require.Nil(t, tr.Validate(map[string]interface{}{"mode": modeMove}))
+	require.NotNil(t, tr.Validate(map[string]interface{}{"mode": "delete"}))
+}
+
+func TestShouldRetryOfTransitioner(t *testing.T) {
+	tr := &Transitioner{}
+	assert.False(t, tr.ShouldRetry())
+}
+
+func TestCollector(t *testing.T) {
+	now := time.Now()
+	ia := &models.StorageHint{StorageClass: "STANDARD_IA", AfterDays: 30}
+	glacier := &models.StorageHint{StorageClass: "GLACIER_IR", AfterDays: 90}
+	idle := now.AddDate(0, 0, -100)
+	active := now.AddDate(0, 0, -1)
+
+	c := newCollector(now)
+	// only referenced by the idle tags
+	c.add("sha256:1", 10, ia, idle)
+	c.add("sha256:1", 10, ia, now.AddDate(0, 0, -31))
+	// referenced by an active tag
+	c.add("sha256:2", 20, ia, idle)
+	c.add("sha256:2", 20, ia, active)
+	// shared with a repository without hint
+	c.add("sha256:3", 30, ia, idle)
+	c.add("sha256:3", 30, nil, idle)
+	// the hints conflict
+	c.add("sha256:4", 40, ia, idle)
+	c.add("sha256:4", 40, glacier, idle)
+	// idle for the days of the first hint but not the second one
+	c.add("sha256:5", 50, glacier, now.AddDate(0, 0, -60))
+	c.add("sha256:6", 60, glacier, idle)
+
+	candidates := c.eligible()
+	require.Equal(t, 2, len(candidates))
+	assert.Equal(t, "sha256:1", candidates[0].digest)
+	assert.Equal(t, "STANDARD_IA", candidates[0].class)
+	assert.Equal(t, int64(10), candidates[0].size)
+	assert.Equal(t, "sha256:6", candidates[1].digest)
+	assert.Equal(t, "GLACIER_IR", candidates[1].class)
+}
+
+func TestSummarize(t *testing.T) {
+	assert.Equal(t, "0 blobs (0 bytes) transitioned", summarize(map[string]int64{}, map[string]int64{}))
+	assert.Equal(t, "3 blobs (60 bytes) transitioned, GLACIER_IR: 1 (50 bytes), STANDARD_IA: 2 (10 bytes)",
+		summarize(map[string]int64{
+			"STANDARD_IA": 2,
+			"GLACIER_IR":  1,
+		}, map[string]int64{
+			"STANDARD_IA": 10,
+			"GLACIER_IR":  50,
+		}))
+}
@@ -0,0 +1,258 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagetransition
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// the environment variables follow the ones overriding the S3 storage configuration of registry,
+// they should be set into jobservice with the same values as registry
+const (
+	regionEnv         = "REGISTRY_STORAGE_S3_REGION"
+	regionEndpointEnv = "REGISTRY_STORAGE_S3_REGIONENDPOINT"
+	bucketEnv         = "REGISTRY_STORAGE_S3_BUCKET"
+	accessKeyEnv      = "REGISTRY_STORAGE_S3_ACCESSKEY"
+	secretKeyEnv      = "REGISTRY_STORAGE_S3_SECRETKEY"
+	rootDirectoryEnv  = "REGISTRY_STORAGE_S3_ROOTDIRECTORY"
+
+	// the tag set on the blobs, the lifecycle rules of the bucket can filter the objects
+	// by it to transition them
+	storageClassTag = "harbor-storage-class"
+	// the max size of the object copied by a single request
+	maxCopySize = 5 * 1024 * 1024 * 1024
+
+	amzDateFormat   = "20060102T150405Z"
+	signedAlgorithm = "AWS4-HMAC-SHA256"
+)
+
+// s3Client tags or copies the blobs of registry in the S3 compatible storage, the
+// requests are signed by AWS signature version 4 and the bucket is addressed in path style
+type s3Client struct {
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	rootDir   string
+	client    *http.Client
+	now       func() time.Time
+}
+
+func newS3Client() (*s3Client, error) {
+	s := &s3Client{
+		endpoint:  strings.TrimSuffix(os.Getenv(regionEndpointEnv), "/"),
+		region:    os.Getenv(regionEnv),
+		bucket:    os.Getenv(bucketEnv),
+		accessKey: os.Getenv(accessKeyEnv),
+		secretKey: os.Getenv(secretKeyEnv),
+		rootDir:   strings.Trim(os.Getenv(rootDirectoryEnv), "/"),
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}
+	if len(s.bucket) == 0 || len(s.region) == 0 {
+		return nil, fmt.Errorf("the S3 storage of registry isn't configured for jobservice, environment variables %s and %s are required",
+			bucketEnv, regionEnv)
+	}
+	// the storage classes are S3 specific, the credentials from the instance profile aren't supported
+	if len(s.accessKey) == 0 || len(s.secretKey) == 0 {
+		return nil, fmt.Errorf("environment variables %s and %s are required", accessKeyEnv, secretKeyEnv)
+	}
+	if len(s.endpoint) == 0 {
+		s.endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.region)
+	}
+	return s, nil
+}
+
+// blobKey returns the key of the blob data in the layout of the registry storage
+func (s *s3Client) blobKey(digest string) (string, error) {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 || len(parts[1]) < 2 {
+		return "", fmt.Errorf("invalid digest %s", digest)
+	}
+	key := fmt.Sprintf("docker/registry/v2/blobs/%s/%s/%s/data", parts[0], parts[1][:2], parts[1])
+	if len(s.rootDir) > 0 {
+		key = s.rootDir + "/" + key
+	}
+	return key, nil
+}
+
+func (s *s3Client) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, key)
+}
+
+// TagBlob tags the blob with the storage class, the lifecycle rules of the bucket transition it
+func (s *s3Client) TagBlob(digest, class string) error {
+	key, err := s.blobKey(digest)
+	if err != nil {
+		return err
+	}
+	body := []byte(fmt.Sprintf(`<Tagging><TagSet><Tag><Key>%s</Key><Value>%s</Value></Tag></TagSet></Tagging>`,
+		storageClassTag, class))
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key)+"?tagging", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(body)
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	req.Header.Set("Content-Type", "application/xml")
+	return s.do(req, body)
+}
+
+// MoveBlob copies the blob in place with the storage class
+func (s *s3Client) MoveBlob(digest, class string, size int64) error {
+	if size > maxCopySize {
+		return fmt.Errorf("the blob %s is larger than %d bytes which can't be copied by a single request", digest, maxCopySize)
+	}
+	key, err := s.blobKey(digest)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-amz-copy-source", "/"+s.bucket+"/"+uriEncode(key, false))
+	req.Header.Set("x-amz-metadata-directive", "COPY")
+	req.Header.Set("x-amz-storage-class", class)
+	return s.do(req, nil)
+}
+
+func (s *s3Client) do(req *http.Request, payload []byte) error {
+	s.sign(req, payload)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	// the copy may fail after the response status is sent, the error is in the body then
+	if resp.StatusCode != http.StatusOK || bytes.Contains(data, []byte("<Error>")) {
+		return fmt.Errorf("failed to %s %s: %d %s", req.Method, req.URL.Path, resp.StatusCode, string(data))
+	}
+	return nil
+}
+
+// sign signs the request by AWS signature version 4 with all the headers of the request
+func (s *s3Client) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{date, s.region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		signedAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signedAlgorithm, s.accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes the string as what AWS signature version 4 requires, the "/" is
+// kept unless it's in the query
+func uriEncode(s string, encodeSlash bool) string {
+	buf := &bytes.Buffer{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			buf.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(buf, "%%%02X", c)
+	}
+	return buf.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -67,6 +67,25 @@ func NewRepositoryClientForJobservice(repository, internalRegistryURL, secret, i
 	})
 }
 
+// NewRegistryClientForJobservice creates a registry client that can only be used to
+// access the internal registry, e.g. listing the catalog
+func NewRegistryClientForJobservice(internalRegistryURL, secret, internalTokenServiceURL string) (*registry.Registry, error) {
+	transport := registry.GetHTTPTransport()
+	credential := httpauth.NewSecretAuthorizer(secret)
+
+	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
+		Transport: transport,
+	}, credential, internalTokenServiceURL)
+
+	uam := &UserAgentModifier{
+		UserAgent: "harbor-registry-client",
+	}
+
+	return registry.NewRegistry(internalRegistryURL, &http.Client{
+		Transport: registry.NewTransport(transport, authorizer, uam),
+	})
+}
+
 // UserAgentModifier adds the "User-Agent" header to the request
 type UserAgentModifier struct {
 	UserAgent string
@@ -15,11 +15,13 @@ type DBLogger struct {
 	bw            *bufio.Writer
 	buffer        *bytes.Buffer
 	key           string
+	jobName       string
 }
 
-// NewDBLogger crates a new DB logger
+// NewDBLogger crates a new DB logger, the job name is kept with the log
+// for the retention of the logs
 // nil might be returned
-func NewDBLogger(key string, level string, depth int) (*DBLogger, error) {
+func NewDBLogger(key string, jobName string, level string, depth int) (*DBLogger, error) {
 	buffer := bytes.NewBuffer(make([]byte, 0))
 	bw := bufio.NewWriter(buffer)
 	logLevel := parseLevel(level)
@@ -31,6 +33,7 @@ func NewDBLogger(key string, level string, depth int) (*DBLogger, error) {
 		bw:            bw,
 		buffer:        buffer,
 		key:           key,
+		jobName:       jobName,
 	}, nil
 }
 
@@ -44,6 +47,7 @@ func (dbl *DBLogger) Close() error {
 
 	jobLog := models.JobLog{
 		UUID:    dbl.key,
+		JobName: dbl.jobName,
 		Content: dbl.buffer.String(),
 	}
 
@@ -37,7 +37,7 @@ func TestMain(m *testing.M) {
 // Test DB logger
 func TestDBLogger(t *testing.T) {
 	uuid := "uuid_for_unit_test"
-	l, err := NewDBLogger(uuid, "DEBUG", 4)
+	l, err := NewDBLogger(uuid, "", "DEBUG", 4)
 	require.Nil(t, err)
 
 	l.Debug("JobLog Debug: TestDBLogger")
@@ -57,7 +57,7 @@ func TestDBLogger(t *testing.T) {
 	log.Infof("get logger %s", ll)
 
 	sweeper.PrepareDBSweep()
-	dbSweeper := sweeper.NewDBSweeper(-1)
+	dbSweeper := sweeper.NewDBSweeper(-1, nil)
 	count, err := dbSweeper.Sweep()
 	require.Nil(t, err)
 	require.Equal(t, 1, count)
@@ -190,7 +190,7 @@ func Init(ctx context.Context) error {
 		}
 		options = append(options, BackendOption(lc.Name, lc.Level, lc.Settings))
 		if lc.Sweeper != nil {
-			sOptions = append(sOptions, SweeperOption(lc.Name, lc.Sweeper.Duration, lc.Sweeper.Settings))
+			sOptions = append(sOptions, SweeperOption(lc.Name, lc.Sweeper.Duration, lc.Sweeper.Retention, lc.Sweeper.Settings))
 		}
 	}
 
@@ -207,7 +207,7 @@ func Init(ctx context.Context) error {
 	for _, lc := range config.DefaultConfig.JobLoggerConfigs {
 		jOptions = append(jOptions, BackendOption(lc.Name, lc.Level, lc.Settings))
 		if lc.Sweeper != nil {
-			sOptions = append(sOptions, SweeperOption(lc.Name, lc.Sweeper.Duration, lc.Sweeper.Settings))
+			sOptions = append(sOptions, SweeperOption(lc.Name, lc.Sweeper.Duration, lc.Sweeper.Retention, lc.Sweeper.Settings))
 		}
 	}
 
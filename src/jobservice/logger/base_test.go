@@ -99,14 +99,14 @@ func TestGetSweeper(t *testing.T) {
 		t.Fatalf("expect non nil error but got nil error when getting sweeper with empty settings: %s", "case_7")
 	}
 
-	_, err = GetSweeper(ctx, SweeperOption("STD_OUTPUT", 1, nil))
+	_, err = GetSweeper(ctx, SweeperOption("STD_OUTPUT", 1, nil, nil))
 	if err == nil {
 		t.Fatalf("expect non nil error but got nil error when getting sweeper with name 'STD_OUTPUT': %s", "case_8")
 	}
 
 	sSettings := map[string]interface{}{}
 	sSettings["work_dir"] = os.TempDir()
-	s, err := GetSweeper(ctx, SweeperOption("FILE", 5, sSettings))
+	s, err := GetSweeper(ctx, SweeperOption("FILE", 5, nil, sSettings))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -37,7 +37,7 @@ func TestMain(m *testing.M) {
 func TestEntry(t *testing.T) {
 	var loggers = make([]Interface, 0)
 	uuid := "uuid_for_unit_test"
-	dbl, err := backend.NewDBLogger(uuid, "DEBUG", 4)
+	dbl, err := backend.NewDBLogger(uuid, "", "DEBUG", 4)
 	require.Nil(t, err)
 	loggers = append(loggers, dbl)
 
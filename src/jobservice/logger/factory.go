@@ -66,8 +66,8 @@ func StdFactory(options ...OptionItem) (Interface, error) {
 // DBFactory is factory of file logger
 func DBFactory(options ...OptionItem) (Interface, error) {
 	var (
-		level, key string
-		depth      int
+		level, key, jobName string
+		depth               int
 	)
 	for _, op := range options {
 		switch op.Field() {
@@ -75,6 +75,8 @@ func DBFactory(options ...OptionItem) (Interface, error) {
 			level = op.String()
 		case "key":
 			key = op.String()
+		case "job_name":
+			jobName = op.String()
 		case "depth":
 			depth = op.Int()
 		default:
@@ -85,5 +87,5 @@ func DBFactory(options ...OptionItem) (Interface, error) {
 		return nil, errors.New("missing key option of the db logger")
 	}
 
-	return backend.NewDBLogger(key, level, depth)
+	return backend.NewDBLogger(key, jobName, level, depth)
 }
@@ -1,5 +1,9 @@
 package getter
 
+import (
+	"github.com/goharbor/harbor/src/common/models"
+)
+
 // Interface defines operations of a log data getter
 type Interface interface {
 	// Retrieve the log data of the specified log entry
@@ -9,4 +13,7 @@ type Interface interface {
 	// If succeed, log data bytes will be returned
 	// otherwise, a non nil error is returned
 	Retrieve(logID string) ([]byte, error)
+
+	// Usage returns the count and the total bytes of the logs per job
+	Usage() ([]*models.JobLogUsage, error)
 }
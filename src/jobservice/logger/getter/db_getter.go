@@ -2,7 +2,9 @@ package getter
 
 import (
 	"errors"
+
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
 )
 
 // DBGetter is responsible for retrieving DB log data
@@ -27,3 +29,8 @@ func (dbg *DBGetter) Retrieve(logID string) ([]byte, error) {
 
 	return []byte(jobLog.Content), nil
 }
+
+// Usage implements @Interface.Usage
+func (dbg *DBGetter) Usage() ([]*models.JobLogUsage, error) {
+	return dao.GetJobLogUsage()
+}
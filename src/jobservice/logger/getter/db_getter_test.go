@@ -35,7 +35,7 @@ func TestMain(m *testing.M) {
 // TestDBGetter
 func TestDBGetter(t *testing.T) {
 	uuid := "uuid_for_unit_test_getter"
-	l, err := backend.NewDBLogger(uuid, "DEBUG", 4)
+	l, err := backend.NewDBLogger(uuid, "", "DEBUG", 4)
 	require.Nil(t, err)
 
 	l.Debug("JobLog Debug: TestDBLoggerGetter")
@@ -47,7 +47,7 @@ func TestDBGetter(t *testing.T) {
 	log.Infof("get logger %s", ll)
 
 	sweeper.PrepareDBSweep()
-	dbSweeper := sweeper.NewDBSweeper(-1)
+	dbSweeper := sweeper.NewDBSweeper(-1, nil)
 	count, err := dbSweeper.Sweep()
 	require.Nil(t, err)
 	require.Equal(t, 1, count)
@@ -56,7 +56,7 @@ func TestDBGetter(t *testing.T) {
 // TestDBGetterError
 func TestDBGetterError(t *testing.T) {
 	uuid := "uuid_for_unit_test_getter_error"
-	l, err := backend.NewDBLogger(uuid, "DEBUG", 4)
+	l, err := backend.NewDBLogger(uuid, "", "DEBUG", 4)
 	require.Nil(t, err)
 
 	l.Debug("JobLog Debug: TestDBLoggerGetter")
@@ -69,7 +69,7 @@ func TestDBGetterError(t *testing.T) {
 	require.NotNil(t, err)
 
 	sweeper.PrepareDBSweep()
-	dbSweeper := sweeper.NewDBSweeper(-1)
+	dbSweeper := sweeper.NewDBSweeper(-1, nil)
 	count, err := dbSweeper.Sweep()
 	require.Nil(t, err)
 	require.Equal(t, 1, count)
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"path/filepath"
+	"sort"
 
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/errs"
-
 	"github.com/goharbor/harbor/src/jobservice/utils"
 )
 
@@ -30,8 +32,48 @@ func (fg *FileGetter) Retrieve(logID string) ([]byte, error) {
 	fPath := path.Join(fg.baseDir, fmt.Sprintf("%s.log", logID))
 
 	if !utils.FileExists(fPath) {
-		return nil, errs.NoObjectFoundError(logID)
+		// the name of the job is in the name of the log file
+		matches, err := filepath.Glob(path.Join(fg.baseDir, fmt.Sprintf("%s.*.log", logID)))
+		if err != nil || len(matches) == 0 {
+			return nil, errs.NoObjectFoundError(logID)
+		}
+		fPath = matches[0]
 	}
 
 	return ioutil.ReadFile(fPath)
 }
+
+// Usage implements @Interface.Usage
+func (fg *FileGetter) Usage() ([]*models.JobLogUsage, error) {
+	files, err := ioutil.ReadDir(fg.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	usages := map[string]*models.JobLogUsage{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		_, jobName, ok := utils.ParseJobLogFileName(file.Name())
+		if !ok {
+			continue
+		}
+		usage, exist := usages[jobName]
+		if !exist {
+			usage = &models.JobLogUsage{
+				JobName: jobName,
+			}
+			usages[jobName] = usage
+		}
+		usage.Count++
+		usage.Size += file.Size()
+	}
+	result := make([]*models.JobLogUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].JobName < result[j].JobName
+	})
+	return result, nil
+}
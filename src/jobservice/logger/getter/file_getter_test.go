@@ -39,3 +39,45 @@ func TestLogDataGetter(t *testing.T) {
 		t.Errorf("expect reading 5 bytes but got %d bytes", len(data))
 	}
 }
+
+func TestLogDataGetterWithJobName(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "TestLogDataGetterWithJobName")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(baseDir)
+
+	for name, data := range map[string]string{
+		"a1.IMAGE_SCAN.log":      "hello",
+		"a2.IMAGE_SCAN.log":      "world!",
+		"a3.IMAGE_REPLICATE.log": "hi",
+		"a4.log":                 "old",
+	} {
+		if err := ioutil.WriteFile(path.Join(baseDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fg := NewFileGetter(baseDir)
+	data, err := fg.Retrieve("a1")
+	if err != nil {
+		t.Error(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("expect hello but got %s", data)
+	}
+
+	usages, err := fg.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 3 {
+		t.Fatalf("expect 3 usages but got %d", len(usages))
+	}
+	if usages[0].JobName != "" || usages[0].Count != 1 || usages[0].Size != 3 {
+		t.Errorf("unexpected usage of the former logs: %+v", usages[0])
+	}
+	if usages[2].JobName != "IMAGE_SCAN" || usages[2].Count != 2 || usages[2].Size != 11 {
+		t.Errorf("unexpected usage of IMAGE_SCAN: %+v", usages[2])
+	}
+}
@@ -50,7 +50,7 @@ func TestKnownLoggers(t *testing.T) {
 // Test GetLoggerName
 func TestGetLoggerName(t *testing.T) {
 	uuid := "uuid_for_unit_test"
-	l, err := backend.NewDBLogger(uuid, "DEBUG", 4)
+	l, err := backend.NewDBLogger(uuid, "", "DEBUG", 4)
 	require.Nil(t, err)
 	require.Equal(t, LoggerNameDB, GetLoggerName(l))
 
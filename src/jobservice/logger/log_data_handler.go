@@ -3,6 +3,7 @@ package logger
 import (
 	"errors"
 
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/logger/getter"
 )
 
@@ -16,6 +17,16 @@ func Retrieve(logID string) ([]byte, error) {
 	return val.(getter.Interface).Retrieve(logID)
 }
 
+// Usage is wrapper func for getter.Usage
+func Usage() ([]*models.JobLogUsage, error) {
+	val, ok := singletons.Load(systemKeyLogDataGetter)
+	if !ok {
+		return nil, errors.New("no log data getter is configured")
+	}
+
+	return val.(getter.Interface).Usage()
+}
+
 // HasLogGetterConfigured checks if a log data getter is there for using
 func HasLogGetterConfigured() bool {
 	_, ok := singletons.Load(systemKeyLogDataGetter)
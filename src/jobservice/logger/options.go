@@ -30,11 +30,13 @@ func BackendOption(name string, level string, settings map[string]interface{}) O
 	}}
 }
 
-// SweeperOption creates option for the sweeper.
-func SweeperOption(name string, duration int, settings map[string]interface{}) Option {
+// SweeperOption creates option for the sweeper, the retention is the days to keep
+// the logs of the specific jobs.
+func SweeperOption(name string, duration int, retention map[string]int, settings map[string]interface{}) Option {
 	return Option{func(op *options) {
 		vals := make([]OptionItem, 0)
 		vals = append(vals, OptionItem{"duration", duration})
+		vals = append(vals, OptionItem{"retention", retention})
 
 		// Append settings if existing
 		if len(settings) > 0 {
@@ -93,6 +95,15 @@ func (o *OptionItem) String() string {
 	return o.val.(string)
 }
 
+// IntMap returns the map value of option with int values
+func (o *OptionItem) IntMap() map[string]int {
+	if o.val == nil {
+		return nil
+	}
+
+	return o.val.(map[string]int)
+}
+
 // Raw returns the raw value
 func (o *OptionItem) Raw() interface{} {
 	return o.val
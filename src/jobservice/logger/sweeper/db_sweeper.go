@@ -2,8 +2,9 @@ package sweeper
 
 import (
 	"fmt"
-	"github.com/goharbor/harbor/src/common/dao"
 	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
 )
 
 var dbInit = make(chan int, 1)
@@ -12,12 +13,15 @@ var isDBInit = false
 // DBSweeper is used to sweep the DB logs
 type DBSweeper struct {
 	duration int
+	// the days to keep the logs of the specific jobs, override the duration
+	retention map[string]int
 }
 
 // NewDBSweeper is constructor of DBSweeper
-func NewDBSweeper(duration int) *DBSweeper {
+func NewDBSweeper(duration int, retention map[string]int) *DBSweeper {
 	return &DBSweeper{
-		duration: duration,
+		duration:  duration,
+		retention: retention,
 	}
 }
 
@@ -26,20 +30,33 @@ func (dbs *DBSweeper) Sweep() (int, error) {
 	// DB initialization not completed, waiting
 	WaitingDBInit()
 
+	// Start to sweep logs of the jobs with retention
+	total := 0
+	jobNames := make([]string, 0, len(dbs.retention))
+	for jobName, days := range dbs.retention {
+		jobNames = append(jobNames, jobName)
+		before := time.Now().Add(time.Duration(days) * oneDay * -1)
+		count, err := dao.DeleteJobLogsOfJobBefore(jobName, before)
+		if err != nil {
+			return total, fmt.Errorf("sweep logs of job %s in DB failed before %s with error: %s", jobName, before, err)
+		}
+		total += int(count)
+	}
+
 	// Start to sweep logs
 	before := time.Now().Add(time.Duration(dbs.duration) * oneDay * -1)
-	count, err := dao.DeleteJobLogsBefore(before)
+	count, err := dao.DeleteJobLogsBefore(before, jobNames...)
 
 	if err != nil {
-		return 0, fmt.Errorf("sweep logs in DB failed before %s with error: %s", before, err)
+		return total, fmt.Errorf("sweep logs in DB failed before %s with error: %s", before, err)
 	}
 
-	return int(count), nil
+	return total + int(count), nil
 }
 
 // Duration for sweeping
 func (dbs *DBSweeper) Duration() int {
-	return dbs.duration
+	return shortestDuration(dbs.duration, dbs.retention)
 }
 
 // WaitingDBInit waiting DB init
@@ -34,14 +34,14 @@ func TestMain(m *testing.M) {
 // TestDBGetter
 func TestDBGetter(t *testing.T) {
 	uuid := "uuid_for_unit_test_sweeper"
-	l, err := backend.NewDBLogger(uuid, "DEBUG", 4)
+	l, err := backend.NewDBLogger(uuid, "", "DEBUG", 4)
 	require.Nil(t, err)
 
 	l.Debug("JobLog Debug: TestDBLoggerSweeper")
 	l.Close()
 
 	PrepareDBSweep()
-	dbSweeper := NewDBSweeper(-1)
+	dbSweeper := NewDBSweeper(-1, nil)
 	count, err := dbSweeper.Sweep()
 	require.Nil(t, err)
 	require.Equal(t, 1, count)
@@ -7,6 +7,8 @@ import (
 	"path"
 	"strings"
 	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/utils"
 )
 
 const (
@@ -17,13 +19,16 @@ const (
 type FileSweeper struct {
 	duration int
 	workDir  string
+	// the days to keep the logs of the specific jobs, override the duration
+	retention map[string]int
 }
 
 // NewFileSweeper is constructor of FileSweeper
-func NewFileSweeper(workDir string, duration int) *FileSweeper {
+func NewFileSweeper(workDir string, duration int, retention map[string]int) *FileSweeper {
 	return &FileSweeper{
-		workDir:  workDir,
-		duration: duration,
+		workDir:   workDir,
+		duration:  duration,
+		retention: retention,
 	}
 }
 
@@ -45,7 +50,13 @@ func (fs *FileSweeper) Sweep() (int, error) {
 	// Record all errors
 	errs := make([]string, 0)
 	for _, logFile := range logFiles {
-		if logFile.ModTime().Add(time.Duration(fs.duration) * oneDay).Before(time.Now()) {
+		duration := fs.duration
+		if _, jobName, ok := utils.ParseJobLogFileName(logFile.Name()); ok {
+			if days, exist := fs.retention[jobName]; exist {
+				duration = days
+			}
+		}
+		if logFile.ModTime().Add(time.Duration(duration) * oneDay).Before(time.Now()) {
 			logFilePath := path.Join(fs.workDir, logFile.Name())
 			if err := os.Remove(logFilePath); err != nil {
 				errs = append(errs, fmt.Sprintf("remove log file '%s' error: %s", logFilePath, err))
@@ -66,5 +77,16 @@ func (fs *FileSweeper) Sweep() (int, error) {
 
 // Duration for sweeping
 func (fs *FileSweeper) Duration() int {
-	return fs.duration
+	return shortestDuration(fs.duration, fs.retention)
+}
+
+// shortestDuration returns the shortest one of the duration and the retention of the
+// jobs, the logs are swept in it to keep them no longer than configured
+func shortestDuration(duration int, retention map[string]int) int {
+	for _, days := range retention {
+		if days > 0 && days < duration {
+			duration = days
+		}
+	}
+	return duration
 }
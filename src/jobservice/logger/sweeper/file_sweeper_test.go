@@ -29,7 +29,7 @@ func TestFileSweeper(t *testing.T) {
 		t.Error(err)
 	}
 
-	fs := NewFileSweeper(workDir, 5)
+	fs := NewFileSweeper(workDir, 5, nil)
 	if fs.Duration() != 5 {
 		t.Errorf("expect duration 5 but got %d", fs.Duration())
 	}
@@ -43,3 +43,39 @@ func TestFileSweeper(t *testing.T) {
 		t.Errorf("expect count 1 but got %d", count)
 	}
 }
+
+// Test the retention of the logs of the specific jobs
+func TestFileSweeperWithRetention(t *testing.T) {
+	workDir, err := ioutil.TempDir("", "TestFileSweeperWithRetention")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workDir)
+
+	modTime := time.Unix(time.Now().Unix()-3*24*3600, 0)
+	for _, name := range []string{"a1.IMAGE_SCAN.log", "a2.IMAGE_REPLICATE.log", "a3.log"} {
+		logFile := path.Join(workDir, name)
+		if err := ioutil.WriteFile(logFile, []byte("hello"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(logFile, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewFileSweeper(workDir, 5, map[string]int{"IMAGE_SCAN": 2, "IMAGE_REPLICATE": 30})
+	if fs.Duration() != 2 {
+		t.Errorf("expect duration 2 but got %d", fs.Duration())
+	}
+
+	count, err := fs.Sweep()
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 1 {
+		t.Errorf("expect count 1 but got %d", count)
+	}
+	if _, err := os.Stat(path.Join(workDir, "a1.IMAGE_SCAN.log")); !os.IsNotExist(err) {
+		t.Error("expect the log of IMAGE_SCAN to be swept")
+	}
+}
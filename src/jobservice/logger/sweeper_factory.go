@@ -12,6 +12,7 @@ type SweeperFactory func(options ...OptionItem) (sweeper.Interface, error)
 // FileSweeperFactory creates file sweeper.
 func FileSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
 	var workDir, duration = "", 1
+	var retention map[string]int
 	for _, op := range options {
 		switch op.Field() {
 		case "work_dir":
@@ -20,6 +21,8 @@ func FileSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
 			if op.Int() > 0 {
 				duration = op.Int()
 			}
+		case "retention":
+			retention = op.IntMap()
 		default:
 		}
 	}
@@ -28,21 +31,24 @@ func FileSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
 		return nil, errors.New("missing required option 'work_dir'")
 	}
 
-	return sweeper.NewFileSweeper(workDir, duration), nil
+	return sweeper.NewFileSweeper(workDir, duration, retention), nil
 }
 
 // DBSweeperFactory creates DB sweeper.
 func DBSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
 	var duration = 1
+	var retention map[string]int
 	for _, op := range options {
 		switch op.Field() {
 		case "duration":
 			if op.Int() > 0 {
 				duration = op.Int()
 			}
+		case "retention":
+			retention = op.IntMap()
 		default:
 		}
 	}
 
-	return sweeper.NewDBSweeper(duration), nil
+	return sweeper.NewDBSweeper(duration, retention), nil
 }
@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/env"
+)
+
+// QueueHandler handles the job fetched from the queue, deliveries is the times the job
+// has been delivered, it's larger than 1 if the job is recovered from the worker which
+// exited without acknowledging it. The job is kept in the queue and delivered again
+// if the handler returns error.
+type QueueHandler func(j *work.Job, deliveries int64) error
+
+// Queue is the pluggable backend the generic jobs are enqueued into and fetched from.
+// The scheduled, periodic and retrying jobs are always kept in the lists of gocraft/work.
+type Queue interface {
+	// Enqueue the job
+	//
+	// j *work.Job : the job to enqueue
+	//
+	// Return:
+	//  error if failed to enqueue
+	Enqueue(j *work.Job) error
+
+	// Start to fetch the jobs and pass them to the handler, the job is acknowledged
+	// only after the handler returns nil.
+	// Unblock action, the fetching stops when the system context is done.
+	//
+	// ctx *env.Context     : the context of the job service
+	// handler QueueHandler : the handler of the fetched jobs
+	//
+	// Return:
+	//  error if failed to start
+	Start(ctx *env.Context, handler QueueHandler) error
+}
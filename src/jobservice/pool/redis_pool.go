@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"reflect"
 	"strings"
 	"time"
@@ -45,6 +46,12 @@ const (
 	periodicEnqueuerHorizon = 4 * time.Minute
 
 	pingRedisMaxTimes = 10
+
+	// Default max fails of the job, consistent with gocraft/work
+	defaultMaxFails = 4
+	// The job delivered more times than it by the queue is treated as the one
+	// crashing the workers and not run again
+	maxQueuedJobDeliveries = 3
 )
 
 // GoCraftWorkPool is the pool implementation based on gocraft/work powered by redis.
@@ -60,11 +67,17 @@ type GoCraftWorkPool struct {
 	statsManager  opm.JobStatsManager
 	messageServer *MessageServer
 	deDuplicator  DeDuplicator
+	// the pluggable queue of the generic jobs, the list of gocraft/work is used if it's nil
+	queue Queue
 
 	// no need to sync as write once and then only read
 	// key is name of known job
 	// value is the type of known job
 	knownJobs map[string]interface{}
+	// key is name of known job
+	// value is the handler and max fails of the job when it's fetched from the queue
+	queuedJobHandlers map[string]func(job *work.Job) error
+	queuedJobMaxFails map[string]int64
 }
 
 // RedisPoolContext ...
@@ -94,9 +107,17 @@ func NewGoCraftWorkPool(ctx *env.Context, namespace string, workerCount uint, re
 		knownJobs:     make(map[string]interface{}),
 		messageServer: msgServer,
 		deDuplicator:  deDepulicator,
+
+		queuedJobHandlers: make(map[string]func(job *work.Job) error),
+		queuedJobMaxFails: make(map[string]int64),
 	}
 }
 
+// SetQueue plugs the queue of the generic jobs, it should be called before starting the pool.
+func (gcwp *GoCraftWorkPool) SetQueue(queue Queue) {
+	gcwp.queue = queue
+}
+
 // Start to serve
 // Unblock action
 func (gcwp *GoCraftWorkPool) Start() error {
@@ -112,6 +133,13 @@ func (gcwp *GoCraftWorkPool) Start() error {
 		return err
 	}
 
+	// Start the plugged queue of the generic jobs
+	if gcwp.queue != nil {
+		if err := gcwp.queue.Start(gcwp.context, gcwp.handleQueuedJob); err != nil {
+			return err
+		}
+	}
+
 	done := make(chan interface{}, 1)
 
 	gcwp.context.WG.Add(1)
@@ -251,6 +279,11 @@ func (gcwp *GoCraftWorkPool) RegisterJob(name string, j interface{}) error {
 		}, // Use generic handler to handle as we do not accept context with this way.
 	)
 	gcwp.knownJobs[name] = j // keep the name of registered jobs as known jobs for future validation
+	gcwp.queuedJobHandlers[name] = redisJob.Run
+	gcwp.queuedJobMaxFails[name] = int64(theJ.MaxFails())
+	if gcwp.queuedJobMaxFails[name] == 0 {
+		gcwp.queuedJobMaxFails[name] = defaultMaxFails
+	}
 
 	logger.Infof("Register job %s with name %s", reflect.TypeOf(j).String(), name)
 
@@ -288,12 +321,22 @@ func (gcwp *GoCraftWorkPool) Enqueue(jobName string, params models.Parameters, i
 			return models.JobStats{}, err
 		}
 
-		if j, err = gcwp.enqueuer.EnqueueUnique(jobName, params); err != nil {
+		if gcwp.queue != nil {
+			j, err = gcwp.enqueueToQueue(jobName, params, true)
+		} else {
+			j, err = gcwp.enqueuer.EnqueueUnique(jobName, params)
+		}
+		if err != nil {
 			return models.JobStats{}, err
 		}
 	} else {
 		// Enqueue job
-		if j, err = gcwp.enqueuer.Enqueue(jobName, params); err != nil {
+		if gcwp.queue != nil {
+			j, err = gcwp.enqueueToQueue(jobName, params, false)
+		} else {
+			j, err = gcwp.enqueuer.Enqueue(jobName, params)
+		}
+		if err != nil {
 			return models.JobStats{}, err
 		}
 	}
@@ -695,6 +738,78 @@ func (gcwp *GoCraftWorkPool) handleOPCommandFiring(data interface{}) error {
 	return gcwp.statsManager.SendCommand(jobID, command, true)
 }
 
+// Enqueue the job to the plugged queue, the uniqueness is guaranteed by the de-duplicator
+func (gcwp *GoCraftWorkPool) enqueueToQueue(jobName string, params models.Parameters, isUnique bool) (*work.Job, error) {
+	if _, ok := gcwp.knownJobs[jobName]; !ok {
+		return nil, fmt.Errorf("job '%s' is not registered", jobName)
+	}
+
+	j := &work.Job{
+		Name:       jobName,
+		ID:         utils.MakeIdentifier(),
+		EnqueuedAt: time.Now().Unix(),
+		Args:       params,
+		Unique:     isUnique,
+	}
+	if err := gcwp.queue.Enqueue(j); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Handle the job fetched from the plugged queue, the failed job is moved into the retry or dead
+// set of gocraft/work, so that it's retried and managed as the ones of the list-based queue
+func (gcwp *GoCraftWorkPool) handleQueuedJob(j *work.Job, deliveries int64) error {
+	maxFails, ok := gcwp.queuedJobMaxFails[j.Name]
+	if !ok {
+		maxFails = defaultMaxFails
+	}
+
+	var err error
+	if deliveries > maxQueuedJobDeliveries {
+		// The job may crash the workers, do not retry it any more
+		err = fmt.Errorf("the job is interrupted %d times", deliveries-1)
+		j.Fails = maxFails
+		gcwp.statsManager.SetJobStatus(j.ID, job.JobStatusError)
+		gcwp.statsManager.DieAt(j.ID, time.Now().Unix())
+		if j.Unique {
+			if e := gcwp.deDuplicator.DelUniqueSign(j.Name, j.Args); e != nil {
+				logger.Errorf("delete job unique sign error: %s", e)
+			}
+		}
+	} else if run, ok := gcwp.queuedJobHandlers[j.Name]; !ok {
+		err = fmt.Errorf("job '%s' is not registered", j.Name)
+		j.Fails = maxFails
+	} else {
+		logger.Infof("Job incoming: %s:%s", j.Name, j.ID)
+		if err = run(j); err == nil {
+			return nil
+		}
+		j.Fails++
+	}
+
+	j.LastErr = err.Error()
+	j.FailedAt = time.Now().Unix()
+	data, err := utils.SerializeJob(j)
+	if err != nil {
+		return err
+	}
+
+	conn := gcwp.redisPool.Get()
+	defer conn.Close()
+
+	if j.Fails < maxFails {
+		// Same backoff with gocraft/work
+		backoff := (j.Fails * j.Fails * j.Fails * j.Fails) + 15 + (rand.Int63n(30) * (j.Fails + 1))
+		_, err = conn.Do("ZADD", utils.RedisKeyRetry(gcwp.namespace), j.FailedAt+backoff, data)
+	} else {
+		_, err = conn.Do("ZADD", utils.RedisKeyDead(gcwp.namespace), j.FailedAt, data)
+	}
+
+	return err
+}
+
 // log the job
 func (rpc *RedisPoolContext) logJob(job *work.Job, next work.NextMiddlewareFunc) error {
 	logger.Infof("Job incoming: %s:%s", job.Name, job.ID)
@@ -0,0 +1,450 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/utils"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// the consumer group shared by all the worker pools
+	streamConsumerGroup = "workers"
+	// the field of the stream entry keeping the serialized job
+	streamJobField = "job"
+	// the max count of the pending entries checked in one recovering
+	streamRecoverBatch = 100
+)
+
+var (
+	// the max time of blocking when waiting for the new entries
+	streamBlockTime = time.Second
+	// the interval of renewing the entries of the running jobs, so that they're not
+	// recovered by the other worker pools
+	streamRenewInterval = 30 * time.Second
+	// the pending entries idle longer than it are treated as the ones of the crashed workers
+	streamRecoverIdleTime = 3 * streamRenewInterval
+	// the interval of checking the pending entries to recover
+	streamRecoverInterval = time.Minute
+	// the waiting time after failing to access the stream
+	streamRetryInterval = 5 * time.Second
+)
+
+// RedisStreamQueue is the queue based on the redis streams (redis 5.0+).
+// All the worker pools consume the stream as one consumer group, the entry is deleted
+// once the job is acknowledged, and the pending entries left by the crashed workers are
+// claimed and delivered again by the living ones.
+type RedisStreamQueue struct {
+	namespace string
+	redisPool *redis.Pool
+	consumer  string
+	// the tokens limit the count of the running jobs
+	slots chan struct{}
+}
+
+// streamEntry is the entry read from the stream, the job is nil if the
+// entry has been deleted or is malformed
+type streamEntry struct {
+	id         string
+	job        *work.Job
+	deliveries int64
+}
+
+// NewRedisStreamQueue is constructor of RedisStreamQueue.
+func NewRedisStreamQueue(namespace string, concurrency uint, redisPool *redis.Pool) *RedisStreamQueue {
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	return &RedisStreamQueue{
+		namespace: namespace,
+		redisPool: redisPool,
+		consumer:  utils.MakeIdentifier(),
+		slots:     make(chan struct{}, concurrency),
+	}
+}
+
+// Enqueue job
+func (q *RedisStreamQueue) Enqueue(j *work.Job) error {
+	if j == nil {
+		return errors.New("nil job")
+	}
+
+	data, err := utils.SerializeJob(j)
+	if err != nil {
+		return err
+	}
+
+	conn := q.redisPool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("XADD", q.key(), "*", streamJobField, data)
+	return err
+}
+
+// Start to fetch jobs
+// Unblock action
+func (q *RedisStreamQueue) Start(ctx *env.Context, handler QueueHandler) error {
+	if handler == nil {
+		return errors.New("nil queue handler")
+	}
+
+	if err := q.createGroup(); err != nil {
+		return err
+	}
+
+	ctx.WG.Add(1)
+	go func() {
+		running := &sync.WaitGroup{}
+		defer func() {
+			// The jobs not finished are left pending and recovered later
+			running.Wait()
+			ctx.WG.Done()
+			logger.Infof("Redis stream queue is stopped")
+		}()
+
+		q.serve(ctx.SystemContext, handler, running)
+	}()
+
+	logger.Infof("Redis stream queue is started with consumer %s", q.consumer)
+
+	return nil
+}
+
+func (q *RedisStreamQueue) serve(ctx context.Context, handler QueueHandler, running *sync.WaitGroup) {
+	// Recover the pending entries once started
+	lastRecovered := time.Time{}
+
+	for {
+		n := q.acquire(ctx)
+		if n == 0 {
+			return
+		}
+
+		var (
+			entries []*streamEntry
+			err     error
+		)
+		if time.Since(lastRecovered) >= streamRecoverInterval {
+			lastRecovered = time.Now()
+			entries, err = q.claim(n)
+		} else {
+			entries, err = q.read(n)
+		}
+		q.release(n - len(entries))
+
+		if err != nil {
+			logger.Errorf("Fetch jobs from redis stream failed with error: %s", err)
+			// The group is lost if the stream is removed
+			if strings.Contains(err.Error(), "NOGROUP") {
+				if err := q.createGroup(); err != nil {
+					logger.Errorf("Create consumer group of redis stream failed with error: %s", err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(streamRetryInterval):
+			}
+			continue
+		}
+
+		for _, e := range entries {
+			running.Add(1)
+			go q.run(e, handler, running)
+		}
+	}
+}
+
+// run the job of the entry and acknowledge it if the job is handled
+func (q *RedisStreamQueue) run(e *streamEntry, handler QueueHandler, running *sync.WaitGroup) {
+	defer func() {
+		q.release(1)
+		running.Done()
+	}()
+
+	if e.job != nil {
+		done := make(chan struct{})
+		go q.renew(e.id, done)
+		err := handler(e.job, e.deliveries)
+		close(done)
+
+		if err != nil {
+			logger.Errorf("Job '%s:%s' is kept pending in redis stream: %s", e.job.Name, e.job.ID, err)
+			return
+		}
+	}
+
+	if err := q.ack(e.id); err != nil {
+		logger.Errorf("Acknowledge entry %s of redis stream failed with error: %s", e.id, err)
+	}
+}
+
+// renew the entry of the running job until done
+func (q *RedisStreamQueue) renew(id string, done chan struct{}) {
+	ticker := time.NewTicker(streamRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			conn := q.redisPool.Get()
+			// Claiming by itself resets the idle time of the entry
+			_, err := conn.Do("XCLAIM", q.key(), streamConsumerGroup, q.consumer, 0, id, "JUSTID")
+			conn.Close()
+			if err != nil {
+				logger.Errorf("Renew entry %s of redis stream failed with error: %s", id, err)
+			}
+		}
+	}
+}
+
+// read the new entries
+func (q *RedisStreamQueue) read(count int) ([]*streamEntry, error) {
+	conn := q.redisPool.Get()
+	defer conn.Close()
+
+	reply, err := conn.Do("XREADGROUP", "GROUP", streamConsumerGroup, q.consumer,
+		"COUNT", count, "BLOCK", int64(streamBlockTime/time.Millisecond), "STREAMS", q.key(), ">")
+	if err != nil {
+		return nil, err
+	}
+	// Timeout
+	if reply == nil {
+		return nil, nil
+	}
+
+	streams, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*streamEntry{}
+	for _, s := range streams {
+		stream, err := redis.Values(s, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(stream) != 2 {
+			return nil, fmt.Errorf("malformed stream reply: %v", stream)
+		}
+		es, err := parseStreamEntries(stream[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, es...)
+	}
+
+	return entries, nil
+}
+
+// claim the entries left pending by the crashed workers
+func (q *RedisStreamQueue) claim(count int) ([]*streamEntry, error) {
+	conn := q.redisPool.Get()
+	defer conn.Close()
+
+	pendings, err := redis.Values(conn.Do("XPENDING", q.key(), streamConsumerGroup, "-", "+", streamRecoverBatch))
+	if err != nil {
+		return nil, err
+	}
+
+	minIdle := int64(streamRecoverIdleTime / time.Millisecond)
+	deliveries := make(map[string]int64)
+	args := []interface{}{q.key(), streamConsumerGroup, q.consumer, minIdle}
+	for _, p := range pendings {
+		if len(deliveries) >= count {
+			break
+		}
+
+		// [ID, consumer, idle time, delivery count]
+		var (
+			id, consumer string
+			idle, times  int64
+		)
+		fields, err := redis.Values(p, nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := redis.Scan(fields, &id, &consumer, &idle, &times); err != nil {
+			return nil, err
+		}
+		if idle < minIdle {
+			continue
+		}
+
+		deliveries[id] = times
+		args = append(args, id)
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	// The entries renewed or claimed by others meanwhile are excluded by the min idle time.
+	// Unlike the renewing, the entries are claimed without "JUSTID", so that the delivery
+	// counts are incremented and the jobs crashing the workers can be stopped
+	reply, err := redis.Values(conn.Do("XCLAIM", args...))
+	if err != nil {
+		return nil, err
+	}
+	// The deleted entries are replied as nil by the old versions of redis
+	claimed := []interface{}{}
+	for _, r := range reply {
+		if r != nil {
+			claimed = append(claimed, r)
+		}
+	}
+	entries, err := parseStreamEntries(claimed, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		e.deliveries = deliveries[e.id] + 1
+		delete(deliveries, e.id)
+		logger.Infof("Recover entry %s of redis stream, delivered %d times", e.id, e.deliveries-1)
+	}
+
+	// The left ones are either claimed by others or deleted, the deleted ones are
+	// acknowledged by running them without jobs
+	for id := range deliveries {
+		es, err := parseStreamEntries(conn.Do("XRANGE", q.key(), id, id))
+		if err != nil {
+			return nil, err
+		}
+		if len(es) == 0 {
+			entries = append(entries, &streamEntry{id: id})
+		}
+	}
+
+	return entries, nil
+}
+
+// ack the entry and delete it from the stream
+func (q *RedisStreamQueue) ack(id string) error {
+	conn := q.redisPool.Get()
+	defer conn.Close()
+
+	if err := conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err := conn.Send("XACK", q.key(), streamConsumerGroup, id); err != nil {
+		return err
+	}
+	if err := conn.Send("XDEL", q.key(), id); err != nil {
+		return err
+	}
+	_, err := conn.Do("EXEC")
+
+	return err
+}
+
+func (q *RedisStreamQueue) createGroup() error {
+	conn := q.redisPool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("XGROUP", "CREATE", q.key(), streamConsumerGroup, "0", "MKSTREAM")
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	return nil
+}
+
+// acquire the free slots, it blocks until at least one slot is free
+// or returns 0 if the context is done
+func (q *RedisStreamQueue) acquire(ctx context.Context) int {
+	select {
+	case <-ctx.Done():
+		return 0
+	case q.slots <- struct{}{}:
+	}
+
+	n := 1
+	for n < cap(q.slots) {
+		select {
+		case q.slots <- struct{}{}:
+			n++
+		default:
+			return n
+		}
+	}
+
+	return n
+}
+
+func (q *RedisStreamQueue) release(n int) {
+	for i := 0; i < n; i++ {
+		<-q.slots
+	}
+}
+
+func (q *RedisStreamQueue) key() string {
+	return fmt.Sprintf("%s%s", utils.KeyNamespacePrefix(q.namespace), "stream:jobs")
+}
+
+// parseStreamEntries parses the entries replied by XRANGE and XREADGROUP:
+// [[ID, [field, value, ...]], ...]
+func parseStreamEntries(reply interface{}, err error) ([]*streamEntry, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*streamEntry{}
+	for _, v := range values {
+		entry, err := redis.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(entry) != 2 {
+			return nil, fmt.Errorf("malformed stream entry: %v", entry)
+		}
+		id, err := redis.String(entry[0], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &streamEntry{
+			id:         id,
+			deliveries: 1,
+		}
+		// The fields are nil if the entry is deleted
+		if entry[1] != nil {
+			fields, err := redis.StringMap(entry[1], nil)
+			if err != nil {
+				return nil, err
+			}
+			if e.job, err = utils.DeSerializeJob([]byte(fields[streamJobField])); err != nil {
+				logger.Errorf("Malformed job in entry %s of redis stream: %s", id, err)
+				e.job = nil
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,174 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/tests"
+	"github.com/goharbor/harbor/src/jobservice/utils"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStreamEntries(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{[]byte("1-0"), []interface{}{[]byte("job"), []byte(`{"name":"fake_job","id":"abc","t":1}`)}},
+		[]interface{}{[]byte("2-0"), nil},
+		[]interface{}{[]byte("3-0"), []interface{}{[]byte("job"), []byte("malformed")}},
+	}
+	entries, err := parseStreamEntries(reply, nil)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(entries))
+	assert.Equal(t, "1-0", entries[0].id)
+	assert.Equal(t, int64(1), entries[0].deliveries)
+	require.NotNil(t, entries[0].job)
+	assert.Equal(t, "fake_job", entries[0].job.Name)
+	assert.Equal(t, "abc", entries[0].job.ID)
+	assert.Nil(t, entries[1].job)
+	assert.Nil(t, entries[2].job)
+
+	_, err = parseStreamEntries([]interface{}{[]interface{}{[]byte("1-0")}}, nil)
+	assert.NotNil(t, err)
+	_, err = parseStreamEntries(nil, errors.New("error"))
+	assert.NotNil(t, err)
+}
+
+func TestRedisStreamQueue(t *testing.T) {
+	defer func() {
+		if err := tests.ClearAll(tests.GiveMeTestNamespace(), redisPool.Get()); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	envCtx := &env.Context{
+		SystemContext: ctx,
+		WG:            new(sync.WaitGroup),
+		ErrorChan:     make(chan error, 1),
+	}
+
+	q := NewRedisStreamQueue(tests.GiveMeTestNamespace(), 2, rPool)
+	handled := make(chan *work.Job, 1)
+	require.Nil(t, q.Start(envCtx, func(j *work.Job, deliveries int64) error {
+		handled <- j
+		return nil
+	}))
+
+	require.Nil(t, q.Enqueue(&work.Job{
+		Name: "fake_job",
+		ID:   "stream_job",
+		Args: map[string]interface{}{"name": "testing:v1"},
+	}))
+	select {
+	case j := <-handled:
+		assert.Equal(t, "stream_job", j.ID)
+		assert.Equal(t, "testing:v1", j.Args["name"])
+	case <-time.After(10 * time.Second):
+		t.Error("expect the job to be handled but timeout")
+	}
+
+	cancel()
+	envCtx.WG.Wait()
+}
+
+func TestRedisStreamQueueRecover(t *testing.T) {
+	defer func() {
+		if err := tests.ClearAll(tests.GiveMeTestNamespace(), redisPool.Get()); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	idleTime := streamRecoverIdleTime
+	streamRecoverIdleTime = 0
+	defer func() {
+		streamRecoverIdleTime = idleTime
+	}()
+
+	crashed := NewRedisStreamQueue(tests.GiveMeTestNamespace(), 1, rPool)
+	require.Nil(t, crashed.createGroup())
+	require.Nil(t, crashed.Enqueue(&work.Job{
+		Name: "fake_job",
+		ID:   "interrupted_job",
+	}))
+	// Read without acknowledging as the worker crashes
+	entries, err := crashed.read(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(entries))
+
+	living := NewRedisStreamQueue(tests.GiveMeTestNamespace(), 1, rPool)
+	entries, err = living.claim(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "interrupted_job", entries[0].job.ID)
+	assert.Equal(t, int64(2), entries[0].deliveries)
+
+	require.Nil(t, living.ack(entries[0].id))
+	entries, err = living.claim(1)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(entries))
+}
+
+func TestRedisStreamQueueRedelivery(t *testing.T) {
+	defer func() {
+		if err := tests.ClearAll(tests.GiveMeTestNamespace(), redisPool.Get()); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	idleTime := streamRecoverIdleTime
+	streamRecoverIdleTime = 0
+	defer func() {
+		streamRecoverIdleTime = idleTime
+	}()
+
+	wp, _, cancel := createRedisWorkerPool()
+	defer cancel()
+
+	q := NewRedisStreamQueue(tests.GiveMeTestNamespace(), 1, rPool)
+	require.Nil(t, q.createGroup())
+	require.Nil(t, q.Enqueue(&work.Job{
+		Name: "fake_job",
+		ID:   "crashing_job",
+	}))
+	entries, err := q.read(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, int64(1), entries[0].deliveries)
+
+	// The job crashes the worker every time it's delivered
+	for i := 2; i <= maxQueuedJobDeliveries+1; i++ {
+		entries, err = q.claim(1)
+		require.Nil(t, err)
+		require.Equal(t, 1, len(entries))
+		require.NotNil(t, entries[0].job)
+		assert.Equal(t, int64(i), entries[0].deliveries)
+	}
+
+	// The job isn't run any more but moved into the dead set
+	require.Nil(t, wp.handleQueuedJob(entries[0].job, entries[0].deliveries))
+	conn := rPool.Get()
+	defer conn.Close()
+	dead, err := redis.Int(conn.Do("ZCARD", utils.RedisKeyDead(tests.GiveMeTestNamespace())))
+	require.Nil(t, err)
+	assert.Equal(t, 1, dead)
+}
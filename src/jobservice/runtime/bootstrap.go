@@ -24,15 +24,23 @@ import (
 	"time"
 
 	"github.com/goharbor/harbor/src/common/job"
+	common_redis "github.com/goharbor/harbor/src/common/utils/redis"
 	"github.com/goharbor/harbor/src/jobservice/api"
 	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/core"
 	"github.com/goharbor/harbor/src/jobservice/env"
 	jsjob "github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/job/impl"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/chartgc"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/coretask"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/gc"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/keyrotation"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/rebuild"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/reconcile"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/replication"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/report"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/scan"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/storagetransition"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/jobservice/models"
 	"github.com/goharbor/harbor/src/jobservice/pool"
@@ -172,32 +180,50 @@ func (bs *Bootstrap) loadAndRunAPIServer(ctx *env.Context, cfg *config.Configura
 
 // Load and run the worker pool
 func (bs *Bootstrap) loadAndRunRedisWorkerPool(ctx *env.Context, cfg *config.Configuration) (pool.Interface, error) {
+	redisOpts, err := common_redis.ParseURL(cfg.PoolConfig.RedisPoolCfg.RedisURL)
+	if err != nil {
+		return nil, err
+	}
+	// All the keys of the worker pool contain the hash tag of namespace, so the pool
+	// can work with the redis cluster as long as it connects to the owner of the tag
+	namespace := fmt.Sprintf("{%s}", cfg.PoolConfig.RedisPoolCfg.Namespace)
+	hashTag := cfg.PoolConfig.RedisPoolCfg.Namespace
+	if redisOpts.Mode == common_redis.ModeCluster {
+		if namespace, hashTag, err = common_redis.ClusterNamespace(cfg.PoolConfig.RedisPoolCfg.Namespace); err != nil {
+			return nil, err
+		}
+	}
 	redisPool := &redis.Pool{
 		MaxActive: 6,
 		MaxIdle:   6,
 		Wait:      true,
 		Dial: func() (redis.Conn, error) {
-			return redis.DialURL(
-				cfg.PoolConfig.RedisPoolCfg.RedisURL,
+			options := []redis.DialOption{
 				redis.DialConnectTimeout(dialConnectionTimeout),
 				redis.DialReadTimeout(dialReadTimeout),
 				redis.DialWriteTimeout(dialWriteTimeout),
-			)
+			}
+			if redisOpts.Mode == common_redis.ModeStandalone {
+				return redis.DialURL(cfg.PoolConfig.RedisPoolCfg.RedisURL, options...)
+			}
+			return common_redis.Dial(redisOpts, hashTag, options...)
 		},
 		TestOnBorrow: func(c redis.Conn, t time.Time) error {
 			if time.Since(t) < time.Minute {
 				return nil
 			}
 
-			_, err := c.Do("PING")
-			return err
+			return common_redis.TestConn(redisOpts, c, hashTag)
 		},
 	}
 
 	redisWorkerPool := pool.NewGoCraftWorkPool(ctx,
-		fmt.Sprintf("{%s}", cfg.PoolConfig.RedisPoolCfg.Namespace),
+		namespace,
 		cfg.PoolConfig.WorkerCount,
 		redisPool)
+	if cfg.PoolConfig.RedisPoolCfg.Queue == config.JobServiceRedisQueueStream {
+		redisWorkerPool.SetQueue(pool.NewRedisStreamQueue(namespace, cfg.PoolConfig.WorkerCount, redisPool))
+	}
 	// Register jobs here
 	if err := redisWorkerPool.RegisterJob(impl.KnownJobDemo, (*impl.DemoJob)(nil)); err != nil {
 		// exit
@@ -205,12 +231,20 @@ func (bs *Bootstrap) loadAndRunRedisWorkerPool(ctx *env.Context, cfg *config.Con
 	}
 	if err := redisWorkerPool.RegisterJobs(
 		map[string]interface{}{
-			job.ImageScanJob:    (*scan.ClairJob)(nil),
-			job.ImageScanAllJob: (*scan.All)(nil),
-			job.ImageTransfer:   (*replication.Transfer)(nil),
-			job.ImageDelete:     (*replication.Deleter)(nil),
-			job.ImageReplicate:  (*replication.Replicator)(nil),
-			job.ImageGC:         (*gc.GarbageCollector)(nil),
+			job.ImageScanJob:          (*scan.ClairJob)(nil),
+			job.ImageScanAllJob:       (*scan.All)(nil),
+			job.SeverityRecalculation: (*scan.SeverityRecalculation)(nil),
+			job.ImageTransfer:         (*replication.Transfer)(nil),
+			job.ImageDelete:           (*replication.Deleter)(nil),
+			job.ImageReplicate:        (*replication.Replicator)(nil),
+			job.ImageGC:               (*gc.GarbageCollector)(nil),
+			job.ChartGC:               (*chartgc.ChartGarbageCollector)(nil),
+			job.RebuildIndex:          (*rebuild.Rebuilder)(nil),
+			job.StorageTransition:     (*storagetransition.Transitioner)(nil),
+			job.Reconciliation:        (*reconcile.Reconciler)(nil),
+			job.SecretReencryption:    (*keyrotation.Reencryptor)(nil),
+			job.ProjectReport:         (*report.Reporter)(nil),
+			job.CoreTask:              (*coretask.Runner)(nil),
 		}); err != nil {
 		// exit
 		return nil, err
@@ -61,6 +61,11 @@ func RedisKeyDead(namespace string) string {
 	return RedisNamespacePrefix(namespace) + "dead"
 }
 
+// RedisKeyRetry returns key of the jobs waiting for retrying.
+func RedisKeyRetry(namespace string) string {
+	return RedisNamespacePrefix(namespace) + "retry"
+}
+
 // SerializeJob encodes work.Job to json data.
 func SerializeJob(job *work.Job) ([]byte, error) {
 	return json.Marshal(job)
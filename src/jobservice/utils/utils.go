@@ -75,6 +75,28 @@ func DirExists(path string) bool {
 	return f.IsDir()
 }
 
+// JobLogFileName returns the name of the log file of the job, the name of the job is kept
+// in it for the retention of the logs, e.g. "<job ID>.IMAGE_SCAN.log"
+func JobLogFileName(jobID, jobName string) string {
+	if len(jobName) == 0 || strings.ContainsAny(jobName, "./") {
+		return jobID + ".log"
+	}
+	return jobID + "." + jobName + ".log"
+}
+
+// ParseJobLogFileName returns the ID and the name of the job from the name of the log
+// file, the name of the job is empty for the logs of the former versions
+func ParseJobLogFileName(fileName string) (string, string, bool) {
+	if !strings.HasSuffix(fileName, ".log") {
+		return "", "", false
+	}
+	base := strings.TrimSuffix(fileName, ".log")
+	if i := strings.Index(base, "."); i >= 0 {
+		return base[:i], base[i+1:], true
+	}
+	return base, "", true
+}
+
 // IsValidPort check if port is valid.
 func IsValidPort(port uint) bool {
 	return port != 0 && port < 65536
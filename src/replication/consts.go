@@ -12,6 +12,18 @@ const (
 
 	// AdaptorKindHarbor : Kind of adaptor of Harbor
 	AdaptorKindHarbor = "Harbor"
+	// AdaptorKindFile : Kind of adaptor of the offline bundle file
+	AdaptorKindFile = "File"
+	// AdaptorKindGeneric : Kind of adaptor of the generic Docker Registry v2 endpoint
+	AdaptorKindGeneric = "Generic"
+	// AdaptorKindQuay : Kind of adaptor of Quay
+	AdaptorKindQuay = "Quay"
+	// AdaptorKindArtifactory : Kind of adaptor of JFrog Artifactory
+	AdaptorKindArtifactory = "Artifactory"
+	// AdaptorKindGitHub : Kind of adaptor of the container registry of GitHub
+	AdaptorKindGitHub = "GitHub"
+	// AdaptorKindGitLab : Kind of adaptor of the container registry of GitLab
+	AdaptorKindGitLab = "GitLab"
 
 	// TriggerKindImmediate : Kind of trigger is 'Immediate'
 	TriggerKindImmediate = "Immediate"
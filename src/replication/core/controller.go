@@ -21,11 +21,14 @@ import (
 
 	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/egress"
 	"github.com/goharbor/harbor/src/core/utils"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/models"
 	"github.com/goharbor/harbor/src/replication/policy"
 	"github.com/goharbor/harbor/src/replication/replicator"
+	"github.com/goharbor/harbor/src/replication/robot"
 	"github.com/goharbor/harbor/src/replication/source"
 	"github.com/goharbor/harbor/src/replication/target"
 	"github.com/goharbor/harbor/src/replication/trigger"
@@ -38,6 +41,7 @@ type Controller interface {
 	policy.Manager
 	Init() error
 	Replicate(policyID int64, metadata ...map[string]interface{}) error
+	ReplicateArtifacts(targetID int64, candidates []models.FilterItem, opUUID string) error
 }
 
 // DefaultController is core module to cordinate and control the overall workflow of the
@@ -224,6 +228,10 @@ func (ctl *DefaultController) Replicate(policyID int64, metadata ...map[string]i
 		targets = append(targets, target)
 	}
 
+	if policy.ReplicateRobots {
+		replicateRobots(&policy, targets)
+	}
+
 	// Get operation uuid from metadata, if none provided, generate one.
 	opUUID, err := getOpUUID(metadata...)
 	if err != nil {
@@ -239,6 +247,46 @@ func (ctl *DefaultController) Replicate(policyID int64, metadata ...map[string]i
 	})
 }
 
+// ReplicateArtifacts replicates the specified artifacts to the target directly, the
+// filters of policies aren't applied and the jobs don't belong to any policy
+func (ctl *DefaultController) ReplicateArtifacts(targetID int64, candidates []models.FilterItem, opUUID string) error {
+	target, err := ctl.targetManager.GetTarget(targetID)
+	if err != nil {
+		return err
+	}
+
+	return ctl.replicator.Replicate(&replicator.Replication{
+		OpUUID:     opUUID,
+		Candidates: candidates,
+		Targets:    []*common_models.RepTarget{target},
+	})
+}
+
+// replicateRobots replicates the robots of the projects of the policy to the targets, the
+// failures are logged rather than failing the replication of the images
+func replicateRobots(policy *models.ReplicationPolicy, targets []*common_models.RepTarget) {
+	for _, projectID := range policy.ProjectIDs {
+		project, err := config.GlobalProjectMgr.Get(projectID)
+		if err != nil {
+			log.Errorf("failed to get project %d: %v", projectID, err)
+			continue
+		}
+		if project == nil {
+			log.Warningf("project %d not found, skip replicating its robots", projectID)
+			continue
+		}
+		for _, target := range targets {
+			if err = egress.Check(target.URL, ""); err != nil {
+				log.Errorf("failed to replicate the robots of project %s to target %s: %v", project.Name, target.Name, err)
+				continue
+			}
+			if err = robot.Replicate(target, project, policy.ReplicateDeletion); err != nil {
+				log.Errorf("failed to replicate the robots of project %s to target %s: %v", project.Name, target.Name, err)
+			}
+		}
+	}
+}
+
 func getCandidates(policy *models.ReplicationPolicy, sourcer *source.Sourcer,
 	metadata ...map[string]interface{}) []models.FilterItem {
 	candidates := []models.FilterItem{}
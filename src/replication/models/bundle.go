@@ -0,0 +1,56 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// BundleMetadata is the content of the metadata file in the offline bundle, which
+// describes the projects, images and charts exported
+type BundleMetadata struct {
+	Version   string           `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Projects  []*BundleProject `json:"projects"`
+}
+
+// BundleProject is a project in the offline bundle
+type BundleProject struct {
+	Name         string              `json:"name"`
+	Metadata     map[string]string   `json:"metadata"`
+	Repositories []*BundleRepository `json:"repositories"`
+	Charts       []*BundleChart      `json:"charts"`
+}
+
+// BundleRepository is a repository in the offline bundle
+type BundleRepository struct {
+	Name string       `json:"name"`
+	Tags []*BundleTag `json:"tags"`
+}
+
+// BundleTag is an image in the offline bundle
+type BundleTag struct {
+	Name      string `json:"name"`
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	// the digests of the config and layers referenced by the manifest
+	Blobs []string `json:"blobs"`
+}
+
+// BundleChart is a chart version in the offline bundle
+type BundleChart struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
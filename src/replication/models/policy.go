@@ -25,6 +25,7 @@ type ReplicationPolicy struct {
 	Description       string
 	Filters           []Filter
 	ReplicateDeletion bool
+	ReplicateRobots   bool     // Replicate the robots of the projects to the Harbor targets
 	Trigger           *Trigger // The trigger of the replication
 	ProjectIDs        []int64  // Projects attached to this policy
 	TargetIDs         []int64
@@ -90,6 +90,7 @@ func convertFromPersistModel(policy *persist_models.RepPolicy) (models.Replicati
 		Name:              policy.Name,
 		Description:       policy.Description,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		ReplicateRobots:   policy.ReplicateRobots,
 		ProjectIDs:        []int64{policy.ProjectID},
 		TargetIDs:         []int64{policy.TargetID},
 		CreationTime:      policy.CreationTime,
@@ -137,6 +138,7 @@ func convertToPersistModel(policy models.ReplicationPolicy) (*persist_models.Rep
 		Name:              policy.Name,
 		Description:       policy.Description,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		ReplicateRobots:   policy.ReplicateRobots,
 		CreationTime:      policy.CreationTime,
 		UpdateTime:        policy.UpdateTime,
 	}
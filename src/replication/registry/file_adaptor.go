@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+)
+
+// FileAdaptor is defined to adapt the offline bundle file, the content is
+// read from the metadata of the bundle
+type FileAdaptor struct {
+	metadata *models.BundleMetadata
+}
+
+// NewFileAdaptor returns an instance of FileAdaptor for the bundle metadata
+func NewFileAdaptor(metadata *models.BundleMetadata) *FileAdaptor {
+	return &FileAdaptor{
+		metadata: metadata,
+	}
+}
+
+// Kind returns the unique kind identifier of the adaptor
+func (fa *FileAdaptor) Kind() string {
+	return replication.AdaptorKindFile
+}
+
+// GetNamespaces returns the projects in the bundle
+func (fa *FileAdaptor) GetNamespaces() []models.Namespace {
+	namespaces := []models.Namespace{}
+	for _, project := range fa.metadata.Projects {
+		namespaces = append(namespaces, toNamespace(project))
+	}
+	return namespaces
+}
+
+// GetNamespace returns the project with the specified name in the bundle
+func (fa *FileAdaptor) GetNamespace(name string) models.Namespace {
+	if project := fa.getProject(name); project != nil {
+		return toNamespace(project)
+	}
+	return models.Namespace{}
+}
+
+// GetRepositories returns the repositories under the project in the bundle
+func (fa *FileAdaptor) GetRepositories(namespace string) []models.Repository {
+	project := fa.getProject(namespace)
+	if project == nil {
+		return nil
+	}
+	repositories := []models.Repository{}
+	for _, repo := range project.Repositories {
+		repositories = append(repositories, models.Repository{
+			Name:      repo.Name,
+			Namespace: toNamespace(project),
+		})
+	}
+	return repositories
+}
+
+// GetRepository returns the repository with the specified name under the project in the bundle
+func (fa *FileAdaptor) GetRepository(name string, namespace string) models.Repository {
+	project := fa.getProject(namespace)
+	if project == nil {
+		return models.Repository{}
+	}
+	for _, repo := range project.Repositories {
+		if repo.Name == name {
+			return models.Repository{
+				Name:      repo.Name,
+				Namespace: toNamespace(project),
+			}
+		}
+	}
+	return models.Repository{}
+}
+
+// GetTags returns the tags of the repository under the project in the bundle
+func (fa *FileAdaptor) GetTags(repositoryName string, namespace string) []models.Tag {
+	repo := fa.GetRepository(repositoryName, namespace)
+	if len(repo.Name) == 0 {
+		return nil
+	}
+	tags := []models.Tag{}
+	for _, tag := range fa.getRepository(repositoryName, namespace).Tags {
+		tags = append(tags, toTag(tag, repo))
+	}
+	return tags
+}
+
+// GetTag returns the tag with the specified name of the repository under the project in the bundle
+func (fa *FileAdaptor) GetTag(name string, repositoryName string, namespace string) models.Tag {
+	repo := fa.GetRepository(repositoryName, namespace)
+	if len(repo.Name) == 0 {
+		return models.Tag{}
+	}
+	for _, tag := range fa.getRepository(repositoryName, namespace).Tags {
+		if tag.Name == name {
+			return toTag(tag, repo)
+		}
+	}
+	return models.Tag{}
+}
+
+func (fa *FileAdaptor) getProject(name string) *models.BundleProject {
+	for _, project := range fa.metadata.Projects {
+		if project.Name == name {
+			return project
+		}
+	}
+	return nil
+}
+
+func (fa *FileAdaptor) getRepository(name, namespace string) *models.BundleRepository {
+	for _, repo := range fa.getProject(namespace).Repositories {
+		if repo.Name == name {
+			return repo
+		}
+	}
+	return nil
+}
+
+func toNamespace(project *models.BundleProject) models.Namespace {
+	metadata := map[string]interface{}{}
+	for k, v := range project.Metadata {
+		metadata[k] = v
+	}
+	return models.Namespace{
+		Name:     project.Name,
+		Metadata: metadata,
+	}
+}
+
+func toTag(tag *models.BundleTag, repo models.Repository) models.Tag {
+	return models.Tag{
+		Name:       tag.Name,
+		Repository: repo,
+		Metadata: map[string]interface{}{
+			"digest":     tag.Digest,
+			"media_type": tag.MediaType,
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var metadata = &models.BundleMetadata{
+	Version: "1",
+	Projects: []*models.BundleProject{
+		{
+			Name: "library",
+			Metadata: map[string]string{
+				"public": "true",
+			},
+			Repositories: []*models.BundleRepository{
+				{
+					Name: "library/hello-world",
+					Tags: []*models.BundleTag{
+						{
+							Name:   "latest",
+							Digest: "sha256:1",
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestFileAdaptor(t *testing.T) {
+	adaptor := NewFileAdaptor(metadata)
+	assert.Equal(t, replication.AdaptorKindFile, adaptor.Kind())
+
+	namespaces := adaptor.GetNamespaces()
+	require.Equal(t, 1, len(namespaces))
+	assert.Equal(t, "library", namespaces[0].Name)
+	assert.Equal(t, "true", namespaces[0].Metadata["public"])
+	assert.Equal(t, "", adaptor.GetNamespace("other").Name)
+
+	repositories := adaptor.GetRepositories("library")
+	require.Equal(t, 1, len(repositories))
+	assert.Equal(t, "library/hello-world", repositories[0].Name)
+	assert.Nil(t, adaptor.GetRepositories("other"))
+	assert.Equal(t, "", adaptor.GetRepository("library/busybox", "library").Name)
+
+	tags := adaptor.GetTags("library/hello-world", "library")
+	require.Equal(t, 1, len(tags))
+	assert.Equal(t, "latest", tags[0].Name)
+	assert.Equal(t, "sha256:1", tags[0].Metadata["digest"])
+	assert.Nil(t, adaptor.GetTags("library/busybox", "library"))
+
+	assert.Equal(t, "latest", adaptor.GetTag("latest", "library/hello-world", "library").Name)
+	assert.Equal(t, "", adaptor.GetTag("1.0", "library/hello-world", "library").Name)
+}
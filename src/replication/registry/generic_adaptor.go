@@ -0,0 +1,268 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+)
+
+// GenericAdaptor is defined to adapt the generic Docker Registry v2 endpoints, e.g. Nexus,
+// the repositories are mapped into the namespaces by the path mappings of the target.
+// It adapts Quay, Artifactory, GitHub and GitLab as well with the quirks of their namespace models
+type GenericAdaptor struct {
+	kind   string
+	target *common_models.RepTarget
+	client *http.Client
+	// the client of the API of GitHub or GitLab which lists the repositories
+	api *common_http.Client
+}
+
+// NewRegistryAdaptor returns the adaptor of the type of the target
+func NewRegistryAdaptor(target *common_models.RepTarget) (Adaptor, error) {
+	kind := ""
+	switch target.Type {
+	case common_models.RepTargetTypeGeneric:
+		kind = replication.AdaptorKindGeneric
+	case common_models.RepTargetTypeQuay:
+		kind = replication.AdaptorKindQuay
+	case common_models.RepTargetTypeArtifactory:
+		kind = replication.AdaptorKindArtifactory
+	case common_models.RepTargetTypeGitHub:
+		kind = replication.AdaptorKindGitHub
+	case common_models.RepTargetTypeGitLab:
+		kind = replication.AdaptorKindGitLab
+	default:
+		return nil, fmt.Errorf("unsupported type %d of target %s", target.Type, target.Name)
+	}
+	adaptor, err := NewGenericAdaptor(target)
+	if err != nil {
+		return nil, err
+	}
+	adaptor.kind = kind
+	return adaptor, nil
+}
+
+// NewGenericAdaptor returns an instance of GenericAdaptor with the auth scheme, token realm
+// and path mappings of the target
+func NewGenericAdaptor(target *common_models.RepTarget) (*GenericAdaptor, error) {
+	transport, err := registry.GetHTTPTransportWithCA(target.Insecure, target.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	authorizer, err := auth.NewAuthorizer(&http.Client{
+		Transport: transport,
+	}, target.AuthScheme, target.Username, target.Password, target.TokenRealm)
+	if err != nil {
+		return nil, err
+	}
+	// the listing stops once the target is down rather than waiting for the timeouts
+	probe := registry.ProbeURL(transport, strings.TrimRight(target.URL, "/")+"/v2/")
+	breaker := registry.NewBreakerTransport(transport, registry.GetBreaker(target.URL, probe))
+	return &GenericAdaptor{
+		kind:   replication.AdaptorKindGeneric,
+		target: target,
+		client: &http.Client{
+			Transport: registry.NewTransport(breaker, authorizer),
+		},
+		api: common_http.NewClient(&http.Client{
+			Transport: transport,
+		}, &apiToken{
+			targetType: target.Type,
+			token:      target.Password,
+		}),
+	}, nil
+}
+
+// Kind returns the unique kind identifier of the adaptor
+func (ga *GenericAdaptor) Kind() string {
+	return ga.kind
+}
+
+// GetNamespaces returns the namespaces which the repositories of the registry are mapped into
+func (ga *GenericAdaptor) GetNamespaces() []models.Namespace {
+	names := map[string]bool{}
+	for _, repo := range ga.listRepositories() {
+		names[repo.Namespace.Name] = true
+	}
+	namespaces := []models.Namespace{}
+	for name := range names {
+		namespaces = append(namespaces, models.Namespace{
+			Name: name,
+		})
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces
+}
+
+// GetNamespace returns the namespace with the specified name if any repository is mapped into it
+func (ga *GenericAdaptor) GetNamespace(name string) models.Namespace {
+	if len(ga.GetRepositories(name)) == 0 {
+		return models.Namespace{}
+	}
+	return models.Namespace{
+		Name: name,
+	}
+}
+
+// GetRepositories returns the repositories mapped into the namespace, the name of the
+// repository on the registry is in the metadata "remote_name"
+func (ga *GenericAdaptor) GetRepositories(namespace string) []models.Repository {
+	var repositories []models.Repository
+	for _, repo := range ga.listRepositories() {
+		if repo.Namespace.Name == namespace {
+			repositories = append(repositories, repo)
+		}
+	}
+	return repositories
+}
+
+// GetRepository returns the repository with the specified name under the namespace
+func (ga *GenericAdaptor) GetRepository(name string, namespace string) models.Repository {
+	for _, repo := range ga.GetRepositories(namespace) {
+		if repo.Name == name {
+			return repo
+		}
+	}
+	return models.Repository{}
+}
+
+// GetTags returns the tags of the repository under the namespace
+func (ga *GenericAdaptor) GetTags(repositoryName string, namespace string) []models.Tag {
+	remote := ga.target.RemoteRepository(repositoryName)
+	client, err := registry.NewRepository(remote, ga.target.URL, ga.client)
+	if err != nil {
+		log.Errorf("failed to create registry client: %v", err)
+		return nil
+	}
+
+	ts, err := client.ListTag()
+	if err != nil {
+		log.Errorf("failed to get tags of repository %s: %v", remote, err)
+		return nil
+	}
+
+	repo := models.Repository{
+		Name: repositoryName,
+		Namespace: models.Namespace{
+			Name: namespace,
+		},
+	}
+	tags := []models.Tag{}
+	for _, t := range ts {
+		tags = append(tags, models.Tag{
+			Name:       t,
+			Repository: repo,
+		})
+	}
+	return tags
+}
+
+// GetTag returns the tag with the specified name of the repository under the namespace
+func (ga *GenericAdaptor) GetTag(name string, repositoryName string, namespace string) models.Tag {
+	for _, tag := range ga.GetTags(repositoryName, namespace) {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return models.Tag{}
+}
+
+// listRepositories lists the repositories in the catalog of the registry and maps them
+// into the namespaces, the ones which can't be mapped are skipped
+func (ga *GenericAdaptor) listRepositories() []models.Repository {
+	names, err := ga.catalog()
+	if err != nil {
+		log.Errorf("failed to get the catalog of registry %s: %v", ga.target.URL, err)
+		return nil
+	}
+
+	repositories := []models.Repository{}
+	for _, name := range names {
+		local, ok := common_models.MapToLocal(ga.target.PathMappings, name)
+		if !ok {
+			log.Debugf("repository %s isn't mapped into any namespace, skip", name)
+			continue
+		}
+		namespace, _ := utils.ParseRepository(local)
+		repositories = append(repositories, models.Repository{
+			Name: local,
+			Namespace: models.Namespace{
+				Name: namespace,
+			},
+			Metadata: map[string]interface{}{
+				"remote_name": name,
+			},
+		})
+	}
+	return repositories
+}
+
+func (ga *GenericAdaptor) catalog() ([]string, error) {
+	switch ga.kind {
+	case replication.AdaptorKindGitHub:
+		return ga.githubCatalog()
+	case replication.AdaptorKindGitLab:
+		return ga.gitlabCatalog()
+	}
+	if ga.kind != replication.AdaptorKindArtifactory {
+		client, err := registry.NewRegistry(ga.target.URL, ga.client)
+		if err != nil {
+			return nil, err
+		}
+		// some registries, e.g. Quay, list only the repositories which the user can access
+		return client.Catalog()
+	}
+
+	// Artifactory lists the catalog per repository, the keys of the repositories
+	// are the first components of the mapped paths
+	keys := map[string]bool{}
+	names := []string{}
+	for _, mapping := range ga.target.PathMappings {
+		key := strings.SplitN(strings.Trim(mapping.Path, "/"), "/", 2)[0]
+		if len(key) == 0 || keys[key] {
+			continue
+		}
+		keys[key] = true
+		client, err := registry.NewRegistry(strings.TrimRight(ga.target.URL, "/")+"/artifactory/api/docker/"+key, ga.client)
+		if err != nil {
+			return nil, err
+		}
+		repositories, err := client.Catalog()
+		if err != nil {
+			return nil, err
+		}
+		for _, repository := range repositories {
+			names = append(names, key+"/"+repository)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no repository of Artifactory is mapped")
+	}
+	return names, nil
+}
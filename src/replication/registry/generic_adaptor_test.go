@@ -0,0 +1,274 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"net/http"
+	"testing"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericAdaptor(t *testing.T) {
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/_catalog",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				user, password, ok := r.BasicAuth()
+				if !ok || user != "admin" || password != "Harbor12345" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{"repositories":["docker-local/team/nginx","alpine","app/web","busybox"]}`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/docker-local/team/nginx/tags/list",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"name":"docker-local/team/nginx","tags":["1.15","latest"]}`),
+			}),
+		})
+	defer server.Close()
+
+	adaptor, err := NewGenericAdaptor(&common_models.RepTarget{
+		URL:        server.URL,
+		Type:       common_models.RepTargetTypeGeneric,
+		Username:   "admin",
+		Password:   "Harbor12345",
+		AuthScheme: common_models.RepTargetAuthBasic,
+		PathMappings: []*common_models.PathMapping{
+			{Project: "library", Path: "docker-local/team"},
+			{Project: "base", Path: "/"},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindGeneric, adaptor.Kind())
+
+	namespaces := adaptor.GetNamespaces()
+	require.Equal(t, 3, len(namespaces))
+	assert.Equal(t, "app", namespaces[0].Name)
+	assert.Equal(t, "base", namespaces[1].Name)
+	assert.Equal(t, "library", namespaces[2].Name)
+	assert.Equal(t, "base", adaptor.GetNamespace("base").Name)
+	assert.Equal(t, "", adaptor.GetNamespace("docker-local").Name)
+
+	repositories := adaptor.GetRepositories("base")
+	require.Equal(t, 2, len(repositories))
+	assert.Equal(t, "base/alpine", repositories[0].Name)
+	assert.Equal(t, "base/busybox", repositories[1].Name)
+
+	repo := adaptor.GetRepository("library/nginx", "library")
+	assert.Equal(t, "library/nginx", repo.Name)
+	assert.Equal(t, "docker-local/team/nginx", repo.Metadata["remote_name"])
+	assert.Equal(t, "", adaptor.GetRepository("library/redis", "library").Name)
+
+	tags := adaptor.GetTags("library/nginx", "library")
+	require.Equal(t, 2, len(tags))
+	assert.Equal(t, "1.15", tags[0].Name)
+	assert.Equal(t, "library/nginx", tags[0].Repository.Name)
+	assert.Equal(t, "latest", adaptor.GetTag("latest", "library/nginx", "library").Name)
+	assert.Equal(t, "", adaptor.GetTag("1.0", "library/nginx", "library").Name)
+
+	_, err = NewGenericAdaptor(&common_models.RepTarget{
+		URL:        server.URL,
+		AuthScheme: "digest",
+	})
+	assert.NotNil(t, err)
+}
+
+func TestArtifactoryAdaptor(t *testing.T) {
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/artifactory/api/docker/docker-local/v2/_catalog",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"repositories":["team/nginx","redis"]}`),
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/docker-local/team/nginx/tags/list",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"name":"docker-local/team/nginx","tags":["latest"]}`),
+			}),
+		})
+	defer server.Close()
+
+	adaptor, err := NewRegistryAdaptor(&common_models.RepTarget{
+		URL:        server.URL,
+		Type:       common_models.RepTargetTypeArtifactory,
+		AuthScheme: common_models.RepTargetAuthAnonymous,
+		PathMappings: []*common_models.PathMapping{
+			{Project: "library", Path: "docker-local/team"},
+			{Project: "local", Path: "docker-local"},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindArtifactory, adaptor.Kind())
+
+	namespaces := adaptor.GetNamespaces()
+	require.Equal(t, 2, len(namespaces))
+	assert.Equal(t, "library", namespaces[0].Name)
+	assert.Equal(t, "local", namespaces[1].Name)
+	assert.Equal(t, "local/redis", adaptor.GetRepository("local/redis", "local").Name)
+
+	tags := adaptor.GetTags("library/nginx", "library")
+	require.Equal(t, 1, len(tags))
+	assert.Equal(t, "latest", tags[0].Name)
+
+	_, err = NewRegistryAdaptor(&common_models.RepTarget{
+		Type: common_models.RepTargetTypeHarbor,
+	})
+	assert.NotNil(t, err)
+}
+
+func TestQuayAdaptor(t *testing.T) {
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/_catalog",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"repositories":["org/app_web"]}`),
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/org/app_web/tags/list",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"name":"org/app_web","tags":["v1"]}`),
+			}),
+		})
+	defer server.Close()
+
+	adaptor, err := NewRegistryAdaptor(&common_models.RepTarget{
+		URL:        server.URL,
+		Type:       common_models.RepTargetTypeQuay,
+		Username:   "org+robot",
+		Password:   "token",
+		AuthScheme: common_models.RepTargetAuthBasic,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindQuay, adaptor.Kind())
+
+	repositories := adaptor.GetRepositories("org")
+	require.Equal(t, 1, len(repositories))
+	assert.Equal(t, "org/app_web", repositories[0].Name)
+	assert.Equal(t, "v1", adaptor.GetTag("v1", "org/app_web", "org").Name)
+}
+
+func TestGitHubAdaptor(t *testing.T) {
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v3/orgs/my-org/packages",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" ||
+					r.URL.Query().Get("package_type") != "container" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`[{"name":"team/nginx"},{"name":"other"}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/my-org/team/nginx/tags/list",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"name":"my-org/team/nginx","tags":["latest"]}`),
+			}),
+		})
+	defer server.Close()
+
+	adaptor, err := NewRegistryAdaptor(&common_models.RepTarget{
+		URL:        server.URL,
+		Type:       common_models.RepTargetTypeGitHub,
+		Username:   "user",
+		Password:   "token",
+		AuthScheme: common_models.RepTargetAuthBasic,
+		PathMappings: []*common_models.PathMapping{
+			{Project: "library", Path: "my-org/team"},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindGitHub, adaptor.Kind())
+
+	repositories := adaptor.GetRepositories("library")
+	require.Equal(t, 1, len(repositories))
+	assert.Equal(t, "library/nginx", repositories[0].Name)
+	assert.Equal(t, "my-org/team/nginx", repositories[0].Metadata["remote_name"])
+	assert.Equal(t, "my-org/other", adaptor.GetRepository("my-org/other", "my-org").Name)
+	assert.Equal(t, "latest", adaptor.GetTag("latest", "library/nginx", "library").Name)
+}
+
+func TestGitLabAdaptor(t *testing.T) {
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v4/",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("PRIVATE-TOKEN") != "token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				// "group/project" is a project rather than a group
+				if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/registry/repositories" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`[{"id":1,"path":"group/project/app"},{"id":2,"path":"group/project/web"}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/group/project/app/tags/list",
+			Handler: test.Handler(&test.Response{
+				Body: []byte(`{"name":"group/project/app","tags":["v1"]}`),
+			}),
+		})
+	defer server.Close()
+
+	adaptor, err := NewRegistryAdaptor(&common_models.RepTarget{
+		URL:        server.URL,
+		Type:       common_models.RepTargetTypeGitLab,
+		Username:   "user",
+		Password:   "token",
+		AuthScheme: common_models.RepTargetAuthBasic,
+		PathMappings: []*common_models.PathMapping{
+			{Project: "library", Path: "group/project"},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindGitLab, adaptor.Kind())
+
+	repositories := adaptor.GetRepositories("library")
+	require.Equal(t, 2, len(repositories))
+	assert.Equal(t, "library/app", repositories[0].Name)
+	assert.Equal(t, "library/web", repositories[1].Name)
+	assert.Equal(t, "v1", adaptor.GetTag("v1", "library/app", "library").Name)
+
+	// no group or project is mapped
+	adaptor, err = NewRegistryAdaptor(&common_models.RepTarget{
+		URL:  server.URL,
+		Type: common_models.RepTargetTypeGitLab,
+	})
+	require.Nil(t, err)
+	assert.Empty(t, adaptor.GetNamespaces())
+}
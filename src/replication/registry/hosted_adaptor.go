@@ -0,0 +1,146 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_models "github.com/goharbor/harbor/src/common/models"
+)
+
+// the max count of the items listed by one page of the APIs of GitHub and GitLab
+const apiPageSize = 100
+
+// apiToken adds the access token, which is the password of the target, to the requests
+// sent to the API of GitHub or GitLab
+type apiToken struct {
+	targetType int
+	token      string
+}
+
+// Modify implements github.com/goharbor/harbor/src/common/http/modifier.Modifier
+func (a *apiToken) Modify(req *http.Request) error {
+	switch a.targetType {
+	case common_models.RepTargetTypeGitHub:
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	case common_models.RepTargetTypeGitLab:
+		req.Header.Set("PRIVATE-TOKEN", a.token)
+	}
+	return nil
+}
+
+// githubCatalog lists the container packages of the owners which the projects are mapped to,
+// or the ones of the user if no project is mapped, as GHCR doesn't support the catalog API
+func (ga *GenericAdaptor) githubCatalog() ([]string, error) {
+	user := strings.ToLower(ga.target.Username)
+	owners := []string{}
+	seen := map[string]bool{}
+	for _, mapping := range ga.target.PathMappings {
+		owner := strings.ToLower(strings.SplitN(strings.Trim(mapping.Path, "/"), "/", 2)[0])
+		if len(owner) == 0 || seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		owners = append(owners, owner)
+	}
+	if len(owners) == 0 {
+		if len(user) == 0 {
+			return nil, errors.New("no owner of GitHub is mapped")
+		}
+		owners = append(owners, user)
+	}
+
+	names := []string{}
+	for _, owner := range owners {
+		path := "/orgs/" + owner + "/packages"
+		if owner == user {
+			path = "/user/packages"
+		}
+		for page := 1; ; page++ {
+			packages := []struct {
+				Name string `json:"name"`
+			}{}
+			if err := ga.api.Get(fmt.Sprintf("%s%s?package_type=container&per_page=%d&page=%d",
+				ga.target.APIURL(), path, apiPageSize, page), &packages); err != nil {
+				return nil, fmt.Errorf("failed to list the packages of %s: %v", owner, err)
+			}
+			for _, p := range packages {
+				names = append(names, owner+"/"+p.Name)
+			}
+			if len(packages) < apiPageSize {
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// gitlabCatalog lists the repositories of the groups or projects which the projects are
+// mapped to, as the catalog API of GitLab is only available to the administrators
+func (ga *GenericAdaptor) gitlabCatalog() ([]string, error) {
+	names := []string{}
+	paths, seen := map[string]bool{}, map[string]bool{}
+	for _, mapping := range ga.target.PathMappings {
+		path := strings.ToLower(strings.Trim(mapping.Path, "/"))
+		if len(path) == 0 || paths[path] {
+			continue
+		}
+		paths[path] = true
+		repositories, err := ga.gitlabRepositories("groups", path)
+		if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+			repositories, err = ga.gitlabRepositories("projects", path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the repositories of %s: %v", path, err)
+		}
+		for _, repository := range repositories {
+			if !seen[repository] {
+				seen[repository] = true
+				names = append(names, repository)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no group or project of GitLab is mapped")
+	}
+	return names, nil
+}
+
+// gitlabRepositories lists the paths of the repositories in the container registry of the
+// group or project, the kind is "groups" or "projects"
+func (ga *GenericAdaptor) gitlabRepositories(kind, path string) ([]string, error) {
+	names := []string{}
+	for page := 1; ; page++ {
+		repositories := []struct {
+			Path string `json:"path"`
+		}{}
+		if err := ga.api.Get(fmt.Sprintf("%s/%s/%s/registry/repositories?per_page=%d&page=%d",
+			ga.target.APIURL(), kind, url.PathEscape(path), apiPageSize, page), &repositories); err != nil {
+			return nil, err
+		}
+		for _, repository := range repositories {
+			names = append(names, repository.Path)
+		}
+		if len(repositories) < apiPageSize {
+			break
+		}
+	}
+	return names, nil
+}
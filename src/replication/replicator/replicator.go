@@ -24,6 +24,7 @@ import (
 	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/egress"
 	"github.com/goharbor/harbor/src/replication/models"
 )
 
@@ -70,6 +71,27 @@ func (d *DefaultReplicator) Replicate(replication *Replication) error {
 
 	for _, target := range replication.Targets {
 		for repository, tags := range repositories {
+			// the job isn't submitted if the repository on the target isn't allowed to reach,
+			// it's recorded as an error so that it shows up in the jobs of the policy
+			if err := egress.Check(target.URL, target.RemoteRepository(repository)); err != nil {
+				if _, ok := err.(*egress.DeniedError); !ok {
+					return err
+				}
+				log.Warningf("skip replicating %s to target %s: %v", repository, target.Name, err)
+				if _, err = dao.AddRepJob(common_models.RepJob{
+					PolicyID:   replication.PolicyID,
+					OpUUID:     replication.OpUUID,
+					Repository: repository,
+					TagList:    tags,
+					Operation:  operation,
+					Status:     common_models.JobError,
+					Error:      err.Error(),
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+
 			// create job in database
 			id, err := dao.AddRepJob(common_models.RepJob{
 				PolicyID:   replication.PolicyID,
@@ -117,6 +139,15 @@ func (d *DefaultReplicator) Replicate(replication *Replication) error {
 					"dst_registry_password": target.Password,
 				}
 			}
+			// the settings of the generic registry, the job treats the target as a Harbor if they're missing
+			job.Parameters["dst_registry_type"] = target.Type
+			job.Parameters["dst_auth_scheme"] = target.AuthScheme
+			job.Parameters["dst_token_realm"] = target.TokenRealm
+			job.Parameters["dst_path_mappings"] = target.PathMappingStr
+			job.Parameters["dst_max_concurrent_transfers"] = target.MaxConcurrentTransfers
+			job.Parameters["dst_backoff_retries"] = target.BackoffRetries
+			job.Parameters["dst_ratelimit_reserve"] = target.RateLimitReserve
+			job.Parameters["dst_ca_bundle"] = target.CABundle
 
 			uuid, err := d.client.SubmitJob(job)
 			if err != nil {
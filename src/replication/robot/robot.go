@@ -0,0 +1,214 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package robot replicates the robots of the projects to the Harbor targets for the disaster
+// recovery, the targets re-issue the tokens under their own keys. The re-issued tokens are
+// kept encrypted as the replicas of the robots, so the CI credentials can be switched over
+// to the target when failing over
+package robot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// ReplicationPath is the path of the API of the Harbor targets replicating the robots
+const ReplicationPath = "/api/replication/robots"
+
+var (
+	// the functions reading and writing the database, replaced in testing
+	listRobots = dao.ListRobots
+	setReplica = dao.SetRobotReplica
+	secretKey  = config.SecretKey
+)
+
+// Replicate replicates the definitions of the robots of the project to the Harbor target, i.e.
+// the names, descriptions, disabled states and access, the project is created on the target if
+// it doesn't exist. The robots not defined in the project are removed from the target if prune
+// is true. The tokens re-issued by the target for the new robots and the ones whose access
+// changed are saved as the replicas
+func Replicate(target *models.RepTarget, project *models.Project, prune bool) error {
+	if target.IsRegistry() {
+		return fmt.Errorf("target %s is not a Harbor", target.Name)
+	}
+	robots, err := listRobots(&models.RobotQuery{ProjectID: project.ProjectID})
+	if err != nil {
+		return fmt.Errorf("failed to list the robots of project %s: %v", project.Name, err)
+	}
+	req := &models.RobotReplicationReq{
+		Project: project.Name,
+		Robots:  []*models.RobotReq{},
+		Prune:   prune,
+	}
+	ids := map[string]int64{}
+	for _, robot := range robots {
+		access, err := robot.GetAccess()
+		if err != nil {
+			return fmt.Errorf("failed to get the access of robot %s: %v", robot.Name, err)
+		}
+		// the token can't be re-issued without knowing the access granted
+		if access == nil {
+			log.Warningf("the access of robot %s is unknown, skip replicating it", robot.Name)
+			continue
+		}
+		req.Robots = append(req.Robots, &models.RobotReq{
+			Name:        strings.TrimPrefix(robot.Name, common.RobotPrefix),
+			Description: robot.Description,
+			Disabled:    robot.Disabled,
+			Access:      namedAccess(access, project),
+		})
+		ids[robot.Name] = robot.ID
+	}
+
+	transport, err := registry.GetHTTPTransportWithCA(target.Insecure, target.CABundle)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+	}
+	if err = createProject(client, target, project); err != nil {
+		return err
+	}
+	tokens := []*models.RobotRep{}
+	code, data, err := post(client, target, ReplicationPath, req)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("failed to replicate the robots of project %s to target %s: %d %s",
+			project.Name, target.Name, code, string(data))
+	}
+	if err = json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("invalid tokens re-issued by target %s: %v", target.Name, err)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	key, err := secretKey()
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		id, ok := ids[token.Name]
+		if !ok {
+			log.Warningf("the token of unknown robot %s re-issued by target %s, skip", token.Name, target.Name)
+			continue
+		}
+		encrypted, err := keyring.Encrypt(token.Token, key)
+		if err != nil {
+			return err
+		}
+		if err = setReplica(&models.RobotReplica{
+			RobotID:  id,
+			TargetID: target.ID,
+			Token:    encrypted,
+		}); err != nil {
+			return fmt.Errorf("failed to save the replica of robot %s: %v", token.Name, err)
+		}
+	}
+	log.Debugf("%d robots of project %s replicated to target %s, %d tokens re-issued",
+		len(req.Robots), project.Name, target.Name, len(tokens))
+	return nil
+}
+
+// namedAccess returns the access whose resources are in the name of the project rather than
+// the ID, as the ID of the project on the target is different
+func namedAccess(access []*rbac.Policy, project *models.Project) []*rbac.Policy {
+	byID := rbac.NewProjectNamespace(project.ProjectID, false).Resource().String()
+	byName := rbac.NewProjectNamespace(project.Name, false).Resource().String()
+	result := []*rbac.Policy{}
+	for _, a := range access {
+		resource := a.Resource.String()
+		if resource == byID || strings.HasPrefix(resource, byID+"/") {
+			resource = byName + strings.TrimPrefix(resource, byID)
+		}
+		// the same access may be granted in both ID and name of the project
+		exist := false
+		for _, r := range result {
+			if r.Resource.String() == resource && r.Action == a.Action && r.GetEffect() == a.GetEffect() {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			result = append(result, &rbac.Policy{
+				Resource: rbac.Resource(resource),
+				Action:   a.Action,
+				Effect:   a.Effect,
+			})
+		}
+	}
+	return result
+}
+
+// createProject creates the project on the target with the same public property, it's
+// fine if the project exists
+func createProject(client *http.Client, target *models.RepTarget, project *models.Project) error {
+	code, data, err := post(client, target, "/api/projects", &models.ProjectRequest{
+		Name: project.Name,
+		Metadata: map[string]string{
+			models.ProMetaPublic: strconv.FormatBool(project.IsPublic()),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if code != http.StatusCreated && code != http.StatusConflict {
+		return fmt.Errorf("failed to create project %s on target %s: %d %s", project.Name, target.Name, code, string(data))
+	}
+	return nil
+}
+
+func post(client *http.Client, target *models.RepTarget, path string, v interface{}) (int, []byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(target.URL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(target.Username) > 0 {
+		req.SetBasicAuth(target.Username, target.Password)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
@@ -0,0 +1,131 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/keyring"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "0123456789abcdef"
+
+func TestNamedAccess(t *testing.T) {
+	project := &models.Project{
+		ProjectID: 5,
+		Name:      "library",
+	}
+	access := namedAccess([]*rbac.Policy{
+		{Resource: "/project/5/repository", Action: "pull"},
+		{Resource: "/project/library/repository", Action: "pull"},
+		{Resource: "/project/5", Action: "read"},
+		{Resource: "/project/51/repository", Action: "push"},
+	}, project)
+	require.Equal(t, 3, len(access))
+	assert.Equal(t, rbac.Resource("/project/library/repository"), access[0].Resource)
+	assert.Equal(t, rbac.Resource("/project/library"), access[1].Resource)
+	assert.Equal(t, rbac.Resource("/project/51/repository"), access[2].Resource)
+}
+
+func TestReplicate(t *testing.T) {
+	defer func(l func(*models.RobotQuery) ([]*models.Robot, error),
+		s func(*models.RobotReplica) error, k func() (string, error)) {
+		listRobots, setReplica, secretKey = l, s, k
+	}(listRobots, setReplica, secretKey)
+
+	pull, _ := json.Marshal([]*rbac.Policy{{Resource: "/project/5/repository", Action: "pull"}})
+	listRobots = func(query *models.RobotQuery) ([]*models.Robot, error) {
+		return []*models.Robot{
+			{ID: 1, Name: "robot$ci", Description: "ci", ProjectID: 5, Access: string(pull)},
+			{ID: 2, Name: "robot$legacy", ProjectID: 5},
+		}, nil
+	}
+	replicas := []*models.RobotReplica{}
+	setReplica = func(replica *models.RobotReplica) error {
+		replicas = append(replicas, replica)
+		return nil
+	}
+	secretKey = func() (string, error) {
+		return testKey, nil
+	}
+
+	created := false
+	var received *models.RobotReplicationReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "admin" || password != "secret" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/projects":
+			created = true
+			w.WriteHeader(http.StatusConflict)
+		case ReplicationPath:
+			data, _ := ioutil.ReadAll(r.Body)
+			received = &models.RobotReplicationReq{}
+			json.Unmarshal(data, received)
+			w.Write([]byte(`[{"Name": "robot$ci", "Token": "dr-token"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target := &models.RepTarget{
+		ID:       3,
+		Name:     "dr",
+		URL:      server.URL,
+		Username: "admin",
+		Password: "secret",
+	}
+	project := &models.Project{
+		ProjectID: 5,
+		Name:      "library",
+	}
+	require.Nil(t, Replicate(target, project, true))
+	assert.True(t, created)
+
+	// the robot whose access is unknown is skipped
+	require.NotNil(t, received)
+	assert.Equal(t, "library", received.Project)
+	assert.True(t, received.Prune)
+	require.Equal(t, 1, len(received.Robots))
+	assert.Equal(t, "ci", received.Robots[0].Name)
+	assert.Equal(t, rbac.Resource("/project/library/repository"), received.Robots[0].Access[0].Resource)
+
+	require.Equal(t, 1, len(replicas))
+	assert.Equal(t, int64(1), replicas[0].RobotID)
+	assert.Equal(t, int64(3), replicas[0].TargetID)
+	token, err := keyring.Decrypt(replicas[0].Token, testKey)
+	require.Nil(t, err)
+	assert.Equal(t, "dr-token", token)
+
+	// invalid credential
+	target.Password = ""
+	assert.NotNil(t, Replicate(target, project, false))
+
+	// not a Harbor
+	target.Type = models.RepTargetTypeGeneric
+	assert.NotNil(t, Replicate(target, project, false))
+}
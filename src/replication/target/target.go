@@ -18,8 +18,9 @@ import (
 	"fmt"
 
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/keyring"
 	"github.com/goharbor/harbor/src/common/models"
-	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/secretstore"
 	"github.com/goharbor/harbor/src/core/config"
 )
 
@@ -53,11 +54,36 @@ func (d *DefaultManager) GetTarget(id int64) (*models.RepTarget, error) {
 		if err != nil {
 			return nil, err
 		}
-		pwd, err := utils.ReversibleDecrypt(target.Password, key)
+		pwd, err := keyring.Decrypt(target.Password, key)
 		if err != nil {
 			return nil, err
 		}
 		target.Password = pwd
 	}
+
+	// decrypt the CA bundle
+	if len(target.CABundle) > 0 {
+		key, err := config.SecretKey()
+		if err != nil {
+			return nil, err
+		}
+		bundle, err := keyring.Decrypt(target.CABundle, key)
+		if err != nil {
+			return nil, err
+		}
+		target.CABundle = bundle
+	}
+
+	// read the credential from the external secret store
+	if len(target.CredentialRef) > 0 {
+		cred, err := secretstore.Resolve(target.CredentialRef)
+		if err != nil {
+			return nil, err
+		}
+		if len(cred.Username) > 0 {
+			target.Username = cred.Username
+		}
+		target.Password = cred.Password
+	}
 	return target, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/job"
 	"github.com/goharbor/harbor/src/common/job/models"
+	commonmodels "github.com/goharbor/harbor/src/common/models"
 )
 
 // MockJobClient ...
@@ -25,9 +26,17 @@ func (mjc *MockJobClient) GetJobLog(uuid string) ([]byte, error) {
 	return nil, &http.Error{404, "Not Found"}
 }
 
+// GetJobLogUsage ...
+func (mjc *MockJobClient) GetJobLogUsage() ([]*commonmodels.JobLogUsage, error) {
+	return []*commonmodels.JobLogUsage{}, nil
+}
+
 // SubmitJob ...
 func (mjc *MockJobClient) SubmitJob(data *models.JobData) (string, error) {
-	if data.Name == job.ImageScanAllJob || data.Name == job.ImageReplicate || data.Name == job.ImageGC || data.Name == job.ImageScanJob {
+	if data.Name == job.ImageScanAllJob || data.Name == job.ImageReplicate || data.Name == job.ImageGC || data.Name == job.ImageScanJob ||
+		data.Name == job.RebuildIndex || data.Name == job.SeverityRecalculation || data.Name == job.ChartGC ||
+		data.Name == job.StorageTransition || data.Name == job.ProjectReport || data.Name == job.Reconciliation ||
+		data.Name == job.SecretReencryption || data.Name == job.CoreTask {
 		uuid := fmt.Sprintf("u-%d", rand.Int())
 		mjc.JobUUID = append(mjc.JobUUID, uuid)
 		return uuid, nil